@@ -51,6 +51,7 @@ require (
 	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c
 	google.golang.org/genproto v0.0.0-20220422154200-b37d22cd5731
 	google.golang.org/grpc v1.46.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -0,0 +1,220 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package access keeps lightweight, in-memory counters of record and record
+// file retrievals so that operators can detect scraping or hotlinking of
+// large attachments. The counters are reset when the process restarts.
+package access
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// window is the length of the window that retrieval rates are measured over.
+const window = time.Minute
+
+// Alert is emitted when the retrieval rate of a record file reaches the
+// configured alert rate.
+type Alert struct {
+	Token string
+	File  string
+	Rate  uint64 // Retrievals in the current window
+}
+
+// FileCount contains the retrieval counters of a record file.
+type FileCount struct {
+	Name  string
+	Count uint64 // Total retrievals
+	Rate  uint64 // Retrievals in the last full window
+}
+
+// RecordCount contains the retrieval counters of a record and its files.
+type RecordCount struct {
+	Token string
+	Count uint64 // Total retrievals
+	Files []FileCount
+}
+
+// file tracks the retrievals of a single record file.
+type file struct {
+	count       uint64
+	windowStart time.Time
+	windowCount uint64 // Retrievals in the current window
+	lastRate    uint64 // Retrievals in the last full window
+}
+
+// record tracks the retrievals of a single record.
+type record struct {
+	count uint64
+	files map[string]*file // [filename]file
+}
+
+// Counter counts record and record file retrievals. When an alert rate is
+// set, an alert is emitted the first time a file reaches the alert rate in
+// a window.
+type Counter struct {
+	sync.Mutex
+	alertRate uint64
+	alertFn   func(Alert)
+	since     time.Time
+	records   map[string]*record // [token]record
+
+	// now is used to allow tests to control the clock.
+	now func() time.Time
+}
+
+// New returns a new Counter. An alert rate of zero disables alerts. The alert
+// function is called synchronously and must not block.
+func New(alertRate uint64, alertFn func(Alert)) *Counter {
+	return &Counter{
+		alertRate: alertRate,
+		alertFn:   alertFn,
+		since:     time.Now(),
+		records:   make(map[string]*record),
+		now:       time.Now,
+	}
+}
+
+// Since returns the time that the counters were started.
+func (c *Counter) Since() time.Time {
+	return c.since
+}
+
+// Add records a retrieval of the provided record and record files.
+func (c *Counter) Add(token string, files []string) {
+	var alerts []Alert
+
+	c.Lock()
+	r, ok := c.records[token]
+	if !ok {
+		r = &record{
+			files: make(map[string]*file, len(files)),
+		}
+		c.records[token] = r
+	}
+	r.count++
+
+	now := c.now()
+	for _, name := range files {
+		f, ok := r.files[name]
+		if !ok {
+			f = &file{
+				windowStart: now.Truncate(window),
+			}
+			r.files[name] = f
+		}
+		f.roll(now)
+		f.count++
+		f.windowCount++
+
+		if c.alertRate > 0 && f.windowCount == c.alertRate {
+			alerts = append(alerts, Alert{
+				Token: token,
+				File:  name,
+				Rate:  f.windowCount,
+			})
+		}
+	}
+	c.Unlock()
+
+	// Emit alerts without holding the lock
+	if c.alertFn != nil {
+		for _, v := range alerts {
+			log.Debugf("Access alert: %v %v %v", v.Token, v.File, v.Rate)
+			c.alertFn(v)
+		}
+	}
+}
+
+// Record returns the counters of the records whose token starts with the
+// provided token prefix.
+func (c *Counter) Record(prefix string) []RecordCount {
+	c.Lock()
+	defer c.Unlock()
+
+	counts := make([]RecordCount, 0, 1)
+	for token, r := range c.records {
+		if !strings.HasPrefix(token, prefix) {
+			continue
+		}
+		counts = append(counts, c.recordCount(token, r))
+	}
+	sortRecordCounts(counts)
+	return counts
+}
+
+// Top returns the counters of the n most retrieved records.
+func (c *Counter) Top(n int) []RecordCount {
+	c.Lock()
+	defer c.Unlock()
+
+	counts := make([]RecordCount, 0, len(c.records))
+	for token, r := range c.records {
+		counts = append(counts, c.recordCount(token, r))
+	}
+	sortRecordCounts(counts)
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// recordCount returns the counters of the provided record.
+//
+// This function must be called WITH the lock held.
+func (c *Counter) recordCount(token string, r *record) RecordCount {
+	now := c.now()
+	files := make([]FileCount, 0, len(r.files))
+	for name, f := range r.files {
+		f.roll(now)
+		files = append(files, FileCount{
+			Name:  name,
+			Count: f.count,
+			Rate:  f.lastRate,
+		})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Count != files[j].Count {
+			return files[i].Count > files[j].Count
+		}
+		return files[i].Name < files[j].Name
+	})
+	return RecordCount{
+		Token: token,
+		Count: r.count,
+		Files: files,
+	}
+}
+
+// roll starts a new window if the current window has ended.
+func (f *file) roll(now time.Time) {
+	elapsed := now.Sub(f.windowStart)
+	switch {
+	case elapsed < window:
+		// Still in the current window
+		return
+	case elapsed < 2*window:
+		// The current window just ended
+		f.lastRate = f.windowCount
+	default:
+		// There were no retrievals in the last full window
+		f.lastRate = 0
+	}
+	f.windowStart = now.Truncate(window)
+	f.windowCount = 0
+}
+
+// sortRecordCounts sorts the provided counters by retrieval count, from
+// highest to lowest.
+func sortRecordCounts(counts []RecordCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Token < counts[j].Token
+	})
+}
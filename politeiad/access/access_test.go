@@ -0,0 +1,67 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package access
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCounter(t *testing.T) {
+	var alerts []Alert
+	c := New(3, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	// Retrieve a file enough times to trigger a single alert
+	for i := 0; i < 5; i++ {
+		c.Add("abcd", []string{"image.png"})
+	}
+	c.Add("abcd", nil)
+	c.Add("efgh", []string{"index.md"})
+	if len(alerts) != 1 {
+		t.Fatalf("got %v alerts, want 1", len(alerts))
+	}
+	if alerts[0].Token != "abcd" || alerts[0].File != "image.png" {
+		t.Errorf("unexpected alert %+v", alerts[0])
+	}
+
+	// The rate of a file is the number of retrievals in the last full
+	// window.
+	now = now.Add(window)
+	top := c.Top(1)
+	if len(top) != 1 {
+		t.Fatalf("got %v records, want 1", len(top))
+	}
+	r := top[0]
+	switch {
+	case r.Token != "abcd" || r.Count != 6:
+		t.Errorf("got record %v count %v, want abcd count 6",
+			r.Token, r.Count)
+	case len(r.Files) != 1 || r.Files[0].Count != 5 ||
+		r.Files[0].Rate != 5:
+		t.Errorf("unexpected file counts %+v", r.Files)
+	}
+
+	// A new window allows new alerts
+	for i := 0; i < 3; i++ {
+		c.Add("abcd", []string{"image.png"})
+	}
+	if len(alerts) != 2 {
+		t.Errorf("got %v alerts, want 2", len(alerts))
+	}
+
+	// The rate drops to zero once a window passes without retrievals
+	now = now.Add(3 * window)
+	rs := c.Record("ef")
+	if len(rs) != 1 || rs[0].Token != "efgh" {
+		t.Fatalf("unexpected records %+v", rs)
+	}
+	if rs[0].Files[0].Rate != 0 {
+		t.Errorf("got rate %v, want 0", rs[0].Files[0].Rate)
+	}
+}
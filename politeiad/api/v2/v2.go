@@ -46,6 +46,18 @@ const (
 	// RoutePluginInventory returns all registered plugins.
 	RoutePluginInventory = "/plugininventory"
 
+	// RouteAccessCounts returns the record and record file retrieval
+	// counters. This route requires the politeiad RPC credentials.
+	RouteAccessCounts = "/accesscounts"
+
+	// RoutePluginJournal returns the plugin write journal entries. This
+	// route requires the politeiad RPC credentials.
+	RoutePluginJournal = "/pluginjournal"
+
+	// RouteTokenResolve returns the full tokens of the records whose
+	// token starts with the provided prefix.
+	RouteTokenResolve = "/tokenresolve"
+
 	// ChallengeSize is the size of a request challenge token in bytes.
 	ChallengeSize = 32
 )
@@ -135,10 +147,19 @@ const (
 	// not allowed since they will cause collisions.
 	ErrorCodeDuplicatePayload ErrorCodeT = 22
 
+	// ErrorCodePageTokenInvalid is returned when a plugin read page token
+	// cannot be decoded or does not correspond to a page of the reply.
+	ErrorCodePageTokenInvalid ErrorCodeT = 23
+
+	// ErrorCodePageInvalid is returned when a plugin read page request is
+	// made for a plugin command whose reply does not contain a pageable
+	// field.
+	ErrorCodePageInvalid ErrorCodeT = 24
+
 	// ErrorCodeLast is used by unit tests to verify that all error codes have
 	// a human readable entry in the ErrorCodes map. This error will never be
 	// returned.
-	ErrorCodeLast ErrorCodeT = 23
+	ErrorCodeLast ErrorCodeT = 25
 )
 
 var (
@@ -167,6 +188,8 @@ var (
 		ErrorCodeRecordStateInvalid:      "record state invalid",
 		ErrorCodeRecordStatusInvalid:     "record status invalid",
 		ErrorCodeDuplicatePayload:        "duplicate payload",
+		ErrorCodePageTokenInvalid:        "page token invalid",
+		ErrorCodePageInvalid:             "page invalid",
 	}
 )
 
@@ -185,10 +208,14 @@ func (e UserErrorReply) Error() string {
 
 // PluginErrorReply is the reply that the server returns when it encounters
 // a plugin error. The error code will be specific to the plugin.
+//
+// ErrorParams is optional and contains the values that the user input was
+// validated against. The param keys are defined by the plugin API.
 type PluginErrorReply struct {
-	PluginID     string `json:"pluginid"`
-	ErrorCode    uint32 `json:"errorcode"`
-	ErrorContext string `json:"errorcontext,omitempty"`
+	PluginID     string            `json:"pluginid"`
+	ErrorCode    uint32            `json:"errorcode"`
+	ErrorContext string            `json:"errorcontext,omitempty"`
+	ErrorParams  map[string]string `json:"errorparams,omitempty"`
 }
 
 // Error satisfies the error interface.
@@ -287,11 +314,17 @@ type MetadataStream struct {
 }
 
 // File represents a record file.
+//
+// CID is the IPFS content identifier of the file. It is only populated on
+// the files of public records when politeiad has been configured to pin
+// record files to IPFS and the file has been pinned. The CID is ignored on
+// requests.
 type File struct {
-	Name    string `json:"name"`    // Basename of the file
-	MIME    string `json:"mime"`    // MIME type
-	Digest  string `json:"digest"`  // SHA256 of decoded Payload
-	Payload string `json:"payload"` // Base64 encoded file payload
+	Name    string `json:"name"`          // Basename of the file
+	MIME    string `json:"mime"`          // MIME type
+	Digest  string `json:"digest"`        // SHA256 of decoded Payload
+	Payload string `json:"payload"`       // Base64 encoded file payload
+	CID     string `json:"cid,omitempty"` // IPFS CID
 }
 
 const (
@@ -302,7 +335,11 @@ const (
 	// token that has been shortened to improved UX. Short tokens can
 	// be used to retrieve record data but cannot be used on any routes
 	// that write record data. 7 characters was chosen to match the git
-	// abbreviated commitment hash size.
+	// abbreviated commitment hash size. This is the default and minimum
+	// length. politeiad can be configured to use a longer prefix length.
+	// The read routes accept token prefixes of any length between this
+	// length and the full token length, regardless of the configured
+	// length, as long as the prefix belongs to a single record.
 	ShortTokenLength = 7
 )
 
@@ -554,6 +591,52 @@ type PluginCmd struct {
 	ID      string `json:"id"`                // Plugin identifier
 	Command string `json:"command"`           // Plugin command
 	Payload string `json:"payload,omitempty"` // Command payload
+
+	// Page is optional and is only used by read-only plugin commands. If
+	// provided, the plugin reply payload is paged and only the requested
+	// page is returned. See PageRequest for the details.
+	Page *PageRequest `json:"page,omitempty"`
+}
+
+const (
+	// PluginReadPageSizeDefault is the page size that is used when a
+	// PageRequest does not specify a page size.
+	PluginReadPageSizeDefault uint32 = 100
+
+	// PluginReadPageSizeMax is the maximum page size that can be requested
+	// using a PageRequest.
+	PluginReadPageSizeMax uint32 = 500
+)
+
+// PageRequest requests a single page of a plugin read reply payload. This
+// allows replies that can grow very large, such as ticketvote results,
+// comment gets, and billing status changes, to be paged uniformly without
+// changes being required to the individual plugin commands.
+//
+// The reply payload must be a JSON object that contains exactly one array or
+// object field. This is the field that is paged. Object fields are paged
+// using the sorted ordering of their keys, where numeric keys, e.g. comment
+// IDs, are sorted numerically. All other fields of the reply payload are
+// returned as is on every page.
+//
+// Token is the opaque page token that was returned in the PageReply of the
+// previous page. An empty token requests the first page.
+//
+// Size is the number of entries that will be included in the page. The
+// PluginReadPageSizeDefault is used if a size is not provided.
+type PageRequest struct {
+	Token string `json:"token,omitempty"`
+	Size  uint32 `json:"size,omitempty"`
+}
+
+// PageReply contains the paging details of a plugin read reply payload that
+// was requested using a PageRequest.
+//
+// NextToken is the page token that should be used to request the next page.
+// It will only be populated if HasMore is true.
+type PageReply struct {
+	NextToken string `json:"nexttoken,omitempty"`
+	HasMore   bool   `json:"hasmore"`
 }
 
 // PluginWrite executes a plugin command that writes data.
@@ -590,6 +673,10 @@ type PluginCmdReply struct {
 	// PluginError will be populated if a plugin error occurred during
 	// plugin command execution.
 	PluginError *PluginErrorReply `json:"pluginerror,omitempty"`
+
+	// Page will only be populated if a PageRequest was included in the
+	// plugin command.
+	Page *PageReply `json:"page,omitempty"`
 }
 
 // PluginReadsReply is the reply to the PluginReads command.
@@ -620,3 +707,152 @@ type PluginInventoryReply struct {
 	Response string   `json:"response"` // Challenge response
 	Plugins  []Plugin `json:"plugins"`
 }
+
+const (
+	// AccessCountsPageSize is the maximum number of records that will be
+	// returned in an AccessCountsReply.
+	AccessCountsPageSize uint32 = 20
+)
+
+// AccessCounts requests the record and record file retrieval counters. If a
+// token is provided, the counters of the records whose token starts with the
+// provided token are returned. Otherwise, the counters of the most retrieved
+// records are returned. The counters are kept in memory and are reset when
+// politeiad is restarted.
+type AccessCounts struct {
+	Challenge string `json:"challenge"`       // Random challenge
+	Token     string `json:"token,omitempty"` // Optional token or prefix
+}
+
+// FileAccessCount contains the retrieval counters of a record file. Rate is
+// the number of times the file was retrieved during the last full minute.
+type FileAccessCount struct {
+	Name  string `json:"name"`
+	Count uint64 `json:"count"`
+	Rate  uint64 `json:"rate"`
+}
+
+// RecordAccessCount contains the retrieval counters of a record and its
+// files.
+type RecordAccessCount struct {
+	Token string            `json:"token"`
+	Count uint64            `json:"count"`
+	Files []FileAccessCount `json:"files"`
+}
+
+// AccessCountsReply is the reply to the AccessCounts command. Since is the
+// UNIX timestamp of when the counters were started.
+type AccessCountsReply struct {
+	Response string              `json:"response"` // Challenge response
+	Since    int64               `json:"since"`
+	Records  []RecordAccessCount `json:"records"`
+}
+
+// JournalStatusT represents the status of a journaled plugin write command.
+type JournalStatusT uint32
+
+const (
+	// JournalStatusInvalid is an invalid status.
+	JournalStatusInvalid JournalStatusT = 0
+
+	// JournalStatusIncomplete indicates that a result was not journaled
+	// for the command. The command is either still executing or was
+	// interrupted before it finished, which may have left the command
+	// partially applied.
+	JournalStatusIncomplete JournalStatusT = 1
+
+	// JournalStatusSuccess indicates that the command executed
+	// successfully.
+	JournalStatusSuccess JournalStatusT = 2
+
+	// JournalStatusFailed indicates that the command returned an error.
+	JournalStatusFailed JournalStatusT = 3
+)
+
+var (
+	// JournalStatuses contains the human readable journal statuses.
+	JournalStatuses = map[JournalStatusT]string{
+		JournalStatusInvalid:    "invalid",
+		JournalStatusIncomplete: "incomplete",
+		JournalStatusSuccess:    "success",
+		JournalStatusFailed:     "failed",
+	}
+)
+
+const (
+	// PluginJournalPageSize is the maximum number of journal entries that
+	// will be returned in a PluginJournalReply.
+	PluginJournalPageSize uint32 = 50
+)
+
+// PluginJournal requests a page of plugin write journal entries. Every plugin
+// write command is journaled before it is executed and the result of the
+// command is journaled once it has finished executing. The journal entries
+// contain the exact command payloads so that failed commands can be
+// diagnosed and replayed. The payloads and replies of commands on unvetted
+// records are not journaled. The journal is rotated once it reaches the size
+// limit that is configured by the server, so old entries may no longer be
+// available.
+//
+// Entries are returned in the order that they were journaled. The entries
+// with an ID greater than After are returned. All other fields are optional
+// filters.
+type PluginJournal struct {
+	Challenge string         `json:"challenge"` // Random challenge
+	After     uint64         `json:"after,omitempty"`
+	Token     string         `json:"token,omitempty"`
+	PluginID  string         `json:"pluginid,omitempty"`
+	Command   string         `json:"command,omitempty"`
+	Status    JournalStatusT `json:"status,omitempty"`
+}
+
+// JournalEntry is a journaled plugin write command and its result. Caller is
+// the remote address of the client that sent the command. Redacted indicates
+// that the command payload and reply were not journaled. Completed, Reply,
+// and Error are only populated once the command has finished executing.
+type JournalEntry struct {
+	ID        uint64         `json:"id"`
+	Timestamp int64          `json:"timestamp"`
+	Caller    string         `json:"caller"`
+	Cmd       PluginCmd      `json:"cmd"`
+	Redacted  bool           `json:"redacted,omitempty"`
+	Status    JournalStatusT `json:"status"`
+	Completed int64          `json:"completed,omitempty"`
+	Reply     string         `json:"reply,omitempty"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// PluginJournalReply is the reply to the PluginJournal command.
+type PluginJournalReply struct {
+	Response string         `json:"response"` // Challenge response
+	Entries  []JournalEntry `json:"entries"`
+}
+
+const (
+	// TokenResolvePageSize is the maximum number of tokens that will be
+	// returned in a TokenResolveReply.
+	TokenResolvePageSize uint32 = 20
+
+	// TokenResolvePrefixMin is the minimum length, in characters, of the
+	// prefix that can be resolved.
+	TokenResolvePrefixMin = 3
+)
+
+// TokenResolve requests the full tokens of the records whose token starts
+// with the provided hex encoded prefix. A prefix that is at least as long as
+// the configured token prefix length resolves to at most one token. Shorter
+// prefixes may be ambiguous.
+type TokenResolve struct {
+	Challenge string `json:"challenge"` // Random challenge
+	Prefix    string `json:"prefix"`
+}
+
+// TokenResolveReply is the reply to the TokenResolve command. PrefixLength is
+// the token prefix length that politeiad has been configured to use. Tokens
+// contains the matching full length tokens sorted in ascending order. The
+// tokens are limited to the TokenResolvePageSize.
+type TokenResolveReply struct {
+	Response     string   `json:"response"` // Challenge response
+	PrefixLength int      `json:"prefixlength"`
+	Tokens       []string `json:"tokens"`
+}
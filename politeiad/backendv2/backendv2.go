@@ -277,11 +277,14 @@ type Plugin struct {
 }
 
 // PluginError represents an error that occurred during plugin execution that
-// was caused by the user.
+// was caused by the user. ErrorParams is optional and contains the values
+// that were used to validate the user input, e.g. min, max, and got, so that
+// clients are able to present the error without parsing the error context.
 type PluginError struct {
 	PluginID     string
 	ErrorCode    uint32
 	ErrorContext string
+	ErrorParams  map[string]string
 }
 
 // Error satisfies the error interface.
@@ -311,6 +314,10 @@ type Backend interface {
 	// RecordExists returns whether a record exists.
 	RecordExists(token []byte) bool
 
+	// TokensByPrefix returns the full length tokens of the records
+	// whose hex encoded token starts with the provided prefix.
+	TokensByPrefix(prefix string) [][]byte
+
 	// RecordTimestamps returns the timestamps for a record. If no
 	// version is provided then timestamps for the most recent version
 	// will be returned.
@@ -360,6 +367,11 @@ type Backend interface {
 	// the coherency of record and plugin data and caches.
 	Fsck() error
 
+	// Reindex performs a synchronous rebuild of the caches and indexes
+	// of the specified plugin. The data of all other plugins is left
+	// untouched. A reindex can be safely restarted if interrupted.
+	Reindex(pluginID string) error
+
 	// Close performs cleanup of the backend.
 	Close()
 }
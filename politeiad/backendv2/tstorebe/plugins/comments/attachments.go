@@ -0,0 +1,378 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/decred/politeia/politeiad/api/v1/mime"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// dataDescriptorAttachment is the blob entry data descriptor of a
+	// comment attachment.
+	dataDescriptorAttachment = pluginID + "-attachment-v1"
+
+	// attachmentNameLengthMax is the maximum number of characters that
+	// are allowed in an attachment filename.
+	attachmentNameLengthMax = 255
+)
+
+// attachment is the structure that is saved to tstore for each comment
+// attachment. The comment ID and version are included so that the same image
+// can be attached to multiple comments without the blobs colliding.
+type attachment struct {
+	CommentID  uint32              `json:"commentid"`
+	Version    uint32              `json:"version"`
+	Attachment comments.Attachment `json:"attachment"`
+}
+
+// parseAttachmentMIMEs parses the JSON encoded attachment MIME types plugin
+// setting value and verifies that it is valid. Only image MIME types are
+// allowed.
+func parseAttachmentMIMEs(value string) ([]string, error) {
+	var mimes []string
+	err := json.Unmarshal([]byte(value), &mimes)
+	if err != nil {
+		return nil, err
+	}
+	dups := make(map[string]struct{}, len(mimes))
+	for _, v := range mimes {
+		if !strings.HasPrefix(v, "image/") {
+			return nil, fmt.Errorf("%v is not an image mime type", v)
+		}
+		if _, ok := dups[v]; ok {
+			return nil, fmt.Errorf("duplicate mime type %v", v)
+		}
+		dups[v] = struct{}{}
+	}
+	return mimes, nil
+}
+
+// attachmentsMsg returns the portion of the comment signature message that
+// corresponds to the provided attachments, i.e. the attachment digests
+// concatenated in order. An empty string is returned when there are no
+// attachments, which keeps the signature message of a comment without
+// attachments unchanged.
+func attachmentsMsg(as []comments.Attachment) string {
+	var b strings.Builder
+	for _, v := range as {
+		b.WriteString(v.Digest)
+	}
+	return b.String()
+}
+
+// attachmentsVerify verifies that the provided attachments adhere to the
+// attachment plugin settings and that each attachment payload matches its
+// declared MIME type and digest.
+func (p *commentsPlugin) attachmentsVerify(as []comments.Attachment) error {
+	if len(as) == 0 {
+		return nil
+	}
+	if len(as) > int(p.attachmentCountMax) {
+		return backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeAttachmentInvalid),
+			ErrorContext: fmt.Sprintf("max number of attachments is %v",
+				p.attachmentCountMax),
+		}
+	}
+	digests := make(map[string]struct{}, len(as))
+	for _, v := range as {
+		err := p.attachmentVerify(v)
+		if err != nil {
+			return backend.PluginError{
+				PluginID:     comments.PluginID,
+				ErrorCode:    uint32(comments.ErrorCodeAttachmentInvalid),
+				ErrorContext: fmt.Sprintf("%v: %v", v.Name, err),
+			}
+		}
+		if _, ok := digests[v.Digest]; ok {
+			return backend.PluginError{
+				PluginID:     comments.PluginID,
+				ErrorCode:    uint32(comments.ErrorCodeAttachmentInvalid),
+				ErrorContext: fmt.Sprintf("duplicate attachment %v", v.Name),
+			}
+		}
+		digests[v.Digest] = struct{}{}
+	}
+	return nil
+}
+
+// attachmentVerify verifies a single comment attachment. The returned error
+// is used as the error context of the user error.
+func (p *commentsPlugin) attachmentVerify(a comments.Attachment) error {
+	// Verify name
+	switch {
+	case a.Name == "":
+		return fmt.Errorf("name is empty")
+	case len(a.Name) > attachmentNameLengthMax:
+		return fmt.Errorf("name exceeds %v characters", attachmentNameLengthMax)
+	case strings.ContainsAny(a.Name, `/\`):
+		return fmt.Errorf("name contains a path separator")
+	}
+
+	// Verify MIME type
+	var allowed bool
+	for _, v := range p.mimeTypes {
+		if a.MIME == v {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("mime type %v is not allowed", a.MIME)
+	}
+
+	// Verify payload
+	b, err := base64.StdEncoding.DecodeString(a.Payload)
+	if err != nil {
+		return fmt.Errorf("invalid base64 payload")
+	}
+	switch {
+	case len(b) == 0:
+		return fmt.Errorf("payload is empty")
+	case len(b) > int(p.attachmentSizeMax):
+		return fmt.Errorf("size exceeds %v bytes", p.attachmentSizeMax)
+	}
+	if m := mime.DetectMimeType(b); m != a.MIME {
+		return fmt.Errorf("payload mime type is %v, want %v", m, a.MIME)
+	}
+	d := hex.EncodeToString(util.Digest(b))
+	if d != a.Digest {
+		return fmt.Errorf("digest is %v, want %v", a.Digest, d)
+	}
+
+	return nil
+}
+
+// attachmentsSave saves the provided attachments to tstore and returns the
+// blob digests of the saved attachments, in the same order as the provided
+// attachments.
+func (p *commentsPlugin) attachmentsSave(token []byte, commentID, version uint32, as []comments.Attachment) ([]string, error) {
+	if len(as) == 0 {
+		return nil, nil
+	}
+	var (
+		blobs   = make([]store.BlobEntry, 0, len(as))
+		digests = make([]string, 0, len(as))
+	)
+	for _, v := range as {
+		be, err := convertBlobEntryFromAttachment(attachment{
+			CommentID:  commentID,
+			Version:    version,
+			Attachment: v,
+		})
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, *be)
+		digests = append(digests, be.Digest)
+	}
+	errs, err := p.tstore.BlobsSave(token, blobs)
+	if err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			// Attachment was saved; continue
+		case errors.Is(err, backend.ErrDuplicatePayload):
+			// The attachment was saved by a previous attempt to
+			// save this comment version that failed before the
+			// comment itself could be saved; continue
+		default:
+			return nil, errors.Errorf("attachment %v: %v", as[i].Name, err)
+		}
+	}
+	return digests, nil
+}
+
+// attachments returns the attachments that correspond to the provided blob
+// digests, including the attachment payloads. The attachments are returned
+// in the same order as the provided digests.
+func (p *commentsPlugin) attachments(token []byte, digests []string) ([]comments.Attachment, error) {
+	ds := make([][]byte, 0, len(digests))
+	for _, v := range digests {
+		d, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, d)
+	}
+	blobs, err := p.tstore.Blobs(token, ds)
+	if err != nil {
+		return nil, err
+	}
+	as := make([]comments.Attachment, 0, len(digests))
+	for _, v := range digests {
+		be, ok := blobs[v]
+		if !ok {
+			return nil, errors.Errorf("blob not found: %v", v)
+		}
+		a, err := convertAttachmentFromBlobEntry(be)
+		if err != nil {
+			return nil, err
+		}
+		as = append(as, a.Attachment)
+	}
+	return as, nil
+}
+
+// attachmentBlobs returns the blob digests of the attachments of all versions
+// of the provided comment adds.
+func attachmentBlobs(adds []comments.CommentAdd) ([][]byte, error) {
+	digests := make([][]byte, 0, len(adds))
+	for _, ca := range adds {
+		for _, v := range ca.AttachmentBlobs {
+			d, err := hex.DecodeString(v)
+			if err != nil {
+				return nil, err
+			}
+			digests = append(digests, d)
+		}
+	}
+	return digests, nil
+}
+
+// cmdAttachments retrieves the attachments of the latest version of a
+// comment.
+func (p *commentsPlugin) cmdAttachments(token []byte, payload string) (string, error) {
+	// Decode payload
+	var a comments.Attachments
+	err := json.Unmarshal([]byte(payload), &a)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record state
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record index
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the comment exists
+	cidx, ok := ridx.Comments[a.CommentID]
+	if !ok {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeCommentNotFound),
+		}
+	}
+
+	// Get the attachments of the latest comment version. The
+	// attachments of a deleted comment have been deleted.
+	as := []comments.Attachment{}
+	if cidx.Del == nil {
+		latest := cidx.Adds[commentVersionLatest(cidx)]
+		adds, err := p.commentAdds(token, [][]byte{latest})
+		if err != nil {
+			return "", errors.Errorf("commentAdds: %v", err)
+		}
+		if len(adds[0].AttachmentBlobs) > 0 {
+			as, err = p.attachments(token, adds[0].AttachmentBlobs)
+			if err != nil {
+				return "", errors.Errorf("attachments: %v", err)
+			}
+		}
+	}
+
+	// Prepare reply
+	ar := comments.AttachmentsReply{
+		State:       comments.RecordStateT(state),
+		Attachments: as,
+	}
+	reply, err := json.Marshal(ar)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+func convertBlobEntryFromAttachment(a attachment) (*store.BlobEntry, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorAttachment,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+func convertAttachmentFromBlobEntry(be store.BlobEntry) (*attachment, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorAttachment {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, want %v",
+			dd.Descriptor, dataDescriptorAttachment)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var a attachment
+	err = json.Unmarshal(b, &a)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal attachment: %v", err)
+	}
+
+	return &a, nil
+}
+
+// convertAttachmentsMetadata returns the provided attachments with the
+// payloads removed.
+func convertAttachmentsMetadata(as []comments.Attachment) []comments.Attachment {
+	if len(as) == 0 {
+		return nil
+	}
+	md := make([]comments.Attachment, 0, len(as))
+	for _, v := range as {
+		v.Payload = ""
+		md = append(md, v)
+	}
+	return md
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/util"
+)
+
+func TestParseAttachmentMIMEs(t *testing.T) {
+	var tests = []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"default", comments.SettingAttachmentMIMETypes, false},
+		{"empty", `[]`, false},
+		{"not an image", `["text/html"]`, true},
+		{"duplicate", `["image/png","image/png"]`, true},
+		{"invalid json", `image/png`, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseAttachmentMIMEs(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAttachmentsVerify(t *testing.T) {
+	p := commentsPlugin{
+		attachmentCountMax: 2,
+		attachmentSizeMax:  16,
+		mimeTypes:          []string{"image/png"},
+	}
+
+	// newAttachment returns a valid attachment for the provided data
+	newAttachment := func(name, mime string, data []byte) comments.Attachment {
+		return comments.Attachment{
+			Name:    name,
+			MIME:    mime,
+			Digest:  hex.EncodeToString(util.Digest(data)),
+			Payload: base64.StdEncoding.EncodeToString(data),
+		}
+	}
+	var (
+		png    = []byte("\x89PNG\x0D\x0A\x1A\x0Adata")
+		png2   = []byte("\x89PNG\x0D\x0A\x1A\x0Adata2")
+		gif    = []byte("GIF89adata")
+		large  = append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), make([]byte, 16)...)
+		digest = newAttachment("chart.png", "image/png", png)
+	)
+	digest.Digest = hex.EncodeToString(util.Digest(png2))
+
+	var tests = []struct {
+		name        string
+		attachments []comments.Attachment
+		wantErr     bool
+	}{
+		{"none", nil, false},
+		{"valid", []comments.Attachment{
+			newAttachment("a.png", "image/png", png),
+			newAttachment("b.png", "image/png", png2),
+		}, false},
+		{"too many", []comments.Attachment{
+			newAttachment("a.png", "image/png", png),
+			newAttachment("b.png", "image/png", png2),
+			newAttachment("c.png", "image/png", png),
+		}, true},
+		{"duplicate", []comments.Attachment{
+			newAttachment("a.png", "image/png", png),
+			newAttachment("b.png", "image/png", png),
+		}, true},
+		{"empty name", []comments.Attachment{
+			newAttachment("", "image/png", png),
+		}, true},
+		{"path name", []comments.Attachment{
+			newAttachment("../a.png", "image/png", png),
+		}, true},
+		{"mime not allowed", []comments.Attachment{
+			newAttachment("a.gif", "image/gif", gif),
+		}, true},
+		{"mime mismatch", []comments.Attachment{
+			newAttachment("a.png", "image/png", gif),
+		}, true},
+		{"too large", []comments.Attachment{
+			newAttachment("a.png", "image/png", large),
+		}, true},
+		{"wrong digest", []comments.Attachment{digest}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := p.attachmentsVerify(tc.attachments)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var pe backend.PluginError
+			if !errors.As(err, &pe) || pe.ErrorCode !=
+				uint32(comments.ErrorCodeAttachmentInvalid) {
+				t.Fatalf("got err %v, want attachment invalid", err)
+			}
+		})
+	}
+
+	// The signature message must be unchanged for comments
+	// without attachments.
+	if msg := attachmentsMsg(nil); msg != "" {
+		t.Fatalf("got msg %v, want empty", msg)
+	}
+	as := []comments.Attachment{
+		newAttachment("a.png", "image/png", png),
+		newAttachment("b.png", "image/png", png2),
+	}
+	if msg := attachmentsMsg(as); msg != strings.Join(
+		[]string{as[0].Digest, as[1].Digest}, "") {
+		t.Fatalf("unexpected msg %v", msg)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 	"github.com/decred/politeia/politeiad/plugins/comments"
 	"github.com/decred/politeia/util"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +29,8 @@ const (
 	dataDescriptorCommentAdd  = pluginID + "-add-v1"
 	dataDescriptorCommentDel  = pluginID + "-del-v1"
 	dataDescriptorCommentVote = pluginID + "-vote-v1"
+	dataDescriptorUserStake   = pluginID + "-stake-v1"
+	dataDescriptorCommentPin  = pluginID + "-pin-v1"
 )
 
 // commentAddSave saves a CommentAdd to the backend.
@@ -259,7 +262,14 @@ func (p *commentsPlugin) comments(token []byte, ridx recordIndex, commentIDs []u
 		if !ok {
 			return nil, errors.Errorf("comment index not found %v", c.CommentID)
 		}
-		c.Downvotes, c.Upvotes = voteScore(cidx)
+		c.Downvotes, c.Upvotes = p.commentVoteScore(cidx)
+		c.Reactions = p.reactionTallies(cidx, c.Downvotes, c.Upvotes)
+		if p.stakeWeighting {
+			c.StakeDownvotes, c.StakeUpvotes = stakeVoteScore(cidx, ridx.Stakes)
+			if p.disableDownvotes {
+				c.StakeDownvotes = 0
+			}
+		}
 		// Populate creation timestamp
 		c.CreatedAt, err = p.commentCreationTimestamp(c, cidx)
 		if err != nil {
@@ -502,30 +512,8 @@ func voteScore(cidx commentIndex) (uint64, uint64) {
 	var upvotes uint64
 	var downvotes uint64
 	for _, votes := range cidx.Votes {
-		// Calculate the vote score that this user is contributing. This
-		// can only ever be -1, 0, or 1.
-		var score int64
-		for _, v := range votes {
-			vote := int64(v.Vote)
-			switch {
-			case score == 0:
-				// No previous vote. New vote becomes the score.
-				score = vote
-
-			case score == vote:
-				// New vote is the same as the previous vote. The vote gets
-				// removed from the score, making the score 0.
-				score = 0
-
-			case score != vote:
-				// New vote is different than the previous vote. New vote
-				// becomes the score.
-				score = vote
-			}
-		}
-
 		// Add the net result of all votes from this user to the totals.
-		switch score {
+		switch score := userVoteScore(votes); score {
 		case 0:
 			// Nothing to do
 		case -1:
@@ -541,6 +529,91 @@ func voteScore(cidx commentIndex) (uint64, uint64) {
 	return downvotes, upvotes
 }
 
+// commentVoteScore returns the total number of downvotes and upvotes,
+// respectively, for a comment that are returned by the plugin commands. The
+// downvotes are omitted when downvotes have been disabled.
+func (p *commentsPlugin) commentVoteScore(cidx commentIndex) (uint64, uint64) {
+	downvotes, upvotes := voteScore(cidx)
+	if p.disableDownvotes {
+		downvotes = 0
+	}
+	return downvotes, upvotes
+}
+
+// downvoteVerify verifies that a user is allowed to cast the provided vote
+// given their previous votes on the comment. Downvotes are rejected when
+// downvotes have been disabled, unless the user is retracting a downvote that
+// was cast before downvotes were disabled. Under the vote toggle semantics a
+// downvote is retracted by casting another downvote.
+func (p *commentsPlugin) downvoteVerify(vote comments.VoteT, votes []voteIndex) error {
+	if !p.disableDownvotes || vote != comments.VoteDownvote {
+		return nil
+	}
+	if userVoteScore(votes) == -1 {
+		// Retracting a previous downvote is allowed
+		return nil
+	}
+	return backend.PluginError{
+		PluginID:  comments.PluginID,
+		ErrorCode: uint32(comments.ErrorCodeDownvotesDisabled),
+	}
+}
+
+// userVoteScore returns the vote score that a user is contributing to a
+// comment. This can only ever be -1, 0, or 1. Reactions that are not mapped
+// to a vote do not contribute to the vote score.
+func userVoteScore(votes []voteIndex) int64 {
+	var score int64
+	for _, v := range votes {
+		if v.Reaction != "" {
+			continue
+		}
+		vote := int64(v.Vote)
+		switch {
+		case score == 0:
+			// No previous vote. New vote becomes the score.
+			score = vote
+
+		case score == vote:
+			// New vote is the same as the previous vote. The vote gets
+			// removed from the score, making the score 0.
+			score = 0
+
+		case score != vote:
+			// New vote is different than the previous vote. New vote
+			// becomes the score.
+			score = vote
+		}
+	}
+	return score
+}
+
+// voteCooldownRemaining returns the number of seconds that a user must wait
+// before they are allowed to cast another vote for the provided reaction. An
+// empty reaction applies to the plain votes. The cooldown is measured from
+// the user's most recent vote for the reaction.
+func voteCooldownRemaining(votes []voteIndex, reaction string, cooldown uint32, now int64) int64 {
+	if cooldown == 0 {
+		return 0
+	}
+	var last int64
+	for _, v := range votes {
+		if v.Reaction == reaction && v.Timestamp > last {
+			last = v.Timestamp
+		}
+	}
+	if last == 0 {
+		// No previous vote or the previous votes were indexed
+		// without a timestamp.
+		return 0
+	}
+	remaining := last + int64(cooldown) - now
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
 // cmdNew creates a new comment.
 func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 	// Decode payload
@@ -562,15 +635,37 @@ func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 		return "", err
 	}
 
+	// Verify content type
+	contentType, err := verifyContentType(n.ContentType)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify attachments
+	err = p.attachmentsVerify(n.Attachments)
+	if err != nil {
+		return "", err
+	}
+
 	// Verify signature
 	msg := strconv.FormatUint(uint64(n.State), 10) + n.Token +
 		strconv.FormatUint(uint64(n.ParentID), 10) + n.Comment +
-		n.ExtraData + n.ExtraDataHint
+		n.ExtraData + n.ExtraDataHint + attachmentsMsg(n.Attachments)
 	err = util.VerifySignature(n.Signature, n.PublicKey, msg)
 	if err != nil {
 		return "", convertSignatureError(err)
 	}
 
+	// Verify user ID. The user ID is used to index the comments of
+	// the user.
+	_, err = uuid.Parse(n.UserID)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeUserIDInvalid),
+		}
+	}
+
 	// Verify comment
 	if len(n.Comment) == 0 {
 		return "", backend.PluginError{
@@ -586,6 +681,10 @@ func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 				p.commentLengthMax),
 		}
 	}
+	err = p.markdownVerify(contentType, n.Comment)
+	if err != nil {
+		return "", err
+	}
 
 	// Verify record state
 	state, err := p.tstore.RecordState(token)
@@ -630,10 +729,19 @@ func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 		Version:       1,
 		Timestamp:     time.Now().Unix(),
 		Receipt:       hex.EncodeToString(receipt[:]),
+		ContentType:   contentType,
+		Attachments:   convertAttachmentsMetadata(n.Attachments),
 		ExtraData:     n.ExtraData,
 		ExtraDataHint: n.ExtraDataHint,
 	}
 
+	// Save the attachments
+	ca.AttachmentBlobs, err = p.attachmentsSave(token, ca.CommentID,
+		ca.Version, n.Attachments)
+	if err != nil {
+		return "", err
+	}
+
 	// Save comment
 	digest, err := p.commentAddSave(token, ca)
 	if err != nil {
@@ -649,8 +757,11 @@ func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 		Votes: make(map[string][]voteIndex),
 	}
 
-	// Save the updated index
+	// Save the updated indexes
 	p.recordIndexSave(token, state, *ridx)
+	p.commentCountSave(token, state, *ridx)
+	p.userIndexAdd(ca.UserID, state, token,
+		map[uint32]int64{ca.CommentID: ca.Timestamp})
 
 	log.Debugf("Comment saved to record %v comment ID %v",
 		ca.Token, ca.CommentID)
@@ -673,6 +784,30 @@ func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 	return string(reply), nil
 }
 
+// editVerify verifies that a comment that was submitted at the provided
+// timestamp and that has already been edited the provided number of times can
+// be edited again. Comment edits are allowed only during the timeframe set by
+// the editPeriod plugin setting and only up to the number of times set by the
+// editsMax plugin setting. An editsMax of 0 does not limit the number of
+// edits.
+func (p *commentsPlugin) editVerify(timestamp int64, edits int, now int64) error {
+	if now > timestamp+int64(p.editPeriod) {
+		return backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeEditPeriodExpired),
+			ErrorContext: fmt.Sprintf("edit period is %v seconds", p.editPeriod),
+		}
+	}
+	if p.editsMax > 0 && edits >= int(p.editsMax) {
+		return backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeEditsMaxExceeded),
+			ErrorContext: fmt.Sprintf("max edits is %v", p.editsMax),
+		}
+	}
+	return nil
+}
+
 // cmdEdit edits an existing comment.
 func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 	// Check if comment edits are allowed
@@ -703,11 +838,24 @@ func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 		return "", err
 	}
 
+	// Verify content type
+	contentType, err := verifyContentType(e.ContentType)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify attachments
+	err = p.attachmentsVerify(e.Attachments)
+	if err != nil {
+		return "", err
+	}
+
 	// Verify signature
 	msg := strconv.FormatUint(uint64(e.State), 10) + e.Token +
 		strconv.FormatUint(uint64(e.ParentID), 10) +
 		strconv.FormatUint(uint64(e.CommentID), 10) +
-		e.Comment + e.ExtraData + e.ExtraDataHint
+		e.Comment + e.ExtraData + e.ExtraDataHint +
+		attachmentsMsg(e.Attachments)
 	err = util.VerifySignature(e.Signature, e.PublicKey, msg)
 	if err != nil {
 		return "", convertSignatureError(err)
@@ -728,6 +876,10 @@ func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 				p.commentLengthMax),
 		}
 	}
+	err = p.markdownVerify(contentType, e.Comment)
+	if err != nil {
+		return "", err
+	}
 
 	// Verify record state
 	state, err := p.tstore.RecordState(token)
@@ -763,14 +915,10 @@ func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 		return "", err
 	}
 
-	// Comment edits are allowed only during the timeframe
-	// set by the editPeriod plugin setting.
-	if time.Now().Unix() > cf.Timestamp+int64(p.editPeriod) {
-		return "", backend.PluginError{
-			PluginID:     comments.PluginID,
-			ErrorCode:    uint32(comments.ErrorCodeEditNotAllowed),
-			ErrorContext: "comment edits timeframe has expired",
-		}
+	// Verify that the comment can still be edited
+	err = p.editVerify(cf.Timestamp, len(cidx.Adds)-1, time.Now().Unix())
+	if err != nil {
+		return "", err
 	}
 
 	// Get the existing comment
@@ -817,9 +965,12 @@ func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 		}
 	}
 
-	// Verify comment changes
+	// Verify comment changes. A content type change is a change to
+	// how the comment is rendered and is allowed on its own.
 	if e.Comment == existing.Comment &&
-		e.ExtraData == existing.ExtraData {
+		e.ExtraData == existing.ExtraData &&
+		contentType == existing.ContentType &&
+		attachmentsMsg(e.Attachments) == attachmentsMsg(existing.Attachments) {
 		return "", backend.PluginError{
 			PluginID:  comments.PluginID,
 			ErrorCode: uint32(comments.ErrorCodeNoChanges),
@@ -840,10 +991,19 @@ func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 		Version:       existing.Version + 1,
 		Timestamp:     time.Now().Unix(),
 		Receipt:       hex.EncodeToString(receipt[:]),
+		ContentType:   contentType,
+		Attachments:   convertAttachmentsMetadata(e.Attachments),
 		ExtraData:     e.ExtraData,
 		ExtraDataHint: e.ExtraDataHint,
 	}
 
+	// Save the attachments
+	ca.AttachmentBlobs, err = p.attachmentsSave(token, ca.CommentID,
+		ca.Version, e.Attachments)
+	if err != nil {
+		return "", err
+	}
+
 	// Save comment
 	digest, err := p.commentAddSave(token, ca)
 	if err != nil {
@@ -910,6 +1070,23 @@ func (p *commentsPlugin) verifyExtraData(extraData, extraDataHint string) error
 	return nil
 }
 
+// verifyContentType verifies that the provided comment content type is
+// supported and returns it. ContentTypeMarkdown is returned if a content type
+// is not provided.
+func verifyContentType(contentType string) (string, error) {
+	if contentType == "" {
+		return comments.ContentTypeMarkdown, nil
+	}
+	if _, ok := comments.ContentTypes[contentType]; !ok {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeContentTypeInvalid),
+			ErrorContext: fmt.Sprintf("unsupported content type %v", contentType),
+		}
+	}
+	return contentType, nil
+}
+
 // cmdDel deletes a comment.
 func (p *commentsPlugin) cmdDel(token []byte, payload string) (string, error) {
 	// Decode payload
@@ -919,6 +1096,15 @@ func (p *commentsPlugin) cmdDel(token []byte, payload string) (string, error) {
 		return "", err
 	}
 
+	// Check if author deletes are allowed
+	if d.Author && !p.allowAuthorDeletes {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeDelNotAllowed),
+			ErrorContext: "comments plugin setting 'allowauthordeletes' is off",
+		}
+	}
+
 	// Verify token
 	err = tokenVerify(token, d.Token)
 	if err != nil {
@@ -965,6 +1151,15 @@ func (p *commentsPlugin) cmdDel(token []byte, payload string) (string, error) {
 		}
 	}
 
+	// Verify that author deletes are made by the comment author
+	if d.Author && d.UserID != existing.UserID {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeUserUnauthorized),
+			ErrorContext: "user is not the comment author",
+		}
+	}
+
 	// Prepare comment delete
 	receipt := p.identity.SignMessage([]byte(d.Signature))
 	cd := comments.CommentDel{
@@ -976,62 +1171,197 @@ func (p *commentsPlugin) cmdDel(token []byte, payload string) (string, error) {
 		Signature: d.Signature,
 		ParentID:  existing.ParentID,
 		UserID:    existing.UserID,
+		Author:    d.Author,
 		Timestamp: time.Now().Unix(),
 		Receipt:   hex.EncodeToString(receipt[:]),
 	}
 
+	// Delete the comment
+	err = p.commentDel(token, state, ridx, cd)
+	if err != nil {
+		return "", err
+	}
+
+	// Return updated comment
+	c, err := p.comment(token, *ridx, d.CommentID)
+	if err != nil {
+		return "", fmt.Errorf("comment %v: %v", d.CommentID, err)
+	}
+
+	// Prepare reply
+	dr := comments.DelReply{
+		Comment: *c,
+	}
+	reply, err := json.Marshal(dr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdDelUser deletes all of the comments that a user has made on a record.
+func (p *commentsPlugin) cmdDelUser(token []byte, payload string) (string, error) {
+	// Decode payload
+	var d comments.DelUser
+	err := json.Unmarshal([]byte(payload), &d)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify user ID
+	_, err = uuid.Parse(d.UserID)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeUserIDInvalid),
+		}
+	}
+
+	// Verify signature
+	msg := d.UserID + d.Reason
+	err = util.VerifySignature(d.Signature, d.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Get the record index
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the user's comments that have not been deleted yet. All of
+	// the comments of the record are looked up instead of relying on
+	// the user index so that no comments are missed if the user index
+	// cache is not coherent.
+	commentIDs := make([]uint32, 0, len(ridx.Comments))
+	for id, cidx := range ridx.Comments {
+		if cidx.Del != nil {
+			continue
+		}
+		commentIDs = append(commentIDs, id)
+	}
+	cs, err := p.comments(token, *ridx, commentIDs)
+	if err != nil {
+		return "", fmt.Errorf("comments: %v", err)
+	}
+	commentIDs = commentIDs[:0]
+	for id, c := range cs {
+		if c.UserID != d.UserID || c.Deleted {
+			continue
+		}
+		commentIDs = append(commentIDs, id)
+	}
+	sort.Slice(commentIDs, func(i, j int) bool {
+		return commentIDs[i] < commentIDs[j]
+	})
+
+	// Delete the comments. A separate CommentDel is saved for each
+	// comment. They all contain the signature of the DelUser command.
+	receipt := p.identity.SignMessage([]byte(d.Signature))
+	for _, id := range commentIDs {
+		existing := cs[id]
+		cd := comments.CommentDel{
+			Token:     existing.Token,
+			State:     existing.State,
+			CommentID: id,
+			Reason:    d.Reason,
+			PublicKey: d.PublicKey,
+			Signature: d.Signature,
+			ParentID:  existing.ParentID,
+			UserID:    existing.UserID,
+			Bulk:      true,
+			Timestamp: time.Now().Unix(),
+			Receipt:   hex.EncodeToString(receipt[:]),
+		}
+		err = p.commentDel(token, state, ridx, cd)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	log.Debugf("Comments of user %v deleted on %x: %v",
+		d.UserID, token, commentIDs)
+
+	// Return the updated comments
+	deleted, err := p.comments(token, *ridx, commentIDs)
+	if err != nil {
+		return "", fmt.Errorf("comments: %v", err)
+	}
+	dr := comments.DelUserReply{
+		Comments: make([]comments.Comment, 0, len(commentIDs)),
+	}
+	for _, id := range commentIDs {
+		dr.Comments = append(dr.Comments, deleted[id])
+	}
+	reply, err := json.Marshal(dr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// commentDel saves the provided CommentDel, updates the record index, and
+// deletes all versions of the comment along with their attachments. The
+// updated record index and comment count are saved to the cache.
+func (p *commentsPlugin) commentDel(token []byte, state backend.StateT, ridx *recordIndex, cd comments.CommentDel) error {
 	// Save comment del
 	digest, err := p.commentDelSave(token, cd)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	// Update the index
-	cidx, ok := ridx.Comments[d.CommentID]
+	cidx, ok := ridx.Comments[cd.CommentID]
 	if !ok {
 		// Should not be possible. The cache is not coherent.
-		panic(fmt.Sprintf("comment not found in index: %v", d.CommentID))
+		panic(fmt.Sprintf("comment not found in index: %v", cd.CommentID))
 	}
 	cidx.Del = digest
-	ridx.Comments[d.CommentID] = cidx
+	ridx.Comments[cd.CommentID] = cidx
 
-	// Svae the updated index
+	// Save the updated indexes. Deleted comments are still included
+	// in the comment count, but the count is saved so that it always
+	// reflects the most recent record index.
 	p.recordIndexSave(token, state, *ridx)
+	p.commentCountSave(token, state, *ridx)
 
-	// Delete all comment versions. A comment is considered deleted
-	// once the CommenDel record has been saved. If attempts to
-	// actually delete the blobs fails, simply log the error and
-	// continue command execution. The period fsck will clean this up
-	// next time it is run.
+	// Delete all comment versions and their attachments. A comment
+	// is considered deleted once the CommenDel record has been saved.
+	// If attempts to actually delete the blobs fails, simply log the
+	// error and continue command execution. The period fsck will
+	// clean this up next time it is run.
 	digests := make([][]byte, 0, len(cidx.Adds))
 	for _, v := range cidx.Adds {
 		digests = append(digests, v)
 	}
-	err = p.tstore.BlobsDel(token, digests)
+	adds, err := p.commentAdds(token, digests)
 	if err != nil {
-		log.Errorf("comments cmdDel %x: BlobsDel %x: %v ",
+		log.Errorf("comments commentDel %x: commentAdds %x: %v",
 			token, digests, err)
+	} else {
+		ads, err := attachmentBlobs(adds)
+		if err != nil {
+			log.Errorf("comments commentDel %x: attachmentBlobs: %v", token, err)
+		}
+		digests = append(digests, ads...)
 	}
-
-	// Return updated comment
-	c, err := p.comment(token, *ridx, d.CommentID)
-	if err != nil {
-		return "", fmt.Errorf("comment %v: %v", d.CommentID, err)
-	}
-
-	// Prepare reply
-	dr := comments.DelReply{
-		Comment: *c,
-	}
-	reply, err := json.Marshal(dr)
+	err = p.tstore.BlobsDel(token, digests)
 	if err != nil {
-		return "", err
+		log.Errorf("comments commentDel %x: BlobsDel %x: %v ",
+			token, digests, err)
 	}
 
-	return string(reply), nil
+	return nil
 }
 
-// cmdVote casts a upvote/downvote for a comment.
+// cmdVote casts a upvote/downvote or a reaction for a comment.
 func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 	// Decode payload
 	var v comments.Vote
@@ -1057,10 +1387,17 @@ func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 		}
 	}
 
-	// Verify signature
+	// Verify reaction
+	reaction, err := p.reactionVerify(v)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify signature. The reaction is only part of the signature
+	// message when it is provided.
 	msg := strconv.FormatUint(uint64(v.State), 10) + v.Token +
 		strconv.FormatUint(uint64(v.CommentID), 10) +
-		strconv.FormatInt(int64(v.Vote), 10)
+		strconv.FormatInt(int64(v.Vote), 10) + v.Reaction
 	err = util.VerifySignature(v.Signature, v.PublicKey, msg)
 	if err != nil {
 		return "", convertSignatureError(err)
@@ -1094,11 +1431,34 @@ func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 		}
 	}
 
-	// Verify user has not exceeded max allowed vote changes
-	if len(cidx.Votes[v.UserID]) > int(p.voteChangesMax) {
+	// Verify downvotes are allowed
+	err = p.downvoteVerify(v.Vote, cidx.Votes[v.UserID])
+	if err != nil {
+		return "", err
+	}
+
+	// Verify user has not exceeded max allowed vote changes. The
+	// vote changes of each reaction are limited separately.
+	if reactionVotes(cidx.Votes[v.UserID], reaction) > int(p.voteChangesMax) {
 		return "", backend.PluginError{
 			PluginID:  comments.PluginID,
 			ErrorCode: uint32(comments.ErrorCodeVoteChangesMaxExceeded),
+			ErrorContext: fmt.Sprintf("max vote changes is %v",
+				p.voteChangesMax),
+		}
+	}
+
+	// Verify the vote change cooldown has elapsed since the user's
+	// previous vote
+	now := time.Now().Unix()
+	remaining := voteCooldownRemaining(cidx.Votes[v.UserID], reaction,
+		p.voteChangeCooldown, now)
+	if remaining > 0 {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeVoteChangeCooldown),
+			ErrorContext: fmt.Sprintf("vote can be changed in %v seconds",
+				remaining),
 		}
 	}
 
@@ -1129,7 +1489,8 @@ func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 		Vote:      v.Vote,
 		PublicKey: v.PublicKey,
 		Signature: v.Signature,
-		Timestamp: time.Now().Unix(),
+		Reaction:  v.Reaction,
+		Timestamp: now,
 		Receipt:   hex.EncodeToString(receipt[:]),
 	}
 
@@ -1145,8 +1506,10 @@ func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 		votes = make([]voteIndex, 0, 1)
 	}
 	votes = append(votes, voteIndex{
-		Vote:   cv.Vote,
-		Digest: digest,
+		Vote:      cv.Vote,
+		Digest:    digest,
+		Reaction:  reaction,
+		Timestamp: cv.Timestamp,
 	})
 	cidx.Votes[cv.UserID] = votes
 	ridx.Comments[cv.CommentID] = cidx
@@ -1155,12 +1518,13 @@ func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 	p.recordIndexSave(token, state, *ridx)
 
 	// Calculate the new vote scores
-	downvotes, upvotes := voteScore(cidx)
+	downvotes, upvotes := p.commentVoteScore(cidx)
 
 	// Prepare reply
 	vr := comments.VoteReply{
 		Downvotes: downvotes,
 		Upvotes:   upvotes,
+		Reactions: p.reactionTallies(cidx, downvotes, upvotes),
 		Timestamp: cv.Timestamp,
 		Receipt:   cv.Receipt,
 	}
@@ -1182,6 +1546,12 @@ func (p *commentsPlugin) cmdGet(token []byte, payload string) (string, error) {
 		return "", err
 	}
 
+	// Verify sort order
+	err = p.sortVerify(g.SortBy)
+	if err != nil {
+		return "", err
+	}
+
 	// Get record state
 	state, err := p.tstore.RecordState(token)
 	if err != nil {
@@ -1204,6 +1574,9 @@ func (p *commentsPlugin) cmdGet(token []byte, payload string) (string, error) {
 	gr := comments.GetReply{
 		Comments: cs,
 	}
+	if g.SortBy != comments.SortInvalid {
+		gr.Order = sortComments(cs, g.SortBy)
+	}
 	reply, err := json.Marshal(gr)
 	if err != nil {
 		return "", err
@@ -1260,6 +1633,130 @@ func (p *commentsPlugin) cmdGetAll(token []byte) (string, error) {
 	return string(reply), nil
 }
 
+// cmdGetPage retrieves a page of comments for a record. The latest version of
+// each comment is returned. The comments are sorted using the record index so
+// that only the comments of the requested page need to be retrieved.
+func (p *commentsPlugin) cmdGetPage(token []byte, payload string) (string, error) {
+	// Decode payload
+	var gp comments.GetPage
+	err := json.Unmarshal([]byte(payload), &gp)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify sort order
+	err = p.sortVerify(gp.SortBy)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record state
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record index
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Compile the comment IDs of the requested page
+	var (
+		order    = commentsOrder(*ridx, gp.SortBy)
+		pageSize = gp.PageSize
+	)
+	if pageSize == 0 || pageSize > p.commentsPageSize {
+		pageSize = p.commentsPageSize
+	}
+	commentIDs := commentsPage(order, gp.Page, pageSize)
+
+	// Get comments
+	c, err := p.comments(token, *ridx, commentIDs)
+	if err != nil {
+		return "", fmt.Errorf("comments: %v", err)
+	}
+	cs := make([]comments.Comment, 0, len(commentIDs))
+	for _, v := range commentIDs {
+		cm, ok := c[v]
+		if !ok {
+			return "", fmt.Errorf("comment not found %v", v)
+		}
+		cs = append(cs, cm)
+	}
+
+	// Prepare reply
+	gpr := comments.GetPageReply{
+		Comments: cs,
+		Total:    uint32(len(order)),
+	}
+	reply, err := json.Marshal(gpr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// sortVerify verifies that the provided comments sort order is supported.
+func (p *commentsPlugin) sortVerify(sortBy comments.SortT) error {
+	switch sortBy {
+	case comments.SortInvalid, comments.SortScore, comments.SortTimestamp:
+		// These are allowed
+	case comments.SortStakeScore:
+		if !p.stakeWeighting {
+			return backend.PluginError{
+				PluginID:  comments.PluginID,
+				ErrorCode: uint32(comments.ErrorCodeStakeWeightingDisabled),
+			}
+		}
+	default:
+		return backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeSortInvalid),
+			ErrorContext: fmt.Sprintf("invalid sort %v", sortBy),
+		}
+	}
+	return nil
+}
+
+// commentsOrder returns the IDs of all comments in the record index in the
+// provided sort order. The comment scores are calculated using the votes in
+// the record index.
+func commentsOrder(ridx recordIndex, sortBy comments.SortT) []uint32 {
+	cs := make(map[uint32]comments.Comment, len(ridx.Comments))
+	for id, cidx := range ridx.Comments {
+		c := comments.Comment{
+			CommentID: id,
+		}
+		c.Downvotes, c.Upvotes = voteScore(cidx)
+		if sortBy == comments.SortStakeScore {
+			c.StakeDownvotes, c.StakeUpvotes = stakeVoteScore(cidx, ridx.Stakes)
+		}
+		cs[id] = c
+	}
+	return sortComments(cs, sortBy)
+}
+
+// commentsPage returns the comment IDs of the requested page. Page 1 is
+// returned if a page is not provided. An empty page is returned if the page
+// does not exist.
+func commentsPage(order []uint32, page, pageSize uint32) []uint32 {
+	if page == 0 {
+		page = 1
+	}
+	start := uint64(page-1) * uint64(pageSize)
+	if start >= uint64(len(order)) {
+		return []uint32{}
+	}
+	end := start + uint64(pageSize)
+	if end > uint64(len(order)) {
+		end = uint64(len(order))
+	}
+	return order[start:end]
+}
+
 // cmdGetVersion retrieves the specified version of a comment.
 func (p *commentsPlugin) cmdGetVersion(token []byte, payload string) (string, error) {
 	// Decode payload
@@ -1318,7 +1815,8 @@ func (p *commentsPlugin) cmdGetVersion(token []byte, payload string) (string, er
 
 	// Convert to a comment
 	c := convertCommentFromCommentAdd(adds[0])
-	c.Downvotes, c.Upvotes = voteScore(cidx)
+	c.Downvotes, c.Upvotes = p.commentVoteScore(cidx)
+	c.Reactions = p.reactionTallies(cidx, c.Downvotes, c.Upvotes)
 
 	// Prepare reply
 	gvr := comments.GetVersionReply{
@@ -1333,7 +1831,9 @@ func (p *commentsPlugin) cmdGetVersion(token []byte, payload string) (string, er
 }
 
 // cmdCount retrieves the comments count for a record. The comments count is
-// the number of comments that have been made on a record.
+// the number of comments that have been made on a record. The count and the
+// pinned comment ID are served from the comment count cache so that the record
+// index does not need to be decoded.
 func (p *commentsPlugin) cmdCount(token []byte) (string, error) {
 	// Get record state
 	state, err := p.tstore.RecordState(token)
@@ -1341,15 +1841,29 @@ func (p *commentsPlugin) cmdCount(token []byte) (string, error) {
 		return "", err
 	}
 
-	// Get record index
-	ridx, err := p.recordIndex(token, state)
+	// Get the cached comment count
+	count, ok, err := p.commentCount(token, state)
 	if err != nil {
 		return "", err
 	}
+	if !ok {
+		// The comment count has not been cached yet. This will be
+		// the case for records whose comments were made prior to
+		// the comment count being cached. Derive the count from the
+		// record index and cache it.
+		ridx, err := p.recordIndex(token, state)
+		if err != nil {
+			return "", err
+		}
+		p.commentCountSave(token, state, *ridx)
+		c := newCommentCount(*ridx)
+		count = &c
+	}
 
 	// Prepare reply
 	cr := comments.CountReply{
-		Count: uint32(len(ridx.Comments)),
+		Count:    count.Count,
+		PinnedID: count.PinnedID,
 	}
 	reply, err := json.Marshal(cr)
 	if err != nil {
@@ -1359,6 +1873,87 @@ func (p *commentsPlugin) cmdCount(token []byte) (string, error) {
 	return string(reply), nil
 }
 
+// cmdUser retrieves the IDs of the comments that were made by a user. The
+// comments are looked up using the user index. If a token is provided, only
+// the comments that were made on that record are returned.
+func (p *commentsPlugin) cmdUser(token []byte, payload string) (string, error) {
+	// Decode payload
+	var u comments.User
+	err := json.Unmarshal([]byte(payload), &u)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the user index
+	uidx, err := p.userIndex(u.UserID)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeUserIDInvalid),
+			ErrorContext: err.Error(),
+		}
+	}
+
+	// Compile the records that the user has commented on
+	records := make([]comments.UserRecordComments, 0, 64)
+	for _, s := range []backend.StateT{backend.StateUnvetted,
+		backend.StateVetted} {
+		for t, ids := range uidx.records(s) {
+			if len(token) > 0 && t != hex.EncodeToString(token) {
+				continue
+			}
+			records = append(records, comments.UserRecordComments{
+				Token:      t,
+				State:      comments.RecordStateT(s),
+				CommentIDs: ids,
+			})
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Token == records[j].Token {
+			return records[i].State < records[j].State
+		}
+		return records[i].Token < records[j].Token
+	})
+
+	// Get the comments if they were requested
+	if u.IncludeComments {
+		for i, v := range records {
+			t, err := tokenDecode(v.Token)
+			if err != nil {
+				return "", err
+			}
+			ridx, err := p.recordIndex(t, backend.StateT(v.State))
+			if err != nil {
+				return "", err
+			}
+			cs, err := p.comments(t, *ridx, v.CommentIDs)
+			if err != nil {
+				return "", fmt.Errorf("comments: %v", err)
+			}
+			records[i].Comments = make([]comments.Comment, 0, len(cs))
+			for _, id := range v.CommentIDs {
+				c, ok := cs[id]
+				if !ok {
+					continue
+				}
+				records[i].Comments = append(records[i].Comments, c)
+			}
+		}
+	}
+
+	// Prepare reply
+	ur := comments.UserReply{
+		Records: records,
+	}
+	reply, err := json.Marshal(ur)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
 // cmdVotes retrieves the comment votes that meet the provided filtering
 // criteria.
 func (p *commentsPlugin) cmdVotes(token []byte, payload string) (string, error) {
@@ -1382,8 +1977,8 @@ func (p *commentsPlugin) cmdVotes(token []byte, payload string) (string, error)
 	}
 
 	// Collect the requested page of comment vote digests
-	digests := collectVoteDigestsPage(ridx.Comments, v.UserID, v.Page,
-		p.votesPageSize)
+	digests := collectVoteDigestsPage(ridx.Comments, v.UserID, v.Since,
+		v.Page, p.votesPageSize)
 
 	// Lookup votes
 	votes, err := p.commentVotes(token, digests)
@@ -1409,8 +2004,9 @@ func (p *commentsPlugin) cmdVotes(token []byte, payload string) (string, error)
 }
 
 // collectVoteDigestsPage accepts a map of all comment indexes with a
-// filtering criteria and it collects the requested page.
-func collectVoteDigestsPage(commentIdxes map[uint32]commentIndex, userID string, page, pageSize uint32) [][]byte {
+// filtering criteria and it collects the requested page. Votes that were cast
+// before the since timestamp are filtered out when since is not 0.
+func collectVoteDigestsPage(commentIdxes map[uint32]commentIndex, userID string, since int64, page, pageSize uint32) [][]byte {
 	// Default to first page if page is not provided
 	if page == 0 {
 		page = 1
@@ -1438,6 +2034,11 @@ func collectVoteDigestsPage(commentIdxes map[uint32]commentIndex, userID string,
 				continue
 			}
 			for _, vidx := range voteIdxs {
+				if vidx.Timestamp < since {
+					// Vote was cast before the since timestamp
+					continue
+				}
+
 				// Add digest if it's part of the requested page
 				if isInPageRange(idx, pageFirstIndex, pageLastIndex) {
 					digests = append(digests, vidx.Digest)
@@ -1458,6 +2059,11 @@ func collectVoteDigestsPage(commentIdxes map[uint32]commentIndex, userID string,
 			userIDs := getSortedUserIDs(cidx.Votes)
 			for _, userID := range userIDs {
 				for _, vidx := range cidx.Votes[userID] {
+					if vidx.Timestamp < since {
+						// Vote was cast before the since timestamp
+						continue
+					}
+
 					// Add digest if it's part of the requested page
 					if isInPageRange(idx, pageFirstIndex, pageLastIndex) {
 						digests = append(digests, vidx.Digest)
@@ -1506,7 +2112,7 @@ func (p *commentsPlugin) cmdTimestamps(token []byte, payload string) (string, er
 	}
 
 	// Get timestamps
-	ctr, err := p.commentTimestamps(token, t.CommentIDs, t.IncludeVotes)
+	ctr, err := p.commentTimestampsCached(token, t.CommentIDs, t.IncludeVotes)
 	if err != nil {
 		return "", err
 	}
@@ -1580,6 +2186,12 @@ func commentIDLatest(idx recordIndex) uint32 {
 }
 
 func convertCommentFromCommentAdd(ca comments.CommentAdd) comments.Comment {
+	// Comments that were saved before content types were introduced
+	// are markdown.
+	contentType := ca.ContentType
+	if contentType == "" {
+		contentType = comments.ContentTypeMarkdown
+	}
 	return comments.Comment{
 		UserID:        ca.UserID,
 		State:         ca.State,
@@ -1596,6 +2208,8 @@ func convertCommentFromCommentAdd(ca comments.CommentAdd) comments.Comment {
 		Upvotes:       0, // Not part of commentAdd data
 		Deleted:       false,
 		Reason:        "",
+		ContentType:   contentType,
+		Attachments:   ca.Attachments,
 		ExtraData:     ca.ExtraData,
 		ExtraDataHint: ca.ExtraDataHint,
 	}
@@ -1619,6 +2233,9 @@ func convertCommentFromCommentDel(cd comments.CommentDel) comments.Comment {
 		Upvotes:   0,
 		Deleted:   true,
 		Reason:    cd.Reason,
+
+		DeletedByAuthor: cd.Author,
+		BulkDeleted:     cd.Bulk,
 	}
 }
 
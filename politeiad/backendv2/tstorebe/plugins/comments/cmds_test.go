@@ -7,6 +7,7 @@ package comments
 import (
 	"encoding/hex"
 	"encoding/json"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -29,7 +30,8 @@ func TestCollectVoteDigestsPage(t *testing.T) {
 	// which has one comment vote on the first comment from "user1",
 	// another two comment votes on the second second comment from
 	// "user1" and "user2", and lastly another three comment votes on
-	// the third comment from all three test users.
+	// the third comment from all three test users. Each vote is cast
+	// at a timestamp equal to the ID of the comment it was cast on.
 	commentIdxes := make(map[uint32]commentIndex, len(commentIDs))
 	for _, commentID := range commentIDs {
 		// Prepare comment index Votes map
@@ -59,8 +61,9 @@ func TestCollectVoteDigestsPage(t *testing.T) {
 			}
 			commentIdx.Votes[userID] = []voteIndex{
 				{
-					Digest: d,
-					Vote:   comments.VoteUpvote,
+					Digest:    d,
+					Vote:      comments.VoteUpvote,
+					Timestamp: int64(commentID),
 				},
 			}
 		}
@@ -73,6 +76,7 @@ func TestCollectVoteDigestsPage(t *testing.T) {
 		name                 string
 		page                 uint32
 		userID               string
+		since                int64
 		resultExpectedLength int
 	}{
 		{
@@ -153,14 +157,42 @@ func TestCollectVoteDigestsPage(t *testing.T) {
 			userID:               "",
 			resultExpectedLength: 2,
 		},
+		{
+			name:                 "first user's votes since timestamp",
+			page:                 1,
+			userID:               userIDs[0],
+			since:                2,
+			resultExpectedLength: 2,
+		},
+		{
+			name:                 "first user's votes since last timestamp",
+			page:                 1,
+			userID:               userIDs[0],
+			since:                3,
+			resultExpectedLength: 1,
+		},
+		{
+			name:                 "all votes since timestamp second page",
+			page:                 2,
+			userID:               "",
+			since:                3,
+			resultExpectedLength: 1,
+		},
+		{
+			name:                 "no votes since timestamp",
+			page:                 1,
+			userID:               "",
+			since:                4,
+			resultExpectedLength: 0,
+		},
 	}
 
 	// Run tests
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			// Run test
-			digests := collectVoteDigestsPage(commentIdxes, tc.userID, tc.page,
-				pageSize)
+			digests := collectVoteDigestsPage(commentIdxes, tc.userID,
+				tc.since, tc.page, pageSize)
 
 			// Verify length of returned page
 			if len(digests) != tc.resultExpectedLength {
@@ -171,6 +203,30 @@ func TestCollectVoteDigestsPage(t *testing.T) {
 	}
 }
 
+func TestCommentsPage(t *testing.T) {
+	order := []uint32{5, 4, 3, 2, 1}
+	var tests = []struct {
+		name     string
+		page     uint32
+		pageSize uint32
+		want     []uint32
+	}{
+		{"default page", 0, 2, []uint32{5, 4}},
+		{"first page", 1, 2, []uint32{5, 4}},
+		{"partial page", 3, 2, []uint32{1}},
+		{"page does not exist", 4, 2, []uint32{}},
+		{"all comments", 1, 10, []uint32{5, 4, 3, 2, 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := commentsPage(order, tc.page, tc.pageSize)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestCmdEdit(t *testing.T) {
 	// Setup comments plugin
 	c, cleanup := newTestCommentsPlugin(t)
@@ -274,6 +330,24 @@ func TestCmdEdit(t *testing.T) {
 			true,
 			pluginError(comments.ErrorCodeTokenInvalid),
 		},
+		{
+			"content type invalid",
+			tokenb,
+			edit(t, fid,
+				comments.Edit{
+					UserID:        userID,
+					State:         state,
+					Token:         token,
+					ParentID:      parentID,
+					CommentID:     commentID,
+					Comment:       comment,
+					ContentType:   "html",
+					ExtraData:     extraData,
+					ExtraDataHint: extraDataHint,
+				}),
+			true,
+			pluginError(comments.ErrorCodeContentTypeInvalid),
+		},
 		{
 			"signature is not hex",
 			tokenb,
@@ -454,6 +528,7 @@ func edit(t *testing.T, fid *identity.FullIdentity, e comments.Edit) comments.Ed
 		ParentID:      e.ParentID,
 		CommentID:     e.CommentID,
 		Comment:       e.Comment,
+		ContentType:   e.ContentType,
 		ExtraData:     e.ExtraData,
 		ExtraDataHint: e.ExtraDataHint,
 		PublicKey:     fid.Public.String(),
@@ -470,6 +545,257 @@ func pluginError(e comments.ErrorCodeT) error {
 	}
 }
 
+func TestCmdDelAuthorDeletesNotAllowed(t *testing.T) {
+	// Setup comments plugin. Author deletes are disabled by default.
+	c, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+
+	token := "45154fb45664714b"
+	tokenb, err := hex.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(comments.Del{
+		State:     comments.RecordStateVetted,
+		Token:     token,
+		CommentID: 1,
+		Author:    true,
+		UserID:    "6dc1c8ca-abb5-4631-8ed4-f991b0169770",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run test
+	_, err = c.cmdDel(tokenb, string(b))
+	var pe backend.PluginError
+	if !errors.As(err, &pe) {
+		t.Fatalf("want plugin error, got '%v'", err)
+	}
+	if comments.ErrorCodeT(pe.ErrorCode) != comments.ErrorCodeDelNotAllowed {
+		t.Fatalf("want error '%v', got '%v'",
+			comments.ErrorCodes[comments.ErrorCodeDelNotAllowed],
+			comments.ErrorCodes[comments.ErrorCodeT(pe.ErrorCode)])
+	}
+}
+
+func TestDownvoteVerify(t *testing.T) {
+	var (
+		upvote   = voteIndex{Vote: comments.VoteUpvote}
+		downvote = voteIndex{Vote: comments.VoteDownvote}
+	)
+	var tests = []struct {
+		name     string
+		disabled bool
+		vote     comments.VoteT
+		votes    []voteIndex
+		wantErr  bool
+	}{
+		{"downvotes enabled", false, comments.VoteDownvote, nil, false},
+		{"upvote", true, comments.VoteUpvote, nil, false},
+		{"downvote", true, comments.VoteDownvote, nil, true},
+		{"downvote after upvote", true, comments.VoteDownvote,
+			[]voteIndex{upvote}, true},
+		{"downvote retracted", true, comments.VoteDownvote,
+			[]voteIndex{downvote, downvote}, true},
+		{"retract downvote", true, comments.VoteDownvote,
+			[]voteIndex{downvote}, false},
+		{"retract downvote after upvote", true, comments.VoteDownvote,
+			[]voteIndex{upvote, downvote}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := commentsPlugin{
+				disableDownvotes: tc.disabled,
+			}
+			err := p.downvoteVerify(tc.vote, tc.votes)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var pe backend.PluginError
+			if !errors.As(err, &pe) {
+				t.Fatalf("want plugin error, got '%v'", err)
+			}
+			if comments.ErrorCodeT(pe.ErrorCode) !=
+				comments.ErrorCodeDownvotesDisabled {
+				t.Fatalf("want error '%v', got '%v'",
+					comments.ErrorCodes[comments.ErrorCodeDownvotesDisabled],
+					comments.ErrorCodes[comments.ErrorCodeT(pe.ErrorCode)])
+			}
+		})
+	}
+}
+
+func TestCommentVoteScore(t *testing.T) {
+	cidx := newCommentIndex()
+	cidx.Votes["a"] = []voteIndex{{Vote: comments.VoteUpvote}}
+	cidx.Votes["b"] = []voteIndex{{Vote: comments.VoteDownvote}}
+	cidx.Votes["c"] = []voteIndex{{Vote: comments.VoteDownvote}}
+
+	var p commentsPlugin
+	downvotes, upvotes := p.commentVoteScore(cidx)
+	if downvotes != 2 || upvotes != 1 {
+		t.Fatalf("got %v/%v, want 2/1", downvotes, upvotes)
+	}
+
+	// The downvotes are omitted when downvotes are disabled
+	p.disableDownvotes = true
+	downvotes, upvotes = p.commentVoteScore(cidx)
+	if downvotes != 0 || upvotes != 1 {
+		t.Fatalf("downvotes disabled: got %v/%v, want 0/1",
+			downvotes, upvotes)
+	}
+}
+
+func TestCmdDelUser(t *testing.T) {
+	c, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+
+	// Setup an identity that will be used to create the payload
+	// signatures.
+	fid, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		token  = "45154fb45664714b"
+		userID = "6dc1c8ca-abb5-4631-8ed4-f991b0169770"
+		reason = "spam"
+
+		signatureb = fid.SignMessage([]byte(userID + reason))
+		signature  = hex.EncodeToString(signatureb[:])
+	)
+	tokenb, err := hex.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup tests. The tstore is not setup, so only the input
+	// validation that happens before the record is retrieved is
+	// tested.
+	tests := []struct {
+		name string
+		d    comments.DelUser
+		err  comments.ErrorCodeT
+	}{
+		{
+			"user id invalid",
+			comments.DelUser{
+				UserID:    "invalid",
+				Reason:    reason,
+				PublicKey: fid.Public.String(),
+				Signature: signature,
+			},
+			comments.ErrorCodeUserIDInvalid,
+		},
+		{
+			"signature does not include the reason",
+			comments.DelUser{
+				UserID:    userID,
+				Reason:    "another reason",
+				PublicKey: fid.Public.String(),
+				Signature: signature,
+			},
+			comments.ErrorCodeSignatureInvalid,
+		},
+	}
+
+	// Run tests
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.cmdDelUser(tokenb, string(b))
+			var pe backend.PluginError
+			if !errors.As(err, &pe) {
+				t.Fatalf("want plugin error, got '%v'", err)
+			}
+			if comments.ErrorCodeT(pe.ErrorCode) != tc.err {
+				t.Fatalf("want error '%v', got '%v'",
+					comments.ErrorCodes[tc.err],
+					comments.ErrorCodes[comments.ErrorCodeT(pe.ErrorCode)])
+			}
+		})
+	}
+}
+
+func TestVoteCooldownRemaining(t *testing.T) {
+	votes := []voteIndex{
+		{Vote: comments.VoteUpvote, Timestamp: 100},
+		{Vote: comments.VoteDownvote, Timestamp: 150},
+		{Vote: comments.VoteUpvote, Reaction: "heart", Timestamp: 180},
+	}
+	var tests = []struct {
+		name     string
+		votes    []voteIndex
+		reaction string
+		cooldown uint32
+		now      int64
+		want     int64
+	}{
+		{"cooldown disabled", votes, "", 0, 150, 0},
+		{"no previous votes", nil, "", 60, 150, 0},
+		{"no timestamps", []voteIndex{{}}, "", 60, 150, 0},
+		{"cooldown active", votes, "", 60, 170, 40},
+		{"cooldown elapsed", votes, "", 60, 210, 0},
+		{"reaction cooldown", votes, "heart", 60, 200, 40},
+		{"other reaction", votes, "rocket", 60, 200, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := voteCooldownRemaining(tc.votes, tc.reaction,
+				tc.cooldown, tc.now)
+			if got != tc.want {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEditVerify(t *testing.T) {
+	var tests = []struct {
+		name     string
+		editsMax uint32
+		edits    int
+		now      int64
+		want     comments.ErrorCodeT // ErrorCodeInvalid for no error
+	}{
+		{"first edit", 0, 0, 100, comments.ErrorCodeInvalid},
+		{"edits unlimited", 0, 50, 100, comments.ErrorCodeInvalid},
+		{"edits remaining", 3, 2, 100, comments.ErrorCodeInvalid},
+		{"edits max exceeded", 3, 3, 100, comments.ErrorCodeEditsMaxExceeded},
+		{"edit period end", 3, 0, 400, comments.ErrorCodeInvalid},
+		{"edit period expired", 0, 0, 401, comments.ErrorCodeEditPeriodExpired},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := commentsPlugin{
+				editPeriod: 300,
+				editsMax:   tc.editsMax,
+			}
+			err := p.editVerify(100, tc.edits, tc.now)
+			if tc.want == comments.ErrorCodeInvalid {
+				if err != nil {
+					t.Fatalf("got err %v, want nil", err)
+				}
+				return
+			}
+			var pe backend.PluginError
+			if !errors.As(err, &pe) {
+				t.Fatalf("got err %v, want plugin error", err)
+			}
+			if comments.ErrorCodeT(pe.ErrorCode) != tc.want {
+				t.Fatalf("got error code %v, want %v", pe.ErrorCode, tc.want)
+			}
+		})
+	}
+}
+
 func TestFinalCommentTimestamps(t *testing.T) {
 	token := "55154fb45664714a"
 
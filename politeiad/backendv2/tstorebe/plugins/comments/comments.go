@@ -14,6 +14,7 @@ import (
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
 	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	"github.com/pkg/errors"
 )
 
@@ -27,11 +28,14 @@ var (
 // commentsPlugin satisfies the plugins PluginClient interface.
 type commentsPlugin struct {
 	sync.RWMutex
-	tstore plugins.TstoreClient
-
-	// dataDir is the comments plugin data directory. The only data
-	// that is stored here is cached data that can be re-created at any
-	// time by walking the trillian trees.
+	backend backend.Backend
+	tstore  plugins.TstoreClient
+
+	// dataDir is the comments plugin data directory. The data that is
+	// stored here is cached data that can be re-created at any time by
+	// walking the trillian trees, with the exception of the comment
+	// thread subscriptions, which are user preferences that are not
+	// part of the record.
 	dataDir string
 
 	// identity contains the full identity that the plugin uses to
@@ -48,6 +52,25 @@ type commentsPlugin struct {
 	timestampsPageSize uint32
 	allowEdits         bool
 	editPeriod         uint32
+	stakeWeighting     bool
+	commentsPageSize   uint32
+	reactionsEncoded   string // JSON encoded []comments.Reaction
+	reactions          []comments.Reaction
+	allowAuthorDeletes bool
+	voteChangeCooldown uint32 // In seconds
+	treeDepthMax       uint32
+	treeChildrenMax    uint32
+	attachmentCountMax uint32
+	attachmentSizeMax  uint32 // In bytes
+	mimeTypesEncoded   string // JSON encoded attachment MIME types
+	mimeTypes          []string
+	searchPageSize     uint32
+	fsckRepairScores   bool
+	markdownValidation bool
+	markdownDataURIMax uint32 // In bytes
+	editsMax           uint32
+	historyPageSize    uint32
+	disableDownvotes   bool
 }
 
 // Setup performs any plugin setup that is required.
@@ -56,6 +79,18 @@ type commentsPlugin struct {
 func (p *commentsPlugin) Setup() error {
 	log.Tracef("comments Setup")
 
+	// Stake weighting requires the ticketvote plugin in order to
+	// verify ticket ownership.
+	if p.stakeWeighting {
+		for _, v := range p.backend.PluginInventory() {
+			if v.ID == ticketvote.PluginID {
+				return nil
+			}
+		}
+		return errors.Errorf("%v plugin dependency not registered",
+			ticketvote.PluginID)
+	}
+
 	return nil
 }
 
@@ -78,6 +113,8 @@ func (p *commentsPlugin) Cmd(token []byte, cmd, payload string) (string, error)
 		return p.cmdGet(token, payload)
 	case comments.CmdGetAll:
 		return p.cmdGetAll(token)
+	case comments.CmdGetPage:
+		return p.cmdGetPage(token, payload)
 	case comments.CmdGetVersion:
 		return p.cmdGetVersion(token, payload)
 	case comments.CmdCount:
@@ -86,6 +123,28 @@ func (p *commentsPlugin) Cmd(token []byte, cmd, payload string) (string, error)
 		return p.cmdVotes(token, payload)
 	case comments.CmdTimestamps:
 		return p.cmdTimestamps(token, payload)
+	case comments.CmdStakeProof:
+		return p.cmdStakeProof(token, payload)
+	case comments.CmdUser:
+		return p.cmdUser(token, payload)
+	case comments.CmdTree:
+		return p.cmdTree(token, payload)
+	case comments.CmdAttachments:
+		return p.cmdAttachments(token, payload)
+	case comments.CmdTimestampsBatch:
+		return p.cmdTimestampsBatch(token, payload)
+	case comments.CmdSubscribe:
+		return p.cmdSubscribe(token, payload)
+	case comments.CmdSubscribers:
+		return p.cmdSubscribers(token, payload)
+	case comments.CmdSearch:
+		return p.cmdSearch(token, payload)
+	case comments.CmdDelUser:
+		return p.cmdDelUser(token, payload)
+	case comments.CmdPin:
+		return p.cmdPin(token, payload)
+	case comments.CmdHistory:
+		return p.cmdHistory(payload)
 	}
 
 	return "", backend.ErrPluginCmdInvalid
@@ -95,7 +154,14 @@ func (p *commentsPlugin) Cmd(token []byte, cmd, payload string) (string, error)
 //
 // This function satisfies the plugins PluginClient interface.
 func (p *commentsPlugin) Hook(h plugins.HookT, payload string) error {
-	log.Tracef("comments Hook: %x %v", plugins.Hooks[h])
+	log.Tracef("comments Hook: %v", plugins.Hooks[h])
+
+	switch h {
+	case plugins.HookTypePluginPost:
+		return p.hookPluginPost(payload)
+	case plugins.HookTypeSetRecordStatusPost:
+		return p.hookSetRecordStatusPost(payload)
+	}
 
 	return nil
 }
@@ -111,9 +177,12 @@ func (p *commentsPlugin) Fsck(tokens [][]byte) error {
 	// cached record index is coherent for each token. The
 	// cache entry will be built from scratch if any errors
 	// are found with it.
-	var rebuilt int
+	var rebuilt, searchRebuilt, scoresMismatched int
 	for i, token := range tokens {
-		log.Debugf("Comments fsck for record %v/%v", i+1, len(tokens))
+		// Log progress every 50 records
+		if i%50 == 0 {
+			log.Infof("Comments fsck progress %v/%v", i, len(tokens))
+		}
 
 		wasRebuilt, err := p.fsckRecordIndex(token)
 		if err != nil {
@@ -122,9 +191,29 @@ func (p *commentsPlugin) Fsck(tokens [][]byte) error {
 		if wasRebuilt {
 			rebuilt++
 		}
+
+		wasRebuilt, err = p.fsckSearchIndex(token)
+		if err != nil {
+			return err
+		}
+		if wasRebuilt {
+			searchRebuilt++
+		}
+
+		mismatches, err := p.fsckVoteScores(token)
+		if err != nil {
+			return err
+		}
+		if mismatches > 0 {
+			scoresMismatched++
+		}
 	}
 
 	log.Infof("%v/%v record indexes required a rebuild", rebuilt, len(tokens))
+	log.Infof("%v/%v search indexes required a rebuild",
+		searchRebuilt, len(tokens))
+	log.Infof("%v/%v records had comment vote score mismatches",
+		scoresMismatched, len(tokens))
 	log.Infof("Comments fsck complete")
 
 	return nil
@@ -169,11 +258,79 @@ func (p *commentsPlugin) Settings() []backend.PluginSetting {
 			Key:   comments.SettingKeyEditPeriod,
 			Value: strconv.FormatUint(uint64(p.editPeriod), 10),
 		},
+		{
+			Key:   comments.SettingKeyStakeWeighting,
+			Value: strconv.FormatBool(p.stakeWeighting),
+		},
+		{
+			Key:   comments.SettingKeyCommentsPageSize,
+			Value: strconv.FormatUint(uint64(p.commentsPageSize), 10),
+		},
+		{
+			Key:   comments.SettingKeyReactions,
+			Value: p.reactionsEncoded,
+		},
+		{
+			Key:   comments.SettingKeyAllowAuthorDeletes,
+			Value: strconv.FormatBool(p.allowAuthorDeletes),
+		},
+		{
+			Key:   comments.SettingKeyVoteChangeCooldown,
+			Value: strconv.FormatUint(uint64(p.voteChangeCooldown), 10),
+		},
+		{
+			Key:   comments.SettingKeyTreeDepthMax,
+			Value: strconv.FormatUint(uint64(p.treeDepthMax), 10),
+		},
+		{
+			Key:   comments.SettingKeyTreeChildrenMax,
+			Value: strconv.FormatUint(uint64(p.treeChildrenMax), 10),
+		},
+		{
+			Key:   comments.SettingKeyAttachmentCountMax,
+			Value: strconv.FormatUint(uint64(p.attachmentCountMax), 10),
+		},
+		{
+			Key:   comments.SettingKeyAttachmentSizeMax,
+			Value: strconv.FormatUint(uint64(p.attachmentSizeMax), 10),
+		},
+		{
+			Key:   comments.SettingKeyAttachmentMIMETypes,
+			Value: p.mimeTypesEncoded,
+		},
+		{
+			Key:   comments.SettingKeySearchPageSize,
+			Value: strconv.FormatUint(uint64(p.searchPageSize), 10),
+		},
+		{
+			Key:   comments.SettingKeyFsckRepairScores,
+			Value: strconv.FormatBool(p.fsckRepairScores),
+		},
+		{
+			Key:   comments.SettingKeyMarkdownValidation,
+			Value: strconv.FormatBool(p.markdownValidation),
+		},
+		{
+			Key:   comments.SettingKeyMarkdownDataURIMax,
+			Value: strconv.FormatUint(uint64(p.markdownDataURIMax), 10),
+		},
+		{
+			Key:   comments.SettingKeyEditsMax,
+			Value: strconv.FormatUint(uint64(p.editsMax), 10),
+		},
+		{
+			Key:   comments.SettingKeyHistoryPageSize,
+			Value: strconv.FormatUint(uint64(p.historyPageSize), 10),
+		},
+		{
+			Key:   comments.SettingKeyDisableDownvotes,
+			Value: strconv.FormatBool(p.disableDownvotes),
+		},
 	}
 }
 
 // New returns a new comments plugin.
-func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity) (*commentsPlugin, error) {
+func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity) (*commentsPlugin, error) {
 	// Setup comments plugin data dir
 	dataDir = filepath.Join(dataDir, comments.PluginID)
 	err := os.MkdirAll(dataDir, 0700)
@@ -191,6 +348,23 @@ func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir
 		timestampsPageSize = comments.SettingTimestampsPageSize
 		allowEdits         = comments.SettingAllowEdits
 		editPeriod         = comments.SettingEditPeriod
+		stakeWeighting     = comments.SettingStakeWeighting
+		commentsPageSize   = comments.SettingCommentsPageSize
+		reactionsEncoded   = comments.SettingReactions
+		allowAuthorDeletes = comments.SettingAllowAuthorDeletes
+		voteChangeCooldown = comments.SettingVoteChangeCooldown
+		treeDepthMax       = comments.SettingTreeDepthMax
+		treeChildrenMax    = comments.SettingTreeChildrenMax
+		attachmentCountMax = comments.SettingAttachmentCountMax
+		attachmentSizeMax  = comments.SettingAttachmentSizeMax
+		mimeTypesEncoded   = comments.SettingAttachmentMIMETypes
+		searchPageSize     = comments.SettingSearchPageSize
+		fsckRepairScores   = comments.SettingFsckRepairScores
+		markdownValidation = comments.SettingMarkdownValidation
+		markdownDataURIMax = comments.SettingMarkdownDataURIMax
+		editsMax           = comments.SettingEditsMax
+		historyPageSize    = comments.SettingHistoryPageSize
+		disableDownvotes   = comments.SettingDisableDownvotes
 	)
 
 	// Override defaults with any passed in settings
@@ -260,12 +434,173 @@ func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir
 			}
 			editPeriod = uint32(u)
 
+		case comments.SettingKeyStakeWeighting:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			stakeWeighting = b
+
+		case comments.SettingKeyCommentsPageSize:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': "+
+					"must be greater than zero", v.Key, v.Value)
+			}
+			commentsPageSize = uint32(u)
+
+		case comments.SettingKeyReactions:
+			reactionsEncoded = v.Value
+
+		case comments.SettingKeyAllowAuthorDeletes:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			allowAuthorDeletes = b
+
+		case comments.SettingKeyVoteChangeCooldown:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			voteChangeCooldown = uint32(u)
+
+		case comments.SettingKeyTreeDepthMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': "+
+					"must be greater than zero", v.Key, v.Value)
+			}
+			treeDepthMax = uint32(u)
+
+		case comments.SettingKeyTreeChildrenMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': "+
+					"must be greater than zero", v.Key, v.Value)
+			}
+			treeChildrenMax = uint32(u)
+
+		case comments.SettingKeyAttachmentCountMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			attachmentCountMax = uint32(u)
+
+		case comments.SettingKeyAttachmentSizeMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': "+
+					"must be greater than zero", v.Key, v.Value)
+			}
+			attachmentSizeMax = uint32(u)
+
+		case comments.SettingKeyAttachmentMIMETypes:
+			mimeTypesEncoded = v.Value
+
+		case comments.SettingKeySearchPageSize:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': "+
+					"must be greater than zero", v.Key, v.Value)
+			}
+			searchPageSize = uint32(u)
+
+		case comments.SettingKeyFsckRepairScores:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			fsckRepairScores = b
+
+		case comments.SettingKeyMarkdownValidation:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			markdownValidation = b
+
+		case comments.SettingKeyMarkdownDataURIMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			markdownDataURIMax = uint32(u)
+
+		case comments.SettingKeyEditsMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			editsMax = uint32(u)
+
+		case comments.SettingKeyHistoryPageSize:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			historyPageSize = uint32(u)
+
+		case comments.SettingKeyDisableDownvotes:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			disableDownvotes = b
+
 		default:
 			return nil, errors.Errorf("invalid comments plugin setting '%v'", v.Key)
 		}
 	}
 
+	// Parse the reactions
+	reactions, err := parseReactions(reactionsEncoded)
+	if err != nil {
+		return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+			comments.SettingKeyReactions, reactionsEncoded, err)
+	}
+
+	// Parse the attachment MIME types
+	mimeTypes, err := parseAttachmentMIMEs(mimeTypesEncoded)
+	if err != nil {
+		return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+			comments.SettingKeyAttachmentMIMETypes, mimeTypesEncoded, err)
+	}
+
 	return &commentsPlugin{
+		backend:            backend,
 		tstore:             tstore,
 		identity:           id,
 		dataDir:            dataDir,
@@ -277,5 +612,24 @@ func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir
 		timestampsPageSize: timestampsPageSize,
 		allowEdits:         allowEdits,
 		editPeriod:         editPeriod,
+		stakeWeighting:     stakeWeighting,
+		commentsPageSize:   commentsPageSize,
+		reactionsEncoded:   reactionsEncoded,
+		reactions:          reactions,
+		allowAuthorDeletes: allowAuthorDeletes,
+		voteChangeCooldown: voteChangeCooldown,
+		treeDepthMax:       treeDepthMax,
+		treeChildrenMax:    treeChildrenMax,
+		attachmentCountMax: attachmentCountMax,
+		attachmentSizeMax:  attachmentSizeMax,
+		mimeTypesEncoded:   mimeTypesEncoded,
+		mimeTypes:          mimeTypes,
+		searchPageSize:     searchPageSize,
+		fsckRepairScores:   fsckRepairScores,
+		markdownValidation: markdownValidation,
+		markdownDataURIMax: markdownDataURIMax,
+		editsMax:           editsMax,
+		historyPageSize:    historyPageSize,
+		disableDownvotes:   disableDownvotes,
 	}, nil
 }
@@ -0,0 +1,129 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// Filenames of the comment counts that are saved to the comments
+	// plugin data dir.
+	fnCommentCountUnvetted = "{shorttoken}-count-unvetted.json"
+	fnCommentCountVetted   = "{shorttoken}-count-vetted.json"
+)
+
+// commentCount contains the number of comments that have been made on a
+// record and the ID of the pinned comment of the record. Deleted comments are
+// included in the count.
+//
+// The comment count is cached separately from the record index so that it
+// can be retrieved without having to decode the full record index, which
+// includes the votes of every comment.
+type commentCount struct {
+	Count    uint32 `json:"count"`
+	PinnedID uint32 `json:"pinnedid,omitempty"`
+}
+
+// newCommentCount returns the commentCount of the provided record index.
+func newCommentCount(ridx recordIndex) commentCount {
+	return commentCount{
+		Count:    uint32(len(ridx.Comments)),
+		PinnedID: pinnedID(ridx),
+	}
+}
+
+// commentCountPath returns the file path for a cached comment count. It
+// accepts both the full length token or the short token, but the short token
+// is always used in the file path string.
+func (p *commentsPlugin) commentCountPath(token []byte, s backend.StateT) (string, error) {
+	var fn string
+	switch s {
+	case backend.StateUnvetted:
+		fn = fnCommentCountUnvetted
+	case backend.StateVetted:
+		fn = fnCommentCountVetted
+	default:
+		return "", fmt.Errorf("invalid state")
+	}
+
+	t, err := util.ShortTokenEncode(token)
+	if err != nil {
+		return "", err
+	}
+	fn = strings.Replace(fn, "{shorttoken}", t, 1)
+	return filepath.Join(p.dataDir, fn), nil
+}
+
+// commentCount returns the cached comment count for the provided record. The
+// returned bool will be false if a cached comment count does not exist.
+//
+// This function must be called WITHOUT the read lock held.
+func (p *commentsPlugin) commentCount(token []byte, s backend.StateT) (*commentCount, bool, error) {
+	fp, err := p.commentCountPath(token, s)
+	if err != nil {
+		return nil, false, err
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		var e *os.PathError
+		if errors.As(err, &e) && !os.IsExist(err) {
+			// File does't exist
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var c commentCount
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &c, true, nil
+}
+
+// _commentCountSave saves the comment count of the provided record index to
+// the comments plugin data dir.
+//
+// This function must be called WITHOUT the read/write lock held.
+func (p *commentsPlugin) _commentCountSave(token []byte, s backend.StateT, ridx recordIndex) error {
+	b, err := json.Marshal(newCommentCount(ridx))
+	if err != nil {
+		return err
+	}
+	fp, err := p.commentCountPath(token, s)
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	return os.WriteFile(fp, b, 0664)
+}
+
+// commentCountSave is a wrapper around the _commentCountSave method that
+// allows us to decide how update errors should be handled. For now we just
+// panic. If an error occurs the cache is no longer coherent and the only way
+// to fix it is to rebuild it.
+func (p *commentsPlugin) commentCountSave(token []byte, s backend.StateT, ridx recordIndex) {
+	err := p._commentCountSave(token, s, ridx)
+	if err != nil {
+		panic(err)
+	}
+}
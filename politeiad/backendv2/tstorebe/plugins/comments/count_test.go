@@ -0,0 +1,65 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+func TestCommentCount(t *testing.T) {
+	p := commentsPlugin{
+		dataDir: t.TempDir(),
+	}
+	token := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	// A count that has not been cached yet
+	_, ok, err := p.commentCount(token, backend.StateVetted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatalf("got ok true, want false")
+	}
+
+	// Save a count
+	ridx := recordIndex{
+		Comments: map[uint32]commentIndex{
+			1: newCommentIndex(),
+			2: newCommentIndex(),
+		},
+	}
+	p.commentCountSave(token, backend.StateVetted, ridx)
+	count, ok, err := p.commentCount(token, backend.StateVetted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || count.Count != 2 {
+		t.Fatalf("got %+v %v, want 2 true", count, ok)
+	}
+
+	// Counts are cached per record state
+	_, ok, err = p.commentCount(token, backend.StateUnvetted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatalf("unvetted: got ok true, want false")
+	}
+
+	// Removing the record index removes the count
+	err = p.recordIndexRemove(token, backend.StateVetted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok, err = p.commentCount(token, backend.StateVetted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatalf("removed: got ok true, want false")
+	}
+}
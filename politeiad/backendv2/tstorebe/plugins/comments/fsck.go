@@ -6,6 +6,12 @@ package comments
 
 import (
 	"encoding/hex"
+	"reflect"
+	"sort"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/google/uuid"
 )
 
 // fsckRecordIndex verifies the coherency of a record index. The record index
@@ -14,9 +20,10 @@ import (
 func (p *commentsPlugin) fsckRecordIndex(token []byte) (bool, error) {
 	log.Debugf("%x fsck record index", token)
 
-	// Get the digests for all of the comment add, del, and
-	// vote entries for the record. The digests are the keys
-	// that are used to pull the full entries from tstore.
+	// Get the digests for all of the comment add, del, vote,
+	// user stake, and comment pin entries for the record. The
+	// digests are the keys that are used to pull the full
+	// entries from tstore.
 	addD, err := p.tstore.DigestsByDataDesc(token,
 		[]string{dataDescriptorCommentAdd})
 	if err != nil {
@@ -32,6 +39,16 @@ func (p *commentsPlugin) fsckRecordIndex(token []byte) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	stakeD, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorUserStake})
+	if err != nil {
+		return false, err
+	}
+	pinD, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorCommentPin})
+	if err != nil {
+		return false, err
+	}
 
 	// Get the cached record index
 	state, err := p.tstore.RecordState(token)
@@ -43,17 +60,29 @@ func (p *commentsPlugin) fsckRecordIndex(token []byte) (bool, error) {
 		return false, err
 	}
 
+	// Add any comments that are missing from the user indexes
+	err = p.fsckUserIndexes(token, state, addD, delD)
+	if err != nil {
+		return false, err
+	}
+
 	// Verify the coherency of the record index
-	if recordIndexIsCoherent(*rindex, addD, delD, voteD) {
+	if recordIndexIsCoherent(*rindex, addD, delD, voteD, stakeD, pinD) {
 		log.Debugf("%x indexes are coherent", token)
 
+		// Verify the cached comment count against the record index
+		err = p.fsckCommentCount(token, state, *rindex)
+		if err != nil {
+			return false, err
+		}
+
 		return false, nil
 	}
 
 	// The record index is not coherent. Rebuilt it from scratch.
 	log.Infof("%x rebuilding indexes", token)
 
-	err = p.rebuildRecordIndex(token, addD, delD, voteD)
+	err = p.rebuildRecordIndex(token, addD, delD, voteD, stakeD, pinD)
 	if err != nil {
 		return false, err
 	}
@@ -61,10 +90,215 @@ func (p *commentsPlugin) fsckRecordIndex(token []byte) (bool, error) {
 	return true, nil
 }
 
+// fsckUserIndexes adds the comments of a record to the user indexes of the
+// comment authors. Comments that are already part of a user index are
+// ignored, so this is safe to run on user indexes that are already coherent.
+func (p *commentsPlugin) fsckUserIndexes(token []byte, state backend.StateT, addDigests, delDigests [][]byte) error {
+	adds, err := p.commentAdds(token, addDigests)
+	if err != nil {
+		return err
+	}
+	dels, err := p.commentDels(token, delDigests)
+	if err != nil {
+		return err
+	}
+	// The creation timestamp of a comment is the timestamp of the
+	// first version of the comment. It is not known for comments that
+	// have been deleted.
+	users := make(map[string]map[uint32]int64) // [userID][commentID]createdAt
+	add := func(userID string, commentID uint32, createdAt int64) {
+		if users[userID] == nil {
+			users[userID] = make(map[uint32]int64)
+		}
+		if createdAt != 0 || users[userID][commentID] == 0 {
+			users[userID][commentID] = createdAt
+		}
+	}
+	for _, v := range adds {
+		var createdAt int64
+		if v.Version == 1 {
+			createdAt = v.Timestamp
+		}
+		add(v.UserID, v.CommentID, createdAt)
+	}
+	for _, v := range dels {
+		add(v.UserID, v.CommentID, 0)
+	}
+	for userID, cs := range users {
+		if _, err := uuid.Parse(userID); err != nil {
+			log.Warnf("%x invalid comment user id %v; skipping user index",
+				token, userID)
+			continue
+		}
+		p.userIndexAdd(userID, state, token, cs)
+	}
+	return nil
+}
+
+// fsckCommentCount verifies that the cached comment count of a record matches
+// the provided record index. The comment count is saved if it is missing or
+// incorrect.
+func (p *commentsPlugin) fsckCommentCount(token []byte, s backend.StateT, ridx recordIndex) error {
+	count, ok, err := p.commentCount(token, s)
+	if err != nil {
+		return err
+	}
+	want := newCommentCount(ridx)
+	if ok && *count == want {
+		return nil
+	}
+
+	log.Infof("%x fixing comment count; got %+v, want %+v",
+		token, count, want)
+
+	p.commentCountSave(token, s, ridx)
+
+	return nil
+}
+
+// commentScore contains the vote score of a comment.
+type commentScore struct {
+	Votes     int               // Number of votes, including reactions
+	Downvotes uint64            // Net downvotes
+	Upvotes   uint64            // Net upvotes
+	Reactions map[string]uint64 // Reactions that are not mapped to a vote
+}
+
+// newCommentScore returns the vote score of the provided comment index.
+func newCommentScore(cidx commentIndex) commentScore {
+	var votes int
+	for _, v := range cidx.Votes {
+		votes += len(v)
+	}
+	downvotes, upvotes := voteScore(cidx)
+	reactions := reactionScore(cidx)
+	if len(reactions) == 0 {
+		reactions = nil
+	}
+	return commentScore{
+		Votes:     votes,
+		Downvotes: downvotes,
+		Upvotes:   upvotes,
+		Reactions: reactions,
+	}
+}
+
+// voteIndexes returns the vote indexes of each comment, built from the
+// provided comment votes. The votes of each user are replayed in the order
+// that they were cast in.
+func (p *commentsPlugin) voteIndexes(votes []comments.CommentVote, digests [][]byte) map[uint32]map[string][]voteIndex {
+	order := make([]int, 0, len(votes))
+	for i := range votes {
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return votes[order[i]].Timestamp < votes[order[j]].Timestamp
+	})
+	indexes := make(map[uint32]map[string][]voteIndex, len(votes))
+	for _, i := range order {
+		v := votes[i]
+		users, ok := indexes[v.CommentID]
+		if !ok {
+			users = make(map[string][]voteIndex)
+			indexes[v.CommentID] = users
+		}
+		users[v.UserID] = append(users[v.UserID], voteIndex{
+			Vote:      v.Vote,
+			Digest:    digests[i],
+			Reaction:  p.indexReaction(v.Reaction),
+			Timestamp: v.Timestamp,
+		})
+	}
+	return indexes
+}
+
+// scoreMismatches returns the sorted IDs of the comments whose cached vote
+// score does not match the vote score of the provided vote indexes.
+func scoreMismatches(ridx recordIndex, votes map[uint32]map[string][]voteIndex) []uint32 {
+	commentIDs := make([]uint32, 0, len(ridx.Comments))
+	for cid, cidx := range ridx.Comments {
+		cached := newCommentScore(cidx)
+		actual := newCommentScore(commentIndex{Votes: votes[cid]})
+		if reflect.DeepEqual(cached, actual) {
+			continue
+		}
+		commentIDs = append(commentIDs, cid)
+	}
+	sort.Slice(commentIDs, func(i, j int) bool {
+		return commentIDs[i] < commentIDs[j]
+	})
+	return commentIDs
+}
+
+// fsckVoteScores recomputes the vote score of each comment on a record from
+// the comment vote blobs and compares it against the vote score of the cached
+// record index. Any mismatches are reported. The cached vote indexes of the
+// mismatched comments are repaired when the fsckrepairscores plugin setting
+// is enabled. The number of mismatched comments is returned.
+func (p *commentsPlugin) fsckVoteScores(token []byte) (int, error) {
+	log.Debugf("%x fsck comment vote scores", token)
+
+	// Get the comment votes
+	voteD, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorCommentVote})
+	if err != nil {
+		return 0, err
+	}
+	votes, err := p.commentVotes(token, voteD)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get the cached record index
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return 0, err
+	}
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return 0, err
+	}
+
+	// Compare the cached vote scores against the vote scores
+	// that are recomputed from the comment votes.
+	vidx := p.voteIndexes(votes, voteD)
+	mismatches := scoreMismatches(*ridx, vidx)
+	if len(mismatches) == 0 {
+		return 0, nil
+	}
+	for _, cid := range mismatches {
+		cached := newCommentScore(ridx.Comments[cid])
+		actual := newCommentScore(commentIndex{Votes: vidx[cid]})
+		log.Warnf("%x comment %v vote score mismatch; cached %+v, "+
+			"want %+v", token, cid, cached, actual)
+	}
+	if !p.fsckRepairScores {
+		log.Warnf("%x %v comment vote scores not repaired; the %v plugin "+
+			"setting is disabled", token, len(mismatches),
+			comments.SettingKeyFsckRepairScores)
+		return len(mismatches), nil
+	}
+
+	// Repair the cached vote indexes
+	log.Infof("%x repairing %v comment vote scores", token, len(mismatches))
+
+	for _, cid := range mismatches {
+		cidx := ridx.Comments[cid]
+		cidx.Votes = vidx[cid]
+		if cidx.Votes == nil {
+			cidx.Votes = make(map[string][]voteIndex)
+		}
+		ridx.Comments[cid] = cidx
+	}
+	p.recordIndexSave(token, state, *ridx)
+
+	return len(mismatches), nil
+}
+
 // rebuildRecordIndex rebuilds a recordIndex and saves it to the cache. If
 // a recordIndex already exists in the cache for this token, it will be
 // overwritten by this function.
-func (p *commentsPlugin) rebuildRecordIndex(token []byte, addDigests, delDigests, voteDigests [][]byte) error {
+func (p *commentsPlugin) rebuildRecordIndex(token []byte, addDigests, delDigests, voteDigests, stakeDigests, pinDigests [][]byte) error {
 	// indexes contains a commentIndex for each comment
 	// that has been made on the record.
 	//
@@ -119,14 +353,72 @@ func (p *commentsPlugin) rebuildRecordIndex(token []byte, addDigests, delDigests
 			voteIndexes = make([]voteIndex, 0, 1024)
 		}
 		voteIndexes = append(voteIndexes, voteIndex{
-			Vote:   v.Vote,
-			Digest: voteDigests[i],
+			Vote:      v.Vote,
+			Digest:    voteDigests[i],
+			Reaction:  p.indexReaction(v.Reaction),
+			Timestamp: v.Timestamp,
 		})
 
 		cindex.Votes[v.UserID] = voteIndexes
 		indexes[v.CommentID] = cindex
 	}
 
+	// Add the user stakes to the stake indexes. A new
+	// stake proof replaces the previous stake proof of
+	// the user, so the stakes are replayed in the order
+	// that they were saved in.
+	stakes, err := p.userStakes(token, stakeDigests)
+	if err != nil {
+		return err
+	}
+	order := make([]int, 0, len(stakes))
+	for i := range stakes {
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return stakes[order[i]].Timestamp < stakes[order[j]].Timestamp
+	})
+	var stakeIndexes map[string]stakeIndex
+	for _, i := range order {
+		if stakeIndexes == nil {
+			stakeIndexes = make(map[string]stakeIndex, len(stakes))
+		}
+		s := stakes[i]
+		sidx := stakeIndexes[s.UserID]
+		sidx.Tickets = make([]string, 0, len(s.Tickets))
+		for _, v := range s.Tickets {
+			sidx.Tickets = append(sidx.Tickets, v.Ticket)
+		}
+		sidx.Weight = s.Weight
+		sidx.Digests = append(sidx.Digests, stakeDigests[i])
+		stakeIndexes[s.UserID] = sidx
+	}
+
+	// Add the comment pins to the pin index. A new pin
+	// replaces the previously pinned comment, so the pins
+	// are replayed in the order that they were saved in.
+	pins, err := p.commentPins(token, pinDigests)
+	if err != nil {
+		return err
+	}
+	order = make([]int, 0, len(pins))
+	for i := range pins {
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return pins[order[i]].Timestamp < pins[order[j]].Timestamp
+	})
+	var pidx *pinIndex
+	for _, i := range order {
+		if pidx == nil {
+			pidx = &pinIndex{
+				Digests: make([][]byte, 0, len(pins)),
+			}
+		}
+		pidx.CommentID = pins[i].CommentID
+		pidx.Digests = append(pidx.Digests, pinDigests[i])
+	}
+
 	// Save the record index to the cache. This
 	// will overwrite any existing record index.
 	state, err := p.tstore.RecordState(token)
@@ -135,17 +427,20 @@ func (p *commentsPlugin) rebuildRecordIndex(token []byte, addDigests, delDigests
 	}
 	rindex := recordIndex{
 		Comments: indexes,
+		Stakes:   stakeIndexes,
+		Pin:      pidx,
 	}
 	p.recordIndexSave(token, state, rindex)
+	p.commentCountSave(token, state, rindex)
 
 	return nil
 }
 
 // recordIndexIsCoherent returns whether the provided recordIndex contains all
-// of the provided comment add, del, and vote digests. If any of the provided
-// digests are not found then the recordIndex is considered incoherent and this
-// function will return false.
-func recordIndexIsCoherent(rindex recordIndex, addDigests, delDigests, voteDigests [][]byte) bool {
+// of the provided comment add, del, vote, user stake, and comment pin digests.
+// If any of the provided digests are not found then the recordIndex is
+// considered incoherent and this function will return false.
+func recordIndexIsCoherent(rindex recordIndex, addDigests, delDigests, voteDigests, stakeDigests, pinDigests [][]byte) bool {
 	// digests contains all of the digests found in the
 	// record index. This includes the digests for all
 	// comment add, del, and vote entries.
@@ -166,6 +461,16 @@ func recordIndexIsCoherent(rindex recordIndex, addDigests, delDigests, voteDiges
 			digests[hex.EncodeToString(cindex.Del)] = struct{}{}
 		}
 	}
+	for _, sindex := range rindex.Stakes {
+		for _, stakeDigest := range sindex.Digests {
+			digests[hex.EncodeToString(stakeDigest)] = struct{}{}
+		}
+	}
+	if rindex.Pin != nil {
+		for _, pinDigest := range rindex.Pin.Digests {
+			digests[hex.EncodeToString(pinDigest)] = struct{}{}
+		}
+	}
 
 	// Verify that each of the provided add, del, and vote digests
 	// have a corresponding entry in the record index. If a match
@@ -189,6 +494,18 @@ func recordIndexIsCoherent(rindex recordIndex, addDigests, delDigests, voteDiges
 			return false
 		}
 	}
+	for _, d := range stakeDigests {
+		_, ok := digests[hex.EncodeToString(d)]
+		if !ok {
+			return false
+		}
+	}
+	for _, d := range pinDigests {
+		_, ok := digests[hex.EncodeToString(d)]
+		if !ok {
+			return false
+		}
+	}
 
 	return true
 }
@@ -0,0 +1,76 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestScoreMismatches(t *testing.T) {
+	p, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+
+	var (
+		up   = comments.VoteUpvote
+		down = comments.VoteDownvote
+	)
+
+	// Setup the comment votes. The votes are not provided in the order
+	// that they were cast in. User "a" upvotes comment 1 and then
+	// changes the upvote to a downvote. User "b" upvotes comment 2.
+	votes := []comments.CommentVote{
+		{UserID: "a", CommentID: 1, Vote: down, Timestamp: 2},
+		{UserID: "a", CommentID: 1, Vote: up, Timestamp: 1},
+		{UserID: "b", CommentID: 2, Vote: up, Timestamp: 3},
+	}
+	digests := [][]byte{{0x01}, {0x02}, {0x03}}
+	vidx := p.voteIndexes(votes, digests)
+
+	// The votes are replayed in the order that they were cast in
+	want := []voteIndex{
+		{Vote: up, Digest: []byte{0x02}, Timestamp: 1},
+		{Vote: down, Digest: []byte{0x01}, Timestamp: 2},
+	}
+	if !reflect.DeepEqual(vidx[1]["a"], want) {
+		t.Fatalf("got vote indexes %+v, want %+v", vidx[1]["a"], want)
+	}
+
+	// Setup a cached record index where the votes of comment 1 were
+	// cached in the wrong order, which gives it an upvote instead of
+	// a downvote, and where the vote on comment 2 is missing. Comment
+	// 3 does not have any votes.
+	ridx := recordIndex{
+		Comments: map[uint32]commentIndex{
+			1: {
+				Votes: map[string][]voteIndex{
+					"a": {vidx[1]["a"][1], vidx[1]["a"][0]},
+				},
+			},
+			2: {
+				Votes: map[string][]voteIndex{},
+			},
+			3: {
+				Votes: map[string][]voteIndex{},
+			},
+		},
+	}
+	got := scoreMismatches(ridx, vidx)
+	if !reflect.DeepEqual(got, []uint32{1, 2}) {
+		t.Fatalf("got mismatches %v, want [1 2]", got)
+	}
+
+	// A coherent record index does not have any mismatches
+	for cid, cidx := range ridx.Comments {
+		cidx.Votes = vidx[cid]
+		ridx.Comments[cid] = cidx
+	}
+	got = scoreMismatches(ridx, vidx)
+	if len(got) != 0 {
+		t.Fatalf("got mismatches %v, want none", got)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+// cmdHistory retrieves a page of the comment history of a user. The comments
+// of the user and their creation timestamps are looked up using the user
+// index, which allows the requested page to be determined before any of the
+// comments are retrieved. Only the comments of the requested page are
+// retrieved.
+func (p *commentsPlugin) cmdHistory(payload string) (string, error) {
+	// Decode payload
+	var h comments.History
+	err := json.Unmarshal([]byte(payload), &h)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify state
+	var state backend.StateT
+	switch h.State {
+	case comments.RecordStateUnvetted:
+		state = backend.StateUnvetted
+	case comments.RecordStateVetted:
+		state = backend.StateVetted
+	default:
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeRecordStateInvalid),
+		}
+	}
+
+	// Get the user index
+	uidx, err := p.userIndex(h.UserID)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeUserIDInvalid),
+			ErrorContext: err.Error(),
+		}
+	}
+
+	// Compile the comment history of the user. The record indexes are
+	// used to filter out the deleted comments.
+	var (
+		created = uidx.createdAt(state)
+		entries = make([]comments.HistoryEntry, 0, 256)
+	)
+	for t, ids := range uidx.records(state) {
+		token, err := tokenDecode(t)
+		if err != nil {
+			return "", err
+		}
+		ridx, err := p.recordIndex(token, state)
+		if err != nil {
+			return "", err
+		}
+		missing := make([]uint32, 0, len(ids))
+		for _, id := range ids {
+			cidx, ok := ridx.Comments[id]
+			if !ok || len(cidx.Del) > 0 {
+				continue
+			}
+			ts := created[t][id]
+			if ts == 0 {
+				missing = append(missing, id)
+				continue
+			}
+			entries = append(entries, comments.HistoryEntry{
+				Token:     t,
+				CommentID: id,
+				Timestamp: ts,
+			})
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		// The creation timestamps of comments that were indexed before
+		// the timestamps were added to the user index are looked up
+		// and saved to the user index.
+		backfilled, err := p.historyBackfill(h.UserID, state, token,
+			*ridx, missing)
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, backfilled...)
+	}
+
+	// Order the comments from newest to oldest
+	sort.Slice(entries, func(i, j int) bool {
+		ei, ej := entries[i], entries[j]
+		switch {
+		case ei.Timestamp != ej.Timestamp:
+			return ei.Timestamp > ej.Timestamp
+		case ei.Token != ej.Token:
+			return ei.Token < ej.Token
+		}
+		return ei.CommentID > ej.CommentID
+	})
+
+	// Populate the excerpts of the requested page
+	page := historyPage(entries, h.Page, p.historyPageSize)
+	err = p.historyExcerpts(state, page)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	hr := comments.HistoryReply{
+		Entries: page,
+		Total:   uint32(len(entries)),
+	}
+	reply, err := json.Marshal(hr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// historyBackfill looks up the creation timestamps of the provided comments
+// and adds them to the user index. A history entry is returned for each of the
+// comments. The excerpts of the returned entries are not populated.
+func (p *commentsPlugin) historyBackfill(userID string, state backend.StateT, token []byte, ridx recordIndex, commentIDs []uint32) ([]comments.HistoryEntry, error) {
+	cs, err := p.comments(token, ridx, commentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("comments %x: %v", token, err)
+	}
+	var (
+		entries = make([]comments.HistoryEntry, 0, len(cs))
+		created = make(map[uint32]int64, len(cs))
+		t       = hex.EncodeToString(token)
+	)
+	for _, c := range cs {
+		entries = append(entries, comments.HistoryEntry{
+			Token:     t,
+			CommentID: c.CommentID,
+			Timestamp: c.CreatedAt,
+		})
+		created[c.CommentID] = c.CreatedAt
+	}
+	err = p._userIndexAdd(userID, state, token, created)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// historyExcerpts populates the excerpts of the provided history entries. The
+// comments are retrieved one record at a time.
+func (p *commentsPlugin) historyExcerpts(state backend.StateT, entries []comments.HistoryEntry) error {
+	records := make(map[string][]uint32, len(entries)) // [token]commentIDs
+	for _, v := range entries {
+		records[v.Token] = append(records[v.Token], v.CommentID)
+	}
+	excerpts := make(map[string]map[uint32]string, len(records))
+	for t, ids := range records {
+		token, err := tokenDecode(t)
+		if err != nil {
+			return err
+		}
+		ridx, err := p.recordIndex(token, state)
+		if err != nil {
+			return err
+		}
+		cs, err := p.comments(token, *ridx, ids)
+		if err != nil {
+			return fmt.Errorf("comments %v: %v", t, err)
+		}
+		excerpts[t] = make(map[uint32]string, len(cs))
+		for _, c := range cs {
+			excerpts[t][c.CommentID] = historyExcerpt(c.Comment)
+		}
+	}
+	for i, v := range entries {
+		entries[i].Excerpt = excerpts[v.Token][v.CommentID]
+	}
+	return nil
+}
+
+// historyExcerpt returns the excerpt of a comment that is included in the
+// comment history. Consecutive whitespace is collapsed into a single space so
+// that the excerpt can be displayed on a single line.
+func historyExcerpt(comment string) string {
+	excerpt := []rune(strings.Join(strings.Fields(comment), " "))
+	if len(excerpt) > comments.HistoryExcerptLength {
+		excerpt = excerpt[:comments.HistoryExcerptLength]
+	}
+	return string(excerpt)
+}
+
+// historyPage returns the requested page of comment history entries. Page 1
+// is returned when a page is not provided.
+func historyPage(entries []comments.HistoryEntry, page, pageSize uint32) []comments.HistoryEntry {
+	if page == 0 {
+		page = 1
+	}
+	start := uint64(page-1) * uint64(pageSize)
+	if start >= uint64(len(entries)) {
+		return []comments.HistoryEntry{}
+	}
+	end := start + uint64(pageSize)
+	if end > uint64(len(entries)) {
+		end = uint64(len(entries))
+	}
+	return entries[start:end]
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestHistoryExcerpt(t *testing.T) {
+	long := strings.Repeat("é", comments.HistoryExcerptLength+1)
+	var tests = []struct {
+		name    string
+		comment string
+		want    string
+	}{
+		{"short", "Looks good.", "Looks good."},
+		{"whitespace", "  Looks\n\n good.\t", "Looks good."},
+		{"truncated", long, long[:len(long)-len("é")]},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := historyExcerpt(tc.comment)
+			if got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHistoryPage(t *testing.T) {
+	entries := []comments.HistoryEntry{
+		{CommentID: 5}, {CommentID: 4}, {CommentID: 3},
+		{CommentID: 2}, {CommentID: 1},
+	}
+	var tests = []struct {
+		name string
+		page uint32
+		want []uint32
+	}{
+		{"default page", 0, []uint32{5, 4}},
+		{"first page", 1, []uint32{5, 4}},
+		{"last page", 3, []uint32{1}},
+		{"out of range", 4, []uint32{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := historyPage(entries, tc.page, 2)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v entries, want %v", len(got), len(tc.want))
+			}
+			for i, v := range got {
+				if v.CommentID != tc.want[i] {
+					t.Fatalf("entry %v: got comment %v, want %v",
+						i, v.CommentID, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCmdHistoryInvalid(t *testing.T) {
+	p, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+
+	var tests = []struct {
+		name string
+		h    comments.History
+		want comments.ErrorCodeT
+	}{
+		{
+			"record state invalid",
+			comments.History{
+				UserID: "6dc1c8ca-abb5-4631-8ed4-f991b0169770",
+			},
+			comments.ErrorCodeRecordStateInvalid,
+		},
+		{
+			"user id invalid",
+			comments.History{
+				UserID: "../user",
+				State:  comments.RecordStateVetted,
+			},
+			comments.ErrorCodeUserIDInvalid,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.cmdHistory(string(b))
+			var pe backend.PluginError
+			if !errors.As(err, &pe) {
+				t.Fatalf("got err %v, want plugin error", err)
+			}
+			if comments.ErrorCodeT(pe.ErrorCode) != tc.want {
+				t.Fatalf("got error code %v, want %v", pe.ErrorCode, tc.want)
+			}
+		})
+	}
+}
+
+func TestCmdHistoryNoComments(t *testing.T) {
+	p, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+
+	b, err := json.Marshal(comments.History{
+		UserID: "6dc1c8ca-abb5-4631-8ed4-f991b0169770",
+		State:  comments.RecordStateVetted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := p.cmdHistory(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hr comments.HistoryReply
+	err = json.Unmarshal([]byte(reply), &hr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Total != 0 || len(hr.Entries) != 0 {
+		t.Fatalf("got %+v, want no entries", hr)
+	}
+}
+
+func TestCmdHistoryPage(t *testing.T) {
+	p, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+	p.historyPageSize = 2
+
+	var (
+		userID = "6dc1c8ca-abb5-4631-8ed4-f991b0169770"
+		token  = "45154fb45664714b"
+	)
+	tokenb, err := tokenDecode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup the indexes. Comment 3 has been deleted.
+	deleted := newCommentIndex()
+	deleted.Del = []byte{0x01}
+	p.recordIndexSave(tokenb, backend.StateVetted, recordIndex{
+		Comments: map[uint32]commentIndex{
+			1: newCommentIndex(),
+			2: newCommentIndex(),
+			3: deleted,
+		},
+	})
+	p.userIndexAdd(userID, backend.StateVetted, tokenb,
+		map[uint32]int64{1: 100, 2: 200, 3: 300})
+
+	// Request a page past the end of the history. The total is
+	// determined using the indexes, so no comments are retrieved.
+	b, err := json.Marshal(comments.History{
+		UserID: userID,
+		State:  comments.RecordStateVetted,
+		Page:   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := p.cmdHistory(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hr comments.HistoryReply
+	err = json.Unmarshal([]byte(reply), &hr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hr.Total != 2 || len(hr.Entries) != 0 {
+		t.Fatalf("got %+v, want total 2 and no entries", hr)
+	}
+}
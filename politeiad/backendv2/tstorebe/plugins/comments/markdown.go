@@ -0,0 +1,117 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+var (
+	// regexpFencedCode matches markdown fenced code blocks.
+	regexpFencedCode = regexp.MustCompile("(?s)(```|~~~).*?(```|~~~|$)")
+
+	// regexpCodeSpan matches markdown inline code spans.
+	regexpCodeSpan = regexp.MustCompile("`[^`\n]*`")
+
+	// regexpHTML matches raw HTML tags, comments, processing
+	// instructions and declarations.
+	regexpHTML = regexp.MustCompile(`<(/?[a-zA-Z][a-zA-Z0-9-]*` +
+		`(\s[^>]*)?/?>|!--|\?|![a-zA-Z])`)
+
+	// regexpInlineLink matches the destination of markdown inline links
+	// and images, e.g. [text](destination).
+	regexpInlineLink = regexp.MustCompile(`\]\(\s*<?([^)>\s]*)`)
+
+	// regexpRefLink matches the destination of markdown link reference
+	// definitions, e.g. [label]: destination.
+	regexpRefLink = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:\s*<?(\S*)`)
+
+	// regexpAutolink matches the destination of markdown autolinks,
+	// e.g. <scheme:destination>.
+	regexpAutolink = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9+.-]*:[^\s<>]*)>`)
+)
+
+// markdownVerify verifies that a markdown comment does not contain any of the
+// constructs that are rejected by the markdown validation. Comments that are
+// not markdown and comments submitted while the markdown validation is
+// disabled are not checked.
+func (p *commentsPlugin) markdownVerify(contentType, comment string) error {
+	if !p.markdownValidation || contentType != comments.ContentTypeMarkdown {
+		return nil
+	}
+	err := markdownCheck(comment, p.markdownDataURIMax)
+	if err != nil {
+		return backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeMarkdownInvalid),
+			ErrorContext: err.Error(),
+		}
+	}
+	return nil
+}
+
+// markdownCheck checks the provided markdown for raw HTML, javascript links,
+// and data URIs that exceed the provided max size. Code spans and fenced code
+// blocks are removed prior to the check so that these constructs can still be
+// discussed in code. The returned error is used as the error context of the
+// user error.
+func markdownCheck(md string, dataURIMax uint32) error {
+	md = regexpFencedCode.ReplaceAllString(md, "")
+	md = regexpCodeSpan.ReplaceAllString(md, "")
+
+	if regexpHTML.MatchString(md) {
+		return fmt.Errorf("raw html is not allowed")
+	}
+
+	var dests []string
+	for _, r := range []*regexp.Regexp{
+		regexpInlineLink, regexpRefLink, regexpAutolink,
+	} {
+		for _, m := range r.FindAllStringSubmatch(md, -1) {
+			dests = append(dests, m[1])
+		}
+	}
+	for _, v := range dests {
+		err := linkDestinationCheck(v, dataURIMax)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// linkDestinationCheck checks a single markdown link destination. HTML
+// entities are decoded and whitespace and control characters are removed
+// prior to the check since renderers ignore them when parsing the scheme.
+func linkDestinationCheck(dest string, dataURIMax uint32) error {
+	d := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(dest))
+	d = strings.ToLower(d)
+
+	switch {
+	case strings.HasPrefix(d, "javascript:"),
+		strings.HasPrefix(d, "vbscript:"):
+		return fmt.Errorf("script links are not allowed")
+	case strings.HasPrefix(d, "data:"):
+		if len(d) > int(dataURIMax) {
+			return fmt.Errorf("data uri is %v bytes; max is %v bytes",
+				len(d), dataURIMax)
+		}
+	}
+
+	return nil
+}
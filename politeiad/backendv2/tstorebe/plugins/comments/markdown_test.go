@@ -0,0 +1,81 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestMarkdownCheck(t *testing.T) {
+	dataURI := "data:image/png;base64," + strings.Repeat("a", 32)
+	var tests = []struct {
+		name    string
+		md      string
+		wantErr bool
+	}{
+		{"plain text", "Looks good to me.", false},
+		{"link", "[decred](https://decred.org)", false},
+		{"autolink", "<https://decred.org>", false},
+		{"comparison", "2 < 3 and 5 > 4", false},
+		{"script tag", "<script>alert(1)</script>", true},
+		{"closing tag", "text</div>", true},
+		{"html comment", "<!-- hidden -->", true},
+		{"tag in code span", "use `<script>` tags", false},
+		{"tag in fenced code", "```\n<script>alert(1)</script>\n```", false},
+		{"javascript link", "[x](javascript:alert(1))", true},
+		{"javascript link case", "[x](JaVaScRiPt:alert(1))", true},
+		{"javascript link entity", "[x](&#106;avascript:alert(1))", true},
+		{"javascript image", "![x](javascript:alert(1))", true},
+		{"javascript reference", "[x]\n\n[x]: javascript:alert(1)", true},
+		{"javascript autolink", "<javascript:alert(1)>", true},
+		{"vbscript link", "[x](vbscript:msgbox)", true},
+		{"data uri", "![x](" + dataURI + ")", false},
+		{"data uri too big", "![x](" + dataURI + "a)", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := markdownCheck(tc.md, uint32(len(dataURI)))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarkdownVerify(t *testing.T) {
+	var (
+		p  commentsPlugin
+		md = "<script>alert(1)</script>"
+	)
+
+	// Validation disabled
+	err := p.markdownVerify(comments.ContentTypeMarkdown, md)
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+
+	// Plain text comments are not checked
+	p.markdownValidation = true
+	err = p.markdownVerify(comments.ContentTypePlain, md)
+	if err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+
+	// Markdown comments are checked
+	err = p.markdownVerify(comments.ContentTypeMarkdown, md)
+	var pe backend.PluginError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got err %v, want plugin error", err)
+	}
+	if comments.ErrorCodeT(pe.ErrorCode) != comments.ErrorCodeMarkdownInvalid {
+		t.Fatalf("got error code %v, want %v", pe.ErrorCode,
+			comments.ErrorCodeMarkdownInvalid)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/util"
+	"github.com/google/uuid"
+)
+
+// cmdPin pins a comment on a record. A new pin replaces the previously pinned
+// comment of the record. A comment ID of 0 unpins the pinned comment.
+func (p *commentsPlugin) cmdPin(token []byte, payload string) (string, error) {
+	// Decode payload
+	var pn comments.Pin
+	err := json.Unmarshal([]byte(payload), &pn)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify token
+	err = tokenVerify(token, pn.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify user ID
+	_, err = uuid.Parse(pn.UserID)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeUserIDInvalid),
+		}
+	}
+
+	// Verify signature
+	msg := strconv.FormatUint(uint64(pn.State), 10) + pn.Token +
+		strconv.FormatUint(uint64(pn.CommentID), 10)
+	err = util.VerifySignature(pn.Signature, pn.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Verify record state
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	if uint32(pn.State) != uint32(state) {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeRecordStateInvalid),
+			ErrorContext: fmt.Sprintf("got %v, want %v", pn.State, state),
+		}
+	}
+
+	// Get record index
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the comment can be pinned
+	err = pinVerify(*ridx, pn.CommentID)
+	if err != nil {
+		return "", err
+	}
+
+	// Save the comment pin
+	receipt := p.identity.SignMessage([]byte(pn.Signature))
+	cp := comments.CommentPin{
+		UserID:    pn.UserID,
+		State:     pn.State,
+		Token:     pn.Token,
+		CommentID: pn.CommentID,
+		PublicKey: pn.PublicKey,
+		Signature: pn.Signature,
+		Timestamp: time.Now().Unix(),
+		Receipt:   hex.EncodeToString(receipt[:]),
+	}
+	digest, err := p.commentPinSave(token, cp)
+	if err != nil {
+		return "", err
+	}
+
+	// Update the indexes
+	if ridx.Pin == nil {
+		ridx.Pin = &pinIndex{}
+	}
+	ridx.Pin.CommentID = cp.CommentID
+	ridx.Pin.Digests = append(ridx.Pin.Digests, digest)
+	p.recordIndexSave(token, state, *ridx)
+	p.commentCountSave(token, state, *ridx)
+
+	log.Debugf("Comment pinned %x %v", token, cp.CommentID)
+
+	// Prepare reply
+	reply, err := json.Marshal(comments.PinReply{
+		Timestamp: cp.Timestamp,
+		Receipt:   cp.Receipt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// pinVerify verifies that the provided comment can be pinned. The comment
+// must exist and must not have been deleted. A comment ID of 0 is always
+// allowed since it unpins the pinned comment.
+func pinVerify(ridx recordIndex, commentID uint32) error {
+	if commentID == 0 {
+		return nil
+	}
+	cidx, ok := ridx.Comments[commentID]
+	if !ok {
+		return backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeCommentNotFound),
+		}
+	}
+	if len(cidx.Del) > 0 {
+		return backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeCommentNotFound),
+			ErrorContext: "comment has been deleted",
+		}
+	}
+	return nil
+}
+
+// pinnedID returns the ID of the pinned comment of the provided record index.
+// 0 is returned if the record does not have a pinned comment or if the pinned
+// comment has since been deleted.
+func pinnedID(ridx recordIndex) uint32 {
+	if ridx.Pin == nil {
+		return 0
+	}
+	cidx, ok := ridx.Comments[ridx.Pin.CommentID]
+	if !ok || len(cidx.Del) > 0 {
+		return 0
+	}
+	return ridx.Pin.CommentID
+}
+
+// commentPinSave saves a CommentPin to the backend.
+func (p *commentsPlugin) commentPinSave(token []byte, cp comments.CommentPin) ([]byte, error) {
+	be, err := convertBlobEntryFromCommentPin(cp)
+	if err != nil {
+		return nil, err
+	}
+	d, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, err
+	}
+	err = p.tstore.BlobSave(token, *be)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// commentPins returns a CommentPin for each of the provided digests. The
+// comment pins are returned in the same order that they are requested in. An
+// error is returned if a blob entry is not found for one or more of the
+// provided digests.
+func (p *commentsPlugin) commentPins(token []byte, digests [][]byte) ([]comments.CommentPin, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.Blobs(token, digests)
+	if err != nil {
+		return nil, err
+	}
+	if len(blobs) != len(digests) {
+		notFound := make([]string, 0, len(blobs))
+		for _, v := range digests {
+			m := hex.EncodeToString(v)
+			_, ok := blobs[m]
+			if !ok {
+				notFound = append(notFound, m)
+			}
+		}
+		return nil, fmt.Errorf("blobs not found: %v", notFound)
+	}
+
+	// Decode blobs
+	pins := make([]comments.CommentPin, 0, len(blobs))
+	for _, digest := range digests {
+		d := hex.EncodeToString(digest)
+		cp, err := convertCommentPinFromBlobEntry(blobs[d])
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, *cp)
+	}
+
+	return pins, nil
+}
+
+func convertBlobEntryFromCommentPin(cp comments.CommentPin) (*store.BlobEntry, error) {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorCommentPin,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+func convertCommentPinFromBlobEntry(be store.BlobEntry) (*comments.CommentPin, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorCommentPin {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, want %v",
+			dd.Descriptor, dataDescriptorCommentPin)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var cp comments.CommentPin
+	err = json.Unmarshal(b, &cp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal CommentPin: %v", err)
+	}
+
+	return &cp, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"errors"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestPinVerify(t *testing.T) {
+	deleted := newCommentIndex()
+	deleted.Del = []byte{0x01}
+	ridx := recordIndex{
+		Comments: map[uint32]commentIndex{
+			1: newCommentIndex(),
+			2: deleted,
+		},
+	}
+	var tests = []struct {
+		name      string
+		commentID uint32
+		wantErr   bool
+	}{
+		{"unpin", 0, false},
+		{"comment", 1, false},
+		{"deleted comment", 2, true},
+		{"comment not found", 3, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := pinVerify(ridx, tc.commentID)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var pe backend.PluginError
+			if !errors.As(err, &pe) {
+				t.Fatalf("got err %v, want plugin error", err)
+			}
+			if comments.ErrorCodeT(pe.ErrorCode) !=
+				comments.ErrorCodeCommentNotFound {
+				t.Fatalf("got error code %v, want %v", pe.ErrorCode,
+					comments.ErrorCodeCommentNotFound)
+			}
+		})
+	}
+}
+
+func TestPinnedID(t *testing.T) {
+	ridx := recordIndex{
+		Comments: map[uint32]commentIndex{
+			1: newCommentIndex(),
+		},
+	}
+
+	// No pin
+	if id := pinnedID(ridx); id != 0 {
+		t.Fatalf("no pin: got %v, want 0", id)
+	}
+
+	// Pinned comment
+	ridx.Pin = &pinIndex{CommentID: 1}
+	if id := pinnedID(ridx); id != 1 {
+		t.Fatalf("pinned: got %v, want 1", id)
+	}
+
+	// The pinned comment is cached with the comment count
+	p := commentsPlugin{
+		dataDir: t.TempDir(),
+	}
+	token := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+	p.commentCountSave(token, backend.StateVetted, ridx)
+	count, ok, err := p.commentCount(token, backend.StateVetted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || count.PinnedID != 1 {
+		t.Fatalf("count: got %+v %v, want pinned 1", count, ok)
+	}
+
+	// A deleted comment is no longer pinned
+	cidx := ridx.Comments[1]
+	cidx.Del = []byte{0x01}
+	ridx.Comments[1] = cidx
+	if id := pinnedID(ridx); id != 0 {
+		t.Fatalf("deleted: got %v, want 0", id)
+	}
+}
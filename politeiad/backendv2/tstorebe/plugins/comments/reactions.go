@@ -0,0 +1,168 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/json"
+	"fmt"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+// parseReactions parses the JSON encoded reactions plugin setting value and
+// verifies that it is valid.
+func parseReactions(value string) ([]comments.Reaction, error) {
+	var rs []comments.Reaction
+	err := json.Unmarshal([]byte(value), &rs)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ids    = make(map[string]struct{}, len(rs))
+		mapped = make(map[comments.VoteT]string, 2)
+	)
+	for _, r := range rs {
+		if r.ID == "" {
+			return nil, fmt.Errorf("reaction id is empty")
+		}
+		if _, ok := ids[r.ID]; ok {
+			return nil, fmt.Errorf("duplicate reaction %v", r.ID)
+		}
+		ids[r.ID] = struct{}{}
+
+		switch r.Vote {
+		case 0:
+			// Reaction is not mapped to a vote
+			continue
+		case comments.VoteDownvote, comments.VoteUpvote:
+			// These are allowed
+		default:
+			return nil, fmt.Errorf("reaction %v has an invalid vote %v",
+				r.ID, r.Vote)
+		}
+		if id, ok := mapped[r.Vote]; ok {
+			return nil, fmt.Errorf("reactions %v and %v are both mapped "+
+				"to vote %v", id, r.ID, r.Vote)
+		}
+		mapped[r.Vote] = r.ID
+	}
+	if rs == nil {
+		rs = []comments.Reaction{}
+	}
+	return rs, nil
+}
+
+// reaction returns the configured reaction for the provided reaction ID.
+func (p *commentsPlugin) reaction(id string) (*comments.Reaction, bool) {
+	for _, v := range p.reactions {
+		if v.ID == id {
+			return &v, true
+		}
+	}
+	return nil, false
+}
+
+// indexReaction returns the reaction that is saved to the vote index for the
+// provided reaction ID. Reactions that are mapped to a vote are indexed as
+// plain votes so that they are included in the vote score of the comment.
+// Reactions that are no longer configured are still indexed as reactions so
+// that they never alter the vote score.
+func (p *commentsPlugin) indexReaction(id string) string {
+	r, ok := p.reaction(id)
+	if ok && r.Vote != 0 {
+		return ""
+	}
+	return id
+}
+
+// reactionVerify verifies the reaction of a comment vote and returns the
+// reaction that should be saved to the vote index.
+func (p *commentsPlugin) reactionVerify(v comments.Vote) (string, error) {
+	if v.Reaction == "" {
+		// Not a reaction
+		return "", nil
+	}
+	r, ok := p.reaction(v.Reaction)
+	if !ok {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeVoteInvalid),
+			ErrorContext: fmt.Sprintf("invalid reaction '%v'", v.Reaction),
+		}
+	}
+	switch {
+	case r.Vote == 0 && v.Vote != comments.VoteUpvote:
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeVoteInvalid),
+			ErrorContext: "reactions must be cast as an upvote",
+		}
+	case r.Vote != 0 && v.Vote != r.Vote:
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeVoteInvalid),
+			ErrorContext: fmt.Sprintf("reaction '%v' must be cast as vote %v",
+				r.ID, r.Vote),
+		}
+	}
+	return p.indexReaction(v.Reaction), nil
+}
+
+// reactionVotes returns the number of votes that a user has cast for the
+// provided reaction. An empty reaction counts the plain votes.
+func reactionVotes(votes []voteIndex, reaction string) int {
+	var count int
+	for _, v := range votes {
+		if v.Reaction == reaction {
+			count++
+		}
+	}
+	return count
+}
+
+// reactionScore returns the tally of each reaction that is not mapped to a
+// vote. Reactions follow the same toggle semantics as votes, so a user's
+// reaction is only counted if the user has cast it an odd number of times.
+func reactionScore(cidx commentIndex) map[string]uint64 {
+	score := make(map[string]uint64)
+	for _, votes := range cidx.Votes {
+		active := make(map[string]bool)
+		for _, v := range votes {
+			if v.Reaction == "" {
+				continue
+			}
+			active[v.Reaction] = !active[v.Reaction]
+		}
+		for reaction, ok := range active {
+			if ok {
+				score[reaction]++
+			}
+		}
+	}
+	return score
+}
+
+// reactionTallies returns the tally of each configured reaction. Reactions
+// that are mapped to a vote are given the downvotes or upvotes tally of the
+// comment. Nil is returned if reactions have not been enabled.
+func (p *commentsPlugin) reactionTallies(cidx commentIndex, downvotes, upvotes uint64) map[string]uint64 {
+	if len(p.reactions) == 0 {
+		return nil
+	}
+	score := reactionScore(cidx)
+	tallies := make(map[string]uint64, len(p.reactions))
+	for _, r := range p.reactions {
+		switch r.Vote {
+		case comments.VoteDownvote:
+			tallies[r.ID] = downvotes
+		case comments.VoteUpvote:
+			tallies[r.ID] = upvotes
+		default:
+			tallies[r.ID] = score[r.ID]
+		}
+	}
+	return tallies
+}
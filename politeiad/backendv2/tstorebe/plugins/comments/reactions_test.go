@@ -0,0 +1,85 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestParseReactions(t *testing.T) {
+	var tests = []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"disabled", `[]`, false},
+		{"mapped", `[{"id":"+1","vote":1},{"id":"-1","vote":-1}]`, false},
+		{"unmapped", `[{"id":"heart"},{"id":"rocket"}]`, false},
+		{"empty id", `[{"id":""}]`, true},
+		{"duplicate id", `[{"id":"heart"},{"id":"heart"}]`, true},
+		{"invalid vote", `[{"id":"+1","vote":2}]`, true},
+		{"duplicate vote", `[{"id":"+1","vote":1},{"id":"ok","vote":1}]`, true},
+		{"invalid json", `heart`, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseReactions(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestReactionTallies(t *testing.T) {
+	var (
+		up    = voteIndex{Vote: comments.VoteUpvote}
+		down  = voteIndex{Vote: comments.VoteDownvote}
+		heart = voteIndex{Vote: comments.VoteUpvote, Reaction: "heart"}
+	)
+	cidx := commentIndex{
+		Votes: map[string][]voteIndex{
+			"user1": {up, heart},          // Upvote and heart
+			"user2": {heart, heart},       // Heart removed
+			"user3": {heart, down},        // Downvote and heart
+			"user4": {up, heart, up},      // Upvote removed, heart
+			"user5": {heart, heart, down}, // Downvote
+		},
+	}
+	p := commentsPlugin{
+		reactions: []comments.Reaction{
+			{ID: "+1", Vote: comments.VoteUpvote},
+			{ID: "-1", Vote: comments.VoteDownvote},
+			{ID: "heart"},
+			{ID: "rocket"},
+		},
+	}
+
+	// Reactions must not affect the vote score
+	downvotes, upvotes := voteScore(cidx)
+	if downvotes != 2 || upvotes != 1 {
+		t.Fatalf("voteScore: got %v/%v, want 2/1", downvotes, upvotes)
+	}
+
+	got := p.reactionTallies(cidx, downvotes, upvotes)
+	want := map[string]uint64{
+		"+1":     1,
+		"-1":     2,
+		"heart":  3,
+		"rocket": 0,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Reactions are not returned when they are disabled
+	p.reactions = nil
+	if got := p.reactionTallies(cidx, downvotes, upvotes); got != nil {
+		t.Fatalf("got %v, want nil", got)
+	}
+}
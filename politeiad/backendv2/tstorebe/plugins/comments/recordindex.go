@@ -27,6 +27,15 @@ const (
 // recordIndex contains the indexes for all comments made on a record.
 type recordIndex struct {
 	Comments map[uint32]commentIndex `json:"comments"` // [commentID]comment
+
+	// Stakes contains the stake proof of each user that has submitted
+	// one for the record. It is only populated when stake weighting
+	// has been enabled.
+	Stakes map[string]stakeIndex `json:"stakes,omitempty"` // [uuid]stake
+
+	// Pin contains the pinned comment of the record. It is nil if a
+	// comment has never been pinned on the record.
+	Pin *pinIndex `json:"pin,omitempty"`
 }
 
 // commentIndex contains the digests of all comment add, dels, and votes for a
@@ -56,9 +65,37 @@ func newCommentIndex() commentIndex {
 // Caching the vote allows us to tally the votes for a comment without needing
 // to pull the vote blobs from the backend. The digest allows us to retrieve
 // the vote blob if we need to.
+//
+// Reaction is only populated for reactions that are not mapped to an upvote
+// or downvote. These votes are not included in the vote score of the comment.
+//
+// Timestamp is the timestamp of the vote. It is used to enforce the vote
+// change cooldown. Votes that were indexed before the timestamp was added
+// have a timestamp of 0.
 type voteIndex struct {
-	Vote   comments.VoteT `json:"vote"`
-	Digest []byte         `json:"digest"`
+	Vote      comments.VoteT `json:"vote"`
+	Digest    []byte         `json:"digest"`
+	Reaction  string         `json:"reaction,omitempty"`
+	Timestamp int64          `json:"timestamp,omitempty"`
+}
+
+// stakeIndex contains the verified tickets and the stake weight of the most
+// recent stake proof of a user. Digests contains the digests of all of the
+// stake proofs that have been submitted by the user, from oldest to newest. A
+// new stake proof replaces the previous stake proof of the user.
+type stakeIndex struct {
+	Tickets []string `json:"tickets"`
+	Weight  uint64   `json:"weight"`
+	Digests [][]byte `json:"digests"`
+}
+
+// pinIndex contains the ID of the pinned comment and the digests of all of
+// the comment pins that have been submitted for the record, from oldest to
+// newest. A comment ID of 0 means that the record does not have a pinned
+// comment.
+type pinIndex struct {
+	CommentID uint32   `json:"commentid"`
+	Digests   [][]byte `json:"digests"`
 }
 
 // recordIndexPath returns the file path for a cached record index. It accepts
@@ -151,8 +188,8 @@ func (p *commentsPlugin) recordIndexSave(token []byte, s backend.StateT, ridx re
 	}
 }
 
-// recordIndexRemove removes the record index cache from the path of the
-// provided record token and state.
+// recordIndexRemove removes the record index cache and the comment count
+// cache from the path of the provided record token and state.
 //
 // This function must be called WITHOUT be write lock held.
 func (p *commentsPlugin) recordIndexRemove(token []byte, s backend.StateT) error {
@@ -163,6 +200,14 @@ func (p *commentsPlugin) recordIndexRemove(token []byte, s backend.StateT) error
 	if err != nil {
 		return err
 	}
+	err = os.RemoveAll(path)
+	if err != nil {
+		return err
+	}
+	path, err = p.commentCountPath(token, s)
+	if err != nil {
+		return err
+	}
 
 	return os.RemoveAll(path)
 }
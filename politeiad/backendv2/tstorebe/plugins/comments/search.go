@@ -0,0 +1,556 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// Filenames of the comment search indexes that are saved to the
+	// comments plugin data dir.
+	fnSearchIndexUnvetted = "{shorttoken}-search-unvetted.json"
+	fnSearchIndexVetted   = "{shorttoken}-search-vetted.json"
+)
+
+// searchIndex contains the searchable data of the latest version of each
+// comment on a record. Deleted comments are not part of the search index.
+//
+// The search index is updated by the comments plugin post plugin hook
+// whenever a comment is added, edited, or deleted.
+type searchIndex struct {
+	Token    string                 `json:"token"`
+	Comments map[uint32]searchEntry `json:"comments"` // [commentID]entry
+}
+
+// searchEntry contains the searchable data of a comment. Text is the comment
+// text normalized using normalizeSearchText.
+type searchEntry struct {
+	UserID    string `json:"userid"`
+	Version   uint32 `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+	Text      string `json:"text"`
+}
+
+// searchTerm is a single term of a search query. Phrase contains one or more
+// normalized words.
+type searchTerm struct {
+	Phrase  string
+	Exclude bool
+}
+
+// normalizeSearchText returns the lowercase words of the provided text joined
+// by single spaces. Any character that is not a letter or number is treated
+// as a word separator.
+func normalizeSearchText(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// parseSearchQuery parses a search query into its terms. See the comments
+// plugin API Search command for the query syntax.
+func parseSearchQuery(q string) ([]searchTerm, error) {
+	if len(q) > comments.SearchQueryLengthMax {
+		return nil, fmt.Errorf("query exceeds %v characters",
+			comments.SearchQueryLengthMax)
+	}
+
+	var (
+		terms    = make([]searchTerm, 0, 8)
+		included int
+		rs       = []rune(q)
+	)
+	for i := 0; i < len(rs); {
+		// Skip whitespace
+		if unicode.IsSpace(rs[i]) {
+			i++
+			continue
+		}
+
+		// Parse the exclude prefix
+		var exclude bool
+		if rs[i] == '-' {
+			exclude = true
+			i++
+			if i == len(rs) {
+				break
+			}
+		}
+
+		// Parse the term
+		var raw string
+		if rs[i] == '"' {
+			end := i + 1
+			for end < len(rs) && rs[end] != '"' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			raw = string(rs[i+1 : end])
+			i = end + 1
+		} else {
+			end := i
+			for end < len(rs) && !unicode.IsSpace(rs[end]) {
+				end++
+			}
+			raw = string(rs[i:end])
+			i = end
+		}
+
+		// Terms that only contain punctuation can not match anything
+		phrase := normalizeSearchText(raw)
+		if phrase == "" {
+			continue
+		}
+		terms = append(terms, searchTerm{
+			Phrase:  phrase,
+			Exclude: exclude,
+		})
+		if !exclude {
+			included++
+		}
+	}
+	if included == 0 {
+		return nil, fmt.Errorf("query does not contain a search term")
+	}
+
+	return terms, nil
+}
+
+// searchMatch returns whether the provided normalized text matches all of the
+// search terms.
+func searchMatch(text string, terms []searchTerm) bool {
+	text = " " + text + " "
+	for _, v := range terms {
+		if strings.Contains(text, " "+v.Phrase+" ") == v.Exclude {
+			return false
+		}
+	}
+	return true
+}
+
+// searchIndexPath returns the file path for a cached search index. It accepts
+// both the full length token or the short token, but the short token is
+// always used in the file path string. A nil token returns the glob pattern
+// that matches the search indexes of all records in the provided state.
+func (p *commentsPlugin) searchIndexPath(token []byte, s backend.StateT) (string, error) {
+	var fn string
+	switch s {
+	case backend.StateUnvetted:
+		fn = fnSearchIndexUnvetted
+	case backend.StateVetted:
+		fn = fnSearchIndexVetted
+	default:
+		return "", fmt.Errorf("invalid state")
+	}
+
+	t := "*"
+	if token != nil {
+		var err error
+		t, err = util.ShortTokenEncode(token)
+		if err != nil {
+			return "", err
+		}
+	}
+	fn = strings.Replace(fn, "{shorttoken}", t, 1)
+	return filepath.Join(p.dataDir, fn), nil
+}
+
+// readSearchIndex reads the search index at the provided file path. If the
+// file does not exist, a new searchIndex will be returned.
+func readSearchIndex(fp string) (*searchIndex, error) {
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		var e *os.PathError
+		if errors.As(err, &e) && !os.IsExist(err) {
+			// File does't exist. Return a new searchIndex instead.
+			return &searchIndex{
+				Comments: make(map[uint32]searchEntry),
+			}, nil
+		}
+		return nil, err
+	}
+
+	var sidx searchIndex
+	err = json.Unmarshal(b, &sidx)
+	if err != nil {
+		return nil, err
+	}
+	if sidx.Comments == nil {
+		sidx.Comments = make(map[uint32]searchEntry)
+	}
+
+	return &sidx, nil
+}
+
+// searchIndexes returns the cached search indexes of the records in the
+// provided state. If a token is provided, only the search index of that
+// record is returned.
+//
+// This function must be called WITHOUT the read lock held.
+func (p *commentsPlugin) searchIndexes(token []byte, s backend.StateT) ([]searchIndex, error) {
+	pattern, err := p.searchIndexPath(token, s)
+	if err != nil {
+		return nil, err
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]searchIndex, 0, len(files))
+	for _, fp := range files {
+		sidx, err := readSearchIndex(fp)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, *sidx)
+	}
+
+	return indexes, nil
+}
+
+// searchIndexSave saves the provided search index to the comments plugin data
+// dir. The search index file is removed when it does not contain any
+// comments.
+//
+// This function must be called WITHOUT the read/write lock held.
+func (p *commentsPlugin) searchIndexSave(token []byte, s backend.StateT, sidx searchIndex) error {
+	fp, err := p.searchIndexPath(token, s)
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	return writeSearchIndex(fp, sidx)
+}
+
+// writeSearchIndex writes the search index to the provided file path.
+//
+// This function must be called WITH the write lock held.
+func writeSearchIndex(fp string, sidx searchIndex) error {
+	if len(sidx.Comments) == 0 {
+		err := os.Remove(fp)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.Marshal(sidx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fp, b, 0664)
+}
+
+// searchIndexUpdate updates the search index entry of the provided comment.
+// Deleted comments are removed from the search index. The read, update, and
+// save are all done while holding the write lock.
+//
+// This function must be called WITHOUT the read/write lock held.
+func (p *commentsPlugin) searchIndexUpdate(c comments.Comment) error {
+	token, err := tokenDecode(c.Token)
+	if err != nil {
+		return err
+	}
+	fp, err := p.searchIndexPath(token, backend.StateT(c.State))
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	sidx, err := readSearchIndex(fp)
+	if err != nil {
+		return err
+	}
+	sidx.Token = c.Token
+	if c.Deleted {
+		delete(sidx.Comments, c.CommentID)
+	} else {
+		sidx.Comments[c.CommentID] = searchEntry{
+			UserID:    c.UserID,
+			Version:   c.Version,
+			Timestamp: c.Timestamp,
+			Text:      normalizeSearchText(c.Comment),
+		}
+	}
+
+	return writeSearchIndex(fp, *sidx)
+}
+
+// hookPluginPost updates the search index when a comment is added, edited,
+// or deleted.
+func (p *commentsPlugin) hookPluginPost(payload string) error {
+	var hpp plugins.HookPluginPost
+	err := json.Unmarshal([]byte(payload), &hpp)
+	if err != nil {
+		return err
+	}
+	if hpp.PluginID != comments.PluginID {
+		return nil
+	}
+
+	// Decode the comments from the plugin reply
+	var cs []comments.Comment
+	switch hpp.Cmd {
+	case comments.CmdNew:
+		var nr comments.NewReply
+		err = json.Unmarshal([]byte(hpp.Reply), &nr)
+		cs = []comments.Comment{nr.Comment}
+	case comments.CmdEdit:
+		var er comments.EditReply
+		err = json.Unmarshal([]byte(hpp.Reply), &er)
+		cs = []comments.Comment{er.Comment}
+	case comments.CmdDel:
+		var dr comments.DelReply
+		err = json.Unmarshal([]byte(hpp.Reply), &dr)
+		cs = []comments.Comment{dr.Comment}
+	case comments.CmdDelUser:
+		var dr comments.DelUserReply
+		err = json.Unmarshal([]byte(hpp.Reply), &dr)
+		cs = dr.Comments
+	default:
+		// Not a comment write; nothing to do
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, v := range cs {
+		err = p.searchIndexUpdate(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hookSetRecordStatusPost removes the search index of a record when the
+// record is censored. The comments of a censored record are no longer
+// searchable.
+func (p *commentsPlugin) hookSetRecordStatusPost(payload string) error {
+	var srs plugins.HookSetRecordStatus
+	err := json.Unmarshal([]byte(payload), &srs)
+	if err != nil {
+		return err
+	}
+	if srs.RecordMetadata.Status != backend.StatusCensored {
+		return nil
+	}
+	token, err := tokenDecode(srs.RecordMetadata.Token)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Removing search index of censored record %x", token)
+
+	return p.searchIndexSave(token, srs.RecordMetadata.State, searchIndex{})
+}
+
+// fsckSearchIndex verifies that the search index of a record contains the
+// latest version of all comments that have not been deleted. The search index
+// is rebuilt from scratch if it is not coherent. This builds the search index
+// of records that contain comments that were made before the search index was
+// added. The returned bool will be true if the search index was rebuilt.
+func (p *commentsPlugin) fsckSearchIndex(token []byte) (bool, error) {
+	// Censored records are not searchable
+	r, err := p.tstore.RecordPartial(token, 0, nil, true)
+	if err != nil {
+		return false, err
+	}
+	state := r.RecordMetadata.State
+	if r.RecordMetadata.Status == backend.StatusCensored {
+		return false, p.searchIndexSave(token, state, searchIndex{})
+	}
+
+	// Get the comments that should be part of the search index
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return false, err
+	}
+	latest := make(map[uint32]uint32, len(ridx.Comments)) // [commentID]version
+	for cid, cidx := range ridx.Comments {
+		if cidx.Del != nil {
+			continue
+		}
+		latest[cid] = commentVersionLatest(cidx)
+	}
+
+	// Verify the search index
+	indexes, err := p.searchIndexes(token, state)
+	if err != nil {
+		return false, err
+	}
+	var sidx searchIndex
+	if len(indexes) > 0 {
+		sidx = indexes[0]
+	}
+	coherent := len(sidx.Comments) == len(latest)
+	for cid, version := range latest {
+		if e, ok := sidx.Comments[cid]; !ok || e.Version != version {
+			coherent = false
+			break
+		}
+	}
+	if coherent {
+		return false, nil
+	}
+
+	// Rebuild the search index
+	log.Debugf("%x rebuilding search index", token)
+
+	commentIDs := make([]uint32, 0, len(latest))
+	for cid := range latest {
+		commentIDs = append(commentIDs, cid)
+	}
+	cs, err := p.comments(token, *ridx, commentIDs)
+	if err != nil {
+		return false, err
+	}
+	sidx = searchIndex{
+		Token:    hex.EncodeToString(token),
+		Comments: make(map[uint32]searchEntry, len(cs)),
+	}
+	for _, c := range cs {
+		sidx.Comments[c.CommentID] = searchEntry{
+			UserID:    c.UserID,
+			Version:   c.Version,
+			Timestamp: c.Timestamp,
+			Text:      normalizeSearchText(c.Comment),
+		}
+	}
+	err = p.searchIndexSave(token, state, sidx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// cmdSearch searches the comments of the records in the provided state.
+func (p *commentsPlugin) cmdSearch(token []byte, payload string) (string, error) {
+	// Decode payload
+	var s comments.Search
+	err := json.Unmarshal([]byte(payload), &s)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify state
+	var state backend.StateT
+	switch s.State {
+	case comments.RecordStateUnvetted:
+		state = backend.StateUnvetted
+	case comments.RecordStateVetted:
+		state = backend.StateVetted
+	default:
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeRecordStateInvalid),
+		}
+	}
+
+	// Parse query
+	terms, err := parseSearchQuery(s.Query)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeSearchQueryInvalid),
+			ErrorContext: err.Error(),
+		}
+	}
+
+	// Search the indexes
+	if len(token) == 0 {
+		token = nil
+	}
+	indexes, err := p.searchIndexes(token, state)
+	if err != nil {
+		return "", err
+	}
+	results := make([]comments.SearchResult, 0, 256)
+	for _, sidx := range indexes {
+		for cid, e := range sidx.Comments {
+			if s.UserID != "" && e.UserID != s.UserID {
+				continue
+			}
+			if !searchMatch(e.Text, terms) {
+				continue
+			}
+			results = append(results, comments.SearchResult{
+				Token:     sidx.Token,
+				CommentID: cid,
+				UserID:    e.UserID,
+				Timestamp: e.Timestamp,
+			})
+		}
+	}
+
+	// Order the results from newest to oldest
+	sort.Slice(results, func(i, j int) bool {
+		ri, rj := results[i], results[j]
+		switch {
+		case ri.Timestamp != rj.Timestamp:
+			return ri.Timestamp > rj.Timestamp
+		case ri.Token != rj.Token:
+			return ri.Token < rj.Token
+		}
+		return ri.CommentID < rj.CommentID
+	})
+
+	// Prepare reply
+	sr := comments.SearchReply{
+		Results: searchPage(results, s.Page, p.searchPageSize),
+		Total:   uint32(len(results)),
+	}
+	reply, err := json.Marshal(sr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// searchPage returns the requested page of search results. Page 1 is returned
+// when a page is not provided.
+func searchPage(results []comments.SearchResult, page, pageSize uint32) []comments.SearchResult {
+	if page == 0 {
+		page = 1
+	}
+	start := uint64(page-1) * uint64(pageSize)
+	if start >= uint64(len(results)) {
+		return []comments.SearchResult{}
+	}
+	end := start + uint64(pageSize)
+	if end > uint64(len(results)) {
+		end = uint64(len(results))
+	}
+	return results[start:end]
+}
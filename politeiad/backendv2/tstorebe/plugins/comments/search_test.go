@@ -0,0 +1,222 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	// Setup tests
+	tests := []struct {
+		name        string
+		query       string
+		shouldError bool
+		terms       []searchTerm
+	}{
+		{
+			name:  "words",
+			query: "Budget  Treasury",
+			terms: []searchTerm{
+				{Phrase: "budget"},
+				{Phrase: "treasury"},
+			},
+		},
+		{
+			name:  "phrase and exclusions",
+			query: `"Milestone 2" -delay -"not paid"`,
+			terms: []searchTerm{
+				{Phrase: "milestone 2"},
+				{Phrase: "delay", Exclude: true},
+				{Phrase: "not paid", Exclude: true},
+			},
+		},
+		{
+			name:  "punctuation is ignored",
+			query: "don't ...",
+			terms: []searchTerm{
+				{Phrase: "don t"},
+			},
+		},
+		{
+			name:        "unterminated quote",
+			query:       `"milestone 2`,
+			shouldError: true,
+		},
+		{
+			name:        "only exclusions",
+			query:       "-delay",
+			shouldError: true,
+		},
+		{
+			name:        "empty",
+			query:       "  - ",
+			shouldError: true,
+		},
+	}
+
+	// Run tests
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			terms, err := parseSearchQuery(tc.query)
+			switch {
+			case tc.shouldError && err == nil:
+				t.Errorf("want error got nil")
+			case !tc.shouldError && err != nil:
+				t.Errorf("want error nil, got '%v'", err)
+			case !tc.shouldError && !reflect.DeepEqual(terms, tc.terms):
+				t.Errorf("unexpected terms; want: %v, got: %v", tc.terms, terms)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	p, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+	p.searchPageSize = 2
+
+	var (
+		token  = "45154fb45664714b"
+		userA  = "a"
+		userB  = "b"
+		vetted = comments.RecordStateVetted
+	)
+
+	// hook executes the post plugin hook for a comments plugin
+	// write command.
+	hook := func(cmd string, reply interface{}) {
+		t.Helper()
+		b, err := json.Marshal(reply)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err = json.Marshal(plugins.HookPluginPost{
+			PluginID: comments.PluginID,
+			Cmd:      cmd,
+			Reply:    string(b),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.hookPluginPost(string(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	comment := func(id uint32, userID, text string, ts int64) comments.Comment {
+		return comments.Comment{
+			UserID:    userID,
+			State:     vetted,
+			Token:     token,
+			CommentID: id,
+			Version:   1,
+			Timestamp: ts,
+			Comment:   text,
+		}
+	}
+
+	// search executes the search command and returns the IDs of the
+	// matched comments.
+	search := func(s comments.Search) ([]uint32, uint32) {
+		t.Helper()
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply, err := p.cmdSearch(nil, string(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sr comments.SearchReply
+		err = json.Unmarshal([]byte(reply), &sr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]uint32, 0, len(sr.Results))
+		for _, v := range sr.Results {
+			ids = append(ids, v.CommentID)
+		}
+		return ids, sr.Total
+	}
+
+	// Add comments
+	hook(comments.CmdNew, comments.NewReply{
+		Comment: comment(1, userA, "The budget looks fine.", 100),
+	})
+	hook(comments.CmdNew, comments.NewReply{
+		Comment: comment(2, userB, "Budget is too high!", 200),
+	})
+	hook(comments.CmdNew, comments.NewReply{
+		Comment: comment(3, userA, "What about the budget for milestone 2?", 300),
+	})
+
+	// Results are ordered from newest to oldest and paginated
+	ids, total := search(comments.Search{Query: "BUDGET", State: vetted})
+	if total != 3 || !reflect.DeepEqual(ids, []uint32{3, 2}) {
+		t.Fatalf("got %v %v, want [3 2] 3", ids, total)
+	}
+	ids, _ = search(comments.Search{Query: "budget", State: vetted, Page: 2})
+	if !reflect.DeepEqual(ids, []uint32{1}) {
+		t.Fatalf("got page 2 %v, want [1]", ids)
+	}
+
+	// Filters
+	ids, _ = search(comments.Search{Query: "budget -high", State: vetted,
+		UserID: userB})
+	if len(ids) != 0 {
+		t.Fatalf("got %v, want no results", ids)
+	}
+	ids, _ = search(comments.Search{Query: `"milestone 2"`, State: vetted})
+	if !reflect.DeepEqual(ids, []uint32{3}) {
+		t.Fatalf("got %v, want [3]", ids)
+	}
+
+	// Edits replace the indexed text and deletes remove the comment
+	c := comment(3, userA, "Nevermind", 400)
+	c.Version = 2
+	hook(comments.CmdEdit, comments.EditReply{Comment: c})
+	hook(comments.CmdDel, comments.DelReply{
+		Comment: comments.Comment{
+			State:     vetted,
+			Token:     token,
+			CommentID: 1,
+			Deleted:   true,
+		},
+	})
+	ids, total = search(comments.Search{Query: "budget", State: vetted})
+	if total != 1 || !reflect.DeepEqual(ids, []uint32{2}) {
+		t.Fatalf("got %v %v, want [2] 1", ids, total)
+	}
+
+	// Unvetted comments are searched separately
+	ids, _ = search(comments.Search{Query: "budget",
+		State: comments.RecordStateUnvetted})
+	if len(ids) != 0 {
+		t.Fatalf("got unvetted %v, want no results", ids)
+	}
+
+	// Bulk deletes remove all of the deleted comments
+	hook(comments.CmdDelUser, comments.DelUserReply{
+		Comments: []comments.Comment{
+			{
+				UserID:    userB,
+				State:     vetted,
+				Token:     token,
+				CommentID: 2,
+				Deleted:   true,
+			},
+		},
+	})
+	ids, total = search(comments.Search{Query: "budget", State: vetted})
+	if total != 0 || len(ids) != 0 {
+		t.Fatalf("got %v %v, want no results", ids, total)
+	}
+}
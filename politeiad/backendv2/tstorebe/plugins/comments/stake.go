@@ -0,0 +1,389 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/util"
+	"github.com/pkg/errors"
+)
+
+// cmdStakeProof verifies and saves a stake proof. The comment votes of the
+// user on the record are weighted by the stake of the proven tickets. A new
+// stake proof from a user replaces the previous stake proof of the user.
+func (p *commentsPlugin) cmdStakeProof(token []byte, payload string) (string, error) {
+	if !p.stakeWeighting {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeStakeWeightingDisabled),
+		}
+	}
+
+	// Decode payload
+	var sp comments.StakeProof
+	err := json.Unmarshal([]byte(payload), &sp)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify token
+	err = tokenVerify(token, sp.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify signature
+	tickets := make([]string, 0, len(sp.Tickets))
+	for _, v := range sp.Tickets {
+		tickets = append(tickets, v.Ticket)
+	}
+	msg := sp.Token + sp.UserID + strings.Join(tickets, "")
+	err = util.VerifySignature(sp.Signature, sp.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Verify record state. Only vetted records have a ticket vote.
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	if state != backend.StateVetted {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeRecordStateInvalid),
+			ErrorContext: "record is not vetted",
+		}
+	}
+
+	// Get record index
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the tickets. A ticket can only be claimed by a single
+	// user.
+	claimed := make(map[string]string, 256) // [ticket]userID
+	for userID, v := range ridx.Stakes {
+		if userID == sp.UserID {
+			continue
+		}
+		for _, ticket := range v.Tickets {
+			claimed[ticket] = userID
+		}
+	}
+	err = verifyStakeTickets(tickets, claimed)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify ticket ownership
+	weight, err := p.ticketOwnership(token, sp)
+	if err != nil {
+		return "", err
+	}
+
+	// Save the user stake
+	receipt := p.identity.SignMessage([]byte(sp.Signature))
+	us := comments.UserStake{
+		UserID:    sp.UserID,
+		Token:     sp.Token,
+		Tickets:   sp.Tickets,
+		PublicKey: sp.PublicKey,
+		Signature: sp.Signature,
+		Weight:    weight,
+		Timestamp: time.Now().Unix(),
+		Receipt:   hex.EncodeToString(receipt[:]),
+	}
+	digest, err := p.userStakeSave(token, us)
+	if err != nil {
+		return "", err
+	}
+
+	// Update the record index
+	if ridx.Stakes == nil {
+		ridx.Stakes = make(map[string]stakeIndex, 1)
+	}
+	sidx := ridx.Stakes[us.UserID]
+	sidx.Tickets = tickets
+	sidx.Weight = us.Weight
+	sidx.Digests = append(sidx.Digests, digest)
+	ridx.Stakes[us.UserID] = sidx
+	p.recordIndexSave(token, state, *ridx)
+
+	log.Debugf("Stake proof %x %v: %v tickets, weight %v",
+		token, us.UserID, len(tickets), us.Weight)
+
+	// Prepare reply
+	reply, err := json.Marshal(comments.StakeProofReply{
+		Weight:    us.Weight,
+		Timestamp: us.Timestamp,
+		Receipt:   us.Receipt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// verifyStakeTickets verifies that the tickets of a stake proof are not empty,
+// do not contain duplicates, and have not been claimed by another user.
+func verifyStakeTickets(tickets []string, claimed map[string]string) error {
+	invalid := func(format string, args ...interface{}) error {
+		return backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeStakeProofInvalid),
+			ErrorContext: fmt.Sprintf(format, args...),
+		}
+	}
+	if len(tickets) == 0 {
+		return invalid("no tickets provided")
+	}
+	dups := make(map[string]struct{}, len(tickets))
+	for _, v := range tickets {
+		if _, ok := dups[v]; ok {
+			return invalid("duplicate ticket %v", v)
+		}
+		dups[v] = struct{}{}
+		if _, ok := claimed[v]; ok {
+			return invalid("ticket %v has been claimed by another user", v)
+		}
+	}
+	return nil
+}
+
+// ticketOwnership verifies the ticket signatures of a stake proof using the
+// ticketvote plugin and returns the total weight of the tickets. An error is
+// returned if any of the tickets fail verification.
+func (p *commentsPlugin) ticketOwnership(token []byte, sp comments.StakeProof) (uint64, error) {
+	to := ticketvote.TicketOwnership{
+		Message:    comments.StakeProofPrefix + sp.Token + sp.UserID,
+		Signatures: make([]ticketvote.TicketSignature, 0, len(sp.Tickets)),
+	}
+	for _, v := range sp.Tickets {
+		to.Signatures = append(to.Signatures, ticketvote.TicketSignature{
+			Ticket:    v.Ticket,
+			Signature: v.Signature,
+		})
+	}
+	b, err := json.Marshal(to)
+	if err != nil {
+		return 0, err
+	}
+	reply, err := p.backend.PluginRead(token, ticketvote.PluginID,
+		ticketvote.CmdTicketOwnership, string(b))
+	if err != nil {
+		var pe backend.PluginError
+		if errors.As(err, &pe) && pe.PluginID == ticketvote.PluginID {
+			// The ticket vote has not been started
+			return 0, backend.PluginError{
+				PluginID:     comments.PluginID,
+				ErrorCode:    uint32(comments.ErrorCodeStakeProofInvalid),
+				ErrorContext: pe.ErrorContext,
+			}
+		}
+		return 0, err
+	}
+	var tor ticketvote.TicketOwnershipReply
+	err = json.Unmarshal([]byte(reply), &tor)
+	if err != nil {
+		return 0, err
+	}
+
+	// Return the first error in the order that the tickets were
+	// provided in.
+	var weight uint64
+	for _, v := range sp.Tickets {
+		if e, ok := tor.Errors[v.Ticket]; ok {
+			return 0, backend.PluginError{
+				PluginID:     comments.PluginID,
+				ErrorCode:    uint32(comments.ErrorCodeStakeProofInvalid),
+				ErrorContext: fmt.Sprintf("ticket %v: %v", v.Ticket, e),
+			}
+		}
+		w, ok := tor.Weights[v.Ticket]
+		if !ok {
+			return 0, fmt.Errorf("ticket weight not found %v", v.Ticket)
+		}
+		weight += w
+	}
+
+	return weight, nil
+}
+
+// stakeVoteScore returns the total stake weighted downvotes and upvotes,
+// respectively, for a comment. The net vote of each user that has submitted a
+// stake proof is weighted by the stake of the user. Votes from users that have
+// not submitted a stake proof are not included.
+func stakeVoteScore(cidx commentIndex, stakes map[string]stakeIndex) (uint64, uint64) {
+	var downvotes, upvotes uint64
+	for userID, votes := range cidx.Votes {
+		sidx, ok := stakes[userID]
+		if !ok {
+			continue
+		}
+		switch userVoteScore(votes) {
+		case -1:
+			downvotes += sidx.Weight
+		case 1:
+			upvotes += sidx.Weight
+		}
+	}
+	return downvotes, upvotes
+}
+
+// sortComments returns the IDs of the provided comments in the provided sort
+// order. Comments with the same score are ordered by comment ID. Comments are
+// ordered by comment ID when a sort order is not provided.
+func sortComments(cs map[uint32]comments.Comment, sortBy comments.SortT) []uint32 {
+	score := func(c comments.Comment) int64 {
+		if sortBy == comments.SortStakeScore {
+			return int64(c.StakeUpvotes) - int64(c.StakeDownvotes)
+		}
+		return int64(c.Upvotes) - int64(c.Downvotes)
+	}
+	order := make([]uint32, 0, len(cs))
+	for id := range cs {
+		order = append(order, id)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		switch sortBy {
+		case comments.SortInvalid:
+			return order[i] < order[j]
+		case comments.SortTimestamp:
+			// Comment IDs are assigned in the order that the
+			// comments are created.
+			return order[i] > order[j]
+		}
+		si, sj := score(cs[order[i]]), score(cs[order[j]])
+		if si != sj {
+			return si > sj
+		}
+		return order[i] < order[j]
+	})
+	return order
+}
+
+// userStakeSave saves a UserStake to the backend.
+func (p *commentsPlugin) userStakeSave(token []byte, us comments.UserStake) ([]byte, error) {
+	be, err := convertBlobEntryFromUserStake(us)
+	if err != nil {
+		return nil, err
+	}
+	d, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, err
+	}
+	err = p.tstore.BlobSave(token, *be)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// userStakes returns a UserStake for each of the provided digests. The user
+// stakes are returned in the same order that they are requested in. An error
+// is returned if a blob entry is not found for one or more of the provided
+// digests.
+func (p *commentsPlugin) userStakes(token []byte, digests [][]byte) ([]comments.UserStake, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.Blobs(token, digests)
+	if err != nil {
+		return nil, err
+	}
+	if len(blobs) != len(digests) {
+		notFound := make([]string, 0, len(blobs))
+		for _, v := range digests {
+			m := hex.EncodeToString(v)
+			_, ok := blobs[m]
+			if !ok {
+				notFound = append(notFound, m)
+			}
+		}
+		return nil, fmt.Errorf("blobs not found: %v", notFound)
+	}
+
+	// Decode blobs
+	stakes := make([]comments.UserStake, 0, len(blobs))
+	for _, digest := range digests {
+		d := hex.EncodeToString(digest)
+		us, err := convertUserStakeFromBlobEntry(blobs[d])
+		if err != nil {
+			return nil, err
+		}
+		stakes = append(stakes, *us)
+	}
+
+	return stakes, nil
+}
+
+func convertBlobEntryFromUserStake(us comments.UserStake) (*store.BlobEntry, error) {
+	data, err := json.Marshal(us)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorUserStake,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+func convertUserStakeFromBlobEntry(be store.BlobEntry) (*comments.UserStake, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorUserStake {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, want %v",
+			dd.Descriptor, dataDescriptorUserStake)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var us comments.UserStake
+	err = json.Unmarshal(b, &us)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal UserStake: %v", err)
+	}
+
+	return &us, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestStakeVoteScore(t *testing.T) {
+	var (
+		up   = voteIndex{Vote: comments.VoteUpvote}
+		down = voteIndex{Vote: comments.VoteDownvote}
+	)
+	cidx := commentIndex{
+		Votes: map[string][]voteIndex{
+			"user1": {up},             // Net upvote
+			"user2": {down},           // Net downvote
+			"user3": {up, up},         // Vote removed
+			"user4": {up, down},       // Net downvote
+			"user5": {down, up, down}, // Net downvote, no stake
+		},
+	}
+	stakes := map[string]stakeIndex{
+		"user1": {Weight: 500},
+		"user2": {Weight: 100},
+		"user3": {Weight: 300},
+		"user4": {Weight: 50},
+	}
+
+	downvotes, upvotes := voteScore(cidx)
+	if downvotes != 3 || upvotes != 1 {
+		t.Fatalf("voteScore: got %v/%v, want 3/1", downvotes, upvotes)
+	}
+	downvotes, upvotes = stakeVoteScore(cidx, stakes)
+	if downvotes != 150 || upvotes != 500 {
+		t.Fatalf("stakeVoteScore: got %v/%v, want 150/500",
+			downvotes, upvotes)
+	}
+}
+
+func TestSortComments(t *testing.T) {
+	cs := map[uint32]comments.Comment{
+		1: {Upvotes: 1, StakeUpvotes: 100},
+		2: {Upvotes: 5, StakeDownvotes: 200},
+		3: {Upvotes: 1, StakeUpvotes: 100},
+		4: {Downvotes: 2, StakeUpvotes: 900},
+	}
+	var tests = []struct {
+		name   string
+		sortBy comments.SortT
+		want   []uint32
+	}{
+		{
+			"score",
+			comments.SortScore,
+			[]uint32{2, 1, 3, 4},
+		},
+		{
+			"stake score",
+			comments.SortStakeScore,
+			[]uint32{4, 1, 3, 2},
+		},
+		{
+			"timestamp",
+			comments.SortTimestamp,
+			[]uint32{4, 3, 2, 1},
+		},
+		{
+			"comment id",
+			comments.SortInvalid,
+			[]uint32{1, 2, 3, 4},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sortComments(cs, tc.sortBy)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
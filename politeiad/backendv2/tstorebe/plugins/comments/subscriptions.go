@@ -0,0 +1,359 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// Filenames of the comment thread subscriptions that are saved to
+	// the comments plugin data dir.
+	fnSubscriptionsUnvetted = "{shorttoken}-subscriptions-unvetted.json"
+	fnSubscriptionsVetted   = "{shorttoken}-subscriptions-vetted.json"
+)
+
+// subscriptions contains the users that are subscribed to each comment thread
+// of a record.
+//
+// Subscriptions are user preferences, not record data, so they are only
+// saved to the plugin data dir and are not saved to tstore.
+type subscriptions struct {
+	Comments map[uint32][]string `json:"comments"` // [commentID]userIDs
+}
+
+// update applies the provided subscription action to the subscriptions. The
+// returned bool will be true if the subscriptions were updated.
+func (s *subscriptions) update(commentID uint32, userID string, action comments.SubscribeActionT) bool {
+	if s.Comments == nil {
+		s.Comments = make(map[uint32][]string)
+	}
+	userIDs := s.Comments[commentID]
+	i := sort.SearchStrings(userIDs, userID)
+	exists := i < len(userIDs) && userIDs[i] == userID
+
+	switch {
+	case action == comments.SubscribeActionSubscribe && !exists:
+		userIDs = append(userIDs, "")
+		copy(userIDs[i+1:], userIDs[i:])
+		userIDs[i] = userID
+		s.Comments[commentID] = userIDs
+		return true
+
+	case action == comments.SubscribeActionUnsubscribe && exists:
+		userIDs = append(userIDs[:i], userIDs[i+1:]...)
+		if len(userIDs) == 0 {
+			delete(s.Comments, commentID)
+		} else {
+			s.Comments[commentID] = userIDs
+		}
+		return true
+	}
+
+	return false
+}
+
+// users returns the sorted IDs of the users that are subscribed to any of the
+// provided comment threads.
+func (s *subscriptions) users(commentIDs []uint32) []string {
+	var (
+		userIDs = make([]string, 0, 64)
+		dups    = make(map[string]struct{}, 64)
+	)
+	for _, cid := range commentIDs {
+		for _, v := range s.Comments[cid] {
+			if _, ok := dups[v]; ok {
+				continue
+			}
+			dups[v] = struct{}{}
+			userIDs = append(userIDs, v)
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs
+}
+
+// subscriptionsPath returns the file path for the cached comment thread
+// subscriptions of a record. It accepts both the full length token or the
+// short token, but the short token is always used in the file path string.
+func (p *commentsPlugin) subscriptionsPath(token []byte, s backend.StateT) (string, error) {
+	var fn string
+	switch s {
+	case backend.StateUnvetted:
+		fn = fnSubscriptionsUnvetted
+	case backend.StateVetted:
+		fn = fnSubscriptionsVetted
+	default:
+		return "", fmt.Errorf("invalid state")
+	}
+
+	t, err := util.ShortTokenEncode(token)
+	if err != nil {
+		return "", err
+	}
+	fn = strings.Replace(fn, "{shorttoken}", t, 1)
+	return filepath.Join(p.dataDir, fn), nil
+}
+
+// readSubscriptions reads the subscriptions at the provided file path. If the
+// file does not exist, a new subscriptions will be returned.
+func readSubscriptions(fp string) (*subscriptions, error) {
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		var e *os.PathError
+		if errors.As(err, &e) && !os.IsExist(err) {
+			// File does't exist. Return a new subscriptions instead.
+			return &subscriptions{}, nil
+		}
+		return nil, err
+	}
+
+	var s subscriptions
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// subscriptions returns the cached comment thread subscriptions for the
+// provided record.
+//
+// This function must be called WITHOUT the read lock held.
+func (p *commentsPlugin) subscriptions(token []byte, s backend.StateT) (*subscriptions, error) {
+	fp, err := p.subscriptionsPath(token, s)
+	if err != nil {
+		return nil, err
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	return readSubscriptions(fp)
+}
+
+// subscriptionsUpdate applies the provided subscription action to the cached
+// subscriptions of a record. The read, update, and save are all done while
+// holding the write lock.
+//
+// This function must be called WITHOUT the read/write lock held.
+func (p *commentsPlugin) subscriptionsUpdate(token []byte, s backend.StateT, commentID uint32, userID string, action comments.SubscribeActionT) error {
+	fp, err := p.subscriptionsPath(token, s)
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	subs, err := readSubscriptions(fp)
+	if err != nil {
+		return err
+	}
+	if !subs.update(commentID, userID, action) {
+		// Nothing to update
+		return nil
+	}
+	b, err := json.Marshal(subs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fp, b, 0664)
+}
+
+// commentThread returns the provided comment ID and the IDs of all of its
+// parent comments.
+func (p *commentsPlugin) commentThread(token []byte, ridx recordIndex, commentID uint32) ([]uint32, error) {
+	thread := make([]uint32, 0, 16)
+	for cid := commentID; cid != 0; {
+		// A comment can not be its own ancestor. This check prevents
+		// an infinite loop if the record index is not coherent.
+		if len(thread) > len(ridx.Comments) {
+			return nil, fmt.Errorf("comment %v parent cycle", commentID)
+		}
+		thread = append(thread, cid)
+
+		cidx, ok := ridx.Comments[cid]
+		if !ok {
+			return nil, fmt.Errorf("comment index not found %v", cid)
+		}
+		if cidx.Del != nil {
+			dels, err := p.commentDels(token, [][]byte{cidx.Del})
+			if err != nil {
+				return nil, fmt.Errorf("commentDels: %v", err)
+			}
+			cid = dels[0].ParentID
+			continue
+		}
+		latest := cidx.Adds[commentVersionLatest(cidx)]
+		adds, err := p.commentAdds(token, [][]byte{latest})
+		if err != nil {
+			return nil, fmt.Errorf("commentAdds: %v", err)
+		}
+		cid = adds[0].ParentID
+	}
+	return thread, nil
+}
+
+// cmdSubscribe subscribes a user to, or unsubscribes a user from, the replies
+// under a comment.
+func (p *commentsPlugin) cmdSubscribe(token []byte, payload string) (string, error) {
+	// Decode payload
+	var s comments.Subscribe
+	err := json.Unmarshal([]byte(payload), &s)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify token
+	err = tokenVerify(token, s.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify action
+	switch s.Action {
+	case comments.SubscribeActionSubscribe,
+		comments.SubscribeActionUnsubscribe:
+		// These are allowed
+	default:
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeSubscribeActionInvalid),
+		}
+	}
+
+	// Verify user ID. The user IDs of the subscribers are used by
+	// politeiawww to look up the users that should be notified.
+	_, err = uuid.Parse(s.UserID)
+	if err != nil {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeUserIDInvalid),
+		}
+	}
+
+	// Verify signature
+	msg := strconv.FormatUint(uint64(s.State), 10) + s.Token +
+		strconv.FormatUint(uint64(s.CommentID), 10) + string(s.Action)
+	err = util.VerifySignature(s.Signature, s.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Verify record state
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	if uint32(s.State) != uint32(state) {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeRecordStateInvalid),
+			ErrorContext: fmt.Sprintf("got %v, want %v", s.State, state),
+		}
+	}
+
+	// Verify comment exists
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+	if !commentExists(*ridx, s.CommentID) {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeCommentNotFound),
+		}
+	}
+
+	// Update the subscriptions
+	err = p.subscriptionsUpdate(token, state, s.CommentID, s.UserID, s.Action)
+	if err != nil {
+		return "", err
+	}
+
+	log.Debugf("Comment thread %x %v %v: %v",
+		token, s.CommentID, s.UserID, s.Action)
+
+	// Prepare reply
+	receipt := p.identity.SignMessage([]byte(s.Signature))
+	reply, err := json.Marshal(comments.SubscribeReply{
+		Timestamp: time.Now().Unix(),
+		Receipt:   hex.EncodeToString(receipt[:]),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdSubscribers retrieves the users that are subscribed to the thread that
+// contains a comment.
+func (p *commentsPlugin) cmdSubscribers(token []byte, payload string) (string, error) {
+	// Decode payload
+	var s comments.Subscribers
+	err := json.Unmarshal([]byte(payload), &s)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record index
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify comment exists
+	if !commentExists(*ridx, s.CommentID) {
+		return "", backend.PluginError{
+			PluginID:  comments.PluginID,
+			ErrorCode: uint32(comments.ErrorCodeCommentNotFound),
+		}
+	}
+
+	// Get the subscribers of the comment and its parent comments
+	subs, err := p.subscriptions(token, state)
+	if err != nil {
+		return "", err
+	}
+	userIDs := []string{}
+	if len(subs.Comments) > 0 {
+		thread, err := p.commentThread(token, *ridx, s.CommentID)
+		if err != nil {
+			return "", err
+		}
+		userIDs = subs.users(thread)
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(comments.SubscribersReply{
+		UserIDs: userIDs,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestSubscriptionsUpdate(t *testing.T) {
+	var (
+		s     subscriptions
+		sub   = comments.SubscribeActionSubscribe
+		unsub = comments.SubscribeActionUnsubscribe
+	)
+
+	// Setup tests. The tests are run in order and each test builds on
+	// the subscriptions of the previous tests.
+	tests := []struct {
+		name      string
+		commentID uint32
+		userID    string
+		action    comments.SubscribeActionT
+		updated   bool
+		want      map[uint32][]string
+	}{
+		{
+			name:      "subscribe",
+			commentID: 1,
+			userID:    "b",
+			action:    sub,
+			updated:   true,
+			want:      map[uint32][]string{1: {"b"}},
+		},
+		{
+			name:      "subscribe is sorted",
+			commentID: 1,
+			userID:    "a",
+			action:    sub,
+			updated:   true,
+			want:      map[uint32][]string{1: {"a", "b"}},
+		},
+		{
+			name:      "already subscribed",
+			commentID: 1,
+			userID:    "a",
+			action:    sub,
+			updated:   false,
+			want:      map[uint32][]string{1: {"a", "b"}},
+		},
+		{
+			name:      "not subscribed",
+			commentID: 2,
+			userID:    "a",
+			action:    unsub,
+			updated:   false,
+			want:      map[uint32][]string{1: {"a", "b"}},
+		},
+		{
+			name:      "unsubscribe",
+			commentID: 1,
+			userID:    "a",
+			action:    unsub,
+			updated:   true,
+			want:      map[uint32][]string{1: {"b"}},
+		},
+		{
+			name:      "unsubscribe last user",
+			commentID: 1,
+			userID:    "b",
+			action:    unsub,
+			updated:   true,
+			want:      map[uint32][]string{},
+		},
+	}
+
+	// Run tests
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updated := s.update(tc.commentID, tc.userID, tc.action)
+			if updated != tc.updated {
+				t.Errorf("got updated %v, want %v", updated, tc.updated)
+			}
+			if !reflect.DeepEqual(s.Comments, tc.want) {
+				t.Errorf("got subscriptions %v, want %v", s.Comments, tc.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionsUsers(t *testing.T) {
+	s := subscriptions{
+		Comments: map[uint32][]string{
+			1: {"b", "c"},
+			3: {"a", "c"},
+			4: {"d"},
+		},
+	}
+
+	// The subscribers of all comments in the thread are returned
+	// without duplicates.
+	got := s.users([]uint32{3, 2, 1})
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got users %v, want %v", got, want)
+	}
+
+	// A thread without any subscribers returns an empty list
+	got = s.users([]uint32{5, 2})
+	if len(got) != 0 {
+		t.Errorf("got users %v, want none", got)
+	}
+}
@@ -6,9 +6,11 @@ package comments
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 
+	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/plugins/comments"
 	"github.com/decred/politeia/util"
 	"github.com/pkg/errors"
@@ -18,8 +20,252 @@ const (
 	// timestampKey is the key for a timestamp entry in the key-value store
 	// cache.
 	timestampKey = "timestamp-{shorttoken}-{commentID}"
+
+	// recordTimestampsKey is the key for the timestamps of all comments
+	// on a frozen record in the key-value store cache.
+	recordTimestampsKey = "timestamps-{shorttoken}"
 )
 
+// commentTimestampsCached returns the CommentTimestamp for each of the
+// provided comment IDs.
+//
+// The comments of a frozen record can no longer change, so once all of the
+// timestamps of a frozen record are final they are cached as a single entry.
+// The timestamps of a frozen record are served from this entry instead of
+// being recomputed from the tlog tree on every request.
+func (p *commentsPlugin) commentTimestampsCached(token []byte, commentIDs []uint32, includeVotes bool) (*comments.TimestampsReply, error) {
+	// Verify there is work to do
+	if len(commentIDs) == 0 {
+		return &comments.TimestampsReply{
+			Comments: map[uint32]comments.CommentTimestamp{},
+		}, nil
+	}
+
+	// Only the timestamps of frozen records are cached in full
+	frozen, err := p.tstore.RecordFrozen(token)
+	if err != nil {
+		return nil, err
+	}
+	if !frozen {
+		return p.commentTimestamps(token, commentIDs, includeVotes)
+	}
+
+	ts, err := p.recordTimestamps(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comments.TimestampsReply{
+		Comments: filterTimestamps(ts, commentIDs, includeVotes),
+	}, nil
+}
+
+// recordTimestamps returns the timestamps of all of the comments on a frozen
+// record, including the comment vote timestamps. The timestamps are retrieved
+// from the cache if they have already been cached. Otherwise, they are
+// retrieved from tlog and cached if they are all final.
+func (p *commentsPlugin) recordTimestamps(token []byte) (map[uint32]comments.CommentTimestamp, error) {
+	ts, ok, err := p.cachedRecordTimestamps(token)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return ts, nil
+	}
+
+	// Get the timestamps of all comments
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return nil, err
+	}
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return nil, err
+	}
+	commentIDs := make([]uint32, 0, len(ridx.Comments))
+	for cid := range ridx.Comments {
+		commentIDs = append(commentIDs, cid)
+	}
+	tr, err := p.commentTimestamps(token, commentIDs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The timestamps can only be cached once they are all final. A
+	// frozen record receives one final anchor after being frozen.
+	if !timestampsAreFinal(tr.Comments) {
+		return tr.Comments, nil
+	}
+	err = p.saveRecordTimestamps(token, state, tr.Comments)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Cached final comment timestamps of frozen record %x", token)
+
+	return tr.Comments, nil
+}
+
+// cachedRecordTimestamps returns the cached timestamps of a frozen record. The
+// returned bool will be false if the timestamps have not been cached.
+func (p *commentsPlugin) cachedRecordTimestamps(token []byte) (map[uint32]comments.CommentTimestamp, bool, error) {
+	k, err := getRecordTimestampsKey(token)
+	if err != nil {
+		return nil, false, err
+	}
+	blobs, err := p.tstore.CacheGet([]string{k})
+	if err != nil {
+		return nil, false, err
+	}
+	b, ok := blobs[k]
+	if !ok {
+		return nil, false, nil
+	}
+	var ts map[uint32]comments.CommentTimestamp
+	err = json.Unmarshal(b, &ts)
+	if err != nil {
+		return nil, false, err
+	}
+	return ts, true, nil
+}
+
+// saveRecordTimestamps saves the timestamps of a frozen record to the
+// key-value cache. The timestamps of unvetted records are encrypted.
+func (p *commentsPlugin) saveRecordTimestamps(token []byte, s backend.StateT, ts map[uint32]comments.CommentTimestamp) error {
+	k, err := getRecordTimestampsKey(token)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(ts)
+	if err != nil {
+		return err
+	}
+	return p.tstore.CachePut(map[string][]byte{k: b},
+		s == backend.StateUnvetted)
+}
+
+// timestampsAreFinal returns whether all of the provided comment timestamps
+// are final.
+func timestampsAreFinal(ts map[uint32]comments.CommentTimestamp) bool {
+	for _, ct := range ts {
+		for _, v := range ct.Adds {
+			if !timestampIsFinal(v) {
+				return false
+			}
+		}
+		if ct.Del != nil && !timestampIsFinal(*ct.Del) {
+			return false
+		}
+		for _, v := range ct.Votes {
+			if !timestampIsFinal(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filterTimestamps returns the timestamps of the provided comment IDs. Comment
+// IDs that do not exist are not included in the returned map. The vote
+// timestamps are only included if includeVotes is set.
+func filterTimestamps(ts map[uint32]comments.CommentTimestamp, commentIDs []uint32, includeVotes bool) map[uint32]comments.CommentTimestamp {
+	r := make(map[uint32]comments.CommentTimestamp, len(commentIDs))
+	for _, cid := range commentIDs {
+		ct, ok := ts[cid]
+		if !ok {
+			continue
+		}
+		if !includeVotes {
+			ct.Votes = nil
+		}
+		r[cid] = ct
+	}
+	return r
+}
+
+// cmdTimestampsBatch retrieves the timestamps for the comments that fall
+// within the provided comment ID ranges.
+func (p *commentsPlugin) cmdTimestampsBatch(token []byte, payload string) (string, error) {
+	// Decode payload
+	var tb comments.TimestampsBatch
+	err := json.Unmarshal([]byte(payload), &tb)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record index
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Expand the comment ID ranges
+	commentIDs, err := commentIDsFromRanges(tb.Ranges,
+		commentIDLatest(*ridx), p.timestampsPageSize)
+	if err != nil {
+		return "", err
+	}
+
+	// Get timestamps
+	ctr, err := p.commentTimestampsCached(token, commentIDs, tb.IncludeVotes)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(*ctr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// commentIDsFromRanges returns the comment IDs that fall within the provided
+// comment ID ranges. The ranges are capped at the latest comment ID of the
+// record and duplicate comment IDs are only returned once. An error is
+// returned if a range is invalid or if the ranges contain more than pageSize
+// comment IDs.
+func commentIDsFromRanges(ranges []comments.CommentIDRange, latestID, pageSize uint32) ([]uint32, error) {
+	var (
+		commentIDs = make([]uint32, 0, pageSize)
+		dups       = make(map[uint32]struct{}, pageSize)
+	)
+	for _, v := range ranges {
+		if v.Start == 0 || v.Start > v.End {
+			return nil, backend.PluginError{
+				PluginID:     comments.PluginID,
+				ErrorCode:    uint32(comments.ErrorCodeCommentIDRangeInvalid),
+				ErrorContext: fmt.Sprintf("invalid range %v-%v", v.Start, v.End),
+			}
+		}
+		end := v.End
+		if end > latestID {
+			end = latestID
+		}
+		for cid := v.Start; cid <= end; cid++ {
+			if _, ok := dups[cid]; ok {
+				continue
+			}
+			if len(commentIDs) == int(pageSize) {
+				return nil, backend.PluginError{
+					PluginID:  comments.PluginID,
+					ErrorCode: uint32(comments.ErrorCodeCommentIDRangeInvalid),
+					ErrorContext: fmt.Sprintf("max page size is %v comments",
+						pageSize),
+				}
+			}
+			dups[cid] = struct{}{}
+			commentIDs = append(commentIDs, cid)
+		}
+	}
+	return commentIDs, nil
+}
+
 // cacheFinalTimestamps accepts a map of comment timestamps, it collects the
 // final timestamps then stores them in the key-value store.
 func (p *commentsPlugin) cacheFinalTimestamps(token []byte, cts map[uint32]comments.CommentTimestamp) error {
@@ -175,6 +421,16 @@ func getTimestampKey(token []byte, commentID uint32) (string, error) {
 	return key, nil
 }
 
+// getRecordTimestampsKey returns the key for the timestamps of a frozen
+// record in the key-value store cache.
+func getRecordTimestampsKey(token []byte) (string, error) {
+	t, err := util.ShortTokenEncode(token)
+	if err != nil {
+		return "", err
+	}
+	return strings.Replace(recordTimestampsKey, "{shorttoken}", t, 1), nil
+}
+
 // parseTimestampKey parses the comment ID from a timestamp key.
 func parseTimestampKey(key string) (uint32, error) {
 	s := strings.Split(key, "-")
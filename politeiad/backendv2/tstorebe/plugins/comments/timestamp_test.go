@@ -6,7 +6,10 @@ package comments
 
 import (
 	"encoding/hex"
+	"reflect"
 	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/comments"
 )
 
 func TestGetTimestampKey(t *testing.T) {
@@ -124,3 +127,116 @@ func TestParseTimestampKey(t *testing.T) {
 		})
 	}
 }
+
+func TestCommentIDsFromRanges(t *testing.T) {
+	// Setup tests
+	tests := []struct {
+		name        string
+		ranges      []comments.CommentIDRange
+		latestID    uint32
+		pageSize    uint32
+		shouldError bool
+		commentIDs  []uint32
+	}{
+		{
+			name:       "single range",
+			ranges:     []comments.CommentIDRange{{Start: 2, End: 4}},
+			latestID:   10,
+			pageSize:   5,
+			commentIDs: []uint32{2, 3, 4},
+		},
+		{
+			name: "overlapping ranges",
+			ranges: []comments.CommentIDRange{
+				{Start: 1, End: 3},
+				{Start: 2, End: 5},
+			},
+			latestID:   10,
+			pageSize:   5,
+			commentIDs: []uint32{1, 2, 3, 4, 5},
+		},
+		{
+			name:       "range capped at latest comment",
+			ranges:     []comments.CommentIDRange{{Start: 3, End: 100}},
+			latestID:   4,
+			pageSize:   5,
+			commentIDs: []uint32{3, 4},
+		},
+		{
+			name:        "zero start",
+			ranges:      []comments.CommentIDRange{{Start: 0, End: 2}},
+			latestID:    10,
+			pageSize:    5,
+			shouldError: true,
+		},
+		{
+			name:        "start after end",
+			ranges:      []comments.CommentIDRange{{Start: 3, End: 2}},
+			latestID:    10,
+			pageSize:    5,
+			shouldError: true,
+		},
+		{
+			name:        "exceeds page size",
+			ranges:      []comments.CommentIDRange{{Start: 1, End: 6}},
+			latestID:    10,
+			pageSize:    5,
+			shouldError: true,
+		},
+	}
+
+	// Run tests
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cids, err := commentIDsFromRanges(tc.ranges, tc.latestID,
+				tc.pageSize)
+			switch {
+			case tc.shouldError && err == nil:
+				t.Errorf("want error got nil")
+			case !tc.shouldError && err != nil:
+				t.Errorf("want error nil, got '%v'", err)
+			case !tc.shouldError && !reflect.DeepEqual(cids, tc.commentIDs):
+				t.Errorf("unexpected comment IDs; want: %v, got: %v",
+					tc.commentIDs, cids)
+			}
+		})
+	}
+}
+
+func TestFilterTimestamps(t *testing.T) {
+	ts := map[uint32]comments.CommentTimestamp{
+		1: {
+			Adds:  []comments.Timestamp{{TxID: "a"}},
+			Votes: []comments.Timestamp{{TxID: "b"}},
+		},
+		2: {
+			Adds: []comments.Timestamp{{TxID: "c"}},
+		},
+	}
+
+	// Comment IDs that do not exist are skipped
+	r := filterTimestamps(ts, []uint32{1, 3}, true)
+	if len(r) != 1 || len(r[1].Votes) != 1 {
+		t.Fatalf("unexpected timestamps %v", r)
+	}
+
+	// Vote timestamps are only included when requested
+	r = filterTimestamps(ts, []uint32{1, 2}, false)
+	if len(r) != 2 || r[1].Votes != nil {
+		t.Fatalf("unexpected timestamps %v", r)
+	}
+	if len(ts[1].Votes) != 1 {
+		t.Fatalf("source timestamps were modified")
+	}
+
+	// Timestamps are only final once all of them have been anchored
+	if !timestampsAreFinal(ts) {
+		t.Fatalf("want final timestamps")
+	}
+	ts[2] = comments.CommentTimestamp{
+		Adds: []comments.Timestamp{{}},
+	}
+	if timestampsAreFinal(ts) {
+		t.Fatalf("want timestamps that are not final")
+	}
+}
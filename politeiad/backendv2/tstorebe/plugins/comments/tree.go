@@ -0,0 +1,126 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/json"
+	"fmt"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+// cmdTree retrieves the subtree of comments that are replies to the provided
+// parent comment.
+func (p *commentsPlugin) cmdTree(token []byte, payload string) (string, error) {
+	// Decode payload
+	var t comments.Tree
+	err := json.Unmarshal([]byte(payload), &t)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify sort order
+	err = p.sortVerify(t.SortBy)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record state
+	state, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Get record index
+	ridx, err := p.recordIndex(token, state)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the parent comment exists
+	if _, ok := ridx.Comments[t.ParentID]; t.ParentID != 0 && !ok {
+		return "", backend.PluginError{
+			PluginID:     comments.PluginID,
+			ErrorCode:    uint32(comments.ErrorCodeParentIDInvalid),
+			ErrorContext: "comment not found",
+		}
+	}
+
+	// Get all comments. The record index does not contain the parent
+	// IDs of the comments, so the full comments are required in order
+	// to build the tree.
+	commentIDs := make([]uint32, 0, len(ridx.Comments))
+	for id := range ridx.Comments {
+		commentIDs = append(commentIDs, id)
+	}
+	cs, err := p.comments(token, *ridx, commentIDs)
+	if err != nil {
+		return "", fmt.Errorf("comments: %v", err)
+	}
+
+	// Build the subtree
+	var (
+		depth    = t.Depth
+		children = t.Children
+	)
+	if depth == 0 || depth > p.treeDepthMax {
+		depth = p.treeDepthMax
+	}
+	if children == 0 || children > p.treeChildrenMax {
+		children = p.treeChildrenMax
+	}
+	tree, replies := commentTree(cs, t.SortBy, t.ParentID, depth, children)
+
+	// Prepare reply
+	tr := comments.TreeReply{
+		Comments: tree,
+		Replies:  replies,
+	}
+	reply, err := json.Marshal(tr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// commentTree returns the subtree of replies to the provided parent comment
+// along with the total number of direct replies to the parent. The subtree
+// contains at most depth levels of replies and at most childrenMax replies
+// per comment. The replies of each comment are in the provided sort order.
+func commentTree(cs map[uint32]comments.Comment, sortBy comments.SortT, parentID, depth, childrenMax uint32) ([]comments.TreeComment, uint32) {
+	// Group the replies by parent. The comments are sorted first so
+	// that the replies of each comment are in the sort order.
+	children := make(map[uint32][]uint32, len(cs))
+	for _, id := range sortComments(cs, sortBy) {
+		pid := cs[id].ParentID
+		children[pid] = append(children[pid], id)
+	}
+
+	// Build the tree. A reply always has a larger comment ID than
+	// its parent so the tree cannot contain cycles.
+	var build func(parentID, depth uint32) []comments.TreeComment
+	build = func(parentID, depth uint32) []comments.TreeComment {
+		ids := children[parentID]
+		if len(ids) > int(childrenMax) {
+			ids = ids[:childrenMax]
+		}
+		tcs := make([]comments.TreeComment, 0, len(ids))
+		for _, id := range ids {
+			tc := comments.TreeComment{
+				Comment: cs[id],
+				Replies: uint32(len(children[id])),
+			}
+			if depth > 1 {
+				tc.Children = build(id, depth-1)
+			}
+			tcs = append(tcs, tc)
+		}
+		return tcs
+	}
+
+	return build(parentID, depth), uint32(len(children[parentID]))
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestCommentTree(t *testing.T) {
+	// Comment hierarchy:
+	// 1
+	// ├── 2
+	// │   └── 4
+	// │       └── 6
+	// └── 3
+	// 5
+	cs := map[uint32]comments.Comment{
+		1: {CommentID: 1, ParentID: 0},
+		2: {CommentID: 2, ParentID: 1},
+		3: {CommentID: 3, ParentID: 1, Upvotes: 2},
+		4: {CommentID: 4, ParentID: 2},
+		5: {CommentID: 5, ParentID: 0},
+		6: {CommentID: 6, ParentID: 4},
+	}
+
+	// ids returns the comment IDs of the tree in depth first order
+	var ids func(tcs []comments.TreeComment) []uint32
+	ids = func(tcs []comments.TreeComment) []uint32 {
+		r := make([]uint32, 0, len(tcs))
+		for _, v := range tcs {
+			r = append(r, v.Comment.CommentID)
+			r = append(r, ids(v.Children)...)
+		}
+		return r
+	}
+
+	var tests = []struct {
+		name        string
+		parentID    uint32
+		depth       uint32
+		childrenMax uint32
+		sortBy      comments.SortT
+		wantIDs     []uint32
+		wantReplies uint32
+	}{
+		{"full tree", 0, 5, 20, comments.SortInvalid,
+			[]uint32{1, 2, 4, 6, 3, 5}, 2},
+		{"depth limit", 0, 2, 20, comments.SortInvalid,
+			[]uint32{1, 2, 3, 5}, 2},
+		{"children limit", 0, 5, 1, comments.SortInvalid,
+			[]uint32{1, 2, 4, 6}, 2},
+		{"subtree", 2, 5, 20, comments.SortInvalid,
+			[]uint32{4, 6}, 1},
+		{"sorted by score", 1, 1, 20, comments.SortScore,
+			[]uint32{3, 2}, 2},
+		{"no replies", 6, 5, 20, comments.SortInvalid,
+			[]uint32{}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, replies := commentTree(cs, tc.sortBy, tc.parentID,
+				tc.depth, tc.childrenMax)
+			if got := ids(tree); !reflect.DeepEqual(got, tc.wantIDs) {
+				t.Fatalf("got ids %v, want %v", got, tc.wantIDs)
+			}
+			if replies != tc.wantReplies {
+				t.Fatalf("got replies %v, want %v", replies, tc.wantReplies)
+			}
+		})
+	}
+
+	// The replies count must include the replies that were not
+	// returned due to the depth limit.
+	tree, _ := commentTree(cs, comments.SortInvalid, 0, 1, 20)
+	if tree[0].Replies != 2 || len(tree[0].Children) != 0 {
+		t.Fatalf("got %v replies and %v children, want 2 and 0",
+			tree[0].Replies, len(tree[0].Children))
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/google/uuid"
+)
+
+const (
+	// Filename of the user indexes that are saved to the comments plugin
+	// data dir.
+	fnUserIndex = "{userid}-user-index.json"
+)
+
+// userIndex contains the IDs of all comments that a user has made. The
+// comments are indexed by record state and record token so that the record
+// index of each record can be retrieved when the comments are looked up.
+//
+// The creation timestamp of each comment is also indexed so that the comment
+// history of a user can be paginated without having to retrieve the comments.
+// Comments that were indexed before the timestamps were added to the index do
+// not have a timestamp until they are backfilled.
+type userIndex struct {
+	Unvetted map[string][]uint32 `json:"unvetted,omitempty"` // [token]commentIDs
+	Vetted   map[string][]uint32 `json:"vetted,omitempty"`   // [token]commentIDs
+
+	UnvettedCreatedAt map[string]map[uint32]int64 `json:"unvettedcreatedat,omitempty"` // [token][commentID]createdAt
+	VettedCreatedAt   map[string]map[uint32]int64 `json:"vettedcreatedat,omitempty"`   // [token][commentID]createdAt
+}
+
+// records returns the comment IDs of the user for the provided record state.
+func (u *userIndex) records(s backend.StateT) map[string][]uint32 {
+	switch s {
+	case backend.StateUnvetted:
+		if u.Unvetted == nil {
+			u.Unvetted = make(map[string][]uint32)
+		}
+		return u.Unvetted
+	case backend.StateVetted:
+		if u.Vetted == nil {
+			u.Vetted = make(map[string][]uint32)
+		}
+		return u.Vetted
+	}
+	return nil
+}
+
+// createdAt returns the comment creation timestamps of the user for the
+// provided record state.
+func (u *userIndex) createdAt(s backend.StateT) map[string]map[uint32]int64 {
+	switch s {
+	case backend.StateUnvetted:
+		if u.UnvettedCreatedAt == nil {
+			u.UnvettedCreatedAt = make(map[string]map[uint32]int64)
+		}
+		return u.UnvettedCreatedAt
+	case backend.StateVetted:
+		if u.VettedCreatedAt == nil {
+			u.VettedCreatedAt = make(map[string]map[uint32]int64)
+		}
+		return u.VettedCreatedAt
+	}
+	return nil
+}
+
+// add adds the provided comments to the index. The comments are provided as
+// a map of comment ID to comment creation timestamp. A creation timestamp of 0
+// indicates that the timestamp is not known. Comment IDs that are already
+// part of the index are ignored, but a missing creation timestamp of an
+// existing comment is added. The returned bool will be true if the index was
+// updated.
+func (u *userIndex) add(s backend.StateT, token string, cs map[uint32]int64) bool {
+	records := u.records(s)
+	if records == nil {
+		return false
+	}
+	ids := records[token]
+	exists := make(map[uint32]struct{}, len(ids))
+	for _, v := range ids {
+		exists[v] = struct{}{}
+	}
+	var idsUpdated, updated bool
+	for id, ts := range cs {
+		if _, ok := exists[id]; !ok {
+			exists[id] = struct{}{}
+			ids = append(ids, id)
+			idsUpdated = true
+		}
+		if ts == 0 {
+			continue
+		}
+		created := u.createdAt(s)
+		if created[token] == nil {
+			created[token] = make(map[uint32]int64)
+		}
+		if created[token][id] != ts {
+			created[token][id] = ts
+			updated = true
+		}
+	}
+	if idsUpdated {
+		sort.Slice(ids, func(i, j int) bool {
+			return ids[i] < ids[j]
+		})
+		records[token] = ids
+	}
+	return idsUpdated || updated
+}
+
+// userIndexPath returns the file path for a cached user index. The user ID
+// must be a valid UUID. This prevents the user ID from being used to escape
+// the plugin data dir.
+func (p *commentsPlugin) userIndexPath(userID string) (string, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user id: %v", err)
+	}
+	fn := strings.Replace(fnUserIndex, "{userid}", uid.String(), 1)
+	return filepath.Join(p.dataDir, fn), nil
+}
+
+// readUserIndex reads the user index at the provided file path. If the file
+// does not exist, a new userIndex will be returned.
+func readUserIndex(fp string) (*userIndex, error) {
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		var e *os.PathError
+		if errors.As(err, &e) && !os.IsExist(err) {
+			// File does't exist. Return a new userIndex instead.
+			return &userIndex{}, nil
+		}
+		return nil, err
+	}
+
+	var uidx userIndex
+	err = json.Unmarshal(b, &uidx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uidx, nil
+}
+
+// userIndex returns the cached userIndex for the provided user. If a cached
+// userIndex does not exist, a new one will be returned.
+//
+// This function must be called WITHOUT the read lock held.
+func (p *commentsPlugin) userIndex(userID string) (*userIndex, error) {
+	fp, err := p.userIndexPath(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	return readUserIndex(fp)
+}
+
+// _userIndexAdd adds the provided comments to the cached user index. The
+// comments are provided as a map of comment ID to comment creation timestamp.
+// The user index is shared by all records, so the read, update, and save are
+// all done while holding the write lock.
+//
+// This function must be called WITHOUT the read/write lock held.
+func (p *commentsPlugin) _userIndexAdd(userID string, s backend.StateT, token []byte, cs map[uint32]int64) error {
+	fp, err := p.userIndexPath(userID)
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	uidx, err := readUserIndex(fp)
+	if err != nil {
+		return err
+	}
+	if !uidx.add(s, hex.EncodeToString(token), cs) {
+		// Nothing to update
+		return nil
+	}
+	b, err := json.Marshal(uidx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fp, b, 0664)
+}
+
+// userIndexAdd is a wrapper around the _userIndexAdd method that allows us to
+// decide how update errors should be handled. For now we just panic. If an
+// error occurs the cache is no longer coherent and the only way to fix it is
+// to rebuild it.
+func (p *commentsPlugin) userIndexAdd(userID string, s backend.StateT, token []byte, cs map[uint32]int64) {
+	err := p._userIndexAdd(userID, s, token, cs)
+	if err != nil {
+		panic(err)
+	}
+}
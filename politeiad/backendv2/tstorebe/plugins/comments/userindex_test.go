@@ -0,0 +1,55 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"reflect"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+func TestUserIndexAdd(t *testing.T) {
+	var uidx userIndex
+
+	// Add comments to a record
+	if !uidx.add(backend.StateVetted, "token1",
+		map[uint32]int64{3: 300, 1: 0}) {
+		t.Fatalf("add: got false, want true")
+	}
+	// Add a duplicate and a new comment
+	if !uidx.add(backend.StateVetted, "token1",
+		map[uint32]int64{1: 0, 2: 200}) {
+		t.Fatalf("add: got false, want true")
+	}
+	// Add only duplicates
+	if uidx.add(backend.StateVetted, "token1", map[uint32]int64{3: 300}) {
+		t.Fatalf("add duplicate: got true, want false")
+	}
+	// Add a missing creation timestamp
+	if !uidx.add(backend.StateVetted, "token1", map[uint32]int64{1: 100}) {
+		t.Fatalf("add timestamp: got false, want true")
+	}
+	// Invalid state
+	if uidx.add(backend.StateInvalid, "token1", map[uint32]int64{4: 400}) {
+		t.Fatalf("add invalid state: got true, want false")
+	}
+
+	want := map[string][]uint32{"token1": {1, 2, 3}}
+	if !reflect.DeepEqual(uidx.Vetted, want) {
+		t.Fatalf("vetted: got %v, want %v", uidx.Vetted, want)
+	}
+	wantCreated := map[string]map[uint32]int64{
+		"token1": {1: 100, 2: 200, 3: 300},
+	}
+	if !reflect.DeepEqual(uidx.VettedCreatedAt, wantCreated) {
+		t.Fatalf("vetted created: got %v, want %v",
+			uidx.VettedCreatedAt, wantCreated)
+	}
+	if len(uidx.Unvetted) != 0 || len(uidx.UnvettedCreatedAt) != 0 {
+		t.Fatalf("unvetted: got %v %v, want empty",
+			uidx.Unvetted, uidx.UnvettedCreatedAt)
+	}
+}
@@ -7,6 +7,7 @@ package dcrdata
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -16,55 +17,21 @@ import (
 	jsonrpc "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	types "github.com/decred/dcrdata/v6/api/types"
 	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/politeiawww/wsdcrdata"
 	"github.com/decred/politeia/util"
 )
 
-// cmdBestBlock returns the best block. If the dcrdata websocket has been
-// disconnected the best block will be fetched from the dcrdata HTTP API. If
-// dcrdata cannot be reached then the most recent cached best block will be
-// returned along with a status of StatusDisconnected. It is the callers
-// responsibility to determine if the stale best block should be used.
+// cmdBestBlock returns the best block. The cached best block is returned if it
+// is current. Otherwise, the best block is fetched from the dcrdata HTTP API
+// and cached. If dcrdata cannot be reached then the most recent cached best
+// block will be returned along with a status of StatusDisconnected. It is the
+// callers responsibility to determine if the stale best block should be used.
 func (p *dcrdataPlugin) cmdBestBlock(payload string) (string, error) {
 	// Payload is empty. Nothing to decode.
 
-	// Get the cached best block
-	bb := p.bestBlockGet()
-	var (
-		fetch  bool
-		stale  uint32
-		status = dcrdata.StatusConnected
-	)
-	switch {
-	case bb == 0:
-		// No cached best block means that the best block has not been
-		// populated by the websocket yet. Fetch is manually.
-		fetch = true
-	case p.bestBlockIsStale():
-		// The cached best block has been populated by the websocket, but
-		// the websocket is currently disconnected and the cached value
-		// is stale. Try to fetch the best block manually and only use
-		// the stale value if manually fetching it fails.
-		fetch = true
-		stale = bb
-	}
-
-	// Fetch the best block manually if required
-	if fetch {
-		block, err := p.bestBlockHTTP()
-		switch {
-		case err == nil:
-			// We got the best block. Use it.
-			bb = block.Height
-		case stale != 0:
-			// Unable to fetch the best block manually. Use the stale
-			// value and mark the connection status as disconnected.
-			bb = stale
-			status = dcrdata.StatusDisconnected
-		default:
-			// Unable to fetch the best block manually and there is no
-			// stale cached value to return.
-			return "", fmt.Errorf("bestBlockHTTP: %v", err)
-		}
+	bb, status, err := p.bestBlockCached()
+	if err != nil {
+		return "", err
 	}
 
 	// Prepare reply
@@ -80,6 +47,46 @@ func (p *dcrdataPlugin) cmdBestBlock(payload string) (string, error) {
 	return string(reply), nil
 }
 
+// bestBlockCached returns the best block and the dcrdata connection status.
+// The best block is only fetched from the dcrdata HTTP API when the cached
+// best block is not current.
+func (p *dcrdataPlugin) bestBlockCached() (uint32, dcrdata.StatusT, error) {
+	// Use the cached best block if it's current
+	bb, ok := p.bestBlockCurrent()
+	if ok {
+		return bb, dcrdata.StatusConnected, nil
+	}
+
+	// Only allow one HTTP request at a time. The cached best block
+	// is checked again once the lock is held since it may have been
+	// updated by a concurrent request.
+	p.bestBlockFetch.Lock()
+	defer p.bestBlockFetch.Unlock()
+
+	bb, ok = p.bestBlockCurrent()
+	if ok {
+		return bb, dcrdata.StatusConnected, nil
+	}
+
+	// Fetch the best block manually
+	block, err := p.bestBlockHTTP()
+	switch {
+	case err == nil:
+		// We got the best block. Cache it and use it.
+		p.bestBlockSet(block.Height)
+		return block.Height, dcrdata.StatusConnected, nil
+	case bb != 0:
+		// Unable to fetch the best block manually. Use the stale
+		// value and mark the connection status as disconnected.
+		log.Debugf("bestBlockHTTP: %v", err)
+		return bb, dcrdata.StatusDisconnected, nil
+	default:
+		// Unable to fetch the best block manually and there is no
+		// stale cached value to return.
+		return 0, dcrdata.StatusInvalid, fmt.Errorf("bestBlockHTTP: %v", err)
+	}
+}
+
 // cmdBlockDetails retrieves the block details for the provided block height.
 func (p *dcrdataPlugin) cmdBlockDetails(payload string) (string, error) {
 	// Decode payload
@@ -163,13 +170,86 @@ func (p *dcrdataPlugin) cmdTxsTrimmed(payload string) (string, error) {
 	return string(reply), nil
 }
 
+// cmdHealth returns the health state of the dcrdata hosts.
+func (p *dcrdataPlugin) cmdHealth(payload string) (string, error) {
+	// Payload is empty. Nothing to decode.
+
+	// The websocket host is only active while the websocket is
+	// connected.
+	ws := p.wsHosts.health()
+	if p.ws == nil || p.ws.Status() != wsdcrdata.StatusOpen {
+		for i := range ws {
+			ws[i].Active = false
+		}
+	}
+
+	// Prepare reply
+	hr := dcrdata.HealthReply{
+		HTTP: p.httpHosts.health(),
+		WS:   ws,
+	}
+	reply, err := json.Marshal(hr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// statusError is returned when dcrdata responds with a non 200 http status
+// code.
+type statusError struct {
+	code int
+	msg  string
+}
+
+// Error satisfies the error interface.
+func (e statusError) Error() string {
+	return e.msg
+}
+
 // makeReq makes a dcrdata http request to the method and route provided,
 // serializing the provided object as the request body, and returning a byte
 // slice of the response body. An error is returned if dcrdata responds with
 // anything other than a 200 http status code.
+//
+// The request is sent to the dcrdata hosts in order of preference. The
+// request fails over to the next host when a host cannot be reached, responds
+// with a server error, or is rate limiting requests. Other client errors are
+// returned without failing over since the request would fail on any host.
 func (p *dcrdataPlugin) makeReq(method string, route string, headers map[string]string, v interface{}) ([]byte, error) {
+	var err error
+	for _, host := range p.httpHosts.candidates() {
+		var resBody []byte
+		resBody, err = p.makeHostReq(host, method, route, headers, v)
+		var se statusError
+		switch {
+		case err == nil:
+			p.httpHosts.success(host)
+			return resBody, nil
+		case errors.As(err, &se) && !hostFailed(se.code):
+			// The host is healthy, the request is not
+			p.httpHosts.success(host)
+			return nil, err
+		}
+		p.httpHosts.failure(host, err)
+	}
+	return nil, err
+}
+
+// hostFailed returns whether the provided http status code indicates that
+// the dcrdata host failed to handle the request, in which case the request
+// should be sent to a different host.
+func hostFailed(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError ||
+		statusCode == http.StatusTooManyRequests
+}
+
+// makeHostReq makes a dcrdata http request to the provided host. See makeReq
+// for more details.
+func (p *dcrdataPlugin) makeHostReq(host, method, route string, headers map[string]string, v interface{}) ([]byte, error) {
 	var (
-		url     = p.hostHTTP + route
+		url     = host + route
 		reqBody []byte
 		err     error
 	)
@@ -202,11 +282,17 @@ func (p *dcrdataPlugin) makeReq(method string, route string, headers map[string]
 	if r.StatusCode != http.StatusOK {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			return nil, fmt.Errorf("%v %v %v %v",
-				r.StatusCode, method, url, err)
+			return nil, statusError{
+				code: r.StatusCode,
+				msg: fmt.Sprintf("%v %v %v %v",
+					r.StatusCode, method, url, err),
+			}
+		}
+		return nil, statusError{
+			code: r.StatusCode,
+			msg: fmt.Sprintf("%v %v %v %s",
+				r.StatusCode, method, url, body),
 		}
-		return nil, fmt.Errorf("%v %v %v %s",
-			r.StatusCode, method, url, body)
 	}
 
 	return util.RespBody(r), nil
@@ -5,9 +5,12 @@
 package dcrdata
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	exptypes "github.com/decred/dcrdata/v6/explorer/types"
@@ -31,6 +34,20 @@ const (
 
 	// Header values
 	contentTypeJSON = "application/json; charset=utf-8"
+
+	// httpTimeout is the timeout of a dcrdata http request, including
+	// any retries.
+	httpTimeout = time.Minute
+
+	// httpRetries is the number of times that a failed dcrdata http
+	// request is retried. All dcrdata requests are reads, which makes
+	// them safe to retry.
+	httpRetries = 2
+
+	// backfillMax is the maximum number of missed blocks that new block
+	// events are replayed for after the websocket reconnects. Only the
+	// most recent blocks are replayed if more blocks were missed.
+	backfillMax = 288 // ~1 day of blocks
 )
 
 var (
@@ -45,20 +62,37 @@ var (
 type dcrdataPlugin struct {
 	sync.Mutex
 	activeNetParams *chaincfg.Params
+	tstore          plugins.TstoreClient
 	client          *http.Client
 	ws              *wsdcrdata.Client
 
-	// Plugin settings
-	hostHTTP string // dcrdata HTTP host
-	hostWS   string // dcrdata websocket host
+	// httpHosts and wsHosts track the health of the dcrdata HTTP and
+	// websocket hosts. The hosts are configured using the plugin
+	// settings and are listed in order of preference.
+	httpHosts *hostPool
+	wsHosts   *hostPool
 
 	// bestBlock is the cached best block height. This field is kept up
-	// to date by the websocket connection. If the websocket connection
-	// drops, the best block is marked as stale and is not marked as
-	// current again until the connection has been re-established and
-	// a new best block message is received.
-	bestBlock      uint32
-	bestBlockStale bool
+	// to date by the websocket connection and by the best block HTTP
+	// requests. The cached best block is current for bestBlockTTL after
+	// it was last updated. If the websocket connection drops, the best
+	// block is marked as stale so that it is fetched from the HTTP API
+	// until the connection has been re-established and a new block
+	// message is received.
+	bestBlock        uint32
+	bestBlockUpdated time.Time
+	bestBlockTTL     time.Duration
+
+	// bestBlockFetch is held while the best block is being fetched from
+	// the HTTP API so that concurrent best block requests that find the
+	// cached best block expired only result in a single HTTP request.
+	bestBlockFetch sync.Mutex
+
+	// eventHeight is the block height of the most recent new block
+	// event. It is used to determine which blocks were missed while
+	// the websocket was disconnected. This field is only accessed by
+	// the websocket monitor and does not require the mutex.
+	eventHeight uint32
 }
 
 // bestBlockGet returns the cached best block.
@@ -69,30 +103,93 @@ func (p *dcrdataPlugin) bestBlockGet() uint32 {
 	return p.bestBlock
 }
 
+// bestBlockCurrent returns the cached best block and whether it is current.
+// The cached best block is current if it has been updated within the best
+// block TTL and has not been marked as stale.
+func (p *dcrdataPlugin) bestBlockCurrent() (uint32, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.bestBlock == 0 || p.bestBlockUpdated.IsZero() {
+		return p.bestBlock, false
+	}
+	return p.bestBlock, time.Since(p.bestBlockUpdated) < p.bestBlockTTL
+}
+
 // bestBlockSet sets the cached best block to a new value.
 func (p *dcrdataPlugin) bestBlockSet(bb uint32) {
 	p.Lock()
 	defer p.Unlock()
 
 	p.bestBlock = bb
-	p.bestBlockStale = false
+	p.bestBlockUpdated = time.Now()
 }
 
-// bestBlockSetStale marks the cached best block as stale.
+// bestBlockSetStale marks the cached best block as stale. The stale value is
+// kept so that it can be returned if dcrdata cannot be reached.
 func (p *dcrdataPlugin) bestBlockSetStale() {
 	p.Lock()
 	defer p.Unlock()
 
-	p.bestBlockStale = true
+	p.bestBlockUpdated = time.Time{}
 }
 
-// bestBlockIsStale returns whether the cached best block has been marked as
-// being stale.
-func (p *dcrdataPlugin) bestBlockIsStale() bool {
-	p.Lock()
-	defer p.Unlock()
+// newBlock updates the cached best block and emits a new block plugin event
+// for the provided block height. Events are also emitted for any blocks that
+// were missed since the previous event so that the plugins are notified of
+// every block, in order.
+//
+// This function must only be called by the websocket monitor.
+func (p *dcrdataPlugin) newBlock(height uint32) {
+	p.bestBlockSet(height)
+
+	start := height
+	switch {
+	case p.eventHeight == 0:
+		// This is the first block since startup. There is
+		// nothing to backfill.
+	case height <= p.eventHeight:
+		// An event has already been emitted for this block
+		return
+	default:
+		start = p.eventHeight + 1
+	}
+	if height-start >= backfillMax {
+		log.Warnf("Missed %v blocks; only replaying the most recent %v",
+			height-start, backfillMax)
+		start = height - backfillMax + 1
+	}
+	if start < height {
+		log.Infof("Backfilling blocks %v to %v", start, height-1)
+	}
 
-	return p.bestBlockStale
+	for h := start; h <= height; h++ {
+		b, err := json.Marshal(dcrdata.NewBlock{
+			Height: h,
+		})
+		if err != nil {
+			log.Errorf("newBlock %v: %v", h, err)
+			return
+		}
+		p.tstore.PluginEvent(nil, dcrdata.EventNewBlock, string(b))
+		p.eventHeight = h
+	}
+}
+
+// backfill fetches the best block from the dcrdata HTTP API and emits the
+// new block events for any blocks that were missed while the websocket was
+// disconnected.
+//
+// This function must only be called by the websocket monitor.
+func (p *dcrdataPlugin) backfill() {
+	block, err := p.bestBlockHTTP()
+	if err != nil {
+		// The missed blocks will be backfilled once the next
+		// new block message is received.
+		log.Errorf("backfill bestBlockHTTP: %v", err)
+		return
+	}
+	p.newBlock(block.Height)
 }
 
 func (p *dcrdataPlugin) websocketMonitor() {
@@ -113,6 +210,8 @@ func (p *dcrdataPlugin) websocketMonitor() {
 				return
 			}
 			log.Infof("Dcrdata websocket connection unexpectedly dropped")
+			p.wsHosts.failure(p.ws.URL(),
+				fmt.Errorf("websocket connection dropped"))
 			goto reconnect
 		}
 
@@ -121,8 +220,8 @@ func (p *dcrdataPlugin) websocketMonitor() {
 		case *exptypes.WebsocketBlock:
 			log.Debugf("WebsocketBlock: %v", m.Block.Height)
 
-			// Update cached best block
-			p.bestBlockSet(uint32(m.Block.Height))
+			// Update cached best block and notify the plugins
+			p.newBlock(uint32(m.Block.Height))
 
 		case *pstypes.HangUp:
 			log.Infof("Dcrdata websocket has hung up. Will reconnect.")
@@ -150,6 +249,10 @@ func (p *dcrdataPlugin) websocketMonitor() {
 		receiver = p.ws.Receive()
 
 		log.Infof("Dcrdata websocket successfully reconnected")
+
+		// Replay the new block events for any blocks that were
+		// missed while the websocket was disconnected.
+		p.backfill()
 	}
 }
 
@@ -206,6 +309,8 @@ func (p *dcrdataPlugin) Cmd(token []byte, cmd, payload string) (string, error) {
 		return p.cmdTicketPool(payload)
 	case dcrdata.CmdTxsTrimmed:
 		return p.cmdTxsTrimmed(payload)
+	case dcrdata.CmdHealth:
+		return p.cmdHealth(payload)
 	}
 
 	return "", backend.ErrPluginCmdInvalid
@@ -239,22 +344,24 @@ func (p *dcrdataPlugin) Settings() []backend.PluginSetting {
 	return nil
 }
 
-func New(settings []backend.PluginSetting, activeNetParams *chaincfg.Params) (*dcrdataPlugin, error) {
+// New returns a new dcrdataPlugin.
+func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, activeNetParams *chaincfg.Params) (*dcrdataPlugin, error) {
 	// Plugin setting
 	var (
-		hostHTTP string
-		hostWS   string
+		hostsHTTP    []string
+		hostsWS      []string
+		bestBlockTTL = dcrdata.SettingBestBlockTTL
 	)
 
 	// Set plugin settings to defaults. These will be overwritten if
 	// the setting was specified by the user.
 	switch activeNetParams.Name {
 	case chaincfg.MainNetParams().Name:
-		hostHTTP = dcrdata.SettingHostHTTPMainNet
-		hostWS = dcrdata.SettingHostWSMainNet
+		hostsHTTP = []string{dcrdata.SettingHostHTTPMainNet}
+		hostsWS = []string{dcrdata.SettingHostWSMainNet}
 	case chaincfg.TestNet3Params().Name:
-		hostHTTP = dcrdata.SettingHostHTTPTestNet
-		hostWS = dcrdata.SettingHostWSTestNet
+		hostsHTTP = []string{dcrdata.SettingHostHTTPTestNet}
+		hostsWS = []string{dcrdata.SettingHostWSTestNet}
 	default:
 		return nil, fmt.Errorf("unknown active net: %v", activeNetParams.Name)
 	}
@@ -263,14 +370,38 @@ func New(settings []backend.PluginSetting, activeNetParams *chaincfg.Params) (*d
 	for _, v := range settings {
 		switch v.Key {
 		case dcrdata.SettingKeyHostHTTP:
-			hostHTTP = v.Value
+			h, err := parseHosts(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			hostsHTTP = h
 			log.Infof("Plugin setting updated: dcrdata %v %v",
-				dcrdata.SettingKeyHostHTTP, hostHTTP)
+				dcrdata.SettingKeyHostHTTP, hostsHTTP)
 
 		case dcrdata.SettingKeyHostWS:
-			hostWS = v.Value
+			h, err := parseHosts(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			hostsWS = h
+			log.Infof("Plugin setting updated: dcrdata %v %v",
+				dcrdata.SettingKeyHostWS, hostsWS)
+
+		case dcrdata.SettingKeyBestBlockTTL:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, fmt.Errorf("invalid plugin setting %v '%v': "+
+					"must be greater than zero", v.Key, v.Value)
+			}
+			bestBlockTTL = uint32(u)
 			log.Infof("Plugin setting updated: dcrdata %v %v",
-				dcrdata.SettingKeyHostWS, hostWS)
+				dcrdata.SettingKeyBestBlockTTL, bestBlockTTL)
 
 		default:
 			return nil, fmt.Errorf("invalid plugin setting '%v'", v.Key)
@@ -278,25 +409,43 @@ func New(settings []backend.PluginSetting, activeNetParams *chaincfg.Params) (*d
 	}
 
 	// Setup http client
-	log.Infof("Dcrdata HTTP host: %v", hostHTTP)
-	client, err := util.NewHTTPClient(false, "")
+	log.Infof("Dcrdata HTTP hosts: %v", hostsHTTP)
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
+		Timeout: httpTimeout,
+		Retries: httpRetries,
+		OnAttempt: func(a util.HTTPAttempt) {
+			if a.Retry {
+				log.Debugf("dcrdata request failed, retrying: %v", a)
+			}
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Setup websocket client
-	ws, err := wsdcrdata.New(hostWS)
+	// Setup websocket client. The websocket connection attempts are
+	// recorded in the websocket host health.
+	wsHosts := newHostPool(hostsWS)
+	ws, err := wsdcrdata.NewWithHosts(hostsWS, func(host string, err error) {
+		if err != nil {
+			wsHosts.failure(host, err)
+			return
+		}
+		wsHosts.success(host)
+	})
 	if err != nil {
 		// Continue even if a websocket connection was not able to be
 		// made. Reconnection attempts will be made in the plugin setup.
-		log.Errorf("wsdcrdata New: %v", err)
+		log.Errorf("wsdcrdata NewWithHosts: %v", err)
 	}
 
 	return &dcrdataPlugin{
 		activeNetParams: activeNetParams,
+		tstore:          tstore,
 		client:          client,
 		ws:              ws,
-		hostHTTP:        hostHTTP,
-		hostWS:          hostWS,
+		httpHosts:       newHostPool(hostsHTTP),
+		wsHosts:         wsHosts,
+		bestBlockTTL:    time.Duration(bestBlockTTL) * time.Second,
 	}, nil
 }
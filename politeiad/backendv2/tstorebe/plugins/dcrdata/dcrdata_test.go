@@ -0,0 +1,162 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dcrdata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/util"
+)
+
+// testTstore records the new block events that are emitted by the plugin.
+type testTstore struct {
+	plugins.TstoreClient
+	heights []uint32
+}
+
+// PluginEvent satisfies the plugins TstoreClient interface.
+func (t *testTstore) PluginEvent(token []byte, event, payload string) {
+	if event != dcrdata.EventNewBlock {
+		return
+	}
+	var nb dcrdata.NewBlock
+	err := json.Unmarshal([]byte(payload), &nb)
+	if err != nil {
+		panic(err)
+	}
+	t.heights = append(t.heights, nb.Height)
+}
+
+func TestNewBestBlockTTLInvalid(t *testing.T) {
+	// Values that do not fit in a uint32 must be rejected instead
+	// of wrapping around.
+	for _, v := range []string{"0", "4294967296", "-1", "abc"} {
+		_, err := New(&testTstore{}, []backend.PluginSetting{
+			{
+				Key:   dcrdata.SettingKeyBestBlockTTL,
+				Value: v,
+			},
+		}, chaincfg.TestNet3Params())
+		if err == nil {
+			t.Errorf("%v: got nil error, want error", v)
+		}
+	}
+}
+
+func TestNewBlock(t *testing.T) {
+	ts := &testTstore{}
+	p := &dcrdataPlugin{
+		tstore: ts,
+	}
+
+	var tests = []struct {
+		name      string
+		height    uint32
+		wantCount int    // Number of events
+		wantFirst uint32 // Height of the first event
+	}{
+		{"first block", 100, 1, 100},
+		{"next block", 101, 1, 101},
+		{"duplicate block", 101, 0, 0},
+		{"missed blocks", 104, 3, 102},
+		{"too many missed blocks", 104 + backfillMax + 5,
+			backfillMax, 110},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			ts.heights = nil
+			p.newBlock(v.height)
+
+			// Verify the events were emitted in order
+			if len(ts.heights) != v.wantCount {
+				t.Fatalf("got %v events, want %v",
+					len(ts.heights), v.wantCount)
+			}
+			for i, h := range ts.heights {
+				if h != v.wantFirst+uint32(i) {
+					t.Fatalf("got heights %v, want %v to %v", ts.heights,
+						v.wantFirst, v.height)
+				}
+			}
+			if p.bestBlockGet() != v.height {
+				t.Errorf("best block: got %v, want %v",
+					p.bestBlockGet(), v.height)
+			}
+		})
+	}
+}
+
+func TestBestBlockCached(t *testing.T) {
+	// Setup a dcrdata host that counts the best block requests
+	var (
+		requests int
+		height   uint32 = 200
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			util.RespondWithJSON(w, http.StatusOK,
+				map[string]uint32{"height": height})
+		}))
+	defer srv.Close()
+
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &dcrdataPlugin{
+		client:       client,
+		httpHosts:    newHostPool([]string{srv.URL}),
+		bestBlockTTL: time.Minute,
+	}
+
+	// verify checks the best block result and the number of HTTP
+	// requests that have been made.
+	verify := func(wantHeight uint32, wantStatus dcrdata.StatusT, wantRequests int) {
+		t.Helper()
+		bb, status, err := p.bestBlockCached()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bb != wantHeight || status != wantStatus {
+			t.Fatalf("got %v %v, want %v %v", bb, status,
+				wantHeight, wantStatus)
+		}
+		if requests != wantRequests {
+			t.Fatalf("got %v requests, want %v", requests, wantRequests)
+		}
+	}
+
+	// No cached best block. The best block is fetched and cached.
+	verify(200, dcrdata.StatusConnected, 1)
+	verify(200, dcrdata.StatusConnected, 1)
+
+	// A websocket new block message replaces the cached best block
+	p.bestBlockSet(201)
+	verify(201, dcrdata.StatusConnected, 1)
+
+	// The best block is fetched once the cached best block expires
+	p.bestBlockUpdated = time.Now().Add(-time.Minute)
+	height = 202
+	verify(202, dcrdata.StatusConnected, 2)
+
+	// The best block is fetched when the cached best block is stale
+	p.bestBlockSetStale()
+	height = 203
+	verify(203, dcrdata.StatusConnected, 3)
+
+	// The stale best block is returned when dcrdata cannot be reached
+	p.bestBlockSetStale()
+	srv.Close()
+	verify(203, dcrdata.StatusDisconnected, 3)
+}
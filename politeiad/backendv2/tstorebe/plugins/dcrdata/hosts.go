@@ -0,0 +1,185 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dcrdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+)
+
+const (
+	// hostRetryInterval is the amount of time that an unhealthy host is
+	// skipped for before requests are attempted against it again. This
+	// allows requests to fail back to a preferred host once it has
+	// recovered.
+	hostRetryInterval = time.Minute
+)
+
+// hostState contains the health state of a dcrdata host.
+type hostState struct {
+	host        string
+	healthy     bool
+	failures    uint32 // Consecutive failures
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastErr     string
+}
+
+// hostPool tracks the health of an ordered list of dcrdata hosts. The hosts
+// are listed in order of preference.
+type hostPool struct {
+	sync.Mutex
+	hosts  []hostState
+	active string // Host that was most recently used successfully
+	now    func() time.Time
+}
+
+// newHostPool returns a new hostPool. All hosts start out as healthy.
+func newHostPool(hosts []string) *hostPool {
+	hs := make([]hostState, 0, len(hosts))
+	for _, v := range hosts {
+		hs = append(hs, hostState{
+			host:    v,
+			healthy: true,
+		})
+	}
+	return &hostPool{
+		hosts: hs,
+		now:   time.Now,
+	}
+}
+
+// candidates returns the hosts in the order that they should be tried. The
+// healthy hosts are returned first in order of preference. Unhealthy hosts
+// that have not been retried for the hostRetryInterval are treated as healthy
+// so that they have a chance to recover. The remaining unhealthy hosts are
+// returned last so that they are still tried when all other hosts fail.
+func (h *hostPool) candidates() []string {
+	h.Lock()
+	defer h.Unlock()
+
+	var (
+		now       = h.now()
+		preferred = make([]string, 0, len(h.hosts))
+		fallback  = make([]string, 0, len(h.hosts))
+	)
+	for _, v := range h.hosts {
+		if v.healthy || now.Sub(v.lastFailure) >= hostRetryInterval {
+			preferred = append(preferred, v.host)
+			continue
+		}
+		fallback = append(fallback, v.host)
+	}
+
+	return append(preferred, fallback...)
+}
+
+// success marks the provided host as healthy and active.
+func (h *hostPool) success(host string) {
+	h.Lock()
+	defer h.Unlock()
+
+	for i, v := range h.hosts {
+		if v.host != host {
+			continue
+		}
+		if !v.healthy {
+			log.Infof("Dcrdata host %v is healthy again", host)
+		}
+		h.hosts[i].healthy = true
+		h.hosts[i].failures = 0
+		h.hosts[i].lastSuccess = h.now()
+		h.active = host
+		return
+	}
+}
+
+// failure marks the provided host as unhealthy.
+func (h *hostPool) failure(host string, err error) {
+	h.Lock()
+	defer h.Unlock()
+
+	for i, v := range h.hosts {
+		if v.host != host {
+			continue
+		}
+		if v.healthy {
+			log.Warnf("Dcrdata host %v is unhealthy: %v", host, err)
+		}
+		h.hosts[i].healthy = false
+		h.hosts[i].failures++
+		h.hosts[i].lastFailure = h.now()
+		h.hosts[i].lastErr = err.Error()
+		return
+	}
+}
+
+// health returns the health state of all hosts in order of preference.
+func (h *hostPool) health() []dcrdata.HostHealth {
+	h.Lock()
+	defer h.Unlock()
+
+	hh := make([]dcrdata.HostHealth, 0, len(h.hosts))
+	for _, v := range h.hosts {
+		hh = append(hh, dcrdata.HostHealth{
+			Host:        v.host,
+			Active:      v.host == h.active,
+			Healthy:     v.healthy,
+			Failures:    v.failures,
+			LastSuccess: unixTime(v.lastSuccess),
+			LastFailure: unixTime(v.lastFailure),
+			LastError:   v.lastErr,
+		})
+	}
+
+	return hh
+}
+
+// unixTime returns the UNIX timestamp of the provided time. Zero is returned
+// for the zero time.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// parseHosts parses a dcrdata host plugin setting value. The value can either
+// be a single host or a JSON encoded []string of hosts.
+func parseHosts(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") {
+		if value == "" {
+			return nil, fmt.Errorf("no host provided")
+		}
+		return []string{value}, nil
+	}
+
+	var hosts []string
+	err := json.Unmarshal([]byte(value), &hosts)
+	if err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no hosts provided")
+	}
+	seen := make(map[string]struct{}, len(hosts))
+	for _, v := range hosts {
+		if v == "" {
+			return nil, fmt.Errorf("empty host")
+		}
+		if _, ok := seen[v]; ok {
+			return nil, fmt.Errorf("duplicate host %v", v)
+		}
+		seen[v] = struct{}{}
+	}
+
+	return hosts, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package dcrdata
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/politeia/util"
+)
+
+func TestHostPoolCandidates(t *testing.T) {
+	now := time.Unix(1000, 0)
+	h := newHostPool([]string{"a", "b", "c"})
+	h.now = func() time.Time { return now }
+
+	// All hosts are healthy
+	want := []string{"a", "b", "c"}
+	if got := h.candidates(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Unhealthy hosts are tried last
+	h.failure("a", errors.New("down"))
+	want = []string{"b", "c", "a"}
+	if got := h.candidates(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Unhealthy hosts are retried once the retry interval has passed
+	now = now.Add(hostRetryInterval)
+	want = []string{"a", "b", "c"}
+	if got := h.candidates(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// A successful request marks the host as healthy
+	h.success("a")
+	hh := h.health()
+	if !hh[0].Healthy || !hh[0].Active || hh[0].Failures != 0 {
+		t.Fatalf("got host health %+v, want healthy and active", hh[0])
+	}
+}
+
+func TestMakeReqFailover(t *testing.T) {
+	// Setup a failing primary and a healthy secondary dcrdata host
+	primary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != routeBestBlock {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			util.RespondWithJSON(w, http.StatusOK,
+				map[string]uint32{"height": 100})
+		}))
+	defer secondary.Close()
+
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &dcrdataPlugin{
+		client:    client,
+		httpHosts: newHostPool([]string{primary.URL, secondary.URL}),
+	}
+
+	// The request should fail over to the secondary host
+	bb, err := p.bestBlockHTTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bb.Height != 100 {
+		t.Fatalf("got height %v, want 100", bb.Height)
+	}
+	hh := p.httpHosts.health()
+	if hh[0].Healthy || hh[0].Failures != 1 {
+		t.Errorf("got primary health %+v, want unhealthy", hh[0])
+	}
+	if !hh[1].Healthy || !hh[1].Active {
+		t.Errorf("got secondary health %+v, want healthy and active", hh[1])
+	}
+
+	// Client errors are not failed over and do not mark the host as
+	// unhealthy.
+	_, err = p.blockDetails(1)
+	var se statusError
+	if !errors.As(err, &se) || se.code != http.StatusNotFound {
+		t.Fatalf("got error %v, want not found", err)
+	}
+	hh = p.httpHosts.health()
+	if !hh[1].Healthy {
+		t.Errorf("got secondary health %+v, want healthy", hh[1])
+	}
+}
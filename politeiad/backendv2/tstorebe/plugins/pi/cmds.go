@@ -10,8 +10,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -23,11 +25,18 @@ import (
 	"github.com/decred/politeia/util"
 )
 
+var (
+	// tokenPrefixRegexp matches hex encoded token prefixes. The length of
+	// the prefix is verified against the command token.
+	tokenPrefixRegexp = regexp.MustCompile("^[0-9a-f]+$")
+)
+
 const (
 	pluginID = pi.PluginID
 
 	// Blob entry data descriptors
-	dataDescriptorBillingStatus = pluginID + "-billingstatus-v1"
+	dataDescriptorBillingStatus    = pluginID + "-billingstatus-v1"
+	dataDescriptorCompletionReport = pluginID + "-completionreport-v1"
 )
 
 var (
@@ -83,20 +92,32 @@ func (p *piPlugin) cmdSetBillingStatus(token []byte, payload string) (string, er
 		}
 	}
 
+	// Verify completion report
+	var reportDigest string
+	if sbs.Report != nil {
+		err := p.completionReportVerify(sbs.Status, *sbs.Report)
+		if err != nil {
+			return "", err
+		}
+		reportDigest = sbs.Report.Digest
+	}
+
 	// Verify signature
-	msg := sbs.Token + strconv.FormatUint(uint64(sbs.Status), 10) + sbs.Reason
+	msg := sbs.Token + strconv.FormatUint(uint64(sbs.Status), 10) +
+		sbs.Reason + reportDigest
 	err = util.VerifySignature(sbs.Signature, sbs.PublicKey, msg)
 	if err != nil {
 		return "", convertSignatureError(err)
 	}
 
-	// Ensure reason is provided when status is set to closed.
-	if sbs.Status == pi.BillingStatusClosed && sbs.Reason == "" {
+	// Ensure reason is provided when the status requires one
+	_, reasonRequired := p.billingStatusReasonRequired[sbs.Status]
+	if reasonRequired && sbs.Reason == "" {
 		return "", backend.PluginError{
 			PluginID:  pi.PluginID,
 			ErrorCode: uint32(pi.ErrorCodeBillingStatusChangeNotAllowed),
-			ErrorContext: "must provide a reason when setting " +
-				"billing status to closed",
+			ErrorContext: fmt.Sprintf("must provide a reason when setting "+
+				"billing status to %v", pi.BillingStatuses[sbs.Status]),
 		}
 	}
 
@@ -165,22 +186,52 @@ func (p *piPlugin) cmdSetBillingStatus(token []byte, payload string) (string, er
 		}
 	}
 
+	// Cache the proposal status prior to saving the billing status
+	// change so that the resulting proposal status transition can be
+	// detected. Failing to do so should not prevent the billing status
+	// change.
+	_, err = p.getProposalStatus(token)
+	if err != nil {
+		log.Errorf("cmdSetBillingStatus: getProposalStatus %x: %v",
+			token, err)
+	}
+
+	// Save the completion report. This is done prior to saving the
+	// billing status change so that a completed billing status change
+	// that references a report will always have the report available.
+	if sbs.Report != nil {
+		err = p.completionReportSave(token, *sbs.Report)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// Save billing status change
 	receipt := p.identity.SignMessage([]byte(sbs.Signature))
 	bsc := pi.BillingStatusChange{
-		Token:     sbs.Token,
-		Status:    sbs.Status,
-		Reason:    sbs.Reason,
-		PublicKey: sbs.PublicKey,
-		Signature: sbs.Signature,
-		Timestamp: time.Now().Unix(),
-		Receipt:   hex.EncodeToString(receipt[:]),
+		Token:        sbs.Token,
+		Status:       sbs.Status,
+		Reason:       sbs.Reason,
+		ReportDigest: reportDigest,
+		PublicKey:    sbs.PublicKey,
+		Signature:    sbs.Signature,
+		Timestamp:    time.Now().Unix(),
+		Receipt:      hex.EncodeToString(receipt[:]),
 	}
 	err = p.billingStatusSave(token, bsc)
 	if err != nil {
 		return "", err
 	}
 
+	// Update the cached proposal status. This emits a proposal status
+	// change event. The billing status change has already been saved,
+	// so errors are logged instead of being returned.
+	_, err = p.getProposalStatus(token)
+	if err != nil {
+		log.Errorf("cmdSetBillingStatus: getProposalStatus %x: %v",
+			token, err)
+	}
+
 	// Prepare reply
 	sbsr := pi.SetBillingStatusReply{
 		Timestamp: bsc.Timestamp,
@@ -197,25 +248,58 @@ func (p *piPlugin) cmdSetBillingStatus(token []byte, payload string) (string, er
 // tokenMatches verifies that the command token (the token for the record that
 // this plugin command is being executed on) matches the payload token (the
 // token that the plugin command payload contains that is typically used in the
-// payload signature). The payload token must be the full length token.
+// payload signature). The payload token can either be the full length token
+// or a prefix of the command token. A token prefix must be at least as long as
+// the short token in order to unambiguously identify the record. Shorter
+// prefixes are rejected with an ErrorCodeTokenAmbiguous error.
 func tokenMatches(cmdToken []byte, payloadToken string) error {
+	// Verify full length tokens
 	pt, err := tokenDecode(payloadToken)
-	if err != nil {
+	if err == nil {
+		if !bytes.Equal(cmdToken, pt) {
+			return backend.PluginError{
+				PluginID:  pi.PluginID,
+				ErrorCode: uint32(pi.ErrorCodeTokenInvalid),
+				ErrorContext: fmt.Sprintf("payload token does not "+
+					"match command token: got %x, want %x",
+					pt, cmdToken),
+			}
+		}
+		return nil
+	}
+
+	// Verify token prefixes
+	ct := hex.EncodeToString(cmdToken)
+	if !tokenPrefixRegexp.MatchString(payloadToken) {
 		return backend.PluginError{
 			PluginID:     pi.PluginID,
 			ErrorCode:    uint32(pi.ErrorCodeTokenInvalid),
 			ErrorContext: util.TokenRegexp(),
 		}
 	}
-	if !bytes.Equal(cmdToken, pt) {
+	if !strings.HasPrefix(ct, payloadToken) {
 		return backend.PluginError{
 			PluginID:  pi.PluginID,
 			ErrorCode: uint32(pi.ErrorCodeTokenInvalid),
 			ErrorContext: fmt.Sprintf("payload token does not "+
-				"match command token: got %x, want %x",
-				pt, cmdToken),
+				"match command token: got %v, want %v",
+				payloadToken, ct),
+		}
+	}
+	shortToken, err := util.ShortTokenString(ct)
+	if err != nil {
+		return err
+	}
+	if len(payloadToken) < len(shortToken) {
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeTokenAmbiguous),
+			ErrorContext: fmt.Sprintf("payload token prefix %v may "+
+				"match multiple records; it must be at least %v "+
+				"characters", payloadToken, len(shortToken)),
 		}
 	}
+
 	return nil
 }
 
@@ -262,6 +346,116 @@ func (p *piPlugin) cmdSummary(token []byte) (string, error) {
 	return string(reply), nil
 }
 
+// cmdIdentity returns the public key of the server identity that the pi
+// plugin uses to sign receipts.
+func (p *piPlugin) cmdIdentity() (string, error) {
+	ir := pi.IdentityReply{
+		PublicKey: p.identity.Public.String(),
+	}
+	reply, err := json.Marshal(ir)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdCompletionReport returns the completion report of a proposal. The
+// report is looked up using the report digest of the most recent completed
+// billing status change. Reports that are not referenced by a billing status
+// change, e.g. a report that was saved prior to a failed billing status change
+// save, are ignored.
+func (p *piPlugin) cmdCompletionReport(token []byte) (string, error) {
+	// Get the report digest of the most recent completed billing
+	// status change.
+	bscs, err := p.billingStatusChanges(token)
+	if err != nil {
+		return "", err
+	}
+	var digest string
+	for i := len(bscs) - 1; i >= 0; i-- {
+		if bscs[i].Status == pi.BillingStatusCompleted {
+			digest = bscs[i].ReportDigest
+			break
+		}
+	}
+
+	// Prepare reply
+	var crr pi.CompletionReportReply
+	if digest != "" {
+		// Get the completion reports
+		reports, err := p.completionReports(token)
+		if err != nil {
+			return "", err
+		}
+		crr.Report = completionReportFind(reports, digest)
+	}
+	reply, err := json.Marshal(crr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// completionReportVerify verifies that a completion report is valid and that
+// it is being attached to a billing status change that sets the billing
+// status to completed.
+func (p *piPlugin) completionReportVerify(status pi.BillingStatusT, r pi.CompletionReport) error {
+	if status != pi.BillingStatusCompleted {
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeCompletionReportInvalid),
+			ErrorContext: "a completion report can only be attached " +
+				"when setting the billing status to completed",
+		}
+	}
+	if r.Name == "" {
+		return backend.PluginError{
+			PluginID:     pi.PluginID,
+			ErrorCode:    uint32(pi.ErrorCodeCompletionReportInvalid),
+			ErrorContext: "report name not provided",
+		}
+	}
+	switch r.MIME {
+	case mimeTypeText, mimeTypeTextUTF8:
+		// Allowed; continue
+	default:
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeCompletionReportInvalid),
+			ErrorContext: fmt.Sprintf("invalid report mime type %v; "+
+				"must be %v", r.MIME, mimeTypeText),
+		}
+	}
+	payload, err := base64.StdEncoding.DecodeString(r.Payload)
+	if err != nil {
+		return backend.PluginError{
+			PluginID:     pi.PluginID,
+			ErrorCode:    uint32(pi.ErrorCodeCompletionReportInvalid),
+			ErrorContext: "report payload is not valid base64",
+		}
+	}
+	if len(payload) == 0 || len(payload) > int(p.textFileSizeMax) {
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeCompletionReportInvalid),
+			ErrorContext: fmt.Sprintf("report size must be between 1 "+
+				"and %v bytes; got %v", p.textFileSizeMax, len(payload)),
+		}
+	}
+	digest := hex.EncodeToString(util.Digest(payload))
+	if r.Digest != digest {
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeCompletionReportInvalid),
+			ErrorContext: fmt.Sprintf("report digest does not match "+
+				"payload: got %v, want %v", r.Digest, digest),
+		}
+	}
+	return nil
+}
+
 // proposalBillingStatus accepts proposal's vote status with the billing status
 // changes and returns the proposal's billing status.
 func proposalBillingStatus(vs ticketvote.VoteStatusT, bscs []pi.BillingStatusChange) pi.BillingStatusT {
@@ -348,6 +542,52 @@ func (p *piPlugin) billingStatusSave(token []byte, bsc pi.BillingStatusChange) e
 	return p.tstore.BlobSave(token, *be)
 }
 
+// completionReportSave saves a CompletionReport to the backend.
+func (p *piPlugin) completionReportSave(token []byte, r pi.CompletionReport) error {
+	// Prepare blob
+	be, err := completionReportEncode(r)
+	if err != nil {
+		return err
+	}
+
+	// Save blob
+	return p.tstore.BlobSave(token, *be)
+}
+
+// completionReports returns the completion reports of a proposal. The reports
+// are ordered from oldest to newest.
+func (p *piPlugin) completionReports(token []byte) ([]pi.CompletionReport, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.BlobsByDataDesc(token,
+		[]string{dataDescriptorCompletionReport})
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode blobs
+	reports := make([]pi.CompletionReport, 0, len(blobs))
+	for _, v := range blobs {
+		r, err := completionReportDecode(v)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *r)
+	}
+
+	return reports, nil
+}
+
+// completionReportFind returns the most recent completion report that matches
+// the provided digest. Nil is returned if a match is not found.
+func completionReportFind(reports []pi.CompletionReport, digest string) *pi.CompletionReport {
+	for i := len(reports) - 1; i >= 0; i-- {
+		if reports[i].Digest == digest {
+			return &reports[i]
+		}
+	}
+	return nil
+}
+
 // billingStatusChanges returns the billing status changes of a proposal.
 func (p *piPlugin) billingStatusChanges(token []byte) ([]pi.BillingStatusChange, error) {
 	// Retrieve blobs
@@ -432,3 +672,60 @@ func billingStatusDecode(be store.BlobEntry) (*pi.BillingStatusChange, error) {
 
 	return &bsc, nil
 }
+
+// completionReportEncode encodes a CompletionReport into a BlobEntry.
+func completionReportEncode(r pi.CompletionReport) (*store.BlobEntry, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorCompletionReport,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+// completionReportDecode decodes a BlobEntry into a CompletionReport.
+func completionReportDecode(be store.BlobEntry) (*pi.CompletionReport, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorCompletionReport {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorCompletionReport)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var r pi.CompletionReport
+	err = json.Unmarshal(b, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal CompletionReport: %v", err)
+	}
+
+	return &r, nil
+}
@@ -5,6 +5,7 @@
 package pi
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -14,6 +15,7 @@ import (
 	"github.com/decred/politeia/politeiad/api/v1/identity"
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/util"
 )
 
 func TestCmdBillingStatus(t *testing.T) {
@@ -43,6 +45,15 @@ func TestCmdBillingStatus(t *testing.T) {
 		signaturebWithClosed = fid.SignMessage([]byte(msgWithClosed))
 		signatureWithClosed  = hex.EncodeToString(signaturebWithClosed[:])
 
+		// Completion report
+		reportPayload = []byte("All milestones were delivered.")
+		report        = pi.CompletionReport{
+			Name:    "report.md",
+			MIME:    "text/plain; charset=utf-8",
+			Digest:  hex.EncodeToString(util.Digest(reportPayload)),
+			Payload: base64.StdEncoding.EncodeToString(reportPayload),
+		}
+
 		// signatureIsWrong is a valid hex encoded, ed25519 signature,
 		// but that does not correspond to the valid input parameters
 		// listed above.
@@ -168,6 +179,62 @@ func TestCmdBillingStatus(t *testing.T) {
 			},
 			pluginError(pi.ErrorCodeBillingStatusChangeNotAllowed),
 		},
+		{
+			"completion report with a closed billing status",
+			tokenb,
+			setBillingStatus(t, fid,
+				pi.SetBillingStatus{
+					Token:  token,
+					Status: pi.BillingStatusClosed,
+					Reason: "reason",
+					Report: &report,
+				}),
+			pluginError(pi.ErrorCodeCompletionReportInvalid),
+		},
+		{
+			"completion report mime type invalid",
+			tokenb,
+			setBillingStatus(t, fid,
+				pi.SetBillingStatus{
+					Token:  token,
+					Status: status,
+					Report: &pi.CompletionReport{
+						Name:    report.Name,
+						MIME:    "image/png",
+						Digest:  report.Digest,
+						Payload: report.Payload,
+					},
+				}),
+			pluginError(pi.ErrorCodeCompletionReportInvalid),
+		},
+		{
+			"completion report digest invalid",
+			tokenb,
+			setBillingStatus(t, fid,
+				pi.SetBillingStatus{
+					Token:  token,
+					Status: status,
+					Report: &pi.CompletionReport{
+						Name:    report.Name,
+						MIME:    report.MIME,
+						Digest:  hex.EncodeToString(util.Digest([]byte("x"))),
+						Payload: report.Payload,
+					},
+				}),
+			pluginError(pi.ErrorCodeCompletionReportInvalid),
+		},
+		{
+			"signature does not include the report digest",
+			tokenb,
+			pi.SetBillingStatus{
+				Token:     token,
+				Status:    status,
+				Report:    &report,
+				PublicKey: publicKey,
+				Signature: signature,
+			},
+			pluginError(pi.ErrorCodeSignatureInvalid),
+		},
 	}
 
 	// Run tests
@@ -239,6 +306,60 @@ func TestCmdBillingStatus(t *testing.T) {
 	}
 }
 
+func TestCmdBillingStatusReasonRequired(t *testing.T) {
+	// Setup pi plugin that requires a reason for completed
+	// billing status changes.
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+	p.billingStatusReasonRequired = map[pi.BillingStatusT]struct{}{
+		pi.BillingStatusClosed:    {},
+		pi.BillingStatusCompleted: {},
+	}
+
+	fid, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := "45154fb45664714b"
+	tokenb, err := hex.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sbs := setBillingStatus(t, fid, pi.SetBillingStatus{
+		Token:  token,
+		Status: pi.BillingStatusCompleted,
+		Reason: "",
+	})
+	b, err := json.Marshal(sbs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.cmdSetBillingStatus(tokenb, string(b))
+	var pe backend.PluginError
+	if !errors.As(err, &pe) {
+		t.Fatalf("want plugin error, got '%v'", err)
+	}
+	gotErrorCode := pi.ErrorCodeT(pe.ErrorCode)
+	if gotErrorCode != pi.ErrorCodeBillingStatusChangeNotAllowed {
+		t.Errorf("want error '%v', got '%v'",
+			pi.ErrorCodes[pi.ErrorCodeBillingStatusChangeNotAllowed],
+			pi.ErrorCodes[gotErrorCode])
+	}
+}
+
+func TestBillingStatusFromName(t *testing.T) {
+	for k, v := range pi.BillingStatuses {
+		got := billingStatusFromName(v)
+		if got != k {
+			t.Errorf("%v: got %v, want %v", v, got, k)
+		}
+	}
+	if s := billingStatusFromName("unknown"); s != pi.BillingStatusInvalid {
+		t.Errorf("unknown: got %v, want %v", s, pi.BillingStatusInvalid)
+	}
+}
+
 func TestCmdSummary(t *testing.T) {
 	// Setup pi plugin
 	p, cleanup := newTestPiPlugin(t)
@@ -320,18 +441,132 @@ func TestCmdSummary(t *testing.T) {
 
 }
 
+func TestCmdIdentity(t *testing.T) {
+	// Setup pi plugin
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	// Setup the server identity
+	fid, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.identity = fid
+
+	// Run test
+	r, err := p.cmdIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ir pi.IdentityReply
+	err = json.Unmarshal([]byte(r), &ir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify that a receipt created by the plugin can be
+	// verified using the returned public key.
+	signature := "e5bb39b4"
+	receipt := p.identity.SignMessage([]byte(signature))
+	err = util.VerifySignature(hex.EncodeToString(receipt[:]),
+		ir.PublicKey, signature)
+	if err != nil {
+		t.Errorf("verify receipt: %v", err)
+	}
+}
+
+func TestCompletionReportFind(t *testing.T) {
+	reports := []pi.CompletionReport{
+		{Name: "first", Digest: "aa"},
+		{Name: "orphan", Digest: "bb"},
+		{Name: "second", Digest: "aa"},
+	}
+
+	var tests = []struct {
+		name   string
+		digest string
+		want   string // Report name, empty if no match
+	}{
+		{"most recent match", "aa", "second"},
+		{"single match", "bb", "orphan"},
+		{"no match", "cc", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := completionReportFind(reports, tc.digest)
+			switch {
+			case tc.want == "" && r != nil:
+				t.Errorf("got report %v, want nil", r.Name)
+			case tc.want != "" && r == nil:
+				t.Errorf("got nil, want report %v", tc.want)
+			case tc.want != "" && r.Name != tc.want:
+				t.Errorf("got report %v, want %v", r.Name, tc.want)
+			}
+		})
+	}
+}
+
+func TestTokenMatches(t *testing.T) {
+	const token = "45154fb45664714b"
+	tokenb, err := hex.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name         string
+		payloadToken string
+		err          error
+	}{
+		{"full length token", token, nil},
+		{"short token", token[:7], nil},
+		{"token prefix", token[:10], nil},
+		{"full length mismatch", "da70d0766348340c",
+			pluginError(pi.ErrorCodeTokenInvalid)},
+		{"prefix mismatch", "da70d07", pluginError(pi.ErrorCodeTokenInvalid)},
+		{"not hex", "zzz", pluginError(pi.ErrorCodeTokenInvalid)},
+		{"empty", "", pluginError(pi.ErrorCodeTokenInvalid)},
+		{"too long", token + "0", pluginError(pi.ErrorCodeTokenInvalid)},
+		{"ambiguous prefix", token[:4], pluginError(pi.ErrorCodeTokenAmbiguous)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tokenMatches(tokenb, tc.payloadToken)
+			switch {
+			case tc.err == nil && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			case tc.err != nil:
+				var e backend.PluginError
+				if !errors.As(err, &e) {
+					t.Fatalf("got error %v, want plugin error", err)
+				}
+				want := tc.err.(backend.PluginError)
+				if e.ErrorCode != want.ErrorCode {
+					t.Errorf("got error code %v, want %v",
+						pi.ErrorCodes[pi.ErrorCodeT(e.ErrorCode)],
+						pi.ErrorCodes[pi.ErrorCodeT(want.ErrorCode)])
+				}
+			}
+		})
+	}
+}
+
 // setBillingStatus uses the provided arguments to return a SetBillingStatus
 // with a valid PublicKey and Signature.
 func setBillingStatus(t *testing.T, fid *identity.FullIdentity, sbs pi.SetBillingStatus) pi.SetBillingStatus {
 	t.Helper()
 
 	msg := sbs.Token + strconv.Itoa(int(sbs.Status)) + sbs.Reason
+	if sbs.Report != nil {
+		msg += sbs.Report.Digest
+	}
 	sig := fid.SignMessage([]byte(msg))
 
 	return pi.SetBillingStatus{
 		Token:     sbs.Token,
 		Status:    sbs.Status,
 		Reason:    sbs.Reason,
+		Report:    sbs.Report,
 		PublicKey: fid.Public.String(),
 		Signature: hex.EncodeToString(sig[:]),
 	}
@@ -0,0 +1,318 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/util"
+	"github.com/pkg/errors"
+)
+
+// fsckReport contains the problems that were found with the pi plugin data
+// of a single record.
+type fsckReport struct {
+	// corrupt contains the digests of the blobs that could not be
+	// decoded or that failed signature verification.
+	corrupt [][]byte
+
+	// orphaned contains the digests of the blobs that are valid, but
+	// should not exist, e.g. the billing status changes of a proposal
+	// whose vote was not approved or a completion report that is not
+	// referenced by any billing status change.
+	orphaned [][]byte
+
+	// missing is the number of blob digests that were found in the
+	// record tree, but whose blobs do not exist in the key-value store.
+	missing int
+
+	// inconsistent is set when the billing status changes of the
+	// proposal are valid individually, but the resulting billing
+	// status history is not a valid sequence of transitions.
+	inconsistent bool
+
+	// statusRepaired is set when the cached proposal status did not
+	// match the proposal status derived from the record data.
+	statusRepaired bool
+}
+
+// billingStatusBlob contains a decoded billing status change and the digest
+// of the blob that it was decoded from.
+type billingStatusBlob struct {
+	digest []byte
+	bsc    pi.BillingStatusChange
+}
+
+// fsck performs the pi plugin file system check. The billing status changes
+// and completion reports of all vetted records are verified and the cached
+// proposal statuses are checked against the statuses that are derived from
+// the record data. Corrupt and orphaned blobs are reported and are only
+// deleted when the fsck repair plugin setting has been enabled.
+func (p *piPlugin) fsck(tokens [][]byte) error {
+	log.Infof("Starting pi fsck for %v records", len(tokens))
+
+	var (
+		corrupt        int
+		orphaned       int
+		missing        int
+		inconsistent   int
+		statusRepaired int
+		deleted        int
+	)
+	for i, token := range tokens {
+		// Billing status changes and completion reports can only be
+		// added to vetted records.
+		state, err := p.tstore.RecordState(token)
+		if err != nil {
+			return err
+		}
+		if state != backend.StateVetted {
+			continue
+		}
+
+		log.Debugf("pi fsck for record %v/%v", i+1, len(tokens))
+
+		r, err := p.fsckRecord(token)
+		if err != nil {
+			return errors.Errorf("%x: %v", token, err)
+		}
+		corrupt += len(r.corrupt)
+		orphaned += len(r.orphaned)
+		missing += r.missing
+		if r.inconsistent {
+			inconsistent++
+		}
+		if r.statusRepaired {
+			statusRepaired++
+		}
+
+		// Delete the corrupt and orphaned blobs
+		del := append(r.corrupt, r.orphaned...)
+		if !p.fsckRepair || len(del) == 0 {
+			continue
+		}
+		err = p.tstore.BlobsDel(token, del)
+		if err != nil {
+			return errors.Errorf("%x: blobs del: %v", token, err)
+		}
+		deleted += len(del)
+
+		log.Infof("pi fsck deleted %v blobs from %x", len(del), token)
+	}
+
+	log.Infof("%v corrupt blobs, %v orphaned blobs, %v missing blobs",
+		corrupt, orphaned, missing)
+	log.Infof("%v records with an inconsistent billing status history",
+		inconsistent)
+	log.Infof("%v cached proposal statuses repaired", statusRepaired)
+	if p.fsckRepair {
+		log.Infof("%v blobs deleted", deleted)
+	} else if corrupt+orphaned > 0 {
+		log.Infof("Enable the %v plugin setting to delete the corrupt and "+
+			"orphaned blobs", pi.SettingKeyFsckRepair)
+	}
+	log.Infof("pi fsck complete")
+
+	return nil
+}
+
+// fsckRecord verifies the pi plugin data of a vetted record.
+func (p *piPlugin) fsckRecord(token []byte) (*fsckReport, error) {
+	var report fsckReport
+
+	// Verify the billing status changes
+	digests, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorBillingStatus})
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := p.tstore.Blobs(token, digests)
+	if err != nil {
+		return nil, err
+	}
+	serverPubKey := p.identity.Public.String()
+	valid := make([]billingStatusBlob, 0, len(digests))
+	for _, d := range digests {
+		be, ok := blobs[hex.EncodeToString(d)]
+		if !ok {
+			log.Errorf("pi fsck %x: billing status change blob %x not found",
+				token, d)
+			report.missing++
+			continue
+		}
+		bsc, err := billingStatusDecode(be)
+		if err == nil {
+			err = billingStatusChangeVerify(token, *bsc, serverPubKey)
+		}
+		if err != nil {
+			log.Errorf("pi fsck %x: corrupt billing status change %x: %v",
+				token, d, err)
+			report.corrupt = append(report.corrupt, d)
+			continue
+		}
+		valid = append(valid, billingStatusBlob{
+			digest: d,
+			bsc:    *bsc,
+		})
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		return valid[i].bsc.Timestamp < valid[j].bsc.Timestamp
+	})
+
+	// Get the record data that is required to determine whether the
+	// proposal is allowed to have billing status changes.
+	r, err := p.record(backend.RecordRequest{
+		Token:     token,
+		Filenames: []string{ticketvote.FileNameVoteMetadata},
+	})
+	if err != nil {
+		return nil, err
+	}
+	vm, err := voteMetadataDecode(r.Files)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := p.voteSummary(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Billing status changes are only allowed on approved proposals
+	// that are not RFPs.
+	bscs := make([]pi.BillingStatusChange, 0, len(valid))
+	if len(valid) > 0 &&
+		(vs.Status != ticketvote.VoteStatusApproved || isRFP(vm)) {
+		for _, v := range valid {
+			log.Errorf("pi fsck %x: orphaned billing status change %x",
+				token, v.digest)
+			report.orphaned = append(report.orphaned, v.digest)
+		}
+	} else {
+		for _, v := range valid {
+			bscs = append(bscs, v.bsc)
+		}
+		err = billingStatusesVerify(bscs, p.billingStatusChangesMax)
+		if err != nil {
+			log.Errorf("pi fsck %x: inconsistent billing status history: %v",
+				token, err)
+			report.inconsistent = true
+		}
+	}
+
+	// Verify the completion reports. A completion report must be
+	// referenced by one of the billing status changes.
+	referenced := make(map[string]struct{}, len(bscs))
+	for _, v := range bscs {
+		if v.ReportDigest != "" {
+			referenced[v.ReportDigest] = struct{}{}
+		}
+	}
+	digests, err = p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorCompletionReport})
+	if err != nil {
+		return nil, err
+	}
+	blobs, err = p.tstore.Blobs(token, digests)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range digests {
+		be, ok := blobs[hex.EncodeToString(d)]
+		if !ok {
+			log.Errorf("pi fsck %x: completion report blob %x not found",
+				token, d)
+			report.missing++
+			continue
+		}
+		cr, err := completionReportDecode(be)
+		if err != nil {
+			log.Errorf("pi fsck %x: corrupt completion report %x: %v",
+				token, d, err)
+			report.corrupt = append(report.corrupt, d)
+			continue
+		}
+		if _, ok := referenced[cr.Digest]; !ok {
+			log.Errorf("pi fsck %x: orphaned completion report %x",
+				token, d)
+			report.orphaned = append(report.orphaned, d)
+		}
+	}
+
+	// Verify that the cached proposal status matches the status that
+	// is derived from the record data. The cache only contains data
+	// that can be rebuilt at any time, so a stale entry is always
+	// replaced regardless of the fsck repair setting.
+	status, err := proposalStatus(r.RecordMetadata.State,
+		r.RecordMetadata.Status, vs.Status, vm, bscs)
+	if err != nil {
+		status, err = p.proposalStatusLegacy(token, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tokenStr := hex.EncodeToString(token)
+	e := p.statuses.get(tokenStr)
+	if e != nil && e.propStatus != status {
+		log.Errorf("pi fsck %x: cached proposal status %v does not match "+
+			"derived status %v", token, e.propStatus, status)
+		p.statuses.set(tokenStr, statusEntry{
+			propStatus:           status,
+			recordState:          r.RecordMetadata.State,
+			recordStatus:         r.RecordMetadata.Status,
+			voteStatus:           vs.Status,
+			voteMetadata:         vm,
+			billingStatusesCount: len(bscs),
+		})
+		report.statusRepaired = true
+	}
+
+	return &report, nil
+}
+
+// billingStatusChangeVerify verifies that a billing status change belongs to
+// the provided record, contains a valid admin signature, and contains a valid
+// server receipt.
+func billingStatusChangeVerify(token []byte, bsc pi.BillingStatusChange, serverPubKey string) error {
+	err := tokenMatches(token, bsc.Token)
+	if err != nil {
+		return err
+	}
+	msg := bsc.Token + strconv.FormatUint(uint64(bsc.Status), 10) +
+		bsc.Reason + bsc.ReportDigest
+	err = util.VerifySignature(bsc.Signature, bsc.PublicKey, msg)
+	if err != nil {
+		return errors.Errorf("invalid signature: %v", err)
+	}
+	err = util.VerifySignature(bsc.Receipt, serverPubKey, bsc.Signature)
+	if err != nil {
+		return errors.Errorf("invalid receipt: %v", err)
+	}
+	return nil
+}
+
+// billingStatusesVerify verifies that the provided billing status changes,
+// sorted from oldest to newest, are a valid sequence of billing status
+// transitions for an approved proposal.
+func billingStatusesVerify(bscs []pi.BillingStatusChange, max uint32) error {
+	if uint32(len(bscs)) > max {
+		return errors.Errorf("%v billing status changes exceeds the "+
+			"maximum of %v", len(bscs), max)
+	}
+	curr := pi.BillingStatusActive
+	for _, v := range bscs {
+		if _, ok := billingStatusChanges[curr][v.Status]; !ok {
+			return errors.Errorf("invalid billing status transition "+
+				"%v to %v", pi.BillingStatuses[curr],
+				pi.BillingStatuses[v.Status])
+		}
+		curr = v.Status
+	}
+	return nil
+}
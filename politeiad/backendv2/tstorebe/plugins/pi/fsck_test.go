@@ -0,0 +1,116 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+)
+
+func TestBillingStatusChangeVerify(t *testing.T) {
+	admin, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const token = "45154fb45664714b"
+	tokenb, err := hex.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// newBSC returns a billing status change with a valid admin
+	// signature and server receipt.
+	newBSC := func() pi.BillingStatusChange {
+		sbs := setBillingStatus(t, admin, pi.SetBillingStatus{
+			Token:  token,
+			Status: pi.BillingStatusClosed,
+			Reason: "reason",
+		})
+		receipt := server.SignMessage([]byte(sbs.Signature))
+		return pi.BillingStatusChange{
+			Token:     sbs.Token,
+			Status:    sbs.Status,
+			Reason:    sbs.Reason,
+			PublicKey: sbs.PublicKey,
+			Signature: sbs.Signature,
+			Receipt:   hex.EncodeToString(receipt[:]),
+		}
+	}
+
+	var (
+		wrongToken  = newBSC()
+		wrongStatus = newBSC()
+		wrongSig    = newBSC()
+		wrongRcpt   = newBSC()
+	)
+	wrongToken.Token = "da70d0766348340c"
+	wrongStatus.Status = pi.BillingStatusCompleted
+	wrongSig.Signature = wrongRcpt.Receipt
+	wrongRcpt.Receipt = newBSC().Signature
+
+	var tests = []struct {
+		name    string
+		bsc     pi.BillingStatusChange
+		wantErr bool
+	}{
+		{"valid", newBSC(), false},
+		{"wrong token", wrongToken, true},
+		{"altered status", wrongStatus, true},
+		{"invalid signature", wrongSig, true},
+		{"invalid receipt", wrongRcpt, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := billingStatusChangeVerify(tokenb, v.bsc,
+				server.Public.String())
+			switch {
+			case v.wantErr && err == nil:
+				t.Errorf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestBillingStatusesVerify(t *testing.T) {
+	var (
+		active    = pi.BillingStatusChange{Status: pi.BillingStatusActive}
+		closed    = pi.BillingStatusChange{Status: pi.BillingStatusClosed}
+		completed = pi.BillingStatusChange{Status: pi.BillingStatusCompleted}
+	)
+	var tests = []struct {
+		name    string
+		bscs    []pi.BillingStatusChange
+		max     uint32
+		wantErr bool
+	}{
+		{"no changes", nil, 1, false},
+		{"closed", []pi.BillingStatusChange{closed}, 1, false},
+		{"completed", []pi.BillingStatusChange{completed}, 1, false},
+		{"reopened", []pi.BillingStatusChange{closed, active}, 2, false},
+		{"active to active", []pi.BillingStatusChange{active}, 1, true},
+		{"closed to closed", []pi.BillingStatusChange{closed, closed}, 2, true},
+		{"exceeds max", []pi.BillingStatusChange{closed, active}, 1, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := billingStatusesVerify(v.bscs, v.max)
+			switch {
+			case v.wantErr && err == nil:
+				t.Errorf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
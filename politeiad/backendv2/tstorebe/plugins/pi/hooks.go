@@ -8,8 +8,11 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
@@ -92,14 +95,217 @@ func (p *piPlugin) hookEditRecordPre(payload string) error {
 	return nil
 }
 
+// hookSetRecordStatusPre adds pi specific validation onto the tstore backend
+// RecordSetStatus method. The proposal status is cached prior to the record
+// status change so that the resulting proposal status transition can be
+// detected.
+func (p *piPlugin) hookSetRecordStatusPre(payload string) error {
+	var srs plugins.HookSetRecordStatus
+	err := json.Unmarshal([]byte(payload), &srs)
+	if err != nil {
+		return err
+	}
+	token, err := tokenDecode(srs.Record.RecordMetadata.Token)
+	if err != nil {
+		return err
+	}
+
+	// Verify the due-diligence checks when a public proposal is being
+	// abandoned. The proposal status is required for these checks, so
+	// failing to determine it prevents the record status change.
+	if isAbandonment(srs.Record.RecordMetadata, srs.RecordMetadata) {
+		propStatus, err := p.getProposalStatus(token)
+		if err != nil {
+			return err
+		}
+		reason, err := statusChangeReason(srs.Metadata)
+		if err != nil {
+			return err
+		}
+		return p.abandonmentVerify(propStatus, reason)
+	}
+
+	// Failing to determine the proposal status should not
+	// prevent the record status change. Log the error and
+	// continue.
+	_, err = p.getProposalStatus(token)
+	if err != nil {
+		log.Errorf("hookSetRecordStatusPre: getProposalStatus %x: %v",
+			token, err)
+	}
+
+	return nil
+}
+
+// isAbandonment returns whether a record status change is the abandonment of
+// a public proposal.
+func isAbandonment(curr, updated backend.RecordMetadata) bool {
+	return curr.State == backend.StateVetted &&
+		curr.Status == backend.StatusPublic &&
+		updated.Status == backend.StatusArchived
+}
+
+// abandonmentVerify verifies that a public proposal with the provided
+// proposal status can be abandoned using the provided reason. A proposal
+// cannot be abandoned while its vote is in progress or while it is actively
+// billing against the treasury. The reason must also meet the minimum length
+// requirement.
+func (p *piPlugin) abandonmentVerify(s pi.PropStatusT, reason string) error {
+	switch s {
+	case pi.PropStatusVoteStarted:
+		return backend.PluginError{
+			PluginID:     pi.PluginID,
+			ErrorCode:    uint32(pi.ErrorCodeAbandonVoteInProgress),
+			ErrorContext: "the proposal vote must finish before it can be abandoned",
+		}
+	case pi.PropStatusActive:
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeAbandonBillingActive),
+			ErrorContext: "the proposal billing status must be set to " +
+				"completed or closed before it can be abandoned",
+		}
+	}
+
+	l := utf8.RuneCountInString(strings.TrimSpace(reason))
+	if uint32(l) < p.abandonReasonLengthMin {
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeAbandonReasonInvalid),
+			ErrorContext: fmt.Sprintf("reason must contain at least %v "+
+				"characters; got %v", p.abandonReasonLengthMin, l),
+			ErrorParams: map[string]string{
+				pi.ErrorParamField: "reason",
+				pi.ErrorParamMin:   strconv.FormatUint(uint64(p.abandonReasonLengthMin), 10),
+				pi.ErrorParamGot:   strconv.Itoa(l),
+			},
+		}
+	}
+
+	return nil
+}
+
+// statusChangeReason returns the reason of the most recent status change from
+// the usermd status changes metadata stream. The usermd plugin verifies the
+// status change signature, which covers the reason. An empty string is
+// returned if the metadata stream does not contain any status changes.
+func statusChangeReason(metadata []backend.MetadataStream) (string, error) {
+	var reason string
+	for _, v := range metadata {
+		if v.PluginID != usermd.PluginID ||
+			v.StreamID != usermd.StreamIDStatusChanges {
+			// Not the mdstream we're looking for
+			continue
+		}
+		d := json.NewDecoder(strings.NewReader(v.Payload))
+		for {
+			var sc usermd.StatusChangeMetadata
+			err := d.Decode(&sc)
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return "", err
+			}
+			reason = sc.Reason
+		}
+		break
+	}
+	return reason, nil
+}
+
+// hookSetRecordStatusPost updates the cached proposal status after a record
+// status change. A proposal status change event is emitted if the record
+// status change resulted in a proposal status transition, e.g. a proposal
+// being archived.
+func (p *piPlugin) hookSetRecordStatusPost(payload string) error {
+	var srs plugins.HookSetRecordStatus
+	err := json.Unmarshal([]byte(payload), &srs)
+	if err != nil {
+		return err
+	}
+	token, err := tokenDecode(srs.RecordMetadata.Token)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.getProposalStatus(token)
+	return err
+}
+
+// hookPluginEvent handles the plugin events that are emitted by other
+// plugins. The cached proposal status is updated when a ticketvote vote ends
+// so that the proposal status transition is detected, and the corresponding
+// event and webhooks are emitted, as soon as the vote has been finalized
+// instead of the next time that the proposal status is requested.
+func (p *piPlugin) hookPluginEvent(payload string) error {
+	var e plugins.HookPluginEvent
+	err := json.Unmarshal([]byte(payload), &e)
+	if err != nil {
+		return err
+	}
+	if e.PluginID != ticketvote.PluginID ||
+		e.Event != ticketvote.EventVoteEnded {
+		return nil
+	}
+	var ve ticketvote.VoteEnded
+	err = json.Unmarshal([]byte(e.Payload), &ve)
+	if err != nil {
+		return err
+	}
+	token, err := tokenDecode(ve.Token)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Vote ended %v: %v", ve.Token,
+		ticketvote.VoteStatuses[ve.Summary.Status])
+
+	_, err = p.getProposalStatus(token)
+	return err
+}
+
 // hookCommentNew adds pi specific validation onto the comments plugin New
 // command.
 func (p *piPlugin) hookCommentNew(token []byte, cmd, payload string) error {
+	var n comments.New
+	err := json.Unmarshal([]byte(payload), &n)
+	if err != nil {
+		return err
+	}
+	err = p.commentLengthVerify(n.State, n.Comment)
+	if err != nil {
+		return err
+	}
+	if n.State == comments.RecordStateVetted && p.commentLockDays > 0 {
+		vs, err := p.voteSummary(token)
+		if err != nil {
+			return err
+		}
+		if p.commentsLocked(*vs) {
+			return backend.PluginError{
+				PluginID:  pi.PluginID,
+				ErrorCode: uint32(pi.ErrorCodeCommentsLocked),
+				ErrorContext: fmt.Sprintf("comments are locked %v days "+
+					"after the vote finishes", p.commentLockDays),
+			}
+		}
+	}
 	return p.commentWritesAllowed(token, cmd, payload)
 }
 
-// hookCommentDel adds pi specific validation onto the comments plugin Del
+// hookCommentEdit adds pi specific validation onto the comments plugin Edit
 // command.
+func (p *piPlugin) hookCommentEdit(payload string) error {
+	var e comments.Edit
+	err := json.Unmarshal([]byte(payload), &e)
+	if err != nil {
+		return err
+	}
+	return p.commentLengthVerify(e.State, e.Comment)
+}
+
+// hookCommentDel adds pi specific validation onto the comments plugin Del
+// and DelUser commands.
 func (p *piPlugin) hookCommentDel(token []byte, cmd, payload string) error {
 	return p.commentWritesAllowed(token, cmd, payload)
 }
@@ -126,7 +332,9 @@ func (p *piPlugin) hookPluginPre(payload string) error {
 		switch hpp.Cmd {
 		case comments.CmdNew:
 			return p.hookCommentNew(hpp.Token, hpp.Cmd, hpp.Payload)
-		case comments.CmdDel:
+		case comments.CmdEdit:
+			return p.hookCommentEdit(hpp.Payload)
+		case comments.CmdDel, comments.CmdDelUser:
 			return p.hookCommentDel(hpp.Token, hpp.Cmd, hpp.Payload)
 		case comments.CmdVote:
 			return p.hookCommentVote(hpp.Token, hpp.Cmd, hpp.Payload)
@@ -226,6 +434,11 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 						"size %v exceeds max size %v",
 						v.Name, len(payload),
 						p.textFileSizeMax),
+					ErrorParams: map[string]string{
+						pi.ErrorParamField: v.Name,
+						pi.ErrorParamMax:   strconv.FormatUint(uint64(p.textFileSizeMax), 10),
+						pi.ErrorParamGot:   strconv.Itoa(len(payload)),
+					},
 				}
 			}
 
@@ -241,6 +454,11 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 						"size %v exceeds max size %v",
 						v.Name, len(payload),
 						p.imageFileSizeMax),
+					ErrorParams: map[string]string{
+						pi.ErrorParamField: v.Name,
+						pi.ErrorParamMax:   strconv.FormatUint(uint64(p.imageFileSizeMax), 10),
+						pi.ErrorParamGot:   strconv.Itoa(len(payload)),
+					},
 				}
 			}
 
@@ -272,6 +490,10 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 			ErrorCode: uint32(pi.ErrorCodeImageFileCountInvalid),
 			ErrorContext: fmt.Sprintf("got %v image files, max "+
 				"is %v", imagesCount, p.imageFileCountMax),
+			ErrorParams: map[string]string{
+				pi.ErrorParamMax: strconv.FormatUint(uint64(p.imageFileCountMax), 10),
+				pi.ErrorParamGot: strconv.FormatUint(uint64(imagesCount), 10),
+			},
 		}
 	}
 
@@ -348,21 +570,34 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 	if !isRFP(vm) {
 		// Validate proposal start date.
 		if !p.proposalStartDateIsValid(pm.StartDate) {
+			startMin := time.Now().Unix() + p.proposalStartDateMin
 			return backend.PluginError{
 				PluginID:  pi.PluginID,
 				ErrorCode: uint32(pi.ErrorCodeProposalStartDateInvalid),
 				ErrorContext: fmt.Sprintf("start date (%v) must be after %v",
-					pm.StartDate, time.Now().Unix()-p.proposalStartDateMin),
+					pm.StartDate, startMin),
+				ErrorParams: map[string]string{
+					pi.ErrorParamField: "startdate",
+					pi.ErrorParamMin:   strconv.FormatInt(startMin, 10),
+					pi.ErrorParamGot:   strconv.FormatInt(pm.StartDate, 10),
+				},
 			}
 		}
 
 		// Validate proposal end date.
 		if !p.proposalEndDateIsValid(pm.StartDate, pm.EndDate) {
+			endMax := time.Now().Unix() + p.proposalEndDateMax
 			return backend.PluginError{
 				PluginID:  pi.PluginID,
 				ErrorCode: uint32(pi.ErrorCodeProposalEndDateInvalid),
-				ErrorContext: fmt.Sprintf("end date (%v) must be before %v",
-					pm.EndDate, time.Now().Unix()+p.proposalEndDateMax),
+				ErrorContext: fmt.Sprintf("end date (%v) must be after the "+
+					"start date and before %v", pm.EndDate, endMax),
+				ErrorParams: map[string]string{
+					pi.ErrorParamField: "enddate",
+					pi.ErrorParamMin:   strconv.FormatInt(pm.StartDate, 10),
+					pi.ErrorParamMax:   strconv.FormatInt(endMax, 10),
+					pi.ErrorParamGot:   strconv.FormatInt(pm.EndDate, 10),
+				},
 			}
 		}
 
@@ -373,6 +608,12 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 				ErrorCode: uint32(pi.ErrorCodeProposalAmountInvalid),
 				ErrorContext: fmt.Sprintf("got %v amount, min is %v, "+
 					"max is %v", pm.Amount, p.proposalAmountMin, p.proposalAmountMax),
+				ErrorParams: map[string]string{
+					pi.ErrorParamField: "amount",
+					pi.ErrorParamMin:   strconv.FormatUint(p.proposalAmountMin, 10),
+					pi.ErrorParamMax:   strconv.FormatUint(p.proposalAmountMax, 10),
+					pi.ErrorParamGot:   strconv.FormatUint(pm.Amount, 10),
+				},
 			}
 		}
 	}
@@ -685,6 +926,61 @@ func (p *piPlugin) commentWritesAllowed(token []byte, cmd, payload string) error
 	}
 }
 
+// commentLengthVerify verifies that a comment does not exceed the maximum
+// comment length that has been configured for the record state. A maximum
+// length of 0 means that the state does not have a pi specific limit.
+func (p *piPlugin) commentLengthVerify(s comments.RecordStateT, comment string) error {
+	var lengthMax uint32
+	switch s {
+	case comments.RecordStateUnvetted:
+		lengthMax = p.commentLengthMaxUnvetted
+	case comments.RecordStateVetted:
+		lengthMax = p.commentLengthMaxVetted
+	}
+	if lengthMax == 0 {
+		return nil
+	}
+
+	l := utf8.RuneCountInString(comment)
+	if uint32(l) > lengthMax {
+		return backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeCommentLengthInvalid),
+			ErrorContext: fmt.Sprintf("comment must not exceed %v "+
+				"characters; got %v", lengthMax, l),
+			ErrorParams: map[string]string{
+				pi.ErrorParamField: "comment",
+				pi.ErrorParamMax:   strconv.FormatUint(uint64(lengthMax), 10),
+				pi.ErrorParamGot:   strconv.Itoa(l),
+			},
+		}
+	}
+
+	return nil
+}
+
+// commentsLocked returns whether the comment lock period has elapsed for a
+// proposal with the provided vote summary. The lock period starts once the
+// vote has finished and lasts for the number of days specified by the
+// comment lock days setting. Proposals that have not finished voting are
+// never locked.
+func (p *piPlugin) commentsLocked(vs ticketvote.SummaryReply) bool {
+	if p.commentLockDays == 0 {
+		return false
+	}
+	switch vs.Status {
+	case ticketvote.VoteStatusFinished, ticketvote.VoteStatusApproved,
+		ticketvote.VoteStatusRejected:
+		// The vote has finished; continue
+	default:
+		return false
+	}
+
+	lockHeight := uint64(vs.EndBlockHeight) +
+		uint64(p.commentLockDays)*uint64(p.blocksPerDay)
+	return uint64(vs.BestBlock) >= lockHeight
+}
+
 // tokenDecode returns the decoded censorship token. An error will be returned
 // if the token is not a full length token.
 func tokenDecode(token string) ([]byte, error) {
@@ -14,6 +14,8 @@ import (
 	"image/png"
 	"net/http"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -22,6 +24,8 @@ import (
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
 	"github.com/decred/politeia/politeiad/plugins/comments"
 	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
 	"github.com/decred/politeia/util"
 )
 
@@ -197,6 +201,212 @@ func TestHookEditRecordPre(t *testing.T) {
 	runProposalFormatTests(t, p.hookEditRecordPre)
 }
 
+func TestAbandonmentVerify(t *testing.T) {
+	// Setup pi plugin
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	reason := strings.Repeat("a", int(p.abandonReasonLengthMin))
+	var tests = []struct {
+		name    string
+		status  pi.PropStatusT
+		reason  string
+		errCode pi.ErrorCodeT
+	}{
+		{"under review", pi.PropStatusUnderReview, reason, 0},
+		{"rejected", pi.PropStatusRejected, reason, 0},
+		{"completed", pi.PropStatusCompleted, reason, 0},
+		{"vote in progress", pi.PropStatusVoteStarted, reason,
+			pi.ErrorCodeAbandonVoteInProgress},
+		{"billing active", pi.PropStatusActive, reason,
+			pi.ErrorCodeAbandonBillingActive},
+		{"reason too short", pi.PropStatusUnderReview, reason[1:],
+			pi.ErrorCodeAbandonReasonInvalid},
+		{"reason whitespace", pi.PropStatusUnderReview,
+			" " + reason[1:] + " ", pi.ErrorCodeAbandonReasonInvalid},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := p.abandonmentVerify(v.status, v.reason)
+			var gotErrCode pi.ErrorCodeT
+			if err != nil {
+				var pe backend.PluginError
+				if !errors.As(err, &pe) {
+					t.Fatalf("got error %v, want plugin error", err)
+				}
+				gotErrCode = pi.ErrorCodeT(pe.ErrorCode)
+			}
+			if gotErrCode != v.errCode {
+				t.Errorf("got error code %v, want %v",
+					pi.ErrorCodes[gotErrCode], pi.ErrorCodes[v.errCode])
+			}
+		})
+	}
+}
+
+func TestCommentLengthVerify(t *testing.T) {
+	// Setup pi plugin
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+	p.commentLengthMaxUnvetted = 10
+	p.commentLengthMaxVetted = 5
+
+	var tests = []struct {
+		name    string
+		state   comments.RecordStateT
+		comment string
+		errCode pi.ErrorCodeT
+	}{
+		{"unvetted at max", comments.RecordStateUnvetted,
+			strings.Repeat("a", 10), 0},
+		{"unvetted too long", comments.RecordStateUnvetted,
+			strings.Repeat("a", 11), pi.ErrorCodeCommentLengthInvalid},
+		{"vetted at max", comments.RecordStateVetted,
+			strings.Repeat("a", 5), 0},
+		{"vetted too long", comments.RecordStateVetted,
+			strings.Repeat("a", 6), pi.ErrorCodeCommentLengthInvalid},
+		{"multibyte characters", comments.RecordStateVetted,
+			strings.Repeat("é", 5), 0},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := p.commentLengthVerify(v.state, v.comment)
+			var gotErrCode pi.ErrorCodeT
+			if err != nil {
+				var pe backend.PluginError
+				if !errors.As(err, &pe) {
+					t.Fatalf("got error %v, want plugin error", err)
+				}
+				gotErrCode = pi.ErrorCodeT(pe.ErrorCode)
+			}
+			if gotErrCode != v.errCode {
+				t.Errorf("got error code %v, want %v",
+					pi.ErrorCodes[gotErrCode], pi.ErrorCodes[v.errCode])
+			}
+		})
+	}
+
+	// A max length of 0 disables the pi specific limit
+	p.commentLengthMaxVetted = 0
+	err := p.commentLengthVerify(comments.RecordStateVetted,
+		strings.Repeat("a", 100))
+	if err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+}
+
+func TestCommentsLocked(t *testing.T) {
+	// Setup pi plugin
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+	p.commentLockDays = 2
+	p.blocksPerDay = 288
+
+	lockHeight := uint32(1000 + 2*288)
+	var tests = []struct {
+		name      string
+		status    ticketvote.VoteStatusT
+		bestBlock uint32
+		want      bool
+	}{
+		{"vote started", ticketvote.VoteStatusStarted, lockHeight, false},
+		{"approved before lock", ticketvote.VoteStatusApproved,
+			lockHeight - 1, false},
+		{"approved at lock", ticketvote.VoteStatusApproved, lockHeight, true},
+		{"rejected after lock", ticketvote.VoteStatusRejected,
+			lockHeight + 1, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			vs := ticketvote.SummaryReply{
+				Status:         v.status,
+				EndBlockHeight: 1000,
+				BestBlock:      v.bestBlock,
+			}
+			got := p.commentsLocked(vs)
+			if got != v.want {
+				t.Errorf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+
+	// A lock period of 0 disables the lock
+	p.commentLockDays = 0
+	vs := ticketvote.SummaryReply{
+		Status:         ticketvote.VoteStatusApproved,
+		EndBlockHeight: 1000,
+		BestBlock:      lockHeight,
+	}
+	if p.commentsLocked(vs) {
+		t.Errorf("got locked, want unlocked")
+	}
+}
+
+func TestProposalFilesVerifyErrorParams(t *testing.T) {
+	// Setup pi plugin
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	amount := p.proposalAmountMax + 1
+	files := filesForProposal(t, &pi.ProposalMetadata{
+		Amount: amount,
+	})
+	err := p.proposalFilesVerify(files)
+	var pe backend.PluginError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got error %v, want plugin error", err)
+	}
+	if pi.ErrorCodeT(pe.ErrorCode) != pi.ErrorCodeProposalAmountInvalid {
+		t.Fatalf("got error code %v, want %v",
+			pi.ErrorCodes[pi.ErrorCodeT(pe.ErrorCode)],
+			pi.ErrorCodes[pi.ErrorCodeProposalAmountInvalid])
+	}
+
+	want := map[string]string{
+		pi.ErrorParamField: "amount",
+		pi.ErrorParamMin:   strconv.FormatUint(p.proposalAmountMin, 10),
+		pi.ErrorParamMax:   strconv.FormatUint(p.proposalAmountMax, 10),
+		pi.ErrorParamGot:   strconv.FormatUint(amount, 10),
+	}
+	if !reflect.DeepEqual(pe.ErrorParams, want) {
+		t.Errorf("got error params %v, want %v", pe.ErrorParams, want)
+	}
+}
+
+func TestStatusChangeReason(t *testing.T) {
+	// Two status changes in a single metadata stream
+	payload := `{"token":"a","status":2,"reason":""}` +
+		`{"token":"a","status":3,"reason":"abandoned"}`
+	md := []backend.MetadataStream{
+		{
+			PluginID: usermd.PluginID,
+			StreamID: usermd.StreamIDUserMetadata,
+			Payload:  `{"userid":"x"}`,
+		},
+		{
+			PluginID: usermd.PluginID,
+			StreamID: usermd.StreamIDStatusChanges,
+			Payload:  payload,
+		},
+	}
+	reason, err := statusChangeReason(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != "abandoned" {
+		t.Errorf("got reason '%v', want 'abandoned'", reason)
+	}
+
+	// No status changes
+	reason, err = statusChangeReason(md[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != "" {
+		t.Errorf("got reason '%v', want empty reason", reason)
+	}
+}
+
 // runProposalFormatTests runs the proposal format tests using the provided
 // hook function as the test function. This allows us to run the same set of
 // formatting tests of multiple hooks without needing to duplicate the setup
@@ -959,6 +1169,9 @@ func fileProposalMetadata(t *testing.T, pm *pi.ProposalMetadata) backend.File {
 	if pm.Domain != "" {
 		pmd.Domain = pm.Domain
 	}
+	if pm.LegacyToken != "" {
+		pmd.LegacyToken = pm.LegacyToken
+	}
 
 	// Setup and return the backend file
 	b, err := json.Marshal(&pmd)
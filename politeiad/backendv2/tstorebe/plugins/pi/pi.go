@@ -11,7 +11,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/politeia/politeiad/api/v1/identity"
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
@@ -37,6 +40,11 @@ type piPlugin struct {
 	// performance of determining the proposal statuses at runtime.
 	statuses proposalStatuses
 
+	// statusChangeMtx serializes the proposal status transition checks
+	// against the persisted last observed proposal statuses so that a
+	// transition is only emitted once.
+	statusChangeMtx sync.Mutex
+
 	// dataDir is the pi plugin data directory. The only data that is
 	// stored here is cached data that can be re-created at any time
 	// by walking the trillian trees.
@@ -65,6 +73,24 @@ type piPlugin struct {
 	billingStatusChangesMax      uint32
 	summariesPageSize            uint32
 	billingStatusChangesPageSize uint32
+	statusCacheWarm              bool
+	billingStatusReasonEncoded   string // JSON encoded []string
+	billingStatusReasonRequired  map[pi.BillingStatusT]struct{}
+	webhookURLsEncoded           string // JSON encoded []string
+	abandonReasonLengthMin       uint32 // In characters
+	fsckRepair                   bool
+	commentLengthMaxUnvetted     uint32 // In characters
+	commentLengthMaxVetted       uint32 // In characters
+	commentLockDays              uint32
+
+	// blocksPerDay is the number of blocks that are expected to be
+	// mined in a day on the active network. It is used to translate
+	// the comment lock days setting into a block height.
+	blocksPerDay uint32
+
+	// webhooks dispatches the proposal status change webhooks. It is
+	// nil if no webhook URLs have been configured.
+	webhooks *webhookDispatcher
 }
 
 // Setup performs any plugin setup that is required.
@@ -73,6 +99,17 @@ type piPlugin struct {
 func (p *piPlugin) Setup() error {
 	log.Tracef("pi Setup")
 
+	// Warm the proposal statuses cache in the background so that
+	// startup is not blocked by the inventory walk.
+	if p.statusCacheWarm {
+		go func() {
+			err := p.statusesCacheWarm()
+			if err != nil {
+				log.Errorf("statusesCacheWarm: %v", err)
+			}
+		}()
+	}
+
 	return nil
 }
 
@@ -89,6 +126,14 @@ func (p *piPlugin) Cmd(token []byte, cmd, payload string) (string, error) {
 		return p.cmdSummary(token)
 	case pi.CmdBillingStatusChanges:
 		return p.cmdBillingStatusChanges(token)
+	case pi.CmdIdentity:
+		return p.cmdIdentity()
+	case pi.CmdCompletionReport:
+		return p.cmdCompletionReport(token)
+	case pi.CmdWithdraw:
+		return p.cmdWithdraw(token, payload)
+	case pi.CmdWithdrawal:
+		return p.cmdWithdrawal(token)
 	}
 
 	return "", backend.ErrPluginCmdInvalid
@@ -105,6 +150,8 @@ func (p *piPlugin) Hook(h plugins.HookT, payload string) error {
 		return p.hookNewRecordPre(payload)
 	case plugins.HookTypeEditRecordPre:
 		return p.hookEditRecordPre(payload)
+	case plugins.HookTypeSetRecordStatusPre:
+		return p.hookSetRecordStatusPre(payload)
 	case plugins.HookTypePluginPre:
 		return p.hookPluginPre(payload)
 	}
@@ -112,6 +159,32 @@ func (p *piPlugin) Hook(h plugins.HookT, payload string) error {
 	return nil
 }
 
+// AsyncHooks returns the hooks that the pi plugin executes asynchronously.
+//
+// This function satisfies the plugins AsyncHookClient interface.
+func (p *piPlugin) AsyncHooks() []plugins.HookT {
+	return []plugins.HookT{
+		plugins.HookTypeSetRecordStatusPost,
+		plugins.HookTypePluginEvent,
+	}
+}
+
+// AsyncHook executes an async plugin hook.
+//
+// This function satisfies the plugins AsyncHookClient interface.
+func (p *piPlugin) AsyncHook(h plugins.HookT, payload string) error {
+	log.Tracef("pi AsyncHook: %v", plugins.Hooks[h])
+
+	switch h {
+	case plugins.HookTypeSetRecordStatusPost:
+		return p.hookSetRecordStatusPost(payload)
+	case plugins.HookTypePluginEvent:
+		return p.hookPluginEvent(payload)
+	}
+
+	return nil
+}
+
 // Fsck performs a plugin file system check. The plugin is provided with the
 // tokens for all records in the backend.
 //
@@ -119,7 +192,7 @@ func (p *piPlugin) Hook(h plugins.HookT, payload string) error {
 func (p *piPlugin) Fsck(tokens [][]byte) error {
 	log.Tracef("pi Fsck")
 
-	return nil
+	return p.fsck(tokens)
 }
 
 // Settings returns the plugin's settings.
@@ -189,11 +262,43 @@ func (p *piPlugin) Settings() []backend.PluginSetting {
 			Key:   pi.SettingKeyBillingStatusChangesPageSize,
 			Value: strconv.FormatUint(uint64(p.billingStatusChangesPageSize), 10),
 		},
+		{
+			Key:   pi.SettingKeyStatusCacheWarm,
+			Value: strconv.FormatBool(p.statusCacheWarm),
+		},
+		{
+			Key:   pi.SettingKeyBillingStatusReasonRequired,
+			Value: p.billingStatusReasonEncoded,
+		},
+		{
+			Key:   pi.SettingKeyWebhookURLs,
+			Value: p.webhookURLsEncoded,
+		},
+		{
+			Key:   pi.SettingKeyAbandonReasonLengthMin,
+			Value: strconv.FormatUint(uint64(p.abandonReasonLengthMin), 10),
+		},
+		{
+			Key:   pi.SettingKeyFsckRepair,
+			Value: strconv.FormatBool(p.fsckRepair),
+		},
+		{
+			Key:   pi.SettingKeyCommentLengthMaxUnvetted,
+			Value: strconv.FormatUint(uint64(p.commentLengthMaxUnvetted), 10),
+		},
+		{
+			Key:   pi.SettingKeyCommentLengthMaxVetted,
+			Value: strconv.FormatUint(uint64(p.commentLengthMaxVetted), 10),
+		},
+		{
+			Key:   pi.SettingKeyCommentLockDays,
+			Value: strconv.FormatUint(uint64(p.commentLockDays), 10),
+		},
 	}
 }
 
 // New returns a new piPlugin.
-func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity) (*piPlugin, error) {
+func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity, activeNetParams *chaincfg.Params) (*piPlugin, error) {
 	// Create plugin data directory
 	dataDir = filepath.Join(dataDir, pi.PluginID)
 	err := os.MkdirAll(dataDir, 0700)
@@ -217,6 +322,14 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		billingStatusChangesMax      = pi.SettingBillingStatusChangesMax
 		summariesPageSize            = pi.SettingSummariesPageSize
 		billingStatusChangesPageSize = pi.SettingBillingStatusChangesPageSize
+		statusCacheWarm              = pi.SettingStatusCacheWarm
+		reasonRequired               = pi.SettingBillingStatusReasonRequired
+		webhookURLs                  = pi.SettingWebhookURLs
+		abandonReasonLengthMin       = pi.SettingAbandonReasonLengthMin
+		fsckRepair                   = pi.SettingFsckRepair
+		commentLengthMaxUnvetted     = pi.SettingCommentLengthMaxUnvetted
+		commentLengthMaxVetted       = pi.SettingCommentLengthMaxVetted
+		commentLockDays              = pi.SettingCommentLockDays
 	)
 
 	// Override defaults with any passed in settings
@@ -337,6 +450,73 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 			}
 			billingStatusChangesPageSize = uint32(u)
 
+		case pi.SettingKeyStatusCacheWarm:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			statusCacheWarm = b
+
+		case pi.SettingKeyBillingStatusReasonRequired:
+			err := json.Unmarshal([]byte(v.Value), &reasonRequired)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+
+		case pi.SettingKeyWebhookURLs:
+			err := json.Unmarshal([]byte(v.Value), &webhookURLs)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			err = webhookURLsVerify(webhookURLs)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v: %v",
+					v.Key, err)
+			}
+
+		case pi.SettingKeyAbandonReasonLengthMin:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			abandonReasonLengthMin = uint32(u)
+
+		case pi.SettingKeyFsckRepair:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			fsckRepair = b
+
+		case pi.SettingKeyCommentLengthMaxUnvetted:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			commentLengthMaxUnvetted = uint32(u)
+
+		case pi.SettingKeyCommentLengthMaxVetted:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			commentLengthMaxVetted = uint32(u)
+
+		case pi.SettingKeyCommentLockDays:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			commentLockDays = uint32(u)
+
 		default:
 			return nil, errors.Errorf("invalid plugin setting: %v", v.Key)
 		}
@@ -371,6 +551,44 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		domainsMap[d] = struct{}{}
 	}
 
+	// Translate the billing statuses that require a reason from their
+	// human readable names to billing status types.
+	reasonRequiredMap := make(map[pi.BillingStatusT]struct{},
+		len(reasonRequired))
+	for _, name := range reasonRequired {
+		s := billingStatusFromName(name)
+		if s == pi.BillingStatusInvalid {
+			return nil, errors.Errorf("invalid plugin setting %v: "+
+				"unknown billing status '%v'",
+				pi.SettingKeyBillingStatusReasonRequired, name)
+		}
+		reasonRequiredMap[s] = struct{}{}
+	}
+	b, err = json.Marshal(reasonRequired)
+	if err != nil {
+		return nil, err
+	}
+	reasonRequiredString := string(b)
+
+	// Setup the webhook dispatcher if any webhook URLs were provided
+	b, err = json.Marshal(webhookURLs)
+	if err != nil {
+		return nil, err
+	}
+	webhookURLsString := string(b)
+	var webhooks *webhookDispatcher
+	if len(webhookURLs) > 0 {
+		webhooks, err = newWebhookDispatcher(webhookURLs, id,
+			webhookRetryDelay)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Translate the comment lock days into blocks using the target
+	// block time of the active network.
+	blocksPerDay := uint32(24 * time.Hour / activeNetParams.TargetTimePerBlock)
+
 	return &piPlugin{
 		dataDir:                      dataDir,
 		identity:                     id,
@@ -392,9 +610,32 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		billingStatusChangesMax:      billingStatusChangesMax,
 		summariesPageSize:            summariesPageSize,
 		billingStatusChangesPageSize: billingStatusChangesPageSize,
+		statusCacheWarm:              statusCacheWarm,
+		billingStatusReasonEncoded:   reasonRequiredString,
+		billingStatusReasonRequired:  reasonRequiredMap,
+		webhookURLsEncoded:           webhookURLsString,
+		abandonReasonLengthMin:       abandonReasonLengthMin,
+		fsckRepair:                   fsckRepair,
+		commentLengthMaxUnvetted:     commentLengthMaxUnvetted,
+		commentLengthMaxVetted:       commentLengthMaxVetted,
+		commentLockDays:              commentLockDays,
+		blocksPerDay:                 blocksPerDay,
+		webhooks:                     webhooks,
 		statuses: proposalStatuses{
 			data:    make(map[string]*statusEntry, statusesCacheLimit),
 			entries: list.New(),
 		},
 	}, nil
 }
+
+// billingStatusFromName returns the billing status that corresponds to the
+// provided human readable billing status name. BillingStatusInvalid is
+// returned if the name does not correspond to a valid billing status.
+func billingStatusFromName(name string) pi.BillingStatusT {
+	for k, v := range pi.BillingStatuses {
+		if k != pi.BillingStatusInvalid && v == name {
+			return k
+		}
+	}
+	return pi.BillingStatusInvalid
+}
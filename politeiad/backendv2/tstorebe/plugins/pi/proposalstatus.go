@@ -6,6 +6,9 @@ package pi
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/plugins/pi"
@@ -114,11 +117,14 @@ determineStatus:
 	propStatus, err = proposalStatus(recordState, recordStatus, voteStatus,
 		voteMetadata, billingStatuses)
 	if err != nil {
-		return "", nil
+		propStatus, err = p.proposalStatusLegacy(token, err)
+		if err != nil {
+			return "", err
+		}
 	}
 
 	// Cache the results
-	p.statuses.set(tokenStr, statusEntry{
+	prevStatus := p.statuses.set(tokenStr, statusEntry{
 		propStatus:           propStatus,
 		recordState:          recordState,
 		recordStatus:         recordStatus,
@@ -127,16 +133,202 @@ determineStatus:
 		billingStatusesCount: len(billingStatuses),
 	})
 
+	// Check for a status transition if the proposal status has changed
+	// since it was last cached or if the proposal was not in the cache.
+	if prevStatus != propStatus {
+		p.proposalStatusChangeCheck(token, propStatus)
+	}
+
 	return propStatus, nil
 }
 
+const (
+	// statusLastKey is the key-value store key for the last observed
+	// status of a proposal. The "{token}" is replaced with the full
+	// length record token.
+	statusLastKey = "propstatus-{token}"
+)
+
+// proposalStatusChangeCheck compares the provided proposal status against the
+// last observed status of the proposal, which is persisted to the key-value
+// store, and emits a status change event if the status has changed. The last
+// observed status is persisted so that transitions are not lost when the
+// proposal is not in the memory cache, e.g. after a restart or a cache
+// eviction. No event is emitted the first time a proposal status is observed.
+//
+// Errors are logged instead of being returned since the status change check
+// must not cause the status lookup to fail.
+func (p *piPlugin) proposalStatusChangeCheck(token []byte, status pi.PropStatusT) {
+	p.statusChangeMtx.Lock()
+	defer p.statusChangeMtx.Unlock()
+
+	key := strings.Replace(statusLastKey, "{token}",
+		hex.EncodeToString(token), 1)
+	blobs, err := p.tstore.CacheGet([]string{key})
+	if err != nil {
+		log.Errorf("proposalStatusChangeCheck %x: CacheGet: %v", token, err)
+		return
+	}
+	prevStatus := pi.PropStatusT(blobs[key])
+	if prevStatus == status {
+		// Nothing has changed
+		return
+	}
+	err = p.tstore.CachePut(map[string][]byte{key: []byte(status)}, false)
+	if err != nil {
+		log.Errorf("proposalStatusChangeCheck %x: CachePut: %v", token, err)
+		return
+	}
+	if prevStatus == "" {
+		// This is the first time that the status has been observed
+		return
+	}
+
+	p.proposalStatusChangeEmit(token, prevStatus, status)
+}
+
+// proposalStatusChangeEmit emits a pi plugin event that notifies the other
+// plugins of a proposal status transition. A webhook is also dispatched if
+// webhooks have been configured.
+func (p *piPlugin) proposalStatusChangeEmit(token []byte, prevStatus, status pi.PropStatusT) {
+	psc := pi.ProposalStatusChange{
+		Token:      hex.EncodeToString(token),
+		PrevStatus: prevStatus,
+		Status:     status,
+		Timestamp:  time.Now().Unix(),
+	}
+	b, err := json.Marshal(psc)
+	if err != nil {
+		log.Errorf("proposalStatusChangeEmit %x: %v", token, err)
+		return
+	}
+
+	log.Debugf("Proposal status changed %x from %v to %v",
+		token, prevStatus, status)
+
+	p.tstore.PluginEvent(token, pi.EventProposalStatusChange, string(b))
+
+	// Notify any external services that have registered a webhook
+	if p.webhooks != nil {
+		p.webhooks.dispatch(psc)
+	}
+}
+
+// statusesCacheWarmPageSize is the number of tokens that are requested from
+// the vetted inventory at a time when warming the proposal statuses cache.
+const statusesCacheWarmPageSize = 50
+
+// statusesCacheWarm walks the vetted inventory, from newest to oldest, and
+// caches the status of every proposal that has reached a final status, e.g.
+// approved, rejected, abandoned or censored. Proposals that have not reached
+// a final status are not cached by the warm up since their cache entries are
+// refreshed on every status lookup anyway. The walk stops once the cache
+// limit has been reached by finished proposals.
+//
+// This function is run in the background during plugin setup. The ticketvote
+// plugin must be registered, but it may not have finished its setup yet, so
+// failing to determine the status of a proposal is not fatal. The error is
+// logged and the status will be determined lazily at runtime instead.
+func (p *piPlugin) statusesCacheWarm() error {
+	// The vote summary is required to determine the
+	// status of a vetted proposal.
+	var ticketvoteFound bool
+	for _, v := range p.backend.PluginInventory() {
+		if v.ID == ticketvote.PluginID {
+			ticketvoteFound = true
+		}
+	}
+	if !ticketvoteFound {
+		return errors.Errorf("%v plugin dependency not registered",
+			ticketvote.PluginID)
+	}
+
+	log.Infof("Warming proposal statuses cache")
+
+	var (
+		page    uint32 = 1
+		cached  int
+		started = time.Now()
+	)
+	for cached < statusesCacheLimit {
+		tokens, err := p.backend.InventoryOrdered(backend.StateVetted,
+			statusesCacheWarmPageSize, page)
+		if err != nil {
+			return err
+		}
+		for _, v := range tokens {
+			if cached >= statusesCacheLimit {
+				break
+			}
+			token, err := tokenDecode(v)
+			if err != nil {
+				return err
+			}
+			tokenStr := hex.EncodeToString(token)
+			inCache := p.statuses.get(tokenStr) != nil
+			s, err := p.getProposalStatus(token)
+			if err != nil {
+				log.Errorf("statusesCacheWarm %v: %v", v, err)
+				continue
+			}
+			if !statusIsFinal(s) {
+				// Don't let an unfinished proposal take up a cache
+				// entry. Entries that were added at runtime are kept
+				// so that status transitions can still be detected.
+				if !inCache {
+					p.statuses.del(tokenStr)
+				}
+				continue
+			}
+			cached++
+		}
+		if len(tokens) < statusesCacheWarmPageSize {
+			// We've reached the end of the inventory
+			break
+		}
+		page++
+	}
+
+	log.Infof("Proposal statuses cache warmed with %v finished proposals "+
+		"in %v", cached, time.Since(started))
+
+	return nil
+}
+
+// proposalStatusLegacy returns the fallback proposal status for a proposal
+// whose status could not be determined from the record data. Legacy proposals
+// that were imported from the git backend may be missing plugin data that the
+// proposal status derivation expects, so they are assigned the legacy
+// proposal status instead of returning an error. The provided status error is
+// returned for all other proposals.
+func (p *piPlugin) proposalStatusLegacy(token []byte, statusErr error) (pi.PropStatusT, error) {
+	r, err := p.record(backend.RecordRequest{
+		Token:     token,
+		Filenames: []string{pi.FileNameProposalMetadata},
+	})
+	if err != nil {
+		return "", err
+	}
+	pm, err := proposalMetadataDecode(r.Files)
+	if err != nil {
+		return "", err
+	}
+	if pm == nil || pm.LegacyToken == "" {
+		return "", statusErr
+	}
+
+	log.Debugf("Legacy proposal %x status: %v", token, statusErr)
+
+	return pi.PropStatusLegacy, nil
+}
+
 // statusIsFinal returns whether the proposal status is a final status and
 // cannot be changed any further.
 func statusIsFinal(s pi.PropStatusT) bool {
 	switch s {
 	case pi.PropStatusUnvettedAbandoned, pi.PropStatusUnvettedCensored,
 		pi.PropStatusAbandoned, pi.PropStatusCensored, pi.PropStatusApproved,
-		pi.PropStatusRejected:
+		pi.PropStatusRejected, pi.PropStatusLegacy:
 		return true
 	default:
 		return false
@@ -277,6 +469,11 @@ func proposalStatus(state backend.StateT, status backend.StatusT, voteStatus tic
 				return pi.PropStatusVoteStarted, nil
 			case ticketvote.VoteStatusRejected:
 				return pi.PropStatusRejected, nil
+			case ticketvote.VoteStatusFinished:
+				// A finished vote does not have an approved outcome,
+				// e.g. a multiple choice vote. The proposal was not
+				// approved for funding so it's considered rejected.
+				return pi.PropStatusRejected, nil
 			case ticketvote.VoteStatusApproved:
 				return proposalStatusApproved(voteMD, bscs)
 			}
@@ -5,10 +5,13 @@
 package pi
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"testing"
 	"time"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
 	"github.com/decred/politeia/politeiad/plugins/pi"
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 )
@@ -96,6 +99,24 @@ func TestProposalStatus(t *testing.T) {
 			nil,
 			pi.PropStatusVoteStarted,
 		},
+		{
+			"rejected",
+			backend.StateVetted,
+			backend.StatusPublic,
+			ticketvote.VoteStatusRejected,
+			nil,
+			nil,
+			pi.PropStatusRejected,
+		},
+		{
+			"multi-option vote finished",
+			backend.StateVetted,
+			backend.StatusPublic,
+			ticketvote.VoteStatusFinished,
+			nil,
+			nil,
+			pi.PropStatusRejected,
+		},
 		{
 			"approved",
 			backend.StateVetted,
@@ -175,3 +196,387 @@ func TestProposalStatus(t *testing.T) {
 		})
 	}
 }
+
+// eventsTstoreClient is a plugins TstoreClient that records the plugin events
+// that are emitted and that provides an in-memory key-value cache. All other
+// TstoreClient methods panic.
+type eventsTstoreClient struct {
+	plugins.TstoreClient
+	events []plugins.HookPluginEvent
+	cache  map[string][]byte
+}
+
+// CachePut saves the provided blobs to the in-memory cache.
+func (c *eventsTstoreClient) CachePut(blobs map[string][]byte, encrypt bool) error {
+	if c.cache == nil {
+		c.cache = make(map[string][]byte, len(blobs))
+	}
+	for k, v := range blobs {
+		c.cache[k] = v
+	}
+	return nil
+}
+
+// CacheGet returns the blobs for the provided keys from the in-memory cache.
+func (c *eventsTstoreClient) CacheGet(keys []string) (map[string][]byte, error) {
+	blobs := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		if b, ok := c.cache[k]; ok {
+			blobs[k] = b
+		}
+	}
+	return blobs, nil
+}
+
+// PluginEvent records the emitted plugin event.
+func (c *eventsTstoreClient) PluginEvent(token []byte, event, payload string) {
+	c.events = append(c.events, plugins.HookPluginEvent{
+		PluginID: pi.PluginID,
+		Event:    event,
+		Token:    token,
+		Payload:  payload,
+	})
+}
+
+func TestProposalStatusChangeEmit(t *testing.T) {
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	c := &eventsTstoreClient{}
+	p.tstore = c
+
+	token := []byte{0x45, 0x15, 0x4f, 0xb4, 0x56, 0x64, 0x71, 0x4a}
+	p.proposalStatusChangeEmit(token, pi.PropStatusActive,
+		pi.PropStatusCompleted)
+
+	if len(c.events) != 1 {
+		t.Fatalf("got %v events, want 1", len(c.events))
+	}
+	e := c.events[0]
+	if e.Event != pi.EventProposalStatusChange {
+		t.Errorf("got event %v, want %v", e.Event, pi.EventProposalStatusChange)
+	}
+	var psc pi.ProposalStatusChange
+	err := json.Unmarshal([]byte(e.Payload), &psc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if psc.Token != "45154fb45664714a" {
+		t.Errorf("got token %v, want 45154fb45664714a", psc.Token)
+	}
+	if psc.PrevStatus != pi.PropStatusActive ||
+		psc.Status != pi.PropStatusCompleted {
+		t.Errorf("got transition %v to %v, want %v to %v", psc.PrevStatus,
+			psc.Status, pi.PropStatusActive, pi.PropStatusCompleted)
+	}
+}
+
+func TestProposalStatusChangeCheck(t *testing.T) {
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	c := &eventsTstoreClient{}
+	p.tstore = c
+	token := []byte{0x45, 0x15, 0x4f, 0xb4, 0x56, 0x64, 0x71, 0x4a}
+
+	// The first observed status does not emit an event
+	p.proposalStatusChangeCheck(token, pi.PropStatusUnderReview)
+	if len(c.events) != 0 {
+		t.Fatalf("got %v events, want 0", len(c.events))
+	}
+
+	// An unchanged status does not emit an event
+	p.proposalStatusChangeCheck(token, pi.PropStatusUnderReview)
+	if len(c.events) != 0 {
+		t.Fatalf("got %v events, want 0", len(c.events))
+	}
+
+	// A plugin that has lost its memory cache, e.g. due to a restart,
+	// still detects the transition using the persisted status.
+	p, cleanup2 := newTestPiPlugin(t)
+	defer cleanup2()
+	p.tstore = c
+	p.proposalStatusChangeCheck(token, pi.PropStatusActive)
+	if len(c.events) != 1 {
+		t.Fatalf("got %v events, want 1", len(c.events))
+	}
+	var psc pi.ProposalStatusChange
+	err := json.Unmarshal([]byte(c.events[0].Payload), &psc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if psc.PrevStatus != pi.PropStatusUnderReview ||
+		psc.Status != pi.PropStatusActive {
+		t.Errorf("got transition %v to %v, want %v to %v", psc.PrevStatus,
+			psc.Status, pi.PropStatusUnderReview, pi.PropStatusActive)
+	}
+}
+
+// inventoryBackend is a backend that returns a fixed vetted inventory. Only
+// the methods required to determine a proposal status are implemented. All
+// other Backend methods panic.
+type inventoryBackend struct {
+	backend.Backend
+	tokens       []string
+	statuses     map[string]backend.StatusT
+	voteStatuses map[string]ticketvote.VoteStatusT
+	files        map[string][]backend.File
+}
+
+// PluginInventory returns the ticketvote plugin.
+func (b *inventoryBackend) PluginInventory() []backend.Plugin {
+	return []backend.Plugin{{ID: ticketvote.PluginID}}
+}
+
+// InventoryOrdered returns the requested page of vetted tokens.
+func (b *inventoryBackend) InventoryOrdered(s backend.StateT, pageSize, pageNumber uint32) ([]string, error) {
+	start := int((pageNumber - 1) * pageSize)
+	if start >= len(b.tokens) {
+		return []string{}, nil
+	}
+	end := start + int(pageSize)
+	if end > len(b.tokens) {
+		end = len(b.tokens)
+	}
+	return b.tokens[start:end], nil
+}
+
+// Records returns abridged vetted records.
+func (b *inventoryBackend) Records(reqs []backend.RecordRequest) (map[string]backend.Record, error) {
+	records := make(map[string]backend.Record, len(reqs))
+	for _, v := range reqs {
+		token := hex.EncodeToString(v.Token)
+		records[token] = backend.Record{
+			RecordMetadata: backend.RecordMetadata{
+				Token:  token,
+				State:  backend.StateVetted,
+				Status: b.statuses[token],
+			},
+			Files: b.files[token],
+		}
+	}
+	return records, nil
+}
+
+// PluginRead returns the ticketvote summary for a record.
+func (b *inventoryBackend) PluginRead(token []byte, pluginID, cmd, payload string) (string, error) {
+	sr := ticketvote.SummaryReply{
+		Status: b.voteStatuses[hex.EncodeToString(token)],
+	}
+	reply, err := json.Marshal(sr)
+	if err != nil {
+		return "", err
+	}
+	return string(reply), nil
+}
+
+func TestStatusesCacheWarm(t *testing.T) {
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+	p.tstore = &eventsTstoreClient{}
+
+	var (
+		review   = "45154fb45664714a"
+		censored = "45154fb45664714b"
+		archived = "45154fb45664714c"
+		rejected = "45154fb45664714d"
+		oldest   = "45154fb45664714e"
+	)
+	p.backend = &inventoryBackend{
+		tokens: []string{review, censored, archived, rejected, oldest},
+		statuses: map[string]backend.StatusT{
+			review:   backend.StatusPublic,
+			censored: backend.StatusCensored,
+			archived: backend.StatusArchived,
+			rejected: backend.StatusPublic,
+			oldest:   backend.StatusCensored,
+		},
+		voteStatuses: map[string]ticketvote.VoteStatusT{
+			review:   ticketvote.VoteStatusUnauthorized,
+			censored: ticketvote.VoteStatusIneligible,
+			archived: ticketvote.VoteStatusIneligible,
+			rejected: ticketvote.VoteStatusRejected,
+			oldest:   ticketvote.VoteStatusIneligible,
+		},
+	}
+
+	// Limit the cache size so that the walk stops before the end of
+	// the inventory. Unfinished proposals are not counted against the
+	// limit.
+	defaultCacheLimit := statusesCacheLimit
+	statusesCacheLimit = 3
+	defer func() {
+		statusesCacheLimit = defaultCacheLimit
+	}()
+
+	err := p.statusesCacheWarm()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]pi.PropStatusT{
+		censored: pi.PropStatusCensored,
+		archived: pi.PropStatusAbandoned,
+		rejected: pi.PropStatusRejected,
+	}
+	for token, status := range want {
+		e := p.statuses.get(token)
+		if e == nil {
+			t.Errorf("%v: entry not cached", token)
+			continue
+		}
+		if e.propStatus != status {
+			t.Errorf("%v: got status %v, want %v", token, e.propStatus, status)
+		}
+	}
+	if e := p.statuses.get(review); e != nil {
+		t.Errorf("%v: got unfinished proposal cached", review)
+	}
+	if e := p.statuses.get(oldest); e != nil {
+		t.Errorf("%v: got entry cached past the cache limit", oldest)
+	}
+}
+
+func TestProposalStatusLegacy(t *testing.T) {
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+	p.tstore = &eventsTstoreClient{}
+
+	// The ticketvote plugin does not return a vote status that the
+	// proposal status derivation can handle for either proposal.
+	var (
+		legacy = "45154fb45664714a"
+		normal = "45154fb45664714b"
+	)
+	p.backend = &inventoryBackend{
+		tokens: []string{legacy, normal},
+		statuses: map[string]backend.StatusT{
+			legacy: backend.StatusPublic,
+			normal: backend.StatusPublic,
+		},
+		voteStatuses: map[string]ticketvote.VoteStatusT{
+			legacy: ticketvote.VoteStatusIneligible,
+			normal: ticketvote.VoteStatusIneligible,
+		},
+		files: map[string][]backend.File{
+			legacy: {
+				fileProposalMetadata(t, &pi.ProposalMetadata{
+					Name:        "legacy",
+					LegacyToken: "d1ff1f3e1e3ab8f6d7d9c8d1a9f3bcbc28d0f2bfb0c1df5e8e5f1d0b6e4d4e5f",
+				}),
+			},
+			normal: {
+				fileProposalMetadata(t, &pi.ProposalMetadata{
+					Name: "normal",
+				}),
+			},
+		},
+	}
+
+	// A legacy proposal falls back to the legacy status
+	token, err := tokenDecode(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.getProposalStatus(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != pi.PropStatusLegacy {
+		t.Errorf("got status %v, want %v", s, pi.PropStatusLegacy)
+	}
+
+	// The legacy status is final and is cached
+	e := p.statuses.get(legacy)
+	if e == nil || e.propStatus != pi.PropStatusLegacy {
+		t.Errorf("legacy status not cached")
+	}
+
+	// Other proposals still return an error
+	token, err = tokenDecode(normal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.getProposalStatus(token)
+	if err == nil {
+		t.Errorf("got nil error, want error")
+	}
+}
+
+func TestHookPluginEventVoteEnded(t *testing.T) {
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	token := "45154fb45664714a"
+	c := &eventsTstoreClient{}
+	p.tstore = c
+	p.backend = &inventoryBackend{
+		statuses: map[string]backend.StatusT{
+			token: backend.StatusPublic,
+		},
+		voteStatuses: map[string]ticketvote.VoteStatusT{
+			token: ticketvote.VoteStatusRejected,
+		},
+	}
+	p.statuses.set(token, statusEntry{
+		propStatus:   pi.PropStatusVoteStarted,
+		recordState:  backend.StateVetted,
+		recordStatus: backend.StatusPublic,
+		voteStatus:   ticketvote.VoteStatusStarted,
+	})
+	tokenb, err := tokenDecode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.proposalStatusChangeCheck(tokenb, pi.PropStatusVoteStarted)
+
+	hookPayload := func(pluginID, event string) string {
+		b, err := json.Marshal(ticketvote.VoteEnded{
+			Token: token,
+			Summary: ticketvote.SummaryReply{
+				Status: ticketvote.VoteStatusRejected,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err = json.Marshal(plugins.HookPluginEvent{
+			PluginID: pluginID,
+			Event:    event,
+			Payload:  string(b),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	// Events from other plugins are ignored
+	err = p.hookPluginEvent(hookPayload(pi.PluginID,
+		pi.EventProposalStatusChange))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.events) != 0 {
+		t.Fatalf("got %v events, want 0", len(c.events))
+	}
+
+	// The vote ended event updates the cached proposal status
+	err = p.hookPluginEvent(hookPayload(ticketvote.PluginID,
+		ticketvote.EventVoteEnded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := p.statuses.get(token)
+	if e == nil || e.propStatus != pi.PropStatusRejected {
+		t.Fatalf("got cache entry %+v, want status %v", e,
+			pi.PropStatusRejected)
+	}
+	if len(c.events) != 1 {
+		t.Fatalf("got %v events, want 1", len(c.events))
+	}
+	if c.events[0].Event != pi.EventProposalStatusChange {
+		t.Errorf("got event %v, want %v", c.events[0].Event,
+			pi.EventProposalStatusChange)
+	}
+}
@@ -67,7 +67,10 @@ func (s *proposalStatuses) get(token string) *statusEntry {
 // set stores the given entry in cache, if a cache entry associated with the
 // token already exists it overwrites the old entry. If the cache is full and
 // a new entry is being added, the oldest entry is removed from the cache.
-func (s *proposalStatuses) set(token string, entry statusEntry) {
+//
+// The proposal status of the entry that was overwritten is returned. An empty
+// string is returned if the cache did not contain an entry for the token.
+func (s *proposalStatuses) set(token string, entry statusEntry) pi.PropStatusT {
 	s.Lock()
 	defer s.Unlock()
 
@@ -77,12 +80,12 @@ func (s *proposalStatuses) set(token string, entry statusEntry) {
 		if e.propStatus == entry.propStatus {
 			// Entry exists, but has not changed. No
 			// need to overwrite the existing entry.
-			return
+			return e.propStatus
 		}
 		s.data[token] = &entry
 		log.Debugf("proposalStatuses: updated entry %v from %v to %v",
 			token, e.propStatus, entry.propStatus)
-		return
+		return e.propStatus
 	}
 
 	// If entry does not exist and cache is full, then remove oldest entry
@@ -99,4 +102,24 @@ func (s *proposalStatuses) set(token string, entry statusEntry) {
 	s.data[token] = &entry
 	log.Debugf("proposalStatuses: added entry %v with status %v",
 		token, entry.propStatus)
+
+	return ""
+}
+
+// del removes the entry associated with the given token from the cache.
+func (s *proposalStatuses) del(token string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.data[token]; !ok {
+		return
+	}
+	delete(s.data, token)
+	for e := s.entries.Front(); e != nil; e = e.Next() {
+		if e.Value.(string) == token {
+			s.entries.Remove(e)
+			break
+		}
+	}
+	log.Debugf("proposalStatuses: removed entry %v", token)
 }
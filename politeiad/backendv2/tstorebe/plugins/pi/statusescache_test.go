@@ -90,7 +90,10 @@ func TestSet(t *testing.T) {
 		recordStatus: backend.StatusPublic,
 		voteStatus:   ticketvote.VoteStatusApproved,
 	}
-	statuses.set(tokenThird, entryThird)
+	prev := statuses.set(tokenThird, entryThird)
+	if prev != "" {
+		t.Errorf("unexpected previous status for new entry: %v", prev)
+	}
 
 	// Ensure that the oldest entry was removed, and that the other two entries
 	// exist is cache.
@@ -121,7 +124,10 @@ func TestSet(t *testing.T) {
 
 	// Overwrite existing cache entry.
 	entryThird.propStatus = pi.PropStatusActive
-	statuses.set(tokenThird, entryThird)
+	prev = statuses.set(tokenThird, entryThird)
+	if prev != pi.PropStatusClosed {
+		t.Errorf("want previous status %v, got %v", pi.PropStatusClosed, prev)
+	}
 
 	// Ensure that the new entry was stored in cache successfully
 	e = statuses.data[tokenThird]
@@ -74,6 +74,10 @@ func newTestPiPlugin(t *testing.T) (*piPlugin, func()) {
 		proposalDomainsEncoded:  domainsString,
 		proposalDomains:         domainsMap,
 		billingStatusChangesMax: pi.SettingBillingStatusChangesMax,
+		abandonReasonLengthMin:  pi.SettingAbandonReasonLengthMin,
+		billingStatusReasonRequired: map[pi.BillingStatusT]struct{}{
+			pi.BillingStatusClosed: {},
+		},
 		statuses: proposalStatuses{
 			data:    make(map[string]*statusEntry, statusesCacheLimit),
 			entries: list.New(),
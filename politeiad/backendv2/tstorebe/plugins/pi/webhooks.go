@@ -0,0 +1,140 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// webhookTimeout is the timeout of a webhook delivery, including
+	// any retries.
+	webhookTimeout = 45 * time.Second
+
+	// webhookRetries is the number of times that a failed webhook
+	// delivery is retried before the webhook is dropped.
+	webhookRetries = 2
+
+	// webhookRetryDelay is the delay between webhook delivery attempts.
+	// The delay is multiplied by the number of failed attempts.
+	webhookRetryDelay = 5 * time.Second
+)
+
+// webhookDispatcher POSTs signed webhooks to the configured webhook URLs when
+// a proposal transitions to one of the pi WebhookStatuses.
+type webhookDispatcher struct {
+	urls     []string
+	identity *identity.FullIdentity
+	client   *http.Client
+}
+
+// newWebhookDispatcher returns a new webhookDispatcher. Failed deliveries are
+// retried using the provided retry delay.
+func newWebhookDispatcher(urls []string, id *identity.FullIdentity, retryDelay time.Duration) (*webhookDispatcher, error) {
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
+		Timeout:    webhookTimeout,
+		Retries:    webhookRetries,
+		RetryDelay: retryDelay,
+		OnAttempt: func(a util.HTTPAttempt) {
+			if a.Retry {
+				log.Errorf("Webhook delivery failed, retrying: %v", a)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &webhookDispatcher{
+		urls:     urls,
+		identity: id,
+		client:   client,
+	}, nil
+}
+
+// dispatch sends a webhook for the provided proposal status change to all of
+// the webhook URLs. The webhooks are sent in the background. Status changes
+// that are not one of the pi WebhookStatuses are ignored.
+func (d *webhookDispatcher) dispatch(psc pi.ProposalStatusChange) {
+	if _, ok := pi.WebhookStatuses[psc.Status]; !ok {
+		return
+	}
+
+	b, err := json.Marshal(pi.Webhook{
+		Event:                pi.EventProposalStatusChange,
+		ProposalStatusChange: psc,
+	})
+	if err != nil {
+		log.Errorf("webhook dispatch %v: %v", psc.Token, err)
+		return
+	}
+	sig := d.identity.SignMessage(b)
+	signature := hex.EncodeToString(sig[:])
+
+	for _, v := range d.urls {
+		go d.deliver(v, b, signature)
+	}
+}
+
+// deliver delivers a webhook to the provided URL. Deliveries that fail due to
+// a network error or a 5xx response are retried by the http client. The
+// webhook is dropped if the delivery still fails.
+func (d *webhookDispatcher) deliver(url string, body []byte, signature string) {
+	err := d.post(url, body, signature)
+	if err != nil {
+		log.Errorf("Webhook to %v dropped: %v: %s", url, err, body)
+		return
+	}
+	log.Debugf("Webhook delivered to %v", url)
+}
+
+// post sends a single webhook request. An error is returned if the request
+// fails or if the response status code is not a 2xx status code.
+func (d *webhookDispatcher) post(url string, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(pi.WebhookHeaderEvent, pi.EventProposalStatusChange)
+	req.Header.Set(pi.WebhookHeaderSignature, signature)
+
+	r, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	r.Body.Close()
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return errors.Errorf("unexpected response status %v", r.Status)
+	}
+
+	return nil
+}
+
+// webhookURLsVerify verifies that the provided webhook URLs are absolute HTTP
+// or HTTPS URLs.
+func webhookURLsVerify(urls []string) error {
+	for _, v := range urls {
+		u, err := url.Parse(v)
+		if err != nil {
+			return errors.Errorf("invalid webhook url '%v': %v", v, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("invalid webhook url '%v': must be an "+
+				"absolute http or https url", v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+)
+
+func TestWebhookDispatch(t *testing.T) {
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup a webhook server that fails the first request
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	var (
+		requests = make(chan request, 10)
+		failed   bool
+	)
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if !failed {
+				failed = true
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			requests <- request{header: r.Header, body: b}
+		}))
+	defer s.Close()
+
+	d, err := newWebhookDispatcher([]string{s.URL}, id, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A status that does not trigger a webhook should be ignored
+	d.dispatch(pi.ProposalStatusChange{
+		Token:      "token",
+		PrevStatus: pi.PropStatusUnderReview,
+		Status:     pi.PropStatusVoteAuthorized,
+	})
+
+	// Dispatch a webhook for a rejected proposal
+	psc := pi.ProposalStatusChange{
+		Token:      "token",
+		PrevStatus: pi.PropStatusVoteStarted,
+		Status:     pi.PropStatusRejected,
+		Timestamp:  time.Now().Unix(),
+	}
+	d.dispatch(psc)
+
+	var r request
+	select {
+	case r = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	// Verify the webhook
+	if r.header.Get(pi.WebhookHeaderEvent) != pi.EventProposalStatusChange {
+		t.Errorf("got event %v, want %v", r.header.Get(pi.WebhookHeaderEvent),
+			pi.EventProposalStatusChange)
+	}
+	sig, err := identity.SignatureFromString(
+		r.header.Get(pi.WebhookHeaderSignature))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !id.Public.VerifyMessage(r.body, *sig) {
+		t.Errorf("invalid webhook signature")
+	}
+	var wh pi.Webhook
+	err = json.Unmarshal(r.body, &wh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wh.ProposalStatusChange != psc {
+		t.Errorf("got status change %+v, want %+v",
+			wh.ProposalStatusChange, psc)
+	}
+
+	// Verify that no other webhooks were delivered
+	select {
+	case r = <-requests:
+		t.Fatalf("unexpected webhook %s", r.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookURLsVerify(t *testing.T) {
+	var tests = []struct {
+		name    string
+		urls    []string
+		wantErr bool
+	}{
+		{"no urls", []string{}, false},
+		{"valid", []string{"https://example.com/hook", "http://x:8080"}, false},
+		{"relative", []string{"/hook"}, true},
+		{"invalid scheme", []string{"ftp://example.com"}, true},
+		{"no host", []string{"https://"}, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := webhookURLsVerify(v.urls)
+			switch {
+			case v.wantErr && err == nil:
+				t.Errorf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
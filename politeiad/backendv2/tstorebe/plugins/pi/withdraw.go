@@ -0,0 +1,247 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// dataDescriptorWithdrawal is the blob entry data descriptor of a
+	// proposal withdrawal.
+	dataDescriptorWithdrawal = pluginID + "-withdrawal-v1"
+)
+
+// cmdWithdraw withdraws a proposal on behalf of its author. The signed
+// withdrawal is saved to the backend. The proposal is archived and the
+// proposal credit is refunded by politeiawww once the withdrawal has been
+// saved. The record status change cannot be made from within this command
+// since the record lock is held for the duration of plugin writes.
+func (p *piPlugin) cmdWithdraw(token []byte, payload string) (string, error) {
+	// Decode payload
+	var w pi.Withdraw
+	err := json.Unmarshal([]byte(payload), &w)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify token. The full length token is required since the
+	// signature is also used for the record status change, which
+	// requires the full length token.
+	err = tokenMatches(token, w.Token)
+	if err != nil {
+		return "", err
+	}
+	if w.Token != hex.EncodeToString(token) {
+		return "", backend.PluginError{
+			PluginID:     pi.PluginID,
+			ErrorCode:    uint32(pi.ErrorCodeTokenInvalid),
+			ErrorContext: "the full length token is required",
+		}
+	}
+
+	// Verify signature
+	msg := withdrawalMsg(w.Token, w.Version, w.Reason)
+	err = util.VerifySignature(w.Signature, w.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Verify that the user is the proposal author
+	authorID, err := p.recordAuthor(token)
+	if err != nil {
+		return "", err
+	}
+	if w.UserID != authorID {
+		return "", backend.PluginError{
+			PluginID:     pi.PluginID,
+			ErrorCode:    uint32(pi.ErrorCodeWithdrawNotAllowed),
+			ErrorContext: "user is not the proposal author",
+		}
+	}
+
+	// Verify that the proposal is under review. A proposal cannot be
+	// withdrawn once its vote has been authorized.
+	propStatus, err := p.getProposalStatus(token)
+	if err != nil {
+		return "", err
+	}
+	if propStatus != pi.PropStatusUnderReview {
+		return "", backend.PluginError{
+			PluginID:  pi.PluginID,
+			ErrorCode: uint32(pi.ErrorCodeWithdrawNotAllowed),
+			ErrorContext: fmt.Sprintf("proposal status must be %v; got %v",
+				pi.PropStatusUnderReview, propStatus),
+		}
+	}
+
+	// Verify the reason. The withdrawal results in the proposal
+	// being abandoned, so it is subject to the same requirements.
+	err = p.abandonmentVerify(propStatus, w.Reason)
+	if err != nil {
+		return "", err
+	}
+
+	// Save the withdrawal
+	receipt := p.identity.SignMessage([]byte(w.Signature))
+	wd := pi.Withdrawal{
+		Token:     w.Token,
+		Version:   w.Version,
+		Reason:    w.Reason,
+		PublicKey: w.PublicKey,
+		Signature: w.Signature,
+		Timestamp: time.Now().Unix(),
+		Receipt:   hex.EncodeToString(receipt[:]),
+	}
+	err = p.withdrawalSave(token, wd)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(pi.WithdrawReply{
+		Timestamp: wd.Timestamp,
+		Receipt:   wd.Receipt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdWithdrawal returns the withdrawal of a proposal. If the withdrawal was
+// submitted multiple times, e.g. because the archival of the proposal failed
+// and the author retried, then the most recent withdrawal is returned.
+func (p *piPlugin) cmdWithdrawal(token []byte) (string, error) {
+	// Get the withdrawals
+	ws, err := p.withdrawals(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	var wr pi.WithdrawalReply
+	if len(ws) > 0 {
+		wr.Withdrawal = &ws[len(ws)-1]
+	}
+	reply, err := json.Marshal(wr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// withdrawalMsg returns the message that is signed by the author when a
+// proposal is withdrawn. This is the same message that is signed for a
+// record status change to archived.
+func withdrawalMsg(token string, version uint32, reason string) string {
+	return token + strconv.FormatUint(uint64(version), 10) +
+		strconv.FormatUint(uint64(backend.StatusArchived), 10) + reason
+}
+
+// withdrawalSave saves a Withdrawal to the backend.
+func (p *piPlugin) withdrawalSave(token []byte, w pi.Withdrawal) error {
+	// Prepare blob
+	be, err := withdrawalEncode(w)
+	if err != nil {
+		return err
+	}
+
+	// Save blob
+	return p.tstore.BlobSave(token, *be)
+}
+
+// withdrawals returns the withdrawals of a proposal. The withdrawals are
+// ordered from oldest to newest.
+func (p *piPlugin) withdrawals(token []byte) ([]pi.Withdrawal, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.BlobsByDataDesc(token,
+		[]string{dataDescriptorWithdrawal})
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode blobs
+	ws := make([]pi.Withdrawal, 0, len(blobs))
+	for _, v := range blobs {
+		w, err := withdrawalDecode(v)
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, *w)
+	}
+
+	return ws, nil
+}
+
+// withdrawalEncode encodes a Withdrawal into a BlobEntry.
+func withdrawalEncode(w pi.Withdrawal) (*store.BlobEntry, error) {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorWithdrawal,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+// withdrawalDecode decodes a BlobEntry into a Withdrawal.
+func withdrawalDecode(be store.BlobEntry) (*pi.Withdrawal, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorWithdrawal {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorWithdrawal)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var w pi.Withdrawal
+	err = json.Unmarshal(b, &w)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal Withdrawal: %v", err)
+	}
+
+	return &w, nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+)
+
+func TestCmdWithdraw(t *testing.T) {
+	// Setup pi plugin
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+
+	// Setup an identity that will be used to create the payload
+	// signatures.
+	fid, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup test data
+	var (
+		token  = "45154fb45664714b"
+		reason = "The proposal has been superseded by a new proposal."
+	)
+	tokenb, err := hex.DecodeString(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup tests
+	var tests = []struct {
+		name string // Test name
+		w    pi.Withdraw
+		err  error // Expected error output
+	}{
+		{
+			"payload token does not match cmd token",
+			withdraw(fid, pi.Withdraw{
+				Token:  "da70d0766348340c",
+				Reason: reason,
+			}),
+			pluginError(pi.ErrorCodeTokenInvalid),
+		},
+		{
+			"payload token is a prefix",
+			withdraw(fid, pi.Withdraw{
+				Token:  token[:10],
+				Reason: reason,
+			}),
+			pluginError(pi.ErrorCodeTokenInvalid),
+		},
+		{
+			"signature is wrong",
+			pi.Withdraw{
+				Token:     token,
+				Version:   1,
+				Reason:    reason,
+				PublicKey: fid.Public.String(),
+				Signature: withdraw(fid, pi.Withdraw{
+					Token:   token,
+					Version: 2,
+					Reason:  reason,
+				}).Signature,
+			},
+			pluginError(pi.ErrorCodeSignatureInvalid),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Run test
+			b, err := json.Marshal(tc.w)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.cmdWithdraw(tokenb, string(b))
+
+			// Verify the error code
+			var want, got backend.PluginError
+			if !errors.As(tc.err, &want) || !errors.As(err, &got) {
+				t.Fatalf("got err %v, want err %v", err, tc.err)
+			}
+			if got.ErrorCode != want.ErrorCode {
+				t.Fatalf("got error code %v, want %v",
+					pi.ErrorCodes[pi.ErrorCodeT(got.ErrorCode)],
+					pi.ErrorCodes[pi.ErrorCodeT(want.ErrorCode)])
+			}
+		})
+	}
+}
+
+func TestWithdrawalEncode(t *testing.T) {
+	w := pi.Withdrawal{
+		Token:     "45154fb45664714b",
+		Version:   1,
+		Reason:    "The proposal has been superseded by a new proposal.",
+		PublicKey: "publickey",
+		Signature: "signature",
+		Receipt:   "receipt",
+		Timestamp: 1646092800,
+	}
+	be, err := withdrawalEncode(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := withdrawalDecode(*be)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*got, w) {
+		t.Fatalf("got %+v, want %+v", *got, w)
+	}
+
+	// Verify that a blob with a different data descriptor is rejected
+	be, err = completionReportEncode(pi.CompletionReport{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = withdrawalDecode(*be)
+	if err == nil {
+		t.Fatal("got nil error, want data descriptor error")
+	}
+}
+
+// withdraw uses the provided arguments to return a Withdraw with a valid
+// PublicKey and Signature.
+func withdraw(fid *identity.FullIdentity, w pi.Withdraw) pi.Withdraw {
+	sig := fid.SignMessage([]byte(withdrawalMsg(w.Token, w.Version, w.Reason)))
+	w.PublicKey = fid.Public.String()
+	w.Signature = hex.EncodeToString(sig[:])
+	return w
+}
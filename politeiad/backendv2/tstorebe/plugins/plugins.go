@@ -54,8 +54,14 @@ const (
 	// HookTypePluginPost is called after a plugin command is executed.
 	HookTypePluginPost HookT = 10
 
+	// HookTypePluginEvent is called when a plugin emits a plugin event.
+	// Plugin events notify the other plugins of state changes that are
+	// not the direct result of a backend write, such as a proposal
+	// status transition.
+	HookTypePluginEvent HookT = 11
+
 	// HookTypeLast unit test only
-	HookTypeLast HookT = 11
+	HookTypeLast HookT = 12
 )
 
 var (
@@ -72,6 +78,7 @@ var (
 		HookTypeSetRecordStatusPost: "set record status post",
 		HookTypePluginPre:           "plugin pre",
 		HookTypePluginPost:          "plugin post",
+		HookTypePluginEvent:         "plugin event",
 	}
 )
 
@@ -133,6 +140,15 @@ type HookPluginPost struct {
 	Reply    string `json:"reply"`
 }
 
+// HookPluginEvent is the payload for the plugin event hook. The event payload
+// is defined by the plugin that emitted the event.
+type HookPluginEvent struct {
+	PluginID string `json:"pluginid"` // Plugin that emitted the event
+	Event    string `json:"event"`
+	Token    []byte `json:"token"`
+	Payload  string `json:"payload"`
+}
+
 // PluginClient provides an API for a tstore instance to use when interacting
 // with a plugin. All tstore plugins must implement the PluginClient interface.
 type PluginClient interface {
@@ -153,6 +169,35 @@ type PluginClient interface {
 	Settings() []backend.PluginSetting
 }
 
+// AsyncHookClient is an optional interface that a PluginClient can implement
+// in order to have post hooks executed asynchronously. Async hooks are saved
+// to a persistent work queue by tstore and executed in the background, with
+// retries, so that record writes are not blocked by slow secondary processing
+// such as cache warming, index updates, and event emission. A hook that is
+// registered as async is not passed to the plugin's Hook method. Only post
+// hooks can be executed asynchronously.
+type AsyncHookClient interface {
+	// AsyncHooks returns the hook types that the plugin wants executed
+	// asynchronously.
+	AsyncHooks() []HookT
+
+	// AsyncHook executes an async hook. Returning an error will cause
+	// the hook to be retried. Async hooks must be idempotent.
+	AsyncHook(h HookT, payload string) error
+}
+
+// IsPostHook returns whether the provided hook type is a post hook, i.e. a
+// hook that is executed after the data has already been saved.
+func IsPostHook(h HookT) bool {
+	switch h {
+	case HookTypeNewRecordPost, HookTypeEditRecordPost,
+		HookTypeEditMetadataPost, HookTypeSetRecordStatusPost,
+		HookTypePluginPost, HookTypePluginEvent:
+		return true
+	}
+	return false
+}
+
 // TstoreClient provides an API for plugins to interact with a tstore instance.
 // Plugins are allowed to save, delete, and get plugin data to/from the tstore
 // backend. Editing plugin data is not allowed.
@@ -164,6 +209,14 @@ type TstoreClient interface {
 	// blob from tstore.
 	BlobSave(token []byte, be store.BlobEntry) error
 
+	// BlobsSave saves a batch of BlobEntry to the tstore instance using
+	// a single tlog append. The returned errors correspond to the
+	// provided blob entries by index. A backend ErrDuplicatePayload is
+	// returned for each blob that already exists. The blobs of a batch
+	// are not guaranteed to be appended in the order in which they are
+	// provided.
+	BlobsSave(token []byte, entries []store.BlobEntry) ([]error, error)
+
 	// BlobsDel deletes the blobs that correspond to the provided
 	// digests.
 	BlobsDel(token []byte, digests [][]byte) error
@@ -216,6 +269,11 @@ type TstoreClient interface {
 	// RecordState returns whether the record is unvetted or vetted.
 	RecordState(token []byte) (backend.StateT, error)
 
+	// RecordFrozen returns whether the record has been frozen. Plugin
+	// data cannot be appended to a frozen record, so any plugin data
+	// that has been saved to a frozen record is final.
+	RecordFrozen(token []byte) (bool, error)
+
 	// CachePut saves the provided key-value pairs to the key-value store. It
 	// prefixes the keys with the plugin ID in order to limit the access of the
 	// plugins only to the data they own.
@@ -232,4 +290,10 @@ type TstoreClient interface {
 	// was returned for all provided keys. It prefixes the keys with the plugin
 	// ID in order to limit the access of the plugins only to the data they own.
 	CacheGet(keys []string) (map[string][]byte, error)
+
+	// PluginEvent emits a plugin event. The event is passed to all
+	// registered plugins using the plugin event hook. Plugin events are
+	// emitted after the state change has occurred, so errors returned by
+	// the event hooks are logged and do not propagate to the caller.
+	PluginEvent(token []byte, event, payload string)
 }
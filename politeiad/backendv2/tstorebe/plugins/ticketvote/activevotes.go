@@ -6,9 +6,12 @@ package ticketvote
 
 import (
 	"encoding/hex"
+	"encoding/json"
+	"sort"
 	"sync"
 
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/pkg/errors"
 )
 
 // activeVotes provides a memory cache for data that is required to validate
@@ -32,11 +35,14 @@ type activeVotes struct {
 // activeVote caches the data required to validate vote ballots for a record
 // with an active voting period.
 //
-// A active vote with 41k tickets will cache a maximum of 10.5 MB of data.
-// This includes a 3 MB vote details, 4.5 MB commitment addresses map, and a
-// potential 3 MB cast votes map if all 41k votes are cast.
+// A active vote with 41k tickets will cache a maximum of 8.8 MB of data.
+// This includes a 1.3 MB eligible ticket snapshot, 4.5 MB commitment
+// addresses map, and a potential 3 MB cast votes map if all 41k votes are
+// cast. The cached vote details do not include the eligible tickets. They
+// are cached as a snapshot instead.
 type activeVote struct {
 	Details   *ticketvote.VoteDetails
+	Eligible  ticketSnapshot
 	CastVotes map[string]string // [ticket]voteBit
 
 	// Addrs contains the largest commitment address for each eligble
@@ -52,11 +58,17 @@ type activeVote struct {
 	// during creation (ex. network errors). If the initial job fails
 	// to complete it will not be retried.
 	Addrs map[string]string // [ticket]address
+
+	// Stakes contains the stake in atoms of each eligible ticket. It is
+	// populated alongside the commitment addresses and is only used by
+	// votes that use VoteWeightStake.
+	Stakes map[string]uint64 // [ticket]stake
 }
 
 // VoteDetails returns the vote details from the active votes cache for the
-// provided token. If the token does not correspond to an active vote then nil
-// is returned.
+// provided token. The eligible tickets are not included in the returned vote
+// details. Use Snapshot to check ticket eligibility. If the token does not
+// correspond to an active vote then nil is returned.
 func (a *activeVotes) VoteDetails(token []byte) *ticketvote.VoteDetails {
 	t := hex.EncodeToString(token)
 
@@ -69,9 +81,6 @@ func (a *activeVotes) VoteDetails(token []byte) *ticketvote.VoteDetails {
 	}
 
 	// Return a copy of the vote details
-	eligible := make([]string, len(av.Details.EligibleTickets))
-	copy(eligible, av.Details.EligibleTickets)
-
 	options := make([]ticketvote.VoteOption, len(av.Details.Params.Options))
 	copy(options, av.Details.Params.Options)
 
@@ -86,20 +95,23 @@ func (a *activeVotes) VoteDetails(token []byte) *ticketvote.VoteDetails {
 			PassPercentage:   av.Details.Params.PassPercentage,
 			Options:          options,
 			Parent:           av.Details.Params.Parent,
+			WinCondition:     av.Details.Params.WinCondition,
+			Winners:          av.Details.Params.Winners,
+			Weight:           av.Details.Params.Weight,
 		},
 		PublicKey:        av.Details.PublicKey,
 		Signature:        av.Details.Signature,
 		StartBlockHeight: av.Details.StartBlockHeight,
 		StartBlockHash:   av.Details.StartBlockHash,
 		EndBlockHeight:   av.Details.EndBlockHeight,
-		EligibleTickets:  eligible,
 	}
 }
 
-// EligibleTickets returns the eligible tickets from the active votes cache for
-// the provided token. If the token does not correspond to an active vote then
-// nil is returned.
-func (a *activeVotes) EligibleTickets(token []byte) map[string]struct{} {
+// Snapshot returns the eligible ticket snapshot from the active votes cache
+// for the provided token. The snapshot is immutable and can be used without
+// holding the cache lock. If the token does not correspond to an active vote
+// then nil is returned.
+func (a *activeVotes) Snapshot(token []byte) ticketSnapshot {
 	t := hex.EncodeToString(token)
 
 	a.RLock()
@@ -110,13 +122,7 @@ func (a *activeVotes) EligibleTickets(token []byte) map[string]struct{} {
 		return nil
 	}
 
-	// Return a map of the eligible tickets
-	eligible := make(map[string]struct{}, len(av.Details.EligibleTickets))
-	for _, v := range av.Details.EligibleTickets {
-		eligible[v] = struct{}{}
-	}
-
-	return eligible
+	return av.Eligible
 }
 
 // VoteIsDuplicate returns whether the vote has already been cast. The first
@@ -162,7 +168,8 @@ func (a *activeVotes) CommitmentAddrs(token []byte, tickets []string) map[string
 		addr, ok := av.Addrs[v]
 		if ok {
 			ca[v] = commitmentAddr{
-				addr: addr,
+				addr:  addr,
+				stake: av.Stakes[v],
 			}
 		}
 	}
@@ -189,6 +196,63 @@ func (a *activeVotes) Tally(token string) map[string]uint32 {
 	return tally
 }
 
+// AllVoted returns whether all of the eligible tickets of an active vote have
+// cast a vote. False is returned if the requested token is not in the active
+// votes cache.
+func (a *activeVotes) AllVoted(token string) bool {
+	a.RLock()
+	defer a.RUnlock()
+
+	av, ok := a.activeVotes[token]
+	if !ok || len(av.Eligible) == 0 {
+		return false
+	}
+	return len(av.CastVotes) >= len(av.Eligible)
+}
+
+// StakeTally returns the total stake of the cast votes for each vote option
+// in an active vote. The returned map is a map[votebit]stake. False is
+// returned if the requested token is not in the active votes cache or if the
+// stake of a cast vote has not been cached.
+func (a *activeVotes) StakeTally(token string) (map[string]uint64, bool) {
+	tally := make(map[string]uint64, 16)
+
+	a.RLock()
+	defer a.RUnlock()
+
+	av, ok := a.activeVotes[token]
+	if !ok {
+		return nil, false
+	}
+	for ticket, votebit := range av.CastVotes {
+		stake, ok := av.Stakes[ticket]
+		if !ok {
+			return nil, false
+		}
+		tally[votebit] += stake
+	}
+	return tally, true
+}
+
+// EligibleStake returns the total stake of the eligible tickets of an active
+// vote. False is returned if the requested token is not in the active votes
+// cache or if the stakes of the eligible tickets have not been fully cached
+// yet.
+func (a *activeVotes) EligibleStake(token string) (uint64, bool) {
+	a.RLock()
+	defer a.RUnlock()
+
+	av, ok := a.activeVotes[token]
+	if !ok || len(av.Stakes) < len(av.Eligible) {
+		return 0, false
+	}
+	var total uint64
+	for _, stake := range av.Stakes {
+		total += stake
+	}
+	return total, true
+}
+
 // AddCastVote adds a cast ticket vote to the active votes cache.
 func (a *activeVotes) AddCastVote(token, ticket, votebit string) {
 	a.Lock()
@@ -226,9 +290,33 @@ func (a *activeVotes) AddCommitmentAddrs(token string, addrs map[string]commitme
 			continue
 		}
 		av.Addrs[ticket] = v.addr
+		if v.stake > 0 {
+			av.Stakes[ticket] = v.stake
+		}
 	}
 }
 
+// SetEndBlockHeight updates the end block height of an active vote. This is
+// used when an active vote is extended.
+func (a *activeVotes) SetEndBlockHeight(token string, endHeight uint32) {
+	a.Lock()
+	defer a.Unlock()
+
+	av, ok := a.activeVotes[token]
+	if !ok {
+		return
+	}
+
+	// The cached vote details are copied on read so they can be
+	// replaced without affecting any existing readers.
+	vd := *av.Details
+	vd.EndBlockHeight = endHeight
+	av.Details = &vd
+	a.activeVotes[token] = av
+
+	log.Debugf("Active votes end height %v %v", token, endHeight)
+}
+
 // Del deletes an active vote from the active votes cache.
 func (a *activeVotes) Del(token string) {
 	a.Lock()
@@ -243,20 +331,77 @@ func (a *activeVotes) Del(token string) {
 // This function should NOT be called directly. The ticketvote method
 // activeVotesAdd(), which also kicks of an async job to fetch the commitment
 // addresses for this active votes entry, should be used instead.
-func (a *activeVotes) Add(vd ticketvote.VoteDetails) {
+func (a *activeVotes) Add(vd ticketvote.VoteDetails, eligible ticketSnapshot) {
 	token := vd.Params.Token
 
+	// The eligible tickets are cached as a snapshot
+	vd.EligibleTickets = nil
+
 	a.Lock()
 	a.activeVotes[token] = activeVote{
 		Details:   &vd,
+		Eligible:  eligible,
 		CastVotes: make(map[string]string, 40960), // Ticket pool size
 		Addrs:     make(map[string]string, 40960), // Ticket pool size
+		Stakes:    make(map[string]uint64),
 	}
 	a.Unlock()
 
 	log.Debugf("Active votes add %v", token)
 }
 
+// CommitmentAddrsCount returns the number of cached commitment addresses for
+// an active vote.
+func (a *activeVotes) CommitmentAddrsCount(token string) int {
+	a.RLock()
+	defer a.RUnlock()
+
+	return len(a.activeVotes[token].Addrs)
+}
+
+// Rebuild replaces the contents of the active votes cache with the contents
+// of the provided cache. The cast votes and commitment addresses of entries
+// that exist in both caches are carried over to the rebuilt entries so that
+// votes that were cast while the provided cache was being built are not lost.
+// The tokens that were added to and removed from the cache are returned.
+func (a *activeVotes) Rebuild(r *activeVotes) ([]string, []string) {
+	a.Lock()
+	defer a.Unlock()
+
+	added := make([]string, 0, len(r.activeVotes))
+	for token, av := range r.activeVotes {
+		prev, ok := a.activeVotes[token]
+		if !ok {
+			added = append(added, token)
+			continue
+		}
+		for ticket, votebit := range prev.CastVotes {
+			av.CastVotes[ticket] = votebit
+		}
+		for ticket, addr := range prev.Addrs {
+			av.Addrs[ticket] = addr
+		}
+		for ticket, stake := range prev.Stakes {
+			av.Stakes[ticket] = stake
+		}
+	}
+	removed := make([]string, 0, len(a.activeVotes))
+	for token := range a.activeVotes {
+		if _, ok := r.activeVotes[token]; !ok {
+			removed = append(removed, token)
+		}
+	}
+	a.activeVotes = r.activeVotes
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	log.Debugf("Active votes rebuilt: %v added, %v removed",
+		len(added), len(removed))
+
+	return added, removed
+}
+
 // newActiveVotes returns a new activeVotes.
 func newActiveVotes() *activeVotes {
 	return &activeVotes{
@@ -264,8 +409,8 @@ func newActiveVotes() *activeVotes {
 	}
 }
 
-// activeVotePopulateAddrs fetches the largest commitment address for each
-// ticket in a vote from dcrdata and caches the results.
+// activeVotePopulateAddrs fetches the commitment address for each ticket in a
+// vote from the chain and caches the results.
 func (p *ticketVotePlugin) activeVotePopulateAddrs(vd ticketvote.VoteDetails) {
 	// Get largest commitment address for each eligible ticket. A
 	// TrimmedTxs response for 500 tickets is ~1MB. It takes ~1.5
@@ -289,7 +434,7 @@ func (p *ticketVotePlugin) activeVotePopulateAddrs(vd ticketvote.VoteDetails) {
 			token, endIdx, len(vd.EligibleTickets))
 
 		tickets := vd.EligibleTickets[startIdx:endIdx]
-		addrs, err := p.largestCommitmentAddrs(tickets)
+		addrs, err := p.commitmentAddrs(tickets, vd.StartBlockHeight)
 		if err != nil {
 			log.Errorf("Populate commitment addresses for %v at %v: %v",
 				token, startIdx, err)
@@ -306,10 +451,135 @@ func (p *ticketVotePlugin) activeVotePopulateAddrs(vd ticketvote.VoteDetails) {
 // activeVotesAdd creates a active votes cache entry for the provided vote
 // details and kicks off an async job that fetches and caches the largest
 // commitment address for each eligible ticket.
-func (p *ticketVotePlugin) activeVotesAdd(vd ticketvote.VoteDetails) {
+func (p *ticketVotePlugin) activeVotesAdd(vd ticketvote.VoteDetails) error {
+	// Create the eligible ticket snapshot
+	eligible, err := newTicketSnapshot(vd.EligibleTickets)
+	if err != nil {
+		return err
+	}
+
 	// Add the vote to the active votes cache
-	p.activeVotes.Add(vd)
+	p.activeVotes.Add(vd, eligible)
 
 	// Fetch the commitment addresses asynchronously
 	go p.activeVotePopulateAddrs(vd)
+
+	return nil
+}
+
+// activeVotesRebuild rebuilds the active votes cache using the records in the
+// inventory that have a vote status of VoteStatusStarted. The new cache is
+// built separately and then swapped in so that cast ballot validation is not
+// blocked while the vote data is being retrieved. The async jobs that fetch
+// the commitment addresses are kicked off for the entries that do not have
+// them cached yet.
+func (p *ticketVotePlugin) activeVotesRebuild() (*ticketvote.ActiveVotesRebuildReply, error) {
+	var (
+		// started is populated with the tokens of all records
+		// that have a vote status of VoteStatusStarted.
+		started = make([]string, 0, 256)
+
+		page uint32 = 1
+	)
+	bestBlock, err := p.chain.BestBlock()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		entries, err := p.inv.GetPageForStatus(bestBlock,
+			ticketvote.VoteStatusStarted, page, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			// We've reached the end of the inventory
+			// for the VoteStatusStarted entries.
+			break
+		}
+		started = append(started, entryTokens(entries)...)
+		page++
+	}
+
+	// Retrieve the data required to build the active
+	// votes cache for the records with ongoing votes.
+	var (
+		r       = newActiveVotes()
+		details = make([]ticketvote.VoteDetails, 0, len(started))
+	)
+	for _, v := range started {
+		// Get the vote details
+		token, err := tokenDecode(v)
+		if err != nil {
+			return nil, err
+		}
+
+		reply, err := p.backend.PluginRead(token, ticketvote.PluginID,
+			ticketvote.CmdDetails, "")
+		if err != nil {
+			return nil, errors.Errorf("PluginRead %x %v %v: %v", token,
+				ticketvote.PluginID, ticketvote.CmdDetails, err)
+		}
+		var dr ticketvote.DetailsReply
+		err = json.Unmarshal([]byte(reply), &dr)
+		if err != nil {
+			return nil, err
+		}
+		if dr.Vote == nil {
+			// Sanity check
+			return nil, errors.Errorf("vote details not found "+
+				"for record in started inventory %x", token)
+		}
+
+		// Add the record to the active votes cache
+		eligible, err := newTicketSnapshot(dr.Vote.EligibleTickets)
+		if err != nil {
+			return nil, err
+		}
+		r.Add(*dr.Vote, eligible)
+		details = append(details, *dr.Vote)
+
+		// Get the cast votes
+		reply, err = p.backend.PluginRead(token, ticketvote.PluginID,
+			ticketvote.CmdResults, "")
+		if err != nil {
+			return nil, errors.Errorf("PluginRead %x %v %v: %v", token,
+				ticketvote.PluginID, ticketvote.CmdResults, err)
+		}
+		var rr ticketvote.ResultsReply
+		err = json.Unmarshal([]byte(reply), &rr)
+		if err != nil {
+			return nil, err
+		}
+
+		// Add the cast votes to the cached active vote entry. The
+		// commitment address and stake of a cast vote were saved
+		// with the vote.
+		for _, v := range rr.Votes {
+			r.AddCastVote(v.Token, v.Ticket, v.VoteBit)
+			if v.Stake > 0 {
+				r.AddCommitmentAddrs(v.Token, map[string]commitmentAddr{
+					v.Ticket: {addr: v.Address, stake: v.Stake},
+				})
+			}
+		}
+	}
+
+	// Swap in the rebuilt cache
+	added, removed := p.activeVotes.Rebuild(r)
+
+	// Fetch the commitment addresses asynchronously
+	for _, vd := range details {
+		token := vd.Params.Token
+		if p.activeVotes.CommitmentAddrsCount(token) >= len(vd.EligibleTickets) {
+			// Commitment addresses were carried over
+			continue
+		}
+		go p.activeVotePopulateAddrs(vd)
+	}
+
+	return &ticketvote.ActiveVotesRebuildReply{
+		Tokens:  started,
+		Added:   added,
+		Removed: removed,
+	}, nil
 }
@@ -0,0 +1,65 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestActiveVotesRebuild(t *testing.T) {
+	newDetails := func(token string) ticketvote.VoteDetails {
+		return ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Token: token,
+			},
+		}
+	}
+
+	// Setup the existing cache. The stale entry is no longer an
+	// active vote and the existing entry has a vote that was cast
+	// while the cache was being rebuilt.
+	a := newActiveVotes()
+	a.Add(newDetails("stale"), nil)
+	a.Add(newDetails("existing"), nil)
+	a.AddCastVote("existing", "ticket1", "1")
+	a.AddCommitmentAddrs("existing", map[string]commitmentAddr{
+		"ticket1": {addr: "addr1"},
+	})
+
+	// Setup the rebuilt cache
+	r := newActiveVotes()
+	r.Add(newDetails("existing"), nil)
+	r.Add(newDetails("missing"), nil)
+	r.AddCastVote("existing", "ticket2", "2")
+
+	added, removed := a.Rebuild(r)
+	if !reflect.DeepEqual(added, []string{"missing"}) {
+		t.Errorf("added: got %v, want [missing]", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"stale"}) {
+		t.Errorf("removed: got %v, want [stale]", removed)
+	}
+
+	// Verify the cache contents
+	if _, ok := a.activeVotes["stale"]; ok {
+		t.Errorf("stale entry was not removed")
+	}
+	if _, ok := a.activeVotes["missing"]; !ok {
+		t.Errorf("missing entry was not added")
+	}
+	wantTally := map[string]uint32{
+		"1": 1,
+		"2": 1,
+	}
+	if tally := a.Tally("existing"); !reflect.DeepEqual(tally, wantTally) {
+		t.Errorf("tally: got %v, want %v", tally, wantTally)
+	}
+	if n := a.CommitmentAddrsCount("existing"); n != 1 {
+		t.Errorf("commitment addrs: got %v, want 1", n)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// auditBundleDirname is the name of the directory in the plugin data
+	// directory that the audit bundles are cached in.
+	auditBundleDirname = "auditbundles"
+)
+
+// cmdAuditBundle returns a signed audit bundle of a finished record vote. The
+// bundle contains the vote details, the eligible tickets, all cast votes, and
+// the timestamps of the vote data.
+//
+// The data of a finished vote does not change, so the bundle is cached in the
+// plugin data directory once all of its timestamps have been anchored. This
+// prevents the timestamps of every cast vote from being retrieved from tlog
+// on each request.
+func (p *ticketVotePlugin) cmdAuditBundle(token []byte) (string, error) {
+	// Check the cache
+	fp := p.auditBundlePath(token)
+	b, err := os.ReadFile(fp)
+	switch {
+	case err == nil:
+		return string(b), nil
+	case errors.Is(err, fs.ErrNotExist):
+		// Not cached; continue
+	default:
+		return "", err
+	}
+
+	// Verify the vote has finished. This cmd does not write any data
+	// so we do not have to use the safe best block.
+	bb, err := p.chain.BestBlockUnsafe()
+	if err != nil {
+		return "", fmt.Errorf("BestBlockUnsafe: %v", err)
+	}
+	sr, err := p.summary(token, bb)
+	if err != nil {
+		return "", fmt.Errorf("summary: %v", err)
+	}
+	switch sr.Status {
+	case ticketvote.VoteStatusFinished, ticketvote.VoteStatusApproved,
+		ticketvote.VoteStatusRejected:
+		// Vote has finished; continue
+	default:
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has not finished",
+		}
+	}
+
+	// The best block is not part of the final vote results
+	sr.BestBlock = 0
+
+	// Get the vote data
+	auths, err := p.auths(token)
+	if err != nil {
+		return "", err
+	}
+	vd, err := p.voteDetails(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		return "", fmt.Errorf("vote details not found for %x", token)
+	}
+	votes, err := p.voteResults(token)
+	if err != nil {
+		return "", err
+	}
+	ts, err := p.auditBundleTimestamps(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Encode and sign the bundle
+	ab := ticketvote.VoteAuditBundle{
+		Version:    ticketvote.AuditBundleVersion,
+		Token:      tokenEncode(token),
+		Summary:    *sr,
+		Auths:      auths,
+		Details:    *vd,
+		Votes:      votes,
+		Timestamps: *ts,
+		Anchored:   auditBundleAnchored(*ts),
+		Timestamp:  time.Now().Unix(),
+	}
+	bundle, err := json.Marshal(ab)
+	if err != nil {
+		return "", err
+	}
+	digest := hex.EncodeToString(util.Digest(bundle))
+	signature := p.identity.SignMessage([]byte(digest))
+
+	// Prepare reply
+	reply, err := json.Marshal(ticketvote.AuditBundleReply{
+		Bundle:    string(bundle),
+		Digest:    digest,
+		PublicKey: p.identity.Public.String(),
+		Signature: hex.EncodeToString(signature[:]),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Cache the bundle once it has been fully anchored. A failure
+	// is not fatal since the bundle can be re-created.
+	if ab.Anchored {
+		err = auditBundleSave(fp, reply)
+		if err != nil {
+			log.Errorf("auditBundleSave %x: %v", token, err)
+		}
+	}
+
+	return string(reply), nil
+}
+
+// auditBundleTimestamps returns the timestamps of all of the vote data of a
+// record vote. The vote timestamps are retrieved one page at a time using the
+// timestamps command so that the timestamps cache is used.
+func (p *ticketVotePlugin) auditBundleTimestamps(token []byte) (*ticketvote.AuditBundleTimestamps, error) {
+	timestamps := func(t ticketvote.Timestamps) (*ticketvote.TimestampsReply, error) {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		reply, err := p.cmdTimestamps(token, string(b))
+		if err != nil {
+			return nil, err
+		}
+		var tr ticketvote.TimestampsReply
+		err = json.Unmarshal([]byte(reply), &tr)
+		if err != nil {
+			return nil, err
+		}
+		return &tr, nil
+	}
+
+	// Get the auth and vote details timestamps
+	tr, err := timestamps(ticketvote.Timestamps{})
+	if err != nil {
+		return nil, err
+	}
+	if tr.Details == nil {
+		return nil, fmt.Errorf("vote details timestamp not found for %x",
+			token)
+	}
+	abt := ticketvote.AuditBundleTimestamps{
+		Auths:   tr.Auths,
+		Details: *tr.Details,
+	}
+
+	// Get the vote timestamps
+	for page := uint32(1); ; page++ {
+		tr, err := timestamps(ticketvote.Timestamps{
+			VotesPage: page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		abt.Votes = append(abt.Votes, tr.Votes...)
+		if len(tr.Votes) == 0 || len(abt.Votes) >= int(tr.VotesCount) {
+			break
+		}
+	}
+
+	// Get the eligible ticket snapshot timestamp. Votes that were
+	// started prior to snapshots being introduced will not have one.
+	digests, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorSnapshot})
+	if err != nil {
+		return nil, fmt.Errorf("DigestsByDataDesc %x %v: %v",
+			token, dataDescriptorSnapshot, err)
+	}
+	if len(digests) > 0 {
+		// A rolled back runoff vote start may leave behind an older
+		// snapshot. Only the most recent snapshot is used.
+		abt.Snapshot, err = p.timestamp(token, digests[len(digests)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		// The snapshot data is binary and is not included. The
+		// snapshot digest is verified against the eligible tickets of
+		// the vote details instead.
+		abt.Snapshot.Data = ""
+	}
+
+	return &abt, nil
+}
+
+// auditBundleAnchored returns whether all of the provided timestamps have been
+// anchored onto the DCR blockchain.
+func auditBundleAnchored(abt ticketvote.AuditBundleTimestamps) bool {
+	ts := make([]ticketvote.Timestamp, 0, len(abt.Auths)+len(abt.Votes)+2)
+	ts = append(ts, abt.Auths...)
+	ts = append(ts, abt.Votes...)
+	ts = append(ts, abt.Details)
+	if abt.Snapshot != nil {
+		ts = append(ts, *abt.Snapshot)
+	}
+	for _, v := range ts {
+		if v.TxID == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// auditBundlePath returns the file path of the cached audit bundle of a
+// record vote.
+func (p *ticketVotePlugin) auditBundlePath(token []byte) string {
+	return filepath.Join(p.dataDir, auditBundleDirname,
+		tokenEncode(token)+".json")
+}
+
+// auditBundleSave saves the provided encoded audit bundle reply to the
+// provided file path. The bundle is written to a temp file that is then
+// renamed so that a partially written bundle is never returned.
+func auditBundleSave(fp string, reply []byte) error {
+	err := os.MkdirAll(filepath.Dir(fp), 0700)
+	if err != nil {
+		return err
+	}
+	tmp := fp + ".tmp"
+	err = os.WriteFile(tmp, reply, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, fp)
+}
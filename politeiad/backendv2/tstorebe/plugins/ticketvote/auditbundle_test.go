@@ -0,0 +1,73 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestAuditBundleAnchored(t *testing.T) {
+	var (
+		anchored   = ticketvote.Timestamp{TxID: "txid"}
+		unanchored = ticketvote.Timestamp{}
+	)
+	var tests = []struct {
+		name string
+		abt  ticketvote.AuditBundleTimestamps
+		want bool
+	}{
+		{
+			"all anchored",
+			ticketvote.AuditBundleTimestamps{
+				Auths:    []ticketvote.Timestamp{anchored},
+				Details:  anchored,
+				Snapshot: &anchored,
+				Votes:    []ticketvote.Timestamp{anchored, anchored},
+			},
+			true,
+		},
+		{
+			"no snapshot",
+			ticketvote.AuditBundleTimestamps{
+				Details: anchored,
+				Votes:   []ticketvote.Timestamp{anchored},
+			},
+			true,
+		},
+		{
+			"vote not anchored",
+			ticketvote.AuditBundleTimestamps{
+				Details: anchored,
+				Votes:   []ticketvote.Timestamp{anchored, unanchored},
+			},
+			false,
+		},
+		{
+			"snapshot not anchored",
+			ticketvote.AuditBundleTimestamps{
+				Details:  anchored,
+				Snapshot: &unanchored,
+			},
+			false,
+		},
+		{
+			"details not anchored",
+			ticketvote.AuditBundleTimestamps{
+				Details: unanchored,
+			},
+			false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := auditBundleAnchored(tc.abt)
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/pkg/errors"
+)
+
+// chain provides the block data that is required to run a ticket vote: the
+// best block and the snapshot block of a vote. The chain that is used is
+// selected using the chain plugin setting. The eligible tickets of a vote are
+// provided separately by the eligibility implementation that is selected
+// using the eligibility plugin setting.
+//
+// Block heights do not need to correspond to the blocks of an actual
+// blockchain, but they must increase monotonically since vote durations are
+// measured in blocks.
+type chain interface {
+	// Setup performs any required chain setup, e.g. verifying that the
+	// plugin dependencies of the chain have been registered.
+	Setup() error
+
+	// BestBlock returns the best block height. An error is returned if
+	// the best block height is not guaranteed to be current.
+	BestBlock() (uint32, error)
+
+	// BestBlockUnsafe returns the best block height. The returned block
+	// height may be stale. Use BestBlock if the caller requires a
+	// guarantee that the best block is current.
+	BestBlockUnsafe() (uint32, error)
+
+	// NewBlock returns the block height of a new block plugin event.
+	// False is returned if the plugin event is not a new block event
+	// for this chain.
+	NewBlock(e plugins.HookPluginEvent) (uint32, bool, error)
+
+	// VoteChainParams returns the chain params for a vote that is being
+	// started with the provided duration. The eligible tickets are not
+	// populated. A non-zero snapshot height overrides the snapshot
+	// block of the vote.
+	VoteChainParams(duration, snapshotHeight uint32) (*voteChainParams, error)
+}
+
+// voteChainParams represent the chain parameters for a ticket vote.
+type voteChainParams struct {
+	StartBlockHeight uint32   `json:"startblockheight"`
+	StartBlockHash   string   `json:"startblockhash"`
+	EndBlockHeight   uint32   `json:"endblockheight"`
+	EligibleTickets  []string `json:"eligibletickets"` // Ticket hashes
+}
+
+// newChain returns the chain implementation for the provided chain plugin
+// setting.
+func newChain(name string, backend backend.Backend, activeNetParams *chaincfg.Params) (chain, error) {
+	switch name {
+	case ticketvote.ChainDecred:
+		return newDcrChain(backend, activeNetParams), nil
+	}
+	return nil, errors.Errorf("unknown chain '%v'", name)
+}
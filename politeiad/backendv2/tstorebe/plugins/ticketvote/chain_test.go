@@ -0,0 +1,77 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestNewChain(t *testing.T) {
+	var tests = []struct {
+		name    string
+		chain   string
+		wantErr bool
+	}{
+		{"decred", ticketvote.ChainDecred, false},
+		{"unknown chain", "unknown", true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := newChain(v.chain, nil, chaincfg.TestNet3Params())
+			if (err != nil) != v.wantErr {
+				t.Errorf("got err %v, want err %v", err, v.wantErr)
+			}
+		})
+	}
+}
+
+func TestDcrChainNewBlock(t *testing.T) {
+	c := newDcrChain(nil, chaincfg.TestNet3Params())
+
+	var tests = []struct {
+		name       string
+		event      plugins.HookPluginEvent
+		wantHeight uint32
+		wantOK     bool
+	}{
+		{
+			"new block",
+			plugins.HookPluginEvent{
+				PluginID: dcrdata.PluginID,
+				Event:    dcrdata.EventNewBlock,
+				Payload:  `{"height":100}`,
+			},
+			100,
+			true,
+		},
+		{
+			"other event",
+			plugins.HookPluginEvent{
+				PluginID: ticketvote.PluginID,
+				Event:    dcrdata.EventNewBlock,
+				Payload:  `{"height":100}`,
+			},
+			0,
+			false,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			height, ok, err := c.NewBlock(v.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if height != v.wantHeight || ok != v.wantOK {
+				t.Errorf("got (%v, %v), want (%v, %v)",
+					height, ok, v.wantHeight, v.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2020-2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/pkg/errors"
+)
+
+var (
+	_ chain = (*dcrChain)(nil)
+)
+
+// dcrChain is the chain implementation for the Decred blockchain. The chain
+// data is retrieved using the dcrdata plugin.
+//
+// dcrChain satisfies the chain interface.
+type dcrChain struct {
+	backend         backend.Backend
+	activeNetParams *chaincfg.Params
+}
+
+// newDcrChain returns a new dcrChain.
+func newDcrChain(backend backend.Backend, activeNetParams *chaincfg.Params) *dcrChain {
+	return &dcrChain{
+		backend:         backend,
+		activeNetParams: activeNetParams,
+	}
+}
+
+// Setup verifies that the dcrdata plugin has been registered.
+//
+// This function satisfies the chain interface.
+func (c *dcrChain) Setup() error {
+	return dcrdataRegistered(c.backend)
+}
+
+// BestBlock fetches the best block from the dcrdata plugin and returns it. If
+// the dcrdata connection is not active, an error will be returned.
+//
+// This function satisfies the chain interface.
+func (c *dcrChain) BestBlock() (uint32, error) {
+	// Get best block
+	payload, err := json.Marshal(dcrdata.BestBlock{})
+	if err != nil {
+		return 0, err
+	}
+	reply, err := c.backend.PluginRead(nil, dcrdata.PluginID,
+		dcrdata.CmdBestBlock, string(payload))
+	if err != nil {
+		return 0, fmt.Errorf("PluginRead %v %v: %v",
+			dcrdata.PluginID, dcrdata.CmdBestBlock, err)
+	}
+
+	// Handle response
+	var bbr dcrdata.BestBlockReply
+	err = json.Unmarshal([]byte(reply), &bbr)
+	if err != nil {
+		return 0, err
+	}
+	if bbr.Status != dcrdata.StatusConnected {
+		// The dcrdata connection is down. The best block cannot be
+		// trusted as being accurate.
+		return 0, fmt.Errorf("dcrdata connection is down")
+	}
+	if bbr.Height == 0 {
+		return 0, fmt.Errorf("invalid best block height 0")
+	}
+
+	return bbr.Height, nil
+}
+
+// BestBlockUnsafe fetches the best block from the dcrdata plugin and returns
+// it. If the dcrdata connection is not active, an error WILL NOT be returned.
+// The dcrdata cached best block height will be returned even though it may be
+// stale. Use BestBlock() if the caller requires a guarantee that the best
+// block is not stale.
+//
+// This function satisfies the chain interface.
+func (c *dcrChain) BestBlockUnsafe() (uint32, error) {
+	// Get best block
+	payload, err := json.Marshal(dcrdata.BestBlock{})
+	if err != nil {
+		return 0, err
+	}
+	reply, err := c.backend.PluginRead(nil, dcrdata.PluginID,
+		dcrdata.CmdBestBlock, string(payload))
+	if err != nil {
+		return 0, fmt.Errorf("PluginRead %v %v: %v",
+			dcrdata.PluginID, dcrdata.CmdBestBlock, err)
+	}
+
+	// Handle response
+	var bbr dcrdata.BestBlockReply
+	err = json.Unmarshal([]byte(reply), &bbr)
+	if err != nil {
+		return 0, err
+	}
+	if bbr.Height == 0 {
+		return 0, fmt.Errorf("invalid best block height 0")
+	}
+
+	return bbr.Height, nil
+}
+
+// NewBlock returns the block height of a dcrdata new block plugin event.
+//
+// This function satisfies the chain interface.
+func (c *dcrChain) NewBlock(e plugins.HookPluginEvent) (uint32, bool, error) {
+	if e.PluginID != dcrdata.PluginID || e.Event != dcrdata.EventNewBlock {
+		return 0, false, nil
+	}
+	var nb dcrdata.NewBlock
+	err := json.Unmarshal([]byte(e.Payload), &nb)
+	if err != nil {
+		return 0, false, err
+	}
+	return nb.Height, true, nil
+}
+
+// VoteChainParams returns the chain params for a vote that is being started
+// with the provided duration. The snapshot block is the best block minus the
+// ticket maturity unless a snapshot height override is provided.
+//
+// This function satisfies the chain interface.
+func (c *dcrChain) VoteChainParams(duration, snapshotHeightOverride uint32) (*voteChainParams, error) {
+	// Get the best block height
+	bb, err := c.BestBlock()
+	if err != nil {
+		return nil, fmt.Errorf("BestBlock: %v", err)
+	}
+
+	// Find the snapshot height. Subtract the ticket maturity from the
+	// block height to get into unforkable territory.
+	ticketMaturity := uint32(c.activeNetParams.TicketMaturity)
+	snapshotHeight := bb - ticketMaturity
+
+	// An overridden snapshot height must also be in unforkable
+	// territory.
+	if snapshotHeightOverride != 0 {
+		if snapshotHeightOverride > snapshotHeight {
+			return nil, backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeStartDetailsInvalid),
+				ErrorContext: fmt.Sprintf("snapshot height %v must be "+
+					"at or below %v", snapshotHeightOverride, snapshotHeight),
+			}
+		}
+		snapshotHeight = snapshotHeightOverride
+	}
+
+	// Fetch the block details for the snapshot height. We need the
+	// block hash in order to fetch the ticket pool snapshot.
+	bd := dcrdata.BlockDetails{
+		Height: snapshotHeight,
+	}
+	payload, err := json.Marshal(bd)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.backend.PluginRead(nil, dcrdata.PluginID,
+		dcrdata.CmdBlockDetails, string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("PluginRead %v %v: %v",
+			dcrdata.PluginID, dcrdata.CmdBlockDetails, err)
+	}
+	var bdr dcrdata.BlockDetailsReply
+	err = json.Unmarshal([]byte(reply), &bdr)
+	if err != nil {
+		return nil, err
+	}
+	if bdr.Block.Hash == "" {
+		return nil, fmt.Errorf("invalid block hash for height %v",
+			snapshotHeight)
+	}
+	snapshotHash := bdr.Block.Hash
+
+	// The start block height has the ticket maturity subtracted from
+	// it to prevent forking issues. This means we the vote starts in
+	// the past. The vote duration is measured from the best block to
+	// correct for this.
+	endBlockHeight := bb + duration
+
+	return &voteChainParams{
+		StartBlockHeight: snapshotHeight,
+		StartBlockHash:   snapshotHash,
+		EndBlockHeight:   endBlockHeight,
+	}, nil
+}
+
+// dcrdataRegistered returns an error if the dcrdata plugin has not been
+// registered with the backend.
+func dcrdataRegistered(backend backend.Backend) error {
+	for _, v := range backend.PluginInventory() {
+		if v.ID == dcrdata.PluginID {
+			return nil
+		}
+	}
+	return errors.Errorf("%v plugin dependency not registered",
+		dcrdata.PluginID)
+}
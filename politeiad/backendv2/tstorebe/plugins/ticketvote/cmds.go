@@ -7,19 +7,19 @@ package ticketvote
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/decred/dcrd/chaincfg/v3"
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
-	"github.com/decred/politeia/politeiad/plugins/dcrdata"
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	"github.com/decred/politeia/util"
 	"github.com/pkg/errors"
@@ -34,6 +34,10 @@ const (
 	dataDescriptorCastVoteDetails = pluginID + "-castvote-v1"
 	dataDescriptorVoteCollider    = pluginID + "-vcollider-v1"
 	dataDescriptorStartRunoff     = pluginID + "-startrunoff-v1"
+	dataDescriptorSnapshot        = pluginID + "-snapshot-v1"
+	dataDescriptorExtendDetails   = pluginID + "-extend-v1"
+	dataDescriptorCancelDetails   = pluginID + "-cancel-v1"
+	dataDescriptorSummary         = pluginID + "-summary-v1"
 )
 
 // cmdAuthorize authorizes a ticket vote or revokes a previous authorization.
@@ -135,16 +139,34 @@ func (p *ticketVotePlugin) cmdAuthorize(token []byte, payload string) (string, e
 		}
 	}
 
+	// Verify that the authorization is not locked. The best block is
+	// only required when the authorization lock has been enabled.
+	var bestBlock uint32
+	if p.authLockBlocks > 0 {
+		bestBlock, err = p.chain.BestBlock()
+		if err != nil {
+			return "", err
+		}
+		if a.Action == ticketvote.AuthActionRevoke {
+			err = authLockVerify(auths[len(auths)-1], p.authLockBlocks,
+				bestBlock)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
 	// Prepare authorize vote
 	receipt := p.identity.SignMessage([]byte(a.Signature))
 	auth := ticketvote.AuthDetails{
-		Token:     a.Token,
-		Version:   a.Version,
-		Action:    string(a.Action),
-		PublicKey: a.PublicKey,
-		Signature: a.Signature,
-		Timestamp: time.Now().Unix(),
-		Receipt:   hex.EncodeToString(receipt[:]),
+		Token:       a.Token,
+		Version:     a.Version,
+		Action:      string(a.Action),
+		PublicKey:   a.PublicKey,
+		Signature:   a.Signature,
+		Timestamp:   time.Now().Unix(),
+		Receipt:     hex.EncodeToString(receipt[:]),
+		BlockHeight: bestBlock,
 	}
 
 	// Save authorize vote
@@ -179,29 +201,112 @@ func (p *ticketVotePlugin) cmdAuthorize(token []byte, payload string) (string, e
 	return string(reply), nil
 }
 
-// voteBitVerify verifies that the vote bit corresponds to a valid vote option.
-func voteBitVerify(options []ticketvote.VoteOption, mask, bit uint64) error {
-	if len(options) == 0 {
-		return fmt.Errorf("no vote options found")
+// authLockVerify verifies that the provided vote authorization is no longer
+// locked and can be revoked. An authorization is locked for the provided
+// number of blocks after it was made. Authorizations that were made before
+// the lock was enabled do not have a block height and are never locked.
+func authLockVerify(auth ticketvote.AuthDetails, lockBlocks, bestBlock uint32) error {
+	if auth.BlockHeight == 0 {
+		return nil
 	}
-	if bit == 0 {
-		return fmt.Errorf("invalid bit 0x%x", bit)
+	unlockHeight := auth.BlockHeight + lockBlocks
+	if bestBlock >= unlockHeight {
+		return nil
+	}
+	return backend.PluginError{
+		PluginID:  ticketvote.PluginID,
+		ErrorCode: uint32(ticketvote.ErrorCodeAuthorizationLocked),
+		ErrorContext: fmt.Sprintf("authorization cannot be revoked "+
+			"until block %v", unlockHeight),
 	}
+}
 
-	// Verify bit is included in mask
-	if mask&bit != bit {
-		return fmt.Errorf("invalid mask 0x%x bit 0x%x", mask, bit)
+// voteBitError is returned when a vote bit is invalid. It identifies the vote
+// option, bit and mask that caused the error.
+type voteBitError struct {
+	ticketvote.VoteBitError
+}
+
+// Error satisfies the error interface.
+func (e voteBitError) Error() string {
+	if e.OptionID == "" {
+		return fmt.Sprintf("bit 0x%x mask 0x%x: %v", e.Bit, e.Mask,
+			ticketvote.VoteBitErrors[e.ErrorCode])
 	}
+	return fmt.Sprintf("vote option %v bit 0x%x mask 0x%x: %v", e.OptionID,
+		e.Bit, e.Mask, ticketvote.VoteBitErrors[e.ErrorCode])
+}
 
-	// Verify bit is included in vote options
-	for _, v := range options {
-		if v.Bit == bit {
-			// Bit matches one of the options. We're done.
-			return nil
+// voteBitVerify verifies that the vote bit corresponds to a valid vote option.
+// A voteBitError is returned if the vote bit is invalid.
+func voteBitVerify(options []ticketvote.VoteOption, mask, bit uint64) error {
+	var code ticketvote.VoteBitErrorT
+	switch {
+	case len(options) == 0:
+		code = ticketvote.VoteBitErrorNoOptions
+	case bit == 0:
+		code = ticketvote.VoteBitErrorBitZero
+	case mask&bit != bit:
+		// Bit is not included in the mask
+		code = ticketvote.VoteBitErrorBitNotInMask
+	default:
+		// Verify bit is included in vote options
+		for _, v := range options {
+			if v.Bit == bit {
+				// Bit matches one of the options. We're done.
+				return nil
+			}
 		}
+		code = ticketvote.VoteBitErrorBitNotInOptions
+	}
+	return voteBitError{
+		ticketvote.VoteBitError{
+			ErrorCode: code,
+			Bit:       bit,
+			Mask:      mask,
+		},
 	}
+}
 
-	return fmt.Errorf("bit 0x%x not found in vote options", bit)
+// voteBitsVerify verifies the bits of the vote options of the provided vote
+// params and returns a vote bit error for each invalid vote option. Each
+// vote option must use a non-zero bit that is included in the vote mask and
+// that is not used by another vote option.
+func voteBitsVerify(vote ticketvote.VoteParams) []ticketvote.VoteBitError {
+	if len(vote.Options) == 0 {
+		return []ticketvote.VoteBitError{
+			{
+				ErrorCode: ticketvote.VoteBitErrorNoOptions,
+				Mask:      vote.Mask,
+			},
+		}
+	}
+	var (
+		errs = make([]ticketvote.VoteBitError, 0, len(vote.Options))
+		bits = make(map[uint64]struct{}, len(vote.Options))
+	)
+	for _, v := range vote.Options {
+		var code ticketvote.VoteBitErrorT
+		switch _, ok := bits[v.Bit]; {
+		case v.Bit == 0:
+			code = ticketvote.VoteBitErrorBitZero
+		case vote.Mask&v.Bit != v.Bit:
+			code = ticketvote.VoteBitErrorBitNotInMask
+		case ok:
+			code = ticketvote.VoteBitErrorBitDuplicate
+		default:
+			// Bit is valid
+			bits[v.Bit] = struct{}{}
+			continue
+		}
+		errs = append(errs, ticketvote.VoteBitError{
+			ErrorCode: code,
+			OptionID:  v.ID,
+			Bit:       v.Bit,
+			Mask:      vote.Mask,
+		})
+	}
+	return errs
 }
 
 // voteParamsVerify verifies that the params of a ticket vote are within
@@ -213,6 +318,8 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 		// This is allowed
 	case ticketvote.VoteTypeRunoff:
 		// This is allowed
+	case ticketvote.VoteTypeMultiOption:
+		// This is allowed
 	default:
 		return backend.PluginError{
 			PluginID:  ticketvote.PluginID,
@@ -304,17 +411,24 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 					strings.Join(missing, ",")),
 			}
 		}
-	}
 
-	// Verify vote bits are somewhat sane
-	for _, v := range vote.Options {
-		err := voteBitVerify(vote.Options, vote.Mask, v.Bit)
+	case ticketvote.VoteTypeMultiOption:
+		// Multi-option votes allow for any number of mutually
+		// exclusive vote options.
+		err := multiOptionsVerify(vote.Options)
 		if err != nil {
-			return backend.PluginError{
-				PluginID:     ticketvote.PluginID,
-				ErrorCode:    uint32(ticketvote.ErrorCodeVoteBitsInvalid),
-				ErrorContext: err.Error(),
-			}
+			return err
+		}
+	}
+
+	// Verify vote bits are somewhat sane. The first vote bit error is
+	// returned.
+	errs := voteBitsVerify(vote)
+	if len(errs) > 0 {
+		return backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteBitsInvalid),
+			ErrorContext: voteBitError{errs[0]}.Error(),
 		}
 	}
 
@@ -327,6 +441,13 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 			ErrorContext: "parent token should not be provided " +
 				"for a standard vote",
 		}
+	case vote.Type == ticketvote.VoteTypeMultiOption && vote.Parent != "":
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteParentInvalid),
+			ErrorContext: "parent token should not be provided " +
+				"for a multi-option vote",
+		}
 	case vote.Type == ticketvote.VoteTypeRunoff:
 		_, err := tokenDecode(vote.Parent)
 		if err != nil {
@@ -339,95 +460,142 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 		}
 	}
 
-	return nil
-}
-
-// voteChainParams represent the dcr blockchain parameters for a ticket vote.
-type voteChainParams struct {
-	StartBlockHeight uint32   `json:"startblockheight"`
-	StartBlockHash   string   `json:"startblockhash"`
-	EndBlockHeight   uint32   `json:"endblockheight"`
-	EligibleTickets  []string `json:"eligibletickets"` // Ticket hashes
-}
+	// Verify runoff winners
+	if vote.Type != ticketvote.VoteTypeRunoff && vote.Winners != 0 {
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeRunoffWinnersInvalid),
+			ErrorContext: "winners should only be provided " +
+				"for a runoff vote",
+		}
+	}
 
-// voteChainParams fetches and returns the voteChainParams for a ticket vote.
-func (p *ticketVotePlugin) voteChainParams(duration uint32) (*voteChainParams, error) {
-	// Get the best block height
-	bb, err := p.bestBlock()
-	if err != nil {
-		return nil, fmt.Errorf("bestBlock: %v", err)
+	// Verify vote weight
+	switch vote.Weight {
+	case ticketvote.VoteWeightTicket, ticketvote.VoteWeightStake:
+		// These are allowed
+	default:
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteWeightInvalid),
+			ErrorContext: fmt.Sprintf("invalid vote weight %v",
+				vote.Weight),
+		}
 	}
 
-	// Find the snapshot height. Subtract the ticket maturity from the
-	// block height to get into unforkable territory.
-	ticketMaturity := uint32(p.activeNetParams.TicketMaturity)
-	snapshotHeight := bb - ticketMaturity
+	// Verify win condition
+	return winConditionVerify(vote)
+}
 
-	// Fetch the block details for the snapshot height. We need the
-	// block hash in order to fetch the ticket pool snapshot.
-	bd := dcrdata.BlockDetails{
-		Height: snapshotHeight,
-	}
-	payload, err := json.Marshal(bd)
-	if err != nil {
-		return nil, err
-	}
-	reply, err := p.backend.PluginRead(nil, dcrdata.PluginID,
-		dcrdata.CmdBlockDetails, string(payload))
-	if err != nil {
-		return nil, fmt.Errorf("PluginRead %v %v: %v",
-			dcrdata.PluginID, dcrdata.CmdBlockDetails, err)
-	}
-	var bdr dcrdata.BlockDetailsReply
-	err = json.Unmarshal([]byte(reply), &bdr)
-	if err != nil {
-		return nil, err
-	}
-	if bdr.Block.Hash == "" {
-		return nil, fmt.Errorf("invalid block hash for height %v",
-			snapshotHeight)
+// voteWeightVerify verifies that the vote weight of the provided vote params
+// is supported by the eligibility source.
+func (p *ticketVotePlugin) voteWeightVerify(vote ticketvote.VoteParams) error {
+	if vote.Weight == ticketvote.VoteWeightStake &&
+		!p.eligibility.StakeWeighted() {
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteWeightInvalid),
+			ErrorContext: "stake weighted votes are not supported " +
+				"by the ticket eligibility",
+		}
 	}
-	snapshotHash := bdr.Block.Hash
+	return nil
+}
 
-	// Fetch the ticket pool snapshot
-	tp := dcrdata.TicketPool{
-		BlockHash: snapshotHash,
-	}
-	payload, err = json.Marshal(tp)
-	if err != nil {
-		return nil, err
+// multiOptionsVerify verifies the vote options of a multi-option vote. A
+// multi-option vote must have at least two vote options. The vote option IDs
+// must be unique and each vote option must use a unique, single bit so that a
+// cast vote can only select one of the options.
+func multiOptionsVerify(options []ticketvote.VoteOption) error {
+	if len(options) < 2 {
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteOptionsInvalid),
+			ErrorContext: fmt.Sprintf("vote options count got %v, "+
+				"want at least 2", len(options)),
+		}
 	}
-	reply, err = p.backend.PluginRead(nil, dcrdata.PluginID,
-		dcrdata.CmdTicketPool, string(payload))
-	if err != nil {
-		return nil, fmt.Errorf("PluginRead %v %v: %v",
-			dcrdata.PluginID, dcrdata.CmdTicketPool, err)
+	var (
+		ids  = make(map[string]struct{}, len(options))
+		bits uint64
+	)
+	for _, v := range options {
+		if v.ID == "" {
+			return backend.PluginError{
+				PluginID:     ticketvote.PluginID,
+				ErrorCode:    uint32(ticketvote.ErrorCodeVoteOptionsInvalid),
+				ErrorContext: "vote option ID not provided",
+			}
+		}
+		if _, ok := ids[v.ID]; ok {
+			return backend.PluginError{
+				PluginID:     ticketvote.PluginID,
+				ErrorCode:    uint32(ticketvote.ErrorCodeVoteOptionsInvalid),
+				ErrorContext: fmt.Sprintf("duplicate vote option ID %v", v.ID),
+			}
+		}
+		ids[v.ID] = struct{}{}
+
+		if v.Bit == 0 || v.Bit&(v.Bit-1) != 0 {
+			return backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeVoteBitsInvalid),
+				ErrorContext: fmt.Sprintf("vote option %v bit 0x%x is "+
+					"not a single bit", v.ID, v.Bit),
+			}
+		}
+		if bits&v.Bit != 0 {
+			return backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeVoteBitsInvalid),
+				ErrorContext: fmt.Sprintf("vote option %v bit 0x%x is "+
+					"used by another option", v.ID, v.Bit),
+			}
+		}
+		bits |= v.Bit
 	}
-	var tpr dcrdata.TicketPoolReply
-	err = json.Unmarshal([]byte(reply), &tpr)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// winConditionVerify verifies that the win condition of a ticket vote is
+// valid for the vote type. Only multi-option votes have a win condition.
+func winConditionVerify(vote ticketvote.VoteParams) error {
+	if vote.Type != ticketvote.VoteTypeMultiOption {
+		if vote.WinCondition != ticketvote.WinConditionInvalid {
+			return backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeWinConditionInvalid),
+				ErrorContext: "win condition should only be provided " +
+					"for a multi-option vote",
+			}
+		}
+		return nil
 	}
-	if len(tpr.Tickets) == 0 {
-		return nil, fmt.Errorf("no tickets found for block %v %v",
-			snapshotHeight, snapshotHash)
+	switch vote.WinCondition {
+	case ticketvote.WinConditionPlurality:
+		if vote.PassPercentage != 0 {
+			return backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeVotePassRateInvalid),
+				ErrorContext: "pass percentage must be 0 for the " +
+					"plurality win condition",
+			}
+		}
+	case ticketvote.WinConditionPassPercentage:
+		// This is allowed
+	default:
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeWinConditionInvalid),
+			ErrorContext: fmt.Sprintf("invalid win condition %v",
+				vote.WinCondition),
+		}
 	}
-
-	// The start block height has the ticket maturity subtracted from
-	// it to prevent forking issues. This means we the vote starts in
-	// the past. The ticket maturity needs to be added to the end block
-	// height to correct for this.
-	endBlockHeight := snapshotHeight + duration + ticketMaturity
-
-	return &voteChainParams{
-		StartBlockHeight: snapshotHeight,
-		StartBlockHash:   snapshotHash,
-		EndBlockHeight:   endBlockHeight,
-		EligibleTickets:  tpr.Tickets,
-	}, nil
+	return nil
 }
 
-// startStandard starts a standard vote.
+// startStandard starts a standard vote. Multi-option votes are started using
+// the same process as standard votes.
 func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*ticketvote.StartReply, error) {
 	// Verify there is only one start details
 	if len(s.Starts) != 1 {
@@ -458,7 +626,12 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 	}
 
 	// Verify vote options and params
-	err = voteParamsVerify(sd.Params, p.voteDurationMin, p.voteDurationMax)
+	voteDurationMin, voteDurationMax := p.voteDurationBounds()
+	err = voteParamsVerify(sd.Params, voteDurationMin, voteDurationMax)
+	if err != nil {
+		return nil, err
+	}
+	err = p.voteWeightVerify(sd.Params)
 	if err != nil {
 		return nil, err
 	}
@@ -486,7 +659,7 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 	}
 
 	// Get vote blockchain data
-	vcp, err := p.voteChainParams(sd.Params.Duration)
+	vcp, err := p.voteChainParams(sd.Params.Duration, sd.SnapshotHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -513,7 +686,7 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 	}
 
 	// Verify vote has not already been started
-	svp, err := p.voteDetails(token)
+	svp, err := p.voteDetailsBlob(token)
 	if err != nil {
 		return nil, err
 	}
@@ -526,6 +699,13 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 		}
 	}
 
+	// Verify that the vote does not exceed the maximum number of
+	// concurrent votes.
+	concurrent, exceeded, err := p.concurrentVotes()
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare vote details
 	receipt := p.identity.SignMessage([]byte(sd.Signature + vcp.StartBlockHash))
 	vd := ticketvote.VoteDetails{
@@ -547,10 +727,19 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 
 	// Update the cached inventory
 	p.inv.UpdateEntryPostVote(vd.Params.Token,
-		ticketvote.VoteStatusStarted, vd.EndBlockHeight)
+		ticketvote.VoteStatusStarted, vd.StartBlockHeight, vd.EndBlockHeight)
 
 	// Update active votes cache
-	p.activeVotesAdd(vd)
+	err = p.activeVotesAdd(vd)
+	if err != nil {
+		return nil, err
+	}
+
+	if exceeded {
+		log.Warnf("Vote started %v with %v concurrent votes; the "+
+			"concurrent votes max is %v", vd.Params.Token, concurrent,
+			p.concurrentVotesMax)
+	}
 
 	return &ticketvote.StartReply{
 		Receipt:          vd.Receipt,
@@ -558,9 +747,49 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 		StartBlockHash:   vd.StartBlockHash,
 		EndBlockHeight:   vd.EndBlockHeight,
 		EligibleTickets:  vd.EligibleTickets,
+
+		ConcurrentVotes:         concurrent,
+		ConcurrentVotesExceeded: exceeded,
 	}, nil
 }
 
+// concurrentVotes returns the number of votes that will be running
+// concurrently once a new vote has been started and whether this exceeds the
+// concurrent votes max.
+func (p *ticketVotePlugin) concurrentVotes() (uint32, bool, error) {
+	bestBlock, err := p.chain.BestBlock()
+	if err != nil {
+		return 0, false, err
+	}
+	active, err := p.inv.StartedCount(bestBlock)
+	if err != nil {
+		return 0, false, err
+	}
+	return concurrentVotesVerify(active, p.concurrentVotesMax,
+		p.concurrentVotesBlock)
+}
+
+// concurrentVotesVerify returns the number of votes that will be running
+// concurrently once a new vote has been started, given the number of votes
+// that are currently active, and whether this exceeds the provided max. An
+// error is returned instead if the max is exceeded and block is set. A max of
+// zero disables the check.
+func concurrentVotesVerify(active, max uint32, block bool) (uint32, bool, error) {
+	concurrent := active + 1
+	if max == 0 || concurrent <= max {
+		return concurrent, false, nil
+	}
+	if block {
+		return 0, false, backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeConcurrentVotesExceeded),
+			ErrorContext: fmt.Sprintf("%v votes are already running; "+
+				"the concurrent votes max is %v", active, max),
+		}
+	}
+	return concurrent, true, nil
+}
+
 // startRunoffRecordSave saves a startRunoffRecord to the backend.
 func (p *ticketVotePlugin) startRunoffRecordSave(token []byte, srr startRunoffRecord) error {
 	be, err := convertBlobEntryFromStartRunoff(srr)
@@ -577,8 +806,7 @@ func (p *ticketVotePlugin) startRunoffRecordSave(token []byte, srr startRunoffRe
 // startRunoffRecord returns the startRunoff record if one exists. Nil is
 // returned if a startRunoff record is not found.
 func (p *ticketVotePlugin) startRunoffRecord(token []byte) (*startRunoffRecord, error) {
-	blobs, err := p.tstore.BlobsByDataDesc(token,
-		[]string{dataDescriptorStartRunoff})
+	blobs, err := p.blobsByDataDesc(token, dataDescriptorStartRunoff)
 	if err != nil {
 		return nil, err
 	}
@@ -645,7 +873,7 @@ func (p *ticketVotePlugin) startRunoffForSub(token []byte, srs startRunoffSubmis
 	// call were to fail before completing, we can simply call the
 	// command again with the same arguments and it will pick up where
 	// it left off.
-	svp, err := p.voteDetails(token)
+	svp, err := p.voteDetailsBlob(token)
 	if err != nil {
 		return err
 	}
@@ -694,12 +922,10 @@ func (p *ticketVotePlugin) startRunoffForSub(token []byte, srs startRunoffSubmis
 
 	// Update the cached inventory
 	p.inv.UpdateEntryPostVote(vd.Params.Token,
-		ticketvote.VoteStatusStarted, vd.EndBlockHeight)
+		ticketvote.VoteStatusStarted, vd.StartBlockHeight, vd.EndBlockHeight)
 
 	// Update active votes cache
-	p.activeVotesAdd(vd)
-
-	return nil
+	return p.activeVotesAdd(vd)
 }
 
 // startRunoffForParent saves a startRunoffRecord to the parent record. Once
@@ -725,8 +951,9 @@ func (p *ticketVotePlugin) startRunoffForParent(token []byte, s ticketvote.Start
 		duration = s.Starts[0].Params.Duration
 		quorum   = s.Starts[0].Params.QuorumPercentage
 		pass     = s.Starts[0].Params.PassPercentage
+		height   = s.Starts[0].SnapshotHeight
 	)
-	vcp, err := p.voteChainParams(duration)
+	vcp, err := p.voteChainParams(duration, height)
 	if err != nil {
 		return nil, err
 	}
@@ -863,8 +1090,9 @@ func (p *ticketVotePlugin) startRunoffForParent(token []byte, s ticketvote.Start
 // It does this by first adding a startRunoffRecord to the runoff vote parent
 // record. Once this has been successfully added the runoff vote is considered
 // to have started. The voting period must now be started on all of the runoff
-// vote submissions individually. If any of these calls fail, they can be
-// retried.  This function will pick up where it left off.
+// vote submissions individually. If any of these calls fail, the runoff vote
+// start is rolled back. If the rollback also fails, the runoff vote start can
+// be retried and this function will pick up where it left off.
 func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticketvote.StartReply, error) {
 	// Sanity check
 	if len(s.Starts) == 0 {
@@ -879,6 +1107,9 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 		quorum   = s.Starts[0].Params.QuorumPercentage
 		pass     = s.Starts[0].Params.PassPercentage
 		parent   = s.Starts[0].Params.Parent
+		winners  = s.Starts[0].Params.Winners
+		weight   = s.Starts[0].Params.Weight
+		height   = s.Starts[0].SnapshotHeight
 	)
 	for _, v := range s.Starts {
 		// Verify vote params are the same for all submissions
@@ -930,6 +1161,30 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 					"not match; all must be the same",
 					v.Params.Token),
 			}
+		case v.Params.Winners != winners:
+			return nil, backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeRunoffWinnersInvalid),
+				ErrorContext: fmt.Sprintf("%v winners does "+
+					"not match; all must be the same",
+					v.Params.Token),
+			}
+		case v.Params.Weight != weight:
+			return nil, backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeVoteWeightInvalid),
+				ErrorContext: fmt.Sprintf("%v weight does "+
+					"not match; all must be the same",
+					v.Params.Token),
+			}
+		case v.SnapshotHeight != height:
+			return nil, backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeStartDetailsInvalid),
+				ErrorContext: fmt.Sprintf("%v snapshot height does "+
+					"not match; all must be the same",
+					v.Params.Token),
+			}
 		}
 
 		// Verify token
@@ -966,13 +1221,29 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 
 		// Verify vote options and params. Vote optoins are required to
 		// be approve and reject.
-		err = voteParamsVerify(v.Params, p.voteDurationMin,
-			p.voteDurationMax)
+		voteDurationMin, voteDurationMax := p.voteDurationBounds()
+		err = voteParamsVerify(v.Params, voteDurationMin, voteDurationMax)
+		if err != nil {
+			return nil, err
+		}
+		err = p.voteWeightVerify(v.Params)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// Verify the number of winners does not exceed the number of
+	// submissions.
+	if winners != ticketvote.RunoffWinnersAll &&
+		int(winners) > len(s.Starts) {
+		return nil, backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeRunoffWinnersInvalid),
+			ErrorContext: fmt.Sprintf("winners %v exceeds the %v "+
+				"submissions", winners, len(s.Starts)),
+		}
+	}
+
 	// Verify plugin command is being executed on the parent record
 	if hex.EncodeToString(token) != parent {
 		return nil, backend.PluginError{
@@ -990,32 +1261,23 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 		return nil, err
 	}
 
-	// Start the voting period of each runoff vote submissions by using the
-	// internal plugin command startRunoffSubmission.
-	for _, v := range s.Starts {
-		token, err = tokenDecode(v.Params.Token)
-		if err != nil {
-			return nil, err
-		}
-		srs := startRunoffSubmission{
-			ParentToken:  v.Params.Parent,
-			StartDetails: v,
-		}
-		b, err := json.Marshal(srs)
-		if err != nil {
-			return nil, err
-		}
-		_, err = p.backend.PluginWrite(token, ticketvote.PluginID,
-			cmdStartRunoffSubmission, string(b))
-		if err != nil {
-			var ue backend.PluginError
-			if errors.As(err, &ue) {
-				return nil, err
-			}
-			return nil, fmt.Errorf("PluginWrite %x %v %v: %v",
-				token, ticketvote.PluginID,
-				cmdStartRunoffSubmission, err)
+	// Start the voting period of each runoff vote submission. If any
+	// of the submissions fail to start, the runoff vote start is rolled
+	// back so that it does not remain in a partially started state.
+	err = p.startRunoffSubs(s.Starts)
+	if err != nil {
+		log.Errorf("Runoff vote start failed %x: %v", token, err)
+		rerr := p.rollbackRunoff(token, s.Starts)
+		if rerr != nil {
+			// The runoff vote start could not be rolled back. The start
+			// runoff record still exists on the parent record, so the
+			// runoff vote start can be retried and will pick up where
+			// it left off.
+			log.Errorf("Runoff vote rollback failed %x: %v", token, rerr)
+		} else {
+			log.Infof("Runoff vote start rolled back %x", token)
 		}
+		return nil, err
 	}
 
 	return &ticketvote.StartReply{
@@ -1026,48 +1288,243 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 	}, nil
 }
 
-// cmdStartRunoffSubmission is an internal plugin command that is used to start
-// the voting period on a runoff vote submission.
-func (p *ticketVotePlugin) cmdStartRunoffSubmission(token []byte, payload string) (string, error) {
-	// Decode payload
-	var srs startRunoffSubmission
-	err := json.Unmarshal([]byte(payload), &srs)
-	if err != nil {
-		return "", err
+// runoffStartBatches splits the runoff vote submission start details into
+// batches of the provided size.
+func runoffStartBatches(starts []ticketvote.StartDetails, batchSize int) [][]ticketvote.StartDetails {
+	batches := make([][]ticketvote.StartDetails, 0,
+		(len(starts)+batchSize-1)/batchSize)
+	for len(starts) > 0 {
+		n := batchSize
+		if n > len(starts) {
+			n = len(starts)
+		}
+		batches = append(batches, starts[:n])
+		starts = starts[n:]
 	}
+	return batches
+}
 
-	// Start voting period on runoff vote submission
-	err = p.startRunoffForSub(token, srs)
-	if err != nil {
-		return "", err
+// startRunoffSubs starts the voting period of the runoff vote submissions.
+// The submissions are started in batches, with the submissions of a batch
+// being started concurrently by a pool of workers. An error is returned if
+// any of the submissions fail to start. The remaining batches are not started
+// when this happens.
+func (p *ticketVotePlugin) startRunoffSubs(starts []ticketvote.StartDetails) error {
+	batches := runoffStartBatches(starts, int(p.runoffStartBatchSize))
+	for i, batch := range batches {
+		log.Debugf("Starting runoff vote submissions batch %v/%v",
+			i+1, len(batches))
+
+		var (
+			wg   sync.WaitGroup
+			jobs = make(chan ticketvote.StartDetails, len(batch))
+			errs = make(chan error, len(batch))
+		)
+		for _, v := range batch {
+			jobs <- v
+		}
+		close(jobs)
+
+		workers := int(p.runoffStartWorkers)
+		if workers > len(batch) {
+			workers = len(batch)
+		}
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for sd := range jobs {
+					err := p.startRunoffSub(sd)
+					if err != nil {
+						errs <- err
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		// Return the first error that was encountered
+		if err, ok := <-errs; ok {
+			return err
+		}
 	}
 
-	return "", nil
+	return nil
 }
 
-// cmdStart starts a ticket vote.
-func (p *ticketVotePlugin) cmdStart(token []byte, payload string) (string, error) {
-	// Decode payload
-	var s ticketvote.Start
-	err := json.Unmarshal([]byte(payload), &s)
+// startRunoffSub starts the voting period of a runoff vote submission using
+// the internal plugin command startRunoffSubmission.
+func (p *ticketVotePlugin) startRunoffSub(sd ticketvote.StartDetails) error {
+	token, err := tokenDecode(sd.Params.Token)
 	if err != nil {
-		return "", err
+		return err
+	}
+	srs := startRunoffSubmission{
+		ParentToken:  sd.Params.Parent,
+		StartDetails: sd,
 	}
+	b, err := json.Marshal(srs)
+	if err != nil {
+		return err
+	}
+	_, err = p.backend.PluginWrite(token, ticketvote.PluginID,
+		cmdStartRunoffSubmission, string(b))
+	if err != nil {
+		var ue backend.PluginError
+		if errors.As(err, &ue) {
+			return err
+		}
+		return fmt.Errorf("PluginWrite %x %v %v: %v",
+			token, ticketvote.PluginID,
+			cmdStartRunoffSubmission, err)
+	}
+	return nil
+}
 
-	// Parse vote type
-	if len(s.Starts) == 0 {
-		return "", backend.PluginError{
-			PluginID:     ticketvote.PluginID,
-			ErrorCode:    uint32(ticketvote.ErrorCodeStartDetailsMissing),
-			ErrorContext: "no start details found",
+// rollbackRunoff rolls back a runoff vote start that failed to start the
+// voting period on all of the runoff vote submissions. The vote details of
+// the submissions that were started and the start runoff record of the parent
+// record are deleted so that the runoff vote can be started again from
+// scratch.
+//
+// A rollback is not performed if votes have already been cast on any of the
+// submissions. The runoff vote start must be retried instead.
+func (p *ticketVotePlugin) rollbackRunoff(parent []byte, starts []ticketvote.StartDetails) error {
+	// Verify that no votes have been cast
+	for _, v := range starts {
+		if len(p.activeVotes.Tally(v.Params.Token)) > 0 {
+			return fmt.Errorf("votes have been cast on %v", v.Params.Token)
 		}
 	}
-	vtype := s.Starts[0].Params.Type
 
-	// Start vote
+	// Roll back the submissions using the internal plugin command
+	// rollbackRunoffSubmission.
+	rrs := rollbackRunoffSubmission{
+		ParentToken: hex.EncodeToString(parent),
+	}
+	b, err := json.Marshal(rrs)
+	if err != nil {
+		return err
+	}
+	for _, v := range starts {
+		token, err := tokenDecode(v.Params.Token)
+		if err != nil {
+			return err
+		}
+		_, err = p.backend.PluginWrite(token, ticketvote.PluginID,
+			cmdRollbackRunoffSub, string(b))
+		if err != nil {
+			return fmt.Errorf("PluginWrite %x %v %v: %v",
+				token, ticketvote.PluginID, cmdRollbackRunoffSub, err)
+		}
+	}
+
+	// Delete the start runoff record from the parent record
+	digests, err := p.tstore.DigestsByDataDesc(parent,
+		[]string{dataDescriptorStartRunoff})
+	if err != nil {
+		return err
+	}
+	return p.tstore.BlobsDel(parent, digests)
+}
+
+// cmdRollbackRunoffSubmission is an internal plugin command that is used to
+// roll back the start of the voting period on a runoff vote submission.
+func (p *ticketVotePlugin) cmdRollbackRunoffSubmission(token []byte, payload string) (string, error) {
+	// Decode payload
+	var rrs rollbackRunoffSubmission
+	err := json.Unmarshal([]byte(payload), &rrs)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the vote details
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		// The voting period was never started. There is
+		// nothing to roll back.
+		return "", nil
+	}
+
+	// Sanity checks
+	if vd.Params.Parent != rrs.ParentToken {
+		return "", fmt.Errorf("parent token mismatch: got %v, want %v",
+			rrs.ParentToken, vd.Params.Parent)
+	}
+	if len(p.activeVotes.Tally(vd.Params.Token)) > 0 {
+		return "", fmt.Errorf("votes have been cast")
+	}
+
+	// Delete the vote details and the eligible ticket snapshot
+	digests, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorVoteDetails, dataDescriptorSnapshot})
+	if err != nil {
+		return "", err
+	}
+	err = p.tstore.BlobsDel(token, digests)
+	if err != nil {
+		return "", err
+	}
+
+	// Update the caches
+	p.activeVotes.Del(vd.Params.Token)
+	p.inv.UpdateEntryPreVote(vd.Params.Token,
+		ticketvote.VoteStatusUnauthorized, time.Now().Unix())
+
+	return "", nil
+}
+
+// cmdStartRunoffSubmission is an internal plugin command that is used to start
+// the voting period on a runoff vote submission.
+func (p *ticketVotePlugin) cmdStartRunoffSubmission(token []byte, payload string) (string, error) {
+	// Decode payload
+	var srs startRunoffSubmission
+	err := json.Unmarshal([]byte(payload), &srs)
+	if err != nil {
+		return "", err
+	}
+
+	// Start voting period on runoff vote submission
+	err = p.startRunoffForSub(token, srs)
+	if err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+// cmdStart starts a ticket vote.
+func (p *ticketVotePlugin) cmdStart(token []byte, payload string) (string, error) {
+	// Decode payload
+	var s ticketvote.Start
+	err := json.Unmarshal([]byte(payload), &s)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse vote type
+	if len(s.Starts) == 0 {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeStartDetailsMissing),
+			ErrorContext: "no start details found",
+		}
+	}
+	vtype := s.Starts[0].Params.Type
+
+	// Prevent the active votes cache from being rebuilt while
+	// the vote is being started.
+	p.activeVotesMtx.RLock()
+	defer p.activeVotesMtx.RUnlock()
+
+	// Start vote
 	var sr *ticketvote.StartReply
 	switch vtype {
-	case ticketvote.VoteTypeStandard:
+	case ticketvote.VoteTypeStandard, ticketvote.VoteTypeMultiOption:
 		sr, err = p.startStandard(token, s)
 		if err != nil {
 			return "", err
@@ -1093,72 +1550,368 @@ func (p *ticketVotePlugin) cmdStart(token []byte, payload string) (string, error
 	return string(reply), nil
 }
 
-// commitmentAddr represents the largest commitment address for a dcr ticket.
-type commitmentAddr struct {
-	addr string // Commitment address
-	err  error  // Error if one occurred
+// cmdVoteLoad returns the number of votes that are currently running.
+func (p *ticketVotePlugin) cmdVoteLoad() (string, error) {
+	// Get the best block. This command does not write
+	// any data so we can use the unsafe best block.
+	bestBlock, err := p.chain.BestBlockUnsafe()
+	if err != nil {
+		return "", err
+	}
+	active, err := p.inv.StartedCount(bestBlock)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(ticketvote.VoteLoadReply{
+		ActiveVotes: active,
+		BestBlock:   bestBlock,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
 }
 
-// largestCommitmentAddrs retrieves the largest commitment addresses for each
-// of the provided tickets from dcrdata. A map[ticket]commitmentAddr is
-// returned. If an error is encountered while retrieving a commitment address,
-// the error will be included in the commitmentAddr struct in the returned
-// map.
-func (p *ticketVotePlugin) largestCommitmentAddrs(tickets []string) (map[string]commitmentAddr, error) {
-	// Get tx details
-	tt := dcrdata.TxsTrimmed{
-		TxIDs: tickets,
+// cmdVoteParamsVerify verifies the provided vote params without starting a
+// vote. The params are verified using the same checks as the start command,
+// excluding the checks that require a record.
+func (p *ticketVotePlugin) cmdVoteParamsVerify(payload string) (string, error) {
+	// Decode payload
+	var vpv ticketvote.VoteParamsVerify
+	err := json.Unmarshal([]byte(payload), &vpv)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the params
+	voteDurationMin, voteDurationMax := p.voteDurationBounds()
+	err = voteParamsVerify(vpv.Params, voteDurationMin, voteDurationMax)
+	if err == nil {
+		err = p.voteWeightVerify(vpv.Params)
+	}
+	vpvr := ticketvote.VoteParamsVerifyReply{
+		Valid:         true,
+		VoteBitErrors: voteBitsVerify(vpv.Params),
 	}
-	payload, err := json.Marshal(tt)
 	if err != nil {
-		return nil, err
+		var pe backend.PluginError
+		if !errors.As(err, &pe) {
+			return "", err
+		}
+		vpvr.Valid = false
+		vpvr.ErrorCode = ticketvote.ErrorCodeT(pe.ErrorCode)
+		vpvr.ErrorContext = pe.ErrorContext
 	}
-	reply, err := p.backend.PluginRead(nil, dcrdata.PluginID,
-		dcrdata.CmdTxsTrimmed, string(payload))
+
+	// Prepare reply
+	reply, err := json.Marshal(vpvr)
 	if err != nil {
-		return nil, fmt.Errorf("PluginRead %v %v: %v",
-			dcrdata.PluginID, dcrdata.CmdTxsTrimmed, err)
+		return "", err
 	}
-	var ttr dcrdata.TxsTrimmedReply
-	err = json.Unmarshal([]byte(reply), &ttr)
+
+	return string(reply), nil
+}
+
+// cmdExtend extends the end block height of an active ticket vote.
+func (p *ticketVotePlugin) cmdExtend(token []byte, payload string) (string, error) {
+	// Decode payload
+	var e ticketvote.Extend
+	err := json.Unmarshal([]byte(payload), &e)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	// Find the largest commitment address for each tx
-	addrs := make(map[string]commitmentAddr, len(ttr.Txs))
-	for _, tx := range ttr.Txs {
-		var (
-			bestAddr string  // Addr with largest commitment amount
-			bestAmt  float64 // Largest commitment amount
-			addrErr  error   // Error if one is encountered
-		)
-		for _, vout := range tx.Vout {
-			scriptPubKey := vout.ScriptPubKeyDecoded
-			switch {
-			case scriptPubKey.CommitAmt == nil:
-				// No commitment amount; continue
-			case len(scriptPubKey.Addresses) == 0:
-				// No commitment address; continue
-			case *scriptPubKey.CommitAmt > bestAmt:
-				// New largest commitment address found
-				bestAddr = scriptPubKey.Addresses[0]
-				bestAmt = *scriptPubKey.CommitAmt
-			}
+	// Verify token
+	err = tokenVerify(token, e.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify signature
+	endHeight := strconv.FormatUint(uint64(e.EndBlockHeight), 10)
+	msg := e.Token + endHeight + e.Reason
+	err = util.VerifySignature(e.Signature, e.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Verify reason
+	if strings.TrimSpace(e.Reason) == "" {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteExtensionInvalid),
+			ErrorContext: "reason not provided",
 		}
-		if bestAddr == "" || bestAmt == 0.0 {
-			addrErr = fmt.Errorf("no largest commitment address " +
-				"found")
+	}
+
+	// Verify the vote is active
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has not been started",
+		}
+	}
+	if vd.Params.Type == ticketvote.VoteTypeRunoff {
+		// The runoff submissions must all share the same voting
+		// period.
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteExtensionInvalid),
+			ErrorContext: "runoff votes cannot be extended",
+		}
+	}
+	exts, err := p.voteExtensions(token)
+	if err != nil {
+		return "", err
+	}
+	prevEndHeight := voteEndBlockHeight(*vd, exts)
+	bestBlock, err := p.chain.BestBlock()
+	if err != nil {
+		return "", err
+	}
+	if voteHasEnded(bestBlock, prevEndHeight) {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has ended",
 		}
+	}
+
+	// A vote that was finished early already has a final summary
+	fs, err := p.summaryFinal(token)
+	if err != nil {
+		return "", err
+	}
+	if fs != nil {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has finished early",
+		}
+	}
 
-		// Store result
-		addrs[tx.TxID] = commitmentAddr{
-			addr: bestAddr,
-			err:  addrErr,
+	// Verify the new end block height
+	switch {
+	case e.EndBlockHeight <= prevEndHeight:
+		return "", backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteExtensionInvalid),
+			ErrorContext: fmt.Sprintf("end block height must be greater "+
+				"than %v", prevEndHeight),
+		}
+	case e.EndBlockHeight-vd.EndBlockHeight > p.voteExtensionMax:
+		return "", backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteExtensionInvalid),
+			ErrorContext: fmt.Sprintf("total extension exceeds max of "+
+				"%v blocks; end block height must be <= %v",
+				p.voteExtensionMax, vd.EndBlockHeight+p.voteExtensionMax),
 		}
 	}
 
-	return addrs, nil
+	// Save extend details
+	receipt := p.identity.SignMessage([]byte(e.Signature))
+	ed := ticketvote.ExtendDetails{
+		Token:              e.Token,
+		EndBlockHeight:     e.EndBlockHeight,
+		Reason:             e.Reason,
+		PublicKey:          e.PublicKey,
+		Signature:          e.Signature,
+		PrevEndBlockHeight: prevEndHeight,
+		Timestamp:          time.Now().Unix(),
+		Receipt:            hex.EncodeToString(receipt[:]),
+	}
+	err = p.extendSave(token, ed)
+	if err != nil {
+		return "", err
+	}
+
+	// Update the active votes cache and the cached inventory
+	p.activeVotes.SetEndBlockHeight(e.Token, e.EndBlockHeight)
+	p.inv.UpdateEntryPostVote(e.Token, ticketvote.VoteStatusStarted,
+		vd.StartBlockHeight, e.EndBlockHeight)
+
+	log.Infof("Vote extended %v from block %v to %v: %v",
+		e.Token, prevEndHeight, e.EndBlockHeight, e.Reason)
+
+	// Prepare reply
+	er := ticketvote.ExtendReply{
+		Timestamp: ed.Timestamp,
+		Receipt:   ed.Receipt,
+	}
+	reply, err := json.Marshal(er)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdCancel cancels a started ticket vote that has not had any ballots cast.
+// The vote details, the eligible ticket snapshot, and any vote extensions are
+// deleted and a signed cancellation record is saved in their place so that
+// the vote can be started again.
+func (p *ticketVotePlugin) cmdCancel(token []byte, payload string) (string, error) {
+	// Decode payload
+	var c ticketvote.Cancel
+	err := json.Unmarshal([]byte(payload), &c)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify token
+	err = tokenVerify(token, c.Token)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify signature
+	msg := c.Token + c.Reason
+	err = util.VerifySignature(c.Signature, c.PublicKey, msg)
+	if err != nil {
+		return "", convertSignatureError(err)
+	}
+
+	// Verify reason
+	if strings.TrimSpace(c.Reason) == "" {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteCancelInvalid),
+			ErrorContext: "reason not provided",
+		}
+	}
+
+	// Prevent the active votes cache from being rebuilt while
+	// the vote is being cancelled.
+	p.activeVotesMtx.RLock()
+	defer p.activeVotesMtx.RUnlock()
+
+	// Verify the vote is active
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has not been started",
+		}
+	}
+	if vd.Params.Type == ticketvote.VoteTypeRunoff {
+		// The runoff submissions must all share the same voting
+		// period.
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteCancelInvalid),
+			ErrorContext: "runoff votes cannot be cancelled",
+		}
+	}
+	exts, err := p.voteExtensions(token)
+	if err != nil {
+		return "", err
+	}
+	endHeight := voteEndBlockHeight(*vd, exts)
+	bestBlock, err := p.chain.BestBlock()
+	if err != nil {
+		return "", err
+	}
+	if voteHasEnded(bestBlock, endHeight) {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has ended",
+		}
+	}
+
+	// Verify that no ballots have been cast. The cast votes are
+	// checked in tstore instead of the active votes cache since
+	// tstore is the source of truth. Ballots cannot be cast while
+	// this command is executing since plugin writes hold the
+	// record lock.
+	digests, err := p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorCastVoteDetails, dataDescriptorVoteCollider})
+	if err != nil {
+		return "", err
+	}
+	if len(digests) > 0 {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteCancelInvalid),
+			ErrorContext: "ballots have been cast",
+		}
+	}
+
+	// Save cancel details
+	receipt := p.identity.SignMessage([]byte(c.Signature))
+	cd := ticketvote.CancelDetails{
+		Token:            c.Token,
+		Reason:           c.Reason,
+		PublicKey:        c.PublicKey,
+		Signature:        c.Signature,
+		StartBlockHeight: vd.StartBlockHeight,
+		EndBlockHeight:   endHeight,
+		Timestamp:        time.Now().Unix(),
+		Receipt:          hex.EncodeToString(receipt[:]),
+	}
+	err = p.cancelSave(token, cd)
+	if err != nil {
+		return "", err
+	}
+
+	// Delete the vote details, the eligible ticket snapshot, and
+	// the vote extensions.
+	digests, err = p.tstore.DigestsByDataDesc(token,
+		[]string{dataDescriptorVoteDetails, dataDescriptorSnapshot,
+			dataDescriptorExtendDetails})
+	if err != nil {
+		return "", err
+	}
+	err = p.tstore.BlobsDel(token, digests)
+	if err != nil {
+		return "", err
+	}
+
+	// Determine the vote status that the record is returned to. A
+	// standard vote cannot be started without an authorization and
+	// an authorization cannot be revoked once the vote has started,
+	// so this should always be authorized.
+	status := ticketvote.VoteStatusUnauthorized
+	auths, err := p.auths(token)
+	if err != nil {
+		return "", err
+	}
+	if len(auths) > 0 && ticketvote.AuthActionT(auths[len(auths)-1].Action) ==
+		ticketvote.AuthActionAuthorize {
+		status = ticketvote.VoteStatusAuthorized
+	}
+
+	// Update the caches
+	p.activeVotes.Del(c.Token)
+	p.inv.UpdateEntryPreVote(c.Token, status, cd.Timestamp)
+
+	log.Infof("Vote cancelled %v at block %v: %v",
+		c.Token, bestBlock, c.Reason)
+
+	// Prepare reply
+	cr := ticketvote.CancelReply{
+		Timestamp: cd.Timestamp,
+		Receipt:   cd.Receipt,
+	}
+	reply, err := json.Marshal(cr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
 }
 
 // voteCollider is used to prevent duplicate votes at the tlog level. The
@@ -1174,44 +1927,31 @@ type voteCollider struct {
 	Ticket string `json:"ticket"` // Ticket hash
 }
 
-// voteColliderSave saves a voteCollider to the backend.
-func (p *ticketVotePlugin) voteColliderSave(token []byte, vc voteCollider) error {
-	// Prepare blob
-	be, err := convertBlobEntryFromVoteCollider(vc)
-	if err != nil {
-		return err
-	}
-
-	// Save blob
-	return p.tstore.BlobSave(token, *be)
-}
-
-// ballotResults is used to aggregate data for votes that are cast
-// concurrently.
+// ballotResults is used to aggregate the data for the votes of a ballot.
 type ballotResults struct {
 	sync.RWMutex
-	addrs   map[string]string                   // [ticket]commitmentAddr
+	addrs   map[string]commitmentAddr           // [ticket]commitmentAddr
 	replies map[string]ticketvote.CastVoteReply // [ticket]CastVoteReply
 }
 
 // newBallotResults returns a new ballotResults context.
 func newBallotResults() ballotResults {
 	return ballotResults{
-		addrs:   make(map[string]string, 40960),
+		addrs:   make(map[string]commitmentAddr, 40960),
 		replies: make(map[string]ticketvote.CastVoteReply, 40960),
 	}
 }
 
-// addrSet sets the largest commitment addresss for a ticket.
-func (r *ballotResults) addrSet(ticket, commitmentAddr string) {
+// addrSet sets the largest commitment addresss and the stake for a ticket.
+func (r *ballotResults) addrSet(ticket string, ca commitmentAddr) {
 	r.Lock()
 	defer r.Unlock()
 
-	r.addrs[ticket] = commitmentAddr
+	r.addrs[ticket] = ca
 }
 
-// addrGet returns the largest commitment address for a ticket.
-func (r *ballotResults) addrGet(ticket string) (string, bool) {
+// addrGet returns the largest commitment address and the stake for a ticket.
+func (r *ballotResults) addrGet(ticket string) (commitmentAddr, bool) {
 	r.RLock()
 	defer r.RUnlock()
 
@@ -1244,147 +1984,270 @@ func (r *ballotResults) repliesLen() int {
 	return len(r.replies)
 }
 
-// castVoteDetailsSave saves a CastVoteDetails to the backend.
-func (p *ticketVotePlugin) castVoteDetailsSave(token []byte, cv ticketvote.CastVoteDetails) error {
-	// Prepare blob
-	be, err := convertBlobEntryFromCastVoteDetails(cv)
+// voteChainParams returns the chain params for a vote that is being started
+// with the provided duration. The eligible tickets are retrieved for the
+// snapshot block of the vote. A non-zero snapshot height overrides the
+// snapshot block of the vote.
+func (p *ticketVotePlugin) voteChainParams(duration, snapshotHeight uint32) (*voteChainParams, error) {
+	err := p.snapshotHeightVerify(snapshotHeight)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	vcp, err := p.chain.VoteChainParams(duration, snapshotHeight)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := p.eligibility.EligibleTickets(vcp.StartBlockHash)
+	if err != nil {
+		return nil, err
 	}
+	if len(tickets) == 0 {
+		return nil, fmt.Errorf("no tickets found for block %v %v",
+			vcp.StartBlockHeight, vcp.StartBlockHash)
+	}
+	vcp.EligibleTickets = tickets
+	return vcp, nil
+}
 
-	// Save blob
-	return p.tstore.BlobSave(token, *be)
+// snapshotHeightVerify verifies that a snapshot height override is allowed.
+// The override must be enabled using the snapshot height override plugin
+// setting, which is only allowed on test networks.
+func (p *ticketVotePlugin) snapshotHeightVerify(snapshotHeight uint32) error {
+	if snapshotHeight == 0 || p.snapshotHeightOverride {
+		return nil
+	}
+	return backend.PluginError{
+		PluginID:     ticketvote.PluginID,
+		ErrorCode:    uint32(ticketvote.ErrorCodeStartDetailsInvalid),
+		ErrorContext: "snapshot height override is not enabled",
+	}
 }
 
 // castVoteVerifySignature verifies the signature of a CastVote. The signature
-// must be created using the largest commitment address from the ticket that is
-// casting a vote.
-func castVoteVerifySignature(cv ticketvote.CastVote, addr string, net *chaincfg.Params) error {
+// must be created using the commitment address of the ticket that is casting
+// a vote.
+func (p *ticketVotePlugin) castVoteVerifySignature(cv ticketvote.CastVote, addr string) error {
 	msg := cv.Token + cv.Ticket + cv.VoteBit
+	return p.eligibility.VerifySignature(addr, msg, cv.Signature)
+}
+
+// castVoteVerify verifies the largest commitment address and the signature
+// of a cast vote. A CastVoteReply containing the error is returned if the
+// vote is invalid. The commitment address of a valid vote is stashed in the
+// ballot results so that it can be added to the CastVoteDetails before the
+// vote is written to disk.
+func (p *ticketVotePlugin) castVoteVerify(v ticketvote.CastVote, addrs map[string]commitmentAddr, vd *ticketvote.VoteDetails, bestBlock uint32, br *ballotResults) *ticketvote.CastVoteReply {
+	commitmentAddr, ok := addrs[v.Ticket]
+	if !ok {
+		cvr := castVoteReplyInternalError(v.Ticket,
+			errors.Errorf("commitment addr not found"))
+		return &cvr
+	}
+	if commitmentAddr.err != nil {
+		cvr := castVoteReplyInternalError(v.Ticket,
+			errors.Errorf("commitment addr error: %v", commitmentAddr.err))
+		return &cvr
+	}
+	err := p.castVoteVerifySignature(v, commitmentAddr.addr)
+	if err != nil {
+		e := ticketvote.VoteErrorSignatureInvalid
+		d := voteDiagnostics("vote must be signed using the ticket "+
+			"commitment address", vd, bestBlock)
+		d.CommitmentAddr = commitmentAddr.addr
+		return &ticketvote.CastVoteReply{
+			Ticket:       v.Ticket,
+			ErrorCode:    &e,
+			ErrorContext: fmt.Sprintf("%v: %v", ticketvote.VoteErrors[e], err),
+			Diagnostics:  d,
+		}
+	}
+
+	// The ticket stake is only saved for stake weighted votes
+	if vd.Params.Weight == ticketvote.VoteWeightStake {
+		if commitmentAddr.stake == 0 {
+			cvr := castVoteReplyInternalError(v.Ticket,
+				errors.Errorf("ticket stake not found"))
+			return &cvr
+		}
+	} else {
+		commitmentAddr.stake = 0
+	}
+
+	br.addrSet(v.Ticket, commitmentAddr)
+
+	return nil
+}
 
-	// Convert hex signature to base64. This is what the verify
-	// message function expects.
-	b, err := hex.DecodeString(cv.Signature)
-	if err != nil {
-		return fmt.Errorf("invalid hex")
+// castVotesVerify verifies the largest commitment addresses and the
+// signatures of the provided votes. Signature verification is CPU bound and
+// ballots can contain hundreds of votes, so the votes are verified
+// concurrently by a bounded pool of workers. Votes that already have an error
+// in their receipt are skipped. The receipts of votes that fail verification
+// are updated with the error. Each worker only writes to the receipts of the
+// votes that it verifies, so the receipts keep the order of the ballot.
+func (p *ticketVotePlugin) castVotesVerify(votes []ticketvote.CastVote, receipts []ticketvote.CastVoteReply, addrs map[string]commitmentAddr, vd *ticketvote.VoteDetails, bestBlock uint32, br *ballotResults) {
+	jobs := make(chan int, len(votes))
+	for k := range votes {
+		if receipts[k].ErrorCode != nil {
+			// Vote has an error. Skip it.
+			continue
+		}
+		jobs <- k
 	}
-	sig := base64.StdEncoding.EncodeToString(b)
+	close(jobs)
 
-	// Verify message
-	validated, err := util.VerifyMessage(addr, msg, sig, net)
-	if err != nil {
-		return err
-	}
-	if !validated {
-		return fmt.Errorf("could not verify message")
+	workers := int(p.castBallotWorkers)
+	if workers > len(jobs) {
+		workers = len(jobs)
 	}
 
-	return nil
-}
+	log.Debugf("Verifying %v cast votes using %v workers",
+		len(jobs), workers)
 
-// ballot casts the provided votes concurrently. The vote results are passed
-// back through the results channel to the calling function. This function
-// waits until all provided votes have been cast before returning.
-func (p *ticketVotePlugin) ballot(token []byte, votes []ticketvote.CastVote, br *ballotResults) {
-	// Cast the votes concurrently
 	var wg sync.WaitGroup
-	for _, v := range votes {
-		// Increment the wait group counter
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-
-		go func(v ticketvote.CastVote, br *ballotResults) {
-			// Decrement wait group counter once vote is cast
+		go func() {
 			defer wg.Done()
-
-			// Declare here to prevent goto errors
-			var (
-				cvd ticketvote.CastVoteDetails
-				cvr ticketvote.CastVoteReply
-				vc  voteCollider
-				err error
-
-				receipt = p.identity.SignMessage([]byte(v.Signature))
-			)
-
-			addr, ok := br.addrGet(v.Ticket)
-			if !ok || addr == "" {
-				// Something went wrong. The largest commitment
-				// address could not be found for this ticket.
-				t := time.Now().Unix()
-				log.Errorf("cmdCastBallot: commitment addr not "+
-					"found %v", t)
-				e := ticketvote.VoteErrorInternalError
-				cvr.Ticket = v.Ticket
-				cvr.ErrorCode = &e
-				cvr.ErrorContext = fmt.Sprintf("%v: %v",
-					ticketvote.VoteErrors[e], t)
-				goto saveReply
-			}
-
-			// Setup cast vote details
-			cvd = ticketvote.CastVoteDetails{
-				Token:     v.Token,
-				Ticket:    v.Ticket,
-				VoteBit:   v.VoteBit,
-				Signature: v.Signature,
-				Address:   addr,
-				Receipt:   hex.EncodeToString(receipt[:]),
-				Timestamp: time.Now().Unix(),
+			for k := range jobs {
+				cvr := p.castVoteVerify(votes[k], addrs, vd, bestBlock, br)
+				if cvr != nil {
+					receipts[k] = *cvr
+				}
 			}
+		}()
+	}
+	wg.Wait()
+}
 
-			// Save cast vote details
-			err = p.castVoteDetailsSave(token, cvd)
-			if errors.Is(err, backend.ErrDuplicatePayload) {
-				// This cast vote has already been saved. Its
-				// possible that a previous attempt to vote
-				// with this ticket failed before the vote
-				// collider could be saved. Continue execution
-				// so that we re-attempt to save the vote
-				// collider.
-			} else if err != nil {
-				t := time.Now().Unix()
-				log.Errorf("cmdCastBallot: castVoteSave %v: "+
-					"%v", t, err)
-				e := ticketvote.VoteErrorInternalError
-				cvr.Ticket = v.Ticket
-				cvr.ErrorCode = &e
-				cvr.ErrorContext = fmt.Sprintf("%v: %v",
-					ticketvote.VoteErrors[e], t)
-				goto saveReply
-			}
+// castVoteReplyInternalError logs the provided error and returns a
+// CastVoteReply that contains an internal error for the ticket. The error is
+// logged using a timestamp that is included in the reply so that it can be
+// looked up in the logs.
+func castVoteReplyInternalError(ticket string, err error) ticketvote.CastVoteReply {
+	t := time.Now().Unix()
+	log.Errorf("cmdCastBallot: %v: %v %v", t, ticket, err)
+	e := ticketvote.VoteErrorInternalError
+	return ticketvote.CastVoteReply{
+		Ticket:       ticket,
+		ErrorCode:    &e,
+		ErrorContext: fmt.Sprintf("%v: %v", ticketvote.VoteErrors[e], t),
+	}
+}
 
-			// Save vote collider
-			vc = voteCollider{
-				Token:  v.Token,
-				Ticket: v.Ticket,
-			}
-			err = p.voteColliderSave(token, vc)
-			if err != nil {
-				t := time.Now().Unix()
-				log.Errorf("cmdCastBallot: voteColliderSave %v: %v", t, err)
-				e := ticketvote.VoteErrorInternalError
-				cvr.Ticket = v.Ticket
-				cvr.ErrorCode = &e
-				cvr.ErrorContext = fmt.Sprintf("%v: %v",
-					ticketvote.VoteErrors[e], t)
-				goto saveReply
-			}
+// ballot casts the provided votes. The cast vote details of all votes are
+// saved to the backend using a single batched tlog append, followed by a
+// second batched append for the vote colliders. A cast vote is only
+// considered valid once its vote collider has been saved, so the vote
+// colliders are not saved until the cast votes have been appended. The vote
+// results are passed back to the calling function using the ballot results.
+func (p *ticketVotePlugin) ballot(token []byte, votes []ticketvote.CastVote, br *ballotResults) {
+	// Prepare the cast vote details
+	var (
+		cvds  = make([]ticketvote.CastVoteDetails, 0, len(votes))
+		blobs = make([]store.BlobEntry, 0, len(votes))
+	)
+	for _, v := range votes {
+		ca, ok := br.addrGet(v.Ticket)
+		if !ok || ca.addr == "" {
+			// Something went wrong. The largest commitment
+			// address could not be found for this ticket.
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket,
+				errors.Errorf("commitment addr not found")))
+			continue
+		}
+		receipt := p.identity.SignMessage([]byte(v.Signature))
+		cvd := ticketvote.CastVoteDetails{
+			Token:     v.Token,
+			Ticket:    v.Ticket,
+			VoteBit:   v.VoteBit,
+			Signature: v.Signature,
+			Address:   ca.addr,
+			Receipt:   hex.EncodeToString(receipt[:]),
+			Timestamp: time.Now().Unix(),
+			Stake:     ca.stake,
+		}
+		be, err := convertBlobEntryFromCastVoteDetails(cvd)
+		if err != nil {
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket, err))
+			continue
+		}
+		cvds = append(cvds, cvd)
+		blobs = append(blobs, *be)
+	}
+	if len(cvds) == 0 {
+		return
+	}
 
-			// Update receipt
-			cvr.Ticket = v.Ticket
-			cvr.Receipt = cvd.Receipt
+	// Save the cast vote details
+	errs, err := p.tstore.BlobsSave(token, blobs)
+	if err != nil {
+		for _, v := range cvds {
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket,
+				errors.Errorf("cast votes save: %v", err)))
+		}
+		return
+	}
 
-			// Update cast votes cache
-			p.activeVotes.AddCastVote(v.Token, v.Ticket, v.VoteBit)
+	// Prepare the vote colliders for the cast votes that were saved
+	var (
+		saved     = make([]ticketvote.CastVoteDetails, 0, len(cvds))
+		colliders = make([]store.BlobEntry, 0, len(cvds))
+	)
+	for i, v := range cvds {
+		switch {
+		case errs[i] == nil:
+			// The cast vote was saved; continue
+		case errors.Is(errs[i], backend.ErrDuplicatePayload):
+			// This cast vote has already been saved. Its possible
+			// that a previous attempt to vote with this ticket
+			// failed before the vote collider could be saved.
+			// Continue execution so that we re-attempt to save
+			// the vote collider.
+		default:
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket,
+				errors.Errorf("cast vote save: %v", errs[i])))
+			continue
+		}
+		vc := voteCollider{
+			Token:  v.Token,
+			Ticket: v.Ticket,
+		}
+		be, err := convertBlobEntryFromVoteCollider(vc)
+		if err != nil {
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket, err))
+			continue
+		}
+		saved = append(saved, v)
+		colliders = append(colliders, *be)
+	}
+	if len(saved) == 0 {
+		return
+	}
 
-		saveReply:
-			// Save the reply
-			br.replySet(v.Ticket, cvr)
-		}(v, br)
+	// Save the vote colliders
+	errs, err = p.tstore.BlobsSave(token, colliders)
+	if err != nil {
+		for _, v := range saved {
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket,
+				errors.Errorf("vote colliders save: %v", err)))
+		}
+		return
 	}
+	for i, v := range saved {
+		if errs[i] != nil {
+			br.replySet(v.Ticket, castVoteReplyInternalError(v.Ticket,
+				errors.Errorf("vote collider save: %v", errs[i])))
+			continue
+		}
 
-	// Wait for the full ballot to be cast before returning.
-	wg.Wait()
+		// Save the receipt and update the cast votes cache
+		br.replySet(v.Ticket, ticketvote.CastVoteReply{
+			Ticket:  v.Ticket,
+			Receipt: v.Receipt,
+		})
+		p.activeVotes.AddCastVote(v.Token, v.Ticket, v.VoteBit)
+	}
 }
 
 // cmdCastBallot casts a ballot of votes. This function will not return a user
@@ -1413,9 +2276,9 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 	}
 
 	// Get the data that we need to validate the votes
-	eligible := p.activeVotes.EligibleTickets(token)
+	eligible := p.activeVotes.Snapshot(token)
 	voteDetails := p.activeVotes.VoteDetails(token)
-	bestBlock, err := p.bestBlock()
+	bestBlock, err := p.chain.BestBlock()
 	if err != nil {
 		return "", err
 	}
@@ -1451,6 +2314,8 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 			receipts[k].ErrorCode = &e
 			receipts[k].ErrorContext = fmt.Sprintf("%v: vote is "+
 				"not active", ticketvote.VoteErrors[e])
+			receipts[k].Diagnostics = voteDiagnostics("vote is not active",
+				nil, bestBlock)
 			continue
 		}
 		if voteHasEnded(bestBlock, voteDetails.EndBlockHeight) {
@@ -1459,6 +2324,8 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 			receipts[k].ErrorCode = &e
 			receipts[k].ErrorContext = fmt.Sprintf("%v: vote has "+
 				"ended", ticketvote.VoteErrors[e])
+			receipts[k].Diagnostics = voteDiagnostics("vote has ended",
+				voteDetails, bestBlock)
 			continue
 		}
 
@@ -1479,16 +2346,20 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 			receipts[k].ErrorCode = &e
 			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
 				ticketvote.VoteErrors[e], err)
+			receipts[k].Diagnostics = voteDiagnostics(err.Error(),
+				voteDetails, bestBlock)
 			continue
 		}
 
 		// Verify ticket is eligible to vote
-		_, ok := eligible[v.Ticket]
-		if !ok {
+		if !eligible.contains(v.Ticket) {
 			e := ticketvote.VoteErrorTicketNotEligible
 			receipts[k].Ticket = v.Ticket
 			receipts[k].ErrorCode = &e
 			receipts[k].ErrorContext = ticketvote.VoteErrors[e]
+			receipts[k].Diagnostics = voteDiagnostics(
+				ineligibleReason(v.Ticket, voteDetails.StartBlockHeight),
+				voteDetails, bestBlock)
 			continue
 		}
 
@@ -1506,12 +2377,14 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 			receipts[k].Ticket = v.Ticket
 			receipts[k].ErrorCode = &e
 			receipts[k].ErrorContext = ticketvote.VoteErrors[e]
+			receipts[k].Diagnostics = voteDiagnostics("a vote has "+
+				"already been cast for this ticket", voteDetails, bestBlock)
 			continue
 		}
 	}
 
 	// Setup a ballotResults context. This is used to aggregate the
-	// cast vote results when votes are cast concurrently.
+	// cast vote results of the ballot batches.
 	br := newBallotResults()
 
 	// Get the largest commitment address for each ticket and verify
@@ -1540,76 +2413,43 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 		len(tickets)-len(notInCache), len(tickets))
 
 	if len(notInCache) > 0 {
-		// Get commitment addresses from dcrdata
-		caddrs, err := p.largestCommitmentAddrs(tickets)
+		// Get the commitment addresses. The vote details are
+		// guaranteed to exist if there are votes without errors.
+		caddrs, err := p.commitmentAddrs(notInCache,
+			voteDetails.StartBlockHeight)
 		if err != nil {
-			return "", fmt.Errorf("largestCommitmentAddrs: %v", err)
+			return "", fmt.Errorf("CommitmentAddrs: %v", err)
 		}
 
 		// Add addresses to the existing map
 		for k, v := range caddrs {
 			addrs[k] = v
 		}
-	}
-
-	// Verify the signatures
-	for k, v := range votes {
-		if receipts[k].ErrorCode != nil {
-			// Vote has an error. Skip it.
-			continue
-		}
-
-		// Verify vote signature
-		commitmentAddr, ok := addrs[v.Ticket]
-		if !ok {
-			t := time.Now().Unix()
-			log.Errorf("cmdCastBallot: commitment addr not found "+
-				"%v: %v", t, v.Ticket)
-			e := ticketvote.VoteErrorInternalError
-			receipts[k].Ticket = v.Ticket
-			receipts[k].ErrorCode = &e
-			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
-				ticketvote.VoteErrors[e], t)
-			continue
-		}
-		if commitmentAddr.err != nil {
-			t := time.Now().Unix()
-			log.Errorf("cmdCastBallot: commitment addr error %v: "+
-				"%v %v", t, v.Ticket, commitmentAddr.err)
-			e := ticketvote.VoteErrorInternalError
-			receipts[k].Ticket = v.Ticket
-			receipts[k].ErrorCode = &e
-			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
-				ticketvote.VoteErrors[e], t)
-			continue
-		}
-		err = castVoteVerifySignature(v, commitmentAddr.addr, p.activeNetParams)
-		if err != nil {
-			e := ticketvote.VoteErrorSignatureInvalid
-			receipts[k].Ticket = v.Ticket
-			receipts[k].ErrorCode = &e
-			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
-				ticketvote.VoteErrors[e], err)
-			continue
-		}
 
-		// Stash the commitment address. This will be added to the
-		// CastVoteDetails before the vote is written to disk.
-		br.addrSet(v.Ticket, commitmentAddr.addr)
+		// Cache the addresses. The stakes of the cast votes
+		// are used to tally the results of stake weighted
+		// votes.
+		p.activeVotes.AddCommitmentAddrs(tokenEncode(token), caddrs)
 	}
 
+	// Verify the commitment addresses and the signatures
+	p.castVotesVerify(votes, receipts, addrs, voteDetails, bestBlock, &br)
+
 	// The votes that have passed validation will be cast in batches of
-	// size batchSize. Each batch of votes is cast concurrently in order to
-	// accommodate the trillian log signer bottleneck. The log signer picks
-	// up queued leaves and appends them onto the trillian tree every xxx
-	// ms, where xxx is a configurable value on the log signer, but is
-	// typically a few hundred milliseconds. Lets use 200ms as an example.
-	// If we don't cast the votes in batches then every vote in the ballot
-	// will take 200 milliseconds since we wait for the leaf to be fully
+	// size batchSize. The cast votes of a batch are appended to the
+	// trillian tree using a single queued leaves call, followed by a
+	// second call for the vote colliders, in order to accommodate the
+	// trillian log signer bottleneck. The log signer picks up queued
+	// leaves and appends them onto the trillian tree every xxx ms, where
+	// xxx is a configurable value on the log signer, but is typically a
+	// few hundred milliseconds. Lets use 200ms as an example. If we
+	// don't cast the votes in batches then every vote in the ballot will
+	// take 200 milliseconds since we wait for the leaf to be fully
 	// appended before considering the trillian call successful. A person
 	// casting hundreds of votes in a single ballot would cause UX issues
-	// for all the voting clients since the backend locks the record during
-	// any plugin write calls. Only one ballot can be cast at a time.
+	// for all the voting clients since the backend locks the record
+	// during any plugin write calls. Only one ballot can be cast at a
+	// time.
 	//
 	// The second variable that we must watch out for is the max trillian
 	// queued leaf batch size. This is also a configurable trillian value
@@ -1617,21 +2457,19 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 	// the queue for all trees in the trillian instance. This value is
 	// typically around the order of magnitude of 1000s of queued leaves.
 	//
-	// The third variable that can cause errors is reaching the trillian
-	// datastore max connection limits. Each vote being cast creates a
-	// trillian connection. Overloading the trillian connections can cause
-	// max connection exceeded errors. The max allowed connections is a
-	// configurable trillian value, but should also be adjusted on the
-	// key-value store database itself as well.
+	// Coalescing the writes of a batch also means that each batch only
+	// uses a single trillian connection at a time instead of one per
+	// vote, which prevents the trillian datastore max connection limits
+	// from being reached.
 	//
-	// This is why a vote batch size of 10 was chosen. It is large enough
+	// This is why a vote batch size of 50 was chosen. It is large enough
 	// to alleviate performance bottlenecks from the log signer interval,
 	// but small enough to still allow multiple records votes to be held
 	// concurrently without running into the queued leaf batch size limit.
 
 	// Prepare work
 	var (
-		batchSize = 10
+		batchSize = 50
 		batch     = make([]ticketvote.CastVote, 0, batchSize)
 		queue     = make([][]ticketvote.CastVote, 0,
 			len(votes)/batchSize)
@@ -1700,6 +2538,11 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 		receipts[k] = cvr
 	}
 
+	// Finish the vote early if the ballot decided the outcome
+	if ballotCount > 0 {
+		p.voteFinishEarly(token, *voteDetails, bestBlock)
+	}
+
 	// Prepare reply
 	cbr := ticketvote.CastBallotReply{
 		Receipts: receipts,
@@ -1726,10 +2569,24 @@ func (p *ticketVotePlugin) cmdDetails(token []byte) (string, error) {
 		return "", fmt.Errorf("voteDetails: %v", err)
 	}
 
+	// Get vote extensions
+	exts, err := p.voteExtensions(token)
+	if err != nil {
+		return "", fmt.Errorf("voteExtensions: %v", err)
+	}
+
+	// Get vote cancellations
+	cancels, err := p.voteCancellations(token)
+	if err != nil {
+		return "", fmt.Errorf("voteCancellations: %v", err)
+	}
+
 	// Prepare rely
 	dr := ticketvote.DetailsReply{
-		Auths: auths,
-		Vote:  vd,
+		Auths:         auths,
+		Vote:          vd,
+		Extensions:    exts,
+		Cancellations: cancels,
 	}
 	reply, err := json.Marshal(dr)
 	if err != nil {
@@ -1781,13 +2638,269 @@ func (p *ticketVotePlugin) cmdResults(token []byte) (string, error) {
 	return string(reply), nil
 }
 
+// cmdResultsExport exports a page of the cast votes of a record vote in the
+// requested format.
+func (p *ticketVotePlugin) cmdResultsExport(token []byte, payload string) (string, error) {
+	// Decode payload
+	var re ticketvote.ResultsExport
+	err := json.Unmarshal([]byte(payload), &re)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify format
+	switch re.Format {
+	case ticketvote.ExportFormatCSV, ticketvote.ExportFormatNDJSON:
+		// These are allowed
+	default:
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeExportFormatInvalid),
+			ErrorContext: fmt.Sprintf("invalid format '%v'", re.Format),
+		}
+	}
+	if re.Page == 0 {
+		re.Page = 1
+	}
+
+	// Get vote results. The results are sorted by ticket hash so the
+	// pages are consistent across requests.
+	votes, err := p.voteResults(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the requested page
+	var (
+		pageSize = p.resultsExportPageSize
+		startAt  = uint64(re.Page-1) * uint64(pageSize)
+		page     = []ticketvote.CastVoteDetails{}
+	)
+	if startAt < uint64(len(votes)) {
+		endAt := startAt + uint64(pageSize)
+		if endAt > uint64(len(votes)) {
+			endAt = uint64(len(votes))
+		}
+		page = votes[startAt:endAt]
+	}
+
+	// Encode the page
+	data, err := resultsExportEncode(re.Format, page)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	rer := ticketvote.ResultsExportReply{
+		Format:     re.Format,
+		Page:       re.Page,
+		PageSize:   pageSize,
+		TotalVotes: uint32(len(votes)),
+		Data:       data,
+	}
+	reply, err := json.Marshal(rer)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// resultsExportEncode encodes the provided cast votes using the provided
+// export format.
+func resultsExportEncode(format ticketvote.ExportFormatT, votes []ticketvote.CastVoteDetails) (string, error) {
+	var b bytes.Buffer
+	switch format {
+	case ticketvote.ExportFormatCSV:
+		w := csv.NewWriter(&b)
+		err := w.Write([]string{"ticket", "votebit", "timestamp", "address"})
+		if err != nil {
+			return "", err
+		}
+		for _, v := range votes {
+			err := w.Write([]string{
+				v.Ticket,
+				v.VoteBit,
+				strconv.FormatInt(v.Timestamp, 10),
+				v.Address,
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+		w.Flush()
+		err = w.Error()
+		if err != nil {
+			return "", err
+		}
+
+	case ticketvote.ExportFormatNDJSON:
+		e := json.NewEncoder(&b)
+		for _, v := range votes {
+			err := e.Encode(ticketvote.ResultsExportVote{
+				Ticket:    v.Ticket,
+				VoteBit:   v.VoteBit,
+				Timestamp: v.Timestamp,
+				Address:   v.Address,
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+
+	default:
+		return "", fmt.Errorf("invalid export format '%v'", format)
+	}
+
+	return b.String(), nil
+}
+
+// cmdReceipts returns the cast vote receipts of the provided tickets. A
+// receipt contains the cast vote and the timestamp of the cast vote.
+func (p *ticketVotePlugin) cmdReceipts(token []byte, payload string) (string, error) {
+	// Decode payload
+	var r ticketvote.Receipts
+	err := json.Unmarshal([]byte(payload), &r)
+	if err != nil {
+		return "", err
+	}
+	tickets := make(map[string]struct{}, len(r.Tickets))
+	for _, v := range r.Tickets {
+		tickets[v] = struct{}{}
+	}
+
+	// Get vote results
+	votes, err := p.voteResults(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the timestamp of each of the requested cast votes. The
+	// timestamp is looked up using the digest of the cast vote blob.
+	receipts := make([]ticketvote.VoteReceipt, 0, len(r.Tickets))
+	for _, v := range votes {
+		if _, ok := tickets[v.Ticket]; !ok {
+			continue
+		}
+		be, err := convertBlobEntryFromCastVoteDetails(v)
+		if err != nil {
+			return "", err
+		}
+		digest, err := hex.DecodeString(be.Digest)
+		if err != nil {
+			return "", err
+		}
+		ts, err := p.timestamp(token, digest)
+		if err != nil {
+			return "", err
+		}
+		receipts = append(receipts, ticketvote.VoteReceipt{
+			Vote:      v,
+			Timestamp: *ts,
+		})
+	}
+
+	// Prepare reply
+	rr := ticketvote.ReceiptsReply{
+		Receipts: receipts,
+	}
+	reply, err := json.Marshal(rr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdVote returns the cast vote of a single ticket. The active votes cache
+// is checked first so that the cast votes of an active vote do not need to
+// be retrieved from the backend when the ticket has not voted.
+func (p *ticketVotePlugin) cmdVote(token []byte, payload string) (string, error) {
+	// Decode payload
+	var v ticketvote.Vote
+	err := json.Unmarshal([]byte(payload), &v)
+	if err != nil {
+		return "", err
+	}
+
+	errNotFound := backend.PluginError{
+		PluginID:     ticketvote.PluginID,
+		ErrorCode:    uint32(ticketvote.ErrorCodeVoteNotFound),
+		ErrorContext: v.Ticket,
+	}
+
+	// Check the active votes cache
+	isActive, isDup := p.activeVotes.VoteIsDuplicate(tokenEncode(token),
+		v.Ticket)
+	if isActive && !isDup {
+		return "", errNotFound
+	}
+
+	// Get vote results
+	votes, err := p.voteResults(token)
+	if err != nil {
+		return "", err
+	}
+	var (
+		cvd   ticketvote.CastVoteDetails
+		found bool
+	)
+	for _, vote := range votes {
+		if vote.Ticket == v.Ticket {
+			cvd = vote
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", errNotFound
+	}
+
+	// Prepare reply
+	vr := ticketvote.VoteReply{
+		Vote: cvd,
+	}
+	reply, err := json.Marshal(vr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdActiveVotesRebuild rebuilds the active votes cache.
+func (p *ticketVotePlugin) cmdActiveVotesRebuild() (string, error) {
+	// Wait for any vote starts that are in progress to finish
+	// before rebuilding the cache.
+	p.activeVotesMtx.Lock()
+	defer p.activeVotesMtx.Unlock()
+
+	log.Infof("Rebuilding active votes cache")
+
+	avr, err := p.activeVotesRebuild()
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("Active votes cache rebuilt: %v active, %v added, %v removed",
+		len(avr.Tokens), len(avr.Added), len(avr.Removed))
+
+	// Prepare reply
+	reply, err := json.Marshal(avr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
 // cmdSummary requests the vote summary for a record.
 func (p *ticketVotePlugin) cmdSummary(token []byte) (string, error) {
 	// Get best block. This cmd does not write any data so we do not
 	// have to use the safe best block.
-	bb, err := p.bestBlockUnsafe()
+	bb, err := p.chain.BestBlockUnsafe()
 	if err != nil {
-		return "", fmt.Errorf("bestBlockUnsafe: %v", err)
+		return "", fmt.Errorf("BestBlockUnsafe: %v", err)
 	}
 
 	// Get summary
@@ -1816,41 +2929,52 @@ func (p *ticketVotePlugin) cmdInventory(payload string) (string, error) {
 
 	// Get the best block. This command does not write
 	// any data so we can use the unsafe best block.
-	bestBlock, err := p.bestBlockUnsafe()
+	bestBlock, err := p.chain.BestBlockUnsafe()
 	if err != nil {
 		return "", err
 	}
 
 	// Get the inventory
-	tokens := make(map[string][]string, 256)
+	if i.Page == 0 {
+		i.Page = 1
+	}
+	var (
+		tokens     = make(map[string][]string, 256)
+		timestamps = make(map[string]int64, 256)
+	)
 	switch i.Status {
 	case ticketvote.VoteStatusInvalid:
 		// No vote status was provided. Return a
 		// page of results for all vote statuses.
-		inv, err := p.inv.GetPage(bestBlock)
+		inv, err := p.inv.GetPage(bestBlock, i.Page, i.PageSize,
+			i.StartedAfter)
 		if err != nil {
 			return "", err
 		}
 		for status, entries := range inv.Entries {
 			statusStr := ticketvote.VoteStatuses[status]
 			tokens[statusStr] = entryTokens(entries)
+			entryStatusTimestamps(entries, timestamps)
 		}
 
 	default:
 		// A vote status was provided. Return a page of results for the
 		// provided status.
-		entries, err := p.inv.GetPageForStatus(bestBlock, i.Status, i.Page)
+		entries, err := p.inv.GetPageForStatus(bestBlock, i.Status, i.Page,
+			i.PageSize, i.StartedAfter)
 		if err != nil {
 			return "", err
 		}
 		statusStr := ticketvote.VoteStatuses[i.Status]
 		tokens[statusStr] = entryTokens(entries)
+		entryStatusTimestamps(entries, timestamps)
 	}
 
 	// Prepare the reply
 	ir := ticketvote.InventoryReply{
-		Tokens:    tokens,
-		BestBlock: bestBlock,
+		Tokens:     tokens,
+		Timestamps: timestamps,
+		BestBlock:  bestBlock,
 	}
 	reply, err := json.Marshal(ir)
 	if err != nil {
@@ -1873,17 +2997,33 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 		auths   = make([]ticketvote.Timestamp, 0, 32)
 		details *ticketvote.Timestamp
 
+		// The page size is capped at the timestamps page size
+		// plugin setting.
 		pageSize = p.timestampsPageSize
 		votes    = make([]ticketvote.Timestamp, 0, pageSize)
+
+		votesCount uint32
 	)
+	if t.VotesPageSize > 0 && t.VotesPageSize < pageSize {
+		pageSize = t.VotesPageSize
+	}
 	switch {
 	case t.VotesPage > 0:
 		// Return a page of vote timestamps
 
-		// Look for final vote timestamps in the key-value cache
-		cachedVotes, err := p.cachedVoteTimestamps(token, t.VotesPage, pageSize)
-		if err != nil {
-			return "", err
+		// Look for final vote timestamps in the key-value cache. The
+		// cache is keyed by the page number of the default page size,
+		// so it's only used when the default page size is requested.
+		var (
+			cachedVotes []ticketvote.Timestamp
+			useCache    = pageSize == p.timestampsPageSize
+		)
+		if useCache {
+			cachedVotes, err = p.cachedVoteTimestamps(token, t.VotesPage,
+				pageSize)
+			if err != nil {
+				return "", err
+			}
 		}
 
 		// Get all cast vote digests from tstore
@@ -1893,12 +3033,17 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 			return "", fmt.Errorf("digestsByKeyPrefix %x %v: %v",
 				token, dataDescriptorVoteDetails, err)
 		}
+		votesCount = uint32(len(digests))
 
 		startAt := (t.VotesPage - 1) * pageSize
 		for i, v := range digests {
 			if i < int(startAt) {
 				continue
 			}
+			if len(votes) == int(pageSize) {
+				// We have a full page. We're done.
+				break
+			}
 
 			// Check if current digest timestamp already exists in cache
 			var foundInCache bool
@@ -1922,17 +3067,14 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 					token, v, err)
 			}
 			votes = append(votes, *ts)
-
-			if len(votes) == int(pageSize) {
-				// We have a full page. We're done.
-				break
-			}
 		}
 
 		// Cache final vote timestamps
-		err = p.cacheFinalVoteTimestamps(token, votes, t.VotesPage)
-		if err != nil {
-			return "", err
+		if useCache {
+			err = p.cacheFinalVoteTimestamps(token, votes, t.VotesPage)
+			if err != nil {
+				return "", err
+			}
 		}
 
 	default:
@@ -2001,10 +3143,12 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 			return "", fmt.Errorf("DigestsByDataDesc %x %v: %v",
 				token, dataDescriptorVoteDetails, err)
 		}
-		// There should never be more than a one vote details
+		// There should never be more than one vote details, unless
+		// a runoff vote start was rolled back and the runoff vote was
+		// started again. The rolled back vote details are deleted, so
+		// only the most recent vote details is used.
 		if len(digests) > 1 {
-			return "", fmt.Errorf("invalid vote details count: "+
-				"got %v, want 1", len(digests))
+			digests = digests[len(digests)-1:]
 		}
 		for _, v := range digests {
 			// Check if vote details digest timestamp already exists in cache
@@ -2035,11 +3179,25 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 		}
 	}
 
+	// Remove the data payloads if only the digests were requested.
+	// This is done after the timestamps have been cached so that
+	// the cache always contains the full timestamps.
+	if t.DigestsOnly {
+		timestampsDataRemove(auths)
+		timestampsDataRemove(votes)
+		if details != nil {
+			d := *details
+			d.Data = ""
+			details = &d
+		}
+	}
+
 	// Prepare reply
 	tr := ticketvote.TimestampsReply{
-		Auths:   auths,
-		Details: details,
-		Votes:   votes,
+		Auths:      auths,
+		Details:    details,
+		Votes:      votes,
+		VotesCount: votesCount,
 	}
 	reply, err := json.Marshal(tr)
 	if err != nil {
@@ -2116,9 +3274,25 @@ func (p *ticketVotePlugin) auths(token []byte) ([]ticketvote.AuthDetails, error)
 	return auths, nil
 }
 
-// voteDetailsSave saves a VoteDetails to the backend.
+// voteDetailsSave saves a VoteDetails to the backend. The eligible tickets
+// are saved as a separate snapshot blob and are not included in the saved
+// vote details blob. The vote details blob includes the digest of the
+// snapshot instead so that the vote details timestamp also proves the
+// eligible tickets.
 func (p *ticketVotePlugin) voteDetailsSave(token []byte, vd ticketvote.VoteDetails) error {
+	// Save the eligible ticket snapshot
+	s, err := newTicketSnapshot(vd.EligibleTickets)
+	if err != nil {
+		return err
+	}
+	err = p.snapshotSave(token, s)
+	if err != nil {
+		return err
+	}
+
 	// Prepare blob
+	vd.EligibleTickets = nil
+	vd.EligibleTicketsDigest = s.digest()
 	be, err := convertBlobEntryFromVoteDetails(vd)
 	if err != nil {
 		return err
@@ -2128,12 +3302,78 @@ func (p *ticketVotePlugin) voteDetailsSave(token []byte, vd ticketvote.VoteDetai
 	return p.tstore.BlobSave(token, *be)
 }
 
-// voteDetails returns the VoteDetails for a record. Nil is returned if a vote
+// voteDetails returns the VoteDetails for a record. The eligible tickets are
+// populated using the eligible ticket snapshot, which means that they are
+// returned sorted in ascending order and without duplicates, and the end block
+// height is updated to include any vote extensions. Nil is returned if a vote
 // details is not found.
 func (p *ticketVotePlugin) voteDetails(token []byte) (*ticketvote.VoteDetails, error) {
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return nil, err
+	}
+	if vd == nil {
+		// A vote details does not exist
+		return nil, nil
+	}
+
+	// Apply any vote extensions
+	exts, err := p.voteExtensions(token)
+	if err != nil {
+		return nil, err
+	}
+	vd.EndBlockHeight = voteEndBlockHeight(*vd, exts)
+
+	if len(vd.EligibleTickets) > 0 {
+		// The vote details was saved prior to snapshots being
+		// introduced and already contains the eligible tickets.
+		s, err := newTicketSnapshot(vd.EligibleTickets)
+		if err != nil {
+			return nil, err
+		}
+		vd.EligibleTickets = s.tickets()
+		return vd, nil
+	}
+
+	s, err := p.snapshot(token)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("eligible ticket snapshot not found for %x",
+			token)
+	}
+	if d := s.digest(); d != vd.EligibleTicketsDigest {
+		return nil, fmt.Errorf("eligible ticket snapshot digest mismatch "+
+			"for %x: got %v, want %v", token, d, vd.EligibleTicketsDigest)
+	}
+	vd.EligibleTickets = s.tickets()
+
+	return vd, nil
+}
+
+// voteStartBlockHeight returns the start block height of a record's vote. An
+// error is returned if a vote details is not found.
+func (p *ticketVotePlugin) voteStartBlockHeight(token string) (uint32, error) {
+	t, err := tokenDecode(token)
+	if err != nil {
+		return 0, err
+	}
+	vd, err := p.voteDetailsBlob(t)
+	if err != nil {
+		return 0, err
+	}
+	if vd == nil {
+		return 0, errors.Errorf("vote details not found")
+	}
+	return vd.StartBlockHeight, nil
+}
+
+// voteDetailsBlob returns the VoteDetails blob for a record as it was saved
+// to the backend. Nil is returned if a vote details is not found.
+func (p *ticketVotePlugin) voteDetailsBlob(token []byte) (*ticketvote.VoteDetails, error) {
 	// Retrieve blobs
-	blobs, err := p.tstore.BlobsByDataDesc(token,
-		[]string{dataDescriptorVoteDetails})
+	blobs, err := p.blobsByDataDesc(token, dataDescriptorVoteDetails)
 	if err != nil {
 		return nil, err
 	}
@@ -2159,6 +3399,125 @@ func (p *ticketVotePlugin) voteDetails(token []byte) (*ticketvote.VoteDetails, e
 	return vd, nil
 }
 
+// blobsByDataDesc returns the blobs that match the provided data descriptor,
+// ordered from oldest to newest. Blobs that have been deleted are skipped.
+// The vote details and start runoff blobs are deleted when a runoff vote
+// start is rolled back. The vote details and vote extension blobs are deleted
+// when a vote is cancelled.
+func (p *ticketVotePlugin) blobsByDataDesc(token []byte, dataDesc string) ([]store.BlobEntry, error) {
+	digests, err := p.tstore.DigestsByDataDesc(token, []string{dataDesc})
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := p.tstore.Blobs(token, digests)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]store.BlobEntry, 0, len(blobs))
+	for _, v := range digests {
+		be, ok := blobs[hex.EncodeToString(v)]
+		if !ok {
+			// Blob has been deleted
+			continue
+		}
+		entries = append(entries, be)
+	}
+	return entries, nil
+}
+
+// extendSave saves a ExtendDetails to the backend.
+func (p *ticketVotePlugin) extendSave(token []byte, ed ticketvote.ExtendDetails) error {
+	// Prepare blob
+	be, err := convertBlobEntryFromExtendDetails(ed)
+	if err != nil {
+		return err
+	}
+
+	// Save blob
+	return p.tstore.BlobSave(token, *be)
+}
+
+// voteExtensions returns all ExtendDetails for a record sorted from oldest to
+// newest. The vote extensions are deleted when a vote is cancelled.
+func (p *ticketVotePlugin) voteExtensions(token []byte) ([]ticketvote.ExtendDetails, error) {
+	// Retrieve blobs
+	blobs, err := p.blobsByDataDesc(token, dataDescriptorExtendDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode blobs
+	exts := make([]ticketvote.ExtendDetails, 0, len(blobs))
+	for _, v := range blobs {
+		e, err := convertExtendDetailsFromBlobEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, *e)
+	}
+
+	// Sanity check. They should already be sorted from oldest to
+	// newest.
+	sort.SliceStable(exts, func(i, j int) bool {
+		return exts[i].Timestamp < exts[j].Timestamp
+	})
+
+	return exts, nil
+}
+
+// cancelSave saves a CancelDetails to the backend.
+func (p *ticketVotePlugin) cancelSave(token []byte, cd ticketvote.CancelDetails) error {
+	// Prepare blob
+	be, err := convertBlobEntryFromCancelDetails(cd)
+	if err != nil {
+		return err
+	}
+
+	// Save blob
+	return p.tstore.BlobSave(token, *be)
+}
+
+// voteCancellations returns all CancelDetails for a record sorted from oldest
+// to newest.
+func (p *ticketVotePlugin) voteCancellations(token []byte) ([]ticketvote.CancelDetails, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.BlobsByDataDesc(token,
+		[]string{dataDescriptorCancelDetails})
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode blobs
+	cancels := make([]ticketvote.CancelDetails, 0, len(blobs))
+	for _, v := range blobs {
+		c, err := convertCancelDetailsFromBlobEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		cancels = append(cancels, *c)
+	}
+
+	// Sanity check. They should already be sorted from oldest to
+	// newest.
+	sort.SliceStable(cancels, func(i, j int) bool {
+		return cancels[i].Timestamp < cancels[j].Timestamp
+	})
+
+	return cancels, nil
+}
+
+// voteEndBlockHeight returns the end block height of a vote once the provided
+// vote extensions have been applied.
+func voteEndBlockHeight(vd ticketvote.VoteDetails, exts []ticketvote.ExtendDetails) uint32 {
+	endHeight := vd.EndBlockHeight
+	for _, v := range exts {
+		if v.EndBlockHeight > endHeight {
+			endHeight = v.EndBlockHeight
+		}
+	}
+	return endHeight
+}
+
 // voteDetailsByToken returns the VoteDetails for a record. Nil is returned
 // if the vote details are not found.
 func (p *ticketVotePlugin) voteDetailsByToken(token []byte) (*ticketvote.VoteDetails, error) {
@@ -2318,17 +3677,26 @@ func (p *ticketVotePlugin) voteResults(token []byte) ([]ticketvote.CastVoteDetai
 }
 
 // voteOptionResults tallies the results of a ticket vote and returns a
-// VoteOptionResult for each vote option in the ticket vote.
-func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.VoteOption) ([]ticketvote.VoteOptionResult, error) {
+// VoteOptionResult for each vote option in the ticket vote. The stake of the
+// cast votes is also tallied for votes that use VoteWeightStake.
+func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.VoteOption, weight ticketvote.VoteWeightT) ([]ticketvote.VoteOptionResult, error) {
 	// Ongoing votes will have the cast votes cached. Calculate the results
 	// using the cached votes if we can since it will be much faster.
 	var (
 		tally  = make(map[string]uint32, len(options))
+		stakes = make(map[string]uint64, len(options))
 		t      = hex.EncodeToString(token)
 		ctally = p.activeVotes.Tally(t)
+
+		// cached is set to false if the stakes of the cached
+		// votes are required but have not been cached.
+		cached = len(ctally) > 0
 	)
+	if cached && weight == ticketvote.VoteWeightStake {
+		stakes, cached = p.activeVotes.StakeTally(t)
+	}
 	switch {
-	case len(ctally) > 0:
+	case cached:
 		// Votes are in the cache. Use the cached results.
 		tally = ctally
 
@@ -2346,8 +3714,10 @@ func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.
 		}
 
 		// Tally the results
+		stakes = make(map[string]uint64, len(options))
 		for _, v := range rr.Votes {
 			tally[v.VoteBit]++
+			stakes[v.VoteBit] += v.Stake
 		}
 	}
 
@@ -2355,17 +3725,64 @@ func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.
 	results := make([]ticketvote.VoteOptionResult, 0, len(options))
 	for _, v := range options {
 		bit := strconv.FormatUint(v.Bit, 16)
-		results = append(results, ticketvote.VoteOptionResult{
+		r := ticketvote.VoteOptionResult{
 			ID:          v.ID,
 			Description: v.Description,
 			VoteBit:     v.Bit,
 			Votes:       uint64(tally[bit]),
-		})
+		}
+		if weight == ticketvote.VoteWeightStake {
+			r.Stake = stakes[bit]
+		}
+		results = append(results, r)
 	}
 
 	return results, nil
 }
 
+// runoffCandidate is a runoff vote submission that met the quorum and pass
+// requirements of the vote.
+type runoffCandidate struct {
+	token      string
+	netApprove int // Approve votes minus reject votes
+}
+
+// runoffWinnersCount returns the number of winners of a runoff vote for the
+// provided vote params winners value. Zero is treated as a single winner in
+// order to remain compatible with runoff votes that were started before the
+// number of winners was configurable.
+func runoffWinnersCount(winners uint32) uint32 {
+	if winners == 0 {
+		return 1
+	}
+	return winners
+}
+
+// runoffWinners returns the tokens of the candidates that won a runoff vote.
+// The candidates with the most net approve votes win. A candidate must have
+// more approve votes than reject votes in order to win. Ties are resolved in
+// favor of the candidate that comes first in the provided list, which matches
+// the behavior of single winner runoff votes.
+func runoffWinners(candidates []runoffCandidate, winners uint32) []string {
+	c := make([]runoffCandidate, 0, len(candidates))
+	for _, v := range candidates {
+		if v.netApprove > 0 {
+			c = append(c, v)
+		}
+	}
+	sort.SliceStable(c, func(i, j int) bool {
+		return c[i].netApprove > c[j].netApprove
+	})
+	if uint64(len(c)) > uint64(winners) {
+		c = c[:winners]
+	}
+	tokens := make([]string, 0, len(c))
+	for _, v := range c {
+		tokens = append(tokens, v.token)
+	}
+	return tokens
+}
+
 // voteSummariesForRunoff calculates and returns the vote summaries of all
 // submissions in a runoff vote. This should only be called once the vote has
 // finished.
@@ -2398,11 +3815,11 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 		summaries = make(map[string]ticketvote.SummaryReply,
 			len(subs))
 
-		// Net number of approve votes of the winner
-		winnerNetApprove int
+		// Submissions that met the quorum and pass requirements
+		candidates = make([]runoffCandidate, 0, len(subs))
 
-		// Token of the winner
-		winnerToken string
+		// Number of winners of the runoff vote
+		winners uint32
 	)
 	for _, v := range subs {
 		token, err := tokenDecode(v)
@@ -2417,7 +3834,14 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 		}
 
 		// Get vote options results
-		results, err := p.voteOptionResults(token, vd.Params.Options)
+		results, err := p.voteOptionResults(token, vd.Params.Options,
+			vd.Params.Weight)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get the total weight of the eligible tickets
+		eligible, err := p.voteEligibleWeight(*vd)
 		if err != nil {
 			return nil, err
 		}
@@ -2434,21 +3858,27 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 			QuorumPercentage: vd.Params.QuorumPercentage,
 			PassPercentage:   vd.Params.PassPercentage,
 			Results:          results,
+			Winners:          runoffWinnersCount(vd.Params.Winners),
+		}
+		if vd.Params.Weight == ticketvote.VoteWeightStake {
+			s.Weight = vd.Params.Weight
+			s.EligibleStake = eligible
 		}
 		summaries[v] = s
+		winners = s.Winners
 
-		// We now check if this record has the most net yes votes.
+		// We now check if this record has enough net yes votes to
+		// be one of the winners.
 
 		// Verify the vote met quorum and pass requirements
-		approved := voteIsApproved(*vd, results)
+		approved := voteIsApproved(*vd, results, eligible)
 		if !approved {
 			// Vote did not meet quorum and pass requirements.
 			// Nothing else to do. Record vote is not approved.
 			continue
 		}
 
-		// Check if this record has more net approved votes then
-		// current highest.
+		// Tally the net approved votes of this record
 		var (
 			votesApprove uint64 // Number of approve votes
 			votesReject  uint64 // Number of reject votes
@@ -2456,36 +3886,27 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 		for _, vor := range s.Results {
 			switch vor.ID {
 			case ticketvote.VoteOptionIDApprove:
-				votesApprove = vor.Votes
+				votesApprove = voteOptionWeight(vd.Params.Weight, vor)
 			case ticketvote.VoteOptionIDReject:
-				votesReject = vor.Votes
+				votesReject = voteOptionWeight(vd.Params.Weight, vor)
 			default:
 				// Runoff vote options can only be
 				// approve/reject
 				return nil, fmt.Errorf("unknown runoff vote "+
 					"option %v", vor.ID)
 			}
-
-			netApprove := int(votesApprove) - int(votesReject)
-			if netApprove > winnerNetApprove {
-				// New winner!
-				winnerToken = v
-				winnerNetApprove = netApprove
-			}
-
-			// This function doesn't handle the unlikely case that
-			// the runoff vote results in a tie. If this happens
-			// then we need to have a debate about how this should
-			// be handled before implementing anything. The cached
-			// vote summary would need to be removed and recreated
-			// using whatever methodology is decided upon.
 		}
+		candidates = append(candidates, runoffCandidate{
+			token:      v,
+			netApprove: int(votesApprove) - int(votesReject),
+		})
 	}
-	if winnerToken != "" {
-		// A winner was found. Mark their summary as approved.
-		s := summaries[winnerToken]
+
+	// Mark the summaries of the winners as approved
+	for _, v := range runoffWinners(candidates, winners) {
+		s := summaries[v]
 		s.Status = ticketvote.VoteStatusApproved
-		summaries[winnerToken] = s
+		summaries[v] = s
 	}
 
 	return summaries, nil
@@ -2504,17 +3925,40 @@ func (p *ticketVotePlugin) summary(tokenB []byte, bestBlock uint32) (*ticketvote
 		// Update the summary's best block and return
 		// it.
 		s.BestBlock = bestBlock
+		summaryExplain(s)
 		return s, nil
 
 	case errors.Is(err, errSummaryNotFound):
 		// A cached summary was not found for the record.
-		// We must build it from scratch. Continue below.
+		// Check if a final summary has been persisted to
+		// tstore. Continue below.
 
 	case err != nil:
 		// All other errors
 		return nil, err
 	}
 
+	// Check if a final vote summary has been persisted for
+	// this record. Final summaries are saved to tstore once
+	// the voting period has ended so that the vote results
+	// do not need to be tallied again when the cache is
+	// rebuilt.
+	s, err = p.summaryFinal(tokenB)
+	if err != nil {
+		return nil, err
+	}
+	if s != nil {
+		// A final summary was found. Repopulate the cache
+		// with it and return it.
+		err = p.summaries.Save(token, *s)
+		if err != nil {
+			return nil, err
+		}
+		s.BestBlock = bestBlock
+		summaryExplain(s)
+		return s, nil
+	}
+
 	// Build the vote summary from scratch. We will need
 	// to pull various pieces of record data to do this,
 	// starting with the abridged record.
@@ -2599,7 +4043,8 @@ func (p *ticketVotePlugin) summary(tokenB []byte, bestBlock uint32) (*ticketvote
 	status = ticketvote.VoteStatusStarted
 
 	// Tally the vote results
-	results, err := p.voteOptionResults(tokenB, vd.Params.Options)
+	results, err := p.voteOptionResults(tokenB, vd.Params.Options,
+		vd.Params.Weight)
 	if err != nil {
 		return nil, err
 	}
@@ -2616,27 +4061,68 @@ func (p *ticketVotePlugin) summary(tokenB []byte, bestBlock uint32) (*ticketvote
 		QuorumPercentage: vd.Params.QuorumPercentage,
 		PassPercentage:   vd.Params.PassPercentage,
 		Results:          results,
+		WinCondition:     vd.Params.WinCondition,
 		BestBlock:        bestBlock,
 	}
+	if vd.Params.Type == ticketvote.VoteTypeRunoff {
+		summary.Winners = runoffWinnersCount(vd.Params.Winners)
+	}
+	if vd.Params.Weight == ticketvote.VoteWeightStake {
+		// The eligible stake of an ongoing vote is only included
+		// once the stakes of all eligible tickets have been
+		// cached.
+		summary.Weight = vd.Params.Weight
+		summary.EligibleStake, _ = p.activeVotes.EligibleStake(vd.Params.Token)
+	}
 
 	// If the vote has not finished yet then we are done for now.
+	// A vote that has been decided early is finished regardless
+	// of the end block height.
 	if !voteHasEnded(bestBlock, vd.EndBlockHeight) {
-		return &summary, nil
+		if !p.voteIsDecided(*vd, results) {
+			summaryExplain(&summary)
+			return &summary, nil
+		}
+		summary.FinishedEarly = true
+	}
+
+	// Get the total weight of the eligible tickets
+	eligible, err := p.voteEligibleWeight(*vd)
+	if err != nil {
+		return nil, err
+	}
+	if vd.Params.Weight == ticketvote.VoteWeightStake {
+		summary.EligibleStake = eligible
 	}
 
 	// The vote has finished. Determine the vote result and
-	// save the vote summary to the cache.
+	// persist the final vote summary.
 	switch vd.Params.Type {
 	case ticketvote.VoteTypeStandard:
 		// Standard votes use a simple approve/reject result
-		if voteIsApproved(*vd, results) {
+		if voteIsApproved(*vd, results, eligible) {
 			summary.Status = ticketvote.VoteStatusApproved
 		} else {
 			summary.Status = ticketvote.VoteStatusRejected
 		}
 
-		// Save the summary to the cache
-		err = p.summaries.Save(token, summary)
+		// Persist the final summary
+		err = p.summaryFinalSave(tokenB, summary)
+		if err != nil {
+			return nil, err
+		}
+
+		// Remove the record from the active votes cache
+		p.activeVotes.Del(vd.Params.Token)
+
+	case ticketvote.VoteTypeMultiOption:
+		// Multi-option votes do not have an approved or rejected
+		// outcome. The result is the winning vote option.
+		summary.Status = ticketvote.VoteStatusFinished
+		summary.WinningOption = voteWinningOption(*vd, results, eligible)
+
+		// Persist the final summary
+		err = p.summaryFinalSave(tokenB, summary)
 		if err != nil {
 			return nil, err
 		}
@@ -2644,31 +4130,176 @@ func (p *ticketVotePlugin) summary(tokenB []byte, bestBlock uint32) (*ticketvote
 		// Remove the record from the active votes cache
 		p.activeVotes.Del(vd.Params.Token)
 
-	case ticketvote.VoteTypeRunoff:
-		// A runoff vote requires that we pull all other runoff
-		// vote submissions to determine if the vote passed.
-		summaries, err := p.summariesForRunoff(vd.Params.Parent)
-		if err != nil {
-			return nil, err
-		}
-		for k, v := range summaries {
-			// Save the summary to the cache
-			err = p.summaries.Save(k, v)
-			if err != nil {
-				return nil, err
-			}
+	case ticketvote.VoteTypeRunoff:
+		// A runoff vote requires that we pull all other runoff
+		// vote submissions to determine if the vote passed.
+		summaries, err := p.summariesForRunoff(vd.Params.Parent)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range summaries {
+			// Persist the final summary
+			t, err := tokenDecode(k)
+			if err != nil {
+				return nil, err
+			}
+			err = p.summaryFinalSave(t, v)
+			if err != nil {
+				return nil, err
+			}
+
+			// Remove the record from the active votes cache
+			p.activeVotes.Del(k)
+		}
+
+		summary = summaries[vd.Params.Token]
+
+	default:
+		return nil, errors.Errorf("unknown vote type")
+	}
+
+	summaryExplain(&summary)
+
+	return &summary, nil
+}
+
+// summaryFinalSave saves the final vote summary of a record whose voting
+// period has ended to the vote summaries cache and persists it to tstore.
+//
+// The summary is persisted using the summary save internal plugin command.
+// Plugin write commands are executed by the backend while holding the record
+// lock, which prevents the summary blob from being appended while the record
+// is being frozen, e.g. when the record is archived or censored. The command
+// is executed in a separate goroutine since vote summaries are also requested
+// by plugin commands and hooks that already hold the record lock.
+func (p *ticketVotePlugin) summaryFinalSave(token []byte, s ticketvote.SummaryReply) error {
+	// The best block is not part of the final vote results and
+	// is set on retrieval. Zero it out so that the blob is the
+	// same regardless of when it was saved.
+	s.BestBlock = 0
+
+	// Save the summary to the cache
+	err := p.summaries.Save(tokenEncode(token), s)
+	if err != nil {
+		return err
+	}
+
+	// Persist the summary
+	b, err := json.Marshal(summarySave{
+		Summary: s,
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		_, err := p.backend.PluginWrite(token, ticketvote.PluginID,
+			cmdSummarySave, string(b))
+		if err != nil {
+			log.Errorf("PluginWrite %x %v %v: %v", token,
+				ticketvote.PluginID, cmdSummarySave, err)
+		}
+	}()
+
+	return nil
+}
+
+// cmdSummarySave is an internal plugin command that persists the final vote
+// summary of a record to tstore and notifies the other plugins that the vote
+// has ended.
+func (p *ticketVotePlugin) cmdSummarySave(token []byte, payload string) (string, error) {
+	var ss summarySave
+	err := json.Unmarshal([]byte(payload), &ss)
+	if err != nil {
+		return "", err
+	}
+
+	// Save the blob. A duplicate payload error means that the
+	// final summary has already been saved by a previous call,
+	// which has also emitted the vote ended event. A locked
+	// record error means that the record has been frozen and can
+	// no longer be updated. The summary remains in the cache and
+	// the vote ended event is still emitted.
+	be, err := convertBlobEntryFromSummary(ss.Summary)
+	if err != nil {
+		return "", err
+	}
+	err = p.tstore.BlobSave(token, *be)
+	switch {
+	case err == nil:
+		log.Debugf("Final vote summary saved for %x", token)
+	case errors.Is(err, backend.ErrDuplicatePayload):
+		return "", nil
+	case errors.Is(err, backend.ErrRecordLocked):
+		log.Debugf("Final vote summary not saved for %x: record is "+
+			"locked", token)
+	default:
+		return "", err
+	}
+
+	// Notify the other plugins that the vote has ended. The
+	// summary has been cached at this point, so the plugins that
+	// handle the event are able to retrieve the final summary
+	// without having to tally the votes again.
+	p.voteEndedEmit(token, ss.Summary)
+
+	// Add the vote to the governance export. A failure is not
+	// fatal since missing votes are added to the export on
+	// startup.
+	err = p.govExportAdd(map[string]ticketvote.SummaryReply{
+		tokenEncode(token): ss.Summary,
+	})
+	if err != nil {
+		log.Errorf("govExportAdd %x: %v", token, err)
+	}
+
+	return "", nil
+}
+
+// voteEndedEmit emits a ticketvote plugin event that notifies the other
+// plugins that a vote has ended and provides them with the final vote
+// summary.
+func (p *ticketVotePlugin) voteEndedEmit(token []byte, s ticketvote.SummaryReply) {
+	b, err := json.Marshal(ticketvote.VoteEnded{
+		Token:   tokenEncode(token),
+		Summary: s,
+	})
+	if err != nil {
+		log.Errorf("voteEndedEmit %x: %v", token, err)
+		return
+	}
 
-			// Remove the record from the active votes cache
-			p.activeVotes.Del(k)
-		}
+	log.Debugf("Vote ended %x: %v", token,
+		ticketvote.VoteStatuses[s.Status])
 
-		summary = summaries[vd.Params.Token]
+	p.tstore.PluginEvent(token, ticketvote.EventVoteEnded, string(b))
+}
 
+// summaryFinal returns the final vote summary that was persisted for a record
+// once its voting period ended. Nil is returned if a final summary has not
+// been persisted.
+func (p *ticketVotePlugin) summaryFinal(token []byte) (*ticketvote.SummaryReply, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.BlobsByDataDesc(token,
+		[]string{dataDescriptorSummary})
+	if err != nil {
+		return nil, err
+	}
+	switch len(blobs) {
+	case 0:
+		// A final summary has not been saved
+		return nil, nil
+	case 1:
+		// A final summary has been saved; continue
 	default:
-		return nil, errors.Errorf("unknown vote type")
+		// This should not happen. Concurrent saves of the same
+		// summary produce identical blobs that are rejected as
+		// duplicates.
+		return nil, fmt.Errorf("multiple final summaries found: %v",
+			len(blobs))
 	}
 
-	return &summary, nil
+	// Decode blob
+	return convertSummaryFromBlobEntry(blobs[0])
 }
 
 // summaryByToken returns the vote summary for a record.
@@ -2737,68 +4368,119 @@ func (p *ticketVotePlugin) recordAbridged(token []byte) (*backend.Record, error)
 	return &r, nil
 }
 
-// bestBlock fetches the best block from the dcrdata plugin and returns it. If
-// the dcrdata connection is not active, an error will be returned.
-func (p *ticketVotePlugin) bestBlock() (uint32, error) {
-	// Get best block
-	payload, err := json.Marshal(dcrdata.BestBlock{})
-	if err != nil {
-		return 0, err
+// voteDiagnostics returns the diagnostics for a cast vote that failed
+// validation. The vote details will be nil if the vote is not active.
+func voteDiagnostics(reason string, vd *ticketvote.VoteDetails, bestBlock uint32) *ticketvote.VoteDiagnostics {
+	d := ticketvote.VoteDiagnostics{
+		Reason:    reason,
+		BestBlock: bestBlock,
 	}
-	reply, err := p.backend.PluginRead(nil, dcrdata.PluginID,
-		dcrdata.CmdBestBlock, string(payload))
-	if err != nil {
-		return 0, fmt.Errorf("PluginRead %v %v: %v",
-			dcrdata.PluginID, dcrdata.CmdBestBlock, err)
+	if vd != nil {
+		d.SnapshotHeight = vd.StartBlockHeight
+		d.EndBlockHeight = vd.EndBlockHeight
 	}
+	return &d
+}
 
-	// Handle response
-	var bbr dcrdata.BestBlockReply
-	err = json.Unmarshal([]byte(reply), &bbr)
-	if err != nil {
-		return 0, err
+// ineligibleReason returns the reason that a ticket is not found in the
+// eligible ticket snapshot of a vote.
+func ineligibleReason(ticket string, snapshotHeight uint32) string {
+	b, err := hex.DecodeString(ticket)
+	if err != nil || len(b) != 32 {
+		return "ticket is not a valid ticket hash"
+	}
+	return fmt.Sprintf("ticket was not live at the snapshot block "+
+		"height %v", snapshotHeight)
+}
+
+// voteEligibleWeight returns the total weight of the eligible tickets of a
+// vote. This is the number of eligible tickets for votes that use
+// VoteWeightTicket and the total stake of the eligible tickets for votes that
+// use VoteWeightStake.
+func (p *ticketVotePlugin) voteEligibleWeight(vd ticketvote.VoteDetails) (uint64, error) {
+	if vd.Params.Weight != ticketvote.VoteWeightStake {
+		return uint64(len(vd.EligibleTickets)), nil
 	}
-	if bbr.Status != dcrdata.StatusConnected {
-		// The dcrdata connection is down. The best block cannot be
-		// trusted as being accurate.
-		return 0, fmt.Errorf("dcrdata connection is down")
+
+	// Use the cached stakes if the active votes cache has been
+	// fully populated.
+	stake, ok := p.activeVotes.EligibleStake(vd.Params.Token)
+	if ok {
+		return stake, nil
 	}
-	if bbr.Height == 0 {
-		return 0, fmt.Errorf("invalid best block height 0")
+
+	// Fetch the stakes of the eligible tickets in batches
+	var (
+		pageSize = 500
+		total    uint64
+	)
+	for startIdx := 0; startIdx < len(vd.EligibleTickets); startIdx += pageSize {
+		endIdx := startIdx + pageSize
+		if endIdx > len(vd.EligibleTickets) {
+			endIdx = len(vd.EligibleTickets)
+		}
+		tickets := vd.EligibleTickets[startIdx:endIdx]
+		addrs, err := p.commitmentAddrs(tickets, vd.StartBlockHeight)
+		if err != nil {
+			return 0, err
+		}
+		for _, v := range tickets {
+			ca, ok := addrs[v]
+			switch {
+			case !ok:
+				return 0, errors.Errorf("stake not found %v", v)
+			case ca.err != nil:
+				return 0, errors.Errorf("stake %v: %v", v, ca.err)
+			}
+			total += ca.stake
+		}
 	}
 
-	return bbr.Height, nil
+	return total, nil
 }
 
-// bestBlockUnsafe fetches the best block from the dcrdata plugin and returns
-// it. If the dcrdata connection is not active, an error WILL NOT be returned.
-// The dcrdata cached best block height will be returned even though it may be
-// stale. Use bestBlock() if the caller requires a guarantee that the best
-// block is not stale.
-func (p *ticketVotePlugin) bestBlockUnsafe() (uint32, error) {
-	// Get best block
-	payload, err := json.Marshal(dcrdata.BestBlock{})
-	if err != nil {
-		return 0, err
-	}
-	reply, err := p.backend.PluginRead(nil, dcrdata.PluginID,
-		dcrdata.CmdBestBlock, string(payload))
-	if err != nil {
-		return 0, fmt.Errorf("PluginRead %v %v: %v",
-			dcrdata.PluginID, dcrdata.CmdBestBlock, err)
+// voteIsDecided returns whether the outcome of a vote that has not reached
+// its end block height can no longer change. This is the case once all
+// eligible tickets have voted. Votes are only decided early when the early
+// finish plugin setting is enabled. Runoff votes are never decided early since
+// the outcome depends on the results of all runoff submissions.
+func (p *ticketVotePlugin) voteIsDecided(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult) bool {
+	if !p.earlyFinish || vd.Params.Type == ticketvote.VoteTypeRunoff ||
+		len(vd.EligibleTickets) == 0 {
+		return false
+	}
+	var cast uint64
+	for _, v := range results {
+		cast += v.Votes
 	}
+	return cast >= uint64(len(vd.EligibleTickets))
+}
 
-	// Handle response
-	var bbr dcrdata.BestBlockReply
-	err = json.Unmarshal([]byte(reply), &bbr)
+// voteFinishEarly finishes a vote prior to its end block height if all of
+// the eligible tickets have voted. The final vote summary is saved and the
+// record is moved to its final vote status in the inventory. Errors are
+// logged instead of returned since the ballot has already been cast. The
+// vote will be finished once the end block height is reached if this fails.
+func (p *ticketVotePlugin) voteFinishEarly(token []byte, vd ticketvote.VoteDetails, bestBlock uint32) {
+	if !p.earlyFinish || vd.Params.Type == ticketvote.VoteTypeRunoff {
+		return
+	}
+	if !p.activeVotes.AllVoted(tokenEncode(token)) {
+		return
+	}
+	s, err := p.summary(token, bestBlock)
 	if err != nil {
-		return 0, err
+		log.Errorf("voteFinishEarly %x: summary: %v", token, err)
+		return
 	}
-	if bbr.Height == 0 {
-		return 0, fmt.Errorf("invalid best block height 0")
+	if !s.FinishedEarly {
+		return
 	}
+	p.inv.UpdateEntryFinishedEarly(vd.Params.Token, s.Status,
+		s.StartBlockHeight, s.EndBlockHeight)
 
-	return bbr.Height, nil
+	log.Infof("Vote finished early %x at block %v: %v", token, bestBlock,
+		ticketvote.VoteStatuses[s.Status])
 }
 
 // voteHasEnded returns whether the vote has ended.
@@ -2806,24 +4488,119 @@ func voteHasEnded(bestBlock, endHeight uint32) bool {
 	return bestBlock >= endHeight
 }
 
+// voteOptionWeight returns the weight of the votes that were cast for a vote
+// option using the provided vote weight.
+func voteOptionWeight(weight ticketvote.VoteWeightT, r ticketvote.VoteOptionResult) uint64 {
+	if weight == ticketvote.VoteWeightStake {
+		return r.Stake
+	}
+	return r.Votes
+}
+
+// voteThresholds returns the quorum and pass thresholds of a vote. The quorum
+// is the weight of the cast votes that is required for the vote to reach a
+// quorum. The pass threshold is the weight of the cast votes that a vote
+// option requires in order to pass. The eligible weight is the total weight
+// of the eligible tickets and the total weight is the total weight of the
+// cast votes.
+func voteThresholds(quorumPerc, passPerc uint32, eligibleWeight, totalWeight uint64) (uint64, uint64) {
+	var (
+		quorum = uint64(float64(quorumPerc) / 100 * float64(eligibleWeight))
+		pass   = uint64(float64(passPerc) / 100 * float64(totalWeight))
+	)
+	return quorum, pass
+}
+
+// votePercentage returns the provided part as a percentage of the provided
+// whole, rounded to two decimal places.
+func votePercentage(part, whole uint64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
+
+// summaryExplain populates the computed fields of a vote summary that explain
+// the vote outcome: the percentage of the eligible weight that has voted, the
+// approval percentage, and the reason that a vote was rejected. The fields
+// are computed from the other summary fields using the same thresholds that
+// are used to determine the vote outcome, so they can be populated for cached
+// summaries as well.
+func summaryExplain(s *ticketvote.SummaryReply) {
+	switch s.Status {
+	case ticketvote.VoteStatusStarted, ticketvote.VoteStatusFinished,
+		ticketvote.VoteStatusApproved, ticketvote.VoteStatusRejected:
+		// The voting period has been started; continue
+	default:
+		return
+	}
+
+	// Tally the votes
+	var (
+		eligible = uint64(s.EligibleTickets)
+		total    uint64
+		approve  uint64
+
+		// approveReject is whether the vote uses the approve and
+		// reject vote options.
+		approveReject = len(s.Results) > 0
+	)
+	if s.Weight == ticketvote.VoteWeightStake {
+		eligible = s.EligibleStake
+	}
+	for _, v := range s.Results {
+		w := voteOptionWeight(s.Weight, v)
+		total += w
+		switch v.ID {
+		case ticketvote.VoteOptionIDApprove:
+			approve = w
+		case ticketvote.VoteOptionIDReject:
+			// Valid vote option
+		default:
+			approveReject = false
+		}
+	}
+
+	// Populate the percentages
+	s.QuorumPercentageAchieved = votePercentage(total, eligible)
+	if approveReject {
+		s.ApprovalPercentage = votePercentage(approve, total)
+	}
+
+	// Determine the rejection reason
+	if s.Status != ticketvote.VoteStatusRejected || !approveReject {
+		return
+	}
+	quorum, pass := voteThresholds(s.QuorumPercentage, s.PassPercentage,
+		eligible, total)
+	switch {
+	case total < quorum:
+		s.RejectionReason = ticketvote.RejectionReasonQuorumNotMet
+	case approve < pass:
+		s.RejectionReason = ticketvote.RejectionReasonApprovalNotMet
+	case s.Type == ticketvote.VoteTypeRunoff:
+		s.RejectionReason = ticketvote.RejectionReasonRunoffLost
+	}
+}
+
 // voteIsApproved returns whether the provided vote option results met the
-// provided quorum and pass percentage requirements. This function can only be
-// called on votes that use VoteOptionIDApprove and VoteOptionIDReject. Any
-// other vote option IDs will cause this function to panic.
-func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult) bool {
+// provided quorum and pass percentage requirements. The eligible weight is
+// the total weight of the eligible tickets, i.e. the number of eligible
+// tickets or the eligible stake depending on the vote weight. This function
+// can only be called on votes that use VoteOptionIDApprove and
+// VoteOptionIDReject. Any other vote option IDs will cause this function to
+// panic.
+func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult, eligibleWeight uint64) bool {
 	// Tally the total votes
 	var total uint64
 	for _, v := range results {
-		total += v.Votes
+		total += voteOptionWeight(vd.Params.Weight, v)
 	}
 
 	// Calculate required thresholds
 	var (
-		eligible   = float64(len(vd.EligibleTickets))
-		quorumPerc = float64(vd.Params.QuorumPercentage)
-		passPerc   = float64(vd.Params.PassPercentage)
-		quorum     = uint64(quorumPerc / 100 * eligible)
-		pass       = uint64(passPerc / 100 * float64(total))
+		quorum, pass = voteThresholds(vd.Params.QuorumPercentage,
+			vd.Params.PassPercentage, eligibleWeight, total)
 
 		approvedVotes uint64
 	)
@@ -2833,7 +4610,7 @@ func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionRe
 		switch v.ID {
 		case ticketvote.VoteOptionIDApprove:
 			// Valid vote option
-			approvedVotes = v.Votes
+			approvedVotes = voteOptionWeight(vd.Params.Weight, v)
 		case ticketvote.VoteOptionIDReject:
 			// Valid vote option
 		default:
@@ -2873,6 +4650,75 @@ func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionRe
 	return approved
 }
 
+// voteWinningOption returns the ID of the vote option that won a multi-option
+// vote. The vote must meet the quorum requirement and the winning option must
+// have the most votes and meet the win condition of the vote. The votes are
+// weighted using the vote weight of the vote and the eligible weight is the
+// total weight of the eligible tickets. An empty string is returned if no
+// vote option won the vote.
+func voteWinningOption(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult, eligibleWeight uint64) string {
+	// Tally the total votes
+	var total uint64
+	for _, v := range results {
+		total += voteOptionWeight(vd.Params.Weight, v)
+	}
+
+	// Calculate required thresholds
+	quorum, pass := voteThresholds(vd.Params.QuorumPercentage,
+		vd.Params.PassPercentage, eligibleWeight, total)
+	if total == 0 || total < quorum {
+		log.Debugf("Quorum not met on %v: votes cast %v, quorum %v",
+			vd.Params.Token, total, quorum)
+		return ""
+	}
+
+	// Find the vote option with the most votes. A tie results
+	// in no winner.
+	var (
+		winner string
+		votes  uint64
+		tie    bool
+	)
+	for _, v := range results {
+		w := voteOptionWeight(vd.Params.Weight, v)
+		switch {
+		case w > votes:
+			winner = v.ID
+			votes = w
+			tie = false
+		case w == votes:
+			tie = true
+		}
+	}
+	if tie {
+		log.Debugf("Vote %v tied with %v votes", vd.Params.Token, votes)
+		return ""
+	}
+
+	// Check the win condition
+	switch vd.Params.WinCondition {
+	case ticketvote.WinConditionPlurality:
+		// The option with the most votes wins
+	case ticketvote.WinConditionPassPercentage:
+		if votes < pass {
+			log.Debugf("Pass threshold not met on %v: option %v votes %v, "+
+				"required %v", vd.Params.Token, winner, votes, pass)
+			return ""
+		}
+	default:
+		// Shouldn't happen. The win condition is verified when
+		// the vote is started.
+		log.Errorf("Invalid win condition on %v: %v",
+			vd.Params.Token, vd.Params.WinCondition)
+		return ""
+	}
+
+	log.Debugf("Vote %v won by %v: quorum %v, pass %v, total %v, votes %v",
+		vd.Params.Token, winner, quorum, pass, total, votes)
+
+	return winner
+}
+
 // tokenEncode encodes a token byte slice.
 func tokenEncode(tokenB []byte) string {
 	return util.TokenEncode(tokenB)
@@ -3128,6 +4974,120 @@ func convertStartRunoffFromBlobEntry(be store.BlobEntry) (*startRunoffRecord, er
 	return &srr, nil
 }
 
+func convertExtendDetailsFromBlobEntry(be store.BlobEntry) (*ticketvote.ExtendDetails, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorExtendDetails {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorExtendDetails)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var ed ticketvote.ExtendDetails
+	err = json.Unmarshal(b, &ed)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal ExtendDetails: %v", err)
+	}
+
+	return &ed, nil
+}
+
+func convertCancelDetailsFromBlobEntry(be store.BlobEntry) (*ticketvote.CancelDetails, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorCancelDetails {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorCancelDetails)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var cd ticketvote.CancelDetails
+	err = json.Unmarshal(b, &cd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal CancelDetails: %v", err)
+	}
+
+	return &cd, nil
+}
+
+func convertSummaryFromBlobEntry(be store.BlobEntry) (*ticketvote.SummaryReply, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorSummary {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorSummary)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var sr ticketvote.SummaryReply
+	err = json.Unmarshal(b, &sr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal SummaryReply: %v", err)
+	}
+
+	return &sr, nil
+}
+
 func convertBlobEntryFromAuthDetails(ad ticketvote.AuthDetails) (*store.BlobEntry, error) {
 	data, err := json.Marshal(ad)
 	if err != nil {
@@ -3212,3 +5172,54 @@ func convertBlobEntryFromStartRunoff(srr startRunoffRecord) (*store.BlobEntry, e
 	be := store.NewBlobEntry(hint, data)
 	return &be, nil
 }
+
+func convertBlobEntryFromExtendDetails(ed ticketvote.ExtendDetails) (*store.BlobEntry, error) {
+	data, err := json.Marshal(ed)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorExtendDetails,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+func convertBlobEntryFromCancelDetails(cd ticketvote.CancelDetails) (*store.BlobEntry, error) {
+	data, err := json.Marshal(cd)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorCancelDetails,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+func convertBlobEntryFromSummary(s ticketvote.SummaryReply) (*store.BlobEntry, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorSummary,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
@@ -0,0 +1,1015 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+// multiOptionParams returns valid vote params for a multi-option vote.
+func multiOptionParams() ticketvote.VoteParams {
+	return ticketvote.VoteParams{
+		Token:            "45154fb45664714a",
+		Version:          1,
+		Type:             ticketvote.VoteTypeMultiOption,
+		Mask:             0x07,
+		Duration:         10,
+		QuorumPercentage: 20,
+		PassPercentage:   0,
+		Options: []ticketvote.VoteOption{
+			{ID: "a", Description: "option a", Bit: 0x01},
+			{ID: "b", Description: "option b", Bit: 0x02},
+			{ID: "c", Description: "option c", Bit: 0x04},
+		},
+		WinCondition: ticketvote.WinConditionPlurality,
+	}
+}
+
+func TestVoteParamsVerifyMultiOption(t *testing.T) {
+	var tests = []struct {
+		name      string
+		params    func(*ticketvote.VoteParams)
+		wantError ticketvote.ErrorCodeT
+	}{
+		{
+			"valid plurality",
+			func(p *ticketvote.VoteParams) {},
+			ticketvote.ErrorCodeInvalid,
+		},
+		{
+			"valid pass percentage",
+			func(p *ticketvote.VoteParams) {
+				p.WinCondition = ticketvote.WinConditionPassPercentage
+				p.PassPercentage = 50
+			},
+			ticketvote.ErrorCodeInvalid,
+		},
+		{
+			"one option",
+			func(p *ticketvote.VoteParams) {
+				p.Options = p.Options[:1]
+			},
+			ticketvote.ErrorCodeVoteOptionsInvalid,
+		},
+		{
+			"duplicate option id",
+			func(p *ticketvote.VoteParams) {
+				p.Options[1].ID = "a"
+			},
+			ticketvote.ErrorCodeVoteOptionsInvalid,
+		},
+		{
+			"duplicate option bit",
+			func(p *ticketvote.VoteParams) {
+				p.Options[1].Bit = 0x01
+			},
+			ticketvote.ErrorCodeVoteBitsInvalid,
+		},
+		{
+			"multiple bits",
+			func(p *ticketvote.VoteParams) {
+				p.Options[2].Bit = 0x06
+			},
+			ticketvote.ErrorCodeVoteBitsInvalid,
+		},
+		{
+			"bit not in mask",
+			func(p *ticketvote.VoteParams) {
+				p.Mask = 0x03
+			},
+			ticketvote.ErrorCodeVoteBitsInvalid,
+		},
+		{
+			"parent",
+			func(p *ticketvote.VoteParams) {
+				p.Parent = "45154fb45664714b"
+			},
+			ticketvote.ErrorCodeVoteParentInvalid,
+		},
+		{
+			"no win condition",
+			func(p *ticketvote.VoteParams) {
+				p.WinCondition = ticketvote.WinConditionInvalid
+			},
+			ticketvote.ErrorCodeWinConditionInvalid,
+		},
+		{
+			"plurality with pass percentage",
+			func(p *ticketvote.VoteParams) {
+				p.PassPercentage = 50
+			},
+			ticketvote.ErrorCodeVotePassRateInvalid,
+		},
+		{
+			"standard vote with win condition",
+			func(p *ticketvote.VoteParams) {
+				p.Type = ticketvote.VoteTypeStandard
+				p.Options = []ticketvote.VoteOption{
+					{ID: ticketvote.VoteOptionIDApprove, Bit: 0x01},
+					{ID: ticketvote.VoteOptionIDReject, Bit: 0x02},
+				}
+			},
+			ticketvote.ErrorCodeWinConditionInvalid,
+		},
+		{
+			"winners",
+			func(p *ticketvote.VoteParams) {
+				p.Winners = 2
+			},
+			ticketvote.ErrorCodeRunoffWinnersInvalid,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p := multiOptionParams()
+			v.params(&p)
+			err := voteParamsVerify(p, 1, 100)
+
+			var pe backend.PluginError
+			switch {
+			case v.wantError == ticketvote.ErrorCodeInvalid && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			case v.wantError == ticketvote.ErrorCodeInvalid:
+				// Success
+			case !errors.As(err, &pe):
+				t.Errorf("got error %v, want plugin error %v", err,
+					ticketvote.ErrorCodes[v.wantError])
+			case pe.ErrorCode != uint32(v.wantError):
+				t.Errorf("got error code %v, want %v",
+					ticketvote.ErrorCodes[ticketvote.ErrorCodeT(pe.ErrorCode)],
+					ticketvote.ErrorCodes[v.wantError])
+			}
+		})
+	}
+}
+
+func TestVoteBitsVerify(t *testing.T) {
+	var tests = []struct {
+		name    string
+		mask    uint64
+		options []ticketvote.VoteOption
+		want    []ticketvote.VoteBitError
+	}{
+		{
+			"valid",
+			0x03,
+			[]ticketvote.VoteOption{
+				{ID: "yes", Bit: 0x01},
+				{ID: "no", Bit: 0x02},
+			},
+			[]ticketvote.VoteBitError{},
+		},
+		{
+			"no options",
+			0x03,
+			nil,
+			[]ticketvote.VoteBitError{
+				{
+					ErrorCode: ticketvote.VoteBitErrorNoOptions,
+					Mask:      0x03,
+				},
+			},
+		},
+		{
+			"multiple invalid bits",
+			0x03,
+			[]ticketvote.VoteOption{
+				{ID: "yes", Bit: 0x01},
+				{ID: "no", Bit: 0x04},
+				{ID: "abstain", Bit: 0x00},
+				{ID: "maybe", Bit: 0x01},
+			},
+			[]ticketvote.VoteBitError{
+				{
+					ErrorCode: ticketvote.VoteBitErrorBitNotInMask,
+					OptionID:  "no",
+					Bit:       0x04,
+					Mask:      0x03,
+				},
+				{
+					ErrorCode: ticketvote.VoteBitErrorBitZero,
+					OptionID:  "abstain",
+					Mask:      0x03,
+				},
+				{
+					ErrorCode: ticketvote.VoteBitErrorBitDuplicate,
+					OptionID:  "maybe",
+					Bit:       0x01,
+					Mask:      0x03,
+				},
+			},
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := voteBitsVerify(ticketvote.VoteParams{
+				Mask:    v.mask,
+				Options: v.options,
+			})
+			if !reflect.DeepEqual(got, v.want) {
+				t.Errorf("got %+v, want %+v", got, v.want)
+			}
+		})
+	}
+
+	// Verify that the vote params error identifies the first invalid
+	// vote option.
+	p := multiOptionParams()
+	p.Mask = 0x03
+	err := voteParamsVerify(p, 1, 100)
+	var pe backend.PluginError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got error %v, want plugin error", err)
+	}
+	want := "vote option c bit 0x4 mask 0x3: bit not included in mask"
+	if pe.ErrorContext != want {
+		t.Fatalf("got error context %q, want %q", pe.ErrorContext, want)
+	}
+}
+
+func TestVoteBitVerify(t *testing.T) {
+	options := multiOptionParams().Options
+	var tests = []struct {
+		name string
+		bit  uint64
+		want ticketvote.VoteBitErrorT
+	}{
+		{"valid", 0x02, ticketvote.VoteBitErrorInvalid},
+		{"zero", 0x00, ticketvote.VoteBitErrorBitZero},
+		{"not in mask", 0x08, ticketvote.VoteBitErrorBitNotInMask},
+		{"not in options", 0x03, ticketvote.VoteBitErrorBitNotInOptions},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := voteBitVerify(options, 0x07, v.bit)
+			var vbe voteBitError
+			switch {
+			case v.want == ticketvote.VoteBitErrorInvalid && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			case v.want == ticketvote.VoteBitErrorInvalid:
+				// Success
+			case !errors.As(err, &vbe):
+				t.Errorf("got error %v, want vote bit error", err)
+			case vbe.ErrorCode != v.want:
+				t.Errorf("got error code %v, want %v",
+					ticketvote.VoteBitErrors[vbe.ErrorCode],
+					ticketvote.VoteBitErrors[v.want])
+			}
+		})
+	}
+}
+
+func TestVoteWinningOption(t *testing.T) {
+	// 10 eligible tickets with a 20% quorum
+	eligible := make([]string, 10)
+	var tests = []struct {
+		name         string
+		winCondition ticketvote.WinConditionT
+		pass         uint32
+		votes        []uint64 // Votes for options a, b, c
+		want         string
+	}{
+		{"plurality", ticketvote.WinConditionPlurality, 0,
+			[]uint64{1, 3, 2}, "b"},
+		{"quorum not met", ticketvote.WinConditionPlurality, 0,
+			[]uint64{0, 1, 0}, ""},
+		{"tie", ticketvote.WinConditionPlurality, 0,
+			[]uint64{3, 3, 1}, ""},
+		{"pass percentage met", ticketvote.WinConditionPassPercentage, 50,
+			[]uint64{1, 4, 2}, "b"},
+		{"pass percentage not met", ticketvote.WinConditionPassPercentage, 60,
+			[]uint64{2, 3, 2}, ""},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p := multiOptionParams()
+			p.WinCondition = v.winCondition
+			p.PassPercentage = v.pass
+			vd := ticketvote.VoteDetails{
+				Params:          p,
+				EligibleTickets: eligible,
+			}
+			results := make([]ticketvote.VoteOptionResult, 0, len(p.Options))
+			for i, o := range p.Options {
+				results = append(results, ticketvote.VoteOptionResult{
+					ID:      o.ID,
+					VoteBit: o.Bit,
+					Votes:   v.votes[i],
+				})
+			}
+			got := voteWinningOption(vd, results, uint64(len(eligible)))
+			if got != v.want {
+				t.Errorf("got winning option '%v', want '%v'", got, v.want)
+			}
+		})
+	}
+}
+
+func TestVoteIsApprovedStake(t *testing.T) {
+	// 1000 atoms of eligible stake with a 20% quorum and a 60% pass
+	// percentage.
+	const eligibleStake = 1000
+	var tests = []struct {
+		name    string
+		approve ticketvote.VoteOptionResult
+		reject  ticketvote.VoteOptionResult
+		want    bool
+	}{
+		{"approved by stake",
+			ticketvote.VoteOptionResult{Votes: 1, Stake: 300},
+			ticketvote.VoteOptionResult{Votes: 5, Stake: 100}, true},
+		{"rejected by stake",
+			ticketvote.VoteOptionResult{Votes: 5, Stake: 100},
+			ticketvote.VoteOptionResult{Votes: 1, Stake: 300}, false},
+		{"quorum not met",
+			ticketvote.VoteOptionResult{Votes: 50, Stake: 150},
+			ticketvote.VoteOptionResult{Votes: 0, Stake: 0}, false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			vd := ticketvote.VoteDetails{
+				Params: ticketvote.VoteParams{
+					Type:             ticketvote.VoteTypeStandard,
+					QuorumPercentage: 20,
+					PassPercentage:   60,
+					Weight:           ticketvote.VoteWeightStake,
+				},
+			}
+			v.approve.ID = ticketvote.VoteOptionIDApprove
+			v.reject.ID = ticketvote.VoteOptionIDReject
+			results := []ticketvote.VoteOptionResult{v.approve, v.reject}
+			got := voteIsApproved(vd, results, eligibleStake)
+			if got != v.want {
+				t.Errorf("got approved %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestSummaryExplain(t *testing.T) {
+	// 100 eligible tickets with a 20% quorum and a 60% pass
+	// percentage.
+	approveReject := func(approve, reject uint64) []ticketvote.VoteOptionResult {
+		return []ticketvote.VoteOptionResult{
+			{ID: ticketvote.VoteOptionIDApprove, Votes: approve},
+			{ID: ticketvote.VoteOptionIDReject, Votes: reject},
+		}
+	}
+	var tests = []struct {
+		name         string
+		voteType     ticketvote.VoteT
+		status       ticketvote.VoteStatusT
+		results      []ticketvote.VoteOptionResult
+		wantQuorum   float64
+		wantApproval float64
+		wantReason   ticketvote.RejectionReasonT
+	}{
+		{"not started", ticketvote.VoteTypeStandard,
+			ticketvote.VoteStatusAuthorized, nil, 0, 0,
+			ticketvote.RejectionReasonInvalid},
+		{"started", ticketvote.VoteTypeStandard,
+			ticketvote.VoteStatusStarted, approveReject(2, 1), 3, 66.67,
+			ticketvote.RejectionReasonInvalid},
+		{"approved", ticketvote.VoteTypeStandard,
+			ticketvote.VoteStatusApproved, approveReject(20, 5), 25, 80,
+			ticketvote.RejectionReasonInvalid},
+		{"quorum not met", ticketvote.VoteTypeStandard,
+			ticketvote.VoteStatusRejected, approveReject(15, 0), 15, 100,
+			ticketvote.RejectionReasonQuorumNotMet},
+		{"approval not met", ticketvote.VoteTypeStandard,
+			ticketvote.VoteStatusRejected, approveReject(10, 15), 25, 40,
+			ticketvote.RejectionReasonApprovalNotMet},
+		{"runoff lost", ticketvote.VoteTypeRunoff,
+			ticketvote.VoteStatusRejected, approveReject(20, 5), 25, 80,
+			ticketvote.RejectionReasonRunoffLost},
+		{"multi-option", ticketvote.VoteTypeMultiOption,
+			ticketvote.VoteStatusFinished,
+			[]ticketvote.VoteOptionResult{
+				{ID: "a", Votes: 10},
+				{ID: "b", Votes: 20},
+			}, 30, 0, ticketvote.RejectionReasonInvalid},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			s := ticketvote.SummaryReply{
+				Type:             v.voteType,
+				Status:           v.status,
+				EligibleTickets:  100,
+				QuorumPercentage: 20,
+				PassPercentage:   60,
+				Results:          v.results,
+			}
+			summaryExplain(&s)
+			if s.QuorumPercentageAchieved != v.wantQuorum {
+				t.Errorf("got quorum achieved %v, want %v",
+					s.QuorumPercentageAchieved, v.wantQuorum)
+			}
+			if s.ApprovalPercentage != v.wantApproval {
+				t.Errorf("got approval %v, want %v",
+					s.ApprovalPercentage, v.wantApproval)
+			}
+			if s.RejectionReason != v.wantReason {
+				t.Errorf("got rejection reason %v, want %v",
+					s.RejectionReason, v.wantReason)
+			}
+		})
+	}
+}
+
+func TestAuthLockVerify(t *testing.T) {
+	var tests = []struct {
+		name        string
+		blockHeight uint32 // Block height of the authorization
+		bestBlock   uint32
+		wantErr     bool
+	}{
+		{"no block height", 0, 100, false},
+		{"locked", 100, 105, true},
+		{"last locked block", 100, 109, true},
+		{"unlocked", 100, 110, false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			auth := ticketvote.AuthDetails{
+				Action:      string(ticketvote.AuthActionAuthorize),
+				BlockHeight: v.blockHeight,
+			}
+			err := authLockVerify(auth, 10, v.bestBlock)
+			var pe backend.PluginError
+			switch {
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			case v.wantErr && !errors.As(err, &pe):
+				t.Errorf("got error %v, want plugin error", err)
+			case v.wantErr && pe.ErrorCode !=
+				uint32(ticketvote.ErrorCodeAuthorizationLocked):
+				t.Errorf("got error code %v, want %v", pe.ErrorCode,
+					ticketvote.ErrorCodeAuthorizationLocked)
+			}
+		})
+	}
+}
+
+func TestConcurrentVotesVerify(t *testing.T) {
+	var tests = []struct {
+		name           string
+		active         uint32
+		max            uint32
+		block          bool
+		wantConcurrent uint32
+		wantExceeded   bool
+		wantErr        bool
+	}{
+		{"disabled", 50, 0, true, 51, false, false},
+		{"under max", 3, 5, false, 4, false, false},
+		{"at max", 4, 5, true, 5, false, false},
+		{"exceeded warning", 5, 5, false, 6, true, false},
+		{"exceeded blocked", 5, 5, true, 0, false, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			concurrent, exceeded, err := concurrentVotesVerify(v.active,
+				v.max, v.block)
+			var pe backend.PluginError
+			switch {
+			case !v.wantErr && err != nil:
+				t.Fatalf("got error %v, want nil", err)
+			case v.wantErr && !errors.As(err, &pe):
+				t.Fatalf("got error %v, want plugin error", err)
+			case v.wantErr && pe.ErrorCode !=
+				uint32(ticketvote.ErrorCodeConcurrentVotesExceeded):
+				t.Fatalf("got error code %v, want %v", pe.ErrorCode,
+					ticketvote.ErrorCodeConcurrentVotesExceeded)
+			}
+			if concurrent != v.wantConcurrent || exceeded != v.wantExceeded {
+				t.Errorf("got %v/%v, want %v/%v", concurrent, exceeded,
+					v.wantConcurrent, v.wantExceeded)
+			}
+		})
+	}
+}
+
+func TestRunoffWinners(t *testing.T) {
+	candidates := []runoffCandidate{
+		{token: "a", netApprove: 5},
+		{token: "b", netApprove: 9},
+		{token: "c", netApprove: 0},
+		{token: "d", netApprove: 5},
+		{token: "e", netApprove: -2},
+	}
+	var tests = []struct {
+		name    string
+		winners uint32
+		want    []string
+	}{
+		{"single winner", runoffWinnersCount(0), []string{"b"}},
+		{"tie resolved by order", 2, []string{"b", "a"}},
+		{"multiple winners", 3, []string{"b", "a", "d"}},
+		{"more winners than candidates", 4, []string{"b", "a", "d"}},
+		{"all winners", ticketvote.RunoffWinnersAll, []string{"b", "a", "d"}},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := runoffWinners(candidates, v.winners)
+			if !reflect.DeepEqual(got, v.want) {
+				t.Errorf("got winners %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestVoteEndBlockHeight(t *testing.T) {
+	vd := ticketvote.VoteDetails{
+		EndBlockHeight: 100,
+	}
+	var tests = []struct {
+		name string
+		exts []uint32 // Extension end block heights
+		want uint32
+	}{
+		{"no extensions", []uint32{}, 100},
+		{"one extension", []uint32{150}, 150},
+		{"multiple extensions", []uint32{120, 180, 150}, 180},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			exts := make([]ticketvote.ExtendDetails, 0, len(v.exts))
+			for _, h := range v.exts {
+				exts = append(exts, ticketvote.ExtendDetails{
+					EndBlockHeight: h,
+				})
+			}
+			got := voteEndBlockHeight(vd, exts)
+			if got != v.want {
+				t.Errorf("got end block height %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestVoteIsDecided(t *testing.T) {
+	var (
+		standard = ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Type: ticketvote.VoteTypeStandard,
+			},
+			EligibleTickets: []string{"a", "b", "c"},
+		}
+		runoff = ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Type: ticketvote.VoteTypeRunoff,
+			},
+			EligibleTickets: []string{"a", "b", "c"},
+		}
+	)
+	results := func(votes ...uint64) []ticketvote.VoteOptionResult {
+		r := make([]ticketvote.VoteOptionResult, 0, len(votes))
+		for _, v := range votes {
+			r = append(r, ticketvote.VoteOptionResult{Votes: v})
+		}
+		return r
+	}
+	var tests = []struct {
+		name        string
+		earlyFinish bool
+		vd          ticketvote.VoteDetails
+		results     []ticketvote.VoteOptionResult
+		want        bool
+	}{
+		{"setting disabled", false, standard, results(2, 1), false},
+		{"tickets remaining", true, standard, results(1, 1), false},
+		{"all tickets voted", true, standard, results(2, 1), true},
+		{"runoff vote", true, runoff, results(2, 1), false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p := &ticketVotePlugin{
+				earlyFinish: v.earlyFinish,
+			}
+			got := p.voteIsDecided(v.vd, v.results)
+			if got != v.want {
+				t.Errorf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestSummaryBlobEntry(t *testing.T) {
+	s := ticketvote.SummaryReply{
+		Type:             ticketvote.VoteTypeStandard,
+		Status:           ticketvote.VoteStatusApproved,
+		Duration:         10,
+		StartBlockHeight: 100,
+		EndBlockHeight:   110,
+		EligibleTickets:  10,
+		QuorumPercentage: 20,
+		PassPercentage:   60,
+		Results: []ticketvote.VoteOptionResult{
+			{ID: ticketvote.VoteOptionIDApprove, VoteBit: 0x01, Votes: 8},
+			{ID: ticketvote.VoteOptionIDReject, VoteBit: 0x02, Votes: 1},
+		},
+	}
+	be, err := convertBlobEntryFromSummary(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := convertSummaryFromBlobEntry(*be)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != s.Status || got.EndBlockHeight != s.EndBlockHeight ||
+		len(got.Results) != len(s.Results) ||
+		got.Results[0].Votes != s.Results[0].Votes {
+		t.Errorf("got summary %+v, want %+v", got, s)
+	}
+
+	// A blob entry with a different data descriptor must be rejected
+	ed, err := convertBlobEntryFromExtendDetails(ticketvote.ExtendDetails{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = convertSummaryFromBlobEntry(*ed)
+	if err == nil {
+		t.Errorf("got nil error, want data descriptor error")
+	}
+}
+
+func TestResultsExportEncode(t *testing.T) {
+	votes := []ticketvote.CastVoteDetails{
+		{Ticket: "aa", VoteBit: "1", Timestamp: 100, Address: "Dsa"},
+		{Ticket: "bb", VoteBit: "2", Timestamp: 200, Address: "Dsb"},
+	}
+	var tests = []struct {
+		name    string
+		format  ticketvote.ExportFormatT
+		votes   []ticketvote.CastVoteDetails
+		want    string
+		wantErr bool
+	}{
+		{"csv", ticketvote.ExportFormatCSV, votes,
+			"ticket,votebit,timestamp,address\n" +
+				"aa,1,100,Dsa\nbb,2,200,Dsb\n", false},
+		{"csv empty page", ticketvote.ExportFormatCSV, nil,
+			"ticket,votebit,timestamp,address\n", false},
+		{"ndjson", ticketvote.ExportFormatNDJSON, votes,
+			`{"ticket":"aa","votebit":"1","timestamp":100,"address":"Dsa"}` +
+				"\n" +
+				`{"ticket":"bb","votebit":"2","timestamp":200,"address":"Dsb"}` +
+				"\n", false},
+		{"ndjson empty page", ticketvote.ExportFormatNDJSON, nil, "", false},
+		{"invalid format", ticketvote.ExportFormatInvalid, votes, "", true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got, err := resultsExportEncode(v.format, v.votes)
+			switch {
+			case v.wantErr && err == nil:
+				t.Errorf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			case got != v.want:
+				t.Errorf("got %q, want %q", got, v.want)
+			}
+		})
+	}
+}
+
+func TestRunoffStartBatches(t *testing.T) {
+	starts := make([]ticketvote.StartDetails, 5)
+	for i := range starts {
+		starts[i].Params.Token = strconv.Itoa(i)
+	}
+	var tests = []struct {
+		name      string
+		starts    []ticketvote.StartDetails
+		batchSize int
+		want      []int // Batch lengths
+	}{
+		{"no starts", nil, 2, []int{}},
+		{"single batch", starts, 10, []int{5}},
+		{"exact batches", starts[:4], 2, []int{2, 2}},
+		{"partial last batch", starts, 2, []int{2, 2, 1}},
+		{"batch size one", starts[:3], 1, []int{1, 1, 1}},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			batches := runoffStartBatches(v.starts, v.batchSize)
+			if len(batches) != len(v.want) {
+				t.Fatalf("got %v batches, want %v",
+					len(batches), len(v.want))
+			}
+			var i int
+			for j, b := range batches {
+				if len(b) != v.want[j] {
+					t.Errorf("batch %v: got len %v, want %v",
+						j, len(b), v.want[j])
+				}
+				// Verify the start details order is preserved
+				for _, sd := range b {
+					if sd.Params.Token != strconv.Itoa(i) {
+						t.Errorf("got token %v, want %v",
+							sd.Params.Token, i)
+					}
+					i++
+				}
+			}
+		})
+	}
+}
+
+func TestVoteDiagnostics(t *testing.T) {
+	vd := &ticketvote.VoteDetails{
+		StartBlockHeight: 100,
+		EndBlockHeight:   200,
+	}
+	ticket := "1d3a3e1e2b4c8f1fbb0d0e5c6a7d8f9e0a1b2c3d4e5f60718293a4b5c6d7e8f9"
+
+	// Vote details are included when the vote is active
+	d := voteDiagnostics(ineligibleReason(ticket, vd.StartBlockHeight),
+		vd, 150)
+	if d.SnapshotHeight != 100 || d.EndBlockHeight != 200 ||
+		d.BestBlock != 150 {
+		t.Errorf("got diagnostics %+v", d)
+	}
+	want := "ticket was not live at the snapshot block height 100"
+	if d.Reason != want {
+		t.Errorf("got reason %q, want %q", d.Reason, want)
+	}
+
+	// Vote details are omitted when the vote is not active
+	d = voteDiagnostics("vote is not active", nil, 150)
+	if d.SnapshotHeight != 0 || d.EndBlockHeight != 0 {
+		t.Errorf("got diagnostics %+v", d)
+	}
+
+	// Malformed tickets
+	want = "ticket is not a valid ticket hash"
+	for _, v := range []string{"", "zz", ticket[:62]} {
+		got := ineligibleReason(v, 100)
+		if got != want {
+			t.Errorf("ticket %q: got reason %q, want %q", v, got, want)
+		}
+	}
+}
+
+// cancelTstoreClient is a plugins TstoreClient that returns the blobs of a
+// single record. All other TstoreClient methods panic.
+type cancelTstoreClient struct {
+	plugins.TstoreClient
+	blobs map[string][]store.BlobEntry // [dataDesc]blobs
+}
+
+// DigestsByDataDesc returns the digests of the blobs that match the provided
+// data descriptors.
+func (c *cancelTstoreClient) DigestsByDataDesc(token []byte, dataDesc []string) ([][]byte, error) {
+	digests := make([][]byte, 0, len(dataDesc))
+	for _, dd := range dataDesc {
+		for _, v := range c.blobs[dd] {
+			d, err := hex.DecodeString(v.Digest)
+			if err != nil {
+				return nil, err
+			}
+			digests = append(digests, d)
+		}
+	}
+	return digests, nil
+}
+
+// Blobs returns the blobs for the provided digests.
+func (c *cancelTstoreClient) Blobs(token []byte, digests [][]byte) (map[string]store.BlobEntry, error) {
+	entries := make(map[string]store.BlobEntry, len(digests))
+	for _, blobs := range c.blobs {
+		for _, v := range blobs {
+			for _, d := range digests {
+				if v.Digest == hex.EncodeToString(d) {
+					entries[v.Digest] = v
+				}
+			}
+		}
+	}
+	return entries, nil
+}
+
+// cancelChain is a chain that returns a fixed best block. All other chain
+// methods panic.
+type cancelChain struct {
+	chain
+	bestBlock uint32
+}
+
+// BestBlock returns the best block.
+func (c *cancelChain) BestBlock() (uint32, error) {
+	return c.bestBlock, nil
+}
+
+func TestCmdCancel(t *testing.T) {
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := "45154fb45664714a"
+	tokenb, err := tokenDecode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup the blobs
+	vd, err := convertBlobEntryFromVoteDetails(ticketvote.VoteDetails{
+		Params:           ticketvote.VoteParams{Token: token},
+		StartBlockHeight: 100,
+		EndBlockHeight:   110,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cv, err := convertBlobEntryFromCastVoteDetails(ticketvote.CastVoteDetails{
+		Token: token,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name      string
+		reason    string
+		blobs     map[string][]store.BlobEntry
+		bestBlock uint32
+		wantErr   ticketvote.ErrorCodeT
+	}{
+		{
+			"reason not provided",
+			" ",
+			nil,
+			105,
+			ticketvote.ErrorCodeVoteCancelInvalid,
+		},
+		{
+			"vote not started",
+			"reason",
+			map[string][]store.BlobEntry{},
+			105,
+			ticketvote.ErrorCodeVoteStatusInvalid,
+		},
+		{
+			"vote ended",
+			"reason",
+			map[string][]store.BlobEntry{
+				dataDescriptorVoteDetails: {*vd},
+			},
+			110,
+			ticketvote.ErrorCodeVoteStatusInvalid,
+		},
+		{
+			"ballots cast",
+			"reason",
+			map[string][]store.BlobEntry{
+				dataDescriptorVoteDetails:     {*vd},
+				dataDescriptorCastVoteDetails: {*cv},
+			},
+			105,
+			ticketvote.ErrorCodeVoteCancelInvalid,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p := &ticketVotePlugin{
+				tstore: &cancelTstoreClient{blobs: v.blobs},
+				chain:  &cancelChain{bestBlock: v.bestBlock},
+			}
+			sig := id.SignMessage([]byte(token + v.reason))
+			b, err := json.Marshal(ticketvote.Cancel{
+				Token:     token,
+				Reason:    v.reason,
+				PublicKey: id.Public.String(),
+				Signature: hex.EncodeToString(sig[:]),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.cmdCancel(tokenb, string(b))
+			var pe backend.PluginError
+			if !errors.As(err, &pe) || pe.ErrorCode != uint32(v.wantErr) {
+				t.Errorf("got error %v, want %v", err,
+					ticketvote.ErrorCodes[v.wantErr])
+			}
+		})
+	}
+}
+
+// sigEligibility is an eligibility that accepts a signature if it is equal to
+// the address that signed the vote. All other eligibility methods panic.
+type sigEligibility struct {
+	eligibility
+}
+
+// VerifySignature verifies that the signature is equal to the address.
+func (e *sigEligibility) VerifySignature(addr, msg, signature string) error {
+	if signature != addr {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func TestCastVotesVerify(t *testing.T) {
+	const voteCount = 100
+	var (
+		votes    = make([]ticketvote.CastVote, 0, voteCount)
+		receipts = make([]ticketvote.CastVoteReply, voteCount)
+		addrs    = make(map[string]commitmentAddr, voteCount)
+		vd       = &ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Weight: ticketvote.VoteWeightStake,
+			},
+		}
+	)
+	for i := 0; i < voteCount; i++ {
+		ticket := fmt.Sprintf("%064x", i)
+		addr := fmt.Sprintf("addr%v", i)
+		v := ticketvote.CastVote{
+			Ticket:    ticket,
+			Signature: addr,
+		}
+		switch i % 5 {
+		case 0:
+			// Valid vote
+		case 1:
+			// Invalid signature
+			v.Signature = "invalid"
+		case 2:
+			// Missing commitment address
+			votes = append(votes, v)
+			continue
+		case 3:
+			// Ticket stake not found
+			addrs[ticket] = commitmentAddr{addr: addr}
+			votes = append(votes, v)
+			continue
+		case 4:
+			// Vote already has an error
+			e := ticketvote.VoteErrorTicketAlreadyVoted
+			receipts[i].Ticket = ticket
+			receipts[i].ErrorCode = &e
+		}
+		addrs[ticket] = commitmentAddr{
+			addr:  addr,
+			stake: 1,
+		}
+		votes = append(votes, v)
+	}
+
+	p := &ticketVotePlugin{
+		eligibility:       &sigEligibility{},
+		castBallotWorkers: 8,
+	}
+	br := newBallotResults()
+	p.castVotesVerify(votes, receipts, addrs, vd, 100, &br)
+
+	for i, v := range votes {
+		r := receipts[i]
+		_, stashed := br.addrGet(v.Ticket)
+		var want *ticketvote.VoteErrorT
+		switch i % 5 {
+		case 0:
+			// No error
+		case 1:
+			e := ticketvote.VoteErrorSignatureInvalid
+			want = &e
+		case 2, 3:
+			e := ticketvote.VoteErrorInternalError
+			want = &e
+		case 4:
+			e := ticketvote.VoteErrorTicketAlreadyVoted
+			want = &e
+		}
+		switch {
+		case want == nil && r.ErrorCode != nil:
+			t.Errorf("vote %v: got error %v, want nil", i, *r.ErrorCode)
+		case want != nil && r.ErrorCode == nil:
+			t.Errorf("vote %v: got nil error, want %v", i, *want)
+		case want != nil && *r.ErrorCode != *want:
+			t.Errorf("vote %v: got error %v, want %v",
+				i, *r.ErrorCode, *want)
+		}
+		if want != nil && r.Ticket != v.Ticket {
+			t.Errorf("vote %v: got ticket %v, want %v", i, r.Ticket, v.Ticket)
+		}
+		if stashed != (want == nil) {
+			t.Errorf("vote %v: got addr stashed %v, want %v",
+				i, stashed, want == nil)
+		}
+	}
+}
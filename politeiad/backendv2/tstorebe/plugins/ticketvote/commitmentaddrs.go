@@ -0,0 +1,127 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+const (
+	// commitmentAddrKey is the key-value store key for a cached commitment
+	// address. The "{ticket}" is replaced with the ticket hash.
+	commitmentAddrKey = "commitmentaddr-{ticket}"
+)
+
+// commitmentAddrEntry is the key-value store entry for a cached commitment
+// address. The commitment address of a ticket is set when the ticket is
+// purchased and never changes, so an entry remains valid for all votes that
+// the ticket is eligible in. The height is the snapshot height of the vote
+// that the address was first retrieved for.
+type commitmentAddrEntry struct {
+	Addr   string `json:"addr"`
+	Stake  uint64 `json:"stake"` // In atoms
+	Height uint32 `json:"height"`
+}
+
+// buildCommitmentAddrKey returns the key-value store key for the cached
+// commitment address of a ticket.
+func buildCommitmentAddrKey(ticket string) string {
+	return strings.Replace(commitmentAddrKey, "{ticket}", ticket, 1)
+}
+
+// commitmentAddrsCacheable returns whether the commitment addresses that are
+// returned by the eligibility source can be cached. Only the dcr ticket
+// commitment addresses are cached. They are retrieved from dcrdata, which is
+// expensive, and are immutable. The voter list eligibility reads them from a
+// local file that can be changed by the operator.
+func (p *ticketVotePlugin) commitmentAddrsCacheable() bool {
+	return p.eligibilityName == ticketvote.EligibilityDecred
+}
+
+// commitmentAddrs returns the largest commitment address for each of the
+// provided tickets. The addresses are first looked up in the key-value store.
+// Any tickets that are not found are retrieved from the eligibility source
+// and the results are saved to the key-value store along with the provided
+// snapshot height.
+//
+// If an error is encountered while retrieving the address of a ticket, the
+// error is included in the commitmentAddr of the ticket and the ticket is not
+// cached.
+func (p *ticketVotePlugin) commitmentAddrs(tickets []string, height uint32) (map[string]commitmentAddr, error) {
+	if !p.commitmentAddrsCacheable() {
+		return p.eligibility.CommitmentAddrs(tickets)
+	}
+
+	// Lookup the cached addresses
+	keys := make([]string, 0, len(tickets))
+	for _, v := range tickets {
+		keys = append(keys, buildCommitmentAddrKey(v))
+	}
+	blobs, err := p.tstore.CacheGet(keys)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make(map[string]commitmentAddr, len(tickets))
+	notCached := make([]string, 0, len(tickets))
+	for _, v := range tickets {
+		b, ok := blobs[buildCommitmentAddrKey(v)]
+		if !ok {
+			notCached = append(notCached, v)
+			continue
+		}
+		var e commitmentAddrEntry
+		err := json.Unmarshal(b, &e)
+		if err != nil {
+			return nil, err
+		}
+		addrs[v] = commitmentAddr{
+			addr:  e.Addr,
+			stake: e.Stake,
+		}
+	}
+
+	log.Debugf("%v/%v commitment addresses found in the key-value store",
+		len(tickets)-len(notCached), len(tickets))
+
+	if len(notCached) == 0 {
+		return addrs, nil
+	}
+
+	// Retrieve the remaining addresses from the eligibility source
+	fetched, err := p.eligibility.CommitmentAddrs(notCached)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string][]byte, len(fetched))
+	for k, v := range fetched {
+		addrs[k] = v
+		if v.err != nil {
+			continue
+		}
+		b, err := json.Marshal(commitmentAddrEntry{
+			Addr:   v.addr,
+			Stake:  v.stake,
+			Height: height,
+		})
+		if err != nil {
+			return nil, err
+		}
+		entries[buildCommitmentAddrKey(k)] = b
+	}
+
+	// Save the retrieved addresses. A failure to cache the addresses
+	// does not prevent them from being returned.
+	if len(entries) > 0 {
+		err = p.tstore.CachePut(entries, false)
+		if err != nil {
+			log.Errorf("Cache commitment addresses: %v", err)
+		}
+	}
+
+	return addrs, nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+// cacheTstoreClient is a plugins TstoreClient that implements the key-value
+// store methods using a map. All other TstoreClient methods panic.
+type cacheTstoreClient struct {
+	plugins.TstoreClient
+	blobs map[string][]byte
+}
+
+// CachePut saves the provided blobs to the map.
+func (c *cacheTstoreClient) CachePut(blobs map[string][]byte, encrypt bool) error {
+	for k, v := range blobs {
+		c.blobs[k] = v
+	}
+	return nil
+}
+
+// CacheGet returns the blobs from the map for the provided keys.
+func (c *cacheTstoreClient) CacheGet(keys []string) (map[string][]byte, error) {
+	blobs := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		if b, ok := c.blobs[k]; ok {
+			blobs[k] = b
+		}
+	}
+	return blobs, nil
+}
+
+// addrsEligibility is an eligibility that records the tickets that commitment
+// addresses are requested for. All other eligibility methods panic.
+type addrsEligibility struct {
+	eligibility
+	requested []string
+}
+
+// CommitmentAddrs returns a commitment address for each ticket. The address
+// of the ticket "bad" contains an error.
+func (e *addrsEligibility) CommitmentAddrs(tickets []string) (map[string]commitmentAddr, error) {
+	e.requested = append(e.requested, tickets...)
+	addrs := make(map[string]commitmentAddr, len(tickets))
+	for _, v := range tickets {
+		if v == "bad" {
+			addrs[v] = commitmentAddr{err: errors.New("not found")}
+			continue
+		}
+		addrs[v] = commitmentAddr{addr: "addr-" + v, stake: 100}
+	}
+	return addrs, nil
+}
+
+func TestCommitmentAddrs(t *testing.T) {
+	var (
+		c = &cacheTstoreClient{blobs: make(map[string][]byte)}
+		e = &addrsEligibility{}
+		p = &ticketVotePlugin{
+			tstore:          c,
+			eligibility:     e,
+			eligibilityName: ticketvote.EligibilityDecred,
+		}
+	)
+
+	// The first lookup should fetch all addresses from the
+	// eligibility source.
+	addrs, err := p.commitmentAddrs([]string{"a", "b", "bad"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.requested) != 3 {
+		t.Fatalf("got %v requested tickets, want 3", len(e.requested))
+	}
+	if addrs["a"].addr != "addr-a" || addrs["a"].stake != 100 {
+		t.Errorf("unexpected commitment addr %+v", addrs["a"])
+	}
+	if addrs["bad"].err == nil {
+		t.Errorf("got nil error for bad ticket")
+	}
+
+	// The second lookup should only fetch the addresses that were
+	// not cached. Addresses with errors are not cached.
+	e.requested = nil
+	addrs, err = p.commitmentAddrs([]string{"a", "bad", "c"}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.requested) != 2 ||
+		e.requested[0] != "bad" || e.requested[1] != "c" {
+		t.Errorf("got requested tickets %v, want [bad c]", e.requested)
+	}
+	if addrs["a"].addr != "addr-a" || addrs["a"].stake != 100 {
+		t.Errorf("unexpected cached commitment addr %+v", addrs["a"])
+	}
+	if len(addrs) != 3 {
+		t.Errorf("got %v addrs, want 3", len(addrs))
+	}
+
+	// The voter list eligibility addresses should not be cached
+	p.eligibilityName = ticketvote.EligibilityVoterList
+	e.requested = nil
+	_, err = p.commitmentAddrs([]string{"a"}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.requested) != 1 {
+		t.Errorf("got %v requested tickets, want 1", len(e.requested))
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/pkg/errors"
+)
+
+// eligibility provides the voting eligibility data of a ticket vote: the
+// eligible tickets of a vote and the addresses that must sign the votes. The
+// eligibility implementation that is used is selected using the eligibility
+// plugin setting. This allows votes to be run using voting eligibility
+// sources other than Decred tickets, such as an externally supplied voter
+// list.
+//
+// Eligible tickets must be hex encoded 32 byte hashes.
+type eligibility interface {
+	// Setup performs any required eligibility setup, e.g. verifying that
+	// the plugin dependencies have been registered.
+	Setup() error
+
+	// EligibleTickets returns the tickets that are eligible to vote at
+	// the provided snapshot block.
+	EligibleTickets(blockHash string) ([]string, error)
+
+	// CommitmentAddrs returns the address that must sign the vote of
+	// each of the provided tickets. If an error is encountered while
+	// retrieving the address of a ticket, the error is included in the
+	// commitmentAddr of the ticket.
+	CommitmentAddrs(tickets []string) (map[string]commitmentAddr, error)
+
+	// VerifySignature verifies that the hex encoded signature is a
+	// valid signature of the message for the provided address.
+	VerifySignature(addr, msg, signature string) error
+
+	// StakeWeighted returns whether the eligibility provides the ticket
+	// stakes that are required to run a VoteWeightStake vote.
+	StakeWeighted() bool
+}
+
+// commitmentAddr represents the address that must sign the vote of a ticket.
+// For dcr tickets this is the largest commitment address of the ticket. The
+// stake is only provided by eligibility implementations that are stake
+// weighted.
+type commitmentAddr struct {
+	addr  string // Commitment address
+	stake uint64 // Sum of the commitment amounts in atoms
+	err   error  // Error if one occurred
+}
+
+// newEligibility returns the eligibility implementation for the provided
+// eligibility plugin setting.
+func newEligibility(name, voterList, dataDir string, backend backend.Backend, activeNetParams *chaincfg.Params) (eligibility, error) {
+	switch name {
+	case ticketvote.EligibilityDecred:
+		return newDcrEligibility(backend, activeNetParams), nil
+	case ticketvote.EligibilityVoterList:
+		if voterList == "" {
+			return nil, errors.Errorf("plugin setting '%v' is required",
+				ticketvote.SettingKeyVoterList)
+		}
+		return newVoterListEligibility(voterList, dataDir)
+	}
+	return nil, errors.Errorf("unknown eligibility '%v'", name)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestNewEligibility(t *testing.T) {
+	var tests = []struct {
+		name        string
+		eligibility string
+		voterList   string
+		wantErr     bool
+	}{
+		{"decred", ticketvote.EligibilityDecred, "", false},
+		{"voter list", ticketvote.EligibilityVoterList, "voters.csv", false},
+		{"voter list missing", ticketvote.EligibilityVoterList, "", true},
+		{"unknown eligibility", "unknown", "", true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := newEligibility(v.eligibility, v.voterList, "", nil,
+				chaincfg.TestNet3Params())
+			if (err != nil) != v.wantErr {
+				t.Errorf("got err %v, want err %v", err, v.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseVoterList(t *testing.T) {
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := id.Public.String()
+
+	var tests = []struct {
+		name    string
+		list    string
+		wantErr bool
+	}{
+		{"valid", fmt.Sprintf("# id,pubkey,weight\nalice,%v,3\n", pk), false},
+		{"empty", "# id,pubkey,weight\n", true},
+		{"invalid public key", "alice,abc,1\n", true},
+		{"duplicate public key",
+			fmt.Sprintf("alice,%v,1\nbob,%v,1\n", pk, pk), true},
+		{"zero weight", fmt.Sprintf("alice,%v,0\n", pk), true},
+		{"weight too large", fmt.Sprintf("alice,%v,%v\n", pk,
+			voterListWeightMax+1), true},
+		{"missing field", fmt.Sprintf("alice,%v\n", pk), true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := parseVoterList([]byte(v.list))
+			if (err != nil) != v.wantErr {
+				t.Errorf("got err %v, want err %v", err, v.wantErr)
+			}
+		})
+	}
+}
+
+func TestVoterListEligibility(t *testing.T) {
+	dir := t.TempDir()
+	alice, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup the voter list
+	source := filepath.Join(dir, "voters.csv")
+	list := fmt.Sprintf("alice,%v,2\nbob,%v,1\n",
+		alice.Public.String(), bob.Public.String())
+	err = os.WriteFile(source, []byte(list), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataDir := filepath.Join(dir, "data")
+	err = os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := newVoterListEligibility(source, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets, err := e.EligibleTickets("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 3 {
+		t.Fatalf("got %v tickets, want 3", len(tickets))
+	}
+
+	// The voters must still be found once the voter list source has
+	// changed and the eligibility has been re-created.
+	err = os.Remove(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err = newVoterListEligibility(source, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown := voterTickets(hex.EncodeToString(make([]byte, 32)), 1)[0]
+	addrs, err := e.CommitmentAddrs(append(tickets, unknown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range tickets {
+		want := alice.Public.String()
+		if i == 2 {
+			want = bob.Public.String()
+		}
+		if addrs[v].err != nil || addrs[v].addr != want {
+			t.Errorf("ticket %v: got (%v, %v), want %v",
+				i, addrs[v].addr, addrs[v].err, want)
+		}
+	}
+	if addrs[unknown].err == nil {
+		t.Errorf("unknown ticket: got nil error")
+	}
+
+	// Verify a vote signature
+	msg := "token" + tickets[0] + "1"
+	sig := alice.SignMessage([]byte(msg))
+	err = e.VerifySignature(alice.Public.String(), msg,
+		hex.EncodeToString(sig[:]))
+	if err != nil {
+		t.Errorf("VerifySignature: %v", err)
+	}
+	err = e.VerifySignature(bob.Public.String(), msg,
+		hex.EncodeToString(sig[:]))
+	if err == nil {
+		t.Errorf("VerifySignature: got nil error for wrong voter")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020-2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/util"
+)
+
+var (
+	_ eligibility = (*dcrEligibility)(nil)
+)
+
+// dcrEligibility is the eligibility implementation for Decred tickets. The
+// eligible tickets of a vote are the live tickets at the snapshot block of
+// the vote and the votes must be signed by the largest commitment address of
+// each ticket. The ticket data is retrieved using the dcrdata plugin.
+//
+// dcrEligibility satisfies the eligibility interface.
+type dcrEligibility struct {
+	backend         backend.Backend
+	activeNetParams *chaincfg.Params
+}
+
+// newDcrEligibility returns a new dcrEligibility.
+func newDcrEligibility(backend backend.Backend, activeNetParams *chaincfg.Params) *dcrEligibility {
+	return &dcrEligibility{
+		backend:         backend,
+		activeNetParams: activeNetParams,
+	}
+}
+
+// Setup verifies that the dcrdata plugin has been registered.
+//
+// This function satisfies the eligibility interface.
+func (e *dcrEligibility) Setup() error {
+	return dcrdataRegistered(e.backend)
+}
+
+// EligibleTickets fetches the ticket pool at the provided block hash from the
+// dcrdata plugin and returns it.
+//
+// This function satisfies the eligibility interface.
+func (e *dcrEligibility) EligibleTickets(blockHash string) ([]string, error) {
+	tp := dcrdata.TicketPool{
+		BlockHash: blockHash,
+	}
+	payload, err := json.Marshal(tp)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := e.backend.PluginRead(nil, dcrdata.PluginID,
+		dcrdata.CmdTicketPool, string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("PluginRead %v %v: %v",
+			dcrdata.PluginID, dcrdata.CmdTicketPool, err)
+	}
+	var tpr dcrdata.TicketPoolReply
+	err = json.Unmarshal([]byte(reply), &tpr)
+	if err != nil {
+		return nil, err
+	}
+
+	return tpr.Tickets, nil
+}
+
+// CommitmentAddrs retrieves the largest commitment addresses for each of the
+// provided tickets from dcrdata. The stake of each ticket, i.e. the sum of its
+// commitment amounts, is returned along with the address. A
+// map[ticket]commitmentAddr is returned. If an error is encountered while
+// retrieving a commitment address, the error will be included in the
+// commitmentAddr struct in the returned map.
+//
+// This function satisfies the eligibility interface.
+func (e *dcrEligibility) CommitmentAddrs(tickets []string) (map[string]commitmentAddr, error) {
+	// Get tx details
+	tt := dcrdata.TxsTrimmed{
+		TxIDs: tickets,
+	}
+	payload, err := json.Marshal(tt)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := e.backend.PluginRead(nil, dcrdata.PluginID,
+		dcrdata.CmdTxsTrimmed, string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("PluginRead %v %v: %v",
+			dcrdata.PluginID, dcrdata.CmdTxsTrimmed, err)
+	}
+	var ttr dcrdata.TxsTrimmedReply
+	err = json.Unmarshal([]byte(reply), &ttr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the largest commitment address for each tx
+	addrs := make(map[string]commitmentAddr, len(ttr.Txs))
+	for _, tx := range ttr.Txs {
+		var (
+			bestAddr string  // Addr with largest commitment amount
+			bestAmt  float64 // Largest commitment amount
+			stake    uint64  // Sum of the commitment amounts
+			addrErr  error   // Error if one is encountered
+		)
+		for _, vout := range tx.Vout {
+			scriptPubKey := vout.ScriptPubKeyDecoded
+			if scriptPubKey.CommitAmt != nil {
+				amt, err := dcrutil.NewAmount(*scriptPubKey.CommitAmt)
+				if err != nil {
+					addrErr = fmt.Errorf("invalid commitment "+
+						"amount: %v", err)
+				}
+				stake += uint64(amt)
+			}
+			switch {
+			case scriptPubKey.CommitAmt == nil:
+				// No commitment amount; continue
+			case len(scriptPubKey.Addresses) == 0:
+				// No commitment address; continue
+			case *scriptPubKey.CommitAmt > bestAmt:
+				// New largest commitment address found
+				bestAddr = scriptPubKey.Addresses[0]
+				bestAmt = *scriptPubKey.CommitAmt
+			}
+		}
+		if bestAddr == "" || bestAmt == 0.0 {
+			addrErr = fmt.Errorf("no largest commitment address " +
+				"found")
+		}
+
+		// Store result
+		addrs[tx.TxID] = commitmentAddr{
+			addr:  bestAddr,
+			stake: stake,
+			err:   addrErr,
+		}
+	}
+
+	return addrs, nil
+}
+
+// VerifySignature verifies that the hex encoded signature is a valid dcr
+// signed message of the provided address.
+//
+// This function satisfies the eligibility interface.
+func (e *dcrEligibility) VerifySignature(addr, msg, signature string) error {
+	// Convert hex signature to base64. This is what the verify
+	// message function expects.
+	b, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid hex")
+	}
+	sig := base64.StdEncoding.EncodeToString(b)
+
+	// Verify message
+	validated, err := util.VerifyMessage(addr, msg, sig, e.activeNetParams)
+	if err != nil {
+		return err
+	}
+	if !validated {
+		return fmt.Errorf("could not verify message")
+	}
+
+	return nil
+}
+
+// StakeWeighted returns true. The ticket stakes are the sums of the ticket
+// commitment amounts.
+//
+// This function satisfies the eligibility interface.
+func (e *dcrEligibility) StakeWeighted() bool {
+	return true
+}
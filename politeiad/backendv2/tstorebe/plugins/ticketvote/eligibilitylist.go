@@ -0,0 +1,275 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// voterListWeightMax is the maximum voting weight of a single voter
+	// in a voter list. A voter is given one eligible ticket for each
+	// unit of voting weight.
+	voterListWeightMax = 100000
+
+	// voterListFilePrefix is the filename prefix of the voter lists that
+	// are saved to the plugin data dir.
+	voterListFilePrefix = "voterlist-"
+)
+
+var (
+	_ eligibility = (*voterListEligibility)(nil)
+)
+
+// voterListEligibility is the eligibility implementation for externally
+// supplied voter lists. See the ticketvote plugin EligibilityVoterList
+// documentation for the voter list format.
+//
+// The voter list is retrieved from the voter list source each time a vote is
+// started, allowing the list to be updated between votes. Every voter list
+// that is used by a vote is saved to the plugin data dir so that the voters
+// of a vote can still be looked up if the voter list source changes or the
+// server is restarted.
+//
+// voterListEligibility satisfies the eligibility interface.
+type voterListEligibility struct {
+	source  string // File path or http(s) URL
+	dataDir string
+	client  *http.Client
+
+	sync.Mutex
+	addrs map[string]string // [ticket]publicKey
+}
+
+// voter represents a single entry in a voter list.
+type voter struct {
+	id        string
+	publicKey string // Hex encoded ed25519 public key
+	weight    uint32
+}
+
+// newVoterListEligibility returns a new voterListEligibility.
+func newVoterListEligibility(source, dataDir string) (*voterListEligibility, error) {
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
+		Retries: 3,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &voterListEligibility{
+		source:  source,
+		dataDir: dataDir,
+		client:  client,
+		addrs:   make(map[string]string),
+	}, nil
+}
+
+// Setup loads the voter lists that have been saved to the plugin data dir.
+//
+// This function satisfies the eligibility interface.
+func (e *voterListEligibility) Setup() error {
+	files, err := filepath.Glob(filepath.Join(e.dataDir,
+		voterListFilePrefix+"*.csv"))
+	if err != nil {
+		return err
+	}
+	for _, fp := range files {
+		b, err := os.ReadFile(fp)
+		if err != nil {
+			return err
+		}
+		voters, err := parseVoterList(b)
+		if err != nil {
+			return fmt.Errorf("%v: %v", fp, err)
+		}
+		e.addVoters(voters)
+	}
+
+	log.Infof("Voter list eligibility: %v saved voter lists loaded",
+		len(files))
+
+	return nil
+}
+
+// EligibleTickets retrieves the voter list from the voter list source and
+// returns the tickets of the voters. The block hash is not used. The voter
+// list that is retrieved when a vote is started is the snapshot of the vote.
+//
+// This function satisfies the eligibility interface.
+func (e *voterListEligibility) EligibleTickets(blockHash string) ([]string, error) {
+	b, err := e.fetch()
+	if err != nil {
+		return nil, err
+	}
+	voters, err := parseVoterList(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the voter list so that the voters can be looked up for the
+	// duration of the vote.
+	fp := filepath.Join(e.dataDir, voterListFilePrefix+
+		hex.EncodeToString(util.Digest(b))+".csv")
+	if _, err := os.Stat(fp); errors.Is(err, os.ErrNotExist) {
+		err = os.WriteFile(fp, b, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+	e.addVoters(voters)
+
+	tickets := make([]string, 0, len(voters))
+	for _, v := range voters {
+		tickets = append(tickets, voterTickets(v.publicKey, v.weight)...)
+	}
+
+	return tickets, nil
+}
+
+// CommitmentAddrs returns the voter public key of each of the provided
+// tickets.
+//
+// This function satisfies the eligibility interface.
+func (e *voterListEligibility) CommitmentAddrs(tickets []string) (map[string]commitmentAddr, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	addrs := make(map[string]commitmentAddr, len(tickets))
+	for _, v := range tickets {
+		var ca commitmentAddr
+		pk, ok := e.addrs[v]
+		if ok {
+			ca.addr = pk
+		} else {
+			ca.err = fmt.Errorf("voter not found")
+		}
+		addrs[v] = ca
+	}
+
+	return addrs, nil
+}
+
+// VerifySignature verifies that the hex encoded signature is a valid ed25519
+// signature of the message for the provided public key.
+//
+// This function satisfies the eligibility interface.
+func (e *voterListEligibility) VerifySignature(addr, msg, signature string) error {
+	return util.VerifySignature(signature, addr, msg)
+}
+
+// StakeWeighted returns false. Voter lists assign voting weight by giving a
+// voter multiple tickets and do not have a stake.
+//
+// This function satisfies the eligibility interface.
+func (e *voterListEligibility) StakeWeighted() bool {
+	return false
+}
+
+// addVoters adds the tickets of the provided voters to the ticket lookup
+// table.
+func (e *voterListEligibility) addVoters(voters []voter) {
+	e.Lock()
+	defer e.Unlock()
+
+	for _, v := range voters {
+		for _, t := range voterTickets(v.publicKey, v.weight) {
+			e.addrs[t] = v.publicKey
+		}
+	}
+}
+
+// fetch retrieves the raw voter list from the voter list source.
+func (e *voterListEligibility) fetch() ([]byte, error) {
+	if !strings.HasPrefix(e.source, "http://") &&
+		!strings.HasPrefix(e.source, "https://") {
+		return os.ReadFile(e.source)
+	}
+
+	r, err := e.client.Get(e.source)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get voter list: %v", r.Status)
+	}
+
+	return io.ReadAll(r.Body)
+}
+
+// parseVoterList parses a CSV encoded voter list.
+func parseVoterList(b []byte) ([]voter, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comment = '#'
+	r.FieldsPerRecord = 3
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("voter list is empty")
+	}
+
+	var (
+		voters = make([]voter, 0, len(records))
+		keys   = make(map[string]struct{}, len(records))
+	)
+	for i, v := range records {
+		var (
+			id        = v[0]
+			publicKey = v[1]
+			line      = i + 1
+		)
+		if _, err := identity.PublicIdentityFromString(publicKey); err != nil {
+			return nil, fmt.Errorf("voter %v: invalid public key: %v",
+				line, err)
+		}
+		if _, ok := keys[publicKey]; ok {
+			return nil, fmt.Errorf("voter %v: duplicate public key", line)
+		}
+		weight, err := strconv.ParseUint(v[2], 10, 32)
+		if err != nil || weight == 0 || weight > voterListWeightMax {
+			return nil, fmt.Errorf("voter %v: invalid weight '%v'",
+				line, v[2])
+		}
+		keys[publicKey] = struct{}{}
+		voters = append(voters, voter{
+			id:        id,
+			publicKey: publicKey,
+			weight:    uint32(weight),
+		})
+	}
+
+	return voters, nil
+}
+
+// voterTickets returns the eligible tickets of a voter. A voter is given one
+// ticket for each unit of voting weight. The ticket hashes are the SHA256
+// digests of the public key concatenated with the base 10 ticket index.
+func voterTickets(publicKey string, weight uint32) []string {
+	tickets := make([]string, 0, weight)
+	for i := uint32(0); i < weight; i++ {
+		msg := publicKey + strconv.FormatUint(uint64(i), 10)
+		tickets = append(tickets, hex.EncodeToString(util.Digest([]byte(msg))))
+	}
+	return tickets
+}
@@ -14,7 +14,7 @@ import (
 //
 //  1. Rebuild the vote summaries cache. Records that have finished voting
 //     will have a vote summary saved to the cache. This cache is rebuilt
-//     from scratch.
+//     from the final vote summaries that have been persisted to tstore.
 //
 //  2. Rebuild the vote inventory cache. All vetted records are included in the
 //     vote inventory cache. This cache is rebuilt from scratch.
@@ -22,6 +22,9 @@ import (
 //  3. Rebuild the runoff vote submissions cache. The submissions cache contains
 //     the list of runoff vote parent records and all of their runoff vote
 //     submissions. This cache is built from scratch.
+//
+//  4. Rebuild the governance export. The export contains the final vote
+//     summaries of all finished votes. The export is rebuilt from scratch.
 func (p *ticketVotePlugin) fsck(tokens [][]byte) error {
 	log.Infof("Starting ticketvote fsck for %v records", len(tokens))
 
@@ -43,15 +46,16 @@ func (p *ticketVotePlugin) fsck(tokens [][]byte) error {
 	// 1. Rebuild the vote summaries cache.
 	//
 	// Records that have finished voting will have a vote summary saved
-	// to the cache. The summary() function builds the vote summary from
-	// scratch for a record and saves it to the cache when appropriate.
-	// The only thing we need to do to rebuild the vote summaries cache
-	// is to delete existing entries and then invoke the summary()
-	// function on all vetted records.
+	// to the cache. The summary() function returns the final vote
+	// summary that was persisted to tstore when one exists and builds
+	// the vote summary from scratch otherwise, saving it to the cache
+	// when appropriate. The only thing we need to do to rebuild the
+	// vote summaries cache is to delete existing entries and then
+	// invoke the summary() function on all vetted records.
 
 	log.Infof("Building the vote summaries cache")
 
-	bestBlock, err := p.bestBlock()
+	bestBlock, err := p.chain.BestBlock()
 	if err != nil {
 		return err
 	}
@@ -87,7 +91,8 @@ func (p *ticketVotePlugin) fsck(tokens [][]byte) error {
 
 	entries := make([]invEntry, 0, len(summaries))
 	for token, v := range summaries {
-		e := newInvEntry(token, v.Status, v.Timestamp, v.EndBlockHeight)
+		e := newInvEntry(token, v.Status, v.Timestamp, v.StartBlockHeight,
+			v.EndBlockHeight)
 		entries = append(entries, *e)
 	}
 	p.inv.Rebuild(entries)
@@ -108,6 +113,28 @@ func (p *ticketVotePlugin) fsck(tokens [][]byte) error {
 
 	log.Info("Runoff vote submissions cache complete")
 
+	// 4. Rebuild the governance export.
+	//
+	// The governance export contains the final vote summaries of all
+	// finished votes. The export is rebuilt from scratch using the
+	// vote summaries that were built above.
+	log.Infof("Building the governance export")
+
+	err = p.govExport.Reset()
+	if err != nil {
+		return err
+	}
+	finished := make(map[string]ticketvote.SummaryReply, len(summaries))
+	for token, v := range summaries {
+		finished[token] = *v
+	}
+	err = p.govExportAdd(finished)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Governance export complete")
+
 	return nil
 }
 
@@ -0,0 +1,394 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+const (
+	// govExportDirname is the name of the directory in the plugin data
+	// directory that the governance export is saved to.
+	govExportDirname = "governanceexport"
+
+	// govExportDataFilename and govExportManifestFilename are the
+	// filenames of the governance export data and manifest.
+	govExportDataFilename     = "votes.ndjson"
+	govExportManifestFilename = "manifest.json"
+)
+
+// govExport manages the governance export of the finished votes. The export
+// is saved to the plugin data directory as an NDJSON data file, containing
+// one GovernanceExportVote per line, and a manifest that describes the data
+// file. Votes are appended to the data file as they finish so that the
+// export never needs to be regenerated from scratch during normal operation.
+// The export can be rebuilt from the vote summaries using fsck.
+type govExport struct {
+	sync.Mutex
+	dir    string
+	tokens map[string]struct{} // Tokens of the exported votes
+}
+
+// newGovExport returns a new govExport that saves the export to the provided
+// plugin data directory. The tokens of the votes that have already been
+// exported are loaded from disk.
+func newGovExport(dataDir string) (*govExport, error) {
+	dir := filepath.Join(dataDir, govExportDirname)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	e := &govExport{
+		dir: dir,
+	}
+	votes, err := e.votes()
+	if err != nil {
+		return nil, err
+	}
+	e.tokens = make(map[string]struct{}, len(votes))
+	for _, v := range votes {
+		e.tokens[v.Token] = struct{}{}
+	}
+	return e, nil
+}
+
+// Has returns whether the vote of the provided record has been exported.
+func (e *govExport) Has(token string) bool {
+	e.Lock()
+	defer e.Unlock()
+
+	_, ok := e.tokens[token]
+	return ok
+}
+
+// Add appends the provided votes to the export. Votes that have already been
+// exported are ignored.
+func (e *govExport) Add(votes []ticketvote.GovernanceExportVote) error {
+	e.Lock()
+	defer e.Unlock()
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	added := make([]string, 0, len(votes))
+	for _, v := range votes {
+		if _, ok := e.tokens[v.Token]; ok {
+			continue
+		}
+		err := enc.Encode(v)
+		if err != nil {
+			return err
+		}
+		added = append(added, v.Token)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	// Append the votes to the data file
+	f, err := os.OpenFile(e.dataPath(),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b.Bytes())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Sync()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	for _, v := range added {
+		e.tokens[v] = struct{}{}
+	}
+
+	log.Debugf("Governance export: added %v votes", len(added))
+
+	return e.manifestUpdate()
+}
+
+// Reset deletes the export.
+func (e *govExport) Reset() error {
+	e.Lock()
+	defer e.Unlock()
+
+	for _, fp := range []string{e.dataPath(), e.manifestPath()} {
+		err := os.Remove(fp)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	e.tokens = make(map[string]struct{})
+
+	return nil
+}
+
+// Get returns the export manifest and the export data starting at the
+// provided offset. The offset is the number of votes to skip.
+func (e *govExport) Get(offset uint32) (*ticketvote.GovernanceExportManifest, []byte, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	m, err := e.manifest()
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := os.ReadFile(e.dataPath())
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return m, []byte{}, nil
+	case err != nil:
+		return nil, nil, err
+	}
+	for i := uint32(0); i < offset && len(b) > 0; i++ {
+		n := bytes.IndexByte(b, '\n')
+		if n < 0 {
+			b = []byte{}
+			break
+		}
+		b = b[n+1:]
+	}
+
+	return m, b, nil
+}
+
+// dataPath returns the file path of the export data.
+func (e *govExport) dataPath() string {
+	return filepath.Join(e.dir, govExportDataFilename)
+}
+
+// manifestPath returns the file path of the export manifest.
+func (e *govExport) manifestPath() string {
+	return filepath.Join(e.dir, govExportManifestFilename)
+}
+
+// votes returns all votes in the export data file.
+func (e *govExport) votes() ([]ticketvote.GovernanceExportVote, error) {
+	f, err := os.Open(e.dataPath())
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return []ticketvote.GovernanceExportVote{}, nil
+	case err != nil:
+		return nil, err
+	}
+	defer f.Close()
+
+	votes := make([]ticketvote.GovernanceExportVote, 0, 256)
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		var v ticketvote.GovernanceExportVote
+		err := json.Unmarshal(s.Bytes(), &v)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", e.dataPath(), err)
+		}
+		votes = append(votes, v)
+	}
+	err = s.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// manifest returns the export manifest. An empty manifest is returned if the
+// export has not been created yet.
+//
+// This function must be called with the lock held.
+func (e *govExport) manifest() (*ticketvote.GovernanceExportManifest, error) {
+	b, err := os.ReadFile(e.manifestPath())
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return &ticketvote.GovernanceExportManifest{
+			SchemaVersion: ticketvote.GovernanceExportSchemaVersion,
+			Format:        ticketvote.GovernanceExportFormat,
+			SHA256:        hex.EncodeToString(sha256.New().Sum(nil)),
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+	var m ticketvote.GovernanceExportManifest
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", e.manifestPath(), err)
+	}
+	return &m, nil
+}
+
+// manifestUpdate updates the export manifest using the current contents of
+// the data file. The manifest is written to a temp file that is then renamed
+// so that a partially written manifest is never read.
+//
+// This function must be called with the lock held.
+func (e *govExport) manifestUpdate() error {
+	b, err := os.ReadFile(e.dataPath())
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(b)
+	m := ticketvote.GovernanceExportManifest{
+		SchemaVersion: ticketvote.GovernanceExportSchemaVersion,
+		Format:        ticketvote.GovernanceExportFormat,
+		Count:         uint32(len(e.tokens)),
+		Size:          int64(len(b)),
+		SHA256:        hex.EncodeToString(digest[:]),
+		Updated:       time.Now().Unix(),
+	}
+	mb, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	tmp := e.manifestPath() + ".tmp"
+	err = os.WriteFile(tmp, mb, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.manifestPath())
+}
+
+// govExportAdd adds the provided final vote summaries to the governance
+// export. The summaries are exported in the order of their end block height.
+// Summaries of votes that have not finished are ignored.
+func (p *ticketVotePlugin) govExportAdd(summaries map[string]ticketvote.SummaryReply) error {
+	votes := make([]ticketvote.GovernanceExportVote, 0, len(summaries))
+	for token, s := range summaries {
+		switch s.Status {
+		case ticketvote.VoteStatusFinished, ticketvote.VoteStatusApproved,
+			ticketvote.VoteStatusRejected:
+			votes = append(votes, convertSummaryToGovExport(token, s))
+		}
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if votes[i].EndBlockHeight != votes[j].EndBlockHeight {
+			return votes[i].EndBlockHeight < votes[j].EndBlockHeight
+		}
+		return votes[i].Token < votes[j].Token
+	})
+	return p.govExport.Add(votes)
+}
+
+// govExportSync adds the finished votes of the inventory that are missing
+// from the governance export. A vote can be missing from the export if
+// politeiad was stopped after the vote was finalized but before it was
+// exported.
+func (p *ticketVotePlugin) govExportSync() error {
+	entries, err := p.inv.Finished()
+	if err != nil {
+		return err
+	}
+	summaries := make(map[string]ticketvote.SummaryReply, 16)
+	for _, v := range entries {
+		if p.govExport.Has(v.Token) {
+			continue
+		}
+		tokenB, err := tokenDecode(v.Token)
+		if err != nil {
+			return err
+		}
+		s, err := p.summary(tokenB, 0)
+		if err != nil {
+			return err
+		}
+		summaries[v.Token] = *s
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	log.Infof("Adding %v missing votes to the governance export",
+		len(summaries))
+
+	return p.govExportAdd(summaries)
+}
+
+// cmdGovernanceExport returns the governance export of all finished votes.
+func (p *ticketVotePlugin) cmdGovernanceExport(payload string) (string, error) {
+	// Decode payload
+	var ge ticketvote.GovernanceExport
+	err := json.Unmarshal([]byte(payload), &ge)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the export
+	m, data, err := p.govExport.Get(ge.Offset)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(ticketvote.GovernanceExportReply{
+		Manifest: *m,
+		Data:     string(data),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// convertSummaryToGovExport converts a final vote summary into a governance
+// export vote.
+func convertSummaryToGovExport(token string, s ticketvote.SummaryReply) ticketvote.GovernanceExportVote {
+	var (
+		total   uint64
+		results = make([]ticketvote.GovernanceExportResult, 0, len(s.Results))
+	)
+	for _, v := range s.Results {
+		total += v.Votes
+		results = append(results, ticketvote.GovernanceExportResult{
+			ID:          v.ID,
+			Description: v.Description,
+			VoteBit:     v.VoteBit,
+			Votes:       v.Votes,
+			Stake:       v.Stake,
+		})
+	}
+	var winCondition string
+	if s.WinCondition != ticketvote.WinConditionInvalid {
+		winCondition = ticketvote.WinConditions[s.WinCondition]
+	}
+	return ticketvote.GovernanceExportVote{
+		Token:            token,
+		Type:             ticketvote.VoteTypes[s.Type],
+		Status:           ticketvote.VoteStatuses[s.Status],
+		Duration:         s.Duration,
+		StartBlockHeight: s.StartBlockHeight,
+		StartBlockHash:   s.StartBlockHash,
+		EndBlockHeight:   s.EndBlockHeight,
+		EligibleTickets:  s.EligibleTickets,
+		QuorumPercentage: s.QuorumPercentage,
+		PassPercentage:   s.PassPercentage,
+		TotalVotes:       total,
+		Results:          results,
+		WinCondition:     winCondition,
+		WinningOption:    s.WinningOption,
+		Winners:          s.Winners,
+		Weight:           ticketvote.VoteWeights[s.Weight],
+		EligibleStake:    s.EligibleStake,
+	}
+}
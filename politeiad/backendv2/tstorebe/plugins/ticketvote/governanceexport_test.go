@@ -0,0 +1,122 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestGovExport(t *testing.T) {
+	dataDir := t.TempDir()
+	e, err := newGovExport(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty export has an empty manifest
+	m, data, err := e.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Count != 0 || len(data) != 0 ||
+		m.SchemaVersion != ticketvote.GovernanceExportSchemaVersion {
+		t.Fatalf("got manifest %+v data %q, want empty export", m, data)
+	}
+
+	// Add votes. Duplicate votes are ignored.
+	votes := []ticketvote.GovernanceExportVote{
+		{Token: "a", EndBlockHeight: 10},
+		{Token: "b", EndBlockHeight: 20},
+	}
+	err = e.Add(votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = e.Add([]ticketvote.GovernanceExportVote{
+		{Token: "b", EndBlockHeight: 20},
+		{Token: "c", EndBlockHeight: 30},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the manifest describes the full export
+	m, data, err = e.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(data)
+	if m.Count != 3 || m.Size != int64(len(data)) ||
+		m.SHA256 != hex.EncodeToString(digest[:]) {
+		t.Fatalf("got manifest %+v, want 3 votes of %v bytes", m, len(data))
+	}
+	if got := exportTokens(t, data); !reflect.DeepEqual(got,
+		[]string{"a", "b", "c"}) {
+		t.Fatalf("got tokens %v, want a b c", got)
+	}
+
+	// Verify the offset skips the votes that were already downloaded
+	_, data, err = e.Get(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := exportTokens(t, data); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Fatalf("got tokens %v, want c", got)
+	}
+	_, data, err = e.Get(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("got data %q, want none", data)
+	}
+
+	// The exported votes are loaded on startup
+	e, err = newGovExport(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.Has("a") || e.Has("d") {
+		t.Fatalf("exported tokens were not loaded")
+	}
+
+	// Reset deletes the export
+	err = e.Reset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, data, err = e.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Count != 0 || len(data) != 0 || e.Has("a") {
+		t.Fatalf("got manifest %+v data %q, want empty export", m, data)
+	}
+}
+
+// exportTokens returns the tokens of the votes in the provided export data.
+func exportTokens(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	tokens := make([]string, 0, 16)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		var v ticketvote.GovernanceExportVote
+		err := json.Unmarshal(s.Bytes(), &v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, v.Token)
+	}
+	return tokens
+}
@@ -91,6 +91,37 @@ func (p *ticketVotePlugin) hookSetRecordStatusPost(payload string) error {
 		recordMD.State, recordMD.Status, srs.Record.Files)
 }
 
+// hookPluginEvent handles the plugin events that are emitted by other
+// plugins.
+func (p *ticketVotePlugin) hookPluginEvent(payload string) error {
+	var e plugins.HookPluginEvent
+	err := json.Unmarshal([]byte(payload), &e)
+	if err != nil {
+		return err
+	}
+
+	height, ok, err := p.chain.NewBlock(e)
+	switch {
+	case err != nil:
+		return err
+	case ok:
+		return p.newBlock(height)
+	}
+
+	return nil
+}
+
+// newBlock finishes the votes that have ended by the provided block height.
+// The votes are finished by updating the cached inventory, which saves the
+// final vote summaries and removes the votes from the active votes cache.
+// Votes are also finished lazily when the summary or inventory is requested,
+// but doing it on each new block ensures that votes are finished on time.
+func (p *ticketVotePlugin) newBlock(height uint32) error {
+	log.Debugf("New block %v", height)
+
+	return p.inv.UpdateBlockHeight(height)
+}
+
 // linkByVerify verifies that the provided link by timestamp meets all
 // ticketvote plugin requirements. See the ticketvote VoteMetadata structure
 // for more details on the link by timestamp.
@@ -17,6 +17,12 @@ const (
 	// internal plugin commands as a workaround.
 	cmdStartRunoffSubmission = "startrunoffsub"
 	cmdRunoffDetails         = "runoffdetails"
+	cmdRollbackRunoffSub     = "rollbackrunoffsub"
+
+	// cmdSummarySave persists the final vote summary of a record. It
+	// is an internal plugin command so that the summary is saved while
+	// the backend holds the record lock.
+	cmdSummarySave = "summarysave"
 )
 
 // startRunoffRecord is the record that is saved to the runoff vote's parent
@@ -49,6 +55,16 @@ type startRunoffSubmission struct {
 // command.
 type startRunoffSubmissionReply struct{}
 
+// rollbackRunoffSubmission is an internal plugin command that is used to
+// roll back the start of the voting period on a runoff vote submission.
+type rollbackRunoffSubmission struct {
+	ParentToken string `json:"parenttoken"`
+}
+
+// rollbackRunoffSubmissionReply is the reply to the rollbackRunoffSubmission
+// command.
+type rollbackRunoffSubmissionReply struct{}
+
 // runoffDetails is an internal plugin command that requests the details of a
 // runoff vote.
 type runoffDetails struct{}
@@ -57,3 +73,12 @@ type runoffDetails struct{}
 type runoffDetailsReply struct {
 	Runoff startRunoffRecord `json:"runoff"`
 }
+
+// summarySave is an internal plugin command that is used to persist the final
+// vote summary of a record.
+type summarySave struct {
+	Summary ticketvote.SummaryReply `json:"summary"`
+}
+
+// summarySaveReply is the reply to the summarySave command.
+type summarySaveReply struct{}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
@@ -155,6 +156,28 @@ func (i *inv) GetPage(status ticketvote.VoteStatusT, pageNumber, pageSize uint32
 	return entries[startIdx:endIdx]
 }
 
+// StartedAfter returns a copy of the inventory that only contains the entries
+// whose vote was started after the provided block height. Entries that have
+// not started voting are not included. The full inventory is returned if the
+// provided block height is 0.
+func (i *inv) StartedAfter(blockHeight uint32) *inv {
+	if blockHeight == 0 {
+		return i
+	}
+	filtered := newInv()
+	filtered.BlockHeight = i.BlockHeight
+	for status, entries := range i.Entries {
+		f := make([]invEntry, 0, len(entries))
+		for _, v := range entries {
+			if v.StartBlockHeight > blockHeight {
+				f = append(f, v)
+			}
+		}
+		filtered.Entries[status] = f
+	}
+	return filtered
+}
+
 // invEntry is an entry in the ticketvote inventory.
 type invEntry struct {
 	Token  string                 `json:"token"`
@@ -167,20 +190,43 @@ type invEntry struct {
 	// ordering.
 	Timestamp int64 `json:"timestamp,omitempty"`
 
+	// StartBlockHeight is the start block height of the vote. This is
+	// used to filter the inventory entries of records that are being
+	// voted on or have already been voted on. This field will be set
+	// to 0 if the vote has not begun yet.
+	//
+	// Inventory caches that were built before this field was added
+	// will not have it populated. It is backfilled from the vote
+	// details during plugin setup.
+	StartBlockHeight uint32 `json:"startblockheight,omitempty"`
+
 	// EndBlockHeight is the end block height of the vote. This is used
 	// to order the inventory entries of records that are being voted
 	// on or have already been voted on. This field will be set to 0 if
 	// the vote has not begun yet.
 	EndBlockHeight uint32 `json:"endblockheight,omitempty"`
+
+	// StatusTimestamp is the unix timestamp of when the entry entered
+	// its current vote status. Unlike Timestamp, this field is kept
+	// once the vote has begun. The timestamp of a vote that has ended
+	// is the time that the inventory was updated with the block that
+	// ended the vote.
+	//
+	// Inventory caches that were built before this field was added
+	// will not have it populated for the entries that have begun
+	// voting. The field is populated once the entry changes status.
+	StatusTimestamp int64 `json:"statustimestamp,omitempty"`
 }
 
 // newInvEntry returns a new invEntry.
-func newInvEntry(token string, status ticketvote.VoteStatusT, timestamp int64, endBlockHeight uint32) *invEntry {
+func newInvEntry(token string, status ticketvote.VoteStatusT, timestamp int64, startBlockHeight, endBlockHeight uint32) *invEntry {
 	return &invEntry{
-		Token:          token,
-		Status:         status,
-		Timestamp:      timestamp,
-		EndBlockHeight: endBlockHeight,
+		Token:            token,
+		Status:           status,
+		Timestamp:        timestamp,
+		StartBlockHeight: startBlockHeight,
+		EndBlockHeight:   endBlockHeight,
+		StatusTimestamp:  timestamp,
 	}
 }
 
@@ -253,7 +299,7 @@ func (c *invClient) UpdateEntryPreVote(token string, status ticketvote.VoteStatu
 	c.Lock()
 	defer c.Unlock()
 
-	err := c.updateEntry(token, status, timestamp, 0)
+	err := c.updateEntry(token, status, timestamp, 0, 0)
 	if err != nil {
 		e := fmt.Sprintf("%v %v %v: %v", token, status, timestamp, err)
 		panic(e)
@@ -262,7 +308,8 @@ func (c *invClient) UpdateEntryPreVote(token string, status ticketvote.VoteStatu
 
 // UpdateEntryPostVote updates an entry in the inventory whose voting period
 // has been started or has already finished. The inventory entries that fall
-// into this category are ordered by the endBlockHeight of the voting period.
+// into this category are ordered by the endBlockHeight of the voting period
+// and can be filtered by the startBlockHeight of the voting period.
 //
 // Plugin writes are not currently executed using a sql transaction, which
 // means that there is no way to unwind previous writes if this cache update
@@ -270,25 +317,56 @@ func (c *invClient) UpdateEntryPreVote(token string, status ticketvote.VoteStatu
 // sysadmin is alerted that the cache is incoherent and needs to be rebuilt.
 //
 // This function is concurrency safe.
-func (c *invClient) UpdateEntryPostVote(token string, status ticketvote.VoteStatusT, endBlockHeight uint32) {
+func (c *invClient) UpdateEntryPostVote(token string, status ticketvote.VoteStatusT, startBlockHeight, endBlockHeight uint32) {
 	c.Lock()
 	defer c.Unlock()
 
-	err := c.updateEntry(token, status, 0, endBlockHeight)
+	err := c.updateEntry(token, status, 0, startBlockHeight, endBlockHeight)
 	if err != nil {
 		e := fmt.Sprintf("%v %v %v: %v", token, status, endBlockHeight, err)
 		panic(e)
 	}
 }
 
-// Page returns a page of inventory results for all vote statuses.
+// UpdateEntryFinishedEarly updates the inventory entry of a vote that was
+// finished prior to its end block height to the final vote status. Nothing is
+// updated if the entry is no longer in the started list, e.g. it was already
+// moved by an inventory block height update.
+//
+// A failed update panics for the same reasons as UpdateEntryPostVote.
+//
+// This function is concurrency safe.
+func (c *invClient) UpdateEntryFinishedEarly(token string, status ticketvote.VoteStatusT, startBlockHeight, endBlockHeight uint32) {
+	c.Lock()
+	defer c.Unlock()
+
+	inv, err := c.getInv()
+	if err != nil {
+		panic(fmt.Sprintf("%v %v: %v", token, status, err))
+	}
+	if !entriesIncludeToken(inv.Entries[ticketvote.VoteStatusStarted], token) {
+		return
+	}
+	err = c.updateEntry(token, status, 0, startBlockHeight, endBlockHeight)
+	if err != nil {
+		e := fmt.Sprintf("%v %v %v: %v", token, status, endBlockHeight, err)
+		panic(e)
+	}
+}
+
+// GetPage returns a page of inventory results for all vote statuses.
 //
 // The best block is required to ensure that the returned results are
 // up-to-date. Certain inventory statuses, such as VoteStatusFinished, are
 // updated based on the vote's ending block height and the best block.
 //
+// Page 1 corresponds to the most recent page of inventory entries. The page
+// size is capped at the inventory page size. A page size of 0 defaults to
+// the inventory page size. If a startedAfter block height is provided, only
+// the entries whose vote was started after the block height are returned.
+//
 // This function is concurrency safe.
-func (c *invClient) GetPage(bestBlock uint32) (*inv, error) {
+func (c *invClient) GetPage(bestBlock, pageNumber, pageSize, startedAfter uint32) (*inv, error) {
 	c.Lock()
 	defer c.Unlock()
 
@@ -296,21 +374,24 @@ func (c *invClient) GetPage(bestBlock uint32) (*inv, error) {
 	if err != nil {
 		return nil, err
 	}
+	filtered := fullInv.StartedAfter(startedAfter)
+	pageSize = c.pageSizeCapped(pageSize)
 	invPage := newInv()
-	for status := range fullInv.Entries {
-		invPage.Entries[status] = fullInv.GetPage(status, 1, c.pageSize)
+	for status := range filtered.Entries {
+		invPage.Entries[status] = filtered.GetPage(status, pageNumber, pageSize)
 	}
 
 	return invPage, nil
 }
 
-// PageForStatus returns a page of inventory results for the provided vote
+// GetPageForStatus returns a page of inventory results for the provided vote
 // status.
 //
-// Page 1 corresponds to the most recent page of inventory entries.
+// Page 1 corresponds to the most recent page of inventory entries. The page
+// size and startedAfter arguments are handled the same way as in GetPage.
 //
 // This function is concurrency safe.
-func (c *invClient) GetPageForStatus(bestBlock uint32, status ticketvote.VoteStatusT, pageNumber uint32) ([]invEntry, error) {
+func (c *invClient) GetPageForStatus(bestBlock uint32, status ticketvote.VoteStatusT, pageNumber, pageSize, startedAfter uint32) ([]invEntry, error) {
 	c.Lock()
 	defer c.Unlock()
 
@@ -318,20 +399,168 @@ func (c *invClient) GetPageForStatus(bestBlock uint32, status ticketvote.VoteSta
 	if err != nil {
 		return nil, err
 	}
+	filtered := fullInv.StartedAfter(startedAfter)
+	pageSize = c.pageSizeCapped(pageSize)
+
+	return filtered.GetPage(status, pageNumber, pageSize), nil
+}
+
+// UpdateBlockHeight updates the inventory to the provided block height. The
+// entries whose vote has ended by the block height are moved to their final
+// vote status. The inventory is not updated if it has already been updated to
+// a later block height.
+//
+// This function is concurrency safe.
+func (c *invClient) UpdateBlockHeight(blockHeight uint32) error {
+	c.Lock()
+	defer c.Unlock()
+
+	inv, err := c.getInv()
+	if err != nil {
+		return err
+	}
+	if inv.BlockHeight >= blockHeight {
+		return nil
+	}
+
+	_, err = c.updateBlockHeight(blockHeight)
+	return err
+}
+
+// StartedCount returns the number of votes that have been started and have not
+// finished as of the provided best block.
+//
+// This function is concurrency safe.
+func (c *invClient) StartedCount(bestBlock uint32) (uint32, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	inv, err := c.updateBlockHeight(bestBlock)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(len(inv.Entries[ticketvote.VoteStatusStarted])), nil
+}
+
+// Finished returns the inventory entries of the votes that have finished,
+// sorted by end block height from oldest to newest.
+//
+// This function is concurrency safe.
+func (c *invClient) Finished() ([]invEntry, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	inv, err := c.getInv()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]invEntry, 0, 256)
+	for _, s := range []ticketvote.VoteStatusT{
+		ticketvote.VoteStatusFinished,
+		ticketvote.VoteStatusApproved,
+		ticketvote.VoteStatusRejected,
+	} {
+		entries = append(entries, inv.Entries[s]...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EndBlockHeight < entries[j].EndBlockHeight
+	})
+
+	return entries, nil
+}
+
+// StartHeightsBackfill populates the start block height of the inventory
+// entries that have begun voting, but that do not have a start block height.
+// These are entries that were added to the inventory before the start block
+// height was added to the inventory entries. The provided function is used to
+// look up the start block height of an entry. Entries whose start block height
+// cannot be determined are logged and skipped. The number of entries that were
+// updated is returned.
+//
+// This function is concurrency safe.
+func (c *invClient) StartHeightsBackfill(startHeight func(token string) (uint32, error)) (int, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	inv, err := c.getInv()
+	if err != nil {
+		return 0, err
+	}
+	var updated int
+	for _, s := range []ticketvote.VoteStatusT{
+		ticketvote.VoteStatusStarted,
+		ticketvote.VoteStatusFinished,
+		ticketvote.VoteStatusApproved,
+		ticketvote.VoteStatusRejected,
+	} {
+		entries := inv.Entries[s]
+		for i, v := range entries {
+			if v.StartBlockHeight != 0 {
+				continue
+			}
+			h, err := startHeight(v.Token)
+			if err != nil {
+				// Don't prevent the plugin from starting up. The
+				// entry can be fixed using the politeiad reindex
+				// option.
+				log.Errorf("Vote inv start height %v: %v", v.Token, err)
+				continue
+			}
+			entries[i].StartBlockHeight = h
+			updated++
+		}
+	}
+	if updated == 0 {
+		return 0, nil
+	}
+
+	err = c.saveInv(*inv)
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
 
-	return fullInv.GetPage(status, pageNumber, c.pageSize), nil
+// pageSizeCapped returns the provided page size capped at the inventory page
+// size. A page size of 0 returns the inventory page size.
+func (c *invClient) pageSizeCapped(pageSize uint32) uint32 {
+	if pageSize == 0 || pageSize > c.pageSize {
+		return c.pageSize
+	}
+	return pageSize
 }
 
 // Rebuild rebuilds the inventory using the provided inventory entries and
 // saves it to the tstore plugin cache.
 //
+// The status timestamps of the entries that have begun voting can not be
+// derived from the vote summaries. They are carried over from the existing
+// inventory when the vote status of the entry has not changed.
+//
 // This function is concurrency safe.
 func (c *invClient) Rebuild(entries []invEntry) error {
 	c.Lock()
 	defer c.Unlock()
 
+	prevInv, err := c.getInv()
+	if err != nil {
+		return err
+	}
+	prev := make(map[string]invEntry, len(entries))
+	for _, es := range prevInv.Entries {
+		for _, v := range es {
+			prev[v.Token] = v
+		}
+	}
+
 	inv := newInv()
 	for _, v := range entries {
+		e, ok := prev[v.Token]
+		if v.StatusTimestamp == 0 && ok && e.Status == v.Status {
+			v.StatusTimestamp = e.StatusTimestamp
+		}
 		inv.Add(v)
 	}
 	inv.Sort()
@@ -353,7 +582,7 @@ func (c *invClient) addEntry(token string, status ticketvote.VoteStatusT, timest
 		return err
 	}
 
-	e := newInvEntry(token, status, timestamp, 0)
+	e := newInvEntry(token, status, timestamp, 0, 0)
 	inv.Add(*e)
 
 	err = c.saveInv(*inv)
@@ -373,7 +602,7 @@ func (c *invClient) addEntry(token string, status ticketvote.VoteStatusT, timest
 //
 // This function is not concurrency safe. It must be called with the mutex
 // locked.
-func (c *invClient) updateEntry(token string, status ticketvote.VoteStatusT, timestamp int64, endBlockHeight uint32) error {
+func (c *invClient) updateEntry(token string, status ticketvote.VoteStatusT, timestamp int64, startBlockHeight, endBlockHeight uint32) error {
 	// Get the existing inventory
 	inv, err := c.getInv()
 	if err != nil {
@@ -399,18 +628,28 @@ func (c *invClient) updateEntry(token string, status ticketvote.VoteStatusT, tim
 	)
 	switch status {
 	case ticketvote.VoteStatusUnauthorized:
+		// A runoff vote submission is updated from started back to
+		// unauthorized when the runoff vote start is rolled back.
 		statusesToScan = []ticketvote.VoteStatusT{
 			ticketvote.VoteStatusAuthorized,
+			ticketvote.VoteStatusStarted,
 		}
 
 	case ticketvote.VoteStatusAuthorized:
+		// A started vote is updated back to authorized when the vote
+		// is cancelled.
 		statusesToScan = []ticketvote.VoteStatusT{
 			ticketvote.VoteStatusUnauthorized,
+			ticketvote.VoteStatusStarted,
 		}
 
 	case ticketvote.VoteStatusStarted:
+		// A started vote is updated when the vote is extended. Runoff
+		// vote submissions are started without being authorized.
 		statusesToScan = []ticketvote.VoteStatusT{
 			ticketvote.VoteStatusAuthorized,
+			ticketvote.VoteStatusStarted,
+			ticketvote.VoteStatusUnauthorized,
 		}
 
 	case ticketvote.VoteStatusFinished,
@@ -455,11 +694,23 @@ func (c *invClient) updateEntry(token string, status ticketvote.VoteStatusT, tim
 
 	// Delete the existing entry then add the updated entry to
 	// the inventory.
+	prev, _ := entryForToken(inv.Entries[prevStatus], token)
 	err = inv.Del(token, prevStatus)
 	if err != nil {
 		return err
 	}
-	e := newInvEntry(token, status, timestamp, endBlockHeight)
+	e := newInvEntry(token, status, timestamp, startBlockHeight,
+		endBlockHeight)
+	switch {
+	case status == prevStatus:
+		// The vote status has not changed, e.g. the vote was
+		// extended. Keep the existing status timestamp.
+		e.StatusTimestamp = prev.StatusTimestamp
+	case e.StatusTimestamp == 0:
+		// Post vote status changes do not have a timestamp
+		// associated with them.
+		e.StatusTimestamp = time.Now().Unix()
+	}
 	inv.Add(*e)
 
 	// Save the updated inventory
@@ -530,7 +781,9 @@ func (c *invClient) updateBlockHeight(blockHeight uint32) (*inv, error) {
 			if err != nil {
 				return nil, err
 			}
-			e := newInvEntry(v.Token, s.Status, 0, s.EndBlockHeight)
+			e := newInvEntry(v.Token, s.Status, 0, s.StartBlockHeight,
+				s.EndBlockHeight)
+			e.StatusTimestamp = time.Now().Unix()
 			inv.Add(*e)
 
 		default:
@@ -607,6 +860,17 @@ func (c *invClient) summary(token string) (*ticketvote.SummaryReply, error) {
 	return &sr, nil
 }
 
+// entryForToken returns the inventory entry that matches the provided token.
+// False is returned if the entries do not include the token.
+func entryForToken(entries []invEntry, token string) (invEntry, bool) {
+	for _, v := range entries {
+		if v.Token == token {
+			return v, true
+		}
+	}
+	return invEntry{}, false
+}
+
 // entriesIncludeToken returns whether the inventory entries include an entry
 // that matches the provided token.
 func entriesIncludeToken(entries []invEntry, token string) bool {
@@ -620,6 +884,18 @@ func entriesIncludeToken(entries []invEntry, token string) bool {
 	return found
 }
 
+// entryStatusTimestamps adds the status timestamps of the provided inventory
+// entries to the provided map. Entries without a status timestamp are
+// skipped.
+func entryStatusTimestamps(entries []invEntry, timestamps map[string]int64) {
+	for _, v := range entries {
+		if v.StatusTimestamp == 0 {
+			continue
+		}
+		timestamps[v.Token] = v.StatusTimestamp
+	}
+}
+
 // entryTokens filters and returns the tokens from the inventory entries.
 func entryTokens(entries []invEntry) []string {
 	tokens := make([]string, 0, 2048)
@@ -0,0 +1,212 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestInvStartedAfter(t *testing.T) {
+	i := newInv()
+	entries := []invEntry{
+		*newInvEntry("a", ticketvote.VoteStatusAuthorized, 100, 0, 0),
+		*newInvEntry("b", ticketvote.VoteStatusStarted, 0, 10, 20),
+		*newInvEntry("c", ticketvote.VoteStatusStarted, 0, 30, 40),
+		*newInvEntry("d", ticketvote.VoteStatusApproved, 0, 5, 15),
+		*newInvEntry("e", ticketvote.VoteStatusApproved, 0, 25, 35),
+	}
+	for _, v := range entries {
+		i.Add(v)
+	}
+	i.Sort()
+
+	var tests = []struct {
+		name         string
+		startedAfter uint32
+		status       ticketvote.VoteStatusT
+		pageNumber   uint32
+		pageSize     uint32
+		want         []string
+	}{
+		{"no filter", 0, ticketvote.VoteStatusStarted, 1, 10,
+			[]string{"c", "b"}},
+		{"no filter unauthorized", 0, ticketvote.VoteStatusAuthorized, 1, 10,
+			[]string{"a"}},
+		{"filter started", 10, ticketvote.VoteStatusStarted, 1, 10,
+			[]string{"c"}},
+		{"filter approved", 4, ticketvote.VoteStatusApproved, 1, 10,
+			[]string{"e", "d"}},
+		{"filter excludes pre vote", 1, ticketvote.VoteStatusAuthorized, 1, 10,
+			[]string{}},
+		{"page size", 0, ticketvote.VoteStatusApproved, 1, 1,
+			[]string{"e"}},
+		{"second page", 0, ticketvote.VoteStatusApproved, 2, 1,
+			[]string{"d"}},
+		{"past last page", 0, ticketvote.VoteStatusApproved, 3, 1,
+			[]string{}},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			f := i.StartedAfter(v.startedAfter)
+			got := entryTokens(f.GetPage(v.status, v.pageNumber, v.pageSize))
+			if !reflect.DeepEqual(got, v.want) {
+				t.Errorf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+}
+
+func TestInvClientStartHeightsBackfill(t *testing.T) {
+	c := newInvClient(&cacheTstoreClient{
+		blobs: make(map[string][]byte),
+	}, nil, 20)
+
+	// Save an inventory that was built before the start block
+	// heights were tracked.
+	inv := newInv()
+	inv.Add(*newInvEntry("a", ticketvote.VoteStatusUnauthorized, 100, 0, 0))
+	inv.Add(*newInvEntry("b", ticketvote.VoteStatusStarted, 0, 0, 30))
+	inv.Add(*newInvEntry("c", ticketvote.VoteStatusApproved, 0, 0, 20))
+	inv.Add(*newInvEntry("d", ticketvote.VoteStatusRejected, 0, 0, 20))
+	inv.Add(*newInvEntry("e", ticketvote.VoteStatusRejected, 0, 5, 15))
+	err := c.saveInv(*inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	heights := map[string]uint32{
+		"b": 25,
+		"c": 12,
+	}
+	n, err := c.StartHeightsBackfill(func(token string) (uint32, error) {
+		h, ok := heights[token]
+		if !ok {
+			return 0, errors.New("vote details not found")
+		}
+		return h, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("got %v entries updated, want 2", n)
+	}
+
+	// The backfilled entries are returned by the started after filter.
+	// Entries whose start height could not be determined are skipped.
+	inv, err = c.getInv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	filtered := inv.StartedAfter(10)
+	var got []string
+	for _, s := range []ticketvote.VoteStatusT{
+		ticketvote.VoteStatusStarted,
+		ticketvote.VoteStatusApproved,
+		ticketvote.VoteStatusRejected,
+	} {
+		for _, v := range filtered.Entries[s] {
+			got = append(got, v.Token)
+		}
+	}
+	want := []string{"b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestInvClientPageSizeCapped(t *testing.T) {
+	c := newInvClient(nil, nil, 20)
+	var tests = []struct {
+		pageSize uint32
+		want     uint32
+	}{
+		{0, 20},
+		{5, 5},
+		{20, 20},
+		{21, 20},
+	}
+	for _, v := range tests {
+		got := c.pageSizeCapped(v.pageSize)
+		if got != v.want {
+			t.Errorf("pageSizeCapped(%v): got %v, want %v",
+				v.pageSize, got, v.want)
+		}
+	}
+}
+
+func TestInvClientStatusTimestamps(t *testing.T) {
+	c := newInvClient(&cacheTstoreClient{
+		blobs: make(map[string][]byte),
+	}, nil, 20)
+
+	// entry returns the inventory entry for the provided token
+	entry := func(token string) invEntry {
+		t.Helper()
+		inv, err := c.getInv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entries := range inv.Entries {
+			if e, ok := entryForToken(entries, token); ok {
+				return e
+			}
+		}
+		t.Fatalf("entry not found %v", token)
+		return invEntry{}
+	}
+
+	// Pre vote entries use the timestamp of the status change
+	c.AddEntry("a", ticketvote.VoteStatusUnauthorized, 100)
+	c.UpdateEntryPreVote("a", ticketvote.VoteStatusAuthorized, 200)
+	if e := entry("a"); e.StatusTimestamp != 200 {
+		t.Fatalf("got status timestamp %v, want 200", e.StatusTimestamp)
+	}
+
+	// Starting the vote sets a new status timestamp
+	c.UpdateEntryPostVote("a", ticketvote.VoteStatusStarted, 10, 20)
+	started := entry("a").StatusTimestamp
+	if started <= 200 {
+		t.Fatalf("got status timestamp %v, want the vote start time",
+			started)
+	}
+
+	// Extending the vote does not change the status timestamp
+	inv, err := c.getInv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv.Entries[ticketvote.VoteStatusStarted][0].StatusTimestamp = 300
+	err = c.saveInv(*inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.UpdateEntryPostVote("a", ticketvote.VoteStatusStarted, 10, 30)
+	e := entry("a")
+	if e.StatusTimestamp != 300 || e.EndBlockHeight != 30 {
+		t.Fatalf("got status timestamp %v, end height %v; want 300, 30",
+			e.StatusTimestamp, e.EndBlockHeight)
+	}
+
+	// Rebuilding the inventory carries over the status timestamps of
+	// the entries whose status has not changed.
+	err = c.Rebuild([]invEntry{
+		*newInvEntry("a", ticketvote.VoteStatusStarted, 0, 10, 30),
+		*newInvEntry("b", ticketvote.VoteStatusStarted, 0, 10, 30),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := entry("a"); e.StatusTimestamp != 300 {
+		t.Fatalf("got status timestamp %v, want 300", e.StatusTimestamp)
+	}
+	if e := entry("b"); e.StatusTimestamp != 0 {
+		t.Fatalf("got status timestamp %v, want 0", e.StatusTimestamp)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"fmt"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+// cmdTicketOwnership verifies signed proofs of ticket ownership against the
+// eligible ticket snapshot of a record vote. A ticket that fails verification
+// does not cause the command to error. The reason is returned in the reply.
+func (p *ticketVotePlugin) cmdTicketOwnership(token []byte, payload string) (string, error) {
+	// Decode payload
+	var to ticketvote.TicketOwnership
+	err := json.Unmarshal([]byte(payload), &to)
+	if err != nil {
+		return "", err
+	}
+
+	// Get the vote details and the eligible ticket snapshot
+	vd, err := p.voteDetails(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has not been started",
+		}
+	}
+	snapshot, err := p.snapshot(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify that the tickets are part of the snapshot
+	var (
+		weights = make(map[string]uint64, len(to.Signatures))
+		errs    = make(map[string]string)
+		tickets = make([]string, 0, len(to.Signatures))
+	)
+	for _, v := range to.Signatures {
+		_, ok := errs[v.Ticket]
+		if ok {
+			continue
+		}
+		if !snapshot.contains(v.Ticket) {
+			errs[v.Ticket] = "ticket not eligible"
+			continue
+		}
+		tickets = append(tickets, v.Ticket)
+	}
+
+	// Verify the signatures
+	addrs, err := p.commitmentAddrs(tickets, vd.StartBlockHeight)
+	if err != nil {
+		return "", fmt.Errorf("commitmentAddrs: %v", err)
+	}
+	for _, v := range to.Signatures {
+		if _, ok := errs[v.Ticket]; ok {
+			continue
+		}
+		if _, ok := weights[v.Ticket]; ok {
+			// Duplicate ticket
+			continue
+		}
+		ca, ok := addrs[v.Ticket]
+		switch {
+		case !ok:
+			errs[v.Ticket] = "commitment address not found"
+			continue
+		case ca.err != nil:
+			errs[v.Ticket] = fmt.Sprintf("commitment address: %v", ca.err)
+			continue
+		}
+		err := p.eligibility.VerifySignature(ca.addr, to.Message, v.Signature)
+		if err != nil {
+			errs[v.Ticket] = fmt.Sprintf("signature invalid: %v", err)
+			continue
+		}
+
+		// The ticket stake is only used as the weight for stake
+		// weighted votes.
+		weight := uint64(1)
+		if vd.Params.Weight == ticketvote.VoteWeightStake {
+			if ca.stake == 0 {
+				errs[v.Ticket] = "ticket stake not found"
+				continue
+			}
+			weight = ca.stake
+		}
+		weights[v.Ticket] = weight
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(ticketvote.TicketOwnershipReply{
+		Weights: weights,
+		Errors:  errs,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
@@ -0,0 +1,441 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// snapshotVersion is the version of the eligible ticket snapshot
+	// encoding.
+	snapshotVersion = 1
+
+	// ticketHashSize is the size of a ticket hash in bytes.
+	ticketHashSize = 32
+)
+
+// ticketHash is a decoded ticket hash.
+type ticketHash [ticketHashSize]byte
+
+// ticketSnapshot is an eligible ticket snapshot. It contains the eligible
+// tickets of a vote as a sorted set of decoded ticket hashes. This allows
+// membership to be checked using a binary search and uses a fraction of the
+// memory of a hex encoded []string or map[string]struct{}.
+//
+// A ticketSnapshot must not be modified once it has been created.
+type ticketSnapshot []ticketHash
+
+// newTicketSnapshot returns a ticketSnapshot for the provided hex encoded
+// ticket hashes. Duplicate tickets are removed.
+func newTicketSnapshot(tickets []string) (ticketSnapshot, error) {
+	s := make(ticketSnapshot, 0, len(tickets))
+	for _, v := range tickets {
+		h, err := ticketHashDecode(v)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, h)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return bytes.Compare(s[i][:], s[j][:]) < 0
+	})
+
+	// Remove duplicates
+	deduped := s[:0]
+	for i, v := range s {
+		if i > 0 && v == s[i-1] {
+			continue
+		}
+		deduped = append(deduped, v)
+	}
+
+	return deduped, nil
+}
+
+// ticketHashDecode decodes a hex encoded ticket hash.
+func ticketHashDecode(ticket string) (ticketHash, error) {
+	var h ticketHash
+	b, err := hex.DecodeString(ticket)
+	if err != nil {
+		return h, fmt.Errorf("invalid ticket %v: %v", ticket, err)
+	}
+	if len(b) != ticketHashSize {
+		return h, fmt.Errorf("invalid ticket %v: invalid length", ticket)
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// contains returns whether the provided hex encoded ticket hash is part of
+// the snapshot.
+func (s ticketSnapshot) contains(ticket string) bool {
+	h, err := ticketHashDecode(ticket)
+	if err != nil {
+		return false
+	}
+	i := sort.Search(len(s), func(i int) bool {
+		return bytes.Compare(s[i][:], h[:]) >= 0
+	})
+	return i < len(s) && s[i] == h
+}
+
+// tickets returns the hex encoded ticket hashes of the snapshot, sorted in
+// ascending order.
+func (s ticketSnapshot) tickets() []string {
+	tickets := make([]string, 0, len(s))
+	for _, v := range s {
+		tickets = append(tickets, hex.EncodeToString(v[:]))
+	}
+	return tickets
+}
+
+// encode returns the binary encoding of the snapshot.
+//
+// The encoding is a version byte, followed by the uvarint encoded ticket
+// count, followed by the delta-encoded ticket hashes in ascending order. Each
+// ticket hash is encoded as a single byte that contains the number of leading
+// bytes that the hash shares with the previous hash, followed by the remaining
+// bytes of the hash. The first hash shares zero bytes.
+func (s ticketSnapshot) encode() []byte {
+	b := make([]byte, 0, 1+binary.MaxVarintLen64+len(s)*ticketHashSize)
+	b = append(b, snapshotVersion)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+
+	var prev ticketHash
+	for i, v := range s {
+		var shared int
+		if i > 0 {
+			for shared < ticketHashSize-1 && v[shared] == prev[shared] {
+				shared++
+			}
+		}
+		b = append(b, byte(shared))
+		b = append(b, v[shared:]...)
+		prev = v
+	}
+
+	return b
+}
+
+// digest returns the hex encoded SHA256 digest of the binary encoding of the
+// snapshot.
+func (s ticketSnapshot) digest() string {
+	return hex.EncodeToString(util.Digest(s.encode()))
+}
+
+// decodeTicketSnapshot decodes a binary encoded ticket snapshot.
+func decodeTicketSnapshot(b []byte) (ticketSnapshot, error) {
+	if len(b) == 0 {
+		return nil, fmt.Errorf("empty snapshot")
+	}
+	if b[0] != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %v", b[0])
+	}
+	b = b[1:]
+	count, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid ticket count")
+	}
+	b = b[n:]
+
+	// Each ticket requires at least two bytes
+	if count > uint64(len(b)/2) {
+		return nil, fmt.Errorf("ticket count %v exceeds snapshot size", count)
+	}
+
+	s := make(ticketSnapshot, 0, count)
+	var prev ticketHash
+	for i := uint64(0); i < count; i++ {
+		if len(b) == 0 {
+			return nil, fmt.Errorf("ticket %v: unexpected end of snapshot", i)
+		}
+		shared := int(b[0])
+		b = b[1:]
+		if shared >= ticketHashSize || (i == 0 && shared != 0) {
+			return nil, fmt.Errorf("ticket %v: invalid shared length %v",
+				i, shared)
+		}
+		suffix := ticketHashSize - shared
+		if len(b) < suffix {
+			return nil, fmt.Errorf("ticket %v: unexpected end of snapshot", i)
+		}
+		var h ticketHash
+		copy(h[:shared], prev[:shared])
+		copy(h[shared:], b[:suffix])
+		b = b[suffix:]
+
+		// Tickets must be strictly ascending
+		if i > 0 && bytes.Compare(h[:], prev[:]) <= 0 {
+			return nil, fmt.Errorf("ticket %v: not sorted", i)
+		}
+
+		s = append(s, h)
+		prev = h
+	}
+	if len(b) != 0 {
+		return nil, fmt.Errorf("%v unexpected trailing bytes", len(b))
+	}
+
+	return s, nil
+}
+
+// snapshotSave saves an eligible ticket snapshot to the backend.
+func (p *ticketVotePlugin) snapshotSave(token []byte, s ticketSnapshot) error {
+	be, err := convertBlobEntryFromSnapshot(s)
+	if err != nil {
+		return err
+	}
+	return p.tstore.BlobSave(token, *be)
+}
+
+// snapshot returns the eligible ticket snapshot for a record. Nil is returned
+// if a snapshot is not found.
+//
+// The active votes cache is checked first. Votes that were started prior to
+// snapshots being introduced include the eligible tickets in the vote details
+// blob instead of in a separate snapshot blob. The snapshot is created from
+// the vote details for these votes.
+func (p *ticketVotePlugin) snapshot(token []byte) (ticketSnapshot, error) {
+	if s := p.activeVotes.Snapshot(token); s != nil {
+		return s, nil
+	}
+
+	blobs, err := p.blobsByDataDesc(token, dataDescriptorSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	if len(blobs) > 0 {
+		// Use the most recent snapshot. There should only be one,
+		// but an additional snapshot is saved if a vote details
+		// save fails and the vote is started again.
+		return convertSnapshotFromBlobEntry(blobs[len(blobs)-1])
+	}
+
+	// Fallback to the vote details
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return nil, err
+	}
+	if vd == nil {
+		return nil, nil
+	}
+	return newTicketSnapshot(vd.EligibleTickets)
+}
+
+// cmdSnapshot returns the binary encoded eligible ticket snapshot for a
+// record vote.
+func (p *ticketVotePlugin) cmdSnapshot(token []byte) (string, error) {
+	s, err := p.snapshot(token)
+	if err != nil {
+		return "", err
+	}
+
+	var sr ticketvote.SnapshotReply
+	if s != nil {
+		sr.Snapshot = base64.StdEncoding.EncodeToString(s.encode())
+		sr.Count = uint32(len(s))
+	}
+	reply, err := json.Marshal(sr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdEligibility returns whether the provided tickets are eligible to vote in
+// a record vote. All tickets are returned as not eligible if a vote has not
+// been started for the record.
+func (p *ticketVotePlugin) cmdEligibility(token []byte, payload string) (string, error) {
+	var e ticketvote.Eligibility
+	err := json.Unmarshal([]byte(payload), &e)
+	if err != nil {
+		return "", err
+	}
+
+	s, err := p.snapshot(token)
+	if err != nil {
+		return "", err
+	}
+
+	eligible := make(map[string]bool, len(e.Tickets))
+	for _, v := range e.Tickets {
+		eligible[v] = s.contains(v)
+	}
+	reply, err := json.Marshal(ticketvote.EligibilityReply{
+		Eligible: eligible,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdSnapshotVerify re-fetches the ticket pool at the snapshot block of a
+// record vote from the chain and verifies that it matches the stored eligible
+// ticket snapshot. A verification report that is signed by the server is
+// returned.
+func (p *ticketVotePlugin) cmdSnapshotVerify(token []byte) (string, error) {
+	// Get the vote details. The snapshot block is saved as the start
+	// block of the vote.
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has not been started",
+		}
+	}
+
+	// Get the stored snapshot
+	s, err := p.snapshot(token)
+	if err != nil {
+		return "", err
+	}
+	if s == nil {
+		return "", fmt.Errorf("eligible ticket snapshot not found for %x",
+			token)
+	}
+
+	// Re-fetch the ticket pool from the chain
+	tickets, err := p.eligibility.EligibleTickets(vd.StartBlockHash)
+	if err != nil {
+		return "", err
+	}
+	c, err := newTicketSnapshot(tickets)
+	if err != nil {
+		return "", err
+	}
+
+	// Compare the snapshots and sign the report
+	missing, extra := snapshotDiff(s, c)
+	sv := ticketvote.SnapshotVerification{
+		Token:            vd.Params.Token,
+		StartBlockHeight: vd.StartBlockHeight,
+		StartBlockHash:   vd.StartBlockHash,
+		SnapshotCount:    uint32(len(s)),
+		SnapshotDigest:   s.digest(),
+		ChainCount:       uint32(len(c)),
+		ChainDigest:      c.digest(),
+		Missing:          missing,
+		Extra:            extra,
+		Timestamp:        time.Now().Unix(),
+	}
+	sv.Match = sv.SnapshotDigest == sv.ChainDigest
+	msg := fmt.Sprintf("%v%v%v%v%v", sv.Token, sv.StartBlockHash,
+		sv.SnapshotDigest, sv.ChainDigest, sv.Timestamp)
+	receipt := p.identity.SignMessage([]byte(msg))
+	sv.Receipt = hex.EncodeToString(receipt[:])
+
+	if !sv.Match {
+		log.Warnf("Eligible ticket snapshot mismatch %v: %v missing, "+
+			"%v extra", sv.Token, len(missing), len(extra))
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(ticketvote.SnapshotVerifyReply{
+		Verification: sv,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// snapshotDiff compares the stored snapshot s against the chain snapshot c.
+// The tickets that are in c but not in s are returned as missing. The tickets
+// that are in s but not in c are returned as extra.
+func snapshotDiff(s, c ticketSnapshot) (missing, extra []string) {
+	missing = make([]string, 0)
+	extra = make([]string, 0)
+	var i, j int
+	for i < len(s) || j < len(c) {
+		switch {
+		case i == len(s):
+			missing = append(missing, hex.EncodeToString(c[j][:]))
+			j++
+		case j == len(c):
+			extra = append(extra, hex.EncodeToString(s[i][:]))
+			i++
+		default:
+			switch cmp := bytes.Compare(s[i][:], c[j][:]); {
+			case cmp == 0:
+				i++
+				j++
+			case cmp < 0:
+				extra = append(extra, hex.EncodeToString(s[i][:]))
+				i++
+			default:
+				missing = append(missing, hex.EncodeToString(c[j][:]))
+				j++
+			}
+		}
+	}
+	return missing, extra
+}
+
+func convertSnapshotFromBlobEntry(be store.BlobEntry) (ticketSnapshot, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorSnapshot {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorSnapshot)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+
+	return decodeTicketSnapshot(b)
+}
+
+func convertBlobEntryFromSnapshot(s ticketSnapshot) (*store.BlobEntry, error) {
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeBinary,
+			Descriptor: dataDescriptorSnapshot,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, s.encode())
+	return &be, nil
+}
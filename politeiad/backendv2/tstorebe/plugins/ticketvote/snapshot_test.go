@@ -0,0 +1,179 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// randomTickets returns n random hex encoded ticket hashes.
+func randomTickets(t *testing.T, n int) []string {
+	t.Helper()
+
+	tickets := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, ticketHashSize)
+		_, err := rand.Read(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tickets = append(tickets, hex.EncodeToString(b))
+	}
+	return tickets
+}
+
+func TestTicketSnapshot(t *testing.T) {
+	tickets := randomTickets(t, 1000)
+
+	// Include a duplicate ticket
+	s, err := newTicketSnapshot(append(tickets, tickets[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != len(tickets) {
+		t.Fatalf("got %v tickets, want %v", len(s), len(tickets))
+	}
+
+	// Verify the encoding round trips
+	b := s.encode()
+	if len(b) >= len(tickets)*ticketHashSize+len(tickets) {
+		t.Errorf("encoding is not delta-encoded: %v bytes", len(b))
+	}
+	decoded, err := decodeTicketSnapshot(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted := append([]string{}, tickets...)
+	sort.Strings(sorted)
+	if !reflect.DeepEqual(decoded.tickets(), sorted) {
+		t.Fatalf("decoded tickets do not match")
+	}
+
+	// Verify membership
+	for _, v := range tickets {
+		if !decoded.contains(v) {
+			t.Fatalf("ticket %v not found", v)
+		}
+	}
+	for _, v := range randomTickets(t, 10) {
+		if decoded.contains(v) {
+			t.Fatalf("unexpected ticket %v found", v)
+		}
+	}
+	if decoded.contains("invalid") {
+		t.Fatalf("invalid ticket found")
+	}
+
+	// A nil snapshot contains nothing
+	var empty ticketSnapshot
+	if empty.contains(tickets[0]) {
+		t.Fatalf("ticket found in nil snapshot")
+	}
+}
+
+func TestTicketSnapshotDigest(t *testing.T) {
+	// The digest is also computed by clients when verifying the
+	// eligible tickets of a vote and must not change.
+	s, err := newTicketSnapshot([]string{
+		strings.Repeat("ab", 32),
+		strings.Repeat("00", 31) + "02",
+		strings.Repeat("00", 31) + "01",
+		strings.Repeat("ab", 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "64f020414a87cad1d4855164ee4a225ad770e460f468dcf82baf93dc1e5674eb"
+	if got := s.digest(); got != want {
+		t.Fatalf("got digest %v, want %v", got, want)
+	}
+}
+
+func TestDecodeTicketSnapshot(t *testing.T) {
+	s, err := newTicketSnapshot(randomTickets(t, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := s.encode()
+
+	// Swap the order of the last two tickets
+	unsorted := ticketSnapshot{s[0], s[2], s[1]}.encode()
+
+	var tests = []struct {
+		name string
+		b    []byte
+	}{
+		{"empty", []byte{}},
+		{"invalid version", append([]byte{2}, valid[1:]...)},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing bytes", append(append([]byte{}, valid...), 0)},
+		{"count too large", []byte{snapshotVersion, 100, 0}},
+		{"unsorted", unsorted},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := decodeTicketSnapshot(v.b)
+			if err == nil {
+				t.Fatalf("got nil error, want error")
+			}
+		})
+	}
+
+	// Empty snapshots are valid
+	b := ticketSnapshot{}.encode()
+	decoded, err := decodeTicketSnapshot(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 0 {
+		t.Fatalf("got %v tickets, want 0", len(decoded))
+	}
+}
+
+func TestNewTicketSnapshotInvalid(t *testing.T) {
+	_, err := newTicketSnapshot([]string{"zz"})
+	if err == nil {
+		t.Fatalf("got nil error for non-hex ticket")
+	}
+	_, err = newTicketSnapshot([]string{"abcd"})
+	if err == nil {
+		t.Fatalf("got nil error for short ticket")
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	tickets := randomTickets(t, 10)
+	sort.Strings(tickets)
+
+	// The stored snapshot contains an extra ticket and is missing
+	// two of the chain tickets.
+	s, err := newTicketSnapshot(append([]string{tickets[0]}, tickets[3:]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newTicketSnapshot(tickets[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, extra := snapshotDiff(s, c)
+	if !reflect.DeepEqual(missing, tickets[1:3]) {
+		t.Errorf("missing: got %v, want %v", missing, tickets[1:3])
+	}
+	if !reflect.DeepEqual(extra, tickets[:1]) {
+		t.Errorf("extra: got %v, want %v", extra, tickets[:1])
+	}
+
+	// Matching snapshots
+	missing, extra = snapshotDiff(c, c)
+	if len(missing) != 0 || len(extra) != 0 {
+		t.Errorf("got %v missing and %v extra, want none",
+			len(missing), len(extra))
+	}
+}
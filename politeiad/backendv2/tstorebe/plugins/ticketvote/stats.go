@@ -0,0 +1,162 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+const (
+	// secondsPerDay is the number of seconds in a day.
+	secondsPerDay = 24 * 60 * 60
+)
+
+// cmdVoteStats returns the turnout and participation statistics of a finished
+// record vote.
+func (p *ticketVotePlugin) cmdVoteStats(token []byte) (string, error) {
+	// Verify the vote has finished. This cmd does not write any data
+	// so we do not have to use the safe best block.
+	bb, err := p.chain.BestBlockUnsafe()
+	if err != nil {
+		return "", fmt.Errorf("BestBlockUnsafe: %v", err)
+	}
+	sr, err := p.summary(token, bb)
+	if err != nil {
+		return "", fmt.Errorf("summary: %v", err)
+	}
+	switch sr.Status {
+	case ticketvote.VoteStatusFinished, ticketvote.VoteStatusApproved,
+		ticketvote.VoteStatusRejected:
+		// Vote has finished; continue
+	default:
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has not finished",
+		}
+	}
+
+	// Get the vote options and the cast votes
+	vd, err := p.voteDetailsBlob(token)
+	if err != nil {
+		return "", err
+	}
+	if vd == nil {
+		return "", fmt.Errorf("vote details not found for %x", token)
+	}
+	votes, err := p.voteResults(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Compute the stats
+	vsr, err := voteStats(vd.Params.Options, sr.EligibleTickets, votes)
+	if err != nil {
+		return "", err
+	}
+	vsr.Status = sr.Status
+
+	// Prepare reply
+	reply, err := json.Marshal(vsr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// voteStats computes the turnout and participation statistics for the
+// provided cast votes. The status of the returned reply is not populated.
+func voteStats(options []ticketvote.VoteOption, eligible uint32, votes []ticketvote.CastVoteDetails) (*ticketvote.VoteStatsReply, error) {
+	// Find the approve and reject vote bits. These will not exist
+	// if the vote does not use the approve and reject vote options.
+	var approveBit, rejectBit uint64
+	for _, v := range options {
+		switch v.ID {
+		case ticketvote.VoteOptionIDApprove:
+			approveBit = v.Bit
+		case ticketvote.VoteOptionIDReject:
+			rejectBit = v.Bit
+		}
+	}
+	hasApproval := approveBit != 0 && rejectBit != 0
+
+	// Sort the votes by timestamp
+	sorted := make([]ticketvote.CastVoteDetails, len(votes))
+	copy(sorted, votes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	var (
+		tickets = make(map[string]struct{}, len(sorted))
+		days    = make([]ticketvote.VoteStatsDay, 0, 16)
+
+		approve, reject uint32
+	)
+	for _, v := range sorted {
+		tickets[v.Ticket] = struct{}{}
+
+		// Add any missing days, including days without votes, so
+		// that the days are contiguous.
+		day := v.Timestamp - v.Timestamp%secondsPerDay
+		if len(days) == 0 {
+			days = append(days, ticketvote.VoteStatsDay{Day: day})
+		}
+		for days[len(days)-1].Day < day {
+			prev := days[len(days)-1]
+			days = append(days, ticketvote.VoteStatsDay{
+				Day:      prev.Day + secondsPerDay,
+				Approve:  prev.Approve,
+				Reject:   prev.Reject,
+				Approval: prev.Approval,
+			})
+		}
+
+		// Tally the vote
+		d := &days[len(days)-1]
+		d.Votes++
+		if !hasApproval {
+			continue
+		}
+		bit, err := strconv.ParseUint(v.VoteBit, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vote bit %v for ticket %v: %v",
+				v.VoteBit, v.Ticket, err)
+		}
+		switch bit {
+		case approveBit:
+			approve++
+		case rejectBit:
+			reject++
+		}
+		d.Approve = approve
+		d.Reject = reject
+		d.Approval = percentage(approve, approve+reject)
+	}
+
+	return &ticketvote.VoteStatsReply{
+		EligibleTickets: eligible,
+		UniqueTickets:   uint32(len(tickets)),
+		Turnout:         percentage(uint32(len(tickets)), eligible),
+		Approval:        percentage(approve, approve+reject),
+		Days:            days,
+	}, nil
+}
+
+// percentage returns n as a percentage of total. Zero is returned if the total
+// is zero.
+func percentage(n, total uint32) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
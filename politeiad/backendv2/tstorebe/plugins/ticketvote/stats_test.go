@@ -0,0 +1,70 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestVoteStats(t *testing.T) {
+	options := []ticketvote.VoteOption{
+		{ID: ticketvote.VoteOptionIDApprove, Bit: 0x01},
+		{ID: ticketvote.VoteOptionIDReject, Bit: 0x02},
+	}
+	var (
+		day0 int64 = 1640995200 // 2022-01-01 00:00:00 UTC
+		day1       = day0 + secondsPerDay
+		day2       = day1 + secondsPerDay
+	)
+	votes := []ticketvote.CastVoteDetails{
+		{Ticket: "t4", VoteBit: "1", Timestamp: day2 + 100},
+		{Ticket: "t1", VoteBit: "1", Timestamp: day0 + 100},
+		{Ticket: "t2", VoteBit: "2", Timestamp: day0 + 200},
+		{Ticket: "t3", VoteBit: "1", Timestamp: day2 + 50},
+	}
+
+	vsr, err := voteStats(options, 8, votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vsr.UniqueTickets != 4 || vsr.Turnout != 50 || vsr.Approval != 75 {
+		t.Errorf("got (%v, %v, %v), want (4, 50, 75)",
+			vsr.UniqueTickets, vsr.Turnout, vsr.Approval)
+	}
+	wantDays := []ticketvote.VoteStatsDay{
+		{Day: day0, Votes: 2, Approve: 1, Reject: 1, Approval: 50},
+		{Day: day1, Votes: 0, Approve: 1, Reject: 1, Approval: 50},
+		{Day: day2, Votes: 2, Approve: 3, Reject: 1, Approval: 75},
+	}
+	if !reflect.DeepEqual(vsr.Days, wantDays) {
+		t.Errorf("got days %+v, want %+v", vsr.Days, wantDays)
+	}
+
+	// Votes that do not use the approve and reject vote options only
+	// include the turnout stats.
+	options = []ticketvote.VoteOption{
+		{ID: "a", Bit: 0x01},
+		{ID: "b", Bit: 0x02},
+	}
+	vsr, err = voteStats(options, 8, votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vsr.Approval != 0 || vsr.Days[2].Approve != 0 {
+		t.Errorf("got approval stats for a vote without approval options")
+	}
+
+	// No votes
+	vsr, err = voteStats(options, 8, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vsr.Turnout != 0 || len(vsr.Days) != 0 {
+		t.Errorf("got stats %+v, want empty stats", vsr)
+	}
+}
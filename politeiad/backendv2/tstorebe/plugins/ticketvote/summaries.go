@@ -24,7 +24,9 @@ var (
 // cache. The data is saved to the TstoreClient provided plugin cache.
 //
 // Vote summaries are only cached once the vote has finished and additional
-// updates to the record's vote data are no longer possible.
+// updates to the record's vote data are no longer possible. The final vote
+// summary is also persisted to tstore as a blob so that the cache can be
+// repopulated without tallying the vote results again.
 //
 // tstore does not provide plugins with a sql transaction that can be used
 // to execute multiple database requests atomically during cache updates.
@@ -5,19 +5,17 @@
 package ticketvote
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/politeia/politeiad/api/v1/identity"
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
-	"github.com/decred/politeia/politeiad/plugins/dcrdata"
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
-	"github.com/pkg/errors"
 )
 
 var (
@@ -30,16 +28,31 @@ var (
 //
 // ticketVotePlugin satisfies the plugins PluginClient interface.
 type ticketVotePlugin struct {
-	backend         backend.Backend
-	tstore          plugins.TstoreClient
-	activeNetParams *chaincfg.Params
-
-	// dataDir is the ticket vote plugin data directory. The only data
-	// that is stored here is cached data that can be re-created at any
-	// time by walking the trillian trees. Ex, the vote summary once a
-	// record vote has ended.
+	backend backend.Backend
+	tstore  plugins.TstoreClient
+
+	// chain provides the chain specific data that is required to run
+	// a vote, e.g. the best block. The chain is selected using the
+	// chain plugin setting.
+	chain chain
+
+	// eligibility provides the eligible tickets of a vote and the
+	// addresses that must sign the votes. The eligibility source is
+	// selected using the eligibility plugin setting.
+	eligibility eligibility
+
+	// dataDir is the ticket vote plugin data directory. The data that
+	// is stored here is cached data that can be re-created at any time
+	// by walking the trillian trees, e.g. the vote summary once a record
+	// vote has ended. The only exception are the voter lists that are
+	// saved by the voter list eligibility source and the vote duration
+	// bounds that are set using the SetVoteDuration command.
 	dataDir string
 
+	// govExport manages the governance export of the finished votes.
+	// The export is saved to the plugin data directory.
+	govExport *govExport
+
 	// identity contains the full identity that the plugin uses to
 	// create receipts, i.e. signatures of user provided data that
 	// prove the backend received and processed a plugin command.
@@ -49,6 +62,11 @@ type ticketVotePlugin struct {
 	// validate vote ballots in a time efficient manner.
 	activeVotes *activeVotes
 
+	// activeVotesMtx prevents the active votes cache from being rebuilt
+	// while a vote is being started. Vote starts hold the read lock so
+	// that they can still be executed concurrently.
+	activeVotesMtx sync.RWMutex
+
 	// inv provides an API for managing the cached vote inventory. The
 	// data is cached in the tstore provided plugin cache.
 	inv *invClient
@@ -61,6 +79,10 @@ type ticketVotePlugin struct {
 	// cache. The data is saved to the tstore provided plugin cache.
 	subs *subsClient
 
+	// voteDurationMtx protects the vote duration bounds, which can be
+	// updated at runtime using the SetVoteDuration command.
+	voteDurationMtx sync.RWMutex
+
 	// Plugin settings
 	linkByPeriodMin    int64  // In seconds
 	linkByPeriodMax    int64  // In seconds
@@ -69,6 +91,33 @@ type ticketVotePlugin struct {
 	summariesPageSize  uint32
 	inventoryPageSize  uint32
 	timestampsPageSize uint32
+	voteExtensionMax   uint32 // In blocks
+
+	resultsExportPageSize uint32
+	chainName             string
+	eligibilityName       string
+	voterList             string
+	authLockBlocks        uint32 // In blocks
+	runoffStartBatchSize  uint32
+	runoffStartWorkers    uint32
+	castBallotWorkers     uint32
+
+	// snapshotHeightOverride allows the snapshot height of a vote to be
+	// provided when the vote is started. It can not be enabled on
+	// mainnet.
+	snapshotHeightOverride bool
+
+	// earlyFinish finishes a vote once all eligible tickets have
+	// voted instead of waiting for the end block height.
+	earlyFinish bool
+
+	// concurrentVotesMax is the number of votes that can be running
+	// concurrently before starting a standard vote results in a
+	// warning. Starting the vote is prevented instead when the
+	// concurrentVotesBlock setting is enabled. A value of zero
+	// disables the check.
+	concurrentVotesMax   uint32
+	concurrentVotesBlock bool
 }
 
 // Setup performs any plugin setup that is required.
@@ -77,95 +126,38 @@ type ticketVotePlugin struct {
 func (p *ticketVotePlugin) Setup() error {
 	log.Tracef("ticketvote Setup")
 
-	// Verify plugin dependencies
-	var dcrdataFound bool
-	for _, v := range p.backend.PluginInventory() {
-		if v.ID == dcrdata.PluginID {
-			dcrdataFound = true
-		}
+	// Setup the chain. This verifies any plugin dependencies.
+	err := p.chain.Setup()
+	if err != nil {
+		return err
 	}
-	if !dcrdataFound {
-		return errors.Errorf("%v plugin dependency not registered",
-			dcrdata.PluginID)
+	err = p.eligibility.Setup()
+	if err != nil {
+		return err
 	}
 
 	// Build the active votes cache
 	log.Infof("Building active votes cache")
 
-	var (
-		// started is populated with the tokens of all records
-		// that have a vote status of VoteStatusStarted.
-		started = make([]string, 0, 256)
-
-		page uint32 = 1
-	)
-	bestBlock, err := p.bestBlock()
+	_, err = p.activeVotesRebuild()
 	if err != nil {
 		return err
 	}
-	for {
-		entries, err := p.inv.GetPageForStatus(bestBlock,
-			ticketvote.VoteStatusStarted, page)
-		if err != nil {
-			return err
-		}
-		if len(entries) == 0 {
-			// We've reached the end of the inventory
-			// for the VoteStatusStarted entries.
-			break
-		}
-		started = append(started, entryTokens(entries)...)
-		page++
-	}
-	// Retrieve the data required to build the active
-	// votes cache for the records with ongoing votes.
-	for _, v := range started {
-		// Get the vote details
-		token, err := tokenDecode(v)
-		if err != nil {
-			return err
-		}
-
-		reply, err := p.backend.PluginRead(token, ticketvote.PluginID,
-			ticketvote.CmdDetails, "")
-		if err != nil {
-			return errors.Errorf("PluginRead %x %v %v: %v", token,
-				ticketvote.PluginID, ticketvote.CmdDetails, err)
-		}
-		var dr ticketvote.DetailsReply
-		err = json.Unmarshal([]byte(reply), &dr)
-		if err != nil {
-			return err
-		}
-		if dr.Vote == nil {
-			// Sanity check
-			return errors.Errorf("vote details not found "+
-				"for record in started inventory %x", token)
-		}
-
-		// Add the record to the active votes cache
-		p.activeVotesAdd(*dr.Vote)
-
-		// Get the cast votes
-		reply, err = p.backend.PluginRead(token, ticketvote.PluginID,
-			ticketvote.CmdResults, "")
-		if err != nil {
-			return errors.Errorf("PluginRead %x %v %v: %v", token,
-				ticketvote.PluginID, ticketvote.CmdResults, err)
-		}
-		var rr ticketvote.ResultsReply
-		err = json.Unmarshal([]byte(reply), &rr)
-		if err != nil {
-			return err
-		}
 
-		// Add the cast votes to the cached active vote entry
-		for _, v := range rr.Votes {
-			p.activeVotes.AddCastVote(v.Token, v.Ticket, v.VoteBit)
-		}
+	// Backfill the start block heights of any inventory entries
+	// that were added before the start block height was tracked.
+	// The StartedAfter inventory filter relies on them.
+	n, err := p.inv.StartHeightsBackfill(p.voteStartBlockHeight)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Infof("Vote inventory start heights backfilled: %v", n)
 	}
 
-	return nil
+	// Add any finished votes that are missing from the governance
+	// export.
+	return p.govExportSync()
 }
 
 // Cmd executes a plugin command.
@@ -193,12 +185,48 @@ func (p *ticketVotePlugin) Cmd(token []byte, cmd, payload string) (string, error
 		return p.cmdInventory(payload)
 	case ticketvote.CmdTimestamps:
 		return p.cmdTimestamps(token, payload)
+	case ticketvote.CmdSnapshot:
+		return p.cmdSnapshot(token)
+	case ticketvote.CmdEligibility:
+		return p.cmdEligibility(token, payload)
+	case ticketvote.CmdExtend:
+		return p.cmdExtend(token, payload)
+	case ticketvote.CmdCancel:
+		return p.cmdCancel(token, payload)
+	case ticketvote.CmdResultsExport:
+		return p.cmdResultsExport(token, payload)
+	case ticketvote.CmdReceipts:
+		return p.cmdReceipts(token, payload)
+	case ticketvote.CmdActiveVotesRebuild:
+		return p.cmdActiveVotesRebuild()
+	case ticketvote.CmdSnapshotVerify:
+		return p.cmdSnapshotVerify(token)
+	case ticketvote.CmdVoteStats:
+		return p.cmdVoteStats(token)
+	case ticketvote.CmdVote:
+		return p.cmdVote(token, payload)
+	case ticketvote.CmdGovernanceExport:
+		return p.cmdGovernanceExport(payload)
+	case ticketvote.CmdSetVoteDuration:
+		return p.cmdSetVoteDuration(payload)
+	case ticketvote.CmdVoteParamsVerify:
+		return p.cmdVoteParamsVerify(payload)
+	case ticketvote.CmdAuditBundle:
+		return p.cmdAuditBundle(token)
+	case ticketvote.CmdTicketOwnership:
+		return p.cmdTicketOwnership(token, payload)
+	case ticketvote.CmdVoteLoad:
+		return p.cmdVoteLoad()
 
 		// Internal plugin commands
 	case cmdStartRunoffSubmission:
 		return p.cmdStartRunoffSubmission(token, payload)
 	case cmdRunoffDetails:
 		return p.cmdRunoffDetails(token)
+	case cmdRollbackRunoffSub:
+		return p.cmdRollbackRunoffSubmission(token, payload)
+	case cmdSummarySave:
+		return p.cmdSummarySave(token, payload)
 	}
 
 	return "", backend.ErrPluginCmdInvalid
@@ -219,6 +247,8 @@ func (p *ticketVotePlugin) Hook(h plugins.HookT, payload string) error {
 		return p.hookSetRecordStatusPre(payload)
 	case plugins.HookTypeSetRecordStatusPost:
 		return p.hookSetRecordStatusPost(payload)
+	case plugins.HookTypePluginEvent:
+		return p.hookPluginEvent(payload)
 	}
 
 	return nil
@@ -240,6 +270,8 @@ func (p *ticketVotePlugin) Fsck(tokens [][]byte) error {
 func (p *ticketVotePlugin) Settings() []backend.PluginSetting {
 	log.Tracef("ticketvote Settings")
 
+	voteDurationMin, voteDurationMax := p.voteDurationBounds()
+
 	return []backend.PluginSetting{
 		{
 			Key:   ticketvote.SettingKeyLinkByPeriodMin,
@@ -251,11 +283,11 @@ func (p *ticketVotePlugin) Settings() []backend.PluginSetting {
 		},
 		{
 			Key:   ticketvote.SettingKeyVoteDurationMin,
-			Value: strconv.FormatUint(uint64(p.voteDurationMin), 10),
+			Value: strconv.FormatUint(uint64(voteDurationMin), 10),
 		},
 		{
 			Key:   ticketvote.SettingKeyVoteDurationMax,
-			Value: strconv.FormatUint(uint64(p.voteDurationMax), 10),
+			Value: strconv.FormatUint(uint64(voteDurationMax), 10),
 		},
 		{
 			Key:   ticketvote.SettingKeySummariesPageSize,
@@ -269,6 +301,58 @@ func (p *ticketVotePlugin) Settings() []backend.PluginSetting {
 			Key:   ticketvote.SettingKeyTimestampsPageSize,
 			Value: strconv.FormatUint(uint64(p.timestampsPageSize), 10),
 		},
+		{
+			Key:   ticketvote.SettingKeyVoteExtensionMax,
+			Value: strconv.FormatUint(uint64(p.voteExtensionMax), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeyResultsExportPageSize,
+			Value: strconv.FormatUint(uint64(p.resultsExportPageSize), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeyChain,
+			Value: p.chainName,
+		},
+		{
+			Key:   ticketvote.SettingKeyEligibility,
+			Value: p.eligibilityName,
+		},
+		{
+			Key:   ticketvote.SettingKeyVoterList,
+			Value: p.voterList,
+		},
+		{
+			Key:   ticketvote.SettingKeyAuthLockBlocks,
+			Value: strconv.FormatUint(uint64(p.authLockBlocks), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeyRunoffStartBatchSize,
+			Value: strconv.FormatUint(uint64(p.runoffStartBatchSize), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeyRunoffStartWorkers,
+			Value: strconv.FormatUint(uint64(p.runoffStartWorkers), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeyCastBallotWorkers,
+			Value: strconv.FormatUint(uint64(p.castBallotWorkers), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeySnapshotHeightOverride,
+			Value: strconv.FormatBool(p.snapshotHeightOverride),
+		},
+		{
+			Key:   ticketvote.SettingKeyEarlyFinish,
+			Value: strconv.FormatBool(p.earlyFinish),
+		},
+		{
+			Key:   ticketvote.SettingKeyConcurrentVotesMax,
+			Value: strconv.FormatUint(uint64(p.concurrentVotesMax), 10),
+		},
+		{
+			Key:   ticketvote.SettingKeyConcurrentVotesBlock,
+			Value: strconv.FormatBool(p.concurrentVotesBlock),
+		},
 	}
 }
 
@@ -279,9 +363,24 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		linkByPeriodMax    int64
 		voteDurationMin    uint32
 		voteDurationMax    uint32
+		voteExtensionMax   uint32
 		summariesPageSize  = ticketvote.SettingSummariesPageSize
 		inventoryPageSize  = ticketvote.SettingInventoryPageSize
 		timestampsPageSize = ticketvote.SettingTimestampsPageSize
+
+		resultsExportPageSize = ticketvote.SettingResultsExportPageSize
+		chainName             = ticketvote.SettingChain
+		eligibilityName       = ticketvote.SettingEligibility
+		voterList             string
+		authLockBlocks        = ticketvote.SettingAuthLockBlocks
+		runoffStartBatchSize  = ticketvote.SettingRunoffStartBatchSize
+		runoffStartWorkers    = ticketvote.SettingRunoffStartWorkers
+		castBallotWorkers     = ticketvote.SettingCastBallotWorkers
+
+		snapshotHeightOverride = ticketvote.SettingSnapshotHeightOverride
+		earlyFinish            = ticketvote.SettingEarlyFinish
+		concurrentVotesMax     = ticketvote.SettingConcurrentVotesMax
+		concurrentVotesBlock   = ticketvote.SettingConcurrentVotesBlock
 	)
 
 	// Set plugin settings to defaults. These will be overwritten if
@@ -292,17 +391,20 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		linkByPeriodMax = ticketvote.SettingMainNetLinkByPeriodMax
 		voteDurationMin = ticketvote.SettingMainNetVoteDurationMin
 		voteDurationMax = ticketvote.SettingMainNetVoteDurationMax
+		voteExtensionMax = ticketvote.SettingMainNetVoteExtensionMax
 	case chaincfg.TestNet3Params().Name:
 		linkByPeriodMin = ticketvote.SettingTestNetLinkByPeriodMin
 		linkByPeriodMax = ticketvote.SettingTestNetLinkByPeriodMax
 		voteDurationMin = ticketvote.SettingTestNetVoteDurationMin
 		voteDurationMax = ticketvote.SettingTestNetVoteDurationMax
+		voteExtensionMax = ticketvote.SettingTestNetVoteExtensionMax
 	case chaincfg.SimNetParams().Name:
 		// Use testnet defaults for simnet
 		linkByPeriodMin = ticketvote.SettingTestNetLinkByPeriodMin
 		linkByPeriodMax = ticketvote.SettingTestNetLinkByPeriodMax
 		voteDurationMin = ticketvote.SettingTestNetVoteDurationMin
 		voteDurationMax = ticketvote.SettingTestNetVoteDurationMax
+		voteExtensionMax = ticketvote.SettingTestNetVoteExtensionMax
 	default:
 		return nil, fmt.Errorf("unknown active net: %v", activeNetParams.Name)
 	}
@@ -380,23 +482,195 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 			log.Infof("Plugin setting updated: ticketvote %v %v",
 				ticketvote.SettingKeyTimestampsPageSize, timestampsPageSize)
 
+		case ticketvote.SettingKeyVoteExtensionMax:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			voteExtensionMax = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyVoteExtensionMax, voteExtensionMax)
+
+		case ticketvote.SettingKeyResultsExportPageSize:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			resultsExportPageSize = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyResultsExportPageSize, resultsExportPageSize)
+
+		case ticketvote.SettingKeyChain:
+			chainName = v.Value
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyChain, chainName)
+
+		case ticketvote.SettingKeyEligibility:
+			eligibilityName = v.Value
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyEligibility, eligibilityName)
+
+		case ticketvote.SettingKeyVoterList:
+			voterList = v.Value
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyVoterList, voterList)
+
+		case ticketvote.SettingKeyAuthLockBlocks:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			authLockBlocks = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyAuthLockBlocks, authLockBlocks)
+
+		case ticketvote.SettingKeyRunoffStartBatchSize:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, fmt.Errorf("plugin setting '%v': must be "+
+					"greater than zero", v.Key)
+			}
+			runoffStartBatchSize = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyRunoffStartBatchSize, runoffStartBatchSize)
+
+		case ticketvote.SettingKeyRunoffStartWorkers:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, fmt.Errorf("plugin setting '%v': must be "+
+					"greater than zero", v.Key)
+			}
+			runoffStartWorkers = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyRunoffStartWorkers, runoffStartWorkers)
+
+		case ticketvote.SettingKeyCastBallotWorkers:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			if u == 0 {
+				return nil, fmt.Errorf("plugin setting '%v': must be "+
+					"greater than zero", v.Key)
+			}
+			castBallotWorkers = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyCastBallotWorkers, castBallotWorkers)
+
+		case ticketvote.SettingKeySnapshotHeightOverride:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseBool(%v): %v",
+					v.Key, v.Value, err)
+			}
+			snapshotHeightOverride = b
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeySnapshotHeightOverride,
+				snapshotHeightOverride)
+
+		case ticketvote.SettingKeyEarlyFinish:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseBool(%v): %v",
+					v.Key, v.Value, err)
+			}
+			earlyFinish = b
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyEarlyFinish, earlyFinish)
+
+		case ticketvote.SettingKeyConcurrentVotesMax:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
+					v.Key, v.Value, err)
+			}
+			concurrentVotesMax = uint32(u)
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyConcurrentVotesMax, concurrentVotesMax)
+
+		case ticketvote.SettingKeyConcurrentVotesBlock:
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("plugin setting '%v': ParseBool(%v): %v",
+					v.Key, v.Value, err)
+			}
+			concurrentVotesBlock = b
+			log.Infof("Plugin setting updated: ticketvote %v %v",
+				ticketvote.SettingKeyConcurrentVotesBlock, concurrentVotesBlock)
+
 		default:
 			return nil, fmt.Errorf("invalid plugin setting '%v'", v.Key)
 		}
 	}
 
+	// The snapshot height override is only intended for testing
+	if snapshotHeightOverride &&
+		activeNetParams.Name == chaincfg.MainNetParams().Name {
+		return nil, fmt.Errorf("plugin setting '%v': not allowed on mainnet",
+			ticketvote.SettingKeySnapshotHeightOverride)
+	}
+
+	// Setup the chain
+	c, err := newChain(chainName, backend, activeNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("plugin setting '%v': %v",
+			ticketvote.SettingKeyChain, err)
+	}
+
 	// Create the plugin data directory
 	dataDir = filepath.Join(dataDir, ticketvote.PluginID)
-	err := os.MkdirAll(dataDir, 0700)
+	err = os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the vote duration bounds that were set using the
+	// SetVoteDuration command. They take precedence over the plugin
+	// settings.
+	vd, err := loadVoteDuration(dataDir)
 	if err != nil {
 		return nil, err
 	}
+	if vd != nil {
+		voteDurationMin = vd.Min
+		voteDurationMax = vd.Max
+		log.Infof("Vote duration bounds override: %v-%v blocks",
+			voteDurationMin, voteDurationMax)
+	}
+
+	// Setup the governance export
+	ge, err := newGovExport(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup the eligibility source
+	e, err := newEligibility(eligibilityName, voterList, dataDir,
+		backend, activeNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("plugin setting '%v': %v",
+			ticketvote.SettingKeyEligibility, err)
+	}
 
 	return &ticketVotePlugin{
-		activeNetParams:    activeNetParams,
 		backend:            backend,
 		tstore:             tstore,
+		chain:              c,
+		eligibility:        e,
 		dataDir:            dataDir,
+		govExport:          ge,
 		identity:           id,
 		activeVotes:        newActiveVotes(),
 		inv:                newInvClient(tstore, backend, inventoryPageSize),
@@ -409,5 +683,20 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		summariesPageSize:  summariesPageSize,
 		inventoryPageSize:  inventoryPageSize,
 		timestampsPageSize: timestampsPageSize,
+		voteExtensionMax:   voteExtensionMax,
+
+		resultsExportPageSize: resultsExportPageSize,
+		chainName:             chainName,
+		eligibilityName:       eligibilityName,
+		voterList:             voterList,
+		authLockBlocks:        authLockBlocks,
+		runoffStartBatchSize:  runoffStartBatchSize,
+		runoffStartWorkers:    runoffStartWorkers,
+		castBallotWorkers:     castBallotWorkers,
+
+		snapshotHeightOverride: snapshotHeightOverride,
+		earlyFinish:            earlyFinish,
+		concurrentVotesMax:     concurrentVotesMax,
+		concurrentVotesBlock:   concurrentVotesBlock,
 	}, nil
 }
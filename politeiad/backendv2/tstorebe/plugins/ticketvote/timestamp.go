@@ -120,6 +120,14 @@ func (p *ticketVotePlugin) cachedVoteTimestamps(token []byte, page, pageSize uin
 	return ts, nil
 }
 
+// timestampsDataRemove removes the data payloads from the provided
+// timestamps. The digests and the inclusion proofs are left intact.
+func timestampsDataRemove(ts []ticketvote.Timestamp) {
+	for i := range ts {
+		ts[i].Data = ""
+	}
+}
+
 // getVoteTimestampVoteKey returns the key for a vote timestamp in the
 // key-value store cache.
 func getVoteTimestampKey(token []byte, page, index uint32) (string, error) {
@@ -7,6 +7,8 @@ package ticketvote
 import (
 	"encoding/hex"
 	"testing"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 )
 
 func TestGetVoteTimestampKey(t *testing.T) {
@@ -319,3 +321,19 @@ func TestParseAuthTimestampKey(t *testing.T) {
 		})
 	}
 }
+
+func TestTimestampsDataRemove(t *testing.T) {
+	ts := []ticketvote.Timestamp{
+		{Data: "{}", Digest: "a", Proofs: []ticketvote.Proof{{Digest: "a"}}},
+		{Data: "{}", Digest: "b", Proofs: []ticketvote.Proof{{Digest: "b"}}},
+	}
+	timestampsDataRemove(ts)
+	for _, v := range ts {
+		if v.Data != "" {
+			t.Errorf("%v: got data %q, want none", v.Digest, v.Data)
+		}
+		if len(v.Proofs) != 1 || v.Proofs[0].Digest != v.Digest {
+			t.Errorf("%v: proofs were modified", v.Digest)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+const (
+	// voteDurationFilename is the filename of the vote duration bounds
+	// that were set using the SetVoteDuration command. The file is saved
+	// to the plugin data directory.
+	voteDurationFilename = "voteduration.json"
+)
+
+// voteDuration contains the vote duration bounds that override the vote
+// duration plugin settings.
+type voteDuration struct {
+	Min uint32 `json:"min"` // In blocks
+	Max uint32 `json:"max"` // In blocks
+}
+
+// loadVoteDuration loads the vote duration bounds from the provided data
+// directory. Nil is returned if the bounds have not been set.
+func loadVoteDuration(dataDir string) (*voteDuration, error) {
+	fp := filepath.Join(dataDir, voteDurationFilename)
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var vd voteDuration
+	err = json.Unmarshal(b, &vd)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", fp, err)
+	}
+	return &vd, nil
+}
+
+// saveVoteDuration saves the vote duration bounds to the provided data
+// directory. The bounds are written to a temp file that is then renamed so
+// that a partially written file is never loaded.
+func saveVoteDuration(dataDir string, vd voteDuration) error {
+	b, err := json.Marshal(vd)
+	if err != nil {
+		return err
+	}
+	fp := filepath.Join(dataDir, voteDurationFilename)
+	tmp := fp + ".tmp"
+	err = os.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, fp)
+}
+
+// voteDurationBounds returns the min and max vote duration that are allowed
+// for new votes.
+func (p *ticketVotePlugin) voteDurationBounds() (uint32, uint32) {
+	p.voteDurationMtx.RLock()
+	defer p.voteDurationMtx.RUnlock()
+
+	return p.voteDurationMin, p.voteDurationMax
+}
+
+// cmdSetVoteDuration sets the vote duration bounds.
+func (p *ticketVotePlugin) cmdSetVoteDuration(payload string) (string, error) {
+	var svd ticketvote.SetVoteDuration
+	err := json.Unmarshal([]byte(payload), &svd)
+	if err != nil {
+		return "", err
+	}
+
+	// Verify the bounds
+	switch {
+	case svd.Min == 0:
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteDurationInvalid),
+			ErrorContext: "min duration must be greater than zero",
+		}
+	case svd.Max < svd.Min:
+		return "", backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteDurationInvalid),
+			ErrorContext: fmt.Sprintf("max duration %v is less than min "+
+				"duration %v", svd.Max, svd.Min),
+		}
+	}
+
+	// Persist the bounds before applying them so that the bounds that
+	// are in effect always match the bounds on disk.
+	p.voteDurationMtx.Lock()
+	defer p.voteDurationMtx.Unlock()
+
+	vd := voteDuration{
+		Min: svd.Min,
+		Max: svd.Max,
+	}
+	err = saveVoteDuration(p.dataDir, vd)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("Vote duration bounds updated from %v-%v to %v-%v blocks",
+		p.voteDurationMin, p.voteDurationMax, vd.Min, vd.Max)
+
+	p.voteDurationMin = vd.Min
+	p.voteDurationMax = vd.Max
+
+	// Prepare reply
+	reply, err := json.Marshal(ticketvote.SetVoteDurationReply{
+		Min: vd.Min,
+		Max: vd.Max,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
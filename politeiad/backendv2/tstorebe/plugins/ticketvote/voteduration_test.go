@@ -0,0 +1,79 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestCmdSetVoteDuration(t *testing.T) {
+	p := &ticketVotePlugin{
+		dataDir:         t.TempDir(),
+		voteDurationMin: 10,
+		voteDurationMax: 100,
+	}
+
+	// No bounds have been saved yet
+	vd, err := loadVoteDuration(p.dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vd != nil {
+		t.Fatalf("got vote duration %+v, want nil", vd)
+	}
+
+	var tests = []struct {
+		name     string
+		min, max uint32
+		wantErr  bool
+	}{
+		{"zero min", 0, 10, true},
+		{"max less than min", 20, 10, true},
+		{"min equals max", 20, 20, false},
+		{"valid", 30, 300, false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			b, err := json.Marshal(ticketvote.SetVoteDuration{
+				Min: v.min,
+				Max: v.max,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.cmdSetVoteDuration(string(b))
+			if v.wantErr {
+				var pe backend.PluginError
+				if !errors.As(err, &pe) || pe.ErrorCode !=
+					uint32(ticketvote.ErrorCodeVoteDurationInvalid) {
+					t.Fatalf("got error %v, want vote duration invalid", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			min, max := p.voteDurationBounds()
+			if min != v.min || max != v.max {
+				t.Errorf("got bounds %v-%v, want %v-%v", min, max,
+					v.min, v.max)
+			}
+		})
+	}
+
+	// The last bounds should have been persisted
+	vd, err = loadVoteDuration(p.dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vd == nil || vd.Min != 30 || vd.Max != 300 {
+		t.Errorf("got persisted vote duration %+v, want 30-300", vd)
+	}
+}
@@ -29,6 +29,15 @@ func (p *usermdPlugin) hookNewRecordPre(payload string) error {
 		return err
 	}
 
+	// Provenance metadata can only be added by migration tooling
+	if provenanceStream(nr.Metadata) != nil {
+		return backend.PluginError{
+			PluginID:     usermd.PluginID,
+			ErrorCode:    uint32(usermd.ErrorCodeProvenanceInvalid),
+			ErrorContext: "provenance cannot be added to new records",
+		}
+	}
+
 	return userMetadataVerify(nr.Metadata, nr.Files)
 }
 
@@ -90,7 +99,7 @@ func (p *usermdPlugin) hookEditRecordPre(payload string) error {
 		}
 	}
 
-	return nil
+	return provenancePreventUpdates(er.Record.Metadata, er.Metadata)
 }
 
 // hookEditRecordPre adds plugin specific validation onto the tstore backend
@@ -102,8 +111,13 @@ func (p *usermdPlugin) hookEditMetadataPre(payload string) error {
 		return err
 	}
 
-	// User metadata should not change on metadata updates
-	return userMetadataPreventUpdates(em.Record.Metadata, em.Metadata)
+	// User metadata and provenance should not change on metadata
+	// updates.
+	err = userMetadataPreventUpdates(em.Record.Metadata, em.Metadata)
+	if err != nil {
+		return err
+	}
+	return provenancePreventUpdates(em.Record.Metadata, em.Metadata)
 }
 
 // hookSetStatusRecordPre adds plugin specific validation onto the tstore
@@ -115,11 +129,16 @@ func (p *usermdPlugin) hookSetRecordStatusPre(payload string) error {
 		return err
 	}
 
-	// User metadata should not change on status changes
+	// User metadata and provenance should not change on status
+	// changes.
 	err = userMetadataPreventUpdates(srs.Record.Metadata, srs.Metadata)
 	if err != nil {
 		return err
 	}
+	err = provenancePreventUpdates(srs.Record.Metadata, srs.Metadata)
+	if err != nil {
+		return err
+	}
 
 	// Verify status change metadata
 	err = statusChangeMetadataVerify(srs.RecordMetadata, srs.Metadata)
@@ -261,6 +280,42 @@ func userMetadataPreventUpdates(current, update []backend.MetadataStream) error
 	return nil
 }
 
+// provenanceStream returns the Provenance metadata stream from the provided
+// backend metadata streams. If a Provenance stream is not found, nil is
+// returned.
+func provenanceStream(metadata []backend.MetadataStream) *backend.MetadataStream {
+	for _, v := range metadata {
+		if v.PluginID == usermd.PluginID &&
+			v.StreamID == usermd.StreamIDProvenance {
+			ms := v
+			return &ms
+		}
+	}
+	return nil
+}
+
+// provenancePreventUpdates errors if the Provenance is being added, changed or
+// removed. The provenance is written once by the migration tooling and must
+// remain unchanged for the lifetime of the record.
+func provenancePreventUpdates(current, update []backend.MetadataStream) error {
+	var (
+		c = provenanceStream(current)
+		u = provenanceStream(update)
+	)
+	switch {
+	case c == nil && u == nil:
+		// Not a migrated record
+		return nil
+	case c == nil || u == nil || c.Payload != u.Payload:
+		return backend.PluginError{
+			PluginID:     usermd.PluginID,
+			ErrorCode:    uint32(usermd.ErrorCodeProvenanceInvalid),
+			ErrorContext: "provenance cannot change",
+		}
+	}
+	return nil
+}
+
 // statusChangesDecode decodes and returns the StatusChangeMetadata from the
 // metadata streams if one is present.
 func statusChangesDecode(metadata []backend.MetadataStream) ([]usermd.StatusChangeMetadata, error) {
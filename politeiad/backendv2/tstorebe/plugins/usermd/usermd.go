@@ -141,7 +141,12 @@ func (p *usermdPlugin) Fsck(tokens [][]byte) error {
 	// Number of records which were added to the user cache.
 	var c int64
 
-	for _, token := range tokens {
+	for i, token := range tokens {
+		// Log progress every 50 records
+		if i%50 == 0 {
+			log.Infof("usermd fsck progress %v/%v", i, len(tokens))
+		}
+
 		r, err := p.tstore.RecordPartial(token, 0, nil, true)
 		if err != nil {
 			return err
@@ -24,6 +24,10 @@ var (
 const (
 	// DataTypeStructure describes a blob entry that contains a structure.
 	DataTypeStructure = "struct"
+
+	// DataTypeBinary describes a blob entry that contains binary
+	// encoded data.
+	DataTypeBinary = "binary"
 )
 
 // DataDescriptor provides hints about a data blob. In practice we JSON encode
@@ -186,8 +186,8 @@ func (t *testClient) LeavesAll(treeID int64) ([]*trillian.LogLeaf, error) {
 	leavesCopy := make([]*trillian.LogLeaf, 0, len(leaves))
 	for _, v := range leaves {
 		var (
-			leafValue []byte
-			extraData []byte
+			leafValue = make([]byte, len(v.LeafValue))
+			extraData = make([]byte, len(v.ExtraData))
 		)
 		copy(leafValue, v.LeafValue)
 		copy(extraData, v.ExtraData)
@@ -0,0 +1,354 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/google/uuid"
+)
+
+const (
+	// asyncHookKeyPrefix is the key-value store key prefix for async
+	// hook jobs. The full key is the prefix followed by the job ID.
+	asyncHookKeyPrefix = "asynchook-"
+
+	// asyncHooksIndexKey is the key-value store key for the async
+	// hooks index. The index contains the IDs of all async hook jobs
+	// that have not finished executing. The key-value store does not
+	// support key iteration so this index is required in order to
+	// reload pending jobs on startup.
+	asyncHooksIndexKey = "asynchooks-index"
+
+	// asyncHookAttemptsMax is the maximum number of times an async
+	// hook will be attempted before it is dropped.
+	asyncHookAttemptsMax = 5
+
+	// asyncHookRetryDelay is the delay before a failed async hook is
+	// retried. The delay is multiplied by the number of attempts that
+	// have already been made.
+	asyncHookRetryDelay = 5 * time.Second
+)
+
+// asyncHookJob is a plugin hook that has been queued for async execution.
+type asyncHookJob struct {
+	ID       string        `json:"id"`
+	PluginID string        `json:"pluginid"`
+	Hook     plugins.HookT `json:"hook"`
+	Payload  string        `json:"payload"`
+	Attempts uint32        `json:"attempts"`
+}
+
+// asyncHookExecFunc executes an async hook job.
+type asyncHookExecFunc func(j asyncHookJob) error
+
+// asyncHookQueue is a persistent work queue of async plugin hooks. Jobs are
+// saved to the key-value store before they are executed so that they survive
+// restarts. A single worker goroutine executes the jobs in the order that they
+// were added. Failed jobs are retried after a delay.
+type asyncHookQueue struct {
+	sync.Mutex
+	store store.BlobKV
+	exec  asyncHookExecFunc
+	index []string // IDs of all pending jobs
+	ready []string // IDs of jobs that are ready to be executed
+
+	// held contains the IDs of jobs that were loaded from the key-value
+	// store on startup. These jobs are held until the plugin that they
+	// belong to has been setup.
+	held map[string][]string // [pluginID][]jobID
+
+	notify chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// asyncHookKey returns the key-value store key for an async hook job.
+func asyncHookKey(id string) string {
+	return asyncHookKeyPrefix + id
+}
+
+// newAsyncHookQueue returns a new asyncHookQueue. The queue does not execute
+// any jobs until start is called.
+func newAsyncHookQueue(s store.BlobKV, exec asyncHookExecFunc) *asyncHookQueue {
+	return &asyncHookQueue{
+		store:  s,
+		exec:   exec,
+		index:  make([]string, 0),
+		ready:  make([]string, 0),
+		held:   make(map[string][]string),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// start loads any pending jobs from the key-value store and launches the
+// worker goroutine. Pending jobs are held until release is called for the
+// plugin that they belong to.
+func (q *asyncHookQueue) start() error {
+	index, err := q.indexLoad()
+	if err != nil {
+		return err
+	}
+	held := make(map[string][]string, len(index))
+	for _, id := range index {
+		j, err := q.job(id)
+		if err != nil {
+			return fmt.Errorf("job %v: %v", id, err)
+		}
+		held[j.PluginID] = append(held[j.PluginID], id)
+	}
+
+	q.Lock()
+	q.index = index
+	q.held = held
+	q.Unlock()
+
+	if len(index) > 0 {
+		log.Infof("%v pending async hooks loaded", len(index))
+	}
+
+	q.wg.Add(1)
+	go q.run()
+
+	return nil
+}
+
+// release adds any pending jobs that were loaded on startup for the provided
+// plugin to the ready queue.
+func (q *asyncHookQueue) release(pluginID string) {
+	q.Lock()
+	ids := q.held[pluginID]
+	delete(q.held, pluginID)
+	q.ready = append(q.ready, ids...)
+	q.Unlock()
+
+	if len(ids) > 0 {
+		q.signal()
+	}
+}
+
+// stop stops the worker goroutine. Pending jobs remain in the key-value store
+// and are executed the next time the queue is started.
+func (q *asyncHookQueue) stop() {
+	close(q.done)
+	q.wg.Wait()
+}
+
+// add adds a job to the queue. The job is saved to the key-value store before
+// this function returns.
+func (q *asyncHookQueue) add(pluginID string, h plugins.HookT, payload string) error {
+	j := asyncHookJob{
+		ID:       uuid.New().String(),
+		PluginID: pluginID,
+		Hook:     h,
+		Payload:  payload,
+	}
+	bj, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	q.Lock()
+	index := append(append([]string{}, q.index...), j.ID)
+	bi, err := json.Marshal(index)
+	if err != nil {
+		q.Unlock()
+		return err
+	}
+
+	// The job and the updated index are saved atomically
+	kv := map[string][]byte{
+		asyncHookKey(j.ID): bj,
+		asyncHooksIndexKey: bi,
+	}
+	err = q.store.Put(kv, false)
+	if err != nil {
+		q.Unlock()
+		return err
+	}
+	q.index = index
+	q.ready = append(q.ready, j.ID)
+	q.Unlock()
+
+	q.signal()
+
+	return nil
+}
+
+// signal wakes up the worker goroutine.
+func (q *asyncHookQueue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+		// The worker has already been signaled
+	}
+}
+
+// next pops the next ready job ID off of the queue.
+func (q *asyncHookQueue) next() (string, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.ready) == 0 {
+		return "", false
+	}
+	id := q.ready[0]
+	q.ready = q.ready[1:]
+	return id, true
+}
+
+// retry adds a job ID back onto the ready queue.
+func (q *asyncHookQueue) retry(id string) {
+	q.Lock()
+	q.ready = append(q.ready, id)
+	q.Unlock()
+
+	q.signal()
+}
+
+// run is the worker goroutine. It executes ready jobs until the queue is
+// stopped.
+func (q *asyncHookQueue) run() {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-q.notify:
+		}
+
+		for {
+			select {
+			case <-q.done:
+				return
+			default:
+			}
+
+			id, ok := q.next()
+			if !ok {
+				break
+			}
+			q.process(id)
+		}
+	}
+}
+
+// process executes a single job. Successful jobs are removed from the queue.
+// Failed jobs are retried after a delay until the max number of attempts has
+// been reached.
+func (q *asyncHookQueue) process(id string) {
+	j, err := q.job(id)
+	if err != nil {
+		log.Errorf("async hook %v: %v", id, err)
+		q.remove(id)
+		return
+	}
+
+	err = q.exec(*j)
+	if err == nil {
+		q.remove(id)
+		return
+	}
+
+	j.Attempts++
+	if j.Attempts >= asyncHookAttemptsMax {
+		log.Criticalf("%v async hook %v failed after %v attempts: %v: %v",
+			j.PluginID, plugins.Hooks[j.Hook], j.Attempts, err, j.Payload)
+		q.remove(id)
+		return
+	}
+
+	log.Errorf("%v async hook %v attempt %v: %v",
+		j.PluginID, plugins.Hooks[j.Hook], j.Attempts, err)
+
+	bj, err := json.Marshal(j)
+	if err != nil {
+		log.Errorf("async hook %v: %v", id, err)
+		return
+	}
+	err = q.store.Put(map[string][]byte{asyncHookKey(id): bj}, false)
+	if err != nil {
+		log.Errorf("async hook %v: %v", id, err)
+	}
+
+	delay := asyncHookRetryDelay * time.Duration(j.Attempts)
+	time.AfterFunc(delay, func() {
+		q.retry(id)
+	})
+}
+
+// job returns the specified job from the key-value store.
+func (q *asyncHookQueue) job(id string) (*asyncHookJob, error) {
+	key := asyncHookKey(id)
+	blobs, err := q.store.Get([]string{key})
+	if err != nil {
+		return nil, err
+	}
+	b, ok := blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("job not found")
+	}
+	var j asyncHookJob
+	err = json.Unmarshal(b, &j)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// remove removes a job from the queue and deletes it from the key-value store.
+func (q *asyncHookQueue) remove(id string) {
+	q.Lock()
+	defer q.Unlock()
+
+	index := make([]string, 0, len(q.index))
+	for _, v := range q.index {
+		if v != id {
+			index = append(index, v)
+		}
+	}
+	bi, err := json.Marshal(index)
+	if err != nil {
+		log.Errorf("async hook remove %v: %v", id, err)
+		return
+	}
+	err = q.store.Put(map[string][]byte{asyncHooksIndexKey: bi}, false)
+	if err != nil {
+		log.Errorf("async hook remove %v: %v", id, err)
+		return
+	}
+	q.index = index
+
+	err = q.store.Del([]string{asyncHookKey(id)})
+	if err != nil {
+		log.Errorf("async hook remove %v: %v", id, err)
+	}
+}
+
+// indexLoad loads the async hooks index from the key-value store.
+func (q *asyncHookQueue) indexLoad() ([]string, error) {
+	blobs, err := q.store.Get([]string{asyncHooksIndexKey})
+	if err != nil {
+		return nil, err
+	}
+	b, ok := blobs[asyncHooksIndexKey]
+	if !ok {
+		// Index doesn't exist yet
+		return []string{}, nil
+	}
+	var index []string
+	err = json.Unmarshal(b, &index)
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/localdb"
+)
+
+func TestAsyncHookQueue(t *testing.T) {
+	// Setup key-value store
+	dataDir, err := os.MkdirTemp("", "asynchooks.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+	kv, err := localdb.New(dataDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kv.Close()
+
+	// Add a job to a queue that has not been started. The job should
+	// be persisted but not executed.
+	q := newAsyncHookQueue(kv, func(j asyncHookJob) error {
+		t.Fatalf("unexpected exec %v", j.ID)
+		return nil
+	})
+	err = q.add("pi", plugins.HookTypeSetRecordStatusPost, "payload")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Start a new queue. The pending job should be reloaded and held
+	// until the plugin is released.
+	executed := make(chan asyncHookJob, 1)
+	q = newAsyncHookQueue(kv, func(j asyncHookJob) error {
+		executed <- j
+		return nil
+	})
+	err = q.start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.stop()
+
+	select {
+	case j := <-executed:
+		t.Fatalf("job executed before release: %v", j.ID)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	q.release("pi")
+
+	select {
+	case j := <-executed:
+		if j.PluginID != "pi" ||
+			j.Hook != plugins.HookTypeSetRecordStatusPost ||
+			j.Payload != "payload" {
+			t.Fatalf("unexpected job %+v", j)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job not executed")
+	}
+
+	// Verify the job was removed from the index once executed
+	var index []string
+	for i := 0; i < 50; i++ {
+		index, err = q.indexLoad()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(index) == 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(index) != 0 {
+		t.Fatalf("got index %v, want empty", index)
+	}
+}
@@ -35,6 +35,17 @@ const (
 type plugin struct {
 	id     string
 	client plugins.PluginClient
+
+	// asyncHooks contains the hook types that the plugin has
+	// registered for async execution.
+	asyncHooks map[plugins.HookT]struct{}
+}
+
+// isAsyncHook returns whether the plugin has registered the provided hook
+// type for async execution.
+func (p plugin) isAsyncHook(h plugins.HookT) bool {
+	_, ok := p.asyncHooks[h]
+	return ok
 }
 
 // plugin returns the specified plugin. Only plugins that have been registered
@@ -79,20 +90,22 @@ func (t *Tstore) PluginRegister(b backend.Backend, p backend.Plugin) error {
 	switch p.ID {
 	case cmplugin.PluginID:
 		tstoreClient := NewTstoreClient(t, cmplugin.PluginID)
-		pluginClient, err = comments.New(tstoreClient,
+		pluginClient, err = comments.New(b, tstoreClient,
 			p.Settings, dataDir, p.Identity)
 		if err != nil {
 			return err
 		}
 	case ddplugin.PluginID:
-		pluginClient, err = dcrdata.New(p.Settings, t.activeNetParams)
+		tstoreClient := NewTstoreClient(t, ddplugin.PluginID)
+		pluginClient, err = dcrdata.New(tstoreClient, p.Settings,
+			t.activeNetParams)
 		if err != nil {
 			return err
 		}
 	case piplugin.PluginID:
 		tstoreClient := NewTstoreClient(t, piplugin.PluginID)
 		pluginClient, err = pi.New(b, tstoreClient,
-			p.Settings, dataDir, p.Identity)
+			p.Settings, dataDir, p.Identity, t.activeNetParams)
 		if err != nil {
 			return err
 		}
@@ -113,12 +126,25 @@ func (t *Tstore) PluginRegister(b backend.Backend, p backend.Plugin) error {
 		return backend.ErrPluginIDInvalid
 	}
 
+	// Register any async hooks
+	asyncHooks := make(map[plugins.HookT]struct{})
+	if ac, ok := pluginClient.(plugins.AsyncHookClient); ok {
+		for _, h := range ac.AsyncHooks() {
+			if !plugins.IsPostHook(h) {
+				return fmt.Errorf("plugin %v: async hook %v is not a post hook",
+					p.ID, plugins.Hooks[h])
+			}
+			asyncHooks[h] = struct{}{}
+		}
+	}
+
 	t.Lock()
 	defer t.Unlock()
 
 	t.plugins[p.ID] = plugin{
-		id:     p.ID,
-		client: pluginClient,
+		id:         p.ID,
+		client:     pluginClient,
+		asyncHooks: asyncHooks,
 	}
 
 	return nil
@@ -133,7 +159,18 @@ func (t *Tstore) PluginSetup(pluginID string) error {
 		return backend.ErrPluginIDInvalid
 	}
 
-	return p.client.Setup()
+	err := p.client.Setup()
+	if err != nil {
+		return err
+	}
+
+	// Release any async hooks that were pending from a previous run
+	// now that the plugin is able to execute them.
+	if t.asyncHooks != nil {
+		t.asyncHooks.release(pluginID)
+	}
+
+	return nil
 }
 
 // PluginHookPre executes a tstore backend pre hook. Pre hooks are hooks that
@@ -162,6 +199,9 @@ func (t *Tstore) PluginHookPre(h plugins.HookT, payload string) error {
 // PluginHookPost executes a tstore backend post hook. Post hooks are hooks that
 // are executed after the tstore backend successfully writes data to disk.
 // These hooks give plugins the opportunity to cache data from the write.
+//
+// Hooks that a plugin has registered as async are added to the async hook
+// queue instead of being executed inline.
 func (t *Tstore) PluginHookPost(h plugins.HookT, payload string) {
 	log.Tracef("PluginHookPost: %v", plugins.Hooks[h])
 
@@ -172,7 +212,23 @@ func (t *Tstore) PluginHookPost(h plugins.HookT, payload string) {
 			log.Errorf("%v PluginHookPost: plugin not found %v", v)
 			continue
 		}
-		err := p.client.Hook(h, payload)
+		if p.isAsyncHook(h) && t.asyncHooks != nil {
+			err := t.asyncHooks.add(v, h, payload)
+			if err != nil {
+				log.Criticalf("%v PluginHookPost %v add async hook: %v: %v",
+					v, plugins.Hooks[h], err, payload)
+			}
+			continue
+		}
+		var err error
+		if ac, ok := p.client.(plugins.AsyncHookClient); ok && p.isAsyncHook(h) {
+			// The async hook queue is not running. Execute the async
+			// hook inline so that it is not dropped. Plugins only
+			// handle their async hooks in AsyncHook.
+			err = ac.AsyncHook(h, payload)
+		} else {
+			err = p.client.Hook(h, payload)
+		}
 		if err != nil {
 			// This is the post plugin hook so the data has already been
 			// saved to tstore. We do not have the ability to unwind. Log
@@ -184,6 +240,24 @@ func (t *Tstore) PluginHookPost(h plugins.HookT, payload string) {
 	}
 }
 
+// asyncHookExec executes an async hook job that was pulled off of the async
+// hook queue.
+func (t *Tstore) asyncHookExec(j asyncHookJob) error {
+	log.Tracef("asyncHookExec: %v %v", j.PluginID, plugins.Hooks[j.Hook])
+
+	p, ok := t.plugin(j.PluginID)
+	if !ok {
+		return fmt.Errorf("plugin not found %v", j.PluginID)
+	}
+	ac, ok := p.client.(plugins.AsyncHookClient)
+	if !ok {
+		return fmt.Errorf("plugin %v does not support async hooks",
+			j.PluginID)
+	}
+
+	return ac.AsyncHook(j.Hook, j.Payload)
+}
+
 // PluginRead executes a read-only plugin command.
 func (t *Tstore) PluginRead(token []byte, pluginID, cmd, payload string) (string, error) {
 	log.Tracef("PluginRead: %x %v %v", token, pluginID, cmd)
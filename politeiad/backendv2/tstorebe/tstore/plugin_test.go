@@ -0,0 +1,72 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+)
+
+// hookClient is a plugin client that records the hooks that it executes.
+type hookClient struct {
+	hooks      []plugins.HookT
+	asyncHooks []plugins.HookT
+}
+
+func (c *hookClient) Setup() error { return nil }
+
+func (c *hookClient) Cmd(token []byte, cmd, payload string) (string, error) {
+	return "", nil
+}
+
+func (c *hookClient) Hook(h plugins.HookT, payload string) error {
+	c.hooks = append(c.hooks, h)
+	return nil
+}
+
+func (c *hookClient) Fsck(tokens [][]byte) error { return nil }
+
+func (c *hookClient) Settings() []backend.PluginSetting { return nil }
+
+func (c *hookClient) AsyncHooks() []plugins.HookT {
+	return []plugins.HookT{plugins.HookTypeSetRecordStatusPost}
+}
+
+func (c *hookClient) AsyncHook(h plugins.HookT, payload string) error {
+	c.asyncHooks = append(c.asyncHooks, h)
+	return nil
+}
+
+func TestPluginHookPostNoAsyncQueue(t *testing.T) {
+	c := &hookClient{}
+	tstore := &Tstore{
+		plugins: map[string]plugin{
+			"test": {
+				id:     "test",
+				client: c,
+				asyncHooks: map[plugins.HookT]struct{}{
+					plugins.HookTypeSetRecordStatusPost: {},
+				},
+			},
+		},
+	}
+
+	// An async hook must be executed inline when the async hook queue
+	// is not running. A sync hook must be passed to Hook.
+	tstore.PluginHookPost(plugins.HookTypeSetRecordStatusPost, "")
+	tstore.PluginHookPost(plugins.HookTypeNewRecordPost, "")
+
+	if len(c.asyncHooks) != 1 ||
+		c.asyncHooks[0] != plugins.HookTypeSetRecordStatusPost {
+		t.Errorf("got async hooks %v, want [%v]", c.asyncHooks,
+			plugins.HookTypeSetRecordStatusPost)
+	}
+	if len(c.hooks) != 1 || c.hooks[0] != plugins.HookTypeNewRecordPost {
+		t.Errorf("got hooks %v, want [%v]", c.hooks,
+			plugins.HookTypeNewRecordPost)
+	}
+}
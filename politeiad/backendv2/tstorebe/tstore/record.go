@@ -32,30 +32,41 @@ const (
 // RecordNew creates a new record in the tstore and returns the record token
 // that serves as the unique identifier for the record. Creating a new record
 // means creating a tlog tree for the record. Nothing is saved to the tree yet.
+//
+// A new tree is created for each attempt when the short token of the created
+// tree collides with the short token of an existing record. An error is
+// returned if a unique short token is not found within the configured number
+// of token retries.
 func (t *Tstore) RecordNew() ([]byte, error) {
-	var token []byte
-	for retries := 0; retries < 10; retries++ {
+	for attempt := 1; attempt <= t.tokenRetries; attempt++ {
 		tree, _, err := t.tlog.TreeNew()
 		if err != nil {
 			return nil, err
 		}
-		token = tokenFromTreeID(tree.TreeId)
+		token := tokenFromTreeID(tree.TreeId)
 
 		// Check for shortened token collisions
 		if t.tokenCollision(token) {
 			// This is a collision. We cannot use this tree. Try again.
-			log.Infof("Token collision %x, creating new token", token)
+			log.Infof("Token collision %x (attempt %v/%v), creating new "+
+				"token", token, attempt, t.tokenRetries)
 			continue
 		}
 
 		// We've found a valid token. Update the tokens cache. This must
 		// be done even if the record creation fails since the tree will
 		// still exist.
-		t.tokenAdd(token)
-		break
+		err = t.tokenAdd(token)
+		if err != nil {
+			return nil, err
+		}
+
+		return token, nil
 	}
 
-	return token, nil
+	return nil, fmt.Errorf("unable to create a token with a unique short "+
+		"token after %v attempts; the short token length may need to be "+
+		"increased", t.tokenRetries)
 }
 
 // recordSave saves the provided record content to the kv store, appends a leaf
@@ -886,6 +897,33 @@ func (t *Tstore) RecordState(token []byte) (backend.StateT, error) {
 	return backend.StateUnvetted, nil
 }
 
+// RecordFrozen returns whether a record has been frozen. A record is frozen
+// when it can no longer be updated, such as when it is censored or archived.
+// Plugin data can no longer be appended to a frozen record.
+func (t *Tstore) RecordFrozen(token []byte) (bool, error) {
+	log.Tracef("RecordFrozen: %x", token)
+
+	// Read methods are allowed to use short tokens. Lookup the full
+	// length token.
+	var err error
+	token, err = t.fullLengthToken(token)
+	if err != nil {
+		return false, err
+	}
+
+	treeID := treeIDFromToken(token)
+	leaves, err := t.leavesAll(treeID)
+	if err != nil {
+		return false, err
+	}
+	idx, err := t.recordIndexLatest(leaves)
+	if err != nil {
+		return false, err
+	}
+
+	return idx.Frozen, nil
+}
+
 // timestamp returns the timestamp given a tlog tree merkle leaf hash.
 func (t *Tstore) timestamp(treeID int64, merkleLeafHash []byte, leaves []*trillian.LogLeaf) (*backend.Timestamp, error) {
 	// Find the leaf
@@ -33,7 +33,9 @@ func NewTestTstore(t *testing.T, dataDir string) *Tstore {
 	}
 
 	return &Tstore{
-		tlog:  tlog.NewTestClient(t),
-		store: store,
+		tlog:         tlog.NewTestClient(t),
+		store:        store,
+		tokens:       make(map[string][]byte),
+		tokenRetries: 10,
 	}
 }
@@ -5,12 +5,17 @@
 package tstore
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	backend "github.com/decred/politeia/politeiad/backendv2"
@@ -55,6 +60,12 @@ type Tstore struct {
 	dcrtime         *dcrtimeClient
 	cron            *cron.Cron
 	plugins         map[string]plugin // [pluginID]plugin
+	asyncHooks      *asyncHookQueue
+
+	// tokenRetries is the number of times that a new token is created
+	// when the short version of the created token collides with the
+	// short token of an existing record.
+	tokenRetries int
 
 	// droppingAnchor indicates whether tstore is in the process of
 	// dropping an anchor, i.e. timestamping unanchored tlog trees
@@ -88,6 +99,40 @@ func tokenIsFullLength(token []byte) bool {
 	return util.TokenIsFullLength(util.TokenTypeTstore, token)
 }
 
+// TokensByPrefix returns the full length tokens of the records whose hex
+// encoded token starts with the provided prefix. The tokens are sorted
+// lexicographically. This can be used to resolve prefixes that are shorter
+// than the short token length and may match multiple records.
+func (t *Tstore) TokensByPrefix(prefix string) [][]byte {
+	t.RLock()
+	defer t.RUnlock()
+
+	// The prefix can be matched using a single lookup if it contains
+	// the full short token.
+	shortLen := util.ShortTokenLength()
+	if len(prefix) >= shortLen {
+		fullToken, ok := t.tokens[prefix[:shortLen]]
+		if !ok || !strings.HasPrefix(hex.EncodeToString(fullToken), prefix) {
+			return [][]byte{}
+		}
+		return [][]byte{fullToken}
+	}
+
+	// The prefix is shorter than the short token. All short tokens
+	// need to be checked.
+	tokens := make([][]byte, 0, 16)
+	for k, v := range t.tokens {
+		if strings.HasPrefix(k, prefix) {
+			tokens = append(tokens, v)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return bytes.Compare(tokens[i], tokens[j]) < 0
+	})
+
+	return tokens
+}
+
 // tokenCollision returns whether the short version of the provided token
 // already exists. This can be used to prevent collisions when creating new
 // tokens.
@@ -116,8 +161,16 @@ func (t *Tstore) tokenAdd(fullToken []byte) error {
 	}
 
 	t.Lock()
+	defer t.Unlock()
+
+	// The short tokens of the existing records will collide if the
+	// short token length has been decreased.
+	existing, ok := t.tokens[shortToken]
+	if ok && !bytes.Equal(existing, fullToken) {
+		return fmt.Errorf("short token %v collision between %x and %x",
+			shortToken, existing, fullToken)
+	}
 	t.tokens[shortToken] = fullToken
-	t.Unlock()
 
 	log.Tracef("Token cache add: %v", shortToken)
 
@@ -184,10 +237,39 @@ func (t *Tstore) Fsck(allTokens [][]byte) error {
 	return nil
 }
 
+// Reindex rebuilds the caches and indexes of a single plugin. This is done
+// by running the plugin fsck, which rebuilds the plugin caches from the tlog
+// data. Unlike Fsck, the trees are not anchored or frozen and the fscks of
+// the other plugins are not run.
+func (t *Tstore) Reindex(pluginID string, allTokens [][]byte) error {
+	p, ok := t.plugin(pluginID)
+	if !ok {
+		return backend.ErrPluginIDInvalid
+	}
+
+	log.Infof("Starting %v plugin reindex", pluginID)
+
+	start := time.Now()
+	err := p.client.Fsck(allTokens)
+	if err != nil {
+		return errors.Errorf("plugin %v reindex: %v", pluginID, err)
+	}
+
+	log.Infof("%v plugin reindex complete in %v", pluginID,
+		time.Since(start).Round(time.Second))
+
+	return nil
+}
+
 // Close performs cleanup of the tstore.
 func (t *Tstore) Close() {
 	log.Tracef("Close")
 
+	// Stop the async hook worker
+	if t.asyncHooks != nil {
+		t.asyncHooks.stop()
+	}
+
 	// Close connections
 	t.tlog.Close()
 	t.store.Close()
@@ -205,14 +287,28 @@ func (t *Tstore) Setup() error {
 	log.Infof("%v records in the tstore", len(tokens))
 
 	for _, v := range tokens {
-		t.tokenAdd(v)
+		err := t.tokenAdd(v)
+		if err != nil {
+			return fmt.Errorf("the short token length of %v characters "+
+				"is not unique for the existing records: %v",
+				util.ShortTokenLength(), err)
+		}
+	}
+
+	log.Infof("Starting async plugin hook worker")
+
+	err = t.asyncHooks.start()
+	if err != nil {
+		return fmt.Errorf("start async hooks: %v", err)
 	}
 
 	return nil
 }
 
-// New returns a new tstore instance.
-func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, dbHost, dbPass, dcrtimeHost, dcrtimeCert string) (*Tstore, error) {
+// New returns a new tstore instance. The token retries is the number of
+// times that a new record token is created when the short token of the new
+// token collides with the short token of an existing record.
+func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, dbHost, dbPass, dcrtimeHost, dcrtimeCert string, tokenRetries int) (*Tstore, error) {
 	// Setup datadir for this tstore instance
 	dataDir = filepath.Join(dataDir)
 	err := os.MkdirAll(dataDir, 0700)
@@ -259,7 +355,9 @@ func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, dbHost, dbPass,
 		cron:            cron.New(),
 		plugins:         make(map[string]plugin),
 		tokens:          make(map[string][]byte),
+		tokenRetries:    tokenRetries,
 	}
+	t.asyncHooks = newAsyncHookQueue(kvstore, t.asyncHookExec)
 
 	// Launch cron
 	log.Infof("Launch cron anchor job")
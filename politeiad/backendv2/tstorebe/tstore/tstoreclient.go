@@ -48,30 +48,44 @@ func NewTstoreClient(tstore *Tstore, pluginID string) plugins.TstoreClient {
 func (t *tstoreClient) BlobSave(token []byte, be store.BlobEntry) error {
 	log.Tracef("BlobSave: %x", token)
 
+	errs, err := t.BlobsSave(token, []store.BlobEntry{be})
+	if err != nil {
+		return err
+	}
+	return errs[0]
+}
+
+// BlobsSave saves a batch of BlobEntry to the tstore instance. The blobs are
+// appended to the trillian tree using a single queued leaves call. The blob
+// entries will be encrypted prior to being written to disk if the record is
+// unvetted.
+//
+// The returned errors correspond to the provided blob entries by index. A nil
+// error means that the blob was saved successfully. A backend
+// ErrDuplicatePayload is returned for blobs that already exist in the tree.
+// The error return value is only used for errors that apply to the entire
+// batch.
+//
+// The blobs of a batch are not guaranteed to be appended to the tree in the
+// order in which they are provided.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (t *tstoreClient) BlobsSave(token []byte, entries []store.BlobEntry) ([]error, error) {
+	log.Tracef("BlobsSave: %x %v", token, len(entries))
+
 	// Verify tree is not frozen
 	treeID := treeIDFromToken(token)
 	leaves, err := t.tstore.leavesAll(treeID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	idx, err := t.tstore.recordIndexLatest(leaves)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if idx.Frozen {
 		// The tree is frozen. The record is locked.
-		return backend.ErrRecordLocked
-	}
-
-	// Parse the data descriptor
-	b, err := base64.StdEncoding.DecodeString(be.DataHint)
-	if err != nil {
-		return err
-	}
-	var dd store.DataDescriptor
-	err = json.Unmarshal(b, &dd)
-	if err != nil {
-		return err
+		return nil, backend.ErrRecordLocked
 	}
 
 	// Only vetted data should be saved plain text
@@ -87,55 +101,76 @@ func (t *tstoreClient) BlobSave(token []byte, be store.BlobEntry) error {
 		panic(fmt.Sprintf("invalid record state %v %v", treeID, idx.State))
 	}
 
-	// Prepare blob and digest
-	digest, err := hex.DecodeString(be.Digest)
-	if err != nil {
-		return err
-	}
-	blob, err := store.Blobify(be)
-	if err != nil {
-		return err
-	}
-	key := storeKeyNew(encrypt)
-	kv := map[string][]byte{key: blob}
+	// Prepare blobs and log leaves
+	var (
+		kv = make(map[string][]byte, len(entries))
 
-	log.Debugf("Saving plugin data blob %v", dd.Descriptor)
+		appendLeaves = make([]*trillian.LogLeaf, 0, len(entries))
+	)
+	for _, be := range entries {
+		// Parse the data descriptor
+		b, err := base64.StdEncoding.DecodeString(be.DataHint)
+		if err != nil {
+			return nil, err
+		}
+		var dd store.DataDescriptor
+		err = json.Unmarshal(b, &dd)
+		if err != nil {
+			return nil, err
+		}
 
-	// Save blob to store
-	err = t.tstore.store.Put(kv, encrypt)
-	if err != nil {
-		return fmt.Errorf("store Put: %v", err)
+		// Prepare blob and digest
+		digest, err := hex.DecodeString(be.Digest)
+		if err != nil {
+			return nil, err
+		}
+		blob, err := store.Blobify(be)
+		if err != nil {
+			return nil, err
+		}
+		key := storeKeyNew(encrypt)
+		kv[key] = blob
+
+		// Prepare log leaf
+		extraData, err := extraDataEncode(key, dd.Descriptor, idx.State)
+		if err != nil {
+			return nil, err
+		}
+		appendLeaves = append(appendLeaves,
+			tlog.NewLogLeaf(digest, extraData))
+
+		log.Debugf("Saving plugin data blob %v", dd.Descriptor)
 	}
 
-	// Prepare log leaf
-	extraData, err := extraDataEncode(key, dd.Descriptor, idx.State)
+	// Save blobs to store
+	err = t.tstore.store.Put(kv, encrypt)
 	if err != nil {
-		return err
-	}
-	leaves = []*trillian.LogLeaf{
-		tlog.NewLogLeaf(digest, extraData),
+		return nil, fmt.Errorf("store Put: %v", err)
 	}
 
-	// Append log leaf to trillian tree
-	queued, _, err := t.tstore.tlog.LeavesAppend(treeID, leaves)
+	// Append log leaves to trillian tree
+	queued, _, err := t.tstore.tlog.LeavesAppend(treeID, appendLeaves)
 	if err != nil {
-		return fmt.Errorf("LeavesAppend: %v", err)
-	}
-	if len(queued) != 1 {
-		return fmt.Errorf("wrong queued leaves count: got %v, want 1",
-			len(queued))
-	}
-	c := codes.Code(queued[0].QueuedLeaf.GetStatus().GetCode())
-	switch c {
-	case codes.OK:
-		// This is ok; continue
-	case codes.AlreadyExists:
-		return backend.ErrDuplicatePayload
-	default:
-		return fmt.Errorf("queued leaf error: %v", c)
+		return nil, fmt.Errorf("LeavesAppend: %v", err)
+	}
+	if len(queued) != len(appendLeaves) {
+		return nil, fmt.Errorf("wrong queued leaves count: got %v, want %v",
+			len(queued), len(appendLeaves))
+	}
+	errs := make([]error, len(queued))
+	for i, v := range queued {
+		c := codes.Code(v.QueuedLeaf.GetStatus().GetCode())
+		switch c {
+		case codes.OK:
+			// This is ok; continue
+		case codes.AlreadyExists:
+			errs[i] = backend.ErrDuplicatePayload
+		default:
+			errs[i] = fmt.Errorf("queued leaf error: %v", c)
+		}
 	}
 
-	return nil
+	return errs, nil
 }
 
 // BlobsDel deletes the blobs that correspond to the provided digests. Blobs
@@ -477,6 +512,32 @@ func (t *tstoreClient) RecordState(token []byte) (backend.StateT, error) {
 	return t.tstore.RecordState(token)
 }
 
+// RecordFrozen is a wrapper of the tstore RecordFrozen func.
+func (t *tstoreClient) RecordFrozen(token []byte) (bool, error) {
+	return t.tstore.RecordFrozen(token)
+}
+
+// PluginEvent emits a plugin event. The event is passed to all registered
+// plugins using the plugin event hook.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (t *tstoreClient) PluginEvent(token []byte, event, payload string) {
+	log.Tracef("PluginEvent: %v %x %v", t.pluginID, token, event)
+
+	b, err := json.Marshal(plugins.HookPluginEvent{
+		PluginID: t.pluginID,
+		Event:    event,
+		Token:    token,
+		Payload:  payload,
+	})
+	if err != nil {
+		log.Errorf("PluginEvent %v %v: %v", t.pluginID, event, err)
+		return
+	}
+
+	t.tstore.PluginHookPost(plugins.HookTypePluginEvent, string(b))
+}
+
 // leavesForDescriptor returns all leaves that have and extra data descriptor
 // that matches the provided descriptor. If a record is vetted, only vetted
 // leaves will be returned.
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+)
+
+func TestBlobsSave(t *testing.T) {
+	dataDir, err := os.MkdirTemp("", "tstoreclient.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataDir)
+	ts := NewTestTstore(t, dataDir)
+
+	// Create an unvetted record
+	token, err := ts.RecordNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("test")
+	rm := backend.RecordMetadata{
+		Token:     hex.EncodeToString(token),
+		Version:   1,
+		Iteration: 1,
+		State:     backend.StateUnvetted,
+		Status:    backend.StatusUnreviewed,
+	}
+	files := []backend.File{
+		{
+			Name:    "index.md",
+			MIME:    "text/plain; charset=utf-8",
+			Digest:  hex.EncodeToString(util.Digest(payload)),
+			Payload: base64.StdEncoding.EncodeToString(payload),
+		},
+	}
+	err = ts.RecordSave(token, rm, []backend.MetadataStream{}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Save a batch of blobs
+	dataDesc := "test-blob-v1"
+	hint, err := json.Marshal(store.DataDescriptor{
+		Type:       store.DataTypeStructure,
+		Descriptor: dataDesc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make([]store.BlobEntry, 0, 5)
+	for i := 0; i < 5; i++ {
+		data := []byte(fmt.Sprintf(`{"i":%v}`, i))
+		entries = append(entries, store.NewBlobEntry(hint, data))
+	}
+	c := NewTstoreClient(ts, "test")
+	errs, err := c.BlobsSave(token, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != len(entries) {
+		t.Fatalf("got %v errors, want %v", len(errs), len(entries))
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("blob %v: %v", i, err)
+		}
+	}
+
+	// Verify the blobs were saved
+	blobs, err := c.BlobsByDataDesc(token, []string{dataDesc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != len(entries) {
+		t.Errorf("got %v blobs, want %v", len(blobs), len(entries))
+	}
+}
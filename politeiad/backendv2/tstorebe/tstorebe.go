@@ -816,6 +816,16 @@ func (t *tstoreBackend) RecordExists(token []byte) bool {
 	return t.tstore.RecordExists(token)
 }
 
+// TokensByPrefix returns the full length tokens of the records whose hex
+// encoded token starts with the provided prefix.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) TokensByPrefix(prefix string) [][]byte {
+	log.Tracef("TokensByPrefix: %v", prefix)
+
+	return t.tstore.TokensByPrefix(prefix)
+}
+
 // RecordTimestamps returns the timestamps for a record. If no version is
 // provided then timestamps for the most recent version will be returned.
 //
@@ -1122,6 +1132,26 @@ func (t *tstoreBackend) Fsck() error {
 	return t.tstore.Fsck(allTokens)
 }
 
+// Reindex performs a synchronous rebuild of the caches and indexes of the
+// specified plugin. The data of all other plugins is left untouched. The
+// plugin caches are rebuilt from the tlog data, so a reindex can be safely
+// restarted if it is interrupted.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) Reindex(pluginID string) error {
+	log.Infof("Reindexing the %v plugin", pluginID)
+
+	// Get the tokens for all records in the backend
+	allTokens, err := t.tstore.Inventory()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("%v records found in the tstore backend", len(allTokens))
+
+	return t.tstore.Reindex(pluginID, allTokens)
+}
+
 // Close performs cleanup of the backend.
 //
 // This function satisfies the backendv2 Backend interface.
@@ -1144,10 +1174,10 @@ func (t *tstoreBackend) setup() error {
 }
 
 // New returns a new tstoreBackend.
-func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, dbHost, dbPass, dcrtimeHost, dcrtimeCert string) (*tstoreBackend, error) {
+func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, dbHost, dbPass, dcrtimeHost, dcrtimeCert string, tokenRetries int) (*tstoreBackend, error) {
 	// Setup tstore instances
 	ts, err := tstore.New(appDir, dataDir, anp, tlogHost,
-		dbHost, dbPass, dcrtimeHost, dcrtimeCert)
+		dbHost, dbPass, dcrtimeHost, dcrtimeCert, tokenRetries)
 	if err != nil {
 		return nil, fmt.Errorf("new tstore: %v", err)
 	}
@@ -27,8 +27,9 @@ type batch struct {
 // resulted from the execution of the plugin command.
 type batchEntry struct {
 	cmd   v2.PluginCmd
-	reply string // JSON encoded reply payload
-	err   error  // Only set if an error is encountered
+	reply string        // JSON encoded reply payload
+	page  *v2.PageReply // Only set if a page was requested
+	err   error         // Only set if an error is encountered
 }
 
 // newBatch returns a new batch.
@@ -76,7 +77,7 @@ func (b *batch) execReadCmd(fn pluginRead, cmd v2.PluginCmd, index int, wg *sync
 				ErrorCode:    v2.ErrorCodeTokenInvalid,
 				ErrorContext: util.TokenRegexp(),
 			}
-			b.setReply(index, "", err)
+			b.setReply(index, "", nil, err)
 			return
 		}
 	}
@@ -84,11 +85,21 @@ func (b *batch) execReadCmd(fn pluginRead, cmd v2.PluginCmd, index int, wg *sync
 	// Execute the read command
 	reply, err := fn(token, cmd.ID, cmd.Command, cmd.Payload)
 	if err != nil {
-		b.setReply(index, "", err)
+		b.setReply(index, "", nil, err)
 		return
 	}
 
-	b.setReply(index, reply, nil)
+	// Page the reply payload if a page was requested
+	var page *v2.PageReply
+	if cmd.Page != nil {
+		reply, page, err = pageReply(reply, *cmd.Page)
+		if err != nil {
+			b.setReply(index, "", nil, err)
+			return
+		}
+	}
+
+	b.setReply(index, reply, page, nil)
 }
 
 // getCmd returns the PluginCmd at the provided index.
@@ -100,12 +111,13 @@ func (b *batch) getCmd(index int) v2.PluginCmd {
 }
 
 // setReply sets the reply for the plugin command at that provided index.
-func (b *batch) setReply(index int, reply string, err error) {
+func (b *batch) setReply(index int, reply string, page *v2.PageReply, err error) {
 	b.Lock()
 	defer b.Unlock()
 
 	c := b.entries[index]
 	c.reply = reply
+	c.page = page
 	c.err = err
 	b.entries[index] = c
 }
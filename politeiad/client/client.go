@@ -29,9 +29,10 @@ type Client struct {
 // an error occurs. PluginID will only be populated if the error occurred
 // during execution of a plugin command.
 type ErrorReply struct {
-	PluginID     string `json:"pluginid"`
-	ErrorCode    uint32 `json:"errorcode"`
-	ErrorContext string `json:"errorcontext"`
+	PluginID     string            `json:"pluginid"`
+	ErrorCode    uint32            `json:"errorcode"`
+	ErrorContext string            `json:"errorcontext"`
+	ErrorParams  map[string]string `json:"errorparams"`
 }
 
 // RespError represents a politeiad response error. A RespError is returned
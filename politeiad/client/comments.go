@@ -103,6 +103,37 @@ func (c *Client) CommentVote(ctx context.Context, v comments.Vote) (*comments.Vo
 	return &vr, nil
 }
 
+// CommentStakeProof sends the comments plugin StakeProof command to the
+// politeiad v2 API.
+func (c *Client) CommentStakeProof(ctx context.Context, sp comments.StakeProof) (*comments.StakeProofReply, error) {
+	// Setup request
+	b, err := json.Marshal(sp)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   sp.Token,
+		ID:      comments.PluginID,
+		Command: comments.CmdStakeProof,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var spr comments.StakeProofReply
+	err = json.Unmarshal([]byte(reply), &spr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spr, nil
+}
+
 // CommentDel sends the comments plugin Del command to the politeiad v2 API.
 func (c *Client) CommentDel(ctx context.Context, d comments.Del) (*comments.DelReply, error) {
 	// Setup request
@@ -133,11 +164,58 @@ func (c *Client) CommentDel(ctx context.Context, d comments.Del) (*comments.DelR
 	return &dr, nil
 }
 
+// CommentDelUser sends the comments plugin DelUser command to the politeiad
+// v2 API.
+func (c *Client) CommentDelUser(ctx context.Context, token string, d comments.DelUser) (*comments.DelUserReply, error) {
+	// Setup request
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   token,
+		ID:      comments.PluginID,
+		Command: comments.CmdDelUser,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var dr comments.DelUserReply
+	err = json.Unmarshal([]byte(reply), &dr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dr, nil
+}
+
 // CommentCount sends a batch of comment plugin Count commands to the
 // politeiad v2 API and returns a map[token]count with the results. If a
 // record is not found for a token or any other error occurs, that token
 // will not be included in the reply.
 func (c *Client) CommentCount(ctx context.Context, tokens []string) (map[string]uint32, error) {
+	replies, err := c.CommentCountReplies(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]uint32, len(replies))
+	for k, v := range replies {
+		counts[k] = v.Count
+	}
+	return counts, nil
+}
+
+// CommentCountReplies sends a batch of comment plugin Count commands to the
+// politeiad v2 API and returns a map[token]CountReply with the results. If a
+// record is not found for a token or any other error occurs, that token will
+// not be included in the reply.
+func (c *Client) CommentCountReplies(ctx context.Context, tokens []string) (map[string]comments.CountReply, error) {
 	// Setup request
 	cmds := make([]pdv2.PluginCmd, 0, len(tokens))
 	for _, v := range tokens {
@@ -158,7 +236,7 @@ func (c *Client) CommentCount(ctx context.Context, tokens []string) (map[string]
 	}
 
 	// Decode replies
-	counts := make(map[string]uint32, len(replies))
+	counts := make(map[string]comments.CountReply, len(replies))
 	for _, v := range replies {
 		// This command swallows individual errors. The token of the
 		// command that errored will not be included in the reply.
@@ -172,7 +250,7 @@ func (c *Client) CommentCount(ctx context.Context, tokens []string) (map[string]
 		if err != nil {
 			continue
 		}
-		counts[v.Token] = cr.Count
+		counts[v.Token] = cr
 	}
 
 	return counts, nil
@@ -254,6 +332,130 @@ func (c *Client) CommentsGetAll(ctx context.Context, token string) ([]comments.C
 	return gar.Comments, nil
 }
 
+// CommentsGetPage sends the comments plugin GetPage command to the politeiad
+// v2 API.
+func (c *Client) CommentsGetPage(ctx context.Context, token string, gp comments.GetPage) (*comments.GetPageReply, error) {
+	// Setup request
+	b, err := json.Marshal(gp)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdGetPage,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var gpr comments.GetPageReply
+	err = json.Unmarshal([]byte(pcr.Payload), &gpr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gpr, nil
+}
+
+// CommentsTree sends the comments plugin Tree command to the politeiad v2
+// API.
+func (c *Client) CommentsTree(ctx context.Context, token string, t comments.Tree) (*comments.TreeReply, error) {
+	// Setup request
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdTree,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var tr comments.TreeReply
+	err = json.Unmarshal([]byte(pcr.Payload), &tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tr, nil
+}
+
+// CommentsUser sends the comments plugin User command to the politeiad v2
+// API. The token is optional. If no token is provided, the comments that the
+// user made on all records are returned.
+func (c *Client) CommentsUser(ctx context.Context, token string, u comments.User) (*comments.UserReply, error) {
+	// Setup request
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdUser,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var ur comments.UserReply
+	err = json.Unmarshal([]byte(pcr.Payload), &ur)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ur, nil
+}
+
 // CommentVotes sends the comments plugin Votes command to the politeiad v2
 // API.
 func (c *Client) CommentVotes(ctx context.Context, token string, v comments.Votes) ([]comments.CommentVote, error) {
@@ -335,3 +537,269 @@ func (c *Client) CommentTimestamps(ctx context.Context, token string, t comments
 
 	return &tr, nil
 }
+
+// CommentTimestampsBatch sends the comments plugin TimestampsBatch command to
+// the politeiad v2 API.
+func (c *Client) CommentTimestampsBatch(ctx context.Context, token string, t comments.TimestampsBatch) (*comments.TimestampsReply, error) {
+	// Setup request
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdTimestampsBatch,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var tr comments.TimestampsReply
+	err = json.Unmarshal([]byte(pcr.Payload), &tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tr, nil
+}
+
+// CommentsAttachments sends the comments plugin Attachments command to the
+// politeiad v2 API.
+func (c *Client) CommentsAttachments(ctx context.Context, token string, a comments.Attachments) (*comments.AttachmentsReply, error) {
+	// Setup request
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdAttachments,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var ar comments.AttachmentsReply
+	err = json.Unmarshal([]byte(pcr.Payload), &ar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ar, nil
+}
+
+// CommentSubscribe sends the comments plugin Subscribe command to the
+// politeiad v2 API.
+func (c *Client) CommentSubscribe(ctx context.Context, s comments.Subscribe) (*comments.SubscribeReply, error) {
+	// Setup request
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   s.Token,
+		ID:      comments.PluginID,
+		Command: comments.CmdSubscribe,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var sr comments.SubscribeReply
+	err = json.Unmarshal([]byte(reply), &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+// CommentPin sends the comments plugin Pin command to the politeiad v2 API.
+func (c *Client) CommentPin(ctx context.Context, p comments.Pin) (*comments.PinReply, error) {
+	// Setup request
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   p.Token,
+		ID:      comments.PluginID,
+		Command: comments.CmdPin,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var pr comments.PinReply
+	err = json.Unmarshal([]byte(reply), &pr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// CommentSubscribers sends the comments plugin Subscribers command to the
+// politeiad v2 API.
+func (c *Client) CommentSubscribers(ctx context.Context, token string, s comments.Subscribers) ([]string, error) {
+	// Setup request
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdSubscribers,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var sr comments.SubscribersReply
+	err = json.Unmarshal([]byte(pcr.Payload), &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return sr.UserIDs, nil
+}
+
+// CommentSearch sends the comments plugin Search command to the politeiad v2
+// API. The token is optional. If a token is not provided, the comments of all
+// records are searched.
+func (c *Client) CommentSearch(ctx context.Context, token string, s comments.Search) (*comments.SearchReply, error) {
+	// Setup request
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      comments.PluginID,
+			Command: comments.CmdSearch,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var sr comments.SearchReply
+	err = json.Unmarshal([]byte(pcr.Payload), &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+// CommentHistory sends the comments plugin History command to the politeiad
+// v2 API.
+func (c *Client) CommentHistory(ctx context.Context, h comments.History) (*comments.HistoryReply, error) {
+	// Setup request
+	b, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      comments.PluginID,
+			Command: comments.CmdHistory,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var hr comments.HistoryReply
+	err = json.Unmarshal([]byte(pcr.Payload), &hr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hr, nil
+}
@@ -396,6 +396,70 @@ func (c *Client) PluginInventory(ctx context.Context) ([]pdv2.Plugin, error) {
 	return pir.Plugins, nil
 }
 
+// AccessCounts sends a v2 AccessCounts request to politeiad. An empty token
+// returns the counters of the most retrieved records.
+func (c *Client) AccessCounts(ctx context.Context, token string) (*pdv2.AccessCountsReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	ac := pdv2.AccessCounts{
+		Challenge: hex.EncodeToString(challenge),
+		Token:     token,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteAccessCounts, ac)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var acr pdv2.AccessCountsReply
+	err = json.Unmarshal(resBody, &acr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, acr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &acr, nil
+}
+
+// PluginJournal sends a v2 PluginJournal request to politeiad.
+func (c *Client) PluginJournal(ctx context.Context, pj pdv2.PluginJournal) ([]pdv2.JournalEntry, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	pj.Challenge = hex.EncodeToString(challenge)
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RoutePluginJournal, pj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var pjr pdv2.PluginJournalReply
+	err = json.Unmarshal(resBody, &pjr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, pjr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return pjr.Entries, nil
+}
+
 // RecordVerify verifies the censorship record of a v2 Record.
 func RecordVerify(r pdv2.Record, serverPubKey string) error {
 	// Verify censorship record merkle root
@@ -477,8 +541,42 @@ func extractPluginCmdError(pcr pdv2.PluginCmdReply) error {
 				PluginID:     pcr.PluginError.PluginID,
 				ErrorCode:    pcr.PluginError.ErrorCode,
 				ErrorContext: pcr.PluginError.ErrorContext,
+				ErrorParams:  pcr.PluginError.ErrorParams,
 			},
 		}
 	}
 	return nil
 }
+
+// TokenResolve sends a v2 TokenResolve request to politeiad.
+func (c *Client) TokenResolve(ctx context.Context, prefix string) (*pdv2.TokenResolveReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	tr := pdv2.TokenResolve{
+		Challenge: hex.EncodeToString(challenge),
+		Prefix:    prefix,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteTokenResolve, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var trr pdv2.TokenResolveReply
+	err = json.Unmarshal(resBody, &trr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, trr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trr, nil
+}
@@ -7,6 +7,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	"github.com/decred/politeia/politeiad/plugins/pi"
@@ -123,3 +124,145 @@ func (c *Client) PiBillingStatusChanges(ctx context.Context, tokens []string) (m
 	return bscsr, nil
 
 }
+
+// PiIdentity sends the pi plugin Identity command to the politeiad v2 API.
+// The returned string is the hex encoded public key of the server identity
+// that the pi plugin uses to sign receipts.
+func (c *Client) PiIdentity(ctx context.Context) (string, error) {
+	// Setup request
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      pi.PluginID,
+			Command: pi.CmdIdentity,
+			Payload: "",
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return "", err
+	}
+	if len(replies) == 0 {
+		return "", fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return "", err
+	}
+
+	// Decode reply
+	var ir pi.IdentityReply
+	err = json.Unmarshal([]byte(pcr.Payload), &ir)
+	if err != nil {
+		return "", err
+	}
+
+	return ir.PublicKey, nil
+}
+
+// PiCompletionReport sends the pi plugin CompletionReport command to the
+// politeiad v2 API. A nil report is returned if the proposal does not have a
+// completion report.
+func (c *Client) PiCompletionReport(ctx context.Context, token string) (*pi.CompletionReport, error) {
+	// Setup request
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      pi.PluginID,
+			Command: pi.CmdCompletionReport,
+			Payload: "",
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var crr pi.CompletionReportReply
+	err = json.Unmarshal([]byte(pcr.Payload), &crr)
+	if err != nil {
+		return nil, err
+	}
+
+	return crr.Report, nil
+}
+
+// PiWithdraw sends the pi plugin Withdraw command to the politeiad v2 API.
+func (c *Client) PiWithdraw(ctx context.Context, w pi.Withdraw) (*pi.WithdrawReply, error) {
+	// Setup request
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   w.Token,
+		ID:      pi.PluginID,
+		Command: pi.CmdWithdraw,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var wr pi.WithdrawReply
+	err = json.Unmarshal([]byte(reply), &wr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wr, nil
+}
+
+// PiWithdrawal sends the pi plugin Withdrawal command to the politeiad v2
+// API. A nil withdrawal is returned if the proposal has not been withdrawn.
+func (c *Client) PiWithdrawal(ctx context.Context, token string) (*pi.Withdrawal, error) {
+	// Setup request
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      pi.PluginID,
+			Command: pi.CmdWithdrawal,
+			Payload: "",
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var wr pi.WithdrawalReply
+	err = json.Unmarshal([]byte(pcr.Payload), &wr)
+	if err != nil {
+		return nil, err
+	}
+
+	return wr.Withdrawal, nil
+}
@@ -75,6 +75,68 @@ func (c *Client) TicketVoteStart(ctx context.Context, token string, s ticketvote
 	return &sr, nil
 }
 
+// TicketVoteExtend sends the ticketvote plugin Extend command to the politeiad
+// v2 API.
+func (c *Client) TicketVoteExtend(ctx context.Context, token string, e ticketvote.Extend) (*ticketvote.ExtendReply, error) {
+	// Setup request
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   token,
+		ID:      ticketvote.PluginID,
+		Command: ticketvote.CmdExtend,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var er ticketvote.ExtendReply
+	err = json.Unmarshal([]byte(reply), &er)
+	if err != nil {
+		return nil, err
+	}
+
+	return &er, nil
+}
+
+// TicketVoteCancel sends the ticketvote plugin Cancel command to the politeiad
+// v2 API.
+func (c *Client) TicketVoteCancel(ctx context.Context, token string, tc ticketvote.Cancel) (*ticketvote.CancelReply, error) {
+	// Setup request
+	b, err := json.Marshal(tc)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   token,
+		ID:      ticketvote.PluginID,
+		Command: ticketvote.CmdCancel,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var cr ticketvote.CancelReply
+	err = json.Unmarshal([]byte(reply), &cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cr, nil
+}
+
 // TicketVoteCastBallot sends the ticketvote plugin CastBallot command to the
 // politeiad v2 API.
 func (c *Client) TicketVoteCastBallot(ctx context.Context, token string, cb ticketvote.CastBallot) (*ticketvote.CastBallotReply, error) {
@@ -180,6 +242,129 @@ func (c *Client) TicketVoteResults(ctx context.Context, token string) (*ticketvo
 	return &rr, nil
 }
 
+// TicketVoteResultsExport sends the ticketvote plugin ResultsExport command
+// to the politeiad v2 API.
+func (c *Client) TicketVoteResultsExport(ctx context.Context, token string, re ticketvote.ResultsExport) (*ticketvote.ResultsExportReply, error) {
+	// Setup request
+	b, err := json.Marshal(re)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdResultsExport,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var rer ticketvote.ResultsExportReply
+	err = json.Unmarshal([]byte(pcr.Payload), &rer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rer, nil
+}
+
+// TicketVoteReceipts sends the ticketvote plugin Receipts command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteReceipts(ctx context.Context, token string, r ticketvote.Receipts) (*ticketvote.ReceiptsReply, error) {
+	// Setup request
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdReceipts,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var rr ticketvote.ReceiptsReply
+	err = json.Unmarshal([]byte(pcr.Payload), &rr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rr, nil
+}
+
+// TicketVoteVote sends the ticketvote plugin Vote command to the politeiad v2
+// API.
+func (c *Client) TicketVoteVote(ctx context.Context, token string, v ticketvote.Vote) (*ticketvote.VoteReply, error) {
+	// Setup request
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdVote,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var vr ticketvote.VoteReply
+	err = json.Unmarshal([]byte(pcr.Payload), &vr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vr, nil
+}
+
 // TicketVoteSummary sends the ticketvote plugin Summary command to the
 // politeiad v2 API.
 func (c *Client) TicketVoteSummary(ctx context.Context, token string) (*ticketvote.SummaryReply, error) {
@@ -375,3 +560,404 @@ func (c *Client) TicketVoteTimestamps(ctx context.Context, token string, t ticke
 
 	return &sr, nil
 }
+
+// TicketVoteSnapshot sends the ticketvote plugin Snapshot command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteSnapshot(ctx context.Context, token string) (*ticketvote.SnapshotReply, error) {
+	// Setup request
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdSnapshot,
+			Token:   token,
+			Payload: "",
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var sr ticketvote.SnapshotReply
+	err = json.Unmarshal([]byte(pcr.Payload), &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+// TicketVoteEligibility sends the ticketvote plugin Eligibility command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteEligibility(ctx context.Context, token string, e ticketvote.Eligibility) (*ticketvote.EligibilityReply, error) {
+	// Setup request
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdEligibility,
+			Token:   token,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var er ticketvote.EligibilityReply
+	err = json.Unmarshal([]byte(pcr.Payload), &er)
+	if err != nil {
+		return nil, err
+	}
+
+	return &er, nil
+}
+
+// TicketVoteSetVoteDuration sends the ticketvote plugin SetVoteDuration
+// command to the politeiad v2 API.
+func (c *Client) TicketVoteSetVoteDuration(ctx context.Context, svd ticketvote.SetVoteDuration) (*ticketvote.SetVoteDurationReply, error) {
+	// Setup request
+	b, err := json.Marshal(svd)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdSetVoteDuration,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var svdr ticketvote.SetVoteDurationReply
+	err = json.Unmarshal([]byte(pcr.Payload), &svdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &svdr, nil
+}
+
+// TicketVoteActiveVotesRebuild sends the ticketvote plugin ActiveVotesRebuild
+// command to the politeiad v2 API.
+func (c *Client) TicketVoteActiveVotesRebuild(ctx context.Context) (*ticketvote.ActiveVotesRebuildReply, error) {
+	// Setup request
+	b, err := json.Marshal(ticketvote.ActiveVotesRebuild{})
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdActiveVotesRebuild,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var avr ticketvote.ActiveVotesRebuildReply
+	err = json.Unmarshal([]byte(pcr.Payload), &avr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &avr, nil
+}
+
+// TicketVoteSnapshotVerify sends the ticketvote plugin SnapshotVerify command
+// to the politeiad v2 API.
+func (c *Client) TicketVoteSnapshotVerify(ctx context.Context, token string) (*ticketvote.SnapshotVerifyReply, error) {
+	// Setup request
+	b, err := json.Marshal(ticketvote.SnapshotVerify{})
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdSnapshotVerify,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var svr ticketvote.SnapshotVerifyReply
+	err = json.Unmarshal([]byte(pcr.Payload), &svr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &svr, nil
+}
+
+// TicketVoteStats sends the ticketvote plugin VoteStats command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteStats(ctx context.Context, token string) (*ticketvote.VoteStatsReply, error) {
+	// Setup request
+	b, err := json.Marshal(ticketvote.VoteStats{})
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdVoteStats,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var vsr ticketvote.VoteStatsReply
+	err = json.Unmarshal([]byte(pcr.Payload), &vsr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vsr, nil
+}
+
+// TicketVoteLoad sends the ticketvote plugin VoteLoad command to the politeiad
+// v2 API.
+func (c *Client) TicketVoteLoad(ctx context.Context) (*ticketvote.VoteLoadReply, error) {
+	// Setup request
+	b, err := json.Marshal(ticketvote.VoteLoad{})
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdVoteLoad,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var vlr ticketvote.VoteLoadReply
+	err = json.Unmarshal([]byte(pcr.Payload), &vlr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vlr, nil
+}
+
+// TicketVoteGovernanceExport sends the ticketvote plugin GovernanceExport
+// command to the politeiad v2 API.
+func (c *Client) TicketVoteGovernanceExport(ctx context.Context, ge ticketvote.GovernanceExport) (*ticketvote.GovernanceExportReply, error) {
+	// Setup request
+	b, err := json.Marshal(ge)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdGovernanceExport,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var ger ticketvote.GovernanceExportReply
+	err = json.Unmarshal([]byte(pcr.Payload), &ger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ger, nil
+}
+
+// TicketVoteVoteParamsVerify sends the ticketvote plugin VoteParamsVerify
+// command to the politeiad v2 API.
+func (c *Client) TicketVoteVoteParamsVerify(ctx context.Context, vpv ticketvote.VoteParamsVerify) (*ticketvote.VoteParamsVerifyReply, error) {
+	// Setup request
+	b, err := json.Marshal(vpv)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdVoteParamsVerify,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var vpvr ticketvote.VoteParamsVerifyReply
+	err = json.Unmarshal([]byte(pcr.Payload), &vpvr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vpvr, nil
+}
+
+// TicketVoteAuditBundle sends the ticketvote plugin AuditBundle command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteAuditBundle(ctx context.Context, token string) (*ticketvote.AuditBundleReply, error) {
+	// Setup request
+	b, err := json.Marshal(ticketvote.AuditBundle{})
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			Token:   token,
+			ID:      ticketvote.PluginID,
+			Command: ticketvote.CmdAuditBundle,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var abr ticketvote.AuditBundleReply
+	err = json.Unmarshal([]byte(pcr.Payload), &abr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &abr, nil
+}
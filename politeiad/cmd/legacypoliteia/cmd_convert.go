@@ -75,7 +75,7 @@ func execConvertCmd(args []string) error {
 		return err
 	}
 
-	client, err := util.NewHTTPClient(false, "")
+	client, err := newHTTPClient()
 	if err != nil {
 		return err
 	}
@@ -39,6 +39,7 @@ import (
 	"github.com/decred/politeia/politeiawww/legacy/user"
 	userdb "github.com/decred/politeia/politeiawww/legacy/user/mysql"
 	"github.com/decred/politeia/util"
+	"github.com/decred/politeia/util/version"
 	"github.com/google/trillian"
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -50,6 +51,10 @@ const (
 	defaultDBHost   = "localhost:3306"
 	defaultDBPass   = "politeiadpass"
 
+	// tokenRetries is the number of times that a new record token is
+	// created when the short token collides with an existing record.
+	tokenRetries = 10
+
 	// User database settings
 	userDBPass = "politeiawwwpass"
 )
@@ -157,7 +162,7 @@ type importCmd struct {
 func newImportCmd(legacyDir, tlogHost, dbHost, dbPass, importToken string, stubUsers bool, params *chaincfg.Params) (*importCmd, error) {
 	// Setup the tstore connection
 	ts, err := tstore.New(politeiadHomeDir, politeiadDataDir,
-		params, tlogHost, dbHost, dbPass, "", "")
+		params, tlogHost, dbHost, dbPass, "", "", tokenRetries)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +195,7 @@ func newImportCmd(legacyDir, tlogHost, dbHost, dbPass, importToken string, stubU
 		if err != nil {
 			return nil, err
 		}
-		httpC, err = util.NewHTTPClient(false, "")
+		httpC, err = newHTTPClient()
 		if err != nil {
 			return nil, err
 		}
@@ -475,6 +480,19 @@ func (c *importCmd) importProposal(p *proposal, parentTstoreToken []byte) ([]byt
 
 	fmt.Printf("  Tstore token: %x\n", tstoreToken)
 
+	// Record where the proposal originated from before the legacy
+	// fields are overwritten.
+	prov := usermd.Provenance{
+		SourceSystem:          usermd.ProvenanceSourceGit,
+		SourceToken:           p.RecordMetadata.Token,
+		SourceVersion:         p.RecordMetadata.Version,
+		SourceTimestamp:       p.RecordMetadata.Timestamp,
+		PublishedAt:           p.StatusChanges[0].Timestamp,
+		ConversionTool:        "legacypoliteia",
+		ConversionToolVersion: version.Version,
+		ImportedAt:            time.Now().Unix(),
+	}
+
 	// Perform proposal data changes
 	err = overwriteProposalFields(p, tstoreToken, parentTstoreToken)
 	if err != nil {
@@ -483,7 +501,7 @@ func (c *importCmd) importProposal(p *proposal, parentTstoreToken []byte) ([]byt
 
 	// Import the proposal contents
 	fmt.Printf("  Importing record data...\n")
-	err = c.importRecord(*p, tstoreToken)
+	err = c.importRecord(*p, prov, tstoreToken)
 	if err != nil {
 		return nil, err
 	}
@@ -517,7 +535,10 @@ func (c *importCmd) importProposal(p *proposal, parentTstoreToken []byte) ([]byt
 // because there are certain steps that the tstore backend must complete, ex.
 // re-saving encrypted blobs as plain text when a proposal is made public, in
 // order for the proposal to be imported correctly.
-func (c *importCmd) importRecord(p proposal, tstoreToken []byte) error {
+//
+// The provenance metadata stream is saved alongside the server generated
+// metadata so that clients are able to identify migrated proposals.
+func (c *importCmd) importRecord(p proposal, prov usermd.Provenance, tstoreToken []byte) error {
 	// Convert user generated metadata into backend files.
 	//
 	// User generated metadata includes:
@@ -553,6 +574,10 @@ func (c *importCmd) importRecord(p proposal, tstoreToken []byte) error {
 	if err != nil {
 		return err
 	}
+	provStream, err := convertProvenanceToMetadataStream(prov)
+	if err != nil {
+		return err
+	}
 
 	var (
 		publicStatus    = p.StatusChanges[0]
@@ -575,6 +600,7 @@ func (c *importCmd) importRecord(p proposal, tstoreToken []byte) error {
 
 	metadataStreams := []backend.MetadataStream{
 		*userStream,
+		*provStream,
 	}
 
 	err = c.tstore.RecordSave(tstoreToken, p.RecordMetadata,
@@ -599,6 +625,7 @@ func (c *importCmd) importRecord(p proposal, tstoreToken []byte) error {
 	metadataStreams = []backend.MetadataStream{
 		*userStream,
 		*statusChangeStream,
+		*provStream,
 	}
 
 	err = c.tstore.RecordSave(tstoreToken, p.RecordMetadata,
@@ -640,6 +667,7 @@ func (c *importCmd) importRecord(p proposal, tstoreToken []byte) error {
 	metadataStreams = []backend.MetadataStream{
 		*userStream,
 		appendMetadataStream(*statusChangeStream, *abandonedStream),
+		*provStream,
 	}
 
 	return c.tstore.RecordFreeze(tstoreToken, p.RecordMetadata,
@@ -1301,6 +1329,20 @@ func convertStatusChangeToMetadataStream(scm usermd.StatusChangeMetadata) (*back
 	}, nil
 }
 
+// convertProvenanceToMetadataStream converts a usermd plugin Provenance into
+// a backend MetadataStream.
+func convertProvenanceToMetadataStream(prov usermd.Provenance) (*backend.MetadataStream, error) {
+	b, err := json.Marshal(prov)
+	if err != nil {
+		return nil, err
+	}
+	return &backend.MetadataStream{
+		PluginID: usermd.PluginID,
+		StreamID: usermd.StreamIDProvenance,
+		Payload:  string(b),
+	}, nil
+}
+
 // addIdentity converts the provided public key string into a politeiawww user
 // identity and adds it to the provided identities list.
 //
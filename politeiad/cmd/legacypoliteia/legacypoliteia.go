@@ -7,7 +7,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+
+	"github.com/decred/politeia/util"
 )
 
 const (
@@ -18,8 +21,27 @@ const (
 	// filePermissions is the file permissions that are used for all directory
 	// and file creation in this tool.
 	filePermissions = 0755
+
+	// httpRetries is the number of times that a failed HTTP request is
+	// retried. The convert command makes a large number of requests to
+	// external APIs, so transient failures are retried instead of
+	// aborting the command.
+	httpRetries = 3
 )
 
+// newHTTPClient returns the HTTP client that is used for all outbound requests
+// made by this tool.
+func newHTTPClient() (*http.Client, error) {
+	return util.NewHTTPClientWithOpts(util.HTTPClientOpts{
+		Retries: httpRetries,
+		OnAttempt: func(a util.HTTPAttempt) {
+			if a.Retry {
+				fmt.Printf("Retrying failed request %v\n", a)
+			}
+		},
+	})
+}
+
 func _main() error {
 	// Parse the CLI args
 	flag.Parse()
@@ -20,6 +20,7 @@ import (
 
 	"github.com/decred/dcrd/dcrutil/v3"
 	v1 "github.com/decred/dcrtime/api/v1"
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	"github.com/decred/politeia/util"
 	"github.com/decred/politeia/util/version"
 	flags "github.com/jessevdk/go-flags"
@@ -45,8 +46,14 @@ const (
 	defaultBackend = backendTstore
 
 	// Tstore default settings
-	defaultDBHost   = "localhost:3306" // MySQL default host
-	defaultTlogHost = "localhost:8090"
+	defaultDBHost            = "localhost:3306" // MySQL default host
+	defaultTlogHost          = "localhost:8090"
+	defaultTokenPrefixLength = pdv2.ShortTokenLength
+	defaultTokenRetries      = 10
+
+	// tokenRetriesMax is the maximum number of token retries that can
+	// be configured.
+	tokenRetriesMax = 100
 
 	// Environment variables
 	envDBPass = "DBPASS"
@@ -72,6 +79,10 @@ var (
 	// defaultReqBodySizeLimit is the maximum number of bytes allowed in a
 	// request body.
 	defaultReqBodySizeLimit int64 = 3 * 1024 * 1024 // 3 MiB
+
+	// defaultJournalMaxSize is the size in MiB that the plugin write
+	// journal file is allowed to grow to before it is rotated.
+	defaultJournalMaxSize int64 = 100
 )
 
 // runServiceCommand is only set to a real function on Windows.  It is used
@@ -104,6 +115,13 @@ type config struct {
 	Identity    string `long:"identity" description:"File containing the politeiad identity file"`
 	Backend     string `long:"backend" description:"Backend type"`
 	Fsck        bool   `long:"fsck" description:"Perform filesystem checks on all record and plugin data"`
+	Reindex     string `long:"reindex" description:"Rebuild the caches and indexes of the specified plugin from the tlog data then exit"`
+	GenData     string `long:"gendata" description:"Populate the backend with a deterministic dataset that is generated from the provided seed then exit; testnet only"`
+	genDataSeed int64  // Parsed from GenData
+	IPFSHost    string `long:"ipfshost" description:"IPFS HTTP API URL used to pin the files of public records"`
+
+	AccessAlertRate uint64 `long:"accessalertrate" description:"Number of retrievals of a single record file in one minute that triggers an access alert; 0 disables alerts"`
+	JournalMaxSize  int64  `long:"journalmaxsize" description:"Size in MiB that the plugin write journal file can grow to before it is rotated; 0 disables rotation"`
 
 	// Web server settings
 	ReadTimeout      int64 `long:"readtimeout" description:"Maximum duration in seconds that is spent reading the request headers and body"`
@@ -119,6 +137,9 @@ type config struct {
 	DBPass   string // Provided in env variable "DBPASS"
 	TlogHost string `long:"tloghost" description:"Trillian log ip:port"`
 
+	TokenPrefixLength int `long:"tokenprefixlength" description:"Length, in characters, of the unique record token prefix (short token); changing it requires running politeiad with --fsck"`
+	TokenRetries      int `long:"tokenretries" description:"Number of times a new record token is created when its prefix collides with an existing record"`
+
 	// Plugin options
 	Plugins        []string `long:"plugin" description:"Plugins"`
 	PluginSettings []string `long:"pluginsetting" description:"Plugin settings"`
@@ -262,20 +283,23 @@ func newConfigParser(cfg *config, so *serviceOptions, options flags.Options) *fl
 func loadConfig() (*config, []string, error) {
 	// Default config.
 	cfg := config{
-		HomeDir:          defaultHomeDir,
-		ConfigFile:       defaultConfigFile,
-		DebugLevel:       defaultLogLevel,
-		DataDir:          defaultDataDir,
-		LogDir:           defaultLogDir,
-		HTTPSKey:         defaultHTTPSKeyFile,
-		HTTPSCert:        defaultHTTPSCertFile,
-		Version:          version.Version,
-		Backend:          defaultBackend,
-		ReadTimeout:      defaultReadTimeout,
-		WriteTimeout:     defaultWriteTimeout,
-		ReqBodySizeLimit: defaultReqBodySizeLimit,
-		DBHost:           defaultDBHost,
-		TlogHost:         defaultTlogHost,
+		HomeDir:           defaultHomeDir,
+		ConfigFile:        defaultConfigFile,
+		DebugLevel:        defaultLogLevel,
+		DataDir:           defaultDataDir,
+		LogDir:            defaultLogDir,
+		HTTPSKey:          defaultHTTPSKeyFile,
+		HTTPSCert:         defaultHTTPSCertFile,
+		Version:           version.Version,
+		Backend:           defaultBackend,
+		ReadTimeout:       defaultReadTimeout,
+		WriteTimeout:      defaultWriteTimeout,
+		ReqBodySizeLimit:  defaultReqBodySizeLimit,
+		DBHost:            defaultDBHost,
+		TlogHost:          defaultTlogHost,
+		TokenPrefixLength: defaultTokenPrefixLength,
+		TokenRetries:      defaultTokenRetries,
+		JournalMaxSize:    defaultJournalMaxSize,
 	}
 
 	// Service options which are only added on Windows.
@@ -552,10 +576,23 @@ func loadConfig() (*config, []string, error) {
 		log.Warnf("RPC password not set, using random value")
 	}
 
+	// Verify the journal settings
+	if cfg.JournalMaxSize < 0 {
+		return nil, nil, fmt.Errorf("journalmaxsize cannot be negative; "+
+			"got %v", cfg.JournalMaxSize)
+	}
+
 	// Verify backend specific settings
 	switch cfg.Backend {
 	case backendGit:
-		// Nothing to do
+		if cfg.Reindex != "" {
+			return nil, nil, fmt.Errorf("reindex requires the %v backend",
+				backendTstore)
+		}
+		if cfg.GenData != "" {
+			return nil, nil, fmt.Errorf("gendata requires the %v backend",
+				backendTstore)
+		}
 	case backendTstore:
 		err = verifyTstoreSettings(&cfg)
 		if err != nil {
@@ -592,5 +629,53 @@ func verifyTstoreSettings(cfg *config) error {
 		return fmt.Errorf("invalid tlog host '%v': %v", cfg.TlogHost, err)
 	}
 
+	// Verify the token settings
+	if cfg.TokenPrefixLength < util.ShortTokenLengthMin ||
+		cfg.TokenPrefixLength > util.ShortTokenLengthMax {
+		return fmt.Errorf("tokenprefixlength must be between %v and %v; "+
+			"got %v", util.ShortTokenLengthMin, util.ShortTokenLengthMax,
+			cfg.TokenPrefixLength)
+	}
+	if cfg.TokenRetries < 1 || cfg.TokenRetries > tokenRetriesMax {
+		return fmt.Errorf("tokenretries must be between 1 and %v; got %v",
+			tokenRetriesMax, cfg.TokenRetries)
+	}
+
+	// Verify the reindex plugin. A plugin can only be reindexed if it
+	// is registered.
+	if cfg.Reindex != "" {
+		if cfg.Fsck {
+			return fmt.Errorf("fsck and reindex cannot be used together")
+		}
+		var found bool
+		for _, v := range cfg.Plugins {
+			if v == cfg.Reindex {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("reindex plugin '%v' is not registered",
+				cfg.Reindex)
+		}
+	}
+
+	// Verify the gendata seed. Generated data is only allowed on
+	// testnet.
+	if cfg.GenData != "" {
+		switch {
+		case !cfg.TestNet:
+			return fmt.Errorf("gendata can only be used on testnet")
+		case cfg.Fsck, cfg.Reindex != "":
+			return fmt.Errorf("gendata cannot be used with fsck or reindex")
+		}
+		seed, err := strconv.ParseInt(cfg.GenData, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid gendata seed '%v': %v",
+				cfg.GenData, err)
+		}
+		cfg.genDataSeed = seed
+	}
+
 	return nil
 }
@@ -0,0 +1,843 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
+	"github.com/decred/politeia/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// The following constants determine the size of the generated
+	// dataset.
+	genDataUsers           = 10
+	genDataVoters          = 5
+	genDataProposals       = 27
+	genDataRFPSubmissions  = 3
+	genDataCommentsMax     = 20
+	genDataCommentVotesMax = 30
+
+	// genDataDcrdataTimeout is the amount of time that the data
+	// generator waits for the dcrdata connection to be established.
+	genDataDcrdataTimeout = time.Minute
+)
+
+var (
+	// genDataStatuses contains the proposal statuses that are rotated
+	// through when the proposals are generated.
+	genDataStatuses = []pi.PropStatusT{
+		pi.PropStatusUnvetted,
+		pi.PropStatusUnvettedCensored,
+		pi.PropStatusUnderReview,
+		pi.PropStatusCensored,
+		pi.PropStatusAbandoned,
+		pi.PropStatusVoteAuthorized,
+		pi.PropStatusVoteStarted,
+		pi.PropStatusApproved,
+		pi.PropStatusRejected,
+	}
+
+	// genDataWords contains the words that are used to generate the
+	// proposal and comment text.
+	genDataWords = strings.Fields(`lorem ipsum dolor sit amet consectetur
+		adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore
+		magna aliqua enim ad minim veniam quis nostrud exercitation ullamco
+		laboris nisi aliquip ex ea commodo consequat duis aute irure in
+		reprehenderit voluptate velit esse cillum fugiat nulla pariatur
+		excepteur sint occaecat cupidatat non proident sunt culpa qui officia
+		deserunt mollit anim id est laborum`)
+)
+
+// genUser is a user that is created by the data generator. The users only
+// exist in politeiad. They are not added to the politeiawww user database.
+type genUser struct {
+	id       string
+	identity identity.FullIdentity
+}
+
+// publicKey returns the hex encoded public key of the user.
+func (u *genUser) publicKey() string {
+	return u.identity.Public.String()
+}
+
+// sign returns the hex encoded signature of the message.
+func (u *genUser) sign(msg string) string {
+	sig := u.identity.SignMessage([]byte(msg))
+	return hex.EncodeToString(sig[:])
+}
+
+// dataGen populates the backend with a deterministic dataset. All random
+// choices, user identities, and text are derived from the seed. Record tokens
+// and timestamps are assigned by the backend and differ between runs.
+type dataGen struct {
+	backend backend.Backend
+	rand    *rand.Rand
+
+	admin  genUser
+	users  []genUser
+	voters []genUser
+
+	// Plugin settings that the generated data must satisfy
+	amountMin    uint64
+	amountMax    uint64
+	startDateMin int64
+	endDateMax   int64
+	domains      []string
+	voteDuration uint32
+	linkByMin    int64
+	linkByMax    int64
+	voterList    string
+}
+
+// genData populates the backend with a deterministic dataset that is generated
+// from the provided seed. The dataset contains proposals in all statuses, an
+// RFP with submissions, finished votes, and deep comment trees.
+//
+// The votes are cast using a generated voter list, so the ticketvote plugin
+// must use the voter list eligibility with a local voter list file. The file
+// is written by the data generator. The early finish setting must be enabled
+// so that the votes are finished as soon as all voters have voted.
+func genData(b backend.Backend, seed int64) error {
+	g := &dataGen{
+		backend: b,
+		rand:    rand.New(rand.NewSource(seed)),
+	}
+	err := g.loadSettings()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Generating data using seed %v", seed)
+
+	// Setup the users
+	g.admin, err = g.newUser()
+	if err != nil {
+		return err
+	}
+	g.users = make([]genUser, 0, genDataUsers)
+	for i := 0; i < genDataUsers; i++ {
+		u, err := g.newUser()
+		if err != nil {
+			return err
+		}
+		g.users = append(g.users, u)
+	}
+	g.voters = make([]genUser, 0, genDataVoters)
+	for i := 0; i < genDataVoters; i++ {
+		u, err := g.newUser()
+		if err != nil {
+			return err
+		}
+		g.voters = append(g.voters, u)
+	}
+	voterList, err := g.writeVoterList()
+	if err != nil {
+		return err
+	}
+
+	// Votes cannot be started until the dcrdata connection has been
+	// established.
+	err = g.waitForDcrdata()
+	if err != nil {
+		return err
+	}
+
+	// Generate the proposals
+	counts := make(map[pi.PropStatusT]int, len(genDataStatuses))
+	for i := 0; i < genDataProposals; i++ {
+		s := genDataStatuses[i%len(genDataStatuses)]
+		token, err := g.proposal(s, voterList)
+		if err != nil {
+			return fmt.Errorf("proposal %v %v: %v", i+1, s, err)
+		}
+		counts[s]++
+
+		log.Infof("Generated proposal %v/%v %v: %v",
+			i+1, genDataProposals, token, s)
+	}
+
+	// Generate an RFP and its submissions
+	err = g.rfp(voterList)
+	if err != nil {
+		return fmt.Errorf("rfp: %v", err)
+	}
+
+	for _, s := range genDataStatuses {
+		log.Infof("%-18v: %v", s, counts[s])
+	}
+	log.Infof("%-18v: 1 with %v submissions", "rfp", genDataRFPSubmissions)
+
+	return nil
+}
+
+// loadSettings loads the plugin settings that the generated data must satisfy
+// and verifies that the required plugins have been registered.
+func (g *dataGen) loadSettings() error {
+	settings := make(map[string]map[string]string, 8) // [pluginID][key]value
+	for _, p := range g.backend.PluginInventory() {
+		s := make(map[string]string, len(p.Settings))
+		for _, v := range p.Settings {
+			s[v.Key] = v.Value
+		}
+		settings[p.ID] = s
+	}
+	for _, v := range []string{pi.PluginID, comments.PluginID,
+		ticketvote.PluginID, usermd.PluginID, dcrdata.PluginID} {
+		if _, ok := settings[v]; !ok {
+			return fmt.Errorf("the %v plugin must be registered", v)
+		}
+	}
+
+	// Verify the ticketvote settings
+	tv := settings[ticketvote.PluginID]
+	switch {
+	case tv[ticketvote.SettingKeyEligibility] != ticketvote.EligibilityVoterList:
+		return fmt.Errorf("ticketvote setting %v must be %v",
+			ticketvote.SettingKeyEligibility, ticketvote.EligibilityVoterList)
+	case tv[ticketvote.SettingKeyEarlyFinish] != "true":
+		return fmt.Errorf("ticketvote setting %v must be enabled",
+			ticketvote.SettingKeyEarlyFinish)
+	case tv[ticketvote.SettingKeyVoterList] == "",
+		strings.HasPrefix(tv[ticketvote.SettingKeyVoterList], "http://"),
+		strings.HasPrefix(tv[ticketvote.SettingKeyVoterList], "https://"):
+		return fmt.Errorf("ticketvote setting %v must be a file path",
+			ticketvote.SettingKeyVoterList)
+	}
+	g.voterList = tv[ticketvote.SettingKeyVoterList]
+
+	// Parse the settings
+	var err error
+	parseUint := func(pluginID, key string) uint64 {
+		if err != nil {
+			return 0
+		}
+		var u uint64
+		u, err = strconv.ParseUint(settings[pluginID][key], 10, 64)
+		if err != nil {
+			err = fmt.Errorf("%v setting %v: %v", pluginID, key, err)
+		}
+		return u
+	}
+	g.amountMin = parseUint(pi.PluginID, pi.SettingKeyProposalAmountMin)
+	g.amountMax = parseUint(pi.PluginID, pi.SettingKeyProposalAmountMax)
+	g.startDateMin = int64(parseUint(pi.PluginID,
+		pi.SettingKeyProposalStartDateMin))
+	g.endDateMax = int64(parseUint(pi.PluginID,
+		pi.SettingKeyProposalEndDateMax))
+	g.voteDuration = uint32(parseUint(ticketvote.PluginID,
+		ticketvote.SettingKeyVoteDurationMin))
+	g.linkByMin = int64(parseUint(ticketvote.PluginID,
+		ticketvote.SettingKeyLinkByPeriodMin))
+	g.linkByMax = int64(parseUint(ticketvote.PluginID,
+		ticketvote.SettingKeyLinkByPeriodMax))
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal([]byte(settings[pi.PluginID][pi.SettingKeyProposalDomains]),
+		&g.domains)
+	if err != nil {
+		return fmt.Errorf("pi setting %v: %v",
+			pi.SettingKeyProposalDomains, err)
+	}
+	if len(g.domains) == 0 {
+		return fmt.Errorf("pi setting %v: no domains found",
+			pi.SettingKeyProposalDomains)
+	}
+
+	return nil
+}
+
+// newUser returns a new user whose user ID and identity are derived from the
+// random source.
+func (g *dataGen) newUser() (genUser, error) {
+	id, err := uuid.NewRandomFromReader(g.rand)
+	if err != nil {
+		return genUser{}, err
+	}
+	pub, priv, err := ed25519.GenerateKey(g.rand)
+	if err != nil {
+		return genUser{}, err
+	}
+	u := genUser{
+		id: id.String(),
+	}
+	copy(u.identity.Public.Key[:], pub)
+	copy(u.identity.PrivateKey[:], priv)
+	return u, nil
+}
+
+// writeVoterList writes the voter list of the generated voters to the voter
+// list file of the ticketvote plugin and returns the tickets of each voter.
+// An existing voter list file is only replaced if it is identical, i.e. it
+// was written by a previous run that used the same seed.
+func (g *dataGen) writeVoterList() (map[string][]string, error) {
+	var (
+		b       bytes.Buffer
+		tickets = make(map[string][]string, len(g.voters)) // [userID]tickets
+	)
+	b.WriteString("# Generated voter list\n")
+	for i, v := range g.voters {
+		weight := 1 + g.rand.Intn(5)
+		fmt.Fprintf(&b, "voter%v,%v,%v\n", i+1, v.publicKey(), weight)
+
+		// The ticket hashes are derived from the public key of the
+		// voter. See the ticketvote EligibilityVoterList docs.
+		ts := make([]string, 0, weight)
+		for j := 0; j < weight; j++ {
+			msg := v.publicKey() + strconv.Itoa(j)
+			ts = append(ts, hex.EncodeToString(util.Digest([]byte(msg))))
+		}
+		tickets[v.id] = ts
+	}
+
+	current, err := os.ReadFile(g.voterList)
+	switch {
+	case err == nil:
+		if !bytes.Equal(current, b.Bytes()) {
+			return nil, fmt.Errorf("voter list %v already exists; "+
+				"remove it to generate data with a new seed", g.voterList)
+		}
+		return tickets, nil
+	case errors.Is(err, os.ErrNotExist):
+		// Continue
+	default:
+		return nil, err
+	}
+	err = os.WriteFile(g.voterList, b.Bytes(), 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Voter list written to %v", g.voterList)
+
+	return tickets, nil
+}
+
+// waitForDcrdata waits for the dcrdata plugin to connect to dcrdata. The
+// ticketvote plugin requires a current best block to start votes.
+func (g *dataGen) waitForDcrdata() error {
+	payload, err := json.Marshal(dcrdata.BestBlock{})
+	if err != nil {
+		return err
+	}
+	timeout := time.Now().Add(genDataDcrdataTimeout)
+	for {
+		reply, err := g.backend.PluginRead(nil, dcrdata.PluginID,
+			dcrdata.CmdBestBlock, string(payload))
+		if err != nil {
+			return err
+		}
+		var bbr dcrdata.BestBlockReply
+		err = json.Unmarshal([]byte(reply), &bbr)
+		if err != nil {
+			return err
+		}
+		if bbr.Status == dcrdata.StatusConnected && bbr.Height > 0 {
+			return nil
+		}
+		if time.Now().After(timeout) {
+			return fmt.Errorf("dcrdata connection timed out")
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// proposal generates a proposal with the provided status and returns its
+// token.
+func (g *dataGen) proposal(s pi.PropStatusT, tickets map[string][]string) (string, error) {
+	author := g.users[g.rand.Intn(len(g.users))]
+	r, err := g.recordNew(author, g.proposalFiles(nil))
+	if err != nil {
+		return "", err
+	}
+	token := r.RecordMetadata.Token
+	version := r.RecordMetadata.Version
+
+	// Handle the statuses that are not public
+	switch s {
+	case pi.PropStatusUnvetted:
+		return token, nil
+	case pi.PropStatusUnvettedCensored:
+		return token, g.setStatus(token, version, backend.StatusCensored,
+			"The proposal does not follow the proposal guidelines.")
+	}
+
+	// All other proposals are made public and receive comments
+	err = g.setStatus(token, version, backend.StatusPublic, "")
+	if err != nil {
+		return "", err
+	}
+	err = g.comments(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Handle the public statuses
+	switch s {
+	case pi.PropStatusUnderReview:
+		return token, nil
+	case pi.PropStatusCensored:
+		return token, g.setStatus(token, version, backend.StatusCensored,
+			"The proposal contains spam.")
+	case pi.PropStatusAbandoned:
+		return token, g.setStatus(token, version, backend.StatusArchived,
+			"The proposal author is no longer able to complete the work.")
+	}
+
+	// All other proposals have their vote authorized
+	err = g.authorize(author, token, version)
+	if err != nil {
+		return "", err
+	}
+	if s == pi.PropStatusVoteAuthorized {
+		return token, nil
+	}
+	err = g.voteStart(token, version)
+	if err != nil {
+		return "", err
+	}
+
+	// Cast the votes. Only some of the voters vote on the proposals
+	// whose vote remains active.
+	var (
+		voters  = g.voters
+		voteBit = "1" // Approve
+	)
+	switch s {
+	case pi.PropStatusVoteStarted:
+		voters = voters[:len(voters)-1]
+		if g.rand.Intn(2) == 0 {
+			voteBit = "2" // Reject
+		}
+	case pi.PropStatusRejected:
+		voteBit = "2"
+	}
+	return token, g.castBallot(token, voters, tickets, voteBit)
+}
+
+// rfp generates an RFP whose vote has been approved and the RFP submissions.
+// The submissions are left under review since the runoff vote can only be
+// started once the RFP deadline has passed.
+func (g *dataGen) rfp(tickets map[string][]string) error {
+	// The RFP deadline is set to the middle of the allowed link by
+	// period so that it is still valid when the submissions are made
+	// public.
+	linkBy := time.Now().Unix() + (g.linkByMin+g.linkByMax)/2
+	author := g.users[g.rand.Intn(len(g.users))]
+	r, err := g.recordNew(author, g.proposalFiles(&ticketvote.VoteMetadata{
+		LinkBy: linkBy,
+	}))
+	if err != nil {
+		return err
+	}
+	token := r.RecordMetadata.Token
+	version := r.RecordMetadata.Version
+	err = g.setStatus(token, version, backend.StatusPublic, "")
+	if err != nil {
+		return err
+	}
+	err = g.comments(token)
+	if err != nil {
+		return err
+	}
+	err = g.authorize(author, token, version)
+	if err != nil {
+		return err
+	}
+	err = g.voteStart(token, version)
+	if err != nil {
+		return err
+	}
+	err = g.castBallot(token, g.voters, tickets, "1")
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Generated rfp %v", token)
+
+	// Generate the submissions
+	for i := 0; i < genDataRFPSubmissions; i++ {
+		author := g.users[g.rand.Intn(len(g.users))]
+		r, err := g.recordNew(author, g.proposalFiles(&ticketvote.VoteMetadata{
+			LinkTo: token,
+		}))
+		if err != nil {
+			return err
+		}
+		t := r.RecordMetadata.Token
+		err = g.setStatus(t, r.RecordMetadata.Version, backend.StatusPublic, "")
+		if err != nil {
+			return err
+		}
+		err = g.comments(t)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Generated rfp submission %v/%v %v",
+			i+1, genDataRFPSubmissions, t)
+	}
+
+	return nil
+}
+
+// proposalFiles returns the files of a new proposal. The proposal is an RFP
+// or an RFP submission if vote metadata is provided.
+func (g *dataGen) proposalFiles(vm *ticketvote.VoteMetadata) []backend.File {
+	pm := pi.ProposalMetadata{
+		Name:   g.title(),
+		Domain: g.domains[g.rand.Intn(len(g.domains))],
+	}
+	if vm == nil || vm.LinkBy == 0 {
+		// RFPs do not have an amount or a schedule. The start date
+		// is a week after the minimum start date and the end date is
+		// at most three months later.
+		amountRange := int64(g.amountMax - g.amountMin)
+		pm.Amount = g.amountMin + uint64(g.rand.Int63n(amountRange+1))
+		pm.StartDate = time.Now().Unix() + g.startDateMin + 604800
+		endDate := pm.StartDate + 7776000
+		if max := time.Now().Unix() + g.endDateMax - 1; endDate > max {
+			endDate = max
+		}
+		pm.EndDate = endDate
+	}
+
+	var index strings.Builder
+	index.WriteString(pm.Name + "\n\n")
+	for i := 0; i < 2+g.rand.Intn(4); i++ {
+		index.WriteString(g.text(20+g.rand.Intn(60)) + "\n\n")
+	}
+
+	files := []backend.File{
+		g.file(pi.FileNameIndexFile, []byte(index.String())),
+		g.jsonFile(pi.FileNameProposalMetadata, pm),
+	}
+	if vm != nil {
+		files = append(files, g.jsonFile(ticketvote.FileNameVoteMetadata, vm))
+	}
+	return files
+}
+
+// file returns a backend file for the provided payload.
+func (g *dataGen) file(name string, payload []byte) backend.File {
+	return backend.File{
+		Name:    name,
+		MIME:    "text/plain; charset=utf-8",
+		Digest:  hex.EncodeToString(util.Digest(payload)),
+		Payload: base64.StdEncoding.EncodeToString(payload),
+	}
+}
+
+// jsonFile returns a backend file for the JSON encoding of the provided
+// structure.
+func (g *dataGen) jsonFile(name string, v interface{}) backend.File {
+	// Marshalling a plain struct cannot fail
+	b, _ := json.Marshal(v)
+	return g.file(name, b)
+}
+
+// title returns a random proposal title.
+func (g *dataGen) title() string {
+	words := strings.Fields(g.text(3 + g.rand.Intn(3)))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// text returns the provided number of random words.
+func (g *dataGen) text(words int) string {
+	s := make([]string, 0, words)
+	for i := 0; i < words; i++ {
+		s = append(s, genDataWords[g.rand.Intn(len(genDataWords))])
+	}
+	return strings.Join(s, " ")
+}
+
+// recordNew submits a new record that is authored by the provided user.
+func (g *dataGen) recordNew(author genUser, files []backend.File) (*backend.Record, error) {
+	digests := make([]string, 0, len(files))
+	for _, v := range files {
+		digests = append(digests, v.Digest)
+	}
+	mr, err := util.MerkleRoot(digests)
+	if err != nil {
+		return nil, err
+	}
+	um := usermd.UserMetadata{
+		UserID:    author.id,
+		PublicKey: author.publicKey(),
+		Signature: author.sign(hex.EncodeToString(mr[:])),
+	}
+	b, err := json.Marshal(um)
+	if err != nil {
+		return nil, err
+	}
+	metadata := []backend.MetadataStream{
+		{
+			PluginID: usermd.PluginID,
+			StreamID: usermd.StreamIDUserMetadata,
+			Payload:  string(b),
+		},
+	}
+	return g.backend.RecordNew(metadata, files)
+}
+
+// setStatus sets the status of a record using the admin identity.
+func (g *dataGen) setStatus(token string, version uint32, status backend.StatusT, reason string) error {
+	t, err := hex.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	s := strconv.FormatUint(uint64(status), 10)
+	v := strconv.FormatUint(uint64(version), 10)
+	scm := usermd.StatusChangeMetadata{
+		Token:     token,
+		Version:   version,
+		Status:    uint32(status),
+		Reason:    reason,
+		PublicKey: g.admin.publicKey(),
+		Signature: g.admin.sign(token + v + s + reason),
+		Timestamp: time.Now().Unix(),
+	}
+	b, err := json.Marshal(scm)
+	if err != nil {
+		return err
+	}
+	mdAppend := []backend.MetadataStream{
+		{
+			PluginID: usermd.PluginID,
+			StreamID: usermd.StreamIDStatusChanges,
+			Payload:  string(b),
+		},
+	}
+	_, err = g.backend.RecordSetStatus(t, status, mdAppend,
+		[]backend.MetadataStream{})
+	return err
+}
+
+// comments generates a comment tree and comment votes on a public record.
+// New comments reply to the previous comment half of the time in order to
+// generate deep comment trees.
+func (g *dataGen) comments(token string) error {
+	t, err := hex.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	var (
+		count   = g.rand.Intn(genDataCommentsMax + 1)
+		authors = make([]genUser, 0, count) // Indexed by comment ID - 1
+	)
+	for i := 0; i < count; i++ {
+		var parentID uint32
+		switch r := g.rand.Intn(4); {
+		case i == 0 || r == 0:
+			// New comment thread
+		case r == 1:
+			// Reply to a random comment
+			parentID = uint32(1 + g.rand.Intn(i))
+		default:
+			// Reply to the previous comment
+			parentID = uint32(i)
+		}
+		u := g.users[g.rand.Intn(len(g.users))]
+		text := g.text(5 + g.rand.Intn(40))
+		state := comments.RecordStateVetted
+		msg := strconv.FormatUint(uint64(state), 10) + token +
+			strconv.FormatUint(uint64(parentID), 10) + text
+		n := comments.New{
+			UserID:    u.id,
+			State:     state,
+			Token:     token,
+			ParentID:  parentID,
+			Comment:   text,
+			PublicKey: u.publicKey(),
+			Signature: u.sign(msg),
+		}
+		b, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		_, err = g.backend.PluginWrite(t, comments.PluginID,
+			comments.CmdNew, string(b))
+		if err != nil {
+			return err
+		}
+		authors = append(authors, u)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	// Cast comment votes. Users cannot vote on their own comments and
+	// only cast a single vote on a comment.
+	voted := make(map[string]struct{}, genDataCommentVotesMax)
+	for i := 0; i < g.rand.Intn(genDataCommentVotesMax+1); i++ {
+		var (
+			commentID = uint32(1 + g.rand.Intn(count))
+			u         = g.users[g.rand.Intn(len(g.users))]
+			key       = u.id + strconv.FormatUint(uint64(commentID), 10)
+			vote      = comments.VoteUpvote
+		)
+		if _, ok := voted[key]; ok || u.id == authors[commentID-1].id {
+			continue
+		}
+		voted[key] = struct{}{}
+		if g.rand.Intn(3) == 0 {
+			vote = comments.VoteDownvote
+		}
+		state := comments.RecordStateVetted
+		msg := strconv.FormatUint(uint64(state), 10) + token +
+			strconv.FormatUint(uint64(commentID), 10) +
+			strconv.FormatInt(int64(vote), 10)
+		v := comments.Vote{
+			UserID:    u.id,
+			State:     state,
+			Token:     token,
+			CommentID: commentID,
+			Vote:      vote,
+			PublicKey: u.publicKey(),
+			Signature: u.sign(msg),
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = g.backend.PluginWrite(t, comments.PluginID,
+			comments.CmdVote, string(b))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authorize authorizes the vote of a record on behalf of the record author.
+func (g *dataGen) authorize(author genUser, token string, version uint32) error {
+	v := strconv.FormatUint(uint64(version), 10)
+	a := ticketvote.Authorize{
+		Token:     token,
+		Version:   version,
+		Action:    ticketvote.AuthActionAuthorize,
+		PublicKey: author.publicKey(),
+		Signature: author.sign(token + v + string(ticketvote.AuthActionAuthorize)),
+	}
+	return g.pluginWrite(token, ticketvote.PluginID, ticketvote.CmdAuthorize, a)
+}
+
+// voteStart starts a standard vote on a record using the admin identity. The
+// vote uses the minimum vote duration.
+func (g *dataGen) voteStart(token string, version uint32) error {
+	vp := ticketvote.VoteParams{
+		Token:            token,
+		Version:          version,
+		Type:             ticketvote.VoteTypeStandard,
+		Mask:             0x03,
+		Duration:         g.voteDuration,
+		QuorumPercentage: 20,
+		PassPercentage:   60,
+		Options: []ticketvote.VoteOption{
+			{
+				ID:          ticketvote.VoteOptionIDApprove,
+				Description: "Approve the proposal",
+				Bit:         0x01,
+			},
+			{
+				ID:          ticketvote.VoteOptionIDReject,
+				Description: "Reject the proposal",
+				Bit:         0x02,
+			},
+		},
+	}
+	b, err := json.Marshal(vp)
+	if err != nil {
+		return err
+	}
+	s := ticketvote.Start{
+		Starts: []ticketvote.StartDetails{
+			{
+				Params:    vp,
+				PublicKey: g.admin.publicKey(),
+				Signature: g.admin.sign(hex.EncodeToString(util.Digest(b))),
+			},
+		},
+	}
+	return g.pluginWrite(token, ticketvote.PluginID, ticketvote.CmdStart, s)
+}
+
+// castBallot casts the votes of all tickets of the provided voters.
+func (g *dataGen) castBallot(token string, voters []genUser, tickets map[string][]string, voteBit string) error {
+	ballot := make([]ticketvote.CastVote, 0, len(voters)*5)
+	for _, v := range voters {
+		for _, ticket := range tickets[v.id] {
+			ballot = append(ballot, ticketvote.CastVote{
+				Token:     token,
+				Ticket:    ticket,
+				VoteBit:   voteBit,
+				Signature: v.sign(token + ticket + voteBit),
+			})
+		}
+	}
+	t, err := hex.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(ticketvote.CastBallot{Ballot: ballot})
+	if err != nil {
+		return err
+	}
+	reply, err := g.backend.PluginWrite(t, ticketvote.PluginID,
+		ticketvote.CmdCastBallot, string(b))
+	if err != nil {
+		return err
+	}
+	var cbr ticketvote.CastBallotReply
+	err = json.Unmarshal([]byte(reply), &cbr)
+	if err != nil {
+		return err
+	}
+	for _, v := range cbr.Receipts {
+		if v.ErrorCode != nil {
+			return fmt.Errorf("vote %v failed: %v", v.Ticket, v.ErrorContext)
+		}
+	}
+	return nil
+}
+
+// pluginWrite executes a plugin write command using the JSON encoding of the
+// provided payload.
+func (g *dataGen) pluginWrite(token, pluginID, cmd string, payload interface{}) error {
+	t, err := hex.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = g.backend.PluginWrite(t, pluginID, cmd, string(b))
+	return err
+}
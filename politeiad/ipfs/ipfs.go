@@ -0,0 +1,234 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package ipfs pins record files to an IPFS node so that the files of public
+// records remain available independently of the politeia deployment.
+package ipfs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// routeAdd is the IPFS HTTP API route that is used to add and pin
+	// a file. CIDv1 is requested so that the returned CIDs are safe to
+	// use in subdomain gateway URLs.
+	routeAdd = "/api/v0/add?pin=true&cid-version=1"
+
+	// requestTimeout is the timeout of an IPFS API request, including
+	// any retries.
+	requestTimeout = 2 * time.Minute
+
+	// requestRetries is the number of times that a failed IPFS API
+	// request is retried. Pinning a file is idempotent so it is safe
+	// to retry.
+	requestRetries = 2
+
+	// indexFilename is the filename of the file that contains the CIDs of
+	// the pinned files. It is saved to the data directory.
+	indexFilename = "ipfs.json"
+)
+
+// Pinner pins record files to an IPFS node and keeps an index of the CIDs of
+// the pinned files. Files are indexed by their SHA256 digest, which means
+// that identical files are only pinned once.
+type Pinner struct {
+	sync.RWMutex
+	host   string
+	client *http.Client
+	index  string            // Index file path
+	cids   map[string]string // [digest]CID
+}
+
+// New returns a new Pinner that pins files to the IPFS node that serves the
+// IPFS HTTP API at the provided host. The CID index is loaded from the
+// provided data directory.
+func New(host, dataDir string) (*Pinner, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid ipfs host scheme '%v'", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("ipfs host not found")
+	}
+
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
+		Timeout: requestTimeout,
+		Retries: requestRetries,
+		OnAttempt: func(a util.HTTPAttempt) {
+			if a.Retry {
+				log.Debugf("ipfs request failed, retrying: %v", a)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := Pinner{
+		host:   u.Scheme + "://" + u.Host,
+		client: client,
+		index:  filepath.Join(dataDir, indexFilename),
+		cids:   make(map[string]string),
+	}
+	b, err := os.ReadFile(p.index)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Nothing has been pinned yet
+	case err != nil:
+		return nil, err
+	default:
+		err = json.Unmarshal(b, &p.cids)
+		if err != nil {
+			return nil, fmt.Errorf("decode %v: %v", p.index, err)
+		}
+	}
+
+	log.Infof("IPFS host: %v", p.host)
+	log.Infof("IPFS pinned files: %v", len(p.cids))
+
+	return &p, nil
+}
+
+// CID returns the CID of the pinned file with the provided digest. An empty
+// string is returned if the file has not been pinned.
+func (p *Pinner) CID(digest string) string {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.cids[digest]
+}
+
+// Pin pins the provided record files in the background. Files that have
+// already been pinned are skipped. Failures are logged and do not affect the
+// caller since the files remain available from the politeia backend.
+func (p *Pinner) Pin(token string, files []backend.File) {
+	go func() {
+		err := p.pin(files)
+		if err != nil {
+			log.Errorf("ipfs pin %v: %v", token, err)
+		}
+	}()
+}
+
+// pin pins the provided record files and saves the resulting CIDs to the
+// index.
+func (p *Pinner) pin(files []backend.File) error {
+	var pinned int
+	for _, v := range files {
+		if p.CID(v.Digest) != "" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return fmt.Errorf("decode %v: %v", v.Name, err)
+		}
+		if hex.EncodeToString(util.Digest(b)) != v.Digest {
+			return fmt.Errorf("%v: digest mismatch", v.Name)
+		}
+		cid, err := p.add(v.Name, b)
+		if err != nil {
+			return fmt.Errorf("add %v: %v", v.Name, err)
+		}
+
+		p.Lock()
+		p.cids[v.Digest] = cid
+		p.Unlock()
+		pinned++
+
+		log.Debugf("Pinned %v %v", v.Digest, cid)
+	}
+	if pinned == 0 {
+		return nil
+	}
+
+	return p.save()
+}
+
+// save writes the CID index to disk. The index is written to a temporary
+// file first so that a failed write does not corrupt the existing index.
+func (p *Pinner) save() error {
+	p.Lock()
+	defer p.Unlock()
+
+	b, err := json.Marshal(p.cids)
+	if err != nil {
+		return err
+	}
+	tmp := p.index + ".tmp"
+	err = os.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.index)
+}
+
+// addReply is the reply to the IPFS add command.
+type addReply struct {
+	Name string `json:"Name"`
+	Hash string `json:"Hash"` // CID
+	Size string `json:"Size"`
+}
+
+// add adds and pins a file to the IPFS node and returns its CID.
+func (p *Pinner) add(name string, payload []byte) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fw, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	_, err = fw.Write(payload)
+	if err != nil {
+		return "", err
+	}
+	err = w.Close()
+	if err != nil {
+		return "", err
+	}
+
+	r, err := p.client.Post(p.host+routeAdd, w.FormDataContentType(), &body)
+	if err != nil {
+		return "", err
+	}
+	defer r.Body.Close()
+
+	rb, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	if r.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%v: %s", r.Status, bytes.TrimSpace(rb))
+	}
+	var ar addReply
+	err = json.Unmarshal(rb, &ar)
+	if err != nil {
+		return "", err
+	}
+	if ar.Hash == "" {
+		return "", fmt.Errorf("cid not found")
+	}
+
+	return ar.Hash, nil
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ipfs
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+func newFile(name, payload string) backend.File {
+	return backend.File{
+		Name:    name,
+		MIME:    "text/plain; charset=utf-8",
+		Digest:  hex.EncodeToString(util.Digest([]byte(payload))),
+		Payload: base64.StdEncoding.EncodeToString([]byte(payload)),
+	}
+}
+
+func TestPin(t *testing.T) {
+	// Setup an IPFS API server that uses the file digest as the CID
+	var adds int
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v0/add" || r.URL.Query().Get("pin") != "true" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			f, fh, err := r.FormFile("file")
+			if err != nil {
+				t.Error(err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			b, err := io.ReadAll(f)
+			if err != nil {
+				t.Error(err)
+			}
+			adds++
+			json.NewEncoder(w).Encode(addReply{
+				Name: fh.Filename,
+				Hash: "cid" + hex.EncodeToString(util.Digest(b)),
+			})
+		}))
+	defer s.Close()
+
+	dataDir := t.TempDir()
+	p, err := New(s.URL, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pin files
+	files := []backend.File{
+		newFile("index.md", "proposal"),
+		newFile("image.png", "image"),
+	}
+	err = p.pin(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range files {
+		want := "cid" + v.Digest
+		if got := p.CID(v.Digest); got != want {
+			t.Errorf("%v: got cid %v, want %v", v.Name, got, want)
+		}
+	}
+
+	// Files that have already been pinned should not be added again
+	err = p.pin(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adds != len(files) {
+		t.Errorf("got %v adds, want %v", adds, len(files))
+	}
+
+	// A file with a digest that does not match its payload should
+	// not be pinned.
+	f := newFile("bad.md", "bad")
+	f.Digest = files[0].Digest[:len(files[0].Digest)-1] + "0"
+	err = p.pin([]backend.File{f})
+	if err == nil {
+		t.Errorf("got nil error, want digest mismatch error")
+	}
+
+	// Verify that the index is loaded from disk
+	p, err = New(s.URL, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range files {
+		if p.CID(v.Digest) == "" {
+			t.Errorf("%v: cid not found after reload", v.Name)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name    string
+		host    string
+		wantErr bool
+	}{
+		{"valid", "http://127.0.0.1:5001", false},
+		{"invalid scheme", "ftp://127.0.0.1:5001", true},
+		{"no host", "http://", true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := New(v.host, t.TempDir())
+			switch {
+			case v.wantErr && err == nil:
+				t.Errorf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
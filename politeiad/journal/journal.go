@@ -0,0 +1,442 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package journal provides a write-ahead journal of plugin write commands.
+// Each command is appended to the journal before it is executed and the
+// result of the command is appended once it has finished executing. The
+// journal is append-only. A command that has no result in the journal was
+// either still executing or was interrupted, e.g. by a crash, which makes
+// the journal useful for diagnosing multi-blob operations that were only
+// partially applied. The journal contains the exact command payloads so
+// that failed commands can be replayed, unless the command was redacted by
+// the caller.
+//
+// The journal file is rotated once it exceeds the configured maximum size.
+// Only the most recently rotated file is retained, which caps the disk usage
+// of the journal at roughly twice the maximum size.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DirName is the recommended name of the directory that the journal
+	// is stored in.
+	DirName = "journal"
+
+	// filename is the filename of the journal file.
+	filename = "pluginwrites.journal"
+
+	// archiveFilename is the filename of the rotated journal file.
+	archiveFilename = filename + ".1"
+
+	// Journal line types
+	lineTypeCmd    = "cmd"
+	lineTypeResult = "result"
+)
+
+// StatusT represents the status of a journaled command.
+type StatusT uint32
+
+const (
+	// StatusInvalid is an invalid status.
+	StatusInvalid StatusT = 0
+
+	// StatusIncomplete indicates that a result was not journaled for the
+	// command. The command is either still executing or was interrupted
+	// before it finished.
+	StatusIncomplete StatusT = 1
+
+	// StatusSuccess indicates that the command executed successfully.
+	StatusSuccess StatusT = 2
+
+	// StatusFailed indicates that the command returned an error.
+	StatusFailed StatusT = 3
+)
+
+// Cmd contains the details of a plugin write command.
+type Cmd struct {
+	Caller   string `json:"caller"` // Remote address of the caller
+	Token    string `json:"token"`
+	PluginID string `json:"pluginid"`
+	Command  string `json:"command"`
+	Payload  string `json:"payload"`
+
+	// Redacted indicates that the payload and the reply of the command
+	// were not journaled.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// Entry is a journaled command and its result.
+type Entry struct {
+	ID        uint64  // Sequential entry ID
+	Timestamp int64   // Unix timestamp of when the cmd was journaled
+	Cmd       Cmd     // Command details
+	Status    StatusT // Command status
+	Completed int64   // Unix timestamp of when the result was journaled
+	Reply     string  // Reply payload
+	Error     string  // Error that the command returned
+}
+
+// Filter is used to query the journal. Entries with an ID that is less than or
+// equal to After are skipped. All other fields are optional and filter the
+// entries on the provided value. A Limit of zero returns all matching
+// entries.
+type Filter struct {
+	After    uint64
+	Token    string
+	PluginID string
+	Command  string
+	Status   StatusT
+	Limit    uint32
+}
+
+// line is a single line of the journal file. A cmd line is written before a
+// command is executed and a result line is written once it has finished
+// executing.
+type line struct {
+	Type      string `json:"type"`
+	ID        uint64 `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+
+	// Cmd is only populated for cmd lines
+	Cmd *Cmd `json:"cmd,omitempty"`
+
+	// Reply and Error are only populated for result lines
+	Reply string `json:"reply,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Journal is an append-only journal of plugin write commands.
+type Journal struct {
+	sync.Mutex
+	path        string
+	archivePath string
+	maxSize     int64 // In bytes, 0 disables rotation
+	size        int64 // Size of the journal file in bytes
+	file        *os.File
+	nextID      uint64
+}
+
+// New opens the journal that is stored in the provided directory. The
+// journal is created if it does not exist yet. The journal file is rotated
+// once it exceeds maxSize bytes. A maxSize of zero disables rotation.
+func New(dataDir string, maxSize int64) (*Journal, error) {
+	err := os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	j := Journal{
+		path:        filepath.Join(dataDir, filename),
+		archivePath: filepath.Join(dataDir, archiveFilename),
+		maxSize:     maxSize,
+		nextID:      1,
+	}
+
+	// Find the last entry ID
+	err = j.scan(func(l line) {
+		if l.ID >= j.nextID {
+			j.nextID = l.ID + 1
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Open the journal for appending
+	err = j.open()
+	if err != nil {
+		return nil, err
+	}
+
+	// Terminate a line that was only partially written so that the
+	// next line is not appended to it.
+	err = j.terminate()
+	if err != nil {
+		return nil, err
+	}
+	fi, err := j.file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	j.size = fi.Size()
+
+	log.Infof("Plugin journal: %v", j.path)
+
+	return &j, nil
+}
+
+// Close closes the journal.
+func (j *Journal) Close() error {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.file.Close()
+}
+
+// Begin journals a command before it is executed. The command must not be
+// executed if an error is returned. The returned entry ID must be provided to
+// Finish once the command has finished executing.
+func (j *Journal) Begin(c Cmd) (uint64, error) {
+	j.Lock()
+	defer j.Unlock()
+
+	id := j.nextID
+	err := j.append(line{
+		Type:      lineTypeCmd,
+		ID:        id,
+		Timestamp: time.Now().Unix(),
+		Cmd:       &c,
+	})
+	if err != nil {
+		return 0, err
+	}
+	j.nextID++
+
+	return id, nil
+}
+
+// Finish journals the result of a command. A nil error indicates that the
+// command executed successfully.
+func (j *Journal) Finish(id uint64, reply string, cmdErr error) error {
+	j.Lock()
+	defer j.Unlock()
+
+	l := line{
+		Type:      lineTypeResult,
+		ID:        id,
+		Timestamp: time.Now().Unix(),
+		Reply:     reply,
+	}
+	if cmdErr != nil {
+		l.Error = cmdErr.Error()
+	}
+	return j.append(l)
+}
+
+// Query returns the journal entries that match the provided filter, ordered
+// by entry ID.
+func (j *Journal) Query(f Filter) ([]Entry, error) {
+	var (
+		entries = make([]Entry, 0, 64)
+		index   = make(map[uint64]int, 64) // [id]entries index
+	)
+	err := j.scan(func(l line) {
+		if l.ID <= f.After {
+			return
+		}
+		switch l.Type {
+		case lineTypeCmd:
+			if l.Cmd == nil || !f.matches(*l.Cmd) {
+				return
+			}
+			index[l.ID] = len(entries)
+			entries = append(entries, Entry{
+				ID:        l.ID,
+				Timestamp: l.Timestamp,
+				Cmd:       *l.Cmd,
+				Status:    StatusIncomplete,
+			})
+		case lineTypeResult:
+			i, ok := index[l.ID]
+			if !ok {
+				return
+			}
+			e := &entries[i]
+			e.Completed = l.Timestamp
+			e.Reply = l.Reply
+			e.Error = l.Error
+			e.Status = StatusSuccess
+			if l.Error != "" {
+				e.Status = StatusFailed
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the status filter and the limit
+	filtered := make([]Entry, 0, len(entries))
+	for _, v := range entries {
+		if f.Status != StatusInvalid && v.Status != f.Status {
+			continue
+		}
+		filtered = append(filtered, v)
+		if f.Limit > 0 && len(filtered) == int(f.Limit) {
+			break
+		}
+	}
+
+	return filtered, nil
+}
+
+// matches returns whether the provided command matches the filter.
+func (f *Filter) matches(c Cmd) bool {
+	switch {
+	case f.Token != "" && c.Token != f.Token:
+		return false
+	case f.PluginID != "" && c.PluginID != f.PluginID:
+		return false
+	case f.Command != "" && c.Command != f.Command:
+		return false
+	}
+	return true
+}
+
+// append appends a line to the journal file and syncs the file to disk.
+//
+// This function must be called WITH the lock held.
+func (j *Journal) append(l line) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = j.file.Write(b)
+	if err != nil {
+		return err
+	}
+	err = j.file.Sync()
+	if err != nil {
+		return err
+	}
+	j.size += int64(len(b))
+
+	// Rotate the journal file once it exceeds the max size. The line
+	// has already been persisted so a rotation failure is logged
+	// instead of being returned.
+	if j.maxSize > 0 && j.size >= j.maxSize {
+		err = j.rotate()
+		if err != nil {
+			log.Errorf("Journal rotation failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// open opens the journal file for appending.
+//
+// This function must be called WITH the lock held.
+func (j *Journal) open() error {
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	j.file = f
+	return nil
+}
+
+// rotate moves the journal file to the archive file, replacing the previous
+// archive file, and starts a new journal file.
+//
+// This function must be called WITH the lock held.
+func (j *Journal) rotate() error {
+	err := j.file.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(j.path, j.archivePath)
+	if err != nil {
+		// Keep appending to the current journal file
+		if oerr := j.open(); oerr != nil {
+			return oerr
+		}
+		return err
+	}
+	err = j.open()
+	if err != nil {
+		return err
+	}
+	j.size = 0
+
+	log.Infof("Plugin journal rotated: %v", j.archivePath)
+
+	return nil
+}
+
+// terminate appends a newline to the journal file if the last line of the
+// file was only partially written.
+func (j *Journal) terminate() error {
+	fi, err := j.file.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() == 0 {
+		return nil
+	}
+	f, err := os.Open(j.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	last := make([]byte, 1)
+	_, err = f.ReadAt(last, fi.Size()-1)
+	if err != nil {
+		return err
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	_, err = j.file.Write([]byte{'\n'})
+	return err
+}
+
+// scan reads the archive file and the journal file and invokes the provided
+// function on every line, from oldest to newest.
+func (j *Journal) scan(fn func(line)) error {
+	err := scanFile(j.archivePath, fn)
+	if err != nil {
+		return err
+	}
+	return scanFile(j.path, fn)
+}
+
+// scanFile reads the provided journal file and invokes the provided function
+// on every line. A line that was only partially written, e.g. due to a crash,
+// is skipped.
+func scanFile(path string, fn func(line)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	// The lines are read using a reader instead of a scanner since
+	// plugin payloads, e.g. ballots, can exceed the maximum token
+	// size of a scanner.
+	r := bufio.NewReader(f)
+	for {
+		b, err := r.ReadBytes('\n')
+		switch {
+		case errors.Is(err, io.EOF):
+			if len(b) > 0 {
+				log.Warnf("Partial journal line skipped")
+			}
+			return nil
+		case err != nil:
+			return err
+		}
+		var l line
+		err = json.Unmarshal(b, &l)
+		if err != nil {
+			log.Warnf("Invalid journal line skipped: %v", err)
+			continue
+		}
+		fn(l)
+	}
+}
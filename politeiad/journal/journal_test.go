@@ -0,0 +1,181 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package journal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	dir := t.TempDir()
+	j, err := New(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Journal a successful cmd, a failed cmd, and a cmd that never
+	// finishes.
+	cmds := []Cmd{
+		{Caller: "a", Token: "t1", PluginID: "ticketvote",
+			Command: "start", Payload: "{}"},
+		{Caller: "a", Token: "t2", PluginID: "ticketvote",
+			Command: "start", Payload: "{}"},
+		{Caller: "b", Token: "t1", PluginID: "comments",
+			Command: "new", Payload: "{}"},
+	}
+	ids := make([]uint64, 0, len(cmds))
+	for _, v := range cmds {
+		id, err := j.Begin(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	err = j.Finish(ids[0], "reply", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = j.Finish(ids[1], "", errors.New("failed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash that occurred while a line was being written
+	err = j.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, filename),
+		os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.Write([]byte(`{"type":"cmd","id":4`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Reopen the journal. The entry IDs should continue where they
+	// left off and the partial line should not affect new lines.
+	j, err = New(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	id, err := j.Begin(cmds[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ids[2]+1 {
+		t.Errorf("got id %v, want %v", id, ids[2]+1)
+	}
+
+	var tests = []struct {
+		name   string
+		filter Filter
+		want   []uint64
+	}{
+		{"all", Filter{}, []uint64{1, 2, 3, 4}},
+		{"after", Filter{After: 2}, []uint64{3, 4}},
+		{"limit", Filter{Limit: 1}, []uint64{1}},
+		{"token", Filter{Token: "t1"}, []uint64{1, 3, 4}},
+		{"plugin cmd", Filter{PluginID: "ticketvote", Command: "start"},
+			[]uint64{1, 2}},
+		{"success", Filter{Status: StatusSuccess}, []uint64{1}},
+		{"failed", Filter{Status: StatusFailed}, []uint64{2}},
+		{"incomplete", Filter{Status: StatusIncomplete}, []uint64{3, 4}},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			entries, err := j.Query(v.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(v.want) {
+				t.Fatalf("got %v entries, want %v", len(entries), len(v.want))
+			}
+			for i, e := range entries {
+				if e.ID != v.want[i] {
+					t.Errorf("got id %v, want %v", e.ID, v.want[i])
+				}
+			}
+		})
+	}
+
+	// Verify the result fields
+	entries, err := j.Query(Filter{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Reply != "reply" || entries[0].Completed == 0 {
+		t.Errorf("unexpected success entry %+v", entries[0])
+	}
+	if entries[1].Error != "failed" {
+		t.Errorf("got error %q, want 'failed'", entries[1].Error)
+	}
+}
+
+func TestJournalRotate(t *testing.T) {
+	dir := t.TempDir()
+
+	// Use a max size that results in a rotation on every cmd line
+	j, err := New(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { j.Close() }()
+
+	c := Cmd{Token: "t1", PluginID: "ticketvote", Command: "start"}
+	for i := 0; i < 3; i++ {
+		id, err := j.Begin(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = j.Finish(id, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the lines of the current and the archive file remain. The
+	// result line of the last cmd is in the archive file and the
+	// current file is empty.
+	entries, err := j.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %v entries, want 0", len(entries))
+	}
+	_, err = os.Stat(filepath.Join(dir, archiveFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Entry IDs continue across rotations and restarts
+	id, err := j.Begin(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 4 {
+		t.Errorf("got id %v, want 4", id)
+	}
+	j.Close()
+	j, err = New(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err = j.Begin(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 5 {
+		t.Errorf("got id %v, want 5", id)
+	}
+}
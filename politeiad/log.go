@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/decred/politeia/politeiad/access"
 	"github.com/decred/politeia/politeiad/backend/gitbe"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/comments"
@@ -20,6 +21,8 @@ import (
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/mysql"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/tlog"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/tstore"
+	"github.com/decred/politeia/politeiad/ipfs"
+	"github.com/decred/politeia/politeiad/journal"
 	"github.com/decred/politeia/politeiawww/wsdcrdata"
 	"github.com/decred/slog"
 	"github.com/jrick/logrotate/rotator"
@@ -60,6 +63,9 @@ var (
 	wsdcrdataLog = backendLog.Logger("WSDD")
 	pluginLog    = backendLog.Logger("PLUG")
 	tlogLog      = backendLog.Logger("TLOG")
+	ipfsLog      = backendLog.Logger("IPFS")
+	accessLog    = backendLog.Logger("ACCS")
+	journalLog   = backendLog.Logger("JRNL")
 )
 
 // Initialize package-global logger variables.
@@ -83,6 +89,9 @@ func init() {
 
 	// Other loggers
 	wsdcrdata.UseLogger(wsdcrdataLog)
+	ipfs.UseLogger(ipfsLog)
+	access.UseLogger(accessLog)
+	journal.UseLogger(journalLog)
 }
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -95,6 +104,9 @@ var subsystemLoggers = map[string]slog.Logger{
 	"WSDD": wsdcrdataLog,
 	"PLUG": pluginLog,
 	"TLOG": tlogLog,
+	"IPFS": ipfsLog,
+	"ACCS": accessLog,
+	"JRNL": journalLog,
 }
 
 // initLogRotator initializes the logging rotater to write logs to logFile and
@@ -0,0 +1,221 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+)
+
+// pageReply returns the page of the plugin reply payload that was requested
+// by the provided page request. The reply payload must be a JSON object that
+// contains exactly one array or object field. This field is paged. All other
+// fields are returned as is.
+//
+// A v2 UserErrorReply is returned if the page request is invalid or if the
+// reply payload cannot be paged.
+func pageReply(payload string, pr v2.PageRequest) (string, *v2.PageReply, error) {
+	// Verify page size
+	size := pr.Size
+	switch {
+	case size == 0:
+		size = v2.PluginReadPageSizeDefault
+	case size > v2.PluginReadPageSizeMax:
+		return "", nil, v2.UserErrorReply{
+			ErrorCode: v2.ErrorCodePageSizeExceeded,
+			ErrorContext: fmt.Sprintf("max page size is %v",
+				v2.PluginReadPageSizeMax),
+		}
+	}
+
+	// Decode the page token
+	offset, err := pageTokenDecode(pr.Token)
+	if err != nil {
+		return "", nil, v2.UserErrorReply{
+			ErrorCode: v2.ErrorCodePageTokenInvalid,
+		}
+	}
+
+	// Find the field that will be paged
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal([]byte(payload), &fields)
+	if err != nil {
+		return "", nil, v2.UserErrorReply{
+			ErrorCode:    v2.ErrorCodePageInvalid,
+			ErrorContext: "reply payload is not a json object",
+		}
+	}
+	var (
+		fieldName string
+		found     int
+	)
+	for k, v := range fields {
+		if isPageable(v) {
+			fieldName = k
+			found++
+		}
+	}
+	if found != 1 {
+		return "", nil, v2.UserErrorReply{
+			ErrorCode: v2.ErrorCodePageInvalid,
+			ErrorContext: fmt.Sprintf("reply payload must contain exactly "+
+				"one array or object field; found %v", found),
+		}
+	}
+
+	// Page the field
+	page, total, err := pageField(fields[fieldName], offset, size)
+	if err != nil {
+		return "", nil, err
+	}
+	if offset > total {
+		return "", nil, v2.UserErrorReply{
+			ErrorCode: v2.ErrorCodePageTokenInvalid,
+		}
+	}
+	fields[fieldName] = page
+
+	// Prepare the reply
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, err
+	}
+	var reply v2.PageReply
+	next := offset + int(size)
+	if next < total {
+		reply.HasMore = true
+		reply.NextToken = pageTokenEncode(next)
+	}
+
+	return string(b), &reply, nil
+}
+
+// pageField returns the page of the provided JSON array or object that starts
+// at the provided offset, along with the total number of entries. A null
+// value is treated as an empty array.
+func pageField(raw json.RawMessage, offset int, size uint32) (json.RawMessage, int, error) {
+	raw = bytes.TrimSpace(raw)
+	switch raw[0] {
+	case 'n':
+		return json.RawMessage("[]"), 0, nil
+
+	case '[':
+		var entries []json.RawMessage
+		err := json.Unmarshal(raw, &entries)
+		if err != nil {
+			return nil, 0, err
+		}
+		if offset > len(entries) {
+			return nil, len(entries), nil
+		}
+		end := pageEnd(offset, size, len(entries))
+		b, err := json.Marshal(entries[offset:end])
+		if err != nil {
+			return nil, 0, err
+		}
+		return b, len(entries), nil
+
+	case '{':
+		var entries map[string]json.RawMessage
+		err := json.Unmarshal(raw, &entries)
+		if err != nil {
+			return nil, 0, err
+		}
+		if offset > len(entries) {
+			return nil, len(entries), nil
+		}
+		keys := make([]string, 0, len(entries))
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sortKeys(keys)
+		end := pageEnd(offset, size, len(keys))
+		page := make(map[string]json.RawMessage, end-offset)
+		for _, k := range keys[offset:end] {
+			page[k] = entries[k]
+		}
+		b, err := json.Marshal(page)
+		if err != nil {
+			return nil, 0, err
+		}
+		return b, len(entries), nil
+	}
+
+	return nil, 0, fmt.Errorf("field is not pageable: %s", raw)
+}
+
+// isPageable returns whether the provided JSON value is a value that can be
+// paged, i.e. an array, an object, or null.
+func isPageable(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return false
+	}
+	switch raw[0] {
+	case '[', '{':
+		return true
+	}
+	return bytes.Equal(raw, []byte("null"))
+}
+
+// pageEnd returns the end index of a page.
+func pageEnd(offset int, size uint32, total int) int {
+	end := offset + int(size)
+	if end > total {
+		end = total
+	}
+	return end
+}
+
+// sortKeys sorts the provided object keys. Keys that are unsigned integers,
+// such as comment IDs, are sorted numerically and are ordered before all other
+// keys. All other keys are sorted lexicographically.
+func sortKeys(keys []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		ni, erri := strconv.ParseUint(keys[i], 10, 64)
+		nj, errj := strconv.ParseUint(keys[j], 10, 64)
+		switch {
+		case erri == nil && errj == nil:
+			return ni < nj
+		case erri == nil:
+			return true
+		case errj == nil:
+			return false
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// pageTokenEncode encodes the provided page offset into an opaque page token.
+func pageTokenEncode(offset int) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.Itoa(offset)))
+}
+
+// pageTokenDecode decodes the provided page token into a page offset. An
+// empty token corresponds to the first page.
+func pageTokenDecode(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	return offset, nil
+}
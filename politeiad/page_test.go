@@ -0,0 +1,143 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+)
+
+func TestPageReply(t *testing.T) {
+	// Setup test payloads
+	var (
+		arrayPayload  = `{"votes":[1,2,3,4,5],"bestblock":10}`
+		objectPayload = `{"comments":{"10":"c","2":"b","1":"a"}}`
+		nullPayload   = `{"votes":null}`
+		twoFields     = `{"auths":[],"vote":{}}`
+		noFields      = `{"count":3}`
+	)
+
+	// Setup tests
+	var tests = []struct {
+		name    string
+		payload string
+		page    v2.PageRequest
+		reply   string // Expected reply payload
+		hasMore bool
+		err     v2.ErrorCodeT // Expected user error code
+	}{
+		{
+			"array first page",
+			arrayPayload,
+			v2.PageRequest{Size: 2},
+			`{"bestblock":10,"votes":[1,2]}`,
+			true,
+			v2.ErrorCodeInvalid,
+		},
+		{
+			"array last page",
+			arrayPayload,
+			v2.PageRequest{Token: pageTokenEncode(4), Size: 2},
+			`{"bestblock":10,"votes":[5]}`,
+			false,
+			v2.ErrorCodeInvalid,
+		},
+		{
+			"object numeric key ordering",
+			objectPayload,
+			v2.PageRequest{Size: 2},
+			`{"comments":{"1":"a","2":"b"}}`,
+			true,
+			v2.ErrorCodeInvalid,
+		},
+		{
+			"null field",
+			nullPayload,
+			v2.PageRequest{},
+			`{"votes":[]}`,
+			false,
+			v2.ErrorCodeInvalid,
+		},
+		{
+			"page size exceeded",
+			arrayPayload,
+			v2.PageRequest{Size: v2.PluginReadPageSizeMax + 1},
+			"",
+			false,
+			v2.ErrorCodePageSizeExceeded,
+		},
+		{
+			"page token invalid",
+			arrayPayload,
+			v2.PageRequest{Token: "zzz"},
+			"",
+			false,
+			v2.ErrorCodePageTokenInvalid,
+		},
+		{
+			"page token out of range",
+			arrayPayload,
+			v2.PageRequest{Token: pageTokenEncode(6)},
+			"",
+			false,
+			v2.ErrorCodePageTokenInvalid,
+		},
+		{
+			"multiple pageable fields",
+			twoFields,
+			v2.PageRequest{},
+			"",
+			false,
+			v2.ErrorCodePageInvalid,
+		},
+		{
+			"no pageable fields",
+			noFields,
+			v2.PageRequest{},
+			"",
+			false,
+			v2.ErrorCodePageInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reply, pr, err := pageReply(tc.payload, tc.page)
+			if tc.err != v2.ErrorCodeInvalid {
+				var ue v2.UserErrorReply
+				if !errors.As(err, &ue) || ue.ErrorCode != tc.err {
+					t.Fatalf("got err %v, want %v", err, v2.ErrorCodes[tc.err])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Compare the decoded payloads so that the
+			// field ordering does not matter.
+			var got, want interface{}
+			if err := json.Unmarshal([]byte(reply), &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.reply), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got reply %v, want %v", reply, tc.reply)
+			}
+			if pr.HasMore != tc.hasMore {
+				t.Errorf("got has more %v, want %v", pr.HasMore, tc.hasMore)
+			}
+			if pr.HasMore && pr.NextToken == "" {
+				t.Errorf("next token not set")
+			}
+		})
+	}
+}
@@ -17,10 +17,23 @@ const (
 	CmdVote       = "vote"       // Vote on a comment
 	CmdGet        = "get"        // Get specified comments
 	CmdGetAll     = "getall"     // Get all comments for a record
+	CmdGetPage    = "getpage"    // Get a page of comments for a record
 	CmdGetVersion = "getversion" // Get specified version of a comment
 	CmdCount      = "count"      // Get comments count for a record
 	CmdVotes      = "votes"      // Get comment votes
 	CmdTimestamps = "timestamps" // Get timestamps
+	CmdStakeProof = "stakeproof" // Submit proof of ticket holdings
+	CmdUser       = "user"       // Get comments made by a user
+	CmdTree       = "tree"       // Get a comment subtree
+
+	CmdAttachments     = "attachments"     // Get comment attachments
+	CmdTimestampsBatch = "timestampsbatch" // Get timestamps by ID ranges
+	CmdSubscribe       = "subscribe"       // Subscribe to a comment thread
+	CmdSubscribers     = "subscribers"     // Get comment thread subscribers
+	CmdSearch          = "search"          // Search comments
+	CmdDelUser         = "deluser"         // Del all comments of a user
+	CmdPin             = "pin"             // Pin a comment
+	CmdHistory         = "history"         // Get a user's comment history
 )
 
 // Plugin setting keys can be used to specify custom plugin settings. Default
@@ -58,6 +71,74 @@ const (
 	// SettingKeyEditPeriod is the plugin setting key for the
 	// SettingEditPeriod plugin setting.
 	SettingKeyEditPeriod = "editperiod"
+
+	// SettingKeyStakeWeighting is the plugin setting key for the
+	// SettingStakeWeighting plugin setting.
+	SettingKeyStakeWeighting = "stakeweighting"
+
+	// SettingKeyCommentsPageSize is the plugin setting key for the
+	// SettingCommentsPageSize plugin setting.
+	SettingKeyCommentsPageSize = "commentspagesize"
+
+	// SettingKeyReactions is the plugin setting key for the
+	// SettingReactions plugin setting.
+	SettingKeyReactions = "reactions"
+
+	// SettingKeyAllowAuthorDeletes is the plugin setting key for the
+	// SettingAllowAuthorDeletes plugin setting.
+	SettingKeyAllowAuthorDeletes = "allowauthordeletes"
+
+	// SettingKeyVoteChangeCooldown is the plugin setting key for the
+	// SettingVoteChangeCooldown plugin setting.
+	SettingKeyVoteChangeCooldown = "votechangecooldown"
+
+	// SettingKeyTreeDepthMax is the plugin setting key for the
+	// SettingTreeDepthMax plugin setting.
+	SettingKeyTreeDepthMax = "treedepthmax"
+
+	// SettingKeyTreeChildrenMax is the plugin setting key for the
+	// SettingTreeChildrenMax plugin setting.
+	SettingKeyTreeChildrenMax = "treechildrenmax"
+
+	// SettingKeyAttachmentCountMax is the plugin setting key for the
+	// SettingAttachmentCountMax plugin setting.
+	SettingKeyAttachmentCountMax = "attachmentcountmax"
+
+	// SettingKeyAttachmentSizeMax is the plugin setting key for the
+	// SettingAttachmentSizeMax plugin setting.
+	SettingKeyAttachmentSizeMax = "attachmentsizemax"
+
+	// SettingKeyAttachmentMIMETypes is the plugin setting key for the
+	// SettingAttachmentMIMETypes plugin setting.
+	SettingKeyAttachmentMIMETypes = "attachmentmimetypes"
+
+	// SettingKeySearchPageSize is the plugin setting key for the
+	// SettingSearchPageSize plugin setting.
+	SettingKeySearchPageSize = "searchpagesize"
+
+	// SettingKeyFsckRepairScores is the plugin setting key for the
+	// SettingFsckRepairScores plugin setting.
+	SettingKeyFsckRepairScores = "fsckrepairscores"
+
+	// SettingKeyMarkdownValidation is the plugin setting key for the
+	// SettingMarkdownValidation plugin setting.
+	SettingKeyMarkdownValidation = "markdownvalidation"
+
+	// SettingKeyMarkdownDataURIMax is the plugin setting key for the
+	// SettingMarkdownDataURIMax plugin setting.
+	SettingKeyMarkdownDataURIMax = "markdowndataurimax"
+
+	// SettingKeyEditsMax is the plugin setting key for the
+	// SettingEditsMax plugin setting.
+	SettingKeyEditsMax = "editsmax"
+
+	// SettingKeyHistoryPageSize is the plugin setting key for the
+	// SettingHistoryPageSize plugin setting.
+	SettingKeyHistoryPageSize = "historypagesize"
+
+	// SettingKeyDisableDownvotes is the plugin setting key for the
+	// SettingDisableDownvotes plugin setting.
+	SettingKeyDisableDownvotes = "disabledownvotes"
 )
 
 // Plugin setting default values. These can be overridden by providing a
@@ -101,6 +182,100 @@ const (
 	// editable. It defaults to five minutes which should be enough time
 	// to spot typos and grammar mistakes.
 	SettingEditPeriod uint32 = 300
+
+	// SettingStakeWeighting is the default value of the bool flag which
+	// determines if users can submit proof of the tickets that they
+	// hold in order to have their comment votes weighted by stake. This
+	// requires the ticketvote plugin.
+	SettingStakeWeighting = false
+
+	// SettingCommentsPageSize is the default maximum number of comments
+	// that can be returned by the GetPage command at any one time.
+	SettingCommentsPageSize uint32 = 100
+
+	// SettingReactions is the default JSON encoded list of Reaction that
+	// users can react to a comment with. Reactions are disabled by
+	// default, in which case only upvotes and downvotes are allowed.
+	//
+	// Example setting value:
+	// [{"id":"+1","vote":1},{"id":"-1","vote":-1},{"id":"heart"}]
+	SettingReactions = "[]"
+
+	// SettingAllowAuthorDeletes is the default value of the bool flag
+	// which determines whether comment authors are allowed to delete
+	// their own comments. Comments can always be deleted by admins.
+	SettingAllowAuthorDeletes = false
+
+	// SettingVoteChangeCooldown is the default minimum amount of time,
+	// in seconds, that a user must wait between casting votes on the
+	// same comment. This prevents a user from being able to rapidly
+	// flip their vote and bloat the record with comment votes. A value
+	// of 0 disables the cooldown.
+	SettingVoteChangeCooldown uint32 = 0
+
+	// SettingTreeDepthMax is the default maximum number of levels of
+	// replies that can be returned by the Tree command at any one time.
+	SettingTreeDepthMax uint32 = 5
+
+	// SettingTreeChildrenMax is the default maximum number of replies
+	// per comment that can be returned by the Tree command at any one
+	// time.
+	SettingTreeChildrenMax uint32 = 20
+
+	// SettingAttachmentCountMax is the default maximum number of
+	// attachments that can be included in a comment. Attachments are
+	// disabled by default.
+	SettingAttachmentCountMax uint32 = 0
+
+	// SettingAttachmentSizeMax is the default maximum size, in bytes,
+	// of a decoded attachment payload.
+	SettingAttachmentSizeMax uint32 = 512 * 1024 // 512 KiB
+
+	// SettingAttachmentMIMETypes is the default JSON encoded list of
+	// image MIME types that are allowed to be attached to a comment.
+	SettingAttachmentMIMETypes = `["image/png","image/jpeg"]`
+
+	// SettingSearchPageSize is the default maximum number of search
+	// results that can be returned by the Search command at any one
+	// time.
+	SettingSearchPageSize uint32 = 20
+
+	// SettingFsckRepairScores is the default setting for whether the
+	// fsck repairs the cached comment vote scores that do not match
+	// the comment votes. The mismatches are only reported when this
+	// is disabled.
+	SettingFsckRepairScores = false
+
+	// SettingMarkdownValidation is the default setting for whether
+	// markdown comments are checked for constructs that are unsafe to
+	// render, i.e. raw HTML, javascript links, and oversized data URIs.
+	// Comments that contain any of these constructs are rejected. Code
+	// spans and fenced code blocks are not checked.
+	SettingMarkdownValidation = false
+
+	// SettingMarkdownDataURIMax is the default maximum size, in bytes,
+	// of a data URI that is embedded in a markdown comment. It is only
+	// enforced when markdown validation is enabled. A value of 0 does
+	// not allow any data URIs.
+	SettingMarkdownDataURIMax uint32 = 1024
+
+	// SettingEditsMax is the default maximum number of times that a
+	// comment can be edited during the timeframe set by
+	// SettingEditPeriod. A value of 0 does not limit the number of
+	// edits.
+	SettingEditsMax uint32 = 0
+
+	// SettingHistoryPageSize is the default maximum number of comments
+	// that can be returned by the History command at any one time.
+	SettingHistoryPageSize uint32 = 20
+
+	// SettingDisableDownvotes is the default setting for whether
+	// comment downvotes are disabled. Downvotes, including reactions
+	// that are mapped to a downvote, are rejected when this is enabled
+	// and the downvote tallies of the comments are omitted. A user can
+	// still retract a downvote that was cast before downvotes were
+	// disabled.
+	SettingDisableDownvotes = false
 )
 
 // ErrorCodeT represents a error that was caused by the user.
@@ -165,10 +340,75 @@ const (
 	// is submitted.
 	ErrorCodeEmptyComment = 14
 
+	// ErrorCodeContentTypeInvalid is returned when a comment content type
+	// is not one of the supported content types.
+	ErrorCodeContentTypeInvalid ErrorCodeT = 15
+
+	// ErrorCodeStakeWeightingDisabled is returned when a stake proof is
+	// submitted and stake weighting has not been enabled.
+	ErrorCodeStakeWeightingDisabled ErrorCodeT = 16
+
+	// ErrorCodeStakeProofInvalid is returned when a stake proof is
+	// invalid.
+	ErrorCodeStakeProofInvalid ErrorCodeT = 17
+
+	// ErrorCodeSortInvalid is returned when a comment sort order is
+	// invalid.
+	ErrorCodeSortInvalid ErrorCodeT = 18
+
+	// ErrorCodeUserIDInvalid is returned when a user ID is invalid.
+	ErrorCodeUserIDInvalid ErrorCodeT = 19
+
+	// ErrorCodeDelNotAllowed is returned when a comment author attempts
+	// to delete their comment and author deletes are not allowed.
+	ErrorCodeDelNotAllowed ErrorCodeT = 20
+
+	// ErrorCodeVoteChangeCooldown is returned when a user attempts to
+	// change their comment vote before the vote change cooldown plugin
+	// setting has elapsed.
+	ErrorCodeVoteChangeCooldown ErrorCodeT = 21
+
+	// ErrorCodeAttachmentInvalid is returned when a comment attachment
+	// is invalid.
+	ErrorCodeAttachmentInvalid ErrorCodeT = 22
+
+	// ErrorCodeCommentIDRangeInvalid is returned when a comment ID range
+	// is invalid or when the comment ID ranges of a request exceed the
+	// page size plugin setting.
+	ErrorCodeCommentIDRangeInvalid ErrorCodeT = 23
+
+	// ErrorCodeSubscribeActionInvalid is returned when a comment thread
+	// subscription action is invalid.
+	ErrorCodeSubscribeActionInvalid ErrorCodeT = 24
+
+	// ErrorCodeSearchQueryInvalid is returned when a comment search
+	// query is invalid.
+	ErrorCodeSearchQueryInvalid ErrorCodeT = 25
+
+	// ErrorCodeMarkdownInvalid is returned when markdown validation is
+	// enabled and a markdown comment contains a construct that is not
+	// allowed.
+	ErrorCodeMarkdownInvalid ErrorCodeT = 26
+
+	// ErrorCodeEditPeriodExpired is returned when a comment edit is
+	// submitted after the timeframe set by the edit period plugin
+	// setting has expired.
+	ErrorCodeEditPeriodExpired ErrorCodeT = 27
+
+	// ErrorCodeEditsMaxExceeded is returned when a comment edit is
+	// submitted for a comment that has already been edited the max
+	// number of times allowed by the edits max plugin setting.
+	ErrorCodeEditsMaxExceeded ErrorCodeT = 28
+
+	// ErrorCodeDownvotesDisabled is returned when a downvote is cast
+	// while downvotes have been disabled by the disable downvotes
+	// plugin setting.
+	ErrorCodeDownvotesDisabled ErrorCodeT = 29
+
 	// ErrorCodeLast is used by unit tests to verify that all error codes have
 	// a human readable entry in the ErrorCodes map. This error code will never
 	// be returned.
-	ErrorCodeLast ErrorCodeT = 15
+	ErrorCodeLast ErrorCodeT = 30
 )
 
 var (
@@ -189,6 +429,41 @@ var (
 		ErrorCodeExtraDataNotAllowed:    "comment extra data not allowed",
 		ErrorCodeEditNotAllowed:         "comment edit is not allowed",
 		ErrorCodeEmptyComment:           "comment is empty",
+		ErrorCodeContentTypeInvalid:     "content type invalid",
+		ErrorCodeStakeWeightingDisabled: "stake weighting disabled",
+		ErrorCodeStakeProofInvalid:      "stake proof invalid",
+		ErrorCodeSortInvalid:            "sort invalid",
+		ErrorCodeUserIDInvalid:          "user id invalid",
+		ErrorCodeDelNotAllowed:          "comment del is not allowed",
+		ErrorCodeVoteChangeCooldown:     "vote change cooldown",
+		ErrorCodeAttachmentInvalid:      "attachment invalid",
+		ErrorCodeCommentIDRangeInvalid:  "comment id range invalid",
+		ErrorCodeSubscribeActionInvalid: "subscribe action invalid",
+		ErrorCodeSearchQueryInvalid:     "search query invalid",
+		ErrorCodeMarkdownInvalid:        "markdown invalid",
+		ErrorCodeEditPeriodExpired:      "comment edit period expired",
+		ErrorCodeEditsMaxExceeded:       "comment edits max exceeded",
+		ErrorCodeDownvotesDisabled:      "downvotes disabled",
+	}
+)
+
+const (
+	// ContentTypeMarkdown indicates that the comment text is markdown.
+	// This is the content type of all comments that were submitted
+	// before content types were introduced and is the default when a
+	// content type is not provided.
+	ContentTypeMarkdown = "markdown"
+
+	// ContentTypePlain indicates that the comment text is plain text
+	// and must not be rendered as markdown.
+	ContentTypePlain = "plain"
+)
+
+var (
+	// ContentTypes contains the supported comment content types.
+	ContentTypes = map[string]struct{}{
+		ContentTypeMarkdown: {},
+		ContentTypePlain:    {},
 	}
 )
 
@@ -206,6 +481,20 @@ const (
 	RecordStateVetted RecordStateT = 2
 )
 
+// Attachment is an image that is attached to a comment.
+//
+// Digest is the hex encoded SHA256 digest of the decoded payload. The
+// attachment digests are included in the comment signature, which allows the
+// attachments to be verified against the comment. The payload is only
+// populated when the attachment is submitted and when it is retrieved using
+// the Attachments command. It is not included in the Comment.
+type Attachment struct {
+	Name    string `json:"name"`              // Filename
+	MIME    string `json:"mime"`              // MIME type
+	Digest  string `json:"digest"`            // SHA256 digest of payload
+	Payload string `json:"payload,omitempty"` // Base64 encoded image
+}
+
 // Comment represent a record comment.
 //
 // A parent ID of 0 indicates that the comment is a base level comment and not
@@ -222,10 +511,27 @@ const (
 // the deleted comment. Everything else from the original comment is
 // permanently deleted.
 //
+// Reactions contains the tally of each reaction that has been configured by
+// the SettingKeyReactions plugin setting. It is only populated when reactions
+// have been enabled. Reactions that map to an upvote or a downvote share the
+// tally of the Upvotes or Downvotes field.
+//
+// StakeDownvotes and StakeUpvotes are only populated when stake weighting has
+// been enabled. They contain the net vote of each user that has submitted a
+// stake proof for the record, weighted by the stake of the user. Votes from
+// users that have not submitted a stake proof are not included.
+//
+// Downvotes and StakeDownvotes are always 0 when downvotes have been disabled
+// by the SettingKeyDisableDownvotes plugin setting.
+//
 // PublicKey is the user's public key that is used to verify the signature.
 //
 // Signature is the user signature of the:
-// State + Token + ParentID + Comment + ExtraData + ExtraDataHint
+// State + Token + ParentID + Comment + ExtraData + ExtraDataHint +
+// Attachment digests
+//
+// Attachments contains the metadata of the comment attachments. The
+// attachment payloads can be retrieved using the Attachments command.
 //
 // Receipt is the server signature of the user signature.
 //
@@ -247,9 +553,31 @@ type Comment struct {
 	Downvotes uint64       `json:"downvotes"` // Tolal downvotes on comment
 	Upvotes   uint64       `json:"upvotes"`   // Total upvotes on comment
 
+	Reactions map[string]uint64 `json:"reactions,omitempty"` // [reaction]tally
+
+	StakeDownvotes uint64 `json:"stakedownvotes,omitempty"`
+	StakeUpvotes   uint64 `json:"stakeupvotes,omitempty"`
+
 	Deleted bool   `json:"deleted,omitempty"` // Comment has been deleted
 	Reason  string `json:"reason,omitempty"`  // Reason for deletion
 
+	// DeletedByAuthor indicates that the comment was deleted by its
+	// author rather than being censored by an admin.
+	DeletedByAuthor bool `json:"deletedbyauthor,omitempty"`
+
+	// BulkDeleted indicates that the comment was deleted by a DelUser
+	// command, which deletes all of the comments of a user. The
+	// deletion signature of a bulk delete is the signature of the
+	// UserID + Reason.
+	BulkDeleted bool `json:"bulkdeleted,omitempty"`
+
+	// ContentType is the content type of the comment text. It is a
+	// rendering hint that is declared per comment version. It is not
+	// populated for deleted comments.
+	ContentType string `json:"contenttype,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
@@ -264,10 +592,16 @@ type Comment struct {
 // associated with a new comment or a comment edit:
 //
 //  1. When a comment is created it's the user signature of the:
-//     State + Token + ParentID + Comment + ExtraData + ExtraDataHint.
+//     State + Token + ParentID + Comment + ExtraData + ExtraDataHint +
+//     Attachment digests.
 //
 //  2. When a comment is edited it's the user signature of the:
-//     State + Token + ParentID + CommentID + Comment + ExtraData + ExtraDataHint.
+//     State + Token + ParentID + CommentID + Comment + ExtraData +
+//     ExtraDataHint + Attachment digests.
+//
+// The attachment payloads are not saved as part of the CommentAdd. Each
+// payload is saved as a separate blob to the record and the blob digests are
+// saved in the AttachmentBlobs field, in the same order as the Attachments.
 //
 // Receipt is the server signature of the user signature.
 //
@@ -289,6 +623,15 @@ type CommentAdd struct {
 	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
 	Receipt   string `json:"receipt"`   // Server signature of client signature
 
+	// ContentType is the content type of the comment text. Comments
+	// that were saved before content types were introduced do not
+	// have a content type and are markdown.
+	ContentType string `json:"contenttype,omitempty"`
+
+	// Attachment metadata. The payloads are not included.
+	Attachments     []Attachment `json:"attachments,omitempty"`
+	AttachmentBlobs []string     `json:"attachmentblobs,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
@@ -305,6 +648,11 @@ type CommentAdd struct {
 // Signature is the user signature of the:
 // State + Token + CommentID + Reason
 //
+// Bulk indicates that the comment was deleted by a DelUser command. The
+// PublicKey and Signature of a bulk delete are the admin public key and
+// signature of the DelUser command, which is the signature of the:
+// UserID + Reason
+//
 // The PublicKey and Signature are hex encoded and use the
 // ed25519 signature scheme.
 type CommentDel struct {
@@ -317,8 +665,37 @@ type CommentDel struct {
 	Signature string       `json:"signature"` // Client signature
 
 	// Metadata generated by server
-	ParentID  uint32 `json:"parentid"`  // Parent comment ID
-	UserID    string `json:"userid"`    // Author user ID
+	ParentID  uint32 `json:"parentid"`         // Parent comment ID
+	UserID    string `json:"userid"`           // Author user ID
+	Author    bool   `json:"author,omitempty"` // Deleted by the author
+	Bulk      bool   `json:"bulk,omitempty"`   // Deleted by DelUser
+	Timestamp int64  `json:"timestamp"`        // Received UNIX timestamp
+	Receipt   string `json:"receipt"`          // Server sig of client sig
+}
+
+// CommentPin is the structure that is saved to disk when a comment is pinned
+// or unpinned. A CommentID of 0 indicates that the pinned comment has been
+// unpinned.
+//
+// PublicKey is the user's public key that is used to verify the signature.
+//
+// Signature is the user signature of the:
+// State + Token + CommentID
+//
+// Receipt is the server signature of the user signature.
+//
+// The PublicKey, Signature, and Receipt are all hex encoded and use the
+// ed25519 signature scheme.
+type CommentPin struct {
+	// Data generated by client
+	UserID    string       `json:"userid"`    // Unique user ID
+	State     RecordStateT `json:"state"`     // Record state
+	Token     string       `json:"token"`     // Record token
+	CommentID uint32       `json:"commentid"` // Comment ID
+	PublicKey string       `json:"publickey"` // Pubkey used for Signature
+	Signature string       `json:"signature"` // Client signature
+
+	// Metadata generated by server
 	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
 	Receipt   string `json:"receipt"`   // Server sig of client sig
 }
@@ -337,13 +714,32 @@ const (
 	VoteUpvote VoteT = 1
 )
 
+// Reaction describes a reaction that a user can react to a comment with. The
+// reactions are configured using the SettingKeyReactions plugin setting.
+//
+// Vote is optional and maps the reaction to a legacy upvote or downvote. A
+// reaction that has been mapped to a vote is interchangeable with that vote,
+// i.e. reacting with it is the same as casting the vote and its tally is the
+// Upvotes or Downvotes tally of the comment. This allows existing up/down
+// votes to be displayed as reactions.
+//
+// Reactions that are not mapped to a vote are tallied separately and do not
+// affect the comment score. They must be cast using VoteUpvote.
+type Reaction struct {
+	ID   string `json:"id"`             // Unique reaction ID, e.g. an emoji
+	Vote VoteT  `json:"vote,omitempty"` // Mapped upvote or downvote
+}
+
 // CommentVote is the structure that is saved to disk when a comment is voted
 // on.
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
+// Reaction is only populated when the vote was cast as a reaction that does
+// not map to an upvote or downvote.
+//
 // Signature is the user signature of the:
-// State + Token + CommentID + Vote
+// State + Token + CommentID + Vote + Reaction
 //
 // The PublicKey and Signature are hex encoded and use the
 // ed25519 signature scheme.
@@ -357,6 +753,8 @@ type CommentVote struct {
 	PublicKey string       `json:"publickey"` // Public key used for signature
 	Signature string       `json:"signature"` // Client signature
 
+	Reaction string `json:"reaction,omitempty"` // Reaction ID
+
 	// Metadata generated by server
 	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
 	Receipt   string `json:"receipt"`   // Server signature of client signature
@@ -369,8 +767,13 @@ type CommentVote struct {
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
+// Attachments are optional and are only allowed when the
+// SettingKeyAttachmentCountMax plugin setting has been set. The attachment
+// payloads must be included.
+//
 // Signature is the user signature of the:
-// State + Token + ParentID + Comment + ExtraData + ExtraDataHint
+// State + Token + ParentID + Comment + ExtraData + ExtraDataHint +
+// Attachment digests
 //
 // Receipt is the server signature of the user signature.
 //
@@ -385,6 +788,13 @@ type New struct {
 	PublicKey string       `json:"publickey"` // Pubkey used for Signature
 	Signature string       `json:"signature"` // Client signature
 
+	// ContentType is the content type of the comment text. It is not
+	// part of the signature. ContentTypeMarkdown is used if a content
+	// type is not provided.
+	ContentType string `json:"contenttype,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
@@ -399,8 +809,13 @@ type NewReply struct {
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
+// Attachments must contain the full set of attachments for the new version
+// of the comment, including any attachments that are unchanged. The
+// attachment payloads must be included.
+//
 // Signature is the user signature of the:
-// State + Token + ParentID + CommentID + Comment + ExtraData + ExtraDataHint
+// State + Token + ParentID + CommentID + Comment + ExtraData + ExtraDataHint +
+// Attachment digests
 //
 // Receipt is the server signature of the user signature.
 //
@@ -416,6 +831,13 @@ type Edit struct {
 	PublicKey string       `json:"publickey"` // Pubkey used for Signature
 	Signature string       `json:"signature"` // Client signature
 
+	// ContentType is the content type of the edited comment text. It
+	// is not part of the signature. ContentTypeMarkdown is used if a
+	// content type is not provided.
+	ContentType string `json:"contenttype,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
@@ -428,6 +850,12 @@ type EditReply struct {
 
 // Del permanently deletes all versions of the provided comment.
 //
+// Author indicates that the comment is being deleted by its author rather
+// than being censored by an admin. Author deletes are only allowed when the
+// SettingKeyAllowAuthorDeletes plugin setting has been enabled. The UserID
+// must be set to the user ID of the comment author for author deletes. A
+// reason is optional for author deletes.
+//
 // PublicKey is the user's public key that is used to verify the signature.
 //
 // Signature is the user signature of the:
@@ -442,6 +870,9 @@ type Del struct {
 	Reason    string       `json:"reason"`    // Reason for deletion
 	PublicKey string       `json:"publickey"` // Public key used for signature
 	Signature string       `json:"signature"` // Client signature
+
+	Author bool   `json:"author,omitempty"` // Deleted by the comment author
+	UserID string `json:"userid,omitempty"` // User ID of the author
 }
 
 // DelReply is the reply to the Del command.
@@ -449,6 +880,59 @@ type DelReply struct {
 	Comment Comment `json:"comment"`
 }
 
+// DelUser permanently deletes all versions of all of the comments that a user
+// has made on a record. This allows an admin to censor the comments of a spam
+// account without having to delete each comment individually. A CommentDel is
+// saved for each of the deleted comments. Comments that have already been
+// deleted are skipped.
+//
+// PublicKey is the admin's public key that is used to verify the signature.
+//
+// Signature is the admin signature of the:
+// UserID + Reason
+//
+// The PublicKey and Signature are hex encoded and use the
+// ed25519 signature scheme.
+type DelUser struct {
+	UserID    string `json:"userid"`    // User ID of the comment author
+	Reason    string `json:"reason"`    // Reason for deletion
+	PublicKey string `json:"publickey"` // Public key used for signature
+	Signature string `json:"signature"` // Client signature
+}
+
+// DelUserReply is the reply to the DelUser command. It contains the comments
+// that were deleted, ordered by comment ID.
+type DelUserReply struct {
+	Comments []Comment `json:"comments"`
+}
+
+// Pin pins a comment so that clients can display it at the top of the record
+// comments, e.g. the latest official update of a record. A record can only
+// have one pinned comment. Pinning a comment replaces the previously pinned
+// comment. A CommentID of 0 unpins the pinned comment. Deleted comments cannot
+// be pinned and a pinned comment is no longer considered pinned once it has
+// been deleted.
+//
+// The comments plugin does not verify the user's permissions. Only the record
+// author and admins should be allowed to pin a comment. This is enforced by
+// the caller.
+//
+// Signature is the client signature of the State+Token+CommentID.
+type Pin struct {
+	UserID    string       `json:"userid"`    // Unique user ID
+	State     RecordStateT `json:"state"`     // Record state
+	Token     string       `json:"token"`     // Record token
+	CommentID uint32       `json:"commentid"` // Comment ID, 0 to unpin
+	PublicKey string       `json:"publickey"` // Public key used for signature
+	Signature string       `json:"signature"` // Client signature
+}
+
+// PinReply is the reply to the Pin command.
+type PinReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
 // Vote casts a comment vote (upvote or downvote).
 //
 // The effect of a new vote on a comment score depends on the previous vote
@@ -457,45 +941,86 @@ type DelReply struct {
 // original upvote. The public key cannot be relied on to remain the same for
 // each user so a user ID must be included.
 //
+// Reaction is optional and can be used to react to a comment with one of the
+// reactions configured by the SettingKeyReactions plugin setting. Reactions
+// follow the same toggle semantics as votes, i.e. reacting with the same
+// reaction twice removes the reaction. See the Reaction type for how
+// reactions map to upvotes and downvotes.
+//
 // PublicKey is the user's public key that is used to verify the signature.
 //
 // Signature is the user signature of the:
-// State + Token + CommentID + Vote
+// State + Token + CommentID + Vote + Reaction
+//
+// The Reaction is only included in the signature message when it is not
+// empty, which keeps the signature of plain votes backwards compatible.
 //
 // The PublicKey and Signature are hex encoded and use the
 // ed25519 signature scheme.
 type Vote struct {
-	UserID    string       `json:"userid"`    // Unique user ID
-	State     RecordStateT `json:"state"`     // Record state
-	Token     string       `json:"token"`     // Record token
-	CommentID uint32       `json:"commentid"` // Comment ID
-	Vote      VoteT        `json:"vote"`      // Upvote or downvote
-	PublicKey string       `json:"publickey"` // Public key used for signature
-	Signature string       `json:"signature"` // Client signature
+	UserID    string       `json:"userid"`             // Unique user ID
+	State     RecordStateT `json:"state"`              // Record state
+	Token     string       `json:"token"`              // Record token
+	CommentID uint32       `json:"commentid"`          // Comment ID
+	Vote      VoteT        `json:"vote"`               // Upvote or downvote
+	PublicKey string       `json:"publickey"`          // Public key used for signature
+	Signature string       `json:"signature"`          // Client signature
+	Reaction  string       `json:"reaction,omitempty"` // Reaction ID
 }
 
-// VoteReply is the reply to the Vote command.
+// VoteReply is the reply to the Vote command. Reactions is only populated
+// when reactions have been enabled.
 type VoteReply struct {
-	Downvotes uint64 `json:"downvotes"` // Tolal downvotes on comment
-	Upvotes   uint64 `json:"upvotes"`   // Total upvotes on comment
-	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
-	Receipt   string `json:"receipt"`   // Server signature of client signature
+	Downvotes uint64            `json:"downvotes"`           // Tolal downvotes on comment
+	Upvotes   uint64            `json:"upvotes"`             // Total upvotes on comment
+	Reactions map[string]uint64 `json:"reactions,omitempty"` // [reaction]tally
+	Timestamp int64             `json:"timestamp"`           // Received UNIX timestamp
+	Receipt   string            `json:"receipt"`             // Server signature of client signature
 }
 
+// SortT represents the order that comments are sorted in.
+type SortT uint32
+
+const (
+	// SortInvalid is an invalid sort order. Comments are not sorted when
+	// a sort order is not provided.
+	SortInvalid SortT = 0
+
+	// SortScore sorts comments by their score, i.e. upvotes minus
+	// downvotes, from highest to lowest.
+	SortScore SortT = 1
+
+	// SortStakeScore sorts comments by their stake weighted score, i.e.
+	// stake upvotes minus stake downvotes, from highest to lowest.
+	SortStakeScore SortT = 2
+
+	// SortTimestamp sorts comments by their creation timestamp, from
+	// newest to oldest.
+	SortTimestamp SortT = 3
+)
+
 // Get retrieves a batch of specified comments. The most recent version of each
 // comment is returned. An error is not returned if a comment is not found for
 // one or more of the comment IDs. Those entries will simply not be included in
 // the reply.
+//
+// SortBy is optional. When provided, the reply includes the order of the
+// returned comments. Comments with the same score are ordered by comment ID.
 type Get struct {
 	CommentIDs []uint32 `json:"commentids"`
+	SortBy     SortT    `json:"sortby,omitempty"`
 }
 
 // GetReply is the reply to the Get command. The returned map will not include
 // an entry for any comment IDs that did not correspond to an actual comment.
 // It is the responsibility of the caller to ensure that a comment was returned
 // for all of the provided comment IDs.
+//
+// Order contains the comment IDs of the returned comments in the requested
+// sort order. It is only populated when a sort order is provided.
 type GetReply struct {
 	Comments map[uint32]Comment `json:"comments"` // [commentID]Comment
+	Order    []uint32           `json:"order,omitempty"`
 }
 
 // GetAll retrieves all comments for a record. The latest version of each
@@ -508,6 +1033,106 @@ type GetAllReply struct {
 	Comments []Comment `json:"comments"`
 }
 
+// GetPage retrieves a page of comments for a record. The latest version of
+// each comment is returned.
+//
+// The comments are ordered by comment ID when a sort order is not provided.
+// Comments with the same score are ordered by comment ID. Page 1 is returned
+// if a page is not provided. The page size defaults to, and is capped at, the
+// SettingCommentsPageSize. An empty page is returned if the requested page
+// does not exist.
+type GetPage struct {
+	SortBy   SortT  `json:"sortby,omitempty"`
+	Page     uint32 `json:"page,omitempty"`
+	PageSize uint32 `json:"pagesize,omitempty"`
+}
+
+// GetPageReply is the reply to the GetPage command. The returned comments are
+// in the requested sort order. Total is the number of comments on the record.
+type GetPageReply struct {
+	Comments []Comment `json:"comments"`
+	Total    uint32    `json:"total"`
+}
+
+// Tree retrieves the subtree of comments that are replies to the provided
+// parent comment. A parent ID of 0 retrieves the base level comments of the
+// record. The latest version of each comment is returned.
+//
+// Depth is the number of levels of replies that are returned and Children is
+// the number of replies that are returned per comment. Both default to, and
+// are capped at, the SettingTreeDepthMax and SettingTreeChildrenMax plugin
+// settings. The replies of each comment are ordered using the provided sort
+// order, or by comment ID when a sort order is not provided.
+//
+// Clients can lazily expand a thread by requesting the subtree of any comment
+// whose Replies count is greater than the number of Children that were
+// returned for it.
+type Tree struct {
+	ParentID uint32 `json:"parentid,omitempty"`
+	Depth    uint32 `json:"depth,omitempty"`
+	Children uint32 `json:"children,omitempty"`
+	SortBy   SortT  `json:"sortby,omitempty"`
+}
+
+// TreeComment is a comment in a comment subtree. Replies is the total number
+// of direct replies to the comment. Children contains the replies that were
+// returned, which may be fewer than Replies if the depth or children limits
+// were reached.
+type TreeComment struct {
+	Comment  Comment       `json:"comment"`
+	Replies  uint32        `json:"replies"`
+	Children []TreeComment `json:"children,omitempty"`
+}
+
+// TreeReply is the reply to the Tree command. Replies is the total number of
+// direct replies to the parent comment.
+type TreeReply struct {
+	Comments []TreeComment `json:"comments"`
+	Replies  uint32        `json:"replies"`
+}
+
+// Attachments retrieves the attachments of the latest version of a comment,
+// including the attachment payloads. No attachments are returned for a
+// deleted comment.
+type Attachments struct {
+	CommentID uint32 `json:"commentid"`
+}
+
+// AttachmentsReply is the reply to the Attachments command. State is the
+// state of the record that the comment was made on.
+type AttachmentsReply struct {
+	State       RecordStateT `json:"state"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// User retrieves the IDs of the comments that were made by a user. If the
+// plugin command is executed with a token then only the comments that were
+// made on that record are returned. If the plugin command is executed without
+// a token then the comments that were made on all records are returned.
+//
+// The latest version of each comment is included in the reply when
+// IncludeComments is set. Deleted comments are included in the reply.
+type User struct {
+	UserID          string `json:"userid"`
+	IncludeComments bool   `json:"includecomments,omitempty"`
+}
+
+// UserRecordComments contains the comments that a user made on a record. The
+// comment IDs are in ascending order. Comments is only populated when the
+// comments were requested and is in the same order as the comment IDs.
+type UserRecordComments struct {
+	Token      string       `json:"token"`
+	State      RecordStateT `json:"state"`
+	CommentIDs []uint32     `json:"commentids"`
+	Comments   []Comment    `json:"comments,omitempty"`
+}
+
+// UserReply is the reply to the User command. The records are ordered by
+// token.
+type UserReply struct {
+	Records []UserRecordComments `json:"records"`
+}
+
 // GetVersion retrieves the specified version of a comment.
 type GetVersion struct {
 	CommentID uint32 `json:"commentid"`
@@ -520,12 +1145,14 @@ type GetVersionReply struct {
 }
 
 // Count retrieves the comments count for a record. The comments count is the
-// number of comments that have been made on a record.
+// number of comments that have been made on a record. The ID of the pinned
+// comment is also returned if the record has one.
 type Count struct{}
 
 // CountReply is the reply to the Count command.
 type CountReply struct {
-	Count uint32 `json:"count"`
+	Count    uint32 `json:"count"`
+	PinnedID uint32 `json:"pinnedid,omitempty"` // Pinned comment ID
 }
 
 // Votes retrieves the record's comment votes that meet the provided filtering
@@ -533,8 +1160,14 @@ type CountReply struct {
 // votes. This command is paginated, if no page is provided, then the first
 // page is returned. If the requested page does not exist an empty page
 // is returned.
+//
+// Since is an optional UNIX timestamp. When provided, only the votes that
+// were cast at or after the timestamp are returned. This allows a client that
+// has already retrieved a user's votes to only retrieve the votes that have
+// changed since.
 type Votes struct {
 	UserID string `json:"userid,omitempty"`
+	Since  int64  `json:"since,omitempty"`
 	Page   uint32 `json:"page,omitempty"`
 }
 
@@ -607,3 +1240,235 @@ type Timestamps struct {
 type TimestampsReply struct {
 	Comments map[uint32]CommentTimestamp `json:"comments"`
 }
+
+// CommentIDRange is an inclusive range of comment IDs.
+type CommentIDRange struct {
+	Start uint32 `json:"start"`
+	End   uint32 `json:"end"`
+}
+
+// TimestampsBatch retrieves the timestamps for the comments that fall within
+// the provided comment ID ranges. Comment IDs that do not exist are not
+// included in the reply. The total number of comments that are requested
+// cannot exceed the SettingKeyTimestampsPageSize plugin setting.
+//
+// The reply is a TimestampsReply.
+type TimestampsBatch struct {
+	Ranges       []CommentIDRange `json:"ranges"`
+	IncludeVotes bool             `json:"includevotes,omitempty"`
+}
+
+const (
+	// StakeProofPrefix is the prefix of the message that is signed by the
+	// commitment address of each ticket in a stake proof.
+	StakeProofPrefix = "politeia comment stake proof:"
+)
+
+// TicketSignature contains a signature that proves ownership of a ticket.
+//
+// Signature is the signature of the ticket commitment address of the:
+// StakeProofPrefix + Token + UserID
+type TicketSignature struct {
+	Ticket    string `json:"ticket"`    // Ticket hash
+	Signature string `json:"signature"` // Commitment address signature
+}
+
+// StakeProof submits proof that a user holds tickets that were eligible to
+// vote in the ticket vote of a record. The comment votes of the user on the
+// record are weighted by the stake of the proven tickets. This command can
+// only be used once stake weighting has been enabled and the ticket vote of
+// the record has been started.
+//
+// A new stake proof from a user replaces the previous stake proof of that
+// user. A ticket can only be claimed by a single user for each record.
+//
+// PublicKey is the user's public key that is used to verify the signature.
+//
+// Signature is the user signature of the:
+// Token + UserID + Ticket hashes
+//
+// The PublicKey and Signature are hex encoded and use the ed25519 signature
+// scheme.
+type StakeProof struct {
+	UserID    string            `json:"userid"`    // Unique user ID
+	Token     string            `json:"token"`     // Record token
+	Tickets   []TicketSignature `json:"tickets"`   // Ticket signatures
+	PublicKey string            `json:"publickey"` // Public key used for signature
+	Signature string            `json:"signature"` // Client signature
+}
+
+// StakeProofReply is the reply to the StakeProof command. Weight is the sum
+// of the weights of the proven tickets. The ticket weight is the ticket stake
+// in atoms for stake weighted votes and 1 for all other votes.
+type StakeProofReply struct {
+	Weight    uint64 `json:"weight"`
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
+// UserStake is the structure that is saved to disk when a stake proof is
+// submitted.
+//
+// PublicKey is the user's public key that is used to verify the signature.
+//
+// Signature is the user signature of the:
+// Token + UserID + Ticket hashes
+//
+// Receipt is the server signature of the user signature.
+//
+// The PublicKey, Signature, and Receipt are all hex encoded and use the
+// ed25519 signature scheme.
+type UserStake struct {
+	// Data generated by client
+	UserID    string            `json:"userid"`    // Unique user ID
+	Token     string            `json:"token"`     // Record token
+	Tickets   []TicketSignature `json:"tickets"`   // Ticket signatures
+	PublicKey string            `json:"publickey"` // Public key used for signature
+	Signature string            `json:"signature"` // Client signature
+
+	// Metadata generated by server
+	Weight    uint64 `json:"weight"`    // Verified stake weight
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
+// SubscribeActionT represents a comment thread subscription action.
+type SubscribeActionT string
+
+const (
+	// SubscribeActionSubscribe subscribes a user to the replies of a
+	// comment thread.
+	SubscribeActionSubscribe SubscribeActionT = "subscribe"
+
+	// SubscribeActionUnsubscribe unsubscribes a user from the replies
+	// of a comment thread.
+	SubscribeActionUnsubscribe SubscribeActionT = "unsubscribe"
+)
+
+// Subscribe subscribes a user to, or unsubscribes a user from, the thread of
+// replies under a comment. A user that is subscribed to a comment thread is
+// notified of all new replies under the comment, including replies to
+// replies. Subscriptions allow a user to follow a single thread of a record
+// without being notified of every new comment on the record.
+//
+// Subscriptions are user preferences, not record data. They are not saved to
+// the record and are not timestamped.
+//
+// Signature is the client signature of the State+Token+CommentID+Action.
+type Subscribe struct {
+	UserID    string           `json:"userid"`    // Unique user ID
+	State     RecordStateT     `json:"state"`     // Record state
+	Token     string           `json:"token"`     // Record token
+	CommentID uint32           `json:"commentid"` // Comment ID
+	Action    SubscribeActionT `json:"action"`    // Subscribe or unsubscribe
+	PublicKey string           `json:"publickey"` // Public key used for signature
+	Signature string           `json:"signature"` // Client signature
+}
+
+// SubscribeReply is the reply to the Subscribe command.
+type SubscribeReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
+// Subscribers retrieves the IDs of the users that are subscribed to the thread
+// that contains the provided comment, i.e. the users that are subscribed to
+// the comment or to any of its parent comments. These are the users that
+// should be notified of a new reply to the comment.
+type Subscribers struct {
+	CommentID uint32 `json:"commentid"`
+}
+
+// SubscribersReply is the reply to the Subscribers command. The user IDs are
+// sorted in ascending order.
+type SubscribersReply struct {
+	UserIDs []string `json:"userids"`
+}
+
+const (
+	// SearchQueryLengthMax is the maximum number of characters that are
+	// allowed in a comment search query.
+	SearchQueryLengthMax = 256
+)
+
+// Search searches the latest version of the comments of the records in the
+// provided state. Deleted comments are not searched. The search is limited to
+// a single record when the plugin command is executed with a record token.
+//
+// The query is made up of whitespace separated terms. All terms must match
+// for a comment to be returned. Matching is case insensitive and ignores
+// punctuation.
+//
+// word       Comments that contain the word.
+// "a phrase" Comments that contain the words of the phrase, in order.
+// -word      Comments that do not contain the word.
+// -"phrase"  Comments that do not contain the phrase.
+//
+// The query must contain at least one word or phrase that is not excluded.
+// UserID can be provided to only return the comments of a single user.
+//
+// The results are ordered from the most recently updated comment to the
+// least recently updated comment. Page 1 is returned when a page is not
+// provided. The page size is determined by the SettingKeySearchPageSize
+// plugin setting.
+type Search struct {
+	Query  string       `json:"query"`
+	State  RecordStateT `json:"state"`
+	UserID string       `json:"userid,omitempty"`
+	Page   uint32       `json:"page,omitempty"`
+}
+
+// SearchResult is a comment that matched a search query. Timestamp is the
+// UNIX timestamp of the latest version of the comment.
+type SearchResult struct {
+	Token     string `json:"token"`
+	CommentID uint32 `json:"commentid"`
+	UserID    string `json:"userid"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SearchReply is the reply to the Search command. Total is the total number
+// of comments that matched the query across all pages.
+type SearchReply struct {
+	Results []SearchResult `json:"results"`
+	Total   uint32         `json:"total"`
+}
+
+const (
+	// HistoryExcerptLength is the maximum number of characters of the
+	// comment text that are included in a HistoryEntry excerpt.
+	HistoryExcerptLength = 200
+)
+
+// History retrieves a page of the comment history of a user. The comment
+// history contains the comments that the user has made on all of the records
+// in the provided state. Deleted comments are not included.
+//
+// The comments are ordered from the most recently created comment to the
+// least recently created comment. Page 1 is returned when a page is not
+// provided. The page size is determined by the SettingKeyHistoryPageSize
+// plugin setting.
+type History struct {
+	UserID string       `json:"userid"`
+	State  RecordStateT `json:"state"`
+	Page   uint32       `json:"page,omitempty"`
+}
+
+// HistoryEntry is a comment in the comment history of a user.
+//
+// Excerpt contains the start of the latest version of the comment text. It is
+// truncated to HistoryExcerptLength characters. Timestamp is the UNIX
+// timestamp of the creation of the comment.
+type HistoryEntry struct {
+	Token     string `json:"token"`
+	CommentID uint32 `json:"commentid"`
+	Excerpt   string `json:"excerpt"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// HistoryReply is the reply to the History command. Total is the total number
+// of comments in the comment history of the user across all pages.
+type HistoryReply struct {
+	Entries []HistoryEntry `json:"entries"`
+	Total   uint32         `json:"total"`
+}
@@ -14,6 +14,7 @@ const (
 	CmdBlockDetails = "blockdetails" // Get details of a block
 	CmdTicketPool   = "ticketpool"   // Get ticket pool
 	CmdTxsTrimmed   = "txstrimmed"   // Get trimmed transactions
+	CmdHealth       = "health"       // Get dcrdata host health
 )
 
 // Plugin setting keys can be used to specify custom plugin settings. Default
@@ -21,12 +22,22 @@ const (
 // and value to the plugin on startup.
 const (
 	// SettingKeyHostHTTP is the plugin setting key for the plugin
-	// setting SettingHostHTTP.
+	// setting SettingHostHTTP. The setting value can be either a
+	// single host or a JSON encoded []string of hosts listed in order
+	// of preference. Requests fail over to the next host when a host
+	// is unhealthy.
 	SettingKeyHostHTTP = "hosthttp"
 
 	// SettingKeyHostWS is the plugin setting key for the plugin
-	// setting SettingHostWS.
+	// setting SettingHostWS. The setting value can be either a single
+	// host or a JSON encoded []string of hosts listed in order of
+	// preference. The websocket connects to the first host that can be
+	// reached.
 	SettingKeyHostWS = "hostws"
+
+	// SettingKeyBestBlockTTL is the plugin setting key for the plugin
+	// setting SettingBestBlockTTL.
+	SettingKeyBestBlockTTL = "bestblockttl"
 )
 
 // Plugin setting default values. These can be overridden by providing a plugin
@@ -45,6 +56,13 @@ const (
 	// SettingHostWSTestNet is the default dcrdata testnet websocket
 	// host.
 	SettingHostWSTestNet = "wss://testnet.decred.org/ps"
+
+	// SettingBestBlockTTL is the default number of seconds that the
+	// cached best block is considered current for. The cached best
+	// block is replaced whenever the websocket receives a new block
+	// message. The best block is only fetched from the dcrdata HTTP
+	// API once the cached best block has expired.
+	SettingBestBlockTTL uint32 = 60
 )
 
 // StatusT represents a dcrdata connection status. Some commands will returned
@@ -190,3 +208,43 @@ type TxsTrimmed struct {
 type TxsTrimmedReply struct {
 	Txs []TrimmedTx `json:"txs"`
 }
+
+const (
+	// EventNewBlock is the plugin event that is emitted when the dcrdata
+	// plugin is notified of a new block. Blocks that were missed while
+	// the websocket was disconnected are backfilled once the connection
+	// has been re-established so that an event is emitted for each block
+	// height, in order. The event payload is a JSON encoded NewBlock.
+	EventNewBlock = "dcrdata-newblock"
+)
+
+// NewBlock is the payload of the EventNewBlock plugin event.
+type NewBlock struct {
+	Height uint32 `json:"height"`
+}
+
+// Health requests the health state of the dcrdata hosts.
+type Health struct{}
+
+// HostHealth contains the health state of a dcrdata host. A host is marked
+// as unhealthy when a request to it fails due to a connection error or a
+// server error and is marked as healthy again once a request to it succeeds.
+// Active is set for the host that is currently being used. Failures is the
+// number of consecutive failures. The timestamps are UNIX timestamps and are
+// zero when the event has not occurred yet.
+type HostHealth struct {
+	Host        string `json:"host"`
+	Active      bool   `json:"active"`
+	Healthy     bool   `json:"healthy"`
+	Failures    uint32 `json:"failures"`
+	LastSuccess int64  `json:"lastsuccess"`
+	LastFailure int64  `json:"lastfailure"`
+	LastError   string `json:"lasterror,omitempty"`
+}
+
+// HealthReply is the reply to the Health command. The hosts are listed in
+// order of preference.
+type HealthReply struct {
+	HTTP []HostHealth `json:"http"`
+	WS   []HostHealth `json:"ws"`
+}
@@ -19,6 +19,23 @@ const (
 
 	// CmdSummary command returns a summary for a proposal.
 	CmdSummary = "summary"
+
+	// CmdIdentity command returns the public key of the server identity
+	// that the pi plugin uses to sign receipts.
+	CmdIdentity = "identity"
+
+	// CmdCompletionReport command returns the completion report of a
+	// proposal. The command does not require a payload. The proposal
+	// token is provided in the plugin command.
+	CmdCompletionReport = "completionreport"
+
+	// CmdWithdraw command withdraws a proposal on behalf of its author.
+	CmdWithdraw = "withdraw"
+
+	// CmdWithdrawal command returns the withdrawal of a proposal. The
+	// command does not require a payload. The proposal token is provided
+	// in the plugin command.
+	CmdWithdrawal = "withdrawal"
 )
 
 // Plugin setting keys can be used to specify custom plugin settings. Default
@@ -80,6 +97,38 @@ const (
 	// SettingKeyBillingStatusChangesPageSize is the plugin key for
 	// the SettingBillingStatusChangesPageSize plugin setting.
 	SettingKeyBillingStatusChangesPageSize = "billingstatuschangespagesize"
+
+	// SettingKeyStatusCacheWarm is the plugin setting key for the
+	// SettingStatusCacheWarm plugin setting.
+	SettingKeyStatusCacheWarm = "statuscachewarm"
+
+	// SettingKeyBillingStatusReasonRequired is the plugin setting key
+	// for the SettingBillingStatusReasonRequired plugin setting.
+	SettingKeyBillingStatusReasonRequired = "billingstatusreasonrequired"
+
+	// SettingKeyWebhookURLs is the plugin setting key for the
+	// SettingWebhookURLs plugin setting.
+	SettingKeyWebhookURLs = "webhookurls"
+
+	// SettingKeyAbandonReasonLengthMin is the plugin setting key for
+	// the SettingAbandonReasonLengthMin plugin setting.
+	SettingKeyAbandonReasonLengthMin = "abandonreasonlengthmin"
+
+	// SettingKeyFsckRepair is the plugin setting key for the
+	// SettingFsckRepair plugin setting.
+	SettingKeyFsckRepair = "fsckrepair"
+
+	// SettingKeyCommentLengthMaxUnvetted is the plugin setting key for
+	// the SettingCommentLengthMaxUnvetted plugin setting.
+	SettingKeyCommentLengthMaxUnvetted = "commentlengthmaxunvetted"
+
+	// SettingKeyCommentLengthMaxVetted is the plugin setting key for
+	// the SettingCommentLengthMaxVetted plugin setting.
+	SettingKeyCommentLengthMaxVetted = "commentlengthmaxvetted"
+
+	// SettingKeyCommentLockDays is the plugin setting key for the
+	// SettingCommentLockDays plugin setting.
+	SettingKeyCommentLockDays = "commentlockdays"
 )
 
 // Plugin setting default values. These can be overridden by providing a plugin
@@ -132,6 +181,41 @@ const (
 	// SettingBillingStatusChangesPageSize is the default maximum number of
 	// billing status changes that can be requested at any one time.
 	SettingBillingStatusChangesPageSize uint32 = 5
+
+	// SettingStatusCacheWarm is the default value for whether the
+	// proposal statuses cache is pre-warmed on startup. When enabled,
+	// the vetted inventory is walked in the background once the plugin
+	// has been setup and the statuses of finished proposals are cached.
+	// The ticketvote plugin must be registered.
+	SettingStatusCacheWarm = false
+
+	// SettingAbandonReasonLengthMin is the default minimum number of
+	// characters that the reason of a public proposal abandonment must
+	// contain. Leading and trailing whitespace is not counted.
+	SettingAbandonReasonLengthMin uint32 = 20
+
+	// SettingFsckRepair is the default value for whether the pi fsck
+	// deletes the corrupt and orphaned plugin blobs that it finds. When
+	// disabled, the fsck only reports the problems that it finds.
+	SettingFsckRepair = false
+
+	// SettingCommentLengthMaxUnvetted is the default maximum number of
+	// characters that a comment on an unvetted proposal can contain. A
+	// value of 0 means that only the comments plugin length limit is
+	// applied.
+	SettingCommentLengthMaxUnvetted uint32 = 0
+
+	// SettingCommentLengthMaxVetted is the default maximum number of
+	// characters that a comment on a vetted proposal can contain. A
+	// value of 0 means that only the comments plugin length limit is
+	// applied.
+	SettingCommentLengthMaxVetted uint32 = 0
+
+	// SettingCommentLockDays is the default number of days after a
+	// proposal vote has finished that new comments are still allowed
+	// on the proposal. Once this period has elapsed the proposal
+	// comments are locked. A value of 0 disables the lock.
+	SettingCommentLockDays uint32 = 0
 )
 
 var (
@@ -149,6 +233,19 @@ var (
 		"research",
 		"design",
 	}
+
+	// SettingBillingStatusReasonRequired contains the billing statuses
+	// that require a reason to be provided when a proposal's billing
+	// status is set to them. The statuses are specified using their
+	// human readable names from the BillingStatuses map.
+	SettingBillingStatusReasonRequired = []string{
+		BillingStatuses[BillingStatusClosed],
+	}
+
+	// SettingWebhookURLs contains the URLs that the pi plugin POSTs a
+	// webhook to when a proposal reaches a terminal status. Webhooks
+	// are disabled by default.
+	SettingWebhookURLs = []string{}
 )
 
 // ErrorCodeT represents a plugin error that was caused by the user.
@@ -246,10 +343,50 @@ const (
 	// during a normal proposal submission.
 	ErrorCodeLegacyTokenNotAllowed = 20
 
+	// ErrorCodeCompletionReportInvalid is returned when a completion report
+	// is invalid or is provided with a billing status other than completed.
+	ErrorCodeCompletionReportInvalid = 21
+
+	// ErrorCodeAbandonVoteInProgress is returned when an admin attempts to
+	// abandon a public proposal that has a vote in progress.
+	ErrorCodeAbandonVoteInProgress = 22
+
+	// ErrorCodeAbandonBillingActive is returned when an admin attempts to
+	// abandon a public proposal that has been approved and is still actively
+	// billing against the treasury.
+	ErrorCodeAbandonBillingActive = 23
+
+	// ErrorCodeAbandonReasonInvalid is returned when the reason that is
+	// given for abandoning a public proposal is shorter than the
+	// SettingAbandonReasonLengthMin plugin setting.
+	ErrorCodeAbandonReasonInvalid = 24
+
+	// ErrorCodeTokenAmbiguous is returned when a token prefix is provided
+	// as part of a plugin command payload that matches the token that was
+	// used in the API request, but is too short to unambiguously identify
+	// the record. Token prefixes must be at least as long as the short
+	// token.
+	ErrorCodeTokenAmbiguous = 25
+
+	// ErrorCodeWithdrawNotAllowed is returned when a proposal withdrawal
+	// is not allowed. A proposal can only be withdrawn by its author and
+	// only while it is under review, i.e. prior to the proposal vote being
+	// authorized.
+	ErrorCodeWithdrawNotAllowed = 26
+
+	// ErrorCodeCommentLengthInvalid is returned when a comment exceeds the
+	// maximum comment length that is allowed for the record state.
+	ErrorCodeCommentLengthInvalid = 27
+
+	// ErrorCodeCommentsLocked is returned when a user attempts to submit a
+	// new comment on a proposal whose comments have been locked because the
+	// CommentLockDays period after the vote finished has elapsed.
+	ErrorCodeCommentsLocked = 28
+
 	// ErrorCodeLast is used by unit tests to verify that all error codes have
 	// a human readable entry in the ErrorCodes map. This error will never be
 	// returned.
-	ErrorCodeLast ErrorCodeT = 21
+	ErrorCodeLast ErrorCodeT = 29
 )
 
 var (
@@ -276,9 +413,37 @@ var (
 		ErrorCodeExtraDataHintInvalid:          "extra data hint invalid",
 		ErrorCodeLegacyTokenNotAllowed:         "setting legacy token is not allowed",
 		ErrorCodeExtraDataInvalid:              "extra data payload invalid",
+		ErrorCodeCompletionReportInvalid:       "completion report invalid",
+		ErrorCodeAbandonVoteInProgress:         "proposal vote is in progress",
+		ErrorCodeAbandonBillingActive:          "proposal billing is active",
+		ErrorCodeAbandonReasonInvalid:          "abandonment reason invalid",
+		ErrorCodeTokenAmbiguous:                "token prefix is ambiguous",
+		ErrorCodeWithdrawNotAllowed:            "withdrawal is not allowed",
+		ErrorCodeCommentLengthInvalid:          "comment length invalid",
+		ErrorCodeCommentsLocked:                "comments are locked",
 	}
 )
 
+const (
+	// The following keys are used in the error params of a plugin error
+	// that was caused by invalid user input. The error params allow
+	// clients to translate the error without parsing the error context.
+	// Only the params that are relevant to the error are included.
+
+	// ErrorParamField contains the name of the field or file that failed
+	// validation.
+	ErrorParamField = "field"
+
+	// ErrorParamMin contains the minimum allowed value.
+	ErrorParamMin = "min"
+
+	// ErrorParamMax contains the maximum allowed value.
+	ErrorParamMax = "max"
+
+	// ErrorParamGot contains the value that was provided by the user.
+	ErrorParamGot = "got"
+)
+
 const (
 	// FileNameIndexFile is the file name of the proposal markdown
 	// file. Every proposal is required to have an index file. The
@@ -365,38 +530,53 @@ var (
 //
 // PublicKey is the admin public key that can be used to verify the signature.
 //
-// Signature is the admin signature of the Token+Status+Reason.
+// Signature is the admin signature of the Token+Status+Reason. If a completion
+// report was attached to the billing status change then the signature is of
+// the Token+Status+Reason+ReportDigest.
+//
+// ReportDigest is the SHA256 digest of the completion report that was
+// attached to the billing status change, if one was attached.
 //
 // Receipt is the server signature of the admin signature.
 //
 // The PublicKey, Signature, and Receipt are all hex encoded and use the
 // ed25519 signature scheme.
 type BillingStatusChange struct {
-	Token     string         `json:"token"`
-	Status    BillingStatusT `json:"status"`
-	Reason    string         `json:"reason,omitempty"`
-	PublicKey string         `json:"publickey"`
-	Signature string         `json:"signature"`
-	Receipt   string         `json:"receipt"`
-	Timestamp int64          `json:"timestamp"` // Unix timestamp
+	Token        string         `json:"token"`
+	Status       BillingStatusT `json:"status"`
+	Reason       string         `json:"reason,omitempty"`
+	ReportDigest string         `json:"reportdigest,omitempty"`
+	PublicKey    string         `json:"publickey"`
+	Signature    string         `json:"signature"`
+	Receipt      string         `json:"receipt"`
+	Timestamp    int64          `json:"timestamp"` // Unix timestamp
 }
 
 // SetBillingStatus sets the billing status of a proposal. Some billing status
 // changes require a reason to be given. Only admins can update the billing
 // status of a proposal.
 //
+// Token can be either the full length token or a prefix of the token that is
+// at least as long as the short token.
+//
 // PublicKey is the admin public key that can be used to verify the signature.
 //
-// Signature is the admin signature of the Token+Status+Reason.
+// Report is an optional completion report. A completion report can only be
+// attached when the billing status is being set to completed.
+//
+// Signature is the admin signature of the Token+Status+Reason. If a completion
+// report is attached then the signature is of the
+// Token+Status+Reason+Report.Digest.
 //
 // The PublicKey and Signature are hex encoded and use the ed25519 signature
 // scheme.
 type SetBillingStatus struct {
-	Token     string         `json:"token"`
-	Status    BillingStatusT `json:"status"`
-	Reason    string         `json:"reason,omitempty"`
-	PublicKey string         `json:"publickey"`
-	Signature string         `json:"signature"`
+	Token     string            `json:"token"`
+	Status    BillingStatusT    `json:"status"`
+	Reason    string            `json:"reason,omitempty"`
+	Report    *CompletionReport `json:"report,omitempty"`
+	PublicKey string            `json:"publickey"`
+	Signature string            `json:"signature"`
 }
 
 // SetBillingStatusReply is the reply to the SetBillingStatus command.
@@ -493,8 +673,10 @@ const (
 	PropStatusApproved PropStatusT = "approved"
 
 	// PropStatusRejected represents a proposal that was voted on by the Decred
-	// stakeholders and did not meet the approval criteria. A rejected proposal
-	// is locked against any additional proposal or plugin changes.
+	// stakeholders and did not meet the approval criteria. This includes
+	// votes that finished without an approved or rejected outcome, such as
+	// multiple choice votes. A rejected proposal is locked against any
+	// additional proposal or plugin changes.
 	PropStatusRejected PropStatusT = "rejected"
 
 	// PropStatusActive represents a proposal that was voted on by the Decred
@@ -519,6 +701,13 @@ const (
 	// out in the  proposal. A closed proposal is locked against any additional
 	// proposal or plugin changes.
 	PropStatusClosed PropStatusT = "closed"
+
+	// PropStatusLegacy represents a legacy proposal that was imported from
+	// the deprecated git backend and whose status cannot be determined from
+	// the imported data, e.g. the proposal is missing plugin data that did
+	// not exist in the git backend. Legacy proposals cannot be changed, so
+	// this status is final.
+	PropStatusLegacy PropStatusT = "legacy"
 )
 
 const (
@@ -545,3 +734,167 @@ type BillingStatusChanges struct {
 type BillingStatusChangesReply struct {
 	BillingStatusChanges []BillingStatusChange `json:"billingstatuschanges"`
 }
+
+// Identity requests the public key of the server identity that the pi plugin
+// uses to sign receipts. This allows clients to verify the receipts, e.g. the
+// BillingStatusChange receipts, offline.
+type Identity struct{}
+
+// IdentityReply is the reply to the Identity command.
+//
+// PublicKey is the hex encoded, ed25519 public key of the server identity.
+type IdentityReply struct {
+	PublicKey string `json:"publickey"`
+}
+
+const (
+	// EventProposalStatusChange is the plugin event that is emitted when
+	// the pi plugin observes a proposal status transition, e.g. a vote
+	// being approved, a billing status being set, or a proposal being
+	// archived. The event payload is a JSON encoded ProposalStatusChange.
+	EventProposalStatusChange = "pi-proposalstatuschange"
+)
+
+// ProposalStatusChange is the payload of the EventProposalStatusChange
+// plugin event.
+//
+// The proposal status is determined at runtime, so a status transition that
+// is not the result of a write, such as a ticket vote finishing, is emitted
+// the first time that the pi plugin determines the new proposal status. The
+// last observed status of each proposal is persisted, so transitions are
+// detected across restarts. PrevStatus is the last observed status, which may
+// skip intermediate statuses that were never observed.
+type ProposalStatusChange struct {
+	Token      string      `json:"token"`
+	PrevStatus PropStatusT `json:"prevstatus"`
+	Status     PropStatusT `json:"status"`
+	Timestamp  int64       `json:"timestamp"` // Unix timestamp
+}
+
+const (
+	// WebhookHeaderEvent is the HTTP header that contains the name of
+	// the event that triggered a webhook.
+	WebhookHeaderEvent = "X-Politeia-Event"
+
+	// WebhookHeaderSignature is the HTTP header that contains the hex
+	// encoded, ed25519 signature of the webhook request body. The body
+	// is signed using the pi plugin server identity. See the Identity
+	// command for the public key.
+	WebhookHeaderSignature = "X-Politeia-Signature"
+)
+
+// WebhookStatuses contains the proposal statuses that trigger a webhook. These
+// are the statuses that a proposal transitions to once its vote has finished
+// and once its billing has ended. An approved proposal that bills against the
+// treasury transitions directly to the active status, so the active status is
+// included as well.
+var WebhookStatuses = map[PropStatusT]struct{}{
+	PropStatusApproved:  {},
+	PropStatusActive:    {},
+	PropStatusRejected:  {},
+	PropStatusCompleted: {},
+	PropStatusClosed:    {},
+}
+
+// Webhook is the JSON encoded request body of a webhook. Webhooks are sent
+// using a HTTP POST request to each of the URLs in the SettingWebhookURLs
+// plugin setting when a proposal transitions to one of the WebhookStatuses.
+//
+// Webhook delivery is best effort. A delivery that fails due to a network
+// error or a 5xx or 429 response is retried a limited number of times before
+// the webhook is dropped. Other non-2xx responses are not retried.
+type Webhook struct {
+	Event                string               `json:"event"`
+	ProposalStatusChange ProposalStatusChange `json:"proposalstatuschange"`
+}
+
+// CompletionReport is a final report that an admin can attach to a proposal
+// when setting its billing status to completed. It provides the community
+// with an auditable close-out artifact for the proposal.
+//
+// The completion report is subject to the same restrictions as the proposal
+// text files. It must be a plain text file that does not exceed the text file
+// size limit.
+//
+// Digest is the hex encoded SHA256 digest of the unencoded payload.
+//
+// Payload is the base64 encoded report contents.
+type CompletionReport struct {
+	Name    string `json:"name"`
+	MIME    string `json:"mime"`
+	Digest  string `json:"digest"`
+	Payload string `json:"payload"`
+}
+
+// CompletionReportReply is the reply to the CompletionReport command. The
+// report will be nil if the proposal does not have a completion report.
+type CompletionReportReply struct {
+	Report *CompletionReport `json:"report,omitempty"`
+}
+
+// Withdrawal represents the structure that is saved to disk when the author
+// of a proposal withdraws it. A proposal can only be withdrawn while it is
+// under review, i.e. prior to the proposal vote being authorized. The
+// withdrawal is followed by the proposal being archived and the proposal
+// credit that was spent on the proposal being refunded to the author.
+//
+// Version is the version of the proposal that was withdrawn.
+//
+// PublicKey is the author public key that can be used to verify the
+// signature.
+//
+// Signature is the author signature of the Token+Version+Status+Reason,
+// where Status is the archived record status. This is the same message
+// that is signed for a record status change, which allows the signature to
+// be used for both the withdrawal and the resulting archival of the record.
+//
+// Receipt is the server signature of the author signature.
+//
+// The PublicKey, Signature, and Receipt are all hex encoded and use the
+// ed25519 signature scheme.
+type Withdrawal struct {
+	Token     string `json:"token"`
+	Version   uint32 `json:"version"`
+	Reason    string `json:"reason"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+	Receipt   string `json:"receipt"`
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
+}
+
+// Withdraw withdraws a proposal on behalf of its author. The reason is subject
+// to the SettingAbandonReasonLengthMin plugin setting.
+//
+// Token can be either the full length token or a prefix of the token that is
+// at least as long as the short token.
+//
+// UserID is the user ID of the author. It is provided by politeiawww, which
+// has already verified that the author signed the withdrawal using their
+// active identity.
+//
+// Signature is the author signature of the Token+Version+Status+Reason, where
+// Status is the archived record status. See the Withdrawal documentation for
+// more details.
+type Withdraw struct {
+	Token     string `json:"token"`
+	Version   uint32 `json:"version"`
+	Reason    string `json:"reason"`
+	UserID    string `json:"userid"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// WithdrawReply is the reply to the Withdraw command.
+//
+// Receipt is the server signature of the client signature. It is hex encoded
+// and uses the ed25519 signature scheme.
+type WithdrawReply struct {
+	Receipt   string `json:"receipt"`
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
+}
+
+// WithdrawalReply is the reply to the Withdrawal command. The withdrawal will
+// be nil if the proposal has not been withdrawn.
+type WithdrawalReply struct {
+	Withdrawal *Withdrawal `json:"withdrawal,omitempty"`
+}
@@ -20,6 +20,53 @@ const (
 	CmdSubmissions = "submissions" // Get runoff vote submissions
 	CmdInventory   = "inventory"   // Get inventory by vote status
 	CmdTimestamps  = "timestamps"  // Get vote timestamps
+	CmdSnapshot    = "snapshot"    // Get eligible ticket snapshot
+	CmdEligibility = "eligibility" // Check ticket eligibility
+	CmdExtend      = "extend"      // Extend an active vote
+	CmdCancel      = "cancel"      // Cancel a started vote
+
+	// CmdResultsExport exports the cast votes of a record vote.
+	CmdResultsExport = "resultsexport"
+
+	// CmdReceipts returns the cast vote receipts of a set of tickets.
+	CmdReceipts = "receipts"
+
+	// CmdActiveVotesRebuild rebuilds the active votes cache.
+	CmdActiveVotesRebuild = "activevotesrebuild"
+
+	// CmdSnapshotVerify re-verifies the eligible ticket snapshot of a
+	// record vote against the chain.
+	CmdSnapshotVerify = "snapshotverify"
+
+	// CmdVoteStats returns the turnout and participation statistics of
+	// a finished record vote.
+	CmdVoteStats = "votestats"
+
+	// CmdVote returns the cast vote of a single ticket.
+	CmdVote = "vote"
+
+	// CmdSetVoteDuration sets the vote duration bounds.
+	CmdSetVoteDuration = "setvoteduration"
+
+	// CmdGovernanceExport returns the governance export of all finished
+	// votes.
+	CmdGovernanceExport = "governanceexport"
+
+	// CmdVoteParamsVerify verifies a set of vote params without
+	// starting a vote.
+	CmdVoteParamsVerify = "voteparamsverify"
+
+	// CmdAuditBundle returns a signed audit bundle of a finished record
+	// vote.
+	CmdAuditBundle = "auditbundle"
+
+	// CmdTicketOwnership verifies signed proofs of ticket ownership
+	// against the eligible ticket snapshot of a record vote.
+	CmdTicketOwnership = "ticketownership"
+
+	// CmdVoteLoad returns the number of votes that are currently
+	// running.
+	CmdVoteLoad = "voteload"
 )
 
 // Plugin setting keys can be used to specify custom plugin settings. Default
@@ -53,6 +100,61 @@ const (
 	// SettingKeyTimestampsPageSize is the plugin setting key for the
 	// SettingTimestampsPageSize plugin setting.
 	SettingKeyTimestampsPageSize = "timestampspagesize"
+
+	// SettingKeyVoteExtensionMax is the plugin setting key for the
+	// SettingVoteExtensionMax plugin setting.
+	SettingKeyVoteExtensionMax = "voteextensionmax"
+
+	// SettingKeyResultsExportPageSize is the plugin setting key for the
+	// SettingResultsExportPageSize plugin setting.
+	SettingKeyResultsExportPageSize = "resultsexportpagesize"
+
+	// SettingKeyChain is the plugin setting key for the SettingChain
+	// plugin setting.
+	SettingKeyChain = "chain"
+
+	// SettingKeyEligibility is the plugin setting key for the
+	// SettingEligibility plugin setting.
+	SettingKeyEligibility = "eligibility"
+
+	// SettingKeyVoterList is the plugin setting key for the voter list
+	// source. The voter list source is a file path or an http(s) URL
+	// that the voter list is retrieved from. It is required when the
+	// eligibility plugin setting is set to EligibilityVoterList. See
+	// EligibilityVoterList for the voter list format.
+	SettingKeyVoterList = "voterlist"
+
+	// SettingKeyAuthLockBlocks is the plugin setting key for the
+	// SettingAuthLockBlocks plugin setting.
+	SettingKeyAuthLockBlocks = "authlockblocks"
+
+	// SettingKeyRunoffStartBatchSize is the plugin setting key for the
+	// SettingRunoffStartBatchSize plugin setting.
+	SettingKeyRunoffStartBatchSize = "runoffstartbatchsize"
+
+	// SettingKeyRunoffStartWorkers is the plugin setting key for the
+	// SettingRunoffStartWorkers plugin setting.
+	SettingKeyRunoffStartWorkers = "runoffstartworkers"
+
+	// SettingKeyCastBallotWorkers is the plugin setting key for the
+	// SettingCastBallotWorkers plugin setting.
+	SettingKeyCastBallotWorkers = "castballotworkers"
+
+	// SettingKeySnapshotHeightOverride is the plugin setting key for the
+	// SettingSnapshotHeightOverride plugin setting.
+	SettingKeySnapshotHeightOverride = "snapshotheightoverride"
+
+	// SettingKeyEarlyFinish is the plugin setting key for the
+	// SettingEarlyFinish plugin setting.
+	SettingKeyEarlyFinish = "earlyfinish"
+
+	// SettingKeyConcurrentVotesMax is the plugin setting key for the
+	// SettingConcurrentVotesMax plugin setting.
+	SettingKeyConcurrentVotesMax = "concurrentvotesmax"
+
+	// SettingKeyConcurrentVotesBlock is the plugin setting key for the
+	// SettingConcurrentVotesBlock plugin setting.
+	SettingKeyConcurrentVotesBlock = "concurrentvotesblock"
 )
 
 // Plugin setting default values. These can be overridden by providing a plugin
@@ -108,6 +210,115 @@ const (
 	// SettingTimestampsPageSize is the default maximum number of comment
 	// timestamps that can be requested at any one time.
 	SettingTimestampsPageSize uint32 = 100
+
+	// SettingMainNetVoteExtensionMax is the default maximum number of
+	// blocks, in total, that a vote can be extended by on mainnet.
+	// This value of 576 blocks (~2 days) was chosen to cover a
+	// temporary outage of the services that stakeholders use to vote.
+	SettingMainNetVoteExtensionMax uint32 = 576
+
+	// SettingTestNetVoteExtensionMax is the default maximum number of
+	// blocks, in total, that a vote can be extended by on testnet.
+	SettingTestNetVoteExtensionMax uint32 = 4032
+
+	// SettingResultsExportPageSize is the default maximum number of cast
+	// votes that will be returned in a single ResultsExportReply.
+	SettingResultsExportPageSize uint32 = 5000
+
+	// SettingChain is the default chain that is used to run votes. The
+	// chain provides the best block and the snapshot block of a vote.
+	// See the Chain constants for the supported chains.
+	SettingChain = ChainDecred
+
+	// SettingEligibility is the default voting eligibility source. The
+	// eligibility source provides the eligible tickets of a vote and the
+	// addresses that must sign the votes. See the Eligibility constants
+	// for the supported eligibility sources.
+	SettingEligibility = EligibilityDecred
+
+	// SettingAuthLockBlocks is the default number of blocks, after a
+	// vote has been authorized, that the vote authorization cannot be
+	// revoked. This gives admins a window to start the vote without the
+	// author revoking the authorization at the last second. A value of
+	// zero disables the lock.
+	SettingAuthLockBlocks uint32 = 0
+
+	// SettingRunoffStartBatchSize is the default number of runoff vote
+	// submissions that have their voting period started in a single
+	// batch. If any submission in a batch fails to start, the remaining
+	// batches are not started and the runoff vote start is rolled back.
+	SettingRunoffStartBatchSize uint32 = 20
+
+	// SettingRunoffStartWorkers is the default number of runoff vote
+	// submissions in a batch that have their voting period started
+	// concurrently.
+	SettingRunoffStartWorkers uint32 = 5
+
+	// SettingCastBallotWorkers is the default number of cast votes in a
+	// ballot that have their commitment address and signature verified
+	// concurrently.
+	SettingCastBallotWorkers uint32 = 8
+
+	// SettingSnapshotHeightOverride is the default value of the bool
+	// flag which determines if the snapshot height of a vote can be
+	// provided when the vote is started. This allows the ticketvote
+	// plugin to be integration tested deterministically. The setting
+	// can not be enabled on mainnet.
+	SettingSnapshotHeightOverride = false
+
+	// SettingEarlyFinish is the default value of the bool flag which
+	// determines if a vote is finished early once the outcome of the
+	// vote can no longer change, i.e. all eligible tickets have voted.
+	// The final vote summary is saved immediately instead of waiting
+	// for the end block height of the vote. Runoff votes are always
+	// run until the end block height.
+	SettingEarlyFinish = false
+
+	// SettingConcurrentVotesMax is the default maximum number of votes
+	// that can be running concurrently before starting a standard vote
+	// is considered to cause voter fatigue. Exceeding the max results
+	// in a warning unless the SettingConcurrentVotesBlock setting is
+	// enabled. A value of zero disables the check.
+	SettingConcurrentVotesMax uint32 = 0
+
+	// SettingConcurrentVotesBlock is the default value of the bool flag
+	// which determines if a standard vote is prevented from being
+	// started when it would exceed the SettingConcurrentVotesMax.
+	SettingConcurrentVotesBlock = false
+)
+
+const (
+	// ChainDecred uses the Decred blockchain to run votes. The chain
+	// data is retrieved using the dcrdata plugin.
+	ChainDecred = "decred"
+)
+
+const (
+	// EligibilityDecred uses Decred tickets as the voting eligibility
+	// source. The ticket data is retrieved using the dcrdata plugin. The
+	// eligible tickets of a vote are the live tickets at the vote's
+	// snapshot block and the votes must be signed by the largest
+	// commitment address of each ticket.
+	EligibilityDecred = "decred"
+
+	// EligibilityVoterList uses an externally supplied voter list as the
+	// voting eligibility source. This allows stake-weighted or
+	// membership-weighted votes to be run. The voter list is retrieved
+	// from the voter list source when a vote is started.
+	//
+	// The voter list is a CSV file with one voter per line. Each line
+	// contains the voter ID, the hex encoded ed25519 public key of the
+	// voter, and the voting weight of the voter. Lines that begin with a
+	// '#' are ignored. Example:
+	//
+	//   alice,<pubkey>,1
+	//
+	// A voter is given one eligible ticket for each unit of voting weight.
+	// The ticket hashes of a voter are the hex encoded SHA256 digests of
+	// the public key concatenated with the base 10 ticket index, starting
+	// at index 0, i.e. SHA256(pubkey + "0"), SHA256(pubkey + "1"), etc.
+	// The votes of each ticket must be signed by the voter's public key.
+	EligibilityVoterList = "voterlist"
 )
 
 // ErrorCodeT represents and error that is caused by the user.
@@ -202,34 +413,81 @@ const (
 	// command is executed on a record that is not public.
 	ErrorCodeRecordStatusInvalid ErrorCodeT = 20
 
+	// ErrorCodeWinConditionInvalid is returned when the win condition
+	// of a start details is invalid.
+	ErrorCodeWinConditionInvalid ErrorCodeT = 21
+
+	// ErrorCodeVoteExtensionInvalid is returned when a vote extension
+	// is invalid.
+	ErrorCodeVoteExtensionInvalid ErrorCodeT = 22
+
+	// ErrorCodeExportFormatInvalid is returned when a results export
+	// format is invalid.
+	ErrorCodeExportFormatInvalid ErrorCodeT = 23
+
+	// ErrorCodeRunoffWinnersInvalid is returned when the number of
+	// winners of a start details is invalid.
+	ErrorCodeRunoffWinnersInvalid ErrorCodeT = 24
+
+	// ErrorCodeAuthorizationLocked is returned when a vote authorization
+	// is attempted to be revoked before the authorization lock period
+	// has expired.
+	ErrorCodeAuthorizationLocked ErrorCodeT = 25
+
+	// ErrorCodeVoteNotFound is returned when a cast vote is requested
+	// for a ticket that has not voted.
+	ErrorCodeVoteNotFound ErrorCodeT = 26
+
+	// ErrorCodeVoteWeightInvalid is returned when the vote weight of a
+	// vote is invalid or is not supported by the ticket eligibility.
+	ErrorCodeVoteWeightInvalid ErrorCodeT = 27
+
+	// ErrorCodeVoteCancelInvalid is returned when a vote cancellation
+	// is invalid.
+	ErrorCodeVoteCancelInvalid ErrorCodeT = 28
+
+	// ErrorCodeConcurrentVotesExceeded is returned when a vote can not
+	// be started because it would exceed the maximum number of votes
+	// that are allowed to run concurrently.
+	ErrorCodeConcurrentVotesExceeded ErrorCodeT = 29
+
 	// ErrorCodeLast unit test only
-	ErrorCodeLast ErrorCodeT = 21
+	ErrorCodeLast ErrorCodeT = 30
 )
 
 var (
 	// ErrorCodes contains the human readable error messages.
 	ErrorCodes = map[ErrorCodeT]string{
-		ErrorCodeInvalid:              "error code invalid",
-		ErrorCodeTokenInvalid:         "token invalid",
-		ErrorCodePublicKeyInvalid:     "public key invalid",
-		ErrorCodeSignatureInvalid:     "signature invalid",
-		ErrorCodeRecordVersionInvalid: "record version invalid",
-		ErrorCodeAuthorizationInvalid: "authorization invalid",
-		ErrorCodeStartDetailsMissing:  "start details missing",
-		ErrorCodeStartDetailsInvalid:  "start details invalid",
-		ErrorCodeVoteTypeInvalid:      "vote type invalid",
-		ErrorCodeVoteDurationInvalid:  "vote duration invalid",
-		ErrorCodeVoteQuorumInvalid:    "quorum percentage invalid",
-		ErrorCodeVotePassRateInvalid:  "pass rate invalid",
-		ErrorCodeVoteOptionsInvalid:   "vote options invalid",
-		ErrorCodeVoteBitsInvalid:      "vote bits invalid",
-		ErrorCodeVoteParentInvalid:    "vote parent invalid",
-		ErrorCodeVoteStatusInvalid:    "vote status invalid",
-		ErrorCodeVoteMetadataInvalid:  "vote metadata invalid",
-		ErrorCodeLinkByInvalid:        "linkby invalid",
-		ErrorCodeLinkToInvalid:        "linkto invalid",
-		ErrorCodeLinkByNotExpired:     "linkby not exipred",
-		ErrorCodeRecordStatusInvalid:  "record status invalid",
+		ErrorCodeInvalid:                 "error code invalid",
+		ErrorCodeTokenInvalid:            "token invalid",
+		ErrorCodePublicKeyInvalid:        "public key invalid",
+		ErrorCodeSignatureInvalid:        "signature invalid",
+		ErrorCodeRecordVersionInvalid:    "record version invalid",
+		ErrorCodeAuthorizationInvalid:    "authorization invalid",
+		ErrorCodeStartDetailsMissing:     "start details missing",
+		ErrorCodeStartDetailsInvalid:     "start details invalid",
+		ErrorCodeVoteTypeInvalid:         "vote type invalid",
+		ErrorCodeVoteDurationInvalid:     "vote duration invalid",
+		ErrorCodeVoteQuorumInvalid:       "quorum percentage invalid",
+		ErrorCodeVotePassRateInvalid:     "pass rate invalid",
+		ErrorCodeVoteOptionsInvalid:      "vote options invalid",
+		ErrorCodeVoteBitsInvalid:         "vote bits invalid",
+		ErrorCodeVoteParentInvalid:       "vote parent invalid",
+		ErrorCodeVoteStatusInvalid:       "vote status invalid",
+		ErrorCodeVoteMetadataInvalid:     "vote metadata invalid",
+		ErrorCodeLinkByInvalid:           "linkby invalid",
+		ErrorCodeLinkToInvalid:           "linkto invalid",
+		ErrorCodeLinkByNotExpired:        "linkby not exipred",
+		ErrorCodeRecordStatusInvalid:     "record status invalid",
+		ErrorCodeWinConditionInvalid:     "win condition invalid",
+		ErrorCodeVoteExtensionInvalid:    "vote extension invalid",
+		ErrorCodeExportFormatInvalid:     "export format invalid",
+		ErrorCodeRunoffWinnersInvalid:    "runoff winners invalid",
+		ErrorCodeAuthorizationLocked:     "authorization locked",
+		ErrorCodeVoteNotFound:            "vote not found",
+		ErrorCodeVoteWeightInvalid:       "vote weight invalid",
+		ErrorCodeVoteCancelInvalid:       "vote cancellation invalid",
+		ErrorCodeConcurrentVotesExceeded: "concurrent votes exceeded",
 	}
 )
 
@@ -272,6 +530,11 @@ type AuthDetails struct {
 	// Metadata generated by server
 	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
 	Receipt   string `json:"receipt"`   // Server signature of client signature
+
+	// BlockHeight is the best block height at the time of the action.
+	// It is only populated when the authorization lock plugin setting
+	// is enabled.
+	BlockHeight uint32 `json:"blockheight,omitempty"`
 }
 
 // VoteT represents the different types of ticket votes that are available.
@@ -288,18 +551,97 @@ const (
 	VoteTypeStandard VoteT = 1
 
 	// VoteTypeRunoff specifies a runoff vote that multiple records
-	// compete in. All records are voted on like normal, but there can
-	// only be one winner in a runoff vote. The winner is the record
-	// that meets the quorum requirement, meets the pass requirement,
-	// and that has the most net yes votes. The winning record is
-	// considered approved and all other records are considered to be
-	// rejected. If no records meet the quorum and pass requirements
-	// then all records are considered rejected. Note, in a runoff vote
-	// it's possible for a record to meet both the quorum and pass
-	// requirements but still be rejected if it does not have the most
-	// net yes votes. Runoff vote participants are not required to have
-	// the voting period authorized prior to the vote starting.
+	// compete in. All records are voted on like normal, but only the
+	// number of winners that is specified by the vote params can be
+	// approved. By default there is a single winner. The winners are
+	// the records that meet the quorum requirement, meet the pass
+	// requirement, and that have the most net yes votes. The winning
+	// records are considered approved and all other records are
+	// considered to be rejected. If no records meet the quorum and pass
+	// requirements then all records are considered rejected. Note, in a
+	// runoff vote it's possible for a record to meet both the quorum
+	// and pass requirements but still be rejected if it does not have
+	// enough net yes votes to be one of the winners. Runoff vote
+	// participants are not required to have the voting period
+	// authorized prior to the vote starting.
 	VoteTypeRunoff VoteT = 2
+
+	// VoteTypeMultiOption specifies a vote that has multiple, mutually
+	// exclusive vote options. Each vote option is tallied separately and
+	// the winning option is determined using the win condition of the
+	// vote. A multi-option vote does not have an approved or rejected
+	// outcome. The vote status is set to finished once the voting period
+	// has ended and the winning option, if there is one, is included in
+	// the vote summary. Multi-option votes must be authorized before the
+	// vote can be started.
+	VoteTypeMultiOption VoteT = 3
+
+	// VoteTypeLast unit test only.
+	VoteTypeLast VoteT = 4
+)
+
+var (
+	// VoteTypes contains the human readable vote types.
+	VoteTypes = map[VoteT]string{
+		VoteTypeInvalid:     "invalid vote type",
+		VoteTypeStandard:    "standard",
+		VoteTypeRunoff:      "runoff",
+		VoteTypeMultiOption: "multi-option",
+	}
+)
+
+// WinConditionT represents the condition that a vote option must meet in order
+// to win a multi-option vote. All win conditions require the vote to meet the
+// quorum requirement. A tie for the most votes results in no winning option.
+type WinConditionT uint32
+
+const (
+	// WinConditionInvalid is an invalid win condition.
+	WinConditionInvalid WinConditionT = 0
+
+	// WinConditionPlurality indicates that the vote option with the most
+	// votes wins. The pass percentage must be zero for this condition.
+	WinConditionPlurality WinConditionT = 1
+
+	// WinConditionPassPercentage indicates that the vote option with the
+	// most votes wins only if it also received the pass percentage of
+	// the cast votes.
+	WinConditionPassPercentage WinConditionT = 2
+)
+
+var (
+	// WinConditions contains the human readable win conditions.
+	WinConditions = map[WinConditionT]string{
+		WinConditionInvalid:        "invalid",
+		WinConditionPlurality:      "plurality",
+		WinConditionPassPercentage: "pass percentage",
+	}
+)
+
+// VoteWeightT represents how the cast votes are weighted when calculating the
+// quorum and pass requirements of a vote.
+type VoteWeightT uint32
+
+const (
+	// VoteWeightTicket weights every ticket vote equally. The quorum and
+	// pass requirements are calculated using the number of tickets. This
+	// is the default vote weight.
+	VoteWeightTicket VoteWeightT = 0
+
+	// VoteWeightStake weights every ticket vote by the DCR amount that
+	// was staked by the ticket, i.e. the sum of the ticket commitment
+	// amounts. The quorum and pass requirements are calculated using
+	// the staked DCR amounts. This vote weight can only be used when
+	// the ticket eligibility is EligibilityDecred.
+	VoteWeightStake VoteWeightT = 1
+)
+
+var (
+	// VoteWeights contains the human readable vote weights.
+	VoteWeights = map[VoteWeightT]string{
+		VoteWeightTicket: "ticket",
+		VoteWeightStake:  "stake",
+	}
 )
 
 const (
@@ -342,16 +684,45 @@ type VoteParams struct {
 	// Parent is the token of the parent record. This field will only
 	// be populated for runoff votes.
 	Parent string `json:"parent,omitempty"`
+
+	// WinCondition is the condition that determines the winning vote
+	// option. This field will only be populated for multi-option votes.
+	WinCondition WinConditionT `json:"wincondition,omitempty"`
+
+	// Winners is the maximum number of records that can be approved in a
+	// runoff vote. A value of zero is the same as one winner. Use
+	// RunoffWinnersAll to approve all records that meet the quorum and
+	// pass requirements. This field will only be populated for runoff
+	// votes and must be the same for all records in the runoff vote.
+	Winners uint32 `json:"winners,omitempty"`
+
+	// Weight is the vote weight that is used to calculate the quorum and
+	// pass requirements. The zero value is VoteWeightTicket.
+	Weight VoteWeightT `json:"weight,omitempty"`
 }
 
+// RunoffWinnersAll is the VoteParams Winners value that allows all records in
+// a runoff vote that meet the quorum and pass requirements to be approved.
+const RunoffWinnersAll = ^uint32(0)
+
 // VoteDetails is the structure that is saved to disk when a vote is started.
-// It contains all of the fields from a Start and a StartReply. A vote details
-// with the eligible tickets snapshot will be ~0.35MB.
+// It contains all of the fields from a Start and a StartReply.
 //
 // Signature is the client signature of the SHA256 digest of the JSON encoded
 // Vote struct.
 //
 // Receipt is the server signature of ClientSignature+StartBlockHash.
+//
+// The eligible tickets are saved separately from the vote details as an
+// eligible ticket snapshot, and the saved vote details only contains the
+// EligibleTicketsDigest. The EligibleTicketsDigest is the SHA256 digest of the
+// binary encoded snapshot, which is described in the SnapshotReply. Since the
+// vote details is timestamped, the digest allows the eligible tickets to be
+// verified against the vote details timestamp. Vote details that are returned
+// by the plugin commands are populated with the eligible tickets of the
+// snapshot, which are sorted in ascending order and contain no duplicates.
+// Votes that were started prior to snapshots being introduced include the
+// eligible tickets in the saved vote details and do not have a digest.
 type VoteDetails struct {
 	// Data generated by client
 	Params    VoteParams `json:"params"`
@@ -359,11 +730,12 @@ type VoteDetails struct {
 	Signature string     `json:"signature"`
 
 	// Metadata generated by server
-	Receipt          string   `json:"receipt"`
-	StartBlockHeight uint32   `json:"startblockheight"`
-	StartBlockHash   string   `json:"startblockhash"`
-	EndBlockHeight   uint32   `json:"endblockheight"`
-	EligibleTickets  []string `json:"eligibletickets"` // Ticket hashes
+	Receipt               string   `json:"receipt"`
+	StartBlockHeight      uint32   `json:"startblockheight"`
+	StartBlockHash        string   `json:"startblockhash"`
+	EndBlockHeight        uint32   `json:"endblockheight"`
+	EligibleTickets       []string `json:"eligibletickets"` // Ticket hashes
+	EligibleTicketsDigest string   `json:"eligibleticketsdigest,omitempty"`
 }
 
 // CastVoteDetails contains the details of a cast vote.
@@ -382,6 +754,10 @@ type CastVoteDetails struct {
 	Address   string `json:"address"`   // Largest commitment address
 	Receipt   string `json:"receipt"`   // Server signature
 	Timestamp int64  `json:"timestamp"` // Unix timestamp
+
+	// Stake is the sum of the ticket commitment amounts in atoms. It is
+	// only populated for votes that use VoteWeightStake.
+	Stake uint64 `json:"stake,omitempty"`
 }
 
 // AuthActionT represents the ticket vote authorization actions.
@@ -417,10 +793,19 @@ type AuthorizeReply struct {
 //
 // Signature is the signature of a SHA256 digest of the JSON encoded VoteParams
 // structure.
+//
+// SnapshotHeight overrides the block height of the eligible ticket snapshot
+// of the vote. It can only be provided when the SettingSnapshotHeightOverride
+// plugin setting has been enabled and must be a block that is at least ticket
+// maturity blocks deep. The vote still ends the vote duration after the best
+// block. The snapshot height is not part of the signature. All of the start
+// details of a runoff vote must use the same snapshot height.
 type StartDetails struct {
 	Params    VoteParams `json:"params"`
 	PublicKey string     `json:"publickey"` // Public key used for signature
 	Signature string     `json:"signature"` // Client signature
+
+	SnapshotHeight uint32 `json:"snapshotheight,omitempty"`
 }
 
 // Start starts a ticket vote.
@@ -428,15 +813,177 @@ type Start struct {
 	Starts []StartDetails `json:"starts"`
 }
 
+// VoteBitErrorT represents a vote bit validation error.
+type VoteBitErrorT uint32
+
+const (
+	// VoteBitErrorInvalid is an invalid vote bit error.
+	VoteBitErrorInvalid VoteBitErrorT = 0
+
+	// VoteBitErrorNoOptions is returned when the vote does not contain
+	// any vote options.
+	VoteBitErrorNoOptions VoteBitErrorT = 1
+
+	// VoteBitErrorBitZero is returned when a vote option bit is zero.
+	VoteBitErrorBitZero VoteBitErrorT = 2
+
+	// VoteBitErrorBitNotInMask is returned when a vote option bit is
+	// not included in the vote mask.
+	VoteBitErrorBitNotInMask VoteBitErrorT = 3
+
+	// VoteBitErrorBitNotInOptions is returned when a cast vote bit does
+	// not correspond to any of the vote options.
+	VoteBitErrorBitNotInOptions VoteBitErrorT = 4
+
+	// VoteBitErrorBitDuplicate is returned when a vote option bit is
+	// used by more than one vote option.
+	VoteBitErrorBitDuplicate VoteBitErrorT = 5
+
+	// VoteBitErrorLast unit test only.
+	VoteBitErrorLast VoteBitErrorT = 6
+)
+
+var (
+	// VoteBitErrors contains the human readable error messages for the
+	// vote bit errors.
+	VoteBitErrors = map[VoteBitErrorT]string{
+		VoteBitErrorInvalid:         "vote bit error invalid",
+		VoteBitErrorNoOptions:       "no vote options found",
+		VoteBitErrorBitZero:         "bit is zero",
+		VoteBitErrorBitNotInMask:    "bit not included in mask",
+		VoteBitErrorBitNotInOptions: "bit not found in vote options",
+		VoteBitErrorBitDuplicate:    "bit used by another option",
+	}
+)
+
+// VoteBitError identifies the vote option, bit and mask of an invalid vote
+// bit. OptionID is not populated for VoteBitErrorNoOptions and
+// VoteBitErrorBitNotInOptions errors.
+type VoteBitError struct {
+	ErrorCode VoteBitErrorT `json:"errorcode"`
+	OptionID  string        `json:"optionid,omitempty"`
+	Bit       uint64        `json:"bit"`
+	Mask      uint64        `json:"mask"`
+}
+
+// VoteParamsVerify verifies the provided vote params without starting a vote.
+// The params are subject to the same validation as the params of a Start
+// command, which allows clients to validate the params before the vote is
+// authorized or started. The record token and version are not verified.
+type VoteParamsVerify struct {
+	Params VoteParams `json:"params"`
+}
+
+// VoteParamsVerifyReply is the reply to the VoteParamsVerify command.
+// ErrorCode and ErrorContext contain the first error that would be returned by
+// the Start command. VoteBitErrors contains all of the vote bit errors of the
+// params, even if the first error is not a vote bit error.
+type VoteParamsVerifyReply struct {
+	Valid         bool           `json:"valid"`
+	ErrorCode     ErrorCodeT     `json:"errorcode,omitempty"`
+	ErrorContext  string         `json:"errorcontext,omitempty"`
+	VoteBitErrors []VoteBitError `json:"votebiterrors,omitempty"`
+}
+
 // StartReply is the reply to the Start command.
 //
 // The Receipt is the server signature of ClientSignature+StartBlockHash.
+//
+// ConcurrentVotes is the number of votes that are running concurrently now
+// that the vote has been started, including the started vote. It is only
+// populated for standard votes. ConcurrentVotesExceeded is a warning that is
+// set when the concurrent votes exceed the SettingConcurrentVotesMax.
 type StartReply struct {
 	Receipt          string   `json:"receipt"`
 	StartBlockHeight uint32   `json:"startblockheight"`
 	StartBlockHash   string   `json:"startblockhash"`
 	EndBlockHeight   uint32   `json:"endblockheight"`
 	EligibleTickets  []string `json:"eligibletickets"`
+
+	ConcurrentVotes         uint32 `json:"concurrentvotes,omitempty"`
+	ConcurrentVotesExceeded bool   `json:"concurrentvotesexceeded,omitempty"`
+}
+
+// ExtendDetails is the structure that is saved to disk when an active vote is
+// extended.
+//
+// Signature is the client signature of the Token+EndBlockHeight+Reason.
+//
+// Receipt is the server signature of the client signature.
+type ExtendDetails struct {
+	// Data generated by client
+	Token          string `json:"token"`          // Record token
+	EndBlockHeight uint32 `json:"endblockheight"` // New end block height
+	Reason         string `json:"reason"`         // Reason for extension
+	PublicKey      string `json:"publickey"`      // Public key used for signature
+	Signature      string `json:"signature"`      // Client signature
+
+	// Metadata generated by server
+	PrevEndBlockHeight uint32 `json:"prevendblockheight"`
+	Timestamp          int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt            string `json:"receipt"`   // Server signature of client signature
+}
+
+// Extend extends the end block height of an active vote. This is intended to
+// be used by admins when stakeholders are prevented from voting, such as
+// during an outage of the services that are used to cast votes. The new end
+// block height must be greater than the current end block height. The total
+// extension of a vote cannot exceed the SettingVoteExtensionMax.
+//
+// Signature is the client signature of the Token+EndBlockHeight+Reason.
+type Extend struct {
+	Token          string `json:"token"`          // Record token
+	EndBlockHeight uint32 `json:"endblockheight"` // New end block height
+	Reason         string `json:"reason"`         // Reason for extension
+	PublicKey      string `json:"publickey"`      // Public key used for signature
+	Signature      string `json:"signature"`      // Client signature
+}
+
+// ExtendReply is the reply to the Extend command.
+type ExtendReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
+// CancelDetails is the structure that is saved to disk when a started vote
+// is cancelled. The start and end block heights are the block heights of the
+// vote that was cancelled.
+//
+// Signature is the client signature of the Token+Reason.
+//
+// Receipt is the server signature of the client signature.
+type CancelDetails struct {
+	// Data generated by client
+	Token     string `json:"token"`     // Record token
+	Reason    string `json:"reason"`    // Reason for cancellation
+	PublicKey string `json:"publickey"` // Public key used for signature
+	Signature string `json:"signature"` // Client signature
+
+	// Metadata generated by server
+	StartBlockHeight uint32 `json:"startblockheight"`
+	EndBlockHeight   uint32 `json:"endblockheight"`
+	Timestamp        int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt          string `json:"receipt"`   // Server signature of client signature
+}
+
+// Cancel cancels a started vote. This is intended to be used by admins when
+// a vote was started by mistake or with the wrong parameters. A vote can only
+// be cancelled if no ballots have been cast. The vote details, the eligible
+// ticket snapshot, and any vote extensions are deleted and the vote status is
+// returned to authorized. Runoff votes cannot be cancelled.
+//
+// Signature is the client signature of the Token+Reason.
+type Cancel struct {
+	Token     string `json:"token"`     // Record token
+	Reason    string `json:"reason"`    // Reason for cancellation
+	PublicKey string `json:"publickey"` // Public key used for signature
+	Signature string `json:"signature"` // Client signature
+}
+
+// CancelReply is the reply to the Cancel command.
+type CancelReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
 }
 
 // VoteErrorT represents errors that can occur while attempting to cast ticket
@@ -521,8 +1068,27 @@ type CastVoteReply struct {
 
 	// The follwing fields will only be present if an error occurred
 	// while attempting to cast the vote.
-	ErrorCode    *VoteErrorT `json:"errorcode,omitempty"`
-	ErrorContext string      `json:"errorcontext,omitempty"`
+	ErrorCode    *VoteErrorT      `json:"errorcode,omitempty"`
+	ErrorContext string           `json:"errorcontext,omitempty"`
+	Diagnostics  *VoteDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// VoteDiagnostics contains structured context that can be used to
+// troubleshoot a cast vote that failed validation. Only the fields that are
+// relevant to the vote error are populated.
+//
+// SnapshotHeight is the block height of the eligible ticket snapshot, i.e.
+// the vote start block height. Only tickets that were live at this height
+// are eligible to vote.
+//
+// CommitmentAddr is the largest commitment address of the ticket. A vote must
+// be signed using the private key of this address.
+type VoteDiagnostics struct {
+	Reason         string `json:"reason"`
+	SnapshotHeight uint32 `json:"snapshotheight,omitempty"`
+	EndBlockHeight uint32 `json:"endblockheight,omitempty"`
+	BestBlock      uint32 `json:"bestblock,omitempty"`
+	CommitmentAddr string `json:"commitmentaddr,omitempty"`
 }
 
 // CastBallot casts a ballot of votes. A ballot can only contain votes for a
@@ -539,10 +1105,44 @@ type CastBallotReply struct {
 // Details returns the vote details for a record.
 type Details struct{}
 
-// DetailsReply is the reply to the Details command.
+// DetailsReply is the reply to the Details command. The vote details end
+// block height includes any vote extensions. Cancellations contains the
+// votes that were started on the record and then cancelled.
 type DetailsReply struct {
-	Auths []AuthDetails `json:"auths"`
-	Vote  *VoteDetails  `json:"vote,omitempty"`
+	Auths         []AuthDetails   `json:"auths"`
+	Vote          *VoteDetails    `json:"vote,omitempty"`
+	Extensions    []ExtendDetails `json:"extensions,omitempty"`
+	Cancellations []CancelDetails `json:"cancellations,omitempty"`
+}
+
+// Snapshot requests the eligible ticket snapshot of a vote.
+type Snapshot struct{}
+
+// SnapshotReply is the reply to the Snapshot command. The snapshot is empty if
+// a vote has not been started for the record.
+//
+// The snapshot is a compact binary encoding of the eligible tickets. It
+// begins with a version byte, which is currently 1, followed by the uvarint
+// encoded ticket count, followed by the ticket hashes sorted in ascending
+// byte order. Ticket hashes are the hex decoded ticket hash strings. Each
+// ticket hash is delta-encoded against the previous hash as a single byte
+// containing the number of leading bytes shared with the previous hash,
+// followed by the remaining bytes of the hash.
+type SnapshotReply struct {
+	Snapshot string `json:"snapshot"` // Base64 encoded
+	Count    uint32 `json:"count"`    // Number of eligible tickets
+}
+
+// Eligibility checks whether the provided tickets are eligible to vote in a
+// record vote.
+type Eligibility struct {
+	Tickets []string `json:"tickets"`
+}
+
+// EligibilityReply is the reply to the Eligibility command. All tickets are
+// returned as not eligible if a vote has not been started for the record.
+type EligibilityReply struct {
+	Eligible map[string]bool `json:"eligible"` // [ticket]isEligible
 }
 
 // Results requests the results of a vote.
@@ -553,6 +1153,275 @@ type ResultsReply struct {
 	Votes []CastVoteDetails `json:"votes"`
 }
 
+// ExportFormatT represents the format of a cast votes export.
+type ExportFormatT string
+
+const (
+	// ExportFormatInvalid is an invalid export format.
+	ExportFormatInvalid ExportFormatT = ""
+
+	// ExportFormatCSV exports the cast votes as CSV. The first line of
+	// each page is the header row.
+	ExportFormatCSV ExportFormatT = "csv"
+
+	// ExportFormatNDJSON exports the cast votes as newline delimited
+	// JSON. Each line contains a JSON encoded ResultsExportVote.
+	ExportFormatNDJSON ExportFormatT = "ndjson"
+)
+
+// ResultsExportVote is a single cast vote in a results export.
+type ResultsExportVote struct {
+	Ticket    string `json:"ticket"`    // Ticket hash
+	VoteBit   string `json:"votebit"`   // Vote bit, hex encoded
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
+	Address   string `json:"address"`   // Largest commitment address
+}
+
+// ResultsExport requests a page of the cast votes of a record vote in the
+// provided format. The cast votes are sorted by ticket hash. Pages start at 1.
+// The page size is set by the SettingResultsExportPageSize plugin setting.
+type ResultsExport struct {
+	Format ExportFormatT `json:"format"`
+	Page   uint32        `json:"page"`
+}
+
+// ResultsExportReply is the reply to the ResultsExport command. Data contains
+// the requested page of cast votes in the requested format. An empty page is
+// returned once the requested page is past the last cast vote.
+type ResultsExportReply struct {
+	Format     ExportFormatT `json:"format"`
+	Page       uint32        `json:"page"`
+	PageSize   uint32        `json:"pagesize"`
+	TotalVotes uint32        `json:"totalvotes"`
+	Data       string        `json:"data"`
+}
+
+const (
+	// GovernanceExportSchemaVersion is the version of the governance export
+	// schema. The version is incremented whenever a change is made to the
+	// GovernanceExportVote schema that is not backwards compatible. Fields
+	// that are added to the schema do not require a version increment.
+	GovernanceExportSchemaVersion uint32 = 1
+
+	// GovernanceExportFormat is the format of the governance export data.
+	GovernanceExportFormat = "ndjson"
+)
+
+// GovernanceExportResult is the result of a single vote option in the
+// governance export.
+type GovernanceExportResult struct {
+	ID          string `json:"id"`              // Vote option ID
+	Description string `json:"description"`     // Vote option description
+	VoteBit     uint64 `json:"votebit"`         // Bits used for the option
+	Votes       uint64 `json:"votes"`           // Number of votes cast
+	Stake       uint64 `json:"stake,omitempty"` // Stake of the votes in atoms
+}
+
+// GovernanceExportVote is a single finished vote in the governance export.
+// This is the version 1 schema of the governance export. The export contains
+// one JSON encoded GovernanceExportVote per line, ordered by when the vote
+// was finalized.
+//
+// Type, Status, WinCondition, and Weight contain the human readable strings
+// of the VoteTypes, VoteStatuses, WinConditions, and VoteWeights. Status is
+// either approved or rejected for standard and runoff votes and finished for
+// multi-option votes. WinCondition and WinningOption are only populated for
+// multi-option votes, Winners is only populated for runoff votes, and
+// EligibleStake and the option Stake are only populated for votes that are
+// weighted by stake.
+type GovernanceExportVote struct {
+	Token            string                   `json:"token"`
+	Type             string                   `json:"type"`
+	Status           string                   `json:"status"`
+	Duration         uint32                   `json:"duration"` // In blocks
+	StartBlockHeight uint32                   `json:"startblockheight"`
+	StartBlockHash   string                   `json:"startblockhash"`
+	EndBlockHeight   uint32                   `json:"endblockheight"`
+	EligibleTickets  uint32                   `json:"eligibletickets"`
+	QuorumPercentage uint32                   `json:"quorumpercentage"`
+	PassPercentage   uint32                   `json:"passpercentage"`
+	TotalVotes       uint64                   `json:"totalvotes"`
+	Results          []GovernanceExportResult `json:"results"`
+	WinCondition     string                   `json:"wincondition,omitempty"`
+	WinningOption    string                   `json:"winningoption,omitempty"`
+	Winners          uint32                   `json:"winners,omitempty"`
+	Weight           string                   `json:"weight"`
+	EligibleStake    uint64                   `json:"eligiblestake,omitempty"`
+}
+
+// GovernanceExportManifest describes the governance export. Count is the
+// number of votes in the export. Size and SHA256 are the size in bytes and
+// the hex encoded SHA256 digest of the full export data. Updated is the UNIX
+// timestamp of when the export was last updated.
+type GovernanceExportManifest struct {
+	SchemaVersion uint32 `json:"schemaversion"`
+	Format        string `json:"format"`
+	Count         uint32 `json:"count"`
+	Size          int64  `json:"size"`
+	SHA256        string `json:"sha256"`
+	Updated       int64  `json:"updated"`
+}
+
+// GovernanceExport requests the governance export of all finished votes. The
+// export is updated incrementally as votes finish. New votes are only ever
+// appended to the export, so clients can download the votes that were added
+// since their last download by setting Offset to the number of votes that
+// they have already downloaded.
+type GovernanceExport struct {
+	Offset uint32 `json:"offset,omitempty"`
+}
+
+// GovernanceExportReply is the reply to the GovernanceExport command. Data
+// contains the NDJSON encoded GovernanceExportVotes starting at the requested
+// offset. The manifest describes the full export.
+type GovernanceExportReply struct {
+	Manifest GovernanceExportManifest `json:"manifest"`
+	Data     string                   `json:"data"`
+}
+
+// Receipts requests the cast vote receipts of the provided tickets for a
+// record vote.
+type Receipts struct {
+	Tickets []string `json:"tickets"`
+}
+
+// VoteReceipt contains a cast vote and the timestamp of the cast vote. The
+// data payload of the timestamp contains the JSON encoded CastVoteDetails.
+// Together they prove that the vote was cast by the ticket, that it was
+// received by the server, and that it was timestamped onto the decred
+// blockchain.
+type VoteReceipt struct {
+	Vote      CastVoteDetails `json:"vote"`
+	Timestamp Timestamp       `json:"timestamp"`
+}
+
+// ReceiptsReply is the reply to the Receipts command. Tickets that have not
+// cast a vote are not included in the reply.
+type ReceiptsReply struct {
+	Receipts []VoteReceipt `json:"receipts"`
+}
+
+// Vote requests the cast vote of a single ticket for a record vote. The
+// returned CastVoteDetails includes the server receipt, which proves that the
+// vote was received by the server. ErrorCodeVoteNotFound is returned if the
+// ticket has not voted.
+type Vote struct {
+	Ticket string `json:"ticket"`
+}
+
+// VoteReply is the reply to the Vote command.
+type VoteReply struct {
+	Vote CastVoteDetails `json:"vote"`
+}
+
+// ActiveVotesRebuild rebuilds the active votes memory cache using the
+// records that have a vote status of VoteStatusStarted. The cache is used to
+// validate cast ballots and can get out of sync with the stored vote data if
+// the server is interrupted during a vote start.
+//
+// Votes that are cast while the cache is being rebuilt are carried over to
+// the rebuilt cache. The command does not require a token.
+type ActiveVotesRebuild struct{}
+
+// ActiveVotesRebuildReply is the reply to the ActiveVotesRebuild command.
+// Tokens contains the records in the rebuilt cache. Added and Removed
+// contain the records that were missing from or stale in the previous cache.
+type ActiveVotesRebuildReply struct {
+	Tokens  []string `json:"tokens"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// SetVoteDuration sets the min and max vote duration that are allowed for new
+// votes. The bounds override the votedurationmin and votedurationmax plugin
+// settings and are persisted by the plugin so that they remain in effect
+// after a restart. This allows the bounds to be changed without redeploying
+// the server. Votes that have already been started are not affected. The
+// command does not require a token.
+type SetVoteDuration struct {
+	Min uint32 `json:"min"` // In blocks
+	Max uint32 `json:"max"` // In blocks
+}
+
+// SetVoteDurationReply is the reply to the SetVoteDuration command. It
+// contains the vote duration bounds that are now in effect.
+type SetVoteDurationReply struct {
+	Min uint32 `json:"min"` // In blocks
+	Max uint32 `json:"max"` // In blocks
+}
+
+// SnapshotVerify requests that the eligible ticket snapshot of a record vote
+// be re-verified. The ticket pool at the snapshot block of the vote is
+// re-fetched from the chain and compared against the stored eligible tickets.
+type SnapshotVerify struct{}
+
+// SnapshotVerification is a verification report of the eligible ticket
+// snapshot of a record vote.
+//
+// SnapshotDigest is the SHA256 digest of the stored snapshot and ChainDigest
+// is the SHA256 digest of the ticket pool that was fetched from the chain.
+// Both digests are of the binary snapshot encoding that is described in the
+// SnapshotReply. The snapshot matches the chain when the digests are equal.
+// Missing contains the tickets that are in the chain ticket pool but not in
+// the snapshot. Extra contains the tickets that are in the snapshot but not
+// in the chain ticket pool.
+//
+// Receipt is the server signature of the
+// Token+StartBlockHash+SnapshotDigest+ChainDigest+Timestamp. This allows
+// auditors to verify that the report was produced by the server.
+type SnapshotVerification struct {
+	Token            string   `json:"token"`
+	StartBlockHeight uint32   `json:"startblockheight"`
+	StartBlockHash   string   `json:"startblockhash"`
+	SnapshotCount    uint32   `json:"snapshotcount"`
+	SnapshotDigest   string   `json:"snapshotdigest"`
+	ChainCount       uint32   `json:"chaincount"`
+	ChainDigest      string   `json:"chaindigest"`
+	Match            bool     `json:"match"`
+	Missing          []string `json:"missing"`
+	Extra            []string `json:"extra"`
+	Timestamp        int64    `json:"timestamp"`
+	Receipt          string   `json:"receipt"`
+}
+
+// SnapshotVerifyReply is the reply to the SnapshotVerify command.
+type SnapshotVerifyReply struct {
+	Verification SnapshotVerification `json:"verification"`
+}
+
+// VoteStats requests the turnout and participation statistics of a finished
+// record vote. The statistics are computed from the cast votes so that
+// clients do not need to retrieve all of the cast votes.
+type VoteStats struct{}
+
+// VoteStatsDay contains the cast vote statistics of a single day. Day is the
+// unix timestamp of the start of the UTC day. Votes is the number of votes
+// that were cast during the day. Approve, Reject, and Approval are cumulative
+// and include all votes that were cast up until the end of the day.
+type VoteStatsDay struct {
+	Day      int64   `json:"day"`
+	Votes    uint32  `json:"votes"`
+	Approve  uint32  `json:"approve"`
+	Reject   uint32  `json:"reject"`
+	Approval float64 `json:"approval"` // Percentage of approve votes
+}
+
+// VoteStatsReply is the reply to the VoteStats command.
+//
+// UniqueTickets is the number of eligible tickets that cast a vote. Turnout is
+// the percentage of eligible tickets that cast a vote. Approval is the
+// percentage of cast votes that voted for the VoteOptionIDApprove option. The
+// approval statistics are only populated for votes that use the
+// VoteOptionIDApprove and VoteOptionIDReject vote options.
+type VoteStatsReply struct {
+	Status          VoteStatusT    `json:"status"`
+	EligibleTickets uint32         `json:"eligibletickets"`
+	UniqueTickets   uint32         `json:"uniquetickets"`
+	Turnout         float64        `json:"turnout"`
+	Approval        float64        `json:"approval"`
+	Days            []VoteStatsDay `json:"days"`
+}
+
 // VoteStatusT represents the status of a ticket vote.
 type VoteStatusT uint32
 
@@ -610,6 +1479,44 @@ var (
 	}
 )
 
+// RejectionReasonT represents the reason that a vote was rejected.
+type RejectionReasonT uint32
+
+const (
+	// RejectionReasonInvalid is an invalid rejection reason. It is used
+	// for votes that have not been rejected.
+	RejectionReasonInvalid RejectionReasonT = 0
+
+	// RejectionReasonQuorumNotMet indicates that the vote was rejected
+	// because not enough of the eligible tickets voted for the vote to
+	// reach a quorum.
+	RejectionReasonQuorumNotMet RejectionReasonT = 1
+
+	// RejectionReasonApprovalNotMet indicates that the vote reached a
+	// quorum, but was rejected because the approve votes did not meet
+	// the pass percentage.
+	RejectionReasonApprovalNotMet RejectionReasonT = 2
+
+	// RejectionReasonRunoffLost indicates that a runoff vote submission
+	// met the quorum and pass requirements, but was rejected because it
+	// did not have enough net approve votes to be one of the winners of
+	// the runoff vote.
+	RejectionReasonRunoffLost RejectionReasonT = 3
+
+	// RejectionReasonLast unit test only.
+	RejectionReasonLast RejectionReasonT = 4
+)
+
+var (
+	// RejectionReasons contains the human readable rejection reasons.
+	RejectionReasons = map[RejectionReasonT]string{
+		RejectionReasonInvalid:        "invalid",
+		RejectionReasonQuorumNotMet:   "quorum not met",
+		RejectionReasonApprovalNotMet: "approval not met",
+		RejectionReasonRunoffLost:     "runoff lost",
+	}
+)
+
 // VoteOptionResult describes a vote option and the total number of votes that
 // have been cast for this option.
 type VoteOptionResult struct {
@@ -617,6 +1524,11 @@ type VoteOptionResult struct {
 	Description string `json:"description"` // Longer description of the vote
 	VoteBit     uint64 `json:"votebit"`     // Bits used for this option
 	Votes       uint64 `json:"votes"`       // Votes cast for this option
+
+	// Stake is the total stake in atoms of the votes that were cast for
+	// this option. It is only populated for votes that use
+	// VoteWeightStake.
+	Stake uint64 `json:"stake,omitempty"`
 }
 
 // Summary requests the vote summary for a record.
@@ -646,10 +1558,72 @@ type SummaryReply struct {
 	PassPercentage   uint32             `json:"passpercentage,omitempty"`
 	Results          []VoteOptionResult `json:"results,omitempty"`
 
+	// The following fields will only be populated for multi-option votes.
+	// The winning option is the ID of the vote option that met the win
+	// condition. It is only populated once the vote has finished and will
+	// be empty if no vote option met the win condition.
+	WinCondition  WinConditionT `json:"wincondition,omitempty"`
+	WinningOption string        `json:"winningoption,omitempty"`
+
+	// Winners is the maximum number of records that can be approved in the
+	// runoff vote. This field will only be populated for runoff votes.
+	Winners uint32 `json:"winners,omitempty"`
+
+	// The following fields will only be populated for votes that use
+	// VoteWeightStake. EligibleStake is the total stake in atoms of the
+	// eligible tickets. The quorum percentage applies to the eligible
+	// stake and the pass percentage applies to the stake of the cast
+	// votes.
+	Weight        VoteWeightT `json:"weight,omitempty"`
+	EligibleStake uint64      `json:"eligiblestake,omitempty"`
+
+	// FinishedEarly is set when the vote was finished prior to the end
+	// block height because all eligible tickets had voted. See the
+	// SettingEarlyFinish plugin setting.
+	FinishedEarly bool `json:"finishedearly,omitempty"`
+
+	// The following fields are computed from the vote results so that
+	// clients do not need to re-implement the pass criteria. They will
+	// only be populated once the voting period has been started.
+	//
+	// QuorumPercentageAchieved is the percentage of the eligible tickets,
+	// or of the eligible stake for VoteWeightStake votes, that has voted.
+	// It is not populated for VoteWeightStake votes until the eligible
+	// stake is known.
+	//
+	// ApprovalPercentage is the percentage of the cast votes that voted
+	// to approve. It is only populated for votes that use the approve
+	// and reject vote options.
+	//
+	// RejectionReason is only populated once the vote has been rejected.
+	QuorumPercentageAchieved float64          `json:"quorumpercentageachieved,omitempty"`
+	ApprovalPercentage       float64          `json:"approvalpercentage,omitempty"`
+	RejectionReason          RejectionReasonT `json:"rejectionreason,omitempty"`
+
 	// BestBlock is the best block value that was used to prepare this summary.
 	BestBlock uint32 `json:"bestblock"`
 }
 
+const (
+	// EventVoteEnded is the plugin event that is emitted when the
+	// ticketvote plugin finalizes a vote whose voting period has ended.
+	// Votes are finalized when the plugin is notified of a new best
+	// block, or lazily when the vote summary is first requested after
+	// the vote has ended. The event is emitted once per record and
+	// once for each record of a runoff vote, including records that
+	// have been frozen before the final summary could be saved. The
+	// event is emitted asynchronously, after the final summary has
+	// been cached. The event payload is a JSON encoded VoteEnded.
+	EventVoteEnded = "ticketvote-voteended"
+)
+
+// VoteEnded is the payload of the EventVoteEnded plugin event. Summary is the
+// final vote summary of the record.
+type VoteEnded struct {
+	Token   string       `json:"token"`
+	Summary SummaryReply `json:"summary"`
+}
+
 // Submissions requests the submissions of a runoff vote. The only records that
 // will have a submissions list are the parent records in a runoff vote. The
 // list will contain all public runoff vote submissions, i.e. records that
@@ -667,13 +1641,26 @@ type SubmissionsReply struct {
 // by vote status.
 //
 // The status and page arguments can be provided to request a specific page of
-// record tokens.
+// record tokens. Page 1 is returned if no page is provided.
+//
+// If no status is provided then the requested page of tokens for all statuses
+// will be returned.
+//
+// PageSize is optional. It defaults to the SettingInventoryPageSize and is
+// capped at the SettingInventoryPageSize.
 //
-// If no status is provided then a page of tokens for all statuses will be
-// returned. The page argument will be ignored.
+// StartedAfter is an optional block height filter. When provided, only the
+// records whose vote was started after the block height are returned. Records
+// that have not started voting are not included. The start block heights of
+// inventory entries that were created by older versions of politeiad are
+// backfilled from the vote details on startup. Entries that could not be
+// backfilled are logged and require the ticketvote plugin to be reindexed
+// before they are returned by this filter.
 type Inventory struct {
-	Status VoteStatusT `json:"status,omitempty"`
-	Page   uint32      `json:"page,omitempty"`
+	Status       VoteStatusT `json:"status,omitempty"`
+	Page         uint32      `json:"page,omitempty"`
+	PageSize     uint32      `json:"pagesize,omitempty"`
+	StartedAfter uint32      `json:"startedafter,omitempty"`
 }
 
 // InventoryReply is the reply to the Inventory command. The returned map is a
@@ -691,6 +1678,14 @@ type Inventory struct {
 type InventoryReply struct {
 	Tokens map[string][]string `json:"tokens"`
 
+	// Timestamps contains the unix timestamp of when each of the returned
+	// records entered its current vote status. The timestamp of a vote
+	// that has ended is the time that the server processed the block
+	// that ended the vote. Records whose timestamp is not known are not
+	// included. This can happen for records that began voting before
+	// the timestamps were tracked.
+	Timestamps map[string]int64 `json:"timestamps,omitempty"` // [token]timestamp
+
 	// BestBlock is the best block value that was used to prepare the
 	// inventory.
 	BestBlock uint32 `json:"bestblock"`
@@ -729,13 +1724,128 @@ type Timestamp struct {
 // If no votes page number is provided then the vote authorization and vote
 // details timestamps will be returned. If a votes page number is provided then
 // the specified page of votes will be returned.
+//
+// VotesPageSize is optional and is capped at the SettingTimestampsPageSize
+// plugin setting, which is also used when a page size is not provided.
+//
+// DigestsOnly omits the data payloads from the returned timestamps. The
+// digests and inclusion proofs are still returned so that clients that
+// already have the data, e.g. the cast votes, can verify the inclusion proofs
+// without downloading the data again.
 type Timestamps struct {
-	VotesPage uint32 `json:"votespage,omitempty"`
+	VotesPage     uint32 `json:"votespage,omitempty"`
+	VotesPageSize uint32 `json:"votespagesize,omitempty"`
+	DigestsOnly   bool   `json:"digestsonly,omitempty"`
 }
 
-// TimestampsReply is the reply to the Timestamps command.
+// TimestampsReply is the reply to the Timestamps command. VotesCount is the
+// total number of cast votes and is only populated when a votes page is
+// requested.
 type TimestampsReply struct {
-	Auths   []Timestamp `json:"auths"`
-	Details *Timestamp  `json:"details,omitempty"`
-	Votes   []Timestamp `json:"votes"`
+	Auths      []Timestamp `json:"auths"`
+	Details    *Timestamp  `json:"details,omitempty"`
+	Votes      []Timestamp `json:"votes"`
+	VotesCount uint32      `json:"votescount,omitempty"`
+}
+
+const (
+	// AuditBundleVersion is the version of the VoteAuditBundle format.
+	AuditBundleVersion uint32 = 1
+)
+
+// AuditBundle requests the audit bundle of a finished record vote. The audit
+// bundle contains all of the data that is required to verify a vote end-to-end
+// offline, without having to query the server.
+type AuditBundle struct{}
+
+// AuditBundleTimestamps contains the timestamps of the vote data that is
+// included in an audit bundle. The timestamps contain the tlog inclusion
+// proofs and the DCR transaction that the tlog merkle root was anchored in.
+//
+// Snapshot is the timestamp of the eligible ticket snapshot. It is only
+// populated for votes that save the eligible tickets separately from the
+// vote details. The snapshot data is binary and is not included in the
+// timestamp. The timestamp digest is the EligibleTicketsDigest of the vote
+// details.
+type AuditBundleTimestamps struct {
+	Auths    []Timestamp `json:"auths"`
+	Details  Timestamp   `json:"details"`
+	Snapshot *Timestamp  `json:"snapshot,omitempty"`
+	Votes    []Timestamp `json:"votes"`
+}
+
+// VoteAuditBundle contains all of the data of a finished record vote.
+//
+// Details includes the eligible tickets of the vote. Votes contains all of the
+// cast votes, sorted by ticket hash. Anchored is true when all of the
+// timestamps have been anchored onto the DCR blockchain and contain a full
+// inclusion proof. A bundle is only cached by the server once it has been
+// fully anchored.
+type VoteAuditBundle struct {
+	Version    uint32                `json:"version"`
+	Token      string                `json:"token"`
+	Summary    SummaryReply          `json:"summary"`
+	Auths      []AuthDetails         `json:"auths"`
+	Details    VoteDetails           `json:"details"`
+	Votes      []CastVoteDetails     `json:"votes"`
+	Timestamps AuditBundleTimestamps `json:"timestamps"`
+	Anchored   bool                  `json:"anchored"`
+	Timestamp  int64                 `json:"timestamp"` // Created at
+}
+
+// AuditBundleReply is the reply to the AuditBundle command.
+//
+// Bundle is the JSON encoded VoteAuditBundle. Digest is the hex encoded
+// SHA256 digest of the Bundle. Signature is the server signature of the
+// Digest. PublicKey is the server public key that can be used to verify the
+// signature. The bundle is returned in its encoded form so that the digest
+// can be verified without having to re-encode the bundle.
+type AuditBundleReply struct {
+	Bundle    string `json:"bundle"` // JSON encoded VoteAuditBundle
+	Digest    string `json:"digest"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// TicketSignature contains a signature that proves ownership of a ticket. The
+// signature is created using the commitment address of the ticket, the same
+// as a cast vote signature.
+type TicketSignature struct {
+	Ticket    string `json:"ticket"`    // Ticket hash
+	Signature string `json:"signature"` // Commitment address signature
+}
+
+// TicketOwnership verifies that the provided tickets were eligible to vote in
+// the record vote and that the message was signed by the commitment address
+// of each ticket. The message is provided by the caller and is used as a
+// challenge so that a signature can not be replayed for a different purpose.
+//
+// The vote must have been started. Ownership is verified against the eligible
+// ticket snapshot of the vote, so tickets can prove ownership during and after
+// the vote.
+type TicketOwnership struct {
+	Message    string            `json:"message"`
+	Signatures []TicketSignature `json:"signatures"`
+}
+
+// TicketOwnershipReply is the reply to the TicketOwnership command.
+//
+// Weights contains the voting weight of each ticket that was verified. The
+// weight is the ticket stake in atoms for stake weighted votes and 1 for all
+// other votes. Errors contains the reason that a ticket could not be verified.
+type TicketOwnershipReply struct {
+	Weights map[string]uint64 `json:"weights"` // [ticket]weight
+	Errors  map[string]string `json:"errors"`  // [ticket]error
+}
+
+// VoteLoad requests the number of votes that are currently running. This
+// allows admins to avoid starting votes during periods of voter fatigue. The
+// command does not require a token.
+type VoteLoad struct{}
+
+// VoteLoadReply is the reply to the VoteLoad command. ActiveVotes is the
+// number of votes that have been started and have not yet finished.
+type VoteLoadReply struct {
+	ActiveVotes uint32 `json:"activevotes"`
+	BestBlock   uint32 `json:"bestblock"`
 }
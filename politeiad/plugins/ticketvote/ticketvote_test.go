@@ -23,4 +23,18 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("VoteStatuses: %v", err)
 	}
+	err = unittest.TestGenericConstMap(VoteBitErrors,
+		uint64(VoteBitErrorLast))
+	if err != nil {
+		t.Fatalf("VoteBitErrors: %v", err)
+	}
+	err = unittest.TestGenericConstMap(VoteTypes, uint64(VoteTypeLast))
+	if err != nil {
+		t.Fatalf("VoteTypes: %v", err)
+	}
+	err = unittest.TestGenericConstMap(RejectionReasons,
+		uint64(RejectionReasonLast))
+	if err != nil {
+		t.Fatalf("RejectionReasons: %v", err)
+	}
 }
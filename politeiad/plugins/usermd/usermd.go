@@ -27,6 +27,11 @@ const (
 	// the status changes metadata. Status changes are appended onto
 	// this metadata stream.
 	StreamIDStatusChanges uint32 = 2
+
+	// StreamIDProvenance is the politeiad metadata stream ID for the
+	// Provenance structure. Only records that were migrated from
+	// another system contain this metadata stream.
+	StreamIDProvenance uint32 = 3
 )
 
 // ErrorCodeT represents a plugin error that was caused by the user.
@@ -70,8 +75,13 @@ const (
 	// is required but is not included.
 	ErrorCodeReasonMissing ErrorCodeT = 8
 
+	// ErrorCodeProvenanceInvalid is returned when the provenance
+	// metadata of a record is being added, changed or removed. The
+	// provenance metadata can only be written by migration tooling.
+	ErrorCodeProvenanceInvalid ErrorCodeT = 9
+
 	// ErrorCodeLast unit test only.
-	ErrorCodeLast ErrorCodeT = 9
+	ErrorCodeLast ErrorCodeT = 10
 )
 
 var (
@@ -86,6 +96,7 @@ var (
 		ErrorCodeTokenInvalid:                 "token invalid",
 		ErrorCodeStatusInvalid:                "status invalid",
 		ErrorCodeReasonMissing:                "status change reason is missing",
+		ErrorCodeProvenanceInvalid:            "provenance invalid",
 	}
 )
 
@@ -115,6 +126,33 @@ type StatusChangeMetadata struct {
 	Timestamp int64  `json:"timestamp"`
 }
 
+const (
+	// ProvenanceSourceGit is the provenance source system of records that
+	// were migrated from the legacy git backend.
+	ProvenanceSourceGit = "politeia-git"
+)
+
+// Provenance describes where a migrated record originated from. It is written
+// by the migration tooling when a record is imported from another system and
+// cannot be changed afterwards. Records that were submitted to this politeia
+// instance do not have provenance metadata.
+//
+// SourceToken, SourceVersion and SourceTimestamp are the token, version and
+// last update timestamp of the record in the source system. PublishedAt is
+// the timestamp of when the record was made public in the source system.
+// ConversionTool and ConversionToolVersion identify the tool that converted
+// the record. ImportedAt is the timestamp of when the record was imported.
+type Provenance struct {
+	SourceSystem          string `json:"sourcesystem"`
+	SourceToken           string `json:"sourcetoken"`
+	SourceVersion         uint32 `json:"sourceversion"`
+	SourceTimestamp       int64  `json:"sourcetimestamp"`
+	PublishedAt           int64  `json:"publishedat,omitempty"`
+	ConversionTool        string `json:"conversiontool"`
+	ConversionToolVersion string `json:"conversiontoolversion"`
+	ImportedAt            int64  `json:"importedat"`
+}
+
 // Author returns the user ID of a record's author.
 type Author struct{}
 
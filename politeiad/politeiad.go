@@ -13,6 +13,7 @@ import (
 	"net/http/httputil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"regexp"
 	"runtime/debug"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/politeia/politeiad/access"
 	v1 "github.com/decred/politeia/politeiad/api/v1"
 	"github.com/decred/politeia/politeiad/api/v1/identity"
 	v2 "github.com/decred/politeia/politeiad/api/v2"
@@ -27,6 +29,8 @@ import (
 	"github.com/decred/politeia/politeiad/backend/gitbe"
 	"github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe"
+	"github.com/decred/politeia/politeiad/ipfs"
+	"github.com/decred/politeia/politeiad/journal"
 	"github.com/decred/politeia/util"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
@@ -46,6 +50,17 @@ type politeia struct {
 	cfg       *config
 	router    *mux.Router
 	identity  *identity.FullIdentity
+
+	// ipfs is only set when an IPFS host has been configured
+	ipfs *ipfs.Pinner
+
+	// access counts record and record file retrievals. It is only set
+	// when using the tstore backend.
+	access *access.Counter
+
+	// journal journals all plugin write commands. It is only set when
+	// using the tstore backend.
+	journal *journal.Journal
 }
 
 func remoteAddr(r *http.Request) string {
@@ -273,9 +288,22 @@ func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
 		return errors.Errorf("router must be initialized")
 	}
 
+	// Setup the token prefix length. This must be done before the
+	// backend is initialized since the backend caches are keyed by the
+	// token prefix.
+	err := verifyTokenPrefixLength(p.cfg.DataDir,
+		p.cfg.TokenPrefixLength, p.cfg.Fsck)
+	if err != nil {
+		return err
+	}
+	err = util.SetShortTokenLength(p.cfg.TokenPrefixLength)
+	if err != nil {
+		return err
+	}
+
 	b, err := tstorebe.New(p.cfg.HomeDir, p.cfg.DataDir,
 		anp, p.cfg.TlogHost, p.cfg.DBHost, p.cfg.DBPass,
-		p.cfg.DcrtimeHost, p.cfg.DcrtimeCert)
+		p.cfg.DcrtimeHost, p.cfg.DcrtimeCert, p.cfg.TokenRetries)
 	if err != nil {
 		return fmt.Errorf("new tstorebe: %v", err)
 	}
@@ -311,10 +339,28 @@ func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
 		p.handlePluginReads, permissionPublic)
 	p.addRouteV2(http.MethodPost, v2.RoutePluginInventory,
 		p.handlePluginInventory, permissionPublic)
+	p.addRouteV2(http.MethodPost, v2.RouteTokenResolve,
+		p.handleTokenResolve, permissionPublic)
 
 	p.addRouteV2(http.MethodPost, v2.RoutePluginInventory,
 		p.handlePluginInventory, permissionPublic)
 
+	// Setup the record access counters. The counters are only
+	// available to privileged clients.
+	p.access = access.New(p.cfg.AccessAlertRate, p.accessAlert)
+	p.addRouteV2(http.MethodPost, v2.RouteAccessCounts,
+		p.handleAccessCounts, permissionAuth)
+
+	// Setup the plugin write journal. The journal can only be
+	// queried by privileged clients.
+	p.journal, err = journal.New(filepath.Join(p.cfg.DataDir,
+		journal.DirName), p.cfg.JournalMaxSize*1024*1024)
+	if err != nil {
+		return fmt.Errorf("journal: %v", err)
+	}
+	p.addRouteV2(http.MethodPost, v2.RoutePluginJournal,
+		p.handlePluginJournal, permissionAuth)
+
 	// Setup plugins
 	if len(p.cfg.Plugins) > 0 {
 		// Parse plugin settings
@@ -376,6 +422,21 @@ func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
 		}
 	}
 
+	// Save the token prefix length now that the caches are consistent
+	// with it.
+	err = saveTokenPrefixLength(p.cfg.DataDir, p.cfg.TokenPrefixLength)
+	if err != nil {
+		return err
+	}
+
+	// Reindex a single plugin
+	if p.cfg.Reindex != "" {
+		err = p.backendv2.Reindex(p.cfg.Reindex)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -492,6 +553,38 @@ func _main() error {
 		return fmt.Errorf("invalid backend selected: %v", cfg.Backend)
 	}
 
+	// The reindex mode exits once the plugin has been reindexed. The
+	// http server is not started.
+	if cfg.Reindex != "" {
+		p.backendv2.Close()
+		log.Infof("Reindex of %v complete; exiting", cfg.Reindex)
+		return nil
+	}
+
+	// The gendata mode exits once the dataset has been generated. The
+	// http server is not started.
+	if cfg.GenData != "" {
+		err := genData(p.backendv2, cfg.genDataSeed)
+		p.backendv2.Close()
+		if err != nil {
+			return fmt.Errorf("gendata: %v", err)
+		}
+		log.Infof("Data generation complete; exiting")
+		return nil
+	}
+
+	// Setup IPFS pinning
+	if cfg.IPFSHost != "" {
+		if cfg.Backend != backendTstore {
+			return fmt.Errorf("ipfs pinning requires the %v backend",
+				backendTstore)
+		}
+		p.ipfs, err = ipfs.New(cfg.IPFSHost, cfg.DataDir)
+		if err != nil {
+			return fmt.Errorf("ipfs: %v", err)
+		}
+	}
+
 	// Bind to a port and pass our router in
 	listenC := make(chan error)
 	for _, listener := range cfg.Listeners {
@@ -532,6 +625,7 @@ done:
 		p.backend.Close()
 	case backendTstore:
 		p.backendv2.Close()
+		p.journal.Close()
 	}
 
 	log.Infof("Exiting")
@@ -0,0 +1,73 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+)
+
+const (
+	// tokenPrefixFilename is the filename of the token prefix length that
+	// the tstore backend caches were built with. The file is saved to the
+	// politeiad data directory.
+	tokenPrefixFilename = "tokenprefixlength"
+)
+
+// loadTokenPrefixLength loads the token prefix length from the provided data
+// directory. The default short token length is returned if the length has
+// not been saved yet since this is the length that all existing data
+// directories were built with.
+func loadTokenPrefixLength(dataDir string) (int, error) {
+	fp := filepath.Join(dataDir, tokenPrefixFilename)
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return v2.ShortTokenLength, nil
+		}
+		return 0, err
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, fmt.Errorf("%v: %v", fp, err)
+	}
+	return length, nil
+}
+
+// saveTokenPrefixLength saves the token prefix length to the provided data
+// directory.
+func saveTokenPrefixLength(dataDir string, length int) error {
+	fp := filepath.Join(dataDir, tokenPrefixFilename)
+	tmp := fp + ".tmp"
+	err := os.WriteFile(tmp, []byte(strconv.Itoa(length)+"\n"), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, fp)
+}
+
+// verifyTokenPrefixLength verifies that the configured token prefix length
+// matches the length that the backend caches were built with. The plugin
+// caches are keyed by the token prefix, so changing the length requires the
+// caches to be rebuilt using the --fsck flag.
+func verifyTokenPrefixLength(dataDir string, length int, fsck bool) error {
+	prev, err := loadTokenPrefixLength(dataDir)
+	if err != nil {
+		return err
+	}
+	if prev != length && !fsck {
+		return fmt.Errorf("the token prefix length has changed from %v to "+
+			"%v; politeiad must be started with --fsck to rebuild the "+
+			"caches that are keyed by the token prefix", prev, length)
+	}
+	return nil
+}
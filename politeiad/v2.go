@@ -10,10 +10,13 @@ import (
 	"fmt"
 	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 
+	"github.com/decred/politeia/politeiad/access"
 	v2 "github.com/decred/politeia/politeiad/api/v2"
 	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/journal"
 	"github.com/decred/politeia/util"
 )
 
@@ -119,6 +122,8 @@ func (p *politeia) handleRecordEdit(w http.ResponseWriter, r *http.Request) {
 	log.Infof("%v Record edited %v",
 		util.RemoteAddr(r), rc.RecordMetadata.Token)
 
+	p.ipfsPin(*rc)
+
 	util.RespondWithJSON(w, http.StatusOK, rer)
 }
 
@@ -230,6 +235,8 @@ func (p *politeia) handleRecordSetStatus(w http.ResponseWriter, r *http.Request)
 	log.Infof("%v Record status set %v %v", util.RemoteAddr(r),
 		rc.RecordMetadata.Token, backendv2.Statuses[rc.RecordMetadata.Status])
 
+	p.ipfsPin(*rc)
+
 	util.RespondWithJSON(w, http.StatusOK, rer)
 }
 
@@ -264,6 +271,15 @@ func (p *politeia) handleRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Resolve any token prefixes. The records are returned keyed by
+	// the token that was provided in the request.
+	requested := make(map[string]string, len(rgb.Requests)) // [resolved]token
+	for k, v := range rgb.Requests {
+		t := p.tokenResolve(v.Token)
+		requested[t] = v.Token
+		rgb.Requests[k].Token = t
+	}
+
 	// Get record batch
 	reqs := convertRecordRequestsToBackend(rgb.Requests)
 	brecords, err := p.backendv2.Records(reqs)
@@ -276,8 +292,21 @@ func (p *politeia) handleRecords(w http.ResponseWriter, r *http.Request) {
 	// Prepare reply
 	records := make(map[string]v2.Record, len(brecords))
 	for k, v := range brecords {
+		if t, ok := requested[k]; ok {
+			k = t
+		}
 		records[k] = p.convertRecordToV2(v)
 	}
+
+	// Update the access counters
+	for _, v := range records {
+		files := make([]string, 0, len(v.Files))
+		for _, f := range v.Files {
+			files = append(files, f.Name)
+		}
+		p.access.Add(v.CensorshipRecord.Token, files)
+	}
+
 	response := p.identity.SignMessage(challenge)
 	reply := v2.RecordsReply{
 		Response: hex.EncodeToString(response[:]),
@@ -308,7 +337,7 @@ func (p *politeia) handleRecordTimestamps(w http.ResponseWriter, r *http.Request
 			})
 		return
 	}
-	token, err := decodeTokenAnyLength(rgt.Token)
+	token, err := decodeTokenAnyLength(p.tokenResolve(rgt.Token))
 	if err != nil {
 		respondWithErrorV2(w, r, "handleRecordTimestamps: decode token",
 			v2.UserErrorReply{
@@ -502,9 +531,34 @@ func (p *politeia) handlePluginWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Journal the plugin cmd before it is executed. The payload is
+	// not journaled for unvetted records.
+	jc := journal.Cmd{
+		Caller:   util.RemoteAddr(r),
+		Token:    pw.Cmd.Token,
+		PluginID: pw.Cmd.ID,
+		Command:  pw.Cmd.Command,
+		Payload:  pw.Cmd.Payload,
+		Redacted: p.journalRedact(token),
+	}
+	if jc.Redacted {
+		jc.Payload = ""
+	}
+	journalID, err := p.journal.Begin(jc)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handlePluginWrite: journal Begin: %v", err)
+		return
+	}
+
 	// Execute plugin cmd
 	payload, err := p.backendv2.PluginWrite(token, pw.Cmd.ID,
 		pw.Cmd.Command, pw.Cmd.Payload)
+	if jc.Redacted {
+		p.journalFinish(journalID, "", err)
+	} else {
+		p.journalFinish(journalID, payload, err)
+	}
 	if err != nil {
 		respondWithErrorV2(w, r,
 			"handlePluginWrite: PluginWrite: %v", err)
@@ -546,20 +600,29 @@ func (p *politeia) handlePluginReads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Resolve any token prefixes
+	cmds := make([]v2.PluginCmd, 0, len(pr.Cmds))
+	for _, v := range pr.Cmds {
+		v.Token = p.tokenResolve(v.Token)
+		cmds = append(cmds, v)
+	}
+
 	// Execute the batch of read cmds
-	batch := newBatch(pr.Cmds)
+	batch := newBatch(cmds)
 	batch.execConcurrently(p.backendv2.PluginRead)
 
-	// Prepare the replies
+	// Prepare the replies. The replies contain the token that was
+	// provided in the request.
 	replies := make([]v2.PluginCmdReply, len(pr.Cmds))
 	for k, v := range batch.entries {
 		if v.err == nil {
 			// Command executed successfully
 			replies[k] = v2.PluginCmdReply{
-				Token:   v.cmd.Token,
+				Token:   pr.Cmds[k].Token,
 				ID:      v.cmd.ID,
 				Command: v.cmd.Command,
 				Payload: v.reply,
+				Page:    v.page,
 			}
 			continue
 		}
@@ -580,6 +643,7 @@ func (p *politeia) handlePluginReads(w http.ResponseWriter, r *http.Request) {
 					PluginID:     pluginErr.PluginID,
 					ErrorCode:    pluginErr.ErrorCode,
 					ErrorContext: pluginErr.ErrorContext,
+					ErrorParams:  pluginErr.ErrorParams,
 				},
 			}
 
@@ -662,18 +726,242 @@ func (p *politeia) handlePluginInventory(w http.ResponseWriter, r *http.Request)
 
 }
 
+func (p *politeia) handleAccessCounts(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleAccessCounts")
+
+	// Decode request
+	var ac v2.AccessCounts
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ac); err != nil {
+		respondWithErrorV2(w, r, "handleAccessCounts: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(ac.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleAccessCounts: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Get the access counters
+	var counts []access.RecordCount
+	if ac.Token != "" {
+		counts = p.access.Record(ac.Token)
+	} else {
+		counts = p.access.Top(int(v2.AccessCountsPageSize))
+	}
+	if len(counts) > int(v2.AccessCountsPageSize) {
+		counts = counts[:v2.AccessCountsPageSize]
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	acr := v2.AccessCountsReply{
+		Response: hex.EncodeToString(response[:]),
+		Since:    p.access.Since().Unix(),
+		Records:  convertRecordCountsToV2(counts),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, acr)
+}
+
+func (p *politeia) handlePluginJournal(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePluginJournal")
+
+	// Decode request
+	var pj v2.PluginJournal
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pj); err != nil {
+		respondWithErrorV2(w, r, "handlePluginJournal: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(pj.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handlePluginJournal: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Query the journal
+	entries, err := p.journal.Query(journal.Filter{
+		After:    pj.After,
+		Token:    pj.Token,
+		PluginID: pj.PluginID,
+		Command:  pj.Command,
+		Status:   journal.StatusT(pj.Status),
+		Limit:    v2.PluginJournalPageSize,
+	})
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handlePluginJournal: Query: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	pjr := v2.PluginJournalReply{
+		Response: hex.EncodeToString(response[:]),
+		Entries:  convertJournalEntriesToV2(entries),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, pjr)
+}
+
+func (p *politeia) handleTokenResolve(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleTokenResolve")
+
+	// Decode request
+	var tr v2.TokenResolve
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&tr); err != nil {
+		respondWithErrorV2(w, r, "handleTokenResolve: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(tr.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleTokenResolve: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Verify the prefix. The prefix can be any length up to the full
+	// token length, but must be hex encoded.
+	prefix := strings.ToLower(tr.Prefix)
+	_, err = hex.DecodeString(prefix + strings.Repeat("0", len(prefix)%2))
+	switch {
+	case err != nil,
+		len(prefix) < v2.TokenResolvePrefixMin,
+		len(prefix) > v2.TokenSize*2:
+		respondWithErrorV2(w, r, "handleTokenResolve: decode prefix",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeTokenInvalid,
+				ErrorContext: fmt.Sprintf("prefix must be %v to %v hex "+
+					"characters", v2.TokenResolvePrefixMin, v2.TokenSize*2),
+			})
+		return
+	}
+
+	// Resolve the prefix
+	tokens := p.backendv2.TokensByPrefix(prefix)
+	if len(tokens) > int(v2.TokenResolvePageSize) {
+		tokens = tokens[:v2.TokenResolvePageSize]
+	}
+	response := p.identity.SignMessage(challenge)
+	trr := v2.TokenResolveReply{
+		Response:     hex.EncodeToString(response[:]),
+		PrefixLength: util.ShortTokenLength(),
+		Tokens:       make([]string, 0, len(tokens)),
+	}
+	for _, v := range tokens {
+		trr.Tokens = append(trr.Tokens, hex.EncodeToString(v))
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, trr)
+}
+
+// journalRedact returns whether the payload and the reply of a plugin write
+// command on the provided record must be omitted from the journal. The
+// journal is stored in plaintext, so the contents of commands on unvetted
+// records, or on records whose state cannot be determined, are not journaled.
+func (p *politeia) journalRedact(token []byte) bool {
+	rs, err := p.backendv2.Records([]backendv2.RecordRequest{
+		{
+			Token:        token,
+			OmitAllFiles: true,
+		},
+	})
+	if err != nil {
+		log.Errorf("journalRedact %x: %v", token, err)
+		return true
+	}
+	r, ok := rs[util.TokenEncode(token)]
+	if !ok {
+		return true
+	}
+	return r.RecordMetadata.State != backendv2.StateVetted
+}
+
+// journalFinish journals the result of a plugin write command. The command
+// has already been executed at this point so an error is logged instead of
+// being returned to the caller.
+func (p *politeia) journalFinish(id uint64, payload string, cmdErr error) {
+	// Include the error context of plugin errors so that the
+	// failure can be diagnosed from the journal.
+	var pe backendv2.PluginError
+	if errors.As(cmdErr, &pe) && pe.ErrorContext != "" {
+		cmdErr = fmt.Errorf("%v: %v", pe, pe.ErrorContext)
+	}
+	err := p.journal.Finish(id, payload, cmdErr)
+	if err != nil {
+		log.Errorf("journal Finish %v: %v", id, err)
+	}
+}
+
+// accessAlert logs a warning when the retrieval rate of a record file spikes.
+func (p *politeia) accessAlert(a access.Alert) {
+	log.Warnf("Record file retrieval spike: %v %v %v retrievals/min",
+		a.Token, a.File, a.Rate)
+}
+
 // decodeToken decodes a v2 token and errors if the token is not the full
 // length token.
 func decodeToken(token string) ([]byte, error) {
 	return util.TokenDecode(util.TokenTypeTstore, token)
 }
 
+// tokenResolve resolves a token prefix to the full length token of the record
+// that it belongs to. Prefixes of any length between the minimum short token
+// length and the full token length are resolved. This allows clients, such as
+// politeiawww, that use a different token prefix length than the length that
+// politeiad has been configured with to continue to use token prefixes. The
+// token is returned unchanged if it is already full length or if it does not
+// belong to exactly one record.
+func (p *politeia) tokenResolve(token string) string {
+	if len(token) < util.ShortTokenLengthMin || len(token) >= v2.TokenSize*2 {
+		return token
+	}
+	_, err := hex.DecodeString(token + strings.Repeat("0", len(token)%2))
+	if err != nil {
+		return token
+	}
+	tokens := p.backendv2.TokensByPrefix(token)
+	if len(tokens) != 1 {
+		return token
+	}
+	return hex.EncodeToString(tokens[0])
+}
+
 // decodeTokenAnyLength decodes a v2 token. It accepts both the full length
-// token and the short token.
+// token and the short token. Token prefixes of other lengths must be resolved
+// using tokenResolve prior to being decoded.
 func decodeTokenAnyLength(token string) ([]byte, error) {
 	return util.TokenDecodeAnyLength(util.TokenTypeTstore, token)
 }
 
+// ipfsPin pins the files of the provided record to IPFS when IPFS pinning
+// has been enabled. Only the files of public records are pinned.
+func (p *politeia) ipfsPin(r backendv2.Record) {
+	if p.ipfs == nil || r.RecordMetadata.Status != backendv2.StatusPublic {
+		return
+	}
+	p.ipfs.Pin(r.RecordMetadata.Token, r.Files)
+}
+
 func (p *politeia) convertRecordToV2(r backendv2.Record) v2.Record {
 	var (
 		metadata = convertMetadataStreamsToV2(r.Metadata)
@@ -681,6 +969,11 @@ func (p *politeia) convertRecordToV2(r backendv2.Record) v2.Record {
 		rm       = r.RecordMetadata
 		sig      = p.identity.SignMessage([]byte(rm.Merkle + rm.Token))
 	)
+	if p.ipfs != nil && rm.Status == backendv2.StatusPublic {
+		for i, v := range files {
+			files[i].CID = p.ipfs.CID(v.Digest)
+		}
+	}
 	return v2.Record{
 		State:     v2.RecordStateT(rm.State),
 		Status:    v2.RecordStatusT(rm.Status),
@@ -927,6 +1220,7 @@ func respondWithErrorV2(w http.ResponseWriter, r *http.Request, format string, e
 				PluginID:     pe.PluginID,
 				ErrorCode:    pe.ErrorCode,
 				ErrorContext: pe.ErrorContext,
+				ErrorParams:  pe.ErrorParams,
 			})
 		return
 	}
@@ -987,3 +1281,46 @@ func convertContentErrorToV2(e backendv2.ContentErrorCodeT) v2.ErrorCodeT {
 	}
 	return v2.ErrorCodeInvalid
 }
+
+func convertJournalEntriesToV2(entries []journal.Entry) []v2.JournalEntry {
+	je := make([]v2.JournalEntry, 0, len(entries))
+	for _, v := range entries {
+		je = append(je, v2.JournalEntry{
+			ID:        v.ID,
+			Timestamp: v.Timestamp,
+			Caller:    v.Cmd.Caller,
+			Cmd: v2.PluginCmd{
+				Token:   v.Cmd.Token,
+				ID:      v.Cmd.PluginID,
+				Command: v.Cmd.Command,
+				Payload: v.Cmd.Payload,
+			},
+			Redacted:  v.Cmd.Redacted,
+			Status:    v2.JournalStatusT(v.Status),
+			Completed: v.Completed,
+			Reply:     v.Reply,
+			Error:     v.Error,
+		})
+	}
+	return je
+}
+
+func convertRecordCountsToV2(counts []access.RecordCount) []v2.RecordAccessCount {
+	rc := make([]v2.RecordAccessCount, 0, len(counts))
+	for _, v := range counts {
+		files := make([]v2.FileAccessCount, 0, len(v.Files))
+		for _, f := range v.Files {
+			files = append(files, v2.FileAccessCount{
+				Name:  f.Name,
+				Count: f.Count,
+				Rate:  f.Rate,
+			})
+		}
+		rc = append(rc, v2.RecordAccessCount{
+			Token: v.Token,
+			Count: v.Count,
+			Files: files,
+		})
+	}
+	return rc
+}
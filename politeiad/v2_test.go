@@ -0,0 +1,86 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	backendv2 "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+// testBackend is a backendv2 Backend that only implements TokensByPrefix.
+type testBackend struct {
+	backendv2.Backend
+	tokens []string
+}
+
+// TokensByPrefix satisfies the backendv2 Backend interface.
+func (b *testBackend) TokensByPrefix(prefix string) [][]byte {
+	tokens := make([][]byte, 0, len(b.tokens))
+	for _, v := range b.tokens {
+		if strings.HasPrefix(v, prefix) {
+			t, _ := hex.DecodeString(v)
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+func TestTokenResolve(t *testing.T) {
+	// Configure politeiad to use a longer token prefix length than
+	// the default length that politeiawww uses.
+	err := util.SetShortTokenLength(v2.ShortTokenLength + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err := util.SetShortTokenLength(v2.ShortTokenLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	var (
+		token1 = "abcdef1200000001"
+		token2 = "abcdef1300000002"
+		token3 = "1234567800000003"
+		p      = &politeia{
+			backendv2: &testBackend{
+				tokens: []string{token1, token2, token3},
+			},
+		}
+	)
+	var tests = []struct {
+		name  string
+		token string
+		want  string // Empty if the token can not be decoded
+	}{
+		{"default length prefix", token3[:v2.ShortTokenLength], token3},
+		{"configured length prefix", token1[:v2.ShortTokenLength+1], token1},
+		{"longer prefix", token3[:10], token3},
+		{"full token", token2, token2},
+		{"ambiguous prefix", token1[:v2.ShortTokenLength], ""},
+		{"prefix not found", "fffffff", ""},
+		{"prefix too short", token3[:v2.ShortTokenLength-1], ""},
+		{"invalid hex", "zzzzzzz", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := decodeTokenAnyLength(p.tokenResolve(tc.token))
+			switch {
+			case tc.want == "" && err == nil:
+				t.Fatalf("got token %x, want error", b)
+			case tc.want != "" && err != nil:
+				t.Fatalf("got error %v, want token %v", err, tc.want)
+			case tc.want != "" && hex.EncodeToString(b) != tc.want:
+				t.Fatalf("got token %x, want %v", b, tc.want)
+			}
+		})
+	}
+}
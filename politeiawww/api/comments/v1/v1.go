@@ -25,6 +25,9 @@ const (
 	// RouteDel deletes a comment.
 	RouteDel = "/del"
 
+	// RouteDelUser deletes all of the comments that were made by a user.
+	RouteDelUser = "/deluser"
+
 	// RouteCount returns the number of comment on a record.
 	RouteCount = "/count"
 
@@ -36,6 +39,39 @@ const (
 
 	// RouteTimestamps returns the timestamps for the comments of a record.
 	RouteTimestamps = "/timestamps"
+
+	// RouteTimestampsBatch returns the timestamps for the comments of a
+	// record that fall within a set of comment ID ranges.
+	RouteTimestampsBatch = "/timestampsbatch"
+
+	// RouteSubscribe subscribes the user to the replies under a comment.
+	RouteSubscribe = "/subscribe"
+
+	// RouteSubmissionStatus returns the status of a new comment that
+	// was queued because politeiad was unavailable.
+	RouteSubmissionStatus = "/submissionstatus"
+
+	// RouteStakeProof submits proof of the tickets that a user holds so
+	// that their comment votes on a record are weighted by stake.
+	RouteStakeProof = "/stakeproof"
+
+	// RouteUserComments returns the comments that were made by a user.
+	RouteUserComments = "/usercomments"
+
+	// RouteTree returns a subtree of the comments on a record.
+	RouteTree = "/tree"
+
+	// RouteAttachments returns the attachments of a comment.
+	RouteAttachments = "/attachments"
+
+	// RouteSearch searches the comments of public records.
+	RouteSearch = "/search"
+
+	// RoutePin pins a comment on a record.
+	RoutePin = "/pin"
+
+	// RouteHistory returns the comment history of a user.
+	RouteHistory = "/history"
 )
 
 // ErrorCodeT represents a user error code.
@@ -84,10 +120,18 @@ const (
 	// cause collisions.
 	ErrorCodeDuplicatePayload ErrorCodeT = 10
 
+	// ErrorCodeSubmissionNotFound is returned when a queued submission
+	// is not found.
+	ErrorCodeSubmissionNotFound ErrorCodeT = 11
+
+	// ErrorCodeSortInvalid is returned when a comments sort order is
+	// invalid or is not supported by the server.
+	ErrorCodeSortInvalid ErrorCodeT = 12
+
 	// ErrorCodeLast is used by unit tests to verify that all error codes have
 	// a human readable entry in the ErrorCodes map. This error will never be
 	// returned.
-	ErrorCodeLast ErrorCodeT = 11
+	ErrorCodeLast ErrorCodeT = 13
 )
 
 var (
@@ -104,6 +148,8 @@ var (
 		ErrorCodeRecordLocked:       "record is locked",
 		ErrorCodePageSizeExceeded:   "page size exceeded",
 		ErrorCodeDuplicatePayload:   "duplicate payload",
+		ErrorCodeSubmissionNotFound: "submission not found",
+		ErrorCodeSortInvalid:        "sort invalid",
 	}
 )
 
@@ -150,15 +196,79 @@ func (e ServerErrorReply) Error() string {
 type Policy struct{}
 
 // PolicyReply is the reply to the policy command.
+//
+// StakeWeighting indicates whether users can submit stake proofs in order to
+// have their comment votes weighted by the tickets that they hold.
+//
+// Reactions contains the reactions that users can react to a comment with.
+// It is empty when reactions have not been enabled.
+//
+// AllowAuthorDeletes indicates whether comment authors are allowed to delete
+// their own comments.
+//
+// VoteChangeCooldown is the minimum amount of time, in seconds, that a user
+// must wait between casting votes on the same comment. A value of 0 means
+// that there is no cooldown.
+//
+// TreeDepthMax and TreeChildrenMax are the maximum depth and the maximum
+// number of replies per comment that can be returned by the Tree command.
+//
+// SearchPageSize is the maximum number of search results that are returned
+// per page by the Search command.
+//
+// HistoryPageSize is the maximum number of comments that are returned per
+// page by the History command.
+//
+// DisableDownvotes indicates whether downvotes have been disabled. Downvotes
+// are rejected and the downvote tallies of the comments are always 0 when
+// downvotes have been disabled.
+//
+// EditPeriod is the amount of time, in seconds, since the submission of a
+// comment where it's still editable. EditsMax is the maximum number of times
+// that a comment can be edited during that time. A value of 0 means that the
+// number of edits is not limited.
+//
+// AttachmentCountMax is the maximum number of image attachments that can be
+// included in a comment. A value of 0 means that attachments are disabled.
+// AttachmentSizeMax is the maximum size, in bytes, of an attachment and
+// AttachmentMIMETypes contains the allowed attachment MIME types.
 type PolicyReply struct {
-	LengthMax          uint32 `json:"lengthmax"` // In characters
-	VoteChangesMax     uint32 `json:"votechangesmax"`
-	AllowExtraData     bool   `json:"allowextradata"`
-	CountPageSize      uint32 `json:"countpagesize"`
-	TimestampsPageSize uint32 `json:"timestampspagesize"`
-	VotesPageSize      uint32 `json:"votespagesize"`
-	AllowEdits         bool   `json:"allowedits"`
-	EditPeriod         uint32 `json:"editperiod"`
+	LengthMax          uint32     `json:"lengthmax"` // In characters
+	VoteChangesMax     uint32     `json:"votechangesmax"`
+	AllowExtraData     bool       `json:"allowextradata"`
+	CountPageSize      uint32     `json:"countpagesize"`
+	TimestampsPageSize uint32     `json:"timestampspagesize"`
+	VotesPageSize      uint32     `json:"votespagesize"`
+	AllowEdits         bool       `json:"allowedits"`
+	EditPeriod         uint32     `json:"editperiod"` // In seconds
+	EditsMax           uint32     `json:"editsmax"`
+	StakeWeighting     bool       `json:"stakeweighting"`
+	CommentsPageSize   uint32     `json:"commentspagesize"`
+	Reactions          []Reaction `json:"reactions"`
+	AllowAuthorDeletes bool       `json:"allowauthordeletes"`
+	VoteChangeCooldown uint32     `json:"votechangecooldown"` // In seconds
+	TreeDepthMax       uint32     `json:"treedepthmax"`
+	TreeChildrenMax    uint32     `json:"treechildrenmax"`
+	SearchPageSize     uint32     `json:"searchpagesize"`
+	HistoryPageSize    uint32     `json:"historypagesize"`
+	DisableDownvotes   bool       `json:"disabledownvotes"`
+	MarkdownValidation bool       `json:"markdownvalidation"`
+	MarkdownDataURIMax uint32     `json:"markdowndataurimax"` // In bytes
+
+	AttachmentCountMax  uint32   `json:"attachmentcountmax"`
+	AttachmentSizeMax   uint32   `json:"attachmentsizemax"` // In bytes
+	AttachmentMIMETypes []string `json:"attachmentmimetypes"`
+}
+
+// Reaction describes a reaction that a user can react to a comment with.
+//
+// Vote is optional and maps the reaction to an upvote or a downvote. Reacting
+// with a mapped reaction is the same as casting the vote and its tally is the
+// Upvotes or Downvotes tally of the comment. Reactions that are not mapped to
+// a vote do not affect the comment score and must be cast as an upvote.
+type Reaction struct {
+	ID   string `json:"id"`             // Unique reaction ID, e.g. an emoji
+	Vote VoteT  `json:"vote,omitempty"` // Mapped upvote or downvote
 }
 
 // RecordStateT represents the state of a record.
@@ -175,6 +285,29 @@ const (
 	RecordStateVetted RecordStateT = 2
 )
 
+// Attachment is an image that is attached to a comment.
+//
+// Digest is the hex encoded SHA256 digest of the decoded payload. The
+// attachment digests are part of the comment signature. The payload is only
+// populated when the attachment is submitted and when it is retrieved using
+// the Attachments command.
+type Attachment struct {
+	Name    string `json:"name"`              // Filename
+	MIME    string `json:"mime"`              // MIME type
+	Digest  string `json:"digest"`            // SHA256 digest of payload
+	Payload string `json:"payload,omitempty"` // Base64 encoded image
+}
+
+const (
+	// ContentTypeMarkdown indicates that the comment text is markdown.
+	// This is the default content type when one is not provided.
+	ContentTypeMarkdown = "markdown"
+
+	// ContentTypePlain indicates that the comment text is plain text
+	// and must not be rendered as markdown.
+	ContentTypePlain = "plain"
+)
+
 // Comment represent a record comment.
 //
 // A parent ID of 0 indicates that the comment is a base level comment and not
@@ -194,12 +327,30 @@ const (
 // PublicKey is the user's public key that is used to verify the signature.
 //
 // Signature is the user signature of the:
-// State + Token + ParentID + Comment + ExtraData + ExtraDataHint
+// State + Token + ParentID + Comment + ExtraData + ExtraDataHint +
+// Attachment digests
 //
 // Receipt is the server signature of the user signature.
 //
 // The PublicKey, Signature, and Receipt are all hex encoded and use the
 // ed25519 signature scheme.
+//
+// Attachments contains the metadata of the comment attachments. The payloads
+// can be retrieved using the Attachments command.
+//
+// ContentType is a rendering hint that tells clients how the comment text
+// should be rendered. It is not part of the signature.
+//
+// HTML contains the server side rendered and sanitized HTML of the comment
+// text. It is only populated when it is requested using the Comments
+// RenderHTML field.
+//
+// Reactions contains the tally of each reaction. It is only populated when
+// reactions have been enabled.
+//
+// StakeDownvotes and StakeUpvotes are only populated when stake weighting has
+// been enabled. They contain the net vote of each user that has submitted a
+// stake proof for the record, weighted by the stake of the user.
 type Comment struct {
 	UserID    string       `json:"userid"`    // Unique user ID
 	Username  string       `json:"username"`  // Username
@@ -217,34 +368,55 @@ type Comment struct {
 	Downvotes uint64       `json:"downvotes"` // Tolal downvotes on comment
 	Upvotes   uint64       `json:"upvotes"`   // Total upvotes on comment
 
+	Reactions map[string]uint64 `json:"reactions,omitempty"` // [reaction]tally
+
+	StakeDownvotes uint64 `json:"stakedownvotes,omitempty"`
+	StakeUpvotes   uint64 `json:"stakeupvotes,omitempty"`
+
 	Deleted bool   `json:"deleted,omitempty"` // Comment has been deleted
 	Reason  string `json:"reason,omitempty"`  // Reason for deletion
 
+	// DeletedByAuthor indicates that the comment was deleted by its
+	// author rather than being censored by an admin.
+	DeletedByAuthor bool `json:"deletedbyauthor,omitempty"`
+
+	// BulkDeleted indicates that the comment was deleted by a DelUser
+	// command, which deletes all of the comments of a user. The
+	// deletion signature of a bulk delete is the signature of the
+	// UserID + Reason.
+	BulkDeleted bool `json:"bulkdeleted,omitempty"`
+
+	ContentType string `json:"contenttype,omitempty"` // Content type
+	HTML        string `json:"html,omitempty"`        // Rendered HTML
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
 }
 
-// CommentVote represents a comment vote (upvote/downvote).
+// CommentVote represents a comment vote (upvote/downvote) or reaction.
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
 // Signature is the user signature of the:
-// State + Token + CommentID + Vote
+// State + Token + CommentID + Vote + Reaction
 //
 // The PublicKey and Signature are hex encoded and use the
 // ed25519 signature scheme.
 type CommentVote struct {
-	UserID    string       `json:"userid"`    // Unique user ID
-	Username  string       `json:"username"`  // Username
-	State     RecordStateT `json:"state"`     // Record state
-	Token     string       `json:"token"`     // Record token
-	CommentID uint32       `json:"commentid"` // Comment ID
-	Vote      VoteT        `json:"vote"`      // Upvote or downvote
-	PublicKey string       `json:"publickey"` // Public key used for signature
-	Signature string       `json:"signature"` // Client signature
-	Timestamp int64        `json:"timestamp"` // Received UNIX timestamp
-	Receipt   string       `json:"receipt"`   // Server sig of client sig
+	UserID    string       `json:"userid"`             // Unique user ID
+	Username  string       `json:"username"`           // Username
+	State     RecordStateT `json:"state"`              // Record state
+	Token     string       `json:"token"`              // Record token
+	CommentID uint32       `json:"commentid"`          // Comment ID
+	Vote      VoteT        `json:"vote"`               // Upvote or downvote
+	PublicKey string       `json:"publickey"`          // Public key used for signature
+	Signature string       `json:"signature"`          // Client signature
+	Timestamp int64        `json:"timestamp"`          // Received UNIX timestamp
+	Receipt   string       `json:"receipt"`            // Server sig of client sig
+	Reaction  string       `json:"reaction,omitempty"` // Reaction ID
 }
 
 // New creates a new comment.
@@ -254,8 +426,12 @@ type CommentVote struct {
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
+// Attachments are optional and are only allowed when the AttachmentCountMax
+// policy is greater than zero. The attachment payloads must be included.
+//
 // Signature is the user signature of the:
-// State + Token + ParentID + Comment + ExtraData + ExtraDataHint
+// State + Token + ParentID + Comment + ExtraData + ExtraDataHint +
+// Attachment digests
 //
 // The PublicKey and Signature are hex encoded and use the
 // ed25519 signature scheme.
@@ -267,22 +443,39 @@ type New struct {
 	PublicKey string       `json:"publickey"`
 	Signature string       `json:"signature"`
 
+	// ContentType is optional and defaults to ContentTypeMarkdown.
+	ContentType string `json:"contenttype,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
 }
 
 // NewReply is the reply to the New command.
+//
+// If politeiad is unavailable and the politeiawww write queue is enabled, the
+// comment is queued and will be submitted once politeiad becomes available.
+// The comment will be empty and the SubmissionID will be populated in this
+// case. The SubmissionID can be used to retrieve the status of the queued
+// comment using the SubmissionStatus command.
 type NewReply struct {
-	Comment Comment `json:"comment"`
+	Comment      Comment `json:"comment"`
+	SubmissionID string  `json:"submissionid,omitempty"`
 }
 
 // Edit edits an existing comment.
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
+// Attachments must contain the full set of attachments for the new version
+// of the comment, including any unchanged attachments. The attachment
+// payloads must be included.
+//
 // Signature is the user signature of the:
-// State + Token + ParentID + CommentID + Comment + ExtraData + ExtraDataHint
+// State + Token + ParentID + CommentID + Comment + ExtraData + ExtraDataHint +
+// Attachment digests
 //
 // Receipt is the server signature of the user signature.
 //
@@ -298,6 +491,11 @@ type Edit struct {
 	PublicKey string       `json:"publickey"` // Pubkey used for Signature
 	Signature string       `json:"signature"` // Client signature
 
+	// ContentType is optional and defaults to ContentTypeMarkdown.
+	ContentType string `json:"contenttype,omitempty"`
+
+	Attachments []Attachment `json:"attachments,omitempty"`
+
 	// Optional fields to be used freely
 	ExtraData     string `json:"extradata,omitempty"`
 	ExtraDataHint string `json:"extradatahint,omitempty"`
@@ -330,10 +528,15 @@ const (
 // upvoted, the resulting vote score is 0 due to the second upvote removing the
 // original upvote.
 //
+// Reaction is optional and reacts to the comment with one of the reactions
+// listed in the policy. Reactions follow the same toggle semantics as votes.
+//
 // PublicKey is the user's public key that is used to verify the signature.
 //
 // Signature is the user signature of the:
-// State + Token + CommentID + Vote
+// State + Token + CommentID + Vote + Reaction
+//
+// The Reaction is only part of the signature message when it is provided.
 //
 // The PublicKey and Signature are hex encoded and use the
 // ed25519 signature scheme.
@@ -344,18 +547,27 @@ type Vote struct {
 	Vote      VoteT        `json:"vote"`
 	PublicKey string       `json:"publickey"`
 	Signature string       `json:"signature"`
+	Reaction  string       `json:"reaction,omitempty"`
 }
 
-// VoteReply is the reply to the Vote command.
+// VoteReply is the reply to the Vote command. Reactions is only populated
+// when reactions have been enabled.
 type VoteReply struct {
-	Downvotes uint64 `json:"downvotes"` // Tolal downvotes on comment
-	Upvotes   uint64 `json:"upvotes"`   // Total upvotes on comment
-	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
-	Receipt   string `json:"receipt"`   // Server sig of client sig
+	Downvotes uint64            `json:"downvotes"`           // Tolal downvotes on comment
+	Upvotes   uint64            `json:"upvotes"`             // Total upvotes on comment
+	Reactions map[string]uint64 `json:"reactions,omitempty"` // [reaction]tally
+	Timestamp int64             `json:"timestamp"`           // Received UNIX timestamp
+	Receipt   string            `json:"receipt"`             // Server sig of client sig
 }
 
-// Del permanently deletes the provided comment. Only admins can delete
-// comments. A reason must be given for the deletion.
+// Del permanently deletes the provided comment. Admins can delete any
+// comment. A reason must be given when an admin deletes a comment.
+//
+// Comment authors can delete their own comments when the AllowAuthorDeletes
+// policy is enabled. A reason is optional for author deletes. Comments that
+// were deleted by their author are marked using the Comment DeletedByAuthor
+// field so that they can be displayed as removed by the author rather than
+// censored.
 //
 // PublicKey is the user's public key that is used to verify the signature.
 //
@@ -378,6 +590,49 @@ type DelReply struct {
 	Comment Comment `json:"comment"`
 }
 
+// DelUser permanently deletes all of the comments that were made by a user.
+// This is an admin only command that allows the comments of a spam account to
+// be censored using a single request. If a token is provided, only the
+// comments that were made on that record are deleted. Otherwise, the comments
+// that were made on all records are deleted. Comments that have already been
+// deleted are skipped. A reason must be given.
+//
+// Each of the deleted comments is saved as a separate comment delete that
+// contains the DelUser signature.
+//
+// PublicKey is the admin's public key that is used to verify the signature.
+//
+// Signature is the admin signature of the:
+// UserID + Reason
+//
+// The PublicKey and Signature are hex encoded and use the
+// ed25519 signature scheme.
+type DelUser struct {
+	UserID    string `json:"userid"`
+	Token     string `json:"token,omitempty"`
+	Reason    string `json:"reason"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// DelUserRecord contains the IDs of the comments that were deleted on a
+// record.
+type DelUserRecord struct {
+	Token      string   `json:"token"`
+	CommentIDs []uint32 `json:"commentids"`
+}
+
+// DelUserReply is the reply to the DelUser command. Records contains the
+// records that had comments deleted, ordered by token. Skipped contains the
+// tokens of the records whose comments are not allowed to be deleted, e.g.
+// records that have been locked. Total is the total number of comments that
+// were deleted.
+type DelUserReply struct {
+	Records []DelUserRecord `json:"records"`
+	Skipped []string        `json:"skipped"`
+	Total   uint32          `json:"total"`
+}
+
 const (
 	// CountPageSize is the maximum number of tokens that can be
 	// included in the Count command.
@@ -394,19 +649,147 @@ type Count struct {
 	Tokens []string `json:"tokens"`
 }
 
-// CountReply is the reply to the count command.
+// CountReply is the reply to the count command. Pinned contains the ID of the
+// pinned comment of each record that has one so that clients can display it
+// at the top of the record comments.
 type CountReply struct {
 	Counts map[string]uint32 `json:"counts"`
+	Pinned map[string]uint32 `json:"pinned,omitempty"` // [token]commentID
 }
 
+// SortT represents the order that comments are sorted in.
+type SortT uint32
+
+const (
+	// SortInvalid is an invalid sort order. Comments are ordered by
+	// comment ID when a sort order is not provided.
+	SortInvalid SortT = 0
+
+	// SortScore sorts comments by their score, i.e. upvotes minus
+	// downvotes, from highest to lowest.
+	SortScore SortT = 1
+
+	// SortStakeScore sorts comments by their stake weighted score, i.e.
+	// stake upvotes minus stake downvotes, from highest to lowest. This
+	// sort order is only supported when stake weighting has been
+	// enabled.
+	SortStakeScore SortT = 2
+
+	// SortTimestamp sorts comments by their creation timestamp, from
+	// newest to oldest.
+	SortTimestamp SortT = 3
+)
+
 // Comments requests a record's comments.
+//
+// If RenderHTML is set, the reply comments will include the sanitized HTML
+// rendering of the comment text. Markdown comments are rendered using a
+// restricted subset of markdown. All other text is HTML escaped.
+//
+// SortBy is optional. Comments with the same score are ordered by comment ID.
+//
+// All comments are returned unless a page or page size is provided. Paginated
+// requests return the requested page of comments in the requested sort order.
+// Page 1 is returned if a page is not provided. The page size defaults to, and
+// is capped at, the policy CommentsPageSize. An empty page is returned if the
+// requested page does not exist.
 type Comments struct {
-	Token string `json:"token"`
+	Token      string `json:"token"`
+	RenderHTML bool   `json:"renderhtml,omitempty"`
+	SortBy     SortT  `json:"sortby,omitempty"`
+	Page       uint32 `json:"page,omitempty"`
+	PageSize   uint32 `json:"pagesize,omitempty"`
 }
 
-// CommentsReply is the reply to the comments command.
+// CommentsReply is the reply to the comments command. Total is the number of
+// comments on the record. It is only populated for paginated requests.
 type CommentsReply struct {
 	Comments []Comment `json:"comments"`
+	Total    uint32    `json:"total,omitempty"`
+}
+
+// Tree requests the subtree of comments that are replies to the provided
+// parent comment. A parent ID of 0 requests the base level comments of the
+// record. This allows clients to lazily expand deep comment threads instead
+// of retrieving all comments and rebuilding the tree.
+//
+// Depth is the number of levels of replies that are returned and Children is
+// the number of replies that are returned per comment. Both default to, and
+// are capped at, the policy TreeDepthMax and TreeChildrenMax. The replies of
+// each comment are ordered using the provided sort order, or by comment ID
+// when a sort order is not provided.
+//
+// If RenderHTML is set, the reply comments will include the sanitized HTML
+// rendering of the comment text.
+type Tree struct {
+	Token      string `json:"token"`
+	ParentID   uint32 `json:"parentid,omitempty"`
+	Depth      uint32 `json:"depth,omitempty"`
+	Children   uint32 `json:"children,omitempty"`
+	SortBy     SortT  `json:"sortby,omitempty"`
+	RenderHTML bool   `json:"renderhtml,omitempty"`
+}
+
+// TreeComment is a comment in a comment subtree. Replies is the total number
+// of direct replies to the comment. A comment can be expanded by requesting
+// its subtree when Replies is greater than the number of returned Children.
+type TreeComment struct {
+	Comment  Comment       `json:"comment"`
+	Replies  uint32        `json:"replies"`
+	Children []TreeComment `json:"children,omitempty"`
+}
+
+// TreeReply is the reply to the Tree command. Replies is the total number of
+// direct replies to the parent comment.
+type TreeReply struct {
+	Comments []TreeComment `json:"comments"`
+	Replies  uint32        `json:"replies"`
+}
+
+// Attachments retrieves the attachments of the latest version of a comment,
+// including the attachment payloads. No attachments are returned for a
+// deleted comment.
+//
+// The attachments of comments that were made on unvetted records are only
+// returned to admins and to the record author.
+type Attachments struct {
+	Token     string `json:"token"`
+	CommentID uint32 `json:"commentid"`
+}
+
+// AttachmentsReply is the reply to the Attachments command.
+type AttachmentsReply struct {
+	Attachments []Attachment `json:"attachments"`
+}
+
+// UserComments retrieves the comments that were made by a user. If a token is
+// provided, only the comments that were made on that record are returned.
+// Otherwise, the comments that were made on all records are returned. The
+// comment IDs are always returned. The latest version of each comment is only
+// returned when IncludeComments is set.
+//
+// Comments that were made on unvetted records are only returned to admins
+// and to the user that made them.
+type UserComments struct {
+	UserID          string `json:"userid"`
+	Token           string `json:"token,omitempty"`
+	IncludeComments bool   `json:"includecomments,omitempty"`
+}
+
+// UserRecordComments contains the comments that a user made on a record. The
+// comment IDs are in ascending order. Comments is only populated when the
+// comments were requested and is in the same order as the comment IDs.
+type UserRecordComments struct {
+	Token      string       `json:"token"`
+	State      RecordStateT `json:"state"`
+	CommentIDs []uint32     `json:"commentids"`
+	Comments   []Comment    `json:"comments,omitempty"`
+}
+
+// UserCommentsReply is the reply to the UserComments command. The records are
+// ordered by token.
+type UserCommentsReply struct {
+	Records []UserRecordComments `json:"records"`
 }
 
 // Votes retrieves the record's comment votes that meet the provided filtering
@@ -414,9 +797,15 @@ type CommentsReply struct {
 // votes. This command is paginated, if no page is provided, then the first
 // page is returned. If the requested page does not exist an empty page
 // is returned.
+//
+// Since is an optional UNIX timestamp. When provided, only the votes that
+// were cast at or after the timestamp are returned. This allows a client that
+// has already retrieved a user's votes to only retrieve the votes that have
+// changed since.
 type Votes struct {
 	Token  string `json:"token"`
 	UserID string `json:"userid,omitempty"`
+	Since  int64  `json:"since,omitempty"`
 	Page   uint32 `json:"page,omitempty"`
 }
 
@@ -494,3 +883,258 @@ type TimestampsReply struct {
 	// map[commentID]CommentTimestamp
 	Comments map[uint32]CommentTimestamp `json:"comments"`
 }
+
+// CommentIDRange is an inclusive range of comment IDs.
+type CommentIDRange struct {
+	Start uint32 `json:"start"`
+	End   uint32 `json:"end"`
+}
+
+// TimestampsBatch requests the timestamps for the comments of a record that
+// fall within the provided comment ID ranges. Ranges that extend past the
+// latest comment ID of the record are capped at the latest comment ID. The
+// total number of comment IDs cannot exceed the TimestampsPageSize policy.
+//
+// The comments of a record that has been censored or archived can no longer
+// change, so their timestamps are cached once they are final.
+type TimestampsBatch struct {
+	Token  string           `json:"token"`
+	Ranges []CommentIDRange `json:"ranges"`
+}
+
+// Search searches the latest version of the comments of public records for
+// the provided query. The search is case insensitive and ignores punctuation.
+// The query supports the following syntax:
+//
+// word       Matches comments that contain the word.
+// "a phrase" Matches comments that contain the exact phrase.
+// -word      Excludes comments that contain the word.
+// -"phrase"  Excludes comments that contain the phrase.
+//
+// A comment must match all of the query terms. The query must contain at
+// least one term that is not an exclusion. Deleted comments are not searched.
+//
+// Token and UserID are optional filters that limit the search to the comments
+// of a single record or of a single user.
+//
+// The results are ordered from newest to oldest. This command is paginated.
+// If no page is provided, then the first page is returned. The page size is
+// the SearchPageSize policy.
+type Search struct {
+	Query  string `json:"query"`
+	Token  string `json:"token,omitempty"`
+	UserID string `json:"userid,omitempty"`
+	Page   uint32 `json:"page,omitempty"`
+}
+
+// SearchReply is the reply to the Search command. Total is the total number
+// of comments that matched the query across all pages.
+type SearchReply struct {
+	Comments []Comment `json:"comments"`
+	Total    uint32    `json:"total"`
+}
+
+// History requests the comment history of a user. The comment history
+// contains the comments that the user has made on public records. Deleted
+// comments are not included.
+//
+// The comments are ordered from newest to oldest. This command is paginated.
+// If no page is provided, then the first page is returned. The page size is
+// the HistoryPageSize policy.
+type History struct {
+	UserID string `json:"userid"`
+	Page   uint32 `json:"page,omitempty"`
+}
+
+// HistoryEntry is a comment in the comment history of a user. Excerpt is the
+// start of the latest version of the comment text and is truncated to
+// HistoryExcerptLength characters. Timestamp is the UNIX timestamp of the
+// creation of the comment.
+type HistoryEntry struct {
+	Token     string `json:"token"`
+	CommentID uint32 `json:"commentid"`
+	Excerpt   string `json:"excerpt"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+const (
+	// HistoryExcerptLength is the maximum number of characters of the
+	// comment text that are included in a HistoryEntry excerpt.
+	HistoryExcerptLength = 200
+)
+
+// HistoryReply is the reply to the History command. Total is the total number
+// of comments in the comment history of the user across all pages.
+type HistoryReply struct {
+	Entries []HistoryEntry `json:"entries"`
+	Total   uint32         `json:"total"`
+}
+
+// SubscribeActionT represents a comment thread subscription action.
+type SubscribeActionT string
+
+const (
+	// SubscribeActionSubscribe subscribes the user to the replies of a
+	// comment thread.
+	SubscribeActionSubscribe SubscribeActionT = "subscribe"
+
+	// SubscribeActionUnsubscribe unsubscribes the user from the replies
+	// of a comment thread.
+	SubscribeActionUnsubscribe SubscribeActionT = "unsubscribe"
+)
+
+// Subscribe subscribes the user to, or unsubscribes the user from, the thread
+// of replies under a comment. A subscribed user is sent a notification for
+// every new reply under the comment, including replies to replies. This
+// allows a user, e.g. a proposal author following the replies to one of their
+// proposal updates, to follow a single thread without being notified of every
+// new comment on the record.
+//
+// PublicKey is the user's public key that is used to verify the signature.
+//
+// Signature is the user signature of the:
+// State + Token + CommentID + Action
+//
+// The PublicKey and Signature are hex encoded and use the
+// ed25519 signature scheme.
+type Subscribe struct {
+	State     RecordStateT     `json:"state"`
+	Token     string           `json:"token"`
+	CommentID uint32           `json:"commentid"`
+	Action    SubscribeActionT `json:"action"`
+	PublicKey string           `json:"publickey"`
+	Signature string           `json:"signature"`
+}
+
+// SubscribeReply is the reply to the Subscribe command.
+type SubscribeReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server sig of client sig
+}
+
+// Pin pins a comment so that clients can display it at the top of the record
+// comments, e.g. the latest official update of a record. A record can only
+// have one pinned comment. Pinning a comment replaces the previously pinned
+// comment. A CommentID of 0 unpins the pinned comment. Deleted comments cannot
+// be pinned.
+//
+// Only the record author and admins are allowed to pin a comment. The pinned
+// comment ID of a record is returned by the Count route.
+//
+// PublicKey is the user's public key that is used to verify the signature.
+//
+// Signature is the user signature of the:
+// State + Token + CommentID
+//
+// The PublicKey and Signature are hex encoded and use the
+// ed25519 signature scheme.
+type Pin struct {
+	State     RecordStateT `json:"state"`
+	Token     string       `json:"token"`
+	CommentID uint32       `json:"commentid"`
+	PublicKey string       `json:"publickey"`
+	Signature string       `json:"signature"`
+}
+
+// PinReply is the reply to the Pin command.
+type PinReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server sig of client sig
+}
+
+// SubmissionStatusT represents the status of a submission that was queued
+// because politeiad was unavailable.
+type SubmissionStatusT uint32
+
+const (
+	// SubmissionStatusInvalid is an invalid submission status.
+	SubmissionStatusInvalid SubmissionStatusT = 0
+
+	// SubmissionStatusPending indicates that the submission has been
+	// persisted by politeiawww and is waiting to be sent to politeiad.
+	SubmissionStatusPending SubmissionStatusT = 1
+
+	// SubmissionStatusComplete indicates that the submission was
+	// successfully sent to politeiad. The reply will be populated.
+	SubmissionStatusComplete SubmissionStatusT = 2
+
+	// SubmissionStatusFailed indicates that politeiad rejected the
+	// submission or that it expired before politeiad became available.
+	// The error will be populated.
+	SubmissionStatusFailed SubmissionStatusT = 3
+)
+
+var (
+	// SubmissionStatuses contains the human readable submission
+	// statuses.
+	SubmissionStatuses = map[SubmissionStatusT]string{
+		SubmissionStatusInvalid:  "invalid",
+		SubmissionStatusPending:  "pending",
+		SubmissionStatusComplete: "complete",
+		SubmissionStatusFailed:   "failed",
+	}
+)
+
+// SubmissionStatus requests the status of a submission that was queued
+// because politeiad was unavailable. The submission ID is returned in the
+// reply of the queued command.
+type SubmissionStatus struct {
+	SubmissionID string `json:"submissionid"`
+}
+
+// SubmissionStatusReply is the reply to the SubmissionStatus command.
+// Attempts is the number of times that politeiawww has attempted to send the
+// submission to politeiad.
+type SubmissionStatusReply struct {
+	Status    SubmissionStatusT `json:"status"`
+	Timestamp int64             `json:"timestamp"` // Unix time of submission
+	Attempts  uint32            `json:"attempts"`
+	Reply     *NewReply         `json:"reply,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+const (
+	// StakeProofPrefix is the prefix of the message that is signed by the
+	// commitment address of each ticket in a stake proof.
+	StakeProofPrefix = "politeia comment stake proof:"
+)
+
+// TicketSignature contains a signature that proves ownership of a ticket.
+//
+// Signature is the signature of the ticket commitment address of the:
+// StakeProofPrefix + Token + UserID
+type TicketSignature struct {
+	Ticket    string `json:"ticket"`    // Ticket hash
+	Signature string `json:"signature"` // Commitment address signature
+}
+
+// StakeProof submits proof that the user holds tickets that were eligible to
+// vote in the ticket vote of a record. The comment votes of the user on the
+// record are weighted by the stake of the proven tickets. Stake proofs can
+// only be submitted once the ticket vote of the record has been started.
+//
+// A new stake proof replaces the previous stake proof of the user. A ticket
+// can only be claimed by a single user for each record.
+//
+// PublicKey is the user's public key that is used to verify the signature.
+//
+// Signature is the user signature of the:
+// Token + UserID + Ticket hashes
+//
+// The PublicKey and Signature are hex encoded and use the ed25519 signature
+// scheme.
+type StakeProof struct {
+	Token     string            `json:"token"`
+	Tickets   []TicketSignature `json:"tickets"`
+	PublicKey string            `json:"publickey"`
+	Signature string            `json:"signature"`
+}
+
+// StakeProofReply is the reply to the StakeProof command. Weight is the sum of
+// the weights of the proven tickets. The ticket weight is the ticket stake in
+// atoms for stake weighted votes and 1 for all other votes.
+type StakeProofReply struct {
+	Weight    uint64 `json:"weight"`
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server sig of client sig
+}
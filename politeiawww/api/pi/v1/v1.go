@@ -4,7 +4,12 @@
 
 package v1
 
-import "fmt"
+import (
+	"fmt"
+
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+)
 
 const (
 	// APIRoute is prefixed onto all routes defined in this package.
@@ -22,6 +27,23 @@ const (
 	// RouteSummaries returns the proposal summary for a page of
 	// records.
 	RouteSummaries = "/summaries"
+
+	// RouteProposalList returns the data that is required to render the
+	// proposal list for a page of records.
+	RouteProposalList = "/proposallist"
+
+	// RouteIdentity returns the public key of the server identity that is
+	// used to sign the pi receipts.
+	RouteIdentity = "/identity"
+
+	// RouteCompletionReport returns the completion report of a proposal.
+	RouteCompletionReport = "/completionreport"
+
+	// RouteWithdraw withdraws a proposal on behalf of its author.
+	RouteWithdraw = "/withdraw"
+
+	// RouteWithdrawal returns the withdrawal of a proposal.
+	RouteWithdrawal = "/withdrawal"
 )
 
 // ErrorCodeT represents a user error code.
@@ -83,10 +105,16 @@ func (e UserErrorReply) Error() string {
 
 // PluginErrorReply is the reply that the server returns when it encounters
 // a plugin error.
+//
+// ErrorParams is optional and contains the values that the user input was
+// validated against, e.g. the min and max allowed values and the value that
+// was provided. Clients can use the error code and the params to present a
+// translated error message. The param keys are defined by the plugin API.
 type PluginErrorReply struct {
-	PluginID     string `json:"pluginid"`
-	ErrorCode    uint32 `json:"errorcode"`
-	ErrorContext string `json:"errorcontext,omitempty"`
+	PluginID     string            `json:"pluginid"`
+	ErrorCode    uint32            `json:"errorcode"`
+	ErrorContext string            `json:"errorcontext,omitempty"`
+	ErrorParams  map[string]string `json:"errorparams,omitempty"`
 }
 
 // Error satisfies the error interface.
@@ -134,6 +162,27 @@ type PolicyReply struct {
 	SummariesPageSize            uint32   `json:"summariespagesize"`
 	BillingStatusChangesPageSize uint32   `json:"billingstatuschangespagesize"`
 	BillingStatusChangesMax      uint32   `json:"billingstatuschangesmax"`
+
+	// BillingStatusReasonRequired contains the billing statuses that
+	// require a reason to be provided when a proposal's billing status
+	// is set to them.
+	BillingStatusReasonRequired []BillingStatusT `json:"billingstatusreasonrequired"`
+
+	// AbandonReasonLengthMin is the minimum number of characters that
+	// the reason for abandoning a public proposal must contain.
+	AbandonReasonLengthMin uint32 `json:"abandonreasonlengthmin"`
+
+	// CommentLengthMaxUnvetted and CommentLengthMaxVetted are the
+	// maximum number of characters that a comment on an unvetted or
+	// vetted proposal can contain. A value of 0 means that only the
+	// comments API length limit applies.
+	CommentLengthMaxUnvetted uint32 `json:"commentlengthmaxunvetted"`
+	CommentLengthMaxVetted   uint32 `json:"commentlengthmaxvetted"`
+
+	// CommentLockDays is the number of days after a proposal vote has
+	// finished that new comments are still allowed on the proposal. A
+	// value of 0 means that comments are never locked.
+	CommentLockDays uint32 `json:"commentlockdays"`
 }
 
 const (
@@ -235,29 +284,43 @@ var (
 //
 // PublicKey is the admin public key that can be used to verify the signature.
 //
-// Signature is the admin signature of the Token+Status+Reason.
+// Signature is the admin signature of the Token+Status+Reason. If a completion
+// report was attached to the billing status change then the signature is of
+// the Token+Status+Reason+ReportDigest.
+//
+// ReportDigest is the SHA256 digest of the completion report that was
+// attached to the billing status change, if one was attached.
 //
 // Receipt is the server signature of the admin signature.
 //
 // The PublicKey, Signature, and Receipt are all hex encoded and use the
 // ed25519 signature scheme.
 type BillingStatusChange struct {
-	Token     string         `json:"token"`
-	Status    BillingStatusT `json:"status"`
-	Reason    string         `json:"reason,omitempty"`
-	PublicKey string         `json:"publickey"`
-	Signature string         `json:"signature"`
-	Receipt   string         `json:"receipt"`
-	Timestamp int64          `json:"timestamp"` // Unix timestamp
+	Token        string         `json:"token"`
+	Status       BillingStatusT `json:"status"`
+	Reason       string         `json:"reason,omitempty"`
+	ReportDigest string         `json:"reportdigest,omitempty"`
+	PublicKey    string         `json:"publickey"`
+	Signature    string         `json:"signature"`
+	Receipt      string         `json:"receipt"`
+	Timestamp    int64          `json:"timestamp"` // Unix timestamp
 }
 
 // SetBillingStatus sets the billing status of a proposal. Some billing status
 // changes require a reason to be given. Only admins can update the billing
 // status of a proposal.
 //
+// Token can be either the full length token or a prefix of the token that is
+// at least as long as the short token.
+//
 // PublicKey is the admin public key that can be used to verify the signature.
 //
-// Signature is the admin signature of the Token+Status+Reason.
+// Report is an optional completion report. A completion report can only be
+// attached when the billing status is being set to completed.
+//
+// Signature is the admin signature of the Token+Status+Reason. If a completion
+// report is attached then the signature is of the
+// Token+Status+Reason+Report.Digest.
 //
 // The PublicKey and Signature are hex encoded and use the ed25519 signature
 // scheme.
@@ -265,10 +328,25 @@ type SetBillingStatus struct {
 	Token     string         `json:"token"`
 	Status    BillingStatusT `json:"status"`
 	Reason    string         `json:"reason,omitempty"`
+	Report    *ReportFile    `json:"report,omitempty"`
 	PublicKey string         `json:"publickey"`
 	Signature string         `json:"signature"`
 }
 
+// ReportFile is a completion report that an admin can attach to a proposal
+// when setting its billing status to completed. It provides an auditable
+// close-out artifact for the proposal. The report must be a plain text file.
+//
+// Digest is the hex encoded SHA256 digest of the unencoded payload.
+//
+// Payload is the base64 encoded report contents.
+type ReportFile struct {
+	Name    string `json:"name"`
+	MIME    string `json:"mime"`
+	Digest  string `json:"digest"`
+	Payload string `json:"payload"`
+}
+
 // SetBillingStatusReply is the reply to the SetBillingStatus command.
 //
 // Receipt is the server signature of the client signature. It is hex encoded
@@ -304,6 +382,101 @@ type BillingStatusChangesReply struct {
 	BillingStatusChanges map[string][]BillingStatusChange `json:"billingstatuschanges"`
 }
 
+// Identity requests the public key of the server identity that is used to
+// sign the pi receipts, e.g. the BillingStatusChange receipts. This allows the
+// receipts to be verified offline.
+type Identity struct{}
+
+// IdentityReply is the reply to the Identity command.
+//
+// PublicKey is the hex encoded, ed25519 public key of the server identity.
+type IdentityReply struct {
+	PublicKey string `json:"publickey"`
+}
+
+// CompletionReport requests the completion report of a proposal.
+type CompletionReport struct {
+	Token string `json:"token"`
+}
+
+// CompletionReportReply is the reply to the CompletionReport command. Report
+// will be nil if the proposal does not have a completion report.
+type CompletionReportReply struct {
+	Report *ReportFile `json:"report,omitempty"`
+}
+
+// Withdrawal is the signed withdrawal of a proposal by its author.
+//
+// Version is the version of the proposal that was withdrawn.
+//
+// PublicKey is the author public key that can be used to verify the
+// signature.
+//
+// Signature is the author signature of the Token+Version+Status+Reason, where
+// Status is the archived record status. This is the same message that is
+// signed for a record status change, which allows the signature to be used for
+// both the withdrawal and the resulting archival of the record.
+//
+// Receipt is the server signature of the author signature.
+//
+// The PublicKey, Signature, and Receipt are all hex encoded and use the
+// ed25519 signature scheme.
+type Withdrawal struct {
+	Token     string `json:"token"`
+	Version   uint32 `json:"version"`
+	Reason    string `json:"reason"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+	Receipt   string `json:"receipt"`
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
+}
+
+// Withdraw withdraws a proposal. Only the proposal author can withdraw a
+// proposal and only while it is under review, i.e. prior to the proposal vote
+// being authorized. The proposal is archived and the proposal credit that was
+// spent on the proposal is refunded to the author. The reason must meet the
+// same requirements as the reason for abandoning a proposal.
+//
+// Token must be the full length token.
+//
+// Signature is the author signature of the Token+Version+Status+Reason, where
+// Status is the archived record status.
+//
+// The PublicKey and Signature are hex encoded and use the ed25519 signature
+// scheme.
+type Withdraw struct {
+	Token     string `json:"token"`
+	Version   uint32 `json:"version"`
+	Reason    string `json:"reason"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// WithdrawReply is the reply to the Withdraw command.
+//
+// CreditRefunded is set to true if a proposal credit was refunded to the
+// author. No credit is refunded when the paywall is disabled or when the
+// credit has already been refunded.
+//
+// Receipt is the server signature of the client signature. It is hex encoded
+// and uses the ed25519 signature scheme.
+type WithdrawReply struct {
+	CreditRefunded bool   `json:"creditrefunded"`
+	Receipt        string `json:"receipt"`
+	Timestamp      int64  `json:"timestamp"` // Unix timestamp
+}
+
+// WithdrawalDetails requests the withdrawal of a proposal.
+type WithdrawalDetails struct {
+	Token string `json:"token"`
+}
+
+// WithdrawalDetailsReply is the reply to the WithdrawalDetails command.
+// Withdrawal will be nil if the proposal has not been withdrawn.
+type WithdrawalDetailsReply struct {
+	Withdrawal *Withdrawal `json:"withdrawal,omitempty"`
+}
+
 const (
 	// ProposalUpdateHint is the hint that is included in a comment's
 	// ExtraDataHint field to indicate that the comment is an update
@@ -349,3 +522,35 @@ type SummariesReply struct {
 type Summary struct {
 	Status string `json:"status"`
 }
+
+// ProposalList requests the data that is required to render the proposal list
+// for a page of proposals. It combines the records Records, pi Summaries,
+// ticketvote Summaries, and comments Count requests into a single request.
+//
+// The number of tokens that can be requested is limited by the
+// SummariesPageSize policy.
+type ProposalList struct {
+	Tokens []string `json:"tokens"`
+}
+
+// ProposalListReply is the reply to the ProposalList command.
+//
+// The Proposals map will not contain an entry for any tokens that did not
+// correspond to an actual proposal. The replies are cached by the server for
+// a short period of time, so they may trail the most recent proposal changes
+// slightly.
+type ProposalListReply struct {
+	Proposals map[string]ProposalListItem `json:"proposals"` // [token]Item
+}
+
+// ProposalListItem contains the proposal list data of a single proposal.
+//
+// The Record only contains the proposal metadata file and, if one exists, the
+// vote metadata file. The files of unvetted proposals are only returned to
+// admins and the proposal author.
+type ProposalListItem struct {
+	Record      rcv1.Record  `json:"record"`
+	Summary     Summary      `json:"summary"`
+	VoteSummary tkv1.Summary `json:"votesummary"`
+	Comments    uint32       `json:"comments"`
+}
@@ -128,10 +128,15 @@ const (
 	// exceeds the maximum page size of the request.
 	ErrorCodePageSizeExceeded ErrorCodeT = 20
 
+	// ErrorCodeFileImageInvalid is returned when an image file is not a
+	// valid image, exceeds the maximum image dimensions, or contains
+	// metadata or trailing data that is not allowed.
+	ErrorCodeFileImageInvalid ErrorCodeT = 21
+
 	// ErrorCodeLast is used by unit tests to verify that all error codes have
 	// a human readable entry in the ErrorCodes map. This error will never be
 	// returned.
-	ErrorCodeLast ErrorCodeT = 21
+	ErrorCodeLast ErrorCodeT = 22
 )
 
 var (
@@ -158,6 +163,7 @@ var (
 		ErrorCodeStatusChangeInvalid:     "status change invalid",
 		ErrorCodeStatusReasonNotFound:    "status reason not found",
 		ErrorCodePageSizeExceeded:        "page size exceeded",
+		ErrorCodeFileImageInvalid:        "file image invalid",
 	}
 )
 
@@ -176,10 +182,16 @@ func (e UserErrorReply) Error() string {
 
 // PluginErrorReply is the reply that the server returns when it encounters
 // a plugin error.
+//
+// ErrorParams is optional and contains the values that the user input was
+// validated against, e.g. the min and max allowed values and the value that
+// was provided. Clients can use the error code and the params to present a
+// translated error message. The param keys are defined by the plugin API.
 type PluginErrorReply struct {
-	PluginID     string `json:"pluginid"`
-	ErrorCode    uint32 `json:"errorcode"`
-	ErrorContext string `json:"errorcontext,omitempty"`
+	PluginID     string            `json:"pluginid"`
+	ErrorCode    uint32            `json:"errorcode"`
+	ErrorContext string            `json:"errorcontext,omitempty"`
+	ErrorParams  map[string]string `json:"errorparams,omitempty"`
 }
 
 // Error satisfies the error interface.
@@ -207,8 +219,18 @@ type Policy struct{}
 type PolicyReply struct {
 	RecordsPageSize   uint32 `json:"recordspagesize"`
 	InventoryPageSize uint32 `json:"inventorypagesize"`
+	ImageWidthMax     uint32 `json:"imagewidthmax"`  // In pixels
+	ImageHeightMax    uint32 `json:"imageheightmax"` // In pixels
 }
 
+const (
+	// ImageWidthMax is the maximum width in pixels of an image file.
+	ImageWidthMax uint32 = 4096
+
+	// ImageHeightMax is the maximum height in pixels of an image file.
+	ImageHeightMax uint32 = 4096
+)
+
 // RecordStateT represents the state of a record.
 type RecordStateT uint32
 
@@ -278,11 +300,15 @@ var (
 )
 
 // File describes an individual file that is part of the record.
+//
+// CID is the IPFS content identifier of the file. It is only populated on
+// the files of public records when the server pins record files to IPFS.
 type File struct {
-	Name    string `json:"name"`    // Filename
-	MIME    string `json:"mime"`    // Mime type
-	Digest  string `json:"digest"`  // SHA256 digest of unencoded payload
-	Payload string `json:"payload"` // File content, base64 encoded
+	Name    string `json:"name"`          // Filename
+	MIME    string `json:"mime"`          // Mime type
+	Digest  string `json:"digest"`        // SHA256 digest of unencoded payload
+	Payload string `json:"payload"`       // File content, base64 encoded
+	CID     string `json:"cid,omitempty"` // IPFS CID
 }
 
 // MetadataStream describes a record metadata stream.
@@ -344,6 +370,47 @@ type StatusChange struct {
 	Timestamp int64         `json:"timestamp"`
 }
 
+// Provenance describes where a migrated record originated from. It is
+// generated by the migration tooling and saved to politeiad as a metadata
+// stream. Only records that were migrated from another system, e.g. the
+// proposals from the legacy git backend, contain provenance metadata.
+//
+// SourceToken, SourceVersion and SourceTimestamp are the token, version and
+// last update timestamp of the record in the source system. PublishedAt is
+// the timestamp of when the record was made public in the source system.
+// Clients should use these fields when rendering migrated records since the
+// record timestamps reflect when the record was imported.
+type Provenance struct {
+	SourceSystem          string `json:"sourcesystem"`
+	SourceToken           string `json:"sourcetoken"`
+	SourceVersion         uint32 `json:"sourceversion"`
+	SourceTimestamp       int64  `json:"sourcetimestamp"`
+	PublishedAt           int64  `json:"publishedat,omitempty"`
+	ConversionTool        string `json:"conversiontool"`
+	ConversionToolVersion string `json:"conversiontoolversion"`
+	ImportedAt            int64  `json:"importedat"`
+}
+
+const (
+	// FormFieldRequest is the multipart/form-data field name of the part
+	// that contains the JSON encoded New or Edit request.
+	FormFieldRequest = "request"
+
+	// FormFieldFile is the multipart/form-data field name of the parts
+	// that contain the record file contents.
+	FormFieldFile = "file"
+)
+
+// Record files can be submitted to the New and Edit routes using either a
+// JSON request body or a multipart/form-data request body. A multipart request
+// body avoids the overhead of base64 encoding the file payloads.
+//
+// A multipart request body must begin with a FormFieldRequest part that
+// contains the JSON encoded New or Edit request. The File payloads of this
+// request may be omitted. The request part is followed by a FormFieldFile part
+// for each file whose payload was omitted. A file part contains the raw file
+// contents and uses the File name as its filename.
+
 // New submits a new record.
 //
 // Signature is the client signature of the record merkle root. The merkle root
@@ -389,8 +456,15 @@ type SetStatus struct {
 }
 
 // SetStatusReply is the reply to the SetStatus command.
+//
+// PendingActionID is only populated when the status change requires the
+// confirmation of a second admin, e.g. censoring a vetted record when the
+// server has been configured to require it. The status change has not been
+// executed in this case and the Record will be empty. See the www v1
+// PendingActionConfirm command.
 type SetStatusReply struct {
-	Record Record `json:"record"`
+	Record          Record `json:"record"`
+	PendingActionID string `json:"pendingactionid,omitempty"`
 }
 
 // Details requests the details of a record. The full record will be returned.
@@ -41,6 +41,52 @@ const (
 
 	// RouteTimestamps returns the timestamps for ticket vote data.
 	RouteTimestamps = "/timestamps"
+
+	// RouteExtend extends the end block height of an active record
+	// vote.
+	RouteExtend = "/extend"
+
+	// RouteCancel cancels a started record vote that has not had any
+	// ballots cast.
+	RouteCancel = "/cancel"
+
+	// RouteReceipts returns the cast vote receipts of a set of tickets.
+	RouteReceipts = "/receipts"
+
+	// RouteActiveVotesRebuild rebuilds the active votes cache that is
+	// used to validate cast ballots. This route is admin only.
+	RouteActiveVotesRebuild = "/activevotes/rebuild"
+
+	// RouteSnapshotVerify re-verifies the eligible ticket snapshot of a
+	// record vote against the chain. This route is admin only.
+	RouteSnapshotVerify = "/snapshot/verify"
+
+	// RouteStats returns the turnout and participation statistics of a
+	// finished record vote.
+	RouteStats = "/stats"
+
+	// RouteSubmissionStatus returns the status of a ballot that was
+	// queued because politeiad was unavailable.
+	RouteSubmissionStatus = "/submissionstatus"
+
+	// RouteVote returns the cast vote of a single ticket.
+	RouteVote = "/vote"
+
+	// RouteSetVoteDuration sets the min and max vote duration that are
+	// allowed for new votes. This route is admin only.
+	RouteSetVoteDuration = "/setvoteduration"
+
+	// RouteGovernanceExport returns the governance export of all
+	// finished votes.
+	RouteGovernanceExport = "/governanceexport"
+
+	// RouteVoteParamsVerify verifies a set of vote params without
+	// starting a vote.
+	RouteVoteParamsVerify = "/voteparamsverify"
+
+	// RouteAuditBundle returns a signed audit bundle of a finished
+	// record vote.
+	RouteAuditBundle = "/auditbundle"
 )
 
 // ErrorCodeT represents a user error code.
@@ -81,24 +127,29 @@ const (
 	// cause collisions.
 	ErrorCodeDuplicatePayload ErrorCodeT = 8
 
+	// ErrorCodeSubmissionNotFound is returned when a queued submission
+	// is not found.
+	ErrorCodeSubmissionNotFound ErrorCodeT = 9
+
 	// ErrorCodeLast is used by unit tests to verify that all error codes have
 	// a human readable entry in the ErrorCodes map. This error will never be
 	// returned.
-	ErrorCodeLast ErrorCodeT = 9
+	ErrorCodeLast ErrorCodeT = 10
 )
 
 var (
 	// ErrorCodes contains the human readable errors.
 	ErrorCodes = map[ErrorCodeT]string{
-		ErrorCodeInvalid:          "error invalid",
-		ErrorCodeInputInvalid:     "input invalid",
-		ErrorCodePublicKeyInvalid: "public key invalid",
-		ErrorCodeUnauthorized:     "unauthorized",
-		ErrorCodeRecordNotFound:   "record not found",
-		ErrorCodeRecordLocked:     "record locked",
-		ErrorCodeTokenInvalid:     "token is invalid",
-		ErrorCodePageSizeExceeded: "page size exceeded",
-		ErrorCodeDuplicatePayload: "duplicate payload",
+		ErrorCodeInvalid:            "error invalid",
+		ErrorCodeInputInvalid:       "input invalid",
+		ErrorCodePublicKeyInvalid:   "public key invalid",
+		ErrorCodeUnauthorized:       "unauthorized",
+		ErrorCodeRecordNotFound:     "record not found",
+		ErrorCodeRecordLocked:       "record locked",
+		ErrorCodeTokenInvalid:       "token is invalid",
+		ErrorCodePageSizeExceeded:   "page size exceeded",
+		ErrorCodeDuplicatePayload:   "duplicate payload",
+		ErrorCodeSubmissionNotFound: "submission not found",
 	}
 )
 
@@ -153,6 +204,22 @@ type PolicyReply struct {
 	SummariesPageSize  uint32 `json:"summariespagesize"`
 	InventoryPageSize  uint32 `json:"inventorypagesize"`
 	TimestampsPageSize uint32 `json:"timestampspagesize"`
+	VoteExtensionMax   uint32 `json:"voteextensionmax"` // In blocks
+
+	// AuthLockBlocks is the number of blocks, after a vote has been
+	// authorized, that the authorization cannot be revoked. A value of
+	// zero means that authorizations can be revoked at any time.
+	AuthLockBlocks uint32 `json:"authlockblocks"`
+
+	// ConcurrentVotesMax is the number of votes that can be running
+	// concurrently before starting a standard vote is considered to
+	// cause voter fatigue. Starting a vote that exceeds the max
+	// results in a warning, or an error if ConcurrentVotesBlock is
+	// set. A value of zero means that there is no max. ActiveVotes is
+	// the number of votes that are currently running.
+	ConcurrentVotesMax   uint32 `json:"concurrentvotesmax"`
+	ConcurrentVotesBlock bool   `json:"concurrentvotesblock"`
+	ActiveVotes          uint32 `json:"activevotes"`
 }
 
 // AuthActionT represents an Authorize action.
@@ -203,28 +270,95 @@ const (
 
 	// VoteTypeRunoff specifies a runoff vote that multiple records
 	// compete in. All records are voted on like normal and all votes
-	// are simple approve/reject votes, but there can only be one
-	// winner in a runoff vote. The winner is the record that meets
-	// the quorum requirement, meets the pass requirement, and that has
-	// the most net yes votes. The winning record is considered
+	// are simple approve/reject votes, but only the number of winners
+	// that is specified by the vote params can be approved. By default
+	// there is a single winner. The winners are the records that meet
+	// the quorum requirement, meet the pass requirement, and that have
+	// the most net yes votes. The winning records are considered
 	// approved and all other records are considered to be rejected.
 	// If no records meet the quorum and pass requirements then all
 	// records are considered rejected. Note, in a runoff vote it is
 	// possible for a record to meet both the quorum and pass
-	// requirements but still be rejected if it does not have the most
-	// net yes votes.
+	// requirements but still be rejected if it does not have enough
+	// net yes votes to be one of the winners.
 	VoteTypeRunoff VoteT = 2
 
+	// VoteTypeMultiOption specifies a vote that has multiple, mutually
+	// exclusive vote options. Each vote option is tallied separately
+	// and the winning option is determined using the win condition of
+	// the vote. A multi-option vote does not have an approved or
+	// rejected outcome. The vote status is set to finished once the
+	// voting period has ended and the winning option, if there is one,
+	// is included in the vote summary. Multi-option votes require an
+	// authorization from the record author before the voting period
+	// can be started by an admin.
+	VoteTypeMultiOption VoteT = 3
+
 	// VoteTypeLast unit test only.
-	VoteTypeLast VoteT = 3
+	VoteTypeLast VoteT = 4
 )
 
 var (
 	// VoteTypes contains the human readable vote types.
 	VoteTypes = map[VoteT]string{
-		VoteTypeInvalid:  "invalid vote type",
-		VoteTypeStandard: "standard",
-		VoteTypeRunoff:   "runoff",
+		VoteTypeInvalid:     "invalid vote type",
+		VoteTypeStandard:    "standard",
+		VoteTypeRunoff:      "runoff",
+		VoteTypeMultiOption: "multi-option",
+	}
+)
+
+// WinConditionT represents the condition that a vote option must meet in order
+// to win a multi-option vote. All win conditions require the vote to meet the
+// quorum requirement. A tie for the most votes results in no winning option.
+type WinConditionT uint32
+
+const (
+	// WinConditionInvalid represents an invalid win condition.
+	WinConditionInvalid WinConditionT = 0
+
+	// WinConditionPlurality indicates that the vote option with the
+	// most votes wins. The pass percentage must be zero for this
+	// condition.
+	WinConditionPlurality WinConditionT = 1
+
+	// WinConditionPassPercentage indicates that the vote option with
+	// the most votes wins only if it also received the pass percentage
+	// of the cast votes.
+	WinConditionPassPercentage WinConditionT = 2
+)
+
+var (
+	// WinConditions contains the human readable win conditions.
+	WinConditions = map[WinConditionT]string{
+		WinConditionInvalid:        "invalid",
+		WinConditionPlurality:      "plurality",
+		WinConditionPassPercentage: "pass percentage",
+	}
+)
+
+// VoteWeightT represents how the cast votes are weighted when calculating the
+// quorum and pass requirements of a vote.
+type VoteWeightT uint32
+
+const (
+	// VoteWeightTicket weights every ticket vote equally. The quorum
+	// and pass requirements are calculated using the number of tickets.
+	// This is the default vote weight.
+	VoteWeightTicket VoteWeightT = 0
+
+	// VoteWeightStake weights every ticket vote by the DCR amount that
+	// was staked by the ticket, i.e. the sum of the ticket commitment
+	// amounts. The quorum and pass requirements are calculated using
+	// the staked DCR amounts.
+	VoteWeightStake VoteWeightT = 1
+)
+
+var (
+	// VoteWeights contains the human readable vote weights.
+	VoteWeights = map[VoteWeightT]string{
+		VoteWeightTicket: "ticket",
+		VoteWeightStake:  "stake",
 	}
 )
 
@@ -300,6 +434,44 @@ var (
 	}
 )
 
+// RejectionReasonT represents the reason that a vote was rejected.
+type RejectionReasonT uint32
+
+const (
+	// RejectionReasonInvalid is an invalid rejection reason. It is used
+	// for votes that have not been rejected.
+	RejectionReasonInvalid RejectionReasonT = 0
+
+	// RejectionReasonQuorumNotMet indicates that the vote was rejected
+	// because not enough of the eligible tickets voted for the vote to
+	// reach a quorum.
+	RejectionReasonQuorumNotMet RejectionReasonT = 1
+
+	// RejectionReasonApprovalNotMet indicates that the vote reached a
+	// quorum, but was rejected because the approve votes did not meet
+	// the pass percentage.
+	RejectionReasonApprovalNotMet RejectionReasonT = 2
+
+	// RejectionReasonRunoffLost indicates that a runoff vote submission
+	// met the quorum and pass requirements, but was rejected because it
+	// did not have enough net approve votes to be one of the winners of
+	// the runoff vote.
+	RejectionReasonRunoffLost RejectionReasonT = 3
+
+	// RejectionReasonLast unit test only.
+	RejectionReasonLast RejectionReasonT = 4
+)
+
+var (
+	// RejectionReasons contains the human readable rejection reasons.
+	RejectionReasons = map[RejectionReasonT]string{
+		RejectionReasonInvalid:        "invalid",
+		RejectionReasonQuorumNotMet:   "quorum not met",
+		RejectionReasonApprovalNotMet: "approval not met",
+		RejectionReasonRunoffLost:     "runoff lost",
+	}
+)
+
 // VoteMetadata that is specified by the user on record submission in order to
 // host or participate in certain types of votes. It is attached to a record
 // submission as a metadata stream.
@@ -345,17 +517,43 @@ type VoteParams struct {
 	// Parent is the token of the parent record. This field will only
 	// be populated for runoff votes.
 	Parent string `json:"parent,omitempty"`
+
+	// WinCondition is the condition that determines the winning vote
+	// option. This field will only be populated for multi-option votes.
+	WinCondition WinConditionT `json:"wincondition,omitempty"`
+
+	// Winners is the maximum number of records that can be approved
+	// in a runoff vote. A value of zero is the same as one winner. Use
+	// RunoffWinnersAll to approve all records that meet the quorum and
+	// pass requirements. This field will only be populated for runoff
+	// votes and must be the same for all records in the runoff vote.
+	Winners uint32 `json:"winners,omitempty"`
+
+	// Weight is the vote weight that is used to calculate the quorum
+	// and pass requirements. The zero value is VoteWeightTicket.
+	Weight VoteWeightT `json:"weight,omitempty"`
 }
 
+// RunoffWinnersAll is the VoteParams Winners value that allows all records in
+// a runoff vote that meet the quorum and pass requirements to be approved.
+const RunoffWinnersAll = ^uint32(0)
+
 // StartDetails is the structure that is provided when starting a record
 // vote.
 //
 // Signature is the signature of a SHA256 digest of the JSON encoded
 // VoteParams.
+//
+// SnapshotHeight overrides the block height of the eligible ticket snapshot
+// of the vote. It is only supported by test network servers that have the
+// snapshot height override enabled and is intended for integration testing.
+// The snapshot height is not part of the signature.
 type StartDetails struct {
 	Params    VoteParams `json:"params"`
 	PublicKey string     `json:"publickey"`
 	Signature string     `json:"signature"`
+
+	SnapshotHeight uint32 `json:"snapshotheight,omitempty"`
 }
 
 // Start starts a record vote or multiple record votes if the vote is a runoff
@@ -377,12 +575,143 @@ type Start struct {
 // StartReply is the reply to the Start command.
 //
 // Receipt is the server signature of ClientSignature+StartBlockHash.
+//
+// ConcurrentVotes is the number of votes that are running concurrently now
+// that the vote has been started, including the started vote. It is only
+// populated for standard and multi-option votes. ConcurrentVotesExceeded is a
+// warning that is set when the concurrent votes exceed the policy
+// ConcurrentVotesMax.
 type StartReply struct {
 	Receipt          string   `json:"receipt"`
 	StartBlockHash   string   `json:"startblockhash"`
 	StartBlockHeight uint32   `json:"startblockheight"`
 	EndBlockHeight   uint32   `json:"endblockheight"`
 	EligibleTickets  []string `json:"eligibletickets"`
+
+	ConcurrentVotes         uint32 `json:"concurrentvotes,omitempty"`
+	ConcurrentVotesExceeded bool   `json:"concurrentvotesexceeded,omitempty"`
+}
+
+// VoteBitErrorT represents a vote bit validation error.
+type VoteBitErrorT uint32
+
+const (
+	// VoteBitErrorInvalid is an invalid vote bit error.
+	VoteBitErrorInvalid VoteBitErrorT = 0
+
+	// VoteBitErrorNoOptions is returned when the vote does not contain
+	// any vote options.
+	VoteBitErrorNoOptions VoteBitErrorT = 1
+
+	// VoteBitErrorBitZero is returned when a vote option bit is zero.
+	VoteBitErrorBitZero VoteBitErrorT = 2
+
+	// VoteBitErrorBitNotInMask is returned when a vote option bit is
+	// not included in the vote mask.
+	VoteBitErrorBitNotInMask VoteBitErrorT = 3
+
+	// VoteBitErrorBitNotInOptions is returned when a cast vote bit does
+	// not correspond to any of the vote options.
+	VoteBitErrorBitNotInOptions VoteBitErrorT = 4
+
+	// VoteBitErrorBitDuplicate is returned when a vote option bit is
+	// used by more than one vote option.
+	VoteBitErrorBitDuplicate VoteBitErrorT = 5
+
+	// VoteBitErrorLast unit test only.
+	VoteBitErrorLast VoteBitErrorT = 6
+)
+
+var (
+	// VoteBitErrors contains the human readable error messages for the
+	// vote bit errors.
+	VoteBitErrors = map[VoteBitErrorT]string{
+		VoteBitErrorInvalid:         "vote bit error invalid",
+		VoteBitErrorNoOptions:       "no vote options found",
+		VoteBitErrorBitZero:         "bit is zero",
+		VoteBitErrorBitNotInMask:    "bit not included in mask",
+		VoteBitErrorBitNotInOptions: "bit not found in vote options",
+		VoteBitErrorBitDuplicate:    "bit used by another option",
+	}
+)
+
+// VoteBitError identifies the vote option, bit and mask of an invalid vote
+// bit. OptionID is not populated for VoteBitErrorNoOptions errors.
+type VoteBitError struct {
+	ErrorCode VoteBitErrorT `json:"errorcode"`
+	OptionID  string        `json:"optionid,omitempty"`
+	Bit       uint64        `json:"bit"`
+	Mask      uint64        `json:"mask"`
+}
+
+// VoteParamsVerify verifies the provided vote params without starting a vote.
+// The params are subject to the same validation as the params of a Start
+// request, which allows clients to validate the params before the vote is
+// authorized or started. The record token and version are not verified.
+type VoteParamsVerify struct {
+	Params VoteParams `json:"params"`
+}
+
+// VoteParamsVerifyReply is the reply to the VoteParamsVerify command.
+//
+// ErrorCode and ErrorContext contain the first error that a Start request
+// with these params would fail with. ErrorCode is a ticketvote plugin error
+// code, i.e. the same error code that is returned in the PluginErrorReply of a
+// failed Start request. VoteBitErrors contains all of the vote bit errors of
+// the params, even if the first error is not a vote bit error.
+type VoteParamsVerifyReply struct {
+	Valid         bool           `json:"valid"`
+	ErrorCode     uint32         `json:"errorcode,omitempty"`
+	ErrorContext  string         `json:"errorcontext,omitempty"`
+	VoteBitErrors []VoteBitError `json:"votebiterrors,omitempty"`
+}
+
+// Extend extends the end block height of an active record vote. This is
+// intended to be used by admins when stakeholders are prevented from voting,
+// such as during an outage of the services that are used to cast votes. The
+// new end block height must be greater than the current end block height. The
+// total extension of a vote cannot exceed the VoteExtensionMax policy. Runoff
+// votes cannot be extended.
+//
+// Signature is the client signature of the Token+EndBlockHeight+Reason.
+type Extend struct {
+	Token          string `json:"token"`
+	EndBlockHeight uint32 `json:"endblockheight"`
+	Reason         string `json:"reason"`
+	PublicKey      string `json:"publickey"`
+	Signature      string `json:"signature"`
+}
+
+// ExtendReply is the reply to the Extend command.
+//
+// Receipt is the server signature of the client signature. This is proof that
+// the server received and processed the Extend command.
+type ExtendReply struct {
+	Timestamp int64  `json:"timestamp"`
+	Receipt   string `json:"receipt"`
+}
+
+// Cancel cancels a started record vote. This is intended to be used by admins
+// when a vote was started by mistake or with the wrong parameters. A vote can
+// only be cancelled if no ballots have been cast. The vote status is returned
+// to authorized and the vote can be started again. Runoff votes cannot be
+// cancelled.
+//
+// Signature is the client signature of the Token+Reason.
+type Cancel struct {
+	Token     string `json:"token"`
+	Reason    string `json:"reason"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// CancelReply is the reply to the Cancel command.
+//
+// Receipt is the server signature of the client signature. This is proof that
+// the server received and processed the Cancel command.
+type CancelReply struct {
+	Timestamp int64  `json:"timestamp"`
+	Receipt   string `json:"receipt"`
 }
 
 // VoteErrorT represents an error that occurred while attempting to cast a
@@ -446,8 +775,27 @@ type CastVoteReply struct {
 
 	// The follwing fields will only be present if an error occurred
 	// while attempting to cast the vote.
-	ErrorCode    *VoteErrorT `json:"errorcode,omitempty"`
-	ErrorContext string      `json:"errorcontext,omitempty"`
+	ErrorCode    *VoteErrorT      `json:"errorcode,omitempty"`
+	ErrorContext string           `json:"errorcontext,omitempty"`
+	Diagnostics  *VoteDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// VoteDiagnostics contains structured context that can be used to
+// troubleshoot a cast vote that failed validation. Only the fields that are
+// relevant to the vote error are populated.
+//
+// SnapshotHeight is the block height of the eligible ticket snapshot, i.e.
+// the vote start block height. Only tickets that were live at this height
+// are eligible to vote.
+//
+// CommitmentAddr is the largest commitment address of the ticket. A vote must
+// be signed using the private key of this address.
+type VoteDiagnostics struct {
+	Reason         string `json:"reason"`
+	SnapshotHeight uint32 `json:"snapshotheight,omitempty"`
+	EndBlockHeight uint32 `json:"endblockheight,omitempty"`
+	BestBlock      uint32 `json:"bestblock,omitempty"`
+	CommitmentAddr string `json:"commitmentaddr,omitempty"`
 }
 
 // CastBallot casts a ballot of votes. A ballot can only contain the votes for
@@ -457,8 +805,15 @@ type CastBallot struct {
 }
 
 // CastBallotReply is a reply to a batched list of votes.
+//
+// If politeiad is unavailable and the politeiawww write queue is enabled, the
+// ballot is queued and will be submitted once politeiad becomes available.
+// The receipts will be empty and the SubmissionID will be populated in this
+// case. The SubmissionID can be used to retrieve the status of the queued
+// ballot using the SubmissionStatus command.
 type CastBallotReply struct {
-	Receipts []CastVoteReply `json:"receipts"`
+	Receipts     []CastVoteReply `json:"receipts"`
+	SubmissionID string          `json:"submissionid,omitempty"`
 }
 
 // AuthDetails contains the details of a vote authorization.
@@ -472,6 +827,10 @@ type AuthDetails struct {
 	Signature string `json:"signature"` // Client signature
 	Timestamp int64  `json:"timestamp"` // Server timestamp
 	Receipt   string `json:"receipt"`   // Server sig of client sig
+
+	// BlockHeight is the best block height at the time of the action.
+	// It is only populated when the authorization lock is enabled.
+	BlockHeight uint32 `json:"blockheight,omitempty"`
 }
 
 // VoteDetails contains the details of a record vote. A vote details with the
@@ -481,15 +840,27 @@ type AuthDetails struct {
 // VoteParams struct.
 //
 // Receipt is the server signature of ClientSignature+StartBlockHash.
+//
+// EligibleTickets are sorted in ascending order and contain no duplicates.
+// EligibleTicketsDigest is the SHA256 digest of the binary encoded eligible
+// ticket snapshot. It is included in the timestamped vote details data and
+// allows the eligible tickets to be verified against the vote details
+// timestamp. The snapshot encoding is a version byte, which is currently 1,
+// followed by the uvarint encoded ticket count, followed by the hex decoded
+// ticket hashes in ascending byte order. Each ticket hash is encoded as a
+// single byte containing the number of leading bytes shared with the previous
+// hash, followed by the remaining bytes of the hash. The digest is empty for
+// votes that were started prior to snapshots being introduced.
 type VoteDetails struct {
-	Params           VoteParams `json:"params"`
-	PublicKey        string     `json:"publickey"`
-	Signature        string     `json:"signature"`
-	Receipt          string     `json:"receipt"`
-	StartBlockHeight uint32     `json:"startblockheight"`
-	StartBlockHash   string     `json:"startblockhash"`
-	EndBlockHeight   uint32     `json:"endblockheight"`
-	EligibleTickets  []string   `json:"eligibletickets"` // Ticket hashes
+	Params                VoteParams `json:"params"`
+	PublicKey             string     `json:"publickey"`
+	Signature             string     `json:"signature"`
+	Receipt               string     `json:"receipt"`
+	StartBlockHeight      uint32     `json:"startblockheight"`
+	StartBlockHash        string     `json:"startblockhash"`
+	EndBlockHeight        uint32     `json:"endblockheight"`
+	EligibleTickets       []string   `json:"eligibletickets"` // Ticket hashes
+	EligibleTicketsDigest string     `json:"eligibleticketsdigest,omitempty"`
 }
 
 // Details requests the vote details for a record vote.
@@ -499,8 +870,44 @@ type Details struct {
 
 // DetailsReply is the reply to the Details command.
 type DetailsReply struct {
-	Auths []AuthDetails `json:"auths"`
-	Vote  *VoteDetails  `json:"vote"`
+	Auths         []AuthDetails   `json:"auths"`
+	Vote          *VoteDetails    `json:"vote"`
+	Extensions    []ExtendDetails `json:"extensions,omitempty"`
+	Cancellations []CancelDetails `json:"cancellations,omitempty"`
+}
+
+// ExtendDetails contains the details of a vote extension. The vote details
+// end block height includes all vote extensions.
+//
+// Signature is the client signature of the Token+EndBlockHeight+Reason.
+//
+// Receipt is the server signature of the client signature.
+type ExtendDetails struct {
+	Token              string `json:"token"`
+	EndBlockHeight     uint32 `json:"endblockheight"`
+	Reason             string `json:"reason"`
+	PublicKey          string `json:"publickey"`
+	Signature          string `json:"signature"`
+	PrevEndBlockHeight uint32 `json:"prevendblockheight"`
+	Timestamp          int64  `json:"timestamp"`
+	Receipt            string `json:"receipt"`
+}
+
+// CancelDetails contains the details of a vote cancellation. The start and
+// end block heights are the block heights of the vote that was cancelled.
+//
+// Signature is the client signature of the Token+Reason.
+//
+// Receipt is the server signature of the client signature.
+type CancelDetails struct {
+	Token            string `json:"token"`
+	Reason           string `json:"reason"`
+	PublicKey        string `json:"publickey"`
+	Signature        string `json:"signature"`
+	StartBlockHeight uint32 `json:"startblockheight"`
+	EndBlockHeight   uint32 `json:"endblockheight"`
+	Timestamp        int64  `json:"timestamp"`
+	Receipt          string `json:"receipt"`
 }
 
 // CastVoteDetails contains the details of a cast vote.
@@ -516,6 +923,10 @@ type CastVoteDetails struct {
 	Signature string `json:"signature"` // Client signature
 	Receipt   string `json:"receipt"`   // Server sig of client sig
 	Timestamp int64  `json:"timestamp"` // Unix timestamp
+
+	// Stake is the sum of the ticket commitment amounts in atoms. It
+	// is only populated for votes that use VoteWeightStake.
+	Stake uint64 `json:"stake,omitempty"`
 }
 
 // Results returns the cast votes for a record.
@@ -535,6 +946,11 @@ type VoteResult struct {
 	Description string `json:"description"` // Longer description of the vote
 	VoteBit     uint64 `json:"votebit"`     // Bits used for this option
 	Votes       uint64 `json:"votes"`       // Votes cast for this option
+
+	// Stake is the total stake in atoms of the votes that were cast
+	// for this option. It is only populated for votes that use
+	// VoteWeightStake.
+	Stake uint64 `json:"stake,omitempty"`
 }
 
 // Summary summarizes the vote params and results of a record vote.
@@ -560,6 +976,49 @@ type Summary struct {
 
 	Results []VoteResult `json:"results"`
 
+	// WinCondition and WinningOption will only be populated for
+	// multi-option votes. WinningOption is the ID of the vote option
+	// that met the win condition. It is only populated once the vote
+	// has finished and will be empty if no vote option met the win
+	// condition.
+	WinCondition  WinConditionT `json:"wincondition,omitempty"`
+	WinningOption string        `json:"winningoption,omitempty"`
+
+	// Winners is the maximum number of records that can be approved
+	// in the runoff vote. This field will only be populated for runoff
+	// votes.
+	Winners uint32 `json:"winners,omitempty"`
+
+	// Weight and EligibleStake will only be populated for votes that
+	// use VoteWeightStake. EligibleStake is the total stake in atoms of
+	// the eligible tickets. The quorum percentage applies to the
+	// eligible stake and the pass percentage applies to the stake of
+	// the cast votes.
+	Weight        VoteWeightT `json:"weight,omitempty"`
+	EligibleStake uint64      `json:"eligiblestake,omitempty"`
+
+	// FinishedEarly is set when the vote was finished prior to the end
+	// block height because all eligible tickets had voted. This is
+	// only done when the server has early vote finishes enabled.
+	FinishedEarly bool `json:"finishedearly,omitempty"`
+
+	// The following fields are computed from the vote results and will
+	// only be populated once the voting period has been started.
+	//
+	// QuorumPercentageAchieved is the percentage of the eligible
+	// tickets, or of the eligible stake for VoteWeightStake votes, that
+	// has voted.
+	//
+	// ApprovalPercentage is the percentage of the cast votes that voted
+	// to approve. It is only populated for votes that use the approve
+	// and reject vote options.
+	//
+	// RejectionReason is only populated once the vote has been
+	// rejected.
+	QuorumPercentageAchieved float64          `json:"quorumpercentageachieved,omitempty"`
+	ApprovalPercentage       float64          `json:"approvalpercentage,omitempty"`
+	RejectionReason          RejectionReasonT `json:"rejectionreason,omitempty"`
+
 	// BestBlock is the best block value that was used to prepare the
 	// summary.
 	BestBlock uint32 `json:"bestblock"`
@@ -615,13 +1074,22 @@ const (
 // categorized by vote status.
 //
 // The status and page arguments can be provided to request a specific page of
-// record tokens.
+// record tokens. Page 1 is returned if no page is provided.
+//
+// If no status is provided then the requested page of tokens for all statuses
+// will be returned.
 //
-// If no status is provided then a page of tokens for all statuses will be
-// returned. The page argument will be ignored.
+// PageSize is optional. It defaults to the InventoryPageSize policy and cannot
+// exceed it.
+//
+// StartedAfter is an optional block height filter. When provided, only the
+// records whose vote was started after the block height are returned. Records
+// that have not started voting are not included.
 type Inventory struct {
-	Status VoteStatusT `json:"status,omitempty"`
-	Page   uint32      `json:"page,omitempty"`
+	Status       VoteStatusT `json:"status,omitempty"`
+	Page         uint32      `json:"page,omitempty"`
+	PageSize     uint32      `json:"pagesize,omitempty"`
+	StartedAfter uint32      `json:"startedafter,omitempty"`
 }
 
 // InventoryReply is the reply to the Inventory command. The returned map is a
@@ -639,6 +1107,14 @@ type Inventory struct {
 type InventoryReply struct {
 	Vetted map[string][]string `json:"vetted"`
 
+	// Timestamps contains the unix timestamp of when each of the returned
+	// records entered its current vote status. The timestamp of a vote
+	// that has ended is the time that the server processed the block
+	// that ended the vote. Records whose timestamp is not known are not
+	// included. This can happen for records that began voting before
+	// the timestamps were tracked.
+	Timestamps map[string]int64 `json:"timestamps,omitempty"` // [token]timestamp
+
 	// BestBlock is the best block value that was used to prepare the
 	// inventory.
 	BestBlock uint32 `json:"bestblock"`
@@ -688,9 +1164,20 @@ const (
 // If no votes page number is provided then the vote authorization and vote
 // details timestamps will be returned. If a votes page number is provided
 // then the specified page of cast vote timestamps will be returned.
+//
+// VotesPageSize is optional and is capped at the VoteTimestampsPageSize
+// policy, which is also used when a page size is not provided.
+//
+// DigestsOnly omits the data payloads from the returned timestamps. The
+// digests and inclusion proofs are still returned so that clients that
+// already have the data, e.g. the cast votes from the Results route, can
+// verify the inclusion proofs incrementally without downloading the data
+// again.
 type Timestamps struct {
-	Token     string `json:"token"`
-	VotesPage uint32 `json:"votespage,omitempty"`
+	Token         string `json:"token"`
+	VotesPage     uint32 `json:"votespage,omitempty"`
+	VotesPageSize uint32 `json:"votespagesize,omitempty"`
+	DigestsOnly   bool   `json:"digestsonly,omitempty"`
 }
 
 // TimestampsReply is the reply to the Timestamps command.
@@ -706,4 +1193,351 @@ type TimestampsReply struct {
 	// Votes contains the timestamps for the cast votes. The data
 	// payloads will contain CastVoteDetails strucutures.
 	Votes []Timestamp `json:"votes,omitempty"`
+
+	// VotesCount is the total number of cast votes. It is only
+	// populated when a votes page is requested and can be used to
+	// determine the number of votes pages.
+	VotesCount uint32 `json:"votescount,omitempty"`
+}
+
+// Receipts requests the cast vote receipts of the provided tickets for a
+// record vote. The number of tickets that can be requested at any one time is
+// limited by the TimestampsPageSize policy.
+type Receipts struct {
+	Token   string   `json:"token"`
+	Tickets []string `json:"tickets"`
+}
+
+// VoteReceipt contains a cast vote and the timestamp of the cast vote. The
+// data payload of the timestamp contains the JSON encoded CastVoteDetails.
+// Together they prove that the vote was cast by the ticket, that it was
+// received by the server, and that it was timestamped onto the decred
+// blockchain.
+type VoteReceipt struct {
+	Vote      CastVoteDetails `json:"vote"`
+	Timestamp Timestamp       `json:"timestamp"`
+}
+
+// ReceiptsReply is the reply to the Receipts command. Tickets that have not
+// cast a vote are not included in the reply.
+type ReceiptsReply struct {
+	Receipts []VoteReceipt `json:"receipts"`
+}
+
+// Vote requests the cast vote of a single ticket for a record vote. The
+// returned CastVoteDetails includes the server receipt, which can be used to
+// prove that the vote was received by the server. A plugin error is returned
+// if the ticket has not voted.
+type Vote struct {
+	Token  string `json:"token"`
+	Ticket string `json:"ticket"`
+}
+
+// VoteReply is the reply to the Vote command.
+type VoteReply struct {
+	Vote CastVoteDetails `json:"vote"`
+}
+
+// SetVoteDuration sets the min and max vote duration that are allowed for new
+// votes. This allows the vote duration bounds to be changed, e.g. after a
+// parameter change has been approved by governance, without redeploying the
+// server. The bounds are persisted by the server and are returned in the
+// Policy reply. Votes that have already been started are not affected.
+type SetVoteDuration struct {
+	Min uint32 `json:"min"` // In blocks
+	Max uint32 `json:"max"` // In blocks
+}
+
+// SetVoteDurationReply is the reply to the SetVoteDuration command. It
+// contains the vote duration bounds that are now in effect.
+type SetVoteDurationReply struct {
+	Min uint32 `json:"min"` // In blocks
+	Max uint32 `json:"max"` // In blocks
+}
+
+const (
+	// GovernanceExportSchemaVersion is the version of the governance export
+	// schema. The version is incremented whenever a change is made to the
+	// GovernanceExportVote schema that is not backwards compatible. Fields
+	// that are added to the schema do not require a version increment.
+	GovernanceExportSchemaVersion uint32 = 1
+
+	// GovernanceExportFormat is the format of the governance export data.
+	GovernanceExportFormat = "ndjson"
+)
+
+// GovernanceExportResult is the result of a single vote option in the
+// governance export.
+type GovernanceExportResult struct {
+	ID          string `json:"id"`              // Vote option ID
+	Description string `json:"description"`     // Vote option description
+	VoteBit     uint64 `json:"votebit"`         // Bits used for the option
+	Votes       uint64 `json:"votes"`           // Number of votes cast
+	Stake       uint64 `json:"stake,omitempty"` // Stake of the votes in atoms
+}
+
+// GovernanceExportVote is a single finished vote in the governance export.
+// This is the version 1 schema of the governance export. The export contains
+// one JSON encoded GovernanceExportVote per line, ordered by when the vote
+// was finalized.
+//
+// Type, Status, WinCondition, and Weight contain the human readable strings
+// of the VoteTypes, VoteStatuses, WinConditions, and VoteWeights. Status is
+// either approved or rejected for standard and runoff votes and finished for
+// multi-option votes. WinCondition and WinningOption are only populated for
+// multi-option votes, Winners is only populated for runoff votes, and
+// EligibleStake and the option Stake are only populated for votes that are
+// weighted by stake.
+type GovernanceExportVote struct {
+	Token            string                   `json:"token"`
+	Type             string                   `json:"type"`
+	Status           string                   `json:"status"`
+	Duration         uint32                   `json:"duration"` // In blocks
+	StartBlockHeight uint32                   `json:"startblockheight"`
+	StartBlockHash   string                   `json:"startblockhash"`
+	EndBlockHeight   uint32                   `json:"endblockheight"`
+	EligibleTickets  uint32                   `json:"eligibletickets"`
+	QuorumPercentage uint32                   `json:"quorumpercentage"`
+	PassPercentage   uint32                   `json:"passpercentage"`
+	TotalVotes       uint64                   `json:"totalvotes"`
+	Results          []GovernanceExportResult `json:"results"`
+	WinCondition     string                   `json:"wincondition,omitempty"`
+	WinningOption    string                   `json:"winningoption,omitempty"`
+	Winners          uint32                   `json:"winners,omitempty"`
+	Weight           string                   `json:"weight"`
+	EligibleStake    uint64                   `json:"eligiblestake,omitempty"`
+}
+
+// GovernanceExportManifest describes the governance export. Count is the
+// number of votes in the export. Size and SHA256 are the size in bytes and
+// the hex encoded SHA256 digest of the full export data. Updated is the UNIX
+// timestamp of when the export was last updated.
+type GovernanceExportManifest struct {
+	SchemaVersion uint32 `json:"schemaversion"`
+	Format        string `json:"format"`
+	Count         uint32 `json:"count"`
+	Size          int64  `json:"size"`
+	SHA256        string `json:"sha256"`
+	Updated       int64  `json:"updated"`
+}
+
+// GovernanceExport requests the governance export of all finished votes. The
+// export is intended for bulk download, e.g. by researchers, and is updated
+// incrementally as votes finish. New votes are only ever appended to the
+// export, so clients can download the votes that were added since their last
+// download by setting Offset to the number of votes that they have already
+// downloaded.
+type GovernanceExport struct {
+	Offset uint32 `json:"offset,omitempty"`
+}
+
+// GovernanceExportReply is the reply to the GovernanceExport command. Data
+// contains the NDJSON encoded GovernanceExportVotes starting at the requested
+// offset. The manifest describes the full export and can be used to verify a
+// complete download.
+type GovernanceExportReply struct {
+	Manifest GovernanceExportManifest `json:"manifest"`
+	Data     string                   `json:"data"`
+}
+
+// ActiveVotesRebuild rebuilds the active votes cache using the records that
+// have a vote status of VoteStatusStarted. This can be used by admins to
+// repair the cache without restarting the server if it has gotten out of sync
+// with the stored vote data, e.g. after the server was interrupted during a
+// vote start.
+type ActiveVotesRebuild struct{}
+
+// ActiveVotesRebuildReply is the reply to the ActiveVotesRebuild command.
+// Tokens contains the records in the rebuilt cache. Added and Removed
+// contain the records that were missing from or stale in the previous cache.
+type ActiveVotesRebuildReply struct {
+	Tokens  []string `json:"tokens"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// SnapshotVerify requests that the eligible ticket snapshot of a record vote
+// be re-verified. The server re-fetches the ticket pool at the snapshot block
+// of the vote and compares it against the stored eligible tickets. The signed
+// verification report can be provided to auditors.
+type SnapshotVerify struct {
+	Token string `json:"token"`
+}
+
+// SnapshotVerification is a verification report of the eligible ticket
+// snapshot of a record vote.
+//
+// SnapshotDigest is the SHA256 digest of the stored snapshot and ChainDigest
+// is the SHA256 digest of the ticket pool that was fetched from the chain.
+// The snapshot matches the chain when the digests are equal. Missing contains
+// the tickets that are in the chain ticket pool but not in the snapshot. Extra
+// contains the tickets that are in the snapshot but not in the chain ticket
+// pool.
+//
+// Receipt is the server signature of the
+// Token+StartBlockHash+SnapshotDigest+ChainDigest+Timestamp.
+type SnapshotVerification struct {
+	Token            string   `json:"token"`
+	StartBlockHeight uint32   `json:"startblockheight"`
+	StartBlockHash   string   `json:"startblockhash"`
+	SnapshotCount    uint32   `json:"snapshotcount"`
+	SnapshotDigest   string   `json:"snapshotdigest"`
+	ChainCount       uint32   `json:"chaincount"`
+	ChainDigest      string   `json:"chaindigest"`
+	Match            bool     `json:"match"`
+	Missing          []string `json:"missing"`
+	Extra            []string `json:"extra"`
+	Timestamp        int64    `json:"timestamp"`
+	Receipt          string   `json:"receipt"`
+}
+
+// SnapshotVerifyReply is the reply to the SnapshotVerify command.
+type SnapshotVerifyReply struct {
+	Verification SnapshotVerification `json:"verification"`
+}
+
+// Stats requests the turnout and participation statistics of a finished record
+// vote. The statistics are computed by the server so that clients do not need
+// to retrieve all of the cast votes.
+type Stats struct {
+	Token string `json:"token"`
+}
+
+// StatsDay contains the cast vote statistics of a single day. Day is the unix
+// timestamp of the start of the UTC day. Votes is the number of votes that
+// were cast during the day. Approve, Reject, and Approval are cumulative and
+// include all votes that were cast up until the end of the day.
+type StatsDay struct {
+	Day      int64   `json:"day"`
+	Votes    uint32  `json:"votes"`
+	Approve  uint32  `json:"approve"`
+	Reject   uint32  `json:"reject"`
+	Approval float64 `json:"approval"` // Percentage of approve votes
+}
+
+// StatsReply is the reply to the Stats command.
+//
+// UniqueTickets is the number of eligible tickets that cast a vote. Turnout is
+// the percentage of eligible tickets that cast a vote. Approval is the
+// percentage of cast votes that voted for the VoteOptionIDApprove option. The
+// approval statistics are only populated for votes that use the
+// VoteOptionIDApprove and VoteOptionIDReject vote options.
+type StatsReply struct {
+	Status          VoteStatusT `json:"status"`
+	EligibleTickets uint32      `json:"eligibletickets"`
+	UniqueTickets   uint32      `json:"uniquetickets"`
+	Turnout         float64     `json:"turnout"`
+	Approval        float64     `json:"approval"`
+	Days            []StatsDay  `json:"days"`
+}
+
+// SubmissionStatusT represents the status of a submission that was queued
+// because politeiad was unavailable.
+type SubmissionStatusT uint32
+
+const (
+	// SubmissionStatusInvalid is an invalid submission status.
+	SubmissionStatusInvalid SubmissionStatusT = 0
+
+	// SubmissionStatusPending indicates that the submission has been
+	// persisted by politeiawww and is waiting to be sent to politeiad.
+	SubmissionStatusPending SubmissionStatusT = 1
+
+	// SubmissionStatusComplete indicates that the submission was
+	// successfully sent to politeiad. The reply will be populated.
+	SubmissionStatusComplete SubmissionStatusT = 2
+
+	// SubmissionStatusFailed indicates that politeiad rejected the
+	// submission or that it expired before politeiad became available.
+	// The error will be populated.
+	SubmissionStatusFailed SubmissionStatusT = 3
+)
+
+var (
+	// SubmissionStatuses contains the human readable submission
+	// statuses.
+	SubmissionStatuses = map[SubmissionStatusT]string{
+		SubmissionStatusInvalid:  "invalid",
+		SubmissionStatusPending:  "pending",
+		SubmissionStatusComplete: "complete",
+		SubmissionStatusFailed:   "failed",
+	}
+)
+
+// SubmissionStatus requests the status of a submission that was queued
+// because politeiad was unavailable. The submission ID is returned in the
+// reply of the queued command.
+type SubmissionStatus struct {
+	SubmissionID string `json:"submissionid"`
+}
+
+// SubmissionStatusReply is the reply to the SubmissionStatus command.
+// Attempts is the number of times that politeiawww has attempted to send the
+// submission to politeiad.
+type SubmissionStatusReply struct {
+	Status    SubmissionStatusT `json:"status"`
+	Timestamp int64             `json:"timestamp"` // Unix time of submission
+	Attempts  uint32            `json:"attempts"`
+	Reply     *CastBallotReply  `json:"reply,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+const (
+	// AuditBundleVersion is the version of the VoteAuditBundle format.
+	AuditBundleVersion uint32 = 1
+)
+
+// AuditBundle requests the audit bundle of a finished record vote. The audit
+// bundle contains all of the data that is required to verify a vote end-to-end
+// offline, without having to query the server.
+type AuditBundle struct {
+	Token string `json:"token"`
+}
+
+// AuditBundleTimestamps contains the timestamps of the vote data that is
+// included in an audit bundle. The timestamps contain the tlog inclusion
+// proofs and the DCR transaction that the tlog merkle root was anchored in.
+//
+// Snapshot is the timestamp of the eligible ticket snapshot. It is only
+// populated for votes that save the eligible tickets separately from the
+// vote details. The snapshot data is binary and is not included in the
+// timestamp. The timestamp digest is the EligibleTicketsDigest of the vote
+// details.
+type AuditBundleTimestamps struct {
+	Auths    []Timestamp `json:"auths"`
+	Details  Timestamp   `json:"details"`
+	Snapshot *Timestamp  `json:"snapshot,omitempty"`
+	Votes    []Timestamp `json:"votes"`
+}
+
+// VoteAuditBundle contains all of the data of a finished record vote.
+//
+// Details includes the eligible tickets of the vote. Votes contains all of the
+// cast votes, sorted by ticket hash. Anchored is true when all of the
+// timestamps have been anchored onto the DCR blockchain and contain a full
+// inclusion proof.
+type VoteAuditBundle struct {
+	Version    uint32                `json:"version"`
+	Token      string                `json:"token"`
+	Summary    Summary               `json:"summary"`
+	Auths      []AuthDetails         `json:"auths"`
+	Details    VoteDetails           `json:"details"`
+	Votes      []CastVoteDetails     `json:"votes"`
+	Timestamps AuditBundleTimestamps `json:"timestamps"`
+	Anchored   bool                  `json:"anchored"`
+	Timestamp  int64                 `json:"timestamp"` // Created at
+}
+
+// AuditBundleReply is the reply to the AuditBundle command.
+//
+// Bundle is the JSON encoded VoteAuditBundle. It is returned exactly as it
+// was signed by the politeiad server. Digest is the hex encoded SHA256 digest
+// of the Bundle. Signature is the politeiad server signature of the Digest.
+// PublicKey is the politeiad server public key that can be used to verify the
+// signature.
+type AuditBundleReply struct {
+	Bundle    string `json:"bundle"` // JSON encoded VoteAuditBundle
+	Digest    string `json:"digest"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
 }
@@ -18,6 +18,7 @@ type UserManageActionT int
 type EmailNotificationT int
 type VoteT int
 type TOTPMethodT int
+type PushNotificationT int
 
 const (
 	PoliteiaWWWAPIVersion = 1 // API version this backend understands
@@ -25,34 +26,68 @@ const (
 	CsrfToken = "X-CSRF-Token"    // CSRF token for replies
 	Forward   = "X-Forwarded-For" // Proxy header
 
-	RouteVersion                  = "/version"
-	RoutePolicy                   = "/policy"
-	RouteSecret                   = "/secret"
-	RouteLogin                    = "/login"
-	RouteLogout                   = "/logout"
-	RouteUserMe                   = "/user/me"
-	RouteNewUser                  = "/user/new"
-	RouteResendVerification       = "/user/new/resend"
-	RouteVerifyNewUser            = "/user/verify"
-	RouteEditUser                 = "/user/edit"
-	RouteUpdateUserKey            = "/user/key"
-	RouteVerifyUpdateUserKey      = "/user/key/verify"
-	RouteChangeUsername           = "/user/username/change"
-	RouteChangePassword           = "/user/password/change"
-	RouteResetPassword            = "/user/password/reset"
-	RouteVerifyResetPassword      = "/user/password/reset/verify"
-	RouteUserRegistrationPayment  = "/user/payments/registration"
-	RouteUserProposalPaywall      = "/user/payments/paywall"
-	RouteUserProposalPaywallTx    = "/user/payments/paywalltx"
-	RouteUserProposalCredits      = "/user/payments/credits"
-	RouteUserPaymentsRescan       = "/user/payments/rescan"
-	RouteManageUser               = "/user/manage"
-	RouteSetTOTP                  = "/user/totp"
-	RouteVerifyTOTP               = "/user/verifytotp"
-	RouteUserDetails              = "/user/{userid:[0-9a-zA-Z-]{36}}"
-	RouteUsers                    = "/users"
-	RouteUnauthenticatedWebSocket = "/ws"
-	RouteAuthenticatedWebSocket   = "/aws"
+	// The following headers are set on the replies of deprecated routes
+	// and of routes that contain deprecated fields. The Sunset header
+	// is only set once a removal date has been scheduled and contains
+	// the earliest removal date. The DeprecationNotices header contains
+	// the JSON encoded []DeprecationNotice that apply to the route.
+	HeaderDeprecation        = "Deprecation"
+	HeaderSunset             = "Sunset"
+	HeaderDeprecationNotices = "X-Deprecation-Notices"
+
+	// QueryExpand is the URL query parameter that can be used to expand
+	// fields of the v1 API replies. Setting it to ExpandEnums replaces the
+	// numeric record, vote, and proposal status codes in the replies of
+	// the records, ticketvote, comments, and pi APIs with Enum objects.
+	QueryExpand = "expand"
+	ExpandEnums = "enums"
+
+	RouteVersion                     = "/version"
+	RoutePolicy                      = "/policy"
+	RouteSecret                      = "/secret"
+	RouteLogin                       = "/login"
+	RouteLogout                      = "/logout"
+	RouteUserMe                      = "/user/me"
+	RouteNewUser                     = "/user/new"
+	RouteResendVerification          = "/user/new/resend"
+	RouteVerifyNewUser               = "/user/verify"
+	RouteEditUser                    = "/user/edit"
+	RouteUpdateUserKey               = "/user/key"
+	RouteVerifyUpdateUserKey         = "/user/key/verify"
+	RouteRevokeUserKey               = "/user/key/revoke"
+	RouteChangeUsername              = "/user/username/change"
+	RouteChangePassword              = "/user/password/change"
+	RouteChangeEmail                 = "/user/email/change"
+	RouteVerifyChangeEmail           = "/user/email/change/verify"
+	RouteResetPassword               = "/user/password/reset"
+	RouteVerifyResetPassword         = "/user/password/reset/verify"
+	RouteUserRegistrationPayment     = "/user/payments/registration"
+	RouteUserProposalPaywall         = "/user/payments/paywall"
+	RouteUserProposalPaywallTx       = "/user/payments/paywalltx"
+	RouteUserProposalCredits         = "/user/payments/credits"
+	RouteUserProposalCreditsTransfer = "/user/payments/credits/transfer"
+	RouteUserPaymentsRescan          = "/user/payments/rescan"
+	RouteManageUser                  = "/user/manage"
+	RouteSetTOTP                     = "/user/totp"
+	RouteVerifyTOTP                  = "/user/verifytotp"
+	RoutePushSubscriptions           = "/user/push"
+	RoutePushSubscribe               = "/user/push/subscribe"
+	RoutePushUnsubscribe             = "/user/push/unsubscribe"
+	RouteUserUsage                   = "/user/usage"
+	RouteUserDetails                 = "/user/{userid:[0-9a-zA-Z-]{36}}"
+	RouteUsers                       = "/users"
+	RouteUsersUsage                  = "/users/usage"
+	RouteUnauthenticatedWebSocket    = "/ws"
+	RouteAuthenticatedWebSocket      = "/aws"
+	RoutePendingActions              = "/admin/pendingactions"
+	RoutePendingActionConfirm        = "/admin/pendingactions/confirm"
+	RoutePendingActionReject         = "/admin/pendingactions/reject"
+	RouteDeprecations                = "/deprecations"
+	RouteChanges                     = "/changes"
+	RouteStatus                      = "/status"
+	RouteBanners                     = "/admin/banners"
+	RouteBannerSet                   = "/admin/banners/set"
+	RouteBannerDel                   = "/admin/banners/del"
 
 	// The following routes have been DEPRECATED.
 	RouteTokenInventory   = "/proposals/tokeninventory"
@@ -83,9 +118,10 @@ const (
 	VerificationTokenSize = 32
 
 	// TokenPrefixLength is the length of the token prefix that can
-	// be used in RouteProposalDetails. This should match what is defined
-	// in politeiad, and once a Policy route is created in politeiad,
-	// this should be removed.
+	// be used in RouteProposalDetails. This is the minimum token prefix
+	// length of politeiad. politeiad resolves prefixes of this length
+	// even when it has been configured to use a longer prefix length,
+	// as long as the prefix belongs to a single record.
 	TokenPrefixLength = 7
 
 	// VerificationExpiryHours is the number of hours before the
@@ -223,7 +259,20 @@ const (
 	ErrorStatusTOTPInvalidType             ErrorStatusT = 78
 	ErrorStatusRequiresTOTPCode            ErrorStatusT = 79
 	ErrorStatusTOTPWaitForNewCode          ErrorStatusT = 80
-	ErrorStatusLast                        ErrorStatusT = 81
+	ErrorStatusPendingActionNotFound       ErrorStatusT = 81
+	ErrorStatusPendingActionResolved       ErrorStatusT = 82
+	ErrorStatusPendingActionExpired        ErrorStatusT = 83
+	ErrorStatusPendingActionSameAdmin      ErrorStatusT = 84
+	ErrorStatusDuplicateEmail              ErrorStatusT = 85
+	ErrorStatusInvalidPushSubscription     ErrorStatusT = 86
+	ErrorStatusPushSubscriptionNotFound    ErrorStatusT = 87
+	ErrorStatusIdentityNotFound            ErrorStatusT = 88
+	ErrorStatusIdentityLimitReached        ErrorStatusT = 89
+	ErrorStatusLastActiveIdentity          ErrorStatusT = 90
+	ErrorStatusInvalidIdentityLabel        ErrorStatusT = 91
+	ErrorStatusInvalidBanner               ErrorStatusT = 92
+	ErrorStatusBannerNotFound              ErrorStatusT = 93
+	ErrorStatusLast                        ErrorStatusT = 94
 
 	// Proposal state codes
 	//
@@ -302,9 +351,21 @@ const (
 	NotificationEmailCommentOnMyProposal         EmailNotificationT = 1 << 7
 	NotificationEmailCommentOnMyComment          EmailNotificationT = 1 << 8
 
+	// Web push notification types
+	NotificationPushVoteStarted  PushNotificationT = 1 << 0
+	NotificationPushCommentReply PushNotificationT = 1 << 1
+
 	// Time-base one time password types
 	TOTPTypeInvalid TOTPMethodT = 0 // Invalid TOTP type
 	TOTPTypeBasic   TOTPMethodT = 1
+
+	// Error param keys. These keys are used in the UserError params of
+	// errors that are caused by input that violates a policy, allowing
+	// clients to translate the error without parsing the error context.
+	ErrorParamField = "field" // Name of the invalid field
+	ErrorParamMin   = "min"   // Minimum allowed value or length
+	ErrorParamMax   = "max"   // Maximum allowed value or length
+	ErrorParamGot   = "got"   // Value or length provided by the user
 )
 
 var (
@@ -409,6 +470,19 @@ var (
 		ErrorStatusTOTPInvalidType:             "invalid totp type",
 		ErrorStatusRequiresTOTPCode:            "login requires totp code",
 		ErrorStatusTOTPWaitForNewCode:          "must wait until next totp code window",
+		ErrorStatusPendingActionNotFound:       "pending action not found",
+		ErrorStatusPendingActionResolved:       "pending action has already been resolved",
+		ErrorStatusPendingActionExpired:        "pending action has expired",
+		ErrorStatusPendingActionSameAdmin:      "pending action must be confirmed by a different admin",
+		ErrorStatusDuplicateEmail:              "email address is already in use",
+		ErrorStatusInvalidPushSubscription:     "invalid push subscription",
+		ErrorStatusPushSubscriptionNotFound:    "push subscription not found",
+		ErrorStatusIdentityNotFound:            "identity not found",
+		ErrorStatusIdentityLimitReached:        "active identity limit reached",
+		ErrorStatusLastActiveIdentity:          "cannot revoke the last active identity",
+		ErrorStatusInvalidIdentityLabel:        "invalid identity label",
+		ErrorStatusInvalidBanner:               "invalid banner",
+		ErrorStatusBannerNotFound:              "banner not found",
 	}
 
 	// PropStatus converts propsal status codes to human readable text
@@ -554,9 +628,13 @@ type ProposalCredit struct {
 
 // UserError represents an error that is caused by something that the user
 // did (malformed input, bad timing, etc).
+//
+// ErrorParams is optional and contains the values that the user input was
+// validated against. See the ErrorParam keys for the possible params.
 type UserError struct {
 	ErrorCode    ErrorStatusT
 	ErrorContext []string
+	ErrorParams  map[string]string `json:",omitempty"`
 }
 
 // Error satisfies the error interface.
@@ -635,8 +713,16 @@ type ResendVerificationReply struct {
 }
 
 // UpdateUserKey is used to request a new active key.
+//
+// By default the new key replaces all of the user's existing active keys. If
+// Additional is set then the new key is added alongside the existing active
+// keys once it has been verified, e.g. to register a key from a second device.
+// Signatures made with any active key are accepted. Label is an optional,
+// human readable description of the key.
 type UpdateUserKey struct {
-	PublicKey string `json:"publickey"`
+	PublicKey  string `json:"publickey"`
+	Label      string `json:"label,omitempty"`
+	Additional bool   `json:"additional,omitempty"`
 }
 
 // UpdateUserKeyReply replies to the UpdateUserKey command.
@@ -653,6 +739,16 @@ type VerifyUpdateUserKey struct {
 // VerifyUpdateUserKeyReply replies to the VerifyUpdateUserKey command.
 type VerifyUpdateUserKeyReply struct{}
 
+// RevokeUserKey is used to revoke one of the user's active keys. The last
+// active key of a user cannot be revoked. Use UpdateUserKey to replace it
+// instead.
+type RevokeUserKey struct {
+	PublicKey string `json:"publickey"`
+}
+
+// RevokeUserKeyReply replies to the RevokeUserKey command.
+type RevokeUserKeyReply struct{}
+
 // ChangeUsername is used to perform a username change while the user
 // is logged in.
 type ChangeUsername struct {
@@ -675,6 +771,36 @@ type ChangePassword struct {
 // is logged in.
 type ChangePasswordReply struct{}
 
+// ChangeEmail is used to request an email address change while the user is
+// logged in. A verification token is sent to both the current and the new
+// email address. The email address is only updated once both tokens have been
+// verified using the VerifyChangeEmail command. The tokens expire after
+// VerificationExpiryHours.
+type ChangeEmail struct {
+	Password string `json:"password"`
+	NewEmail string `json:"newemail"`
+}
+
+// ChangeEmailReply is the reply to the ChangeEmail command. The verification
+// tokens are only returned when the server does not have email enabled.
+type ChangeEmailReply struct {
+	OldEmailVerificationToken string `json:"oldemailverificationtoken,omitempty"`
+	NewEmailVerificationToken string `json:"newemailverificationtoken,omitempty"`
+}
+
+// VerifyChangeEmail is used to verify one of the verification tokens that was
+// sent during the ChangeEmail command.
+type VerifyChangeEmail struct {
+	VerificationToken string `json:"verificationtoken"`
+}
+
+// VerifyChangeEmailReply is the reply to the VerifyChangeEmail command.
+// Completed is set to true once both verification tokens have been verified
+// and the user's email address has been updated.
+type VerifyChangeEmailReply struct {
+	Completed bool `json:"completed"`
+}
+
 // ResetPassword is used to perform a password change when the user is not
 // logged in. If the username and email address match the user record in the
 // database then a reset password verification token will be email to the user.
@@ -764,6 +890,7 @@ type LoginReply struct {
 	Email              string `json:"email"`              // User email
 	Username           string `json:"username"`           // Username
 	PublicKey          string `json:"publickey"`          // Active public key
+	ActiveKeys         uint32 `json:"activekeys"`         // Number of active public keys
 	PaywallAddress     string `json:"paywalladdress"`     // Registration paywall address
 	PaywallAmount      uint64 `json:"paywallamount"`      // Registration paywall amount in atoms
 	PaywallTxNotBefore int64  `json:"paywalltxnotbefore"` // Minimum timestamp for paywall tx
@@ -835,8 +962,44 @@ type UserProposalCredits struct{}
 // It contains unspent credits that the user purchased but did not yet use, and
 // the credits the user already spent to submit proposals.
 type UserProposalCreditsReply struct {
-	UnspentCredits []ProposalCredit `json:"unspentcredits"`
-	SpentCredits   []ProposalCredit `json:"spentcredits"`
+	UnspentCredits []ProposalCredit         `json:"unspentcredits"`
+	SpentCredits   []ProposalCredit         `json:"spentcredits"`
+	Transfers      []ProposalCreditTransfer `json:"transfers,omitempty"`
+	Refunds        []ProposalCreditRefund   `json:"refunds,omitempty"`
+}
+
+// ProposalCreditRefund is a proposal credit ledger entry that records the
+// refund of a spent proposal credit. A credit is refunded when the author
+// withdraws the proposal that the credit was spent on.
+type ProposalCreditRefund struct {
+	Token     string         `json:"token"` // Token of the withdrawn proposal
+	Credit    ProposalCredit `json:"credit"`
+	Timestamp int64          `json:"timestamp"` // Unix timestamp
+}
+
+// ProposalCreditTransfer is a proposal credit ledger entry that records the
+// transfer of unspent proposal credits from one user to another.
+type ProposalCreditTransfer struct {
+	FromUserID   string `json:"fromuserid"`
+	FromUsername string `json:"fromusername"`
+	ToUserID     string `json:"touserid"`
+	ToUsername   string `json:"tousername"`
+	NumCredits   uint64 `json:"numcredits"`
+	Timestamp    int64  `json:"timestamp"` // Unix timestamp
+}
+
+// UserProposalCreditsTransfer transfers unspent proposal credits from the
+// logged in user to the user with the provided username. Credits are
+// transferred FIFO. The recipient is notified of the transfer by email.
+type UserProposalCreditsTransfer struct {
+	Username   string `json:"username"`   // Recipient username
+	NumCredits uint64 `json:"numcredits"` // Number of credits to transfer
+}
+
+// UserProposalCreditsTransferReply is used to reply to the
+// UserProposalCreditsTransfer command.
+type UserProposalCreditsTransferReply struct {
+	Transfer ProposalCreditTransfer `json:"transfer"`
 }
 
 // UserPaymentsRescan allows an admin to rescan a user's paywall address to
@@ -979,6 +1142,38 @@ type GetAllVettedReply struct {
 	Proposals []ProposalRecord `json:"proposals"`
 }
 
+// DeprecationNotice describes a deprecated API route or a deprecated field of
+// an API route. Route is the full route, including the API version prefix,
+// as it is registered on the router. Field is the JSON or query param name of
+// the deprecated field and is empty when the entire route is deprecated.
+// Sunset is the date that the route or field is scheduled to be removed on,
+// formatted as YYYY-MM-DD. It is empty if a removal date has not been
+// scheduled yet. Replacement is the route or field that should be used
+// instead.
+type DeprecationNotice struct {
+	Route       string `json:"route"`
+	Field       string `json:"field,omitempty"`
+	Sunset      string `json:"sunset,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Enum is the expanded representation of a status code. It is returned in
+// place of the numeric status code when the ExpandEnums query param is set.
+// Code is the numeric status code. ID is a stable string identifier of the
+// status that does not change between API versions, e.g. "public".
+type Enum struct {
+	Code uint32 `json:"code"`
+	ID   string `json:"id"`
+}
+
+// Deprecations requests the deprecation notices of all API routes.
+type Deprecations struct{}
+
+// DeprecationsReply is the reply to the Deprecations command.
+type DeprecationsReply struct {
+	Notices []DeprecationNotice `json:"notices"`
+}
+
 // Policy returns a struct with various maxima.  The client shall observe the
 // maxima.
 type Policy struct{}
@@ -1333,7 +1528,107 @@ type ManageUser struct {
 }
 
 // ManageUserReply is the reply for the ManageUserReply command.
-type ManageUserReply struct{}
+//
+// PendingActionID is only populated when the action requires the confirmation
+// of a second admin. The action has not been executed in this case. See the
+// PendingActionConfirm command.
+type ManageUserReply struct {
+	PendingActionID string `json:"pendingactionid,omitempty"`
+}
+
+// PendingActionT represents the type of a destructive admin action that
+// requires the confirmation of a second admin.
+type PendingActionT int
+
+const (
+	// PendingActionInvalid is an invalid pending action type.
+	PendingActionInvalid PendingActionT = 0
+
+	// PendingActionCensorRecord censors a vetted record. The payload is
+	// a JSON encoded records v1 SetStatus.
+	PendingActionCensorRecord PendingActionT = 1
+
+	// PendingActionDeactivateUser deactivates a user. The payload is a
+	// JSON encoded ManageUser.
+	PendingActionDeactivateUser PendingActionT = 2
+)
+
+// PendingActionStatusT represents the status of a pending admin action.
+type PendingActionStatusT int
+
+const (
+	// PendingActionStatusInvalid is an invalid pending action status.
+	PendingActionStatusInvalid PendingActionStatusT = 0
+
+	// PendingActionStatusPending indicates that the action is awaiting
+	// the confirmation of a second admin.
+	PendingActionStatusPending PendingActionStatusT = 1
+
+	// PendingActionStatusConfirmed indicates that the action was
+	// confirmed by a second admin and has been executed.
+	PendingActionStatusConfirmed PendingActionStatusT = 2
+
+	// PendingActionStatusRejected indicates that the action was rejected
+	// by an admin and was not executed.
+	PendingActionStatusRejected PendingActionStatusT = 3
+
+	// PendingActionStatusExpired indicates that the action was not
+	// confirmed within the confirmation window and can no longer be
+	// confirmed.
+	PendingActionStatusExpired PendingActionStatusT = 4
+)
+
+// PendingAction is a destructive admin action that requires the confirmation
+// of a second admin before it is executed. Censoring a vetted record and
+// deactivating a user require a second admin's confirmation when the server
+// has been configured to do so.
+type PendingAction struct {
+	ID          string               `json:"id"`
+	Type        PendingActionT       `json:"type"`
+	Status      PendingActionStatusT `json:"status"`
+	Payload     string               `json:"payload"`     // JSON encoded request
+	RequestedBy string               `json:"requestedby"` // Admin user ID
+	ResolvedBy  string               `json:"resolvedby,omitempty"`
+	CreatedAt   int64                `json:"createdat"`  // Unix timestamp
+	ExpiresAt   int64                `json:"expiresat"`  // Unix timestamp
+	ResolvedAt  int64                `json:"resolvedat"` // Unix timestamp
+}
+
+// PendingActions retrieves the pending admin actions. Actions that are
+// awaiting confirmation are returned by default. The Status field can be used
+// to retrieve the actions of a different status.
+type PendingActions struct {
+	Status PendingActionStatusT `json:"status,omitempty"`
+}
+
+// PendingActionsReply is the reply to the PendingActions command. The actions
+// are sorted from oldest to newest.
+type PendingActionsReply struct {
+	PendingActions []PendingAction `json:"pendingactions"`
+}
+
+// PendingActionConfirm confirms and executes a pending admin action. The
+// action must be confirmed by an admin other than the admin that requested
+// it.
+type PendingActionConfirm struct {
+	ID string `json:"id"`
+}
+
+// PendingActionConfirmReply is the reply to the PendingActionConfirm command.
+type PendingActionConfirmReply struct {
+	PendingAction PendingAction `json:"pendingaction"`
+}
+
+// PendingActionReject rejects a pending admin action. Any admin, including
+// the admin that requested the action, can reject it.
+type PendingActionReject struct {
+	ID string `json:"id"`
+}
+
+// PendingActionRejectReply is the reply to the PendingActionReject command.
+type PendingActionRejectReply struct {
+	PendingAction PendingAction `json:"pendingaction"`
+}
 
 // EditUser edits a user's preferences.
 type EditUser struct {
@@ -1367,12 +1662,24 @@ type User struct {
 	Identities                      []UserIdentity `json:"identities"`
 	ProposalCredits                 uint64         `json:"proposalcredits"`
 	EmailNotifications              uint64         `json:"emailnotifications"` // Notify the user via emails
+	ChangeEmailAddress              string         `json:"changeemailaddress,omitempty"`
+	ChangeEmailVerificationExpiry   int64          `json:"changeemailverificationexpiry,omitempty"`
+	EmailChanges                    []EmailChange  `json:"emailchanges,omitempty"`
+}
+
+// EmailChange is an audit log entry of a user email address change.
+type EmailChange struct {
+	Action    string `json:"action"` // Human readable action
+	OldEmail  string `json:"oldemail"`
+	NewEmail  string `json:"newemail"`
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
 }
 
 // UserIdentity represents a user's unique identity.
 type UserIdentity struct {
 	Pubkey string `json:"pubkey"`
 	Active bool   `json:"isactive"`
+	Label  string `json:"label,omitempty"`
 }
 
 // EditProposal attempts to edit a proposal
@@ -1495,3 +1802,268 @@ type VerifyTOTP struct {
 // with no errors.
 type VerifyTOTPReply struct {
 }
+
+// PushSubscription is a web push subscription of a browser client. The
+// Endpoint, P256dh and Auth fields are the values returned by the browser's
+// PushManager.subscribe() call. The keys are base64url encoded.
+// Notifications is a bit field of the PushNotificationT types that the
+// subscription should receive.
+type PushSubscription struct {
+	Endpoint      string `json:"endpoint"`
+	P256dh        string `json:"p256dh"`
+	Auth          string `json:"auth"`
+	Notifications uint64 `json:"notifications"`
+	Timestamp     int64  `json:"timestamp"` // Unix timestamp of creation
+}
+
+// PushSubscriptions retrieves the web push subscriptions of the logged in
+// user.
+type PushSubscriptions struct{}
+
+// PushSubscriptionsReply is the reply to the PushSubscriptions command.
+// PublicKey is the base64url encoded VAPID public key of the server. It
+// must be provided as the applicationServerKey when creating a browser
+// push subscription.
+type PushSubscriptionsReply struct {
+	PublicKey     string             `json:"publickey"`
+	Subscriptions []PushSubscription `json:"subscriptions"`
+}
+
+// PushSubscribe adds a web push subscription to the logged in user. If a
+// subscription with the same endpoint already exists it is replaced.
+type PushSubscribe struct {
+	Endpoint      string `json:"endpoint"`
+	P256dh        string `json:"p256dh"`
+	Auth          string `json:"auth"`
+	Notifications uint64 `json:"notifications"`
+}
+
+// PushSubscribeReply is the reply to the PushSubscribe command.
+type PushSubscribeReply struct{}
+
+// PushUnsubscribe removes the web push subscription with the provided
+// endpoint from the logged in user.
+type PushUnsubscribe struct {
+	Endpoint string `json:"endpoint"`
+}
+
+// PushUnsubscribeReply is the reply to the PushUnsubscribe command.
+type PushUnsubscribeReply struct{}
+
+// PushMessage is the JSON payload of a web push notification that is
+// delivered to the service worker of a browser client.
+type PushMessage struct {
+	Type  PushNotificationT `json:"type"`
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	URL   string            `json:"url"` // GUI link
+}
+
+// RouteUsage contains the API request counts of a route. Route is the full
+// route template, e.g. "/v1/user/me". Errors is the number of requests that
+// were replied to with a 4xx or 5xx status code.
+type RouteUsage struct {
+	Route    string `json:"route"`
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+}
+
+// DayUsage contains the API request counts of a user for a single UTC day.
+type DayUsage struct {
+	Date     string       `json:"date"` // YYYY-MM-DD
+	Requests uint64       `json:"requests"`
+	Errors   uint64       `json:"errors"`
+	Routes   []RouteUsage `json:"routes"`
+}
+
+// UserUsage retrieves the daily API request counts of the logged in user.
+// Only the requests that were made while logged in are counted.
+type UserUsage struct{}
+
+// UserUsageReply is the reply to the UserUsage command. Retention is the
+// number of days that request counts are kept for. The days are sorted by
+// date in ascending order.
+type UserUsageReply struct {
+	Retention uint32     `json:"retention"`
+	Days      []DayUsage `json:"days"`
+}
+
+// UsersUsage retrieves the aggregate API request counts of all users over the
+// provided number of days, including the current UTC day. The retention
+// window is used if Days is not provided. The daily request counts of a
+// specific user are also returned when a UserID is provided.
+type UsersUsage struct {
+	Days   uint32 `json:"days"`
+	UserID string `json:"userid,omitempty"`
+}
+
+// UserUsageSummary contains the API request counts of a user.
+type UserUsageSummary struct {
+	UserID   string `json:"userid"`
+	Username string `json:"username"`
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+}
+
+// UsersUsageReply is the reply to the UsersUsage command. From and To are the
+// first and last UTC dates of the aggregate, inclusive. UserCount is the
+// number of users that made requests during this period. Users contains the
+// users with the most requests and Routes contains all routes, both sorted by
+// request count in descending order. UserDays contains the daily request
+// counts of the requested user.
+type UsersUsageReply struct {
+	Retention uint32             `json:"retention"`
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	Requests  uint64             `json:"requests"`
+	Errors    uint64             `json:"errors"`
+	UserCount uint32             `json:"usercount"`
+	Users     []UserUsageSummary `json:"users"`
+	Routes    []RouteUsage       `json:"routes"`
+	UserDays  []DayUsage         `json:"userdays,omitempty"`
+}
+
+const (
+	// ChangesPageSize is the maximum number of changes that are returned
+	// in a single Changes reply.
+	ChangesPageSize uint32 = 500
+)
+
+// Change is a single change to the public record inventory. Sequence is a
+// monotonically increasing sequence number that is assigned by the server.
+// Type is one of "recordnew", "recordedit", "recordstatus", "commentnew" or
+// "votes". Version and Status are set for record changes, CommentID is set for
+// new comments and Votes is the number of votes that were successfully cast
+// for vote changes.
+type Change struct {
+	Sequence  uint64 `json:"sequence"`
+	Type      string `json:"type"`
+	Token     string `json:"token"`
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
+	Version   uint32 `json:"version,omitempty"`
+	Status    uint32 `json:"status,omitempty"`
+	CommentID uint32 `json:"commentid,omitempty"`
+	Votes     uint32 `json:"votes,omitempty"`
+}
+
+// Changes retrieves the changes to the public record inventory that were made
+// after the provided sequence number, ordered by sequence number. At most
+// ChangesPageSize changes are returned. Clients sync incrementally by
+// requesting the changes since the most recent sequence number that they have
+// processed.
+type Changes struct {
+	Since uint64 `schema:"since"`
+}
+
+// ChangesReply is the reply to the Changes command. Oldest is the sequence
+// number of the oldest change that is still kept by the server and Latest is
+// the sequence number of the most recent change. Resync is set when changes
+// that were made after the requested sequence number are no longer kept, or
+// when the requested sequence number is greater than the latest sequence
+// number. The client must re-crawl the inventory when Resync is set and then
+// continue syncing from Latest.
+type ChangesReply struct {
+	Changes []Change `json:"changes"`
+	Oldest  uint64   `json:"oldest"`
+	Latest  uint64   `json:"latest"`
+	Resync  bool     `json:"resync"`
+}
+
+// BannerSeverityT represents the severity of a site-wide banner.
+type BannerSeverityT int
+
+const (
+	// BannerSeverityInvalid is an invalid banner severity.
+	BannerSeverityInvalid BannerSeverityT = 0
+
+	// BannerSeverityInfo is used for general announcements, e.g. a
+	// reminder that a vote is about to end.
+	BannerSeverityInfo BannerSeverityT = 1
+
+	// BannerSeverityWarning is used for announcements that may affect
+	// users, e.g. scheduled maintenance.
+	BannerSeverityWarning BannerSeverityT = 2
+
+	// BannerSeverityCritical is used for announcements that require
+	// the immediate attention of users, e.g. an ongoing outage.
+	BannerSeverityCritical BannerSeverityT = 3
+
+	// BannerSeverityLast unit test only.
+	BannerSeverityLast BannerSeverityT = 4
+)
+
+var (
+	// BannerSeverities contains the human readable banner severities.
+	BannerSeverities = map[BannerSeverityT]string{
+		BannerSeverityInvalid:  "invalid",
+		BannerSeverityInfo:     "info",
+		BannerSeverityWarning:  "warning",
+		BannerSeverityCritical: "critical",
+	}
+)
+
+const (
+	// BannerMessageMaxLength is the maximum number of characters that a
+	// banner message can contain.
+	BannerMessageMaxLength = 500
+)
+
+// Banner is a site-wide announcement that is published by an admin. A banner
+// is displayed from its StartsAt timestamp until its EndsAt timestamp. A
+// banner with an EndsAt of 0 is displayed until it is deleted.
+type Banner struct {
+	ID        string          `json:"id"`
+	Message   string          `json:"message"`
+	Severity  BannerSeverityT `json:"severity"`
+	StartsAt  int64           `json:"startsat"`         // Unix timestamp
+	EndsAt    int64           `json:"endsat,omitempty"` // Unix timestamp
+	CreatedBy string          `json:"createdby"`        // Admin user ID
+	CreatedAt int64           `json:"createdat"`        // Unix timestamp
+	UpdatedAt int64           `json:"updatedat"`        // Unix timestamp
+}
+
+// Status retrieves the current status of the server. It is intended to be
+// polled by clients and only returns a small amount of data.
+type Status struct{}
+
+// StatusReply is the reply to the Status command. Banners contains the
+// banners that are currently being displayed, sorted by severity from most to
+// least severe. Timestamp is the current server time.
+type StatusReply struct {
+	Banners   []Banner `json:"banners"`
+	Timestamp int64    `json:"timestamp"` // Unix timestamp
+}
+
+// Banners retrieves all banners, including the banners that are scheduled to
+// be displayed in the future and the banners that have ended.
+type Banners struct{}
+
+// BannersReply is the reply to the Banners command. The banners are sorted by
+// StartsAt from oldest to newest.
+type BannersReply struct {
+	Banners []Banner `json:"banners"`
+}
+
+// BannerSet creates or updates a banner. A new banner is created when an ID is
+// not provided. The existing banner is updated when an ID is provided. The
+// banner is displayed immediately when StartsAt is not provided.
+type BannerSet struct {
+	ID       string          `json:"id,omitempty"`
+	Message  string          `json:"message"`
+	Severity BannerSeverityT `json:"severity"`
+	StartsAt int64           `json:"startsat,omitempty"` // Unix timestamp
+	EndsAt   int64           `json:"endsat,omitempty"`   // Unix timestamp
+}
+
+// BannerSetReply is the reply to the BannerSet command.
+type BannerSetReply struct {
+	Banner Banner `json:"banner"`
+}
+
+// BannerDel deletes a banner.
+type BannerDel struct {
+	ID string `json:"id"`
+}
+
+// BannerDelReply is the reply to the BannerDel command.
+type BannerDelReply struct{}
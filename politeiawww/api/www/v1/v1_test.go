@@ -27,4 +27,9 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("UserManageAction: %v", err)
 	}
+	err = unittest.TestGenericConstMap(BannerSeverities,
+		uint64(BannerSeverityLast))
+	if err != nil {
+		t.Fatalf("BannerSeverities: %v", err)
+	}
 }
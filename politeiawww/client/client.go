@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"reflect"
 
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	"github.com/decred/politeia/util"
 	"github.com/gorilla/schema"
 	"golang.org/x/net/publicsuffix"
@@ -30,8 +31,13 @@ type Client struct {
 	verbose    bool
 	rawJSON    bool
 	http       *http.Client
+	deprecated DeprecatedFunc
 }
 
+// DeprecatedFunc is called when politeiawww replies with deprecation headers,
+// i.e. when the requested route or one of its fields has been deprecated.
+type DeprecatedFunc func(route string, notices []www.DeprecationNotice)
+
 // makeReq makes a politeiawww http request to the method and route provided,
 // serializing the provided object as the request body, and returning a byte
 // slice of the response body. An ReqError is returned if politeiawww responds
@@ -133,6 +139,22 @@ func (c *Client) makeReq(method string, api, route string, v interface{}) ([]byt
 		}
 	}
 
+	// Handle deprecation notices
+	notices, err := DeprecationNotices(r.Header)
+	if err != nil {
+		return nil, err
+	}
+	if len(notices) > 0 {
+		switch {
+		case c.deprecated != nil:
+			c.deprecated(api+route, notices)
+		case c.verbose:
+			for _, v := range notices {
+				fmt.Printf("Deprecated: %+v\n", v)
+			}
+		}
+	}
+
 	// Decode response body
 	respBody := util.RespBody(r)
 
@@ -158,6 +180,11 @@ type Opts struct {
 	HeaderCSRF string
 	Verbose    bool // Print verbose output
 	RawJSON    bool // Print raw json
+
+	// Deprecated is called with the deprecation notices of a reply. The
+	// notices are printed when verbose output is enabled and this func
+	// is not provided.
+	Deprecated DeprecatedFunc
 }
 
 // New returns a new politeiawww client.
@@ -191,5 +218,23 @@ func New(host string, opts Opts) (*Client, error) {
 		verbose:    opts.Verbose,
 		rawJSON:    opts.RawJSON,
 		http:       h,
+		deprecated: opts.Deprecated,
 	}, nil
 }
+
+// DeprecationNotices returns the deprecation notices that are contained in
+// the provided politeiawww reply headers. Nil is returned if the reply does
+// not contain any deprecation notices.
+func DeprecationNotices(h http.Header) ([]www.DeprecationNotice, error) {
+	v := h.Get(www.HeaderDeprecationNotices)
+	if v == "" {
+		return nil, nil
+	}
+	var notices []www.DeprecationNotice
+	err := json.Unmarshal([]byte(v), &notices)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v header: %v",
+			www.HeaderDeprecationNotices, err)
+	}
+	return notices, nil
+}
@@ -5,12 +5,15 @@
 package client
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
+	cmplugin "github.com/decred/politeia/politeiad/plugins/comments"
 	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
 	"github.com/decred/politeia/util"
 )
@@ -83,6 +86,57 @@ func (c *Client) CommentVote(v cmv1.Vote) (*cmv1.VoteReply, error) {
 	return &vr, nil
 }
 
+// CommentSubscribe sends a comments v1 Subscribe request to politeiawww.
+func (c *Client) CommentSubscribe(s cmv1.Subscribe) (*cmv1.SubscribeReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteSubscribe, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var sr cmv1.SubscribeReply
+	err = json.Unmarshal(resBody, &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+// CommentPin sends a comments v1 Pin request to politeiawww.
+func (c *Client) CommentPin(p cmv1.Pin) (*cmv1.PinReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RoutePin, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr cmv1.PinReply
+	err = json.Unmarshal(resBody, &pr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// CommentStakeProof sends a comments v1 StakeProof request to politeiawww.
+func (c *Client) CommentStakeProof(sp cmv1.StakeProof) (*cmv1.StakeProofReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteStakeProof, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	var spr cmv1.StakeProofReply
+	err = json.Unmarshal(resBody, &spr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spr, nil
+}
+
 // CommentDel sends a comments v1 Del request to politeiawww.
 func (c *Client) CommentDel(d cmv1.Del) (*cmv1.DelReply, error) {
 	resBody, err := c.makeReq(http.MethodPost,
@@ -100,6 +154,23 @@ func (c *Client) CommentDel(d cmv1.Del) (*cmv1.DelReply, error) {
 	return &dr, nil
 }
 
+// CommentDelUser sends a comments v1 DelUser request to politeiawww.
+func (c *Client) CommentDelUser(d cmv1.DelUser) (*cmv1.DelUserReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteDelUser, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var dr cmv1.DelUserReply
+	err = json.Unmarshal(resBody, &dr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dr, nil
+}
+
 // CommentCount sends a comments v1 Count request to politeiawww.
 func (c *Client) CommentCount(cc cmv1.Count) (*cmv1.CountReply, error) {
 	resBody, err := c.makeReq(http.MethodPost,
@@ -151,6 +222,92 @@ func (c *Client) CommentVotes(v cmv1.Votes) (*cmv1.VotesReply, error) {
 	return &vr, nil
 }
 
+// CommentsUser sends a comments v1 UserComments request to politeiawww.
+func (c *Client) CommentsUser(uc cmv1.UserComments) (*cmv1.UserCommentsReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteUserComments, uc)
+	if err != nil {
+		return nil, err
+	}
+
+	var ucr cmv1.UserCommentsReply
+	err = json.Unmarshal(resBody, &ucr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ucr, nil
+}
+
+// CommentTree sends a comments v1 Tree request to politeiawww.
+func (c *Client) CommentTree(t cmv1.Tree) (*cmv1.TreeReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteTree, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var tr cmv1.TreeReply
+	err = json.Unmarshal(resBody, &tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tr, nil
+}
+
+// CommentAttachments sends a comments v1 Attachments request to
+// politeiawww.
+func (c *Client) CommentAttachments(a cmv1.Attachments) (*cmv1.AttachmentsReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteAttachments, a)
+	if err != nil {
+		return nil, err
+	}
+
+	var ar cmv1.AttachmentsReply
+	err = json.Unmarshal(resBody, &ar)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ar, nil
+}
+
+// CommentSearch sends a comments v1 Search request to politeiawww.
+func (c *Client) CommentSearch(s cmv1.Search) (*cmv1.SearchReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteSearch, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var sr cmv1.SearchReply
+	err = json.Unmarshal(resBody, &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+// CommentHistory sends a comments v1 History request to politeiawww.
+func (c *Client) CommentHistory(h cmv1.History) (*cmv1.HistoryReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteHistory, h)
+	if err != nil {
+		return nil, err
+	}
+
+	var hr cmv1.HistoryReply
+	err = json.Unmarshal(resBody, &hr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hr, nil
+}
+
 // CommentTimestamps sends a comments v1 Timestamps request to politeiawww.
 func (c *Client) CommentTimestamps(t cmv1.Timestamps) (*cmv1.TimestampsReply, error) {
 	resBody, err := c.makeReq(http.MethodPost,
@@ -168,6 +325,42 @@ func (c *Client) CommentTimestamps(t cmv1.Timestamps) (*cmv1.TimestampsReply, er
 	return &tr, nil
 }
 
+// CommentTimestampsBatch sends a comments v1 TimestampsBatch request to
+// politeiawww.
+func (c *Client) CommentTimestampsBatch(t cmv1.TimestampsBatch) (*cmv1.TimestampsReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteTimestampsBatch, t)
+	if err != nil {
+		return nil, err
+	}
+
+	var tr cmv1.TimestampsReply
+	err = json.Unmarshal(resBody, &tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tr, nil
+}
+
+// CommentSubmissionStatus sends a comments v1 SubmissionStatus request to
+// politeiawww.
+func (c *Client) CommentSubmissionStatus(s cmv1.SubmissionStatus) (*cmv1.SubmissionStatusReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		cmv1.APIRoute, cmv1.RouteSubmissionStatus, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var ssr cmv1.SubmissionStatusReply
+	err = json.Unmarshal(resBody, &ssr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssr, nil
+}
+
 // commentDelVerify verifies the signature of a comment that has been deleted.
 // The signature will be from the deletion event, not the original comment
 // submission.
@@ -177,9 +370,13 @@ func commentDelVerify(c cmv1.Comment, serverPublicKey string) error {
 	}
 
 	// Verify delete action. The deletion signature is of the
-	// State+Token+CommentID+Reason.
+	// State+Token+CommentID+Reason. The deletion signature of a
+	// bulk delete is of the UserID+Reason.
 	msg := strconv.FormatUint(uint64(c.State), 10) + c.Token +
 		strconv.FormatUint(uint64(c.CommentID), 10) + c.Reason
+	if c.BulkDeleted {
+		msg = c.UserID + c.Reason
+	}
 	err := util.VerifySignature(c.Signature, c.PublicKey, msg)
 	if err != nil {
 		return fmt.Errorf("unable to verify comment %v del signature: %v",
@@ -197,15 +394,42 @@ func commentDelVerify(c cmv1.Comment, serverPublicKey string) error {
 	return nil
 }
 
+// CommentAttachmentsMsg returns the portion of a comment signature message
+// that corresponds to the comment attachments, i.e. the attachment digests
+// concatenated in order.
+func CommentAttachmentsMsg(as []cmv1.Attachment) string {
+	var msg string
+	for _, v := range as {
+		msg += v.Digest
+	}
+	return msg
+}
+
+// CommentAttachmentVerify verifies that the attachment payload matches the
+// attachment digest.
+func CommentAttachmentVerify(a cmv1.Attachment) error {
+	b, err := base64.StdEncoding.DecodeString(a.Payload)
+	if err != nil {
+		return fmt.Errorf("attachment %v: invalid payload: %v", a.Name, err)
+	}
+	d := hex.EncodeToString(util.Digest(b))
+	if d != a.Digest {
+		return fmt.Errorf("attachment %v: digest mismatch; got %v, want %v",
+			a.Name, d, a.Digest)
+	}
+	return nil
+}
+
 // CommentEditVerify verifies the edited comment signature and receipt.
 func CommentEditVerify(c cmv1.Comment, serverPublicKey string) error {
 	// Verify comment. The signature is the client signature of the:
 	// State + Token + ParentID + CommentID + Comment +
-	// ExtraData + ExtraDataHint.
+	// ExtraData + ExtraDataHint + Attachment digests.
 	msg := strconv.FormatUint(uint64(c.State), 10) + c.Token +
 		strconv.FormatUint(uint64(c.ParentID), 10) +
 		strconv.FormatUint(uint64(c.CommentID), 10) +
-		c.Comment + c.ExtraData + c.ExtraDataHint
+		c.Comment + c.ExtraData + c.ExtraDataHint +
+		CommentAttachmentsMsg(c.Attachments)
 	err := util.VerifySignature(c.Signature, c.PublicKey, msg)
 	if err != nil {
 		return fmt.Errorf("unable to verify edited comment %v signature: %v",
@@ -231,10 +455,11 @@ func CommentVerify(c cmv1.Comment, serverPublicKey string) error {
 	}
 
 	// Verify comment. The signature is the client signature of the
-	// State + Token + ParentID + Comment + ExtraData + ExtraDataHint.
+	// State + Token + ParentID + Comment + ExtraData + ExtraDataHint +
+	// Attachment digests.
 	msg := strconv.FormatUint(uint64(c.State), 10) + c.Token +
 		strconv.FormatUint(uint64(c.ParentID), 10) + c.Comment +
-		c.ExtraData + c.ExtraDataHint
+		c.ExtraData + c.ExtraDataHint + CommentAttachmentsMsg(c.Attachments)
 	err := util.VerifySignature(c.Signature, c.PublicKey, msg)
 	if err != nil {
 		return fmt.Errorf("unable to verify comment %v signature: %v",
@@ -300,6 +525,92 @@ func CommentTimestampsVerify(tr cmv1.TimestampsReply) ([]uint32, error) {
 	return notTimestamped, nil
 }
 
+// CommentTimestampVerifyComment verifies that the provided CommentTimestamp
+// is a valid timestamp of the provided comment. The timestamped data payload
+// must correspond to the comment, i.e. the most recent comment add for a
+// comment and the comment del for a deleted comment, and the timestamp proofs
+// must be valid. backend.ErrNotTimestamped is returned if the comment has not
+// been anchored onto the DCR blockchain yet.
+func CommentTimestampVerifyComment(c cmv1.Comment, ct cmv1.CommentTimestamp) error {
+	// Verify that the timestamped data payload is the comment
+	var (
+		ts        cmv1.Timestamp
+		signature string
+		commentID uint32
+		token     string
+	)
+	switch {
+	case c.Deleted:
+		if ct.Del == nil {
+			return fmt.Errorf("comment %v del timestamp not found", c.CommentID)
+		}
+		var cd cmplugin.CommentDel
+		err := json.Unmarshal([]byte(ct.Del.Data), &cd)
+		if err != nil {
+			return fmt.Errorf("unable to decode comment %v del timestamp "+
+				"data: %v", c.CommentID, err)
+		}
+		ts = *ct.Del
+		signature = cd.Signature
+		commentID = cd.CommentID
+		token = cd.Token
+
+	default:
+		if len(ct.Adds) == 0 {
+			return fmt.Errorf("comment %v add timestamp not found", c.CommentID)
+		}
+		var ca cmplugin.CommentAdd
+		ts = ct.Adds[len(ct.Adds)-1]
+		err := json.Unmarshal([]byte(ts.Data), &ca)
+		if err != nil {
+			return fmt.Errorf("unable to decode comment %v add timestamp "+
+				"data: %v", c.CommentID, err)
+		}
+		signature = ca.Signature
+		commentID = ca.CommentID
+		token = ca.Token
+	}
+	if commentID != c.CommentID || token != c.Token ||
+		signature != c.Signature {
+		return fmt.Errorf("comment %v timestamp data does not match comment",
+			c.CommentID)
+	}
+
+	// Verify the timestamp proofs
+	err := backend.VerifyTimestamp(convertCommentTimestamp(ts))
+	if err != nil {
+		if err == backend.ErrNotTimestamped {
+			return err
+		}
+		return fmt.Errorf("unable to verify comment %v timestamp: %v",
+			c.CommentID, err)
+	}
+
+	return nil
+}
+
+// CommentsTimestampsVerify verifies that the provided timestamps are valid
+// timestamps of the provided comments. Every comment must have a timestamp.
+// The IDs of comments that have not been anchored yet are returned.
+func CommentsTimestampsVerify(comments []cmv1.Comment, timestamps map[uint32]cmv1.CommentTimestamp) ([]uint32, error) {
+	notTimestamped := make([]uint32, 0, len(comments))
+	for _, c := range comments {
+		ct, ok := timestamps[c.CommentID]
+		if !ok {
+			return nil, fmt.Errorf("comment %v timestamp not found", c.CommentID)
+		}
+		err := CommentTimestampVerifyComment(c, ct)
+		if err != nil {
+			if err == backend.ErrNotTimestamped {
+				notTimestamped = append(notTimestamped, c.CommentID)
+				continue
+			}
+			return nil, err
+		}
+	}
+	return notTimestamped, nil
+}
+
 func convertCommentProof(p cmv1.Proof) backend.Proof {
 	return backend.Proof{
 		Type:       p.Type,
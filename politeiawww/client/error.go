@@ -25,6 +25,7 @@ type ErrorReply struct {
 	PluginID     string
 	ErrorCode    int
 	ErrorContext string
+	ErrorParams  map[string]string
 }
 
 // RespErr represents a politeiawww response error. A RespErr is returned
@@ -9,9 +9,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
 	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	"github.com/decred/politeia/util"
 )
 
 // PiPolicy sends a pi v1 Policy request to politeiawww.
@@ -66,6 +68,23 @@ func (c *Client) PiSummaries(s piv1.Summaries) (*piv1.SummariesReply, error) {
 	return &sr, nil
 }
 
+// PiProposalList sends a pi v1 ProposalList request to politeiawww.
+func (c *Client) PiProposalList(pl piv1.ProposalList) (*piv1.ProposalListReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		piv1.APIRoute, piv1.RouteProposalList, pl)
+	if err != nil {
+		return nil, err
+	}
+
+	var plr piv1.ProposalListReply
+	err = json.Unmarshal(resBody, &plr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plr, nil
+}
+
 // PiBillingStatusChanges sends a pi v1 BillingStatusChanges request to
 // politeiawww.
 func (c *Client) PiBillingStatusChanges(bscs piv1.BillingStatusChanges) (*piv1.BillingStatusChangesReply, error) {
@@ -84,6 +103,75 @@ func (c *Client) PiBillingStatusChanges(bscs piv1.BillingStatusChanges) (*piv1.B
 	return &bscsr, nil
 }
 
+// PiIdentity sends a pi v1 Identity request to politeiawww.
+func (c *Client) PiIdentity() (*piv1.IdentityReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		piv1.APIRoute, piv1.RouteIdentity, piv1.Identity{})
+	if err != nil {
+		return nil, err
+	}
+
+	var ir piv1.IdentityReply
+	err = json.Unmarshal(resBody, &ir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ir, nil
+}
+
+// PiCompletionReport sends a pi v1 CompletionReport request to politeiawww.
+func (c *Client) PiCompletionReport(cr piv1.CompletionReport) (*piv1.CompletionReportReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		piv1.APIRoute, piv1.RouteCompletionReport, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	var crr piv1.CompletionReportReply
+	err = json.Unmarshal(resBody, &crr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crr, nil
+}
+
+// PiWithdraw sends a pi v1 Withdraw request to politeiawww.
+func (c *Client) PiWithdraw(w piv1.Withdraw) (*piv1.WithdrawReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		piv1.APIRoute, piv1.RouteWithdraw, w)
+	if err != nil {
+		return nil, err
+	}
+
+	var wr piv1.WithdrawReply
+	err = json.Unmarshal(resBody, &wr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wr, nil
+}
+
+// PiWithdrawalDetails sends a pi v1 WithdrawalDetails request to
+// politeiawww.
+func (c *Client) PiWithdrawalDetails(wd piv1.WithdrawalDetails) (*piv1.WithdrawalDetailsReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		piv1.APIRoute, piv1.RouteWithdrawal, wd)
+	if err != nil {
+		return nil, err
+	}
+
+	var wdr piv1.WithdrawalDetailsReply
+	err = json.Unmarshal(resBody, &wdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wdr, nil
+}
+
 // ProposalMetadataDecode decodes and returns the ProposalMetadata from the
 // Provided record files. An error returned if a ProposalMetadata is not found.
 func ProposalMetadataDecode(files []rcv1.File) (*piv1.ProposalMetadata, error) {
@@ -132,3 +220,72 @@ func VoteMetadataDecode(files []rcv1.File) (*piv1.VoteMetadata, error) {
 	}
 	return vmp, nil
 }
+
+// BillingStatusChangeVerify verifies the signature and receipt of the
+// provided pi v1 BillingStatusChange. The signature is the admin signature of
+// the Token+Status+Reason+ReportDigest. The ReportDigest is empty when a
+// completion report was not included. The receipt is the server signature of
+// the admin signature.
+func BillingStatusChangeVerify(bsc piv1.BillingStatusChange, serverPublicKey string) error {
+	// Verify signature
+	msg := bsc.Token + strconv.FormatUint(uint64(bsc.Status), 10) +
+		bsc.Reason + bsc.ReportDigest
+	err := util.VerifySignature(bsc.Signature, bsc.PublicKey, msg)
+	if err != nil {
+		return fmt.Errorf("verify signature: %v", err)
+	}
+
+	// Verify receipt
+	err = util.VerifySignature(bsc.Receipt, serverPublicKey, bsc.Signature)
+	if err != nil {
+		return fmt.Errorf("verify receipt: %v", err)
+	}
+
+	return nil
+}
+
+// BillingStatusChangesVerify verifies the signatures and receipts of the
+// provided pi v1 BillingStatusChanges.
+func BillingStatusChangesVerify(bscs []piv1.BillingStatusChange, serverPublicKey string) error {
+	for i, v := range bscs {
+		err := BillingStatusChangeVerify(v, serverPublicKey)
+		if err != nil {
+			return fmt.Errorf("billing status change %v: %v", i, err)
+		}
+	}
+	return nil
+}
+
+var (
+	// billingStatusTransitions contains the allowed billing status
+	// transitions. This mirrors the billing status transitions that are
+	// enforced by the pi plugin.
+	billingStatusTransitions = map[piv1.BillingStatusT]map[piv1.BillingStatusT]struct{}{
+		// Active to...
+		piv1.BillingStatusActive: {
+			piv1.BillingStatusClosed:    {},
+			piv1.BillingStatusCompleted: {},
+		},
+		// Closed to...
+		piv1.BillingStatusClosed: {
+			piv1.BillingStatusActive:    {},
+			piv1.BillingStatusCompleted: {},
+		},
+		// Completed to...
+		piv1.BillingStatusCompleted: {
+			piv1.BillingStatusActive: {},
+			piv1.BillingStatusClosed: {},
+		},
+	}
+)
+
+// BillingStatusTransitionVerify verifies that a proposal is allowed to
+// transition from one billing status to another. The billing status of an
+// approved proposal starts as active.
+func BillingStatusTransitionVerify(from, to piv1.BillingStatusT) error {
+	if _, ok := billingStatusTransitions[from][to]; !ok {
+		return fmt.Errorf("invalid billing status transition %v to %v",
+			piv1.BillingStatuses[from], piv1.BillingStatuses[to])
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"testing"
+
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+)
+
+func TestBillingStatusTransitionVerify(t *testing.T) {
+	var tests = []struct {
+		name    string
+		from    piv1.BillingStatusT
+		to      piv1.BillingStatusT
+		wantErr bool
+	}{
+		{"active to closed", piv1.BillingStatusActive,
+			piv1.BillingStatusClosed, false},
+		{"active to completed", piv1.BillingStatusActive,
+			piv1.BillingStatusCompleted, false},
+		{"closed to active", piv1.BillingStatusClosed,
+			piv1.BillingStatusActive, false},
+		{"completed to closed", piv1.BillingStatusCompleted,
+			piv1.BillingStatusClosed, false},
+		{"active to active", piv1.BillingStatusActive,
+			piv1.BillingStatusActive, true},
+		{"active to invalid", piv1.BillingStatusActive,
+			piv1.BillingStatusInvalid, true},
+		{"invalid to active", piv1.BillingStatusInvalid,
+			piv1.BillingStatusActive, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := BillingStatusTransitionVerify(tc.from, tc.to)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+		})
+	}
+}
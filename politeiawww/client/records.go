@@ -405,6 +405,27 @@ func StatusChangesDecode(metadata []v1.MetadataStream) ([]v1.StatusChange, error
 	return statuses, nil
 }
 
+// ProvenanceDecode decodes and returns the Provenance from the provided
+// metadata streams. An error IS NOT returned if provenance metadata is not
+// found. Nil is returned for records that were not migrated from another
+// system.
+func ProvenanceDecode(ms []v1.MetadataStream) (*v1.Provenance, error) {
+	for _, v := range ms {
+		if v.PluginID != usermd.PluginID ||
+			v.StreamID != usermd.StreamIDProvenance {
+			// Not provenance metadata
+			continue
+		}
+		var p v1.Provenance
+		err := json.Unmarshal([]byte(v.Payload), &p)
+		if err != nil {
+			return nil, err
+		}
+		return &p, nil
+	}
+	return nil, nil
+}
+
 // StatusChanges verifies the signatures on all status change metadata.
 func StatusChangesVerify(sc []v1.StatusChange) error {
 	// Verify signatures
@@ -5,15 +5,19 @@
 package client
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	backend "github.com/decred/politeia/politeiad/backendv2"
+	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
 	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
 	"github.com/decred/politeia/util"
 )
@@ -69,6 +73,40 @@ func (c *Client) TicketVoteStart(s tkv1.Start) (*tkv1.StartReply, error) {
 	return &sr, nil
 }
 
+// TicketVoteExtend sends a ticketvote v1 Extend request to politeiawww.
+func (c *Client) TicketVoteExtend(e tkv1.Extend) (*tkv1.ExtendReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteExtend, e)
+	if err != nil {
+		return nil, err
+	}
+
+	var er tkv1.ExtendReply
+	err = json.Unmarshal(resBody, &er)
+	if err != nil {
+		return nil, err
+	}
+
+	return &er, nil
+}
+
+// TicketVoteCancel sends a ticketvote v1 Cancel request to politeiawww.
+func (c *Client) TicketVoteCancel(tc tkv1.Cancel) (*tkv1.CancelReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteCancel, tc)
+	if err != nil {
+		return nil, err
+	}
+
+	var cr tkv1.CancelReply
+	err = json.Unmarshal(resBody, &cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cr, nil
+}
+
 // TicketVoteCastBallot sends a ticketvote v1 CastBallot request to
 // politeiawww.
 func (c *Client) TicketVoteCastBallot(cb tkv1.CastBallot) (*tkv1.CastBallotReply, error) {
@@ -191,6 +229,129 @@ func (c *Client) TicketVoteTimestamps(t tkv1.Timestamps) (*tkv1.TimestampsReply,
 	return &tr, nil
 }
 
+// TicketVoteReceipts sends a ticketvote v1 Receipts request to politeiawww.
+func (c *Client) TicketVoteReceipts(r tkv1.Receipts) (*tkv1.ReceiptsReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteReceipts, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rr tkv1.ReceiptsReply
+	err = json.Unmarshal(resBody, &rr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rr, nil
+}
+
+// TicketVoteActiveVotesRebuild sends a ticketvote v1 ActiveVotesRebuild
+// request to politeiawww.
+func (c *Client) TicketVoteActiveVotesRebuild(avr tkv1.ActiveVotesRebuild) (*tkv1.ActiveVotesRebuildReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteActiveVotesRebuild, avr)
+	if err != nil {
+		return nil, err
+	}
+
+	var avrr tkv1.ActiveVotesRebuildReply
+	err = json.Unmarshal(resBody, &avrr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &avrr, nil
+}
+
+// TicketVoteSetVoteDuration sends a ticketvote v1 SetVoteDuration request to
+// politeiawww.
+func (c *Client) TicketVoteSetVoteDuration(svd tkv1.SetVoteDuration) (*tkv1.SetVoteDurationReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteSetVoteDuration, svd)
+	if err != nil {
+		return nil, err
+	}
+
+	var svdr tkv1.SetVoteDurationReply
+	err = json.Unmarshal(resBody, &svdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &svdr, nil
+}
+
+// TicketVoteSnapshotVerify sends a ticketvote v1 SnapshotVerify request to
+// politeiawww.
+func (c *Client) TicketVoteSnapshotVerify(sv tkv1.SnapshotVerify) (*tkv1.SnapshotVerifyReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteSnapshotVerify, sv)
+	if err != nil {
+		return nil, err
+	}
+
+	var svr tkv1.SnapshotVerifyReply
+	err = json.Unmarshal(resBody, &svr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &svr, nil
+}
+
+// TicketVoteStats sends a ticketvote v1 Stats request to politeiawww.
+func (c *Client) TicketVoteStats(s tkv1.Stats) (*tkv1.StatsReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteStats, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var sr tkv1.StatsReply
+	err = json.Unmarshal(resBody, &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
+// TicketVoteSubmissionStatus sends a ticketvote v1 SubmissionStatus request
+// to politeiawww.
+func (c *Client) TicketVoteSubmissionStatus(s tkv1.SubmissionStatus) (*tkv1.SubmissionStatusReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteSubmissionStatus, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var ssr tkv1.SubmissionStatusReply
+	err = json.Unmarshal(resBody, &ssr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ssr, nil
+}
+
+// TicketVoteVote sends a ticketvote v1 Vote request to politeiawww.
+func (c *Client) TicketVoteVote(v tkv1.Vote) (*tkv1.VoteReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteVote, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var vr tkv1.VoteReply
+	err = json.Unmarshal(resBody, &vr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vr, nil
+}
+
 // TicketVoteTimestampVerify verifies that the provided ticketvote v1 Timestamp
 // is valid.
 func TicketVoteTimestampVerify(t tkv1.Timestamp) error {
@@ -288,6 +449,69 @@ func VoteDetailsVerify(vd tkv1.VoteDetails, serverPublicKey string) error {
 	return nil
 }
 
+// EligibleTicketsVerify verifies that the eligible tickets of the provided
+// ticketvote v1 VoteDetails match the eligible tickets digest. Vote details
+// of votes that were started prior to the digest being introduced do not have
+// a digest and are not verified.
+func EligibleTicketsVerify(vd tkv1.VoteDetails) error {
+	if vd.EligibleTicketsDigest == "" {
+		return nil
+	}
+	b, err := eligibleTicketsEncode(vd.EligibleTickets)
+	if err != nil {
+		return err
+	}
+	digest := hex.EncodeToString(util.Digest(b))
+	if digest != vd.EligibleTicketsDigest {
+		return fmt.Errorf("eligible tickets digest mismatch: got %v, want %v",
+			digest, vd.EligibleTicketsDigest)
+	}
+	return nil
+}
+
+// eligibleTicketsEncode returns the binary encoded eligible ticket snapshot
+// of the provided tickets. See the ticketvote v1 VoteDetails for a description
+// of the encoding.
+func eligibleTicketsEncode(tickets []string) ([]byte, error) {
+	hashes := make([][]byte, 0, len(tickets))
+	for _, v := range tickets {
+		h, err := hex.DecodeString(v)
+		if err != nil || len(h) != 32 {
+			return nil, fmt.Errorf("invalid ticket %v", v)
+		}
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i], hashes[j]) < 0
+	})
+
+	// Remove duplicates
+	deduped := hashes[:0]
+	for i, v := range hashes {
+		if i > 0 && bytes.Equal(v, hashes[i-1]) {
+			continue
+		}
+		deduped = append(deduped, v)
+	}
+
+	b := make([]byte, 0, 1+binary.MaxVarintLen64+len(deduped)*32)
+	b = append(b, 1) // Snapshot version
+	b = binary.AppendUvarint(b, uint64(len(deduped)))
+	for i, v := range deduped {
+		var shared int
+		if i > 0 {
+			prev := deduped[i-1]
+			for shared < len(v)-1 && v[shared] == prev[shared] {
+				shared++
+			}
+		}
+		b = append(b, byte(shared))
+		b = append(b, v[shared:]...)
+	}
+
+	return b, nil
+}
+
 // CastVoteDetailsVerify verifies the receipt of the provided ticketvote v1
 // CastVoteDetails.
 func CastVoteDetailsVerify(cvd tkv1.CastVoteDetails, serverPublicKey string) error {
@@ -333,6 +557,144 @@ func CastVoteDetailsVerify(cvd tkv1.CastVoteDetails, serverPublicKey string) err
 	return nil
 }
 
+// VoteReceiptVerify verifies the provided ticketvote v1 VoteReceipt. This
+// includes verifying the cast vote signature and receipt, verifying that the
+// timestamp is of the cast vote, and verifying the timestamp proofs.
+// backend.ErrNotTimestamped is returned if the cast vote has not been anchored
+// onto the DCR blockchain yet.
+func VoteReceiptVerify(r tkv1.VoteReceipt, serverPublicKey string) error {
+	// Verify the cast vote signature and receipt
+	err := CastVoteDetailsVerify(r.Vote, serverPublicKey)
+	if err != nil {
+		return err
+	}
+
+	// Verify that the timestamped data payload is the cast vote
+	var cvd tkplugin.CastVoteDetails
+	err = json.Unmarshal([]byte(r.Timestamp.Data), &cvd)
+	if err != nil {
+		return fmt.Errorf("unable to decode timestamp data: %v", err)
+	}
+	if cvd.Token != r.Vote.Token || cvd.Ticket != r.Vote.Ticket ||
+		cvd.VoteBit != r.Vote.VoteBit || cvd.Signature != r.Vote.Signature ||
+		cvd.Receipt != r.Vote.Receipt {
+		return fmt.Errorf("timestamp data does not match cast vote")
+	}
+
+	// Verify the timestamp proofs
+	return TicketVoteTimestampVerify(r.Timestamp)
+}
+
+// SnapshotVerificationVerify verifies the server receipt of the provided
+// ticketvote v1 SnapshotVerification.
+func SnapshotVerificationVerify(sv tkv1.SnapshotVerification, serverPublicKey string) error {
+	msg := fmt.Sprintf("%v%v%v%v%v", sv.Token, sv.StartBlockHash,
+		sv.SnapshotDigest, sv.ChainDigest, sv.Timestamp)
+	err := util.VerifySignature(sv.Receipt, serverPublicKey, msg)
+	if err != nil {
+		return fmt.Errorf("could not verify receipt: %v", err)
+	}
+	return nil
+}
+
+// AuditBundleVerify verifies the server signature of the provided ticketvote
+// v1 AuditBundleReply and returns the decoded audit bundle. The vote details,
+// cast votes, and authorizations are verified against the server public key
+// that signed the bundle. The timestamps are only verified once the bundle
+// has been fully anchored.
+func AuditBundleVerify(abr tkv1.AuditBundleReply) (*tkv1.VoteAuditBundle, error) {
+	// Verify the digest and signature
+	digest := hex.EncodeToString(util.Digest([]byte(abr.Bundle)))
+	if digest != abr.Digest {
+		return nil, fmt.Errorf("digest mismatch: got %v, want %v",
+			digest, abr.Digest)
+	}
+	err := util.VerifySignature(abr.Signature, abr.PublicKey, abr.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("could not verify signature: %v", err)
+	}
+
+	// Decode the bundle
+	var ab tkv1.VoteAuditBundle
+	err = json.Unmarshal([]byte(abr.Bundle), &ab)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the vote data
+	for k, v := range ab.Auths {
+		err := AuthDetailsVerify(v, abr.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("verify authorization %v: %v", k, err)
+		}
+	}
+	err = VoteDetailsVerify(ab.Details, abr.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("verify vote details: %v", err)
+	}
+	err = EligibleTicketsVerify(ab.Details)
+	if err != nil {
+		return nil, fmt.Errorf("verify vote details: %v", err)
+	}
+	for _, v := range ab.Votes {
+		err := CastVoteDetailsVerify(v, abr.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("verify vote %v: %v", v.Ticket, err)
+		}
+	}
+
+	// Verify the timestamps
+	if !ab.Anchored {
+		return &ab, nil
+	}
+	ts := ab.Timestamps
+	err = TicketVoteTimestampsVerify(tkv1.TimestampsReply{
+		Auths:   ts.Auths,
+		Details: &ts.Details,
+		Votes:   ts.Votes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = voteDetailsTimestampMatch(ab.Details, ts.Details)
+	if err != nil {
+		return nil, err
+	}
+	if ts.Snapshot != nil {
+		err := TicketVoteTimestampVerify(*ts.Snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("verify snapshot timestamp: %v", err)
+		}
+		if ts.Snapshot.Digest != ab.Details.EligibleTicketsDigest {
+			return nil, fmt.Errorf("snapshot timestamp digest %v does not "+
+				"match the eligible tickets digest %v", ts.Snapshot.Digest,
+				ab.Details.EligibleTicketsDigest)
+		}
+	}
+
+	return &ab, nil
+}
+
+// voteDetailsTimestampMatch verifies that the eligible tickets digest of the
+// provided vote details matches the digest of the timestamped vote details
+// data. This ties the eligible tickets to the vote details timestamp.
+func voteDetailsTimestampMatch(vd tkv1.VoteDetails, t tkv1.Timestamp) error {
+	if t.Data == "" {
+		return nil
+	}
+	var tvd tkplugin.VoteDetails
+	err := json.Unmarshal([]byte(t.Data), &tvd)
+	if err != nil {
+		return fmt.Errorf("unmarshal vote details timestamp data: %v", err)
+	}
+	if tvd.EligibleTicketsDigest != vd.EligibleTicketsDigest {
+		return fmt.Errorf("eligible tickets digest %v does not match the "+
+			"timestamped digest %v", vd.EligibleTicketsDigest,
+			tvd.EligibleTicketsDigest)
+	}
+	return nil
+}
+
 func convertVoteProof(p tkv1.Proof) backend.Proof {
 	return backend.Proof{
 		Type:       p.Type,
@@ -356,3 +718,57 @@ func convertVoteTimestamp(t tkv1.Timestamp) backend.Timestamp {
 		Proofs:     proofs,
 	}
 }
+
+// TicketVoteGovernanceExport sends a ticketvote v1 GovernanceExport request to
+// politeiawww.
+func (c *Client) TicketVoteGovernanceExport(ge tkv1.GovernanceExport) (*tkv1.GovernanceExportReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteGovernanceExport, ge)
+	if err != nil {
+		return nil, err
+	}
+
+	var ger tkv1.GovernanceExportReply
+	err = json.Unmarshal(resBody, &ger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ger, nil
+}
+
+// TicketVoteVoteParamsVerify sends a ticketvote v1 VoteParamsVerify request
+// to politeiawww.
+func (c *Client) TicketVoteVoteParamsVerify(vpv tkv1.VoteParamsVerify) (*tkv1.VoteParamsVerifyReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteVoteParamsVerify, vpv)
+	if err != nil {
+		return nil, err
+	}
+
+	var vpvr tkv1.VoteParamsVerifyReply
+	err = json.Unmarshal(resBody, &vpvr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vpvr, nil
+}
+
+// TicketVoteAuditBundle sends a ticketvote v1 AuditBundle request to
+// politeiawww.
+func (c *Client) TicketVoteAuditBundle(ab tkv1.AuditBundle) (*tkv1.AuditBundleReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteAuditBundle, ab)
+	if err != nil {
+		return nil, err
+	}
+
+	var abr tkv1.AuditBundleReply
+	err = json.Unmarshal(resBody, &abr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &abr, nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+)
+
+func TestEligibleTicketsVerify(t *testing.T) {
+	var (
+		t1     = strings.Repeat("00", 31) + "01"
+		t2     = strings.Repeat("00", 31) + "02"
+		t3     = strings.Repeat("ab", 32)
+		digest = "64f020414a87cad1d4855164ee4a225ad770e460f468dcf82baf93dc1e5674eb"
+	)
+	var tests = []struct {
+		name    string
+		tickets []string
+		digest  string
+		wantErr bool
+	}{
+		{"match", []string{t1, t2, t3}, digest, false},
+		{"unsorted with duplicates", []string{t3, t2, t1, t3}, digest, false},
+		{"no digest", []string{t1}, "", false},
+		{"missing ticket", []string{t1, t3}, digest, true},
+		{"invalid ticket", []string{t1, t2, "zz"}, digest, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := EligibleTicketsVerify(tkv1.VoteDetails{
+				EligibleTickets:       tc.tickets,
+				EligibleTicketsDigest: tc.digest,
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("got err %v, want err %v", err, tc.wantErr)
+			}
+		})
+	}
+}
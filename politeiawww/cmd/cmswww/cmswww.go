@@ -101,6 +101,7 @@ type cmswww struct {
 	TestRun                TestRunCmd                   `command:"testrun" description:"         test cmswww routes"`
 	TokenInventory         shared.TokenInventoryCmd     `command:"tokeninventory" description:"(user) get the censorship record tokens of all proposals (passthrough)"`
 	UpdateUserKey          shared.UserKeyUpdateCmd      `command:"updateuserkey" description:"(user)   generate a new identity for the logged in user"`
+	RevokeUserKey          shared.UserKeyRevokeCmd      `command:"revokeuserkey" description:"(user)   revoke one of the identities of the logged in user"`
 	UserDetails            UserDetailsCmd               `command:"userdetails" description:"(user)   get current cms user details"`
 	UserInvoices           UserInvoicesCmd              `command:"userinvoices" description:"(user)   get all invoices submitted by a specific user"`
 	UserSubContractors     UserSubContractorsCmd        `command:"usersubcontractors" description:"(user)   get all users that are linked to the user"`
@@ -0,0 +1,39 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// bannerDelCmd deletes a banner. Requires admin privileges.
+type bannerDelCmd struct {
+	Args struct {
+		ID string `positional-arg-name:"id" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the bannerDelCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *bannerDelCmd) Execute(args []string) error {
+	bdr, err := client.BannerDel(www.BannerDel{
+		ID: cmd.Args.ID,
+	})
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(bdr)
+}
+
+// bannerDelHelpMsg is the output of the help command when 'bannerdel' is
+// specified.
+const bannerDelHelpMsg = `bannerdel "id"
+
+Delete a banner. Requires admin privileges.
+
+Arguments:
+1. id  (string, required)  Banner ID`
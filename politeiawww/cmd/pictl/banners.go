@@ -0,0 +1,33 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// bannersCmd gets all banners. Requires admin privileges.
+type bannersCmd struct{}
+
+// Execute executes the bannersCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *bannersCmd) Execute(args []string) error {
+	br, err := client.Banners(www.Banners{})
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(br)
+}
+
+// bannersHelpMsg is the output of the help command when 'banners' is
+// specified.
+const bannersHelpMsg = `banners
+
+Fetch all banners, including the banners that are scheduled to be displayed in
+the future and the banners that have ended. Requires admin privileges.
+
+Arguments: None`
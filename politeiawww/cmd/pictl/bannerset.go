@@ -0,0 +1,82 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// bannerSetCmd creates or updates a banner. Requires admin privileges.
+type bannerSetCmd struct {
+	Args struct {
+		Severity string `positional-arg-name:"severity" required:"true"`
+		Message  string `positional-arg-name:"message" required:"true"`
+	} `positional-args:"true"`
+
+	ID       string `long:"id" optional:"true"`
+	StartsAt int64  `long:"startsat" optional:"true"`
+	EndsAt   int64  `long:"endsat" optional:"true"`
+}
+
+// Execute executes the bannerSetCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *bannerSetCmd) Execute(args []string) error {
+	severity, err := parseBannerSeverity(cmd.Args.Severity)
+	if err != nil {
+		return err
+	}
+	bsr, err := client.BannerSet(www.BannerSet{
+		ID:       cmd.ID,
+		Message:  cmd.Args.Message,
+		Severity: severity,
+		StartsAt: cmd.StartsAt,
+		EndsAt:   cmd.EndsAt,
+	})
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(bsr)
+}
+
+// parseBannerSeverity parses the provided banner severity. The severity can be
+// provided as either the human readable name or the numeric code.
+func parseBannerSeverity(s string) (www.BannerSeverityT, error) {
+	for k, v := range www.BannerSeverities {
+		if k != www.BannerSeverityInvalid && v == s {
+			return k, nil
+		}
+	}
+	u, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid banner severity '%v'", s)
+	}
+	return www.BannerSeverityT(u), nil
+}
+
+// bannerSetHelpMsg is the output of the help command when 'bannerset' is
+// specified.
+const bannerSetHelpMsg = `bannerset "severity" "message"
+
+Create or update a site-wide banner. A new banner is created when an ID is not
+provided. The banner is displayed immediately when a start time is not
+provided and until it is deleted when an end time is not provided. Requires
+admin privileges.
+
+Arguments:
+1. severity  (string, required)  Banner severity (info, warning, critical)
+2. message   (string, required)  Banner message
+
+Flags:
+ --id        (string, optional)  ID of the banner to update.
+ --startsat  (int64, optional)   Unix timestamp to start displaying the banner.
+ --endsat    (int64, optional)   Unix timestamp to stop displaying the banner.
+
+Example:
+pictl bannerset warning "Scheduled maintenance on Saturday at 12:00 UTC"`
@@ -0,0 +1,45 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// changesCmd gets the changes to the public record inventory that were made
+// since a sequence number.
+type changesCmd struct {
+	Since uint64 `long:"since" optional:"true"`
+}
+
+// Execute executes the changesCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *changesCmd) Execute(args []string) error {
+	cr, err := client.Changes(&www.Changes{
+		Since: cmd.Since,
+	})
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(cr)
+}
+
+// changesHelpMsg is the output of the help command when 'changes' is
+// specified.
+const changesHelpMsg = `changes
+
+Fetch the changes to the public record inventory that were made since the
+provided sequence number. Changes include records being made public, edited or
+changing status, new comments and cast votes. The reply includes the latest
+sequence number, which should be provided as the --since flag on the next
+request. The inventory must be re-crawled when the reply has resync set.
+
+Arguments: None
+
+Flags:
+ --since  (uint64, optional)  Sequence number of the most recent change that
+                              has been processed. Defaults to 0.`
@@ -0,0 +1,163 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdBillingVerify verifies the billing status changes of a proposal.
+type cmdBillingVerify struct {
+	Args struct {
+		Token string `positional-arg-name:"token" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdBillingVerify command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdBillingVerify) Execute(args []string) error {
+	token := c.Args.Token
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get the pi policy and the server public key that is used to
+	// sign the pi receipts.
+	pr, err := pc.PiPolicy()
+	if err != nil {
+		return err
+	}
+	ir, err := pc.PiIdentity()
+	if err != nil {
+		return err
+	}
+
+	// Get the billing status changes
+	bscsr, err := pc.PiBillingStatusChanges(piv1.BillingStatusChanges{
+		Tokens: []string{token},
+	})
+	if err != nil {
+		return err
+	}
+	bscs, ok := bscsr.BillingStatusChanges[token]
+	if !ok {
+		return fmt.Errorf("billing status changes not found for %v", token)
+	}
+
+	// Verify the billing status changes
+	reasonRequired := make(map[piv1.BillingStatusT]struct{},
+		len(pr.BillingStatusReasonRequired))
+	for _, v := range pr.BillingStatusReasonRequired {
+		reasonRequired[v] = struct{}{}
+	}
+	var (
+		failed int
+		curr   = piv1.BillingStatusActive
+	)
+	check := func(name string, err error) {
+		if err != nil {
+			failed++
+			printf("  %-11v: FAIL %v\n", name, err)
+			return
+		}
+		printf("  %-11v: ok\n", name)
+	}
+
+	printf("Token         : %v\n", token)
+	printf("Server key    : %v\n", ir.PublicKey)
+	printf("Status changes: %v\n", len(bscs))
+	for i, v := range bscs {
+		printf("Change %v\n", i+1)
+		printf("  Status     : %v\n", piv1.BillingStatuses[v.Status])
+		if v.Reason != "" {
+			printf("  Reason     : %v\n", v.Reason)
+		}
+		printf("  Admin key  : %v\n", v.PublicKey)
+		printf("  Timestamp  : %v\n", dateAndTimeFromUnix(v.Timestamp))
+
+		// Verify the change belongs to the proposal
+		err = nil
+		if !strings.HasPrefix(v.Token, token) {
+			err = fmt.Errorf("got token %v", v.Token)
+		}
+		check("Token", err)
+
+		// Verify the admin signature and the server receipt
+		check("Signatures", pclient.BillingStatusChangeVerify(v,
+			ir.PublicKey))
+
+		// Verify the billing status transition
+		check("Transition", pclient.BillingStatusTransitionVerify(curr,
+			v.Status))
+		curr = v.Status
+
+		// Verify that a reason was given if one is required
+		err = nil
+		if _, ok := reasonRequired[v.Status]; ok && v.Reason == "" {
+			err = fmt.Errorf("%v requires a reason",
+				piv1.BillingStatuses[v.Status])
+		}
+		check("Reason", err)
+
+		// Verify that the changes are ordered chronologically
+		err = nil
+		if i > 0 && v.Timestamp < bscs[i-1].Timestamp {
+			err = fmt.Errorf("timestamp is before the previous change")
+		}
+		check("Order", err)
+	}
+
+	// Verify the number of billing status changes
+	printf("Summary\n")
+	err = nil
+	if uint32(len(bscs)) > pr.BillingStatusChangesMax {
+		err = fmt.Errorf("%v changes exceeds the maximum of %v",
+			len(bscs), pr.BillingStatusChangesMax)
+	}
+	check("Count", err)
+	if len(bscs) > 0 {
+		printf("  Status     : %v\n", piv1.BillingStatuses[curr])
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%v billing status checks failed", failed)
+	}
+	printf("All billing status checks passed\n")
+
+	return nil
+}
+
+// billingVerifyHelpMsg is printed to stdout by the help command.
+const billingVerifyHelpMsg = `billingverify "token"
+
+Verify the billing status changes of a proposal. Every billing status change
+is checked for a valid admin signature and a valid server receipt, where the
+server receipt is verified against the key returned by the pi api identity
+route. The sequence of billing status changes is checked against the allowed
+billing status transitions, starting from the active billing status of an
+approved proposal. The reason requirements and the maximum number of billing
+status changes are checked against the pi api policy.
+
+A detailed report is printed. The command returns an error if any of the
+checks fail.
+
+Arguments:
+1. token  (string, required)  Proposal censorship token
+
+Example usage:
+$ pictl billingverify cda97ace0a476514`
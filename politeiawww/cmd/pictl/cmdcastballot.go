@@ -171,6 +171,12 @@ func (c *cmdCastBallot) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
+	if cbr.SubmissionID != "" {
+		// politeiad was unavailable and the ballot was queued
+		printf("Ballot queued; submission ID %v\n", cbr.SubmissionID)
+		printf("Use the castballotstatus command to check its status\n")
+		return nil
+	}
 
 	// Get the server pubkey so that we can validate the receipts.
 	version, err := client.Version()
@@ -218,11 +224,32 @@ func (c *cmdCastBallot) Execute(args []string) error {
 	printf("Votes failed   : %v\n", len(failedReceipts))
 	for i, v := range failedReceipts {
 		printf("Failed vote    : %v %v\n", failedTickets[i], v.ErrorContext)
+		if v.Diagnostics != nil {
+			printVoteDiagnostics(*v.Diagnostics)
+		}
 	}
 
 	return nil
 }
 
+// printVoteDiagnostics prints the diagnostics of a failed vote. Fields that
+// were not populated by the server are not printed.
+func printVoteDiagnostics(d tkv1.VoteDiagnostics) {
+	printf("  Reason         : %v\n", d.Reason)
+	if d.SnapshotHeight != 0 {
+		printf("  Snapshot height: %v\n", d.SnapshotHeight)
+	}
+	if d.EndBlockHeight != 0 {
+		printf("  End height     : %v\n", d.EndBlockHeight)
+	}
+	if d.BestBlock != 0 {
+		printf("  Best block     : %v\n", d.BestBlock)
+	}
+	if d.CommitmentAddr != "" {
+		printf("  Commitment addr: %v\n", d.CommitmentAddr)
+	}
+}
+
 // castBallotHelpMsg is printed to stdout by the help command.
 const castBallotHelpMsg = `castballot "token" "voteid"
 
@@ -0,0 +1,76 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdCastBallotStatus retrieves the status of a ballot that was queued
+// because politeiad was unavailable.
+type cmdCastBallotStatus struct {
+	Args struct {
+		SubmissionID string `positional-arg-name:"submissionid" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdCastBallotStatus command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCastBallotStatus) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get submission status
+	s := tkv1.SubmissionStatus{
+		SubmissionID: c.Args.SubmissionID,
+	}
+	ssr, err := pc.TicketVoteSubmissionStatus(s)
+	if err != nil {
+		return err
+	}
+
+	// Print status
+	printf("Status   : %v\n", tkv1.SubmissionStatuses[ssr.Status])
+	printf("Submitted: %v\n", time.Unix(ssr.Timestamp, 0).UTC())
+	printf("Attempts : %v\n", ssr.Attempts)
+	if ssr.Error != "" {
+		printf("Error    : %v\n", ssr.Error)
+	}
+	if ssr.Reply != nil {
+		var failed int
+		for _, v := range ssr.Reply.Receipts {
+			if v.ErrorCode != nil {
+				failed++
+				printf("Failed vote    : %v %v\n", v.Ticket, v.ErrorContext)
+			}
+		}
+		printf("Votes succeeded: %v\n", len(ssr.Reply.Receipts)-failed)
+		printf("Votes failed   : %v\n", failed)
+	}
+
+	return nil
+}
+
+// castBallotStatusHelpMsg is printed to stdout by the help command.
+const castBallotStatusHelpMsg = `castballotstatus "submissionid"
+
+Get the status of a ballot that was queued by politeiawww because politeiad
+was unavailable. The submission ID is printed by the castballot command when
+a ballot is queued.
+
+Arguments:
+1. submissionid  (string, required)  Submission ID of the queued ballot`
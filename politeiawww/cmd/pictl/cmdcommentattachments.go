@@ -0,0 +1,111 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/util"
+)
+
+// cmdCommentAttachments retrieves the attachments of a comment.
+type cmdCommentAttachments struct {
+	Args struct {
+		Token     string `positional-arg-name:"token" required:"true"`
+		CommentID uint32 `positional-arg-name:"commentid" required:"true"`
+	} `positional-args:"true"`
+
+	// Dir is used to save the attachments to the provided directory.
+	Dir string `long:"dir" optional:"true"`
+}
+
+// Execute executes the cmdCommentAttachments command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentAttachments) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get attachments
+	a := cmv1.Attachments{
+		Token:     c.Args.Token,
+		CommentID: c.Args.CommentID,
+	}
+	ar, err := pc.CommentAttachments(a)
+	if err != nil {
+		return err
+	}
+
+	// Verify attachments
+	for _, v := range ar.Attachments {
+		err := pclient.CommentAttachmentVerify(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Print attachments
+	for _, v := range ar.Attachments {
+		printf("%v\n", v.Name)
+		printf("  MIME   : %v\n", v.MIME)
+		printf("  Digest : %v\n", v.Digest)
+	}
+
+	// Save attachments
+	if c.Dir == "" {
+		return nil
+	}
+	dir := util.CleanAndExpandPath(c.Dir)
+	for _, v := range ar.Attachments {
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return err
+		}
+		fp := filepath.Join(dir, filepath.Base(v.Name))
+		err = os.WriteFile(fp, b, 0644)
+		if err != nil {
+			return fmt.Errorf("WriteFile %v: %v", fp, err)
+		}
+		printf("Saved %v\n", fp)
+	}
+
+	return nil
+}
+
+// commentAttachmentsHelpMsg is printed to stdout by the help command.
+const commentAttachmentsHelpMsg = `commentattachments "token" commentid
+
+Get the image attachments of a comment. The attachments are verified against
+the attachment digests that are part of the comment signature.
+
+Retrieving the attachments of a comment on an unvetted record requires the
+user be either an admin or the record author.
+
+Arguments:
+1. token      (string, required)  Proposal censorship token
+2. commentid  (uint32, required)  Comment ID
+
+Flags:
+ --dir  (string, optional)  Directory to save the attachments to.
+
+Example usage
+$ commentattachments d594fbadef0f9378 3
+$ commentattachments --dir=~/Downloads d594fbadef0f9378 3
+`
@@ -0,0 +1,100 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// cmdCommentCensorUser censors all of the comments that were made by a user.
+type cmdCommentCensorUser struct {
+	Args struct {
+		UserID string `positional-arg-name:"userid"`
+		Reason string `positional-arg-name:"reason"`
+	} `positional-args:"true" required:"true"`
+
+	// Token is used to only censor the comments that were made on a
+	// single record.
+	Token string `long:"token" optional:"true"`
+}
+
+// Execute executes the cmdCommentCensorUser command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentCensorUser) Execute(args []string) error {
+	// Unpack args
+	var (
+		userID = c.Args.UserID
+		reason = c.Args.Reason
+	)
+
+	// Check for user identity. A user identity is required to sign
+	// the censor request.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup request
+	sig := cfg.Identity.SignMessage([]byte(userID + reason))
+	d := cmv1.DelUser{
+		UserID:    userID,
+		Token:     c.Token,
+		Reason:    reason,
+		Signature: hex.EncodeToString(sig[:]),
+		PublicKey: cfg.Identity.Public.String(),
+	}
+
+	// Send request
+	dr, err := pc.CommentDelUser(d)
+	if err != nil {
+		return err
+	}
+
+	// Print results
+	for _, v := range dr.Records {
+		printf("Token   : %v\n", v.Token)
+		printf("Comments: %v\n", v.CommentIDs)
+	}
+	for _, v := range dr.Skipped {
+		printf("Skipped : %v\n", v)
+	}
+	printf("Total censored: %v\n", dr.Total)
+
+	return nil
+}
+
+// commentCensorUserHelpMsg is printed to stdout by the help command.
+const commentCensorUserHelpMsg = `commentcensoruser "userid" "reason"
+
+Censor all of the comments that were made by a user. The comments made on all
+records are censored unless the --token flag is used to specify a record.
+Records whose comments can no longer be censored, e.g. locked records, are
+skipped.
+
+This command requires admin priviledges.
+
+Arguments:
+1. userid  (string, required)  ID of the user
+2. reason  (string, required)  Reason for censoring the comments
+
+Flags:
+  --token  (string, optional)  Only censor the comments made on this record`
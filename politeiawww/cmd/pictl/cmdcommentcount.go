@@ -55,6 +55,10 @@ func commentCount(c *cmdCommentCount) (map[string]uint32, error) {
 
 	// Print counts
 	for k, v := range cr.Counts {
+		if id, ok := cr.Pinned[k]; ok {
+			printf("%v %v (pinned comment %v)\n", k, v, id)
+			continue
+		}
 		printf("%v %v\n", k, v)
 	}
 
@@ -65,7 +69,8 @@ func commentCount(c *cmdCommentCount) (map[string]uint32, error) {
 const commentCountHelpMsg = `commentcount "tokens..." 
 
 Get the number of comments that have been made on each of the provided
-records.
+records. The ID of the pinned comment is also printed for records that have
+one.
 
 Arguments:
 1. token  (string, required)  Proposal censorship token
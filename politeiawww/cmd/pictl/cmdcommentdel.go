@@ -0,0 +1,123 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// cmdCommentDel deletes a proposal comment that was authored by the logged in
+// user.
+type cmdCommentDel struct {
+	Args struct {
+		Token     string `positional-arg-name:"token" required:"true"`
+		CommentID uint32 `positional-arg-name:"commentid" required:"true"`
+		Reason    string `positional-arg-name:"reason" optional:"true"`
+	} `positional-args:"true"`
+
+	// Unvetted is used to delete the comment on an unvetted record. If
+	// this flag is not used the command assumes the record is vetted.
+	Unvetted bool `long:"unvetted" optional:"true"`
+}
+
+// Execute executes the cmdCommentDel command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentDel) Execute(args []string) error {
+	// Unpack args
+	var (
+		token     = c.Args.Token
+		commentID = c.Args.CommentID
+		reason    = c.Args.Reason
+	)
+
+	// Check for user identity. A user identity is required to sign
+	// the delete request.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup state
+	var state cmv1.RecordStateT
+	switch {
+	case c.Unvetted:
+		state = cmv1.RecordStateUnvetted
+	default:
+		state = cmv1.RecordStateVetted
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup request
+	msg := strconv.FormatUint(uint64(state), 10) + token +
+		strconv.FormatUint(uint64(commentID), 10) + reason
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	d := cmv1.Del{
+		State:     state,
+		Token:     token,
+		CommentID: commentID,
+		Reason:    reason,
+		Signature: hex.EncodeToString(sig[:]),
+		PublicKey: cfg.Identity.Public.String(),
+	}
+
+	// Send request
+	dr, err := pc.CommentDel(d)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	err = pclient.CommentVerify(dr.Comment, vr.PubKey)
+	if err != nil {
+		return err
+	}
+
+	// Print comment
+	printComment(dr.Comment)
+
+	return nil
+}
+
+// commentDelHelpMsg is printed to stdout by the help command.
+const commentDelHelpMsg = `commentdel "token" "commentID" "reason"
+
+Delete a comment that was authored by the logged in user. The comment is
+displayed as removed by its author rather than censored.
+
+Comment authors are only allowed to delete their own comments when the
+allowauthordeletes comments policy is enabled. Admins should use the
+commentcensor command to censor comments.
+
+If the record is unvetted, the --unvetted flag must be used.
+
+Arguments:
+1. token      (string, required)  Proposal censorship token
+2. commentid  (string, required)  ID of the comment
+3. reason     (string, optional)  Reason for deleting the comment
+
+Flags:
+  --unvetted  (bool, optional)  Record is unvetted.
+`
@@ -31,6 +31,10 @@ type cmdCommentEdit struct {
 
 	// UpdateTitle is used to post a new author update.
 	UpdateTitle string `long:"updatetitle" optional:"true"`
+
+	// Attachments is used to attach images to the edited comment. The
+	// flag can be used multiple times.
+	Attachments []string `long:"attachment" optional:"true"`
 }
 
 // Execute executes the cmdCommentEdit command.
@@ -100,11 +104,18 @@ func (c *cmdCommentEdit) Execute(args []string) error {
 	}
 	userID := lr.UserID
 
+	// Load attachments
+	attachments, err := commentAttachmentsFromDisk(c.Attachments)
+	if err != nil {
+		return err
+	}
+
 	// Setup request
 	msg := strconv.FormatUint(uint64(state), 10) + token +
 		strconv.FormatUint(uint64(parentID), 10) +
 		strconv.FormatUint(uint64(commentID), 10) +
-		comment + extraData + extraDataHint
+		comment + extraData + extraDataHint +
+		pclient.CommentAttachmentsMsg(attachments)
 	sig := cfg.Identity.SignMessage([]byte(msg))
 	e := cmv1.Edit{
 		UserID:        userID,
@@ -115,6 +126,7 @@ func (c *cmdCommentEdit) Execute(args []string) error {
 		Comment:       comment,
 		Signature:     hex.EncodeToString(sig[:]),
 		PublicKey:     cfg.Identity.Public.String(),
+		Attachments:   attachments,
 		ExtraDataHint: extraDataHint,
 		ExtraData:     extraData,
 	}
@@ -153,6 +165,9 @@ unvetted records requires admin priviledges.
 
 Proposal's author may edit an author update using the --updatetitle flag.
 
+The attachments of the edited comment replace the attachments of the previous
+version. Any attachments that should be kept must be provided again.
+
 Arguments:
 1. token     (string, required)  Proposal censorship token.
 2. commentid (string, required)  Comment ID. 
@@ -163,4 +178,6 @@ Arguments:
 Flags:
   --unvetted    (bool, optional)   Record is unvetted.
   --updatetitle (string, optional) Authour update title.
+  --attachment  (string, optional) Path to an image to attach to the comment.
+                                   Can be used multiple times.
 `
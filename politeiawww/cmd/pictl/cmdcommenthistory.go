@@ -0,0 +1,79 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdCommentHistory retrieves the comment history of a user.
+type cmdCommentHistory struct {
+	Args struct {
+		UserID string `positional-arg-name:"userid" required:"true"`
+	} `positional-args:"true"`
+
+	Page uint32 `long:"page" optional:"true"`
+}
+
+// Execute executes the cmdCommentHistory command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentHistory) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get comment history
+	h := cmv1.History{
+		UserID: c.Args.UserID,
+		Page:   c.Page,
+	}
+	hr, err := pc.CommentHistory(h)
+	if err != nil {
+		return err
+	}
+
+	// Print comment history
+	if len(hr.Entries) == 0 {
+		printf("No comments found for user %v\n", c.Args.UserID)
+		return nil
+	}
+	printf("Total: %v\n", hr.Total)
+	for _, v := range hr.Entries {
+		printf("Token    : %v\n", v.Token)
+		printf("Comment  : %v\n", v.CommentID)
+		printf("Timestamp: %v\n", dateAndTimeFromUnix(v.Timestamp))
+		printf("Excerpt  : %v\n", v.Excerpt)
+		printf("-----\n")
+	}
+
+	return nil
+}
+
+// commentHistoryHelpMsg is printed to stdout by the help command.
+const commentHistoryHelpMsg = `commenthistory "userid"
+
+Get the comment history of a user. The comment history contains the comments
+that the user has made on public records, ordered from newest to oldest.
+Deleted comments are not included.
+
+Arguments:
+1. userid  (string, required)  User ID
+
+Flags:
+  --page  (uint32, optional)  Results page to return. Default: 1
+
+Example:
+$ pictl commenthistory 6dc1c8ca-abb5-4631-8ed4-f991b0169770 --page=2`
@@ -29,6 +29,14 @@ type cmdCommentNew struct {
 
 	// UpdateTitle is used to post a new author update.
 	UpdateTitle string `long:"updatetitle" optional:"true"`
+
+	// ContentType is used to declare the content type of the comment
+	// text. The server defaults to markdown if it is not provided.
+	ContentType string `long:"contenttype" optional:"true"`
+
+	// Attachments is used to attach images to the comment. The flag
+	// can be used multiple times.
+	Attachments []string `long:"attachment" optional:"true"`
 }
 
 // Execute executes the cmdCommentNew command.
@@ -87,10 +95,16 @@ func (c *cmdCommentNew) Execute(args []string) error {
 		extraData = string(b)
 	}
 
+	// Load attachments
+	attachments, err := commentAttachmentsFromDisk(c.Attachments)
+	if err != nil {
+		return err
+	}
+
 	// Setup request
 	msg := strconv.FormatUint(uint64(state), 10) + token +
 		strconv.FormatUint(uint64(parentID), 10) + comment +
-		extraData + extraDataHint
+		extraData + extraDataHint + pclient.CommentAttachmentsMsg(attachments)
 	sig := cfg.Identity.SignMessage([]byte(msg))
 	n := cmv1.New{
 		State:         state,
@@ -99,6 +113,8 @@ func (c *cmdCommentNew) Execute(args []string) error {
 		Comment:       comment,
 		Signature:     hex.EncodeToString(sig[:]),
 		PublicKey:     cfg.Identity.Public.String(),
+		ContentType:   c.ContentType,
+		Attachments:   attachments,
 		ExtraDataHint: extraDataHint,
 		ExtraData:     extraData,
 	}
@@ -108,6 +124,12 @@ func (c *cmdCommentNew) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
+	if nr.SubmissionID != "" {
+		// politeiad was unavailable and the comment was queued
+		printf("Comment queued; submission ID %v\n", nr.SubmissionID)
+		printf("Use the commentstatus command to check its status\n")
+		return nil
+	}
 
 	// Verify receipt
 	vr, err := client.Version()
@@ -150,4 +172,11 @@ Arguments:
 Flags:
   --unvetted    (bool, optional)   Record is unvetted.
   --updatetitle (string, optional) Authour update title.
+  --contenttype (string, optional) Content type of the comment text. Supported
+                                   types are markdown and plain. Defaults to
+                                   markdown.
+  --attachment  (string, optional) Path to an image to attach to the comment.
+                                   Can be used multiple times. See the
+                                   commentpolicy command for the allowed
+                                   attachment types and sizes.
 `
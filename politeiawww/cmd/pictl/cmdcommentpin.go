@@ -0,0 +1,122 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
+)
+
+// cmdCommentPin pins a comment on a record.
+type cmdCommentPin struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`
+		CommentID uint32 `positional-arg-name:"commentID"`
+	} `positional-args:"true" required:"true"`
+
+	// Unvetted is used to pin a comment on an unvetted record. If this
+	// flag is not used the command assumes the record is vetted.
+	Unvetted bool `long:"unvetted" optional:"true"`
+}
+
+// Execute executes the cmdCommentPin command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentPin) Execute(args []string) error {
+	// Check for user identity. A user identity is required to sign
+	// the pin.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup state
+	state := cmv1.RecordStateVetted
+	if c.Unvetted {
+		state = cmv1.RecordStateUnvetted
+	}
+
+	// Setup request
+	msg := strconv.FormatUint(uint64(state), 10) + c.Args.Token +
+		strconv.FormatUint(uint64(c.Args.CommentID), 10)
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	p := cmv1.Pin{
+		State:     state,
+		Token:     c.Args.Token,
+		CommentID: c.Args.CommentID,
+		PublicKey: cfg.Identity.Public.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	pr, err := pc.CommentPin(p)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	serverID, err := identity.PublicIdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptb, err := util.ConvertSignature(pr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(p.Signature), receiptb) {
+		return fmt.Errorf("could not verify receipt")
+	}
+
+	// Print receipt
+	printf("Timestamp: %v\n", dateAndTimeFromUnix(pr.Timestamp))
+	printf("Receipt  : %v\n", pr.Receipt)
+
+	return nil
+}
+
+// commentPinHelpMsg is printed to stdout by the help command.
+const commentPinHelpMsg = `commentpin "token" "commentID"
+
+Pin a comment on a record so that clients can display it at the top of the
+record comments. A record can only have one pinned comment. Pinning a comment
+replaces the previously pinned comment. A comment ID of 0 unpins the pinned
+comment.
+
+Requires the user to be the record author or an admin.
+
+Arguments:
+1. token      (string, required)  Proposal censorship token
+2. commentID  (string, required)  Comment ID, 0 to unpin
+
+Flags:
+ --unvetted   (bool, optional)  Record is unvetted.
+
+Example usage
+$ commentpin d594fbadef0f9378 3
+$ commentpin d594fbadef0f9378 0
+`
@@ -5,6 +5,8 @@
 package main
 
 import (
+	"fmt"
+
 	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
 	pclient "github.com/decred/politeia/politeiawww/client"
 )
@@ -14,6 +16,15 @@ type cmdComments struct {
 	Args struct {
 		Token string `positional-arg-name:"token"` // Censorship token
 	} `positional-args:"true" required:"true"`
+
+	// Sort is used to sort the comments. Options are "score", "stake",
+	// and "new".
+	Sort string `long:"sort" optional:"true"`
+
+	// Page and PageSize are used to request a page of comments instead
+	// of all comments.
+	Page     uint32 `long:"page" optional:"true"`
+	PageSize uint32 `long:"pagesize" optional:"true"`
 }
 
 // Execute executes the cmdComments command.
@@ -33,9 +44,24 @@ func (c *cmdComments) Execute(args []string) error {
 		return err
 	}
 
+	// Parse sort order
+	sorts := map[string]cmv1.SortT{
+		"":      cmv1.SortInvalid,
+		"score": cmv1.SortScore,
+		"stake": cmv1.SortStakeScore,
+		"new":   cmv1.SortTimestamp,
+	}
+	sortBy, ok := sorts[c.Sort]
+	if !ok {
+		return fmt.Errorf("invalid sort '%v'", c.Sort)
+	}
+
 	// Get comments
 	cm := cmv1.Comments{
-		Token: c.Args.Token,
+		Token:    c.Args.Token,
+		SortBy:   sortBy,
+		Page:     c.Page,
+		PageSize: c.PageSize,
 	}
 	cr, err := pc.Comments(cm)
 	if err != nil {
@@ -47,6 +73,9 @@ func (c *cmdComments) Execute(args []string) error {
 		printComment(v)
 		printf("\n")
 	}
+	if c.Page > 0 || c.PageSize > 0 {
+		printf("Comments: %v/%v\n", len(cr.Comments), cr.Total)
+	}
 
 	return nil
 }
@@ -62,4 +91,17 @@ record author.
 
 Arguments:
 1. token  (string, required)  Proposal censorship token
+
+Flags:
+ --sort      (string, optional)  Sort the comments. Options are "score",
+                                 "stake", and "new". The "stake" option sorts
+                                 by the stake weighted score and requires the
+                                 server to have comment stake weighting
+                                 enabled. The "new" option sorts the comments
+                                 from newest to oldest.
+ --page      (uint32, optional)  Page of comments to return. All comments are
+                                 returned unless a page or page size is
+                                 provided.
+ --pagesize  (uint32, optional)  Number of comments per page. Defaults to the
+                                 comments page size policy.
 `
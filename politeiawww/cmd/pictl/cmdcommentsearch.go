@@ -0,0 +1,92 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdCommentSearch searches the comments of public records.
+type cmdCommentSearch struct {
+	Args struct {
+		Query string `positional-arg-name:"query" required:"true"`
+	} `positional-args:"true"`
+
+	// Filtering options
+	Token  string `long:"token" optional:"true"`
+	UserID string `long:"userid" optional:"true"`
+	Page   uint32 `long:"page" optional:"true"`
+}
+
+// Execute executes the cmdCommentSearch command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentSearch) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Search comments
+	s := cmv1.Search{
+		Query:  c.Args.Query,
+		Token:  c.Token,
+		UserID: c.UserID,
+		Page:   c.Page,
+	}
+	sr, err := pc.CommentSearch(s)
+	if err != nil {
+		return err
+	}
+
+	// Print comments
+	if len(sr.Comments) == 0 {
+		printf("No comments found\n")
+		return nil
+	}
+	printf("Total: %v\n", sr.Total)
+	for _, v := range sr.Comments {
+		printf("Token  : %v\n", v.Token)
+		printComment(v)
+		printf("-----\n")
+	}
+
+	return nil
+}
+
+// commentSearchHelpMsg is printed to stdout by the help command.
+const commentSearchHelpMsg = `commentsearch "query"
+
+Search the comments of public records. The search is case insensitive and only
+matches the latest version of each comment. The results are ordered from
+newest to oldest.
+
+A comment must match all of the terms in the query. The query supports the
+following syntax:
+
+  word        Match comments that contain the word
+  "a phrase"  Match comments that contain the exact phrase
+  -word       Exclude comments that contain the word
+  -"phrase"   Exclude comments that contain the phrase
+
+Arguments:
+1. query  (string, required)  Search query
+
+Flags:
+  --token   (string, optional)  Only search the comments of this record
+  --userid  (string, optional)  Only search the comments made by this user
+  --page    (uint32, optional)  Results page to return. Default: 1
+
+Example:
+$ pictl commentsearch 'budget -"milestone 1"' --page=2`
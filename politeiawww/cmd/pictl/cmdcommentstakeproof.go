@@ -0,0 +1,220 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"decred.org/dcrwallet/rpc/walletrpc"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
+	"github.com/pkg/errors"
+)
+
+// cmdCommentStakeProof submits proof of the tickets that the logged in user
+// holds so that their comment votes on a record are weighted by stake.
+type cmdCommentStakeProof struct {
+	Args struct {
+		Token string `positional-arg-name:"token"`
+	} `positional-args:"true" required:"true"`
+	Password string `long:"password" optional:"true"`
+}
+
+// Execute executes the cmdCommentStakeProof command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentStakeProof) Execute(args []string) error {
+	token := c.Args.Token
+
+	// Check for user identity. A user identity is required to sign
+	// the stake proof.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup politeiawww client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get user ID of logged in user
+	lr, err := client.Me()
+	if err != nil {
+		if err.Error() == "401" {
+			return errors.Errorf("no logged in user found")
+		}
+		return err
+	}
+	userID := lr.UserID
+
+	// Setup dcrwallet client
+	ctx := context.Background()
+	wc, err := newDcrwalletClient(cfg.WalletHost, cfg.WalletCert,
+		cfg.ClientCert, cfg.ClientKey)
+	if err != nil {
+		return err
+	}
+	defer wc.conn.Close()
+
+	// Get the eligible tickets of the vote
+	dr, err := pc.TicketVoteDetails(tkv1.Details{
+		Token: token,
+	})
+	if err != nil {
+		return err
+	}
+	if dr.Vote == nil {
+		return fmt.Errorf("vote not started")
+	}
+	ticketPool := make([][]byte, 0, len(dr.Vote.EligibleTickets))
+	for _, v := range dr.Vote.EligibleTickets {
+		h, err := chainhash.NewHashFromStr(v)
+		if err != nil {
+			return err
+		}
+		ticketPool = append(ticketPool, h[:])
+	}
+
+	// Get the user's tickets that were eligible to vote
+	ctr, err := wc.wallet.CommittedTickets(ctx,
+		&walletrpc.CommittedTicketsRequest{
+			Tickets: ticketPool,
+		})
+	if err != nil {
+		return fmt.Errorf("CommittedTickets: %v", err)
+	}
+	if len(ctr.TicketAddresses) == 0 {
+		return fmt.Errorf("user has no eligible tickets")
+	}
+	tickets := make([]string, 0, len(ctr.TicketAddresses))
+	for _, v := range ctr.TicketAddresses {
+		h, err := chainhash.NewHash(v.Ticket)
+		if err != nil {
+			return fmt.Errorf("NewHash %x: %v", v.Ticket, err)
+		}
+		tickets = append(tickets, h.String())
+	}
+
+	// Have the user's wallet sign the stake proof message for each
+	// ticket. The wallet password is needed for this.
+	var passphrase []byte
+	if c.Password != "" {
+		passphrase = []byte(c.Password)
+	} else {
+		passphrase, err = promptWalletPassword()
+		if err != nil {
+			return err
+		}
+	}
+	msg := cmv1.StakeProofPrefix + token + userID
+	messages := make([]*walletrpc.SignMessagesRequest_Message, 0,
+		len(ctr.TicketAddresses))
+	for _, v := range ctr.TicketAddresses {
+		messages = append(messages, &walletrpc.SignMessagesRequest_Message{
+			Address: v.Address,
+			Message: msg,
+		})
+	}
+	sigs, err := wc.wallet.SignMessages(ctx, &walletrpc.SignMessagesRequest{
+		Passphrase: passphrase,
+		Messages:   messages,
+	})
+	if err != nil {
+		return fmt.Errorf("SignMessages: %v", err)
+	}
+	ticketSigs := make([]cmv1.TicketSignature, 0, len(tickets))
+	for i, r := range sigs.Replies {
+		// The tickets and the signature replies share the same ordering
+		if r.Error != "" {
+			return fmt.Errorf("signature failed for ticket %v: %v",
+				tickets[i], r.Error)
+		}
+		ticketSigs = append(ticketSigs, cmv1.TicketSignature{
+			Ticket:    tickets[i],
+			Signature: hex.EncodeToString(r.Signature),
+		})
+	}
+
+	// Setup request
+	msg = token + userID + strings.Join(tickets, "")
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	sp := cmv1.StakeProof{
+		Token:     token,
+		Tickets:   ticketSigs,
+		PublicKey: cfg.Identity.Public.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	spr, err := pc.CommentStakeProof(sp)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	serverID, err := identity.PublicIdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptb, err := util.ConvertSignature(spr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(sp.Signature), receiptb) {
+		return fmt.Errorf("could not verify receipt")
+	}
+
+	// Print receipt
+	printf("Tickets  : %v\n", len(ticketSigs))
+	printf("Weight   : %v\n", spr.Weight)
+	printf("Timestamp: %v\n", dateAndTimeFromUnix(spr.Timestamp))
+	printf("Receipt  : %v\n", spr.Receipt)
+
+	return nil
+}
+
+// commentStakeProofHelpMsg is printed to stdout by the help command.
+const commentStakeProofHelpMsg = `commentstakeproof "token"
+
+Submit proof of the tickets that you hold so that your comment votes on a
+record are weighted by stake.
+
+Requires the user to be logged in and the server to have comment stake
+weighting enabled. The tickets of the connected wallet that were eligible to
+vote in the ticket vote of the record are signed using their commitment
+addresses. A new stake proof replaces your previous stake proof.
+
+If no password is provided, the user will be prompted for their wallet
+password.
+
+Arguments:
+1. token  (string, required)  Proposal censorship token
+
+Flags:
+ --password  (string, optional)  Wallet password
+
+Example usage
+$ commentstakeproof d594fbadef0f9378
+`
@@ -0,0 +1,68 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdCommentStatus retrieves the status of a comment that was queued because
+// politeiad was unavailable.
+type cmdCommentStatus struct {
+	Args struct {
+		SubmissionID string `positional-arg-name:"submissionid" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdCommentStatus command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentStatus) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get submission status
+	s := cmv1.SubmissionStatus{
+		SubmissionID: c.Args.SubmissionID,
+	}
+	ssr, err := pc.CommentSubmissionStatus(s)
+	if err != nil {
+		return err
+	}
+
+	// Print status
+	printf("Status   : %v\n", cmv1.SubmissionStatuses[ssr.Status])
+	printf("Submitted: %v\n", time.Unix(ssr.Timestamp, 0).UTC())
+	printf("Attempts : %v\n", ssr.Attempts)
+	if ssr.Error != "" {
+		printf("Error    : %v\n", ssr.Error)
+	}
+	if ssr.Reply != nil {
+		printComment(ssr.Reply.Comment)
+	}
+
+	return nil
+}
+
+// commentStatusHelpMsg is printed to stdout by the help command.
+const commentStatusHelpMsg = `commentstatus "submissionid"
+
+Get the status of a comment that was queued by politeiawww because politeiad
+was unavailable. The submission ID is printed by the commentnew command when
+a comment is queued. The comment is printed once it has been submitted.
+
+Arguments:
+1. submissionid  (string, required)  Submission ID of the queued comment`
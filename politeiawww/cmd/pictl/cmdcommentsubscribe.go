@@ -0,0 +1,133 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
+)
+
+// cmdCommentSubscribe subscribes the logged in user to the replies under a
+// comment.
+type cmdCommentSubscribe struct {
+	Args struct {
+		Token     string `positional-arg-name:"token"`
+		CommentID uint32 `positional-arg-name:"commentID"`
+	} `positional-args:"true" required:"true"`
+
+	// Unsubscribe is used to unsubscribe from the comment thread.
+	Unsubscribe bool `long:"unsubscribe" optional:"true"`
+
+	// Unvetted is used to subscribe to a comment thread on an unvetted
+	// record. If this flag is not used the command assumes the record
+	// is vetted.
+	Unvetted bool `long:"unvetted" optional:"true"`
+}
+
+// Execute executes the cmdCommentSubscribe command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentSubscribe) Execute(args []string) error {
+	// Check for user identity. A user identity is required to sign
+	// the subscription.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup state and action
+	state := cmv1.RecordStateVetted
+	if c.Unvetted {
+		state = cmv1.RecordStateUnvetted
+	}
+	action := cmv1.SubscribeActionSubscribe
+	if c.Unsubscribe {
+		action = cmv1.SubscribeActionUnsubscribe
+	}
+
+	// Setup request
+	msg := strconv.FormatUint(uint64(state), 10) + c.Args.Token +
+		strconv.FormatUint(uint64(c.Args.CommentID), 10) + string(action)
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	s := cmv1.Subscribe{
+		State:     state,
+		Token:     c.Args.Token,
+		CommentID: c.Args.CommentID,
+		Action:    action,
+		PublicKey: cfg.Identity.Public.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	sr, err := pc.CommentSubscribe(s)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	serverID, err := identity.PublicIdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	receiptb, err := util.ConvertSignature(sr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(s.Signature), receiptb) {
+		return fmt.Errorf("could not verify receipt")
+	}
+
+	// Print receipt
+	printf("Action   : %v\n", action)
+	printf("Timestamp: %v\n", dateAndTimeFromUnix(sr.Timestamp))
+	printf("Receipt  : %v\n", sr.Receipt)
+
+	return nil
+}
+
+// commentSubscribeHelpMsg is printed to stdout by the help command.
+const commentSubscribeHelpMsg = `commentsubscribe "token" "commentID"
+
+Subscribe to the replies under a comment. A notification is sent for every new
+reply under the comment, including replies to replies, without subscribing to
+the rest of the comments on the record.
+
+Requires the user to be logged in.
+
+Arguments:
+1. token      (string, required)  Proposal censorship token
+2. commentID  (string, required)  Comment ID
+
+Flags:
+ --unsubscribe (bool, optional)  Unsubscribe from the comment thread.
+ --unvetted    (bool, optional)  Record is unvetted.
+
+Example usage
+$ commentsubscribe d594fbadef0f9378 3
+$ commentsubscribe --unsubscribe d594fbadef0f9378 3
+`
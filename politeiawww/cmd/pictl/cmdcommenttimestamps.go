@@ -15,6 +15,10 @@ type cmdCommentTimestamps struct {
 		Token      string   `positional-arg-name:"token" required:"true"`
 		CommentIDs []uint32 `positional-arg-name:"commentids" optional:"true"`
 	} `positional-args:"true"`
+
+	// Batch requests the timestamps of all comments using comment ID
+	// ranges instead of individual comment IDs.
+	Batch bool `long:"batch" optional:"true"`
 }
 
 // Execute executes the cmdCommentTimestamps command.
@@ -66,6 +70,18 @@ func (c *cmdCommentTimestamps) Execute(args []string) error {
 	}
 	pageSize := pr.TimestampsPageSize
 
+	// Request the timestamps using comment ID ranges if the batch
+	// flag was provided and no comment IDs were given.
+	if c.Batch && len(c.Args.CommentIDs) == 0 {
+		var latest uint32
+		for _, v := range commentIDs {
+			if v > latest {
+				latest = v
+			}
+		}
+		return commentTimestampsBatch(pc, c.Args.Token, latest, pageSize)
+	}
+
 	// Timestamps route is paginated, request timestamps page by page.
 	var (
 		pageStartIdx        int
@@ -113,6 +129,42 @@ func (c *cmdCommentTimestamps) Execute(args []string) error {
 	return nil
 }
 
+// commentTimestampsBatch retrieves and verifies the timestamps of all
+// comments up to the provided latest comment ID using comment ID ranges.
+func commentTimestampsBatch(pc *pclient.Client, token string, latest, pageSize uint32) error {
+	var fetched, totalNotTimestamped int
+	for start := uint32(1); start <= latest; start += pageSize {
+		// Get timestamps
+		t := cmv1.TimestampsBatch{
+			Token: token,
+			Ranges: []cmv1.CommentIDRange{
+				{
+					Start: start,
+					End:   start + pageSize - 1,
+				},
+			},
+		}
+		tr, err := pc.CommentTimestampsBatch(t)
+		if err != nil {
+			return err
+		}
+		fetched = fetched + len(tr.Comments)
+
+		// Verify timestamps
+		notTimestamped, err := pclient.CommentTimestampsVerify(*tr)
+		if err != nil {
+			return err
+		}
+		totalNotTimestamped = totalNotTimestamped + len(notTimestamped)
+
+		printf("Latest comment ID: %v, fetched: %v, timestamped: %v, "+
+			"not timestamped: %v \n", latest, fetched,
+			fetched-totalNotTimestamped, totalNotTimestamped)
+	}
+
+	return nil
+}
+
 // commentTimestampsHelpMsg is printed to stdout by the help command.
 const commentTimestampsHelpMsg = `commenttimestamps [flags] "token" commentIDs
 
@@ -123,10 +175,18 @@ onto the decred blockchain.
 If comment IDs are not provided then the timestamps for all comments will be
 returned. If the record is unvetted, the --unvetted flag must be used.
 
+The --batch flag requests the timestamps of all comments using comment ID
+ranges. The timestamps of records that have been censored or archived are
+cached by the server once they are final, which makes batched requests for
+these records fast.
+
 Arguments:
 1. token      (string, required)   Proposal token
 2. commentIDs ([]uint32, optional) Proposal version
 
+Flags:
+ --batch (bool, optional) Request the timestamps using comment ID ranges.
+
 Example: Fetch all record comment timestamps
 $ pictl commenttimestamps 0a265dd93e9bae6d 
 
@@ -0,0 +1,126 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdCommentTree retrieves a subtree of the comments on a record.
+type cmdCommentTree struct {
+	Args struct {
+		Token    string `positional-arg-name:"token" required:"true"`
+		ParentID uint32 `positional-arg-name:"parentid" optional:"true"`
+	} `positional-args:"true"`
+
+	// Depth and Children are used to limit the number of levels of
+	// replies and the number of replies per comment that are returned.
+	Depth    uint32 `long:"depth" optional:"true"`
+	Children uint32 `long:"children" optional:"true"`
+
+	// Sort is used to sort the replies of each comment. Options are
+	// "score", "stake", and "new".
+	Sort string `long:"sort" optional:"true"`
+}
+
+// Execute executes the cmdCommentTree command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdCommentTree) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Parse sort order
+	sorts := map[string]cmv1.SortT{
+		"":      cmv1.SortInvalid,
+		"score": cmv1.SortScore,
+		"stake": cmv1.SortStakeScore,
+		"new":   cmv1.SortTimestamp,
+	}
+	sortBy, ok := sorts[c.Sort]
+	if !ok {
+		return fmt.Errorf("invalid sort '%v'", c.Sort)
+	}
+
+	// Get comment tree
+	t := cmv1.Tree{
+		Token:    c.Args.Token,
+		ParentID: c.Args.ParentID,
+		Depth:    c.Depth,
+		Children: c.Children,
+		SortBy:   sortBy,
+	}
+	tr, err := pc.CommentTree(t)
+	if err != nil {
+		return err
+	}
+
+	// Print comment tree
+	printf("Replies to comment %v: %v\n", c.Args.ParentID, tr.Replies)
+	printCommentTree(tr.Comments, 1)
+
+	return nil
+}
+
+// printCommentTree prints the provided comment tree to stdout. Each level of
+// replies is indented further than its parent.
+func printCommentTree(tcs []cmv1.TreeComment, level int) {
+	indent := strings.Repeat("  ", level)
+	for _, v := range tcs {
+		cm := v.Comment
+		text := cm.Comment
+		if cm.Deleted {
+			text = "[deleted]"
+		}
+		printf("%v%v %v (%v %v): %v\n", indent, cm.CommentID, cm.Username,
+			int64(cm.Downvotes)*-1, cm.Upvotes, text)
+		if v.Replies > uint32(len(v.Children)) {
+			printf("%v  %v more replies\n", indent,
+				v.Replies-uint32(len(v.Children)))
+		}
+		printCommentTree(v.Children, level+1)
+	}
+}
+
+// commentTreeHelpMsg is printed to stdout by the help command.
+const commentTreeHelpMsg = `commenttree "token" "parentid"
+
+Get the subtree of comments that are replies to a parent comment. The base
+level comments of the record are returned if a parent ID is not provided.
+
+Comments that have more replies than were returned can be expanded by
+requesting their subtree. Retrieving the comments on an unvetted record
+requires the user be either an admin or the record author.
+
+Arguments:
+1. token     (string, required)  Proposal censorship token
+2. parentid  (uint32, optional)  Parent comment ID
+
+Flags:
+ --depth     (uint32, optional)  Number of levels of replies to return.
+                                 Defaults to the tree depth max policy.
+ --children  (uint32, optional)  Number of replies to return per comment.
+                                 Defaults to the tree children max policy.
+ --sort      (string, optional)  Sort the replies of each comment. Options
+                                 are "score", "stake", and "new".
+
+Example usage
+$ commenttree d594fbadef0f9378
+$ commenttree --depth=2 --children=5 --sort=score d594fbadef0f9378 3
+`
@@ -24,6 +24,10 @@ type cmdCommentVote struct {
 		CommentID uint32 `positional-arg-name:"commentID"`
 		Vote      string `positional-arg-name:"vote"`
 	} `positional-args:"true" required:"true"`
+
+	// Reaction is used to react to the comment with one of the
+	// reactions that are listed in the comments policy.
+	Reaction string `long:"reaction" optional:"true"`
 }
 
 // Execute executes the cmdCommentVote command.
@@ -66,7 +70,7 @@ func (c *cmdCommentVote) Execute(args []string) error {
 	state := cmv1.RecordStateVetted
 	msg := strconv.FormatUint(uint64(state), 10) + c.Args.Token +
 		strconv.FormatUint(uint64(c.Args.CommentID), 10) +
-		strconv.FormatInt(int64(vote), 10)
+		strconv.FormatInt(int64(vote), 10) + c.Reaction
 	sig := cfg.Identity.SignMessage([]byte(msg))
 	v := cmv1.Vote{
 		State:     state,
@@ -75,6 +79,7 @@ func (c *cmdCommentVote) Execute(args []string) error {
 		Vote:      vote,
 		Signature: hex.EncodeToString(sig[:]),
 		PublicKey: cfg.Identity.Public.String(),
+		Reaction:  c.Reaction,
 	}
 
 	// Send request
@@ -103,6 +108,9 @@ func (c *cmdCommentVote) Execute(args []string) error {
 	// Print receipt
 	printf("Downvotes: %v\n", int64(cvr.Downvotes)*-1)
 	printf("Upvotes  : %v\n", cvr.Upvotes)
+	if len(cvr.Reactions) > 0 {
+		printf("Reactions: %v\n", reactionsString(cvr.Reactions))
+	}
 	printf("Timestamp: %v\n", dateAndTimeFromUnix(cvr.Timestamp))
 	printf("Receipt  : %v\n", cvr.Receipt)
 
@@ -112,7 +120,7 @@ func (c *cmdCommentVote) Execute(args []string) error {
 // commentVoteHelpMsg is printed to stdout by the help command.
 const commentVoteHelpMsg = `commentvote "token" "commentID" "vote"
 
-Upvote or downvote a comment.
+Upvote or downvote a comment or react to a comment.
 
 Requires the user to be logged in. Votes can only be cast on vetted records.
 
@@ -121,6 +129,13 @@ Arguments:
 2. commentID  (string, required)  Comment ID
 3. vote       (string, required)  Upvote or downvote
 
+Flags:
+ --reaction   (string, optional)  React to the comment using the provided
+                                  reaction ID. The reactions are listed in
+                                  the comments policy. Reactions that are not
+                                  mapped to a downvote must be cast as an
+                                  upvote.
+
 You can specify either the numeric vote option (1 or -1) or the human readable
 vote option.
 upvote (1)
@@ -129,4 +144,5 @@ downvote (-1)
 Example usage
 $ commentvote d594fbadef0f9378 3 downvote
 $ commentvote d594fbadef0f9378 3 -1
+$ commentvote --reaction=rocket d594fbadef0f9378 3 upvote
 `
@@ -18,6 +18,7 @@ type cmdCommentVotes struct {
 
 	// Filtering options
 	UserID string `long:"userid"`
+	Since  int64  `long:"since"`
 	Page   uint32 `long:"page"`
 }
 
@@ -29,6 +30,7 @@ func (c *cmdCommentVotes) Execute(args []string) error {
 	var (
 		token  = c.Args.Token
 		userID = c.UserID
+		since  = c.Since
 		page   = c.Page
 	)
 
@@ -49,6 +51,7 @@ func (c *cmdCommentVotes) Execute(args []string) error {
 	v := cmv1.Votes{
 		Token:  token,
 		UserID: userID,
+		Since:  since,
 		Page:   page,
 	}
 	vr, err := pc.CommentVotes(v)
@@ -77,11 +80,13 @@ const commentVotesHelpMsg = `commentvotes "token"
 Get paginated comment up/downvotes of a proposal. The --userid flag can be 
 used to retrieve the votes of a specific user. The --page flag can be used 
 to retrieve a specific page, if no page is provided then the first page is 
-returned.
+returned. The --since flag can be used to only retrieve the votes that were
+cast at or after a UNIX timestamp.
 
 Arguments:
 1. token  (string, required)  Proposal censorship token
 
 Flags:
   --userid  (string, optional)  User ID
+  --since   (int64, optional)   Only return votes cast since this timestamp
   --page    (uint32, optional)  Requested page`
@@ -51,16 +51,48 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", userProposalPaywallTxHelpMsg)
 	case "userproposalcredits":
 		fmt.Printf("%s\n", userProposalCreditsHelpMsg)
+	case "userproposalcreditstransfer":
+		fmt.Printf("%s\n", userProposalCreditsTransferHelpMsg)
+	case "userpushsubscriptions":
+		fmt.Printf("%s\n", userPushSubscriptionsHelpMsg)
+	case "userpushsubscribe":
+		fmt.Printf("%s\n", userPushSubscribeHelpMsg)
+	case "userpushunsubscribe":
+		fmt.Printf("%s\n", userPushUnsubscribeHelpMsg)
+	case "userusage":
+		fmt.Printf("%s\n", userUsageHelpMsg)
+	case "usersusage":
+		fmt.Printf("%s\n", usersUsageHelpMsg)
+	case "changes":
+		fmt.Printf("%s\n", changesHelpMsg)
+	case "status":
+		fmt.Printf("%s\n", statusHelpMsg)
+	case "banners":
+		fmt.Printf("%s\n", bannersHelpMsg)
+	case "bannerset":
+		fmt.Printf("%s\n", bannerSetHelpMsg)
+	case "bannerdel":
+		fmt.Printf("%s\n", bannerDelHelpMsg)
 	case "userpaymentsrescan":
 		fmt.Printf("%s\n", userPaymentsRescanHelpMsg)
 	case "usermanage":
 		fmt.Printf("%s\n", shared.UserManageHelpMsg)
 	case "userkeyupdate":
 		fmt.Printf("%s\n", shared.UserKeyUpdateHelpMsg)
+	case "userkeyrevoke":
+		fmt.Printf("%s\n", shared.UserKeyRevokeHelpMsg)
+	case "identityexport":
+		fmt.Printf("%s\n", identityExportHelpMsg)
+	case "identityimport":
+		fmt.Printf("%s\n", identityImportHelpMsg)
 	case "userverificationresend":
 		fmt.Printf("%s\n", userVerificationResendHelpMsg)
 	case "userusernamechange":
 		fmt.Printf("%s\n", shared.UserUsernameChangeHelpMsg)
+	case "useremailchange":
+		fmt.Printf("%s\n", shared.UserEmailChangeHelpMsg)
+	case "useremailchangeverify":
+		fmt.Printf("%s\n", shared.UserEmailChangeVerifyHelpMsg)
 	case "userpasswordchange":
 		fmt.Printf("%s\n", shared.UserPasswordChangeHelpMsg)
 	case "userpasswordreset":
@@ -81,6 +113,10 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", proposalSetBillingStatusHelpMsg)
 	case "proposalbillingstatuschanges":
 		fmt.Printf("%s\n", proposalBillingStatusChangesHelpMsg)
+	case "billingverify":
+		fmt.Printf("%s\n", billingVerifyHelpMsg)
+	case "proposalwithdraw":
+		fmt.Printf("%s\n", proposalWithdrawHelpMsg)
 	case "proposaldetails":
 		fmt.Printf("%s\n", proposalDetailsHelpMsg)
 	case "proposaltimestamps":
@@ -89,6 +125,8 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", proposalsHelpMsg)
 	case "proposalsummaries":
 		fmt.Printf("%s\n", proposalSummariesHelpMsg)
+	case "proposallist":
+		fmt.Printf("%s\n", proposalListHelpMsg)
 	case "proposalinv":
 		fmt.Printf("%s\n", proposalInvHelpMsg)
 	case "proposalinvordered":
@@ -109,16 +147,38 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", commentEditHelpMsg)
 	case "commentvote":
 		fmt.Printf("%s\n", commentVoteHelpMsg)
+	case "commentsubscribe":
+		fmt.Printf("%s\n", commentSubscribeHelpMsg)
+	case "commentpin":
+		fmt.Printf("%s\n", commentPinHelpMsg)
+	case "commentstakeproof":
+		fmt.Printf("%s\n", commentStakeProofHelpMsg)
+	case "usercomments":
+		fmt.Printf("%s\n", userCommentsHelpMsg)
+	case "commenthistory":
+		fmt.Printf("%s\n", commentHistoryHelpMsg)
 	case "commentcensor":
 		fmt.Printf("%s\n", commentCensorHelpMsg)
+	case "commentcensoruser":
+		fmt.Printf("%s\n", commentCensorUserHelpMsg)
+	case "commentdel":
+		fmt.Printf("%s\n", commentDelHelpMsg)
 	case "commentcount":
 		fmt.Printf("%s\n", commentCountHelpMsg)
 	case "comments":
 		fmt.Printf("%s\n", commentsHelpMsg)
+	case "commenttree":
+		fmt.Printf("%s\n", commentTreeHelpMsg)
+	case "commentattachments":
+		fmt.Printf("%s\n", commentAttachmentsHelpMsg)
+	case "commentsearch":
+		fmt.Printf("%s\n", commentSearchHelpMsg)
 	case "commentvotes":
 		fmt.Printf("%s\n", commentVotesHelpMsg)
 	case "commenttimestamps":
 		fmt.Printf("%s\n", commentTimestampsHelpMsg)
+	case "commentstatus":
+		fmt.Printf("%s\n", commentStatusHelpMsg)
 
 	// Vote commands
 	case "votepolicy":
@@ -127,8 +187,22 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", voteAuthorizeHelpMsg)
 	case "votestart":
 		fmt.Printf("%s\n", voteStartHelpMsg)
+	case "voteextend":
+		fmt.Printf("%s\n", voteExtendHelpMsg)
+	case "votecancel":
+		fmt.Printf("%s\n", voteCancelHelpMsg)
+	case "voterebuild":
+		fmt.Printf("%s\n", voteRebuildHelpMsg)
+	case "votesnapshotverify":
+		fmt.Printf("%s\n", voteSnapshotVerifyHelpMsg)
+	case "votesetduration":
+		fmt.Printf("%s\n", voteSetDurationHelpMsg)
+	case "voteparamsverify":
+		fmt.Printf("%s\n", voteParamsVerifyHelpMsg)
 	case "castballot":
 		fmt.Printf("%s\n", castBallotHelpMsg)
+	case "castballotstatus":
+		fmt.Printf("%s\n", castBallotStatusHelpMsg)
 	case "votedetails":
 		fmt.Printf("%s\n", voteDetailsHelpMsg)
 	case "voteresults":
@@ -141,6 +215,16 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", voteInvHelpMsg)
 	case "votetimestamps":
 		fmt.Printf("%s\n", voteTimestampsHelpMsg)
+	case "votestats":
+		fmt.Printf("%s\n", voteStatsHelpMsg)
+	case "voteexport":
+		fmt.Printf("%s\n", voteExportHelpMsg)
+	case "voteauditbundle":
+		fmt.Printf("%s\n", voteAuditBundleHelpMsg)
+	case "votereceipts":
+		fmt.Printf("%s\n", voteReceiptsHelpMsg)
+	case "votereceipt":
+		fmt.Printf("%s\n", voteReceiptHelpMsg)
 
 	// Dev commands
 	case "sendfaucettx":
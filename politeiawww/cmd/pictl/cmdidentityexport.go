@@ -0,0 +1,80 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// cmdIdentityExport exports the identity of the logged in user to an
+// encrypted backup file and prints the mnemonic of the identity.
+type cmdIdentityExport struct {
+	Args struct {
+		File string `positional-arg-name:"file" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdIdentityExport command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdIdentityExport) Execute(args []string) error {
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Prompt the user for the backup passphrase
+	pass, err := promptPassphrase("Enter a passphrase to encrypt the " +
+		"identity backup")
+	if err != nil {
+		return err
+	}
+	confirm, err := promptPassphrase("Confirm passphrase")
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pass, confirm) {
+		return fmt.Errorf("passphrases do not match")
+	}
+
+	// Save the encrypted identity backup
+	b, err := newIdentityBackup(cfg.Identity, pass)
+	if err != nil {
+		return err
+	}
+	err = saveIdentityBackup(c.Args.File, b)
+	if err != nil {
+		return err
+	}
+
+	printf("Identity backup saved to %v\n", c.Args.File)
+	printf("Public key: %v\n", b.PublicKey)
+	printf("\n")
+	printf("The identity mnemonic can be used to restore the identity " +
+		"without the backup\nfile or passphrase. Write it down and " +
+		"store it in a safe place. Anyone with\nthe mnemonic can sign " +
+		"messages as you.\n\n")
+	printf("%v\n", identityMnemonic(cfg.Identity))
+
+	return nil
+}
+
+// identityExportHelpMsg is printed to stdout by the help command.
+const identityExportHelpMsg = `identityexport "file"
+
+Export the identity of the logged in user to an encrypted backup file. The
+identity is encrypted using a passphrase that the user is prompted for. The
+backup file can be restored using the identityimport command.
+
+The mnemonic of the identity is also printed. The mnemonic is not encrypted
+and can be used to restore the identity without the backup file.
+
+Losing the identity means losing the ability to edit your proposals and
+comments using this identity.
+
+Arguments:
+1. file  (string, required)  File path to save the identity backup to`
@@ -0,0 +1,106 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+)
+
+// cmdIdentityImport restores the identity of the logged in user from an
+// identity backup file or from the identity mnemonic.
+type cmdIdentityImport struct {
+	Args struct {
+		File string `positional-arg-name:"file"`
+	} `positional-args:"true"`
+
+	// Mnemonic instructs the command to prompt for the identity
+	// mnemonic instead of reading an identity backup file.
+	Mnemonic bool `long:"mnemonic" optional:"true"`
+}
+
+// Execute executes the cmdIdentityImport command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdIdentityImport) Execute(args []string) error {
+	switch {
+	case c.Mnemonic && c.Args.File != "":
+		return fmt.Errorf("--mnemonic cannot be used with a backup file")
+	case !c.Mnemonic && c.Args.File == "":
+		return fmt.Errorf("a backup file or the --mnemonic flag is required")
+	}
+
+	// Get the logged in user. The identity is saved for this user.
+	me, err := client.Me()
+	if err != nil {
+		return err
+	}
+
+	// Restore the identity
+	var id *identity.FullIdentity
+	if c.Mnemonic {
+		printf("Enter the identity mnemonic: ")
+		r := bufio.NewReader(os.Stdin)
+		mnemonic, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		id, err = identityFromMnemonic(mnemonic)
+		if err != nil {
+			return err
+		}
+	} else {
+		b, err := loadIdentityBackup(c.Args.File)
+		if err != nil {
+			return err
+		}
+		pass, err := promptPassphrase("Enter the identity backup passphrase")
+		if err != nil {
+			return err
+		}
+		id, err = b.decrypt(pass)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Verify that the identity is the user's active identity
+	if id.Public.String() != me.PublicKey {
+		return fmt.Errorf("identity %v is not the active identity of %v; "+
+			"the active identity is %v", id.Public.String(), me.Username,
+			me.PublicKey)
+	}
+
+	// Save the identity
+	err = cfg.SaveIdentity(me.Username, id)
+	if err != nil {
+		return err
+	}
+
+	printf("Identity restored for %v: %v\n", me.Username, id.Public.String())
+
+	return nil
+}
+
+// identityImportHelpMsg is printed to stdout by the help command.
+const identityImportHelpMsg = `identityimport "file"
+
+Restore the identity of the logged in user from an identity backup file that
+was created using the identityexport command. The user is prompted for the
+backup passphrase.
+
+The --mnemonic flag can be used to restore the identity from the identity
+mnemonic instead of a backup file. The user is prompted for the mnemonic.
+
+The restored identity must be the active identity of the logged in user.
+
+Arguments:
+1. file  (string, optional)  Identity backup file
+
+Flags:
+ --mnemonic  (bool, optional)  Restore the identity from its mnemonic`
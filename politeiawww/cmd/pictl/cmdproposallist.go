@@ -0,0 +1,89 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdProposalList retrieves the proposal list data for the provided proposal
+// tokens.
+type cmdProposalList struct {
+	Args struct {
+		Tokens []string `positional-arg-name:"tokens"`
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the cmdProposalList command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdProposalList) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get proposal list
+	pl := piv1.ProposalList{
+		Tokens: c.Args.Tokens,
+	}
+	plr, err := pc.PiProposalList(pl)
+	if err != nil {
+		return err
+	}
+
+	// Print proposal list
+	for k, v := range plr.Proposals {
+		printProposalListItem(k, v)
+		printf("-----\n")
+	}
+
+	return nil
+}
+
+// printProposalListItem prints a proposal list item.
+func printProposalListItem(token string, p piv1.ProposalListItem) {
+	var name string
+	pm, err := pclient.ProposalMetadataDecode(p.Record.Files)
+	if err == nil && pm != nil {
+		name = pm.Name
+	}
+	printf("Token      : %v\n", token)
+	printf("Name       : %v\n", name)
+	printf("Username   : %v\n", p.Record.Username)
+	printf("Version    : %v\n", p.Record.Version)
+	printf("State      : %v\n", rcv1.RecordStates[p.Record.State])
+	printf("Status     : %v\n", rcv1.RecordStatuses[p.Record.Status])
+	printf("Timestamp  : %v\n", dateAndTimeFromUnix(p.Record.Timestamp))
+	printf("Pi status  : %v\n", p.Summary.Status)
+	printf("Vote status: %v\n", tkv1.VoteStatuses[p.VoteSummary.Status])
+	printf("Comments   : %v\n", p.Comments)
+}
+
+// proposalListHelpMsg is printed to stdout by the help command.
+const proposalListHelpMsg = `proposallist "tokens..."
+
+Fetch the data that is required to render the proposal list for the provided
+tokens. This includes the abridged proposal record, the proposal summary, the
+vote summary, and the comment count of each proposal. This command accepts
+both full length tokens and token prefixes.
+
+The files of unvetted proposals are only returned to admins and the proposal
+author.
+
+Example usage:
+$ pictl proposallist cda97ace0a476514 71dd3a110500fb6a
+$ pictl proposallist cda97ac 71dd3a1`
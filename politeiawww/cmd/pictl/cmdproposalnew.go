@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"path/filepath"
 	"time"
 
 	"github.com/decred/politeia/politeiad/api/v1/mime"
@@ -49,6 +50,12 @@ type cmdProposalNew struct {
 	// RandomImages generates random image attachments. The Attachments
 	// argument is not allowed when using this flag.
 	RandomImages bool `long:"randomimages" optional:"true"`
+
+	// FromRepo is the path to a proposal repo. The proposal files and
+	// metadata are read from the repo and the token of the submitted
+	// proposal is written back to the repo. The IndexFile and
+	// Attachments arguments are not allowed when using this flag.
+	FromRepo string `long:"from-repo" optional:"true"`
 }
 
 // Execute executes the cmdProposalNew command.
@@ -69,6 +76,50 @@ func proposalNew(c *cmdProposalNew) (*rcv1.Record, error) {
 	indexFile := c.Args.IndexFile
 	attachments := c.Args.Attachments
 
+	// Load the proposal repo
+	var repo *proposalRepo
+	if c.FromRepo != "" {
+		if c.Random || indexFile != "" || len(attachments) > 0 {
+			return nil, fmt.Errorf("you cannot provide proposal files or " +
+				"use the --random flag when using the --from-repo flag")
+		}
+		var err error
+		repo, err = loadProposalRepo(c.FromRepo)
+		if err != nil {
+			return nil, err
+		}
+		if repo.Token != "" {
+			return nil, fmt.Errorf("the proposal in %v has already been "+
+				"submitted; token %v", repo.Dir, repo.Token)
+		}
+		indexFile = repo.IndexFile
+		attachments = repo.Attachments
+
+		// Metadata flags take precedence over the repo metadata
+		m := repo.Metadata
+		if c.Name == "" {
+			c.Name = m.Name
+		}
+		if c.Amount == 0 {
+			c.Amount = m.Amount
+		}
+		if c.StartDate == "" {
+			c.StartDate = m.StartDate
+		}
+		if c.EndDate == "" {
+			c.EndDate = m.EndDate
+		}
+		if c.Domain == "" {
+			c.Domain = m.Domain
+		}
+		if c.LinkTo == "" {
+			c.LinkTo = m.LinkTo
+		}
+		if c.LinkBy == "" && !c.RFP {
+			c.LinkBy = m.LinkBy
+		}
+	}
+
 	// Verify args and flags
 	switch {
 	case !c.Random && indexFile == "":
@@ -185,6 +236,14 @@ func proposalNew(c *cmdProposalNew) (*rcv1.Record, error) {
 		}
 	}
 
+	// Verify the repo proposal against the policy before it's signed
+	if repo != nil {
+		err = proposalRepoVerify(files, pm, *pr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	pmb, err := json.Marshal(pm)
 	if err != nil {
 		return nil, err
@@ -264,6 +323,17 @@ func proposalNew(c *cmdProposalNew) (*rcv1.Record, error) {
 	printf("Merkle : %v\n", nr.Record.CensorshipRecord.Merkle)
 	printf("Receipt: %v\n", nr.Record.CensorshipRecord.Signature)
 
+	// Write the token back to the proposal repo
+	if repo != nil {
+		err = repo.saveToken(nr.Record.CensorshipRecord.Token)
+		if err != nil {
+			return nil, fmt.Errorf("proposal submitted but the token could "+
+				"not be saved to the repo: %v", err)
+		}
+		printf("Token written to %v\n",
+			filepath.Join(repo.Dir, proposalRepoTokenFile))
+	}
+
 	return &nr.Record, nil
 }
 
@@ -315,6 +385,25 @@ Flags:
  --randomimages (bool)   Generate random attachments. The attachments argument
                          is not allowed when using this flag.
 
+ --from-repo    (string) Path to a proposal repo. The repo is a directory,
+                         typically a git repository, that contains an
+                         index.md file, the proposal attachments, and a
+                         metadata.yaml file. The proposal is validated against
+                         the pi policy before it is signed and submitted. The
+                         token of the submitted proposal is written to a
+                         "token" file in the repo. Metadata flags take
+                         precedence over the metadata.yaml fields. The
+                         arguments are not allowed when using this flag.
+
+metadata.yaml fields:
+  name:      (string) Name of the proposal.
+  amount:    (int)    Funding amount in cents.
+  startdate: (string) Start Date, Format: "01/02/2006"
+  enddate:   (string) End Date, Format: "01/02/2006"
+  domain:    (string) Proposal domain.
+  linkto:    (string) Token of an existing public proposal to link to.
+  linkby:    (string) Linkby deadline as a duration from the current time.
+
 Examples:
 
 # Set linkby 24 hours from current time
@@ -322,4 +411,7 @@ $ pictl proposalnew --random --linkby=24h
 
 # Use --rfp to set the linky 1 month from current time
 $ pictl proposalnew --rfp index.md proposalmetadata.json
+
+# Submit the proposal that is in a git repo
+$ pictl proposalnew --from-repo ~/proposals/myproposal
 `
@@ -5,13 +5,17 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 
 	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
 	pclient "github.com/decred/politeia/politeiawww/client"
 	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
 )
 
 // cmdProposalSetBillingStatus sets the status of a proposal.
@@ -21,6 +25,11 @@ type cmdProposalSetBillingStatus struct {
 		Status string `positional-arg-name:"status" required:"true"`
 		Reason string `positional-arg-name:"reason"`
 	} `positional-args:"true"`
+
+	// Report is the path to a completion report file that will be
+	// attached to the billing status change. A completion report can
+	// only be attached when the billing status is set to completed.
+	Report string `long:"report" optional:"true"`
 }
 
 // Execute executes the cmdProposalSetBillingStatus command.
@@ -53,13 +62,34 @@ func (c *cmdProposalSetBillingStatus) Execute(args []string) error {
 		return err
 	}
 
+	// Load the completion report
+	var (
+		report       *piv1.ReportFile
+		reportDigest string
+	)
+	if c.Report != "" {
+		b, err := os.ReadFile(c.Report)
+		if err != nil {
+			return err
+		}
+		reportDigest = hex.EncodeToString(util.Digest(b))
+		report = &piv1.ReportFile{
+			Name:    filepath.Base(c.Report),
+			MIME:    "text/plain; charset=utf-8",
+			Digest:  reportDigest,
+			Payload: base64.StdEncoding.EncodeToString(b),
+		}
+	}
+
 	// Setup request
-	msg := c.Args.Token + strconv.Itoa(int(status)) + c.Args.Reason
+	msg := c.Args.Token + strconv.Itoa(int(status)) + c.Args.Reason +
+		reportDigest
 	sig := cfg.Identity.SignMessage([]byte(msg))
 	sbs := piv1.SetBillingStatus{
 		Token:     c.Args.Token,
 		Status:    status,
 		Reason:    c.Args.Reason,
+		Report:    report,
 		PublicKey: cfg.Identity.Public.String(),
 		Signature: hex.EncodeToString(sig[:]),
 	}
@@ -116,11 +146,19 @@ Valid statuses:
   (2) close
   (3) complete
 
-The following statuses require a billing status change reason to be included:
+The following statuses require a billing status change reason to be included
+by default. The statuses that require a reason are configurable by the server
+and are returned by the pi policy command.
   close
 
 Arguments:
 1. token   (string, required)   Proposal censorship token
 2. status  (string, required)   New billing status
 3. reason  (string, optional)   Billing status change reason
+
+Flags:
+ --report  (string, optional)   Path to a plain text completion report that
+                                will be attached to the billing status change.
+                                Only allowed when setting the status to
+                                completed.
 `
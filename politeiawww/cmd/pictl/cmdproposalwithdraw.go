@@ -0,0 +1,96 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"strconv"
+
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// cmdProposalWithdraw withdraws a proposal on behalf of its author.
+type cmdProposalWithdraw struct {
+	Args struct {
+		Token  string `positional-arg-name:"token" required:"true"`
+		Reason string `positional-arg-name:"reason" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdProposalWithdraw command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdProposalWithdraw) Execute(args []string) error {
+	// Verify user identity. This will be needed to sign the
+	// withdrawal.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get the full length token and the version of the proposal
+	r, err := pc.RecordDetails(rcv1.Details{
+		Token: c.Args.Token,
+	})
+	if err != nil {
+		return err
+	}
+	token := r.CensorshipRecord.Token
+
+	// Setup request. The withdrawal is signed using the record status
+	// change message for the archived status.
+	msg := token + strconv.FormatUint(uint64(r.Version), 10) +
+		strconv.Itoa(int(rcv1.RecordStatusArchived)) + c.Args.Reason
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	w := piv1.Withdraw{
+		Token:     token,
+		Version:   r.Version,
+		Reason:    c.Args.Reason,
+		PublicKey: cfg.Identity.Public.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	wr, err := pc.PiWithdraw(w)
+	if err != nil {
+		return err
+	}
+
+	// Print the withdrawal
+	printf("Proposal %v withdrawn\n", token)
+	printf("Credit refunded: %v\n", wr.CreditRefunded)
+	printf("Timestamp      : %v\n", dateAndTimeFromUnix(wr.Timestamp))
+	printf("Receipt        : %v\n", wr.Receipt)
+
+	return nil
+}
+
+// proposalWithdrawHelpMsg is printed to stdout by the help command.
+const proposalWithdrawHelpMsg = `proposalwithdraw "token" "reason"
+
+Withdraw a proposal. Only the proposal author can withdraw a proposal and only
+while it is under review, i.e. before the proposal vote has been authorized.
+The proposal is archived and the proposal credit that was spent on the
+proposal is refunded to the author.
+
+Arguments:
+1. token   (string, required)   Proposal censorship token
+2. reason  (string, required)   Reason for withdrawing the proposal
+`
@@ -0,0 +1,85 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdUserComments retrieves the comments that were made by a user.
+type cmdUserComments struct {
+	Args struct {
+		UserID string `positional-arg-name:"userid" required:"true"`
+	} `positional-args:"true"`
+
+	// Filtering options
+	Token    string `long:"token" optional:"true"`
+	Comments bool   `long:"comments" optional:"true"`
+}
+
+// Execute executes the cmdUserComments command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdUserComments) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get user comments
+	uc := cmv1.UserComments{
+		UserID:          c.Args.UserID,
+		Token:           c.Token,
+		IncludeComments: c.Comments,
+	}
+	ucr, err := pc.CommentsUser(uc)
+	if err != nil {
+		return err
+	}
+
+	// Print comments
+	if len(ucr.Records) == 0 {
+		printf("No comments found for user %v\n", c.Args.UserID)
+		return nil
+	}
+	for _, v := range ucr.Records {
+		printf("Token   : %v\n", v.Token)
+		printf("State   : %v\n", rcv1.RecordStates[rcv1.RecordStateT(v.State)])
+		printf("Comments: %v\n", v.CommentIDs)
+		for _, cm := range v.Comments {
+			printComment(cm)
+		}
+		printf("-----\n")
+	}
+
+	return nil
+}
+
+// userCommentsHelpMsg is printed to stdout by the help command.
+const userCommentsHelpMsg = `usercomments "userid"
+
+Get the IDs of the comments that were made by a user. The comments made on all
+records are returned unless the --token flag is used to specify a record. The
+--comments flag can be used to also return the latest version of each comment.
+
+Comments that were made on unvetted records are only returned to admins and to
+the user that made them.
+
+Arguments:
+1. userid  (string, required)  User ID
+
+Flags:
+  --token     (string, optional)  Only return comments made on this record
+  --comments  (bool, optional)    Return the comments, not just the IDs`
@@ -0,0 +1,89 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteAuditBundle retrieves the signed audit bundle of a finished proposal
+// vote and saves it to disk.
+type cmdVoteAuditBundle struct {
+	Args struct {
+		Token string `positional-arg-name:"token" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteAuditBundle command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteAuditBundle) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get the audit bundle
+	abr, err := pc.TicketVoteAuditBundle(tkv1.AuditBundle{
+		Token: c.Args.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Verify the audit bundle
+	ab, err := pclient.AuditBundleVerify(*abr)
+	if err != nil {
+		return err
+	}
+
+	// Save the audit bundle
+	b, err := json.Marshal(abr)
+	if err != nil {
+		return err
+	}
+	fp := fmt.Sprintf("%v-vote-audit.json", ab.Token)
+	err = os.WriteFile(fp, b, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Print the audit bundle summary
+	printf("Token            : %v\n", ab.Token)
+	printf("Status           : %v\n", tkv1.VoteStatuses[ab.Summary.Status])
+	printf("Eligible tickets : %v\n", len(ab.Details.EligibleTickets))
+	printf("Cast votes       : %v\n", len(ab.Votes))
+	printf("Anchored         : %v\n", ab.Anchored)
+	printf("Server public key: %v\n", abr.PublicKey)
+	printf("Audit bundle saved to %v\n", fp)
+
+	return nil
+}
+
+// voteAuditBundleHelpMsg is printed to stdout by the help command.
+const voteAuditBundleHelpMsg = `voteauditbundle "token"
+
+Retrieve the audit bundle of a finished proposal vote. The bundle contains the
+vote details, the eligible tickets, all cast votes, and the timestamps of the
+vote data, and is signed by the server. It can be verified offline using
+politeiaverify.
+
+The bundle is saved to the current directory as [token]-vote-audit.json. The
+timestamps of a recently finished vote may not have been anchored yet. The
+bundle can be fetched again later to get the full inclusion proofs.
+
+Arguments:
+1. token  (string, required)  Proposal censorship token`
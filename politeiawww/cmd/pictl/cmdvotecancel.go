@@ -0,0 +1,104 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
+)
+
+// cmdVoteCancel cancels a started ticket vote that has not had any ballots
+// cast.
+type cmdVoteCancel struct {
+	Args struct {
+		Token  string `positional-arg-name:"token" required:"true"`
+		Reason string `positional-arg-name:"reason" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteCancel command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteCancel) Execute(args []string) error {
+	// Verify user identity. An identity is required to sign the vote
+	// cancellation.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup request
+	msg := c.Args.Token + c.Args.Reason
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	tc := tkv1.Cancel{
+		Token:     c.Args.Token,
+		Reason:    c.Args.Reason,
+		PublicKey: cfg.Identity.Public.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	cr, err := pc.TicketVoteCancel(tc)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	serverID, err := identity.PublicIdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	s, err := util.ConvertSignature(cr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(tc.Signature), s) {
+		return fmt.Errorf("could not verify receipt")
+	}
+
+	// Print receipt
+	printf("Token         : %v\n", tc.Token)
+	printf("Reason        : %v\n", tc.Reason)
+	printf("Timestamp     : %v\n", dateAndTimeFromUnix(cr.Timestamp))
+	printf("Receipt       : %v\n", cr.Receipt)
+
+	return nil
+}
+
+// voteCancelHelpMsg is printed to stdout by the help command.
+const voteCancelHelpMsg = `votecancel "token" "reason"
+
+Cancel a started ticket vote. Requires admin privileges.
+
+A vote can only be cancelled if no ballots have been cast on it. This is
+intended to be used when a vote was started by mistake or with the wrong
+parameters. The proposal is returned to the authorized vote status and the
+vote can be started again. Runoff votes cannot be cancelled.
+
+Arguments:
+1. token   (string, required)  Record token.
+2. reason  (string, required)  Justification for the cancellation.`
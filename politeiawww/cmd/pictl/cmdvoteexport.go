@@ -0,0 +1,93 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteExport retrieves the governance export of all finished votes.
+type cmdVoteExport struct {
+	Offset uint32 `long:"offset" optional:"true"`
+	Out    string `long:"out" optional:"true"`
+}
+
+// Execute executes the cmdVoteExport command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteExport) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get the export
+	ger, err := pc.TicketVoteGovernanceExport(tkv1.GovernanceExport{
+		Offset: c.Offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Verify the export against the manifest when the full export
+	// was downloaded.
+	m := ger.Manifest
+	if c.Offset == 0 {
+		digest := sha256.Sum256([]byte(ger.Data))
+		if hex.EncodeToString(digest[:]) != m.SHA256 {
+			return fmt.Errorf("export digest does not match the manifest")
+		}
+	}
+
+	// Print the manifest
+	printf("Schema version: %v\n", m.SchemaVersion)
+	printf("Format        : %v\n", m.Format)
+	printf("Votes         : %v\n", m.Count)
+	printf("Size          : %v bytes\n", m.Size)
+	printf("SHA256        : %v\n", m.SHA256)
+	printf("Updated       : %v\n", time.Unix(m.Updated, 0).UTC())
+
+	// Save or print the export data
+	if c.Out == "" {
+		printf("%v", ger.Data)
+		return nil
+	}
+	err = os.WriteFile(c.Out, []byte(ger.Data), 0644)
+	if err != nil {
+		return err
+	}
+	printf("Export saved to %v\n", c.Out)
+
+	return nil
+}
+
+// voteExportHelpMsg is printed to stdout by the help command.
+const voteExportHelpMsg = `voteexport
+
+Get the governance export of all finished votes. The export contains one JSON
+encoded vote summary per line (NDJSON), ordered by when the vote finished. New
+votes are only ever appended to the export, so the votes that finished since a
+previous download can be retrieved using the --offset flag.
+
+The digest of the export is verified against the manifest when the full export
+is downloaded.
+
+Flags:
+ --offset (uint32, optional) Number of votes to skip.
+ --out    (string, optional) File to save the export data to. The data is
+                             printed to stdout if this is not provided.`
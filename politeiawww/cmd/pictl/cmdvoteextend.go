@@ -0,0 +1,109 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
+)
+
+// cmdVoteExtend extends the end block height of an active ticket vote.
+type cmdVoteExtend struct {
+	Args struct {
+		Token          string `positional-arg-name:"token" required:"true"`
+		EndBlockHeight uint32 `positional-arg-name:"endblockheight" required:"true"`
+		Reason         string `positional-arg-name:"reason" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteExtend command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteExtend) Execute(args []string) error {
+	// Verify user identity. An identity is required to sign the vote
+	// extension.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup request
+	msg := c.Args.Token +
+		strconv.FormatUint(uint64(c.Args.EndBlockHeight), 10) + c.Args.Reason
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	e := tkv1.Extend{
+		Token:          c.Args.Token,
+		EndBlockHeight: c.Args.EndBlockHeight,
+		Reason:         c.Args.Reason,
+		PublicKey:      cfg.Identity.Public.String(),
+		Signature:      hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	er, err := pc.TicketVoteExtend(e)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	serverID, err := identity.PublicIdentityFromString(vr.PubKey)
+	if err != nil {
+		return err
+	}
+	s, err := util.ConvertSignature(er.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(e.Signature), s) {
+		return fmt.Errorf("could not verify receipt")
+	}
+
+	// Print receipt
+	printf("Token         : %v\n", e.Token)
+	printf("End block     : %v\n", e.EndBlockHeight)
+	printf("Reason        : %v\n", e.Reason)
+	printf("Timestamp     : %v\n", dateAndTimeFromUnix(er.Timestamp))
+	printf("Receipt       : %v\n", er.Receipt)
+
+	return nil
+}
+
+// voteExtendHelpMsg is printed to stdout by the help command.
+const voteExtendHelpMsg = `voteextend "token" endblockheight "reason"
+
+Extend the end block height of an active ticket vote. Requires admin
+privileges.
+
+The new end block height must be greater than the current end block height
+of the vote and the total extension cannot exceed the voteextensionmax
+policy. Runoff votes cannot be extended.
+
+Arguments:
+1. token           (string, required)  Record token.
+2. endblockheight  (uint32, required)  New vote end block height.
+3. reason          (string, required)  Justification for the extension.`
@@ -19,6 +19,13 @@ type cmdVoteInv struct {
 		Status string `positional-arg-name:"status"`
 		Page   uint32 `positional-arg-name:"page"`
 	} `positional-args:"true" optional:"true"`
+
+	// PageSize is the number of tokens to return for each status.
+	PageSize uint32 `long:"pagesize" optional:"true"`
+
+	// StartedAfter filters the inventory by the vote start block
+	// height.
+	StartedAfter uint32 `long:"startedafter" optional:"true"`
 }
 
 // Execute executes the cmdVoteInv command.
@@ -65,8 +72,10 @@ func voteInv(c *cmdVoteInv) (map[string][]string, error) {
 
 	// Get vote inventory
 	i := tkv1.Inventory{
-		Status: status,
-		Page:   c.Args.Page,
+		Status:       status,
+		Page:         c.Args.Page,
+		PageSize:     c.PageSize,
+		StartedAfter: c.StartedAfter,
 	}
 	ir, err := pc.TicketVoteInventory(i)
 	if err != nil {
@@ -122,10 +131,10 @@ Inventory requests the tokens of public records in the inventory categorized by
 vote status.
 
 The status and page arguments can be provided to request a specific page of
-record tokens.
+record tokens. Page 1 is returned if no page is provided.
 
-If no status is provided then a page of tokens for all statuses will be
-returned. The page argument will be ignored.
+If no status is provided then the requested page of tokens for all statuses
+will be returned.
 
 Valid statuses:
   ("1") "unauthorized"
@@ -138,4 +147,10 @@ Valid statuses:
 Arguments:
 1. status (string, optional) Status of tokens being requested.
 2. page   (uint32, optional) Page number.
+
+Flags:
+  --pagesize     (uint32, optional) Number of tokens to return for each
+                                    status. Defaults to the max page size.
+  --startedafter (uint32, optional) Only return records whose vote was
+                                    started after this block height.
 `
@@ -0,0 +1,83 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteParamsVerify verifies a set of vote params without starting a vote.
+type cmdVoteParamsVerify struct {
+	Args struct {
+		Params string `positional-arg-name:"params" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteParamsVerify command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteParamsVerify) Execute(args []string) error {
+	// Read the vote params
+	b, err := os.ReadFile(c.Args.Params)
+	if err != nil {
+		return err
+	}
+	var vp tkv1.VoteParams
+	err = json.Unmarshal(b, &vp)
+	if err != nil {
+		return fmt.Errorf("unmarshal vote params: %v", err)
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	vpvr, err := pc.TicketVoteVoteParamsVerify(tkv1.VoteParamsVerify{
+		Params: vp,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Print results
+	printf("Valid: %v\n", vpvr.Valid)
+	if vpvr.ErrorCode != 0 {
+		printf("Error: %v %v\n", vpvr.ErrorCode, vpvr.ErrorContext)
+	}
+	for _, v := range vpvr.VoteBitErrors {
+		printf("Vote bit error: option %v bit 0x%x mask 0x%x: %v\n",
+			v.OptionID, v.Bit, v.Mask, tkv1.VoteBitErrors[v.ErrorCode])
+	}
+
+	return nil
+}
+
+// voteParamsVerifyHelpMsg is printed to stdout by the help command.
+const voteParamsVerifyHelpMsg = `voteparamsverify "params"
+
+Verify a set of vote params without starting a vote. The params are subject to
+the same validation as the params of a vote start, excluding the record token
+and version. The first error that a vote start would fail with is printed along
+with all of the vote bit errors, which identify the vote option, bit and mask
+of each invalid vote bit.
+
+Arguments:
+1. params (string, required) Path to a JSON file that contains the vote
+                             params.`
@@ -0,0 +1,66 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteRebuild rebuilds the active votes cache.
+type cmdVoteRebuild struct{}
+
+// Execute executes the cmdVoteRebuild command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteRebuild) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	avr, err := pc.TicketVoteActiveVotesRebuild(tkv1.ActiveVotesRebuild{})
+	if err != nil {
+		return err
+	}
+
+	// Print results
+	printf("Active votes: %v\n", len(avr.Tokens))
+	for _, v := range avr.Tokens {
+		printf("  %v\n", v)
+	}
+	printf("Added       : %v\n", len(avr.Added))
+	for _, v := range avr.Added {
+		printf("  %v\n", v)
+	}
+	printf("Removed     : %v\n", len(avr.Removed))
+	for _, v := range avr.Removed {
+		printf("  %v\n", v)
+	}
+
+	return nil
+}
+
+// voteRebuildHelpMsg is printed to stdout by the help command.
+const voteRebuildHelpMsg = `voterebuild
+
+Rebuild the active votes cache that is used to validate cast ballots. Requires
+admin privileges.
+
+The cache is rebuilt using the records that have a vote status of started.
+This can be used to repair the cache without restarting politeiad if it has
+gotten out of sync with the stored vote data. The records that were missing
+from and that were removed from the cache are printed.
+
+Arguments: None`
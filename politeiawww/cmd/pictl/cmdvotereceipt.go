@@ -0,0 +1,82 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteReceipt retrieves the cast vote of a single ticket and verifies the
+// server receipt.
+type cmdVoteReceipt struct {
+	Args struct {
+		Token  string `positional-arg-name:"token" required:"true"`
+		Ticket string `positional-arg-name:"ticket" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteReceipt command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteReceipt) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get the cast vote
+	v := tkv1.Vote{
+		Token:  c.Args.Token,
+		Ticket: c.Args.Ticket,
+	}
+	vr, err := pc.TicketVoteVote(v)
+	if err != nil {
+		return err
+	}
+
+	// Verify the vote signature and server receipt
+	version, err := client.Version()
+	if err != nil {
+		return fmt.Errorf("Version: %v", err)
+	}
+	err = pclient.CastVoteDetailsVerify(vr.Vote, version.PubKey)
+	if err != nil {
+		return fmt.Errorf("could not verify cast vote: %v", err)
+	}
+
+	// Print the cast vote
+	printf("Ticket   : %v\n", vr.Vote.Ticket)
+	printf("Vote bit : %v\n", vr.Vote.VoteBit)
+	printf("Address  : %v\n", vr.Vote.Address)
+	printf("Signature: %v\n", vr.Vote.Signature)
+	printf("Receipt  : %v\n", vr.Vote.Receipt)
+	printf("Receipt verified\n")
+
+	return nil
+}
+
+// voteReceiptHelpMsg is printed to stdout by the help command.
+const voteReceiptHelpMsg = `votereceipt "token" "ticket"
+
+Get the cast vote of a single ticket, including the server receipt, and verify
+the vote signature and receipt. This proves that the vote was recorded by the
+server without downloading the full vote results.
+
+Use the votereceipts command to save a bundle of the receipts and timestamps
+of all the tickets in your wallet.
+
+Arguments:
+1. token   (string, required) Record token.
+2. ticket  (string, required) Ticket hash.
+`
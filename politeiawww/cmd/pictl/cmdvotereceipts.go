@@ -0,0 +1,188 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"decred.org/dcrwallet/rpc/walletrpc"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteReceipts retrieves the cast vote receipts of the user's tickets and
+// saves them to a vote receipts bundle that can be verified offline using
+// politeiaverify.
+type cmdVoteReceipts struct {
+	Args struct {
+		Token string `positional-arg-name:"token" required:"true"`
+	} `positional-args:"true"`
+}
+
+// voteReceiptsBundle represents a bundle of the cast vote receipts of a
+// single voter. This is the bundle format that politeiaverify accepts.
+type voteReceiptsBundle struct {
+	Receipts        []tkv1.VoteReceipt `json:"receipts"`
+	ServerPublicKey string             `json:"serverpublickey"`
+}
+
+// Execute executes the cmdVoteReceipts command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteReceipts) Execute(args []string) error {
+	token := c.Args.Token
+
+	// Setup politeiawww client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup dcrwallet client
+	ctx := context.Background()
+	wc, err := newDcrwalletClient(cfg.WalletHost, cfg.WalletCert,
+		cfg.ClientCert, cfg.ClientKey)
+	if err != nil {
+		return err
+	}
+	defer wc.conn.Close()
+
+	// Get vote details
+	d := tkv1.Details{
+		Token: token,
+	}
+	dr, err := pc.TicketVoteDetails(d)
+	if err != nil {
+		return err
+	}
+	if dr.Vote == nil {
+		return fmt.Errorf("vote not started")
+	}
+
+	// Get the user's tickets that are eligible to vote
+	ticketPool := make([][]byte, 0, len(dr.Vote.EligibleTickets))
+	for _, v := range dr.Vote.EligibleTickets {
+		h, err := chainhash.NewHashFromStr(v)
+		if err != nil {
+			return err
+		}
+		ticketPool = append(ticketPool, h[:])
+	}
+	ct := walletrpc.CommittedTicketsRequest{
+		Tickets: ticketPool,
+	}
+	ctr, err := wc.wallet.CommittedTickets(ctx, &ct)
+	if err != nil {
+		return fmt.Errorf("CommittedTickets: %v", err)
+	}
+	if len(ctr.TicketAddresses) == 0 {
+		return fmt.Errorf("user has no eligible tickets")
+	}
+	tickets := make([]string, 0, len(ctr.TicketAddresses))
+	for _, v := range ctr.TicketAddresses {
+		h, err := chainhash.NewHash(v.Ticket)
+		if err != nil {
+			return fmt.Errorf("NewHash %x: %v", v.Ticket, err)
+		}
+		tickets = append(tickets, h.String())
+	}
+
+	// Get the vote receipts. The number of tickets that can be
+	// requested at once is limited by the timestamps page size.
+	pr, err := pc.TicketVotePolicy()
+	if err != nil {
+		return err
+	}
+	pageSize := int(pr.TimestampsPageSize)
+	receipts := make([]tkv1.VoteReceipt, 0, len(tickets))
+	for len(tickets) > 0 {
+		page := tickets
+		if len(page) > pageSize {
+			page = tickets[:pageSize]
+		}
+		tickets = tickets[len(page):]
+
+		r := tkv1.Receipts{
+			Token:   token,
+			Tickets: page,
+		}
+		rr, err := pc.TicketVoteReceipts(r)
+		if err != nil {
+			return err
+		}
+		receipts = append(receipts, rr.Receipts...)
+	}
+	if len(receipts) == 0 {
+		return fmt.Errorf("user has not cast any votes")
+	}
+
+	// Get the server pubkey so that we can verify the receipts
+	version, err := client.Version()
+	if err != nil {
+		return fmt.Errorf("Version: %v", err)
+	}
+
+	// Verify the receipts
+	var notTimestamped int
+	for _, v := range receipts {
+		err := pclient.VoteReceiptVerify(v, version.PubKey)
+		switch err {
+		case nil:
+			// Receipt verified
+		case backend.ErrNotTimestamped:
+			notTimestamped++
+		default:
+			return fmt.Errorf("could not verify vote receipt %v: %v",
+				v.Vote.Ticket, err)
+		}
+	}
+
+	// Save the vote receipts bundle
+	b, err := json.Marshal(voteReceiptsBundle{
+		Receipts:        receipts,
+		ServerPublicKey: version.PubKey,
+	})
+	if err != nil {
+		return err
+	}
+	fp := fmt.Sprintf("%v-vote-receipts.json", token)
+	err = os.WriteFile(fp, b, 0644)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Vote receipts      : %v\n", len(receipts))
+	fmt.Printf("Not timestamped yet: %v\n", notTimestamped)
+	fmt.Printf("Bundle saved to %v\n", fp)
+
+	return nil
+}
+
+// voteReceiptsHelpMsg is printed to stdout by the help command.
+const voteReceiptsHelpMsg = `votereceipts "token"
+
+Retrieve the cast vote receipts of the tickets in your wallet and save them
+to a vote receipts bundle. The bundle contains the cast votes and their
+timestamps and can be verified offline using politeiaverify.
+
+The bundle is saved to the current directory as [token]-vote-receipts.json.
+Votes that have not been timestamped yet can be fetched again later to get
+their full inclusion proofs.
+
+Requires a running dcrwallet instance.
+
+Arguments:
+1. token  (string, required) Record token.
+`
@@ -0,0 +1,65 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteSetDuration sets the min and max vote duration.
+type cmdVoteSetDuration struct {
+	Args struct {
+		Min uint32 `positional-arg-name:"min" required:"true"`
+		Max uint32 `positional-arg-name:"max" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteSetDuration command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteSetDuration) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	svdr, err := pc.TicketVoteSetVoteDuration(tkv1.SetVoteDuration{
+		Min: c.Args.Min,
+		Max: c.Args.Max,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Print results
+	printf("Vote duration min: %v blocks\n", svdr.Min)
+	printf("Vote duration max: %v blocks\n", svdr.Max)
+
+	return nil
+}
+
+// voteSetDurationHelpMsg is printed to stdout by the help command.
+const voteSetDurationHelpMsg = `votesetduration "min" "max"
+
+Set the min and max vote duration that are allowed for new votes. Requires
+admin privileges.
+
+The bounds override the votedurationmin and votedurationmax politeiad plugin
+settings and are persisted by politeiad so that they remain in effect after a
+restart. Votes that have already been started are not affected.
+
+Arguments:
+1. min (uint32, required) Min vote duration in blocks.
+2. max (uint32, required) Max vote duration in blocks.`
@@ -0,0 +1,74 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteSnapshotVerify re-verifies the eligible ticket snapshot of a
+// proposal vote against the chain.
+type cmdVoteSnapshotVerify struct {
+	Args struct {
+		Token string `positional-arg-name:"token" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteSnapshotVerify command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteSnapshotVerify) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	sv := tkv1.SnapshotVerify{
+		Token: c.Args.Token,
+	}
+	svr, err := pc.TicketVoteSnapshotVerify(sv)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	err = pclient.SnapshotVerificationVerify(svr.Verification, vr.PubKey)
+	if err != nil {
+		return err
+	}
+
+	// Print the verification report
+	printJSON(svr.Verification)
+
+	return nil
+}
+
+// voteSnapshotVerifyHelpMsg is printed to stdout by the help command.
+const voteSnapshotVerifyHelpMsg = `votesnapshotverify "token"
+
+Re-verify the eligible ticket snapshot of a proposal vote. Requires admin
+privileges.
+
+The server re-fetches the ticket pool at the snapshot block of the vote and
+compares it against the stored eligible tickets. The verification report is
+signed by the server and can be provided to auditors. The server signature is
+verified before the report is printed.
+
+Arguments:
+1. token  (string, required)  Proposal censorship token`
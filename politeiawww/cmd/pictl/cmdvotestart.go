@@ -38,6 +38,22 @@ type cmdVoteStart struct {
 	// Runoff is used to indicate the vote is a runoff vote and the
 	// provided token is the parent token of the runoff vote.
 	Runoff bool `long:"runoff"`
+
+	// Winners is the maximum number of records that can be approved in a
+	// runoff vote. AllWinners allows all records that meet the quorum and
+	// pass requirements to be approved.
+	Winners    uint32 `long:"winners"`
+	AllWinners bool   `long:"allwinners"`
+
+	// Stake is used to indicate that the quorum and pass requirements
+	// of the vote are calculated using the staked DCR amounts of the
+	// tickets instead of the number of tickets.
+	Stake bool `long:"stake"`
+
+	// SnapshotHeight overrides the block height of the eligible ticket
+	// snapshot. This is only allowed on test networks that have the
+	// snapshot height override enabled.
+	SnapshotHeight uint32 `long:"snapshotheight"`
 }
 
 // Execute executes the cmdVoteStart command.
@@ -68,6 +84,17 @@ func (c *cmdVoteStart) Execute(args []string) error {
 	if c.Passing != 0 {
 		passing = c.Passing
 	}
+	winners := c.Winners
+	if c.AllWinners {
+		winners = tkv1.RunoffWinnersAll
+	}
+	if winners != 0 && !c.Runoff {
+		return fmt.Errorf("winners can only be used for runoff votes")
+	}
+	weight := tkv1.VoteWeightTicket
+	if c.Stake {
+		weight = tkv1.VoteWeightStake
+	}
 
 	// Setup client
 	opts := pclient.Opts{
@@ -85,12 +112,14 @@ func (c *cmdVoteStart) Execute(args []string) error {
 	// Start the voting period
 	var sr *tkv1.StartReply
 	if c.Runoff {
-		sr, err = voteStartRunoff(token, duration, quorum, passing, pc)
+		sr, err = voteStartRunoff(token, duration, quorum, passing,
+			winners, weight, c.SnapshotHeight, pc)
 		if err != nil {
 			return err
 		}
 	} else {
-		sr, err = voteStartStandard(token, duration, quorum, passing, pc)
+		sr, err = voteStartStandard(token, duration, quorum, passing,
+			weight, c.SnapshotHeight, pc)
 		if err != nil {
 			return err
 		}
@@ -101,11 +130,18 @@ func (c *cmdVoteStart) Execute(args []string) error {
 	printf("StartBlockHash  : %v\n", sr.StartBlockHash)
 	printf("StartBlockHeight: %v\n", sr.StartBlockHeight)
 	printf("EndBlockHeight  : %v\n", sr.EndBlockHeight)
+	if sr.ConcurrentVotes > 0 {
+		printf("ConcurrentVotes : %v\n", sr.ConcurrentVotes)
+	}
+	if sr.ConcurrentVotesExceeded {
+		printf("Warning: the number of concurrent votes exceeds the " +
+			"concurrent votes max\n")
+	}
 
 	return nil
 }
 
-func voteStartStandard(token string, duration, quorum, pass uint32, pc *pclient.Client) (*tkv1.StartReply, error) {
+func voteStartStandard(token string, duration, quorum, pass uint32, weight tkv1.VoteWeightT, snapshotHeight uint32, pc *pclient.Client) (*tkv1.StartReply, error) {
 	// Get record version
 	d := rcv1.Details{
 		Token: token,
@@ -136,6 +172,7 @@ func voteStartStandard(token string, duration, quorum, pass uint32, pc *pclient.
 				Bit:         0x02,
 			},
 		},
+		Weight: weight,
 	}
 	vpb, err := json.Marshal(vp)
 	if err != nil {
@@ -147,9 +184,10 @@ func voteStartStandard(token string, duration, quorum, pass uint32, pc *pclient.
 	s := tkv1.Start{
 		Starts: []tkv1.StartDetails{
 			{
-				Params:    vp,
-				PublicKey: cfg.Identity.Public.String(),
-				Signature: signature,
+				Params:         vp,
+				PublicKey:      cfg.Identity.Public.String(),
+				Signature:      signature,
+				SnapshotHeight: snapshotHeight,
 			},
 		},
 	}
@@ -158,7 +196,7 @@ func voteStartStandard(token string, duration, quorum, pass uint32, pc *pclient.
 	return pc.TicketVoteStart(s)
 }
 
-func voteStartRunoff(parentToken string, duration, quorum, pass uint32, pc *pclient.Client) (*tkv1.StartReply, error) {
+func voteStartRunoff(parentToken string, duration, quorum, pass, winners uint32, weight tkv1.VoteWeightT, snapshotHeight uint32, pc *pclient.Client) (*tkv1.StartReply, error) {
 	// Get runoff vote submissions
 	s := tkv1.Submissions{
 		Token: parentToken,
@@ -206,7 +244,9 @@ func voteStartRunoff(parentToken string, duration, quorum, pass uint32, pc *pcli
 					Bit:         0x02,
 				},
 			},
-			Parent: parentToken,
+			Parent:  parentToken,
+			Winners: winners,
+			Weight:  weight,
 		}
 		vpb, err := json.Marshal(vp)
 		if err != nil {
@@ -215,9 +255,10 @@ func voteStartRunoff(parentToken string, duration, quorum, pass uint32, pc *pcli
 		msg := hex.EncodeToString(util.Digest(vpb))
 		sig := cfg.Identity.SignMessage([]byte(msg))
 		starts = append(starts, tkv1.StartDetails{
-			Params:    vp,
-			PublicKey: cfg.Identity.Public.String(),
-			Signature: hex.EncodeToString(sig[:]),
+			Params:         vp,
+			PublicKey:      cfg.Identity.Public.String(),
+			Signature:      hex.EncodeToString(sig[:]),
+			SnapshotHeight: snapshotHeight,
 		})
 	}
 
@@ -251,4 +292,17 @@ Flags:
                      (default: 60)
  --runoff  (bool)    The vote being started is a runoff vote.
                      (default: false)
+ --winners (uint32)  Maximum number of records that can be approved in a
+                     runoff vote.
+                     (default: 1)
+ --allwinners (bool) Approve all runoff vote records that meet the quorum
+                     and pass requirements.
+                     (default: false)
+ --stake   (bool)    Weight the votes by the staked DCR amount of the tickets
+                     instead of by the number of tickets.
+                     (default: false)
+ --snapshotheight (uint32) Block height of the eligible ticket snapshot.
+                     Only allowed on test networks that have the snapshot
+                     height override enabled.
+                     (default: best block minus the ticket maturity)
 `
@@ -0,0 +1,71 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"time"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdVoteStats retrieves the turnout and participation statistics of a
+// finished proposal vote.
+type cmdVoteStats struct {
+	Args struct {
+		Token string `positional-arg-name:"token" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteStats command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteStats) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert: cfg.HTTPSCert,
+		Verbose:   cfg.Verbose,
+		RawJSON:   cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get vote stats
+	s := tkv1.Stats{
+		Token: c.Args.Token,
+	}
+	sr, err := pc.TicketVoteStats(s)
+	if err != nil {
+		return err
+	}
+
+	// Print stats
+	printf("Status          : %v\n", tkv1.VoteStatuses[sr.Status])
+	printf("Eligible tickets: %v\n", sr.EligibleTickets)
+	printf("Unique tickets  : %v\n", sr.UniqueTickets)
+	printf("Turnout         : %.2f%%\n", sr.Turnout)
+	printf("Approval        : %.2f%%\n", sr.Approval)
+	printf("Votes per day\n")
+	for _, v := range sr.Days {
+		day := time.Unix(v.Day, 0).UTC().Format("2006-01-02")
+		printf("  %v  %6v votes  %6.2f%% approval\n",
+			day, v.Votes, v.Approval)
+	}
+
+	return nil
+}
+
+// voteStatsHelpMsg is printed to stdout by the help command.
+const voteStatsHelpMsg = `votestats "token"
+
+Get the turnout and participation statistics of a finished proposal vote. This
+includes the turnout percentage, the number of tickets that voted, the number
+of votes cast per day, and the cumulative approval percentage at the end of
+each day.
+
+Arguments:
+1. token  (string, required)  Proposal censorship token`
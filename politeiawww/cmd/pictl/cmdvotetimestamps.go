@@ -15,6 +15,12 @@ type cmdVoteTimestamps struct {
 		Token     string `positional-arg-name:"token" required:"true"`
 		VotesPage uint32 `positional-arg-name:"votespage" optional:"true"`
 	} `positional-args:"true"`
+
+	// PageSize is the number of cast vote timestamps to request.
+	PageSize uint32 `long:"pagesize" optional:"true"`
+
+	// DigestsOnly omits the data payloads from the timestamps.
+	DigestsOnly bool `long:"digestsonly" optional:"true"`
 }
 
 // Execute executes the cmdVoteTimestamps command.
@@ -36,8 +42,10 @@ func (c *cmdVoteTimestamps) Execute(args []string) error {
 
 	// Get timestamps
 	t := tkv1.Timestamps{
-		Token:     c.Args.Token,
-		VotesPage: c.Args.VotesPage,
+		Token:         c.Args.Token,
+		VotesPage:     c.Args.VotesPage,
+		VotesPageSize: c.PageSize,
+		DigestsOnly:   c.DigestsOnly,
 	}
 	tr, err := pc.TicketVoteTimestamps(t)
 	if err != nil {
@@ -45,7 +53,16 @@ func (c *cmdVoteTimestamps) Execute(args []string) error {
 	}
 
 	// Verify timestamps
-	return pclient.TicketVoteTimestampsVerify(*tr)
+	err = pclient.TicketVoteTimestampsVerify(*tr)
+	if err != nil {
+		return err
+	}
+	if tr.VotesCount > 0 {
+		printf("Verified %v of %v vote timestamps\n", len(tr.Votes),
+			tr.VotesCount)
+	}
+
+	return nil
 }
 
 // voteTimestampsHelpMsg is printed to stdout by the help command.
@@ -60,4 +77,10 @@ the specified page of votes will be returned.
 Arguments:
 1. token     (string, required) Record token.
 2. votepage  (uint32, optional) Page number for cast vote timestamps. 
+
+Flags:
+ --pagesize    (uint32, optional) Number of cast vote timestamps per page. The
+                                  page size is capped at the server policy.
+ --digestsonly (bool, optional)   Omit the data payloads from the timestamps.
+                                  The digests and proofs are still verified.
 `
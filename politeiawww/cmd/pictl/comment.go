@@ -5,9 +5,17 @@
 package main
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/decred/politeia/politeiad/api/v1/mime"
 	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	"github.com/decred/politeia/util"
 )
 
 func printComment(c cmv1.Comment) {
@@ -15,10 +23,21 @@ func printComment(c cmv1.Comment) {
 
 	printf("Comment %v\n", c.CommentID)
 	printf("  Score        : %v %v\n", downvotes, c.Upvotes)
+	if c.StakeDownvotes != 0 || c.StakeUpvotes != 0 {
+		printf("  Stake score  : %v %v\n",
+			int64(c.StakeDownvotes)*-1, c.StakeUpvotes)
+	}
+	if len(c.Reactions) > 0 {
+		printf("  Reactions    : %v\n", reactionsString(c.Reactions))
+	}
 	printf("  Username     : %v\n", c.Username)
 	printf("  Parent ID    : %v\n", c.ParentID)
 	printf("  Timestamp    : %v\n", dateAndTimeFromUnix(c.Timestamp))
 
+	for _, v := range c.Attachments {
+		printf("  Attachment   : %v (%v)\n", v.Name, v.MIME)
+	}
+
 	// If the comment is an author update print extra data info
 	if c.ExtraDataHint != "" {
 		printf("  ExtraDataHint: %v\n", c.ExtraDataHint)
@@ -29,6 +48,9 @@ func printComment(c cmv1.Comment) {
 	// present. Print the reason for deletion instead and exit.
 	if c.Deleted {
 		printf("  Deleted      : %v\n", c.Deleted)
+		if c.DeletedByAuthor {
+			printf("  Deleted by   : author\n")
+		}
 		printf("  Reason       : %v\n", c.Reason)
 		return
 	}
@@ -67,3 +89,41 @@ func printCommentVotes(votes []cmv1.CommentVote) {
 			v.Vote)
 	}
 }
+
+// reactionsString returns the provided reaction tallies as a human readable
+// string. The reactions are sorted by reaction ID.
+func reactionsString(reactions map[string]uint64) string {
+	ids := make([]string, 0, len(reactions))
+	for id := range reactions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	s := make([]string, 0, len(ids))
+	for _, id := range ids {
+		s = append(s, fmt.Sprintf("%v %v", id, reactions[id]))
+	}
+	return strings.Join(s, ", ")
+}
+
+// commentAttachmentsFromDisk loads the provided image files from disk and
+// returns them as comment attachments.
+func commentAttachmentsFromDisk(paths []string) ([]cmv1.Attachment, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	as := make([]cmv1.Attachment, 0, len(paths))
+	for _, fn := range paths {
+		fp := util.CleanAndExpandPath(fn)
+		payload, err := os.ReadFile(fp)
+		if err != nil {
+			return nil, fmt.Errorf("ReadFile %v: %v", fp, err)
+		}
+		as = append(as, cmv1.Attachment{
+			Name:    filepath.Base(fn),
+			MIME:    mime.DetectMimeType(payload),
+			Digest:  hex.EncodeToString(util.Digest(payload)),
+			Payload: base64.StdEncoding.EncodeToString(payload),
+		})
+	}
+	return as, nil
+}
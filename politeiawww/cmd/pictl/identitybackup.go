@@ -0,0 +1,177 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"decred.org/dcrwallet/walletseed"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/util"
+	"github.com/marcopeereboom/sbox"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// identityBackupVersion is the version of the identity backup file
+	// format.
+	identityBackupVersion = 1
+)
+
+// identityBackup is the portable identity backup file format. The identity
+// seed is encrypted using a key that is derived from the user's passphrase
+// using argon2id. The public key is included so that the identity can be
+// identified without decrypting it.
+type identityBackup struct {
+	Version   uint32            `json:"version"`
+	PublicKey string            `json:"publickey"`
+	Params    util.Argon2Params `json:"params"`
+	Encrypted []byte            `json:"encrypted"` // sbox encrypted seed
+}
+
+// identitySeed returns the ed25519 seed of the provided identity. The seed is
+// all that is needed to recreate the identity.
+func identitySeed(id *identity.FullIdentity) []byte {
+	return ed25519.PrivateKey(id.PrivateKey[:]).Seed()
+}
+
+// identityFromSeed recreates an identity from its ed25519 seed.
+func identityFromSeed(seed []byte) (*identity.FullIdentity, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid seed length: got %v, want %v",
+			len(seed), ed25519.SeedSize)
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	var id identity.FullIdentity
+	copy(id.PrivateKey[:], priv)
+	copy(id.Public.Key[:], priv.Public().(ed25519.PublicKey))
+	util.Zero(priv)
+	return &id, nil
+}
+
+// identityMnemonic returns the PGP word list mnemonic of the identity seed.
+// The mnemonic is the same encoding that is used for dcrwallet seeds.
+func identityMnemonic(id *identity.FullIdentity) string {
+	return walletseed.EncodeMnemonic(identitySeed(id))
+}
+
+// identityFromMnemonic recreates an identity from the mnemonic of its seed.
+func identityFromMnemonic(mnemonic string) (*identity.FullIdentity, error) {
+	seed, err := walletseed.DecodeUserInput(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	return identityFromSeed(seed)
+}
+
+// identityBackupKey derives the encryption key of an identity backup from the
+// passphrase.
+func identityBackupKey(passphrase []byte, ap util.Argon2Params) *[32]byte {
+	k := argon2.IDKey(passphrase, ap.Salt, ap.Time, ap.Memory,
+		ap.Threads, ap.KeyLen)
+	var key [32]byte
+	copy(key[:], k)
+	util.Zero(k)
+	return &key
+}
+
+// newIdentityBackup returns the identity backup of the provided identity,
+// encrypted using the passphrase.
+func newIdentityBackup(id *identity.FullIdentity, passphrase []byte) (*identityBackup, error) {
+	ap := util.NewArgon2Params()
+	key := identityBackupKey(passphrase, ap)
+	defer util.Zero(key[:])
+
+	seed := identitySeed(id)
+	defer util.Zero(seed)
+	encrypted, err := sbox.Encrypt(identityBackupVersion, key, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identityBackup{
+		Version:   identityBackupVersion,
+		PublicKey: id.Public.String(),
+		Params:    ap,
+		Encrypted: encrypted,
+	}, nil
+}
+
+// decrypt decrypts the identity backup using the passphrase and returns the
+// identity.
+func (b *identityBackup) decrypt(passphrase []byte) (*identity.FullIdentity, error) {
+	if b.Version != identityBackupVersion {
+		return nil, fmt.Errorf("unsupported identity backup version %v",
+			b.Version)
+	}
+	key := identityBackupKey(passphrase, b.Params)
+	defer util.Zero(key[:])
+
+	seed, _, err := sbox.Decrypt(key, b.Encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("invalid passphrase")
+	}
+	defer util.Zero(seed)
+	id, err := identityFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sanity check
+	if id.Public.String() != b.PublicKey {
+		return nil, fmt.Errorf("decrypted identity does not match "+
+			"public key %v", b.PublicKey)
+	}
+
+	return id, nil
+}
+
+// saveIdentityBackup writes the identity backup to the provided file path.
+func saveIdentityBackup(fp string, b *identityBackup) error {
+	j, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fp, j, 0600)
+}
+
+// loadIdentityBackup reads the identity backup at the provided file path.
+func loadIdentityBackup(fp string) (*identityBackup, error) {
+	j, err := os.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	var b identityBackup
+	err = json.Unmarshal(j, &b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity backup: %v", err)
+	}
+	if _, err := hex.DecodeString(b.PublicKey); err != nil {
+		return nil, fmt.Errorf("invalid identity backup public key")
+	}
+	return &b, nil
+}
+
+// promptPassphrase prints the message to stdout prompting the user for a
+// passphrase then reads in the passphrase from stdin.
+func promptPassphrase(msg string) ([]byte, error) {
+	printf("%v: ", msg)
+	pass, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	printf("\n")
+	pass = bytes.TrimSpace(pass)
+	if len(pass) == 0 {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+	return pass, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+)
+
+func TestIdentityBackup(t *testing.T) {
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pass := []byte("passphrase")
+
+	// Save and load the backup
+	b, err := newIdentityBackup(id, pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := filepath.Join(t.TempDir(), "identity.json")
+	err = saveIdentityBackup(fp, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = loadIdentityBackup(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decrypt the backup
+	_, err = b.decrypt([]byte("wrong passphrase"))
+	if err == nil {
+		t.Fatalf("decrypt: got nil error for wrong passphrase")
+	}
+	restored, err := b.decrypt(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *restored != *id {
+		t.Fatalf("decrypted identity does not match")
+	}
+
+	// Restore the identity from its mnemonic
+	restored, err = identityFromMnemonic(identityMnemonic(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *restored != *id {
+		t.Fatalf("mnemonic identity does not match")
+	}
+}
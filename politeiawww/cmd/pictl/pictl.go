@@ -45,24 +45,40 @@ type pictl struct {
 	Login   shared.LoginCmd   `command:"login"`
 	Logout  shared.LogoutCmd  `command:"logout"`
 	Me      shared.MeCmd      `command:"me"`
+	Changes changesCmd        `command:"changes"`
+	Status  statusCmd         `command:"status"`
 
 	// User commands
-	UserNew                 userNewCmd                   `command:"usernew"`
-	UserEdit                userEditCmd                  `command:"useredit"`
-	UserManage              shared.UserManageCmd         `command:"usermanage"`
-	UserEmailVerify         userEmailVerifyCmd           `command:"useremailverify"`
-	UserVerificationResend  userVerificationResendCmd    `command:"userverificationresend"`
-	UserPasswordReset       shared.UserPasswordResetCmd  `command:"userpasswordreset"`
-	UserPasswordChange      shared.UserPasswordChangeCmd `command:"userpasswordchange"`
-	UserUsernameChange      shared.UserUsernameChangeCmd `command:"userusernamechange"`
-	UserKeyUpdate           shared.UserKeyUpdateCmd      `command:"userkeyupdate"`
-	UserRegistrationPayment userRegistrationPaymentCmd   `command:"userregistrationpayment"`
-	UserPaymentsRescan      userPaymentsRescanCmd        `command:"userpaymentsrescan"`
-	UserProposalPaywall     userProposalPaywallCmd       `command:"userproposalpaywall"`
-	UserProposalPaywallTx   userProposalPaywallTxCmd     `command:"userproposalpaywalltx"`
-	UserProposalCredits     userProposalCreditsCmd       `command:"userproposalcredits"`
-	UserDetails             userDetailsCmd               `command:"userdetails"`
-	Users                   shared.UsersCmd              `command:"users"`
+	UserNew                     userNewCmd                      `command:"usernew"`
+	UserEdit                    userEditCmd                     `command:"useredit"`
+	UserManage                  shared.UserManageCmd            `command:"usermanage"`
+	UserEmailVerify             userEmailVerifyCmd              `command:"useremailverify"`
+	UserVerificationResend      userVerificationResendCmd       `command:"userverificationresend"`
+	UserPasswordReset           shared.UserPasswordResetCmd     `command:"userpasswordreset"`
+	UserPasswordChange          shared.UserPasswordChangeCmd    `command:"userpasswordchange"`
+	UserUsernameChange          shared.UserUsernameChangeCmd    `command:"userusernamechange"`
+	UserEmailChange             shared.UserEmailChangeCmd       `command:"useremailchange"`
+	UserEmailChangeVerify       shared.UserEmailChangeVerifyCmd `command:"useremailchangeverify"`
+	UserKeyUpdate               shared.UserKeyUpdateCmd         `command:"userkeyupdate"`
+	UserKeyRevoke               shared.UserKeyRevokeCmd         `command:"userkeyrevoke"`
+	IdentityExport              cmdIdentityExport               `command:"identityexport"`
+	IdentityImport              cmdIdentityImport               `command:"identityimport"`
+	UserRegistrationPayment     userRegistrationPaymentCmd      `command:"userregistrationpayment"`
+	UserPaymentsRescan          userPaymentsRescanCmd           `command:"userpaymentsrescan"`
+	UserProposalPaywall         userProposalPaywallCmd          `command:"userproposalpaywall"`
+	UserProposalPaywallTx       userProposalPaywallTxCmd        `command:"userproposalpaywalltx"`
+	UserProposalCredits         userProposalCreditsCmd          `command:"userproposalcredits"`
+	UserProposalCreditsTransfer userProposalCreditsTransferCmd  `command:"userproposalcreditstransfer"`
+	UserPushSubscriptions       userPushSubscriptionsCmd        `command:"userpushsubscriptions"`
+	UserPushSubscribe           userPushSubscribeCmd            `command:"userpushsubscribe"`
+	UserPushUnsubscribe         userPushUnsubscribeCmd          `command:"userpushunsubscribe"`
+	UserUsage                   userUsageCmd                    `command:"userusage"`
+	UserDetails                 userDetailsCmd                  `command:"userdetails"`
+	Users                       shared.UsersCmd                 `command:"users"`
+	UsersUsage                  usersUsageCmd                   `command:"usersusage"`
+	Banners                     bannersCmd                      `command:"banners"`
+	BannerSet                   bannerSetCmd                    `command:"bannerset"`
+	BannerDel                   bannerDelCmd                    `command:"bannerdel"`
 
 	// Proposal commands
 	ProposalPolicy               cmdProposalPolicy               `command:"proposalpolicy"`
@@ -71,10 +87,13 @@ type pictl struct {
 	ProposalSetStatus            cmdProposalSetStatus            `command:"proposalsetstatus"`
 	ProposalSetBillingStatus     cmdProposalSetBillingStatus     `command:"proposalsetbillingstatus"`
 	ProposalBillingStatusChanges cmdProposalBillingStatusChanges `command:"proposalbillingstatuschanges"`
+	BillingVerify                cmdBillingVerify                `command:"billingverify"`
+	ProposalWithdraw             cmdProposalWithdraw             `command:"proposalwithdraw"`
 	ProposalDetails              cmdProposalDetails              `command:"proposaldetails"`
 	ProposalTimestamps           cmdProposalTimestamps           `command:"proposaltimestamps"`
 	Proposals                    cmdProposals                    `command:"proposals"`
 	ProposalSummaries            cmdProposalSummaries            `command:"proposalsummaries"`
+	ProposalList                 cmdProposalList                 `command:"proposallist"`
 	ProposalInv                  cmdProposalInv                  `command:"proposalinv"`
 	ProposalInvOrdered           cmdProposalInvOrdered           `command:"proposalinvordered"`
 	UserProposals                cmdUserProposals                `command:"userproposals"`
@@ -83,27 +102,49 @@ type pictl struct {
 	RecordPolicy cmdRecordPolicy `command:"recordpolicy"`
 
 	// Comments commands
-	CommentsPolicy    cmdCommentPolicy     `command:"commentpolicy"`
-	CommentNew        cmdCommentNew        `command:"commentnew"`
-	CommentEdit       cmdCommentEdit       `command:"commentedit"`
-	CommentVote       cmdCommentVote       `command:"commentvote"`
-	CommentCensor     cmdCommentCensor     `command:"commentcensor"`
-	CommentCount      cmdCommentCount      `command:"commentcount"`
-	Comments          cmdComments          `command:"comments"`
-	CommentVotes      cmdCommentVotes      `command:"commentvotes"`
-	CommentTimestamps cmdCommentTimestamps `command:"commenttimestamps"`
+	CommentsPolicy     cmdCommentPolicy      `command:"commentpolicy"`
+	CommentNew         cmdCommentNew         `command:"commentnew"`
+	CommentEdit        cmdCommentEdit        `command:"commentedit"`
+	CommentVote        cmdCommentVote        `command:"commentvote"`
+	CommentSubscribe   cmdCommentSubscribe   `command:"commentsubscribe"`
+	CommentPin         cmdCommentPin         `command:"commentpin"`
+	CommentCensor      cmdCommentCensor      `command:"commentcensor"`
+	CommentCensorUser  cmdCommentCensorUser  `command:"commentcensoruser"`
+	CommentDel         cmdCommentDel         `command:"commentdel"`
+	CommentCount       cmdCommentCount       `command:"commentcount"`
+	Comments           cmdComments           `command:"comments"`
+	CommentTree        cmdCommentTree        `command:"commenttree"`
+	CommentAttachments cmdCommentAttachments `command:"commentattachments"`
+	CommentSearch      cmdCommentSearch      `command:"commentsearch"`
+	CommentVotes       cmdCommentVotes       `command:"commentvotes"`
+	CommentTimestamps  cmdCommentTimestamps  `command:"commenttimestamps"`
+	CommentStatus      cmdCommentStatus      `command:"commentstatus"`
+	CommentStakeProof  cmdCommentStakeProof  `command:"commentstakeproof"`
+	UserComments       cmdUserComments       `command:"usercomments"`
+	CommentHistory     cmdCommentHistory     `command:"commenthistory"`
 
 	// Vote commands
-	VotePolicy      cmdVotePolicy      `command:"votepolicy"`
-	VoteAuthorize   cmdVoteAuthorize   `command:"voteauthorize"`
-	VoteStart       cmdVoteStart       `command:"votestart"`
-	CastBallot      cmdCastBallot      `command:"castballot"`
-	VoteDetails     cmdVoteDetails     `command:"votedetails"`
-	VoteResults     cmdVoteResults     `command:"voteresults"`
-	VoteSummaries   cmdVoteSummaries   `command:"votesummaries"`
-	VoteSubmissions cmdVoteSubmissions `command:"votesubmissions"`
-	VoteInv         cmdVoteInv         `command:"voteinv"`
-	VoteTimestamps  cmdVoteTimestamps  `command:"votetimestamps"`
+	VotePolicy         cmdVotePolicy         `command:"votepolicy"`
+	VoteAuthorize      cmdVoteAuthorize      `command:"voteauthorize"`
+	VoteStart          cmdVoteStart          `command:"votestart"`
+	VoteExtend         cmdVoteExtend         `command:"voteextend"`
+	VoteCancel         cmdVoteCancel         `command:"votecancel"`
+	VoteRebuild        cmdVoteRebuild        `command:"voterebuild"`
+	VoteSnapshotVerify cmdVoteSnapshotVerify `command:"votesnapshotverify"`
+	VoteSetDuration    cmdVoteSetDuration    `command:"votesetduration"`
+	VoteParamsVerify   cmdVoteParamsVerify   `command:"voteparamsverify"`
+	CastBallot         cmdCastBallot         `command:"castballot"`
+	CastBallotStatus   cmdCastBallotStatus   `command:"castballotstatus"`
+	VoteDetails        cmdVoteDetails        `command:"votedetails"`
+	VoteResults        cmdVoteResults        `command:"voteresults"`
+	VoteSummaries      cmdVoteSummaries      `command:"votesummaries"`
+	VoteSubmissions    cmdVoteSubmissions    `command:"votesubmissions"`
+	VoteInv            cmdVoteInv            `command:"voteinv"`
+	VoteTimestamps     cmdVoteTimestamps     `command:"votetimestamps"`
+	VoteStats          cmdVoteStats          `command:"votestats"`
+	VoteExport         cmdVoteExport         `command:"voteexport"`
+	VoteAuditBundle    cmdVoteAuditBundle    `command:"voteauditbundle"`
+	VoteReceipts       cmdVoteReceipts       `command:"votereceipts"`
 
 	// Dev commands
 	SendFaucetTx  cmdSendFaucetTx  `command:"sendfaucettx"`
@@ -140,6 +181,8 @@ Basic commands
   login                        (public) Login to politeiawww
   logout                       (user)   Logout from politeiawww
   me                           (user)   Get details of the logged in user
+  changes                      (public) Get changes to the public inventory
+  status                       (public) Get server status and active banners
 
 User commands
   usernew                      (public) Create a new user
@@ -150,14 +193,28 @@ User commands
   userpasswordreset            (public) Reset password 
   userpasswordchange           (user)   Change password
   userusernamechange           (user)   Change username
+  useremailchange              (user)   Change email address
+  useremailchangeverify        (user)   Verify email address change
   userkeyupdate                (user)   Update user key (i.e. identity)
+  userkeyrevoke                (user)   Revoke one of the user's keys
+  identityexport               (user)   Export an encrypted identity backup
+  identityimport               (user)   Restore an identity from a backup
   userregistrationpayment      (user)   Verify registration payment
   userpaymentsrescan           (user)   Rescan all user payments
   userproposalpaywall          (user)   Get user paywall details
   userproposalpaywalltx        (user)   Get pending user payments
   userproposalcredits          (user)   Get user proposal credits
+  userproposalcreditstransfer  (user)   Transfer proposal credits to a user
+  userpushsubscriptions        (user)   Get web push subscriptions
+  userpushsubscribe            (user)   Add a web push subscription
+  userpushunsubscribe          (user)   Remove a web push subscription
+  userusage                    (user)   Get user API usage
   userdetails                  (public) Get user details
   users                        (public) Get users
+  usersusage                   (admin)  Get aggregate API usage of all users
+  banners                      (admin)  Get all site-wide banners
+  bannerset                    (admin)  Create or update a site-wide banner
+  bannerdel                    (admin)  Delete a site-wide banner
 
 Proposal commands
   proposalpolicy               (public) Get the pi api policy
@@ -166,10 +223,13 @@ Proposal commands
   proposalsetstatus            (admin)  Set the status of a proposal
   proposalsetbillingstatus     (admin)  Set the billing status of a proposal
   proposalbillingstatuschanges (public) Get billing status changes
+  billingverify                (public) Verify billing status changes
+  proposalwithdraw             (user)   Withdraw a proposal and refund its credit
   proposaldetails              (public) Get a full proposal record
   proposaltimestamps           (public) Get timestamps for a proposal
   proposals                    (public) Get proposals without their files
   proposalsummaries            (public) Get proposal summaries
+  proposallist                 (public) Get the proposal list page data
   proposalinv                  (public) Get inventory by proposal status
   proposalinvordered           (public) Get inventory ordered chronologically
   userproposals                (public) Get proposals submitted by a user
@@ -182,30 +242,53 @@ Comment commands
   commentnew                   (user)   Submit a new comment
   commentedit                  (user)   Edit a comment
   commentvote                  (user)   Upvote/downvote a comment
+  commentsubscribe             (user)   Subscribe to a comment thread
+  commentpin                   (user)   Pin a comment on a record
   commentcensor                (admin)  Censor a comment
+  commentcensoruser            (admin)  Censor all comments made by a user
+  commentdel                   (user)   Delete your own comment
   commentcount                 (public) Get the number of comments
   comments                     (public) Get comments
+  commenttree                  (public) Get a comment subtree
+  commentattachments           (public) Get comment attachments
+  commentsearch                (public) Search comments
   commentvotes                 (public) Get comment votes
   commenttimestamps            (public) Get comment timestamps
+  commentstatus                (public) Get the status of a queued comment
+  commentstakeproof            (user)   Submit proof of ticket holdings
+  usercomments                 (public) Get the comments made by a user
+  commenthistory               (public) Get the comment history of a user
 
 Vote commands
   votepolicy                   (public) Get the ticketvote api policy
   voteauthorize                (user)   Authorize a proposal vote
   votestart                    (admin)  Start a proposal vote
+  voteextend                   (admin)  Extend an active proposal vote
+  votecancel                   (admin)  Cancel a started proposal vote
+  voterebuild                  (admin)  Rebuild the active votes cache
+  votesnapshotverify           (admin)  Re-verify a vote's eligible tickets
+  votesetduration              (admin)  Set the vote duration bounds
+  voteparamsverify             (public) Verify vote params without starting
   castballot                   (public) Cast a ballot of votes
+  castballotstatus             (public) Get the status of a queued ballot
   votedetails                  (public) Get details for a vote
   voteresults                  (public) Get full vote results
   votesummaries                (public) Get vote summaries
   votesubmissions              (public) Get runoff vote submissions
   voteinv                      (public) Get proposal inventory by vote status
   votetimestamps               (public) Get vote timestamps
+  votestats                    (public) Get vote turnout statistics
+  voteexport                   (public) Get the governance export of all votes
+  voteauditbundle              (public) Save the audit bundle of a vote
+  votereceipts                 (public) Save a bundle of your vote receipts
+  votereceipt                  (public) Get the cast vote of a ticket
 
 Websocket commands
   subscribe                    (public) Subscribe/unsubscribe to websocket event
 
 Dev commands
   sendfaucettx                 Send a dcr faucet tx
-  testrun                      Execute a test run of the pi routes
+  testrun                      Execute a test scenario against the pi routes
   seedproposals                Seed the backend with proposals
   votetestsetup                Setup a vote test
   votetest                     Execute a vote test
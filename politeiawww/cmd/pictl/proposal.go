@@ -115,6 +115,21 @@ func printProposal(r rcv1.Record) error {
 		size := byteCountSI(int64(len([]byte(v.Payload))))
 		printf("  %-8v %-2v %v\n", v.PluginID, v.StreamID, size)
 	}
+	prov, err := pclient.ProvenanceDecode(r.Metadata)
+	if err != nil {
+		return err
+	}
+	if prov != nil {
+		printf("Provenance\n")
+		printf("  Source   : %v %v version %v\n", prov.SourceSystem,
+			prov.SourceToken, prov.SourceVersion)
+		printf("  Updated  : %v\n", dateAndTimeFromUnix(prov.SourceTimestamp))
+		if prov.PublishedAt != 0 {
+			printf("  Published: %v\n", dateAndTimeFromUnix(prov.PublishedAt))
+		}
+		printf("  Imported : %v by %v %v\n", dateAndTimeFromUnix(prov.ImportedAt),
+			prov.ConversionTool, prov.ConversionToolVersion)
+	}
 	printf("Files\n")
 	return printProposalFiles(r.Files)
 }
@@ -247,6 +262,15 @@ func proposalFilesFromDisk(indexFile string, attachments []string) ([]rcv1.File,
 			return nil, fmt.Errorf("ReadFile %v: %v", fp, err)
 		}
 
+		// Strip any metadata from PNG images. The server rejects
+		// images that contain metadata.
+		if mime.DetectMimeType(payload) == "image/png" {
+			payload, err = util.PNGSanitize(payload)
+			if err != nil {
+				return nil, fmt.Errorf("PNGSanitize %v: %v", fp, err)
+			}
+		}
+
 		files = append(files, rcv1.File{
 			Name:    filepath.Base(fn),
 			MIME:    mime.DetectMimeType(payload),
@@ -295,7 +319,8 @@ func parseProposalStatus(status string) pi.PropStatusT {
 		pi.PropStatusRejected,
 		pi.PropStatusActive,
 		pi.PropStatusCompleted,
-		pi.PropStatusClosed:
+		pi.PropStatusClosed,
+		pi.PropStatusLegacy:
 	default:
 		return pi.PropStatusInvalid
 	}
@@ -0,0 +1,190 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/decred/politeia/politeiad/api/v1/mime"
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	"github.com/decred/politeia/util"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// proposalRepoMetadataFile is the name of the file in a proposal
+	// repo that contains the proposal metadata.
+	proposalRepoMetadataFile = "metadata.yaml"
+
+	// proposalRepoTokenFile is the name of the file that the proposal
+	// token is written to once the proposal has been submitted.
+	proposalRepoTokenFile = "token"
+)
+
+// proposalRepo contains the contents of a proposal repo. A proposal repo is
+// a directory, typically a git repository, that contains an index.md file,
+// the proposal attachments, and a metadata.yaml file. All files in the root
+// of the directory, other than the metadata file, the token file, and hidden
+// files, are treated as proposal files.
+type proposalRepo struct {
+	Dir         string
+	IndexFile   string
+	Attachments []string
+	Metadata    proposalRepoMetadata
+	Token       string // Populated if the proposal has been submitted
+}
+
+// proposalRepoMetadata is the format of the metadata.yaml file. The fields
+// use the same formats as the corresponding proposalnew flags.
+type proposalRepoMetadata struct {
+	Name      string `yaml:"name"`
+	Amount    uint64 `yaml:"amount"`    // In cents
+	StartDate string `yaml:"startdate"` // Format: "01/02/2006"
+	EndDate   string `yaml:"enddate"`   // Format: "01/02/2006"
+	Domain    string `yaml:"domain"`
+	LinkTo    string `yaml:"linkto"`
+	LinkBy    string `yaml:"linkby"` // Duration from the current time
+}
+
+// loadProposalRepo loads the proposal repo at the provided path.
+func loadProposalRepo(path string) (*proposalRepo, error) {
+	dir := util.CleanAndExpandPath(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := proposalRepo{
+		Dir: dir,
+	}
+	for _, v := range entries {
+		fp := filepath.Join(dir, v.Name())
+		switch {
+		case strings.HasPrefix(v.Name(), "."):
+			// Skip hidden files, i.e. the .git directory
+			continue
+		case v.IsDir():
+			continue
+		case v.Name() == piv1.FileNameIndexFile:
+			r.IndexFile = fp
+		case v.Name() == proposalRepoMetadataFile:
+			b, err := os.ReadFile(fp)
+			if err != nil {
+				return nil, err
+			}
+			err = yaml.UnmarshalStrict(b, &r.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", proposalRepoMetadataFile, err)
+			}
+		case v.Name() == proposalRepoTokenFile:
+			b, err := os.ReadFile(fp)
+			if err != nil {
+				return nil, err
+			}
+			r.Token = strings.TrimSpace(string(b))
+		default:
+			r.Attachments = append(r.Attachments, fp)
+		}
+	}
+	if r.IndexFile == "" {
+		return nil, fmt.Errorf("%v not found in %v",
+			piv1.FileNameIndexFile, dir)
+	}
+
+	return &r, nil
+}
+
+// saveToken writes the proposal token to the token file of the repo.
+func (r *proposalRepo) saveToken(token string) error {
+	fp := filepath.Join(r.Dir, proposalRepoTokenFile)
+	return os.WriteFile(fp, []byte(token+"\n"), 0644)
+}
+
+// proposalRepoVerify verifies that the proposal files and metadata of a
+// proposal repo adhere to the pi policy requirements. The server performs
+// the same checks. They are done here so that the author is shown all policy
+// violations before the proposal is signed and submitted.
+func proposalRepoVerify(files []rcv1.File, pm piv1.ProposalMetadata, pr piv1.PolicyReply) error {
+	errs := make([]string, 0, 16)
+
+	// Verify the files
+	var imageCount uint32
+	for _, v := range files {
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(v.MIME, "text/plain"):
+			if uint32(len(b)) > pr.TextFileSizeMax {
+				errs = append(errs, fmt.Sprintf("%v exceeds the max text "+
+					"file size of %v bytes", v.Name, pr.TextFileSizeMax))
+			}
+		case v.MIME == "image/png":
+			imageCount++
+			if uint32(len(b)) > pr.ImageFileSizeMax {
+				errs = append(errs, fmt.Sprintf("%v exceeds the max image "+
+					"file size of %v bytes", v.Name, pr.ImageFileSizeMax))
+			}
+		default:
+			if !mime.MimeValid(v.MIME) {
+				errs = append(errs, fmt.Sprintf("%v has an unsupported "+
+					"mime type %v", v.Name, v.MIME))
+			}
+		}
+	}
+	if imageCount > pr.ImageFileCountMax {
+		errs = append(errs, fmt.Sprintf("got %v image files, max is %v",
+			imageCount, pr.ImageFileCountMax))
+	}
+
+	// Verify the metadata
+	nameLen := uint32(utf8.RuneCountInString(pm.Name))
+	if nameLen < pr.NameLengthMin || nameLen > pr.NameLengthMax {
+		errs = append(errs, fmt.Sprintf("name must be between %v and %v "+
+			"characters", pr.NameLengthMin, pr.NameLengthMax))
+	}
+	var domainValid bool
+	for _, v := range pr.Domains {
+		if pm.Domain == v {
+			domainValid = true
+			break
+		}
+	}
+	if !domainValid {
+		errs = append(errs, fmt.Sprintf("domain '%v' is not one of %v",
+			pm.Domain, pr.Domains))
+	}
+	if pm.Amount != 0 &&
+		(pm.Amount < pr.AmountMin || pm.Amount > pr.AmountMax) {
+		errs = append(errs, fmt.Sprintf("amount must be between %v and %v "+
+			"cents", pr.AmountMin, pr.AmountMax))
+	}
+	now := time.Now().Unix()
+	if pm.StartDate != 0 && pm.StartDate < now+pr.StartDateMin {
+		errs = append(errs, fmt.Sprintf("start date must be at least %v "+
+			"from now", time.Duration(pr.StartDateMin)*time.Second))
+	}
+	if pm.EndDate != 0 && pm.EndDate > now+pr.EndDateMax {
+		errs = append(errs, fmt.Sprintf("end date must be at most %v "+
+			"from now", time.Duration(pr.EndDateMax)*time.Second))
+	}
+	if pm.StartDate != 0 && pm.EndDate != 0 && pm.EndDate <= pm.StartDate {
+		errs = append(errs, "end date must be after the start date")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("proposal repo does not adhere to the pi "+
+			"policy:\n  %v", strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
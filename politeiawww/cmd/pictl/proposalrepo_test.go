@@ -0,0 +1,82 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+)
+
+func TestLoadProposalRepo(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Missing index file
+	_, err := loadProposalRepo(dir)
+	if err == nil {
+		t.Fatalf("got nil error for missing index file")
+	}
+
+	// Valid repo. Hidden files and directories should be ignored.
+	write(piv1.FileNameIndexFile, "# Proposal")
+	write("notes.txt", "attachment")
+	write(".gitignore", "")
+	write(proposalRepoMetadataFile, "name: My proposal\n"+
+		"amount: 100000\n"+
+		"domain: development\n")
+	err = os.Mkdir(filepath.Join(dir, ".git"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := loadProposalRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Attachments) != 1 ||
+		filepath.Base(r.Attachments[0]) != "notes.txt" {
+		t.Errorf("got attachments %v, want [notes.txt]", r.Attachments)
+	}
+	m := r.Metadata
+	if m.Name != "My proposal" || m.Amount != 100000 ||
+		m.Domain != "development" {
+		t.Errorf("unexpected metadata %+v", m)
+	}
+	if r.Token != "" {
+		t.Errorf("got token %v, want none", r.Token)
+	}
+
+	// Saved token should be loaded and should not be treated as an
+	// attachment.
+	err = r.saveToken("abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = loadProposalRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Token != "abcdef" {
+		t.Errorf("got token %v, want abcdef", r.Token)
+	}
+	if len(r.Attachments) != 1 {
+		t.Errorf("got %v attachments, want 1", len(r.Attachments))
+	}
+
+	// Unknown metadata fields should be rejected
+	write(proposalRepoMetadataFile, "title: My proposal\n")
+	_, err = loadProposalRepo(dir)
+	if err == nil {
+		t.Errorf("got nil error for unknown metadata field")
+	}
+}
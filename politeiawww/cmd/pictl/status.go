@@ -0,0 +1,32 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// statusCmd gets the server status.
+type statusCmd struct{}
+
+// Execute executes the statusCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *statusCmd) Execute(args []string) error {
+	sr, err := client.Status(www.Status{})
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(sr)
+}
+
+// statusHelpMsg is the output of the help command when 'status' is specified.
+const statusHelpMsg = `status
+
+Fetch the server status. The status includes the banners that are currently
+being displayed, sorted from most to least severe.
+
+Arguments: None`
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// userProposalCreditsTransferCmd transfers unspent proposal credits from the
+// logged in user to another user.
+type userProposalCreditsTransferCmd struct {
+	Args struct {
+		Username   string `positional-arg-name:"username"`   // Recipient
+		NumCredits uint64 `positional-arg-name:"numcredits"` // Credits
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the userProposalCreditsTransferCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *userProposalCreditsTransferCmd) Execute(args []string) error {
+	t := &www.UserProposalCreditsTransfer{
+		Username:   cmd.Args.Username,
+		NumCredits: cmd.Args.NumCredits,
+	}
+
+	// Print request details
+	err := shared.PrintJSON(t)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	tr, err := client.UserProposalCreditsTransfer(t)
+	if err != nil {
+		return err
+	}
+
+	// Print response details
+	return shared.PrintJSON(tr)
+}
+
+// userProposalCreditsTransferHelpMsg is the output of the help command when
+// 'userproposalcreditstransfer' is specified.
+const userProposalCreditsTransferHelpMsg = `userproposalcreditstransfer "username" numcredits
+
+Transfer unspent proposal credits from the logged in user to another user. The
+recipient is notified of the transfer by email.
+
+Arguments:
+1. username      (string, required)   Recipient username
+2. numcredits    (uint64, required)   Number of credits to transfer`
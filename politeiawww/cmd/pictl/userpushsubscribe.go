@@ -0,0 +1,65 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// userPushSubscribeCmd adds a web push subscription to the logged in user.
+type userPushSubscribeCmd struct {
+	Args struct {
+		Endpoint      string `positional-arg-name:"endpoint"`
+		P256dh        string `positional-arg-name:"p256dh"`
+		Auth          string `positional-arg-name:"auth"`
+		Notifications uint64 `positional-arg-name:"notifications"`
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the userPushSubscribeCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *userPushSubscribeCmd) Execute(args []string) error {
+	ps := &www.PushSubscribe{
+		Endpoint:      cmd.Args.Endpoint,
+		P256dh:        cmd.Args.P256dh,
+		Auth:          cmd.Args.Auth,
+		Notifications: cmd.Args.Notifications,
+	}
+
+	// Print request details
+	err := shared.PrintJSON(ps)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	psr, err := client.PushSubscribe(ps)
+	if err != nil {
+		return err
+	}
+
+	// Print response details
+	return shared.PrintJSON(psr)
+}
+
+// userPushSubscribeHelpMsg is the output of the help command when
+// 'userpushsubscribe' is specified.
+const userPushSubscribeHelpMsg = `userpushsubscribe "endpoint" "p256dh" "auth" notifications
+
+Add a web push subscription to the logged in user. The endpoint and keys are
+the values of the subscription returned by the browser Push API. An existing
+subscription with the same endpoint is replaced.
+
+Notification bits:
+1. Vote started
+2. Reply to one of your comments
+
+Arguments:
+1. endpoint       (string, required)   Push service endpoint url
+2. p256dh         (string, required)   Base64url encoded client public key
+3. auth           (string, required)   Base64url encoded client auth secret
+4. notifications  (uint64, required)   Notification bit field`
@@ -0,0 +1,32 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import "github.com/decred/politeia/politeiawww/cmd/shared"
+
+// userPushSubscriptionsCmd gets the web push subscriptions of the logged in
+// user.
+type userPushSubscriptionsCmd struct{}
+
+// Execute executes the userPushSubscriptionsCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *userPushSubscriptionsCmd) Execute(args []string) error {
+	psr, err := client.PushSubscriptions()
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(psr)
+}
+
+// userPushSubscriptionsHelpMsg is the output of the help command when
+// 'userpushsubscriptions' is specified.
+const userPushSubscriptionsHelpMsg = `userpushsubscriptions
+
+Fetch the logged in user's web push subscriptions and the server's VAPID
+public key. The public key must be used as the applicationServerKey when
+creating a browser push subscription.
+
+Arguments: None`
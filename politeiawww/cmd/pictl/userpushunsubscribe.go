@@ -0,0 +1,52 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// userPushUnsubscribeCmd removes a web push subscription from the logged in
+// user.
+type userPushUnsubscribeCmd struct {
+	Args struct {
+		Endpoint string `positional-arg-name:"endpoint"`
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the userPushUnsubscribeCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *userPushUnsubscribeCmd) Execute(args []string) error {
+	pu := &www.PushUnsubscribe{
+		Endpoint: cmd.Args.Endpoint,
+	}
+
+	// Print request details
+	err := shared.PrintJSON(pu)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	pur, err := client.PushUnsubscribe(pu)
+	if err != nil {
+		return err
+	}
+
+	// Print response details
+	return shared.PrintJSON(pur)
+}
+
+// userPushUnsubscribeHelpMsg is the output of the help command when
+// 'userpushunsubscribe' is specified.
+const userPushUnsubscribeHelpMsg = `userpushunsubscribe "endpoint"
+
+Remove the web push subscription with the provided endpoint from the logged in
+user.
+
+Arguments:
+1. endpoint  (string, required)   Push service endpoint url`
@@ -0,0 +1,47 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+)
+
+// usersUsageCmd gets the aggregate API request counts of all users.
+type usersUsageCmd struct {
+	Days   uint32 `long:"days" optional:"true"`
+	UserID string `long:"userid" optional:"true"`
+}
+
+// Execute executes the usersUsageCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *usersUsageCmd) Execute(args []string) error {
+	uur, err := client.UsersUsage(&www.UsersUsage{
+		Days:   cmd.Days,
+		UserID: cmd.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(uur)
+}
+
+// usersUsageHelpMsg is the output of the help command when 'usersusage' is
+// specified.
+const usersUsageHelpMsg = `usersusage
+
+Fetch the aggregate API request counts of all users. The request counts of the
+users with the most requests and of all routes are returned. The daily request
+counts of a specific user are also returned when a user ID is provided.
+
+Arguments: None
+
+Flags:
+ --days    (uint32, optional)  Number of days to aggregate, including the
+                               current UTC day. Defaults to the retention
+                               window of the server.
+ --userid  (string, optional)  ID of a user to return the daily request
+                               counts for.`
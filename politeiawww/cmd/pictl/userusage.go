@@ -0,0 +1,30 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import "github.com/decred/politeia/politeiawww/cmd/shared"
+
+// userUsageCmd gets the daily API request counts of the logged in user.
+type userUsageCmd struct{}
+
+// Execute executes the userUsageCmd command.
+//
+// This function satisfies the go-flags Commander interface.
+func (cmd *userUsageCmd) Execute(args []string) error {
+	uur, err := client.UserUsage()
+	if err != nil {
+		return err
+	}
+	return shared.PrintJSON(uur)
+}
+
+// userUsageHelpMsg is the output of the help command when 'userusage' is
+// specified.
+const userUsageHelpMsg = `userusage
+
+Fetch the daily API request counts of the logged in user. Only the requests
+that were made while logged in are counted.
+
+Arguments: None`
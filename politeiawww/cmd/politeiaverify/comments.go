@@ -16,17 +16,20 @@ import (
 )
 
 // commentsBundle represents the comments bundle that is available for download
-// in politeiagui.
+// in politeiagui. The timestamps are optional. When included, they allow the
+// bundle to prove that each comment existed at a specific block height.
 type commentsBundle struct {
-	Comments        []cmv1.Comment `json:"comments"`
-	ServerPublicKey string         `json:"serverpublickey"`
+	Comments        []cmv1.Comment                   `json:"comments"`
+	ServerPublicKey string                           `json:"serverpublickey"`
+	Timestamps      map[uint32]cmv1.CommentTimestamp `json:"timestamps,omitempty"`
 }
 
 // verifyCommentsBundle takes the filepath of a comments bundle and verifies
 // the contents of the file. This includes verifying the signature and receipt
 // of each comment in the bundle. If the comment has been deleted, the original
 // comment signature will not exist but the deletion signature and receipt are
-// verified instead.
+// verified instead. If the bundle includes comment timestamps, the timestamp
+// of each comment is verified as well.
 func verifyCommentsBundle(fp string) error {
 	// Decode comments bundle
 	b, err := os.ReadFile(fp)
@@ -65,6 +68,22 @@ func verifyCommentsBundle(fp string) error {
 	fmt.Printf("Deleted comments: %v\n", dels)
 	fmt.Printf("All signatures and receipts verified!\n")
 
+	if len(cb.Timestamps) == 0 {
+		return nil
+	}
+
+	// Verify comment timestamps
+	notTimestamped, err := client.CommentsTimestampsVerify(cb.Comments,
+		cb.Timestamps)
+	if err != nil {
+		return err
+	}
+	if len(notTimestamped) > 0 {
+		fmt.Printf("Comments not yet timestamped: %v\n",
+			commentIDsString(notTimestamped))
+	}
+	fmt.Printf("All timestamps verified!\n")
+
 	return nil
 }
 
@@ -96,20 +115,8 @@ func verifyCommentTimestamps(fp string) error {
 
 	// Print the IDs of the comments that have not been timestamped yet
 	if len(notTimestamped) > 0 {
-		// Write all comment IDs to a string
-		builder := strings.Builder{}
-		for i, cid := range notTimestamped {
-			s := strconv.FormatUint(uint64(cid), 10)
-			if i == len(notTimestamped)-1 {
-				// This is the last comment ID. Don't include a comma.
-				builder.WriteString(s)
-				break
-			}
-			builder.WriteString(fmt.Sprintf("%v, ", s))
-		}
-
-		// Print results
-		fmt.Printf("Comments not yet timestamped: %v\n", builder.String())
+		fmt.Printf("Comments not yet timestamped: %v\n",
+			commentIDsString(notTimestamped))
 	}
 
 	fmt.Printf("All timestamps verified!\n")
@@ -117,3 +124,19 @@ func verifyCommentTimestamps(fp string) error {
 	return nil
 
 }
+
+// commentIDsString returns the provided comment IDs as a comma separated
+// string.
+func commentIDsString(commentIDs []uint32) string {
+	builder := strings.Builder{}
+	for i, cid := range commentIDs {
+		s := strconv.FormatUint(uint64(cid), 10)
+		if i == len(commentIDs)-1 {
+			// This is the last comment ID. Don't include a comma.
+			builder.WriteString(s)
+			break
+		}
+		builder.WriteString(fmt.Sprintf("%v, ", s))
+	}
+	return builder.String()
+}
@@ -117,6 +117,8 @@ var (
 	expCommentTimestamps = `^[0-9a-f]{7,16}-comments-timestamps.json$`
 	expVotes             = `^[0-9a-f]{7,16}-votes.json$`
 	expVoteTimestamps    = `^[0-9a-f]{7,16}-votes-timestamps.json$`
+	expVoteReceipts      = `^[0-9a-f]{7,16}-vote-receipts.json$`
+	expVoteAudit         = `^[0-9a-f]{7,16}-vote-audit.json$`
 
 	regexpJSONFile          = regexp.MustCompile(expJSONFile)
 	regexpRecord            = regexp.MustCompile(expRecord)
@@ -125,6 +127,8 @@ var (
 	regexpCommentTimestamps = regexp.MustCompile(expCommentTimestamps)
 	regexpVotes             = regexp.MustCompile(expVotes)
 	regexpVoteTimestamps    = regexp.MustCompile(expVoteTimestamps)
+	regexpVoteReceipts      = regexp.MustCompile(expVoteReceipts)
+	regexpVoteAudit         = regexp.MustCompile(expVoteAudit)
 )
 
 // verifyFile verifies a data file downloaded from politeiagui. This can be
@@ -138,6 +142,8 @@ var (
 // Comment timestamps: [token]-comments-timestamps.json
 // Votes bundle      : [token]-votes.json
 // Vote timestamps   : [token]-votes-timestamps.json
+// Vote receipts     : [token]-vote-receipts.json
+// Vote audit bundle : [token]-vote-audit.json
 func verifyFile(fp string) error {
 	fp = util.CleanAndExpandPath(fp)
 	filename := filepath.Base(fp)
@@ -156,6 +162,10 @@ func verifyFile(fp string) error {
 		return verifyVotesBundle(fp)
 	case regexpVoteTimestamps.FindString(filename) != "":
 		return verifyVoteTimestamps(fp)
+	case regexpVoteReceipts.FindString(filename) != "":
+		return verifyVoteReceiptsBundle(fp)
+	case regexpVoteAudit.FindString(filename) != "":
+		return verifyVoteAuditBundle(fp)
 	}
 
 	return fmt.Errorf("file not recognized")
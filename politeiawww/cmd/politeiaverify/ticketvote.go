@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
@@ -24,6 +25,14 @@ type votesBundle struct {
 	ServerPublicKey string                 `json:"serverpublickey"`
 }
 
+// voteReceiptsBundle represents a bundle of the cast vote receipts of a
+// single voter. It allows a voter to prove their participation in a vote
+// without relying on the server.
+type voteReceiptsBundle struct {
+	Receipts        []tkv1.VoteReceipt `json:"receipts"`
+	ServerPublicKey string             `json:"serverpublickey"`
+}
+
 // verifyVotesBundle takes the filepath of a votes bundle and verifies the
 // contents of the file. This includes verifying all signatures of the vote
 // authorizations, vote details, and cast votes. The cast votes are also
@@ -76,6 +85,10 @@ func verifyVotesBundle(fp string) error {
 	if err != nil {
 		return err
 	}
+	err = client.EligibleTicketsVerify(*vb.Details)
+	if err != nil {
+		return err
+	}
 
 	fmt.Printf("Vote details signature and receipt verified!\n")
 	fmt.Printf("\n")
@@ -205,3 +218,130 @@ func verifyVoteTimestamps(fp string) error {
 
 	return nil
 }
+
+// verifyVoteReceiptsBundle takes the filepath of a vote receipts bundle and
+// verifies the contents of the file. This includes verifying the signature
+// and receipt of each cast vote and verifying that the timestamp of each cast
+// vote is valid.
+func verifyVoteReceiptsBundle(fp string) error {
+	// Decode vote receipts bundle
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	var rb voteReceiptsBundle
+	err = json.Unmarshal(b, &rb)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal vote receipts bundle: %v", err)
+	}
+	if len(rb.Receipts) == 0 {
+		return fmt.Errorf("no vote receipts found")
+	}
+
+	fmt.Printf("Token            : %v\n", rb.Receipts[0].Vote.Token)
+	fmt.Printf("Server public key: %v\n", rb.ServerPublicKey)
+	fmt.Printf("Cast votes       : %v\n", len(rb.Receipts))
+	fmt.Printf("\n")
+
+	// Verify vote receipts
+	notTimestamped := make([]string, 0, len(rb.Receipts))
+	for _, v := range rb.Receipts {
+		fmt.Printf("Ticket    : %v\n", v.Vote.Ticket)
+		fmt.Printf("  Vote bit: %v\n", v.Vote.VoteBit)
+		fmt.Printf("  Receipt : %v\n", v.Vote.Receipt)
+		err := client.VoteReceiptVerify(v, rb.ServerPublicKey)
+		switch err {
+		case nil:
+			fmt.Printf("  DCR tx  : %v\n", v.Timestamp.TxID)
+		case backend.ErrNotTimestamped:
+			notTimestamped = append(notTimestamped, v.Vote.Ticket)
+		default:
+			return fmt.Errorf("could not verify vote receipt %v: %v",
+				v.Vote.Ticket, err)
+		}
+	}
+
+	fmt.Printf("\n")
+	fmt.Printf("Not timestamped yet: %v\n", len(notTimestamped))
+	for _, v := range notTimestamped {
+		fmt.Printf("  %v\n", v)
+	}
+	fmt.Printf("Vote receipts verified!\n")
+
+	return nil
+}
+
+// verifyVoteAuditBundle takes the filepath of a vote audit bundle and verifies
+// the contents of the file. This includes verifying the server signature of
+// the bundle, the signatures and receipts of the vote data, the timestamps of
+// the vote data, that each cast vote was made by an eligible ticket, and that
+// the cast votes add up to the vote results.
+func verifyVoteAuditBundle(fp string) error {
+	// Decode vote audit bundle
+	b, err := os.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	var abr tkv1.AuditBundleReply
+	err = json.Unmarshal(b, &abr)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal vote audit bundle: %v", err)
+	}
+
+	// Verify the signatures, receipts, and timestamps
+	ab, err := client.AuditBundleVerify(abr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Token            : %v\n", ab.Token)
+	fmt.Printf("Server public key: %v\n", abr.PublicKey)
+	fmt.Printf("Status           : %v\n", tkv1.VoteStatuses[ab.Summary.Status])
+	fmt.Printf("Eligible tickets : %v\n", len(ab.Details.EligibleTickets))
+	fmt.Printf("Cast votes       : %v\n", len(ab.Votes))
+	fmt.Printf("\n")
+
+	// Verify that each ticket is eligible and only voted once
+	eligible := make(map[string]struct{}, len(ab.Details.EligibleTickets))
+	for _, v := range ab.Details.EligibleTickets {
+		eligible[v] = struct{}{}
+	}
+	voted := make(map[string]struct{}, len(ab.Votes))
+	tally := make(map[uint64]uint64, len(ab.Details.Params.Options))
+	for _, v := range ab.Votes {
+		if _, ok := eligible[v.Ticket]; !ok {
+			return fmt.Errorf("ticket %v is not eligible", v.Ticket)
+		}
+		if _, ok := voted[v.Ticket]; ok {
+			return fmt.Errorf("ticket %v voted more than once", v.Ticket)
+		}
+		voted[v.Ticket] = struct{}{}
+		bit, err := strconv.ParseUint(v.VoteBit, 16, 64)
+		if err != nil {
+			return fmt.Errorf("ticket %v vote bit %v: %v",
+				v.Ticket, v.VoteBit, err)
+		}
+		tally[bit]++
+	}
+	fmt.Printf("Cast vote eligibility verified!\n")
+
+	// Verify the vote results
+	for _, v := range ab.Summary.Results {
+		if tally[v.VoteBit] != v.Votes {
+			return fmt.Errorf("vote option %v: got %v votes, want %v",
+				v.ID, tally[v.VoteBit], v.Votes)
+		}
+		fmt.Printf("  %-8v %v\n", v.ID, v.Votes)
+	}
+	fmt.Printf("Vote results verified!\n")
+
+	if !ab.Anchored {
+		fmt.Printf("\n")
+		fmt.Printf("The vote data has not been fully timestamped yet. " +
+			"Download the bundle again later to verify the timestamps.\n")
+		return nil
+	}
+	fmt.Printf("Vote timestamps verified!\n")
+
+	return nil
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	defaultHost = "https://proposals.decred.org"
+	defaultDir  = "snapshot"
+)
+
+var (
+	// CLI flags
+	host      = flag.String("host", defaultHost, "politeiawww host")
+	dir       = flag.String("dir", defaultDir, "snapshot output directory")
+	httpsCert = flag.String("httpscert", "", "politeiawww https cert")
+	verbose   = flag.Bool("v", false, "verbose output")
+
+	// snapshotStatuses contains the record statuses that are included
+	// in a snapshot. Unvetted records are not public and are never
+	// included. Censored records only contain their censorship metadata.
+	snapshotStatuses = []rcv1.RecordStatusT{
+		rcv1.RecordStatusPublic,
+		rcv1.RecordStatusArchived,
+		rcv1.RecordStatusCensored,
+	}
+)
+
+// snapshot renders the public politeiawww dataset into a snapshot directory.
+type snapshot struct {
+	client  *client.Client
+	objects *objectStore
+
+	// Page sizes
+	inventoryPageSize       uint32
+	voteSummariesPageSize   uint32
+	proposalSummaryPageSize uint32
+}
+
+// newClient returns a politeiawww client that is able to make requests to
+// the CSRF protected politeiawww routes. Public routes that use the POST
+// method are CSRF protected, so a CSRF token is retrieved from the version
+// route prior to making any other requests.
+func newClient(host, httpsCert string) (*client.Client, error) {
+	h, err := util.NewHTTPClient(false, httpsCert)
+	if err != nil {
+		return nil, err
+	}
+	route := host + www.PoliteiaWWWAPIRoute + www.RouteVersion
+	r, err := h.Get(route)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("version: %v", r.Status)
+	}
+
+	// CSRF protection works via the double-submit method. One token
+	// is sent in the cookie. A second token is sent in the header.
+	return client.New(host, client.Opts{
+		HTTPSCert:  httpsCert,
+		Cookies:    r.Cookies(),
+		HeaderCSRF: r.Header.Get(www.CsrfToken),
+	})
+}
+
+// inventory returns the tokens of all vetted records that have the provided
+// record status.
+func (s *snapshot) inventory(status rcv1.RecordStatusT) ([]string, error) {
+	var (
+		tokens = make([]string, 0, 256)
+		page   uint32
+	)
+	for {
+		page++
+		ir, err := s.client.RecordInventory(rcv1.Inventory{
+			State:  rcv1.RecordStateVetted,
+			Status: status,
+			Page:   page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		t := ir.Vetted[rcv1.RecordStatuses[status]]
+		tokens = append(tokens, t...)
+		if uint32(len(t)) < s.inventoryPageSize {
+			return tokens, nil
+		}
+	}
+}
+
+// proposal saves the data of a single proposal to the object store and
+// returns the manifest entry for it.
+func (s *snapshot) proposal(token string) (*proposalEntry, error) {
+	r, err := s.client.RecordDetails(rcv1.Details{
+		Token: token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record details: %v", err)
+	}
+	e := proposalEntry{
+		Token:        token,
+		RecordStatus: rcv1.RecordStatuses[r.Status],
+	}
+	e.Record, err = s.objects.put(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range r.Files {
+		if v.Name != piv1.FileNameIndexFile {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode index file: %v", err)
+		}
+		e.Index = string(b)
+	}
+	if pm, err := client.ProposalMetadataDecode(r.Files); err == nil {
+		e.Name = pm.Name
+	}
+
+	// The files of censored records have been deleted and censored
+	// records do not have any plugin data that needs to be mirrored.
+	if r.Status == rcv1.RecordStatusCensored {
+		return &e, nil
+	}
+
+	cr, err := s.client.Comments(cmv1.Comments{
+		Token: token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("comments: %v", err)
+	}
+	e.Comments, err = s.objects.put(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	dr, err := s.client.TicketVoteDetails(tkv1.Details{
+		Token: token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("vote details: %v", err)
+	}
+	if len(dr.Auths) > 0 || dr.Vote != nil {
+		e.VoteDetails, err = s.objects.put(dr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dr.Vote != nil {
+		rr, err := s.client.TicketVoteResults(tkv1.Results{
+			Token: token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("vote results: %v", err)
+		}
+		e.VoteResults, err = s.objects.put(rr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+
+// summaries saves the vote summaries and proposal summaries of the provided
+// manifest entries to the object store.
+func (s *snapshot) summaries(entries []proposalEntry) error {
+	// Vote summaries
+	for _, page := range entryPages(entries, s.voteSummariesPageSize) {
+		sr, err := s.client.TicketVoteSummaries(tkv1.Summaries{
+			Tokens: entryTokens(page),
+		})
+		if err != nil {
+			return fmt.Errorf("vote summaries: %v", err)
+		}
+		for j := range page {
+			v, ok := sr.Summaries[page[j].Token]
+			if !ok {
+				continue
+			}
+			page[j].VoteStatus = tkv1.VoteStatuses[v.Status]
+			page[j].VoteSummary, err = s.objects.put(v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Proposal summaries
+	for _, page := range entryPages(entries, s.proposalSummaryPageSize) {
+		sr, err := s.client.PiSummaries(piv1.Summaries{
+			Tokens: entryTokens(page),
+		})
+		if err != nil {
+			return fmt.Errorf("proposal summaries: %v", err)
+		}
+		for j := range page {
+			v, ok := sr.Summaries[page[j].Token]
+			if !ok {
+				continue
+			}
+			page[j].ProposalStatus = v.Status
+			page[j].ProposalSummary, err = s.objects.put(v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// entryPages splits the provided manifest entries into pages of the provided
+// size. The returned pages share the backing array of the provided entries.
+func entryPages(entries []proposalEntry, size uint32) [][]proposalEntry {
+	pages := make([][]proposalEntry, 0, len(entries)/int(size)+1)
+	for len(entries) > 0 {
+		n := int(size)
+		if n > len(entries) {
+			n = len(entries)
+		}
+		pages = append(pages, entries[:n])
+		entries = entries[n:]
+	}
+	return pages
+}
+
+// entryTokens returns the tokens of the provided manifest entries.
+func entryTokens(entries []proposalEntry) []string {
+	tokens := make([]string, 0, len(entries))
+	for _, v := range entries {
+		tokens = append(tokens, v.Token)
+	}
+	return tokens
+}
+
+func _main() error {
+	flag.Parse()
+
+	snapshotDir := util.CleanAndExpandPath(*dir)
+	objects, err := newObjectStore(snapshotDir)
+	if err != nil {
+		return err
+	}
+	c, err := newClient(*host, *httpsCert)
+	if err != nil {
+		return err
+	}
+
+	// Get the route page sizes
+	rp, err := c.RecordPolicy()
+	if err != nil {
+		return err
+	}
+	vp, err := c.TicketVotePolicy()
+	if err != nil {
+		return err
+	}
+	pp, err := c.PiPolicy()
+	if err != nil {
+		return err
+	}
+	if rp.InventoryPageSize == 0 || vp.SummariesPageSize == 0 ||
+		pp.SummariesPageSize == 0 {
+		return fmt.Errorf("invalid policy page sizes")
+	}
+	s := snapshot{
+		client:                  c,
+		objects:                 objects,
+		inventoryPageSize:       rp.InventoryPageSize,
+		voteSummariesPageSize:   vp.SummariesPageSize,
+		proposalSummaryPageSize: pp.SummariesPageSize,
+	}
+
+	// Save all public records
+	m := manifest{
+		Host:      *host,
+		Timestamp: time.Now().Unix(),
+	}
+	for _, status := range snapshotStatuses {
+		tokens, err := s.inventory(status)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v %v records\n", len(tokens), rcv1.RecordStatuses[status])
+		for _, token := range tokens {
+			if *verbose {
+				fmt.Printf("  %v\n", token)
+			}
+			e, err := s.proposal(token)
+			if err != nil {
+				return fmt.Errorf("%v: %v", token, err)
+			}
+			m.Proposals = append(m.Proposals, *e)
+		}
+	}
+	err = s.summaries(m.Proposals)
+	if err != nil {
+		return err
+	}
+
+	// Write the manifest and HTML pages
+	err = writeManifest(snapshotDir, m)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Snapshot written to %v\n", snapshotDir)
+
+	return nil
+}
+
+func main() {
+	err := _main()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
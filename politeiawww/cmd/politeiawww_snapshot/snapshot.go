@@ -0,0 +1,257 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// dirObjects is the snapshot directory that contains the content
+	// addressed JSON objects.
+	dirObjects = "objects"
+
+	// dirProposals is the snapshot directory that contains the proposal
+	// HTML pages.
+	dirProposals = "proposals"
+
+	// fileManifest is the snapshot manifest file. The manifest is the
+	// entry point into the snapshot.
+	fileManifest = "index.json"
+
+	// fileIndex is the snapshot HTML index page.
+	fileIndex = "index.html"
+)
+
+// objectStore writes content addressed JSON objects to disk. Each object is
+// saved to a file that is named using the hex encoded SHA256 digest of the
+// object's JSON encoding, allowing the snapshot to be served from content
+// addressed storage such as IPFS and allowing identical objects to be shared
+// between snapshots.
+type objectStore struct {
+	dir string
+}
+
+// newObjectStore returns a new objectStore that writes objects to the objects
+// directory of the provided snapshot directory.
+func newObjectStore(snapshotDir string) (*objectStore, error) {
+	dir := filepath.Join(snapshotDir, dirObjects)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &objectStore{
+		dir: dir,
+	}, nil
+}
+
+// put writes the JSON encoding of the provided object to disk and returns
+// the object digest. Objects that already exist on disk are not rewritten.
+func (s *objectStore) put(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	digest := hex.EncodeToString(util.Digest(b))
+	fp := filepath.Join(s.dir, digest+".json")
+	_, err = os.Stat(fp)
+	switch {
+	case err == nil:
+		// Object already exists
+		return digest, nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return "", err
+	}
+	err = os.WriteFile(fp, b, 0644)
+	if err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+// objectPath returns the path of an object relative to the snapshot root.
+func objectPath(digest string) string {
+	if digest == "" {
+		return ""
+	}
+	return dirObjects + "/" + digest + ".json"
+}
+
+// manifest is the snapshot manifest. It contains an entry for every record
+// that is included in the snapshot. The object fields contain the digest of
+// the content addressed object. Object fields are left empty when the data
+// does not exist for the record, e.g. a record that has not been voted on
+// will not have vote results.
+type manifest struct {
+	Host      string          `json:"host"`      // politeiawww host
+	Timestamp int64           `json:"timestamp"` // Unix timestamp
+	Proposals []proposalEntry `json:"proposals"`
+}
+
+// proposalEntry is the manifest entry for a single proposal.
+type proposalEntry struct {
+	Token          string `json:"token"`
+	Name           string `json:"name,omitempty"`
+	RecordStatus   string `json:"recordstatus"`
+	ProposalStatus string `json:"proposalstatus,omitempty"`
+	VoteStatus     string `json:"votestatus,omitempty"`
+
+	// Content addressed object digests
+	Record          string `json:"record"`
+	Comments        string `json:"comments,omitempty"`
+	VoteDetails     string `json:"votedetails,omitempty"`
+	VoteResults     string `json:"voteresults,omitempty"`
+	VoteSummary     string `json:"votesummary,omitempty"`
+	ProposalSummary string `json:"proposalsummary,omitempty"`
+
+	// Index contains the proposal index file text. It is only used to
+	// render the proposal HTML page and is not included in the manifest.
+	Index string `json:"-"`
+}
+
+// sortProposals sorts the manifest proposals by token so that the manifest
+// encoding is deterministic.
+func (m *manifest) sortProposals() {
+	sort.Slice(m.Proposals, func(i, j int) bool {
+		return m.Proposals[i].Token < m.Proposals[j].Token
+	})
+}
+
+var funcs = template.FuncMap{
+	"object": objectPath,
+}
+
+var tmplIndex = template.Must(template.New("index").Funcs(funcs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Politeia snapshot</title>
+</head>
+<body>
+<h1>Politeia snapshot</h1>
+<p>Snapshot of {{.Host}} taken at unix time {{.Timestamp}}.
+The full dataset is described by the <a href="index.json">manifest</a>.</p>
+<table>
+<tr><th>Proposal</th><th>Record status</th><th>Proposal status</th><th>Vote status</th></tr>
+{{- range .Proposals}}
+<tr>
+<td><a href="proposals/{{.Token}}.html">{{if .Name}}{{.Name}}{{else}}{{.Token}}{{end}}</a></td>
+<td>{{.RecordStatus}}</td>
+<td>{{.ProposalStatus}}</td>
+<td>{{.VoteStatus}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+var tmplProposal = template.Must(template.New("proposal").Funcs(funcs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{if .Name}}{{.Name}}{{else}}{{.Token}}{{end}}</title>
+</head>
+<body>
+<p><a href="../index.html">All proposals</a></p>
+<h1>{{if .Name}}{{.Name}}{{else}}{{.Token}}{{end}}</h1>
+<ul>
+<li>Token: {{.Token}}</li>
+<li>Record status: {{.RecordStatus}}</li>
+{{- if .ProposalStatus}}
+<li>Proposal status: {{.ProposalStatus}}</li>
+{{- end}}
+{{- if .VoteStatus}}
+<li>Vote status: {{.VoteStatus}}</li>
+{{- end}}
+</ul>
+<h2>Data</h2>
+<ul>
+<li><a href="../{{object .Record}}">Record</a></li>
+{{- if .Comments}}
+<li><a href="../{{object .Comments}}">Comments</a></li>
+{{- end}}
+{{- if .VoteDetails}}
+<li><a href="../{{object .VoteDetails}}">Vote details</a></li>
+{{- end}}
+{{- if .VoteResults}}
+<li><a href="../{{object .VoteResults}}">Vote results</a></li>
+{{- end}}
+{{- if .VoteSummary}}
+<li><a href="../{{object .VoteSummary}}">Vote summary</a></li>
+{{- end}}
+{{- if .ProposalSummary}}
+<li><a href="../{{object .ProposalSummary}}">Proposal summary</a></li>
+{{- end}}
+</ul>
+{{- if .Index}}
+<h2>Proposal</h2>
+<pre>{{.Index}}</pre>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeManifest writes the manifest and the HTML pages to the snapshot
+// directory.
+func writeManifest(snapshotDir string, m manifest) error {
+	m.sortProposals()
+
+	// Write manifest
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(snapshotDir, fileManifest), b, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Write HTML pages
+	err = writeTemplate(filepath.Join(snapshotDir, fileIndex), tmplIndex, m)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(snapshotDir, dirProposals)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, v := range m.Proposals {
+		fp := filepath.Join(dir, v.Token+".html")
+		err = writeTemplate(fp, tmplProposal, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeTemplate executes the provided template and writes the result to the
+// provided file path.
+func writeTemplate(fp string, t *template.Template, data interface{}) error {
+	f, err := os.Create(fp)
+	if err != nil {
+		return err
+	}
+	err = t.Execute(f, data)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/decred/politeia/util"
+)
+
+func TestObjectStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newObjectStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the object is content addressed
+	v := map[string]string{"token": "45154fb45664714b"}
+	digest, err := s.put(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, objectPath(digest)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(util.Digest(b)) != digest {
+		t.Fatalf("object digest does not match object content")
+	}
+
+	// Identical objects are only written once
+	digest2, err := s.put(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != digest2 {
+		t.Fatalf("got digest %v, want %v", digest2, digest)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, dirObjects))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %v objects, want 1", len(entries))
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	m := manifest{
+		Host:      "https://localhost:4443",
+		Timestamp: 1,
+		Proposals: []proposalEntry{
+			{
+				Token:        "b",
+				Name:         "<script>",
+				RecordStatus: "public",
+				Record:       "digest",
+				Index:        "proposal text",
+			},
+			{
+				Token:        "a",
+				RecordStatus: "censored",
+				Record:       "digest",
+			},
+		},
+	}
+	err := writeManifest(dir, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the manifest is sorted and does not include the index
+	b, err := os.ReadFile(filepath.Join(dir, fileManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got manifest
+	err = json.Unmarshal(b, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Proposals[0].Token != "a" || got.Proposals[1].Token != "b" {
+		t.Errorf("manifest proposals are not sorted")
+	}
+	if strings.Contains(string(b), "proposal text") {
+		t.Errorf("manifest contains the proposal index file")
+	}
+
+	// Verify the HTML pages were written and are escaped
+	b, err = os.ReadFile(filepath.Join(dir, fileIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "<script>") {
+		t.Errorf("index page contains unescaped html")
+	}
+	b, err = os.ReadFile(filepath.Join(dir, dirProposals, "b.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "proposal text") {
+		t.Errorf("proposal page does not contain the proposal index file")
+	}
+	if !strings.Contains(string(b), objectPath("digest")) {
+		t.Errorf("proposal page does not link to the record object")
+	}
+}
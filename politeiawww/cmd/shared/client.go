@@ -698,6 +698,63 @@ func (c *Client) ChangePassword(cp *www.ChangePassword) (*www.ChangePasswordRepl
 	return &cpr, nil
 }
 
+// ChangeEmail requests an email address change for the logged in user.
+func (c *Client) ChangeEmail(ce *www.ChangeEmail) (*www.ChangeEmailReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteChangeEmail, ce)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var cer www.ChangeEmailReply
+	err = json.Unmarshal(respBody, &cer)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal ChangeEmailReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(cer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &cer, nil
+}
+
+// VerifyChangeEmail verifies an email change verification token for the
+// logged in user.
+func (c *Client) VerifyChangeEmail(vce *www.VerifyChangeEmail) (*www.VerifyChangeEmailReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteVerifyChangeEmail, vce)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var vcer www.VerifyChangeEmailReply
+	err = json.Unmarshal(respBody, &vcer)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal VerifyChangeEmailReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(vcer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &vcer, nil
+}
+
 // ResetPassword resets the password of the specified user.
 func (c *Client) ResetPassword(rp *www.ResetPassword) (*www.ResetPasswordReply, error) {
 	statusCode, respBody, err := c.makeRequest(http.MethodPost,
@@ -1660,6 +1717,349 @@ func (c *Client) UserProposalCredits() (*www.UserProposalCreditsReply, error) {
 	return &upcr, nil
 }
 
+// UserProposalCreditsTransfer transfers unspent proposal credits from the
+// logged in user to another user.
+func (c *Client) UserProposalCreditsTransfer(t *www.UserProposalCreditsTransfer) (*www.UserProposalCreditsTransferReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteUserProposalCreditsTransfer, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var tr www.UserProposalCreditsTransferReply
+	err = json.Unmarshal(respBody, &tr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal "+
+			"UserProposalCreditsTransferReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(tr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &tr, nil
+}
+
+// PushSubscriptions returns the web push subscriptions of the logged in
+// user.
+func (c *Client) PushSubscriptions() (*www.PushSubscriptionsReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodGet,
+		www.PoliteiaWWWAPIRoute, www.RoutePushSubscriptions, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.PushSubscriptionsReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal PushSubscriptionsReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// PushSubscribe adds a web push subscription to the logged in user.
+func (c *Client) PushSubscribe(ps *www.PushSubscribe) (*www.PushSubscribeReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RoutePushSubscribe, ps)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.PushSubscribeReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal PushSubscribeReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// RevokeUserKey revokes one of the active identities of the logged in user.
+func (c *Client) RevokeUserKey(rk *www.RevokeUserKey) (*www.RevokeUserKeyReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteRevokeUserKey, rk)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.RevokeUserKeyReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal RevokeUserKeyReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// PushUnsubscribe removes a web push subscription from the logged in
+// user.
+func (c *Client) PushUnsubscribe(pu *www.PushUnsubscribe) (*www.PushUnsubscribeReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RoutePushUnsubscribe, pu)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.PushUnsubscribeReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal PushUnsubscribeReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// UserUsage returns the daily API request counts of the logged in user.
+func (c *Client) UserUsage() (*www.UserUsageReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodGet,
+		www.PoliteiaWWWAPIRoute, www.RouteUserUsage, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.UserUsageReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal UserUsageReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// UsersUsage returns the aggregate API request counts of all users.
+func (c *Client) UsersUsage(uu *www.UsersUsage) (*www.UsersUsageReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodGet,
+		www.PoliteiaWWWAPIRoute, www.RouteUsersUsage, uu)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.UsersUsageReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal UsersUsageReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// Changes returns the changes to the public record inventory that were made
+// since the provided sequence number.
+func (c *Client) Changes(ch *www.Changes) (*www.ChangesReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodGet,
+		www.PoliteiaWWWAPIRoute, www.RouteChanges, ch)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.ChangesReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal ChangesReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// Status returns the server status, which includes the banners that are
+// currently being displayed.
+func (c *Client) Status(st www.Status) (*www.StatusReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodGet,
+		www.PoliteiaWWWAPIRoute, www.RouteStatus, st)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.StatusReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal StatusReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// Banners returns all banners, including the banners that are not
+// currently being displayed.
+func (c *Client) Banners(b www.Banners) (*www.BannersReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteBanners, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.BannersReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal BannersReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// BannerSet creates or updates a banner.
+func (c *Client) BannerSet(bs www.BannerSet) (*www.BannerSetReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteBannerSet, bs)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.BannerSetReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal BannerSetReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// BannerDel deletes a banner.
+func (c *Client) BannerDel(bd www.BannerDel) (*www.BannerDelReply, error) {
+	statusCode, respBody, err := c.makeRequest(http.MethodPost,
+		www.PoliteiaWWWAPIRoute, www.RouteBannerDel, bd)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wwwError(respBody, statusCode)
+	}
+
+	var r www.BannerDelReply
+	err = json.Unmarshal(respBody, &r)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal BannerDelReply: %v", err)
+	}
+
+	if c.cfg.Verbose {
+		err := prettyPrintJSON(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
 // ResendVerification re-sends the user verification email for an unverified
 // user.
 func (c *Client) ResendVerification(rv www.ResendVerification) (*www.ResendVerificationReply, error) {
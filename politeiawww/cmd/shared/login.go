@@ -6,6 +6,7 @@ package shared
 
 import (
 	"fmt"
+	"os"
 
 	v1 "github.com/decred/politeia/politeiawww/api/www/v1"
 )
@@ -54,7 +55,22 @@ func (cmd *LoginCmd) Execute(args []string) error {
 	}
 
 	// Print response details
-	return PrintJSON(lr)
+	err = PrintJSON(lr)
+	if err != nil {
+		return err
+	}
+
+	// Warn the user if losing a single key would lock them out of
+	// their account. The warning is printed to stderr so that it
+	// does not interfere with the JSON output.
+	if lr.ActiveKeys == 1 {
+		fmt.Fprintf(os.Stderr, "WARNING: your account only has a single "+
+			"active identity. If it is lost you will no longer be able to "+
+			"sign anything. Consider registering a key from a second device "+
+			"using 'userkeyupdate --additional'.\n")
+	}
+
+	return nil
 }
 
 // LoginHelpMsg is the output for the help command when 'login' is specified.
@@ -74,6 +90,7 @@ Result:
   "email":                (string)  User email
   "username":             (string)  Username
   "publickey":            (string)  Active public key
+  "activekeys":           (uint32)  Number of active public keys
   "paywalladdress":       (string)  Registration paywall address
   "paywallamount":        (uint64)  Registration paywall amount in atoms
   "paywalltxnotbefore":   (int64)   Minimum timestamp for paywall tx
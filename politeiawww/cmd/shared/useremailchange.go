@@ -0,0 +1,93 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package shared
+
+import v1 "github.com/decred/politeia/politeiawww/api/www/v1"
+
+// UserEmailChangeCmd requests an email address change for the logged in
+// user.
+type UserEmailChangeCmd struct {
+	Args struct {
+		Password string `positional-arg-name:"password"` // User password
+		NewEmail string `positional-arg-name:"newemail"` // New email
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the change email command.
+func (cmd *UserEmailChangeCmd) Execute(args []string) error {
+	ce := &v1.ChangeEmail{
+		Password: DigestSHA3(cmd.Args.Password),
+		NewEmail: cmd.Args.NewEmail,
+	}
+
+	// Print request details
+	err := PrintJSON(ce)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	cer, err := client.ChangeEmail(ce)
+	if err != nil {
+		return err
+	}
+
+	// Print response details
+	return PrintJSON(cer)
+}
+
+// UserEmailChangeHelpMsg is the output of the help command when
+// 'useremailchange' is specified.
+var UserEmailChangeHelpMsg = `useremailchange "password" "newemail"
+
+Request an email address change for the currently logged in user. A
+verification token is sent to both the current and the new email address. The
+email address is only updated once both tokens have been verified using the
+useremailchangeverify command.
+
+Arguments:
+1. password      (string, required)   Current password
+2. newemail      (string, required)   New email address`
+
+// UserEmailChangeVerifyCmd verifies an email change verification token for
+// the logged in user.
+type UserEmailChangeVerifyCmd struct {
+	Args struct {
+		Token string `positional-arg-name:"token"` // Verification token
+	} `positional-args:"true" required:"true"`
+}
+
+// Execute executes the verify change email command.
+func (cmd *UserEmailChangeVerifyCmd) Execute(args []string) error {
+	vce := &v1.VerifyChangeEmail{
+		VerificationToken: cmd.Args.Token,
+	}
+
+	// Print request details
+	err := PrintJSON(vce)
+	if err != nil {
+		return err
+	}
+
+	// Send request
+	vcer, err := client.VerifyChangeEmail(vce)
+	if err != nil {
+		return err
+	}
+
+	// Print response details
+	return PrintJSON(vcer)
+}
+
+// UserEmailChangeVerifyHelpMsg is the output of the help command when
+// 'useremailchangeverify' is specified.
+var UserEmailChangeVerifyHelpMsg = `useremailchangeverify "token"
+
+Verify one of the email change verification tokens for the currently logged in
+user. The email address is updated once the tokens of both the old and the new
+email address have been verified.
+
+Arguments:
+1. token         (string, required)   Verification token`
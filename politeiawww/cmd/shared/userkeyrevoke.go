@@ -0,0 +1,48 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package shared
+
+import (
+	"fmt"
+
+	v1 "github.com/decred/politeia/politeiawww/api/www/v1"
+)
+
+// UserKeyRevokeCmd revokes one of the active identities of the logged in
+// user.
+type UserKeyRevokeCmd struct {
+	Args struct {
+		PublicKey string `positional-arg-name:"publickey" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the revoke user key command.
+func (cmd *UserKeyRevokeCmd) Execute(args []string) error {
+	rk := &v1.RevokeUserKey{
+		PublicKey: cmd.Args.PublicKey,
+	}
+
+	err := PrintJSON(rk)
+	if err != nil {
+		return err
+	}
+
+	rkr, err := client.RevokeUserKey(rk)
+	if err != nil {
+		return fmt.Errorf("RevokeUserKey: %v", err)
+	}
+
+	return PrintJSON(rkr)
+}
+
+// UserKeyRevokeHelpMsg is the output of the help command when 'userkeyrevoke'
+// is specified.
+const UserKeyRevokeHelpMsg = `userkeyrevoke "publickey"
+
+Revoke one of the active public keys of the currently logged in user, e.g. the
+key of a lost device. The last active key cannot be revoked.
+
+Arguments:
+1. publickey  (string, required)  Public key to revoke`
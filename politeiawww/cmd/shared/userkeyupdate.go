@@ -13,7 +13,9 @@ import (
 
 // UserKeyUpdateCmd creates a new identity for the logged in user.
 type UserKeyUpdateCmd struct {
-	NoSave bool `long:"nosave"` // Don't save new identity to disk
+	NoSave     bool   `long:"nosave"`     // Don't save new identity to disk
+	Label      string `long:"label"`      // Identity description
+	Additional bool   `long:"additional"` // Keep existing active identities
 }
 
 // Execute executes the update user key command.
@@ -33,7 +35,9 @@ func (cmd *UserKeyUpdateCmd) Execute(args []string) error {
 
 	// Update user key
 	uuk := &v1.UpdateUserKey{
-		PublicKey: hex.EncodeToString(id.Public.Key[:]),
+		PublicKey:  hex.EncodeToString(id.Public.Key[:]),
+		Label:      cmd.Label,
+		Additional: cmd.Additional,
 	}
 
 	err = PrintJSON(uuk)
@@ -73,5 +77,14 @@ const UserKeyUpdateHelpMsg = `userkeyupdate
 
 Generate a new public key for the currently logged in user. 
 
+By default the new key replaces all of the user's active keys. Use the
+--additional flag to keep the existing active keys, e.g. when registering a key
+from a second device. Signatures made with any active key are accepted.
+
 Arguments:
-None`
+None
+
+Flags:
+ --label      (string) Description of the key, e.g. the device name.
+ --additional (bool)   Add the key alongside the existing active keys.
+ --nosave     (bool)   Don't save the new identity to disk.`
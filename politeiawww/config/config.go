@@ -16,10 +16,12 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/politeia/politeiad/api/v1/identity"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
 	"github.com/decred/politeia/politeiawww/logger"
 	"github.com/decred/politeia/util"
 	"github.com/decred/politeia/util/version"
@@ -76,6 +78,7 @@ const (
 	defaultReadTimeout        int64  = 5               // In seconds
 	defaultWriteTimeout       int64  = 60              // In seconds
 	defaultReqBodySizeLimit   int64  = 3 * 1024 * 1024 // 3 MiB
+	defaultFileBodySizeLimit  int64  = 8 * 1024 * 1024 // 8 MiB
 	defaultWebsocketReadLimit int64  = 4 * 1024 * 1024 // 4 KiB
 	defaultPluginBatchLimit   uint32 = 20
 
@@ -131,6 +134,7 @@ type Config struct {
 	ReadTimeout        int64    `long:"readtimeout" description:"Maximum duration in seconds that is spent reading the request headers and body"`
 	WriteTimeout       int64    `long:"writetimeout" description:"Maximum duration in seconds that a request connection is kept open"`
 	ReqBodySizeLimit   int64    `long:"reqbodysizelimit" description:"Maximum number of bytes allowed in a request body submitted by a client"`
+	RouteBodySizeLimit []string `long:"routebodysizelimit" description:"Maximum number of bytes allowed in the request body of a specific route in the format <route>,<bytes> -- Overrides reqbodysizelimit for the route"`
 	WebsocketReadLimit int64    `long:"websocketreadlimit" description:"Maximum number of bytes allowed for a message read from a websocket client"`
 	PluginBatchLimit   uint32   `long:"pluginbatchlimit" description:"Maximum number of plugins command allowed in a batch request."`
 
@@ -170,6 +174,7 @@ type Config struct {
 	LegacyConfig
 
 	Version     string
+	RouteLimits map[string]int64         // [route]body size limit in bytes
 	ActiveNet   *ChainParams             // Active DCR network
 	Identity    *identity.PublicIdentity // politeiad identity
 	SystemCerts *x509.CertPool
@@ -232,6 +237,9 @@ func Load() (*Config, []string, error) {
 			VoteDurationMin:          defaultVoteDurationMin,
 			VoteDurationMax:          defaultVoteDurationMax,
 			MailRateLimit:            defaultMailRateLimit,
+			AdminConfirmWindow:       defaultAdminConfirmWindow,
+			UsageRetention:           defaultUsageRetention,
+			ChangesMax:               defaultChangesMax,
 		},
 
 		Version: version.Version,
@@ -440,6 +448,21 @@ func setupHTTPServerSettings(cfg *Config) error {
 	cfg.HTTPSKey = util.CleanAndExpandPath(cfg.HTTPSKey)
 	cfg.CookieKeyFile = util.CleanAndExpandPath(cfg.CookieKeyFile)
 
+	// Parse the route body size limits. The routes that accept record
+	// file uploads are given a larger default limit than the other
+	// routes.
+	cfg.RouteLimits = map[string]int64{
+		rcv1.APIRoute + rcv1.RouteNew:  defaultFileBodySizeLimit,
+		rcv1.APIRoute + rcv1.RouteEdit: defaultFileBodySizeLimit,
+	}
+	for _, v := range cfg.RouteBodySizeLimit {
+		route, limit, err := parseRouteBodySizeLimit(v)
+		if err != nil {
+			return fmt.Errorf("invalid routebodysizelimit '%v': %v", v, err)
+		}
+		cfg.RouteLimits[route] = limit
+	}
+
 	// Add the default listener if none were specified. The
 	// default listener is all addresses on the listen port
 	// for the network we are to connect to.
@@ -460,6 +483,27 @@ func setupHTTPServerSettings(cfg *Config) error {
 	return nil
 }
 
+// parseRouteBodySizeLimit parses a routebodysizelimit config setting. The
+// setting uses the format <route>,<bytes>.
+func parseRouteBodySizeLimit(s string) (string, int64, error) {
+	i := strings.LastIndex(s, ",")
+	if i == -1 {
+		return "", 0, fmt.Errorf("format must be <route>,<bytes>")
+	}
+	route, limitStr := s[:i], s[i+1:]
+	if !strings.HasPrefix(route, "/") {
+		return "", 0, fmt.Errorf("route must start with '/'")
+	}
+	limit, err := strconv.ParseInt(limitStr, 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	if limit <= 0 {
+		return "", 0, fmt.Errorf("limit must be positive")
+	}
+	return route, limit, nil
+}
+
 // setupRPCSettings sets up the politeiad RPC config settings.
 func setupRPCSettings(cfg *Config) error {
 	// Setup default values if none were provided
@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/decred/dcrd/hdkeychain/v3"
@@ -33,6 +34,15 @@ const (
 	defaultMailAddressCMS = "Contractor Management System <noreply@example.org>"
 	defaultMailRateLimit  = 100 // Email limit per user
 
+	defaultAdminConfirmWindow int64 = 86400 // 24 hours in seconds
+
+	defaultWebPushKeyFilename = "webpush.key"
+
+	defaultUsageRetention = uint32(30) // In days
+	usageRetentionMax     = uint32(366)
+
+	defaultChangesMax = uint32(10000)
+
 	defaultVoteDurationMin = uint32(2016)
 	defaultVoteDurationMax = uint32(4032)
 
@@ -75,6 +85,10 @@ type LegacyConfig struct {
 	MailRateLimit    int    `long:"mailratelimit" description:"Limits the amount of emails a user can receive in 24h"`
 	WebServerAddress string `long:"webserveraddress" description:"Web server address used to create email links (format: <scheme>://<host>[:<port>])"`
 
+	// Legacy admin settings
+	AdminConfirm       bool  `long:"adminconfirm" description:"Require a second admin to confirm censoring a vetted record or deactivating a user"`
+	AdminConfirmWindow int64 `long:"adminconfirmwindow" description:"Number of seconds that a second admin has to confirm a pending admin action"`
+
 	// Legacy API settings
 	Mode        string `long:"mode" description:"Mode www runs as. Supported values: piwww, cmswww"`
 	DcrdataHost string `long:"dcrdatahost" description:"Dcrdata ip:port"`
@@ -83,6 +97,11 @@ type LegacyConfig struct {
 	PaywallAmount            uint64 `long:"paywallamount" description:"Amount of DCR (in atoms) required for a user to register or submit a proposal."`
 	PaywallXpub              string `long:"paywallxpub" description:"Extended public key for deriving paywall addresses."`
 	MinConfirmationsRequired uint64 `long:"minconfirmations" description:"Minimum blocks confirmation for accepting paywall as paid. Only works in TestNet."`
+	WebPushSubject           string `long:"webpushsubject" description:"Contact URI (mailto: or https:) that is sent to push services. Web push notifications are enabled when this is set."`
+	WebPushKeyFile           string `long:"webpushkey" description:"File containing the VAPID private key used to sign web push notifications"`
+	WriteQueue               bool   `long:"writequeue" description:"Queue comment and ballot submissions to disk when politeiad is unavailable and retry them once it becomes available"`
+	UsageRetention           uint32 `long:"usageretention" description:"Number of days that the per-user API request counts are kept for. API usage tracking is disabled when set to 0."`
+	ChangesMax               uint32 `long:"changesmax" description:"Maximum number of public inventory changes that are kept for incremental syncing by mirrors. The changes route is disabled when set to 0."`
 
 	// Legacy cmswww settings
 	BuildCMSDB           bool     `long:"buildcmsdb" description:"Build the cmsdb from scratch"`
@@ -127,6 +146,17 @@ func setupLegacyConfig(cfg *Config) error {
 		return err
 	}
 
+	// Verify the admin confirmation settings
+	if cfg.AdminConfirmWindow <= 0 {
+		return fmt.Errorf("adminconfirmwindow must be positive")
+	}
+
+	// Verify the API usage settings
+	if cfg.UsageRetention > usageRetentionMax {
+		return fmt.Errorf("usageretention cannot exceed %v days",
+			usageRetentionMax)
+	}
+
 	// Verify the SMTP mail settings
 	switch {
 	case cfg.MailHost == "" && cfg.MailUser == "" &&
@@ -266,6 +296,20 @@ func setupLegacyUserDBSettings(cfg *Config) error {
 
 // setupLegacyPiSettings sets up the legacy piwww settings.
 func setupLegacyPiSettings(cfg *Config) error {
+	// Setup web push settings
+	if cfg.WebPushSubject != "" {
+		if !strings.HasPrefix(cfg.WebPushSubject, "mailto:") &&
+			!strings.HasPrefix(cfg.WebPushSubject, "https:") {
+			return fmt.Errorf("invalid webpushsubject '%v': must be a "+
+				"mailto: or https: uri", cfg.WebPushSubject)
+		}
+		if cfg.WebPushKeyFile == "" {
+			cfg.WebPushKeyFile = filepath.Join(cfg.HomeDir,
+				defaultWebPushKeyFilename)
+		}
+		cfg.WebPushKeyFile = util.CleanAndExpandPath(cfg.WebPushKeyFile)
+	}
+
 	// Verify paywall settings
 	paywallIsEnabled := cfg.PaywallAmount != 0 || cfg.PaywallXpub != ""
 	if !paywallIsEnabled {
@@ -0,0 +1,358 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// bannersCacheTTL is the amount of time that the banners are cached
+	// in memory. The status route is polled by clients, so the banners
+	// are cached to prevent every poll from hitting the user database.
+	bannersCacheTTL = time.Minute
+)
+
+// handleStatus handles the incoming status command. It returns the banners
+// that are currently being displayed.
+func (p *Politeiawww) handleStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleStatus")
+
+	sr, err := p.processStatus()
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleStatus: processStatus %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, sr)
+}
+
+// handleBanners handles the incoming banners command. It returns all banners,
+// including the banners that are not currently being displayed.
+func (p *Politeiawww) handleBanners(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleBanners")
+
+	var b www.Banners
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&b); err != nil {
+		RespondWithError(w, r, 0, "handleBanners: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	br, err := p.processBanners(b)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleBanners: processBanners %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, br)
+}
+
+// handleBannerSet handles the incoming banner set command. It creates or
+// updates a banner.
+func (p *Politeiawww) handleBannerSet(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleBannerSet")
+
+	var bs www.BannerSet
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&bs); err != nil {
+		RespondWithError(w, r, 0, "handleBannerSet: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	adminUser, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleBannerSet: getSessionUser %v", err)
+		return
+	}
+
+	bsr, err := p.processBannerSet(bs, adminUser)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleBannerSet: processBannerSet %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, bsr)
+}
+
+// handleBannerDel handles the incoming banner del command.
+func (p *Politeiawww) handleBannerDel(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleBannerDel")
+
+	var bd www.BannerDel
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&bd); err != nil {
+		RespondWithError(w, r, 0, "handleBannerDel: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	adminUser, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleBannerDel: getSessionUser %v", err)
+		return
+	}
+
+	bdr, err := p.processBannerDel(bd, adminUser)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleBannerDel: processBannerDel %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, bdr)
+}
+
+// processStatus returns the banners that are currently being displayed,
+// sorted by severity from most to least severe.
+func (p *Politeiawww) processStatus() (*www.StatusReply, error) {
+	log.Tracef("processStatus")
+
+	all, err := p.bannersGetAll()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	banners := make([]www.Banner, 0, len(all))
+	for _, v := range all {
+		if !v.Active(now) {
+			continue
+		}
+		banners = append(banners, convertBannerToWWW(v))
+	}
+	sort.SliceStable(banners, func(i, j int) bool {
+		return banners[i].Severity > banners[j].Severity
+	})
+
+	return &www.StatusReply{
+		Banners:   banners,
+		Timestamp: now,
+	}, nil
+}
+
+// processBanners returns all banners sorted by their start time from oldest
+// to newest.
+func (p *Politeiawww) processBanners(b www.Banners) (*www.BannersReply, error) {
+	log.Tracef("processBanners")
+
+	all, err := p.bannersGetAll()
+	if err != nil {
+		return nil, err
+	}
+	banners := make([]www.Banner, 0, len(all))
+	for _, v := range all {
+		banners = append(banners, convertBannerToWWW(v))
+	}
+
+	return &www.BannersReply{
+		Banners: banners,
+	}, nil
+}
+
+// processBannerSet creates a new banner or updates an existing banner.
+func (p *Politeiawww) processBannerSet(bs www.BannerSet, adminUser *user.User) (*www.BannerSetReply, error) {
+	log.Tracef("processBannerSet: %v %v", bs.ID, adminUser.ID)
+
+	p.bannersMtx.Lock()
+	defer p.bannersMtx.Unlock()
+
+	// Get the existing banner if this is an update
+	now := time.Now().Unix()
+	b := user.Banner{
+		ID:        uuid.New(),
+		CreatedBy: adminUser.ID,
+		CreatedAt: now,
+	}
+	if bs.ID != "" {
+		eb, err := p.bannerGet(bs.ID)
+		if err != nil {
+			return nil, err
+		}
+		b = *eb
+	}
+
+	// Verify the banner
+	startsAt := bs.StartsAt
+	if startsAt == 0 {
+		startsAt = now
+	}
+	err := verifyBanner(bs, startsAt, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the banner
+	b.Message = strings.TrimSpace(bs.Message)
+	b.Severity = user.BannerSeverityT(bs.Severity)
+	b.StartsAt = startsAt
+	b.EndsAt = bs.EndsAt
+	b.UpdatedAt = now
+	err = p.banners.BannerSave(b)
+	if err != nil {
+		return nil, err
+	}
+	p.bannersCache = nil
+
+	log.Infof("Banner set %v by %v: %v", b.ID,
+		adminUser.Username, www.BannerSeverities[bs.Severity])
+
+	return &www.BannerSetReply{
+		Banner: convertBannerToWWW(b),
+	}, nil
+}
+
+// processBannerDel deletes a banner.
+func (p *Politeiawww) processBannerDel(bd www.BannerDel, adminUser *user.User) (*www.BannerDelReply, error) {
+	log.Tracef("processBannerDel: %v %v", bd.ID, adminUser.ID)
+
+	p.bannersMtx.Lock()
+	defer p.bannersMtx.Unlock()
+
+	id, err := uuid.Parse(bd.ID)
+	if err != nil {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusBannerNotFound,
+		}
+	}
+	err = p.banners.BannerDel(id)
+	if err != nil {
+		if errors.Is(err, user.ErrBannerNotFound) {
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusBannerNotFound,
+			}
+		}
+		return nil, err
+	}
+	p.bannersCache = nil
+
+	log.Infof("Banner deleted %v by %v", id, adminUser.Username)
+
+	return &www.BannerDelReply{}, nil
+}
+
+// bannersGetAll returns all banners sorted by their start time from oldest to
+// newest. The banners are served from the in-memory cache when it has not
+// expired.
+func (p *Politeiawww) bannersGetAll() ([]user.Banner, error) {
+	p.bannersMtx.Lock()
+	defer p.bannersMtx.Unlock()
+
+	if p.bannersCache != nil &&
+		time.Since(p.bannersCachedAt) < bannersCacheTTL {
+		return p.bannersCache, nil
+	}
+
+	banners, err := p.banners.BannersGetAll()
+	if err != nil {
+		return nil, err
+	}
+	p.bannersCache = banners
+	p.bannersCachedAt = time.Now()
+
+	return banners, nil
+}
+
+// bannerGet returns the banner for the provided ID. A banner not found error
+// is returned if the banner does not exist.
+//
+// This function must be called with the banners lock held.
+func (p *Politeiawww) bannerGet(id string) (*user.Banner, error) {
+	bannerID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusBannerNotFound,
+		}
+	}
+	banners, err := p.banners.BannersGetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range banners {
+		if v.ID == bannerID {
+			return &v, nil
+		}
+	}
+	return nil, www.UserError{
+		ErrorCode: www.ErrorStatusBannerNotFound,
+	}
+}
+
+// verifyBanner verifies the fields of a banner set request. The start time
+// is the start time of the banner once the default has been applied.
+func verifyBanner(bs www.BannerSet, startsAt, now int64) error {
+	invalid := func(format string, args ...interface{}) error {
+		return www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidBanner,
+			ErrorContext: []string{fmt.Sprintf(format, args...)},
+		}
+	}
+
+	msg := strings.TrimSpace(bs.Message)
+	switch {
+	case msg == "":
+		return invalid("message is empty")
+	case utf8.RuneCountInString(msg) > www.BannerMessageMaxLength:
+		return invalid("message exceeds max length of %v characters",
+			www.BannerMessageMaxLength)
+	}
+	switch bs.Severity {
+	case www.BannerSeverityInfo, www.BannerSeverityWarning,
+		www.BannerSeverityCritical:
+		// Valid severity; continue
+	default:
+		return invalid("invalid severity %v", bs.Severity)
+	}
+	if startsAt < 0 {
+		return invalid("invalid start time %v", startsAt)
+	}
+	if bs.EndsAt != 0 {
+		switch {
+		case bs.EndsAt <= startsAt:
+			return invalid("end time must be after the start time")
+		case bs.EndsAt <= now:
+			return invalid("end time is in the past")
+		}
+	}
+
+	return nil
+}
+
+func convertBannerToWWW(b user.Banner) www.Banner {
+	return www.Banner{
+		ID:        b.ID.String(),
+		Message:   b.Message,
+		Severity:  www.BannerSeverityT(b.Severity),
+		StartsAt:  b.StartsAt,
+		EndsAt:    b.EndsAt,
+		CreatedBy: b.CreatedBy.String(),
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/google/uuid"
+)
+
+func TestProcessBannerSet(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	admin, _ := newUser(t, p, true, true)
+	now := time.Now().Unix()
+
+	var tests = []struct {
+		name string
+		bs   www.BannerSet
+		want error
+	}{
+		{
+			"empty message",
+			www.BannerSet{
+				Message:  " ",
+				Severity: www.BannerSeverityInfo,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidBanner,
+			},
+		},
+		{
+			"message too long",
+			www.BannerSet{
+				Message:  strings.Repeat("a", www.BannerMessageMaxLength+1),
+				Severity: www.BannerSeverityInfo,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidBanner,
+			},
+		},
+		{
+			"invalid severity",
+			www.BannerSet{
+				Message:  "message",
+				Severity: www.BannerSeverityLast,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidBanner,
+			},
+		},
+		{
+			"ends before start",
+			www.BannerSet{
+				Message:  "message",
+				Severity: www.BannerSeverityInfo,
+				StartsAt: now + 7200,
+				EndsAt:   now + 3600,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidBanner,
+			},
+		},
+		{
+			"banner not found",
+			www.BannerSet{
+				ID:       uuid.New().String(),
+				Message:  "message",
+				Severity: www.BannerSeverityInfo,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusBannerNotFound,
+			},
+		},
+		{
+			"success",
+			www.BannerSet{
+				Message:  "message",
+				Severity: www.BannerSeverityInfo,
+			},
+			nil,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := p.processBannerSet(v.bs, admin)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestProcessStatus(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	admin, _ := newUser(t, p, true, true)
+	now := time.Now().Unix()
+
+	// Create an active info banner, an active critical banner and a
+	// scheduled banner.
+	banners := []www.BannerSet{
+		{
+			Message:  "info",
+			Severity: www.BannerSeverityInfo,
+		},
+		{
+			Message:  "critical",
+			Severity: www.BannerSeverityCritical,
+		},
+		{
+			Message:  "scheduled",
+			Severity: www.BannerSeverityWarning,
+			StartsAt: now + 3600,
+		},
+	}
+	ids := make([]string, 0, len(banners))
+	for _, v := range banners {
+		bsr, err := p.processBannerSet(v, admin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, bsr.Banner.ID)
+	}
+
+	// Verify that only the active banners are returned and that they
+	// are sorted by severity.
+	sr, err := p.processStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sr.Banners) != 2 ||
+		sr.Banners[0].ID != ids[1] || sr.Banners[1].ID != ids[0] {
+		t.Fatalf("got banners %+v, want %v, %v", sr.Banners, ids[1], ids[0])
+	}
+
+	// Verify that all banners are returned to admins
+	br, err := p.processBanners(www.Banners{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(br.Banners) != 3 {
+		t.Fatalf("got %v banners, want 3", len(br.Banners))
+	}
+
+	// Delete a banner and verify that the cache is invalidated
+	_, err = p.processBannerDel(www.BannerDel{ID: ids[1]}, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sr, err = p.processStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sr.Banners) != 1 || sr.Banners[0].ID != ids[0] {
+		t.Fatalf("got banners %+v, want %v", sr.Banners, ids[0])
+	}
+
+	// A deleted banner can not be deleted again
+	_, err = p.processBannerDel(www.BannerDel{ID: ids[1]}, admin)
+	got := errToStr(err)
+	want := errToStr(www.UserError{
+		ErrorCode: www.ErrorStatusBannerNotFound,
+	})
+	if got != want {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"net/http"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/changes"
+	"github.com/decred/politeia/util"
+)
+
+// handleChanges returns the changes to the public record inventory that were
+// made since the provided sequence number.
+func (p *Politeiawww) handleChanges(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleChanges")
+
+	var c www.Changes
+	err := util.ParseGetParams(r, &c)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleChanges: ParseGetParams",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	reply := p.processChanges(c)
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
+// processChanges returns the changes to the public record inventory that were
+// made since the provided sequence number.
+func (p *Politeiawww) processChanges(c www.Changes) *www.ChangesReply {
+	log.Tracef("processChanges: %v", c.Since)
+
+	cs, oldest, latest := p.changes.Since(c.Since, www.ChangesPageSize)
+
+	// The client must re-crawl the inventory if it has missed changes
+	// that are no longer kept or if it is ahead of the server, e.g. the
+	// change log was reset.
+	var resync bool
+	switch {
+	case c.Since > latest:
+		resync = true
+	case oldest > 0 && c.Since < oldest-1:
+		resync = true
+	}
+
+	return &www.ChangesReply{
+		Changes: convertChanges(cs),
+		Oldest:  oldest,
+		Latest:  latest,
+		Resync:  resync,
+	}
+}
+
+func convertChanges(cs []changes.Change) []www.Change {
+	c := make([]www.Change, 0, len(cs))
+	for _, v := range cs {
+		c = append(c, www.Change{
+			Sequence:  v.Sequence,
+			Type:      string(v.Type),
+			Token:     v.Token,
+			Timestamp: v.Timestamp,
+			Version:   v.Version,
+			Status:    v.Status,
+			CommentID: v.CommentID,
+			Votes:     v.Votes,
+		})
+	}
+	return c
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package changes maintains a log of the changes that are made to the public
+// record inventory. Each change is assigned a monotonically increasing
+// sequence number so that mirrors can sync incrementally by requesting the
+// changes that were made since the last sequence number that they have seen.
+// The log is persisted to disk as an append-only file so that sequence
+// numbers are never reused across restarts.
+package changes
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeT represents a change type.
+type ChangeT string
+
+const (
+	// ChangeTypeRecordNew indicates that a record was made public.
+	ChangeTypeRecordNew ChangeT = "recordnew"
+
+	// ChangeTypeRecordEdit indicates that a public record was edited.
+	ChangeTypeRecordEdit ChangeT = "recordedit"
+
+	// ChangeTypeRecordStatus indicates that the status of a public
+	// record changed, e.g. the record was censored or archived.
+	ChangeTypeRecordStatus ChangeT = "recordstatus"
+
+	// ChangeTypeCommentNew indicates that a comment was made on a public
+	// record.
+	ChangeTypeCommentNew ChangeT = "commentnew"
+
+	// ChangeTypeVotes indicates that votes were cast on a record.
+	ChangeTypeVotes ChangeT = "votes"
+)
+
+// Change is a single change to the public record inventory. The fields that
+// are populated depend on the change type. Version and Status are set for
+// record changes, CommentID is set for new comments and Votes is the number of
+// successfully cast votes for vote changes.
+type Change struct {
+	Sequence  uint64  `json:"sequence"`
+	Type      ChangeT `json:"type"`
+	Token     string  `json:"token"`
+	Timestamp int64   `json:"timestamp"`
+	Version   uint32  `json:"version,omitempty"`
+	Status    uint32  `json:"status,omitempty"`
+	CommentID uint32  `json:"commentid,omitempty"`
+	Votes     uint32  `json:"votes,omitempty"`
+}
+
+// Log is a log of the changes that are made to the public record inventory.
+// The most recent changes are kept in memory, up to the configured maximum.
+type Log struct {
+	sync.Mutex
+	fp      string
+	max     int
+	changes []Change // Ordered by sequence, oldest first
+	latest  uint64   // Sequence of the most recent change
+	lines   int      // Number of changes in the file
+	now     func() time.Time
+}
+
+// New returns a new Log that persists the changes to the provided file path
+// and keeps at most the provided number of changes.
+func New(fp string, max uint32) (*Log, error) {
+	return newLog(fp, max, time.Now)
+}
+
+// newLog returns a new Log that uses the provided function to get the current
+// time.
+func newLog(fp string, max uint32, now func() time.Time) (*Log, error) {
+	if max == 0 {
+		return nil, fmt.Errorf("max must be greater than zero")
+	}
+	l := &Log{
+		fp:      fp,
+		max:     int(max),
+		changes: make([]Change, 0, max),
+		now:     now,
+	}
+	err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Add assigns the next sequence number and timestamp to the provided change
+// and adds it to the log. The change is written to disk before it is made
+// available to callers of Since.
+func (l *Log) Add(c Change) error {
+	l.Lock()
+	defer l.Unlock()
+
+	c.Sequence = l.latest + 1
+	c.Timestamp = l.now().Unix()
+
+	// Compact the file once it contains twice the number of changes
+	// that are kept in memory. Otherwise, append the change.
+	var err error
+	if l.lines >= 2*l.max {
+		err = l.compact(c)
+	} else {
+		err = l.append(c)
+	}
+	if err != nil {
+		return err
+	}
+
+	l.latest = c.Sequence
+	l.changes = append(l.changes, c)
+	if len(l.changes) > l.max {
+		l.changes = l.changes[len(l.changes)-l.max:]
+	}
+
+	return nil
+}
+
+// Since returns the changes with a sequence number greater than the provided
+// sequence number, up to the provided limit. It also returns the sequence
+// number of the oldest change that is still in the log and of the most recent
+// change. The oldest sequence is zero when the log is empty.
+func (l *Log) Since(seq uint64, limit uint32) ([]Change, uint64, uint64) {
+	l.Lock()
+	defer l.Unlock()
+
+	var oldest uint64
+	if len(l.changes) > 0 {
+		oldest = l.changes[0].Sequence
+	}
+
+	// The changes are ordered by sequence
+	i := sort.Search(len(l.changes), func(i int) bool {
+		return l.changes[i].Sequence > seq
+	})
+	end := len(l.changes)
+	if limit > 0 && end-i > int(limit) {
+		end = i + int(limit)
+	}
+	c := make([]Change, end-i)
+	copy(c, l.changes[i:end])
+
+	return c, oldest, l.latest
+}
+
+// Max returns the maximum number of changes that are kept.
+func (l *Log) Max() uint32 {
+	return uint32(l.max)
+}
+
+// load loads the changes from disk.
+func (l *Log) load() error {
+	f, err := os.Open(l.fp)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+	defer f.Close()
+
+	var invalid bool
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for s.Scan() {
+		var c Change
+		err := json.Unmarshal(s.Bytes(), &c)
+		if err != nil {
+			// The last line may be partially written if the
+			// server was shut down during a write.
+			log.Warnf("Skipping invalid change: %v", err)
+			invalid = true
+			continue
+		}
+		l.lines++
+		l.latest = c.Sequence
+		l.changes = append(l.changes, c)
+		if len(l.changes) > l.max {
+			l.changes = l.changes[1:]
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if invalid {
+		// Force a compaction on the next write so that the
+		// next change is not appended to a partial line.
+		l.lines = 2 * l.max
+	}
+
+	log.Infof("Changes loaded: %v, latest sequence %v",
+		len(l.changes), l.latest)
+
+	return nil
+}
+
+// append appends the provided change to the file.
+//
+// This function must be called with the lock held.
+func (l *Log) append(c Change) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.fp, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	l.lines++
+	return nil
+}
+
+// compact rewrites the file so that it only contains the changes that are
+// kept in memory plus the provided change. The changes are written to a temp
+// file that is then renamed so that a partially written file is never read.
+//
+// This function must be called with the lock held.
+func (l *Log) compact(c Change) error {
+	keep := l.changes
+	if len(keep) >= l.max {
+		keep = keep[len(keep)-l.max+1:]
+	}
+	tmp := l.fp + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, v := range append(keep[:len(keep):len(keep)], c) {
+		err = enc.Encode(v)
+		if err != nil {
+			f.Close()
+			return err
+		}
+	}
+	err = w.Flush()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp, l.fp)
+	if err != nil {
+		return err
+	}
+	l.lines = len(keep) + 1
+	return nil
+}
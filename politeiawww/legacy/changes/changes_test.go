@@ -0,0 +1,178 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package changes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	"github.com/decred/politeia/politeiawww/legacy/records"
+)
+
+func TestLog(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "changes.ndjson")
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	nowFn := func() time.Time { return now }
+	l, err := newLog(fp, 3, nowFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify an empty log
+	cs, oldest, latest := l.Since(0, 0)
+	if len(cs) != 0 || oldest != 0 || latest != 0 {
+		t.Fatalf("Since: got %v changes, oldest %v, latest %v; "+
+			"want an empty log", len(cs), oldest, latest)
+	}
+
+	// Add enough changes to trigger a compaction
+	for i := 0; i < 8; i++ {
+		err = l.Add(Change{
+			Type:  ChangeTypeVotes,
+			Token: "45154fb45664714b",
+			Votes: uint32(i + 1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the most recent changes are kept
+	verifySince := func(l *Log, seq uint64, limit uint32, want []uint64) {
+		t.Helper()
+		cs, oldest, latest := l.Since(seq, limit)
+		if oldest != 6 || latest != 8 {
+			t.Fatalf("Since(%v): got oldest %v, latest %v; want 6, 8",
+				seq, oldest, latest)
+		}
+		if len(cs) != len(want) {
+			t.Fatalf("Since(%v, %v): got %v changes, want %v",
+				seq, limit, len(cs), len(want))
+		}
+		for i, v := range cs {
+			if v.Sequence != want[i] {
+				t.Fatalf("Since(%v, %v): got sequence %v, want %v",
+					seq, limit, v.Sequence, want[i])
+			}
+			if v.Votes != uint32(v.Sequence) {
+				t.Fatalf("got votes %v, want %v", v.Votes, v.Sequence)
+			}
+			if v.Timestamp != now.Unix() {
+				t.Fatalf("got timestamp %v, want %v", v.Timestamp, now.Unix())
+			}
+		}
+	}
+	verifySince(l, 0, 0, []uint64{6, 7, 8})
+	verifySince(l, 6, 0, []uint64{7, 8})
+	verifySince(l, 5, 1, []uint64{6})
+	verifySince(l, 8, 0, []uint64{})
+	verifySince(l, 9, 0, []uint64{})
+
+	// Reload the log and verify that the sequence continues
+	l, err = newLog(fp, 3, nowFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifySince(l, 0, 0, []uint64{6, 7, 8})
+
+	// Simulate a partially written change and verify that the log
+	// recovers from it.
+	f, err := os.OpenFile(fp, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString(`{"sequence":9,"ty`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	l, err = newLog(fp, 3, nowFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = l.Add(Change{Type: ChangeTypeVotes, Votes: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err = newLog(fp, 3, nowFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, oldest, latest = l.Since(0, 0)
+	if len(cs) != 3 || oldest != 7 || latest != 9 {
+		t.Fatalf("Since: got %v changes, oldest %v, latest %v; "+
+			"want 3, 7, 9", len(cs), oldest, latest)
+	}
+}
+
+func TestChangeFromEvent(t *testing.T) {
+	record := func(state rcv1.RecordStateT, status rcv1.RecordStatusT) rcv1.Record {
+		return rcv1.Record{
+			State:   state,
+			Status:  status,
+			Version: 2,
+			CensorshipRecord: rcv1.CensorshipRecord{
+				Token: "45154fb45664714b",
+			},
+		}
+	}
+	var tests = []struct {
+		name  string
+		event interface{}
+		want  ChangeT
+		ok    bool
+	}{
+		{
+			"unvetted status change",
+			records.EventSetStatus{
+				Record: record(rcv1.RecordStateUnvetted,
+					rcv1.RecordStatusCensored),
+			},
+			"",
+			false,
+		},
+		{
+			"record made public",
+			records.EventSetStatus{
+				Record: record(rcv1.RecordStateVetted,
+					rcv1.RecordStatusPublic),
+			},
+			ChangeTypeRecordNew,
+			true,
+		},
+		{
+			"vetted status change",
+			records.EventSetStatus{
+				Record: record(rcv1.RecordStateVetted,
+					rcv1.RecordStatusArchived),
+			},
+			ChangeTypeRecordStatus,
+			true,
+		},
+		{
+			"vetted record edit",
+			records.EventEdit{
+				Record: record(rcv1.RecordStateVetted,
+					rcv1.RecordStatusPublic),
+			},
+			ChangeTypeRecordEdit,
+			true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := changeFromEvent(tc.event)
+			if ok != tc.ok {
+				t.Fatalf("got ok %v, want %v", ok, tc.ok)
+			}
+			if c.Type != tc.want {
+				t.Fatalf("got type %v, want %v", c.Type, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package changes
+
+import (
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	"github.com/decred/politeia/politeiawww/legacy/comments"
+	"github.com/decred/politeia/politeiawww/legacy/events"
+	"github.com/decred/politeia/politeiawww/legacy/records"
+	"github.com/decred/politeia/politeiawww/legacy/ticketvote"
+)
+
+// Listen registers the change log as a listener for the events that change
+// the public record inventory. Events for unvetted records are ignored since
+// the unvetted inventory is not public.
+func (l *Log) Listen(e *events.Manager) {
+	log.Debugf("Setting up changes event listeners")
+
+	ch := make(chan interface{})
+	e.Register(records.EventTypeEdit, ch)
+	e.Register(records.EventTypeSetStatus, ch)
+	e.Register(comments.EventTypeNew, ch)
+	e.Register(ticketvote.EventTypeCastBallot, ch)
+	go l.handleEvents(ch)
+}
+
+// handleEvents adds a change to the log for each event that is received on
+// the provided channel.
+func (l *Log) handleEvents(ch chan interface{}) {
+	for msg := range ch {
+		c, ok := changeFromEvent(msg)
+		if !ok {
+			continue
+		}
+		err := l.Add(c)
+		if err != nil {
+			log.Errorf("Add change %v %v: %v", c.Type, c.Token, err)
+		}
+	}
+}
+
+// changeFromEvent returns the change for the provided event. False is
+// returned if the event does not change the public record inventory.
+func changeFromEvent(msg interface{}) (Change, bool) {
+	switch e := msg.(type) {
+	case records.EventEdit:
+		if e.Record.State != rcv1.RecordStateVetted {
+			return Change{}, false
+		}
+		return recordChange(ChangeTypeRecordEdit, e.Record), true
+
+	case records.EventSetStatus:
+		if e.Record.State != rcv1.RecordStateVetted {
+			return Change{}, false
+		}
+		// A record is added to the public inventory when it is made
+		// public.
+		t := ChangeTypeRecordStatus
+		if e.Record.Status == rcv1.RecordStatusPublic {
+			t = ChangeTypeRecordNew
+		}
+		return recordChange(t, e.Record), true
+
+	case comments.EventNew:
+		if e.State != cmv1.RecordStateVetted {
+			return Change{}, false
+		}
+		return Change{
+			Type:      ChangeTypeCommentNew,
+			Token:     e.Comment.Token,
+			CommentID: e.Comment.CommentID,
+		}, true
+
+	case ticketvote.EventCastBallot:
+		return Change{
+			Type:  ChangeTypeVotes,
+			Token: e.Token,
+			Votes: e.Votes,
+		}, true
+
+	default:
+		log.Errorf("changes invalid event: %v", msg)
+		return Change{}, false
+	}
+}
+
+// recordChange returns a change of the provided type for the provided record.
+func recordChange(t ChangeT, r rcv1.Record) Change {
+	return Change{
+		Type:    t,
+		Token:   r.CensorshipRecord.Token,
+		Version: r.Version,
+		Status:  uint32(r.Status),
+	}
+}
@@ -14,10 +14,11 @@ import (
 	"github.com/decred/politeia/politeiad/plugins/comments"
 	v1 "github.com/decred/politeia/politeiawww/api/comments/v1"
 	"github.com/decred/politeia/politeiawww/config"
+	"github.com/decred/politeia/politeiawww/legacy/enums"
 	"github.com/decred/politeia/politeiawww/legacy/events"
 	"github.com/decred/politeia/politeiawww/legacy/sessions"
 	"github.com/decred/politeia/politeiawww/legacy/user"
-	"github.com/decred/politeia/util"
+	"github.com/decred/politeia/politeiawww/legacy/writequeue"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +29,7 @@ type Comments struct {
 	userdb    user.Database
 	sessions  *sessions.Sessions
 	events    *events.Manager
+	queue     *writequeue.Queue // Nil if the write queue is disabled
 	policy    *v1.PolicyReply
 }
 
@@ -35,7 +37,7 @@ type Comments struct {
 func (c *Comments) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, c.policy)
+	enums.RespondWithJSON(w, r, http.StatusOK, c.policy)
 }
 
 // HandleNew is the request handler for the comments v1 New route.
@@ -66,7 +68,32 @@ func (c *Comments) HandleNew(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, nr)
+	enums.RespondWithJSON(w, r, http.StatusOK, nr)
+}
+
+// HandleSubmissionStatus is the request handler for the comments v1
+// SubmissionStatus route.
+func (c *Comments) HandleSubmissionStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSubmissionStatus")
+
+	var ss v1.SubmissionStatus
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ss); err != nil {
+		respondWithError(w, r, "HandleSubmissionStatus: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	ssr, err := c.processSubmissionStatus(ss)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSubmissionStatus: processSubmissionStatus: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, ssr)
 }
 
 // HandleEdit is the request handler for the comments v1 Edit route.
@@ -97,7 +124,7 @@ func (c *Comments) HandleEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, vr)
+	enums.RespondWithJSON(w, r, http.StatusOK, vr)
 }
 
 // HandleVote is the request handler for the comments v1 Vote route.
@@ -128,7 +155,102 @@ func (c *Comments) HandleVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, vr)
+	enums.RespondWithJSON(w, r, http.StatusOK, vr)
+}
+
+// HandleSubscribe is the request handler for the comments v1 Subscribe
+// route.
+func (c *Comments) HandleSubscribe(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSubscribe")
+
+	var s v1.Subscribe
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&s); err != nil {
+		respondWithError(w, r, "HandleSubscribe: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSubscribe: GetSessionUser: %v", err)
+		return
+	}
+
+	sr, err := c.processSubscribe(r.Context(), s, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSubscribe: processSubscribe: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, sr)
+}
+
+// HandlePin is the request handler for the comments v1 Pin route.
+func (c *Comments) HandlePin(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandlePin")
+
+	var p v1.Pin
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&p); err != nil {
+		respondWithError(w, r, "HandlePin: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandlePin: GetSessionUser: %v", err)
+		return
+	}
+
+	pr, err := c.processPin(r.Context(), p, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandlePin: processPin: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, pr)
+}
+
+// HandleStakeProof is the request handler for the comments v1 StakeProof
+// route.
+func (c *Comments) HandleStakeProof(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleStakeProof")
+
+	var sp v1.StakeProof
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&sp); err != nil {
+		respondWithError(w, r, "HandleStakeProof: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleStakeProof: GetSessionUser: %v", err)
+		return
+	}
+
+	spr, err := c.processStakeProof(r.Context(), sp, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleStakeProof: processStakeProof: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, spr)
 }
 
 // HandleDel is the request handler for the comments v1 Del route.
@@ -159,7 +281,38 @@ func (c *Comments) HandleDel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, dr)
+	enums.RespondWithJSON(w, r, http.StatusOK, dr)
+}
+
+// HandleDelUser is the request handler for the comments v1 DelUser route.
+func (c *Comments) HandleDelUser(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleDelUser")
+
+	var d v1.DelUser
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&d); err != nil {
+		respondWithError(w, r, "HandleDelUser: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleDelUser: GetSessionUser: %v", err)
+		return
+	}
+
+	dr, err := c.processDelUser(r.Context(), d, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleDelUser: processDelUser: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, dr)
 }
 
 // HandleCount is the request handler for the comments v1 Count route.
@@ -183,7 +336,7 @@ func (c *Comments) HandleCount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, cr)
+	enums.RespondWithJSON(w, r, http.StatusOK, cr)
 }
 
 // HandleComments is the request handler for the comments v1 Comments route.
@@ -216,7 +369,156 @@ func (c *Comments) HandleComments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, cr)
+	enums.RespondWithJSON(w, r, http.StatusOK, cr)
+}
+
+// HandleTree is the request handler for the comments v1 Tree route.
+func (c *Comments) HandleTree(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleTree")
+
+	var t v1.Tree
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&t); err != nil {
+		respondWithError(w, r, "HandleTree: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleTree: GetSessionUser: %v", err)
+		return
+	}
+
+	tr, err := c.processTree(r.Context(), t, u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleTree: processTree: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, tr)
+}
+
+// HandleAttachments is the request handler for the comments v1 Attachments
+// route.
+func (c *Comments) HandleAttachments(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleAttachments")
+
+	var a v1.Attachments
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&a); err != nil {
+		respondWithError(w, r, "HandleAttachments: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleAttachments: GetSessionUser: %v", err)
+		return
+	}
+
+	ar, err := c.processAttachments(r.Context(), a, u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleAttachments: processAttachments: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, ar)
+}
+
+// HandleUserComments is the request handler for the comments v1 UserComments
+// route.
+func (c *Comments) HandleUserComments(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleUserComments")
+
+	var uc v1.UserComments
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&uc); err != nil {
+		respondWithError(w, r, "HandleUserComments: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleUserComments: GetSessionUser: %v", err)
+		return
+	}
+
+	ucr, err := c.processUserComments(r.Context(), uc, u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleUserComments: processUserComments: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, ucr)
+}
+
+// HandleSearch is the request handler for the comments v1 Search route.
+func (c *Comments) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSearch")
+
+	var s v1.Search
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&s); err != nil {
+		respondWithError(w, r, "HandleSearch: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	sr, err := c.processSearch(r.Context(), s)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSearch: processSearch: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, sr)
+}
+
+// HandleHistory is the request handler for the comments v1 History route.
+func (c *Comments) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleHistory")
+
+	var h v1.History
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&h); err != nil {
+		respondWithError(w, r, "HandleHistory: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	hr, err := c.processHistory(r.Context(), h)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleHistory: processHistory: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, hr)
 }
 
 // HandleVotes is the request handler for the comments v1 Votes route.
@@ -240,7 +542,7 @@ func (c *Comments) HandleVotes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, vr)
+	enums.RespondWithJSON(w, r, http.StatusOK, vr)
 }
 
 // HandleTimestamps is the request handler for the comments v1 Timestamps
@@ -275,11 +577,46 @@ func (c *Comments) HandleTimestamps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, tr)
+	enums.RespondWithJSON(w, r, http.StatusOK, tr)
+}
+
+// HandleTimestampsBatch is the request handler for the comments v1
+// TimestampsBatch route.
+func (c *Comments) HandleTimestampsBatch(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleTimestampsBatch")
+
+	var t v1.TimestampsBatch
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&t); err != nil {
+		respondWithError(w, r, "HandleTimestampsBatch: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleTimestampsBatch: GetSessionUser: %v", err)
+		return
+	}
+
+	isAdmin := u != nil && u.Admin
+	tr, err := c.processTimestampsBatch(r.Context(), t, isAdmin)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleTimestampsBatch: processTimestampsBatch: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, tr)
 }
 
 // New returns a new Comments context.
-func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*Comments, error) {
+func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *sessions.Sessions, e *events.Manager, q *writequeue.Queue, plugins []pdv2.Plugin) (*Comments, error) {
 	// Parse plugin settings
 	var (
 		lengthMax          uint32
@@ -290,6 +627,22 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *session
 		timestampsPageSize uint32
 		allowEdits         bool
 		editPeriod         uint32
+		editsMax           uint32
+		stakeWeighting     bool
+		commentsPageSize   uint32
+		reactions          = []v1.Reaction{}
+		allowAuthorDeletes bool
+		voteChangeCooldown uint32
+		treeDepthMax       uint32
+		treeChildrenMax    uint32
+		searchPageSize     uint32
+		historyPageSize    uint32
+		disableDownvotes   bool
+		markdownValidation bool
+		markdownDataURIMax uint32
+		attachmentCountMax uint32
+		attachmentSizeMax  uint32
+		attachmentMIMEs    = []string{}
 	)
 	for _, p := range plugins {
 		if p.ID != comments.PluginID {
@@ -356,6 +709,134 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *session
 				}
 				editPeriod = uint32(u)
 
+			case comments.SettingKeyEditsMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				editsMax = uint32(u)
+
+			case comments.SettingKeyStakeWeighting:
+				b, err := strconv.ParseBool(v.Value)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				stakeWeighting = b
+
+			case comments.SettingKeyCommentsPageSize:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				commentsPageSize = uint32(u)
+
+			case comments.SettingKeyReactions:
+				err := json.Unmarshal([]byte(v.Value), &reactions)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+
+			case comments.SettingKeyAllowAuthorDeletes:
+				b, err := strconv.ParseBool(v.Value)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				allowAuthorDeletes = b
+
+			case comments.SettingKeyVoteChangeCooldown:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				voteChangeCooldown = uint32(u)
+
+			case comments.SettingKeyTreeDepthMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				treeDepthMax = uint32(u)
+
+			case comments.SettingKeyTreeChildrenMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				treeChildrenMax = uint32(u)
+
+			case comments.SettingKeySearchPageSize:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				searchPageSize = uint32(u)
+
+			case comments.SettingKeyHistoryPageSize:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				historyPageSize = uint32(u)
+
+			case comments.SettingKeyDisableDownvotes:
+				b, err := strconv.ParseBool(v.Value)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				disableDownvotes = b
+
+			case comments.SettingKeyFsckRepairScores:
+				// Only used by the politeiad fsck
+
+			case comments.SettingKeyMarkdownValidation:
+				b, err := strconv.ParseBool(v.Value)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				markdownValidation = b
+
+			case comments.SettingKeyMarkdownDataURIMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				markdownDataURIMax = uint32(u)
+
+			case comments.SettingKeyAttachmentCountMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				attachmentCountMax = uint32(u)
+
+			case comments.SettingKeyAttachmentSizeMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+				attachmentSizeMax = uint32(u)
+
+			case comments.SettingKeyAttachmentMIMETypes:
+				err := json.Unmarshal([]byte(v.Value), &attachmentMIMEs)
+				if err != nil {
+					return nil, errors.Errorf("invalid plugin setting %v '%v': %v",
+						v.Key, v.Value, err)
+				}
+
 			default:
 				// Skip unknown settings
 				log.Warnf("Unknown plugin setting %v; Skipping...", v.Key)
@@ -383,14 +864,18 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *session
 	case editPeriod == 0:
 		return nil, errors.Errorf("plugin setting not found: %v",
 			comments.SettingKeyEditPeriod)
+	case commentsPageSize == 0:
+		return nil, errors.Errorf("plugin setting not found: %v",
+			comments.SettingKeyCommentsPageSize)
 	}
 
-	return &Comments{
+	c := &Comments{
 		cfg:       cfg,
 		politeiad: pdc,
 		userdb:    udb,
 		sessions:  s,
 		events:    e,
+		queue:     q,
 		policy: &v1.PolicyReply{
 			LengthMax:          lengthMax,
 			VoteChangesMax:     voteChangesMax,
@@ -400,6 +885,30 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *session
 			TimestampsPageSize: timestampsPageSize,
 			AllowEdits:         allowEdits,
 			EditPeriod:         editPeriod,
+			EditsMax:           editsMax,
+			StakeWeighting:     stakeWeighting,
+			CommentsPageSize:   commentsPageSize,
+			Reactions:          reactions,
+			AllowAuthorDeletes: allowAuthorDeletes,
+			VoteChangeCooldown: voteChangeCooldown,
+			TreeDepthMax:       treeDepthMax,
+			TreeChildrenMax:    treeChildrenMax,
+			SearchPageSize:     searchPageSize,
+			HistoryPageSize:    historyPageSize,
+			DisableDownvotes:   disableDownvotes,
+			MarkdownValidation: markdownValidation,
+			MarkdownDataURIMax: markdownDataURIMax,
+
+			AttachmentCountMax:  attachmentCountMax,
+			AttachmentSizeMax:   attachmentSizeMax,
+			AttachmentMIMETypes: attachmentMIMEs,
 		},
-	}, nil
+	}
+
+	// Register the queued submission types
+	if q != nil {
+		q.Register(submissionTypeNew, c.execQueuedNew)
+	}
+
+	return c, nil
 }
@@ -6,14 +6,19 @@ package comments
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	pdclient "github.com/decred/politeia/politeiad/client"
 	"github.com/decred/politeia/politeiad/plugins/comments"
 	v1 "github.com/decred/politeia/politeiawww/api/comments/v1"
 	"github.com/decred/politeia/politeiawww/config"
 	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/politeiawww/legacy/writequeue"
 	"github.com/google/uuid"
 )
 
@@ -29,7 +34,7 @@ func (c *Comments) processNew(ctx context.Context, n v1.New, u user.User) (*v1.N
 	}
 
 	// Verify user signed using active identity
-	if u.PublicKey() != n.PublicKey {
+	if !u.IsActivePublicKey(n.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
@@ -71,9 +76,37 @@ func (c *Comments) processNew(ctx context.Context, n v1.New, u user.User) (*v1.N
 		Comment:       n.Comment,
 		PublicKey:     n.PublicKey,
 		Signature:     n.Signature,
+		ContentType:   n.ContentType,
+		Attachments:   convertAttachmentsToPlugin(n.Attachments),
 		ExtraData:     n.ExtraData,
 		ExtraDataHint: n.ExtraDataHint,
 	}
+	nr, err := c.commentNew(ctx, n.State, cn, u)
+	if err != nil {
+		if c.queue == nil || !writequeue.IsRetryable(err) {
+			return nil, err
+		}
+
+		// politeiad is unavailable. Queue the comment so that it is
+		// submitted once politeiad becomes available again.
+		s, err := c.queue.Add(submissionTypeNew,
+			queuedNew{
+				State: n.State,
+				New:   cn,
+			})
+		if err != nil {
+			return nil, err
+		}
+		return &v1.NewReply{
+			SubmissionID: s.ID,
+		}, nil
+	}
+
+	return nr, nil
+}
+
+// commentNew sends a new comment to politeiad and emits a new comment event.
+func (c *Comments) commentNew(ctx context.Context, s v1.RecordStateT, cn comments.New, u user.User) (*v1.NewReply, error) {
 	pdc, err := c.politeiad.CommentNew(ctx, cn)
 	if err != nil {
 		return nil, err
@@ -86,7 +119,7 @@ func (c *Comments) processNew(ctx context.Context, n v1.New, u user.User) (*v1.N
 	// Emit event
 	c.events.Emit(EventTypeNew,
 		EventNew{
-			State:   n.State,
+			State:   s,
 			Comment: cm,
 		})
 
@@ -95,6 +128,80 @@ func (c *Comments) processNew(ctx context.Context, n v1.New, u user.User) (*v1.N
 	}, nil
 }
 
+// submissionTypeNew is the write queue submission type for new comments.
+const submissionTypeNew = "comments-new"
+
+// queuedNew is the write queue payload for a new comment.
+type queuedNew struct {
+	State v1.RecordStateT `json:"state"`
+	New   comments.New    `json:"new"`
+}
+
+// execQueuedNew sends a queued new comment to politeiad. It satisfies the
+// writequeue ExecFunc type.
+func (c *Comments) execQueuedNew(ctx context.Context, payload []byte) ([]byte, error) {
+	var qn queuedNew
+	err := json.Unmarshal(payload, &qn)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := uuid.Parse(qn.New.UserID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := c.userdb.UserGetById(uid)
+	if err != nil {
+		return nil, err
+	}
+	nr, err := c.commentNew(ctx, qn.State, qn.New, *u)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(nr)
+}
+
+func (c *Comments) processSubmissionStatus(ss v1.SubmissionStatus) (*v1.SubmissionStatusReply, error) {
+	log.Tracef("processSubmissionStatus: %v", ss.SubmissionID)
+
+	if c.queue == nil {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodeSubmissionNotFound,
+		}
+	}
+	s, err := c.queue.Get(submissionTypeNew, ss.SubmissionID)
+	if err != nil {
+		if errors.Is(err, writequeue.ErrNotFound) {
+			return nil, v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeSubmissionNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	// Prepare reply
+	ssr := v1.SubmissionStatusReply{
+		Timestamp: s.Timestamp,
+		Attempts:  s.Attempts,
+		Error:     s.Error,
+	}
+	switch s.Status {
+	case writequeue.StatusPending:
+		ssr.Status = v1.SubmissionStatusPending
+	case writequeue.StatusComplete:
+		var nr v1.NewReply
+		err = json.Unmarshal(s.Reply, &nr)
+		if err != nil {
+			return nil, err
+		}
+		ssr.Status = v1.SubmissionStatusComplete
+		ssr.Reply = &nr
+	case writequeue.StatusFailed:
+		ssr.Status = v1.SubmissionStatusFailed
+	}
+
+	return &ssr, nil
+}
+
 func (c *Comments) processEdit(ctx context.Context, e v1.Edit, u user.User) (*v1.EditReply, error) {
 	log.Tracef("processEdit: %v %v", e.Token, e.CommentID)
 
@@ -107,7 +214,7 @@ func (c *Comments) processEdit(ctx context.Context, e v1.Edit, u user.User) (*v1
 	}
 
 	// Verify user signed using active identity
-	if u.PublicKey() != e.PublicKey {
+	if !u.IsActivePublicKey(e.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
@@ -143,6 +250,8 @@ func (c *Comments) processEdit(ctx context.Context, e v1.Edit, u user.User) (*v1
 		Comment:       e.Comment,
 		PublicKey:     e.PublicKey,
 		Signature:     e.Signature,
+		ContentType:   e.ContentType,
+		Attachments:   convertAttachmentsToPlugin(e.Attachments),
 		ExtraData:     e.ExtraData,
 		ExtraDataHint: e.ExtraDataHint,
 	}
@@ -172,7 +281,7 @@ func (c *Comments) processVote(ctx context.Context, v v1.Vote, u user.User) (*v1
 	}
 
 	// Verify user signed using active identity
-	if u.PublicKey() != v.PublicKey {
+	if !u.IsActivePublicKey(v.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
@@ -206,6 +315,7 @@ func (c *Comments) processVote(ctx context.Context, v v1.Vote, u user.User) (*v1
 		Vote:      comments.VoteT(v.Vote),
 		PublicKey: v.PublicKey,
 		Signature: v.Signature,
+		Reaction:  v.Reaction,
 	}
 	vr, err := c.politeiad.CommentVote(ctx, cv)
 	if err != nil {
@@ -215,11 +325,155 @@ func (c *Comments) processVote(ctx context.Context, v v1.Vote, u user.User) (*v1
 	return &v1.VoteReply{
 		Downvotes: vr.Downvotes,
 		Upvotes:   vr.Upvotes,
+		Reactions: vr.Reactions,
 		Timestamp: vr.Timestamp,
 		Receipt:   vr.Receipt,
 	}, nil
 }
 
+func (c *Comments) processSubscribe(ctx context.Context, s v1.Subscribe, u user.User) (*v1.SubscribeReply, error) {
+	log.Tracef("processSubscribe: %v %v %v", s.Token, s.CommentID, s.Action)
+
+	// Verify state
+	state := convertStateToPlugin(s.State)
+	if state == comments.RecordStateInvalid {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodeRecordStateInvalid,
+		}
+	}
+
+	// Verify user signed using active identity
+	if !u.IsActivePublicKey(s.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Send plugin command
+	cs := comments.Subscribe{
+		UserID:    u.ID.String(),
+		State:     state,
+		Token:     s.Token,
+		CommentID: s.CommentID,
+		Action:    comments.SubscribeActionT(s.Action),
+		PublicKey: s.PublicKey,
+		Signature: s.Signature,
+	}
+	sr, err := c.politeiad.CommentSubscribe(ctx, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SubscribeReply{
+		Timestamp: sr.Timestamp,
+		Receipt:   sr.Receipt,
+	}, nil
+}
+
+func (c *Comments) processPin(ctx context.Context, p v1.Pin, u user.User) (*v1.PinReply, error) {
+	log.Tracef("processPin: %v %v", p.Token, p.CommentID)
+
+	// Verify state
+	state := convertStateToPlugin(p.State)
+	if state == comments.RecordStateInvalid {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodeRecordStateInvalid,
+		}
+	}
+
+	// Verify user signed using active identity
+	if !u.IsActivePublicKey(p.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Only admins and the record author are allowed to pin a
+	// comment.
+	if !u.Admin {
+		authorID, err := c.politeiad.Author(ctx, p.Token)
+		if err != nil {
+			return nil, err
+		}
+		if u.ID.String() != authorID {
+			return nil, v1.UserErrorReply{
+				ErrorCode:    v1.ErrorCodeUnauthorized,
+				ErrorContext: "user is not author or admin",
+			}
+		}
+	}
+
+	// Send plugin command
+	cp := comments.Pin{
+		UserID:    u.ID.String(),
+		State:     state,
+		Token:     p.Token,
+		CommentID: p.CommentID,
+		PublicKey: p.PublicKey,
+		Signature: p.Signature,
+	}
+	pr, err := c.politeiad.CommentPin(ctx, cp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.PinReply{
+		Timestamp: pr.Timestamp,
+		Receipt:   pr.Receipt,
+	}, nil
+}
+
+func (c *Comments) processStakeProof(ctx context.Context, sp v1.StakeProof, u user.User) (*v1.StakeProofReply, error) {
+	log.Tracef("processStakeProof: %v %v %v",
+		sp.Token, u.Username, len(sp.Tickets))
+
+	// Verify user signed using active identity
+	if !u.IsActivePublicKey(sp.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Execute pre plugin hooks. Stake proofs are subject to the same
+	// restrictions as comment votes.
+	switch c.cfg.Mode {
+	case config.PiWWWMode:
+		err := c.piHookVotePre(u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Send plugin command
+	tickets := make([]comments.TicketSignature, 0, len(sp.Tickets))
+	for _, v := range sp.Tickets {
+		tickets = append(tickets, comments.TicketSignature{
+			Ticket:    v.Ticket,
+			Signature: v.Signature,
+		})
+	}
+	psp := comments.StakeProof{
+		UserID:    u.ID.String(),
+		Token:     sp.Token,
+		Tickets:   tickets,
+		PublicKey: sp.PublicKey,
+		Signature: sp.Signature,
+	}
+	spr, err := c.politeiad.CommentStakeProof(ctx, psp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.StakeProofReply{
+		Weight:    spr.Weight,
+		Timestamp: spr.Timestamp,
+		Receipt:   spr.Receipt,
+	}, nil
+}
+
 func (c *Comments) processDel(ctx context.Context, d v1.Del, u user.User) (*v1.DelReply, error) {
 	log.Tracef("processDel: %v %v %v", d.Token, d.CommentID, d.Reason)
 
@@ -232,14 +486,17 @@ func (c *Comments) processDel(ctx context.Context, d v1.Del, u user.User) (*v1.D
 	}
 
 	// Verify user signed with their active identity
-	if u.PublicKey() != d.PublicKey {
+	if !u.IsActivePublicKey(d.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
 		}
 	}
 
-	// Send plugin command
+	// Send plugin command. Admins are allowed to delete any comment.
+	// Users that are not admins are only allowed to delete their own
+	// comments, which the plugin verifies when the delete is flagged
+	// as an author delete.
 	cd := comments.Del{
 		State:     state,
 		Token:     d.Token,
@@ -248,6 +505,10 @@ func (c *Comments) processDel(ctx context.Context, d v1.Del, u user.User) (*v1.D
 		PublicKey: d.PublicKey,
 		Signature: d.Signature,
 	}
+	if !u.Admin {
+		cd.Author = true
+		cd.UserID = u.ID.String()
+	}
 	cdr, err := c.politeiad.CommentDel(ctx, cd)
 	if err != nil {
 		return nil, err
@@ -262,6 +523,99 @@ func (c *Comments) processDel(ctx context.Context, d v1.Del, u user.User) (*v1.D
 	}, nil
 }
 
+func (c *Comments) processDelUser(ctx context.Context, d v1.DelUser, u user.User) (*v1.DelUserReply, error) {
+	log.Tracef("processDelUser: %v %v %v", d.UserID, d.Token, d.Reason)
+
+	// Verify reason
+	if d.Reason == "" {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodeInputInvalid,
+			ErrorContext: "a reason must be given",
+		}
+	}
+
+	// Verify user signed with their active identity
+	if !u.IsActivePublicKey(d.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Get the records that the user has commented on. The comments
+	// plugin looks up the user's comments on each record itself, so
+	// the records only need to be looked up when a token was not
+	// provided.
+	tokens := []string{d.Token}
+	if d.Token == "" {
+		ur, err := c.politeiad.CommentsUser(ctx, "",
+			comments.User{UserID: d.UserID})
+		if err != nil {
+			return nil, err
+		}
+		tokens = make([]string, 0, len(ur.Records))
+		for _, v := range ur.Records {
+			// A record can be included for both record states if it
+			// was made public after the user commented on it.
+			if len(tokens) > 0 && tokens[len(tokens)-1] == v.Token {
+				continue
+			}
+			tokens = append(tokens, v.Token)
+		}
+	}
+
+	// Delete the user's comments one record at a time. Records that do
+	// not allow their comments to be deleted are skipped so that they
+	// do not prevent the comments on the remaining records from being
+	// deleted.
+	var (
+		pdu = comments.DelUser{
+			UserID:    d.UserID,
+			Reason:    d.Reason,
+			PublicKey: d.PublicKey,
+			Signature: d.Signature,
+		}
+		records = make([]v1.DelUserRecord, 0, len(tokens))
+		skipped = make([]string, 0, len(tokens))
+		total   uint32
+	)
+	for _, t := range tokens {
+		dr, err := c.politeiad.CommentDelUser(ctx, t, pdu)
+		if err != nil {
+			var pde pdclient.RespError
+			if d.Token == "" && errors.As(err, &pde) &&
+				pde.HTTPCode == http.StatusBadRequest {
+				log.Debugf("Comments of user %v not deleted on %v: %v",
+					d.UserID, t, err)
+				skipped = append(skipped, t)
+				continue
+			}
+			return nil, err
+		}
+		if len(dr.Comments) == 0 {
+			continue
+		}
+		commentIDs := make([]uint32, 0, len(dr.Comments))
+		for _, v := range dr.Comments {
+			commentIDs = append(commentIDs, v.CommentID)
+		}
+		records = append(records, v1.DelUserRecord{
+			Token:      t,
+			CommentIDs: commentIDs,
+		})
+		total += uint32(len(commentIDs))
+	}
+
+	log.Infof("Comments of user %v deleted by %v: %v",
+		d.UserID, u.Username, total)
+
+	return &v1.DelUserReply{
+		Records: records,
+		Skipped: skipped,
+		Total:   total,
+	}, nil
+}
+
 func (c *Comments) processCount(ctx context.Context, ct v1.Count) (*v1.CountReply, error) {
 	log.Tracef("processCount: %v", ct.Tokens)
 
@@ -281,27 +635,69 @@ func (c *Comments) processCount(ctx context.Context, ct v1.Count) (*v1.CountRepl
 	}
 
 	// Get comment counts
-	counts, err := c.politeiad.CommentCount(ctx, ct.Tokens)
+	replies, err := c.politeiad.CommentCountReplies(ctx, ct.Tokens)
 	if err != nil {
 		return nil, err
 	}
+	var (
+		counts = make(map[string]uint32, len(replies))
+		pinned map[string]uint32
+	)
+	for token, v := range replies {
+		counts[token] = v.Count
+		if v.PinnedID == 0 {
+			continue
+		}
+		if pinned == nil {
+			pinned = make(map[string]uint32, len(replies))
+		}
+		pinned[token] = v.PinnedID
+	}
 
 	return &v1.CountReply{
 		Counts: counts,
+		Pinned: pinned,
 	}, nil
 }
 
 func (c *Comments) processComments(ctx context.Context, cs v1.Comments, u *user.User) (*v1.CommentsReply, error) {
 	log.Tracef("processComments: %v", cs.Token)
 
-	// Send plugin command
-	pcomments, err := c.politeiad.CommentsGetAll(ctx, cs.Token)
+	// Verify sort order
+	err := c.sortVerify(cs.SortBy)
 	if err != nil {
 		return nil, err
 	}
+
+	// Send plugin command. Paginated requests are sorted by the
+	// plugin.
+	var (
+		pcomments []comments.Comment
+		total     uint32
+		paginated = cs.Page > 0 || cs.PageSize > 0
+	)
+	if paginated {
+		gp := comments.GetPage{
+			SortBy:   convertSortToPlugin(cs.SortBy),
+			Page:     cs.Page,
+			PageSize: cs.PageSize,
+		}
+		gpr, err := c.politeiad.CommentsGetPage(ctx, cs.Token, gp)
+		if err != nil {
+			return nil, err
+		}
+		pcomments = gpr.Comments
+		total = gpr.Total
+	} else {
+		pcomments, err = c.politeiad.CommentsGetAll(ctx, cs.Token)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if len(pcomments) == 0 {
 		return &v1.CommentsReply{
 			Comments: []v1.Comment{},
+			Total:    total,
 		}, nil
 	}
 
@@ -309,30 +705,9 @@ func (c *Comments) processComments(ctx context.Context, cs v1.Comments, u *user.
 	// unvetted comments. This is a public route so a user might
 	// not exist.
 	if pcomments[0].State == comments.RecordStateUnvetted {
-		var isAllowed bool
-		switch {
-		case u == nil:
-			// No logged in user. Not allowed.
-			isAllowed = false
-		case u.Admin:
-			// User is an admin. Allowed.
-			isAllowed = true
-		default:
-			// User is not an admin. Get the record author.
-			authorID, err := c.politeiad.Author(ctx, cs.Token)
-			if err != nil {
-				return nil, err
-			}
-			if u.ID.String() == authorID {
-				// User is the author. Allowed.
-				isAllowed = true
-			}
-		}
-		if !isAllowed {
-			return nil, v1.UserErrorReply{
-				ErrorCode:    v1.ErrorCodeUnauthorized,
-				ErrorContext: "user is not author or admin",
-			}
+		err := c.unvettedCommentsAllowed(ctx, cs.Token, u)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -353,23 +728,326 @@ func (c *Comments) processComments(ctx context.Context, cs v1.Comments, u *user.
 		}
 		commentPopulateUserData(&cm, *u)
 
+		// Render the comment HTML if requested. Deleted comments
+		// do not have any text to render.
+		if cs.RenderHTML && !cm.Deleted {
+			cm.HTML = renderHTML(cm.Comment, cm.ContentType)
+		}
+
 		// Add comment
 		comments = append(comments, cm)
 	}
 
+	// The plugin returns all comments ordered by comment ID
+	if !paginated && cs.SortBy != v1.SortInvalid {
+		sortComments(comments, cs.SortBy)
+	}
+
 	return &v1.CommentsReply{
 		Comments: comments,
+		Total:    total,
+	}, nil
+}
+
+// processTree processes a comments v1 tree request.
+func (c *Comments) processTree(ctx context.Context, t v1.Tree, u *user.User) (*v1.TreeReply, error) {
+	log.Tracef("processTree: %v %v", t.Token, t.ParentID)
+
+	// Verify sort order
+	err := c.sortVerify(t.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send plugin command
+	pt := comments.Tree{
+		ParentID: t.ParentID,
+		Depth:    t.Depth,
+		Children: t.Children,
+		SortBy:   convertSortToPlugin(t.SortBy),
+	}
+	tr, err := c.politeiad.CommentsTree(ctx, t.Token, pt)
+	if err != nil {
+		return nil, err
+	}
+	if len(tr.Comments) == 0 {
+		return &v1.TreeReply{
+			Comments: []v1.TreeComment{},
+			Replies:  tr.Replies,
+		}, nil
+	}
+
+	// Only admins and the record author are allowed to retrieve
+	// unvetted comments. This is a public route so a user might
+	// not exist.
+	if tr.Comments[0].Comment.State == comments.RecordStateUnvetted {
+		err := c.unvettedCommentsAllowed(ctx, t.Token, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Prepare reply. Comment user data must be pulled from the
+	// userdb. The users are cached since the same users tend to
+	// reply to one another throughout a thread.
+	users := make(map[string]*user.User)
+	var convert func(tcs []comments.TreeComment) ([]v1.TreeComment, error)
+	convert = func(tcs []comments.TreeComment) ([]v1.TreeComment, error) {
+		r := make([]v1.TreeComment, 0, len(tcs))
+		for _, v := range tcs {
+			cm := convertComment(v.Comment)
+
+			// Get comment user data
+			cu, ok := users[cm.UserID]
+			if !ok {
+				uuid, err := uuid.Parse(cm.UserID)
+				if err != nil {
+					return nil, err
+				}
+				cu, err = c.userdb.UserGetById(uuid)
+				if err != nil {
+					return nil, err
+				}
+				users[cm.UserID] = cu
+			}
+			commentPopulateUserData(&cm, *cu)
+
+			// Render the comment HTML if requested. Deleted comments
+			// do not have any text to render.
+			if t.RenderHTML && !cm.Deleted {
+				cm.HTML = renderHTML(cm.Comment, cm.ContentType)
+			}
+
+			children, err := convert(v.Children)
+			if err != nil {
+				return nil, err
+			}
+			tc := v1.TreeComment{
+				Comment: cm,
+				Replies: v.Replies,
+			}
+			if len(children) > 0 {
+				tc.Children = children
+			}
+			r = append(r, tc)
+		}
+		return r, nil
+	}
+	tcs, err := convert(tr.Comments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.TreeReply{
+		Comments: tcs,
+		Replies:  tr.Replies,
+	}, nil
+}
+
+func (c *Comments) processAttachments(ctx context.Context, a v1.Attachments, u *user.User) (*v1.AttachmentsReply, error) {
+	log.Tracef("processAttachments: %v %v", a.Token, a.CommentID)
+
+	// Send plugin command
+	pa := comments.Attachments{
+		CommentID: a.CommentID,
+	}
+	ar, err := c.politeiad.CommentsAttachments(ctx, a.Token, pa)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only admins and the record author are allowed to retrieve
+	// the attachments of unvetted comments. This is a public route
+	// so a user might not exist.
+	if ar.State == comments.RecordStateUnvetted {
+		err := c.unvettedCommentsAllowed(ctx, a.Token, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	as := convertAttachmentsToV1(ar.Attachments)
+	if as == nil {
+		as = []v1.Attachment{}
+	}
+
+	return &v1.AttachmentsReply{
+		Attachments: as,
+	}, nil
+}
+
+// processUserComments processes a comments v1 usercomments request.
+func (c *Comments) processUserComments(ctx context.Context, uc v1.UserComments, u *user.User) (*v1.UserCommentsReply, error) {
+	log.Tracef("processUserComments: %v %v", uc.UserID, uc.Token)
+
+	// Send plugin command
+	pu := comments.User{
+		UserID:          uc.UserID,
+		IncludeComments: uc.IncludeComments,
+	}
+	ur, err := c.politeiad.CommentsUser(ctx, uc.Token, pu)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comment user data must be pulled from the userdb. All of the
+	// comments were made by the same user, so the user only needs to
+	// be looked up once.
+	var cu *user.User
+	if uc.IncludeComments && len(ur.Records) > 0 {
+		uid, err := uuid.Parse(uc.UserID)
+		if err != nil {
+			return nil, err
+		}
+		cu, err = c.userdb.UserGetById(uid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Only admins and the user that made the comments are allowed to
+	// retrieve the comments that were made on unvetted records. This
+	// is a public route so a user might not exist.
+	var (
+		isAdmin = u != nil && u.Admin
+		isUser  = u != nil && u.ID.String() == uc.UserID
+	)
+	records := make([]v1.UserRecordComments, 0, len(ur.Records))
+	for _, v := range ur.Records {
+		if v.State == comments.RecordStateUnvetted && !isAdmin && !isUser {
+			continue
+		}
+		var cs []v1.Comment
+		if uc.IncludeComments {
+			cs = make([]v1.Comment, 0, len(v.Comments))
+			for _, pc := range v.Comments {
+				cm := convertComment(pc)
+				commentPopulateUserData(&cm, *cu)
+				cs = append(cs, cm)
+			}
+		}
+		records = append(records, v1.UserRecordComments{
+			Token:      v.Token,
+			State:      convertStateToV1(v.State),
+			CommentIDs: v.CommentIDs,
+			Comments:   cs,
+		})
+	}
+
+	return &v1.UserCommentsReply{
+		Records: records,
+	}, nil
+}
+
+func (c *Comments) processSearch(ctx context.Context, s v1.Search) (*v1.SearchReply, error) {
+	log.Tracef("processSearch: %q %v %v %v", s.Query, s.Token, s.UserID, s.Page)
+
+	// Search the comments. Only the comments of vetted records are
+	// searched since this is a public route.
+	ps := comments.Search{
+		Query:  s.Query,
+		State:  comments.RecordStateVetted,
+		UserID: s.UserID,
+		Page:   s.Page,
+	}
+	sr, err := c.politeiad.CommentSearch(ctx, s.Token, ps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the matched comments. The results can span multiple records
+	// so the comments are retrieved one record at a time.
+	var (
+		tokens     = make([]string, 0, len(sr.Results))
+		commentIDs = make(map[string][]uint32, len(sr.Results)) // [token]IDs
+	)
+	for _, v := range sr.Results {
+		if _, ok := commentIDs[v.Token]; !ok {
+			tokens = append(tokens, v.Token)
+		}
+		commentIDs[v.Token] = append(commentIDs[v.Token], v.CommentID)
+	}
+	pcomments := make(map[string]map[uint32]comments.Comment, len(tokens))
+	for _, t := range tokens {
+		cs, err := c.politeiad.CommentsGet(ctx, t,
+			comments.Get{CommentIDs: commentIDs[t]})
+		if err != nil {
+			return nil, err
+		}
+		pcomments[t] = cs
+	}
+
+	// Prepare reply. The results are returned in the same order that
+	// the plugin returned them. Comment user data must be pulled from
+	// the userdb. A user can have made multiple of the matched comments
+	// so the users are only looked up once.
+	var (
+		cs    = make([]v1.Comment, 0, len(sr.Results))
+		users = make(map[string]*user.User, len(sr.Results)) // [userID]User
+	)
+	for _, v := range sr.Results {
+		pc, ok := pcomments[v.Token][v.CommentID]
+		if !ok {
+			// The comment was deleted after the search was
+			// performed.
+			continue
+		}
+		cm := convertComment(pc)
+
+		// Get comment user data
+		u, ok := users[cm.UserID]
+		if !ok {
+			uid, err := uuid.Parse(cm.UserID)
+			if err != nil {
+				return nil, err
+			}
+			u, err = c.userdb.UserGetById(uid)
+			if err != nil {
+				return nil, err
+			}
+			users[cm.UserID] = u
+		}
+		commentPopulateUserData(&cm, *u)
+
+		cs = append(cs, cm)
+	}
+
+	return &v1.SearchReply{
+		Comments: cs,
+		Total:    sr.Total,
+	}, nil
+}
+
+func (c *Comments) processHistory(ctx context.Context, h v1.History) (*v1.HistoryReply, error) {
+	log.Tracef("processHistory: %v %v", h.UserID, h.Page)
+
+	// Get the comment history. Only the comments of vetted records
+	// are returned since this is a public route.
+	ph := comments.History{
+		UserID: h.UserID,
+		State:  comments.RecordStateVetted,
+		Page:   h.Page,
+	}
+	hr, err := c.politeiad.CommentHistory(ctx, ph)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.HistoryReply{
+		Entries: convertHistoryEntries(hr.Entries),
+		Total:   hr.Total,
 	}, nil
 }
 
 func (c *Comments) processVotes(ctx context.Context, v v1.Votes) (*v1.VotesReply, error) {
-	log.Tracef("processVotes: %v %v", v.Token, v.UserID)
+	log.Tracef("processVotes: %v %v %v", v.Token, v.UserID, v.Since)
 
 	// Get comment votes. Votes are only allowed on vetted comments so
 	// there is no need to check the user permissions since all vetted
 	// comments are public.
 	cm := comments.Votes{
 		UserID: v.UserID,
+		Since:  v.Since,
 		Page:   v.Page,
 	}
 	votes, err := c.politeiad.CommentVotes(ctx, v.Token, cm)
@@ -510,15 +1188,68 @@ func (c *Comments) processTimestamps(ctx context.Context, t v1.Timestamps, isAdm
 		return nil, err
 	}
 
-	// Prepare reply
-	var (
-		comments = make(map[uint32]v1.CommentTimestamp, len(ctr.Comments))
+	// Unvetted data payloads are removed from the timestamp if the
+	// user is not an admin.
+	rmPayloads := (r.State == pdv2.RecordStateUnvetted) && !isAdmin
 
-		// Unvetted data payloads are removed from the timestamp if the
-		// user is not an admin.
-		rmPayloads = (r.State == pdv2.RecordStateUnvetted) && !isAdmin
-	)
-	for commentID, ct := range ctr.Comments {
+	return &v1.TimestampsReply{
+		Comments: convertCommentTimestamps(ctr.Comments, rmPayloads),
+	}, nil
+}
+
+func (c *Comments) processTimestampsBatch(ctx context.Context, t v1.TimestampsBatch, isAdmin bool) (*v1.TimestampsReply, error) {
+	log.Tracef("processTimestampsBatch: %v %v", t.Token, t.Ranges)
+
+	// Verify there is work to do
+	if len(t.Ranges) == 0 {
+		return &v1.TimestampsReply{
+			Comments: map[uint32]v1.CommentTimestamp{},
+		}, nil
+	}
+
+	// Get record state
+	r, err := c.recordNoFiles(ctx, t.Token)
+	if err != nil {
+		if err == errRecordNotFound {
+			return nil, v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeRecordNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	// Get timestamps. The comment ID ranges are validated by the
+	// comments plugin.
+	ranges := make([]comments.CommentIDRange, 0, len(t.Ranges))
+	for _, v := range t.Ranges {
+		ranges = append(ranges, comments.CommentIDRange{
+			Start: v.Start,
+			End:   v.End,
+		})
+	}
+	tb := comments.TimestampsBatch{
+		Ranges: ranges,
+	}
+	ctr, err := c.politeiad.CommentTimestampsBatch(ctx, t.Token, tb)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unvetted data payloads are removed from the timestamp if the
+	// user is not an admin.
+	rmPayloads := (r.State == pdv2.RecordStateUnvetted) && !isAdmin
+
+	return &v1.TimestampsReply{
+		Comments: convertCommentTimestamps(ctr.Comments, rmPayloads),
+	}, nil
+}
+
+// convertCommentTimestamps converts the comments plugin comment timestamps to
+// comments v1 comment timestamps. The data payloads are removed from the
+// timestamps when rmPayloads is set.
+func convertCommentTimestamps(cts map[uint32]comments.CommentTimestamp, rmPayloads bool) map[uint32]v1.CommentTimestamp {
+	r := make(map[uint32]v1.CommentTimestamp, len(cts))
+	for commentID, ct := range cts {
 		adds := make([]v1.Timestamp, 0, len(ct.Adds))
 		for _, ts := range ct.Adds {
 			if rmPayloads {
@@ -536,15 +1267,12 @@ func (c *Comments) processTimestamps(ctx context.Context, t v1.Timestamps, isAdm
 			del = &d
 		}
 
-		comments[commentID] = v1.CommentTimestamp{
+		r[commentID] = v1.CommentTimestamp{
 			Adds: adds,
 			Del:  del,
 		}
 	}
-
-	return &v1.TimestampsReply{
-		Comments: comments,
-	}, nil
+	return r
 }
 
 var (
@@ -575,6 +1303,54 @@ func (c *Comments) recordNoFiles(ctx context.Context, token string) (*pdv2.Recor
 
 // commentPopulateUserData populates the comment with user data that is not
 // stored in politeiad.
+// sortVerify verifies that the provided comments sort order is supported.
+func (c *Comments) sortVerify(sortBy v1.SortT) error {
+	switch sortBy {
+	case v1.SortInvalid, v1.SortScore, v1.SortTimestamp:
+		// These are allowed
+	case v1.SortStakeScore:
+		if !c.policy.StakeWeighting {
+			return v1.UserErrorReply{
+				ErrorCode:    v1.ErrorCodeSortInvalid,
+				ErrorContext: "stake weighting is not enabled",
+			}
+		}
+	default:
+		return v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodeSortInvalid,
+		}
+	}
+	return nil
+}
+
+// unvettedCommentsAllowed returns an error if the provided user is not
+// allowed to retrieve the comments of an unvetted record. Only admins and the
+// record author are allowed to retrieve unvetted comments. The user will be
+// nil if there is no logged in user.
+func (c *Comments) unvettedCommentsAllowed(ctx context.Context, token string, u *user.User) error {
+	switch {
+	case u == nil:
+		// No logged in user. Not allowed.
+	case u.Admin:
+		// User is an admin. Allowed.
+		return nil
+	default:
+		// User is not an admin. Get the record author.
+		authorID, err := c.politeiad.Author(ctx, token)
+		if err != nil {
+			return err
+		}
+		if u.ID.String() == authorID {
+			// User is the author. Allowed.
+			return nil
+		}
+	}
+	return v1.UserErrorReply{
+		ErrorCode:    v1.ErrorCodeUnauthorized,
+		ErrorContext: "user is not author or admin",
+	}
+}
+
 func commentPopulateUserData(c *v1.Comment, u user.User) {
 	c.Username = u.Username
 }
@@ -603,28 +1379,101 @@ func convertComment(c comments.Comment) v1.Comment {
 	// Fields that are intentionally omitted are not stored in
 	// politeiad. They need to be pulled from the userdb.
 	return v1.Comment{
-		UserID:        c.UserID,
-		Username:      "", // Intentionally omitted
-		State:         convertStateToV1(c.State),
-		Token:         c.Token,
-		ParentID:      c.ParentID,
-		Comment:       c.Comment,
-		PublicKey:     c.PublicKey,
-		Signature:     c.Signature,
-		CommentID:     c.CommentID,
-		Version:       c.Version,
-		CreatedAt:     c.CreatedAt,
-		Timestamp:     c.Timestamp,
-		Receipt:       c.Receipt,
-		Downvotes:     c.Downvotes,
-		Upvotes:       c.Upvotes,
-		Deleted:       c.Deleted,
-		Reason:        c.Reason,
-		ExtraData:     c.ExtraData,
-		ExtraDataHint: c.ExtraDataHint,
+		UserID:          c.UserID,
+		Username:        "", // Intentionally omitted
+		State:           convertStateToV1(c.State),
+		Token:           c.Token,
+		ParentID:        c.ParentID,
+		Comment:         c.Comment,
+		PublicKey:       c.PublicKey,
+		Signature:       c.Signature,
+		CommentID:       c.CommentID,
+		Version:         c.Version,
+		CreatedAt:       c.CreatedAt,
+		Timestamp:       c.Timestamp,
+		Receipt:         c.Receipt,
+		Downvotes:       c.Downvotes,
+		Upvotes:         c.Upvotes,
+		Reactions:       c.Reactions,
+		Deleted:         c.Deleted,
+		StakeDownvotes:  c.StakeDownvotes,
+		StakeUpvotes:    c.StakeUpvotes,
+		Reason:          c.Reason,
+		DeletedByAuthor: c.DeletedByAuthor,
+		BulkDeleted:     c.BulkDeleted,
+		ContentType:     c.ContentType,
+		Attachments:     convertAttachmentsToV1(c.Attachments),
+		ExtraData:       c.ExtraData,
+		ExtraDataHint:   c.ExtraDataHint,
 	}
 }
 
+func convertAttachmentsToPlugin(as []v1.Attachment) []comments.Attachment {
+	if len(as) == 0 {
+		return nil
+	}
+	r := make([]comments.Attachment, 0, len(as))
+	for _, v := range as {
+		r = append(r, comments.Attachment{
+			Name:    v.Name,
+			MIME:    v.MIME,
+			Digest:  v.Digest,
+			Payload: v.Payload,
+		})
+	}
+	return r
+}
+
+func convertAttachmentsToV1(as []comments.Attachment) []v1.Attachment {
+	if len(as) == 0 {
+		return nil
+	}
+	r := make([]v1.Attachment, 0, len(as))
+	for _, v := range as {
+		r = append(r, v1.Attachment{
+			Name:    v.Name,
+			MIME:    v.MIME,
+			Digest:  v.Digest,
+			Payload: v.Payload,
+		})
+	}
+	return r
+}
+
+// sortComments sorts the provided comments in place using the provided sort
+// order. Comments with the same score keep their existing order.
+func sortComments(cs []v1.Comment, sortBy v1.SortT) {
+	if sortBy == v1.SortTimestamp {
+		// Comment IDs are assigned in the order that the comments
+		// are created.
+		sort.SliceStable(cs, func(i, j int) bool {
+			return cs[i].CommentID > cs[j].CommentID
+		})
+		return
+	}
+	score := func(c v1.Comment) int64 {
+		if sortBy == v1.SortStakeScore {
+			return int64(c.StakeUpvotes) - int64(c.StakeDownvotes)
+		}
+		return int64(c.Upvotes) - int64(c.Downvotes)
+	}
+	sort.SliceStable(cs, func(i, j int) bool {
+		return score(cs[i]) > score(cs[j])
+	})
+}
+
+func convertSortToPlugin(s v1.SortT) comments.SortT {
+	switch s {
+	case v1.SortScore:
+		return comments.SortScore
+	case v1.SortStakeScore:
+		return comments.SortStakeScore
+	case v1.SortTimestamp:
+		return comments.SortTimestamp
+	}
+	return comments.SortInvalid
+}
+
 func convertCommentVotes(cv []comments.CommentVote) []v1.CommentVote {
 	c := make([]v1.CommentVote, 0, len(cv))
 	for _, v := range cv {
@@ -638,11 +1487,25 @@ func convertCommentVotes(cv []comments.CommentVote) []v1.CommentVote {
 			Signature: v.Signature,
 			Timestamp: v.Timestamp,
 			Receipt:   v.Receipt,
+			Reaction:  v.Reaction,
 		})
 	}
 	return c
 }
 
+func convertHistoryEntries(entries []comments.HistoryEntry) []v1.HistoryEntry {
+	es := make([]v1.HistoryEntry, 0, len(entries))
+	for _, v := range entries {
+		es = append(es, v1.HistoryEntry{
+			Token:     v.Token,
+			CommentID: v.CommentID,
+			Excerpt:   v.Excerpt,
+			Timestamp: v.Timestamp,
+		})
+	}
+	return es
+}
+
 func convertProof(p comments.Proof) v1.Proof {
 	return v1.Proof{
 		Type:       p.Type,
@@ -0,0 +1,316 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	v1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+)
+
+// renderHTML returns the sanitized HTML rendering of the provided comment
+// text.
+//
+// The renderer escapes everything by default. No HTML from the comment text
+// is ever passed through. Markdown comments are rendered using a restricted
+// markdown subset: paragraphs, headings, block quotes, lists, fenced code
+// blocks, inline code, bold, italic, and links. Links are only rendered for
+// absolute http and https URLs. All other content types are rendered as
+// escaped plain text.
+func renderHTML(text, contentType string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	switch contentType {
+	case v1.ContentTypeMarkdown, "":
+		return renderMarkdown(text)
+	default:
+		return renderPlain(text)
+	}
+}
+
+// renderPlain renders the provided text as escaped paragraphs. Blank lines
+// separate paragraphs and line breaks are preserved.
+func renderPlain(text string) string {
+	var b strings.Builder
+	for _, p := range strings.Split(text, "\n\n") {
+		p = strings.Trim(p, "\n")
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		lines := strings.Split(p, "\n")
+		for i, l := range lines {
+			lines[i] = escape(l)
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.Join(lines, "<br>"))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+// markdownBlock represents the markdown block element that is currently
+// being rendered.
+type markdownBlock int
+
+const (
+	blockNone markdownBlock = iota
+	blockParagraph
+	blockQuote
+	blockUnorderedList
+	blockOrderedList
+)
+
+// markdownRenderer renders markdown text one line at a time.
+type markdownRenderer struct {
+	b     strings.Builder
+	block markdownBlock
+	lines []string // Lines of the current paragraph or block quote
+}
+
+// renderMarkdown renders the provided markdown text using the markdown
+// subset that is described by renderHTML.
+func renderMarkdown(text string) string {
+	var (
+		r      markdownRenderer
+		inCode bool
+	)
+	for _, l := range strings.Split(text, "\n") {
+		// Fenced code blocks
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			if inCode {
+				r.b.WriteString("</code></pre>")
+				inCode = false
+				continue
+			}
+			r.close()
+			r.b.WriteString("<pre><code>")
+			inCode = true
+			continue
+		}
+		if inCode {
+			r.b.WriteString(escape(l))
+			r.b.WriteString("\n")
+			continue
+		}
+
+		trimmed := strings.TrimSpace(l)
+		switch {
+		case trimmed == "":
+			r.close()
+
+		case headingLevel(trimmed) > 0:
+			r.close()
+			n := headingLevel(trimmed)
+			fmt.Fprintf(&r.b, "<h%v>%v</h%v>", n,
+				renderInline(strings.TrimSpace(trimmed[n:])), n)
+
+		case strings.HasPrefix(trimmed, ">"):
+			r.open(blockQuote)
+			r.lines = append(r.lines,
+				strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+
+		case unorderedItem(trimmed) != "":
+			r.open(blockUnorderedList)
+			fmt.Fprintf(&r.b, "<li>%v</li>",
+				renderInline(unorderedItem(trimmed)))
+
+		case orderedItem(trimmed) != "":
+			r.open(blockOrderedList)
+			fmt.Fprintf(&r.b, "<li>%v</li>",
+				renderInline(orderedItem(trimmed)))
+
+		default:
+			r.open(blockParagraph)
+			r.lines = append(r.lines, trimmed)
+		}
+	}
+	if inCode {
+		r.b.WriteString("</code></pre>")
+	}
+	r.close()
+
+	return r.b.String()
+}
+
+// open opens the provided block element. The current block element is
+// closed if it is a different block element.
+func (r *markdownRenderer) open(block markdownBlock) {
+	if r.block == block {
+		return
+	}
+	r.close()
+	r.block = block
+	switch block {
+	case blockUnorderedList:
+		r.b.WriteString("<ul>")
+	case blockOrderedList:
+		r.b.WriteString("<ol>")
+	}
+}
+
+// close closes the current block element.
+func (r *markdownRenderer) close() {
+	switch r.block {
+	case blockParagraph:
+		fmt.Fprintf(&r.b, "<p>%v</p>",
+			renderInline(strings.Join(r.lines, "\n")))
+	case blockQuote:
+		fmt.Fprintf(&r.b, "<blockquote><p>%v</p></blockquote>",
+			renderInline(strings.Join(r.lines, "\n")))
+	case blockUnorderedList:
+		r.b.WriteString("</ul>")
+	case blockOrderedList:
+		r.b.WriteString("</ol>")
+	}
+	r.block = blockNone
+	r.lines = nil
+}
+
+// headingLevel returns the heading level of the provided line. Zero is
+// returned if the line is not a heading.
+func headingLevel(l string) int {
+	n := 0
+	for n < len(l) && l[n] == '#' {
+		n++
+	}
+	if n == 0 || n > 6 || n == len(l) || l[n] != ' ' {
+		return 0
+	}
+	return n
+}
+
+// unorderedItem returns the text of the provided unordered list item. An
+// empty string is returned if the line is not an unordered list item.
+func unorderedItem(l string) string {
+	for _, prefix := range []string{"- ", "* ", "+ "} {
+		if strings.HasPrefix(l, prefix) {
+			return strings.TrimSpace(l[len(prefix):])
+		}
+	}
+	return ""
+}
+
+// orderedItem returns the text of the provided ordered list item. An empty
+// string is returned if the line is not an ordered list item.
+func orderedItem(l string) string {
+	n := 0
+	for n < len(l) && l[n] >= '0' && l[n] <= '9' {
+		n++
+	}
+	if n == 0 || !strings.HasPrefix(l[n:], ". ") {
+		return ""
+	}
+	return strings.TrimSpace(l[n+2:])
+}
+
+// renderInline renders the inline markdown elements of the provided text.
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '`':
+			// Inline code
+			j := strings.IndexByte(s[i+1:], '`')
+			if j < 0 {
+				break
+			}
+			b.WriteString("<code>")
+			b.WriteString(escape(s[i+1 : i+1+j]))
+			b.WriteString("</code>")
+			i += j + 2
+			continue
+
+		case strings.HasPrefix(s[i:], "**"):
+			// Bold
+			j := strings.Index(s[i+2:], "**")
+			if j <= 0 {
+				break
+			}
+			b.WriteString("<strong>")
+			b.WriteString(renderInline(s[i+2 : i+2+j]))
+			b.WriteString("</strong>")
+			i += j + 4
+			continue
+
+		case s[i] == '*':
+			// Italic
+			j := strings.IndexByte(s[i+1:], '*')
+			if j <= 0 {
+				break
+			}
+			b.WriteString("<em>")
+			b.WriteString(renderInline(s[i+1 : i+1+j]))
+			b.WriteString("</em>")
+			i += j + 2
+			continue
+
+		case s[i] == '[':
+			// Link
+			text, link, n := parseLink(s[i:])
+			if n == 0 {
+				break
+			}
+			if !linkAllowed(link) {
+				// Render the link text without the link
+				b.WriteString(renderInline(text))
+				i += n
+				continue
+			}
+			fmt.Fprintf(&b, "<a href=\"%v\" rel=\"nofollow noopener "+
+				"noreferrer\">%v</a>", escape(link), renderInline(text))
+			i += n
+			continue
+		}
+
+		b.WriteString(escape(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// parseLink parses a markdown link, i.e. [text](link), from the start of the
+// provided string. The number of bytes that were parsed is returned. Zero is
+// returned if the string does not start with a link.
+func parseLink(s string) (string, string, int) {
+	end := strings.Index(s, "](")
+	if end < 0 {
+		return "", "", 0
+	}
+	text := s[1:end]
+	if strings.ContainsAny(text, "[]") {
+		return "", "", 0
+	}
+	closing := strings.IndexByte(s[end+2:], ')')
+	if closing < 0 {
+		return "", "", 0
+	}
+	link := strings.TrimSpace(s[end+2 : end+2+closing])
+	return text, link, end + 3 + closing
+}
+
+// linkAllowed returns whether the provided link is an absolute http or https
+// URL that can be rendered.
+func linkAllowed(link string) bool {
+	if strings.ContainsAny(link, " \t\n") {
+		return false
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+	default:
+		return false
+	}
+	return u.Host != ""
+}
+
+// escape escapes the HTML special characters of the provided string.
+func escape(s string) string {
+	return html.EscapeString(s)
+}
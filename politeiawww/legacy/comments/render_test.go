@@ -0,0 +1,123 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"testing"
+
+	v1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+)
+
+func TestRenderHTML(t *testing.T) {
+	var tests = []struct {
+		name        string
+		text        string
+		contentType string
+		want        string
+	}{
+		{
+			"plain text",
+			"a <b>\nc\n\nd",
+			v1.ContentTypePlain,
+			"<p>a &lt;b&gt;<br>c</p><p>d</p>",
+		},
+		{
+			"plain text markdown",
+			"**a**",
+			v1.ContentTypePlain,
+			"<p>**a**</p>",
+		},
+		{
+			"paragraphs",
+			"a\nb\n\nc",
+			v1.ContentTypeMarkdown,
+			"<p>a\nb</p><p>c</p>",
+		},
+		{
+			"default content type",
+			"*a*",
+			"",
+			"<p><em>a</em></p>",
+		},
+		{
+			"inline",
+			"**a** *b* `<c>`",
+			v1.ContentTypeMarkdown,
+			"<p><strong>a</strong> <em>b</em> <code>&lt;c&gt;</code></p>",
+		},
+		{
+			"heading",
+			"## a",
+			v1.ContentTypeMarkdown,
+			"<h2>a</h2>",
+		},
+		{
+			"lists",
+			"- a\n- b\n1. c",
+			v1.ContentTypeMarkdown,
+			"<ul><li>a</li><li>b</li></ul><ol><li>c</li></ol>",
+		},
+		{
+			"block quote",
+			"> a\n> b",
+			v1.ContentTypeMarkdown,
+			"<blockquote><p>a\nb</p></blockquote>",
+		},
+		{
+			"code block",
+			"```\n<script>\n```",
+			v1.ContentTypeMarkdown,
+			"<pre><code>&lt;script&gt;\n</code></pre>",
+		},
+		{
+			"link",
+			"[a](https://decred.org)",
+			v1.ContentTypeMarkdown,
+			"<p><a href=\"https://decred.org\" rel=\"nofollow noopener " +
+				"noreferrer\">a</a></p>",
+		},
+		{
+			"html",
+			"<script>alert(1)</script><img src=x onerror=alert(1)>",
+			v1.ContentTypeMarkdown,
+			"<p>&lt;script&gt;alert(1)&lt;/script&gt;&lt;img src=x " +
+				"onerror=alert(1)&gt;</p>",
+		},
+		{
+			"javascript link",
+			"[a](javascript:alert(1))",
+			v1.ContentTypeMarkdown,
+			"<p>a)</p>",
+		},
+		{
+			"relative link",
+			"[a](/path)",
+			v1.ContentTypeMarkdown,
+			"<p>a</p>",
+		},
+		{
+			"link attribute injection",
+			"[a](https://decred.org/\"onmouseover=\"alert(1))",
+			v1.ContentTypeMarkdown,
+			"<p><a href=\"https://decred.org/&#34;onmouseover=&#34;alert" +
+				"(1\" rel=\"nofollow noopener noreferrer\">a</a>)</p>",
+		},
+		{
+			"link text",
+			"[<b>](https://decred.org)",
+			v1.ContentTypeMarkdown,
+			"<p><a href=\"https://decred.org\" rel=\"nofollow noopener " +
+				"noreferrer\">&lt;b&gt;</a></p>",
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := renderHTML(v.text, v.contentType)
+			if got != v.want {
+				t.Errorf("got %q, want %q", got, v.want)
+			}
+		})
+	}
+}
@@ -581,13 +581,13 @@ func (p *Politeiawww) validateDCC(nd cms.NewDCC, u *user.User) error {
 	}
 
 	// Verify public key
-	if u.PublicKey() != nd.PublicKey {
+	if !u.IsActivePublicKey(nd.PublicKey) {
 		return www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
 	}
 
-	pk, err := identity.PublicIdentityFromBytes(u.ActiveIdentity().Key[:])
+	pk, err := identity.PublicIdentityFromString(nd.PublicKey)
 	if err != nil {
 		return err
 	}
@@ -1057,7 +1057,7 @@ func (p *Politeiawww) processNewCommentDCC(ctx context.Context, nc www.NewCommen
 	}
 
 	// Ensure the public key is the user's active key
-	if nc.PublicKey != u.PublicKey() {
+	if !u.IsActivePublicKey(nc.PublicKey) {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
@@ -1227,7 +1227,7 @@ func (p *Politeiawww) processSetDCCStatus(ctx context.Context, sds cms.SetDCCSta
 	log.Tracef("processSetDCCStatus: %v", u.PublicKey())
 
 	// Ensure the provided public key is the user's active key.
-	if sds.PublicKey != u.PublicKey() {
+	if !u.IsActivePublicKey(sds.PublicKey) {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
@@ -1284,7 +1284,7 @@ func (p *Politeiawww) processSetDCCStatus(ctx context.Context, sds cms.SetDCCSta
 	// Create the change record.
 	c := mdstream.DCCStatusChange{
 		Version:        mdstream.VersionDCCStatusChange,
-		AdminPublicKey: u.PublicKey(),
+		AdminPublicKey: sds.PublicKey,
 		Timestamp:      time.Now().Unix(),
 		NewStatus:      sds.Status,
 		Reason:         sds.Reason,
@@ -1801,7 +1801,7 @@ func (p *Politeiawww) processStartVoteDCC(ctx context.Context, sv cms.StartVote,
 	}
 
 	// Ensure the public key is the user's active key
-	if sv.PublicKey != u.PublicKey() {
+	if !u.IsActivePublicKey(sv.PublicKey) {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
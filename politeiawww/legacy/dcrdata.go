@@ -85,8 +85,11 @@ func makeRequest(ctx context.Context, url string, timeout time.Duration) ([]byte
 		return nil, fmt.Errorf("unable to create request: %v", err)
 	}
 
-	client := &http.Client{
+	client, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
 		Timeout: timeout * time.Second,
+	})
+	if err != nil {
+		return nil, err
 	}
 	response, err := client.Do(req)
 	if err != nil {
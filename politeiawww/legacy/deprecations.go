@@ -0,0 +1,155 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// sunsetLayout is the layout of the DeprecationNotice sunset date.
+	sunsetLayout = "2006-01-02"
+)
+
+// deprecations is the registry of deprecated API routes and fields. The
+// deprecation headers are added to the replies of every route that has a
+// notice in this registry. A sunset date should be added to a notice once
+// the removal of the route or field has been scheduled so that clients are
+// warned ahead of the breaking change.
+var deprecations = []www.DeprecationNotice{
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteTokenInventory,
+		Replacement: tkv1.APIRoute + tkv1.RouteInventory,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteAllVetted,
+		Replacement: rcv1.APIRoute + rcv1.RouteInventoryOrdered,
+	},
+	{
+		Route: www.PoliteiaWWWAPIRoute + www.RouteAllVetted,
+		Field: "before",
+	},
+	{
+		Route: www.PoliteiaWWWAPIRoute + www.RouteAllVetted,
+		Field: "after",
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteProposalDetails,
+		Replacement: rcv1.APIRoute + rcv1.RouteDetails,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteBatchProposals,
+		Replacement: rcv1.APIRoute + rcv1.RouteRecords,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteVoteStatus,
+		Replacement: tkv1.APIRoute + tkv1.RouteSummaries,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteAllVoteStatus,
+		Replacement: tkv1.APIRoute + tkv1.RouteSummaries,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteBatchVoteSummary,
+		Replacement: tkv1.APIRoute + tkv1.RouteSummaries,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteActiveVote,
+		Replacement: tkv1.APIRoute + tkv1.RouteDetails,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteCastVotes,
+		Replacement: tkv1.APIRoute + tkv1.RouteCastBallot,
+	},
+	{
+		Route:       www.PoliteiaWWWAPIRoute + www.RouteVoteResults,
+		Replacement: tkv1.APIRoute + tkv1.RouteResults,
+	},
+}
+
+// deprecationNotices returns the deprecation notices from the provided
+// registry that apply to the provided route.
+func deprecationNotices(registry []www.DeprecationNotice, route string) []www.DeprecationNotice {
+	notices := make([]www.DeprecationNotice, 0, len(registry))
+	for _, v := range registry {
+		if v.Route == route {
+			notices = append(notices, v)
+		}
+	}
+	return notices
+}
+
+// deprecationHeaders returns the deprecation headers for the provided
+// notices. Nil is returned if there are no notices.
+func deprecationHeaders(notices []www.DeprecationNotice) (http.Header, error) {
+	if len(notices) == 0 {
+		return nil, nil
+	}
+
+	// Find the earliest sunset date
+	var sunset time.Time
+	for _, v := range notices {
+		if v.Sunset == "" {
+			continue
+		}
+		t, err := time.Parse(sunsetLayout, v.Sunset)
+		if err != nil {
+			return nil, err
+		}
+		if sunset.IsZero() || t.Before(sunset) {
+			sunset = t
+		}
+	}
+
+	b, err := json.Marshal(notices)
+	if err != nil {
+		return nil, err
+	}
+	h := make(http.Header, 3)
+	h.Set(www.HeaderDeprecation, "true")
+	h.Set(www.HeaderDeprecationNotices, string(b))
+	if !sunset.IsZero() {
+		h.Set(www.HeaderSunset, sunset.UTC().Format(http.TimeFormat))
+	}
+
+	return h, nil
+}
+
+// withDeprecationHeaders wraps the provided handler with a handler that adds
+// the deprecation headers for the provided route to the reply. The provided
+// handler is returned unchanged if the route has no deprecation notices.
+func withDeprecationHeaders(route string, handler http.HandlerFunc) http.HandlerFunc {
+	h, err := deprecationHeaders(deprecationNotices(deprecations, route))
+	if err != nil {
+		// The registry is defined in code. This is a developer
+		// error.
+		panic(err)
+	}
+	if h == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range h {
+			w.Header()[k] = v
+		}
+		handler(w, r)
+	}
+}
+
+// handleDeprecations returns the deprecation notices of all API routes.
+func (p *Politeiawww) handleDeprecations(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleDeprecations")
+
+	util.RespondWithJSON(w, http.StatusOK, www.DeprecationsReply{
+		Notices: deprecations,
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/client"
+)
+
+func TestDeprecationsRegistry(t *testing.T) {
+	// Verify that the headers can be created for every route in the
+	// registry.
+	for _, v := range deprecations {
+		if v.Route == "" {
+			t.Errorf("notice is missing a route: %+v", v)
+		}
+		_, err := deprecationHeaders(deprecationNotices(deprecations, v.Route))
+		if err != nil {
+			t.Errorf("%v: %v", v.Route, err)
+		}
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	var (
+		route    = "/v1/route"
+		registry = []www.DeprecationNotice{
+			{Route: route, Sunset: "2030-06-01"},
+			{Route: route, Field: "field", Sunset: "2030-01-01"},
+			{Route: "/v1/other", Sunset: "2029-01-01"},
+		}
+	)
+
+	// Route with no notices
+	h, err := deprecationHeaders(deprecationNotices(registry, "/v1/none"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h != nil {
+		t.Fatalf("got headers %v, want nil", h)
+	}
+
+	// Route with notices. The sunset header should contain the
+	// earliest sunset date of the route.
+	notices := deprecationNotices(registry, route)
+	h, err = deprecationHeaders(notices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Get(www.HeaderDeprecation) != "true" {
+		t.Errorf("got deprecation header %q, want true",
+			h.Get(www.HeaderDeprecation))
+	}
+	want := "Tue, 01 Jan 2030 00:00:00 GMT"
+	if h.Get(www.HeaderSunset) != want {
+		t.Errorf("got sunset header %q, want %q",
+			h.Get(www.HeaderSunset), want)
+	}
+
+	// Verify the notices can be decoded by the client
+	got, err := client.DeprecationNotices(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(notices) {
+		t.Fatalf("got %v notices, want %v", len(got), len(notices))
+	}
+	for i, v := range got {
+		if v != notices[i] {
+			t.Errorf("got notice %+v, want %+v", v, notices[i])
+		}
+	}
+
+	// Notices without a sunset date
+	h, err = deprecationHeaders([]www.DeprecationNotice{{Route: route}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Get(www.HeaderSunset) != "" {
+		t.Errorf("got sunset header %q, want none", h.Get(www.HeaderSunset))
+	}
+
+	// Invalid sunset date
+	_, err = deprecationHeaders([]www.DeprecationNotice{
+		{Route: route, Sunset: "01/01/2030"},
+	})
+	if err == nil {
+		t.Errorf("got nil error for invalid sunset date")
+	}
+}
+
+func TestWithDeprecationHeaders(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	var tests = []struct {
+		name       string
+		route      string
+		deprecated bool
+	}{
+		{"deprecated route",
+			www.PoliteiaWWWAPIRoute + www.RouteCastVotes, true},
+		{"supported route",
+			www.PoliteiaWWWAPIRoute + www.RoutePolicy, false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, v.route, nil)
+			withDeprecationHeaders(v.route, handler)(rr, req)
+
+			got := rr.Header().Get(www.HeaderDeprecation) != ""
+			if got != v.deprecated {
+				t.Errorf("got deprecated %v, want %v", got, v.deprecated)
+			}
+		})
+	}
+}
@@ -31,6 +31,17 @@ func (p *Politeiawww) setUserEmailsCache(email string, id uuid.UUID) {
 	p.userEmails[email] = id
 }
 
+// updateUserEmailsCache replaces the old email-userID mapping with a mapping
+// for the new email address in the user emails cache.
+//
+// This function must be called WITHOUT the lock held.
+func (p *Politeiawww) updateUserEmailsCache(oldEmail, newEmail string, id uuid.UUID) {
+	p.Lock()
+	defer p.Unlock()
+	delete(p.userEmails, oldEmail)
+	p.userEmails[newEmail] = id
+}
+
 // userIDByEmail returns a userID given their email address.
 //
 // This function must be called WITHOUT the lock held.
@@ -0,0 +1,210 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package enums expands the numeric status codes of the v1 API replies into
+// www.Enum objects that contain both the numeric code and a stable string
+// identifier. Clients request expanded replies using the www.QueryExpand URL
+// query param so that they do not need to hardcode numeric status values.
+package enums
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/util"
+)
+
+// registry contains the string identifiers of the codes of every enum type
+// that is expanded, i.e. map[enumType]map[code]id.
+var registry = make(map[reflect.Type]map[uint64]string, 16)
+
+func init() {
+	register(rcv1.RecordStates)
+	register(rcv1.RecordStatuses)
+	register(map[cmv1.RecordStateT]string{
+		cmv1.RecordStateInvalid:  rcv1.RecordStates[rcv1.RecordStateInvalid],
+		cmv1.RecordStateUnvetted: rcv1.RecordStates[rcv1.RecordStateUnvetted],
+		cmv1.RecordStateVetted:   rcv1.RecordStates[rcv1.RecordStateVetted],
+	})
+	register(cmv1.SubmissionStatuses)
+	register(tkv1.VoteStatuses)
+	register(tkv1.VoteTypes)
+	register(tkv1.RejectionReasons)
+	register(tkv1.SubmissionStatuses)
+	register(piv1.BillingStatuses)
+}
+
+// register adds the provided map[enumType]string to the registry. The map
+// keys must be unsigned integers.
+func register(m interface{}) {
+	v := reflect.ValueOf(m)
+	ids := make(map[uint64]string, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		ids[iter.Key().Uint()] = iter.Value().String()
+	}
+	registry[v.Type().Key()] = ids
+}
+
+// Requested returns whether the request asks for the enums to be expanded.
+func Requested(r *http.Request) bool {
+	for _, v := range r.URL.Query()[www.QueryExpand] {
+		for _, s := range strings.Split(v, ",") {
+			if s == www.ExpandEnums {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RespondWithJSON responds with the JSON encoded payload. The enums in the
+// payload are expanded when they are requested by the provided request.
+func RespondWithJSON(w http.ResponseWriter, r *http.Request, code int, payload interface{}) {
+	if Requested(r) {
+		payload = Expand(payload)
+	}
+	util.RespondWithJSON(w, code, payload)
+}
+
+// Expand returns a value that encodes to the same JSON as the provided value
+// except that all registered enums are encoded as www.Enum objects. Codes
+// that are not found in the registry are expanded using an empty ID.
+func Expand(v interface{}) interface{} {
+	return expand(reflect.ValueOf(v))
+}
+
+var (
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// expand walks the provided value and returns a value that can be JSON
+// encoded. Structs are converted to maps keyed by their JSON field names so
+// that the registered enums can be replaced with www.Enum objects.
+func expand(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if ids, ok := registry[v.Type()]; ok {
+		return www.Enum{
+			Code: uint32(v.Uint()),
+			ID:   ids[v.Uint()],
+		}
+	}
+	if v.Type().Implements(marshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return expand(v.Elem())
+
+	case reflect.Struct:
+		m := make(map[string]interface{}, v.NumField())
+		expandStruct(v, m)
+		return m
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64 encoded
+			return v.Interface()
+		}
+		fallthrough
+
+	case reflect.Array:
+		s := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s = append(s, expand(v.Index(i)))
+		}
+		return s
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[mapKey(iter.Key())] = expand(iter.Value())
+		}
+		return m
+	}
+
+	return v.Interface()
+}
+
+// expandStruct adds the expanded fields of the provided struct to the
+// provided map using the same field names and omitempty rules as the json
+// package. The fields of embedded structs that do not have a JSON name are
+// promoted to the parent struct.
+func expandStruct(v reflect.Value, m map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+		if f.Anonymous && name == "" {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				expandStruct(fv, m)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "omitempty") && isEmpty(fv) {
+			continue
+		}
+		m[name] = expand(fv)
+	}
+}
+
+// isEmpty returns whether the value is considered empty by the omitempty
+// option of the json package.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
+
+// mapKey returns the JSON object key of the provided map key.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+	return fmt.Sprint(k.Interface())
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package enums
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+)
+
+func TestRequested(t *testing.T) {
+	var tests = []struct {
+		url  string
+		want bool
+	}{
+		{"/v1/records/details", false},
+		{"/v1/records/details?expand=enums", true},
+		{"/v1/records/details?expand=other,enums", true},
+		{"/v1/records/details?expand=other", false},
+	}
+	for _, v := range tests {
+		r := httptest.NewRequest("POST", v.url, nil)
+		got := Requested(r)
+		if got != v.want {
+			t.Errorf("%v: got %v, want %v", v.url, got, v.want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	// Verify that a reply without enums encodes to the same JSON
+	// object as the unexpanded reply. The key order of the expanded
+	// reply differs since the structs are converted to maps.
+	tr := rcv1.TimestampsReply{
+		RecordMetadata: rcv1.Timestamp{
+			Data:   "data",
+			Digest: "digest",
+			Proofs: []rcv1.Proof{{Type: "type", MerklePath: []string{"a"}}},
+		},
+		Files: map[string]rcv1.Timestamp{"index.md": {}},
+	}
+	want, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(Expand(tr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotv, wantv interface{}
+	err = json.Unmarshal(got, &gotv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(want, &wantv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotv, wantv) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Verify that the enums are expanded
+	sr := tkv1.SummariesReply{
+		Summaries: map[string]tkv1.Summary{
+			"token": {
+				Type:   tkv1.VoteTypeStandard,
+				Status: tkv1.VoteStatusApproved,
+			},
+		},
+	}
+	b, err := json.Marshal(Expand(sr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expanded struct {
+		Summaries map[string]struct {
+			Type   www.Enum `json:"type"`
+			Status www.Enum `json:"status"`
+		} `json:"summaries"`
+	}
+	err = json.Unmarshal(b, &expanded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := expanded.Summaries["token"]
+	wantStatus := www.Enum{
+		Code: uint32(tkv1.VoteStatusApproved),
+		ID:   "approved",
+	}
+	if s.Status != wantStatus {
+		t.Errorf("got status %+v, want %+v", s.Status, wantStatus)
+	}
+	if s.Type.ID != tkv1.VoteTypes[tkv1.VoteTypeStandard] {
+		t.Errorf("got type %+v, want %v", s.Type,
+			tkv1.VoteTypes[tkv1.VoteTypeStandard])
+	}
+}
@@ -63,6 +63,7 @@ func RespondWithError(w http.ResponseWriter, r *http.Request, userHttpCode int,
 			www.UserError{
 				ErrorCode:    userErr.ErrorCode,
 				ErrorContext: userErr.ErrorContext,
+				ErrorParams:  userErr.ErrorParams,
 			})
 		return
 	}
@@ -829,13 +829,13 @@ func (p *Politeiawww) validateInvoice(ni cms.NewInvoice, u *user.CMSUser) error
 	}
 
 	// Verify public key
-	if u.PublicKey() != ni.PublicKey {
+	if !u.IsActivePublicKey(ni.PublicKey) {
 		return www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
 	}
 
-	pk, err := identity.PublicIdentityFromBytes(u.ActiveIdentity().Key[:])
+	pk, err := identity.PublicIdentityFromString(ni.PublicKey)
 	if err != nil {
 		return err
 	}
@@ -1233,7 +1233,7 @@ func (p *Politeiawww) processSetInvoiceStatus(ctx context.Context, sis cms.SetIn
 	}
 
 	// Ensure the provided public key is the user's active key.
-	if sis.PublicKey != u.PublicKey() {
+	if !u.IsActivePublicKey(sis.PublicKey) {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
@@ -1264,7 +1264,7 @@ func (p *Politeiawww) processSetInvoiceStatus(ctx context.Context, sis cms.SetIn
 	// Create the change record.
 	c := mdstream.InvoiceStatusChange{
 		Version:        mdstream.VersionInvoiceStatusChange,
-		AdminPublicKey: u.PublicKey(),
+		AdminPublicKey: sis.PublicKey,
 		Timestamp:      time.Now().Unix(),
 		NewStatus:      sis.Status,
 		Reason:         sis.Reason,
@@ -1940,7 +1940,7 @@ func (p *Politeiawww) processNewCommentInvoice(ctx context.Context, nc www.NewCo
 	}
 
 	// Ensure the public key is the user's active key
-	if nc.PublicKey != u.PublicKey() {
+	if !u.IsActivePublicKey(nc.PublicKey) {
 		return nil, www.UserError{
 			ErrorCode: www.ErrorStatusInvalidSigningKey,
 		}
@@ -290,29 +290,42 @@ func (p *Politeiawww) verifyProposalPayment(ctx context.Context, u *user.User) (
 			return &tx, nil
 		default:
 			// Payment tx found that meets all criteria. Create
-			// proposal credits and update user db record.
-			paywall.TxID = tx.TxID
-			paywall.TxAmount = tx.Amount
-			paywall.NumCredits = tx.Amount / paywall.CreditPrice
-
-			// Create proposal credits
-			c := make([]user.ProposalCredit, paywall.NumCredits)
-			timestamp := time.Now().Unix()
-			for i := uint64(0); i < paywall.NumCredits; i++ {
-				c[i] = user.ProposalCredit{
-					PaywallID:     paywall.ID,
-					Price:         paywall.CreditPrice,
-					DatePurchased: timestamp,
-					TxID:          paywall.TxID,
-				}
-			}
-			u.UnspentProposalCredits = append(u.UnspentProposalCredits, c...)
-
-			// Update user database.
-			err = p.db.UserUpdate(*u)
+			// proposal credits and update user db record. The user
+			// is relooked up while the user's proposal credits are
+			// locked so that concurrent credit changes are not
+			// overwritten.
+			var dbu *user.User
+			err = user.ProposalCreditsUpdate(p.db, []uuid.UUID{u.ID},
+				func(users []*user.User) error {
+					dbu = users[0]
+					pw := p.mostRecentProposalPaywall(dbu)
+					if pw == nil || pw.ID != paywall.ID || pw.TxID != "" {
+						// The payment has already been verified
+						return nil
+					}
+					pw.TxID = tx.TxID
+					pw.TxAmount = tx.Amount
+					pw.NumCredits = tx.Amount / pw.CreditPrice
+
+					// Create proposal credits
+					c := make([]user.ProposalCredit, pw.NumCredits)
+					timestamp := time.Now().Unix()
+					for i := uint64(0); i < pw.NumCredits; i++ {
+						c[i] = user.ProposalCredit{
+							PaywallID:     pw.ID,
+							Price:         pw.CreditPrice,
+							DatePurchased: timestamp,
+							TxID:          pw.TxID,
+						}
+					}
+					dbu.UnspentProposalCredits = append(dbu.UnspentProposalCredits,
+						c...)
+					return nil
+				})
 			if err != nil {
 				return nil, fmt.Errorf("database UserUpdate: %v", err)
 			}
+			*u = *dbu
 
 			return &tx, nil
 		}
@@ -0,0 +1,339 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/util"
+	"github.com/google/uuid"
+)
+
+// handlePendingActions handles the incoming pending actions command. It
+// returns the destructive admin actions that require the confirmation of a
+// second admin.
+func (p *Politeiawww) handlePendingActions(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePendingActions")
+
+	var pa www.PendingActions
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pa); err != nil {
+		RespondWithError(w, r, 0, "handlePendingActions: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	par, err := p.processPendingActions(pa)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePendingActions: processPendingActions %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, par)
+}
+
+// handlePendingActionConfirm handles the incoming pending action confirm
+// command. It executes a pending admin action once it has been confirmed by a
+// second admin.
+func (p *Politeiawww) handlePendingActionConfirm(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePendingActionConfirm")
+
+	var pac www.PendingActionConfirm
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pac); err != nil {
+		RespondWithError(w, r, 0, "handlePendingActionConfirm: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	adminUser, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePendingActionConfirm: getSessionUser %v", err)
+		return
+	}
+
+	pacr, err := p.processPendingActionConfirm(r.Context(), pac, adminUser)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePendingActionConfirm: processPendingActionConfirm %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, pacr)
+}
+
+// handlePendingActionReject handles the incoming pending action reject
+// command.
+func (p *Politeiawww) handlePendingActionReject(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePendingActionReject")
+
+	var par www.PendingActionReject
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&par); err != nil {
+		RespondWithError(w, r, 0, "handlePendingActionReject: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	adminUser, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePendingActionReject: getSessionUser %v", err)
+		return
+	}
+
+	parr, err := p.processPendingActionReject(par, adminUser)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePendingActionReject: processPendingActionReject %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, parr)
+}
+
+// processPendingActions returns the pending admin actions that have the
+// requested status. Actions that are awaiting confirmation are returned if a
+// status is not provided.
+func (p *Politeiawww) processPendingActions(pa www.PendingActions) (*www.PendingActionsReply, error) {
+	log.Tracef("processPendingActions: %v", pa.Status)
+
+	status := pa.Status
+	if status == www.PendingActionStatusInvalid {
+		status = www.PendingActionStatusPending
+	}
+
+	all, err := p.pendingActions.PendingActionsGetAll()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	actions := make([]www.PendingAction, 0, len(all))
+	for _, v := range all {
+		a := convertPendingActionToWWW(v, now)
+		if a.Status != status {
+			continue
+		}
+		actions = append(actions, a)
+	}
+
+	return &www.PendingActionsReply{
+		PendingActions: actions,
+	}, nil
+}
+
+// processPendingActionConfirm confirms and executes a pending admin action.
+// The action must be confirmed prior to its expiry by an admin other than the
+// admin that requested it.
+func (p *Politeiawww) processPendingActionConfirm(ctx context.Context, pac www.PendingActionConfirm, adminUser *user.User) (*www.PendingActionConfirmReply, error) {
+	log.Tracef("processPendingActionConfirm: %v %v", pac.ID, adminUser.ID)
+
+	// Prevent the same action from being resolved concurrently
+	p.pendingActionsMtx.Lock()
+	defer p.pendingActionsMtx.Unlock()
+
+	pa, err := p.pendingActionGet(pac.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if now > pa.ExpiresAt {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusPendingActionExpired,
+		}
+	}
+	if pa.RequestedBy == adminUser.ID {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusPendingActionSameAdmin,
+		}
+	}
+
+	// Execute the action
+	switch pa.Type {
+	case user.PendingActionTypeCensorRecord:
+		if p.records == nil {
+			return nil, fmt.Errorf("records api not available")
+		}
+		err = p.records.PendingActionExec(ctx, *pa)
+		if err != nil {
+			var ue rcv1.UserErrorReply
+			if errors.As(err, &ue) {
+				return nil, convertRecordsUserError(ue)
+			}
+			return nil, err
+		}
+
+	case user.PendingActionTypeDeactivateUser:
+		var mu www.ManageUser
+		err = json.Unmarshal([]byte(pa.Payload), &mu)
+		if err != nil {
+			return nil, err
+		}
+		u, err := p.userByIDStr(mu.UserID)
+		if err != nil {
+			return nil, err
+		}
+		u.Deactivated = true
+		err = p.db.UserUpdate(*u)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid pending action type %v", pa.Type)
+	}
+
+	// Update the action status
+	pa.Status = user.PendingActionStatusConfirmed
+	pa.ResolvedBy = adminUser.ID
+	pa.ResolvedAt = now
+	err = p.pendingActions.PendingActionSave(*pa)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Pending action %v confirmed by %v", pa.ID, adminUser.Username)
+
+	return &www.PendingActionConfirmReply{
+		PendingAction: convertPendingActionToWWW(*pa, now),
+	}, nil
+}
+
+// processPendingActionReject rejects a pending admin action. Any admin can
+// reject a pending action, including the admin that requested it.
+func (p *Politeiawww) processPendingActionReject(par www.PendingActionReject, adminUser *user.User) (*www.PendingActionRejectReply, error) {
+	log.Tracef("processPendingActionReject: %v %v", par.ID, adminUser.ID)
+
+	// Prevent the same action from being resolved concurrently
+	p.pendingActionsMtx.Lock()
+	defer p.pendingActionsMtx.Unlock()
+
+	pa, err := p.pendingActionGet(par.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	pa.Status = user.PendingActionStatusRejected
+	pa.ResolvedBy = adminUser.ID
+	pa.ResolvedAt = now
+	err = p.pendingActions.PendingActionSave(*pa)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Pending action %v rejected by %v", pa.ID, adminUser.Username)
+
+	return &www.PendingActionRejectReply{
+		PendingAction: convertPendingActionToWWW(*pa, now),
+	}, nil
+}
+
+// pendingActionGet returns the pending action for the provided ID. An error
+// is returned if the action does not exist or has already been resolved.
+func (p *Politeiawww) pendingActionGet(id string) (*user.PendingAction, error) {
+	paID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusPendingActionNotFound,
+		}
+	}
+	pa, err := p.pendingActions.PendingActionGet(paID)
+	if err != nil {
+		if errors.Is(err, user.ErrPendingActionNotFound) {
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusPendingActionNotFound,
+			}
+		}
+		return nil, err
+	}
+	if pa.Status != user.PendingActionStatusPending {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusPendingActionResolved,
+		}
+	}
+	return pa, nil
+}
+
+// pendingActionDeactivateUser saves a pending action to deactivate the user
+// specified in the provided ManageUser command. The ID of the pending action
+// is returned.
+func (p *Politeiawww) pendingActionDeactivateUser(mu www.ManageUser, adminUser *user.User) (string, error) {
+	b, err := json.Marshal(mu)
+	if err != nil {
+		return "", err
+	}
+	window := time.Duration(p.cfg.AdminConfirmWindow) * time.Second
+	pa := user.NewPendingAction(user.PendingActionTypeDeactivateUser,
+		string(b), adminUser.ID, window)
+	err = p.pendingActions.PendingActionSave(pa)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("User deactivation %v requested by %v", pa.ID,
+		adminUser.Username)
+
+	return pa.ID.String(), nil
+}
+
+// convertPendingActionToWWW converts a user database pending action into a
+// www pending action. Pending actions that were not confirmed prior to their
+// expiry are returned with an expired status.
+func convertPendingActionToWWW(pa user.PendingAction, now int64) www.PendingAction {
+	status := www.PendingActionStatusT(pa.Status)
+	if pa.Status == user.PendingActionStatusPending && now > pa.ExpiresAt {
+		status = www.PendingActionStatusExpired
+	}
+	var resolvedBy string
+	if pa.ResolvedBy != uuid.Nil {
+		resolvedBy = pa.ResolvedBy.String()
+	}
+	return www.PendingAction{
+		ID:          pa.ID.String(),
+		Type:        www.PendingActionT(pa.Type),
+		Status:      status,
+		Payload:     pa.Payload,
+		RequestedBy: pa.RequestedBy.String(),
+		ResolvedBy:  resolvedBy,
+		CreatedAt:   pa.CreatedAt,
+		ExpiresAt:   pa.ExpiresAt,
+		ResolvedAt:  pa.ResolvedAt,
+	}
+}
+
+// convertRecordsUserError converts a records API user error into a www user
+// error. The records error code and context are included in the error
+// context.
+func convertRecordsUserError(ue rcv1.UserErrorReply) www.UserError {
+	ec := []string{
+		"records error code " + strconv.Itoa(int(ue.ErrorCode)),
+		rcv1.ErrorCodes[ue.ErrorCode],
+	}
+	if ue.ErrorContext != "" {
+		ec = append(ec, ue.ErrorContext)
+	}
+	return www.UserError{
+		ErrorCode:    www.ErrorStatusInvalidInput,
+		ErrorContext: ec,
+	}
+}
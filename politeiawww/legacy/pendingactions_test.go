@@ -0,0 +1,166 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"context"
+	"testing"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+)
+
+func TestProcessPendingActionConfirm(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	p.cfg.AdminConfirm = true
+	p.cfg.AdminConfirmWindow = 3600
+
+	usr, _ := newUser(t, p, true, false)
+	admin1, _ := newUser(t, p, true, true)
+	admin2, _ := newUser(t, p, true, true)
+
+	// Request a user deactivation. The user should not be
+	// deactivated until the action has been confirmed.
+	mu := www.ManageUser{
+		UserID: usr.ID.String(),
+		Action: www.UserManageDeactivate,
+		Reason: "reason",
+	}
+	mur, err := p.processManageUser(&mu, admin1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mur.PendingActionID == "" {
+		t.Fatalf("pending action id not returned")
+	}
+	u, err := p.db.UserGetById(usr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Deactivated {
+		t.Fatalf("user deactivated prior to confirmation")
+	}
+
+	// Verify the pending action is returned
+	par, err := p.processPendingActions(www.PendingActions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(par.PendingActions) != 1 ||
+		par.PendingActions[0].ID != mur.PendingActionID {
+		t.Fatalf("got pending actions %+v, want %v",
+			par.PendingActions, mur.PendingActionID)
+	}
+
+	ctx := context.Background()
+	var tests = []struct {
+		name string
+		id   string
+		want error
+	}{
+		{
+			"not found",
+			"invalid",
+			www.UserError{
+				ErrorCode: www.ErrorStatusPendingActionNotFound,
+			},
+		},
+		{
+			"same admin",
+			mur.PendingActionID,
+			www.UserError{
+				ErrorCode: www.ErrorStatusPendingActionSameAdmin,
+			},
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			pac := www.PendingActionConfirm{ID: v.id}
+			_, err := p.processPendingActionConfirm(ctx, pac, admin1)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Confirm the action using a second admin
+	pac := www.PendingActionConfirm{ID: mur.PendingActionID}
+	pacr, err := p.processPendingActionConfirm(ctx, pac, admin2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pacr.PendingAction.Status != www.PendingActionStatusConfirmed {
+		t.Errorf("got status %v, want %v", pacr.PendingAction.Status,
+			www.PendingActionStatusConfirmed)
+	}
+	u, err = p.db.UserGetById(usr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.Deactivated {
+		t.Errorf("user not deactivated")
+	}
+
+	// A resolved action can not be confirmed again
+	_, err = p.processPendingActionConfirm(ctx, pac, admin2)
+	got := errToStr(err)
+	want := errToStr(www.UserError{
+		ErrorCode: www.ErrorStatusPendingActionResolved,
+	})
+	if got != want {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
+
+func TestProcessPendingActionReject(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	p.cfg.AdminConfirm = true
+	p.cfg.AdminConfirmWindow = 3600
+
+	usr, _ := newUser(t, p, true, false)
+	admin, _ := newUser(t, p, true, true)
+
+	mu := www.ManageUser{
+		UserID: usr.ID.String(),
+		Action: www.UserManageDeactivate,
+		Reason: "reason",
+	}
+	mur, err := p.processManageUser(&mu, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The requesting admin is allowed to reject the action
+	par := www.PendingActionReject{ID: mur.PendingActionID}
+	parr, err := p.processPendingActionReject(par, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parr.PendingAction.Status != www.PendingActionStatusRejected {
+		t.Errorf("got status %v, want %v", parr.PendingAction.Status,
+			www.PendingActionStatusRejected)
+	}
+	u, err := p.db.UserGetById(usr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Deactivated {
+		t.Errorf("user deactivated after rejection")
+	}
+
+	// Rejected actions are not returned by default
+	pas, err := p.processPendingActions(www.PendingActions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pas.PendingActions) != 0 {
+		t.Errorf("got %v pending actions, want 0", len(pas.PendingActions))
+	}
+}
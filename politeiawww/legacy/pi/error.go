@@ -64,6 +64,7 @@ func respondWithError(w http.ResponseWriter, r *http.Request, format string, err
 				PluginID:     pe.PluginID,
 				ErrorCode:    pe.ErrorCode,
 				ErrorContext: pe.ErrorContext,
+				ErrorParams:  pe.ErrorParams,
 			})
 		return
 
@@ -102,6 +103,7 @@ func handlePDError(w http.ResponseWriter, r *http.Request, format string, pde pd
 		pluginID   = pde.ErrorReply.PluginID
 		errCode    = pde.ErrorReply.ErrorCode
 		errContext = pde.ErrorReply.ErrorContext
+		errParams  = pde.ErrorReply.ErrorParams
 	)
 	e := convertPDErrorCode(errCode)
 	switch {
@@ -118,6 +120,7 @@ func handlePDError(w http.ResponseWriter, r *http.Request, format string, pde pd
 				PluginID:     pluginID,
 				ErrorCode:    errCode,
 				ErrorContext: errContext,
+				ErrorParams:  errParams,
 			})
 		return
 
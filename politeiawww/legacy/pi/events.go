@@ -385,13 +385,33 @@ func (p *Pi) ntfnCommentReply(c cmv1.Comment, proposalName string) error {
 		return err
 	}
 
+	// Author replied to their own comment
+	if c.UserID == pauthor.ID.String() {
+		log.Debugf("Comment reply ntfn to parent author not needed %v", c.Token)
+		return nil
+	}
+
+	// Send push notifications. Push notifications are managed using
+	// push subscriptions and are independent of the email notification
+	// settings.
+	pushBit := uint64(www.NotificationPushCommentReply)
+	subs := pauthor.PushSubscriptionsEnabled(pushBit)
+	if len(subs) > 0 {
+		pushRecipient := map[uuid.UUID][]user.PushSubscription{
+			pauthor.ID: subs,
+		}
+		err = p.pushNtfnCommentReply(c.Token, c.CommentID,
+			c.Username, proposalName, pushRecipient)
+		if err != nil {
+			// Log the error and continue. This error should not
+			// prevent the email notification from being sent.
+			log.Errorf("pushNtfnCommentReply: %v", err)
+		}
+	}
+
 	// Check if notification should be sent
 	ntfnBit := uint64(www.NotificationEmailCommentOnMyComment)
 	switch {
-	case c.UserID == pauthor.ID.String():
-		// Author replied to their own comment
-		log.Debugf("Comment reply ntfn to parent author not needed %v", c.Token)
-		return nil
 	case !pauthor.NotificationIsEnabled(ntfnBit):
 		// Author does not have notification bit set
 		log.Debugf("Comment reply ntfn to parent author not enabled %v", c.Token)
@@ -413,6 +433,89 @@ func (p *Pi) ntfnCommentReply(c cmv1.Comment, proposalName string) error {
 	return nil
 }
 
+func (p *Pi) ntfnCommentThread(c cmv1.Comment, proposalName string) error {
+	// Verify there is work to do. Only replies are part of a comment
+	// thread.
+	if c.ParentID == 0 {
+		log.Debugf("Comment thread ntfn not needed %v", c.Token)
+		return nil
+	}
+
+	// Get the users that are subscribed to the thread of the parent
+	// comment
+	s := cmplugin.Subscribers{
+		CommentID: c.ParentID,
+	}
+	userIDs, err := p.politeiad.CommentSubscribers(context.Background(),
+		c.Token, s)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		log.Debugf("Comment thread ntfn has no subscribers %v", c.Token)
+		return nil
+	}
+
+	// Get the parent comment author. The parent comment author is
+	// notified by the comment reply notification when it is enabled.
+	g := cmplugin.Get{
+		CommentIDs: []uint32{c.ParentID},
+	}
+	cs, err := p.politeiad.CommentsGet(context.Background(), c.Token, g)
+	if err != nil {
+		return err
+	}
+	parent, ok := cs[c.ParentID]
+	if !ok {
+		return fmt.Errorf("parent comment %v not found", c.ParentID)
+	}
+
+	// Compile the notification email list
+	var (
+		recipients = make(map[uuid.UUID]string, len(userIDs))
+		replyBit   = uint64(www.NotificationEmailCommentOnMyComment)
+	)
+	for _, v := range userIDs {
+		if v == c.UserID {
+			// Users are not notified of their own replies
+			continue
+		}
+		uid, err := uuid.Parse(v)
+		if err != nil {
+			return err
+		}
+		u, err := p.userdb.UserGetById(uid)
+		if err != nil {
+			return fmt.Errorf("UserGetById %v: %v", v, err)
+		}
+		switch {
+		case u.Deactivated:
+			// User has been deactivated
+			continue
+		case v == parent.UserID && u.NotificationIsEnabled(replyBit):
+			// User was notified by the comment reply notification
+			continue
+		}
+		recipients[u.ID] = u.Email
+	}
+	if len(recipients) == 0 {
+		log.Debugf("Comment thread ntfn not needed %v", c.Token)
+		return nil
+	}
+
+	// Send notification email
+	err = p.mailNtfnCommentThread(c.Token, c.CommentID,
+		c.Username, proposalName, recipients)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Comment thread ntfn sent to %v subscribers %v",
+		len(recipients), c.Token)
+
+	return nil
+}
+
 func (p *Pi) handleEventCommentNew(ch chan interface{}) {
 	for msg := range ch {
 		e, ok := msg.(comments.EventNew)
@@ -449,7 +552,15 @@ func (p *Pi) handleEventCommentNew(ch chan interface{}) {
 		// Notify the parent comment author
 		err = p.ntfnCommentReply(e.Comment, proposalName)
 		if err != nil {
-			err = fmt.Errorf("ntfnCommentReply: %v", err)
+			// Log error and continue. This error should not prevent the
+			// comment thread subscribers from being notified.
+			log.Errorf("ntfnCommentReply: %v", err)
+		}
+
+		// Notify the users that are subscribed to the comment thread
+		err = p.ntfnCommentThread(e.Comment, proposalName)
+		if err != nil {
+			err = fmt.Errorf("ntfnCommentThread: %v", err)
 			goto failed
 		}
 
@@ -570,16 +681,27 @@ func (p *Pi) ntfnVoteStarted(sd tkv1.StartDetails, eventUser user.User, authorID
 	var (
 		token   = sd.Params.Token
 		ntfnBit = uint64(www.NotificationEmailRegularProposalVoteStarted)
+		pushBit = uint64(www.NotificationPushVoteStarted)
 	)
 
-	// Compile user notification list
+	// Compile user notification lists
 	recipients := make(map[uuid.UUID]string, 1024)
+	pushRecipients := make(map[uuid.UUID][]user.PushSubscription, 256)
 	err := p.userdb.AllUsers(func(u *user.User) {
-		switch {
-		case u.ID.String() == eventUser.ID.String():
+		if u.ID.String() == eventUser.ID.String() {
 			// Don't send a notification to the user that sent the request
 			// to start the vote.
 			return
+		}
+
+		// Push notifications are sent to all users that have a push
+		// subscription for this notification, including the author.
+		subs := u.PushSubscriptionsEnabled(pushBit)
+		if len(subs) > 0 {
+			pushRecipients[u.ID] = subs
+		}
+
+		switch {
 		case u.ID.String() == authorID:
 			// Don't send the notification to the author. They are sent a
 			// separate notification.
@@ -596,6 +718,14 @@ func (p *Pi) ntfnVoteStarted(sd tkv1.StartDetails, eventUser user.User, authorID
 		return fmt.Errorf("AllUsers: %v", err)
 	}
 
+	// Send push notifications
+	err = p.pushNtfnVoteStarted(token, proposalName, pushRecipients)
+	if err != nil {
+		// Log the error and continue. This error should not prevent
+		// the email notifications from being sent.
+		log.Errorf("pushNtfnVoteStarted: %v", err)
+	}
+
 	// Email users
 	err = p.mailNtfnVoteStarted(token, proposalName, recipients)
 	if err != nil {
@@ -738,6 +868,7 @@ func convertFilesToV1(f []pdv2.File) []rcv1.File {
 			MIME:    v.MIME,
 			Digest:  v.Digest,
 			Payload: v.Payload,
+			CID:     v.CID,
 		})
 	}
 	return files
@@ -304,6 +304,47 @@ func (p *Pi) mailNtfnCommentReply(token string, commentID uint32, commentUsernam
 	return p.mail.SendToUsers(subject, body, recipient)
 }
 
+type commentThread struct {
+	Username string // Comment author username
+	Name     string // Proposal name
+	Link     string // Comment link
+}
+
+var commentThreadText = `
+{{.Username}} has replied to a comment thread that you are subscribed to on
+"{{.Name}}".
+
+{{.Link}}
+`
+
+var commentThreadTmpl = template.Must(
+	template.New("commentThread").Parse(commentThreadText))
+
+func (p *Pi) mailNtfnCommentThread(token string, commentID uint32, commentUsername, proposalName string, recipients map[uuid.UUID]string) error {
+	cid := strconv.FormatUint(uint64(commentID), 10)
+	route := strings.Replace(guiRouteRecordComment, "{token}", token, 1)
+	route = strings.Replace(route, "{id}", cid, 1)
+
+	u, err := url.Parse(p.cfg.WebServerAddress + route)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf(`New Reply in a Comment Thread on "%v"`,
+		proposalName)
+	tmplData := commentThread{
+		Username: commentUsername,
+		Name:     proposalName,
+		Link:     u.String(),
+	}
+	body, err := populateTemplate(commentThreadTmpl, tmplData)
+	if err != nil {
+		return err
+	}
+
+	return p.mail.SendToUsers(subject, body, recipients)
+}
+
 type voteAuthorized struct {
 	Name string // Proposal name
 	Link string // GUI proposal details url
@@ -14,11 +14,12 @@ import (
 	"github.com/decred/politeia/politeiad/plugins/pi"
 	v1 "github.com/decred/politeia/politeiawww/api/pi/v1"
 	"github.com/decred/politeia/politeiawww/config"
+	"github.com/decred/politeia/politeiawww/legacy/enums"
 	"github.com/decred/politeia/politeiawww/legacy/events"
 	"github.com/decred/politeia/politeiawww/legacy/mail"
 	"github.com/decred/politeia/politeiawww/legacy/sessions"
 	"github.com/decred/politeia/politeiawww/legacy/user"
-	"github.com/decred/politeia/util"
+	"github.com/decred/politeia/politeiawww/legacy/webpush"
 	"github.com/pkg/errors"
 )
 
@@ -28,16 +29,21 @@ type Pi struct {
 	politeiad *pdclient.Client
 	userdb    user.Database
 	mail      mail.Mailer
+	push      webpush.Pusher
 	sessions  *sessions.Sessions
 	events    *events.Manager
 	policy    *v1.PolicyReply
+
+	// proposalList caches the proposal list items that are returned
+	// by the ProposalList route.
+	proposalList *proposalListCache
 }
 
 // HandlePolicy is the request handler for the pi v1 Policy route.
 func (p *Pi) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, p.policy)
+	enums.RespondWithJSON(w, r, http.StatusOK, p.policy)
 }
 
 // HandleSetBillingStatus is the request handler for the pi v1 BillingStatus
@@ -69,7 +75,7 @@ func (p *Pi) HandleSetBillingStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, bsr)
+	enums.RespondWithJSON(w, r, http.StatusOK, bsr)
 }
 
 // HandleBillingStatusChanges is the request handler for the pi v1
@@ -94,7 +100,7 @@ func (p *Pi) HandleBillingStatusChanges(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, bsr)
+	enums.RespondWithJSON(w, r, http.StatusOK, bsr)
 
 }
 
@@ -119,11 +125,140 @@ func (p *Pi) HandleSummaries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, bsr)
+	enums.RespondWithJSON(w, r, http.StatusOK, bsr)
+}
+
+// HandleProposalList is the request handler for the pi v1 ProposalList
+// route.
+func (p *Pi) HandleProposalList(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleProposalList")
+
+	var pl v1.ProposalList
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pl); err != nil {
+		respondWithError(w, r, "HandleProposalList: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := p.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleProposalList: GetSessionUser: %v", err)
+		return
+	}
+
+	plr, err := p.processProposalList(r.Context(), pl, u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleProposalList: processProposalList: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, plr)
+}
+
+// HandleIdentity is the request handler for the pi v1 Identity route.
+func (p *Pi) HandleIdentity(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleIdentity")
+
+	ir, err := p.processIdentity(r.Context())
+	if err != nil {
+		respondWithError(w, r,
+			"HandleIdentity: processIdentity: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, ir)
+}
+
+// HandleCompletionReport is the request handler for the pi v1
+// CompletionReport route.
+func (p *Pi) HandleCompletionReport(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleCompletionReport")
+
+	var cr v1.CompletionReport
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cr); err != nil {
+		respondWithError(w, r, "HandleCompletionReport: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	crr, err := p.processCompletionReport(r.Context(), cr)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleCompletionReport: processCompletionReport: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, crr)
+}
+
+// HandleWithdraw is the request handler for the pi v1 Withdraw route.
+func (p *Pi) HandleWithdraw(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleWithdraw")
+
+	var wd v1.Withdraw
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&wd); err != nil {
+		respondWithError(w, r, "HandleWithdraw: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleWithdraw: GetSessionUser: %v", err)
+		return
+	}
+
+	wr, err := p.processWithdraw(r.Context(), wd, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleWithdraw: processWithdraw: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, wr)
+}
+
+// HandleWithdrawalDetails is the request handler for the pi v1 Withdrawal
+// route.
+func (p *Pi) HandleWithdrawalDetails(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleWithdrawalDetails")
+
+	var wd v1.WithdrawalDetails
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&wd); err != nil {
+		respondWithError(w, r, "HandleWithdrawalDetails: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	wdr, err := p.processWithdrawalDetails(r.Context(), wd)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleWithdrawalDetails: processWithdrawalDetails: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, wdr)
 }
 
 // New returns a new Pi context.
-func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, m mail.Mailer, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*Pi, error) {
+func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, m mail.Mailer, wp webpush.Pusher, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*Pi, error) {
 	// Parse plugin settings
 	var (
 		textFileSizeMax              uint32
@@ -140,6 +275,11 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, m mail.Mai
 		billingStatusChangesMax      uint32
 		summariesPageSize            uint32
 		billingStatusChangesPageSize uint32
+		reasonRequired               = make([]v1.BillingStatusT, 0, 2)
+		abandonReasonLengthMin       uint32
+		commentLengthMaxUnvetted     uint32
+		commentLengthMaxVetted       uint32
+		commentLockDays              uint32
 	)
 	for _, p := range plugins {
 		if p.ID != pi.PluginID {
@@ -251,6 +391,58 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, m mail.Mai
 				}
 				billingStatusChangesPageSize = uint32(u)
 
+			case pi.SettingKeyStatusCacheWarm:
+				// This setting is only used by politeiad
+
+			case pi.SettingKeyWebhookURLs:
+				// This setting is only used by politeiad
+
+			case pi.SettingKeyFsckRepair:
+				// This setting is only used by politeiad
+
+			case pi.SettingKeyAbandonReasonLengthMin:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				abandonReasonLengthMin = uint32(u)
+
+			case pi.SettingKeyCommentLengthMaxUnvetted:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				commentLengthMaxUnvetted = uint32(u)
+
+			case pi.SettingKeyCommentLengthMaxVetted:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				commentLengthMaxVetted = uint32(u)
+
+			case pi.SettingKeyCommentLockDays:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				commentLockDays = uint32(u)
+
+			case pi.SettingKeyBillingStatusReasonRequired:
+				var names []string
+				err := json.Unmarshal([]byte(v.Value), &names)
+				if err != nil {
+					return nil, err
+				}
+				for _, name := range names {
+					s := convertBillingStatusFromName(name)
+					if s == v1.BillingStatusInvalid {
+						return nil, errors.Errorf("invalid billing status "+
+							"'%v' in plugin setting %v", name, v.Key)
+					}
+					reasonRequired = append(reasonRequired, s)
+				}
+
 			default:
 				// Skip unknown settings
 				log.Warnf("Unknown plugin setting %v; Skipping...", v.Key)
@@ -306,6 +498,7 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, m mail.Mai
 		sessions:  s,
 		events:    e,
 		mail:      m,
+		push:      wp,
 		policy: &v1.PolicyReply{
 			TextFileSizeMax:              textFileSizeMax,
 			ImageFileCountMax:            imageFileCountMax,
@@ -321,7 +514,13 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, m mail.Mai
 			SummariesPageSize:            summariesPageSize,
 			BillingStatusChangesPageSize: billingStatusChangesPageSize,
 			BillingStatusChangesMax:      billingStatusChangesMax,
+			BillingStatusReasonRequired:  reasonRequired,
+			AbandonReasonLengthMin:       abandonReasonLengthMin,
+			CommentLengthMaxUnvetted:     commentLengthMaxUnvetted,
+			CommentLengthMaxVetted:       commentLengthMaxVetted,
+			CommentLockDays:              commentLockDays,
 		},
+		proposalList: newProposalListCache(),
 	}
 
 	// Setup event listeners
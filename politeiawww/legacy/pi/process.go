@@ -24,7 +24,7 @@ func (p *Pi) processSetBillingStatus(ctx context.Context, sbs v1.SetBillingStatu
 	}
 
 	// Verify user signed with their active identity
-	if u.PublicKey() != sbs.PublicKey {
+	if !u.IsActivePublicKey(sbs.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
@@ -110,15 +110,48 @@ func (p *Pi) processSummaries(ctx context.Context, s v1.Summaries) (*v1.Summarie
 	}, nil
 }
 
+// processIdentity processes a pi v1 identity request.
+func (p *Pi) processIdentity(ctx context.Context) (*v1.IdentityReply, error) {
+	log.Tracef("processIdentity")
+
+	pk, err := p.politeiad.PiIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.IdentityReply{
+		PublicKey: pk,
+	}, nil
+}
+
+// processCompletionReport processes a pi v1 completionreport request.
+func (p *Pi) processCompletionReport(ctx context.Context, cr v1.CompletionReport) (*v1.CompletionReportReply, error) {
+	log.Tracef("processCompletionReport: %v", cr.Token)
+
+	r, err := p.politeiad.PiCompletionReport(ctx, cr.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	var crr v1.CompletionReportReply
+	if r != nil {
+		rf := convertCompletionReportToAPI(*r)
+		crr.Report = &rf
+	}
+
+	return &crr, nil
+}
+
 func convertBillingStatusChangeToAPI(bsc pi.BillingStatusChange) v1.BillingStatusChange {
 	return v1.BillingStatusChange{
-		Token:     bsc.Token,
-		Status:    convertBillingStatusToAPI(bsc.Status),
-		Reason:    bsc.Reason,
-		PublicKey: bsc.PublicKey,
-		Signature: bsc.Signature,
-		Receipt:   bsc.Receipt,
-		Timestamp: bsc.Timestamp,
+		Token:        bsc.Token,
+		Status:       convertBillingStatusToAPI(bsc.Status),
+		Reason:       bsc.Reason,
+		ReportDigest: bsc.ReportDigest,
+		PublicKey:    bsc.PublicKey,
+		Signature:    bsc.Signature,
+		Receipt:      bsc.Receipt,
+		Timestamp:    bsc.Timestamp,
 	}
 }
 
@@ -135,15 +168,34 @@ func convertBillingStatusToAPI(bs pi.BillingStatusT) v1.BillingStatusT {
 }
 
 func convertSetBillingStatusToPlugin(sbs v1.SetBillingStatus) pi.SetBillingStatus {
+	var r *pi.CompletionReport
+	if sbs.Report != nil {
+		r = &pi.CompletionReport{
+			Name:    sbs.Report.Name,
+			MIME:    sbs.Report.MIME,
+			Digest:  sbs.Report.Digest,
+			Payload: sbs.Report.Payload,
+		}
+	}
 	return pi.SetBillingStatus{
 		Token:     sbs.Token,
 		Status:    convertBillingStatusToPlugin(sbs.Status),
 		Reason:    sbs.Reason,
+		Report:    r,
 		PublicKey: sbs.PublicKey,
 		Signature: sbs.Signature,
 	}
 }
 
+func convertCompletionReportToAPI(r pi.CompletionReport) v1.ReportFile {
+	return v1.ReportFile{
+		Name:    r.Name,
+		MIME:    r.MIME,
+		Digest:  r.Digest,
+		Payload: r.Payload,
+	}
+}
+
 func convertBillingStatusToPlugin(bs v1.BillingStatusT) pi.BillingStatusT {
 	switch bs {
 	case v1.BillingStatusActive:
@@ -155,3 +207,14 @@ func convertBillingStatusToPlugin(bs v1.BillingStatusT) pi.BillingStatusT {
 	}
 	return pi.BillingStatusInvalid
 }
+
+// convertBillingStatusFromName converts a human readable pi plugin billing
+// status name into an API billing status.
+func convertBillingStatusFromName(name string) v1.BillingStatusT {
+	for k, v := range pi.BillingStatuses {
+		if v == name {
+			return convertBillingStatusToAPI(k)
+		}
+	}
+	return v1.BillingStatusInvalid
+}
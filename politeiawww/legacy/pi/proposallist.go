@@ -0,0 +1,249 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	piplugin "github.com/decred/politeia/politeiad/plugins/pi"
+	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
+	v1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/google/uuid"
+)
+
+const (
+	// proposalListCacheTTL is the amount of time that a proposal list
+	// item is cached in memory. The proposal list is requested on every
+	// render of the proposal list page, so the items are cached to
+	// prevent every render from hitting politeiad. The TTL is kept short
+	// since the vote results of an ongoing vote change constantly.
+	proposalListCacheTTL = 30 * time.Second
+)
+
+// proposalListCache is an in-memory cache of proposal list items. The items
+// are cached before any user specific filtering is applied.
+type proposalListCache struct {
+	sync.Mutex
+	entries map[string]proposalListEntry // [token]entry
+}
+
+// proposalListEntry is a cached proposal list item.
+type proposalListEntry struct {
+	item     v1.ProposalListItem
+	cachedAt time.Time
+}
+
+// newProposalListCache returns a new proposalListCache.
+func newProposalListCache() *proposalListCache {
+	return &proposalListCache{
+		entries: make(map[string]proposalListEntry),
+	}
+}
+
+// get returns the cached items for the provided tokens along with the tokens
+// that were not found in the cache or whose cache entry has expired.
+func (c *proposalListCache) get(tokens []string, now time.Time) (map[string]v1.ProposalListItem, []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	var (
+		items   = make(map[string]v1.ProposalListItem, len(tokens))
+		missing = make([]string, 0, len(tokens))
+	)
+	for _, token := range tokens {
+		e, ok := c.entries[token]
+		if !ok || now.Sub(e.cachedAt) > proposalListCacheTTL {
+			missing = append(missing, token)
+			continue
+		}
+		items[token] = e.item
+	}
+
+	return items, missing
+}
+
+// put adds the provided items to the cache. Expired entries are pruned from
+// the cache so that it does not grow without bound.
+func (c *proposalListCache) put(items map[string]v1.ProposalListItem, now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	for token, e := range c.entries {
+		if now.Sub(e.cachedAt) > proposalListCacheTTL {
+			delete(c.entries, token)
+		}
+	}
+	for token, item := range items {
+		c.entries[token] = proposalListEntry{
+			item:     item,
+			cachedAt: now,
+		}
+	}
+}
+
+// processProposalList processes a pi v1 proposallist request.
+func (p *Pi) processProposalList(ctx context.Context, pl v1.ProposalList, u *user.User) (*v1.ProposalListReply, error) {
+	log.Tracef("processProposalList: %v", pl.Tokens)
+
+	// Verify request size
+	if len(pl.Tokens) > int(p.policy.SummariesPageSize) {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodePageSizeExceeded,
+			ErrorContext: fmt.Sprintf("max page size is %v",
+				p.policy.SummariesPageSize),
+		}
+	}
+
+	// Get the items that are not cached from politeiad
+	now := time.Now()
+	items, missing := p.proposalList.get(pl.Tokens, now)
+	if len(missing) > 0 {
+		fetched, err := p.proposalListItems(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		p.proposalList.put(fetched, now)
+		for token, item := range fetched {
+			items[token] = item
+		}
+	}
+
+	// Only admins and the proposal author are allowed to retrieve
+	// unvetted proposal files. Remove files if the user is not an
+	// admin or the author. This is a public route so a user may not
+	// exist. The cached items are shared, so the files slice is
+	// replaced instead of modified.
+	for token, item := range items {
+		if item.Record.State == rcv1.RecordStateVetted {
+			continue
+		}
+		var (
+			authorID = userIDFromMetadata(item.Record.Metadata)
+			isAuthor = u != nil && u.ID.String() == authorID
+			isAdmin  = u != nil && u.Admin
+		)
+		if !isAuthor && !isAdmin {
+			item.Record.Files = []rcv1.File{}
+			items[token] = item
+		}
+	}
+
+	return &v1.ProposalListReply{
+		Proposals: items,
+	}, nil
+}
+
+// proposalListItems assembles the proposal list items for the provided tokens
+// from politeiad. Tokens that do not correspond to a proposal are not included
+// in the returned map.
+func (p *Pi) proposalListItems(ctx context.Context, tokens []string) (map[string]v1.ProposalListItem, error) {
+	// Get the abridged records
+	reqs := make([]pdv2.RecordRequest, 0, len(tokens))
+	for _, token := range tokens {
+		reqs = append(reqs, pdv2.RecordRequest{
+			Token: token,
+			Filenames: []string{
+				piplugin.FileNameProposalMetadata,
+				tkplugin.FileNameVoteMetadata,
+			},
+		})
+	}
+	rs, err := p.politeiad.Records(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rs) == 0 {
+		return map[string]v1.ProposalListItem{}, nil
+	}
+	found := make([]string, 0, len(rs))
+	for token := range rs {
+		found = append(found, token)
+	}
+
+	// Get the plugin data of the records
+	ps, err := p.politeiad.PiSummaries(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := p.politeiad.TicketVoteSummaries(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := p.politeiad.CommentCount(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+
+	// Assemble the items
+	items := make(map[string]v1.ProposalListItem, len(rs))
+	for token, r := range rs {
+		rc := convertRecordToV1(r)
+
+		// Fill in the user data
+		uid, err := uuid.Parse(userIDFromMetadata(rc.Metadata))
+		if err != nil {
+			return nil, err
+		}
+		u, err := p.userdb.UserGetById(uid)
+		if err != nil {
+			return nil, err
+		}
+		rc.Username = u.Username
+
+		items[token] = v1.ProposalListItem{
+			Record: rc,
+			Summary: v1.Summary{
+				Status: string(ps[token].Summary.Status),
+			},
+			VoteSummary: convertVoteSummaryToV1(vs[token]),
+			Comments:    counts[token],
+		}
+	}
+
+	return items, nil
+}
+
+func convertVoteSummaryToV1(s tkplugin.SummaryReply) tkv1.Summary {
+	results := make([]tkv1.VoteResult, 0, len(s.Results))
+	for _, v := range s.Results {
+		results = append(results, tkv1.VoteResult{
+			ID:          v.ID,
+			Description: v.Description,
+			VoteBit:     v.VoteBit,
+			Votes:       v.Votes,
+			Stake:       v.Stake,
+		})
+	}
+	return tkv1.Summary{
+		Type:             tkv1.VoteT(s.Type),
+		Status:           tkv1.VoteStatusT(s.Status),
+		Duration:         s.Duration,
+		StartBlockHeight: s.StartBlockHeight,
+		StartBlockHash:   s.StartBlockHash,
+		EndBlockHeight:   s.EndBlockHeight,
+		EligibleTickets:  s.EligibleTickets,
+		QuorumPercentage: s.QuorumPercentage,
+		PassPercentage:   s.PassPercentage,
+		Results:          results,
+		WinCondition:     tkv1.WinConditionT(s.WinCondition),
+		WinningOption:    s.WinningOption,
+		Winners:          s.Winners,
+		Weight:           tkv1.VoteWeightT(s.Weight),
+		EligibleStake:    s.EligibleStake,
+		FinishedEarly:    s.FinishedEarly,
+
+		QuorumPercentageAchieved: s.QuorumPercentageAchieved,
+		ApprovalPercentage:       s.ApprovalPercentage,
+		RejectionReason:          tkv1.RejectionReasonT(s.RejectionReason),
+		BestBlock:                s.BestBlock,
+	}
+}
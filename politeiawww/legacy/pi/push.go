@@ -0,0 +1,117 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/politeiawww/legacy/webpush"
+	"github.com/google/uuid"
+)
+
+func (p *Pi) pushNtfnVoteStarted(token, name string, recipients map[uuid.UUID][]user.PushSubscription) error {
+	route := strings.Replace(guiRouteRecordDetails, "{token}", token, 1)
+	u, err := url.Parse(p.cfg.WebServerAddress + route)
+	if err != nil {
+		return err
+	}
+
+	m := www.PushMessage{
+		Type:  www.NotificationPushVoteStarted,
+		Title: "Voting Started",
+		Body:  name,
+		URL:   u.String(),
+	}
+
+	return p.pushSend(m, recipients)
+}
+
+func (p *Pi) pushNtfnCommentReply(token string, commentID uint32, commentUsername, proposalName string, recipient map[uuid.UUID][]user.PushSubscription) error {
+	cid := strconv.FormatUint(uint64(commentID), 10)
+	route := strings.Replace(guiRouteRecordComment, "{token}", token, 1)
+	route = strings.Replace(route, "{id}", cid, 1)
+
+	u, err := url.Parse(p.cfg.WebServerAddress + route)
+	if err != nil {
+		return err
+	}
+
+	m := www.PushMessage{
+		Type:  www.NotificationPushCommentReply,
+		Title: fmt.Sprintf("%v replied to your comment", commentUsername),
+		Body:  proposalName,
+		URL:   u.String(),
+	}
+
+	return p.pushSend(m, recipient)
+}
+
+// pushSend sends the push message to all of the provided subscriptions.
+// Failing to deliver to a single subscription does not prevent delivery to
+// the remaining subscriptions. Subscriptions that the push service reports
+// as gone are removed from the user.
+func (p *Pi) pushSend(m www.PushMessage, recipients map[uuid.UUID][]user.PushSubscription) error {
+	if !p.push.IsEnabled() || len(recipients) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	for userID, subs := range recipients {
+		gone := make(map[string]struct{}, len(subs))
+		for _, v := range subs {
+			s := webpush.Subscription{
+				Endpoint: v.Endpoint,
+				P256dh:   v.P256dh,
+				Auth:     v.Auth,
+			}
+			err := p.push.Send(s, payload)
+			switch {
+			case errors.Is(err, webpush.ErrSubscriptionGone):
+				gone[v.Endpoint] = struct{}{}
+			case err != nil:
+				log.Errorf("pushSend %v %v: %v", userID, v.Endpoint, err)
+			}
+		}
+		if len(gone) == 0 {
+			continue
+		}
+		err := p.pushSubscriptionsDel(userID, gone)
+		if err != nil {
+			log.Errorf("pushSubscriptionsDel %v: %v", userID, err)
+		}
+	}
+
+	return nil
+}
+
+// pushSubscriptionsDel removes the push subscriptions for the provided
+// endpoints from the user.
+func (p *Pi) pushSubscriptionsDel(userID uuid.UUID, endpoints map[string]struct{}) error {
+	u, err := p.userdb.UserGetById(userID)
+	if err != nil {
+		return err
+	}
+	subs := make([]user.PushSubscription, 0, len(u.PushSubscriptions))
+	for _, v := range u.PushSubscriptions {
+		if _, ok := endpoints[v.Endpoint]; ok {
+			log.Debugf("Push subscription gone: %v %v", u.Username, v.Endpoint)
+			continue
+		}
+		subs = append(subs, v)
+	}
+	u.PushSubscriptions = subs
+	return p.userdb.UserUpdate(*u)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
+	v1 "github.com/decred/politeia/politeiawww/api/pi/v1"
+	"github.com/decred/politeia/politeiawww/legacy/records"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/google/uuid"
+)
+
+// processWithdraw processes a pi v1 withdraw request.
+//
+// The pi plugin verifies and saves the signed withdrawal. The proposal is then
+// archived using the same signature and the proposal credit that was spent on
+// the proposal is refunded to the author. The archival cannot be done by the
+// pi plugin since politeiad holds the record lock for the duration of plugin
+// writes. A failed archival can be retried by the author since the proposal
+// remains under review until it has been archived.
+func (p *Pi) processWithdraw(ctx context.Context, w v1.Withdraw, u user.User) (*v1.WithdrawReply, error) {
+	log.Tracef("processWithdraw: %v %v", w.Token, u.Username)
+
+	// Verify user signed with their active identity
+	if !u.IsActivePublicKey(w.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Send plugin command. The pi plugin verifies that the user is
+	// the proposal author and that the proposal can be withdrawn.
+	pwr, err := p.politeiad.PiWithdraw(ctx, pi.Withdraw{
+		Token:     w.Token,
+		Version:   w.Version,
+		Reason:    w.Reason,
+		UserID:    u.ID.String(),
+		PublicKey: w.PublicKey,
+		Signature: w.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Archive the proposal. The withdrawal signature is the signature
+	// of the record status change.
+	scm := usermd.StatusChangeMetadata{
+		Token:     w.Token,
+		Version:   w.Version,
+		Status:    uint32(pdv2.RecordStatusArchived),
+		Reason:    w.Reason,
+		PublicKey: w.PublicKey,
+		Signature: w.Signature,
+		Timestamp: time.Now().Unix(),
+	}
+	b, err := json.Marshal(scm)
+	if err != nil {
+		return nil, err
+	}
+	mdAppend := []pdv2.MetadataStream{
+		{
+			PluginID: usermd.PluginID,
+			StreamID: usermd.StreamIDStatusChanges,
+			Payload:  string(b),
+		},
+	}
+	pdr, err := p.politeiad.RecordSetStatus(ctx, w.Token,
+		pdv2.RecordStatusArchived, mdAppend, []pdv2.MetadataStream{})
+	if err != nil {
+		return nil, err
+	}
+	p.events.Emit(records.EventTypeSetStatus,
+		records.EventSetStatus{
+			Record: convertRecordToV1(*pdr),
+		})
+
+	log.Infof("Proposal withdrawn by author: %v %v", w.Token, u.Username)
+
+	// Refund the proposal credit
+	refunded, err := p.refundProposalCredit(u, w.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.WithdrawReply{
+		CreditRefunded: refunded,
+		Receipt:        pwr.Receipt,
+		Timestamp:      pwr.Timestamp,
+	}, nil
+}
+
+// processWithdrawalDetails processes a pi v1 withdrawal request.
+func (p *Pi) processWithdrawalDetails(ctx context.Context, wd v1.WithdrawalDetails) (*v1.WithdrawalDetailsReply, error) {
+	log.Tracef("processWithdrawalDetails: %v", wd.Token)
+
+	w, err := p.politeiad.PiWithdrawal(ctx, wd.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	var wdr v1.WithdrawalDetailsReply
+	if w != nil {
+		a := convertWithdrawalToAPI(*w)
+		wdr.Withdrawal = &a
+	}
+
+	return &wdr, nil
+}
+
+// refundProposalCredit moves the spent proposal credit of the provided
+// proposal back to the user's unspent proposal credits and adds a refund
+// entry to the user's proposal credit ledger. It returns whether a credit
+// was refunded. No credit is refunded if the user did not spend a credit on
+// the proposal, e.g. the paywall is disabled, or if the credit has already
+// been refunded.
+//
+// This function is a temporary function that will be removed once user
+// plugins have been implemented.
+func (p *Pi) refundProposalCredit(u user.User, token string) (bool, error) {
+	// The user is relooked up by the update to ensure that the most
+	// recent credit lists are updated.
+	var refunded bool
+	err := user.ProposalCreditsUpdate(p.userdb, []uuid.UUID{u.ID},
+		func(users []*user.User) error {
+			dbu := users[0]
+
+			// Find the credit that was spent on the proposal
+			i := spentCreditIndex(dbu.SpentProposalCredits, token)
+			if i < 0 {
+				return nil
+			}
+			c := dbu.SpentProposalCredits[i]
+
+			// Refund the credit
+			dbu.SpentProposalCredits = append(dbu.SpentProposalCredits[:i],
+				dbu.SpentProposalCredits[i+1:]...)
+			dbu.ProposalCreditRefunds = append(dbu.ProposalCreditRefunds,
+				user.ProposalCreditRefund{
+					Token:     token,
+					Credit:    c,
+					Timestamp: time.Now().Unix(),
+				})
+			c.CensorshipToken = ""
+			dbu.UnspentProposalCredits = append(dbu.UnspentProposalCredits, c)
+			refunded = true
+			return nil
+		})
+	if err != nil {
+		return false, err
+	}
+	if !refunded {
+		return false, nil
+	}
+
+	log.Infof("Proposal credit refunded: %v %v", u.Username, token)
+
+	return true, nil
+}
+
+// spentCreditIndex returns the index of the credit that was spent on the
+// provided proposal. -1 is returned if a credit was not spent on the
+// proposal.
+func spentCreditIndex(credits []user.ProposalCredit, token string) int {
+	for i, v := range credits {
+		if v.CensorshipToken == token {
+			return i
+		}
+	}
+	return -1
+}
+
+func convertWithdrawalToAPI(w pi.Withdrawal) v1.Withdrawal {
+	return v1.Withdrawal{
+		Token:     w.Token,
+		Version:   w.Version,
+		Reason:    w.Reason,
+		PublicKey: w.PublicKey,
+		Signature: w.Signature,
+		Receipt:   w.Receipt,
+		Timestamp: w.Timestamp,
+	}
+}
@@ -6,6 +6,7 @@ package legacy
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/google/uuid"
 )
 
 // processUserRegistrationPayment verifies that the provided transaction
@@ -148,6 +150,111 @@ func (p *Politeiawww) processUserProposalCredits(u *user.User) (*www.UserProposa
 	return &www.UserProposalCreditsReply{
 		UnspentCredits: upc,
 		SpentCredits:   spc,
+		Transfers:      convertProposalCreditTransfersFromUserDB(u.ProposalCreditTransfers),
+		Refunds:        convertProposalCreditRefundsFromUserDB(u.ProposalCreditRefunds),
+	}, nil
+}
+
+// processUserProposalCreditsTransfer transfers unspent proposal credits from
+// the logged in user to the user with the provided username. Credits are
+// transferred FIFO. A ledger entry is added to both users and the recipient is
+// notified of the transfer by email.
+func (p *Politeiawww) processUserProposalCreditsTransfer(u *user.User, t www.UserProposalCreditsTransfer) (*www.UserProposalCreditsTransferReply, error) {
+	log.Tracef("processUserProposalCreditsTransfer: %v %v %v",
+		u.Username, t.Username, t.NumCredits)
+
+	// Verify the number of credits
+	if t.NumCredits == 0 {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"number of credits must be greater than 0"},
+		}
+	}
+
+	// Verify the recipient
+	username := formatUsername(t.Username)
+	if username == u.Username {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"cannot transfer credits to yourself"},
+		}
+	}
+
+	to, err := p.db.UserGetByUsername(username)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusUserNotFound,
+			}
+		}
+		return nil, err
+	}
+	if to.Deactivated {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusUserDeactivated,
+		}
+	}
+
+	// Move the credits and add the ledger entry to both users. The
+	// proposal credits of both users are locked during the update and
+	// the users are relooked up, so credits that the sender has spent
+	// since the start of this request cannot be transferred. The
+	// sender is saved first so that a failure can never result in the
+	// credits existing in both accounts.
+	var pct user.ProposalCreditTransfer
+	err = user.ProposalCreditsUpdate(p.db, []uuid.UUID{u.ID, to.ID},
+		func(users []*user.User) error {
+			sender, receiver := users[0], users[1]
+
+			// Verify the sender has enough unspent credits
+			if uint64(len(sender.UnspentProposalCredits)) < t.NumCredits {
+				return www.UserError{
+					ErrorCode: www.ErrorStatusNoProposalCredits,
+					ErrorContext: []string{fmt.Sprintf("%v unspent credits",
+						len(sender.UnspentProposalCredits))},
+				}
+			}
+
+			credits := make([]user.ProposalCredit, t.NumCredits)
+			copy(credits, sender.UnspentProposalCredits[:t.NumCredits])
+			pct = user.ProposalCreditTransfer{
+				FromUserID:   sender.ID.String(),
+				FromUsername: sender.Username,
+				ToUserID:     receiver.ID.String(),
+				ToUsername:   receiver.Username,
+				Credits:      credits,
+				Timestamp:    time.Now().Unix(),
+			}
+
+			sender.UnspentProposalCredits =
+				sender.UnspentProposalCredits[t.NumCredits:]
+			sender.ProposalCreditTransfers =
+				append(sender.ProposalCreditTransfers, pct)
+			receiver.UnspentProposalCredits =
+				append(receiver.UnspentProposalCredits, credits...)
+			receiver.ProposalCreditTransfers =
+				append(receiver.ProposalCreditTransfers, pct)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Proposal credits transferred: %v %v to %v",
+		t.NumCredits, pct.FromUsername, pct.ToUsername)
+
+	// Notify the recipient
+	recipient := map[uuid.UUID]string{
+		to.ID: to.Email,
+	}
+	err = p.emailUserProposalCreditsReceived(pct.ToUsername,
+		pct.FromUsername, t.NumCredits, recipient)
+	if err != nil {
+		log.Errorf("emailUserProposalCreditsReceived %v: %v", to.ID, err)
+	}
+
+	return &www.UserProposalCreditsTransferReply{
+		Transfer: convertProposalCreditTransferFromUserDB(pct),
 	}, nil
 }
 
@@ -219,6 +326,24 @@ func (p *Politeiawww) processUserPaymentsRescan(ctx context.Context, upr www.Use
 			continue
 		}
 
+		// Credits that were transferred to another user are no
+		// longer in the user's credit lists, but are still recorded
+		// in the sender's transfer ledger.
+		for _, t := range u.ProposalCreditTransfers {
+			if t.FromUserID != u.ID.String() {
+				continue
+			}
+			for _, credit := range t.Credits {
+				if credit.TxID == payment.TxID {
+					found = true
+					break
+				}
+			}
+		}
+		if found {
+			continue
+		}
+
 		// Credits were not found for this payment which means that it
 		// was missed by paywall polling. Create new credits using the
 		// paywall details that correspond to the payment timestamp. If
@@ -266,20 +391,17 @@ func (p *Politeiawww) processUserPaymentsRescan(ctx context.Context, upr www.Use
 		newCredits = append(newCredits, c...)
 	}
 
-	// Update user record
-	// We relookup the user record here in case the user has spent proposal
-	// credits since the start of this request. Failure to relookup the
-	// user record here could result in adding proposal credits to the
-	// user's account that have already been spent.
-	u, err = p.userByEmail(u.Email)
-	if err != nil {
-		return nil, fmt.Errorf("UserGet %v", err)
-	}
-
-	u.UnspentProposalCredits = append(u.UnspentProposalCredits,
-		newCredits...)
-
-	err = p.db.UserUpdate(*u)
+	// Update user record. The user record is relooked up while the
+	// user's proposal credits are locked in case the user has spent
+	// proposal credits since the start of this request. Failure to
+	// relookup the user record here could result in adding proposal
+	// credits to the user's account that have already been spent.
+	err = user.ProposalCreditsUpdate(p.db, []uuid.UUID{u.ID},
+		func(users []*user.User) error {
+			users[0].UnspentProposalCredits = append(
+				users[0].UnspentProposalCredits, newCredits...)
+			return nil
+		})
 	if err != nil {
 		return nil, fmt.Errorf("UserUpdate %v", err)
 	}
@@ -303,3 +425,34 @@ func convertProposalCreditFromUserDB(credit user.ProposalCredit) www.ProposalCre
 		TxID:          credit.TxID,
 	}
 }
+
+func convertProposalCreditTransferFromUserDB(t user.ProposalCreditTransfer) www.ProposalCreditTransfer {
+	return www.ProposalCreditTransfer{
+		FromUserID:   t.FromUserID,
+		FromUsername: t.FromUsername,
+		ToUserID:     t.ToUserID,
+		ToUsername:   t.ToUsername,
+		NumCredits:   uint64(len(t.Credits)),
+		Timestamp:    t.Timestamp,
+	}
+}
+
+func convertProposalCreditTransfersFromUserDB(transfers []user.ProposalCreditTransfer) []www.ProposalCreditTransfer {
+	t := make([]www.ProposalCreditTransfer, 0, len(transfers))
+	for _, v := range transfers {
+		t = append(t, convertProposalCreditTransferFromUserDB(v))
+	}
+	return t
+}
+
+func convertProposalCreditRefundsFromUserDB(refunds []user.ProposalCreditRefund) []www.ProposalCreditRefund {
+	r := make([]www.ProposalCreditRefund, 0, len(refunds))
+	for _, v := range refunds {
+		r = append(r, www.ProposalCreditRefund{
+			Token:     v.Token,
+			Credit:    convertProposalCreditFromUserDB(v.Credit),
+			Timestamp: v.Timestamp,
+		})
+	}
+	return r
+}
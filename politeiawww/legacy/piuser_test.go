@@ -0,0 +1,210 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"testing"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/google/uuid"
+)
+
+func TestProcessUserProposalCreditsTransfer(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	// Create a sender with two unspent credits and a recipient
+	from, _ := newUser(t, p, true, false)
+	to, _ := newUser(t, p, true, false)
+	from.UnspentProposalCredits = []user.ProposalCredit{
+		{PaywallID: 1, TxID: "tx1"},
+		{PaywallID: 1, TxID: "tx2"},
+	}
+	err := p.db.UserUpdate(*from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup tests
+	var tests = []struct {
+		name string
+		t    www.UserProposalCreditsTransfer
+		want error
+	}{
+		{
+			"zero credits",
+			www.UserProposalCreditsTransfer{
+				Username:   to.Username,
+				NumCredits: 0,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			},
+		},
+		{
+			"transfer to self",
+			www.UserProposalCreditsTransfer{
+				Username:   from.Username,
+				NumCredits: 1,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			},
+		},
+		{
+			"recipient not found",
+			www.UserProposalCreditsTransfer{
+				Username:   "notfound",
+				NumCredits: 1,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusUserNotFound,
+			},
+		},
+		{
+			"not enough credits",
+			www.UserProposalCreditsTransfer{
+				Username:   to.Username,
+				NumCredits: 3,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusNoProposalCredits,
+			},
+		},
+		{
+			"success",
+			www.UserProposalCreditsTransfer{
+				Username:   to.Username,
+				NumCredits: 1,
+			},
+			nil,
+		},
+	}
+
+	// Run tests
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := p.processUserProposalCreditsTransfer(from, v.t)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Verify the credits were moved FIFO and that the ledger entry
+	// was added to both users.
+	fromDB, err := p.db.UserGetById(from.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toDB, err := p.db.UserGetById(to.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(fromDB.UnspentProposalCredits) != 1 ||
+		fromDB.UnspentProposalCredits[0].TxID != "tx2":
+		t.Errorf("sender credits not updated: %v",
+			fromDB.UnspentProposalCredits)
+	case len(toDB.UnspentProposalCredits) != 1 ||
+		toDB.UnspentProposalCredits[0].TxID != "tx1":
+		t.Errorf("recipient credits not updated: %v",
+			toDB.UnspentProposalCredits)
+	case len(fromDB.ProposalCreditTransfers) != 1 ||
+		len(toDB.ProposalCreditTransfers) != 1:
+		t.Errorf("transfer ledger entries not found")
+	}
+}
+
+func TestProcessUserProposalCreditsTransferConcurrentSpend(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	// Create a sender with two unspent credits and a recipient
+	from, _ := newUser(t, p, true, false)
+	to, _ := newUser(t, p, true, false)
+	from.UnspentProposalCredits = []user.ProposalCredit{
+		{PaywallID: 1, TxID: "tx1"},
+		{PaywallID: 1, TxID: "tx2"},
+	}
+	err := p.db.UserUpdate(*from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Start spending a credit of the sender and pause the spend while
+	// the sender's proposal credits are locked.
+	var (
+		locked   = make(chan struct{})
+		release  = make(chan struct{})
+		spendErr = make(chan error)
+	)
+	go func() {
+		spendErr <- user.ProposalCreditsUpdate(p.db, []uuid.UUID{from.ID},
+			func(users []*user.User) error {
+				close(locked)
+				<-release
+				u := users[0]
+				c := u.UnspentProposalCredits[0]
+				c.CensorshipToken = "token"
+				u.UnspentProposalCredits = u.UnspentProposalCredits[1:]
+				u.SpentProposalCredits = append(u.SpentProposalCredits, c)
+				return nil
+			})
+	}()
+	<-locked
+
+	// Transfer both credits using the stale sender, then let the spend
+	// finish. The transfer must wait for the spend and only see the
+	// remaining credit.
+	transferErr := make(chan error)
+	go func() {
+		_, err := p.processUserProposalCreditsTransfer(from,
+			www.UserProposalCreditsTransfer{
+				Username:   to.Username,
+				NumCredits: 2,
+			})
+		transferErr <- err
+	}()
+	close(release)
+
+	err = <-spendErr
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := errToStr(<-transferErr)
+	want := errToStr(www.UserError{
+		ErrorCode: www.ErrorStatusNoProposalCredits,
+	})
+	if got != want {
+		t.Fatalf("got error %v, want %v", got, want)
+	}
+
+	// Verify that no credits were created or lost
+	fromDB, err := p.db.UserGetById(from.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toDB, err := p.db.UserGetById(to.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(fromDB.UnspentProposalCredits) != 1 ||
+		fromDB.UnspentProposalCredits[0].TxID != "tx2":
+		t.Errorf("sender unspent credits: got %v, want tx2",
+			fromDB.UnspentProposalCredits)
+	case len(fromDB.SpentProposalCredits) != 1 ||
+		fromDB.SpentProposalCredits[0].TxID != "tx1":
+		t.Errorf("sender spent credits: got %v, want tx1",
+			fromDB.SpentProposalCredits)
+	case len(toDB.UnspentProposalCredits) != 0:
+		t.Errorf("recipient credits: got %v, want none",
+			toDB.UnspentProposalCredits)
+	}
+}
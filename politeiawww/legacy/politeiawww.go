@@ -25,6 +25,7 @@ import (
 	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
 	umplugin "github.com/decred/politeia/politeiad/plugins/usermd"
 	"github.com/decred/politeia/politeiawww/config"
+	"github.com/decred/politeia/politeiawww/legacy/changes"
 	"github.com/decred/politeia/politeiawww/legacy/cmsdatabase"
 	database "github.com/decred/politeia/politeiawww/legacy/cmsdatabase"
 	cmsdb "github.com/decred/politeia/politeiawww/legacy/cmsdatabase/cockroachdb"
@@ -38,10 +39,13 @@ import (
 	"github.com/decred/politeia/politeiawww/legacy/records"
 	"github.com/decred/politeia/politeiawww/legacy/sessions"
 	"github.com/decred/politeia/politeiawww/legacy/ticketvote"
+	"github.com/decred/politeia/politeiawww/legacy/usage"
 	"github.com/decred/politeia/politeiawww/legacy/user"
 	"github.com/decred/politeia/politeiawww/legacy/user/cockroachdb"
 	"github.com/decred/politeia/politeiawww/legacy/user/localdb"
 	"github.com/decred/politeia/politeiawww/legacy/user/mysql"
+	"github.com/decred/politeia/politeiawww/legacy/webpush"
+	"github.com/decred/politeia/politeiawww/legacy/writequeue"
 	"github.com/decred/politeia/politeiawww/wsdcrdata"
 	"github.com/decred/politeia/util"
 	"github.com/google/uuid"
@@ -71,7 +75,26 @@ type Politeiawww struct {
 	// removed once all user by email lookups have been taken out.
 	userEmails map[string]uuid.UUID // [email]userID
 
+	// pendingActions contains the destructive admin actions that
+	// require the confirmation of a second admin. It is only used when
+	// the admin confirmation setting is enabled.
+	pendingActions    user.PendingActionsDB
+	pendingActionsMtx sync.Mutex
+
+	// banners contains the site-wide banners that are published by
+	// the admins. The banners are cached in memory since they are
+	// returned by the status route, which is polled by clients.
+	banners         user.BannersDB
+	bannersMtx      sync.Mutex
+	bannersCache    []user.Banner // Nil if not cached
+	bannersCachedAt time.Time
+
 	// The following fields are only used during piwww mode.
+	records         *records.Records
+	push            webpush.Pusher
+	queue           *writequeue.Queue               // Nil if the write queue is disabled
+	usage           *usage.Tracker                  // Nil if usage tracking is disabled
+	changes         *changes.Log                    // Nil if the change log is disabled
 	userPaywallPool map[uuid.UUID]paywallPoolMember // [userid][paywallPoolMember]
 
 	// The following fields are use only during cmswww mode.
@@ -97,6 +120,8 @@ func NewPoliteiawww(cfg *config.Config, router, auth *mux.Router, params *chainc
 
 	var userDB user.Database
 	var mailerDB user.MailerDB
+	var pendingDB user.PendingActionsDB
+	var bannersDB user.BannersDB
 	switch cfg.UserDB {
 	case config.LevelDB:
 		db, err := localdb.New(cfg.DataDir)
@@ -104,6 +129,8 @@ func NewPoliteiawww(cfg *config.Config, router, auth *mux.Router, params *chainc
 			return nil, err
 		}
 		userDB = db
+		pendingDB = db
+		bannersDB = db
 
 	case config.MySQL, config.CockroachDB:
 		// If old encryption key is set it means that we need
@@ -127,6 +154,8 @@ func NewPoliteiawww(cfg *config.Config, router, auth *mux.Router, params *chainc
 			}
 			userDB = mysql
 			mailerDB = mysql
+			pendingDB = mysql
+			bannersDB = mysql
 		case config.CockroachDB:
 			cdb, err := cockroachdb.New(cfg.DBHost, network,
 				cfg.DBRootCert, cfg.DBCert, cfg.DBKey,
@@ -136,6 +165,8 @@ func NewPoliteiawww(cfg *config.Config, router, auth *mux.Router, params *chainc
 			}
 			userDB = cdb
 			mailerDB = cdb
+			pendingDB = cdb
+			bannersDB = cdb
 		}
 
 		// Rotate keys.
@@ -182,6 +213,8 @@ func NewPoliteiawww(cfg *config.Config, router, auth *mux.Router, params *chainc
 		politeiad:       pdclient,
 		http:            httpClient,
 		db:              userDB,
+		pendingActions:  pendingDB,
+		banners:         bannersDB,
 		mail:            mailer,
 		sessions:        sessions.New(userDB, cookieKey),
 		events:          events.NewManager(),
@@ -205,7 +238,12 @@ func (p *Politeiawww) Close() {
 	// Perform application specific shutdown
 	switch p.cfg.Mode {
 	case config.PiWWWMode:
-		// Nothing to do
+		if p.queue != nil {
+			p.queue.Close()
+		}
+		if p.usage != nil {
+			p.usage.Close()
+		}
 	case config.CMSWWWMode:
 		p.wsDcrdata.Close()
 	}
@@ -262,19 +300,58 @@ func (p *Politeiawww) setupPi() error {
 		return fmt.Errorf("required politeiad plugins not found: %v", notFound)
 	}
 
+	// Setup web push client
+	p.push, err = webpush.NewClient(p.cfg.WebPushKeyFile,
+		p.cfg.WebPushSubject)
+	if err != nil {
+		return fmt.Errorf("new web push client: %v", err)
+	}
+
+	// Setup the write queue
+	if p.cfg.WriteQueue {
+		p.queue, err = writequeue.New(filepath.Join(p.cfg.DataDir,
+			"writequeue"))
+		if err != nil {
+			return fmt.Errorf("new write queue: %v", err)
+		}
+	}
+
+	// Setup the API usage tracker
+	if p.cfg.UsageRetention > 0 {
+		p.usage, err = usage.New(filepath.Join(p.cfg.DataDir,
+			"usage.json"), p.cfg.UsageRetention)
+		if err != nil {
+			return fmt.Errorf("new usage tracker: %v", err)
+		}
+		p.usage.Start()
+	}
+
+	// Setup the change log. This must be done before the api contexts
+	// are setup so that no events are missed.
+	if p.cfg.ChangesMax > 0 {
+		p.changes, err = changes.New(filepath.Join(p.cfg.DataDir,
+			"changes.ndjson"), p.cfg.ChangesMax)
+		if err != nil {
+			return fmt.Errorf("new change log: %v", err)
+		}
+		p.changes.Listen(p.events)
+	}
+
 	// Setup api contexts
-	recordsCtx := records.New(p.cfg, p.politeiad, p.db, p.sessions, p.events)
+	recordsCtx := records.New(p.cfg, p.politeiad, p.db, p.pendingActions,
+		p.sessions, p.events)
+	p.records = recordsCtx
 	commentsCtx, err := comments.New(p.cfg, p.politeiad, p.db,
-		p.sessions, p.events, plugins)
+		p.sessions, p.events, p.queue, plugins)
 	if err != nil {
 		return fmt.Errorf("new comments api: %v", err)
 	}
 	voteCtx, err := ticketvote.New(p.cfg, p.politeiad,
-		p.sessions, p.events, plugins)
+		p.sessions, p.events, p.queue, plugins)
 	if err != nil {
 		return fmt.Errorf("new ticketvote api: %v", err)
 	}
-	piCtx, err := pi.New(p.cfg, p.politeiad, p.db, p.mail,
+	piCtx, err := pi.New(p.cfg, p.politeiad, p.db, p.mail, p.push,
 		p.sessions, p.events, plugins)
 	if err != nil {
 		return fmt.Errorf("new pi api: %v", err)
@@ -284,6 +361,12 @@ func (p *Politeiawww) setupPi() error {
 	p.setUserWWWRoutes()
 	p.setPiRoutes(recordsCtx, commentsCtx, voteCtx, piCtx)
 
+	// Start retrying any queued submissions. This must be done after
+	// the api contexts have registered their submission types.
+	if p.queue != nil {
+		p.queue.Start()
+	}
+
 	// Verify paywall settings
 	switch {
 	case p.cfg.PaywallAmount != 0 && p.cfg.PaywallXpub != "":
@@ -17,6 +17,7 @@ import (
 	cms "github.com/decred/politeia/politeiawww/api/cms/v1"
 	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	database "github.com/decred/politeia/politeiawww/legacy/cmsdatabase"
+	"github.com/decred/politeia/util"
 )
 
 const binanceURL = "https://api.binance.com"
@@ -122,9 +123,12 @@ func getPricesPolo(ctx context.Context, pairing string, startDate int64, endDate
 	q.Set("period", strconv.Itoa(pricePeriod))
 	req.URL.RawQuery = q.Encode()
 
-	// Create HTTP client,
-	httpClient := http.Client{
+	// Create HTTP client
+	httpClient, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
 		Timeout: httpTimeout,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Send HTTP request
@@ -174,9 +178,12 @@ func getPricesBinance(ctx context.Context, pairing string, startDate int64, endD
 
 	req.URL.RawQuery = q.Encode()
 
-	// Create HTTP client,
-	httpClient := http.Client{
+	// Create HTTP client
+	httpClient, err := util.NewHTTPClientWithOpts(util.HTTPClientOpts{
 		Timeout: httpTimeout,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Send HTTP request
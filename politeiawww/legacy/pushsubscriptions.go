@@ -0,0 +1,139 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"fmt"
+	"time"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/politeiawww/legacy/webpush"
+)
+
+const (
+	// pushSubscriptionsMax is the maximum number of web push
+	// subscriptions that a user can have. The oldest subscription is
+	// dropped when a new subscription would exceed this limit.
+	pushSubscriptionsMax = 10
+
+	// pushNotificationsAll contains all valid push notification bits.
+	pushNotificationsAll = uint64(www.NotificationPushVoteStarted |
+		www.NotificationPushCommentReply)
+)
+
+// processPushSubscriptions returns the web push subscriptions of the logged
+// in user along with the server's VAPID public key.
+func (p *Politeiawww) processPushSubscriptions(u *user.User) (*www.PushSubscriptionsReply, error) {
+	log.Tracef("processPushSubscriptions: %v", u.Username)
+
+	return &www.PushSubscriptionsReply{
+		PublicKey:     p.push.PublicKey(),
+		Subscriptions: convertPushSubscriptionsFromUserDB(u.PushSubscriptions),
+	}, nil
+}
+
+// processPushSubscribe adds a web push subscription to the logged in user.
+// An existing subscription with the same endpoint is replaced.
+func (p *Politeiawww) processPushSubscribe(u *user.User, ps www.PushSubscribe) (*www.PushSubscribeReply, error) {
+	log.Tracef("processPushSubscribe: %v", u.Username)
+
+	// Verify the notification bits
+	if ps.Notifications == 0 || ps.Notifications&^pushNotificationsAll != 0 {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPushSubscription,
+			ErrorContext: []string{fmt.Sprintf("invalid notifications %v",
+				ps.Notifications)},
+		}
+	}
+
+	// Verify the subscription endpoint and keys
+	err := webpush.VerifySubscription(webpush.Subscription{
+		Endpoint: ps.Endpoint,
+		P256dh:   ps.P256dh,
+		Auth:     ps.Auth,
+	})
+	if err != nil {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidPushSubscription,
+			ErrorContext: []string{err.Error()},
+		}
+	}
+
+	// Remove any existing subscription for the endpoint and add the
+	// new one. Drop the oldest subscriptions if the limit has been
+	// exceeded.
+	subs := pushSubscriptionsDel(u.PushSubscriptions, ps.Endpoint)
+	subs = append(subs, user.PushSubscription{
+		Endpoint:      ps.Endpoint,
+		P256dh:        ps.P256dh,
+		Auth:          ps.Auth,
+		Notifications: ps.Notifications,
+		Timestamp:     time.Now().Unix(),
+	})
+	if len(subs) > pushSubscriptionsMax {
+		subs = subs[len(subs)-pushSubscriptionsMax:]
+	}
+	u.PushSubscriptions = subs
+
+	err = p.db.UserUpdate(*u)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Push subscription added: %v %v", u.Username, ps.Endpoint)
+
+	return &www.PushSubscribeReply{}, nil
+}
+
+// processPushUnsubscribe removes a web push subscription from the logged in
+// user.
+func (p *Politeiawww) processPushUnsubscribe(u *user.User, pu www.PushUnsubscribe) (*www.PushUnsubscribeReply, error) {
+	log.Tracef("processPushUnsubscribe: %v", u.Username)
+
+	subs := pushSubscriptionsDel(u.PushSubscriptions, pu.Endpoint)
+	if len(subs) == len(u.PushSubscriptions) {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusPushSubscriptionNotFound,
+		}
+	}
+	u.PushSubscriptions = subs
+
+	err := p.db.UserUpdate(*u)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Push subscription removed: %v %v", u.Username, pu.Endpoint)
+
+	return &www.PushUnsubscribeReply{}, nil
+}
+
+// pushSubscriptionsDel returns the provided subscriptions without the
+// subscription for the provided endpoint.
+func pushSubscriptionsDel(subs []user.PushSubscription, endpoint string) []user.PushSubscription {
+	s := make([]user.PushSubscription, 0, len(subs)+1)
+	for _, v := range subs {
+		if v.Endpoint == endpoint {
+			continue
+		}
+		s = append(s, v)
+	}
+	return s
+}
+
+func convertPushSubscriptionsFromUserDB(subs []user.PushSubscription) []www.PushSubscription {
+	s := make([]www.PushSubscription, 0, len(subs))
+	for _, v := range subs {
+		s = append(s, www.PushSubscription{
+			Endpoint:      v.Endpoint,
+			P256dh:        v.P256dh,
+			Auth:          v.Auth,
+			Notifications: v.Notifications,
+			Timestamp:     v.Timestamp,
+		})
+	}
+	return s
+}
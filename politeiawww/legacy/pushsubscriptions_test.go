@@ -0,0 +1,147 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+)
+
+func TestProcessPushSubscribe(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	usr, _ := newUser(t, p, true, false)
+
+	// Setup valid subscription keys
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256dh := base64.RawURLEncoding.EncodeToString(
+		elliptic.Marshal(elliptic.P256(), key.X, key.Y))
+	auth := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+	endpoint := "https://push.example.com/send/abc"
+	ntfns := uint64(www.NotificationPushVoteStarted)
+
+	// Setup tests
+	var tests = []struct {
+		name string
+		ps   www.PushSubscribe
+		want error
+	}{
+		{
+			"no notifications",
+			www.PushSubscribe{
+				Endpoint: endpoint,
+				P256dh:   p256dh,
+				Auth:     auth,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPushSubscription,
+			},
+		},
+		{
+			"invalid notifications",
+			www.PushSubscribe{
+				Endpoint:      endpoint,
+				P256dh:        p256dh,
+				Auth:          auth,
+				Notifications: 1 << 10,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPushSubscription,
+			},
+		},
+		{
+			"invalid endpoint",
+			www.PushSubscribe{
+				Endpoint:      "http://push.example.com/send/abc",
+				P256dh:        p256dh,
+				Auth:          auth,
+				Notifications: ntfns,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPushSubscription,
+			},
+		},
+		{
+			"invalid keys",
+			www.PushSubscribe{
+				Endpoint:      endpoint,
+				P256dh:        auth,
+				Auth:          auth,
+				Notifications: ntfns,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPushSubscription,
+			},
+		},
+		{
+			"success",
+			www.PushSubscribe{
+				Endpoint:      endpoint,
+				P256dh:        p256dh,
+				Auth:          auth,
+				Notifications: ntfns,
+			},
+			nil,
+		},
+		{
+			"replace existing",
+			www.PushSubscribe{
+				Endpoint:      endpoint,
+				P256dh:        p256dh,
+				Auth:          auth,
+				Notifications: pushNotificationsAll,
+			},
+			nil,
+		},
+	}
+
+	// Run tests
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := p.processPushSubscribe(usr, v.ps)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+
+	// Verify the subscription was replaced and not duplicated
+	u, err := p.db.UserGetById(usr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.PushSubscriptions) != 1 ||
+		u.PushSubscriptions[0].Notifications != pushNotificationsAll {
+		t.Fatalf("unexpected subscriptions: %+v", u.PushSubscriptions)
+	}
+
+	// Unsubscribe
+	pu := www.PushUnsubscribe{
+		Endpoint: endpoint,
+	}
+	_, err = p.processPushUnsubscribe(u, pu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.processPushUnsubscribe(u, pu)
+	got := errToStr(err)
+	want := errToStr(www.UserError{
+		ErrorCode: www.ErrorStatusPushSubscriptionNotFound,
+	})
+	if got != want {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
@@ -64,6 +64,7 @@ func respondWithError(w http.ResponseWriter, r *http.Request, format string, err
 				PluginID:     pe.PluginID,
 				ErrorCode:    pe.ErrorCode,
 				ErrorContext: pe.ErrorContext,
+				ErrorParams:  pe.ErrorParams,
 			})
 		return
 
@@ -101,6 +102,7 @@ func handlePDError(w http.ResponseWriter, r *http.Request, format string, pde pd
 		pluginID   = pde.ErrorReply.PluginID
 		errCode    = pde.ErrorReply.ErrorCode
 		errContext = pde.ErrorReply.ErrorContext
+		errParams  = pde.ErrorReply.ErrorParams
 	)
 	e := convertPDErrorCode(errCode)
 	switch {
@@ -117,6 +119,7 @@ func handlePDError(w http.ResponseWriter, r *http.Request, format string, pde pd
 				PluginID:     pluginID,
 				ErrorCode:    errCode,
 				ErrorContext: errContext,
+				ErrorParams:  errParams,
 			})
 		return
 
@@ -9,6 +9,7 @@ import (
 
 	v1 "github.com/decred/politeia/politeiawww/api/records/v1"
 	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/google/uuid"
 )
 
 // paywallIsEnabled returns whether the user paywall is enabled.
@@ -37,23 +38,29 @@ func userHasProposalCredits(u user.User) bool {
 }
 
 // spendProposalCredit moves a unspent credit to the spent credit list and
-// updates the user in the database.
+// updates the user in the database. The user is relooked up while the user's
+// proposal credits are locked since the provided user may be stale, e.g.
+// credits may have been transferred since the start of the request.
 //
 // This function is a temporary function that will be removed once user plugins
 // have been implemented.
 func (r *Records) spendProposalCredit(u user.User, token string) error {
-	// Verify there are credits to be spent
-	if !userHasProposalCredits(u) {
-		return fmt.Errorf("no proposal credits found")
-	}
+	return user.ProposalCreditsUpdate(r.userdb, []uuid.UUID{u.ID},
+		func(users []*user.User) error {
+			u := users[0]
 
-	// Credits are spent FIFO
-	c := u.UnspentProposalCredits[0]
-	c.CensorshipToken = token
-	u.SpentProposalCredits = append(u.SpentProposalCredits, c)
-	u.UnspentProposalCredits = u.UnspentProposalCredits[1:]
+			// Verify there are credits to be spent
+			if !userHasProposalCredits(*u) {
+				return fmt.Errorf("no proposal credits found")
+			}
 
-	return r.userdb.UserUpdate(u)
+			// Credits are spent FIFO
+			c := u.UnspentProposalCredits[0]
+			c.CensorshipToken = token
+			u.SpentProposalCredits = append(u.SpentProposalCredits, c)
+			u.UnspentProposalCredits = u.UnspentProposalCredits[1:]
+			return nil
+		})
 }
 
 // piHookNewRecordpre executes the new record pre hook for pi.
@@ -6,9 +6,11 @@ package records
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
@@ -17,6 +19,7 @@ import (
 	"github.com/decred/politeia/politeiawww/client"
 	"github.com/decred/politeia/politeiawww/config"
 	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/util"
 	"github.com/google/uuid"
 )
 
@@ -24,13 +27,19 @@ func (r *Records) processNew(ctx context.Context, n v1.New, u user.User) (*v1.Ne
 	log.Tracef("processNew: %v", u.Username)
 
 	// Verify user signed using active identity
-	if u.PublicKey() != n.PublicKey {
+	if !u.IsActivePublicKey(n.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
 		}
 	}
 
+	// Verify image files
+	err := filesVerifyImages(n.Files)
+	if err != nil {
+		return nil, err
+	}
+
 	// Execute pre plugin hooks. Checking the mode is a temporary
 	// measure until user plugins have been properly implemented.
 	switch r.cfg.Mode {
@@ -97,6 +106,34 @@ func (r *Records) processNew(ctx context.Context, n v1.New, u user.User) (*v1.Ne
 	}, nil
 }
 
+// filesVerifyImages verifies that all PNG image files are sanitized PNGs that
+// do not exceed the maximum image dimensions. Images are verified before they
+// are sent to politeiad so that malicious or enormous images are rejected
+// early. Images are not normalized by the server since this would change the
+// file digests that the user signed.
+func filesVerifyImages(files []v1.File) error {
+	for _, v := range files {
+		if v.MIME != "image/png" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return v1.UserErrorReply{
+				ErrorCode:    v1.ErrorCodeFilePayloadInvalid,
+				ErrorContext: v.Name,
+			}
+		}
+		err = util.PNGVerify(b, v1.ImageWidthMax, v1.ImageHeightMax)
+		if err != nil {
+			return v1.UserErrorReply{
+				ErrorCode:    v1.ErrorCodeFileImageInvalid,
+				ErrorContext: fmt.Sprintf("%v: %v", v.Name, err),
+			}
+		}
+	}
+	return nil
+}
+
 // filesToDel returns the names of the files that are included in the current
 // files but are not included in updated files. These are the files that need
 // to be deleted from a record on update.
@@ -121,13 +158,19 @@ func (r *Records) processEdit(ctx context.Context, e v1.Edit, u user.User) (*v1.
 	log.Tracef("processEdit: %v %v", e.Token, u.Username)
 
 	// Verify user signed using active identity
-	if u.PublicKey() != e.PublicKey {
+	if !u.IsActivePublicKey(e.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
 		}
 	}
 
+	// Verify image files
+	err := filesVerifyImages(e.Files)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get current record
 	curr, err := r.record(ctx, e.Token, 0)
 	if err != nil {
@@ -192,13 +235,81 @@ func (r *Records) processSetStatus(ctx context.Context, ss v1.SetStatus, u user.
 	log.Tracef("processSetStatus: %v %v %v", ss.Token, ss.Status, ss.Reason)
 
 	// Verify user signed using active identity
-	if u.PublicKey() != ss.PublicKey {
+	if !u.IsActivePublicKey(ss.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
 		}
 	}
 
+	// Censoring a vetted record requires the confirmation of a second
+	// admin when admin confirmations have been enabled.
+	if r.cfg.AdminConfirm && ss.Status == v1.RecordStatusCensored {
+		rc, err := r.record(ctx, ss.Token, 0)
+		if err != nil {
+			if err == errRecordNotFound {
+				return nil, v1.UserErrorReply{
+					ErrorCode: v1.ErrorCodeRecordNotFound,
+				}
+			}
+			return nil, err
+		}
+		if rc.State == v1.RecordStateVetted {
+			return r.setStatusPending(ss, u)
+		}
+	}
+
+	return r.setStatus(ctx, ss)
+}
+
+// setStatusPending saves the provided status change as a pending action that
+// must be confirmed by a second admin before it is executed. The signature is
+// verified up front so that an invalid status change is not left pending.
+func (r *Records) setStatusPending(ss v1.SetStatus, u user.User) (*v1.SetStatusReply, error) {
+	msg := ss.Token + strconv.FormatUint(uint64(ss.Version), 10) +
+		strconv.FormatUint(uint64(ss.Status), 10) + ss.Reason
+	err := util.VerifySignature(ss.Signature, ss.PublicKey, msg)
+	if err != nil {
+		return nil, convertSignatureError(err)
+	}
+
+	b, err := json.Marshal(ss)
+	if err != nil {
+		return nil, err
+	}
+	pa := user.NewPendingAction(user.PendingActionTypeCensorRecord,
+		string(b), u.ID,
+		time.Duration(r.cfg.AdminConfirmWindow)*time.Second)
+	err = r.pending.PendingActionSave(pa)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Record censor pending confirmation: %v %v", ss.Token, pa.ID)
+
+	return &v1.SetStatusReply{
+		PendingActionID: pa.ID.String(),
+	}, nil
+}
+
+// PendingActionExec executes a pending record status change that has been
+// confirmed by a second admin.
+func (r *Records) PendingActionExec(ctx context.Context, pa user.PendingAction) error {
+	if pa.Type != user.PendingActionTypeCensorRecord {
+		return fmt.Errorf("invalid pending action type %v", pa.Type)
+	}
+	var ss v1.SetStatus
+	err := json.Unmarshal([]byte(pa.Payload), &ss)
+	if err != nil {
+		return err
+	}
+	_, err = r.setStatus(ctx, ss)
+	return err
+}
+
+// setStatus sends the provided status change to politeiad and emits a set
+// status event.
+func (r *Records) setStatus(ctx context.Context, ss v1.SetStatus) (*v1.SetStatusReply, error) {
 	// Setup status change metadata
 	scm := usermd.StatusChangeMetadata{
 		Token:     ss.Token,
@@ -563,6 +674,23 @@ func userIDFromMetadataStreams(ms []v1.MetadataStream) string {
 	return um.UserID
 }
 
+func convertSignatureError(err error) v1.UserErrorReply {
+	var e util.SignatureError
+	var s v1.ErrorCodeT
+	if errors.As(err, &e) {
+		switch e.ErrorCode {
+		case util.ErrorStatusPublicKeyInvalid:
+			s = v1.ErrorCodePublicKeyInvalid
+		case util.ErrorStatusSignatureInvalid:
+			s = v1.ErrorCodeSignatureInvalid
+		}
+	}
+	return v1.UserErrorReply{
+		ErrorCode:    s,
+		ErrorContext: e.ErrorContext,
+	}
+}
+
 func convertStateToV1(s pdv2.RecordStateT) v1.RecordStateT {
 	switch s {
 	case pdv2.RecordStateUnvetted:
@@ -595,6 +723,7 @@ func convertFilesToV1(f []pdv2.File) []v1.File {
 			MIME:    v.MIME,
 			Digest:  v.Digest,
 			Payload: v.Payload,
+			CID:     v.CID,
 		})
 	}
 	return files
@@ -5,16 +5,20 @@
 package records
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 
 	pdclient "github.com/decred/politeia/politeiad/client"
 	v1 "github.com/decred/politeia/politeiawww/api/records/v1"
 	"github.com/decred/politeia/politeiawww/config"
+	"github.com/decred/politeia/politeiawww/legacy/enums"
 	"github.com/decred/politeia/politeiawww/legacy/events"
 	"github.com/decred/politeia/politeiawww/legacy/sessions"
 	"github.com/decred/politeia/politeiawww/legacy/user"
-	"github.com/decred/politeia/util"
 )
 
 // Records is the context for the records API.
@@ -22,6 +26,7 @@ type Records struct {
 	cfg       *config.Config
 	politeiad *pdclient.Client
 	userdb    user.Database
+	pending   user.PendingActionsDB
 	sessions  *sessions.Sessions
 	events    *events.Manager
 	policy    *v1.PolicyReply
@@ -31,7 +36,7 @@ type Records struct {
 func (c *Records) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, c.policy)
+	enums.RespondWithJSON(w, r, http.StatusOK, c.policy)
 }
 
 // HandleNew is the request handler for the records v1 New route.
@@ -39,12 +44,9 @@ func (c *Records) HandleNew(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandleNew")
 
 	var n v1.New
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&n); err != nil {
-		respondWithError(w, r, "HandleNew: unmarshal",
-			v1.UserErrorReply{
-				ErrorCode: v1.ErrorCodeInputInvalid,
-			})
+	err := decodeFilesRequest(r, &n, &n.Files)
+	if err != nil {
+		respondWithError(w, r, "HandleNew: decodeFilesRequest: %v", err)
 		return
 	}
 
@@ -62,7 +64,7 @@ func (c *Records) HandleNew(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, nr)
+	enums.RespondWithJSON(w, r, http.StatusOK, nr)
 }
 
 // HandleEdit is the request handler for the records v1 Edit route.
@@ -70,12 +72,9 @@ func (c *Records) HandleEdit(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandleEdit")
 
 	var e v1.Edit
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&e); err != nil {
-		respondWithError(w, r, "HandleEdit: unmarshal",
-			v1.UserErrorReply{
-				ErrorCode: v1.ErrorCodeInputInvalid,
-			})
+	err := decodeFilesRequest(r, &e, &e.Files)
+	if err != nil {
+		respondWithError(w, r, "HandleEdit: decodeFilesRequest: %v", err)
 		return
 	}
 
@@ -93,7 +92,7 @@ func (c *Records) HandleEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, er)
+	enums.RespondWithJSON(w, r, http.StatusOK, er)
 }
 
 // HandleSetStatus is the request handler for the records v1 SetStatus route.
@@ -124,7 +123,7 @@ func (c *Records) HandleSetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, ssr)
+	enums.RespondWithJSON(w, r, http.StatusOK, ssr)
 }
 
 // HandleDetails is the request handler for the records v1 Details route.
@@ -157,7 +156,7 @@ func (c *Records) HandleDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, dr)
+	enums.RespondWithJSON(w, r, http.StatusOK, dr)
 }
 
 // HandleTimestamps is the request handler for the records v1 Timestamps route.
@@ -191,7 +190,7 @@ func (c *Records) HandleTimestamps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, tr)
+	enums.RespondWithJSON(w, r, http.StatusOK, tr)
 }
 
 // HandleRecords is the request handler for the records v1 Records route.
@@ -224,7 +223,7 @@ func (c *Records) HandleRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, rsr)
+	enums.RespondWithJSON(w, r, http.StatusOK, rsr)
 }
 
 // HandleInventory is the request handler for the records v1 Inventory route.
@@ -257,7 +256,7 @@ func (c *Records) HandleInventory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, ir)
+	enums.RespondWithJSON(w, r, http.StatusOK, ir)
 }
 
 // HandleInventoryOrdered is the request handler for the records v1
@@ -291,7 +290,7 @@ func (c *Records) HandleInventoryOrdered(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, ir)
+	enums.RespondWithJSON(w, r, http.StatusOK, ir)
 }
 
 // HandleUserRecords is the request handler for the records v1 UserRecords
@@ -325,20 +324,108 @@ func (c *Records) HandleUserRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, urr)
+	enums.RespondWithJSON(w, r, http.StatusOK, urr)
+}
+
+// decodeFilesRequest decodes a request that contains record files into v.
+// The files argument must point to the Files field of v. The request body can
+// be either JSON or multipart/form-data. See the records v1 API for details on
+// the multipart request format.
+//
+// A v1 UserErrorReply is returned if the request body cannot be decoded.
+func decodeFilesRequest(r *http.Request, v interface{}, files *[]v1.File) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		// Not a multipart request. Decode the JSON request body.
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(v); err != nil {
+			return v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			}
+		}
+		return nil
+	}
+
+	// The parts are read as a stream so that the request body size
+	// limit is enforced as the body is read, before any of the
+	// request is buffered.
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return inputInvalid("invalid multipart body: %v", err)
+	}
+
+	// The first part must contain the JSON encoded request
+	p, err := mr.NextPart()
+	if err != nil {
+		return inputInvalid("request part not found: %v", err)
+	}
+	if p.FormName() != v1.FormFieldRequest {
+		return inputInvalid("first part must be the %v part",
+			v1.FormFieldRequest)
+	}
+	decoder := json.NewDecoder(p)
+	if err := decoder.Decode(v); err != nil {
+		return inputInvalid("unable to decode request part: %v", err)
+	}
+
+	// Add the file contents to the request files
+	fileIdx := make(map[string]int, len(*files)) // [filename]index
+	for i, f := range *files {
+		fileIdx[f.Name] = i
+	}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return inputInvalid("invalid multipart body: %v", err)
+		}
+		if p.FormName() != v1.FormFieldFile {
+			return inputInvalid("unexpected part %v", p.FormName())
+		}
+		i, ok := fileIdx[p.FileName()]
+		if !ok {
+			return inputInvalid("file part %v is not a request file "+
+				"or is a duplicate", p.FileName())
+		}
+		b, err := io.ReadAll(p)
+		if err != nil {
+			return inputInvalid("unable to read file part %v: %v",
+				p.FileName(), err)
+		}
+		(*files)[i].Payload = base64.StdEncoding.EncodeToString(b)
+
+		// Prevent duplicate file parts
+		delete(fileIdx, p.FileName())
+	}
+
+	return nil
+}
+
+// inputInvalid returns a v1 UserErrorReply with the input invalid error code
+// and the provided error context.
+func inputInvalid(format string, a ...interface{}) v1.UserErrorReply {
+	return v1.UserErrorReply{
+		ErrorCode:    v1.ErrorCodeInputInvalid,
+		ErrorContext: fmt.Sprintf(format, a...),
+	}
 }
 
 // New returns a new Records context.
-func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *sessions.Sessions, e *events.Manager) *Records {
+func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, pdb user.PendingActionsDB, s *sessions.Sessions, e *events.Manager) *Records {
 	return &Records{
 		cfg:       cfg,
 		politeiad: pdc,
 		userdb:    udb,
+		pending:   pdb,
 		sessions:  s,
 		events:    e,
 		policy: &v1.PolicyReply{
 			RecordsPageSize:   v1.RecordsPageSize,
 			InventoryPageSize: v1.InventoryPageSize,
+			ImageWidthMax:     v1.ImageWidthMax,
+			ImageHeightMax:    v1.ImageHeightMax,
 		},
 	}
 }
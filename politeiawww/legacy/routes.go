@@ -74,12 +74,21 @@ func (p *Politeiawww) setUserWWWRoutes() {
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteVerifyUpdateUserKey, p.handleVerifyUpdateUserKey,
 		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteRevokeUserKey, p.handleRevokeUserKey,
+		permissionLogin)
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteChangeUsername, p.handleChangeUsername,
 		permissionLogin)
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteChangePassword, p.handleChangePassword,
 		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteChangeEmail, p.handleChangeEmail,
+		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteVerifyChangeEmail, p.handleVerifyChangeEmail,
+		permissionLogin)
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteEditUser, p.handleEditUser,
 		permissionLogin)
@@ -95,6 +104,9 @@ func (p *Politeiawww) setUserWWWRoutes() {
 	p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
 		www.RouteUserProposalCredits, p.handleUserProposalCredits,
 		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteUserProposalCreditsTransfer,
+		p.handleUserProposalCreditsTransfer, permissionLogin)
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteSetTOTP, p.handleSetTOTP,
 		permissionLogin)
@@ -102,6 +114,31 @@ func (p *Politeiawww) setUserWWWRoutes() {
 		www.RouteVerifyTOTP, p.handleVerifyTOTP,
 		permissionLogin)
 
+	// Web push subscription routes are only registered when web push
+	// notifications have been enabled.
+	if p.push.IsEnabled() {
+		p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+			www.RoutePushSubscriptions, p.handlePushSubscriptions,
+			permissionLogin)
+		p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+			www.RoutePushSubscribe, p.handlePushSubscribe,
+			permissionLogin)
+		p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+			www.RoutePushUnsubscribe, p.handlePushUnsubscribe,
+			permissionLogin)
+	}
+
+	// API usage routes are only registered when usage tracking has
+	// been enabled.
+	if p.usage != nil {
+		p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+			www.RouteUserUsage, p.handleUserUsage,
+			permissionLogin)
+		p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+			www.RouteUsersUsage, p.handleUsersUsage,
+			permissionAdmin)
+	}
+
 	// Routes that require being logged in as an admin user.
 	p.addRoute(http.MethodPut, www.PoliteiaWWWAPIRoute,
 		www.RouteUserPaymentsRescan, p.handleUserPaymentsRescan,
@@ -109,6 +146,24 @@ func (p *Politeiawww) setUserWWWRoutes() {
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteManageUser, p.handleManageUser,
 		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RoutePendingActions, p.handlePendingActions,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RoutePendingActionConfirm, p.handlePendingActionConfirm,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RoutePendingActionReject, p.handlePendingActionReject,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteBanners, p.handleBanners,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteBannerSet, p.handleBannerSet,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteBannerDel, p.handleBannerDel,
+		permissionAdmin)
 }
 
 // setCMSUserWWWRoutes setsup the user routes for cms mode
@@ -144,12 +199,21 @@ func (p *Politeiawww) setCMSUserWWWRoutes() {
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteVerifyUpdateUserKey, p.handleVerifyUpdateUserKey,
 		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteRevokeUserKey, p.handleRevokeUserKey,
+		permissionLogin)
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteChangeUsername, p.handleChangeUsername,
 		permissionLogin)
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteChangePassword, p.handleChangePassword,
 		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteChangeEmail, p.handleChangeEmail,
+		permissionLogin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteVerifyChangeEmail, p.handleVerifyChangeEmail,
+		permissionLogin)
 	p.addRoute(http.MethodGet, cms.APIRoute,
 		www.RouteUserDetails, p.handleCMSUserDetails,
 		permissionLogin)
@@ -179,6 +243,24 @@ func (p *Politeiawww) setCMSUserWWWRoutes() {
 	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
 		www.RouteManageUser, p.handleManageUser,
 		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RoutePendingActions, p.handlePendingActions,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RoutePendingActionConfirm, p.handlePendingActionConfirm,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RoutePendingActionReject, p.handlePendingActionReject,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteBanners, p.handleBanners,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteBannerSet, p.handleBannerSet,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, www.PoliteiaWWWAPIRoute,
+		www.RouteBannerDel, p.handleBannerDel,
+		permissionAdmin)
 }
 
 func (p *Politeiawww) setCMSWWWRoutes() {
@@ -193,6 +275,9 @@ func (p *Politeiawww) setCMSWWWRoutes() {
 	p.addRoute(http.MethodGet, cms.APIRoute,
 		www.RoutePolicy, p.handleCMSPolicy,
 		permissionPublic)
+	p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+		www.RouteStatus, p.handleStatus,
+		permissionPublic)
 
 	// Routes that require being logged in.
 	p.addRoute(http.MethodPost, cms.APIRoute,
@@ -313,6 +398,26 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 		HandleFunc(www.PoliteiaWWWAPIRoute+www.RouteVersion, p.handleVersion).
 		Methods(http.MethodGet)
 
+	// The deprecations route returns the deprecation notices of all
+	// API routes.
+	p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+		www.RouteDeprecations, p.handleDeprecations,
+		permissionPublic)
+
+	// The status route returns the banners that are currently being
+	// displayed. It is polled by clients.
+	p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+		www.RouteStatus, p.handleStatus,
+		permissionPublic)
+
+	// The changes route is only registered when the change log has
+	// been enabled.
+	if p.changes != nil {
+		p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
+			www.RouteChanges, p.handleChanges,
+			permissionPublic)
+	}
+
 	// Legacy www routes. These routes have been DEPRECATED. Support
 	// will be removed in a future release.
 	p.addRoute(http.MethodGet, www.PoliteiaWWWAPIRoute,
@@ -394,8 +499,17 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 	p.addRoute(http.MethodPost, cmv1.APIRoute,
 		cmv1.RouteVote, c.HandleVote,
 		permissionLogin)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteSubscribe, c.HandleSubscribe,
+		permissionLogin)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RoutePin, c.HandlePin,
+		permissionLogin)
 	p.addRoute(http.MethodPost, cmv1.APIRoute,
 		cmv1.RouteDel, c.HandleDel,
+		permissionLogin)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteDelUser, c.HandleDelUser,
 		permissionAdmin)
 	p.addRoute(http.MethodPost, cmv1.APIRoute,
 		cmv1.RouteCount, c.HandleCount,
@@ -409,6 +523,30 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 	p.addRoute(http.MethodPost, cmv1.APIRoute,
 		cmv1.RouteTimestamps, c.HandleTimestamps,
 		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteTimestampsBatch, c.HandleTimestampsBatch,
+		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteSubmissionStatus, c.HandleSubmissionStatus,
+		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteStakeProof, c.HandleStakeProof,
+		permissionLogin)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteUserComments, c.HandleUserComments,
+		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteTree, c.HandleTree,
+		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteAttachments, c.HandleAttachments,
+		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteSearch, c.HandleSearch,
+		permissionPublic)
+	p.addRoute(http.MethodPost, cmv1.APIRoute,
+		cmv1.RouteHistory, c.HandleHistory,
+		permissionPublic)
 
 	// Ticket vote routes
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
@@ -420,6 +558,21 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteStart, t.HandleStart,
 		permissionAdmin)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteExtend, t.HandleExtend,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteCancel, t.HandleCancel,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteActiveVotesRebuild, t.HandleActiveVotesRebuild,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteSnapshotVerify, t.HandleSnapshotVerify,
+		permissionAdmin)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteSetVoteDuration, t.HandleSetVoteDuration,
+		permissionAdmin)
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteCastBallot, t.HandleCastBallot,
 		permissionPublic)
@@ -429,6 +582,9 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteResults, t.HandleResults,
 		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteStats, t.HandleStats,
+		permissionPublic)
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteSummaries, t.HandleSummaries,
 		permissionPublic)
@@ -441,6 +597,24 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteTimestamps, t.HandleTimestamps,
 		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteReceipts, t.HandleReceipts,
+		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteVote, t.HandleVote,
+		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteSubmissionStatus, t.HandleSubmissionStatus,
+		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteGovernanceExport, t.HandleGovernanceExport,
+		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteVoteParamsVerify, t.HandleVoteParamsVerify,
+		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteAuditBundle, t.HandleAuditBundle,
+		permissionPublic)
 
 	// Pi routes
 	p.addRoute(http.MethodPost, piv1.APIRoute,
@@ -455,6 +629,21 @@ func (p *Politeiawww) setPiRoutes(r *records.Records, c *comments.Comments, t *t
 	p.addRoute(http.MethodPost, piv1.APIRoute,
 		piv1.RouteSummaries, pic.HandleSummaries,
 		permissionPublic)
+	p.addRoute(http.MethodPost, piv1.APIRoute,
+		piv1.RouteProposalList, pic.HandleProposalList,
+		permissionPublic)
+	p.addRoute(http.MethodPost, piv1.APIRoute,
+		piv1.RouteIdentity, pic.HandleIdentity,
+		permissionPublic)
+	p.addRoute(http.MethodPost, piv1.APIRoute,
+		piv1.RouteCompletionReport, pic.HandleCompletionReport,
+		permissionPublic)
+	p.addRoute(http.MethodPost, piv1.APIRoute,
+		piv1.RouteWithdraw, pic.HandleWithdraw,
+		permissionLogin)
+	p.addRoute(http.MethodPost, piv1.APIRoute,
+		piv1.RouteWithdrawal, pic.HandleWithdrawalDetails,
+		permissionPublic)
 }
 
 // addRoute sets up a handler for a specific method+route. If method is not
@@ -467,12 +656,16 @@ func (p *Politeiawww) addRoute(method string, routeVersion string, route string,
 	}
 
 	fullRoute := routeVersion + route
+	handler = withDeprecationHeaders(fullRoute, handler)
 	switch perm {
 	case permissionAdmin:
 		handler = p.isLoggedInAsAdmin(handler)
 	case permissionLogin:
 		handler = p.isLoggedIn(handler)
 	}
+	if p.usage != nil && method != "" {
+		handler = p.withUsage(fullRoute, handler)
+	}
 
 	if method == "" {
 		// Websocket
@@ -20,6 +20,7 @@ import (
 	"github.com/decred/politeia/politeiawww/legacy/sessions"
 	"github.com/decred/politeia/politeiawww/legacy/user"
 	"github.com/decred/politeia/politeiawww/legacy/user/localdb"
+	"github.com/decred/politeia/politeiawww/legacy/webpush"
 	"github.com/decred/politeia/politeiawww/logger"
 	"github.com/decred/politeia/util"
 	"github.com/google/uuid"
@@ -260,6 +261,13 @@ func newTestPoliteiawww(t *testing.T) (*Politeiawww, func()) {
 		t.Fatal(err)
 	}
 
+	// Setup web push client
+	pushClient, err := webpush.NewClient(filepath.Join(dataDir, "webpush.key"),
+		"mailto:admin@example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	// Setup sessions
 	cookieKey, err := util.Random(32)
 	if err != nil {
@@ -274,7 +282,10 @@ func newTestPoliteiawww(t *testing.T) (*Politeiawww, func()) {
 		auth:            mux.NewRouter(),
 		sessions:        sessions.New(db, cookieKey),
 		mail:            mailClient,
+		push:            pushClient,
 		db:              db,
+		pendingActions:  db,
+		banners:         db,
 		test:            true,
 		userEmails:      make(map[string]uuid.UUID),
 		userPaywallPool: make(map[uuid.UUID]paywallPoolMember),
@@ -372,6 +383,8 @@ func newTestCMSwww(t *testing.T) (*Politeiawww, func()) {
 	p := Politeiawww{
 		cfg:             cfg,
 		db:              db,
+		pendingActions:  db,
+		banners:         db,
 		params:          chaincfg.TestNet3Params(),
 		router:          mux.NewRouter(),
 		auth:            mux.NewRouter(),
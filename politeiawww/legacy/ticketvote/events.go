@@ -15,6 +15,9 @@ const (
 
 	// EventTypeStart is emitted when a vote is started.
 	EventTypeStart = "ticketvote-start"
+
+	// EventTypeCastBallot is emitted when a ballot is cast.
+	EventTypeCastBallot = "ticketvote-castballot"
 )
 
 // EventAuthorize is the event data for EventTypeAuthorize.
@@ -28,3 +31,10 @@ type EventStart struct {
 	Starts []v1.StartDetails
 	User   user.User
 }
+
+// EventCastBallot is the event data for EventTypeCastBallot. Votes is the
+// number of votes in the ballot that were successfully cast.
+type EventCastBallot struct {
+	Token string
+	Votes uint32
+}
@@ -6,18 +6,21 @@ package ticketvote
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	v1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
 	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/decred/politeia/politeiawww/legacy/writequeue"
 )
 
 func (t *TicketVote) processAuthorize(ctx context.Context, a v1.Authorize, u user.User) (*v1.AuthorizeReply, error) {
 	log.Tracef("processAuthorize: %v", a.Token)
 
 	// Verify user signed with their active identity
-	if u.PublicKey() != a.PublicKey {
+	if !u.IsActivePublicKey(a.PublicKey) {
 		return nil, v1.UserErrorReply{
 			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 			ErrorContext: "not active identity",
@@ -75,7 +78,7 @@ func (t *TicketVote) processStart(ctx context.Context, s v1.Start, u user.User)
 
 	// Verify user signed with their active identity
 	for _, v := range s.Starts {
-		if u.PublicKey() != v.PublicKey {
+		if !u.IsActivePublicKey(v.PublicKey) {
 			return nil, v1.UserErrorReply{
 				ErrorCode:    v1.ErrorCodePublicKeyInvalid,
 				ErrorContext: "not active identity",
@@ -91,9 +94,9 @@ func (t *TicketVote) processStart(ctx context.Context, s v1.Start, u user.User)
 			// This is a runoff vote. Execute the plugin command on the
 			// parent record.
 			token = v.Params.Parent
-		case v1.VoteTypeStandard:
-			// This is a standard vote. Execute the plugin command on the
-			// record specified in the vote params.
+		case v1.VoteTypeStandard, v1.VoteTypeMultiOption:
+			// This is a standard or multi-option vote. Execute the
+			// plugin command on the record specified in the vote params.
 			token = v.Params.Token
 		}
 	}
@@ -118,6 +121,153 @@ func (t *TicketVote) processStart(ctx context.Context, s v1.Start, u user.User)
 		StartBlockHash:   tsr.StartBlockHash,
 		EndBlockHeight:   tsr.EndBlockHeight,
 		EligibleTickets:  tsr.EligibleTickets,
+
+		ConcurrentVotes:         tsr.ConcurrentVotes,
+		ConcurrentVotesExceeded: tsr.ConcurrentVotesExceeded,
+	}, nil
+}
+
+func (t *TicketVote) processExtend(ctx context.Context, e v1.Extend, u user.User) (*v1.ExtendReply, error) {
+	log.Tracef("processExtend: %v %v", e.Token, e.EndBlockHeight)
+
+	// Verify user signed with their active identity
+	if !u.IsActivePublicKey(e.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Send plugin command
+	te := ticketvote.Extend{
+		Token:          e.Token,
+		EndBlockHeight: e.EndBlockHeight,
+		Reason:         e.Reason,
+		PublicKey:      e.PublicKey,
+		Signature:      e.Signature,
+	}
+	ter, err := t.politeiad.TicketVoteExtend(ctx, e.Token, te)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ExtendReply{
+		Timestamp: ter.Timestamp,
+		Receipt:   ter.Receipt,
+	}, nil
+}
+
+func (t *TicketVote) processCancel(ctx context.Context, c v1.Cancel, u user.User) (*v1.CancelReply, error) {
+	log.Tracef("processCancel: %v", c.Token)
+
+	// Verify user signed with their active identity
+	if !u.IsActivePublicKey(c.PublicKey) {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Send plugin command
+	tc := ticketvote.Cancel{
+		Token:     c.Token,
+		Reason:    c.Reason,
+		PublicKey: c.PublicKey,
+		Signature: c.Signature,
+	}
+	tcr, err := t.politeiad.TicketVoteCancel(ctx, c.Token, tc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CancelReply{
+		Timestamp: tcr.Timestamp,
+		Receipt:   tcr.Receipt,
+	}, nil
+}
+
+// processActiveVotesRebuild rebuilds the politeiad active votes cache.
+func (t *TicketVote) processActiveVotesRebuild(ctx context.Context, avr v1.ActiveVotesRebuild, u user.User) (*v1.ActiveVotesRebuildReply, error) {
+	log.Tracef("processActiveVotesRebuild: %v", u.Username)
+
+	// Send plugin command
+	r, err := t.politeiad.TicketVoteActiveVotesRebuild(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Active votes cache rebuilt by %v: %v active, %v added, "+
+		"%v removed", u.Username, len(r.Tokens), len(r.Added),
+		len(r.Removed))
+
+	return &v1.ActiveVotesRebuildReply{
+		Tokens:  r.Tokens,
+		Added:   r.Added,
+		Removed: r.Removed,
+	}, nil
+}
+
+// processSetVoteDuration sets the vote duration bounds. The policy is updated
+// once politeiad has applied the new bounds.
+func (t *TicketVote) processSetVoteDuration(ctx context.Context, svd v1.SetVoteDuration, u user.User) (*v1.SetVoteDurationReply, error) {
+	log.Tracef("processSetVoteDuration: %v %v %v",
+		svd.Min, svd.Max, u.Username)
+
+	// Send plugin command
+	r, err := t.politeiad.TicketVoteSetVoteDuration(ctx,
+		ticketvote.SetVoteDuration{
+			Min: svd.Min,
+			Max: svd.Max,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the policy
+	t.policyMtx.Lock()
+	t.policy.VoteDurationMin = r.Min
+	t.policy.VoteDurationMax = r.Max
+	t.policyMtx.Unlock()
+
+	log.Infof("Vote duration bounds set to %v-%v blocks by %v",
+		r.Min, r.Max, u.Username)
+
+	return &v1.SetVoteDurationReply{
+		Min: r.Min,
+		Max: r.Max,
+	}, nil
+}
+
+// processSnapshotVerify re-verifies the eligible ticket snapshot of a record
+// vote against the chain.
+func (t *TicketVote) processSnapshotVerify(ctx context.Context, sv v1.SnapshotVerify, u user.User) (*v1.SnapshotVerifyReply, error) {
+	log.Tracef("processSnapshotVerify: %v %v", sv.Token, u.Username)
+
+	// Send plugin command
+	r, err := t.politeiad.TicketVoteSnapshotVerify(ctx, sv.Token)
+	if err != nil {
+		return nil, err
+	}
+	v := r.Verification
+
+	log.Infof("Eligible ticket snapshot verified by %v: %v match %v",
+		u.Username, v.Token, v.Match)
+
+	return &v1.SnapshotVerifyReply{
+		Verification: v1.SnapshotVerification{
+			Token:            v.Token,
+			StartBlockHeight: v.StartBlockHeight,
+			StartBlockHash:   v.StartBlockHash,
+			SnapshotCount:    v.SnapshotCount,
+			SnapshotDigest:   v.SnapshotDigest,
+			ChainCount:       v.ChainCount,
+			ChainDigest:      v.ChainDigest,
+			Match:            v.Match,
+			Missing:          v.Missing,
+			Extra:            v.Extra,
+			Timestamp:        v.Timestamp,
+			Receipt:          v.Receipt,
+		},
 	}, nil
 }
 
@@ -137,14 +287,121 @@ func (t *TicketVote) processCastBallot(ctx context.Context, cb v1.CastBallot) (*
 	}
 	tcbr, err := t.politeiad.TicketVoteCastBallot(ctx, token, tcb)
 	if err != nil {
-		return nil, err
+		if t.queue == nil || !writequeue.IsRetryable(err) {
+			return nil, err
+		}
+
+		// politeiad is unavailable. Queue the ballot so that it is
+		// submitted once politeiad becomes available again.
+		s, err := t.queue.Add(submissionTypeCastBallot,
+			queuedCastBallot{
+				Token:  token,
+				Ballot: tcb,
+			})
+		if err != nil {
+			return nil, err
+		}
+		return &v1.CastBallotReply{
+			Receipts:     []v1.CastVoteReply{},
+			SubmissionID: s.ID,
+		}, nil
 	}
 
+	t.emitCastBallot(token, tcbr.Receipts)
+
 	return &v1.CastBallotReply{
 		Receipts: convertCastVoteRepliesToV1(tcbr.Receipts),
 	}, nil
 }
 
+// submissionTypeCastBallot is the write queue submission type for ballots.
+const submissionTypeCastBallot = "ticketvote-castballot"
+
+// queuedCastBallot is the write queue payload for a ballot.
+type queuedCastBallot struct {
+	Token  string                `json:"token"`
+	Ballot ticketvote.CastBallot `json:"ballot"`
+}
+
+// execQueuedCastBallot sends a queued ballot to politeiad. It satisfies the
+// writequeue ExecFunc type.
+func (t *TicketVote) execQueuedCastBallot(ctx context.Context, payload []byte) ([]byte, error) {
+	var qcb queuedCastBallot
+	err := json.Unmarshal(payload, &qcb)
+	if err != nil {
+		return nil, err
+	}
+	tcbr, err := t.politeiad.TicketVoteCastBallot(ctx, qcb.Token, qcb.Ballot)
+	if err != nil {
+		return nil, err
+	}
+	t.emitCastBallot(qcb.Token, tcbr.Receipts)
+	return json.Marshal(v1.CastBallotReply{
+		Receipts: convertCastVoteRepliesToV1(tcbr.Receipts),
+	})
+}
+
+// emitCastBallot emits a cast ballot event if any of the votes in the ballot
+// were successfully cast.
+func (t *TicketVote) emitCastBallot(token string, receipts []ticketvote.CastVoteReply) {
+	var votes uint32
+	for _, v := range receipts {
+		if v.ErrorCode == nil {
+			votes++
+		}
+	}
+	if votes == 0 {
+		return
+	}
+	t.events.Emit(EventTypeCastBallot,
+		EventCastBallot{
+			Token: token,
+			Votes: votes,
+		})
+}
+
+func (t *TicketVote) processSubmissionStatus(ss v1.SubmissionStatus) (*v1.SubmissionStatusReply, error) {
+	log.Tracef("processSubmissionStatus: %v", ss.SubmissionID)
+
+	if t.queue == nil {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodeSubmissionNotFound,
+		}
+	}
+	s, err := t.queue.Get(submissionTypeCastBallot, ss.SubmissionID)
+	if err != nil {
+		if errors.Is(err, writequeue.ErrNotFound) {
+			return nil, v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeSubmissionNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	// Prepare reply
+	ssr := v1.SubmissionStatusReply{
+		Timestamp: s.Timestamp,
+		Attempts:  s.Attempts,
+		Error:     s.Error,
+	}
+	switch s.Status {
+	case writequeue.StatusPending:
+		ssr.Status = v1.SubmissionStatusPending
+	case writequeue.StatusComplete:
+		var cbr v1.CastBallotReply
+		err = json.Unmarshal(s.Reply, &cbr)
+		if err != nil {
+			return nil, err
+		}
+		ssr.Status = v1.SubmissionStatusComplete
+		ssr.Reply = &cbr
+	case writequeue.StatusFailed:
+		ssr.Status = v1.SubmissionStatusFailed
+	}
+
+	return &ssr, nil
+}
+
 func (t *TicketVote) processDetails(ctx context.Context, d v1.Details) (*v1.DetailsReply, error) {
 	log.Tracef("processsDetails: %v", d.Token)
 
@@ -160,8 +417,10 @@ func (t *TicketVote) processDetails(ctx context.Context, d v1.Details) (*v1.Deta
 	}
 
 	return &v1.DetailsReply{
-		Auths: convertAuthDetailsToV1(tdr.Auths),
-		Vote:  vote,
+		Auths:         convertAuthDetailsToV1(tdr.Auths),
+		Vote:          vote,
+		Extensions:    convertExtendDetailsToV1(tdr.Extensions),
+		Cancellations: convertCancelDetailsToV1(tdr.Cancellations),
 	}, nil
 }
 
@@ -215,12 +474,24 @@ func (t *TicketVote) processSubmissions(ctx context.Context, s v1.Submissions) (
 }
 
 func (t *TicketVote) processInventory(ctx context.Context, i v1.Inventory) (*v1.InventoryReply, error) {
-	log.Tracef("processInventory: %v %v", i.Status, i.Page)
+	log.Tracef("processInventory: %v %v %v %v", i.Status, i.Page,
+		i.PageSize, i.StartedAfter)
+
+	// Verify page size
+	if i.PageSize > t.policy.InventoryPageSize {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodePageSizeExceeded,
+			ErrorContext: fmt.Sprintf("max page size is %v",
+				t.policy.InventoryPageSize),
+		}
+	}
 
 	// Get inventory
 	ti := ticketvote.Inventory{
-		Status: convertVoteStatusToPlugin(i.Status),
-		Page:   i.Page,
+		Status:       convertVoteStatusToPlugin(i.Status),
+		Page:         i.Page,
+		PageSize:     i.PageSize,
+		StartedAfter: i.StartedAfter,
 	}
 	ir, err := t.politeiad.TicketVoteInventory(ctx, ti)
 	if err != nil {
@@ -228,8 +499,9 @@ func (t *TicketVote) processInventory(ctx context.Context, i v1.Inventory) (*v1.
 	}
 
 	return &v1.InventoryReply{
-		Vetted:    ir.Tokens,
-		BestBlock: ir.BestBlock,
+		Vetted:     ir.Tokens,
+		Timestamps: ir.Timestamps,
+		BestBlock:  ir.BestBlock,
 	}, nil
 }
 
@@ -238,7 +510,9 @@ func (t *TicketVote) processTimestamps(ctx context.Context, ts v1.Timestamps) (*
 
 	// Send plugin command
 	tt := ticketvote.Timestamps{
-		VotesPage: ts.VotesPage,
+		VotesPage:     ts.VotesPage,
+		VotesPageSize: ts.VotesPageSize,
+		DigestsOnly:   ts.DigestsOnly,
 	}
 	tsr, err := t.politeiad.TicketVoteTimestamps(ctx, ts.Token, tt)
 	if err != nil {
@@ -264,9 +538,162 @@ func (t *TicketVote) processTimestamps(ctx context.Context, ts v1.Timestamps) (*
 	}
 
 	return &v1.TimestampsReply{
-		Auths:   auths,
-		Details: details,
-		Votes:   votes,
+		Auths:      auths,
+		Details:    details,
+		Votes:      votes,
+		VotesCount: tsr.VotesCount,
+	}, nil
+}
+
+func (t *TicketVote) processReceipts(ctx context.Context, r v1.Receipts) (*v1.ReceiptsReply, error) {
+	log.Tracef("processReceipts: %v %v", r.Token, len(r.Tickets))
+
+	// Verify request size
+	if len(r.Tickets) > int(t.policy.TimestampsPageSize) {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodePageSizeExceeded,
+			ErrorContext: fmt.Sprintf("max page size is %v",
+				t.policy.TimestampsPageSize),
+		}
+	}
+
+	// Send plugin command
+	tr := ticketvote.Receipts{
+		Tickets: r.Tickets,
+	}
+	rr, err := t.politeiad.TicketVoteReceipts(ctx, r.Token, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ReceiptsReply{
+		Receipts: convertVoteReceiptsToV1(rr.Receipts),
+	}, nil
+}
+
+func (t *TicketVote) processStats(ctx context.Context, s v1.Stats) (*v1.StatsReply, error) {
+	log.Tracef("processStats: %v", s.Token)
+
+	// Send plugin command
+	vsr, err := t.politeiad.TicketVoteStats(ctx, s.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]v1.StatsDay, 0, len(vsr.Days))
+	for _, v := range vsr.Days {
+		days = append(days, v1.StatsDay{
+			Day:      v.Day,
+			Votes:    v.Votes,
+			Approve:  v.Approve,
+			Reject:   v.Reject,
+			Approval: v.Approval,
+		})
+	}
+
+	return &v1.StatsReply{
+		Status:          convertVoteStatusToV1(vsr.Status),
+		EligibleTickets: vsr.EligibleTickets,
+		UniqueTickets:   vsr.UniqueTickets,
+		Turnout:         vsr.Turnout,
+		Approval:        vsr.Approval,
+		Days:            days,
+	}, nil
+}
+
+func (t *TicketVote) processVote(ctx context.Context, v v1.Vote) (*v1.VoteReply, error) {
+	log.Tracef("processVote: %v %v", v.Token, v.Ticket)
+
+	// Send plugin command
+	tv := ticketvote.Vote{
+		Ticket: v.Ticket,
+	}
+	vr, err := t.politeiad.TicketVoteVote(ctx, v.Token, tv)
+	if err != nil {
+		return nil, err
+	}
+	cvds := convertCastVoteDetailsToV1([]ticketvote.CastVoteDetails{vr.Vote})
+
+	return &v1.VoteReply{
+		Vote: cvds[0],
+	}, nil
+}
+
+// processGovernanceExport returns the governance export of all finished
+// votes. The export data uses the same schema in the plugin and the v1 API,
+// so it is returned as is.
+func (t *TicketVote) processGovernanceExport(ctx context.Context, ge v1.GovernanceExport) (*v1.GovernanceExportReply, error) {
+	log.Tracef("processGovernanceExport: %v", ge.Offset)
+
+	// Send plugin command
+	r, err := t.politeiad.TicketVoteGovernanceExport(ctx,
+		ticketvote.GovernanceExport{
+			Offset: ge.Offset,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.GovernanceExportReply{
+		Manifest: v1.GovernanceExportManifest{
+			SchemaVersion: r.Manifest.SchemaVersion,
+			Format:        r.Manifest.Format,
+			Count:         r.Manifest.Count,
+			Size:          r.Manifest.Size,
+			SHA256:        r.Manifest.SHA256,
+			Updated:       r.Manifest.Updated,
+		},
+		Data: r.Data,
+	}, nil
+}
+
+// processVoteParamsVerify verifies a set of vote params without starting a
+// vote.
+func (t *TicketVote) processVoteParamsVerify(ctx context.Context, vpv v1.VoteParamsVerify) (*v1.VoteParamsVerifyReply, error) {
+	log.Tracef("processVoteParamsVerify")
+
+	r, err := t.politeiad.TicketVoteVoteParamsVerify(ctx,
+		ticketvote.VoteParamsVerify{
+			Params: convertVoteParamsToPlugin(vpv.Params),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	vbe := make([]v1.VoteBitError, 0, len(r.VoteBitErrors))
+	for _, v := range r.VoteBitErrors {
+		vbe = append(vbe, v1.VoteBitError{
+			ErrorCode: v1.VoteBitErrorT(v.ErrorCode),
+			OptionID:  v.OptionID,
+			Bit:       v.Bit,
+			Mask:      v.Mask,
+		})
+	}
+
+	return &v1.VoteParamsVerifyReply{
+		Valid:         r.Valid,
+		ErrorCode:     uint32(r.ErrorCode),
+		ErrorContext:  r.ErrorContext,
+		VoteBitErrors: vbe,
+	}, nil
+}
+
+// processAuditBundle returns the signed audit bundle of a finished record
+// vote. The bundle is returned exactly as it was signed by politeiad so that
+// the signature can be verified by the client.
+func (t *TicketVote) processAuditBundle(ctx context.Context, ab v1.AuditBundle) (*v1.AuditBundleReply, error) {
+	log.Tracef("processAuditBundle: %v", ab.Token)
+
+	r, err := t.politeiad.TicketVoteAuditBundle(ctx, ab.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.AuditBundleReply{
+		Bundle:    r.Bundle,
+		Digest:    r.Digest,
+		PublicKey: r.PublicKey,
+		Signature: r.Signature,
 	}, nil
 }
 
@@ -297,6 +724,8 @@ func convertVoteTypeToPlugin(t v1.VoteT) ticketvote.VoteT {
 		return ticketvote.VoteTypeStandard
 	case v1.VoteTypeRunoff:
 		return ticketvote.VoteTypeRunoff
+	case v1.VoteTypeMultiOption:
+		return ticketvote.VoteTypeMultiOption
 	}
 	return ticketvote.VoteTypeInvalid
 }
@@ -311,6 +740,9 @@ func convertVoteParamsToPlugin(v v1.VoteParams) ticketvote.VoteParams {
 		QuorumPercentage: v.QuorumPercentage,
 		PassPercentage:   v.PassPercentage,
 		Parent:           v.Parent,
+		WinCondition:     ticketvote.WinConditionT(v.WinCondition),
+		Winners:          v.Winners,
+		Weight:           ticketvote.VoteWeightT(v.Weight),
 	}
 	// Convert vote options
 	vo := make([]ticketvote.VoteOption, 0, len(v.Options))
@@ -328,9 +760,10 @@ func convertVoteParamsToPlugin(v v1.VoteParams) ticketvote.VoteParams {
 
 func convertStartDetailsToPlugin(sd v1.StartDetails) ticketvote.StartDetails {
 	return ticketvote.StartDetails{
-		Params:    convertVoteParamsToPlugin(sd.Params),
-		PublicKey: sd.PublicKey,
-		Signature: sd.Signature,
+		Params:         convertVoteParamsToPlugin(sd.Params),
+		PublicKey:      sd.PublicKey,
+		Signature:      sd.Signature,
+		SnapshotHeight: sd.SnapshotHeight,
 	}
 }
 
@@ -363,6 +796,8 @@ func convertVoteTypeToV1(t ticketvote.VoteT) v1.VoteT {
 		return v1.VoteTypeStandard
 	case ticketvote.VoteTypeRunoff:
 		return v1.VoteTypeRunoff
+	case ticketvote.VoteTypeMultiOption:
+		return v1.VoteTypeMultiOption
 	}
 	return v1.VoteTypeInvalid
 
@@ -377,6 +812,9 @@ func convertVoteParamsToV1(v ticketvote.VoteParams) v1.VoteParams {
 		Duration:         v.Duration,
 		QuorumPercentage: v.QuorumPercentage,
 		PassPercentage:   v.PassPercentage,
+		WinCondition:     v1.WinConditionT(v.WinCondition),
+		Winners:          v.Winners,
+		Weight:           v1.VoteWeightT(v.Weight),
 	}
 	vo := make([]v1.VoteOption, 0, len(v.Options))
 	for _, o := range v.Options {
@@ -427,21 +865,36 @@ func convertCastVoteRepliesToV1(replies []ticketvote.CastVoteReply) []v1.CastVot
 			Receipt:      v.Receipt,
 			ErrorCode:    convertVoteErrorToV1(v.ErrorCode),
 			ErrorContext: v.ErrorContext,
+			Diagnostics:  convertVoteDiagnosticsToV1(v.Diagnostics),
 		})
 	}
 	return r
 }
 
+func convertVoteDiagnosticsToV1(d *ticketvote.VoteDiagnostics) *v1.VoteDiagnostics {
+	if d == nil {
+		return nil
+	}
+	return &v1.VoteDiagnostics{
+		Reason:         d.Reason,
+		SnapshotHeight: d.SnapshotHeight,
+		EndBlockHeight: d.EndBlockHeight,
+		BestBlock:      d.BestBlock,
+		CommitmentAddr: d.CommitmentAddr,
+	}
+}
+
 func convertVoteDetailsToV1(vd ticketvote.VoteDetails) v1.VoteDetails {
 	return v1.VoteDetails{
-		Params:           convertVoteParamsToV1(vd.Params),
-		PublicKey:        vd.PublicKey,
-		Signature:        vd.Signature,
-		Receipt:          vd.Receipt,
-		StartBlockHeight: vd.StartBlockHeight,
-		StartBlockHash:   vd.StartBlockHash,
-		EndBlockHeight:   vd.EndBlockHeight,
-		EligibleTickets:  vd.EligibleTickets,
+		Params:                convertVoteParamsToV1(vd.Params),
+		PublicKey:             vd.PublicKey,
+		Signature:             vd.Signature,
+		Receipt:               vd.Receipt,
+		StartBlockHeight:      vd.StartBlockHeight,
+		StartBlockHash:        vd.StartBlockHash,
+		EndBlockHeight:        vd.EndBlockHeight,
+		EligibleTickets:       vd.EligibleTickets,
+		EligibleTicketsDigest: vd.EligibleTicketsDigest,
 	}
 }
 
@@ -449,18 +902,53 @@ func convertAuthDetailsToV1(auths []ticketvote.AuthDetails) []v1.AuthDetails {
 	a := make([]v1.AuthDetails, 0, len(auths))
 	for _, v := range auths {
 		a = append(a, v1.AuthDetails{
-			Token:     v.Token,
-			Version:   v.Version,
-			Action:    v.Action,
-			PublicKey: v.PublicKey,
-			Signature: v.Signature,
-			Timestamp: v.Timestamp,
-			Receipt:   v.Receipt,
+			Token:       v.Token,
+			Version:     v.Version,
+			Action:      v.Action,
+			PublicKey:   v.PublicKey,
+			Signature:   v.Signature,
+			Timestamp:   v.Timestamp,
+			Receipt:     v.Receipt,
+			BlockHeight: v.BlockHeight,
 		})
 	}
 	return a
 }
 
+func convertExtendDetailsToV1(exts []ticketvote.ExtendDetails) []v1.ExtendDetails {
+	e := make([]v1.ExtendDetails, 0, len(exts))
+	for _, v := range exts {
+		e = append(e, v1.ExtendDetails{
+			Token:              v.Token,
+			EndBlockHeight:     v.EndBlockHeight,
+			Reason:             v.Reason,
+			PublicKey:          v.PublicKey,
+			Signature:          v.Signature,
+			PrevEndBlockHeight: v.PrevEndBlockHeight,
+			Timestamp:          v.Timestamp,
+			Receipt:            v.Receipt,
+		})
+	}
+	return e
+}
+
+func convertCancelDetailsToV1(cancels []ticketvote.CancelDetails) []v1.CancelDetails {
+	c := make([]v1.CancelDetails, 0, len(cancels))
+	for _, v := range cancels {
+		c = append(c, v1.CancelDetails{
+			Token:            v.Token,
+			Reason:           v.Reason,
+			PublicKey:        v.PublicKey,
+			Signature:        v.Signature,
+			StartBlockHeight: v.StartBlockHeight,
+			EndBlockHeight:   v.EndBlockHeight,
+			Timestamp:        v.Timestamp,
+			Receipt:          v.Receipt,
+		})
+	}
+	return c
+}
+
 func convertCastVoteDetailsToV1(votes []ticketvote.CastVoteDetails) []v1.CastVoteDetails {
 	vs := make([]v1.CastVoteDetails, 0, len(votes))
 	for _, v := range votes {
@@ -472,6 +960,7 @@ func convertCastVoteDetailsToV1(votes []ticketvote.CastVoteDetails) []v1.CastVot
 			Signature: v.Signature,
 			Receipt:   v.Receipt,
 			Timestamp: v.Timestamp,
+			Stake:     v.Stake,
 		})
 	}
 	return vs
@@ -508,6 +997,7 @@ func convertSummaryToV1(s ticketvote.SummaryReply) v1.Summary {
 			Description: v.Description,
 			VoteBit:     v.VoteBit,
 			Votes:       v.Votes,
+			Stake:       v.Stake,
 		})
 	}
 	return v1.Summary{
@@ -521,7 +1011,17 @@ func convertSummaryToV1(s ticketvote.SummaryReply) v1.Summary {
 		QuorumPercentage: s.QuorumPercentage,
 		PassPercentage:   s.PassPercentage,
 		Results:          results,
-		BestBlock:        s.BestBlock,
+		WinCondition:     v1.WinConditionT(s.WinCondition),
+		WinningOption:    s.WinningOption,
+		Winners:          s.Winners,
+		Weight:           v1.VoteWeightT(s.Weight),
+		EligibleStake:    s.EligibleStake,
+		FinishedEarly:    s.FinishedEarly,
+
+		QuorumPercentageAchieved: s.QuorumPercentageAchieved,
+		ApprovalPercentage:       s.ApprovalPercentage,
+		RejectionReason:          v1.RejectionReasonT(s.RejectionReason),
+		BestBlock:                s.BestBlock,
 	}
 }
 
@@ -556,3 +1056,20 @@ func convertTimestampToV1(t ticketvote.Timestamp) v1.Timestamp {
 		Proofs:     proofs,
 	}
 }
+
+func convertVoteReceiptsToV1(receipts []ticketvote.VoteReceipt) []v1.VoteReceipt {
+	votes := make([]ticketvote.CastVoteDetails, 0, len(receipts))
+	for _, v := range receipts {
+		votes = append(votes, v.Vote)
+	}
+	cvds := convertCastVoteDetailsToV1(votes)
+	r := make([]v1.VoteReceipt, 0, len(receipts))
+	for i, v := range receipts {
+		// receipts and cvds share the same ordering
+		r = append(r, v1.VoteReceipt{
+			Vote:      cvds[i],
+			Timestamp: convertTimestampToV1(v.Timestamp),
+		})
+	}
+	return r
+}
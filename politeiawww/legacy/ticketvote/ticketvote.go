@@ -9,15 +9,17 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	pdclient "github.com/decred/politeia/politeiad/client"
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	v1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
 	"github.com/decred/politeia/politeiawww/config"
+	"github.com/decred/politeia/politeiawww/legacy/enums"
 	"github.com/decred/politeia/politeiawww/legacy/events"
 	"github.com/decred/politeia/politeiawww/legacy/sessions"
-	"github.com/decred/politeia/util"
+	"github.com/decred/politeia/politeiawww/legacy/writequeue"
 )
 
 // TicketVote is the context for the ticketvote API.
@@ -26,6 +28,11 @@ type TicketVote struct {
 	politeiad *pdclient.Client
 	sessions  *sessions.Sessions
 	events    *events.Manager
+	queue     *writequeue.Queue // Nil if the write queue is disabled
+
+	// policyMtx protects the vote duration bounds of the policy. They
+	// can be updated at runtime using the SetVoteDuration route.
+	policyMtx sync.RWMutex
 	policy    *v1.PolicyReply
 }
 
@@ -33,7 +40,20 @@ type TicketVote struct {
 func (t *TicketVote) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, t.policy)
+	t.policyMtx.RLock()
+	policy := *t.policy
+	t.policyMtx.RUnlock()
+
+	// Add the current vote load
+	vlr, err := t.politeiad.TicketVoteLoad(r.Context())
+	if err != nil {
+		respondWithError(w, r,
+			"HandlePolicy: TicketVoteLoad: %v", err)
+		return
+	}
+	policy.ActiveVotes = vlr.ActiveVotes
+
+	enums.RespondWithJSON(w, r, http.StatusOK, policy)
 }
 
 // HandleAuthorize is the request handler for the ticketvote v1 Authorize
@@ -65,7 +85,7 @@ func (t *TicketVote) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, ar)
+	enums.RespondWithJSON(w, r, http.StatusOK, ar)
 }
 
 // HandleStart is the requeset handler for the ticketvote v1 Start route.
@@ -96,7 +116,165 @@ func (t *TicketVote) HandleStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, sr)
+	enums.RespondWithJSON(w, r, http.StatusOK, sr)
+}
+
+// HandleExtend is the request handler for the ticketvote v1 Extend route.
+func (t *TicketVote) HandleExtend(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleExtend")
+
+	var e v1.Extend
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&e); err != nil {
+		respondWithError(w, r, "HandleExtend: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := t.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleExtend: GetSessionUser: %v", err)
+		return
+	}
+
+	er, err := t.processExtend(r.Context(), e, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleExtend: processExtend: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, er)
+}
+
+// HandleCancel is the request handler for the ticketvote v1 Cancel route.
+func (t *TicketVote) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleCancel")
+
+	var c v1.Cancel
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&c); err != nil {
+		respondWithError(w, r, "HandleCancel: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := t.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleCancel: GetSessionUser: %v", err)
+		return
+	}
+
+	cr, err := t.processCancel(r.Context(), c, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleCancel: processCancel: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, cr)
+}
+
+// HandleActiveVotesRebuild is the request handler for the ticketvote v1
+// ActiveVotesRebuild route.
+func (t *TicketVote) HandleActiveVotesRebuild(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleActiveVotesRebuild")
+
+	var avr v1.ActiveVotesRebuild
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&avr); err != nil {
+		respondWithError(w, r, "HandleActiveVotesRebuild: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := t.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleActiveVotesRebuild: GetSessionUser: %v", err)
+		return
+	}
+
+	avrr, err := t.processActiveVotesRebuild(r.Context(), avr, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleActiveVotesRebuild: processActiveVotesRebuild: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, avrr)
+}
+
+// HandleSetVoteDuration is the request handler for the ticketvote v1
+// SetVoteDuration route.
+func (t *TicketVote) HandleSetVoteDuration(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSetVoteDuration")
+
+	var svd v1.SetVoteDuration
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&svd); err != nil {
+		respondWithError(w, r, "HandleSetVoteDuration: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := t.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSetVoteDuration: GetSessionUser: %v", err)
+		return
+	}
+
+	svdr, err := t.processSetVoteDuration(r.Context(), svd, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSetVoteDuration: processSetVoteDuration: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, svdr)
+}
+
+// HandleSnapshotVerify is the request handler for the ticketvote v1
+// SnapshotVerify route.
+func (t *TicketVote) HandleSnapshotVerify(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSnapshotVerify")
+
+	var sv v1.SnapshotVerify
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&sv); err != nil {
+		respondWithError(w, r, "HandleSnapshotVerify: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := t.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSnapshotVerify: GetSessionUser: %v", err)
+		return
+	}
+
+	svr, err := t.processSnapshotVerify(r.Context(), sv, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSnapshotVerify: processSnapshotVerify: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, svr)
 }
 
 // HandleCastBallot is the request handler for the ticketvote v1 CastBallot
@@ -121,7 +299,32 @@ func (t *TicketVote) HandleCastBallot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, cbr)
+	enums.RespondWithJSON(w, r, http.StatusOK, cbr)
+}
+
+// HandleSubmissionStatus is the request handler for the ticketvote v1
+// SubmissionStatus route.
+func (t *TicketVote) HandleSubmissionStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSubmissionStatus")
+
+	var ss v1.SubmissionStatus
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ss); err != nil {
+		respondWithError(w, r, "HandleSubmissionStatus: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	ssr, err := t.processSubmissionStatus(ss)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSubmissionStatus: processSubmissionStatus: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, ssr)
 }
 
 // HandleDetails is the request handler for the ticketvote v1 Details route.
@@ -145,7 +348,7 @@ func (t *TicketVote) HandleDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, dr)
+	enums.RespondWithJSON(w, r, http.StatusOK, dr)
 }
 
 // HandleResults is the request handler for the ticketvote v1 Results route.
@@ -169,7 +372,7 @@ func (t *TicketVote) HandleResults(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, rsr)
+	enums.RespondWithJSON(w, r, http.StatusOK, rsr)
 }
 
 // HandleSummaries is the request handler for the ticketvote v1 Summaries
@@ -194,7 +397,7 @@ func (t *TicketVote) HandleSummaries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, sr)
+	enums.RespondWithJSON(w, r, http.StatusOK, sr)
 }
 
 // HandleSubmissions is the request handler for the ticketvote v1 Submissions
@@ -219,7 +422,7 @@ func (t *TicketVote) HandleSubmissions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, sr)
+	enums.RespondWithJSON(w, r, http.StatusOK, sr)
 }
 
 // HandleInventory is the request handler for the ticketvote v1 Inventory
@@ -244,7 +447,7 @@ func (t *TicketVote) HandleInventory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, ir)
+	enums.RespondWithJSON(w, r, http.StatusOK, ir)
 }
 
 // HandleTimestamps is the request handler for the ticketvote v1 Timestamps
@@ -269,11 +472,158 @@ func (t *TicketVote) HandleTimestamps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondWithJSON(w, http.StatusOK, tsr)
+	enums.RespondWithJSON(w, r, http.StatusOK, tsr)
+}
+
+// HandleReceipts is the request handler for the ticketvote v1 Receipts route.
+func (t *TicketVote) HandleReceipts(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleReceipts")
+
+	var rc v1.Receipts
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&rc); err != nil {
+		respondWithError(w, r, "HandleReceipts: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	rr, err := t.processReceipts(r.Context(), rc)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleReceipts: processReceipts: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, rr)
+}
+
+// HandleStats is the request handler for the ticketvote v1 Stats route.
+func (t *TicketVote) HandleStats(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleStats")
+
+	var s v1.Stats
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&s); err != nil {
+		respondWithError(w, r, "HandleStats: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	sr, err := t.processStats(r.Context(), s)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleStats: processStats: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, sr)
+}
+
+// HandleVote is the request handler for the ticketvote v1 Vote route.
+func (t *TicketVote) HandleVote(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleVote")
+
+	var v v1.Vote
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&v); err != nil {
+		respondWithError(w, r, "HandleVote: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	vr, err := t.processVote(r.Context(), v)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleVote: processVote: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, vr)
+}
+
+// HandleGovernanceExport is the request handler for the ticketvote v1
+// GovernanceExport route.
+func (t *TicketVote) HandleGovernanceExport(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleGovernanceExport")
+
+	var ge v1.GovernanceExport
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ge); err != nil {
+		respondWithError(w, r, "HandleGovernanceExport: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	ger, err := t.processGovernanceExport(r.Context(), ge)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleGovernanceExport: processGovernanceExport: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, ger)
+}
+
+// HandleVoteParamsVerify is the request handler for the ticketvote v1
+// VoteParamsVerify route.
+func (t *TicketVote) HandleVoteParamsVerify(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleVoteParamsVerify")
+
+	var vpv v1.VoteParamsVerify
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&vpv); err != nil {
+		respondWithError(w, r, "HandleVoteParamsVerify: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	vpvr, err := t.processVoteParamsVerify(r.Context(), vpv)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleVoteParamsVerify: processVoteParamsVerify: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, vpvr)
+}
+
+// HandleAuditBundle is the request handler for the ticketvote v1 AuditBundle
+// route.
+func (t *TicketVote) HandleAuditBundle(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleAuditBundle")
+
+	var ab v1.AuditBundle
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ab); err != nil {
+		respondWithError(w, r, "HandleAuditBundle: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	abr, err := t.processAuditBundle(r.Context(), ab)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleAuditBundle: processAuditBundle: %v", err)
+		return
+	}
+
+	enums.RespondWithJSON(w, r, http.StatusOK, abr)
 }
 
 // New returns a new TicketVote context.
-func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*TicketVote, error) {
+func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *events.Manager, q *writequeue.Queue, plugins []pdv2.Plugin) (*TicketVote, error) {
 	// Parse plugin settings
 	var (
 		linkByPeriodMin    int64
@@ -283,6 +633,11 @@ func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *even
 		summariesPageSize  uint32
 		inventoryPageSize  uint32
 		timestampsPageSize uint32
+		voteExtensionMax   uint32
+		authLockBlocks     uint32
+
+		concurrentVotesMax   uint32
+		concurrentVotesBlock bool
 	)
 	for _, p := range plugins {
 		if p.ID != ticketvote.PluginID {
@@ -340,6 +695,34 @@ func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *even
 				}
 				timestampsPageSize = uint32(u)
 
+			case ticketvote.SettingKeyVoteExtensionMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				voteExtensionMax = uint32(u)
+
+			case ticketvote.SettingKeyAuthLockBlocks:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				authLockBlocks = uint32(u)
+
+			case ticketvote.SettingKeyConcurrentVotesMax:
+				u, err := strconv.ParseUint(v.Value, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				concurrentVotesMax = uint32(u)
+
+			case ticketvote.SettingKeyConcurrentVotesBlock:
+				b, err := strconv.ParseBool(v.Value)
+				if err != nil {
+					return nil, err
+				}
+				concurrentVotesBlock = b
+
 			default:
 				log.Warnf("Unknown plugin setting %v; Skipping...", v.Key)
 			}
@@ -371,11 +754,12 @@ func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *even
 			ticketvote.SettingKeyTimestampsPageSize)
 	}
 
-	return &TicketVote{
+	t := &TicketVote{
 		cfg:       cfg,
 		politeiad: pdc,
 		sessions:  s,
 		events:    e,
+		queue:     q,
 		policy: &v1.PolicyReply{
 			LinkByPeriodMin:    linkByPeriodMin,
 			LinkByPeriodMax:    linkByPeriodMax,
@@ -384,6 +768,18 @@ func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *even
 			SummariesPageSize:  summariesPageSize,
 			InventoryPageSize:  inventoryPageSize,
 			TimestampsPageSize: timestampsPageSize,
+			VoteExtensionMax:   voteExtensionMax,
+			AuthLockBlocks:     authLockBlocks,
+
+			ConcurrentVotesMax:   concurrentVotesMax,
+			ConcurrentVotesBlock: concurrentVotesBlock,
 		},
-	}, nil
+	}
+
+	// Register the queued submission types
+	if q != nil {
+		q.Register(submissionTypeCastBallot, t.execQueuedCastBallot)
+	}
+
+	return t, nil
 }
@@ -0,0 +1,166 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"errors"
+	"net/http"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/usage"
+	"github.com/decred/politeia/politeiawww/legacy/user"
+	"github.com/google/uuid"
+)
+
+const (
+	// usersUsageMax is the maximum number of users that are returned in
+	// the UsersUsage reply.
+	usersUsageMax = 100
+)
+
+// statusRecorder is a http.ResponseWriter that records the status code of the
+// reply.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// WriteHeader records the status code and writes the header.
+//
+// This function satisfies the http.ResponseWriter interface.
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// withUsage wraps the provided handler with a handler that adds the request to
+// the API usage of the logged in user. Requests that are made without a user
+// session are not counted. The session is only looked up when the request
+// contains a session cookie so that anonymous requests to public routes do not
+// result in a session lookup.
+func (p *Politeiawww) withUsage(route string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie(www.CookieSession); err != nil {
+			handler(w, r)
+			return
+		}
+		userID, err := p.sessions.GetSessionUserID(w, r)
+		if err != nil {
+			handler(w, r)
+			return
+		}
+		sr := &statusRecorder{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+		handler(sr, r)
+		p.usage.Add(userID, route, sr.statusCode)
+	}
+}
+
+// processUserUsage returns the daily API request counts of the logged in user.
+func (p *Politeiawww) processUserUsage(u *user.User) (*www.UserUsageReply, error) {
+	log.Tracef("processUserUsage: %v", u.Username)
+
+	return &www.UserUsageReply{
+		Retention: p.usage.Retention(),
+		Days:      convertDayUsage(p.usage.User(u.ID.String())),
+	}, nil
+}
+
+// processUsersUsage returns the aggregate API request counts of all users.
+func (p *Politeiawww) processUsersUsage(uu www.UsersUsage) (*www.UsersUsageReply, error) {
+	log.Tracef("processUsersUsage: %v %v", uu.Days, uu.UserID)
+
+	// Get the daily request counts of the requested user
+	var userDays []www.DayUsage
+	if uu.UserID != "" {
+		u, err := p.userByIDStr(uu.UserID)
+		if err != nil {
+			return nil, err
+		}
+		userDays = convertDayUsage(p.usage.User(u.ID.String()))
+	}
+
+	// Get the aggregate request counts. Only the users with the most
+	// requests are returned.
+	a := p.usage.Aggregate(uu.Days)
+	users := a.Users
+	if len(users) > usersUsageMax {
+		users = users[:usersUsageMax]
+	}
+	summaries := make([]www.UserUsageSummary, 0, len(users))
+	for _, v := range users {
+		s := www.UserUsageSummary{
+			UserID:   v.UserID,
+			Requests: v.Requests,
+			Errors:   v.Errors,
+		}
+		username, err := p.usernameByID(v.UserID)
+		if err != nil {
+			return nil, err
+		}
+		s.Username = username
+		summaries = append(summaries, s)
+	}
+	routes := make([]www.RouteUsage, 0, len(a.Routes))
+	for _, v := range a.Routes {
+		routes = append(routes, convertRouteUsage(v))
+	}
+
+	return &www.UsersUsageReply{
+		Retention: p.usage.Retention(),
+		From:      a.From,
+		To:        a.To,
+		Requests:  a.Requests,
+		Errors:    a.Errors,
+		UserCount: uint32(len(a.Users)),
+		Users:     summaries,
+		Routes:    routes,
+		UserDays:  userDays,
+	}, nil
+}
+
+// usernameByID returns the username of the provided user ID. An empty string
+// is returned if the user does not exist.
+func (p *Politeiawww) usernameByID(userID string) (string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "", nil
+	}
+	u, err := p.db.UserGetById(id)
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		return "", nil
+	case err != nil:
+		return "", err
+	}
+	return u.Username, nil
+}
+
+func convertRouteUsage(r usage.RouteCounts) www.RouteUsage {
+	return www.RouteUsage{
+		Route:    r.Route,
+		Requests: r.Requests,
+		Errors:   r.Errors,
+	}
+}
+
+func convertDayUsage(days []usage.Day) []www.DayUsage {
+	d := make([]www.DayUsage, 0, len(days))
+	for _, v := range days {
+		routes := make([]www.RouteUsage, 0, len(v.Routes))
+		for _, r := range v.Routes {
+			routes = append(routes, convertRouteUsage(r))
+		}
+		d = append(d, www.DayUsage{
+			Date:     v.Date,
+			Requests: v.Requests,
+			Errors:   v.Errors,
+			Routes:   routes,
+		})
+	}
+	return d
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package usage tracks the number of API requests that are made by each user.
+// The request counts are aggregated per user, per route, per UTC day and are
+// kept for a configurable number of days. The counts are persisted to disk so
+// that they survive a restart.
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of the UTC dates that the request counts
+	// are aggregated by.
+	DateLayout = "2006-01-02"
+
+	// saveInterval is the interval at which the request counts are
+	// persisted to disk.
+	saveInterval = time.Minute
+)
+
+// Counts contains the request counts of a user or route. Errors is the number
+// of requests that were replied to with a 4xx or 5xx status code.
+type Counts struct {
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+}
+
+// add adds the provided counts.
+func (c *Counts) add(a Counts) {
+	c.Requests += a.Requests
+	c.Errors += a.Errors
+}
+
+// RouteCounts contains the request counts of a route.
+type RouteCounts struct {
+	Route string
+	Counts
+}
+
+// Day contains the request counts of a user for a single UTC day. The routes
+// are sorted by route.
+type Day struct {
+	Date string
+	Counts
+	Routes []RouteCounts
+}
+
+// UserCounts contains the request counts of a user.
+type UserCounts struct {
+	UserID string
+	Counts
+}
+
+// Aggregate contains the request counts of all users over a range of UTC
+// days. The users and routes are sorted by request count in descending order.
+type Aggregate struct {
+	From string // First date, inclusive
+	To   string // Last date, inclusive
+	Counts
+	Users  []UserCounts
+	Routes []RouteCounts
+}
+
+// counts is the persisted request counts.
+type counts map[string]map[string]map[string]Counts // [date][userID][route]
+
+// Tracker tracks the number of API requests that are made by each user.
+type Tracker struct {
+	sync.Mutex
+	fp        string
+	retention uint32 // In days
+	counts    counts
+	dirty     bool // Counts have changed since they were last saved
+	now       func() time.Time
+
+	wg   sync.WaitGroup
+	done chan struct{}
+}
+
+// New returns a new Tracker that persists the request counts to the provided
+// file path. Request counts are kept for the provided number of days,
+// including the current day.
+func New(fp string, retention uint32) (*Tracker, error) {
+	return newTracker(fp, retention, time.Now)
+}
+
+// newTracker returns a new Tracker that uses the provided function to get
+// the current time.
+func newTracker(fp string, retention uint32, now func() time.Time) (*Tracker, error) {
+	t := &Tracker{
+		fp:        fp,
+		retention: retention,
+		counts:    make(counts),
+		now:       now,
+		done:      make(chan struct{}),
+	}
+	err := t.load()
+	if err != nil {
+		return nil, err
+	}
+	t.prune()
+	return t, nil
+}
+
+// Retention returns the number of days that the request counts are kept for.
+func (t *Tracker) Retention() uint32 {
+	return t.retention
+}
+
+// Start starts the loop that persists the request counts to disk.
+func (t *Tracker) Start() {
+	t.wg.Add(1)
+	go t.run()
+}
+
+// Close stops the save loop and persists the request counts to disk.
+func (t *Tracker) Close() {
+	close(t.done)
+	t.wg.Wait()
+	err := t.save()
+	if err != nil {
+		log.Errorf("Save usage: %v", err)
+	}
+}
+
+// Add adds a request to the counts of the provided user and route. The
+// status code is the status code of the reply.
+func (t *Tracker) Add(userID, route string, statusCode int) {
+	t.Lock()
+	defer t.Unlock()
+
+	date := t.date(0)
+	users, ok := t.counts[date]
+	if !ok {
+		// This is the first request of a new day. Prune the days
+		// that are no longer within the retention window.
+		users = make(map[string]map[string]Counts)
+		t.counts[date] = users
+		t.prune()
+	}
+	routes, ok := users[userID]
+	if !ok {
+		routes = make(map[string]Counts)
+		users[userID] = routes
+	}
+	c := routes[route]
+	c.Requests++
+	if statusCode >= http.StatusBadRequest {
+		c.Errors++
+	}
+	routes[route] = c
+	t.dirty = true
+}
+
+// User returns the daily request counts of the provided user. The days are
+// sorted by date in ascending order. Days without requests are not included.
+func (t *Tracker) User(userID string) []Day {
+	t.Lock()
+	defer t.Unlock()
+
+	days := make([]Day, 0, len(t.counts))
+	for date, users := range t.counts {
+		routes, ok := users[userID]
+		if !ok {
+			continue
+		}
+		d := Day{
+			Date:   date,
+			Routes: make([]RouteCounts, 0, len(routes)),
+		}
+		for route, c := range routes {
+			d.add(c)
+			d.Routes = append(d.Routes, RouteCounts{
+				Route:  route,
+				Counts: c,
+			})
+		}
+		sort.Slice(d.Routes, func(i, j int) bool {
+			return d.Routes[i].Route < d.Routes[j].Route
+		})
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date < days[j].Date
+	})
+
+	return days
+}
+
+// Aggregate returns the request counts of all users over the provided number
+// of days, including the current day. The retention window is used if the
+// number of days is zero or exceeds the retention window.
+func (t *Tracker) Aggregate(days uint32) Aggregate {
+	t.Lock()
+	defer t.Unlock()
+
+	if days == 0 || days > t.retention {
+		days = t.retention
+	}
+	var (
+		from   = t.date(int(days) - 1)
+		to     = t.date(0)
+		total  Counts
+		users  = make(map[string]Counts, 256)
+		routes = make(map[string]Counts, 64)
+	)
+	for date, u := range t.counts {
+		if date < from || date > to {
+			continue
+		}
+		for userID, r := range u {
+			uc := users[userID]
+			for route, c := range r {
+				rc := routes[route]
+				rc.add(c)
+				routes[route] = rc
+				uc.add(c)
+				total.add(c)
+			}
+			users[userID] = uc
+		}
+	}
+
+	a := Aggregate{
+		From:   from,
+		To:     to,
+		Counts: total,
+		Users:  make([]UserCounts, 0, len(users)),
+		Routes: make([]RouteCounts, 0, len(routes)),
+	}
+	for userID, c := range users {
+		a.Users = append(a.Users, UserCounts{
+			UserID: userID,
+			Counts: c,
+		})
+	}
+	sort.Slice(a.Users, func(i, j int) bool {
+		if a.Users[i].Requests != a.Users[j].Requests {
+			return a.Users[i].Requests > a.Users[j].Requests
+		}
+		return a.Users[i].UserID < a.Users[j].UserID
+	})
+	for route, c := range routes {
+		a.Routes = append(a.Routes, RouteCounts{
+			Route:  route,
+			Counts: c,
+		})
+	}
+	sort.Slice(a.Routes, func(i, j int) bool {
+		if a.Routes[i].Requests != a.Routes[j].Requests {
+			return a.Routes[i].Requests > a.Routes[j].Requests
+		}
+		return a.Routes[i].Route < a.Routes[j].Route
+	})
+
+	return a
+}
+
+// run persists the request counts to disk at the save interval until the
+// tracker is closed.
+func (t *Tracker) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(saveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			err := t.save()
+			if err != nil {
+				log.Errorf("Save usage: %v", err)
+			}
+		}
+	}
+}
+
+// date returns the UTC date of the day that is the provided number of days
+// before the current day.
+func (t *Tracker) date(daysAgo int) string {
+	return t.now().UTC().AddDate(0, 0, -daysAgo).Format(DateLayout)
+}
+
+// prune deletes the request counts of the days that are no longer within the
+// retention window.
+//
+// This function must be called with the lock held.
+func (t *Tracker) prune() {
+	oldest := t.date(int(t.retention) - 1)
+	for date := range t.counts {
+		if date < oldest {
+			delete(t.counts, date)
+			t.dirty = true
+		}
+	}
+}
+
+// load loads the request counts from disk.
+func (t *Tracker) load() error {
+	b, err := os.ReadFile(t.fp)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	}
+	return json.Unmarshal(b, &t.counts)
+}
+
+// save persists the request counts to disk if they have changed. The counts
+// are written to a temp file that is then renamed so that a partially
+// written file is never read.
+func (t *Tracker) save() error {
+	t.Lock()
+	if !t.dirty {
+		t.Unlock()
+		return nil
+	}
+	b, err := json.Marshal(t.counts)
+	if err != nil {
+		t.Unlock()
+		return err
+	}
+	t.dirty = false
+	t.Unlock()
+
+	tmp := t.fp + ".tmp"
+	err = os.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.fp)
+}
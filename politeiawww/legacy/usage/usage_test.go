@@ -0,0 +1,114 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package usage
+
+import (
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "usage.json")
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	nowFn := func() time.Time { return now }
+	tr, err := newTracker(fp, 2, nowFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Day 1
+	tr.Add("alice", "/a", http.StatusOK)
+	tr.Add("alice", "/a", http.StatusBadRequest)
+	tr.Add("bob", "/b", http.StatusOK)
+
+	// Day 2
+	now = now.AddDate(0, 0, 1)
+	tr.Add("alice", "/b", http.StatusInternalServerError)
+	tr.Add("bob", "/b", http.StatusOK)
+	tr.Add("bob", "/b", http.StatusOK)
+
+	wantAlice := []Day{
+		{
+			Date:   "2022-03-01",
+			Counts: Counts{Requests: 2, Errors: 1},
+			Routes: []RouteCounts{
+				{Route: "/a", Counts: Counts{Requests: 2, Errors: 1}},
+			},
+		},
+		{
+			Date:   "2022-03-02",
+			Counts: Counts{Requests: 1, Errors: 1},
+			Routes: []RouteCounts{
+				{Route: "/b", Counts: Counts{Requests: 1, Errors: 1}},
+			},
+		},
+	}
+	if got := tr.User("alice"); !reflect.DeepEqual(got, wantAlice) {
+		t.Fatalf("User: got %+v, want %+v", got, wantAlice)
+	}
+	if got := tr.User("carol"); len(got) != 0 {
+		t.Fatalf("User: got %+v, want no days", got)
+	}
+
+	// Aggregate the current day only
+	wantToday := Aggregate{
+		From:   "2022-03-02",
+		To:     "2022-03-02",
+		Counts: Counts{Requests: 3, Errors: 1},
+		Users: []UserCounts{
+			{UserID: "bob", Counts: Counts{Requests: 2}},
+			{UserID: "alice", Counts: Counts{Requests: 1, Errors: 1}},
+		},
+		Routes: []RouteCounts{
+			{Route: "/b", Counts: Counts{Requests: 3, Errors: 1}},
+		},
+	}
+	if got := tr.Aggregate(1); !reflect.DeepEqual(got, wantToday) {
+		t.Fatalf("Aggregate: got %+v, want %+v", got, wantToday)
+	}
+
+	// Aggregate the retention window
+	wantAll := Aggregate{
+		From:   "2022-03-01",
+		To:     "2022-03-02",
+		Counts: Counts{Requests: 6, Errors: 2},
+		Users: []UserCounts{
+			{UserID: "alice", Counts: Counts{Requests: 3, Errors: 2}},
+			{UserID: "bob", Counts: Counts{Requests: 3}},
+		},
+		Routes: []RouteCounts{
+			{Route: "/b", Counts: Counts{Requests: 4, Errors: 1}},
+			{Route: "/a", Counts: Counts{Requests: 2, Errors: 1}},
+		},
+	}
+	if got := tr.Aggregate(0); !reflect.DeepEqual(got, wantAll) {
+		t.Fatalf("Aggregate: got %+v, want %+v", got, wantAll)
+	}
+
+	// Verify the counts are persisted
+	err = tr.save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr2, err := newTracker(fp, 2, nowFn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tr2.User("alice"); !reflect.DeepEqual(got, wantAlice) {
+		t.Fatalf("User after load: got %+v, want %+v", got, wantAlice)
+	}
+
+	// Verify the first day is pruned once it leaves the retention
+	// window.
+	now = now.AddDate(0, 0, 1)
+	tr2.Add("alice", "/a", http.StatusOK)
+	days := tr2.User("alice")
+	if len(days) != 2 || days[0].Date != "2022-03-02" {
+		t.Fatalf("User after prune: got %+v", days)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/politeiawww/legacy/usage"
+)
+
+func TestUsage(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	var err error
+	p.usage, err = usage.New(filepath.Join(t.TempDir(), "usage.json"), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usr, _ := newUser(t, p, true, false)
+	route := www.PoliteiaWWWAPIRoute + www.RouteUserMe
+
+	// Make a successful and a failed request with a user session and
+	// a request without a user session.
+	for _, statusCode := range []int{http.StatusOK, http.StatusBadRequest} {
+		statusCode := statusCode
+		h := p.withUsage(route, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+		})
+		r := httptest.NewRequest(http.MethodGet, route, nil)
+		addSessionToReq(t, p, r, usr.ID.String())
+		h(httptest.NewRecorder(), r)
+	}
+	h := p.withUsage(route, func(w http.ResponseWriter, r *http.Request) {})
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, route, nil))
+
+	// Verify the user usage
+	uur, err := p.processUserUsage(usr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uur.Days) != 1 {
+		t.Fatalf("got %v days, want 1", len(uur.Days))
+	}
+	d := uur.Days[0]
+	if d.Requests != 2 || d.Errors != 1 || len(d.Routes) != 1 ||
+		d.Routes[0].Route != route {
+		t.Fatalf("unexpected day usage: %+v", d)
+	}
+
+	// Verify the users usage
+	uusr, err := p.processUsersUsage(www.UsersUsage{
+		UserID: usr.ID.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uusr.Requests != 2 || uusr.UserCount != 1 || len(uusr.Users) != 1 ||
+		uusr.Users[0].Username != usr.Username || len(uusr.UserDays) != 1 {
+		t.Fatalf("unexpected users usage: %+v", uusr)
+	}
+
+	// Verify an invalid user ID is rejected
+	_, err = p.processUsersUsage(www.UsersUsage{
+		UserID: "invalid",
+	})
+	var ue www.UserError
+	if !errors.As(err, &ue) || ue.ErrorCode != www.ErrorStatusInvalidUUID {
+		t.Fatalf("got error %v, want %v", err,
+			www.ErrorStatus[www.ErrorStatusInvalidUUID])
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/decred/politeia/politeiad/api/v1/identity"
 	www "github.com/decred/politeia/politeiawww/api/www/v1"
@@ -36,6 +37,14 @@ const (
 	// Route to reset password at GUI
 	ResetPasswordGuiRoute = "/password" // XXX what is this doing here?
 
+	// activeIdentitiesMax is the maximum number of active identities
+	// that a user can have at any one time.
+	activeIdentitiesMax = 5
+
+	// identityLabelMaxLength is the maximum length of an identity
+	// label.
+	identityLabelMaxLength = 64
+
 	emailRegex = `^[a-zA-Z0-9.!#$%&'*+/=?^_` +
 		"`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?" +
 		"(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$"
@@ -677,6 +686,22 @@ func (p *Politeiawww) processUpdateUserKey(usr *user.User, uuk www.UpdateUserKey
 	if err != nil {
 		return nil, err
 	}
+	err = validateIdentityLabel(uuk.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify that the user has not reached the active identity limit
+	// when the new identity is being added alongside the existing
+	// active identities.
+	if uuk.Additional && len(usr.ActiveIdentities()) >= activeIdentitiesMax {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusIdentityLimitReached,
+			ErrorContext: []string{
+				strconv.Itoa(activeIdentitiesMax),
+			},
+		}
+	}
 	_, err = p.db.UserGetByPubKey(uuk.PublicKey)
 	switch err {
 	case user.ErrUserNotFound:
@@ -716,6 +741,8 @@ func (p *Politeiawww) processUpdateUserKey(usr *user.User, uuk www.UpdateUserKey
 	if err != nil {
 		return nil, err
 	}
+	id.Label = uuk.Label
+	id.Additional = uuk.Additional
 	err = usr.AddIdentity(*id)
 	if err != nil {
 		return nil, err
@@ -813,7 +840,7 @@ func (p *Politeiawww) processVerifyUpdateUserKey(u *user.User, vu www.VerifyUpda
 	}
 
 	// Clear out the verification token fields in the db and activate
-	// the key and deactivate the one it's replacing.
+	// the key and deactivate the ones it's replacing.
 	u.UpdateKeyVerificationToken = nil
 	u.UpdateKeyVerificationExpiry = 0
 	err = u.ActivateIdentity(id.Key[:])
@@ -824,6 +851,45 @@ func (p *Politeiawww) processVerifyUpdateUserKey(u *user.User, vu www.VerifyUpda
 	return u, p.db.UserUpdate(*u)
 }
 
+// processRevokeUserKey deactivates one of the user's active keys. The last
+// active key cannot be revoked since the user would no longer be able to sign
+// anything.
+func (p *Politeiawww) processRevokeUserKey(u *user.User, rk www.RevokeUserKey) (*www.RevokeUserKeyReply, error) {
+	log.Tracef("processRevokeUserKey: %v %v", u.Username, rk.PublicKey)
+
+	err := validatePubKey(rk.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsActivePublicKey(rk.PublicKey) {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusIdentityNotFound,
+		}
+	}
+	if len(u.ActiveIdentities()) == 1 {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusLastActiveIdentity,
+		}
+	}
+
+	key, err := hex.DecodeString(rk.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	err = u.RevokeIdentity(key)
+	if err != nil {
+		return nil, err
+	}
+	err = p.db.UserUpdate(*u)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("User key revoked: %v %v", u.Username, rk.PublicKey)
+
+	return &www.RevokeUserKeyReply{}, nil
+}
+
 // processChangeUsername checks that the password matches the one
 // in the database, then checks that the username is valid and not
 // already taken, then changes the user record in the database to
@@ -936,6 +1002,226 @@ func (p *Politeiawww) processChangePassword(email string, cp www.ChangePassword)
 	return &reply, nil
 }
 
+// processChangeEmail checks that the password matches the one in the
+// database and that the new email address is valid and not already in use,
+// then emails a verification token to both the current and the new email
+// address. The email address is not updated until both tokens have been
+// verified. A new email change cannot be requested until any existing email
+// change verification has expired.
+func (p *Politeiawww) processChangeEmail(u *user.User, ce www.ChangeEmail) (*www.ChangeEmailReply, error) {
+	log.Tracef("processChangeEmail: %v", u.Username)
+
+	// Check the user's password.
+	err := bcrypt.CompareHashAndPassword(u.HashedPassword,
+		[]byte(ce.Password))
+	if err != nil {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusInvalidPassword,
+		}
+	}
+
+	// Format and validate the new email address.
+	newEmail := strings.ToLower(ce.NewEmail)
+	if !validEmail.MatchString(newEmail) {
+		log.Debugf("processChangeEmail: invalid email '%v'", newEmail)
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusMalformedEmail,
+		}
+	}
+	if newEmail == u.Email {
+		return nil, www.UserError{
+			ErrorCode:    www.ErrorStatusInvalidInput,
+			ErrorContext: []string{"new email is the current email"},
+		}
+	}
+
+	// Check for duplicate email
+	_, err = p.userByEmail(newEmail)
+	switch err {
+	case nil:
+		// Duplicate
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusDuplicateEmail,
+		}
+	case user.ErrUserNotFound:
+		// Doesn't exist; continue
+	default:
+		// All other errors
+		return nil, err
+	}
+
+	// Check if an existing email change verification has expired yet.
+	if u.ChangeEmailAddress != "" &&
+		u.ChangeEmailVerificationExpiry > time.Now().Unix() {
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusVerificationTokenUnexpired,
+			ErrorContext: []string{
+				strconv.FormatInt(u.ChangeEmailVerificationExpiry, 10),
+			},
+		}
+	}
+
+	// Generate the verification tokens. Both tokens use the
+	// same expiry.
+	oldToken, expiry, err := newVerificationTokenAndExpiry()
+	if err != nil {
+		return nil, err
+	}
+	newToken, _, err := newVerificationTokenAndExpiry()
+	if err != nil {
+		return nil, err
+	}
+	u.ChangeEmailAddress = newEmail
+	u.ChangeEmailOldToken = oldToken
+	u.ChangeEmailNewToken = newToken
+	u.ChangeEmailVerificationExpiry = expiry
+	u.AddEmailChange(user.EmailChangeActionRequested, u.Email, newEmail)
+
+	// Email the verification links. The database does not get
+	// updated if this fails.
+	//
+	// This is conditional on the email server being setup.
+	var (
+		oldTokenHex = hex.EncodeToString(oldToken)
+		newTokenHex = hex.EncodeToString(newToken)
+		recipient   = map[uuid.UUID]string{
+			u.ID: u.Email,
+		}
+	)
+	err = p.emailUserEmailChange(u.Username, u.Email, newEmail,
+		oldTokenHex, newTokenHex, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save user changes to the database
+	err = p.db.UserUpdate(*u)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Email change requested by %v: %v to %v",
+		u.Username, u.Email, newEmail)
+
+	// Only set the tokens if email verification is disabled.
+	var reply www.ChangeEmailReply
+	if !p.mail.IsEnabled() {
+		reply.OldEmailVerificationToken = oldTokenHex
+		reply.NewEmailVerificationToken = newTokenHex
+	}
+	return &reply, nil
+}
+
+// processVerifyChangeEmail verifies one of the tokens that was sent during
+// the change email command. The user's email address is updated once the
+// tokens for both the old and the new email address have been verified.
+func (p *Politeiawww) processVerifyChangeEmail(u *user.User, vce www.VerifyChangeEmail) (*www.VerifyChangeEmailReply, error) {
+	log.Tracef("processVerifyChangeEmail: %v", u.Username)
+
+	// Decode the verification token.
+	token, err := hex.DecodeString(vce.VerificationToken)
+	if err != nil {
+		log.Debugf("processVerifyChangeEmail: decode hex '%v': %v",
+			vce.VerificationToken, err)
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusVerificationTokenInvalid,
+		}
+	}
+
+	// Check that an email change is pending and that the token
+	// matches one of the outstanding tokens.
+	var action user.EmailChangeActionT
+	switch {
+	case u.ChangeEmailAddress == "" || len(token) == 0:
+		// No email change pending
+	case bytes.Equal(token, u.ChangeEmailOldToken):
+		action = user.EmailChangeActionVerifiedOld
+	case bytes.Equal(token, u.ChangeEmailNewToken):
+		action = user.EmailChangeActionVerifiedNew
+	}
+	if action == user.EmailChangeActionInvalid {
+		log.Debugf("processVerifyChangeEmail: wrong token for %v",
+			u.Username)
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusVerificationTokenInvalid,
+		}
+	}
+
+	// Check that the token hasn't expired.
+	if u.ChangeEmailVerificationExpiry < time.Now().Unix() {
+		log.Debugf("processVerifyChangeEmail: token expired: %v %v",
+			u.ChangeEmailVerificationExpiry, time.Now().Unix())
+		return nil, www.UserError{
+			ErrorCode: www.ErrorStatusVerificationTokenExpired,
+		}
+	}
+
+	// Clear the verified token
+	var (
+		oldEmail = u.Email
+		newEmail = u.ChangeEmailAddress
+	)
+	switch action {
+	case user.EmailChangeActionVerifiedOld:
+		u.ChangeEmailOldToken = nil
+	case user.EmailChangeActionVerifiedNew:
+		u.ChangeEmailNewToken = nil
+	}
+	u.AddEmailChange(action, oldEmail, newEmail)
+
+	// Update the email address once both tokens have been verified.
+	// The new email address could have been registered by another
+	// user since the email change was requested.
+	completed := u.ChangeEmailOldToken == nil && u.ChangeEmailNewToken == nil
+	if completed {
+		_, err = p.userByEmail(newEmail)
+		switch err {
+		case nil:
+			// Duplicate
+			return nil, www.UserError{
+				ErrorCode: www.ErrorStatusDuplicateEmail,
+			}
+		case user.ErrUserNotFound:
+			// Doesn't exist; continue
+		default:
+			// All other errors
+			return nil, err
+		}
+
+		u.Email = newEmail
+		u.ChangeEmailAddress = ""
+		u.ChangeEmailVerificationExpiry = 0
+		u.AddEmailChange(user.EmailChangeActionCompleted, oldEmail, newEmail)
+	}
+
+	err = p.db.UserUpdate(*u)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Email change %v by %v: %v to %v",
+		user.EmailChangeActions[action], u.Username, oldEmail, newEmail)
+
+	if completed {
+		p.updateUserEmailsCache(oldEmail, newEmail, u.ID)
+
+		log.Infof("Email change completed by %v: %v to %v",
+			u.Username, oldEmail, newEmail)
+
+		// Notify the old email address of the change. The email
+		// address has already been updated so a failure is only
+		// logged.
+		err = p.emailUserEmailChanged(u.Username, oldEmail, newEmail)
+		if err != nil {
+			log.Errorf("emailUserEmailChanged %v: %v", u.Username, err)
+		}
+	}
+
+	return &www.VerifyChangeEmailReply{
+		Completed: completed,
+	}, nil
+}
+
 // processUsers returns a list of users given a set of filters. Admins can
 // search by pubkey, username or email. Username and email searches will
 // return partial matches. Pubkey searches must be an exact match. Non admins
@@ -1091,6 +1377,19 @@ func (p *Politeiawww) processManageUser(mu *www.ManageUser, adminUser *user.User
 		}
 	}
 
+	// Deactivating a user requires the confirmation of a second admin
+	// when admin confirmations are enabled. The user is not updated
+	// until the action has been confirmed.
+	if p.cfg.AdminConfirm && mu.Action == www.UserManageDeactivate {
+		id, err := p.pendingActionDeactivateUser(*mu, adminUser)
+		if err != nil {
+			return nil, err
+		}
+		return &www.ManageUserReply{
+			PendingActionID: id,
+		}, nil
+	}
+
 	// -168 hours is 7 days in the past
 	expiredTime := time.Now().Add(-168 * time.Hour).Unix()
 
@@ -1344,6 +1643,7 @@ func (p *Politeiawww) createLoginReply(u *user.User, lastLoginTime int64) (*www.
 		Email:              u.Email,
 		Username:           u.Username,
 		PublicKey:          u.PublicKey(),
+		ActiveKeys:         uint32(len(u.ActiveIdentities())),
 		PaywallAddress:     u.NewUserPaywallAddress,
 		PaywallAmount:      u.NewUserPaywallAmount,
 		PaywallTxNotBefore: u.NewUserPaywallTxNotBefore,
@@ -1507,6 +1807,27 @@ func createUsernameRegex() string {
 
 // validatePubKey verifies that the provided public key is a valid ed25519
 // public key.
+// validateIdentityLabel verifies that the provided identity label is valid.
+// An empty label is allowed.
+func validateIdentityLabel(label string) error {
+	if len(label) > identityLabelMaxLength {
+		return www.UserError{
+			ErrorCode: www.ErrorStatusInvalidIdentityLabel,
+			ErrorContext: []string{"label must be at most " +
+				strconv.Itoa(identityLabelMaxLength) + " characters"},
+		}
+	}
+	for _, r := range label {
+		if !unicode.IsPrint(r) {
+			return www.UserError{
+				ErrorCode:    www.ErrorStatusInvalidIdentityLabel,
+				ErrorContext: []string{"label contains invalid characters"},
+			}
+		}
+	}
+	return nil
+}
+
 func validatePubKey(publicKey string) error {
 	pk, err := hex.DecodeString(publicKey)
 	if err != nil {
@@ -1589,6 +1910,12 @@ func validateUsername(username string) error {
 			username)
 		return www.UserError{
 			ErrorCode: www.ErrorStatusMalformedUsername,
+			ErrorParams: map[string]string{
+				www.ErrorParamField: "username",
+				www.ErrorParamMin:   strconv.Itoa(www.PolicyMinUsernameLength),
+				www.ErrorParamMax:   strconv.Itoa(www.PolicyMaxUsernameLength),
+				www.ErrorParamGot:   strconv.Itoa(len(username)),
+			},
 		}
 	}
 	if !validUsername.MatchString(username) {
@@ -1606,6 +1933,11 @@ func validatePassword(password string) error {
 	if len(password) < www.PolicyMinPasswordLength {
 		return www.UserError{
 			ErrorCode: www.ErrorStatusMalformedPassword,
+			ErrorParams: map[string]string{
+				www.ErrorParamField: "password",
+				www.ErrorParamMin:   strconv.Itoa(www.PolicyMinPasswordLength),
+				www.ErrorParamGot:   strconv.Itoa(len(password)),
+			},
 		}
 	}
 
@@ -1667,7 +1999,28 @@ func convertWWWUserFromDatabaseUser(user *user.User) www.User {
 		Identities:                      convertWWWIdentitiesFromDatabaseIdentities(user.Identities),
 		ProposalCredits:                 uint64(len(user.UnspentProposalCredits)),
 		EmailNotifications:              user.EmailNotifications,
+		ChangeEmailAddress:              user.ChangeEmailAddress,
+		ChangeEmailVerificationExpiry:   user.ChangeEmailVerificationExpiry,
+		EmailChanges:                    convertWWWEmailChangesFromDatabase(user.EmailChanges),
+	}
+}
+
+// convertWWWEmailChangesFromDatabase converts the user email change audit log
+// to the www email change audit log.
+func convertWWWEmailChangesFromDatabase(changes []user.EmailChange) []www.EmailChange {
+	if len(changes) == 0 {
+		return nil
+	}
+	c := make([]www.EmailChange, 0, len(changes))
+	for _, v := range changes {
+		c = append(c, www.EmailChange{
+			Action:    user.EmailChangeActions[v.Action],
+			OldEmail:  v.OldEmail,
+			NewEmail:  v.NewEmail,
+			Timestamp: v.Timestamp,
+		})
 	}
+	return c
 }
 
 // convertWWWIdentitiesFromDatabaseIdentities converts a user Identity to a www
@@ -1687,5 +2040,6 @@ func convertWWWIdentityFromDatabaseIdentity(id user.Identity) www.UserIdentity {
 	return www.UserIdentity{
 		Pubkey: id.String(),
 		Active: id.IsActive(),
+		Label:  id.Label,
 	}
 }
@@ -0,0 +1,65 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package user
+
+import "github.com/google/uuid"
+
+// BannersDB describes the interface used to interact with the banners table
+// from the user database. A banner is a site-wide announcement that is
+// published by an admin.
+type BannersDB interface {
+	// BannerSave creates or updates the provided banner.
+	BannerSave(Banner) error
+
+	// BannerDel deletes the banner for the provided ID.
+	// ErrBannerNotFound is returned if a banner is not found.
+	BannerDel(id uuid.UUID) error
+
+	// BannersGetAll returns all banners sorted by their start time from
+	// oldest to newest.
+	BannersGetAll() ([]Banner, error)
+}
+
+// BannerSeverityT represents the severity of a banner.
+type BannerSeverityT int
+
+const (
+	// BannerSeverityInvalid is an invalid banner severity.
+	BannerSeverityInvalid BannerSeverityT = 0
+
+	// BannerSeverityInfo is used for general announcements.
+	BannerSeverityInfo BannerSeverityT = 1
+
+	// BannerSeverityWarning is used for announcements that may affect
+	// users, e.g. scheduled maintenance.
+	BannerSeverityWarning BannerSeverityT = 2
+
+	// BannerSeverityCritical is used for announcements that require the
+	// immediate attention of users.
+	BannerSeverityCritical BannerSeverityT = 3
+)
+
+// Banner is a site-wide announcement that is published by an admin. A banner
+// is displayed from its start time until its end time. A banner without an
+// end time is displayed until it is deleted.
+type Banner struct {
+	ID        uuid.UUID       `json:"id"`
+	Message   string          `json:"message"`
+	Severity  BannerSeverityT `json:"severity"`
+	StartsAt  int64           `json:"startsat"`  // Unix timestamp
+	EndsAt    int64           `json:"endsat"`    // Unix timestamp
+	CreatedBy uuid.UUID       `json:"createdby"` // Admin user ID
+	CreatedAt int64           `json:"createdat"` // Unix timestamp
+	UpdatedAt int64           `json:"updatedat"` // Unix timestamp
+}
+
+// Active returns whether the banner should be displayed at the provided unix
+// timestamp.
+func (b Banner) Active(now int64) bool {
+	return now >= b.StartsAt && (b.EndsAt == 0 || now < b.EndsAt)
+}
+
+// VersionBanner is the version of the Banner struct.
+const VersionBanner uint32 = 1
@@ -32,6 +32,8 @@ const (
 	tableIdentities     = "identities"
 	tableSessions       = "sessions"
 	tableEmailHistories = "email_histories"
+	tablePendingActions = "pending_actions"
+	tableBanners        = "banners"
 
 	// Database user (read/write access)
 	userPoliteiawww = "politeiawww"
@@ -42,8 +44,10 @@ const (
 )
 
 var (
-	_ user.Database = (*cockroachdb)(nil)
-	_ user.MailerDB = (*cockroachdb)(nil)
+	_ user.Database         = (*cockroachdb)(nil)
+	_ user.MailerDB         = (*cockroachdb)(nil)
+	_ user.PendingActionsDB = (*cockroachdb)(nil)
+	_ user.BannersDB        = (*cockroachdb)(nil)
 )
 
 // cockroachdb implements the user database interface.
@@ -887,6 +891,262 @@ func (c *cockroachdb) convertEmailHistoryToUser(eh EmailHistory) (*user.EmailHis
 	return &h, nil
 }
 
+// PendingActionSave creates or updates the provided pending action.
+//
+// PendingActionSave satisfies the user PendingActionsDB interface.
+func (c *cockroachdb) PendingActionSave(pa user.PendingAction) error {
+	log.Tracef("PendingActionSave: %v", pa.ID)
+
+	if c.isShutdown() {
+		return user.ErrShutdown
+	}
+
+	var update bool
+	err := c.userDB.Find(&PendingAction{ID: pa.ID}).Error
+	switch err {
+	case nil:
+		// DB entry already exists, update it.
+		update = true
+	case gorm.ErrRecordNotFound:
+		// DB entry doesn't exist, create new one.
+	default:
+		// All other errors
+		return fmt.Errorf("find pending action: %v", err)
+	}
+
+	paDB, err := c.convertPendingActionFromUser(pa)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		err := c.userDB.Save(paDB).Error
+		if err != nil {
+			return fmt.Errorf("save: %v", err)
+		}
+	} else {
+		err := c.userDB.Create(paDB).Error
+		if err != nil {
+			return fmt.Errorf("create: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// PendingActionGet returns the pending action for the provided ID.
+//
+// PendingActionGet satisfies the user PendingActionsDB interface.
+func (c *cockroachdb) PendingActionGet(id uuid.UUID) (*user.PendingAction, error) {
+	log.Tracef("PendingActionGet: %v", id)
+
+	if c.isShutdown() {
+		return nil, user.ErrShutdown
+	}
+
+	var pa PendingAction
+	err := c.userDB.
+		Where("id = ?", id).
+		Find(&pa).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = user.ErrPendingActionNotFound
+		}
+		return nil, err
+	}
+
+	return c.convertPendingActionToUser(pa)
+}
+
+// PendingActionsGetAll returns all pending actions sorted by creation time
+// from oldest to newest.
+//
+// PendingActionsGetAll satisfies the user PendingActionsDB interface.
+func (c *cockroachdb) PendingActionsGetAll() ([]user.PendingAction, error) {
+	log.Tracef("PendingActionsGetAll")
+
+	if c.isShutdown() {
+		return nil, user.ErrShutdown
+	}
+
+	var result []PendingAction
+	err := c.userDB.
+		Order("created_at").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]user.PendingAction, 0, len(result))
+	for _, v := range result {
+		pa, err := c.convertPendingActionToUser(v)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, *pa)
+	}
+
+	return actions, nil
+}
+
+func (c *cockroachdb) convertPendingActionFromUser(pa user.PendingAction) (*PendingAction, error) {
+	b, err := json.Marshal(pa)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := c.encrypt(user.VersionPendingAction, b)
+	if err != nil {
+		return nil, err
+	}
+	return &PendingAction{
+		ID:        pa.ID,
+		CreatedAt: pa.CreatedAt,
+		Blob:      eb,
+	}, nil
+}
+
+func (c *cockroachdb) convertPendingActionToUser(pa PendingAction) (*user.PendingAction, error) {
+	b, _, err := c.decrypt(pa.Blob)
+	if err != nil {
+		return nil, err
+	}
+	var upa user.PendingAction
+	err = json.Unmarshal(b, &upa)
+	if err != nil {
+		return nil, err
+	}
+	return &upa, nil
+}
+
+// BannerSave creates or updates the provided banner.
+//
+// BannerSave satisfies the user BannersDB interface.
+func (c *cockroachdb) BannerSave(b user.Banner) error {
+	log.Tracef("BannerSave: %v", b.ID)
+
+	if c.isShutdown() {
+		return user.ErrShutdown
+	}
+
+	var update bool
+	err := c.userDB.Find(&Banner{ID: b.ID}).Error
+	switch err {
+	case nil:
+		// DB entry already exists, update it.
+		update = true
+	case gorm.ErrRecordNotFound:
+		// DB entry doesn't exist, create new one.
+	default:
+		// All other errors
+		return fmt.Errorf("find banner: %v", err)
+	}
+
+	bDB, err := c.convertBannerFromUser(b)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		err := c.userDB.Save(bDB).Error
+		if err != nil {
+			return fmt.Errorf("save: %v", err)
+		}
+	} else {
+		err := c.userDB.Create(bDB).Error
+		if err != nil {
+			return fmt.Errorf("create: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// BannerDel deletes the banner for the provided ID.
+//
+// BannerDel satisfies the user BannersDB interface.
+func (c *cockroachdb) BannerDel(id uuid.UUID) error {
+	log.Tracef("BannerDel: %v", id)
+
+	if c.isShutdown() {
+		return user.ErrShutdown
+	}
+
+	res := c.userDB.
+		Where("id = ?", id).
+		Delete(Banner{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return user.ErrBannerNotFound
+	}
+
+	return nil
+}
+
+// BannersGetAll returns all banners sorted by their start time from oldest to
+// newest.
+//
+// BannersGetAll satisfies the user BannersDB interface.
+func (c *cockroachdb) BannersGetAll() ([]user.Banner, error) {
+	log.Tracef("BannersGetAll")
+
+	if c.isShutdown() {
+		return nil, user.ErrShutdown
+	}
+
+	var result []Banner
+	err := c.userDB.
+		Order("starts_at").
+		Find(&result).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	banners := make([]user.Banner, 0, len(result))
+	for _, v := range result {
+		b, err := c.convertBannerToUser(v)
+		if err != nil {
+			return nil, err
+		}
+		banners = append(banners, *b)
+	}
+
+	return banners, nil
+}
+
+func (c *cockroachdb) convertBannerFromUser(b user.Banner) (*Banner, error) {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := c.encrypt(user.VersionBanner, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Banner{
+		ID:       b.ID,
+		StartsAt: b.StartsAt,
+		Blob:     eb,
+	}, nil
+}
+
+func (c *cockroachdb) convertBannerToUser(b Banner) (*user.Banner, error) {
+	payload, _, err := c.decrypt(b.Blob)
+	if err != nil {
+		return nil, err
+	}
+	var ub user.Banner
+	err = json.Unmarshal(payload, &ub)
+	if err != nil {
+		return nil, err
+	}
+	return &ub, nil
+}
+
 // Close shuts down the database. All interface functions must return with
 // errShutdown if the backend is shutting down.
 //
@@ -936,6 +1196,18 @@ func (c *cockroachdb) createTables(tx *gorm.DB) error {
 			return err
 		}
 	}
+	if !tx.HasTable(tablePendingActions) {
+		err := tx.CreateTable(&PendingAction{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if !tx.HasTable(tableBanners) {
+		err := tx.CreateTable(&Banner{}).Error
+		if err != nil {
+			return err
+		}
+	}
 
 	// Insert version record
 	kv := KeyValue{
@@ -57,6 +57,34 @@ func (EmailHistory) TableName() string {
 	return tableEmailHistories
 }
 
+// PendingAction represents a pending admin action. Blob is an encrypted
+// user.PendingAction. The creation time is broken out of the encrypted blob
+// so that the pending actions can be ordered.
+type PendingAction struct {
+	ID        uuid.UUID `gorm:"primary_key"` // Pending action UUID
+	CreatedAt int64     `gorm:"not null"`    // Created at UNIX timestamp
+	Blob      []byte    `gorm:"not null"`    // Encrypted pending action
+}
+
+// TableName returns the table name of the PendingAction table.
+func (PendingAction) TableName() string {
+	return tablePendingActions
+}
+
+// Banner represents a site-wide banner. Blob is an encrypted user.Banner. The
+// start time is broken out of the encrypted blob so that the banners can be
+// ordered.
+type Banner struct {
+	ID       uuid.UUID `gorm:"primary_key"` // Banner UUID
+	StartsAt int64     `gorm:"not null"`    // Start UNIX timestamp
+	Blob     []byte    `gorm:"not null"`    // Encrypted banner
+}
+
+// TableName returns the table name of the Banner table.
+func (Banner) TableName() string {
+	return tableBanners
+}
+
 // Session represents a user session.
 //
 // Key is a SHA256 hash of the decoded session ID. The session Store handles
@@ -0,0 +1,120 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Everything defined in this file is a temporary measure until proper user
+// plugins have been added to politeiawww.
+
+var (
+	// creditsMtx protects the creditsLocks map.
+	creditsMtx sync.Mutex
+
+	// creditsLocks contains the proposal credit locks of the users that
+	// currently have a proposal credit update in progress.
+	creditsLocks = make(map[uuid.UUID]*creditsLock)
+)
+
+// creditsLock is the proposal credits lock of a single user. The lock is
+// removed from the creditsLocks map once it is no longer referenced.
+type creditsLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lockProposalCredits locks the proposal credits of the provided users. The
+// locks are acquired in a deterministic order to prevent deadlocks between
+// updates that lock the same users. The returned function releases the locks.
+func lockProposalCredits(userIDs []uuid.UUID) func() {
+	ids := make([]uuid.UUID, 0, len(userIDs))
+	seen := make(map[uuid.UUID]struct{}, len(userIDs))
+	for _, v := range userIDs {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		ids = append(ids, v)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+
+	locks := make([]*creditsLock, 0, len(ids))
+	creditsMtx.Lock()
+	for _, id := range ids {
+		l, ok := creditsLocks[id]
+		if !ok {
+			l = &creditsLock{}
+			creditsLocks[id] = l
+		}
+		l.refs++
+		locks = append(locks, l)
+	}
+	creditsMtx.Unlock()
+
+	for _, l := range locks {
+		l.Lock()
+	}
+
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+		creditsMtx.Lock()
+		for i, id := range ids {
+			locks[i].refs--
+			if locks[i].refs == 0 {
+				delete(creditsLocks, id)
+			}
+		}
+		creditsMtx.Unlock()
+	}
+}
+
+// ProposalCreditsUpdate executes the provided update function on the users
+// with the provided IDs and saves the updated users to the database. All
+// changes to the proposal credits of a user must be made using this function.
+//
+// The proposal credits of the users are locked for the duration of the
+// update and the users are retrieved from the database after the locks have
+// been acquired. This ensures that the update is applied to the most recent
+// version of the users and that concurrent updates cannot spend the same
+// proposal credits twice. The users are passed to the update function and are
+// saved in the same order as the provided IDs. The users are not saved if the
+// update function returns an error.
+func ProposalCreditsUpdate(db Database, userIDs []uuid.UUID, update func(users []*User) error) error {
+	unlock := lockProposalCredits(userIDs)
+	defer unlock()
+
+	users := make([]*User, 0, len(userIDs))
+	for _, id := range userIDs {
+		u, err := db.UserGetById(id)
+		if err != nil {
+			return err
+		}
+		users = append(users, u)
+	}
+
+	err := update(users)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		err := db.UserUpdate(*u)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
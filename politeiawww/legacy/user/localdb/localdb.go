@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -27,10 +28,18 @@ const (
 
 	// The key for a user email history is emailHistoryPrefix+userID
 	emailHistoryPrefix = "emailhistory:"
+
+	// The key for a pending action is pendingActionPrefix+actionID
+	pendingActionPrefix = "pendingaction:"
+
+	// The key for a banner is bannerPrefix+bannerID
+	bannerPrefix = "banner:"
 )
 
 var (
-	_ user.Database = (*localdb)(nil)
+	_ user.Database         = (*localdb)(nil)
+	_ user.PendingActionsDB = (*localdb)(nil)
+	_ user.BannersDB        = (*localdb)(nil)
 )
 
 // localdb implements the Database interface.
@@ -58,7 +67,9 @@ func isUserRecord(key string) bool {
 		!strings.HasPrefix(key, sessionPrefix) &&
 		!strings.HasPrefix(key, cmsUserPrefix) &&
 		!strings.HasPrefix(key, cmsCodeStatsPrefix) &&
-		!strings.HasPrefix(key, emailHistoryPrefix)
+		!strings.HasPrefix(key, emailHistoryPrefix) &&
+		!strings.HasPrefix(key, pendingActionPrefix) &&
+		!strings.HasPrefix(key, bannerPrefix)
 }
 
 // Store new user.
@@ -367,12 +378,18 @@ func (l *localdb) UserUpdate(u user.User) error {
 
 	log.Debugf("UserUpdate: %v", u)
 
-	// Make sure user already exists
+	// Make sure user already exists. User records are keyed by
+	// email so the record must be moved if the email has changed.
 	exists, err := l.userdb.Has([]byte(u.Email), nil)
 	if err != nil {
 		return err
-	} else if !exists {
-		return user.ErrUserNotFound
+	}
+	var oldKey []byte
+	if !exists {
+		oldKey, err = l.userKeyByID(u.ID)
+		if err != nil {
+			return err
+		}
 	}
 
 	payload, err := user.EncodeUser(u)
@@ -380,7 +397,42 @@ func (l *localdb) UserUpdate(u user.User) error {
 		return err
 	}
 
-	return l.userdb.Put([]byte(u.Email), payload, nil)
+	if oldKey == nil {
+		return l.userdb.Put([]byte(u.Email), payload, nil)
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete(oldKey)
+	batch.Put([]byte(u.Email), payload)
+	return l.userdb.Write(batch, nil)
+}
+
+// userKeyByID returns the database key of the user record with the provided
+// ID.
+//
+// This function must be called WITH the lock held.
+func (l *localdb) userKeyByID(id uuid.UUID) ([]byte, error) {
+	iter := l.userdb.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		if !isUserRecord(string(key)) {
+			continue
+		}
+		u, err := user.DecodeUser(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		if u.ID == id {
+			k := make([]byte, len(key))
+			copy(k, key)
+			return k, nil
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	return nil, user.ErrUserNotFound
 }
 
 // Update existing user.
@@ -542,6 +594,176 @@ func (l *localdb) EmailHistoriesGet(users []uuid.UUID) (map[uuid.UUID]user.Email
 	return histories, nil
 }
 
+// PendingActionSave creates or updates the provided pending action.
+//
+// PendingActionSave satisfies the user PendingActionsDB interface.
+func (l *localdb) PendingActionSave(pa user.PendingAction) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.shutdown {
+		return user.ErrShutdown
+	}
+
+	log.Debugf("PendingActionSave: %v", pa.ID)
+
+	payload, err := json.Marshal(pa)
+	if err != nil {
+		return err
+	}
+	key := []byte(pendingActionPrefix + pa.ID.String())
+	return l.userdb.Put(key, payload, nil)
+}
+
+// PendingActionGet returns the pending action for the provided ID.
+//
+// PendingActionGet satisfies the user PendingActionsDB interface.
+func (l *localdb) PendingActionGet(id uuid.UUID) (*user.PendingAction, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.shutdown {
+		return nil, user.ErrShutdown
+	}
+
+	log.Debugf("PendingActionGet: %v", id)
+
+	payload, err := l.userdb.Get([]byte(pendingActionPrefix+id.String()), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, user.ErrPendingActionNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var pa user.PendingAction
+	err = json.Unmarshal(payload, &pa)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pa, nil
+}
+
+// PendingActionsGetAll returns all pending actions sorted by creation time
+// from oldest to newest.
+//
+// PendingActionsGetAll satisfies the user PendingActionsDB interface.
+func (l *localdb) PendingActionsGetAll() ([]user.PendingAction, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.shutdown {
+		return nil, user.ErrShutdown
+	}
+
+	log.Debugf("PendingActionsGetAll")
+
+	actions := make([]user.PendingAction, 0, 16)
+	iter := l.userdb.NewIterator(util.BytesPrefix([]byte(pendingActionPrefix)),
+		nil)
+	for iter.Next() {
+		var pa user.PendingAction
+		err := json.Unmarshal(iter.Value(), &pa)
+		if err != nil {
+			iter.Release()
+			return nil, err
+		}
+		actions = append(actions, pa)
+	}
+	iter.Release()
+	if iter.Error() != nil {
+		return nil, iter.Error()
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].CreatedAt < actions[j].CreatedAt
+	})
+
+	return actions, nil
+}
+
+// BannerSave creates or updates the provided banner.
+//
+// BannerSave satisfies the user BannersDB interface.
+func (l *localdb) BannerSave(b user.Banner) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.shutdown {
+		return user.ErrShutdown
+	}
+
+	log.Debugf("BannerSave: %v", b.ID)
+
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	key := []byte(bannerPrefix + b.ID.String())
+	return l.userdb.Put(key, payload, nil)
+}
+
+// BannerDel deletes the banner for the provided ID.
+//
+// BannerDel satisfies the user BannersDB interface.
+func (l *localdb) BannerDel(id uuid.UUID) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.shutdown {
+		return user.ErrShutdown
+	}
+
+	log.Debugf("BannerDel: %v", id)
+
+	key := []byte(bannerPrefix + id.String())
+	ok, err := l.userdb.Has(key, nil)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return user.ErrBannerNotFound
+	}
+	return l.userdb.Delete(key, nil)
+}
+
+// BannersGetAll returns all banners sorted by their start time from oldest to
+// newest.
+//
+// BannersGetAll satisfies the user BannersDB interface.
+func (l *localdb) BannersGetAll() ([]user.Banner, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.shutdown {
+		return nil, user.ErrShutdown
+	}
+
+	log.Debugf("BannersGetAll")
+
+	banners := make([]user.Banner, 0, 16)
+	iter := l.userdb.NewIterator(util.BytesPrefix([]byte(bannerPrefix)), nil)
+	for iter.Next() {
+		var b user.Banner
+		err := json.Unmarshal(iter.Value(), &b)
+		if err != nil {
+			iter.Release()
+			return nil, err
+		}
+		banners = append(banners, b)
+	}
+	iter.Release()
+	if iter.Error() != nil {
+		return nil, iter.Error()
+	}
+
+	sort.SliceStable(banners, func(i, j int) bool {
+		return banners[i].StartsAt < banners[j].StartsAt
+	})
+
+	return banners, nil
+}
+
 // Close shuts down the database.  All interface functions MUST return with
 // errShutdown if the backend is shutting down.
 //
@@ -197,6 +197,10 @@ func TestIsUserRecord(t *testing.T) {
 			input: sessionPrefix + uuid.New().String(),
 			want:  false,
 		},
+		{
+			input: pendingActionPrefix + uuid.New().String(),
+			want:  false,
+		},
 	}
 
 	for _, test := range tests {
@@ -207,3 +211,59 @@ func TestIsUserRecord(t *testing.T) {
 		}
 	}
 }
+
+func TestPendingActions(t *testing.T) {
+	db, dataDir := setupTestData(t)
+	defer teardownTestData(t, db, dataDir)
+
+	// Lookup a pending action that doesn't exist
+	_, err := db.PendingActionGet(uuid.New())
+	if !errors.Is(err, user.ErrPendingActionNotFound) {
+		t.Fatalf("got error %v, want %v", err, user.ErrPendingActionNotFound)
+	}
+
+	// Save pending actions out of order
+	pa1 := user.PendingAction{
+		ID:          uuid.New(),
+		Type:        user.PendingActionTypeCensorRecord,
+		Status:      user.PendingActionStatusPending,
+		RequestedBy: uuid.New(),
+		CreatedAt:   2,
+	}
+	pa2 := user.PendingAction{
+		ID:          uuid.New(),
+		Type:        user.PendingActionTypeDeactivateUser,
+		Status:      user.PendingActionStatusPending,
+		RequestedBy: uuid.New(),
+		CreatedAt:   1,
+	}
+	for _, v := range []user.PendingAction{pa1, pa2} {
+		err = db.PendingActionSave(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Update a pending action
+	pa1.Status = user.PendingActionStatusRejected
+	err = db.PendingActionSave(pa1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.PendingActionGet(pa1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != pa1 {
+		t.Fatalf("got %+v, want %+v", *got, pa1)
+	}
+
+	// Verify all pending actions are returned in order
+	all, err := db.PendingActionsGetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0] != pa2 || all[1] != pa1 {
+		t.Fatalf("got %+v, want [%+v %+v]", all, pa2, pa1)
+	}
+}
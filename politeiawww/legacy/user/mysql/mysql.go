@@ -44,6 +44,8 @@ const (
 	tableNameIdentities     = "identities"
 	tableNameSessions       = "sessions"
 	tableNameEmailHistories = "email_histories"
+	tableNamePendingActions = "pending_actions"
+	tableNameBanners        = "banners"
 
 	// Key-value store keys.
 	keyPaywallAddressIndex = "paywalladdressindex"
@@ -88,9 +90,25 @@ const tableEmailHistories = `
   h_blob  BLOB NOT NULL
 `
 
+// tablePendingActions defines the pending_actions table.
+const tablePendingActions = `
+  id         VARCHAR(36) NOT NULL PRIMARY KEY,
+  created_at INT(11) NOT NULL,
+  pa_blob    BLOB NOT NULL
+`
+
+// tableBanners defines the banners table.
+const tableBanners = `
+  id        VARCHAR(36) NOT NULL PRIMARY KEY,
+  starts_at INT(11) NOT NULL,
+  b_blob    BLOB NOT NULL
+`
+
 var (
-	_ user.Database = (*mysql)(nil)
-	_ user.MailerDB = (*mysql)(nil)
+	_ user.Database         = (*mysql)(nil)
+	_ user.MailerDB         = (*mysql)(nil)
+	_ user.PendingActionsDB = (*mysql)(nil)
+	_ user.BannersDB        = (*mysql)(nil)
 )
 
 // mysql implements the user.Database interface.
@@ -1315,6 +1333,231 @@ func (m *mysql) EmailHistoriesGet(users []uuid.UUID) (map[uuid.UUID]user.EmailHi
 	return histories, nil
 }
 
+// PendingActionSave creates or updates the provided pending action.
+//
+// PendingActionSave satisfies the user PendingActionsDB interface.
+func (m *mysql) PendingActionSave(pa user.PendingAction) error {
+	log.Tracef("PendingActionSave: %v", pa.ID)
+
+	if m.isShutdown() {
+		return user.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	b, err := json.Marshal(pa)
+	if err != nil {
+		return err
+	}
+	eb, err := m.encrypt(user.VersionPendingAction, b)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.userDB.ExecContext(ctx,
+		`INSERT INTO pending_actions (id, created_at, pa_blob)
+    VALUES (?, ?, ?)
+    ON DUPLICATE KEY UPDATE
+    pa_blob = ?`,
+		pa.ID.String(), pa.CreatedAt, eb, eb)
+	if err != nil {
+		return fmt.Errorf("save pending action: %v", err)
+	}
+
+	return nil
+}
+
+// PendingActionGet returns the pending action for the provided ID.
+//
+// PendingActionGet satisfies the user PendingActionsDB interface.
+func (m *mysql) PendingActionGet(id uuid.UUID) (*user.PendingAction, error) {
+	log.Tracef("PendingActionGet: %v", id)
+
+	if m.isShutdown() {
+		return nil, user.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	var paBlob []byte
+	err := m.userDB.QueryRowContext(ctx,
+		"SELECT pa_blob FROM pending_actions WHERE id = ?", id.String()).
+		Scan(&paBlob)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, user.ErrPendingActionNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	return m.decodePendingAction(paBlob)
+}
+
+// PendingActionsGetAll returns all pending actions sorted by creation time
+// from oldest to newest.
+//
+// PendingActionsGetAll satisfies the user PendingActionsDB interface.
+func (m *mysql) PendingActionsGetAll() ([]user.PendingAction, error) {
+	log.Tracef("PendingActionsGetAll")
+
+	if m.isShutdown() {
+		return nil, user.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	rows, err := m.userDB.QueryContext(ctx,
+		"SELECT pa_blob FROM pending_actions ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions := make([]user.PendingAction, 0, 16)
+	for rows.Next() {
+		var paBlob []byte
+		if err := rows.Scan(&paBlob); err != nil {
+			return nil, err
+		}
+		pa, err := m.decodePendingAction(paBlob)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, *pa)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// decodePendingAction decrypts and decodes a pending action blob.
+func (m *mysql) decodePendingAction(paBlob []byte) (*user.PendingAction, error) {
+	b, _, err := m.decrypt(paBlob)
+	if err != nil {
+		return nil, err
+	}
+	var pa user.PendingAction
+	err = json.Unmarshal(b, &pa)
+	if err != nil {
+		return nil, err
+	}
+	return &pa, nil
+}
+
+// BannerSave creates or updates the provided banner.
+//
+// BannerSave satisfies the user BannersDB interface.
+func (m *mysql) BannerSave(b user.Banner) error {
+	log.Tracef("BannerSave: %v", b.ID)
+
+	if m.isShutdown() {
+		return user.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	eb, err := m.encrypt(user.VersionBanner, payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.userDB.ExecContext(ctx,
+		`INSERT INTO banners (id, starts_at, b_blob)
+    VALUES (?, ?, ?)
+    ON DUPLICATE KEY UPDATE
+    starts_at = ?, b_blob = ?`,
+		b.ID.String(), b.StartsAt, eb, b.StartsAt, eb)
+	if err != nil {
+		return fmt.Errorf("save banner: %v", err)
+	}
+
+	return nil
+}
+
+// BannerDel deletes the banner for the provided ID.
+//
+// BannerDel satisfies the user BannersDB interface.
+func (m *mysql) BannerDel(id uuid.UUID) error {
+	log.Tracef("BannerDel: %v", id)
+
+	if m.isShutdown() {
+		return user.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	res, err := m.userDB.ExecContext(ctx,
+		"DELETE FROM banners WHERE id = ?", id.String())
+	if err != nil {
+		return fmt.Errorf("delete banner: %v", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrBannerNotFound
+	}
+
+	return nil
+}
+
+// BannersGetAll returns all banners sorted by their start time from oldest to
+// newest.
+//
+// BannersGetAll satisfies the user BannersDB interface.
+func (m *mysql) BannersGetAll() ([]user.Banner, error) {
+	log.Tracef("BannersGetAll")
+
+	if m.isShutdown() {
+		return nil, user.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	rows, err := m.userDB.QueryContext(ctx,
+		"SELECT b_blob FROM banners ORDER BY starts_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	banners := make([]user.Banner, 0, 16)
+	for rows.Next() {
+		var bBlob []byte
+		if err := rows.Scan(&bBlob); err != nil {
+			return nil, err
+		}
+		b, _, err := m.decrypt(bBlob)
+		if err != nil {
+			return nil, err
+		}
+		var banner user.Banner
+		err = json.Unmarshal(b, &banner)
+		if err != nil {
+			return nil, err
+		}
+		banners = append(banners, banner)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return banners, nil
+}
+
 // Close shuts down the database.  All interface functions must return with
 // errShutdown if the backend is shutting down.
 //
@@ -1402,6 +1645,24 @@ func New(host, password, network, encryptionKey string) (*mysql, error) {
 			tableNameEmailHistories, err)
 	}
 
+	// Setup pending_actions table.
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNamePendingActions, tablePendingActions)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create %v table: %v",
+			tableNamePendingActions, err)
+	}
+
+	// Setup banners table.
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameBanners, tableBanners)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create %v table: %v",
+			tableNameBanners, err)
+	}
+
 	// Load encryption key.
 	key, err := util.LoadEncryptionKey(log, encryptionKey)
 	if err != nil {
@@ -1035,3 +1035,97 @@ func TestSetPaywallAddressIndex(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %s", err)
 	}
 }
+
+func TestPendingActionSave(t *testing.T) {
+	mdb, mock, close := setupTestDB(t)
+	defer close()
+
+	// Arguments
+	pa := user.PendingAction{
+		ID:        uuid.New(),
+		Type:      user.PendingActionTypeDeactivateUser,
+		Status:    user.PendingActionStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+
+	// Query
+	sql := `INSERT INTO pending_actions (id, created_at, pa_blob)
+    VALUES (?, ?, ?)
+    ON DUPLICATE KEY UPDATE
+    pa_blob = ?`
+
+	// Success Expectations
+	mock.ExpectExec(regexp.QuoteMeta(sql)).
+		WithArgs(pa.ID.String(), pa.CreatedAt, AnyBlob{}, AnyBlob{}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Execute method
+	err := mdb.PendingActionSave(pa)
+	if err != nil {
+		t.Errorf("PendingActionSave unwanted error: %s", err)
+	}
+
+	// Make sure expectations were met
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPendingActionGet(t *testing.T) {
+	mdb, mock, close := setupTestDB(t)
+	defer close()
+
+	// Arguments
+	pa := user.PendingAction{
+		ID:        uuid.New(),
+		Type:      user.PendingActionTypeCensorRecord,
+		Status:    user.PendingActionStatusPending,
+		CreatedAt: time.Now().Unix(),
+	}
+	b, err := json.Marshal(pa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := mdb.encrypt(user.VersionPendingAction, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Query
+	sql := `SELECT pa_blob FROM pending_actions WHERE id = ?`
+
+	// Success Expectations
+	mock.ExpectQuery(regexp.QuoteMeta(sql)).
+		WithArgs(pa.ID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"pa_blob"}).AddRow(blob))
+
+	// Execute method
+	got, err := mdb.PendingActionGet(pa.ID)
+	if err != nil {
+		t.Errorf("PendingActionGet unwanted error: %s", err)
+	}
+	if got == nil || *got != pa {
+		t.Errorf("got pending action %+v, want %+v", got, pa)
+	}
+
+	// Negative Expectations
+	randomID := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(sql)).
+		WithArgs(randomID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"pa_blob"}))
+
+	// Execute method
+	_, err = mdb.PendingActionGet(randomID)
+	if !errors.Is(err, user.ErrPendingActionNotFound) {
+		t.Errorf("expecting error %s but got %v",
+			user.ErrPendingActionNotFound, err)
+	}
+
+	// Make sure expectations were met for both success and failure
+	// conditions
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
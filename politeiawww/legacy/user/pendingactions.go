@@ -0,0 +1,99 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PendingActionsDB describes the interface used to interact with the pending
+// actions table from the user database. A pending action is a destructive
+// admin action that must be confirmed by a second admin before it is
+// executed.
+type PendingActionsDB interface {
+	// PendingActionSave creates or updates the provided pending action.
+	PendingActionSave(PendingAction) error
+
+	// PendingActionGet returns the pending action for the provided ID.
+	// ErrPendingActionNotFound is returned if a pending action is not
+	// found.
+	PendingActionGet(id uuid.UUID) (*PendingAction, error)
+
+	// PendingActionsGetAll returns all pending actions, regardless of
+	// their status, sorted by creation time from oldest to newest.
+	PendingActionsGetAll() ([]PendingAction, error)
+}
+
+// PendingActionT represents the type of a pending admin action.
+type PendingActionT int
+
+const (
+	// PendingActionTypeInvalid is an invalid pending action type.
+	PendingActionTypeInvalid PendingActionT = 0
+
+	// PendingActionTypeCensorRecord is a pending action to censor a
+	// vetted record. The payload is a JSON encoded records v1
+	// SetStatus.
+	PendingActionTypeCensorRecord PendingActionT = 1
+
+	// PendingActionTypeDeactivateUser is a pending action to deactivate
+	// a user. The payload is a JSON encoded www v1 ManageUser.
+	PendingActionTypeDeactivateUser PendingActionT = 2
+)
+
+// PendingActionStatusT represents the status of a pending admin action.
+type PendingActionStatusT int
+
+const (
+	// PendingActionStatusInvalid is an invalid pending action status.
+	PendingActionStatusInvalid PendingActionStatusT = 0
+
+	// PendingActionStatusPending indicates that the action is awaiting
+	// confirmation from a second admin.
+	PendingActionStatusPending PendingActionStatusT = 1
+
+	// PendingActionStatusConfirmed indicates that the action was
+	// confirmed by a second admin and has been executed.
+	PendingActionStatusConfirmed PendingActionStatusT = 2
+
+	// PendingActionStatusRejected indicates that the action was rejected
+	// by an admin and will not be executed.
+	PendingActionStatusRejected PendingActionStatusT = 3
+)
+
+// PendingAction is a destructive admin action that requires the confirmation
+// of a second admin before it is executed. A pending action that has not been
+// confirmed prior to its expiry can no longer be confirmed.
+type PendingAction struct {
+	ID          uuid.UUID            `json:"id"`
+	Type        PendingActionT       `json:"type"`
+	Status      PendingActionStatusT `json:"status"`
+	Payload     string               `json:"payload"`     // JSON encoded request
+	RequestedBy uuid.UUID            `json:"requestedby"` // Admin user ID
+	ResolvedBy  uuid.UUID            `json:"resolvedby"`  // Admin user ID
+	CreatedAt   int64                `json:"createdat"`   // Unix timestamp
+	ExpiresAt   int64                `json:"expiresat"`   // Unix timestamp
+	ResolvedAt  int64                `json:"resolvedat"`  // Unix timestamp
+}
+
+// NewPendingAction returns a new pending action that must be confirmed within
+// the provided confirmation window.
+func NewPendingAction(t PendingActionT, payload string, requestedBy uuid.UUID, window time.Duration) PendingAction {
+	now := time.Now()
+	return PendingAction{
+		ID:          uuid.New(),
+		Type:        t,
+		Status:      PendingActionStatusPending,
+		Payload:     payload,
+		RequestedBy: requestedBy,
+		CreatedAt:   now.Unix(),
+		ExpiresAt:   now.Add(window).Unix(),
+	}
+}
+
+// VersionPendingAction is the version of the PendingAction struct.
+const VersionPendingAction uint32 = 1
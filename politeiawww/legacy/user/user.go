@@ -42,6 +42,14 @@ var (
 	// ErrCodeStatsNotFound indicates that an requested code stats entry wasn't
 	// found.
 	ErrCodeStatsNotFound = errors.New("code stats not found")
+
+	// ErrPendingActionNotFound indicates that a pending admin action was
+	// not found in the database.
+	ErrPendingActionNotFound = errors.New("pending action not found")
+
+	// ErrBannerNotFound indicates that a banner was not found in the
+	// database.
+	ErrBannerNotFound = errors.New("banner not found")
 )
 
 // Identity wraps an ed25519 public key and timestamps to indicate if it is
@@ -59,11 +67,18 @@ var (
 // inactive and active identities can be marked as deactivated. An inactive
 // identity being deactivated means that the identity was never verified before
 // a newer identity was created. An active identity being deactivated means
-// that a newer identity was created to replace the active identity.
+// that a newer identity was created to replace the active identity or that
+// the user revoked the identity.
+//
+// A user may have multiple active identities. An identity that is marked as
+// additional is activated alongside the existing active identities instead of
+// replacing them.
 type Identity struct {
-	Key         [identity.PublicKeySize]byte `json:"key"`         // ed25519 public key
-	Activated   int64                        `json:"activated"`   // Time key as activated for use
-	Deactivated int64                        `json:"deactivated"` // Time key was deactivated
+	Key         [identity.PublicKeySize]byte `json:"key"`                  // ed25519 public key
+	Activated   int64                        `json:"activated"`            // Time key as activated for use
+	Deactivated int64                        `json:"deactivated"`          // Time key was deactivated
+	Label       string                       `json:"label,omitempty"`      // User provided description
+	Additional  bool                         `json:"additional,omitempty"` // Don't replace active identities
 }
 
 // Activate activates the identity by setting the activated timestamp.
@@ -162,6 +177,84 @@ type ProposalCredit struct {
 	CensorshipToken string `json:"censorshiptoken"` // Token of proposal that spent this credit
 }
 
+// ProposalCreditTransfer is a proposal credit ledger entry that records the
+// transfer of unspent proposal credits from one user to another. The entry is
+// saved to both the sender and the recipient. The transferred credits retain
+// the paywall and payment details of the original purchase.
+type ProposalCreditTransfer struct {
+	FromUserID   string           `json:"fromuserid"`
+	FromUsername string           `json:"fromusername"`
+	ToUserID     string           `json:"touserid"`
+	ToUsername   string           `json:"tousername"`
+	Credits      []ProposalCredit `json:"credits"`
+	Timestamp    int64            `json:"timestamp"` // Unix timestamp
+}
+
+// ProposalCreditRefund is a proposal credit ledger entry that records the
+// refund of a spent proposal credit. A credit is refunded when the author
+// withdraws the proposal that the credit was spent on. The refunded credit is
+// moved back to the user's unspent proposal credits.
+type ProposalCreditRefund struct {
+	Token     string         `json:"token"` // Token of the withdrawn proposal
+	Credit    ProposalCredit `json:"credit"`
+	Timestamp int64          `json:"timestamp"` // Unix timestamp
+}
+
+// EmailChangeActionT represents an email change audit log action.
+type EmailChangeActionT int
+
+const (
+	// EmailChangeActionInvalid is an invalid email change action.
+	EmailChangeActionInvalid EmailChangeActionT = 0
+
+	// EmailChangeActionRequested indicates that the user requested an
+	// email change and that verification tokens were sent to both the
+	// old and the new email address.
+	EmailChangeActionRequested EmailChangeActionT = 1
+
+	// EmailChangeActionVerifiedOld indicates that the token that was
+	// sent to the old email address was verified.
+	EmailChangeActionVerifiedOld EmailChangeActionT = 2
+
+	// EmailChangeActionVerifiedNew indicates that the token that was
+	// sent to the new email address was verified.
+	EmailChangeActionVerifiedNew EmailChangeActionT = 3
+
+	// EmailChangeActionCompleted indicates that both tokens were
+	// verified and that the user email address was updated.
+	EmailChangeActionCompleted EmailChangeActionT = 4
+)
+
+var (
+	// EmailChangeActions contains the human readable email change
+	// actions.
+	EmailChangeActions = map[EmailChangeActionT]string{
+		EmailChangeActionInvalid:     "invalid",
+		EmailChangeActionRequested:   "requested",
+		EmailChangeActionVerifiedOld: "verified old email",
+		EmailChangeActionVerifiedNew: "verified new email",
+		EmailChangeActionCompleted:   "completed",
+	}
+)
+
+// EmailChange is an email change audit log entry.
+type EmailChange struct {
+	Action    EmailChangeActionT `json:"action"`
+	OldEmail  string             `json:"oldemail"`
+	NewEmail  string             `json:"newemail"`
+	Timestamp int64              `json:"timestamp"` // Unix timestamp
+}
+
+// PushSubscription is a web push subscription of one of the user's browser
+// clients. The keys are the base64url encoded keys returned by the Push API.
+type PushSubscription struct {
+	Endpoint      string `json:"endpoint"`      // Push service endpoint URL
+	P256dh        string `json:"p256dh"`        // Client ECDH public key
+	Auth          string `json:"auth"`          // Client auth secret
+	Notifications uint64 `json:"notifications"` // Push notification bits
+	Timestamp     int64  `json:"timestamp"`     // Unix timestamp of creation
+}
+
 // VersionUser is the version of the User struct.
 const VersionUser uint32 = 1
 
@@ -186,6 +279,20 @@ type User struct {
 	ResetPasswordVerificationToken  []byte `json:"resetpasswordverificationtoken"`
 	ResetPasswordVerificationExpiry int64  `json:"resetpasswordverificationexpiry"`
 
+	// Email change verification. An email change must be verified
+	// using both the token that was sent to the current email address
+	// and the token that was sent to the new email address. A token is
+	// cleared once it has been verified. The email address is updated
+	// once both tokens have been verified.
+	ChangeEmailAddress            string `json:"changeemailaddress"`
+	ChangeEmailOldToken           []byte `json:"changeemailoldtoken"`
+	ChangeEmailNewToken           []byte `json:"changeemailnewtoken"`
+	ChangeEmailVerificationExpiry int64  `json:"changeemailverificationexpiry"`
+
+	// EmailChanges is the audit log of all email change actions for
+	// the user in chronological order.
+	EmailChanges []EmailChange `json:"emailchanges"`
+
 	// PaywallAddressIndex is the index that is used to generate the
 	// paywall address for the user. The same paywall address is used
 	// for the user registration paywall and for proposal credit
@@ -226,12 +333,15 @@ type User struct {
 	// A user will only ever have one inactive identity at a time.
 	//
 	// Active identities
-	// A verified user will always have one active identity.
-	// A verified user may have both an active and inactive identity if
-	// they have requested a new identity but have not yet verified it.
+	// A verified user will always have at least one active identity.
+	// A user may register additional active identities, e.g. one for
+	// each device that they use. A verified user may have both active
+	// and inactive identities if they have requested a new identity
+	// but have not yet verified it.
 	//
 	// Deactivated identities
-	// An identity is deactivated when it is replaced by a new identity.
+	// An identity is deactivated when it is replaced by a new identity
+	// or when it is revoked by the user.
 	// The key of a deactivated identity is no longer valid.
 	// An identity cannot be re-activated once it has been deactivated.
 	Identities []Identity `json:"identities"`
@@ -253,15 +363,28 @@ type User struct {
 	// the proposal credit was purchased at is in atoms.
 	SpentProposalCredits []ProposalCredit `json:"spentproposalcredits"`
 
+	// All proposal credit transfers that the user has sent or received in
+	// chronological order.
+	ProposalCreditTransfers []ProposalCreditTransfer `json:"proposalcredittransfers,omitempty"`
+
+	// All proposal credit refunds that the user has received in
+	// chronological order.
+	ProposalCreditRefunds []ProposalCreditRefund `json:"proposalcreditrefunds,omitempty"`
+
 	// TOTP Secret Key and type of TOTP being used.
 	TOTPSecret             string  `json:"totpsecret"`
 	TOTPType               int     `json:"totptype"`
 	TOTPVerified           bool    `json:"totpverified"` // whether current totp secret has been verified with
 	TOTPLastUpdated        []int64 `json:"totplastupdated"`
 	TOTPLastFailedCodeTime []int64 `json:"totplastfailedcodetime"`
+
+	// PushSubscriptions contains the web push subscriptions of the
+	// user's browser clients.
+	PushSubscriptions []PushSubscription `json:"pushsubscriptions,omitempty"`
 }
 
-// ActiveIdentity returns the active identity for the user if one exists.
+// ActiveIdentity returns the active identity for the user if one exists. If
+// the user has multiple active identities, the first one is returned.
 func (u *User) ActiveIdentity() *Identity {
 	for k, v := range u.Identities {
 		if v.IsActive() {
@@ -271,6 +394,28 @@ func (u *User) ActiveIdentity() *Identity {
 	return nil
 }
 
+// ActiveIdentities returns all of the active identities for the user.
+func (u *User) ActiveIdentities() []Identity {
+	ids := make([]Identity, 0, len(u.Identities))
+	for _, v := range u.Identities {
+		if v.IsActive() {
+			ids = append(ids, v)
+		}
+	}
+	return ids
+}
+
+// IsActivePublicKey returns whether the provided hex encoded public key
+// corresponds to one of the user's active identities.
+func (u *User) IsActivePublicKey(publicKey string) bool {
+	for _, v := range u.Identities {
+		if v.IsActive() && v.String() == publicKey {
+			return true
+		}
+	}
+	return false
+}
+
 // InactiveIdentity returns the inactive identity for the user if one exists.
 func (u *User) InactiveIdentity() *Identity {
 	for k, v := range u.Identities {
@@ -331,8 +476,8 @@ func (u *User) AddIdentity(id Identity) error {
 
 // ActivateIdentity sets the identity associated with the provided key as the
 // active identity for the user. The provided key must correspond to an
-// inactive identity. If there is an existing active identity, it wil be
-// deactivated.
+// inactive identity. If there are existing active identities, they will be
+// deactivated unless the inactive identity is marked as additional.
 func (u *User) ActivateIdentity(key []byte) error {
 	if u.Identities == nil {
 		return fmt.Errorf("identity not found")
@@ -355,16 +500,17 @@ func (u *User) ActivateIdentity(key []byte) error {
 		return fmt.Errorf("identity is activated")
 	}
 
-	// Deactivate any active identities. There should only ever be a
-	// single active identity at a time, but due to a prior bug in the
-	// early version of politeia, this may not hold true. Check all
-	// identities just to be sure.
+	// Deactivate any other identities. Active identities are kept if
+	// the new identity is being added alongside them.
 	for k, v := range u.Identities {
 		// Skip the inactive identity that is going
 		// to be the new active identity.
 		if inactive.String() == v.String() {
 			continue
 		}
+		if inactive.Additional && v.IsActive() {
+			continue
+		}
 
 		if v.Deactivated == 0 {
 			u.Identities[k].Deactivate()
@@ -377,6 +523,40 @@ func (u *User) ActivateIdentity(key []byte) error {
 	return nil
 }
 
+// RevokeIdentity deactivates the active identity associated with the provided
+// key. The last active identity of a user cannot be revoked.
+func (u *User) RevokeIdentity(key []byte) error {
+	var active *Identity
+	for k, v := range u.Identities {
+		if bytes.Equal(v.Key[:], key) {
+			active = &u.Identities[k]
+			break
+		}
+	}
+	switch {
+	case active == nil:
+		return fmt.Errorf("identity not found")
+	case !active.IsActive():
+		return fmt.Errorf("identity is not active")
+	case len(u.ActiveIdentities()) == 1:
+		return fmt.Errorf("identity is the last active identity")
+	}
+
+	active.Deactivate()
+
+	return nil
+}
+
+// AddEmailChange appends an entry to the email change audit log of the user.
+func (u *User) AddEmailChange(action EmailChangeActionT, oldEmail, newEmail string) {
+	u.EmailChanges = append(u.EmailChanges, EmailChange{
+		Action:    action,
+		OldEmail:  oldEmail,
+		NewEmail:  newEmail,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
 // NotificationIsEnabled returns whether the user has the provided notification
 // bit enabled. This function will always return false if the user has been
 // deactivated.
@@ -387,6 +567,21 @@ func (u *User) NotificationIsEnabled(ntfnBit uint64) bool {
 	return u.EmailNotifications&ntfnBit != 0
 }
 
+// PushSubscriptionsEnabled returns the user's web push subscriptions that
+// have the provided push notification bit set.
+func (u *User) PushSubscriptionsEnabled(ntfnBit uint64) []PushSubscription {
+	if u.Deactivated {
+		return nil
+	}
+	subs := make([]PushSubscription, 0, len(u.PushSubscriptions))
+	for _, v := range u.PushSubscriptions {
+		if v.Notifications&ntfnBit != 0 {
+			subs = append(subs, v)
+		}
+	}
+	return subs
+}
+
 // EncodeUser encodes User into a JSON byte slice.
 func EncodeUser(u User) ([]byte, error) {
 	b, err := json.Marshal(u)
@@ -884,6 +884,106 @@ func TestProcessVerifyUpdateUserKey(t *testing.T) {
 	}
 }
 
+func TestProcessRevokeUserKey(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	usr, id := newUser(t, p, true, false)
+	pubkey := id.Public.String()
+
+	// Register an additional key for the user
+	newid, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newpk := newid.Public.String()
+	uukr, err := p.processUpdateUserKey(usr, www.UpdateUserKey{
+		PublicKey:  newpk,
+		Label:      "laptop",
+		Additional: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newid.SignMessage([]byte(uukr.VerificationToken))
+	_, err = p.processVerifyUpdateUserKey(usr, www.VerifyUpdateUserKey{
+		VerificationToken: uukr.VerificationToken,
+		Signature:         hex.EncodeToString(s[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !usr.IsActivePublicKey(pubkey) || !usr.IsActivePublicKey(newpk) {
+		t.Fatalf("additional key did not remain alongside the existing key")
+	}
+
+	randomid, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name string
+		rk   www.RevokeUserKey
+		want error
+	}{
+		{
+			"invalid public key",
+			www.RevokeUserKey{
+				PublicKey: "",
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPublicKey,
+			},
+		},
+		{
+			"identity not found",
+			www.RevokeUserKey{
+				PublicKey: randomid.Public.String(),
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusIdentityNotFound,
+			},
+		},
+		{
+			"success",
+			www.RevokeUserKey{
+				PublicKey: pubkey,
+			},
+			nil,
+		},
+		{
+			"already revoked",
+			www.RevokeUserKey{
+				PublicKey: pubkey,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusIdentityNotFound,
+			},
+		},
+		{
+			"last active identity",
+			www.RevokeUserKey{
+				PublicKey: newpk,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusLastActiveIdentity,
+			},
+		},
+	}
+
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := p.processRevokeUserKey(usr, v.rk)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestLogin(t *testing.T) {
 	p, cleanup := newTestPoliteiawww(t)
 	defer cleanup()
@@ -924,6 +1024,7 @@ func TestLogin(t *testing.T) {
 		Email:              usr.Email,
 		Username:           usr.Username,
 		PublicKey:          id.Public.String(),
+		ActiveKeys:         1,
 		PaywallAddress:     usr.NewUserPaywallAddress,
 		PaywallAmount:      usr.NewUserPaywallAmount,
 		PaywallTxNotBefore: usr.NewUserPaywallTxNotBefore,
@@ -1058,6 +1159,7 @@ func TestLogin(t *testing.T) {
 		Email:              usrTOTPVerified.Email,
 		Username:           usrTOTPVerified.Username,
 		PublicKey:          idTOTP.Public.String(),
+		ActiveKeys:         1,
 		PaywallAddress:     usrTOTPVerified.NewUserPaywallAddress,
 		PaywallAmount:      usrTOTPVerified.NewUserPaywallAmount,
 		PaywallTxNotBefore: usrTOTPVerified.NewUserPaywallTxNotBefore,
@@ -1180,6 +1282,7 @@ func TestLogin(t *testing.T) {
 		Email:              usrTOTPVerifiedTimeout.Email,
 		Username:           usrTOTPVerifiedTimeout.Username,
 		PublicKey:          idTOTPTimeout.Public.String(),
+		ActiveKeys:         1,
 		PaywallAddress:     usrTOTPVerifiedTimeout.NewUserPaywallAddress,
 		PaywallAmount:      usrTOTPVerifiedTimeout.NewUserPaywallAmount,
 		PaywallTxNotBefore: usrTOTPVerifiedTimeout.NewUserPaywallTxNotBefore,
@@ -1406,6 +1509,144 @@ func TestProcessChangePassword(t *testing.T) {
 	}
 }
 
+func TestProcessChangeEmail(t *testing.T) {
+	p, cleanup := newTestPoliteiawww(t)
+	defer cleanup()
+
+	// Create a new user. newUser() sets the password
+	// as the username.
+	u, _ := newUser(t, p, true, false)
+	other, _ := newUser(t, p, true, false)
+	pass := u.Username
+	oldEmail := u.Email
+	newEmail := "new" + u.Email
+
+	// Setup tests
+	var tests = []struct {
+		name string
+		ce   www.ChangeEmail
+		want error
+	}{
+		{
+			"wrong password",
+			www.ChangeEmail{
+				Password: "wrong!",
+				NewEmail: newEmail,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidPassword,
+			},
+		},
+		{
+			"malformed email",
+			www.ChangeEmail{
+				Password: pass,
+				NewEmail: "invalid",
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusMalformedEmail,
+			},
+		},
+		{
+			"duplicate email",
+			www.ChangeEmail{
+				Password: pass,
+				NewEmail: other.Email,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusDuplicateEmail,
+			},
+		},
+		{
+			"success",
+			www.ChangeEmail{
+				Password: pass,
+				NewEmail: newEmail,
+			},
+			nil,
+		},
+		{
+			"change already pending",
+			www.ChangeEmail{
+				Password: pass,
+				NewEmail: newEmail,
+			},
+			www.UserError{
+				ErrorCode: www.ErrorStatusVerificationTokenUnexpired,
+			},
+		},
+	}
+
+	// Run tests
+	var cer *www.ChangeEmailReply
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			reply, err := p.processChangeEmail(u, v.ce)
+			got := errToStr(err)
+			want := errToStr(v.want)
+			if got != want {
+				t.Errorf("got error %v, want %v",
+					got, want)
+			}
+			if err == nil {
+				cer = reply
+			}
+		})
+	}
+	if cer == nil {
+		t.Fatalf("email change was not requested")
+	}
+
+	// Verify the email change. The email address should only be
+	// updated once both tokens have been verified.
+	vce := www.VerifyChangeEmail{
+		VerificationToken: cer.NewEmailVerificationToken,
+	}
+	vcer, err := p.processVerifyChangeEmail(u, vce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vcer.Completed || u.Email != oldEmail {
+		t.Fatalf("email changed after a single verification")
+	}
+
+	// A token cannot be verified twice
+	_, err = p.processVerifyChangeEmail(u, vce)
+	got := errToStr(err)
+	want := errToStr(www.UserError{
+		ErrorCode: www.ErrorStatusVerificationTokenInvalid,
+	})
+	if got != want {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+
+	vce.VerificationToken = cer.OldEmailVerificationToken
+	vcer, err = p.processVerifyChangeEmail(u, vce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vcer.Completed {
+		t.Fatalf("email change not completed")
+	}
+
+	// Verify the user was updated and can be looked up by the new
+	// email address.
+	du, err := p.userByEmail(newEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if du.ID != u.ID || du.Email != newEmail {
+		t.Errorf("got user %v %v, want %v %v",
+			du.ID, du.Email, u.ID, newEmail)
+	}
+	if _, err := p.userByEmail(oldEmail); err != user.ErrUserNotFound {
+		t.Errorf("got error %v, want %v", err, user.ErrUserNotFound)
+	}
+	if len(du.EmailChanges) != 4 {
+		t.Errorf("got %v audit log entries, want 4", len(du.EmailChanges))
+	}
+}
+
 func TestProcessResetPassword(t *testing.T) {
 	p, cleanup := newTestPoliteiawww(t)
 	defer cleanup()
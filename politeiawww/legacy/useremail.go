@@ -139,6 +139,81 @@ func (p *Politeiawww) emailUserPasswordChanged(username string, recipient map[uu
 	return p.mail.SendToUsers(subject, body, recipient)
 }
 
+// emailUserEmailChange emails the links with the email change verification
+// tokens to both the old and the new email address. The old email address is
+// also notified of the requested change for security purposes.
+func (p *Politeiawww) emailUserEmailChange(username, oldEmail, newEmail, oldToken, newToken string, recipient map[uuid.UUID]string) error {
+	oldLink, err := p.createEmailLink(www.RouteVerifyChangeEmail, "",
+		oldToken, "")
+	if err != nil {
+		return err
+	}
+	newLink, err := p.createEmailLink(www.RouteVerifyChangeEmail, "",
+		newToken, "")
+	if err != nil {
+		return err
+	}
+
+	subject := "Verify Your Email Change"
+	tplData := userEmailChange{
+		Username: username,
+		OldEmail: oldEmail,
+		NewEmail: newEmail,
+		Link:     oldLink,
+	}
+	body, err := createBody(userEmailChangeTmpl, tplData)
+	if err != nil {
+		return err
+	}
+	err = p.mail.SendToUsers(subject, body, recipient)
+	if err != nil {
+		return err
+	}
+
+	// The new email address does not belong to a user yet
+	tplData.Link = newLink
+	body, err = createBody(userEmailChangeTmpl, tplData)
+	if err != nil {
+		return err
+	}
+	return p.mail.SendTo(subject, body, []string{newEmail})
+}
+
+// emailUserEmailChanged notifies the old email address that the email address
+// of the account has been changed.
+func (p *Politeiawww) emailUserEmailChanged(username, oldEmail, newEmail string) error {
+	tplData := userEmailChanged{
+		Username: username,
+		NewEmail: newEmail,
+	}
+
+	subject := "Email Changed - Security Notification"
+	body, err := createBody(userEmailChangedTmpl, tplData)
+	if err != nil {
+		return err
+	}
+
+	return p.mail.SendTo(subject, body, []string{oldEmail})
+}
+
+// emailUserProposalCreditsReceived notifies the user that proposal credits
+// have been transferred to their account by another user.
+func (p *Politeiawww) emailUserProposalCreditsReceived(username, fromUsername string, numCredits uint64, recipient map[uuid.UUID]string) error {
+	tplData := userProposalCreditsReceived{
+		Username:     username,
+		FromUsername: fromUsername,
+		NumCredits:   numCredits,
+	}
+
+	subject := "Proposal Credits Received"
+	body, err := createBody(userProposalCreditsReceivedTmpl, tplData)
+	if err != nil {
+		return err
+	}
+
+	return p.mail.SendToUsers(subject, body, recipient)
+}
+
 func (p *Politeiawww) createEmailLink(path, email, token, username string) (string, error) {
 	l, err := url.Parse(p.cfg.WebServerAddress + path)
 	if err != nil {
@@ -276,3 +351,71 @@ https://chat.decred.org/#/room/#politeia:decred.org
 
 var userPasswordChangedTmpl = template.Must(
 	template.New("userPasswordChanged").Parse(userPasswordChangedText))
+
+// User email change - Send verification link to the old and new email
+type userEmailChange struct {
+	Username string
+	OldEmail string
+	NewEmail string
+	Link     string // Verification link
+}
+
+const userEmailChangeText = `
+An email address change was requested for the Politeia account with the
+username {{.Username}}.
+
+Old email: {{.OldEmail}}
+New email: {{.NewEmail}}
+
+The change must be verified from both email addresses. Click the link below to
+verify the change from this email address:
+
+{{.Link}}
+
+If you did not perform this action, it's possible that your account has been
+compromised.  Please contact a Politeia administrator in the Politeia channel
+on Matrix.
+
+https://chat.decred.org/#/room/#politeia:decred.org
+`
+
+var userEmailChangeTmpl = template.Must(
+	template.New("userEmailChange").Parse(userEmailChangeText))
+
+// User email changed - Send to the old email address
+type userEmailChanged struct {
+	Username string
+	NewEmail string
+}
+
+const userEmailChangedText = `
+The email address of your Politeia account with the username {{.Username}} has
+been changed to {{.NewEmail}}. This email address will no longer receive
+notifications for the account.
+
+If you did not perform this action, it's possible that your account has been
+compromised.  Please contact a Politeia administrator in the Politeia channel
+on Matrix.
+
+https://chat.decred.org/#/room/#politeia:decred.org
+`
+
+var userEmailChangedTmpl = template.Must(
+	template.New("userEmailChanged").Parse(userEmailChangedText))
+
+// User proposal credits received - Send to user
+type userProposalCreditsReceived struct {
+	Username     string
+	FromUsername string
+	NumCredits   uint64
+}
+
+const userProposalCreditsReceivedText = `
+{{.FromUsername}} has transferred {{.NumCredits}} proposal credit(s) to your
+Politeia account with the username {{.Username}}. The credits can be used to
+submit new proposals.
+`
+
+var userProposalCreditsReceivedTmpl = template.Must(
+	template.New("userProposalCreditsReceived").
+		Parse(userProposalCreditsReceivedText))
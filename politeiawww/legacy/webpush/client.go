@@ -0,0 +1,239 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// messageTTL is the number of seconds that a push service should
+	// retain a push message when the user agent is not reachable.
+	messageTTL = 24 * 60 * 60
+
+	// jwtExpiry is the duration that a VAPID JWT is valid for. RFC 8292
+	// prohibits values longer than 24 hours.
+	jwtExpiry = 12 * time.Hour
+
+	// sendTimeout is the timeout of a single push service request.
+	sendTimeout = 30 * time.Second
+)
+
+// client provides a Web Push client that signs push service requests using
+// the application server's VAPID key (RFC 8292).
+//
+// client implements the Pusher interface.
+type client struct {
+	http      *http.Client
+	key       *ecdsa.PrivateKey // VAPID private key
+	publicKey string            // base64url encoded VAPID public key
+	subject   string            // VAPID contact URI
+	disabled  bool              // Has web push been disabled
+}
+
+// IsEnabled returns whether web push notifications are enabled.
+//
+// This function satisfies the Pusher interface.
+func (c *client) IsEnabled() bool {
+	return !c.disabled
+}
+
+// PublicKey returns the base64url encoded VAPID public key.
+//
+// This function satisfies the Pusher interface.
+func (c *client) PublicKey() string {
+	return c.publicKey
+}
+
+// Send encrypts the payload for the provided subscription and delivers it
+// to the subscription's push service.
+//
+// This function satisfies the Pusher interface.
+func (c *client) Send(s Subscription, payload []byte) error {
+	if c.disabled {
+		return nil
+	}
+
+	body, err := encrypt(s, payload)
+	if err != nil {
+		return err
+	}
+	auth, err := c.authorization(s.Endpoint, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.Endpoint,
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("TTL", strconv.Itoa(messageTTL))
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	r, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	switch r.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+		return nil
+	case http.StatusNotFound, http.StatusGone:
+		return ErrSubscriptionGone
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(r.Body, 1024))
+	return fmt.Errorf("push service %v: %v %s",
+		r.Request.URL.Host, r.StatusCode, bytes.TrimSpace(msg))
+}
+
+// authorization returns the VAPID Authorization header value for a push
+// service request to the provided endpoint.
+func (c *client) authorization(endpoint string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	// The audience is the origin of the push service.
+	header := map[string]string{
+		"typ": "JWT",
+		"alg": "ES256",
+	}
+	claims := map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(jwtExpiry).Unix(),
+		"sub": c.subject,
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cl, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(h) + "." +
+		base64.RawURLEncoding.EncodeToString(cl)
+
+	// ES256 signatures are the 32 byte big endian r and s values
+	// concatenated together.
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, c.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	jwt := unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+
+	return fmt.Sprintf("vapid t=%v, k=%v", jwt, c.publicKey), nil
+}
+
+// VerifySubscription verifies that the provided subscription contains a
+// valid push service endpoint and valid encryption keys.
+func VerifySubscription(s Subscription) error {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %v", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid endpoint: must be an https url")
+	}
+	_, _, err = keysDecode(s)
+	return err
+}
+
+// keyLoad loads the VAPID private key from the provided file. A new key is
+// generated and saved to the file if one does not exist yet. The key is
+// stored as the raw 32 byte private scalar.
+func keyLoad(keyFile string) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		log.Infof("Web push key not found, generating one...")
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		d := make([]byte, 32)
+		key.D.FillBytes(d)
+		err = os.WriteFile(keyFile, d, 0400)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("Web push key generated")
+		return key, nil
+	}
+	if len(b) != 32 {
+		return nil, fmt.Errorf("invalid web push key size: %v", len(b))
+	}
+
+	key := &ecdsa.PrivateKey{
+		D: new(big.Int).SetBytes(b),
+	}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(b)
+	return key, nil
+}
+
+// NewClient returns a new web push client. Web push is considered disabled
+// if no VAPID subject is provided.
+func NewClient(keyFile, subject string) (*client, error) {
+	if subject == "" {
+		log.Infof("Web push: DISABLED")
+		return &client{
+			disabled: true,
+		}, nil
+	}
+	if !strings.HasPrefix(subject, "mailto:") &&
+		!strings.HasPrefix(subject, "https:") {
+		return nil, fmt.Errorf("invalid web push subject '%v': must be "+
+			"a mailto: or https: uri", subject)
+	}
+
+	key, err := keyLoad(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	pk := elliptic.Marshal(key.Curve, key.X, key.Y)
+
+	c := client{
+		http: &http.Client{
+			Timeout: sendTimeout,
+		},
+		key:       key,
+		publicKey: base64.RawURLEncoding.EncodeToString(pk),
+		subject:   subject,
+	}
+
+	log.Infof("Web push subject: %v", subject)
+	log.Infof("Web push public key: %v", c.publicKey)
+
+	return &c, nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSubscription returns a push subscription and the user agent
+// private key that can be used to decrypt messages sent to it.
+func newTestSubscription(t *testing.T) (Subscription, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, authSecretSize)
+	_, err = rand.Read(auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := elliptic.Marshal(elliptic.P256(), key.X, key.Y)
+	return Subscription{
+		Endpoint: "https://push.example.com/send/abc",
+		P256dh:   base64.RawURLEncoding.EncodeToString(pk),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}, key
+}
+
+// decrypt decrypts an aes128gcm push message the way a user agent would.
+func decrypt(t *testing.T, s Subscription, uaPrivate *ecdsa.PrivateKey, msg []byte) []byte {
+	t.Helper()
+
+	curve := elliptic.P256()
+	salt := msg[:saltSize]
+	rs := binary.BigEndian.Uint32(msg[16:20])
+	if rs != recordSize {
+		t.Fatalf("record size: got %v, want %v", rs, recordSize)
+	}
+	idlen := int(msg[20])
+	asRaw := msg[21 : 21+idlen]
+	ciphertext := msg[21+idlen:]
+
+	auth, err := base64Decode(s.Auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := elliptic.Unmarshal(curve, asRaw)
+	sx, _ := curve.ScalarMult(x, y, uaPrivate.D.Bytes())
+	secret := make([]byte, 32)
+	sx.FillBytes(secret)
+
+	uaRaw := elliptic.Marshal(curve, uaPrivate.X, uaPrivate.Y)
+	keyInfo := append([]byte("WebPush: info\x00"), uaRaw...)
+	keyInfo = append(keyInfo, asRaw...)
+	ikm := hkdf(auth, secret, keyInfo, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Fatalf("invalid last record delimiter")
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+func TestEncrypt(t *testing.T) {
+	s, uaPrivate := newTestSubscription(t)
+
+	payload := []byte(`{"title":"Voting Started"}`)
+	msg, err := encrypt(s, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := decrypt(t, s, uaPrivate, msg)
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("got %s, want %s", got, payload)
+	}
+
+	// Payloads that do not fit into a single record are rejected.
+	_, err = encrypt(s, make([]byte, PayloadSizeMax+1))
+	if err == nil {
+		t.Fatalf("oversized payload was not rejected")
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "webpush.key")
+	c, err := NewClient(keyFile, "mailto:admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The key must be loaded from disk on subsequent runs.
+	c2, err := NewClient(keyFile, "mailto:admin@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.PublicKey() != c2.PublicKey() {
+		t.Fatalf("public key mismatch: %v != %v",
+			c.PublicKey(), c2.PublicKey())
+	}
+
+	now := time.Now()
+	auth, err := c.authorization("https://push.example.com/send/abc", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := "vapid t="
+	if !strings.HasPrefix(auth, prefix) {
+		t.Fatalf("invalid authorization %v", auth)
+	}
+	parts := strings.Split(strings.TrimPrefix(auth, prefix), ", k=")
+	if len(parts) != 2 || parts[1] != c.PublicKey() {
+		t.Fatalf("invalid authorization %v", auth)
+	}
+
+	// Verify the JWT signature and claims
+	jwt := strings.Split(parts[0], ".")
+	if len(jwt) != 3 {
+		t.Fatalf("invalid jwt %v", parts[0])
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(jwt[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(jwt[0] + "." + jwt[1]))
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&c2.key.PublicKey, digest[:], r, s) {
+		t.Fatalf("invalid jwt signature")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(jwt[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	err = json.Unmarshal(b, &claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Aud != "https://push.example.com" ||
+		claims.Exp != now.Add(jwtExpiry).Unix() ||
+		claims.Sub != "mailto:admin@example.com" {
+		t.Fatalf("invalid claims %+v", claims)
+	}
+}
+
+func TestVerifySubscription(t *testing.T) {
+	s, _ := newTestSubscription(t)
+
+	httpEndpoint := s
+	httpEndpoint.Endpoint = "http://push.example.com/send/abc"
+
+	badKey := s
+	badKey.P256dh = base64.RawURLEncoding.EncodeToString(make([]byte, 65))
+
+	badAuth := s
+	badAuth.Auth = base64.RawURLEncoding.EncodeToString(make([]byte, 8))
+
+	var tests = []struct {
+		name    string
+		s       Subscription
+		wantErr bool
+	}{
+		{"valid", s, false},
+		{"http endpoint", httpEndpoint, true},
+		{"invalid p256dh", badKey, true},
+		{"invalid auth", badAuth, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := VerifySubscription(v.s)
+			if (err != nil) != v.wantErr {
+				t.Errorf("got err %v, want err %v", err, v.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// recordSize is the aes128gcm record size that is used for all
+	// push messages. The entire payload is encrypted into a single
+	// record.
+	recordSize uint32 = 4096
+
+	// PayloadSizeMax is the maximum size of a push message payload
+	// before encryption. It is the record size minus the content coding
+	// header, the padding delimiter, and the AEAD tag.
+	PayloadSizeMax = int(recordSize) - 86 - 1 - 16
+
+	// authSecretSize is the size of the user agent authentication
+	// secret.
+	authSecretSize = 16
+
+	// saltSize is the size of the random salt that is generated for
+	// each push message.
+	saltSize = 16
+)
+
+// keysDecode decodes and validates the user agent public key and the
+// authentication secret of a push subscription.
+func keysDecode(s Subscription) (*ecdsa.PublicKey, []byte, error) {
+	b, err := base64Decode(s.P256dh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid p256dh: %v", err)
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), b)
+	if x == nil {
+		return nil, nil, fmt.Errorf("invalid p256dh: not a P-256 point")
+	}
+	auth, err := base64Decode(s.Auth)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid auth: %v", err)
+	}
+	if len(auth) != authSecretSize {
+		return nil, nil, fmt.Errorf("invalid auth: got %v bytes, want %v",
+			len(auth), authSecretSize)
+	}
+	pk := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     x,
+		Y:     y,
+	}
+	return pk, auth, nil
+}
+
+// encrypt encrypts the payload for the provided subscription using the
+// aes128gcm content coding described in RFC 8291 (Message Encryption for
+// Web Push). A new ephemeral application server key pair and salt are
+// generated for every message.
+func encrypt(s Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > PayloadSizeMax {
+		return nil, fmt.Errorf("payload exceeds max size: %v > %v",
+			len(payload), PayloadSizeMax)
+	}
+	uaPublic, auth, err := keysDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	asPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltSize)
+	_, err = io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, err
+	}
+	return encryptWithKeys(uaPublic, auth, asPrivate, salt, payload)
+}
+
+// encryptWithKeys performs the RFC 8291 encryption using the provided
+// application server key pair and salt.
+func encryptWithKeys(uaPublic *ecdsa.PublicKey, auth []byte, asPrivate *ecdsa.PrivateKey, salt, payload []byte) ([]byte, error) {
+	var (
+		curve      = elliptic.P256()
+		uaRaw      = elliptic.Marshal(curve, uaPublic.X, uaPublic.Y)
+		asRaw      = elliptic.Marshal(curve, asPrivate.X, asPrivate.Y)
+		sx, _      = curve.ScalarMult(uaPublic.X, uaPublic.Y, asPrivate.D.Bytes())
+		ecdhSecret = make([]byte, 32)
+	)
+	sx.FillBytes(ecdhSecret)
+
+	// Combine the ECDH secret with the authentication secret.
+	keyInfo := make([]byte, 0, 14+len(uaRaw)+len(asRaw))
+	keyInfo = append(keyInfo, []byte("WebPush: info\x00")...)
+	keyInfo = append(keyInfo, uaRaw...)
+	keyInfo = append(keyInfo, asRaw...)
+	ikm := hkdf(auth, ecdhSecret, keyInfo, 32)
+
+	// Derive the content encryption key and nonce.
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The payload is encrypted into a single record which makes it
+	// the last record. The last record is delimited using 0x02.
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, 0x02)
+
+	// Build the content coding header: salt | rs | idlen | keyid
+	var buf bytes.Buffer
+	buf.Write(salt)
+	rs := make([]byte, 4)
+	binary.BigEndian.PutUint32(rs, recordSize)
+	buf.Write(rs)
+	buf.WriteByte(byte(len(asRaw)))
+	buf.Write(asRaw)
+	buf.Write(gcm.Seal(nil, nonce, plaintext, nil))
+
+	return buf.Bytes(), nil
+}
+
+// hkdf performs a HMAC-SHA-256 based HKDF extract and expand. The output
+// length must not exceed a single SHA-256 block, which is always the case
+// for the keys that are derived by RFC 8291.
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	prk := mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{0x01})
+	return mac.Sum(nil)[:length]
+}
+
+// base64Decode decodes a base64url string. Both padded and unpadded
+// encodings are accepted since browsers are not consistent about it.
+func base64Decode(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		return b, nil
+	}
+	return base64.URLEncoding.DecodeString(s)
+}
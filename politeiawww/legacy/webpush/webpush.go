@@ -0,0 +1,41 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webpush
+
+import "errors"
+
+var (
+	// ErrSubscriptionGone is returned by Send when the push service
+	// reports that the subscription has expired or has been revoked by
+	// the browser. The subscription should be removed by the caller.
+	ErrSubscriptionGone = errors.New("push subscription is gone")
+)
+
+// Subscription represents a browser push subscription as returned by the
+// PushManager.subscribe() call of the Push API. The keys are base64url
+// encoded.
+type Subscription struct {
+	Endpoint string // Push service endpoint URL
+	P256dh   string // User agent P-256 ECDH public key
+	Auth     string // User agent authentication secret
+}
+
+// Pusher provides an API for sending Web Push notifications to browser
+// clients.
+type Pusher interface {
+	// IsEnabled determines if web push notifications are enabled.
+	IsEnabled() bool
+
+	// PublicKey returns the base64url encoded VAPID public key of the
+	// application server. Browser clients must provide this key as the
+	// applicationServerKey when creating a push subscription.
+	PublicKey() string
+
+	// Send encrypts the payload for the provided subscription and
+	// delivers it to the subscription's push service. ErrSubscriptionGone
+	// is returned if the push service no longer accepts messages for the
+	// subscription.
+	Send(s Subscription, payload []byte) error
+}
@@ -0,0 +1,424 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package writequeue provides a persistent write-behind queue for user
+// submissions that could not be sent to politeiad because politeiad was
+// unavailable, e.g. during a brief politeiad restart. Queued submissions are
+// persisted to disk and are retried until politeiad becomes available again.
+package writequeue
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// retryInterval is the interval at which the pending submissions
+	// are retried.
+	retryInterval = 10 * time.Second
+
+	// execTimeout is the timeout for a single submission attempt.
+	execTimeout = 30 * time.Second
+
+	// expiry is the duration that a submission is kept for. Pending
+	// submissions that have not been sent to politeiad by then are
+	// marked as failed. Complete and failed submissions are deleted.
+	expiry = 24 * time.Hour
+
+	// idSize is the size in bytes of a submission ID.
+	idSize = 16
+
+	// fileExt is the file extension of persisted submissions.
+	fileExt = ".json"
+)
+
+var (
+	// ErrNotFound is returned when a submission is not found.
+	ErrNotFound = errors.New("submission not found")
+)
+
+// StatusT represents the status of a queued submission.
+type StatusT int
+
+const (
+	// StatusInvalid is an invalid submission status.
+	StatusInvalid StatusT = 0
+
+	// StatusPending indicates that the submission has not been sent to
+	// politeiad yet.
+	StatusPending StatusT = 1
+
+	// StatusComplete indicates that the submission was successfully
+	// sent to politeiad.
+	StatusComplete StatusT = 2
+
+	// StatusFailed indicates that politeiad rejected the submission or
+	// that the submission expired.
+	StatusFailed StatusT = 3
+)
+
+// Submission is a user submission that has been persisted to the queue.
+//
+// Payload is the JSON encoded payload that is provided to the ExecFunc of
+// the submission type. Reply is the JSON encoded reply that was returned by
+// the ExecFunc.
+type Submission struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    StatusT         `json:"status"`
+	Timestamp int64           `json:"timestamp"` // Unix time of submission
+	Seq       uint64          `json:"seq"`       // Submission order
+	Attempts  uint32          `json:"attempts"`
+	Reply     json.RawMessage `json:"reply,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// ExecFunc sends a queued submission payload to politeiad and returns the
+// JSON encoded reply. Errors for which IsRetryable returns true cause the
+// submission to be retried. All other errors cause the submission to fail.
+type ExecFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+// Queue is a persistent write-behind queue. Each submission is persisted to
+// its own file in the queue directory.
+type Queue struct {
+	sync.Mutex
+	dir   string
+	execs map[string]ExecFunc // [type]ExecFunc
+	subs  map[string]*Submission
+
+	// seq is the sequence number of the most recent submission. It is
+	// used to preserve the submission order of submissions that were
+	// added within the same second.
+	seq uint64
+
+	// retry is used to trigger a retry of the pending submissions.
+	retry chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New returns a new Queue that persists submissions to the provided
+// directory. Submissions that were persisted by a previous instance are
+// loaded from disk.
+func New(dir string) (*Queue, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	q := &Queue{
+		dir:   dir,
+		execs: make(map[string]ExecFunc),
+		subs:  make(map[string]*Submission),
+		retry: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	err = q.load()
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Register registers the ExecFunc for a submission type. All submission
+// types must be registered prior to calling Start.
+func (q *Queue) Register(submissionType string, fn ExecFunc) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.execs[submissionType] = fn
+}
+
+// Start starts the retry loop.
+func (q *Queue) Start() {
+	q.wg.Add(1)
+	go q.run()
+}
+
+// Close stops the retry loop. Pending submissions remain on disk and are
+// retried when the queue is started again.
+func (q *Queue) Close() {
+	close(q.done)
+	q.wg.Wait()
+}
+
+// Add adds a submission to the queue. The payload is JSON encoded and
+// persisted to disk before the submission is returned.
+func (q *Queue) Add(submissionType string, payload interface{}) (*Submission, error) {
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	id, err := util.Random(idSize)
+	if err != nil {
+		return nil, err
+	}
+	s := Submission{
+		ID:        hex.EncodeToString(id),
+		Type:      submissionType,
+		Payload:   p,
+		Status:    StatusPending,
+		Timestamp: time.Now().Unix(),
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	if _, ok := q.execs[submissionType]; !ok {
+		return nil, fmt.Errorf("submission type not registered: %v",
+			submissionType)
+	}
+	q.seq++
+	s.Seq = q.seq
+	err = q.save(s)
+	if err != nil {
+		return nil, err
+	}
+	q.subs[s.ID] = &s
+
+	log.Infof("Submission queued %v %v", s.Type, s.ID)
+
+	// Trigger a retry without waiting for the next interval
+	select {
+	case q.retry <- struct{}{}:
+	default:
+	}
+
+	return &s, nil
+}
+
+// Get returns a copy of the submission for the provided ID. ErrNotFound is
+// returned if the submission does not exist or if the submission is not of
+// the provided type.
+func (q *Queue) Get(submissionType, id string) (*Submission, error) {
+	q.Lock()
+	defer q.Unlock()
+
+	s, ok := q.subs[id]
+	if !ok || s.Type != submissionType {
+		return nil, ErrNotFound
+	}
+	c := *s
+	return &c, nil
+}
+
+// IsRetryable returns whether the provided error was caused by politeiad
+// being unreachable. Errors that were returned by politeiad itself are not
+// retryable.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// run retries the pending submissions until the queue is closed.
+func (q *Queue) run() {
+	defer q.wg.Done()
+
+	t := time.NewTicker(retryInterval)
+	defer t.Stop()
+
+	for {
+		q.process()
+
+		select {
+		case <-q.done:
+			return
+		case <-t.C:
+		case <-q.retry:
+		}
+	}
+}
+
+// process attempts to send the pending submissions to politeiad, oldest
+// first. Processing stops at the first retryable error since politeiad is
+// likely still unavailable. Expired submissions are also cleaned up.
+func (q *Queue) process() {
+	q.Lock()
+	pending := make([]Submission, 0, len(q.subs))
+	for _, v := range q.subs {
+		if v.Status == StatusPending {
+			pending = append(pending, *v)
+		}
+	}
+	q.Unlock()
+
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].Timestamp != pending[j].Timestamp {
+			return pending[i].Timestamp < pending[j].Timestamp
+		}
+		return pending[i].Seq < pending[j].Seq
+	})
+
+	for _, s := range pending {
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+
+		retry := q.exec(s)
+		if retry {
+			break
+		}
+	}
+
+	q.cleanup()
+}
+
+// exec sends a single submission to politeiad and updates the submission
+// with the result. It returns true if the submission failed with a
+// retryable error.
+func (q *Queue) exec(s Submission) bool {
+	q.Lock()
+	fn, ok := q.execs[s.Type]
+	q.Unlock()
+
+	s.Attempts++
+	var retry bool
+	if !ok {
+		// This should not happen
+		s.Status = StatusFailed
+		s.Error = fmt.Sprintf("submission type not registered: %v", s.Type)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+		reply, err := fn(ctx, s.Payload)
+		cancel()
+		switch {
+		case err == nil:
+			s.Status = StatusComplete
+			s.Reply = reply
+			log.Infof("Submission complete %v %v", s.Type, s.ID)
+		case IsRetryable(err):
+			retry = true
+			log.Debugf("Submission retry %v %v: %v", s.Type, s.ID, err)
+		default:
+			s.Status = StatusFailed
+			s.Error = err.Error()
+			log.Infof("Submission failed %v %v: %v", s.Type, s.ID, err)
+		}
+	}
+
+	q.update(s)
+	return retry
+}
+
+// cleanup fails the pending submissions that have expired and deletes the
+// complete and failed submissions that have expired.
+func (q *Queue) cleanup() {
+	expired := time.Now().Add(-expiry).Unix()
+
+	q.Lock()
+	defer q.Unlock()
+
+	for id, s := range q.subs {
+		if s.Timestamp > expired {
+			continue
+		}
+		switch s.Status {
+		case StatusPending:
+			c := *s
+			c.Status = StatusFailed
+			c.Error = "submission expired"
+			err := q.save(c)
+			if err != nil {
+				log.Errorf("save %v: %v", id, err)
+				continue
+			}
+			*s = c
+			log.Infof("Submission expired %v %v", s.Type, s.ID)
+		default:
+			err := os.Remove(q.path(id))
+			if err != nil && !os.IsNotExist(err) {
+				log.Errorf("remove %v: %v", id, err)
+				continue
+			}
+			delete(q.subs, id)
+		}
+	}
+}
+
+// update saves the updated submission to disk and to the in-memory cache.
+func (q *Queue) update(s Submission) {
+	q.Lock()
+	defer q.Unlock()
+
+	err := q.save(s)
+	if err != nil {
+		log.Errorf("save %v: %v", s.ID, err)
+	}
+	q.subs[s.ID] = &s
+}
+
+// save writes a submission to disk. The submission is written to a temporary
+// file first so that a crash does not leave a partially written submission.
+//
+// This function must be called WITH the lock held.
+func (q *Queue) save(s Submission) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	fp := q.path(s.ID)
+	tmp := fp + ".tmp"
+	err = os.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, fp)
+}
+
+// load loads all persisted submissions from disk.
+func (q *Queue) load() error {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	var pending int
+	for _, v := range files {
+		if v.IsDir() || !strings.HasSuffix(v.Name(), fileExt) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(q.dir, v.Name()))
+		if err != nil {
+			return err
+		}
+		var s Submission
+		err = json.Unmarshal(b, &s)
+		if err != nil {
+			return fmt.Errorf("%v: %v", v.Name(), err)
+		}
+		q.subs[s.ID] = &s
+		if s.Seq > q.seq {
+			q.seq = s.Seq
+		}
+		if s.Status == StatusPending {
+			pending++
+		}
+	}
+
+	log.Infof("Write queue loaded: %v submissions, %v pending",
+		len(q.subs), pending)
+
+	return nil
+}
+
+// path returns the file path for a submission.
+func (q *Queue) path(id string) string {
+	return filepath.Join(q.dir, id+fileExt)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package writequeue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	dir := t.TempDir()
+	q, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Register an exec func that fails with a connection error until
+	// politeiad is marked as available.
+	var (
+		available bool
+		connErr   = &url.Error{Op: "Post", URL: "https://politeiad",
+			Err: errors.New("connection refused")}
+		userErr = errors.New("user error")
+	)
+	exec := func(ctx context.Context, payload []byte) ([]byte, error) {
+		if !available {
+			return nil, connErr
+		}
+		var s string
+		err := json.Unmarshal(payload, &s)
+		if err != nil {
+			return nil, err
+		}
+		if s == "bad" {
+			return nil, userErr
+		}
+		return payload, nil
+	}
+	q.Register("echo", exec)
+
+	// Unregistered types are not allowed
+	_, err = q.Add("invalid", "hello")
+	if err == nil {
+		t.Fatalf("got nil error for unregistered type")
+	}
+
+	good, err := q.Add("echo", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := q.Add("echo", "bad")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// politeiad is unavailable. The submissions should remain pending.
+	q.process()
+	s, err := q.Get("echo", good.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != StatusPending || s.Attempts != 1 {
+		t.Fatalf("got status %v attempts %v, want pending 1",
+			s.Status, s.Attempts)
+	}
+
+	// Verify the submissions are loaded from disk by a new queue
+	q, err = New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Register("echo", exec)
+	available = true
+	q.process()
+
+	s, err = q.Get("echo", good.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != StatusComplete || string(s.Reply) != `"hello"` {
+		t.Fatalf("got status %v reply %s, want complete \"hello\"",
+			s.Status, s.Reply)
+	}
+	s, err = q.Get("echo", bad.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Status != StatusFailed || s.Error != userErr.Error() {
+		t.Fatalf("got status %v error %q, want failed %q",
+			s.Status, s.Error, userErr)
+	}
+
+	// Submissions can only be retrieved using their own type
+	_, err = q.Get("other", good.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	var tests = []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection error",
+			&url.Error{Op: "Post", Err: errors.New("refused")}, true},
+		{"canceled",
+			&url.Error{Op: "Post", Err: context.Canceled}, false},
+		{"other error", errors.New("other"), false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			got := IsRetryable(v.err)
+			if got != v.want {
+				t.Errorf("got %v, want %v", got, v.want)
+			}
+		})
+	}
+}
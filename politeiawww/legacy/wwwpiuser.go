@@ -103,6 +103,39 @@ func (p *Politeiawww) handleUserProposalCredits(w http.ResponseWriter, r *http.R
 	util.RespondWithJSON(w, http.StatusOK, reply)
 }
 
+// handleUserProposalCreditsTransfer transfers unspent proposal credits from
+// the logged in user to another user.
+func (p *Politeiawww) handleUserProposalCreditsTransfer(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleUserProposalCreditsTransfer")
+
+	var t www.UserProposalCreditsTransfer
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&t); err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserProposalCreditsTransfer: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserProposalCreditsTransfer: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processUserProposalCreditsTransfer(user, t)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleUserProposalCreditsTransfer: "+
+			"processUserProposalCreditsTransfer: %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
 // handleUserPaymentsRescan allows an admin to rescan a user's paywall address
 // to check for any payments that may have been missed by paywall polling.
 func (p *Politeiawww) handleUserPaymentsRescan(w http.ResponseWriter, r *http.Request) {
@@ -128,3 +161,133 @@ func (p *Politeiawww) handleUserPaymentsRescan(w http.ResponseWriter, r *http.Re
 
 	util.RespondWithJSON(w, http.StatusOK, reply)
 }
+
+// handlePushSubscriptions returns the web push subscriptions of the logged in
+// user.
+func (p *Politeiawww) handlePushSubscriptions(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePushSubscriptions")
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePushSubscriptions: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processPushSubscriptions(user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePushSubscriptions: processPushSubscriptions %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
+// handlePushSubscribe adds a web push subscription to the logged in user.
+func (p *Politeiawww) handlePushSubscribe(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePushSubscribe")
+
+	var ps www.PushSubscribe
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ps); err != nil {
+		RespondWithError(w, r, 0, "handlePushSubscribe: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePushSubscribe: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processPushSubscribe(user, ps)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePushSubscribe: processPushSubscribe %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
+// handlePushUnsubscribe removes a web push subscription from the logged in
+// user.
+func (p *Politeiawww) handlePushUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePushUnsubscribe")
+
+	var pu www.PushUnsubscribe
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pu); err != nil {
+		RespondWithError(w, r, 0, "handlePushUnsubscribe: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePushUnsubscribe: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processPushUnsubscribe(user, pu)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handlePushUnsubscribe: processPushUnsubscribe %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
+// handleUserUsage returns the daily API request counts of the logged in user.
+func (p *Politeiawww) handleUserUsage(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleUserUsage")
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserUsage: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processUserUsage(user)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUserUsage: processUserUsage %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
+// handleUsersUsage returns the aggregate API request counts of all users.
+func (p *Politeiawww) handleUsersUsage(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleUsersUsage")
+
+	var uu www.UsersUsage
+	err := util.ParseGetParams(r, &uu)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleUsersUsage: ParseGetParams",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	reply, err := p.processUsersUsage(uu)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleUsersUsage: processUsersUsage %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
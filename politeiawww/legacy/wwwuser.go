@@ -411,6 +411,37 @@ func (p *Politeiawww) handleVerifyUpdateUserKey(w http.ResponseWriter, r *http.R
 	util.RespondWithJSON(w, http.StatusOK, www.VerifyUpdateUserKeyReply{})
 }
 
+// handleRevokeUserKey handles the incoming revoke user key command.
+func (p *Politeiawww) handleRevokeUserKey(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleRevokeUserKey")
+
+	var rk www.RevokeUserKey
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&rk); err != nil {
+		RespondWithError(w, r, 0, "handleRevokeUserKey: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleRevokeUserKey: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processRevokeUserKey(user, rk)
+	if err != nil {
+		RespondWithError(w, r, 0, "handleRevokeUserKey: "+
+			"processRevokeUserKey %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
 // handleChangeUsername handles the change user name command.
 func (p *Politeiawww) handleChangeUsername(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handleChangeUsername")
@@ -492,6 +523,68 @@ func (p *Politeiawww) handleChangePassword(w http.ResponseWriter, r *http.Reques
 	util.RespondWithJSON(w, http.StatusOK, reply)
 }
 
+// handleChangeEmail handles the change email command.
+func (p *Politeiawww) handleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleChangeEmail")
+
+	var ce www.ChangeEmail
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ce); err != nil {
+		RespondWithError(w, r, 0, "handleChangeEmail: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleChangeEmail: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processChangeEmail(user, ce)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleChangeEmail: processChangeEmail %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
+// handleVerifyChangeEmail handles the verify change email command.
+func (p *Politeiawww) handleVerifyChangeEmail(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleVerifyChangeEmail")
+
+	var vce www.VerifyChangeEmail
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&vce); err != nil {
+		RespondWithError(w, r, 0, "handleVerifyChangeEmail: unmarshal",
+			www.UserError{
+				ErrorCode: www.ErrorStatusInvalidInput,
+			})
+		return
+	}
+
+	user, err := p.sessions.GetSessionUser(w, r)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleVerifyChangeEmail: getSessionUser %v", err)
+		return
+	}
+
+	reply, err := p.processVerifyChangeEmail(user, vce)
+	if err != nil {
+		RespondWithError(w, r, 0,
+			"handleVerifyChangeEmail: processVerifyChangeEmail %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, reply)
+}
+
 // handleUsers handles fetching a list of users.
 func (p *Politeiawww) handleUsers(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handleUsers")
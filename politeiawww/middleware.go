@@ -77,19 +77,43 @@ func recoverMiddleware(next http.Handler) http.Handler {
 // middleware contains the middleware that use configurable settings.
 type middleware struct {
 	reqBodySizeLimit int64 // In bytes
+
+	// routeBodySizeLimits contains the request body size limits that
+	// override the default reqBodySizeLimit for specific routes.
+	routeBodySizeLimits map[string]int64 // [route]limit in bytes
+}
+
+// bodySizeLimit returns the maximum request body size for the provided route.
+func (m *middleware) bodySizeLimit(route string) int64 {
+	limit, ok := m.routeBodySizeLimits[route]
+	if !ok {
+		return m.reqBodySizeLimit
+	}
+	return limit
 }
 
 // reqBodySizeLimitMiddleware applies a maximum request body size limit to
-// requests.
+// requests. Requests that declare a content length that exceeds the limit are
+// rejected before any of the request body is read.
 //
-// NOTE: This will only cause an error if the request body is read by the
-// request handler, e.g. the JSON from a POST request is decoded into a struct.
+// NOTE: The limit will only cause an error for requests that do not declare a
+// content length if the request body is read by the request handler, e.g. the
+// JSON from a POST request is decoded into a struct.
 func (m *middleware) reqBodySizeLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := m.bodySizeLimit(r.URL.Path)
+
 		log.Tracef("Applying a max body size of %v bytes to the request body",
-			m.reqBodySizeLimit)
+			limit)
+
+		if r.ContentLength > limit {
+			log.Infof("%v %v %v %v Request body too large: %v bytes",
+				util.RemoteAddr(r), r.Method, r.URL, r.Proto, r.ContentLength)
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
 
-		r.Body = http.MaxBytesReader(w, r.Body, m.reqBodySizeLimit)
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
 		next.ServeHTTP(w, r)
 	})
 }
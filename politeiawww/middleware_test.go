@@ -17,8 +17,12 @@ import (
 func TestReqBodySizeMiddleware(t *testing.T) {
 	// Setup the test router
 	router := mux.NewRouter()
+	testRouteLarge := "/testlarge"
 	m := middleware{
 		reqBodySizeLimit: 5,
+		routeBodySizeLimits: map[string]int64{
+			testRouteLarge: 6,
+		},
 	}
 	router.Use(closeBodyMiddleware)
 	router.Use(m.reqBodySizeLimitMiddleware)
@@ -26,14 +30,16 @@ func TestReqBodySizeMiddleware(t *testing.T) {
 	// Setup a route handler that reads the request body. Reading
 	// the request body is required in order to trigger the error.
 	testRoute := "/test"
-	router.HandleFunc(testRoute, func(w http.ResponseWriter, r *http.Request) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
 		_, err := io.ReadAll(r.Body)
 		if err != nil {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 		w.WriteHeader(http.StatusOK)
-	})
+	}
+	router.HandleFunc(testRoute, handler)
+	router.HandleFunc(testRouteLarge, handler)
 
 	// Setup test request bodies
 	const (
@@ -44,30 +50,54 @@ func TestReqBodySizeMiddleware(t *testing.T) {
 
 	// Setup tests
 	var tests = []struct {
-		name     string
-		reqBody  string
-		wantCode int
+		name          string
+		route         string
+		reqBody       string
+		unknownLength bool // Content length is not set
+		wantCode      int
 	}{
 		{
 			"no request body",
+			testRoute,
 			"",
+			false,
 			http.StatusOK,
 		},
 		{
 			"under the req body limit",
+			testRoute,
 			fourBytes,
+			false,
 			http.StatusOK,
 		},
 		{
 			"at the req body limit",
+			testRoute,
 			fiveBytes,
+			false,
 			http.StatusOK,
 		},
 		{
 			"over the req body limit",
+			testRoute,
+			sixBytes,
+			false,
+			http.StatusRequestEntityTooLarge,
+		},
+		{
+			"over the req body limit unknown length",
+			testRoute,
 			sixBytes,
+			true,
 			http.StatusBadRequest,
 		},
+		{
+			"at the route req body limit",
+			testRouteLarge,
+			sixBytes,
+			false,
+			http.StatusOK,
+		},
 	}
 
 	// Run tests
@@ -75,10 +105,13 @@ func TestReqBodySizeMiddleware(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup the test request
 			req, err := http.NewRequest(http.MethodPost,
-				testRoute, strings.NewReader(tc.reqBody))
+				tc.route, strings.NewReader(tc.reqBody))
 			if err != nil {
 				t.Fatal(err)
 			}
+			if tc.unknownLength {
+				req.ContentLength = -1
+			}
 
 			// Send the test request
 			rr := httptest.NewRecorder()
@@ -33,7 +33,8 @@ func (p *politeiawww) setupRouter() error {
 	// Add router middleware. Middleware is executed
 	// in the same order that they are registered in.
 	m := middleware{
-		reqBodySizeLimit: p.cfg.ReqBodySizeLimit,
+		reqBodySizeLimit:    p.cfg.ReqBodySizeLimit,
+		routeBodySizeLimits: p.cfg.RouteLimits,
 	}
 	p.router.Use(closeBodyMiddleware) // MUST be registered first
 	p.router.Use(m.reqBodySizeLimitMiddleware)
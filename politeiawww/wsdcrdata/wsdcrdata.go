@@ -75,7 +75,9 @@ type pendingEvent struct {
 // subscriptions.
 type Client struct {
 	sync.Mutex
-	url           string
+	urls          []string            // Hosts in order of preference
+	url           string              // Host of the current connection
+	onAttempt     func(string, error) // Optional connection attempt hook
 	status        StatusT             // Websocket status
 	client        *psclient.Client    // dcrdata websocket client
 	subscriptions map[string]struct{} // Active subscriptions
@@ -105,6 +107,35 @@ func (c *Client) clientSet(psc *psclient.Client) {
 	c.client = psc
 }
 
+// urlSet sets the host of the current connection.
+func (c *Client) urlSet(url string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.url = url
+}
+
+// connect attempts to connect to each of the dcrdata hosts in order of
+// preference and returns the first connection that is made. The error of the
+// last connection attempt is returned if no connection could be made.
+func (c *Client) connect() (*psclient.Client, error) {
+	var err error
+	for _, url := range c.urls {
+		var psc *psclient.Client
+		psc, err = psclientNew(url)
+		if c.onAttempt != nil {
+			c.onAttempt(url, err)
+		}
+		if err != nil {
+			log.Errorf("Dcrdata websocket host %v: %v", url, err)
+			continue
+		}
+		c.urlSet(url)
+		return psc, nil
+	}
+	return nil, err
+}
+
 // subAdd adds an event subscription to the subscriptions map.
 func (c *Client) subAdd(event string) {
 	c.Lock()
@@ -260,6 +291,14 @@ func (c *Client) Status() StatusT {
 	return c.status
 }
 
+// URL returns the dcrdata host of the current or most recent connection.
+func (c *Client) URL() string {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.url
+}
+
 // AddressSubscribe subscribes to events for the provided address.
 func (c *Client) AddressSubscribe(address string) error {
 	log.Tracef("AddressSubscribe: %v", address)
@@ -332,8 +371,10 @@ func (c *Client) Reconnect() {
 	for !done {
 		log.Infof("Attempting to reconnect dcrdata websocket")
 
-		// Reconnect to dcrdata
-		client, err := psclientNew(c.url)
+		// Reconnect to dcrdata. The hosts are tried in order of
+		// preference so that the connection fails back to the
+		// primary host once it is available again.
+		client, err := c.connect()
 		if err != nil {
 			log.Errorf("New client failed: %v", err)
 			goto wait
@@ -477,27 +518,41 @@ func psclientNew(url string) (*psclient.Client, error) {
 
 // New returns a new Client.
 func New(dcrdataURL string) (*Client, error) {
-	log.Tracef("New: %v", dcrdataURL)
+	return NewWithHosts([]string{dcrdataURL}, nil)
+}
+
+// NewWithHosts returns a new Client that fails over between the provided
+// dcrdata hosts. The hosts are listed in order of preference. A connection is
+// made to the first host that can be reached, both on startup and when the
+// client reconnects. The optional onAttempt function is called with the
+// result of every connection attempt.
+func NewWithHosts(dcrdataURLs []string, onAttempt func(string, error)) (*Client, error) {
+	log.Tracef("NewWithHosts: %v", dcrdataURLs)
+
+	if len(dcrdataURLs) == 0 {
+		return nil, fmt.Errorf("no dcrdata hosts provided")
+	}
 
 	// Setup dcrdata connection. If there is an error when connecting
 	// to dcrdata, return both the error and the Client so that the
 	// caller can decide if reconnection attempts should be made.
-	var status StatusT
-	c, err := psclientNew(dcrdataURL)
+	client := &Client{
+		urls:          dcrdataURLs,
+		url:           dcrdataURLs[0],
+		onAttempt:     onAttempt,
+		subscriptions: make(map[string]struct{}),
+		pending:       make([]pendingEvent, 0),
+	}
+	c, err := client.connect()
 	if err == nil {
 		// Connection is good
-		status = StatusOpen
+		client.status = StatusOpen
 	} else {
 		// Unable to make a connection
 		c = &psclient.Client{}
-		status = StatusShutdown
+		client.status = StatusShutdown
 	}
+	client.client = c
 
-	return &Client{
-		url:           dcrdataURL,
-		status:        status,
-		client:        c,
-		subscriptions: make(map[string]struct{}),
-		pending:       make([]pendingEvent, 0),
-	}, err
+	return client, err
 }
@@ -0,0 +1,214 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// defaultHTTPTimeout is the default timeout that is used for HTTP
+	// clients. It applies to the full request, including retries.
+	defaultHTTPTimeout = 2 * time.Minute
+
+	// defaultHTTPRetryDelay is the default delay between HTTP request
+	// attempts. The delay is multiplied by the number of failed attempts.
+	defaultHTTPRetryDelay = time.Second
+)
+
+// HTTPClientOpts contains the options that are used to build an HTTP client.
+// The zero value returns a client that uses the default timeouts and does not
+// retry failed requests.
+type HTTPClientOpts struct {
+	// SkipVerify skips the verification of the server TLS certificate.
+	SkipVerify bool
+
+	// CertPath is the path to a TLS certificate that is added to the
+	// system cert pool. It is ignored when SkipVerify is set.
+	CertPath string
+
+	// Timeout is the overall request timeout, including retries. The
+	// default timeout is used if this is not set.
+	Timeout time.Duration
+
+	// Proxy is the URL of the proxy that requests are sent through.
+	// The proxy settings of the environment are used if this is not
+	// set.
+	Proxy string
+
+	// Retries is the number of times that a failed request is retried.
+	// A request is considered to have failed if a network error occurs
+	// or if the server replies with a 5xx or a 429 status code. Only
+	// enable retries for clients whose requests are safe to repeat.
+	Retries int
+
+	// RetryDelay is the delay between request attempts. The delay is
+	// multiplied by the number of failed attempts. The default delay
+	// is used if this is not set.
+	RetryDelay time.Duration
+
+	// OnAttempt, if set, is called after every request attempt. It can
+	// be used to log and collect metrics on outbound requests.
+	OnAttempt func(HTTPAttempt)
+}
+
+// HTTPAttempt contains the result of a single HTTP request attempt.
+type HTTPAttempt struct {
+	Method     string
+	URL        string
+	Attempt    int           // Starts at 1
+	StatusCode int           // Zero if the request failed with an error
+	Duration   time.Duration // Duration of the attempt
+	Err        error
+	Retry      bool // Whether the request will be retried
+}
+
+// String returns a description of the request attempt that is suitable for
+// logging.
+func (a HTTPAttempt) String() string {
+	s := fmt.Sprintf("%v %v attempt %v", a.Method, a.URL, a.Attempt)
+	if a.Err != nil {
+		return fmt.Sprintf("%v: %v", s, a.Err)
+	}
+	return fmt.Sprintf("%v: %v %v", s, a.StatusCode, a.Duration)
+}
+
+// NewHTTPClientWithOpts returns a new http Client that has been configured
+// using the provided options.
+func NewHTTPClientWithOpts(opts HTTPClientOpts) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.SkipVerify,
+	}
+	if !opts.SkipVerify && opts.CertPath != "" {
+		cert, err := os.ReadFile(opts.CertPath)
+		if err != nil {
+			return nil, err
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			fmt.Printf("WARN: unable to get system cert pool: %v\n", err)
+			certPool = x509.NewCertPool()
+		}
+		certPool.AppendCertsFromPEM(cert)
+		tlsConfig.RootCAs = certPool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		u, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %v", err)
+		}
+		proxy = http.ProxyURL(u)
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultHTTPTimeout
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy:                 proxy,
+		IdleConnTimeout:       timeout,
+		ResponseHeaderTimeout: timeout,
+		TLSClientConfig:       tlsConfig,
+	}
+	if opts.Retries > 0 || opts.OnAttempt != nil {
+		retryDelay := opts.RetryDelay
+		if retryDelay == 0 {
+			retryDelay = defaultHTTPRetryDelay
+		}
+		transport = &retryTransport{
+			transport:  transport,
+			retries:    opts.Retries,
+			retryDelay: retryDelay,
+			onAttempt:  opts.OnAttempt,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}, nil
+}
+
+// retryTransport is a http RoundTripper that retries failed requests and
+// reports the result of every request attempt.
+type retryTransport struct {
+	transport  http.RoundTripper
+	retries    int
+	retryDelay time.Duration
+	onAttempt  func(HTTPAttempt)
+}
+
+// RoundTrip satisfies the http RoundTripper interface.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		// The request body is consumed by every attempt, so it must
+		// be reset prior to a retry.
+		r := req
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		start := time.Now()
+		resp, err := t.transport.RoundTrip(r)
+		retry := attempt <= t.retries && retryable(req, resp, err)
+
+		if t.onAttempt != nil {
+			a := HTTPAttempt{
+				Method:   req.Method,
+				URL:      req.URL.String(),
+				Attempt:  attempt,
+				Duration: time.Since(start),
+				Err:      err,
+				Retry:    retry,
+			}
+			if resp != nil {
+				a.StatusCode = resp.StatusCode
+			}
+			t.onAttempt(a)
+		}
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.retryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+// retryable returns whether a request attempt failed in a way that can be
+// retried. Requests that have a body that cannot be reset are never retried.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientRetries(t *testing.T) {
+	// Setup a server that fails the first two requests
+	var (
+		requests int
+		bodies   []string
+	)
+	s := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			bodies = append(bodies, string(b))
+			if requests <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer s.Close()
+
+	var attempts []HTTPAttempt
+	c, err := NewHTTPClientWithOpts(HTTPClientOpts{
+		Retries:    2,
+		RetryDelay: time.Millisecond,
+		OnAttempt: func(a HTTPAttempt) {
+			attempts = append(attempts, a)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The request should succeed on the final retry and the request
+	// body should be resent on every attempt.
+	r, err := c.Post(s.URL, "text/plain", bytes.NewReader([]byte("body")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		t.Errorf("got status %v, want %v", r.StatusCode, http.StatusOK)
+	}
+	if len(attempts) != 3 {
+		t.Fatalf("got %v attempts, want 3", len(attempts))
+	}
+	for i, a := range attempts {
+		wantRetry := i < 2
+		if a.Attempt != i+1 || a.Retry != wantRetry {
+			t.Errorf("attempt %v: got %+v", i+1, a)
+		}
+	}
+	for _, v := range bodies {
+		if v != "body" {
+			t.Errorf("got body '%v', want 'body'", v)
+		}
+	}
+
+	// The final failed attempt should be returned once the retries
+	// have been exhausted.
+	requests = 0
+	c, err = NewHTTPClientWithOpts(HTTPClientOpts{
+		Retries:    1,
+		RetryDelay: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err = c.Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+	if r.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %v, want %v", r.StatusCode,
+			http.StatusServiceUnavailable)
+	}
+	if requests != 2 {
+		t.Errorf("got %v requests, want 2", requests)
+	}
+}
@@ -6,14 +6,11 @@ package util
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"os"
-	"time"
 
 	pdv1 "github.com/decred/politeia/politeiad/api/v1"
 	"github.com/gorilla/schema"
@@ -29,33 +26,14 @@ func NormalizeAddress(addr, defaultPort string) string {
 	return addr
 }
 
-// NewHTTPClient returns a new http Client.
+// NewHTTPClient returns a new http Client that uses the default timeouts and
+// does not retry failed requests. See NewHTTPClientWithOpts for building a
+// client with additional options.
 func NewHTTPClient(skipVerify bool, certPath string) (*http.Client, error) {
-	tlsConfig := &tls.Config{
-		InsecureSkipVerify: skipVerify,
-	}
-
-	if !skipVerify && certPath != "" {
-		cert, err := os.ReadFile(certPath)
-		if err != nil {
-			return nil, err
-		}
-		certPool, err := x509.SystemCertPool()
-		if err != nil {
-			fmt.Printf("WARN: unable to get system cert pool: %v\n", err)
-			certPool = x509.NewCertPool()
-		}
-		certPool.AppendCertsFromPEM(cert)
-		tlsConfig.RootCAs = certPool
-	}
-
-	return &http.Client{
-		Timeout: 2 * time.Minute,
-		Transport: &http.Transport{
-			IdleConnTimeout:       2 * time.Minute,
-			ResponseHeaderTimeout: 2 * time.Minute,
-			TLSClientConfig:       tlsConfig,
-		}}, nil
+	return NewHTTPClientWithOpts(HTTPClientOpts{
+		SkipVerify: skipVerify,
+		CertPath:   certPath,
+	})
 }
 
 // ConvertBodyToByteArray converts a response body into a byte array
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/png"
+)
+
+// pngSignature is the 8 byte signature that all PNG files begin with.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngChunksAllowed contains the PNG chunk types that are allowed in a
+// sanitized PNG. These chunks are required to render the image correctly and
+// cannot contain arbitrary data. Chunks that can contain metadata, such as
+// text, timestamps, EXIF data, or embedded ICC profiles, are not allowed.
+var pngChunksAllowed = map[string]struct{}{
+	"IHDR": {},
+	"PLTE": {},
+	"IDAT": {},
+	"IEND": {},
+	"tRNS": {},
+	"gAMA": {},
+	"cHRM": {},
+	"sRGB": {},
+	"sBIT": {},
+	"bKGD": {},
+	"pHYs": {},
+}
+
+// PNGVerify verifies that the provided payload is a sanitized PNG image. A
+// sanitized PNG is a valid PNG that does not exceed the provided dimensions,
+// does not contain any metadata chunks, and does not contain any data after
+// the end of the image. The dimensions are checked prior to decoding the image
+// data in order to prevent decompression bombs.
+func PNGVerify(b []byte, widthMax, heightMax uint32) error {
+	// Verify chunks
+	if !bytes.HasPrefix(b, pngSignature) {
+		return fmt.Errorf("invalid png signature")
+	}
+	r := b[len(pngSignature):]
+	var iend bool
+	for !iend {
+		// Each chunk is made up of a 4 byte length, a 4 byte type,
+		// the chunk data, and a 4 byte CRC.
+		if len(r) < 12 {
+			return fmt.Errorf("unexpected end of png")
+		}
+		size := binary.BigEndian.Uint32(r[:4])
+		chunk := string(r[4:8])
+		if uint64(size)+12 > uint64(len(r)) {
+			return fmt.Errorf("chunk %v exceeds png size", chunk)
+		}
+		if _, ok := pngChunksAllowed[chunk]; !ok {
+			return fmt.Errorf("chunk %v not allowed", chunk)
+		}
+		r = r[12+size:]
+		iend = chunk == "IEND"
+	}
+	if len(r) != 0 {
+		return fmt.Errorf("%v bytes found after end of png", len(r))
+	}
+
+	// Verify dimensions
+	cfg, err := png.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return fmt.Errorf("invalid dimensions %vx%v", cfg.Width, cfg.Height)
+	}
+	if uint32(cfg.Width) > widthMax || uint32(cfg.Height) > heightMax {
+		return fmt.Errorf("dimensions %vx%v exceed max %vx%v",
+			cfg.Width, cfg.Height, widthMax, heightMax)
+	}
+
+	// Verify image data
+	_, err = png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PNGSanitize decodes and re-encodes the provided PNG image. The returned PNG
+// does not contain any of the metadata chunks that were included in the
+// original image.
+func PNGSanitize(b []byte) ([]byte, error) {
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// newTestPNG returns a PNG encoded image with the provided dimensions.
+func newTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.SetRGBA(0, 0, color.RGBA{255, 0, 0, 255})
+	var b bytes.Buffer
+	err := png.Encode(&b, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+// pngChunk returns an encoded PNG chunk.
+func pngChunk(chunk string, data []byte) []byte {
+	b := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(b[:4], uint32(len(data)))
+	copy(b[4:8], chunk)
+	b = append(b, data...)
+	crc := crc32.ChecksumIEEE(b[4:])
+	return binary.BigEndian.AppendUint32(b, crc)
+}
+
+// pngInsertChunk inserts a chunk directly after the IHDR chunk.
+func pngInsertChunk(b, chunk []byte) []byte {
+	// The IHDR chunk is 25 bytes and follows the 8 byte signature
+	i := len(pngSignature) + 25
+	r := make([]byte, 0, len(b)+len(chunk))
+	r = append(r, b[:i]...)
+	r = append(r, chunk...)
+	return append(r, b[i:]...)
+}
+
+func TestPNGVerify(t *testing.T) {
+	valid := newTestPNG(t, 10, 10)
+	withText := pngInsertChunk(valid, pngChunk("tEXt", []byte("Author\x00me")))
+
+	var tests = []struct {
+		name    string
+		b       []byte
+		wantErr bool
+	}{
+		{"valid", valid, false},
+		{"not a png", []byte("not a png"), true},
+		{"metadata chunk", withText, true},
+		{"trailing data", append(append([]byte{}, valid...), 'x'), true},
+		{"truncated", valid[:len(valid)-4], true},
+		{"too wide", newTestPNG(t, 21, 10), true},
+		{"too tall", newTestPNG(t, 10, 21), true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := PNGVerify(v.b, 20, 20)
+			switch {
+			case v.wantErr && err == nil:
+				t.Errorf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestPNGSanitize(t *testing.T) {
+	b := newTestPNG(t, 10, 10)
+	b = pngInsertChunk(b, pngChunk("tEXt", []byte("Author\x00me")))
+
+	sanitized, err := PNGSanitize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = PNGVerify(sanitized, 20, 20)
+	if err != nil {
+		t.Fatalf("sanitized png is invalid: %v", err)
+	}
+}
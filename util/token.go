@@ -24,9 +24,45 @@ var (
 	// tokenRegexp is a regexp that matches short tokens and full
 	// length tokens.
 	tokenRegexp = regexp.MustCompile(fmt.Sprintf("^[0-9a-f]{%v,%v}$",
-		pdv2.ShortTokenLength, pdv2.TokenSize*2))
+		ShortTokenLengthMin, pdv2.TokenSize*2))
+
+	// shortTokenLength is the length, in characters, of a hex encoded
+	// tstore short token. It defaults to the politeiad v2 short token
+	// length and can be changed on startup using SetShortTokenLength.
+	shortTokenLength = pdv2.ShortTokenLength
 )
 
+const (
+	// ShortTokenLengthMin is the minimum short token length that can be
+	// set using SetShortTokenLength.
+	ShortTokenLengthMin = pdv2.ShortTokenLength
+
+	// ShortTokenLengthMax is the maximum short token length that can be
+	// set using SetShortTokenLength. A decoded short token, including
+	// any padding, must be smaller than a full length token so that the
+	// two can be told apart.
+	ShortTokenLengthMax = pdv2.TokenSize*2 - 2
+)
+
+// SetShortTokenLength sets the length, in characters, of the hex encoded
+// short tokens. This must be called on startup prior to any tokens being
+// encoded or decoded.
+func SetShortTokenLength(length int) error {
+	if length < ShortTokenLengthMin || length > ShortTokenLengthMax {
+		return fmt.Errorf("short token length must be between %v and %v "+
+			"characters; got %v", ShortTokenLengthMin, ShortTokenLengthMax,
+			length)
+	}
+	shortTokenLength = length
+	return nil
+}
+
+// ShortTokenLength returns the length, in characters, of a hex encoded short
+// token.
+func ShortTokenLength() int {
+	return shortTokenLength
+}
+
 // ShortTokenSize returns the size, in bytes, of a politeiad short token.
 func ShortTokenSize() int {
 	// If the short token length is an odd number of characters then
@@ -34,12 +70,12 @@ func ShortTokenSize() int {
 	// to hex to prevent a hex.ErrLenth (odd length hex string) error.
 	// This function accounts for this padding in the returned size.
 	var size int
-	if pdv1.TokenPrefixLength%2 == 1 {
+	if shortTokenLength%2 == 1 {
 		// Add 1 to the length to account for padding
-		size = (pdv2.ShortTokenLength + 1) / 2
+		size = (shortTokenLength + 1) / 2
 	} else {
 		// No padding was required
-		size = pdv2.ShortTokenLength / 2
+		size = shortTokenLength / 2
 	}
 	return size
 }
@@ -59,7 +95,11 @@ func ShortTokenString(token string) (string, error) {
 	if tokenRegexp.FindString(token) == "" {
 		return "", fmt.Errorf("invalid token %v", tokenRegexp.String())
 	}
-	return token[:pdv2.ShortTokenLength], nil
+	if len(token) < shortTokenLength {
+		return "", fmt.Errorf("token is shorter than %v characters",
+			shortTokenLength)
+	}
+	return token[:shortTokenLength], nil
 }
 
 // ShortTokenEncode returns the hex encoded shortened token.
@@ -115,6 +155,7 @@ func TokenDecodeAnyLength(tokenType, token string) ([]byte, error) {
 
 	// Decode token. If provided token has odd length, add padding
 	// to prevent a hex.ErrLength (odd length hex string) error.
+	tokenLen := len(token)
 	if len(token)%2 == 1 {
 		token = token + "0"
 	}
@@ -126,7 +167,7 @@ func TokenDecodeAnyLength(tokenType, token string) ([]byte, error) {
 	// Verify token byte slice is either a short token or a full length
 	// token.
 	switch {
-	case len(t) == ShortTokenSize():
+	case len(t) == ShortTokenSize() && tokenLen == shortTokenLength:
 		// This is a short token. Short tokens are the same size
 		// regardless of token type.
 	case tokenType == TokenTypeGit && TokenIsFullLength(TokenTypeGit, t):
@@ -146,10 +187,10 @@ func TokenDecodeAnyLength(tokenType, token string) ([]byte, error) {
 // the token.
 func TokenEncode(token []byte) string {
 	t := hex.EncodeToString(token)
-	if len(t) == pdv2.ShortTokenLength+1 {
+	if shortTokenLength%2 == 1 && len(t) == shortTokenLength+1 {
 		// This is a short token that has had padding added to it. Remove
 		// the padding.
-		t = t[:pdv2.ShortTokenLength]
+		t = t[:shortTokenLength]
 	}
 	return t
 }
@@ -0,0 +1,97 @@
+// Copyright (c) 2022 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"testing"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+)
+
+func TestSetShortTokenLength(t *testing.T) {
+	defer func() {
+		shortTokenLength = pdv2.ShortTokenLength
+	}()
+
+	var tests = []struct {
+		name    string
+		length  int
+		wantErr bool
+	}{
+		{"below min", ShortTokenLengthMin - 1, true},
+		{"min", ShortTokenLengthMin, false},
+		{"max", ShortTokenLengthMax, false},
+		{"above max", ShortTokenLengthMax + 1, true},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := SetShortTokenLength(v.length)
+			switch {
+			case v.wantErr && err == nil:
+				t.Fatalf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Fatal(err)
+			case !v.wantErr && ShortTokenLength() != v.length:
+				t.Fatalf("got length %v, want %v",
+					ShortTokenLength(), v.length)
+			}
+		})
+	}
+}
+
+func TestShortTokenRoundTrip(t *testing.T) {
+	defer func() {
+		shortTokenLength = pdv2.ShortTokenLength
+	}()
+
+	full := "0123456789abcdef"
+	fullb, err := TokenDecode(TokenTypeTstore, full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for l := ShortTokenLengthMin; l <= ShortTokenLengthMax; l++ {
+		err := SetShortTokenLength(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Encode the short token
+		short, err := ShortTokenEncode(fullb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if short != full[:l] {
+			t.Fatalf("length %v: got short token %v, want %v",
+				l, short, full[:l])
+		}
+
+		// Decode the short token and encode it again
+		b, err := TokenDecodeAnyLength(TokenTypeTstore, short)
+		if err != nil {
+			t.Fatalf("length %v: %v", l, err)
+		}
+		if got := TokenEncode(b); got != short {
+			t.Fatalf("length %v: got %v, want %v", l, got, short)
+		}
+
+		// Full length tokens must not be altered
+		if got := TokenEncode(fullb); got != full {
+			t.Fatalf("length %v: got full token %v, want %v",
+				l, got, full)
+		}
+
+		// Longer prefixes are not short tokens. A prefix that is one
+		// character short of a full token pads to a full token size and
+		// is not checked.
+		if l+1 >= len(full)-1 {
+			continue
+		}
+		_, err = TokenDecodeAnyLength(TokenTypeTstore, full[:l+1])
+		if err == nil {
+			t.Fatalf("length %v: decoded a %v character token",
+				l, l+1)
+		}
+	}
+}